@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The discovery endpoint of the Brokers. It serves the EventTypes registered in a namespace, and
+// the JSON Schemas of their data, to the requesters allowed to list them.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/broker/discovery"
+	"github.com/knative/eventing/pkg/client/clientset/versioned"
+	"github.com/knative/eventing/pkg/client/informers/externalversions"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+
+	port int
+)
+
+func init() {
+	flag.IntVar(&port, "port", 8080, "The port the discovery endpoint is served on.")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+
+	stopCh := signals.SetupSignalHandler()
+
+	cfg := config.GetConfigOrDie()
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		logger.Fatal("Unable to create the kubernetes client", zap.Error(err))
+	}
+	eventingClient, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		logger.Fatal("Unable to create the eventing client", zap.Error(err))
+	}
+
+	informerFactory := externalversions.NewSharedInformerFactory(eventingClient, 0)
+	eventTypeInformer := informerFactory.Eventing().V1alpha1().EventTypes()
+	informerFactory.Start(stopCh)
+	if ok := cache.WaitForCacheSync(stopCh, eventTypeInformer.Informer().HasSynced); !ok {
+		logger.Fatal("Unable to wait for the EventType informer to sync")
+	}
+
+	h := discovery.NewHandler(
+		eventTypeInformer.Lister(),
+		schema.NewResolver(schema.KubeConfigMapGetter(kc)),
+		authz.KubeAuthorizer(kc),
+		logger,
+	)
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      h,
+		ErrorLog:     zap.NewStdLog(logger),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			logger.Error("Unable to shut down cleanly", zap.Error(err))
+		}
+	}()
+	logger.Info("Broker discovery listening...", zap.String("Address", s.Addr))
+	if err := s.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Fatal("Unable to serve", zap.Error(err))
+	}
+}
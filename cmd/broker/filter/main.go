@@ -0,0 +1,91 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The filter of a Broker. It receives the events of the Broker's Channel and delivers them to the
+// subscribers whose filter they match. The routes are read from a mounted ConfigMap.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+
+	configDir string
+)
+
+func init() {
+	flag.StringVar(&configDir, "config_dir", filter.ConfigDir, "The directory the filter's ConfigMap is mounted in.")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+
+	codes, err := filter.PermanentStatusCodesFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid permanent status codes", zap.Error(err))
+	}
+	h := filter.NewHandler(logger, filter.WithPermanentStatusCodes(codes), filter.WithReplyExtensions(provisioners.ReplyExtensionsFromEnv()))
+	cw, err := filter.NewConfigWatcher(logger, configDir, h)
+	if err != nil {
+		logger.Fatal("Unable to read the filter config", zap.Error(err))
+	}
+
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", provisioners.MessageReceiverPort),
+		Handler:      h,
+		ErrorLog:     zap.NewStdLog(logger),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	var g errgroup.Group
+	g.Go(func() error {
+		return cw.Start(stopCh)
+	})
+	g.Go(func() error {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		return s.Shutdown(ctx)
+	})
+	logger.Info("Broker filter listening...", zap.String("Address", s.Addr))
+	if err := s.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Fatal("Unable to serve", zap.Error(err))
+	}
+	if err := g.Wait(); err != nil {
+		logger.Error("Unable to shut down cleanly", zap.Error(err))
+	}
+}
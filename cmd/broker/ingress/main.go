@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The ingress of a Broker. It accepts events sent to the Broker and writes them to the Broker's
+// Channel. If the CHANNEL environment variable is not set, it runs as the multi-tenant ingress
+// shared by many Brokers, whose Channels are read from a mounted ConfigMap.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/knative/eventing/pkg/broker/ingress"
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+
+	configDir string
+)
+
+func init() {
+	flag.StringVar(&configDir, "config_dir", ingress.ConfigDir, "The directory the multi-tenant ingress's ConfigMap is mounted in.")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	var g errgroup.Group
+
+	var h http.Handler
+	channelURI := os.Getenv("CHANNEL")
+	if channelURI != "" {
+		logger.Info("Writing events to a single Channel", zap.String("channel", channelURI))
+		h = ingress.NewHandler(logger, channelURI)
+	} else {
+		logger.Info("Writing events to the Channels in the config", zap.String("configDir", configDir))
+		mth := ingress.NewMultiTenantHandler(logger)
+		cw, err := ingress.NewConfigWatcher(logger, configDir, mth)
+		if err != nil {
+			logger.Fatal("Unable to read the ingress config", zap.Error(err))
+		}
+		g.Go(func() error {
+			return cw.Start(stopCh)
+		})
+		h = mth
+	}
+
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", provisioners.MessageReceiverPort),
+		Handler:      h,
+		ErrorLog:     zap.NewStdLog(logger),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+
+	g.Go(func() error {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		return s.Shutdown(ctx)
+	})
+	logger.Info("Broker ingress listening...", zap.String("Address", s.Addr))
+	if err := s.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Fatal("Unable to serve", zap.Error(err))
+	}
+	if err := g.Wait(); err != nil {
+		logger.Error("Unable to shut down cleanly", zap.Error(err))
+	}
+}
@@ -19,7 +19,28 @@ import (
 	"strings"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	istionetworkingv1alpha3 "github.com/knative/eventing/pkg/apis/istio/networking/v1alpha3"
+	sourcesv1alpha1 "github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/broker"
+	"github.com/knative/eventing/pkg/controller/eventing/channelmigration"
+	"github.com/knative/eventing/pkg/controller/eventing/eventstore"
+	"github.com/knative/eventing/pkg/controller/eventing/namespace"
+	"github.com/knative/eventing/pkg/controller/eventing/parallel"
+	"github.com/knative/eventing/pkg/controller/eventing/sequence"
 	"github.com/knative/eventing/pkg/controller/eventing/subscription"
+	"github.com/knative/eventing/pkg/controller/eventing/trigger"
+	"github.com/knative/eventing/pkg/controller/gc"
+	"github.com/knative/eventing/pkg/controller/sources/apiserversource"
+	"github.com/knative/eventing/pkg/controller/sources/awssqssource"
+	"github.com/knative/eventing/pkg/controller/sources/containersource"
+	"github.com/knative/eventing/pkg/controller/sources/cronjobsource"
+	"github.com/knative/eventing/pkg/controller/sources/githubsource"
+	"github.com/knative/eventing/pkg/controller/sources/kafkasource"
+	"github.com/knative/eventing/pkg/controller/sources/mqttsource"
+	"github.com/knative/eventing/pkg/controller/sources/sinkbinding"
+	"github.com/knative/eventing/pkg/controller/sources/webhooksource"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	"github.com/knative/eventing/pkg/leaderelection"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,7 +62,23 @@ type ProvideFunc func(manager.Manager) (controller.Controller, error)
 // controller-runtime. When the controllers are no longer experimental they may
 // be added to the default providers list.
 var ExperimentalControllers = map[string]ProvideFunc{
-	"subscription.eventing.knative.dev": subscription.ProvideController,
+	"subscription.eventing.knative.dev":            subscription.ProvideController,
+	"broker.eventing.knative.dev":                  broker.ProvideController,
+	"trigger.eventing.knative.dev":                 trigger.ProvideController,
+	"namespace.eventing.knative.dev":               namespace.ProvideController,
+	"sequence.eventing.knative.dev":                sequence.ProvideController,
+	"parallel.eventing.knative.dev":                parallel.ProvideController,
+	"eventstore.eventing.knative.dev":              eventstore.ProvideController,
+	"channelmigration.eventing.knative.dev":        channelmigration.ProvideController,
+	"apiserversource.sources.eventing.knative.dev": apiserversource.ProvideController,
+	"awssqssource.sources.eventing.knative.dev":    awssqssource.ProvideController,
+	"containersource.sources.eventing.knative.dev": containersource.ProvideController,
+	"cronjobsource.sources.eventing.knative.dev":   cronjobsource.ProvideController,
+	"githubsource.sources.eventing.knative.dev":    githubsource.ProvideController,
+	"kafkasource.sources.eventing.knative.dev":     kafkasource.ProvideController,
+	"mqttsource.sources.eventing.knative.dev":      mqttsource.ProvideController,
+	"sinkbinding.sources.eventing.knative.dev":     sinkbinding.ProvideController,
+	"webhooksource.sources.eventing.knative.dev":   webhooksource.ProvideController,
 }
 
 // controllerRuntimeStart runs controllers written for controller-runtime. It's
@@ -50,8 +87,13 @@ var ExperimentalControllers = map[string]ProvideFunc{
 func controllerRuntimeStart(logger *zap.SugaredLogger, experimental string) error {
 	logf.SetLogger(logf.ZapLogger(false))
 
+	// Tune the controllers and their resync period by the flags of tuningOptions.
+	if err := tuning.Configure(tuningOptions); err != nil {
+		return err
+	}
+
 	// Setup a Manager
-	mrg, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	mrg, err := manager.New(config.GetConfigOrDie(), tuningOptions.ManagerOptions(manager.Options{}))
 	if err != nil {
 		return err
 	}
@@ -59,7 +101,9 @@ func controllerRuntimeStart(logger *zap.SugaredLogger, experimental string) erro
 	// Add custom types to this array to get them into the manager's scheme.
 	schemeFuncs := []SchemeFunc{
 		istiov1alpha3.AddToScheme,
+		istionetworkingv1alpha3.AddToScheme,
 		eventingv1alpha1.AddToScheme,
+		sourcesv1alpha1.AddToScheme,
 	}
 	for _, schemeFunc := range schemeFuncs {
 		schemeFunc(mrg.GetScheme())
@@ -76,7 +120,19 @@ func controllerRuntimeStart(logger *zap.SugaredLogger, experimental string) erro
 		}
 	}
 
-	return mrg.Start(signals.SetupSignalHandler())
+	// Sweep the orphaned objects of Channels, only on the leader like the controllers.
+	if orphanSweepInterval > 0 {
+		sweeper := gc.NewSweeper(mrg.GetClient(), orphanSweepInterval, orphanSweepDryRun, logger.Desugar().Named("gc"))
+		if err := mrg.Add(sweeper); err != nil {
+			return err
+		}
+	}
+
+	election, err := leaderelection.ConfigFromEnv("eventing-controller")
+	if err != nil {
+		return err
+	}
+	return leaderelection.RunManager(signals.SetupSignalHandler(), election, mrg, logger.Desugar())
 }
 
 func getExperimentalControllers(logger *zap.SugaredLogger, experimental string) []ProvideFunc {
@@ -26,8 +26,13 @@ import (
 	// Uncomment the following line to load the gcp plugin (only required to authenticate against GKE clusters).
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/channeldefaulter"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	"github.com/knative/eventing/pkg/debug"
 	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/system"
+	"github.com/knative/eventing/pkg/tracing"
 	"github.com/knative/pkg/configmap"
 	"github.com/knative/pkg/logging"
 	"github.com/knative/pkg/logging/logkey"
@@ -39,14 +44,16 @@ import (
 )
 
 const (
-	threadsPerController = 2
-	metricsScrapeAddr    = ":9090"
-	metricsScrapePath    = "/metrics"
+	metricsScrapeAddr = ":9090"
+	metricsScrapePath = "/metrics"
 )
 
 var (
 	experimentalControllers string
 	hardcodedLoggingConfig  bool
+	tuningOptions           = tuning.DefaultOptions()
+	orphanSweepInterval     time.Duration
+	orphanSweepDryRun       bool
 )
 
 func main() {
@@ -81,6 +88,14 @@ func main() {
 	// Watch the logging config map and dynamically update logging levels.
 	configMapWatcher := configmap.NewInformedWatcher(kubeClient, system.Namespace)
 	configMapWatcher.Watch(logconfig.ConfigName, logging.UpdateLevelFromConfigMap(logger, atomicLevel, logconfig.Controller, logconfig.Controller))
+	// Watch the tracing config map and dynamically update the export of spans.
+	configMapWatcher.Watch(tracing.ConfigName, tracing.UpdateExporterFromConfigMap(logconfig.Controller, logger))
+	// Watch the default Channel ConfigMaps, so that the Brokers, Sequences and Parallels without a
+	// channelTemplate create their Channels with the default template of their namespace.
+	channelDefaulter := channeldefaulter.New(logger.Desugar())
+	eventingv1alpha1.ChannelDefaulterSingleton = channelDefaulter
+	configMapWatcher.Watch(channeldefaulter.ConfigMapName, channelDefaulter.UpdateConfigMap)
+	configMapWatcher.Watch(channeldefaulter.TemplatesConfigMapName, channelDefaulter.UpdateTemplatesConfigMap)
 	if err = configMapWatcher.Start(stopCh); err != nil {
 		logger.Fatalf("failed to start controller config map watcher: %v", err)
 	}
@@ -92,9 +107,11 @@ func main() {
 		}
 	}()
 
-	// Start the endpoint that Prometheus scraper talks to
-	srv := &http.Server{Addr: metricsScrapeAddr}
-	http.Handle(metricsScrapePath, promhttp.Handler())
+	// Start the endpoint that Prometheus scraper talks to. It has its own mux, so that the debug
+	// endpoints registered on the default one are not served with the metrics.
+	mux := http.NewServeMux()
+	mux.Handle(metricsScrapePath, promhttp.Handler())
+	srv := &http.Server{Addr: metricsScrapeAddr, Handler: mux}
 	go func() {
 		logger.Info("Starting metrics listener at %s", metricsScrapeAddr)
 		if err := srv.ListenAndServe(); err != nil {
@@ -102,6 +119,14 @@ func main() {
 		}
 	}()
 
+	if addr := debug.Addr(); addr != "" {
+		go func() {
+			if err := debug.Serve(addr, stopCh); err != nil {
+				logger.Errorf("Unable to serve the debug endpoints: %v", err)
+			}
+		}()
+	}
+
 	<-stopCh
 
 	// Close the http server gracefully
@@ -113,6 +138,9 @@ func main() {
 func init() {
 	flag.StringVar(&experimentalControllers, "experimentalControllers", "", "List of experimental controllers to include in the Knative Controller.")
 	flag.BoolVar(&hardcodedLoggingConfig, "hardCodedLoggingConfig", false, "If true, use the hard coded logging config. It is intended to be used only when debugging outside a Kubernetes cluster.")
+	flag.DurationVar(&orphanSweepInterval, "orphanSweepInterval", 10*time.Minute, "How often the Services and VirtualServices of Channels that no longer exist are deleted. They are not if it is 0.")
+	flag.BoolVar(&orphanSweepDryRun, "orphanSweepDryRun", false, "If true, the Services and VirtualServices of Channels that no longer exist are logged instead of deleted.")
+	tuningOptions.AddFlags(flag.CommandLine)
 }
 
 func getLoggingConfigOrDie() map[string]string {
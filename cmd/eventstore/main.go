@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The event store. It archives the events delivered to it by the archive Subscriptions of
+// Channels to object storage, and deletes them once they are older than the retention.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/knative/eventing/pkg/eventstore"
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+
+	retentionInterval time.Duration
+)
+
+func init() {
+	flag.DurationVar(&retentionInterval, "retention_interval", time.Hour, "How often the events older than the retention are deleted.")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+
+	config, err := eventstore.FromEnv()
+	if err != nil {
+		logger.Fatal("Unable to configure the event store", zap.Error(err))
+	}
+	maxBodySize, err := provisioners.MaxBodySizeFromEnv()
+	if err != nil {
+		logger.Fatal("Unable to read the maximum body size", zap.Error(err))
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	archiver := eventstore.NewArchiver(*config, logger)
+	go archiver.RunRetention(retentionInterval, stopCh)
+
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", provisioners.MessageReceiverPort),
+		Handler:      eventstore.NewHandler(archiver, maxBodySize, logger),
+		ErrorLog:     zap.NewStdLog(logger),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			logger.Error("Unable to shut down cleanly", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Event store listening...", zap.String("Address", s.Addr), zap.String("prefix", config.Prefix), zap.Int("retentionDays", config.RetentionDays))
+	if err := s.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Fatal("Unable to serve", zap.Error(err))
+	}
+}
@@ -25,13 +25,28 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/logconfig"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/amqp/ingress"
+	"github.com/knative/eventing/pkg/provisioners/audit"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/faults"
+	"github.com/knative/eventing/pkg/provisioners/push"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+	"github.com/knative/eventing/pkg/provisioners/signing"
+	"github.com/knative/eventing/pkg/provisioners/tap"
 	"github.com/knative/eventing/pkg/sidecar/configmap/filesystem"
 	"github.com/knative/eventing/pkg/sidecar/configmap/watcher"
+	"github.com/knative/eventing/pkg/sidecar/fanout"
+	"github.com/knative/eventing/pkg/sidecar/sharding"
 	"github.com/knative/eventing/pkg/sidecar/swappable"
 	"github.com/knative/eventing/pkg/system"
+	"github.com/knative/eventing/pkg/tracing"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/client-go/kubernetes"
@@ -43,6 +58,10 @@ import (
 const (
 	defaultConfigMapName = "in-memory-channel-dispatcher-config-map"
 
+	// dispatcherStatefulSetName is the name of the StatefulSet whose pods dispatch the shards of
+	// the in-memory channels.
+	dispatcherStatefulSetName = "in-memory-channel-dispatcher"
+
 	// The following are the only valid values of the config_map_noticer flag.
 	cmnfVolume  = "volume"
 	cmnfWatcher = "watcher"
@@ -53,6 +72,13 @@ var (
 	writeTimeout = 1 * time.Minute
 
 	port               int
+	metricsPort        int
+	metricsGranularity string
+	healthPort         int
+	strictCloudEvents  bool
+	fanoutConcurrency  int
+	fanoutQueueSize    int
+	maxBodySize        int64
 	configMapNoticer   string
 	configMapNamespace string
 	configMapName      string
@@ -60,6 +86,13 @@ var (
 
 func init() {
 	flag.IntVar(&port, "sidecar_port", -1, "The port to run the sidecar on.")
+	flag.IntVar(&metricsPort, "metrics_port", -1, "The port to serve the Prometheus metrics on. They are not served if it is not set.")
+	flag.StringVar(&metricsGranularity, "metrics_granularity", string(provisioners.SubscriptionGranularity), "The finest level the delivery metrics are labeled at: subscription, channel or namespace.")
+	flag.IntVar(&healthPort, "health_port", -1, "The port to serve the /healthz and /readyz endpoints on. They are not served if it is not set.")
+	flag.BoolVar(&strictCloudEvents, "strict_cloudevents", false, "Reject the events that are not valid CloudEvents with a 400, rather than fanning them out.")
+	flag.IntVar(&fanoutConcurrency, "fanout_concurrency", 100, "The number of events of each channel delivered to subscribers at once. Deliveries are unbounded if it is zero.")
+	flag.IntVar(&fanoutQueueSize, "fanout_queue_size", 1000, "The number of deliveries of each channel waiting for one of the --fanout_concurrency deliveries to finish. Events that would exceed it are rejected.")
+	flag.Int64Var(&maxBodySize, "max_body_size", provisioners.DefaultMaxBodySize, "The largest body, in bytes, of the events accepted and of the replies forwarded. Bodies are unbounded if it is zero.")
 	flag.StringVar(&configMapNoticer, "config_map_noticer", "", fmt.Sprintf("The system to notice changes to the ConfigMap. Valid values are: %s", configMapNoticerValues()))
 	flag.StringVar(&configMapNamespace, "config_map_namespace", system.Namespace, "The namespace of the ConfigMap that is watched for configuration.")
 	flag.StringVar(&configMapName, "config_map_name", defaultConfigMapName, "The name of the ConfigMap that is watched for configuration.")
@@ -72,21 +105,118 @@ func configMapNoticerValues() string {
 func main() {
 	flag.Parse()
 
-	logger, err := zap.NewProduction()
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	kc, err := kubernetes.NewForConfig(config.GetConfigOrDie())
 	if err != nil {
-		log.Fatalf("Unable to create logger: %v", err)
+		log.Fatalf("Unable to create kubernetes client: %v", err)
 	}
 
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.InMemoryDispatcher, stopCh).Desugar()
+
 	if port < 0 {
 		logger.Fatal("--sidecar_port flag must be set")
 	}
+	if err := provisioners.SetMetricsGranularity(metricsGranularity); err != nil {
+		logger.Fatal("Invalid --metrics_granularity flag", zap.Error(err))
+	}
+	authResolver := auth.NewResolver(auth.KubeSecretGetter(kc))
+	schemaResolver := schema.NewResolver(schema.KubeConfigMapGetter(kc))
+
+	// The events being fanned out are the backlog of the in-memory channels.
+	backlog := provisioners.NewBacklogCounter()
+	if err := provisioners.RegisterBacklog(backlog); err != nil {
+		logger.Fatal("Unable to register the backlog metric", zap.Error(err))
+	}
 
-	sh, err := swappable.NewEmptyHandler(logger)
+	opts := []fanout.Option{fanout.WithAuthResolver(authResolver), fanout.WithSchemaResolver(schemaResolver), fanout.WithBacklogCounter(backlog)}
+	if strictCloudEvents {
+		opts = append(opts, fanout.WithStrictCloudEvents())
+	}
+	if maxBodySize < 0 {
+		logger.Fatal("--max_body_size flag must not be negative")
+	}
+	opts = append(opts, fanout.WithMaxBodySize(maxBodySize))
+	if names := provisioners.ReplyExtensionsFromEnv(); len(names) > 0 {
+		opts = append(opts, fanout.WithReplyExtensions(names))
+	}
+	if fanoutConcurrency < 0 || fanoutQueueSize < 0 {
+		logger.Fatal("--fanout_concurrency and --fanout_queue_size flags must not be negative")
+	}
+	if fanoutConcurrency > 0 {
+		opts = append(opts, fanout.WithWorkerPool(fanoutConcurrency, fanoutQueueSize))
+	}
+	auditSink, err := audit.FromEnv(logger, stopCh)
+	if err != nil {
+		logger.Fatal("Invalid audit configuration", zap.Error(err))
+	}
+	if auditSink != nil {
+		opts = append(opts, fanout.WithAuditSink(auditSink))
+	}
+	// Events can be signed at the ingress, so that the subscribers can authenticate their origin,
+	// and their signature verified before they are delivered.
+	signer, err := signing.SignerFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid signing configuration", zap.Error(err))
+	}
+	if signer != nil {
+		opts = append(opts, fanout.WithSigning(signer))
+	}
+	verifier, err := signing.VerifierFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid signature verification configuration", zap.Error(err))
+	}
+	if verifier != nil {
+		opts = append(opts, fanout.WithSignatureVerification(verifier))
+	}
+	tlsConfig, err := auth.TLSConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid client certificate configuration", zap.Error(err))
+	}
+	if tlsConfig != nil {
+		opts = append(opts, fanout.WithTLSConfig(tlsConfig))
+	}
+	tapHub, err := tap.FromEnv(kc, logger, stopCh)
+	if err != nil {
+		logger.Fatal("Invalid tap configuration", zap.Error(err))
+	}
+	if tapHub != nil {
+		opts = append(opts, fanout.WithTap(tapHub))
+	}
+	pushHub, err := push.FromEnv(kc, logger, stopCh)
+	if err != nil {
+		logger.Fatal("Invalid push configuration", zap.Error(err))
+	}
+	if pushHub != nil {
+		opts = append(opts, fanout.WithPusher(pushHub))
+	}
+	// Faults are only injected in test environments, to validate the subscribers.
+	injector, err := faults.FromEnv(logger)
+	if err != nil {
+		logger.Fatal("Invalid fault injection configuration", zap.Error(err))
+	}
+	if injector != nil {
+		opts = append(opts, fanout.WithFaultInjection(injector))
+	}
+	if err := ingress.FromEnv(port, logger, stopCh); err != nil {
+		logger.Fatal("Invalid AMQP configuration", zap.Error(err))
+	}
+	sh, err := swappable.NewEmptyHandler(logger, opts...)
 	if err != nil {
 		logger.Fatal("Unable to create swappable.Handler", zap.Error(err))
 	}
 
-	mgr, err := setupConfigMapNoticer(logger, sh.UpdateConfig)
+	// The replicas of a sharded dispatcher only fan out the channels of their shard, which is the
+	// ordinal of their pod in the dispatcher StatefulSet.
+	updateConfig := sh.UpdateConfig
+	hostname, _ := os.Hostname()
+	if shard, ok := sharding.Ordinal(hostname, dispatcherStatefulSetName); ok {
+		logger.Info("Fanning out the channels of the shard of this replica when sharded", zap.Int("shard", shard))
+		updateConfig = sharding.Filter(shard, sh.UpdateConfig)
+	}
+
+	mgr, err := setupConfigMapNoticer(logger, updateConfig)
 	if err != nil {
 		logger.Fatal("Unable to create configMap noticer.", zap.Error(err))
 	}
@@ -99,16 +229,36 @@ func main() {
 		WriteTimeout: writeTimeout,
 	}
 
+	if err := tracing.WatchConfigMap(kc, system.Namespace, logconfig.InMemoryDispatcher, logger.Sugar(), stopCh); err != nil {
+		logger.Warn("Spans are not exported until the tracing config map is created", zap.Error(err))
+	}
+
 	// Start both the manager (which notices ConfigMap changes) and the HTTP server.
 	var g errgroup.Group
 	g.Go(func() error {
-		// set up signals so we handle the first shutdown signal gracefully
-		stopCh := signals.SetupSignalHandler()
 		// Start blocks forever, so run it in a goroutine.
 		return mgr.Start(stopCh)
 	})
 	logger.Info("Fanout sidecar Listening...", zap.String("Address", s.Addr))
 	g.Go(s.ListenAndServe)
+	if metricsPort >= 0 {
+		ms := &http.Server{
+			Addr:    fmt.Sprintf(":%d", metricsPort),
+			Handler: provisioners.MetricsHandler(),
+		}
+		g.Go(ms.ListenAndServe)
+	}
+	if addr := debug.Addr(); addr != "" {
+		g.Go(func() error {
+			return debug.Serve(addr, stopCh)
+		})
+	}
+	if healthPort >= 0 {
+		checks := map[string]provisioners.ReadinessCheck{"config": sh.Ready}
+		g.Go(func() error {
+			return provisioners.ServeHealth(fmt.Sprintf(":%d", healthPort), checks, stopCh)
+		})
+	}
 	err = g.Wait()
 	if err != nil {
 		logger.Error("Either the HTTP server or the ConfigMap noticer failed.", zap.Error(err))
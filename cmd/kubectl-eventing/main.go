@@ -0,0 +1,386 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-eventing is a kubectl plugin for working with Knative Eventing. Install it on the PATH
+// and run `kubectl eventing <command>`.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/knative/eventing/pkg/cli"
+	"github.com/knative/eventing/pkg/client/clientset/versioned"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// commands are the subcommands of the plugin, by name.
+var commands = map[string]struct {
+	usage string
+	run   func(args []string) error
+}{
+	"export":        {"Write the Channels and Subscriptions to a portable bundle", export},
+	"import":        {"Create the Channels and Subscriptions of a bundle", importBundle},
+	"send":          {"Send a test CloudEvent to a Channel, Broker, Sequence or Parallel", send},
+	"subscriptions": {"List the subscribers of a Channel, their status and deliveries", subscriptions},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(1)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: kubectl eventing <command> [flags]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'kubectl eventing <command> --help' for the flags of a command.")
+}
+
+// kubeFlags are the flags selecting the cluster and namespace, shared by the commands.
+type kubeFlags struct {
+	kubeconfig string
+	context    string
+	namespace  string
+}
+
+func (k *kubeFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&k.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to $KUBECONFIG or ~/.kube/config.")
+	fs.StringVar(&k.context, "context", "", "The kubeconfig context to use. Defaults to the current context.")
+	fs.StringVar(&k.namespace, "namespace", "", "The namespace of the object. Defaults to the namespace of the context.")
+	fs.StringVar(&k.namespace, "n", "", "Shorthand for --namespace.")
+}
+
+// clients returns the clients of the selected cluster, and the selected namespace.
+func (k *kubeFlags) clients() (*cli.Resolver, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = k.kubeconfig
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: k.context})
+	namespace := k.namespace
+	if namespace == "" {
+		var err error
+		if namespace, _, err = config.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+	cfg, err := config.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	eventingClient, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	proxy := func(namespace, pod string, port int, path string) ([]byte, error) {
+		return kubeClient.CoreV1().RESTClient().Get().
+			Namespace(namespace).
+			Resource("pods").
+			Name(fmt.Sprintf("%s:%d", pod, port)).
+			SubResource("proxy").
+			Suffix(path).
+			DoRaw()
+	}
+	return &cli.Resolver{Eventing: eventingClient, Dynamic: dynamicClient, Proxy: proxy}, namespace, nil
+}
+
+// args returns the flags passed on to kubectl.
+func (k *kubeFlags) args() []string {
+	var args []string
+	if k.kubeconfig != "" {
+		args = append(args, "--kubeconfig", k.kubeconfig)
+	}
+	if k.context != "" {
+		args = append(args, "--context", k.context)
+	}
+	return args
+}
+
+func send(args []string) error {
+	fs := flag.NewFlagSet("send", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: kubectl eventing send (--channel|--broker|--sequence|--parallel) NAME [flags]\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	var k kubeFlags
+	k.register(fs)
+	targets := map[string]*string{}
+	for _, kind := range []string{"channel", "broker", "sequence", "parallel"} {
+		targets[kind] = fs.String(kind, "", fmt.Sprintf("The name of the %s to send the event to.", kind))
+	}
+	portForward := fs.Bool("port-forward", false, "Reach the object through kubectl port-forward, from outside the cluster.")
+	kubectl := fs.String("kubectl", "kubectl", "The kubectl binary used by --port-forward.")
+	timeout := fs.Duration("timeout", 30*time.Second, "How long to wait for the response.")
+	var event cli.Event
+	fs.StringVar(&event.ID, "id", "", "The ID of the event. Defaults to a generated UUID.")
+	fs.StringVar(&event.Type, "type", "dev.knative.eventing.test", "The type of the event.")
+	fs.StringVar(&event.Source, "source", "", "The source of the event. Defaults to the hostname.")
+	fs.StringVar(&event.Data, "data", `{"hello": "world!"}`, "The data of the event, a JSON document.")
+	fs.Parse(args)
+
+	kind, name := "", ""
+	for t, v := range targets {
+		if *v == "" {
+			continue
+		}
+		if kind != "" {
+			return fmt.Errorf("only one of --channel, --broker, --sequence and --parallel may be set")
+		}
+		kind, name = t, *v
+	}
+	if kind == "" {
+		fs.Usage()
+		return fmt.Errorf("one of --channel, --broker, --sequence and --parallel is required")
+	}
+
+	resolver, namespace, err := k.clients()
+	if err != nil {
+		return err
+	}
+	hostname, err := resolver.Hostname(kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	url, host := "http://"+hostname+"/", ""
+	if *portForward {
+		target, err := resolver.Target(hostname)
+		if err != nil {
+			return err
+		}
+		local, stop, err := cli.PortForward(*kubectl, k.args(), target)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		url, host = "http://"+local+"/", target.Host
+	}
+
+	res, body, err := cli.Send(&http.Client{Timeout: *timeout}, url, host, event)
+	if err != nil {
+		return fmt.Errorf("failed to send the event to %s %s/%s: %v", kind, namespace, name, err)
+	}
+	fmt.Printf("Sent the event to %s %s/%s (%s): %s\n", kind, namespace, name, hostname, res.Status)
+	if len(body) > 0 {
+		fmt.Println(string(body))
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the event was not accepted")
+	}
+	return nil
+}
+
+func subscriptions(args []string) error {
+	fs := flag.NewFlagSet("subscriptions", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: kubectl eventing subscriptions --channel NAME [flags]\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	var k kubeFlags
+	k.register(fs)
+	channel := fs.String("channel", "", "The name of the Channel.")
+	metricsPort := fs.Int("metrics-port", 9090, "The port serving the metrics of the dispatchers.")
+	noMetrics := fs.Bool("no-metrics", false, "Do not read the deliveries and backlog from the metrics of the dispatchers.")
+	fs.Parse(args)
+	if *channel == "" {
+		fs.Usage()
+		return fmt.Errorf("--channel is required")
+	}
+
+	resolver, namespace, err := k.clients()
+	if err != nil {
+		return err
+	}
+	if *noMetrics {
+		resolver.Proxy = nil
+	}
+	report, err := resolver.InspectChannel(namespace, *channel, *metricsPort)
+	if err != nil {
+		return err
+	}
+
+	backlog := "-"
+	if report.Backlog != nil {
+		backlog = strconv.FormatInt(*report.Backlog, 10)
+	}
+	fmt.Printf("Channel %s/%s (provisioner %s): Ready=%s Hostname=%s Backlog=%s\n",
+		report.Namespace, report.Name, report.Provisioner, report.Ready, orDash(report.Hostname), backlog)
+	if report.MetricsError != nil {
+		fmt.Printf("Warning: unable to read the metrics of the dispatchers: %v\n", report.MetricsError)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBSCRIPTION\tREADY\tSUBSCRIBER\tREPLY\tDEAD LETTER\tON ERROR\tPAUSED\tDELIVERED\tFAILURES\tRETRIES")
+	for _, s := range report.Subscribers {
+		name, ready := "-", "-"
+		if s.Ref != nil {
+			name = s.Ref.Namespace + "/" + s.Ref.Name
+		}
+		if s.Ready != "" {
+			ready = string(s.Ready)
+		}
+		if s.Reason != "" {
+			ready += " (" + s.Reason + ")"
+		}
+		delivered, failures, retries := "-", "-", "-"
+		if d := s.Deliveries; d != nil {
+			delivered = strconv.FormatFloat(d.Delivered, 'f', -1, 64)
+			failures = strconv.FormatFloat(d.Failures, 'f', -1, 64)
+			retries = strconv.FormatFloat(d.Retries, 'f', -1, 64)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\t%s\t%s\n", name, ready,
+			orDash(s.SubscriberURI), orDash(s.ReplyURI), orDash(s.DeadLetterURI), orDash(s.ErrorURI), s.Paused,
+			delivered, failures, retries)
+	}
+	return w.Flush()
+}
+
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: kubectl eventing export [flags] > bundle.yaml\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	var k kubeFlags
+	k.register(fs)
+	allNamespaces := fs.Bool("all-namespaces", false, "Export the objects of all namespaces.")
+	output := fs.String("o", "", "The file to write the bundle to. Defaults to stdout.")
+	fs.Parse(args)
+
+	resolver, namespace, err := k.clients()
+	if err != nil {
+		return err
+	}
+	if *allNamespaces {
+		namespace = ""
+	}
+	bundle, err := resolver.Export(namespace)
+	if err != nil {
+		return err
+	}
+	data, err := cli.MarshalBundle(bundle)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = ioutil.WriteFile(*output, data, 0644)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d Channels and %d Subscriptions\n", len(bundle.Channels), len(bundle.Subscriptions))
+	if len(bundle.Provisioners) > 0 {
+		fmt.Fprintf(os.Stderr, "They require the ClusterChannelProvisioners: %s\n", strings.Join(bundle.Provisioners, ", "))
+	}
+	for _, s := range bundle.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s, which is recreated by its controller\n", s)
+	}
+	return nil
+}
+
+func importBundle(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: kubectl eventing import -f bundle.yaml [flags]\n\nFlags:")
+		fs.PrintDefaults()
+	}
+	var k kubeFlags
+	k.register(fs)
+	file := fs.String("f", "", "The bundle written by export, or - for stdin.")
+	overwrite := fs.Bool("overwrite", false, "Replace the spec of the objects that already exist.")
+	fs.Parse(args)
+	if *file == "" {
+		fs.Usage()
+		return fmt.Errorf("-f is required")
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+	bundle, err := cli.UnmarshalBundle(data)
+	if err != nil {
+		return err
+	}
+	resolver, _, err := k.clients()
+	if err != nil {
+		return err
+	}
+	result, err := resolver.Import(bundle, *overwrite)
+	if result != nil {
+		for _, o := range result.Created {
+			fmt.Printf("%s created\n", o)
+		}
+		for _, o := range result.Updated {
+			fmt.Printf("%s updated\n", o)
+		}
+		for _, o := range result.Unchanged {
+			fmt.Printf("%s unchanged\n", o)
+		}
+	}
+	return err
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2019 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The event recorder. It records the events delivered to it, e.g. by a Subscription, and serves
+// them back through the query API of package recordevents, to validate event pipelines.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/recordevents"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+
+	maxEvents int
+)
+
+func init() {
+	flag.IntVar(&maxEvents, "max_events", recordevents.DefaultMaxEvents, "The number of events kept, the oldest being forgotten first. Every event is kept if it is zero.")
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+	if maxEvents < 0 {
+		logger.Fatal("--max_events flag must not be negative")
+	}
+	maxBodySize, err := provisioners.MaxBodySizeFromEnv()
+	if err != nil {
+		logger.Fatal("Unable to read the maximum body size", zap.Error(err))
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", provisioners.MessageReceiverPort),
+		Handler:      recordevents.NewRecorder(maxEvents, maxBodySize, logger),
+		ErrorLog:     zap.NewStdLog(logger),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			logger.Error("Unable to shut down cleanly", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Event recorder listening...", zap.String("Address", s.Addr), zap.Int("maxEvents", maxEvents))
+	if err := s.ListenAndServe(); err != http.ErrServerClosed {
+		logger.Fatal("Unable to serve", zap.Error(err))
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The apiserver receive adapter watches the resources of an ApiServerSource and sends an event to
+// its sink every time one of them is added, updated or deleted.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/apiserversource"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+func main() {
+	adapter.Main("apiserversource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		var resources []apiserversource.Resource
+		if err := json.Unmarshal([]byte(os.Getenv("RESOURCES")), &resources); err != nil {
+			return nil, fmt.Errorf("unable to parse the resources: %v", err)
+		}
+		namespaces := strings.Split(os.Getenv("NAMESPACES"), ",")
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := dynamic.NewForConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Watching resources", zap.Strings("namespaces", namespaces))
+		return &apiserversource.Adapter{
+			Resources:  resources,
+			Namespaces: namespaces,
+			Mode:       v1alpha1.ApiServerSourceMode(os.Getenv("MODE")),
+			Source:     cfg.Host,
+			Dynamic:    dynamicClient,
+			Logger:     logger,
+			Client:     client,
+		}, nil
+	})
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The awssqs receive adapter receives the messages of the SQS queue of an AwsSqsSource and sends
+// them to its sink.
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/awssqssource"
+	"go.uber.org/zap"
+)
+
+func main() {
+	adapter.Main("awssqssource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		concurrency, _ := strconv.Atoi(os.Getenv("SQS_CONCURRENCY"))
+		visibilityTimeout, _ := strconv.Atoi(os.Getenv("SQS_VISIBILITY_TIMEOUT"))
+		a := &awssqssource.Adapter{
+			QueueURL: os.Getenv("AWS_SQS_URL"),
+			Region:   os.Getenv("AWS_REGION"),
+			// Reads AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+			Credentials:       credentials.NewEnvCredentials(),
+			Concurrency:       concurrency,
+			VisibilityTimeout: time.Duration(visibilityTimeout) * time.Second,
+			Logger:            logger,
+			Client:            client,
+		}
+		logger.Info("Receiving messages", zap.String("queueURL", a.QueueURL), zap.Int("concurrency", a.Concurrency))
+		return a, nil
+	})
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The cronjob receive adapter sends an event to the sink of a CronJobSource every time its schedule
+// fires, and records the time in the status of the CronJobSource.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/cronjobsource"
+	clientset "github.com/knative/eventing/pkg/client/clientset/versioned"
+	"github.com/knative/eventing/pkg/cron"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+func main() {
+	adapter.Main("cronjobsource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		schedule, err := cron.Parse(os.Getenv("SCHEDULE"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the schedule: %v", err)
+		}
+
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return nil, err
+		}
+		sourcesClient, err := clientset.NewForConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Sending events", zap.String("schedule", os.Getenv("SCHEDULE")))
+		return &cronjobsource.Adapter{
+			Schedule: schedule,
+			Data:     os.Getenv("DATA"),
+			Source:   fmt.Sprintf("/apis/v1/namespaces/%s/cronjobsources/%s", env.Namespace, env.Name),
+			OnFire: func(t time.Time) error {
+				sources := sourcesClient.SourcesV1alpha1().CronJobSources(env.Namespace)
+				s, err := sources.Get(env.Name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				lastScheduleTime := metav1.NewTime(t)
+				s.Status.LastScheduleTime = &lastScheduleTime
+				_, err = sources.Update(s)
+				return err
+			},
+			Logger: logger,
+			Client: client,
+		}, nil
+	})
+}
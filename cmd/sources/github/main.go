@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The github receive adapter receives the webhook requests of GitHub for a GitHubSource, and sends
+// their payloads to its sink.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/githubsource"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"go.uber.org/zap"
+)
+
+func main() {
+	adapter.Main("githubsource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		secretToken := os.Getenv("SECRET_TOKEN")
+		if secretToken == "" {
+			return nil, errors.New("the SECRET_TOKEN environment variable is not set")
+		}
+		ownerRepo := os.Getenv("OWNER_REPO")
+		logger.Info("Receiving webhook requests", zap.String("repository", ownerRepo))
+		return &githubsource.Adapter{
+			SecretToken: secretToken,
+			Source:      fmt.Sprintf("%s/%s", v1alpha1.GitHubEventSourcePrefix, ownerRepo),
+			Logger:      logger,
+			Client:      client,
+		}, nil
+	})
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The kafka receive adapter consumes the topics of a KafkaSource and sends their records to its
+// sink.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/kafkasource"
+	"go.uber.org/zap"
+)
+
+func main() {
+	adapter.Main("kafkasource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		saslEnable, _ := strconv.ParseBool(os.Getenv("KAFKA_NET_SASL_ENABLE"))
+		tlsEnable, _ := strconv.ParseBool(os.Getenv("KAFKA_NET_TLS_ENABLE"))
+		maxRetries, _ := strconv.Atoi(os.Getenv("KAFKA_MAX_RETRIES"))
+		a := &kafkasource.Adapter{
+			BootstrapServers: strings.Split(os.Getenv("KAFKA_BOOTSTRAP_SERVERS"), ","),
+			Topics:           strings.Split(os.Getenv("KAFKA_TOPICS"), ","),
+			ConsumerGroup:    os.Getenv("KAFKA_CONSUMER_GROUP"),
+			Net: kafkasource.NetConfig{
+				SASLEnable:   saslEnable,
+				SASLUser:     os.Getenv("KAFKA_NET_SASL_USER"),
+				SASLPassword: os.Getenv("KAFKA_NET_SASL_PASSWORD"),
+				TLSEnable:    tlsEnable,
+				TLSCert:      os.Getenv("KAFKA_NET_TLS_CERT"),
+				TLSKey:       os.Getenv("KAFKA_NET_TLS_KEY"),
+				TLSCACert:    os.Getenv("KAFKA_NET_TLS_CA_CERT"),
+			},
+			MaxRetries: maxRetries,
+			Source:     fmt.Sprintf("/apis/v1/namespaces/%s/kafkasources/%s", env.Namespace, env.Name),
+			Logger:     logger,
+			Client:     client,
+		}
+		logger.Info("Consuming records", zap.Strings("topics", a.Topics), zap.String("consumerGroup", a.ConsumerGroup), zap.Int("maxRetries", a.MaxRetries))
+		return a, nil
+	})
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The mqtt receive adapter subscribes to the topics of an MQTTSource and sends their messages to
+// its sink.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/mqttsource"
+	"go.uber.org/zap"
+)
+
+func main() {
+	adapter.Main("mqttsource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		var topics []mqttsource.Topic
+		if err := json.Unmarshal([]byte(os.Getenv("MQTT_TOPICS")), &topics); err != nil {
+			return nil, fmt.Errorf("invalid MQTT_TOPICS: %v", err)
+		}
+		qos, _ := strconv.Atoi(os.Getenv("MQTT_QOS"))
+		tlsEnable, _ := strconv.ParseBool(os.Getenv("MQTT_NET_TLS_ENABLE"))
+		a := &mqttsource.Adapter{
+			Broker:   os.Getenv("MQTT_BROKER"),
+			Topics:   topics,
+			ClientID: os.Getenv("MQTT_CLIENT_ID"),
+			QoS:      byte(qos),
+			Net: mqttsource.NetConfig{
+				User:      os.Getenv("MQTT_NET_USER"),
+				Password:  os.Getenv("MQTT_NET_PASSWORD"),
+				TLSEnable: tlsEnable,
+				TLSCert:   os.Getenv("MQTT_NET_TLS_CERT"),
+				TLSKey:    os.Getenv("MQTT_NET_TLS_KEY"),
+				TLSCACert: os.Getenv("MQTT_NET_TLS_CA_CERT"),
+			},
+			Logger: logger,
+			Client: client,
+		}
+		logger.Info("Subscribing to topics", zap.String("broker", a.Broker), zap.Any("topics", a.Topics))
+		return a, nil
+	})
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// The webhook receive adapter accepts the JSON payloads posted to the endpoint of a WebhookSource,
+// and sends them to its sink.
+
+package main
+
+import (
+	"os"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/adapter/webhooksource"
+	"go.uber.org/zap"
+)
+
+func main() {
+	adapter.Main("webhooksource", func(env *adapter.EnvConfig, client *adapter.Client, logger *zap.Logger) (adapter.Adapter, error) {
+		a := &webhooksource.Adapter{
+			EventType:       os.Getenv("EVENT_TYPE"),
+			EventSource:     os.Getenv("EVENT_SOURCE"),
+			Secret:          os.Getenv("SECRET"),
+			SignatureHeader: os.Getenv("SIGNATURE_HEADER"),
+			Logger:          logger,
+			Client:          client,
+		}
+		logger.Info("Receiving payloads", zap.String("eventType", a.EventType), zap.Bool("signed", a.Secret != ""))
+		return a, nil
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"log"
 
 	"github.com/knative/eventing/pkg/channeldefaulter"
+	"github.com/knative/eventing/pkg/channelvalidator"
 
 	"go.uber.org/zap"
 
@@ -29,13 +30,25 @@ import (
 	"github.com/knative/pkg/signals"
 	"github.com/knative/pkg/webhook"
 
+	"github.com/knative/eventing/pkg/admission"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingv1beta1 "github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	sourcesv1alpha1 "github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/attribution"
+	"github.com/knative/eventing/pkg/client/clientset/versioned"
+	"github.com/knative/eventing/pkg/client/informers/externalversions"
+	"github.com/knative/eventing/pkg/conversion"
 	"github.com/knative/eventing/pkg/logconfig"
+	"github.com/knative/eventing/pkg/namespacequota"
+	"github.com/knative/eventing/pkg/sinkbinding"
+	"github.com/knative/eventing/pkg/subscriptionvalidator"
 	"github.com/knative/eventing/pkg/system"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 )
 
 func main() {
@@ -73,16 +86,133 @@ func main() {
 
 	configMapWatcher.Watch(logconfig.ConfigName, logging.UpdateLevelFromConfigMap(logger, atomicLevel, logconfig.Webhook, logconfig.Webhook))
 
-	// Watch the default-channel-webhook ConfigMap and dynamically update the default
-	// ClusterChannelProvisioner.
+	// Watch the default-channel-webhook and config-default-channel ConfigMaps and dynamically
+	// update the default ClusterChannelProvisioner and arguments.
 	channelDefaulter := channeldefaulter.New(logger.Desugar())
 	eventingv1alpha1.ChannelDefaulterSingleton = channelDefaulter
 	configMapWatcher.Watch(channeldefaulter.ConfigMapName, channelDefaulter.UpdateConfigMap)
+	configMapWatcher.Watch(channeldefaulter.TemplatesConfigMapName, channelDefaulter.UpdateTemplatesConfigMap)
+
+	// The namespace quota webhook rejects the Channels and Subscriptions exceeding the quotas of
+	// their namespace, which are updated when the config-namespace-quotas ConfigMap changes.
+	quotaWebhook := &namespacequota.Webhook{
+		Client: kubeClient,
+		Options: admission.Options{
+			WebhookName: "namespace-quota.webhook.eventing.knative.dev",
+			ServiceName: "namespace-quota-webhook",
+			Namespace:   system.Namespace,
+			Port:        8447,
+		},
+		Logger: logger.Desugar(),
+	}
+	configMapWatcher.Watch(namespacequota.ConfigMapName, quotaWebhook.UpdateConfigMap)
 
 	if err = configMapWatcher.Start(stopCh); err != nil {
 		logger.Fatalf("failed to start webhook configmap watcher: %v", err)
 	}
 
+	eventingClient, err := versioned.NewForConfig(clusterConfig)
+	if err != nil {
+		logger.Fatal("Failed to get the eventing client set", zap.Error(err))
+	}
+
+	// The sink binding webhook binds the subjects of SinkBindings as they are created and updated.
+	informerFactory := externalversions.NewSharedInformerFactory(eventingClient, 0)
+	sinkBindingInformer := informerFactory.Sources().V1alpha1().SinkBindings()
+	sinkBindingWebhook := &sinkbinding.Webhook{
+		Client: kubeClient,
+		Options: admission.Options{
+			WebhookName: "sinkbindings.webhook.sources.eventing.knative.dev",
+			ServiceName: "sinkbinding-webhook",
+			Namespace:   system.Namespace,
+			Port:        8443,
+		},
+		Lister: sinkBindingInformer.Lister(),
+		Logger: logger.Desugar(),
+	}
+
+	// Validate the arguments of Channels against the schemas published by their
+	// ClusterChannelProvisioners.
+	provisionerInformer := informerFactory.Eventing().V1alpha1().ClusterChannelProvisioners()
+	eventingv1alpha1.ChannelArgumentsValidatorSingleton = channelvalidator.New(provisionerInformer.Lister(), logger.Desugar())
+
+	channelInformer := informerFactory.Eventing().V1alpha1().Channels()
+	subscriptionInformer := informerFactory.Eventing().V1alpha1().Subscriptions()
+	quotaWebhook.Channels = channelInformer.Lister()
+	quotaWebhook.Subscriptions = subscriptionInformer.Lister()
+
+	informerFactory.Start(stopCh)
+	if ok := cache.WaitForCacheSync(stopCh, sinkBindingInformer.Informer().HasSynced, provisionerInformer.Informer().HasSynced,
+		channelInformer.Informer().HasSynced, subscriptionInformer.Informer().HasSynced); !ok {
+		logger.Fatal("Failed to wait for the informers to sync")
+	}
+	go func() {
+		if err := quotaWebhook.Run(stopCh); err != nil {
+			logger.Fatal("Failed to run the namespace quota webhook", zap.Error(err))
+		}
+	}()
+	go func() {
+		if err := sinkBindingWebhook.Run(stopCh); err != nil {
+			logger.Fatal("Failed to run the sink binding webhook", zap.Error(err))
+		}
+	}()
+
+	// The attribution webhook records who created and last changed the Channels and Subscriptions.
+	attributionWebhook := &attribution.Webhook{
+		Client: kubeClient,
+		Options: admission.Options{
+			WebhookName: "attribution.webhook.eventing.knative.dev",
+			ServiceName: "attribution-webhook",
+			Namespace:   system.Namespace,
+			Port:        8444,
+		},
+		Logger: logger.Desugar(),
+	}
+	go func() {
+		if err := attributionWebhook.Run(stopCh); err != nil {
+			logger.Fatal("Failed to run the attribution webhook", zap.Error(err))
+		}
+	}()
+
+	// The conversion webhook converts Channels and Subscriptions between v1alpha1 and v1beta1.
+	dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+	if err != nil {
+		logger.Fatal("Failed to get the dynamic client", zap.Error(err))
+	}
+	conversionWebhook := &conversion.Webhook{
+		Client: dynamicClient,
+		Options: conversion.Options{
+			ServiceName: "conversion-webhook",
+			Namespace:   system.Namespace,
+			Port:        8445,
+		},
+		Logger: logger.Desugar(),
+	}
+	go func() {
+		if err := conversionWebhook.Run(stopCh); err != nil {
+			logger.Fatal("Failed to run the conversion webhook", zap.Error(err))
+		}
+	}()
+
+	// The subscription validation webhook rejects the Subscriptions whose references cannot resolve.
+	subscriptionValidationWebhook := &subscriptionvalidator.Webhook{
+		Client:    kubeClient,
+		Discovery: kubeClient.Discovery(),
+		Dynamic:   dynamicClient,
+		Options: admission.Options{
+			WebhookName: "subscription-validation.webhook.eventing.knative.dev",
+			ServiceName: "subscription-validation-webhook",
+			Namespace:   system.Namespace,
+			Port:        8446,
+		},
+		Logger: logger.Desugar(),
+	}
+	go func() {
+		if err := subscriptionValidationWebhook.Run(stopCh); err != nil {
+			logger.Fatal("Failed to run the subscription validation webhook", zap.Error(err))
+		}
+	}()
+
 	options := webhook.ControllerOptions{
 		ServiceName:    "webhook",
 		DeploymentName: "webhook",
@@ -96,9 +226,27 @@ func main() {
 		Options: options,
 		Handlers: map[schema.GroupVersionKind]webhook.GenericCRD{
 			// For group eventing.knative.dev,
+			eventingv1alpha1.SchemeGroupVersion.WithKind("Broker"):                    &eventingv1alpha1.Broker{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("Channel"):                   &eventingv1alpha1.Channel{},
+			eventingv1alpha1.SchemeGroupVersion.WithKind("ChannelMigration"):          &eventingv1alpha1.ChannelMigration{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("ClusterChannelProvisioner"): &eventingv1alpha1.ClusterChannelProvisioner{},
+			eventingv1alpha1.SchemeGroupVersion.WithKind("EventType"):                 &eventingv1alpha1.EventType{},
+			eventingv1alpha1.SchemeGroupVersion.WithKind("Parallel"):                  &eventingv1alpha1.Parallel{},
+			eventingv1alpha1.SchemeGroupVersion.WithKind("Sequence"):                  &eventingv1alpha1.Sequence{},
 			eventingv1alpha1.SchemeGroupVersion.WithKind("Subscription"):              &eventingv1alpha1.Subscription{},
+			eventingv1alpha1.SchemeGroupVersion.WithKind("Trigger"):                   &eventingv1alpha1.Trigger{},
+			eventingv1beta1.SchemeGroupVersion.WithKind("Channel"):                    &eventingv1beta1.Channel{},
+			eventingv1beta1.SchemeGroupVersion.WithKind("Subscription"):               &eventingv1beta1.Subscription{},
+			// For group sources.eventing.knative.dev,
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("ApiServerSource"): &sourcesv1alpha1.ApiServerSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("AwsSqsSource"):    &sourcesv1alpha1.AwsSqsSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("ContainerSource"): &sourcesv1alpha1.ContainerSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("CronJobSource"):   &sourcesv1alpha1.CronJobSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("GitHubSource"):    &sourcesv1alpha1.GitHubSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("KafkaSource"):     &sourcesv1alpha1.KafkaSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("MQTTSource"):      &sourcesv1alpha1.MQTTSource{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("SinkBinding"):     &sourcesv1alpha1.SinkBinding{},
+			sourcesv1alpha1.SchemeGroupVersion.WithKind("WebhookSource"):   &sourcesv1alpha1.WebhookSource{},
 		},
 		Logger: logger,
 	}
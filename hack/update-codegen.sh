@@ -28,13 +28,14 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(cd ${REPO_ROOT_DIR}; ls -d -1 ./vendor/k8s.io/code-
 #                  instead of the $GOPATH directly. For normal projects this can be dropped.
 ${CODEGEN_PKG}/generate-groups.sh "deepcopy,client,informer,lister" \
   github.com/knative/eventing/pkg/client github.com/knative/eventing/pkg/apis \
-  "eventing:v1alpha1" \
+  "eventing:v1alpha1,v1beta1 sources:v1alpha1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate/boilerplate.go.txt
 
-# Only deepcopy the Duck types, as they are not real resources.
+# Only deepcopy the Duck types, as they are not real resources, and the Istio types, whose clients
+# are not used.
 ${CODEGEN_PKG}/generate-groups.sh "deepcopy" \
   github.com/knative/eventing/pkg/client github.com/knative/eventing/pkg/apis \
-  "duck:v1alpha1" \
+  "duck:v1alpha1 istio/security:v1beta1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate/boilerplate.go.txt
 
 # Make sure our dependencies are up-to-date
@@ -0,0 +1,43 @@
+#!/usr/bin/env bash
+
+# Copyright 2018 The Knative Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Generates the Go code of the protobuf messages and gRPC services. protoc must be installed, the
+# protoc-gen-go plugin is built from the vendor dir so that the generated code matches the
+# vendored protobuf and gRPC libraries.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source $(dirname $0)/../vendor/github.com/knative/test-infra/scripts/library.sh
+
+readonly PLUGIN_DIR="$(mktemp -d)"
+trap "rm -rf ${PLUGIN_DIR}" EXIT
+
+(cd "${REPO_ROOT_DIR}" && go build -o "${PLUGIN_DIR}/protoc-gen-go" ./vendor/github.com/golang/protobuf/protoc-gen-go)
+
+readonly PROTO_DIR="${REPO_ROOT_DIR}/pkg/provisioners/cloudeventspb"
+protoc \
+  --plugin=protoc-gen-go="${PLUGIN_DIR}/protoc-gen-go" \
+  --go_out=plugins=grpc:"${PROTO_DIR}" \
+  --proto_path="${PROTO_DIR}" \
+  "${PROTO_DIR}/cloudevent.proto" "${PROTO_DIR}/subscriber.proto"
+
+# Prepend the license header to the generated files.
+for f in "${PROTO_DIR}"/*.pb.go; do
+  { cat "${REPO_ROOT_DIR}/hack/boilerplate/boilerplate.go.txt"; echo; cat "${f}"; } > "${f}.tmp"
+  mv "${f}.tmp" "${f}"
+done
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package adapter is a library for writing the receive adapters of sources. It reads the
+// configuration common to all receive adapters from the environment, sends events to the sink,
+// serves metrics, configures tracing, runs a single leader when asked to, and shuts the adapter
+// down gracefully when the process is signalled to stop.
+package adapter
+
+import (
+	"log"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+// Adapter is the receive adapter of a source.
+type Adapter interface {
+	// Start runs the adapter until stopCh is closed. It returns once the adapter has stopped.
+	Start(stopCh <-chan struct{}) error
+}
+
+// Constructor returns the Adapter configured by env, which sends its events with client.
+type Constructor func(env *EnvConfig, client *Client, logger *zap.Logger) (Adapter, error)
+
+// Main runs the receive adapter named component, returned by ctor, until the process is signalled
+// to stop. It does not return if the adapter fails.
+func Main(component string, ctor Constructor) {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+	logger = logger.With(zap.String("component", component))
+	defer logger.Sync()
+
+	env, err := GetEnvConfig()
+	if err != nil {
+		logger.Fatal("Unable to read the environment", zap.Error(err))
+	}
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(env.TracingSampleRate)})
+
+	stopCh := signals.SetupSignalHandler()
+	if env.MetricsPort != 0 {
+		go func() {
+			if err := ListenAndServe(stopCh, env.MetricsPort, MetricsHandler()); err != nil {
+				logger.Error("Unable to serve the metrics", zap.Error(err))
+			}
+		}()
+	}
+
+	a, err := ctor(env, NewClient(env.SinkURI), logger)
+	if err != nil {
+		logger.Fatal("Unable to create the adapter", zap.Error(err))
+	}
+	logger.Info("Starting the adapter", zap.String("sink", env.SinkURI))
+	if env.LeaderElection {
+		err = RunLeaderElected(stopCh, env, component, logger, a.Start)
+	} else {
+		err = a.Start(stopCh)
+	}
+	if err != nil {
+		logger.Fatal("The adapter failed", zap.Error(err))
+	}
+	logger.Info("The adapter stopped")
+}
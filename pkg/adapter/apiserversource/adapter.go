@@ -0,0 +1,177 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package apiserversource implements the receive adapter of ApiServerSources, which watches
+// Kubernetes resources and sends an event to the sink every time one of them is added, updated
+// or deleted.
+package apiserversource
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Resource is a kind of resources watched by the Adapter.
+type Resource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Resource is the plural resource name of Kind, such as 'pods'.
+	Resource string `json:"resource"`
+	// LabelSelector, if set, restricts the watched resources to the ones it selects.
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// GroupVersionResource returns the GroupVersionResource of r.
+func (r Resource) GroupVersionResource() (schema.GroupVersionResource, error) {
+	gv, err := schema.ParseGroupVersion(r.APIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return gv.WithResource(r.Resource), nil
+}
+
+// Adapter watches Resources in Namespaces, and sends an event to the sink every time one of them
+// is added, updated or deleted.
+type Adapter struct {
+	Resources  []Resource
+	Namespaces []string
+	// Mode selects whether the events carry the whole resource or a reference to it.
+	Mode v1alpha1.ApiServerSourceMode
+	// Source is the source of the events.
+	Source string
+
+	Dynamic dynamic.Interface
+	Logger  *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+}
+
+// Start watches the resources and sends events until stopCh is closed.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	var informers []cache.SharedInformer
+	for _, r := range a.Resources {
+		gvr, err := r.GroupVersionResource()
+		if err != nil {
+			return err
+		}
+		for _, ns := range a.Namespaces {
+			informer := cache.NewSharedInformer(a.listWatch(gvr, ns, r.LabelSelector), &unstructured.Unstructured{}, 0)
+			informer.AddEventHandler(a.handler(r))
+			informers = append(informers, informer)
+		}
+	}
+	for _, informer := range informers {
+		go informer.Run(stopCh)
+	}
+	<-stopCh
+	return nil
+}
+
+// listWatch lists and watches the resources gvr in namespace ns selected by labelSelector.
+func (a *Adapter) listWatch(gvr schema.GroupVersionResource, ns, labelSelector string) *cache.ListWatch {
+	client := a.Dynamic.Resource(gvr).Namespace(ns)
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector
+			return client.List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector
+			return client.Watch(options)
+		},
+	}
+}
+
+// handler sends the events for the resources r.
+func (a *Adapter) handler(r Resource) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			a.sendLogged(r, v1alpha1.ApiServerSourceAddEventType, v1alpha1.ApiServerSourceAddRefEventType, obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			a.sendLogged(r, v1alpha1.ApiServerSourceUpdateEventType, v1alpha1.ApiServerSourceUpdateRefEventType, obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			a.sendLogged(r, v1alpha1.ApiServerSourceDeleteEventType, v1alpha1.ApiServerSourceDeleteRefEventType, obj)
+		},
+	}
+}
+
+// sendLogged sends the event about obj, of type resourceType in Resource mode and refType in Ref
+// mode, and logs the failures.
+func (a *Adapter) sendLogged(r Resource, resourceType, refType string, obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		a.Logger.Error("Unexpected object", zap.String("type", fmt.Sprintf("%T", obj)))
+		return
+	}
+	eventType, data := resourceType, interface{}(u)
+	if a.Mode == v1alpha1.ApiServerSourceModeRef {
+		eventType, data = refType, reference(r, u)
+	}
+	if err := a.send(eventType, data); err != nil {
+		a.Logger.Error("Failed to send the event", zap.Error(err), zap.String("type", eventType),
+			zap.String("namespace", u.GetNamespace()), zap.String("name", u.GetName()))
+	}
+}
+
+// send sends an event of type eventType with data to the sink.
+func (a *Adapter) send(eventType string, data interface{}) error {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            uuid.New().String(),
+		EventTime:          time.Now().UTC(),
+		EventType:          eventType,
+		Source:             a.Source,
+	}
+	return a.Client.Send(ctx, data)
+}
+
+// reference returns a reference to u, one of the resources r.
+func reference(r Resource, u *unstructured.Unstructured) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		APIVersion:      u.GetAPIVersion(),
+		Kind:            u.GetKind(),
+		Namespace:       u.GetNamespace(),
+		Name:            u.GetName(),
+		UID:             u.GetUID(),
+		ResourceVersion: u.GetResourceVersion(),
+	}
+	// The items of a list do not always carry their type.
+	if ref.APIVersion == "" {
+		ref.APIVersion = r.APIVersion
+	}
+	if ref.Kind == "" {
+		ref.Kind = r.Kind
+	}
+	return ref
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apiserversource
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+func TestHandler(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"namespace":       "ns",
+			"name":            "pod",
+			"uid":             "pod-uid",
+			"resourceVersion": "7",
+		},
+	}}
+	// The items of a list do not always carry their type.
+	untyped := pod.DeepCopy()
+	delete(untyped.Object, "apiVersion")
+	delete(untyped.Object, "kind")
+
+	podJSON := `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod","namespace":"ns","resourceVersion":"7","uid":"pod-uid"}}`
+	refJSON := `{"kind":"Pod","namespace":"ns","name":"pod","uid":"pod-uid","apiVersion":"v1","resourceVersion":"7"}`
+
+	tests := []struct {
+		name     string
+		mode     v1alpha1.ApiServerSourceMode
+		send     func(cache.ResourceEventHandler)
+		wantType string
+		wantBody string
+	}{{
+		name:     "add",
+		mode:     v1alpha1.ApiServerSourceModeResource,
+		send:     func(h cache.ResourceEventHandler) { h.OnAdd(pod) },
+		wantType: "dev.knative.apiserver.resource.add",
+		wantBody: podJSON,
+	}, {
+		name:     "update",
+		mode:     v1alpha1.ApiServerSourceModeResource,
+		send:     func(h cache.ResourceEventHandler) { h.OnUpdate(pod, pod) },
+		wantType: "dev.knative.apiserver.resource.update",
+		wantBody: podJSON,
+	}, {
+		name:     "delete",
+		mode:     v1alpha1.ApiServerSourceModeResource,
+		send:     func(h cache.ResourceEventHandler) { h.OnDelete(pod) },
+		wantType: "dev.knative.apiserver.resource.delete",
+		wantBody: podJSON,
+	}, {
+		name:     "add ref",
+		mode:     v1alpha1.ApiServerSourceModeRef,
+		send:     func(h cache.ResourceEventHandler) { h.OnAdd(untyped) },
+		wantType: "dev.knative.apiserver.ref.add",
+		wantBody: refJSON,
+	}, {
+		name:     "update ref",
+		mode:     v1alpha1.ApiServerSourceModeRef,
+		send:     func(h cache.ResourceEventHandler) { h.OnUpdate(pod, pod) },
+		wantType: "dev.knative.apiserver.ref.update",
+		wantBody: refJSON,
+	}, {
+		name: "delete ref of a tombstone",
+		mode: v1alpha1.ApiServerSourceModeRef,
+		send: func(h cache.ResourceEventHandler) {
+			h.OnDelete(cache.DeletedFinalStateUnknown{Key: "ns/pod", Obj: pod})
+		},
+		wantType: "dev.knative.apiserver.ref.delete",
+		wantBody: refJSON,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := make(chan received, 1)
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				events <- received{headers: r.Header, body: string(b)}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer sink.Close()
+
+			a := &Adapter{
+				Mode:   test.mode,
+				Client: adapter.NewClient(sink.URL),
+				Source: "https://10.0.0.1:443",
+				Logger: zap.NewNop(),
+			}
+			test.send(a.handler(Resource{APIVersion: "v1", Kind: "Pod", Resource: "pods"}))
+
+			e := <-events
+			if e.body != test.wantBody {
+				t.Errorf("Unexpected body. Expected: %q. Actual: %q", test.wantBody, e.body)
+			}
+			if got := e.headers.Get("CE-EventType"); got != test.wantType {
+				t.Errorf("Unexpected event type %q", got)
+			}
+			if got := e.headers.Get("CE-Source"); got != a.Source {
+				t.Errorf("Unexpected source %q", got)
+			}
+		})
+	}
+}
+
+func TestGroupVersionResource(t *testing.T) {
+	gvr, err := Resource{APIVersion: "apps/v1", Kind: "Deployment", Resource: "deployments"}.GroupVersionResource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gvr.Group != "apps" || gvr.Version != "v1" || gvr.Resource != "deployments" {
+		t.Errorf("Unexpected GroupVersionResource %v", gvr)
+	}
+	if _, err := (Resource{APIVersion: "a/b/c"}).GroupVersionResource(); err == nil {
+		t.Error("Expected an error for an invalid apiVersion")
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package awssqssource implements the receive adapter of AwsSqsSources, which receives the
+// messages of an SQS queue and sends them to the sink as events.
+package awssqssource
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+)
+
+const (
+	// minBackoff and maxBackoff bound the delay before a failed receive is retried.
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// queue is the part of a Queue used by the Adapter.
+type queue interface {
+	Receive(ctx context.Context, max int, visibilityTimeout time.Duration) ([]Message, error)
+	Delete(ctx context.Context, receiptHandle string) error
+	ChangeVisibility(ctx context.Context, receiptHandle string, timeout time.Duration) error
+	VisibilityTimeout(ctx context.Context) (time.Duration, error)
+}
+
+// Adapter receives the messages of the queue at QueueURL, and sends every message to the sink.
+type Adapter struct {
+	QueueURL string
+	Region   string
+	// Credentials sign the requests to the queue.
+	Credentials *credentials.Credentials
+	// Concurrency is the number of messages that are sent to the sink at a time.
+	Concurrency int
+	// VisibilityTimeout is how long received messages are hidden from the other consumers of the
+	// queue. If it is zero, the visibility timeout of the queue is used.
+	VisibilityTimeout time.Duration
+
+	Logger *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+
+	// queue is replaced in tests.
+	queue queue
+}
+
+// Start receives the messages of the queue until stopCh is closed, and then waits for the messages
+// being sent. A message is deleted from the queue once the sink accepts it, and made visible again
+// if the sink rejects it, so that every message is delivered at least once. The visibility of a
+// message is extended while the sink takes to respond.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	q := a.queue
+	if q == nil {
+		var err error
+		if q, err = NewQueue(a.QueueURL, a.Region, a.Credentials, a.Client.HTTP); err != nil {
+			return err
+		}
+	}
+	visibilityTimeout := a.VisibilityTimeout
+	if visibilityTimeout == 0 {
+		t, err := q.VisibilityTimeout(ctx)
+		if err != nil {
+			return err
+		}
+		visibilityTimeout = t
+	}
+	concurrency := a.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// Every message being sent holds a slot, and messages are only received for free slots, so
+	// that no message waits for a send while it is hidden.
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	backoff := minBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case slots <- struct{}{}:
+		}
+		free := 1
+	fill:
+		for free < concurrency && free < MaxMessages {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break fill
+			}
+		}
+
+		msgs, err := q.Receive(ctx, free, a.VisibilityTimeout)
+		for i := len(msgs); i < free; i++ {
+			<-slots
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			a.Logger.Error("Failed to receive messages", zap.Error(err), zap.Duration("backoff", backoff))
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = minBackoff
+
+		for i := range msgs {
+			wg.Add(1)
+			go func(msg *Message) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				a.handle(q, msg, visibilityTimeout)
+			}(&msgs[i])
+		}
+	}
+}
+
+// handle sends msg to the sink, extending its visibility every half visibilityTimeout until the
+// sink responds. The message is then deleted, or made visible again if it was not accepted.
+func (a *Adapter) handle(q queue, msg *Message, visibilityTimeout time.Duration) {
+	// The message is acknowledged even when the adapter stops, so that it is not sent again.
+	ctx := context.Background()
+	logger := a.Logger.With(zap.String("messageId", msg.MessageID))
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- a.send(msg)
+	}()
+	// Visibility timeouts are whole seconds, so shorter ones are not extended.
+	var heartbeat <-chan time.Time
+	if visibilityTimeout >= 2*time.Second {
+		ticker := time.NewTicker(visibilityTimeout / 2)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	var err error
+	for waiting := true; waiting; {
+		select {
+		case err = <-sent:
+			waiting = false
+		case <-heartbeat:
+			if err := q.ChangeVisibility(ctx, msg.ReceiptHandle, visibilityTimeout); err != nil {
+				logger.Warn("Failed to extend the visibility of the message", zap.Error(err))
+			}
+		}
+	}
+
+	if err != nil {
+		logger.Error("Failed to send the message", zap.Error(err))
+		if err := q.ChangeVisibility(ctx, msg.ReceiptHandle, 0); err != nil {
+			logger.Warn("Failed to make the message visible", zap.Error(err))
+		}
+		return
+	}
+	if err := q.Delete(ctx, msg.ReceiptHandle); err != nil {
+		logger.Error("Failed to delete the message", zap.Error(err))
+	}
+}
+
+// send sends the message msg to the sink.
+func (a *Adapter) send(msg *Message) error {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            msg.MessageID,
+		EventTime:          msg.SentTime(),
+		EventType:          v1alpha1.AwsSqsEventType,
+		Source:             a.QueueURL,
+	}
+	return a.Client.Send(ctx, eventData(msg.Body))
+}
+
+// eventData returns body as JSON if it is valid JSON, and as a JSON string otherwise.
+func eventData(body string) interface{} {
+	if json.Valid([]byte(body)) {
+		return json.RawMessage(body)
+	}
+	return body
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package awssqssource
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"go.uber.org/zap"
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+type fakeQueue struct {
+	messages chan Message
+	mu       sync.Mutex
+	max      []int
+	deleted  chan string
+	visible  chan string
+}
+
+func (q *fakeQueue) Receive(ctx context.Context, max int, _ time.Duration) ([]Message, error) {
+	q.mu.Lock()
+	q.max = append(q.max, max)
+	q.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case msg := <-q.messages:
+		return []Message{msg}, nil
+	}
+}
+
+func (q *fakeQueue) Delete(_ context.Context, receiptHandle string) error {
+	q.deleted <- receiptHandle
+	return nil
+}
+
+func (q *fakeQueue) ChangeVisibility(_ context.Context, receiptHandle string, timeout time.Duration) error {
+	if timeout == 0 {
+		q.visible <- receiptHandle
+	}
+	return nil
+}
+
+func (q *fakeQueue) VisibilityTimeout(context.Context) (time.Duration, error) {
+	return 30 * time.Second, nil
+}
+
+func TestAdapter(t *testing.T) {
+	events := make(chan received, 2)
+	statuses := make(chan int, 2)
+	statuses <- http.StatusAccepted
+	statuses <- http.StatusInternalServerError
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		events <- received{headers: r.Header, body: string(b)}
+		w.WriteHeader(<-statuses)
+	}))
+	defer sink.Close()
+
+	q := &fakeQueue{
+		messages: make(chan Message, 2),
+		deleted:  make(chan string, 2),
+		visible:  make(chan string, 2),
+	}
+	q.messages <- Message{
+		MessageID:     "first",
+		ReceiptHandle: "first-handle",
+		Body:          `{"id":7}`,
+		Attributes:    map[string]string{"SentTimestamp": "1546858800000"},
+	}
+
+	a := &Adapter{
+		QueueURL:    "https://sqs.us-east-1.amazonaws.com/123456789012/orders",
+		Concurrency: 1,
+		Client:      adapter.NewClient(sink.URL),
+		Logger:      zap.NewNop(),
+		queue:       q,
+	}
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- a.Start(stopCh)
+	}()
+
+	// The accepted message is deleted, and the rejected one is made visible again.
+	if got := <-q.deleted; got != "first-handle" {
+		t.Errorf("Unexpected deleted message %q", got)
+	}
+	q.messages <- Message{MessageID: "second", ReceiptHandle: "second-handle", Body: "plain text"}
+	if got := <-q.visible; got != "second-handle" {
+		t.Errorf("Unexpected visible message %q", got)
+	}
+	close(stopCh)
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []struct {
+		id   string
+		body string
+		time string
+	}{
+		{id: "first", body: `{"id":7}`, time: "2019-01-07T11:00:00Z"},
+		{id: "second", body: `"plain text"`},
+	} {
+		e := <-events
+		if e.body != want.body {
+			t.Errorf("Unexpected body. Expected: %q. Actual: %q", want.body, e.body)
+		}
+		if got := e.headers.Get("CE-EventID"); got != want.id {
+			t.Errorf("Unexpected event ID %q", got)
+		}
+		if got := e.headers.Get("CE-EventType"); got != "aws.sqs.message" {
+			t.Errorf("Unexpected event type %q", got)
+		}
+		if got := e.headers.Get("CE-Source"); got != a.QueueURL {
+			t.Errorf("Unexpected source %q", got)
+		}
+		if want.time != "" {
+			if got := e.headers.Get("CE-EventTime"); got != want.time {
+				t.Errorf("Unexpected event time %q", got)
+			}
+		}
+	}
+}
+
+func TestAdapterConcurrency(t *testing.T) {
+	block := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	q := &fakeQueue{
+		messages: make(chan Message, 3),
+		deleted:  make(chan string, 3),
+		visible:  make(chan string, 3),
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		q.messages <- Message{MessageID: id, ReceiptHandle: id}
+	}
+	a := &Adapter{
+		QueueURL:          "https://sqs.us-east-1.amazonaws.com/123456789012/orders",
+		Concurrency:       2,
+		VisibilityTimeout: time.Minute,
+		Client:            adapter.NewClient(sink.URL),
+		Logger:            zap.NewNop(),
+		queue:             q,
+	}
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- a.Start(stopCh)
+	}()
+
+	// Only two messages are received while the sink holds both slots.
+	time.Sleep(100 * time.Millisecond)
+	if got := len(q.messages); got != 1 {
+		t.Errorf("Unexpected number of messages left in the queue: %d", got)
+	}
+	close(block)
+	for i := 0; i < 3; i++ {
+		<-q.deleted
+	}
+	close(stopCh)
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.max[0] != 2 {
+		t.Errorf("Unexpected number of messages requested %d, want 2", q.max[0])
+	}
+}
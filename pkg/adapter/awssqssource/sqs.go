@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package awssqssource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+const (
+	// MaxMessages is the largest number of messages a single receive returns.
+	MaxMessages = 10
+
+	// WaitTime is how long a receive waits for a message to arrive in an empty queue.
+	WaitTime = 20 * time.Second
+)
+
+// Message is a message received from an SQS queue.
+type Message struct {
+	MessageID     string
+	ReceiptHandle string
+	Body          string
+	// Attributes are the system attributes of the message, e.g. SentTimestamp.
+	Attributes map[string]string
+}
+
+// SentTime returns the time the message was sent to the queue, or the zero time if it is unknown.
+func (m *Message) SentTime() time.Time {
+	ms, err := strconv.ParseInt(m.Attributes[sqs.MessageSystemAttributeNameSentTimestamp], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)).UTC()
+}
+
+// Queue is a client of an SQS queue.
+type Queue struct {
+	// URL is the URL of the queue.
+	URL string
+
+	client *sqs.SQS
+}
+
+// NewQueue creates a client of the queue at queueURL in region, which signs its requests with
+// creds and sends them with httpClient, or http.DefaultClient if it is nil. The requests are sent
+// to the host of queueURL, so that queues served by an SQS-compatible service can be used.
+func NewQueue(queueURL, region string, creds *credentials.Credentials, httpClient *http.Client) (*Queue, error) {
+	u, err := url.Parse(queueURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid queue URL %q", queueURL)
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Endpoint:    aws.String(u.Scheme + "://" + u.Host),
+		Credentials: creds,
+		HTTPClient:  httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Queue{URL: queueURL, client: sqs.New(sess)}, nil
+}
+
+// Receive receives up to max messages, waiting up to WaitTime for one to arrive. The messages
+// are hidden from the other consumers of the queue for visibilityTimeout, or for the visibility
+// timeout of the queue if it is zero.
+func (q *Queue) Receive(ctx context.Context, max int, visibilityTimeout time.Duration) ([]Message, error) {
+	in := &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(q.URL),
+		MaxNumberOfMessages: aws.Int64(int64(max)),
+		WaitTimeSeconds:     aws.Int64(int64(WaitTime / time.Second)),
+		AttributeNames:      aws.StringSlice([]string{sqs.QueueAttributeNameAll}),
+	}
+	if visibilityTimeout > 0 {
+		in.VisibilityTimeout = aws.Int64(int64(visibilityTimeout / time.Second))
+	}
+	out, err := q.client.ReceiveMessageWithContext(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]Message, 0, len(out.Messages))
+	for _, m := range out.Messages {
+		msgs = append(msgs, Message{
+			MessageID:     aws.StringValue(m.MessageId),
+			ReceiptHandle: aws.StringValue(m.ReceiptHandle),
+			Body:          aws.StringValue(m.Body),
+			Attributes:    aws.StringValueMap(m.Attributes),
+		})
+	}
+	return msgs, nil
+}
+
+// Delete deletes the message with receiptHandle from the queue.
+func (q *Queue) Delete(ctx context.Context, receiptHandle string) error {
+	_, err := q.client.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(q.URL),
+		ReceiptHandle: aws.String(receiptHandle),
+	})
+	return err
+}
+
+// ChangeVisibility hides the message with receiptHandle from the other consumers of the queue for
+// timeout from now. A timeout of zero makes it visible immediately.
+func (q *Queue) ChangeVisibility(ctx context.Context, receiptHandle string, timeout time.Duration) error {
+	_, err := q.client.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(q.URL),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: aws.Int64(int64(timeout / time.Second)),
+	})
+	return err
+}
+
+// VisibilityTimeout returns the visibility timeout of the queue.
+func (q *Queue) VisibilityTimeout(ctx context.Context) (time.Duration, error) {
+	out, err := q.client.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(q.URL),
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameVisibilityTimeout}),
+	})
+	if err != nil {
+		return 0, err
+	}
+	value, ok := out.Attributes[sqs.QueueAttributeNameVisibilityTimeout]
+	if !ok {
+		return 0, fmt.Errorf("the queue has no visibility timeout")
+	}
+	seconds, err := strconv.Atoi(aws.StringValue(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid visibility timeout %q", aws.StringValue(value))
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package awssqssource
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+func TestQueue(t *testing.T) {
+	var forms []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(auth, "/us-east-1/sqs/aws4_request") {
+			t.Errorf("Unexpected Authorization header %q", auth)
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(b))
+		forms = append(forms, form)
+		switch form.Get("Action") {
+		case "ReceiveMessage":
+			w.Write([]byte(`<ReceiveMessageResponse><ReceiveMessageResult><Message>
+<MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+<ReceiptHandle>handle</ReceiptHandle>
+<Body>{"id":7}</Body>
+<MD5OfBody>f909686c4adf64f7a8c468b29e6e66aa</MD5OfBody>
+<Attribute><Name>SenderId</Name><Value>195004372649</Value></Attribute>
+<Attribute><Name>SentTimestamp</Name><Value>1546858800000</Value></Attribute>
+</Message></ReceiveMessageResult></ReceiveMessageResponse>`))
+		case "GetQueueAttributes":
+			w.Write([]byte(`<GetQueueAttributesResponse><GetQueueAttributesResult>
+<Attribute><Name>VisibilityTimeout</Name><Value>45</Value></Attribute>
+</GetQueueAttributesResult></GetQueueAttributesResponse>`))
+		case "DeleteMessage":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>ReceiptHandleIsInvalid</Code>
+<Message>The receipt handle is not valid.</Message></Error></ErrorResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	creds := credentials.NewStaticCredentials("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "")
+	q, err := NewQueue(server.URL+"/123456789012/orders", "us-east-1", creds, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	msgs, err := q.Receive(ctx, 5, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].MessageID != "5fea7756-0ea4-451a-a703-a558b933e274" || msgs[0].ReceiptHandle != "handle" || msgs[0].Body != `{"id":7}` {
+		t.Fatalf("Unexpected messages %+v", msgs)
+	}
+	if got, want := msgs[0].SentTime(), time.Date(2019, time.January, 7, 11, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Unexpected sent time %v, want %v", got, want)
+	}
+	if got := forms[0]; got.Get("MaxNumberOfMessages") != "5" || got.Get("WaitTimeSeconds") != "20" || got.Get("VisibilityTimeout") != "30" || got.Get("QueueUrl") != q.URL {
+		t.Errorf("Unexpected ReceiveMessage parameters %v", got)
+	}
+
+	timeout, err := q.VisibilityTimeout(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if timeout != 45*time.Second {
+		t.Errorf("Unexpected visibility timeout %v", timeout)
+	}
+
+	err = q.Delete(ctx, "handle")
+	if e, ok := err.(awserr.RequestFailure); !ok || e.StatusCode() != http.StatusBadRequest || e.Code() != "ReceiptHandleIsInvalid" {
+		t.Errorf("Unexpected error %#v", err)
+	}
+	if got := forms[2].Get("ReceiptHandle"); got != "handle" {
+		t.Errorf("Unexpected receipt handle %q", got)
+	}
+}
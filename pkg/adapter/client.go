@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/knative/pkg/cloudevents"
+	"go.opencensus.io/plugin/ochttp"
+)
+
+// Client sends events to a sink.
+type Client struct {
+	// SinkURI is the URI of the sink.
+	SinkURI string
+
+	// HTTP is the client the events are sent with.
+	HTTP *http.Client
+}
+
+// NewClient returns a Client sending events to sinkURI, which propagates the trace context of
+// the events.
+func NewClient(sinkURI string) *Client {
+	return &Client{
+		SinkURI: sinkURI,
+		HTTP:    &http.Client{Transport: &ochttp.Transport{}},
+	}
+}
+
+// Send sends an event with the context ctx and data to the sink, in the binary content mode. It
+// returns an error if the sink does not accept the event.
+func (c *Client) Send(ctx cloudevents.EventContext, data interface{}) error {
+	start := time.Now()
+	err := c.send(ctx, data)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	eventsSent.WithLabelValues(ctx.EventType, result).Inc()
+	sendLatency.WithLabelValues(ctx.EventType).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (c *Client) send(ctx cloudevents.EventContext, data interface{}) error {
+	req, err := cloudevents.Binary.NewRequest(c.SinkURI, data, ctx)
+	if err != nil {
+		return err
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return &SendError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+	return nil
+}
+
+// SendError is the error returned when the sink responds to an event with an unsuccessful
+// status.
+type SendError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *SendError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = strconv.Itoa(e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected response status %s", status)
+}
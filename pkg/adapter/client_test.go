@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/knative/pkg/cloudevents"
+)
+
+func TestClientSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+	}{{
+		name:   "accepted",
+		status: http.StatusAccepted,
+	}, {
+		name:       "rejected",
+		status:     http.StatusBadRequest,
+		wantStatus: http.StatusBadRequest,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var headers http.Header
+			var body string
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				headers, body = r.Header, string(b)
+				w.WriteHeader(test.status)
+			}))
+			defer sink.Close()
+
+			c := NewClient(sink.URL)
+			err := c.Send(cloudevents.EventContext{
+				CloudEventsVersion: cloudevents.CloudEventsVersion,
+				EventID:            "1234",
+				EventTime:          time.Now(),
+				EventType:          "dev.knative.test",
+				Source:             "/test",
+			}, map[string]string{"message": "hello"})
+			if test.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if se, ok := err.(*SendError); !ok || se.StatusCode != test.wantStatus {
+				t.Fatalf("unexpected error: want a SendError with status %d, got %v", test.wantStatus, err)
+			}
+
+			if got := headers.Get("CE-EventType"); got != "dev.knative.test" {
+				t.Errorf("unexpected event type: %q", got)
+			}
+			if got := headers.Get("CE-EventID"); got != "1234" {
+				t.Errorf("unexpected event ID: %q", got)
+			}
+			if want := `{"message":"hello"}`; body != want {
+				t.Errorf("unexpected body: want %q, got %q", want, body)
+			}
+		})
+	}
+}
+
+func TestClientSendUnreachable(t *testing.T) {
+	sink := httptest.NewServer(http.NotFoundHandler())
+	sink.Close()
+	c := NewClient(sink.URL)
+	err := c.Send(cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            "1234",
+		EventType:          "dev.knative.test",
+		Source:             "/test",
+	}, "hello")
+	if err == nil {
+		t.Error("expected an error sending to a closed sink")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package cronjobsource implements the receive adapter of CronJobSources, which sends an event to
+// the sink every time the schedule fires.
+package cronjobsource
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/cron"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+)
+
+// Adapter sends an event with Data to the sink every time Schedule fires.
+type Adapter struct {
+	Schedule cron.Schedule
+	Data     string
+	// Source is the source of the events.
+	Source string
+
+	// OnFire, if set, is called with the time of every event that was delivered.
+	OnFire func(time.Time) error
+
+	Logger *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+
+	// now and after are replaced in tests.
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// Start sends events until stopCh is closed.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	now, after := a.now, a.after
+	if now == nil {
+		now = time.Now
+	}
+	if after == nil {
+		after = time.After
+	}
+	for {
+		next := a.Schedule.Next(now())
+		if next.IsZero() {
+			a.Logger.Warn("The schedule never fires again")
+			<-stopCh
+			return nil
+		}
+		select {
+		case <-stopCh:
+			return nil
+		case <-after(next.Sub(now())):
+		}
+		if err := a.send(next); err != nil {
+			a.Logger.Error("Failed to send the event", zap.Error(err), zap.Time("scheduleTime", next))
+			continue
+		}
+		if a.OnFire != nil {
+			if err := a.OnFire(next); err != nil {
+				a.Logger.Warn("Failed to record the schedule time", zap.Error(err))
+			}
+		}
+	}
+}
+
+// send sends the event scheduled at t to the sink.
+func (a *Adapter) send(t time.Time) error {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            uuid.New().String(),
+		EventTime:          t.UTC(),
+		EventType:          v1alpha1.CronJobEventType,
+		Source:             a.Source,
+	}
+	return a.Client.Send(ctx, eventData(a.Data))
+}
+
+// eventData returns data as JSON if it is valid JSON, and as a JSON string otherwise.
+func eventData(data string) interface{} {
+	if json.Valid([]byte(data)) {
+		return json.RawMessage(data)
+	}
+	return data
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cronjobsource
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/cron"
+	"go.uber.org/zap"
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+func TestAdapter(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		status   int
+		wantBody string
+		wantFire bool
+	}{{
+		name:     "JSON data",
+		data:     `{"message":"Hello world!"}`,
+		status:   http.StatusAccepted,
+		wantBody: `{"message":"Hello world!"}`,
+		wantFire: true,
+	}, {
+		name:     "text data",
+		data:     "Hello world!",
+		status:   http.StatusOK,
+		wantBody: `"Hello world!"`,
+		wantFire: true,
+	}, {
+		name:     "sink rejects the event",
+		data:     "{}",
+		status:   http.StatusInternalServerError,
+		wantBody: "{}",
+		wantFire: false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := make(chan received, 1)
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				events <- received{headers: r.Header, body: string(b)}
+				w.WriteHeader(test.status)
+			}))
+			defer sink.Close()
+
+			schedule, err := cron.Parse("@hourly")
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Date(2019, time.January, 7, 10, 30, 0, 0, time.UTC)
+			fired := make(chan time.Time, 1)
+			stopCh := make(chan struct{})
+			calls := 0
+			// Closed when the adapter waits for the second event.
+			waiting := make(chan struct{})
+			a := &Adapter{
+				Schedule: schedule,
+				Data:     test.data,
+				Client:   adapter.NewClient(sink.URL),
+				Source:   "/apis/v1/namespaces/ns/cronjobsources/source",
+				OnFire: func(t time.Time) error {
+					fired <- t
+					return nil
+				},
+				Logger: zap.NewNop(),
+				now:    func() time.Time { return start },
+				// Only the first event is sent.
+				after: func(d time.Duration) <-chan time.Time {
+					ch := make(chan time.Time, 1)
+					if calls == 0 {
+						ch <- start.Add(d)
+					} else if calls == 1 {
+						close(waiting)
+					}
+					calls++
+					return ch
+				},
+			}
+			done := make(chan error)
+			go func() {
+				done <- a.Start(stopCh)
+			}()
+
+			e := <-events
+			<-waiting
+			close(stopCh)
+			if e.body != test.wantBody {
+				t.Errorf("Unexpected body. Expected: %q. Actual: %q", test.wantBody, e.body)
+			}
+			if got := e.headers.Get("CE-EventType"); got != "dev.knative.cronjob.event" {
+				t.Errorf("Unexpected event type %q", got)
+			}
+			if got := e.headers.Get("CE-Source"); got != a.Source {
+				t.Errorf("Unexpected source %q", got)
+			}
+			if got := e.headers.Get("CE-EventTime"); got != "2019-01-07T11:00:00Z" {
+				t.Errorf("Unexpected event time %q", got)
+			}
+			if err := <-done; err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			select {
+			case got := <-fired:
+				if !test.wantFire {
+					t.Errorf("Unexpected fire at %v", got)
+				} else if !got.Equal(start.Add(30 * time.Minute)) {
+					t.Errorf("Unexpected fire time %v", got)
+				}
+			default:
+				if test.wantFire {
+					t.Error("Expected a fire")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	// defaultMetricsPort is the port the metrics are served on if METRICS_PORT is not set.
+	defaultMetricsPort = 9090
+)
+
+// EnvConfig is the configuration shared by all receive adapters, read from their environment.
+type EnvConfig struct {
+	// SinkURI is the URI events are sent to. It is read from K_SINK, which SinkBindings set, or
+	// from SINK_URI, which the source controllers set.
+	SinkURI string
+
+	// Namespace and Name are the namespace and name of the source, read from NAMESPACE and NAME.
+	Namespace string
+	Name      string
+
+	// MetricsPort is the port the Prometheus metrics are served on, read from METRICS_PORT. Zero
+	// disables the metrics.
+	MetricsPort int
+
+	// TracingSampleRate is the probability that an event sent to the sink is traced, read from
+	// TRACING_SAMPLE_RATE. Traces are exported to the exporters registered with the trace package.
+	TracingSampleRate float64
+
+	// LeaderElection, read from LEADER_ELECTION, makes a single replica of the adapter run at a
+	// time.
+	LeaderElection bool
+
+	// PodName is the identity of the replica in the leader election, read from POD_NAME. It
+	// defaults to the host name.
+	PodName string
+}
+
+// GetEnvConfig reads the EnvConfig from the environment.
+func GetEnvConfig() (*EnvConfig, error) {
+	env := &EnvConfig{
+		SinkURI:     os.Getenv("K_SINK"),
+		Namespace:   os.Getenv("NAMESPACE"),
+		Name:        os.Getenv("NAME"),
+		MetricsPort: defaultMetricsPort,
+		PodName:     os.Getenv("POD_NAME"),
+	}
+	if env.SinkURI == "" {
+		env.SinkURI = os.Getenv("SINK_URI")
+	}
+	if env.SinkURI == "" {
+		return nil, errors.New("neither the K_SINK nor the SINK_URI environment variable is set")
+	}
+
+	var err error
+	if v := os.Getenv("METRICS_PORT"); v != "" {
+		if env.MetricsPort, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid METRICS_PORT %q: %v", v, err)
+		}
+	}
+	if v := os.Getenv("TRACING_SAMPLE_RATE"); v != "" {
+		env.TracingSampleRate, err = strconv.ParseFloat(v, 64)
+		if err != nil || env.TracingSampleRate < 0 || env.TracingSampleRate > 1 {
+			return nil, fmt.Errorf("invalid TRACING_SAMPLE_RATE %q: it must be between 0 and 1", v)
+		}
+	}
+	if v := os.Getenv("LEADER_ELECTION"); v != "" {
+		if env.LeaderElection, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid LEADER_ELECTION %q: %v", v, err)
+		}
+	}
+	if env.PodName == "" {
+		if env.PodName, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
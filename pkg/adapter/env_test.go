@@ -0,0 +1,118 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetEnvConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    *EnvConfig
+		wantErr bool
+	}{{
+		name: "defaults",
+		env: map[string]string{
+			"SINK_URI": "http://sink/",
+			"POD_NAME": "adapter-0",
+		},
+		want: &EnvConfig{
+			SinkURI:     "http://sink/",
+			MetricsPort: defaultMetricsPort,
+			PodName:     "adapter-0",
+		},
+	}, {
+		name: "all set",
+		env: map[string]string{
+			"K_SINK":              "http://bound/",
+			"SINK_URI":            "http://sink/",
+			"NAMESPACE":           "default",
+			"NAME":                "source",
+			"METRICS_PORT":        "0",
+			"TRACING_SAMPLE_RATE": "0.5",
+			"LEADER_ELECTION":     "true",
+			"POD_NAME":            "adapter-0",
+		},
+		want: &EnvConfig{
+			SinkURI:           "http://bound/",
+			Namespace:         "default",
+			Name:              "source",
+			TracingSampleRate: 0.5,
+			LeaderElection:    true,
+			PodName:           "adapter-0",
+		},
+	}, {
+		name:    "no sink",
+		env:     map[string]string{},
+		wantErr: true,
+	}, {
+		name: "invalid metrics port",
+		env: map[string]string{
+			"SINK_URI":     "http://sink/",
+			"METRICS_PORT": "metrics",
+		},
+		wantErr: true,
+	}, {
+		name: "sample rate out of range",
+		env: map[string]string{
+			"SINK_URI":            "http://sink/",
+			"TRACING_SAMPLE_RATE": "2",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid leader election",
+		env: map[string]string{
+			"SINK_URI":        "http://sink/",
+			"LEADER_ELECTION": "sometimes",
+		},
+		wantErr: true,
+	}}
+	vars := []string{"K_SINK", "SINK_URI", "NAMESPACE", "NAME", "METRICS_PORT", "TRACING_SAMPLE_RATE", "LEADER_ELECTION", "POD_NAME"}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, v := range vars {
+				os.Unsetenv(v)
+			}
+			for k, v := range test.env {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for _, v := range vars {
+					os.Unsetenv(v)
+				}
+			}()
+
+			got, err := GetEnvConfig()
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected config (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package githubsource implements the receive adapter of GitHubSources, which validates the
+// webhook requests of GitHub and sends their payloads to the sink as events.
+package githubsource
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+)
+
+const (
+	// Port is the port the adapter listens on.
+	Port = 8080
+
+	// The headers of the webhook requests of GitHub.
+	eventHeader        = "X-GitHub-Event"
+	deliveryHeader     = "X-GitHub-Delivery"
+	signatureHeader    = "X-Hub-Signature"
+	signature256Header = "X-Hub-Signature-256"
+
+	// pingEvent is sent by GitHub when a webhook is registered. It is not sent to the sink.
+	pingEvent = "ping"
+
+	// maxPayloadBytes is the largest payload GitHub sends.
+	maxPayloadBytes = 25 << 20
+)
+
+// Adapter is an http.Handler receiving the webhook requests of GitHub. It sends the payload of
+// every request signed with SecretToken to the sink.
+type Adapter struct {
+	SecretToken string
+	// Source is the source of the events, the URL of the repository.
+	Source string
+
+	Logger *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+}
+
+var _ http.Handler = (*Adapter)(nil)
+var _ adapter.Adapter = (*Adapter)(nil)
+
+// Start serves the webhook requests of GitHub on Port until stopCh is closed.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	return adapter.ListenAndServe(stopCh, Port, a)
+}
+
+// ServeHTTP validates the webhook request and sends its payload to the sink.
+func (a *Adapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadBytes+1))
+	if err != nil {
+		http.Error(w, "unable to read the payload", http.StatusBadRequest)
+		return
+	}
+	// The signature of a truncated payload would not match.
+	if len(payload) > maxPayloadBytes {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := a.verifySignature(r.Header, payload); err != nil {
+		a.Logger.Info("Rejected a webhook request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	event := r.Header.Get(eventHeader)
+	if event == "" {
+		http.Error(w, "missing "+eventHeader+" header", http.StatusBadRequest)
+		return
+	}
+	if event == pingEvent {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err := a.send(event, r.Header.Get(deliveryHeader), payload); err != nil {
+		a.Logger.Error("Failed to send the event", zap.Error(err), zap.String("event", event))
+		// GitHub records the failed delivery, which can be redelivered.
+		http.Error(w, "unable to send the event", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verifySignature returns an error unless payload is signed with the secret token. The SHA-256
+// signature is preferred over the SHA-1 signature, which older GitHub Enterprise servers only send.
+func (a *Adapter) verifySignature(h http.Header, payload []byte) error {
+	signature, prefix, newHash := h.Get(signature256Header), "sha256=", sha256.New
+	if signature == "" {
+		signature, prefix, newHash = h.Get(signatureHeader), "sha1=", sha1.New
+	}
+	if signature == "" {
+		return fmt.Errorf("missing %s header", signatureHeader)
+	}
+	if !strings.HasPrefix(signature, prefix) {
+		return fmt.Errorf("unexpected signature %q", signature)
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return fmt.Errorf("unexpected signature %q", signature)
+	}
+	if !hmac.Equal(got, sign(newHash, a.SecretToken, payload)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// sign returns the HMAC of payload with secret.
+func sign(newHash func() hash.Hash, secret string, payload []byte) []byte {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// send sends the payload of the GitHub event with the given delivery ID to the sink.
+func (a *Adapter) send(event, deliveryID string, payload []byte) error {
+	if deliveryID == "" {
+		deliveryID = uuid.New().String()
+	}
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            deliveryID,
+		EventTime:          time.Now().UTC(),
+		EventType:          fmt.Sprintf("%s.%s", v1alpha1.GitHubEventTypePrefix, event),
+		Source:             a.Source,
+	}
+	return a.Client.Send(ctx, json.RawMessage(payload))
+}
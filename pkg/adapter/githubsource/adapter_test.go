@@ -0,0 +1,185 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package githubsource
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"go.uber.org/zap"
+)
+
+const (
+	secretToken = "s3cr3t"
+	payload     = `{"ref":"refs/heads/master"}`
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+func TestAdapter(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		sinkStatus int
+		wantStatus int
+		wantEvent  bool
+	}{{
+		name:   "push event",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-GitHub-Delivery":   "delivery-id",
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sign(sha256.New, secretToken, []byte(payload))),
+		},
+		sinkStatus: http.StatusAccepted,
+		wantStatus: http.StatusAccepted,
+		wantEvent:  true,
+	}, {
+		name:   "SHA-1 signature",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-GitHub-Event":    "push",
+			"X-GitHub-Delivery": "delivery-id",
+			"X-Hub-Signature":   "sha1=" + hex.EncodeToString(sign(sha1.New, secretToken, []byte(payload))),
+		},
+		sinkStatus: http.StatusOK,
+		wantStatus: http.StatusAccepted,
+		wantEvent:  true,
+	}, {
+		name:   "invalid signature",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sign(sha256.New, "wrong", []byte(payload))),
+		},
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:   "missing signature",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-GitHub-Event": "push",
+		},
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:   "missing event",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sign(sha256.New, secretToken, []byte(payload))),
+		},
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:   "ping is not sent",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-GitHub-Event":      "ping",
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sign(sha256.New, secretToken, []byte(payload))),
+		},
+		wantStatus: http.StatusNoContent,
+	}, {
+		name:       "not a POST",
+		method:     http.MethodGet,
+		wantStatus: http.StatusMethodNotAllowed,
+	}, {
+		name:   "sink rejects the event",
+		method: http.MethodPost,
+		headers: map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-GitHub-Delivery":   "delivery-id",
+			"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sign(sha256.New, secretToken, []byte(payload))),
+		},
+		sinkStatus: http.StatusInternalServerError,
+		wantStatus: http.StatusBadGateway,
+		wantEvent:  true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := make(chan received, 1)
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				events <- received{headers: r.Header, body: string(b)}
+				w.WriteHeader(test.sinkStatus)
+			}))
+			defer sink.Close()
+
+			a := &Adapter{
+				SecretToken: secretToken,
+				Client:      adapter.NewClient(sink.URL),
+				Source:      "https://github.com/knative/eventing",
+				Logger:      zap.NewNop(),
+			}
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(payload))
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			a.ServeHTTP(w, req)
+
+			if w.Code != test.wantStatus {
+				t.Errorf("Unexpected status. Expected: %v. Actual: %v", test.wantStatus, w.Code)
+			}
+			select {
+			case e := <-events:
+				if !test.wantEvent {
+					t.Fatalf("Unexpected event %v", e)
+				}
+				if e.body != payload {
+					t.Errorf("Unexpected body. Expected: %q. Actual: %q", payload, e.body)
+				}
+				if got := e.headers.Get("CE-EventType"); got != "dev.knative.source.github.push" {
+					t.Errorf("Unexpected event type %q", got)
+				}
+				if got := e.headers.Get("CE-EventID"); got != "delivery-id" {
+					t.Errorf("Unexpected event ID %q", got)
+				}
+				if got := e.headers.Get("CE-Source"); got != a.Source {
+					t.Errorf("Unexpected source %q", got)
+				}
+			default:
+				if test.wantEvent {
+					t.Error("Expected an event")
+				}
+			}
+		})
+	}
+}
+
+func TestAdapterPayloadTooLarge(t *testing.T) {
+	large := []byte(strings.Repeat("a", maxPayloadBytes+1))
+	a := &Adapter{
+		SecretToken: secretToken,
+		Logger:      zap.NewNop(),
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(large))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(sign(sha256.New, secretToken, large)))
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Unexpected status. Expected: %v. Actual: %v", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package kafkasource implements the receive adapter of KafkaSources, which consumes the records of
+// Kafka topics in a consumer group and sends them to the sink as events.
+package kafkasource
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Shopify/sarama"
+	cluster "github.com/bsm/sarama-cluster"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+)
+
+const (
+	// minBackoff and maxBackoff bound the delay before sending a record the sink did not accept
+	// again.
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// NetConfig configures the connections to Kafka. The certificates and key are PEM encoded.
+type NetConfig struct {
+	SASLEnable   bool
+	SASLUser     string
+	SASLPassword string
+
+	TLSEnable bool
+	TLSCert   string
+	TLSKey    string
+	TLSCACert string
+}
+
+// consumer is the part of a sarama-cluster consumer used by the Adapter.
+type consumer interface {
+	Messages() <-chan *sarama.ConsumerMessage
+	MarkOffset(msg *sarama.ConsumerMessage, metadata string)
+	Close() error
+}
+
+// Adapter consumes Topics in ConsumerGroup, and sends every record to the sink.
+type Adapter struct {
+	BootstrapServers []string
+	Topics           []string
+	ConsumerGroup    string
+	Net              NetConfig
+	// Source is the source of the events. It is followed by '#' and the topic of the record.
+	Source string
+	// MaxRetries is the number of times a record the sink does not accept is sent again before it
+	// is dropped.
+	MaxRetries int
+
+	Logger *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+
+	// newConsumer is replaced in tests.
+	newConsumer func(*cluster.Config) (consumer, error)
+}
+
+// Start consumes the topics until stopCh is closed. A record is sent until the sink accepts it, the
+// sink rejects it permanently or the retries are exhausted, and only then is its offset marked.
+// Marked offsets are committed periodically, so that the consumer group resumes after the last
+// record sent when the adapter restarts.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	config, err := NewConfig(a.Net)
+	if err != nil {
+		return err
+	}
+	newConsumer := a.newConsumer
+	if newConsumer == nil {
+		newConsumer = func(config *cluster.Config) (consumer, error) {
+			return cluster.NewConsumer(a.BootstrapServers, a.ConsumerGroup, a.Topics, config)
+		}
+	}
+	c, err := newConsumer(config)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case msg, ok := <-c.Messages():
+			if !ok {
+				return errors.New("the consumer was closed")
+			}
+			if !a.deliver(stopCh, msg) {
+				return nil
+			}
+			c.MarkOffset(msg, "")
+		}
+	}
+}
+
+// deliver sends the record msg to the sink, with an exponential backoff between attempts, until
+// the sink accepts it. The record is dropped if the sink rejects it permanently, or still does not
+// accept it after MaxRetries retries, so that it does not block its partition. It returns false if
+// stopCh was closed first.
+func (a *Adapter) deliver(stopCh <-chan struct{}, msg *sarama.ConsumerMessage) bool {
+	backoff := minBackoff
+	for retry := 0; ; retry++ {
+		err := a.send(msg)
+		if err == nil {
+			return true
+		}
+		fields := []zap.Field{zap.Error(err), zap.String("topic", msg.Topic), zap.Int32("partition", msg.Partition), zap.Int64("offset", msg.Offset)}
+		if isPermanent(err) {
+			a.Logger.Error("Dropping the record rejected by the sink", fields...)
+			return true
+		}
+		if retry >= a.MaxRetries {
+			a.Logger.Error("Dropping the record the sink did not accept after the retries", append(fields, zap.Int("retries", retry))...)
+			return true
+		}
+		a.Logger.Error("Failed to send the record", append(fields, zap.Duration("backoff", backoff))...)
+		select {
+		case <-stopCh:
+			return false
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// isPermanent returns whether err is a status of the sink that sending the record again would not
+// change: the client errors, except for timeouts and throttling.
+func isPermanent(err error) bool {
+	sendErr, ok := err.(*adapter.SendError)
+	if !ok {
+		return false
+	}
+	switch sendErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return sendErr.StatusCode >= 400 && sendErr.StatusCode < 500
+}
+
+// NewConfig returns the configuration of a consumer connecting to Kafka with net.
+func NewConfig(net NetConfig) (*cluster.Config, error) {
+	config := cluster.NewConfig()
+	config.Version = sarama.V1_1_0_0
+	if net.SASLEnable {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.Handshake = true
+		config.Net.SASL.User = net.SASLUser
+		config.Net.SASL.Password = net.SASLPassword
+	}
+	if net.TLSEnable {
+		tlsConfig := &tls.Config{}
+		if net.TLSCert != "" || net.TLSKey != "" {
+			cert, err := tls.X509KeyPair([]byte(net.TLSCert), []byte(net.TLSKey))
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate: %v", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		if net.TLSCACert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(net.TLSCACert)) {
+				return nil, errors.New("invalid CA certificate")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+	return config, nil
+}
+
+// send sends the record msg to the sink.
+func (a *Adapter) send(msg *sarama.ConsumerMessage) error {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            fmt.Sprintf("partition:%d/offset:%d", msg.Partition, msg.Offset),
+		EventTime:          msg.Timestamp.UTC(),
+		EventType:          v1alpha1.KafkaEventType,
+		Source:             fmt.Sprintf("%s#%s", a.Source, msg.Topic),
+	}
+	return a.Client.Send(ctx, eventData(msg.Value))
+}
+
+// eventData returns value as JSON if it is valid JSON, and as a JSON string otherwise.
+func eventData(value []byte) interface{} {
+	if json.Valid(value) {
+		return json.RawMessage(value)
+	}
+	return string(value)
+}
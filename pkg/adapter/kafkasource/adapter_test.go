@@ -0,0 +1,264 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package kafkasource
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	cluster "github.com/bsm/sarama-cluster"
+	"github.com/knative/eventing/pkg/adapter"
+	"go.uber.org/zap"
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+type fakeConsumer struct {
+	messages chan *sarama.ConsumerMessage
+	marked   chan *sarama.ConsumerMessage
+	closed   bool
+}
+
+func (c *fakeConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func (c *fakeConsumer) MarkOffset(msg *sarama.ConsumerMessage, _ string) {
+	c.marked <- msg
+}
+
+func (c *fakeConsumer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestAdapter(t *testing.T) {
+	events := make(chan received, 3)
+	statuses := make(chan int, 3)
+	statuses <- http.StatusInternalServerError
+	statuses <- http.StatusAccepted
+	statuses <- http.StatusAccepted
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		events <- received{headers: r.Header, body: string(b)}
+		w.WriteHeader(<-statuses)
+	}))
+	defer sink.Close()
+
+	c := &fakeConsumer{
+		messages: make(chan *sarama.ConsumerMessage, 2),
+		marked:   make(chan *sarama.ConsumerMessage, 2),
+	}
+	timestamp := time.Date(2019, time.January, 7, 11, 0, 0, 0, time.UTC)
+	c.messages <- &sarama.ConsumerMessage{Topic: "orders", Partition: 1, Offset: 41, Timestamp: timestamp, Value: []byte(`{"id":7}`)}
+	c.messages <- &sarama.ConsumerMessage{Topic: "orders", Partition: 1, Offset: 42, Timestamp: timestamp, Value: []byte("plain text")}
+
+	var groupConfig *cluster.Config
+	a := &Adapter{
+		Topics:        []string{"orders"},
+		ConsumerGroup: "group",
+		Net:           NetConfig{SASLEnable: true, SASLUser: "user", SASLPassword: "password"},
+		Client:        adapter.NewClient(sink.URL),
+		Source:        "/apis/v1/namespaces/ns/kafkasources/source",
+		MaxRetries:    1,
+		Logger:        zap.NewNop(),
+		newConsumer: func(config *cluster.Config) (consumer, error) {
+			groupConfig = config
+			return c, nil
+		},
+	}
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- a.Start(stopCh)
+	}()
+
+	// The first record is sent again after the sink rejected it, and marked once it is accepted.
+	first, second := <-c.marked, <-c.marked
+	close(stopCh)
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if first.Offset != 41 || second.Offset != 42 {
+		t.Errorf("Unexpected marked offsets %d and %d", first.Offset, second.Offset)
+	}
+	if !c.closed {
+		t.Error("The consumer was not closed")
+	}
+	if !groupConfig.Net.SASL.Enable || groupConfig.Net.SASL.User != "user" {
+		t.Errorf("Unexpected SASL config %+v", groupConfig.Net.SASL)
+	}
+
+	for _, want := range []struct {
+		id   string
+		body string
+	}{
+		{id: "partition:1/offset:41", body: `{"id":7}`},
+		{id: "partition:1/offset:41", body: `{"id":7}`},
+		{id: "partition:1/offset:42", body: `"plain text"`},
+	} {
+		e := <-events
+		if e.body != want.body {
+			t.Errorf("Unexpected body. Expected: %q. Actual: %q", want.body, e.body)
+		}
+		if got := e.headers.Get("CE-EventID"); got != want.id {
+			t.Errorf("Unexpected event ID %q", got)
+		}
+		if got := e.headers.Get("CE-EventType"); got != "dev.knative.kafka.event" {
+			t.Errorf("Unexpected event type %q", got)
+		}
+		if got := e.headers.Get("CE-Source"); got != "/apis/v1/namespaces/ns/kafkasources/source#orders" {
+			t.Errorf("Unexpected source %q", got)
+		}
+		if got := e.headers.Get("CE-EventTime"); got != "2019-01-07T11:00:00Z" {
+			t.Errorf("Unexpected event time %q", got)
+		}
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	config, err := NewConfig(NetConfig{TLSEnable: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !config.Net.TLS.Enable || config.Net.TLS.Config == nil {
+		t.Errorf("TLS is not enabled: %+v", config.Net.TLS)
+	}
+	if config.Net.SASL.Enable {
+		t.Error("SASL is enabled")
+	}
+
+	if _, err := NewConfig(NetConfig{TLSEnable: true, TLSCert: "not a certificate", TLSKey: "not a key"}); err == nil {
+		t.Error("Expected an error for an invalid client certificate")
+	}
+	if _, err := NewConfig(NetConfig{TLSEnable: true, TLSCACert: "not a certificate"}); err == nil {
+		t.Error("Expected an error for an invalid CA certificate")
+	}
+}
+
+func TestAdapter_Dropped(t *testing.T) {
+	testCases := map[string]struct {
+		status     int
+		maxRetries int
+		wantSent   int
+	}{
+		"permanent failure": {
+			status:     http.StatusBadRequest,
+			maxRetries: 3,
+			wantSent:   1,
+		},
+		"payload too large": {
+			status:     http.StatusRequestEntityTooLarge,
+			maxRetries: 3,
+			wantSent:   1,
+		},
+		"retries exhausted": {
+			status:   http.StatusServiceUnavailable,
+			wantSent: 1,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			sent := 0
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent++
+				w.WriteHeader(tc.status)
+			}))
+			defer sink.Close()
+
+			c := &fakeConsumer{
+				messages: make(chan *sarama.ConsumerMessage, 1),
+				marked:   make(chan *sarama.ConsumerMessage, 1),
+			}
+			c.messages <- &sarama.ConsumerMessage{Topic: "orders", Partition: 1, Offset: 41, Value: []byte(`{"id":7}`)}
+
+			a := &Adapter{
+				Topics:      []string{"orders"},
+				Client:      adapter.NewClient(sink.URL),
+				MaxRetries:  tc.maxRetries,
+				Logger:      zap.NewNop(),
+				newConsumer: func(*cluster.Config) (consumer, error) { return c, nil },
+			}
+			stopCh := make(chan struct{})
+			done := make(chan error)
+			go func() {
+				done <- a.Start(stopCh)
+			}()
+
+			// The record is dropped, and its offset marked so that the partition is not blocked.
+			msg := <-c.marked
+			close(stopCh)
+			if err := <-done; err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if msg.Offset != 41 {
+				t.Errorf("Unexpected marked offset %d", msg.Offset)
+			}
+			if sent != tc.wantSent {
+				t.Errorf("Unexpected number of sends. Expected %d. Actual: %d", tc.wantSent, sent)
+			}
+		})
+	}
+}
+
+func TestAdapter_StopWhileRetrying(t *testing.T) {
+	sent := make(chan struct{}, 1)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case sent <- struct{}{}:
+		default:
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer sink.Close()
+
+	c := &fakeConsumer{
+		messages: make(chan *sarama.ConsumerMessage, 1),
+		marked:   make(chan *sarama.ConsumerMessage, 1),
+	}
+	c.messages <- &sarama.ConsumerMessage{Topic: "orders", Partition: 1, Offset: 41, Value: []byte(`{"id":7}`)}
+
+	a := &Adapter{
+		Topics:      []string{"orders"},
+		Client:      adapter.NewClient(sink.URL),
+		MaxRetries:  3,
+		Logger:      zap.NewNop(),
+		newConsumer: func(*cluster.Config) (consumer, error) { return c, nil },
+	}
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- a.Start(stopCh)
+	}()
+
+	<-sent
+	close(stopCh)
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	select {
+	case msg := <-c.marked:
+		t.Errorf("Unexpected marked offset %d of a record the sink did not accept", msg.Offset)
+	default:
+	}
+}
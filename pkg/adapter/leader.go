@@ -0,0 +1,48 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/knative/eventing/pkg/leaderelection"
+)
+
+// RunLeaderElected runs start while the replica env.PodName leads the election of the adapter
+// named component of the source in env, and until stopCh is closed. The election is held on a
+// ConfigMap in the namespace of the source. It returns an error if the replica stops leading
+// before stopCh is closed, so that it is restarted and campaigns again.
+func RunLeaderElected(stopCh <-chan struct{}, env *EnvConfig, component string, logger *zap.Logger, start func(<-chan struct{}) error) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	election := leaderelection.NewConfig(fmt.Sprintf("%s-%s", env.Name, component))
+	election.LeaseNamespace = env.Namespace
+	lock, err := leaderelection.NewLock(election, kubeClient, env.PodName)
+	if err != nil {
+		return err
+	}
+	return leaderelection.Run(stopCh, election, lock, logger, start)
+}
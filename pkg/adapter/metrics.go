@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	eventsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "adapter",
+		Name:      "events_sent_total",
+		Help:      "The number of events sent to the sink, by event type and result.",
+	}, []string{"event_type", "result"})
+
+	sendLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "adapter",
+		Name:      "event_send_latency_seconds",
+		Help:      "The time it took the sink to respond to an event, by event type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"event_type"})
+)
+
+func init() {
+	prometheus.MustRegister(eventsSent, sendLatency)
+}
+
+// MetricsHandler returns the handler serving the Prometheus metrics of the adapter at /metrics.
+func MetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package mqttsource implements the receive adapter of MQTTSources, which subscribes to the topics
+// of an MQTT broker and sends their messages to the sink as events.
+package mqttsource
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+)
+
+const (
+	// keepAlive is the interval of the pings of the broker.
+	keepAlive = 30 * time.Second
+
+	// minBackoff and maxBackoff bound the delay before the adapter connects again.
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+
+	// disconnectQuiesce is how long the messages being handled are waited for when the adapter
+	// stops.
+	disconnectQuiesce = 5 * time.Second
+)
+
+// NetConfig configures the connection to the broker. The certificates and key are PEM encoded.
+type NetConfig struct {
+	User     string
+	Password string
+
+	TLSEnable bool
+	TLSCert   string
+	TLSKey    string
+	TLSCACert string
+}
+
+// Topic is a topic filter, and the type of the events of the messages whose topic matches it.
+type Topic struct {
+	Filter    string
+	EventType string
+}
+
+// Adapter subscribes to Topics on Broker, and sends every message to the sink.
+type Adapter struct {
+	// Broker is the host:port of the broker.
+	Broker   string
+	Topics   []Topic
+	ClientID string
+	QoS      byte
+	Net      NetConfig
+
+	Logger *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+}
+
+// Start receives the messages of the topics until stopCh is closed. The MQTT client connects again
+// and subscribes to the topics again whenever the connection to the broker is lost. With QoS 1,
+// the session of the client is kept by the broker and every message is acknowledged once it has
+// been handled, so that the messages published while the adapter is disconnected are delivered
+// when it connects again.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	config, err := NewTLSConfig(a.Net)
+	if err != nil {
+		return err
+	}
+	filters := make(map[string]byte, len(a.Topics))
+	for _, t := range a.Topics {
+		filters[t.Filter] = a.QoS
+	}
+	opts := mqtt.NewClientOptions().
+		SetProtocolVersion(4).
+		SetClientID(a.ClientID).
+		SetUsername(a.Net.User).
+		SetPassword(a.Net.Password).
+		SetCleanSession(a.QoS == 0).
+		SetKeepAlive(keepAlive).
+		SetConnectTimeout(keepAlive).
+		SetMaxReconnectInterval(maxBackoff).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			if token := c.SubscribeMultiple(filters, a.handle); token.Wait() && token.Error() != nil {
+				a.Logger.Error("Failed to subscribe to the topics", zap.Error(token.Error()))
+			}
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			a.Logger.Error("Lost the connection to the broker", zap.Error(err))
+		})
+	if config != nil {
+		opts.AddBroker("ssl://" + a.Broker).SetTLSConfig(config)
+	} else {
+		opts.AddBroker("tcp://" + a.Broker)
+	}
+	client := mqtt.NewClient(opts)
+
+	// The client only connects again by itself once it has connected.
+	backoff := minBackoff
+	for {
+		token := client.Connect()
+		if token.Wait() && token.Error() == nil {
+			break
+		}
+		a.Logger.Error("Failed to connect to the broker", zap.Error(token.Error()), zap.Duration("backoff", backoff))
+		select {
+		case <-stopCh:
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	<-stopCh
+	client.Disconnect(uint(disconnectQuiesce / time.Millisecond))
+	return nil
+}
+
+// NewTLSConfig returns the TLS configuration of the connection to the broker, or nil if TLS is not
+// enabled.
+func NewTLSConfig(net NetConfig) (*tls.Config, error) {
+	if !net.TLSEnable {
+		return nil, nil
+	}
+	config := &tls.Config{}
+	if net.TLSCert != "" || net.TLSKey != "" {
+		cert, err := tls.X509KeyPair([]byte(net.TLSCert), []byte(net.TLSKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if net.TLSCACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(net.TLSCACert)) {
+			return nil, errors.New("invalid CA certificate")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// handle sends msg to the sink. The message is acknowledged once handle returns, even if the
+// sink rejected it.
+func (a *Adapter) handle(_ mqtt.Client, msg mqtt.Message) {
+	if err := a.send(msg.Topic(), msg.Payload()); err != nil {
+		a.Logger.Error("Failed to send the message", zap.Error(err), zap.String("topic", msg.Topic()))
+	}
+}
+
+// send sends the message published to topic with payload to the sink.
+func (a *Adapter) send(topic string, payload []byte) error {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            uuid.New().String(),
+		EventTime:          time.Now().UTC(),
+		EventType:          a.eventType(topic),
+		Source:             fmt.Sprintf("mqtt://%s/%s", a.Broker, topic),
+		Extensions: map[string]interface{}{
+			v1alpha1.MQTTTopicExtension: topic,
+		},
+	}
+	return a.Client.Send(ctx, eventData(payload))
+}
+
+// eventType returns the event type of the first topic whose filter matches topic.
+func (a *Adapter) eventType(topic string) string {
+	for _, t := range a.Topics {
+		if MatchTopic(t.Filter, topic) && t.EventType != "" {
+			return t.EventType
+		}
+	}
+	return v1alpha1.MQTTEventType
+}
+
+// MatchTopic returns true if the topic name matches the topic filter.
+func MatchTopic(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	// The wildcards do not match the topics starting with '$', which are reserved by brokers.
+	if strings.HasPrefix(topic, "$") && (filterLevels[0] == "#" || filterLevels[0] == "+") {
+		return false
+	}
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) || (level != "+" && level != topicLevels[i]) {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+// eventData returns payload as JSON if it is valid JSON, and as a JSON string otherwise.
+func eventData(payload []byte) interface{} {
+	if json.Valid(payload) {
+		return json.RawMessage(payload)
+	}
+	return string(payload)
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mqttsource
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/knative/eventing/pkg/adapter"
+	"go.uber.org/zap"
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+// fakeBroker is the broker side of an MQTT connection.
+type fakeBroker struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+// expect reads the next packet, which must be of type typ.
+func (b *fakeBroker) expect(typ byte) packets.ControlPacket {
+	p, err := packets.ReadPacket(b.conn)
+	if err != nil {
+		b.t.Fatalf("Unexpected error reading a packet: %v", err)
+	}
+	if reflect.TypeOf(p) != reflect.TypeOf(packets.NewControlPacket(typ)) {
+		b.t.Fatalf("Unexpected packet %v, expecting %s", p, packets.PacketNames[typ])
+	}
+	return p
+}
+
+func (b *fakeBroker) write(p packets.ControlPacket) {
+	if err := p.Write(b.conn); err != nil {
+		b.t.Errorf("Unexpected error writing a packet: %v", err)
+	}
+}
+
+// publish publishes payload to topic with QoS 1.
+func (b *fakeBroker) publish(topic string, id uint16, payload string) {
+	p := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	p.TopicName = topic
+	p.Qos = 1
+	p.MessageID = id
+	p.Payload = []byte(payload)
+	b.write(p)
+}
+
+func TestAdapter(t *testing.T) {
+	events := make(chan received, 2)
+	statuses := make(chan int, 2)
+	statuses <- http.StatusAccepted
+	statuses <- http.StatusInternalServerError
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		events <- received{headers: r.Header, body: string(b)}
+		w.WriteHeader(<-statuses)
+	}))
+	defer sink.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer l.Close()
+	acked := make(chan uint16, 2)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		broker := &fakeBroker{t: t, conn: conn}
+		connect := broker.expect(packets.Connect).(*packets.ConnectPacket)
+		if connect.ClientIdentifier != "ns.source" || connect.CleanSession {
+			t.Errorf("Unexpected CONNECT %v", connect)
+		}
+		broker.write(packets.NewControlPacket(packets.Connack))
+		subscribe := broker.expect(packets.Subscribe).(*packets.SubscribePacket)
+		suback := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+		suback.MessageID = subscribe.MessageID
+		suback.ReturnCodes = subscribe.Qoss
+		broker.write(suback)
+		broker.publish("sensors/1/temperature", 1, `{"celsius":21.5}`)
+		broker.publish("alarms/door", 2, "open")
+		for {
+			p, err := packets.ReadPacket(conn)
+			if err != nil {
+				return
+			}
+			switch p := p.(type) {
+			case *packets.PubackPacket:
+				acked <- p.MessageID
+			case *packets.PingreqPacket:
+				broker.write(packets.NewControlPacket(packets.Pingresp))
+			}
+		}
+	}()
+
+	a := &Adapter{
+		Broker:   l.Addr().String(),
+		Topics:   []Topic{{Filter: "sensors/+/temperature", EventType: "com.example.temperature"}, {Filter: "alarms/#"}},
+		ClientID: "ns.source",
+		QoS:      1,
+		Client:   adapter.NewClient(sink.URL),
+		Logger:   zap.NewNop(),
+	}
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- a.Start(stopCh)
+	}()
+
+	// Both messages are acknowledged, even the one the sink rejected.
+	first, second := <-acked, <-acked
+	close(stopCh)
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if first != 1 || second != 2 {
+		t.Errorf("Unexpected acknowledged messages %d and %d", first, second)
+	}
+
+	for _, want := range []struct {
+		eventType string
+		source    string
+		// topic is JSON encoded, like every extension.
+		topic string
+		body  string
+	}{
+		{eventType: "com.example.temperature", source: "mqtt://" + a.Broker + "/sensors/1/temperature", topic: `"sensors/1/temperature"`, body: `{"celsius":21.5}`},
+		{eventType: "dev.knative.mqtt.message", source: "mqtt://" + a.Broker + "/alarms/door", topic: `"alarms/door"`, body: `"open"`},
+	} {
+		e := <-events
+		if e.body != want.body {
+			t.Errorf("Unexpected body. Expected: %q. Actual: %q", want.body, e.body)
+		}
+		if got := e.headers.Get("CE-EventType"); got != want.eventType {
+			t.Errorf("Unexpected event type %q", got)
+		}
+		if got := e.headers.Get("CE-Source"); got != want.source {
+			t.Errorf("Unexpected source %q", got)
+		}
+		if got := e.headers.Get("CE-X-Topic"); got != want.topic {
+			t.Errorf("Unexpected topic %q", got)
+		}
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	config, err := NewTLSConfig(NetConfig{})
+	if err != nil || config != nil {
+		t.Errorf("Unexpected TLS config %v, %v", config, err)
+	}
+	if config, err = NewTLSConfig(NetConfig{TLSEnable: true}); err != nil || config == nil {
+		t.Errorf("Unexpected TLS config %v, %v", config, err)
+	}
+	if _, err := NewTLSConfig(NetConfig{TLSEnable: true, TLSCert: "not a certificate", TLSKey: "not a key"}); err == nil {
+		t.Error("Expected an error for an invalid client certificate")
+	}
+	if _, err := NewTLSConfig(NetConfig{TLSEnable: true, TLSCACert: "not a certificate"}); err == nil {
+		t.Error("Expected an error for an invalid CA certificate")
+	}
+}
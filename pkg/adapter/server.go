@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// ShutdownTimeout is how long ListenAndServe waits for the requests in flight when it shuts
+	// the server down.
+	ShutdownTimeout = 1 * time.Minute
+
+	readTimeout  = 1 * time.Minute
+	writeTimeout = 1 * time.Minute
+)
+
+// ListenAndServe serves handler on port until stopCh is closed. It then shuts the server down
+// gracefully, waiting at most ShutdownTimeout for the requests in flight.
+func ListenAndServe(stopCh <-chan struct{}, port int, handler http.Handler) error {
+	s := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      handler,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+	return serve(stopCh, s, s.ListenAndServe)
+}
+
+// serve runs listen, which serves s, until stopCh is closed.
+func serve(stopCh <-chan struct{}, s *http.Server, listen func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- listen()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-stopCh:
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		return err
+	}
+	if err := <-errCh; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
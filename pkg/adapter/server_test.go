@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListenAndServe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	released := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-released
+		w.WriteHeader(http.StatusAccepted)
+	})
+	s := &http.Server{Handler: handler}
+	stopCh := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serve(stopCh, s, func() error { return s.Serve(l) })
+	}()
+
+	// A request in flight when the server is stopped completes.
+	resCh := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Get("http://" + l.Addr().String())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		resCh <- res
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(stopCh)
+	time.Sleep(100 * time.Millisecond)
+	close(released)
+
+	if res := <-resCh; res != nil && res.StatusCode != http.StatusAccepted {
+		t.Errorf("unexpected status: want %d, got %d", http.StatusAccepted, res.StatusCode)
+	}
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server did not stop")
+	}
+}
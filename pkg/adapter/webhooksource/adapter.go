@@ -0,0 +1,142 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package webhooksource implements the receive adapter of WebhookSources, which accepts the JSON
+// payloads posted to the endpoint of the source and sends them to the sink as events.
+package webhooksource
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knative/eventing/pkg/adapter"
+	"github.com/knative/pkg/cloudevents"
+	"go.uber.org/zap"
+)
+
+const (
+	// Port is the port the adapter listens on.
+	Port = 8080
+
+	// signaturePrefix precedes the hex HMAC-SHA256 signature of a payload.
+	signaturePrefix = "sha256="
+
+	// maxPayloadBytes is the largest payload that is accepted.
+	maxPayloadBytes = 10 << 20
+)
+
+// Adapter is an http.Handler accepting posted JSON payloads. It sends every payload to the sink as
+// an event of EventType from EventSource. If Secret is set, only the payloads signed with it are
+// accepted.
+type Adapter struct {
+	EventType   string
+	EventSource string
+	// Secret is the secret the payloads are signed with, and SignatureHeader the header holding
+	// their signature.
+	Secret          string
+	SignatureHeader string
+
+	Logger *zap.Logger
+	// Client sends the events to the sink.
+	Client *adapter.Client
+}
+
+var _ http.Handler = (*Adapter)(nil)
+var _ adapter.Adapter = (*Adapter)(nil)
+
+// Start serves the posted payloads on Port until stopCh is closed.
+func (a *Adapter) Start(stopCh <-chan struct{}) error {
+	return adapter.ListenAndServe(stopCh, Port, a)
+}
+
+// ServeHTTP validates the posted payload and sends it to the sink.
+func (a *Adapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadBytes+1))
+	if err != nil {
+		http.Error(w, "unable to read the payload", http.StatusBadRequest)
+		return
+	}
+	if len(payload) > maxPayloadBytes {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if a.Secret != "" {
+		if err := a.verifySignature(r.Header.Get(a.SignatureHeader), payload); err != nil {
+			a.Logger.Info("Rejected a request", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+	if !json.Valid(payload) {
+		http.Error(w, "the payload is not JSON", http.StatusBadRequest)
+		return
+	}
+	if err := a.send(payload); err != nil {
+		a.Logger.Error("Failed to send the event", zap.Error(err))
+		http.Error(w, "unable to send the event", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verifySignature returns an error unless signature is the signature of payload with the secret.
+func (a *Adapter) verifySignature(signature string, payload []byte) error {
+	if signature == "" {
+		return fmt.Errorf("missing %s header", a.SignatureHeader)
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return fmt.Errorf("unexpected signature %q", signature)
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return fmt.Errorf("unexpected signature %q", signature)
+	}
+	if !hmac.Equal(got, sign(a.Secret, payload)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// sign returns the HMAC-SHA256 of payload with secret.
+func sign(secret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// send sends payload to the sink.
+func (a *Adapter) send(payload []byte) error {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            uuid.New().String(),
+		EventTime:          time.Now().UTC(),
+		EventType:          a.EventType,
+		Source:             a.EventSource,
+	}
+	return a.Client.Send(ctx, json.RawMessage(payload))
+}
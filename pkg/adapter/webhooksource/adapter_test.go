@@ -0,0 +1,152 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package webhooksource
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/knative/eventing/pkg/adapter"
+	"go.uber.org/zap"
+)
+
+const (
+	secret  = "s3cr3t"
+	payload = `{"order":7}`
+)
+
+type received struct {
+	headers http.Header
+	body    string
+}
+
+func TestAdapter(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		secret     string
+		payload    string
+		headers    map[string]string
+		sinkStatus int
+		wantStatus int
+		wantEvent  bool
+	}{{
+		name:       "unsigned payload",
+		method:     http.MethodPost,
+		payload:    payload,
+		sinkStatus: http.StatusAccepted,
+		wantStatus: http.StatusAccepted,
+		wantEvent:  true,
+	}, {
+		name:    "signed payload",
+		method:  http.MethodPost,
+		secret:  secret,
+		payload: payload,
+		headers: map[string]string{
+			"X-Webhook-Signature": "sha256=" + hex.EncodeToString(sign(secret, []byte(payload))),
+		},
+		sinkStatus: http.StatusOK,
+		wantStatus: http.StatusAccepted,
+		wantEvent:  true,
+	}, {
+		name:    "invalid signature",
+		method:  http.MethodPost,
+		secret:  secret,
+		payload: payload,
+		headers: map[string]string{
+			"X-Webhook-Signature": "sha256=" + hex.EncodeToString(sign("wrong", []byte(payload))),
+		},
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:       "missing signature",
+		method:     http.MethodPost,
+		secret:     secret,
+		payload:    payload,
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:       "not JSON",
+		method:     http.MethodPost,
+		payload:    "order=7",
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:       "not a POST",
+		method:     http.MethodGet,
+		wantStatus: http.StatusMethodNotAllowed,
+	}, {
+		name:       "sink rejects the event",
+		method:     http.MethodPost,
+		payload:    payload,
+		sinkStatus: http.StatusInternalServerError,
+		wantStatus: http.StatusBadGateway,
+		wantEvent:  true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := make(chan received, 1)
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				events <- received{headers: r.Header, body: string(b)}
+				w.WriteHeader(test.sinkStatus)
+			}))
+			defer sink.Close()
+
+			a := &Adapter{
+				EventType:       "com.example.order.created",
+				EventSource:     "https://orders-webhooksource.ns.example.com",
+				Secret:          test.secret,
+				SignatureHeader: "X-Webhook-Signature",
+				Client:          adapter.NewClient(sink.URL),
+				Logger:          zap.NewNop(),
+			}
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.payload))
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			a.ServeHTTP(w, req)
+
+			if w.Code != test.wantStatus {
+				t.Errorf("Unexpected status. Expected: %v. Actual: %v", test.wantStatus, w.Code)
+			}
+			select {
+			case e := <-events:
+				if !test.wantEvent {
+					t.Fatalf("Unexpected event %v", e)
+				}
+				if e.body != payload {
+					t.Errorf("Unexpected body. Expected: %q. Actual: %q", payload, e.body)
+				}
+				if got := e.headers.Get("CE-EventType"); got != a.EventType {
+					t.Errorf("Unexpected event type %q", got)
+				}
+				if got := e.headers.Get("CE-EventID"); got == "" {
+					t.Error("Missing event ID")
+				}
+				if got := e.headers.Get("CE-Source"); got != a.EventSource {
+					t.Errorf("Unexpected source %q", got)
+				}
+			default:
+				if test.wantEvent {
+					t.Error("Expected an event")
+				}
+			}
+		})
+	}
+}
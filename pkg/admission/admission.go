@@ -0,0 +1,191 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admission holds what the admission webhooks of eventing share: generating their
+// certificates, registering them with the API server, and decoding the AdmissionReviews they
+// serve. The webhooks only implement the admission of the requests.
+package admission
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/knative/pkg/logging"
+	"github.com/knative/pkg/webhook"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options configures a webhook.
+type Options struct {
+	// WebhookName is the name of the webhook configuration registering the webhook.
+	WebhookName string
+
+	// ServiceName is the name of the Service, in Namespace, that routes to the webhook.
+	ServiceName string
+
+	// Namespace is the namespace of the Service.
+	Namespace string
+
+	// Port is the port the webhook listens on.
+	Port int
+}
+
+// Serve generates the certificates of the webhook, registers the webhook trusting them with
+// register, and serves handler until stop is closed. The certificates are generated each time
+// the webhook starts.
+func Serve(logger *zap.Logger, opts Options, register func(caCert []byte) error, handler http.Handler, stop <-chan struct{}) error {
+	ctx := logging.WithLogger(context.TODO(), logger.Sugar())
+	serverKey, serverCert, caCert, err := webhook.CreateCerts(ctx, opts.ServiceName, opts.Namespace)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		return err
+	}
+	if err := register(caCert); err != nil {
+		return err
+	}
+	logger.Info("Registered the webhook", zap.String("service", opts.ServiceName))
+
+	server := &http.Server{
+		Handler:   handler,
+		Addr:      fmt.Sprintf(":%d", opts.Port),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServeTLS("", "")
+	}()
+	select {
+	case <-stop:
+		return server.Close()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// RegisterMutating creates the MutatingWebhookConfiguration of the webhook, or updates it to
+// match rules and trust caCert.
+func RegisterMutating(kc kubernetes.Interface, opts Options, rules []admissionregistrationv1beta1.RuleWithOperations, caCert []byte) error {
+	config := &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: opts.WebhookName,
+		},
+		Webhooks: webhooks(opts, rules, caCert),
+	}
+
+	client := kc.AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+	current, err := client.Get(config.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(config)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(current.Webhooks, config.Webhooks) {
+		return nil
+	}
+	current.Webhooks = config.Webhooks
+	_, err = client.Update(current)
+	return err
+}
+
+// RegisterValidating creates the ValidatingWebhookConfiguration of the webhook, or updates it to
+// match rules and trust caCert.
+func RegisterValidating(kc kubernetes.Interface, opts Options, rules []admissionregistrationv1beta1.RuleWithOperations, caCert []byte) error {
+	config := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: opts.WebhookName,
+		},
+		Webhooks: webhooks(opts, rules, caCert),
+	}
+
+	client := kc.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	current, err := client.Get(config.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(config)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(current.Webhooks, config.Webhooks) {
+		return nil
+	}
+	current.Webhooks = config.Webhooks
+	_, err = client.Update(current)
+	return err
+}
+
+// webhooks returns the webhooks of the configuration of the webhook described by opts.
+func webhooks(opts Options, rules []admissionregistrationv1beta1.RuleWithOperations, caCert []byte) []admissionregistrationv1beta1.Webhook {
+	// None of the webhooks is required for the resources to work, so changes must not be rejected
+	// because a webhook is unavailable.
+	failurePolicy := admissionregistrationv1beta1.Ignore
+	return []admissionregistrationv1beta1.Webhook{{
+		Name:  opts.WebhookName,
+		Rules: rules,
+		ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+			Service: &admissionregistrationv1beta1.ServiceReference{
+				Namespace: opts.Namespace,
+				Name:      opts.ServiceName,
+			},
+			CABundle: caCert,
+		},
+		FailurePolicy: &failurePolicy,
+	}}
+}
+
+// AdmitFunc admits an admission request. The UID of the response is set by Handler.
+type AdmitFunc func(*admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse
+
+// Handler returns the http.Handler decoding the AdmissionReviews sent to a webhook, and
+// responding with the responses of admit.
+func Handler(admit AdmitFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "invalid Content-Type, want `application/json`", http.StatusUnsupportedMediaType)
+			return
+		}
+		var review admissionv1beta1.AdmissionReview
+		defer r.Body.Close()
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			http.Error(w, fmt.Sprintf("could not decode body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(w, "the review has no request", http.StatusBadRequest)
+			return
+		}
+
+		response := admissionv1beta1.AdmissionReview{Response: admit(review.Request)}
+		response.Response.UID = review.Request.UID
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
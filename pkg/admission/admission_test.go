@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestHandler(t *testing.T) {
+	review, _ := json.Marshal(admissionv1beta1.AdmissionReview{
+		Request: &admissionv1beta1.AdmissionRequest{UID: "uid"},
+	})
+	noRequest, _ := json.Marshal(admissionv1beta1.AdmissionReview{})
+
+	testCases := map[string]struct {
+		contentType string
+		body        []byte
+		wantStatus  int
+	}{
+		"wrong content type": {
+			contentType: "text/plain",
+			body:        review,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		"invalid body": {
+			contentType: "application/json",
+			body:        []byte("{"),
+			wantStatus:  http.StatusBadRequest,
+		},
+		"no request": {
+			contentType: "application/json",
+			body:        noRequest,
+			wantStatus:  http.StatusBadRequest,
+		},
+		"admitted": {
+			contentType: "application/json",
+			body:        review,
+			wantStatus:  http.StatusOK,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			h := Handler(func(*admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+				return &admissionv1beta1.AdmissionResponse{Allowed: true}
+			})
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("unexpected status: want %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var got admissionv1beta1.AdmissionReview
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("could not decode the response: %v", err)
+			}
+			if got.Response == nil || !got.Response.Allowed || got.Response.UID != "uid" {
+				t.Errorf("unexpected response: %+v", got.Response)
+			}
+		})
+	}
+}
+
+func TestRegisterValidating(t *testing.T) {
+	opts := Options{
+		WebhookName: "test.webhook.eventing.knative.dev",
+		ServiceName: "test-webhook",
+		Namespace:   "knative-eventing",
+	}
+	rules := []admissionregistrationv1beta1.RuleWithOperations{{
+		Operations: []admissionregistrationv1beta1.OperationType{admissionregistrationv1beta1.Create},
+		Rule: admissionregistrationv1beta1.Rule{
+			APIGroups:   []string{"eventing.knative.dev"},
+			APIVersions: []string{"v1alpha1"},
+			Resources:   []string{"subscriptions"},
+		},
+	}}
+
+	var stored *admissionregistrationv1beta1.ValidatingWebhookConfiguration
+	var writes int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(&metav1.Status{
+					TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+					Status:   metav1.StatusFailure,
+					Reason:   metav1.StatusReasonNotFound,
+					Code:     http.StatusNotFound,
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+			return
+		}
+		writes++
+		stored = &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+		json.NewDecoder(r.Body).Decode(stored)
+		json.NewEncoder(w).Encode(stored)
+	}))
+	defer s.Close()
+	kc, err := kubernetes.NewForConfig(&rest.Config{Host: s.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for i, caCert := range []string{"ca", "ca", "rotated"} {
+		if err := RegisterValidating(kc, opts, rules, []byte(caCert)); err != nil {
+			t.Fatalf("RegisterValidating() = %v", err)
+		}
+		if stored == nil || stored.Name != opts.WebhookName || len(stored.Webhooks) != 1 {
+			t.Fatalf("unexpected configuration: %+v", stored)
+		}
+		if got := string(stored.Webhooks[0].ClientConfig.CABundle); got != caCert {
+			t.Errorf("registration %d: unexpected CA bundle: want %q, got %q", i, caCert, got)
+		}
+	}
+	// The configuration is created, left alone while it is up to date, then updated.
+	if writes != 2 {
+		t.Errorf("unexpected number of writes: want 2, got %d", writes)
+	}
+	if p := stored.Webhooks[0].FailurePolicy; p == nil || *p != admissionregistrationv1beta1.Ignore {
+		t.Errorf("unexpected failure policy: %v", p)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/knative/pkg/apis/duck"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+)
+
+// SubscribableFromUnstructured reads the Subscribable portion of obj, such as an object returned by
+// a dynamic client. obj may be of any kind that has a spec.subscribable, so any CRD can act as a
+// Channel.
+func SubscribableFromUnstructured(obj duck.Marshalable) (*Channel, error) {
+	c := &Channel{}
+	if err := duck.FromUnstructured(obj, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SubscribersPatch returns the JSON patch replacing the spec.subscribable of c with subs.
+func (c *Channel) SubscribersPatch(subs *Subscribable) ([]byte, error) {
+	after := c.DeepCopy()
+	after.Spec.Subscribable = subs
+	patch, err := duck.CreatePatch(c, after)
+	if err != nil {
+		return nil, err
+	}
+	return patch.MarshalJSON()
+}
+
+// AddressFromUnstructured returns the hostname in the status.address of obj, such as an object
+// returned by a dynamic client. obj may be of any kind that is Addressable, so any CRD can act as a
+// sink. It returns an error if obj has no address yet.
+func AddressFromUnstructured(obj duck.Marshalable) (string, error) {
+	a := duckv1alpha1.AddressableType{}
+	if err := duck.FromUnstructured(obj, &a); err != nil {
+		return "", err
+	}
+	if a.Status.Address == nil {
+		return "", fmt.Errorf("status does not contain address")
+	}
+	return a.Status.Address.Hostname, nil
+}
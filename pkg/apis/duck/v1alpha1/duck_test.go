@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// widget is a CRD that is not a Channel, but implements Subscribable and Addressable.
+func widget(status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"namespace": "default",
+				"name":      "widget",
+			},
+			"spec": map[string]interface{}{
+				"color": "blue",
+				"subscribable": map[string]interface{}{
+					"subscribers": []interface{}{
+						map[string]interface{}{
+							"subscriberURI": "call1",
+						},
+					},
+				},
+			},
+			"status": status,
+		},
+	}
+}
+
+func TestSubscribableFromUnstructured(t *testing.T) {
+	c, err := SubscribableFromUnstructured(widget(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Name != "widget" {
+		t.Errorf("Unexpected name. Expected %q. Actual %q", "widget", c.Name)
+	}
+	want := &Subscribable{Subscribers: []ChannelSubscriberSpec{{SubscriberURI: "call1"}}}
+	if diff := cmp.Diff(want, c.Spec.Subscribable); diff != "" {
+		t.Errorf("Unexpected subscribable (-want, +got): %v", diff)
+	}
+}
+
+func TestSubscribersPatch(t *testing.T) {
+	c, err := SubscribableFromUnstructured(widget(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	patch, err := c.SubscribersPatch(&Subscribable{Subscribers: []ChannelSubscriberSpec{{SubscriberURI: "call2"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `[{"op":"replace","path":"/spec/subscribable/subscribers/0/subscriberURI","value":"call2"}]`
+	if diff := cmp.Diff(want, string(patch)); diff != "" {
+		t.Errorf("Unexpected patch (-want, +got): %v", diff)
+	}
+}
+
+func TestAddressFromUnstructured(t *testing.T) {
+	testCases := map[string]struct {
+		status  map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		"address": {
+			status: map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": "widget.default.svc.cluster.local",
+				},
+			},
+			want: "widget.default.svc.cluster.local",
+		},
+		"no address": {
+			status:  map[string]interface{}{},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := AddressFromUnstructured(widget(tc.status))
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("Unexpected address. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -35,6 +35,15 @@ type Subscribable struct {
 // Ref is a reference to the Subscription this ChannelSubscriberSpec was created for
 // SubscriberURI is the endpoint for the subscriber
 // ReplyURI is the endpoint for the reply
+// Filter is an expression selecting the events delivered to this subscriber
+// Transform is a template rewriting the events delivered to this subscriber
+// Schema is the JSON Schema the data of the events delivered to this subscriber must conform to
+// DeadLetterURI receives the events whose data does not conform to Schema
+// ErrorURI receives the events SubscriberURI fails to accept, with the failure in their extensions
+// Auth describes the credentials attached to deliveries to SubscriberURI
+// Paused stops deliveries to this subscriber, while keeping its position in durable channels
+// Protocol is the protocol events are delivered to SubscriberURI with, HTTP by default, or SSE
+// to push them to the consumers connected to the dispatcher, without a SubscriberURI
 // At least one of SubscriberURI and ReplyURI must be present
 type ChannelSubscriberSpec struct {
 	// +optional
@@ -43,6 +52,121 @@ type ChannelSubscriberSpec struct {
 	SubscriberURI string `json:"subscriberURI,omitempty"`
 	// +optional
 	ReplyURI string `json:"replyURI,omitempty"`
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// +optional
+	Transform string `json:"transform,omitempty"`
+	// +optional
+	Schema *SubscriberSchema `json:"schema,omitempty"`
+	// +optional
+	DeadLetterURI string `json:"deadLetterURI,omitempty"`
+	// +optional
+	ErrorURI string `json:"errorURI,omitempty"`
+	// +optional
+	Auth *SubscriberAuth `json:"auth,omitempty"`
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// +optional
+	Protocol DeliveryProtocol `json:"protocol,omitempty"`
+}
+
+// DeliveryProtocol is the protocol a dispatcher delivers events to a subscriber with.
+type DeliveryProtocol string
+
+const (
+	// HTTPDeliveryProtocol delivers events with the CloudEvents HTTP binding.
+	HTTPDeliveryProtocol DeliveryProtocol = "HTTP"
+
+	// GRPCDeliveryProtocol delivers events with the CloudEvents protobuf format, calling the
+	// Deliver method of the knative.eventing.v1alpha1.Subscriber gRPC service of the subscriber.
+	GRPCDeliveryProtocol DeliveryProtocol = "gRPC"
+
+	// AMQPDeliveryProtocol sends events to a node of an AMQP 1.0 container with the CloudEvents
+	// AMQP binding. The path of the subscriber URI is the address of the node.
+	AMQPDeliveryProtocol DeliveryProtocol = "AMQP"
+
+	// MQTTDeliveryProtocol publishes events to a topic of an MQTT broker with the CloudEvents MQTT
+	// binding. The path of the subscriber URI is the topic.
+	MQTTDeliveryProtocol DeliveryProtocol = "MQTT"
+
+	// SSEDeliveryProtocol pushes events as Server-Sent Events to the consumers connected to the
+	// dispatcher, rather than delivering them to an endpoint of the subscriber.
+	SSEDeliveryProtocol DeliveryProtocol = "SSE"
+)
+
+// SubscriberAuth describes the credentials a dispatcher attaches to the requests it sends to a
+// subscriber. At most one of BearerToken, Basic and OIDC may be set, and ClientCertificate may be
+// combined with any of them. The referenced Secrets are read from the namespace of the
+// Subscription.
+type SubscriberAuth struct {
+	// BearerToken is sent in the Authorization header as 'Bearer <token>'.
+	// +optional
+	BearerToken *corev1.SecretKeySelector `json:"bearerToken,omitempty"`
+
+	// Basic is sent in the Authorization header using HTTP basic authentication.
+	// +optional
+	Basic *BasicAuth `json:"basic,omitempty"`
+
+	// OIDC obtains an access token from an OAuth 2.0 token endpoint using the
+	// client credentials flow, and sends it as a bearer token.
+	// +optional
+	OIDC *OIDCClientCredentials `json:"oidc,omitempty"`
+
+	// ClientCertificate is presented to subscribers that require mutual TLS.
+	// +optional
+	ClientCertificate *ClientCertificate `json:"clientCertificate,omitempty"`
+}
+
+// ClientCertificate references the certificate a dispatcher presents to a subscriber that
+// requires mutual TLS.
+type ClientCertificate struct {
+	// SecretName is the name of a Secret of type kubernetes.io/tls. Its tls.crt and tls.key are
+	// the certificate chain and private key presented to the subscriber. If it also has a ca.crt,
+	// the certificate of the subscriber is verified against it rather than the system roots.
+	SecretName string `json:"secretName"`
+}
+
+// SubscriberSchema references the JSON Schema that the data of the events delivered to a
+// subscriber must conform to. Exactly one of the fields may be set. The referenced ConfigMap is
+// read from the namespace of the Subscription.
+type SubscriberSchema struct {
+	// ConfigMapKeyRef selects a JSON Schema in a ConfigMap.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// Registry selects a JSON Schema in a Confluent Schema Registry.
+	// +optional
+	Registry *SchemaRegistryRef `json:"registry,omitempty"`
+}
+
+// SchemaRegistryRef selects a version of the schema of a subject of a Confluent Schema Registry.
+type SchemaRegistryRef struct {
+	// URL is the base URL of the registry.
+	URL string `json:"url"`
+	// Subject is the subject the schema is registered under.
+	Subject string `json:"subject"`
+	// Version is the version of the schema. Defaults to 'latest'.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// BasicAuth references the user name and password used for HTTP basic authentication.
+type BasicAuth struct {
+	Username corev1.SecretKeySelector `json:"username"`
+	Password corev1.SecretKeySelector `json:"password"`
+}
+
+// OIDCClientCredentials configures the OAuth 2.0 client credentials flow.
+type OIDCClientCredentials struct {
+	// TokenURL is the token endpoint of the identity provider.
+	TokenURL string `json:"tokenURL"`
+	// ClientID references the client identifier.
+	ClientID corev1.SecretKeySelector `json:"clientID"`
+	// ClientSecret references the client secret.
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+	// Scopes are the (optional) scopes requested with the token.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // Channel is a skeleton type wrapping Subscribable in the manner we expect resource writers
@@ -81,6 +205,13 @@ func (c *Channel) Populate() {
 			},
 			SubscriberURI: "call1",
 			ReplyURI:      "sink2",
+			Filter:        "type = 'dev.knative.foo'",
+			Auth: &SubscriberAuth{
+				BearerToken: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "subscriber-token"},
+					Key:                  "token",
+				},
+			},
 		}, {
 			Ref: &corev1.ObjectReference{
 				APIVersion: "eventing.knative.dev/v1alpha1",
@@ -59,6 +59,13 @@ func TestPopulate(t *testing.T) {
 					},
 					SubscriberURI: "call1",
 					ReplyURI:      "sink2",
+					Filter:        "type = 'dev.knative.foo'",
+					Auth: &SubscriberAuth{
+						BearerToken: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "subscriber-token"},
+							Key:                  "token",
+						},
+					},
 				}, {
 					Ref: &corev1.ObjectReference{
 						APIVersion: "eventing.knative.dev/v1alpha1",
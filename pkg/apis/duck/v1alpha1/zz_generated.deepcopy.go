@@ -25,6 +25,24 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Channel) DeepCopyInto(out *Channel) {
 	*out = *in
@@ -114,6 +132,24 @@ func (in *ChannelSubscriberSpec) DeepCopyInto(out *ChannelSubscriberSpec) {
 			**out = **in
 		}
 	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSchema)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberAuth)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
@@ -127,6 +163,61 @@ func (in *ChannelSubscriberSpec) DeepCopy() *ChannelSubscriberSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificate) DeepCopyInto(out *ClientCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificate.
+func (in *ClientCertificate) DeepCopy() *ClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCClientCredentials) DeepCopyInto(out *OIDCClientCredentials) {
+	*out = *in
+	in.ClientID.DeepCopyInto(&out.ClientID)
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCClientCredentials.
+func (in *OIDCClientCredentials) DeepCopy() *OIDCClientCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCClientCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryRef) DeepCopyInto(out *SchemaRegistryRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryRef.
+func (in *SchemaRegistryRef) DeepCopy() *SchemaRegistryRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscribable) DeepCopyInto(out *Subscribable) {
 	*out = *in
@@ -149,3 +240,89 @@ func (in *Subscribable) DeepCopy() *Subscribable {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriberAuth) DeepCopyInto(out *SubscriberAuth) {
+	*out = *in
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.SecretKeySelector)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Basic != nil {
+		in, out := &in.Basic, &out.Basic
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(BasicAuth)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(OIDCClientCredentials)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ClientCertificate)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberAuth.
+func (in *SubscriberAuth) DeepCopy() *SubscriberAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriberAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriberSchema) DeepCopyInto(out *SubscriberSchema) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ConfigMapKeySelector)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SchemaRegistryRef)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberSchema.
+func (in *SubscriberSchema) DeepCopy() *SubscriberSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriberSchema)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBrokerDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+	}{{
+		name: "no annotations",
+		want: map[string]string{BrokerClassAnnotationKey: ChannelBasedBrokerClass},
+	}, {
+		name:        "other annotations",
+		annotations: map[string]string{"foo": "bar"},
+		want:        map[string]string{"foo": "bar", BrokerClassAnnotationKey: ChannelBasedBrokerClass},
+	}, {
+		name:        "class",
+		annotations: map[string]string{BrokerClassAnnotationKey: "KafkaBroker"},
+		want:        map[string]string{BrokerClassAnnotationKey: "KafkaBroker"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			b.SetDefaults()
+			if diff := cmp.Diff(test.want, b.Annotations); diff != "" {
+				t.Errorf("unexpected annotations (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Broker is an Addressable entry point for events. Events sent to the Broker's ingress are
+// written to a Channel, and a filter reads them back from the Channel and routes them to
+// interested subscribers.
+type Broker struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Broker.
+	Spec BrokerSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Broker. This data may be out of
+	// date.
+	// +optional
+	Status BrokerStatus `json:"status,omitempty"`
+}
+
+const (
+	// BrokerClassAnnotationKey is the annotation that selects the implementation of a Broker. Each
+	// class of Broker is reconciled by its own controller.
+	BrokerClassAnnotationKey = "eventing.knative.dev/broker.class"
+
+	// ChannelBasedBrokerClass is the class of the Brokers that hold their events in a Channel. It
+	// is the default class.
+	ChannelBasedBrokerClass = "ChannelBasedBroker"
+
+	// MultiTenantChannelBasedBrokerClass is the class of the channel-based Brokers whose events
+	// are routed by an ingress and a filter shared by all the Brokers of the class, which run in
+	// the system namespace.
+	MultiTenantChannelBasedBrokerClass = "MTChannelBasedBroker"
+)
+
+// Check that Broker can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Broker)(nil)
+var _ apis.Defaultable = (*Broker)(nil)
+var _ apis.Immutable = (*Broker)(nil)
+var _ runtime.Object = (*Broker)(nil)
+var _ webhook.GenericCRD = (*Broker)(nil)
+
+// BrokerSpec specifies the Channel backing a Broker, and the default delivery of its Triggers.
+type BrokerSpec struct {
+	// ChannelTemplate is the spec of the Channel the Broker creates to hold its events. If it is
+	// not specified, the Channel is created with the default template of the namespace.
+	// +optional
+	ChannelTemplate *ChannelSpec `json:"channelTemplate,omitempty"`
+
+	// Delivery specifies how events are delivered to the subscribers of the Broker's Triggers
+	// that fail to accept them. It applies to the Triggers that do not specify their own.
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+}
+
+var brokerCondSet = duckv1alpha1.NewLivingConditionSet(BrokerConditionChannel, BrokerConditionIngress, BrokerConditionFilter, BrokerConditionAddressable)
+
+// BrokerStatus represents the current state of a Broker.
+type BrokerStatus struct {
+	// ObservedGeneration is the most recent generation observed for this Broker.
+	// It corresponds to the Broker's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Broker is Addressable. It exposes the endpoint of its ingress as a
+	// fully-qualified DNS name.
+	//
+	// It generally has the form {broker}-broker-ingress.{namespace}.svc.cluster.local
+	Address duckv1alpha1.Addressable `json:"address,omitempty"`
+
+	// Represents the latest available observations of a broker's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// BrokerConditionReady has status True when the Broker is ready to
+	// accept and route events.
+	BrokerConditionReady = duckv1alpha1.ConditionReady
+
+	// BrokerConditionChannel has status True when the Channel holding the
+	// Broker's events is ready.
+	BrokerConditionChannel duckv1alpha1.ConditionType = "ChannelReady"
+
+	// BrokerConditionIngress has status True when the ingress Deployment and
+	// Service have been created.
+	BrokerConditionIngress duckv1alpha1.ConditionType = "IngressReady"
+
+	// BrokerConditionFilter has status True when the filter Deployment and
+	// Service have been created.
+	BrokerConditionFilter duckv1alpha1.ConditionType = "FilterReady"
+
+	// BrokerConditionAddressable has status true when this Broker meets
+	// the Addressable contract and has a non-empty hostname.
+	BrokerConditionAddressable duckv1alpha1.ConditionType = "Addressable"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (bs *BrokerStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return brokerCondSet.Manage(bs).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (bs *BrokerStatus) IsReady() bool {
+	return brokerCondSet.Manage(bs).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (bs *BrokerStatus) InitializeConditions() {
+	brokerCondSet.Manage(bs).InitializeConditions()
+}
+
+// MarkChannelReady sets BrokerConditionChannel condition to True state.
+func (bs *BrokerStatus) MarkChannelReady() {
+	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionChannel)
+}
+
+// MarkChannelNotReady sets BrokerConditionChannel condition to False state.
+func (bs *BrokerStatus) MarkChannelNotReady(reason, messageFormat string, messageA ...interface{}) {
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionChannel, reason, messageFormat, messageA...)
+}
+
+// MarkIngressReady sets BrokerConditionIngress condition to True state.
+func (bs *BrokerStatus) MarkIngressReady() {
+	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionIngress)
+}
+
+// MarkIngressNotReady sets BrokerConditionIngress condition to False state.
+func (bs *BrokerStatus) MarkIngressNotReady(reason, messageFormat string, messageA ...interface{}) {
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionIngress, reason, messageFormat, messageA...)
+}
+
+// MarkFilterReady sets BrokerConditionFilter condition to True state.
+func (bs *BrokerStatus) MarkFilterReady() {
+	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionFilter)
+}
+
+// MarkFilterNotReady sets BrokerConditionFilter condition to False state.
+func (bs *BrokerStatus) MarkFilterNotReady(reason, messageFormat string, messageA ...interface{}) {
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionFilter, reason, messageFormat, messageA...)
+}
+
+// SetAddress makes this Broker addressable by setting the hostname. It also
+// sets the BrokerConditionAddressable to true.
+func (bs *BrokerStatus) SetAddress(hostname string) {
+	bs.Address.Hostname = hostname
+	if hostname != "" {
+		brokerCondSet.Manage(bs).MarkTrue(BrokerConditionAddressable)
+	} else {
+		brokerCondSet.Manage(bs).MarkFalse(BrokerConditionAddressable, "emptyHostname", "hostname is the empty string")
+	}
+}
+
+// Class returns the class of the Broker, which is ChannelBasedBrokerClass if it is not annotated
+// with one.
+func (b *Broker) Class() string {
+	if class := b.Annotations[BrokerClassAnnotationKey]; class != "" {
+		return class
+	}
+	return ChannelBasedBrokerClass
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BrokerList is a collection of Brokers.
+type BrokerList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Broker `json:"items"`
+}
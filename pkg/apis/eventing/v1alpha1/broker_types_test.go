@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBrokerInitializeConditions(t *testing.T) {
+	bs := &BrokerStatus{}
+	bs.InitializeConditions()
+	want := &BrokerStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   BrokerConditionAddressable,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   BrokerConditionChannel,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   BrokerConditionFilter,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   BrokerConditionIngress,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   BrokerConditionReady,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, bs, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestBrokerIsReady(t *testing.T) {
+	tests := []struct {
+		name        string
+		markChannel bool
+		markIngress bool
+		markFilter  bool
+		address     string
+		wantReady   bool
+	}{{
+		name:        "all happy",
+		markChannel: true,
+		markIngress: true,
+		markFilter:  true,
+		address:     "hostname",
+		wantReady:   true,
+	}, {
+		name:        "channel sad",
+		markChannel: false,
+		markIngress: true,
+		markFilter:  true,
+		address:     "hostname",
+		wantReady:   false,
+	}, {
+		name:        "ingress sad",
+		markChannel: true,
+		markIngress: false,
+		markFilter:  true,
+		address:     "hostname",
+		wantReady:   false,
+	}, {
+		name:        "filter sad",
+		markChannel: true,
+		markIngress: true,
+		markFilter:  false,
+		address:     "hostname",
+		wantReady:   false,
+	}, {
+		name:        "no address",
+		markChannel: true,
+		markIngress: true,
+		markFilter:  true,
+		wantReady:   false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bs := &BrokerStatus{}
+			bs.InitializeConditions()
+			if test.markChannel {
+				bs.MarkChannelReady()
+			} else {
+				bs.MarkChannelNotReady("NotReady", "testing")
+			}
+			if test.markIngress {
+				bs.MarkIngressReady()
+			} else {
+				bs.MarkIngressNotReady("NotReady", "testing")
+			}
+			if test.markFilter {
+				bs.MarkFilterReady()
+			} else {
+				bs.MarkFilterNotReady("NotReady", "testing")
+			}
+			bs.SetAddress(test.address)
+			if got := bs.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
+
+func TestBrokerClass(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{{
+		name: "no annotation",
+		want: ChannelBasedBrokerClass,
+	}, {
+		name:        "empty annotation",
+		annotations: map[string]string{BrokerClassAnnotationKey: ""},
+		want:        ChannelBasedBrokerClass,
+	}, {
+		name:        "annotation",
+		annotations: map[string]string{BrokerClassAnnotationKey: "KafkaBroker"},
+		want:        "KafkaBroker",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := b.Class(); got != test.want {
+				t.Errorf("unexpected class: want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+)
+
+func (b *Broker) Validate() *apis.FieldError {
+	return b.Spec.Validate().ViaField("spec")
+}
+
+func (bs *BrokerSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ct := bs.ChannelTemplate; ct != nil {
+		// The subscribers of the Broker's Channel are managed by the Broker.
+		if ct.Subscribable != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("subscribable").ViaField("channelTemplate"))
+		}
+	}
+	if bs.Delivery != nil {
+		if fe := bs.Delivery.Validate(); fe != nil {
+			errs = errs.Also(fe.ViaField("delivery"))
+		}
+	}
+	return errs
+}
+
+func (current *Broker) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*Broker)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a Broker"}
+	}
+	if original.Class() != current.Class() {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"metadata.annotations." + BrokerClassAnnotationKey},
+		}
+	}
+	if diff := cmp.Diff(original.Spec.ChannelTemplate, current.Spec.ChannelTemplate); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.channelTemplate"},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBrokerValidation(t *testing.T) {
+	tests := []CRDTest{{
+		name: "empty",
+		cr:   &Broker{},
+		want: nil,
+	}, {
+		name: "channel template",
+		cr: &Broker{
+			Spec: BrokerSpec{
+				ChannelTemplate: &ChannelSpec{
+					Provisioner: &corev1.ObjectReference{
+						Name: "foo",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "channel template with subscribers",
+		cr: &Broker{
+			Spec: BrokerSpec{
+				ChannelTemplate: &ChannelSpec{
+					Subscribable: &eventingduck.Subscribable{
+						Subscribers: []eventingduck.ChannelSubscriberSpec{{
+							SubscriberURI: "subscriberendpoint",
+						}},
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.channelTemplate.subscribable"),
+	}, {
+		name: "invalid delivery",
+		cr: &Broker{
+			Spec: BrokerSpec{
+				Delivery: &DeliverySpec{
+					Retry: -1,
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("-1", "spec.delivery.retry")
+			fe.Details = "must be between 0 and 20"
+			return fe
+		}(),
+	}}
+
+	doValidateTest(t, tests)
+}
+
+func TestBrokerImmutableFields(t *testing.T) {
+	template := &ChannelSpec{
+		Provisioner: &corev1.ObjectReference{
+			Name: "foo",
+		},
+	}
+	tests := []struct {
+		name string
+		new  apis.Immutable
+		old  apis.Immutable
+		want *apis.FieldError
+	}{{
+		name: "good (new)",
+		new:  &Broker{},
+		old:  nil,
+		want: nil,
+	}, {
+		name: "good (no change)",
+		new:  &Broker{Spec: BrokerSpec{ChannelTemplate: template}},
+		old:  &Broker{Spec: BrokerSpec{ChannelTemplate: template.DeepCopy()}},
+		want: nil,
+	}, {
+		name: "bad (channel template change)",
+		new: &Broker{
+			Spec: BrokerSpec{
+				ChannelTemplate: &ChannelSpec{
+					Provisioner: &corev1.ObjectReference{
+						Name: "bar",
+					},
+				},
+			},
+		},
+		old: &Broker{Spec: BrokerSpec{ChannelTemplate: template}},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.channelTemplate"},
+		},
+	}, {
+		name: "good (default class)",
+		new: &Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{BrokerClassAnnotationKey: ChannelBasedBrokerClass},
+		}},
+		old:  &Broker{},
+		want: nil,
+	}, {
+		name: "bad (class change)",
+		new: &Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{BrokerClassAnnotationKey: "KafkaBroker"},
+		}},
+		old: &Broker{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{BrokerClassAnnotationKey: ChannelBasedBrokerClass},
+		}},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"metadata.annotations.eventing.knative.dev/broker.class"},
+		},
+	}, {
+		name: "bad (type)",
+		new:  &Broker{},
+		old:  &Channel{},
+		want: &apis.FieldError{
+			Message: "The provided resource was not a Broker",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckImmutableFields (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -22,10 +22,31 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ChannelArgumentsValidator validates the Arguments of Channels against the arguments accepted by
+// their provisioner.
+type ChannelArgumentsValidator interface {
+	// ValidateArguments returns the errors of the arguments of the given channel, relative to the
+	// arguments field. It does not modify the given channel.
+	ValidateArguments(c *Channel) *apis.FieldError
+}
+
+var (
+	// ChannelArgumentsValidatorSingleton is the global singleton used to validate the arguments
+	// of Channels.
+	ChannelArgumentsValidatorSingleton ChannelArgumentsValidator
 )
 
 func (c *Channel) Validate() *apis.FieldError {
-	return c.Spec.Validate().ViaField("spec")
+	errs := c.Spec.Validate()
+	// The singleton may not have been set, if so the arguments are validated by the provisioner
+	// when it reconciles the Channel.
+	if cv := ChannelArgumentsValidatorSingleton; cv != nil && c.Spec.Provisioner != nil {
+		errs = errs.Also(cv.ValidateArguments(c.DeepCopy()).ViaField("arguments"))
+	}
+	return errs.ViaField("spec")
 }
 
 func (cs *ChannelSpec) Validate() *apis.FieldError {
@@ -60,7 +81,22 @@ func (current *Channel) CheckImmutableFields(og apis.Immutable) *apis.FieldError
 		return &apis.FieldError{
 			Message: "Immutable fields changed",
 			Paths:   []string{"spec.provisioner"},
+			Details: fmt.Sprintf("The provisioner of a Channel cannot be changed from %s to %s, the events "+
+				"and subscribers of the Channel cannot be moved to another backend. To migrate, create a "+
+				"ChannelMigration moving the subscribers of this Channel to a Channel of the new provisioner.",
+				provisionerName(original.Spec.Provisioner), provisionerName(current.Spec.Provisioner)),
 		}
 	}
 	return nil
 }
+
+// provisionerName formats a provisioner reference for error messages.
+func provisionerName(ref *corev1.ObjectReference) string {
+	if ref == nil {
+		return "none"
+	}
+	if ref.Kind == "" {
+		return fmt.Sprintf("%q", ref.Name)
+	}
+	return fmt.Sprintf("%s %q", ref.Kind, ref.Name)
+}
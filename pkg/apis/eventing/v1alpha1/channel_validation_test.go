@@ -106,6 +106,52 @@ func TestChannelValidation(t *testing.T) {
 	doValidateTest(t, tests)
 }
 
+// argumentsValidator rejects the arguments of the channels of the provisioner "strict".
+type argumentsValidator struct{}
+
+func (argumentsValidator) ValidateArguments(c *Channel) *apis.FieldError {
+	if c.Spec.Provisioner.Name == "strict" && c.Spec.Arguments != nil {
+		return &apis.FieldError{Message: "invalid arguments", Paths: []string{apis.CurrentField}}
+	}
+	return nil
+}
+
+func TestChannelArgumentsValidation(t *testing.T) {
+	ChannelArgumentsValidatorSingleton = argumentsValidator{}
+	defer func() {
+		ChannelArgumentsValidatorSingleton = nil
+	}()
+
+	tests := []CRDTest{{
+		name: "valid arguments",
+		cr: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &corev1.ObjectReference{Name: "strict"},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid arguments",
+		cr: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &corev1.ObjectReference{Name: "strict"},
+				Arguments:   &runtime.RawExtension{Raw: []byte(`{"foo":"bar"}`)},
+			},
+		},
+		want: &apis.FieldError{Message: "invalid arguments", Paths: []string{"spec.arguments"}},
+	}, {
+		name: "no provisioner",
+		cr: &Channel{
+			Spec: ChannelSpec{
+				Arguments: &runtime.RawExtension{Raw: []byte(`{"foo":"bar"}`)},
+			},
+		},
+		want: apis.ErrMissingField("spec.provisioner"),
+	}}
+
+	doValidateTest(t, tests)
+}
+
 func TestChannelImmutableFields(t *testing.T) {
 	tests := []struct {
 		name string
@@ -195,6 +241,25 @@ func TestChannelImmutableFields(t *testing.T) {
 		want: &apis.FieldError{
 			Message: "Immutable fields changed",
 			Paths:   []string{"spec.provisioner"},
+			Details: `The provisioner of a Channel cannot be changed from "bar" to "foo", the events and subscribers of the Channel cannot be moved to another backend. To migrate, create a ChannelMigration moving the subscribers of this Channel to a Channel of the new provisioner.`,
+		},
+	}, {
+		name: "bad (provisioner removed)",
+		new: &Channel{
+			Spec: ChannelSpec{},
+		},
+		old: &Channel{
+			Spec: ChannelSpec{
+				Provisioner: &corev1.ObjectReference{
+					Kind: "ClusterChannelProvisioner",
+					Name: "bar",
+				},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.provisioner"},
+			Details: `The provisioner of a Channel cannot be changed from ClusterChannelProvisioner "bar" to none, the events and subscribers of the Channel cannot be moved to another backend. To migrate, create a ChannelMigration moving the subscribers of this Channel to a Channel of the new provisioner.`,
 		},
 	}}
 
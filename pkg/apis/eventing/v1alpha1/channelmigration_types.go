@@ -0,0 +1,199 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ChannelMigration moves the subscribers of a Channel to a Channel of another provisioner without
+// losing events. It creates the target Channel, mirrors the events of the migrated Channel to it,
+// moves the Subscriptions of the migrated Channel to the target Channel and, once finalized,
+// deletes the migrated Channel.
+type ChannelMigration struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the ChannelMigration.
+	Spec ChannelMigrationSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the ChannelMigration. This data may be out of
+	// date.
+	// +optional
+	Status ChannelMigrationStatus `json:"status,omitempty"`
+}
+
+// Check that ChannelMigration can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*ChannelMigration)(nil)
+var _ apis.Defaultable = (*ChannelMigration)(nil)
+var _ apis.Immutable = (*ChannelMigration)(nil)
+var _ runtime.Object = (*ChannelMigration)(nil)
+var _ webhook.GenericCRD = (*ChannelMigration)(nil)
+
+// ChannelMigrationSpec specifies the Channel being migrated and the provisioner it is migrated to.
+type ChannelMigrationSpec struct {
+	// Channel is the name of the Channel being migrated, in the namespace of the
+	// ChannelMigration.
+	Channel string `json:"channel"`
+
+	// Provisioner is the provisioner of the target Channel. The target Channel has the name of
+	// the ChannelMigration.
+	Provisioner *corev1.ObjectReference `json:"provisioner"`
+
+	// Arguments is the arguments of the target Channel.
+	// +optional
+	Arguments *runtime.RawExtension `json:"arguments,omitempty"`
+
+	// Finalize deletes the migrated Channel once all its Subscriptions are moved. Producers
+	// must send their events to the target Channel before the migration is finalized.
+	// +optional
+	Finalize bool `json:"finalize,omitempty"`
+}
+
+var channelMigrationCondSet = duckv1alpha1.NewLivingConditionSet(ChannelMigrationConditionTargetChannelReady, ChannelMigrationConditionMirroring, ChannelMigrationConditionSubscriptionsMoved)
+
+// ChannelMigrationStatus represents the current state of a ChannelMigration.
+type ChannelMigrationStatus struct {
+	// ObservedGeneration is the most recent generation observed for this ChannelMigration.
+	// It corresponds to the ChannelMigration's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ChannelMigration is Addressable. It exposes the address of the target Channel, which
+	// producers send their events to instead of the migrated Channel.
+	Address duckv1alpha1.Addressable `json:"address,omitempty"`
+
+	// MovedSubscriptions are the Subscriptions of the migrated Channel that were moved to the
+	// target Channel.
+	// +optional
+	MovedSubscriptions []MovedSubscription `json:"movedSubscriptions,omitempty"`
+
+	// Represents the latest available observations of a channel migration's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// MovedSubscription is a Subscription of the migrated Channel and its copy on the target Channel.
+type MovedSubscription struct {
+	// From is the name of the Subscription of the migrated Channel, which is deleted.
+	From string `json:"from"`
+
+	// To is the name of the Subscription of the target Channel replacing it.
+	To string `json:"to"`
+}
+
+const (
+	// ChannelMigrationConditionReady has status True when all the subscribers of
+	// the migrated Channel receive their events from the target Channel.
+	ChannelMigrationConditionReady = duckv1alpha1.ConditionReady
+
+	// ChannelMigrationConditionTargetChannelReady has status True when the
+	// target Channel is ready and has an address.
+	ChannelMigrationConditionTargetChannelReady duckv1alpha1.ConditionType = "TargetChannelReady"
+
+	// ChannelMigrationConditionMirroring has status True when the events sent
+	// to the migrated Channel are delivered to the target Channel.
+	ChannelMigrationConditionMirroring duckv1alpha1.ConditionType = "Mirroring"
+
+	// ChannelMigrationConditionSubscriptionsMoved has status True when all
+	// the Subscriptions of the migrated Channel are moved to the target Channel.
+	ChannelMigrationConditionSubscriptionsMoved duckv1alpha1.ConditionType = "SubscriptionsMoved"
+
+	// ChannelMigrationConditionFinalized has status True when the migrated
+	// Channel is deleted. It is not part of the readiness of the ChannelMigration.
+	ChannelMigrationConditionFinalized duckv1alpha1.ConditionType = "Finalized"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (cms *ChannelMigrationStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return channelMigrationCondSet.Manage(cms).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (cms *ChannelMigrationStatus) IsReady() bool {
+	return channelMigrationCondSet.Manage(cms).IsHappy()
+}
+
+// IsFinalized returns true if the migrated Channel is deleted.
+func (cms *ChannelMigrationStatus) IsFinalized() bool {
+	return cms.GetCondition(ChannelMigrationConditionFinalized).IsTrue()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (cms *ChannelMigrationStatus) InitializeConditions() {
+	channelMigrationCondSet.Manage(cms).InitializeConditions()
+}
+
+// MarkTargetChannelReady sets ChannelMigrationConditionTargetChannelReady condition to True state
+// and exposes the address of the target Channel.
+func (cms *ChannelMigrationStatus) MarkTargetChannelReady(hostname string) {
+	cms.Address.Hostname = hostname
+	channelMigrationCondSet.Manage(cms).MarkTrue(ChannelMigrationConditionTargetChannelReady)
+}
+
+// MarkTargetChannelNotReady sets ChannelMigrationConditionTargetChannelReady condition to False state.
+func (cms *ChannelMigrationStatus) MarkTargetChannelNotReady(reason, messageFormat string, messageA ...interface{}) {
+	channelMigrationCondSet.Manage(cms).MarkFalse(ChannelMigrationConditionTargetChannelReady, reason, messageFormat, messageA...)
+}
+
+// MarkMirroring sets ChannelMigrationConditionMirroring condition to True state.
+func (cms *ChannelMigrationStatus) MarkMirroring() {
+	channelMigrationCondSet.Manage(cms).MarkTrue(ChannelMigrationConditionMirroring)
+}
+
+// MarkNotMirroring sets ChannelMigrationConditionMirroring condition to False state.
+func (cms *ChannelMigrationStatus) MarkNotMirroring(reason, messageFormat string, messageA ...interface{}) {
+	channelMigrationCondSet.Manage(cms).MarkFalse(ChannelMigrationConditionMirroring, reason, messageFormat, messageA...)
+}
+
+// MarkSubscriptionsMoved sets ChannelMigrationConditionSubscriptionsMoved condition to True state.
+func (cms *ChannelMigrationStatus) MarkSubscriptionsMoved() {
+	channelMigrationCondSet.Manage(cms).MarkTrue(ChannelMigrationConditionSubscriptionsMoved)
+}
+
+// MarkSubscriptionsNotMoved sets ChannelMigrationConditionSubscriptionsMoved condition to False state.
+func (cms *ChannelMigrationStatus) MarkSubscriptionsNotMoved(reason, messageFormat string, messageA ...interface{}) {
+	channelMigrationCondSet.Manage(cms).MarkFalse(ChannelMigrationConditionSubscriptionsMoved, reason, messageFormat, messageA...)
+}
+
+// MarkFinalized sets ChannelMigrationConditionFinalized condition to True state.
+func (cms *ChannelMigrationStatus) MarkFinalized() {
+	channelMigrationCondSet.Manage(cms).MarkTrue(ChannelMigrationConditionFinalized)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ChannelMigrationList is a collection of ChannelMigrations.
+type ChannelMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelMigration `json:"items"`
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestChannelMigrationInitializeConditions(t *testing.T) {
+	cms := &ChannelMigrationStatus{}
+	cms.InitializeConditions()
+	want := &ChannelMigrationStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   ChannelMigrationConditionMirroring,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ChannelMigrationConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ChannelMigrationConditionSubscriptionsMoved,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ChannelMigrationConditionTargetChannelReady,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, cms, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestChannelMigrationIsReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		markTarget bool
+		markMirror bool
+		markMoved  bool
+		wantReady  bool
+	}{{
+		name:       "all happy",
+		markTarget: true,
+		markMirror: true,
+		markMoved:  true,
+		wantReady:  true,
+	}, {
+		name:       "target sad",
+		markMirror: true,
+		markMoved:  true,
+	}, {
+		name:       "mirror sad",
+		markTarget: true,
+		markMoved:  true,
+	}, {
+		name:       "subscriptions not moved",
+		markTarget: true,
+		markMirror: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cms := &ChannelMigrationStatus{}
+			cms.InitializeConditions()
+			if test.markTarget {
+				cms.MarkTargetChannelReady("target.ns.svc.cluster.local")
+			} else {
+				cms.MarkTargetChannelNotReady("NotReady", "testing")
+			}
+			if test.markMirror {
+				cms.MarkMirroring()
+			} else {
+				cms.MarkNotMirroring("NotReady", "testing")
+			}
+			if test.markMoved {
+				cms.MarkSubscriptionsMoved()
+			} else {
+				cms.MarkSubscriptionsNotMoved("NotMoved", "testing")
+			}
+			if got := cms.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
+
+func TestChannelMigrationFinalized(t *testing.T) {
+	cms := &ChannelMigrationStatus{}
+	cms.InitializeConditions()
+	if cms.IsFinalized() {
+		t.Errorf("Expected a new ChannelMigration not to be finalized")
+	}
+	cms.MarkTargetChannelReady("target.ns.svc.cluster.local")
+	cms.MarkMirroring()
+	cms.MarkSubscriptionsMoved()
+	cms.MarkFinalized()
+	if !cms.IsFinalized() || !cms.IsReady() {
+		t.Errorf("Expected the ChannelMigration to be finalized and ready, got %v", cms.Conditions)
+	}
+}
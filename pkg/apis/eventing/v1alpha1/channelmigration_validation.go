@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knative/pkg/apis"
+)
+
+func (cm *ChannelMigration) Validate() *apis.FieldError {
+	errs := cm.Spec.Validate().ViaField("spec")
+	// The target Channel has the name of the ChannelMigration.
+	if cm.Spec.Channel != "" && cm.Spec.Channel == cm.Name {
+		fe := apis.ErrInvalidValue(cm.Spec.Channel, "spec.channel")
+		fe.Details = "the migrated Channel must not have the name of the ChannelMigration, which is the name of the target Channel"
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+func (cms *ChannelMigrationSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if cms.Channel == "" {
+		errs = errs.Also(apis.ErrMissingField("channel"))
+	}
+	if cms.Provisioner == nil || cms.Provisioner.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("provisioner"))
+	}
+	return errs
+}
+
+func (current *ChannelMigration) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*ChannelMigration)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a ChannelMigration"}
+	}
+	// Only finalize can be changed, the target Channel is not updated.
+	ignoreFinalize := cmpopts.IgnoreFields(ChannelMigrationSpec{}, "Finalize")
+	if diff := cmp.Diff(original.Spec, current.Spec, ignoreFinalize); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec"},
+			Details: diff,
+		}
+	}
+	return nil
+}
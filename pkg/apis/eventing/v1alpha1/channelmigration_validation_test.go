@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestChannelMigrationValidation(t *testing.T) {
+	provisioner := &corev1.ObjectReference{
+		APIVersion: SchemeGroupVersion.String(),
+		Kind:       "ClusterChannelProvisioner",
+		Name:       "kafka",
+	}
+	tests := []CRDTest{{
+		name: "valid",
+		cr: &ChannelMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-kafka"},
+			Spec: ChannelMigrationSpec{
+				Channel:     "orders",
+				Provisioner: provisioner,
+			},
+		},
+		want: nil,
+	}, {
+		name: "empty",
+		cr:   &ChannelMigration{ObjectMeta: metav1.ObjectMeta{Name: "orders-kafka"}},
+		want: apis.ErrMissingField("spec.channel").Also(apis.ErrMissingField("spec.provisioner")),
+	}, {
+		name: "provisioner without name",
+		cr: &ChannelMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-kafka"},
+			Spec: ChannelMigrationSpec{
+				Channel:     "orders",
+				Provisioner: &corev1.ObjectReference{Kind: "ClusterChannelProvisioner"},
+			},
+		},
+		want: apis.ErrMissingField("spec.provisioner"),
+	}, {
+		name: "target is the migrated Channel",
+		cr: &ChannelMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders"},
+			Spec: ChannelMigrationSpec{
+				Channel:     "orders",
+				Provisioner: provisioner,
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("orders", "spec.channel")
+			fe.Details = "the migrated Channel must not have the name of the ChannelMigration, which is the name of the target Channel"
+			return fe
+		}(),
+	}}
+
+	doValidateTest(t, tests)
+}
+
+func TestChannelMigrationImmutableFields(t *testing.T) {
+	spec := ChannelMigrationSpec{
+		Channel: "orders",
+		Provisioner: &corev1.ObjectReference{
+			Name: "kafka",
+		},
+	}
+	finalized := spec
+	finalized.Finalize = true
+	moved := spec
+	moved.Channel = "payments"
+
+	tests := []struct {
+		name      string
+		new       apis.Immutable
+		old       apis.Immutable
+		wantError bool
+	}{{
+		name: "good (new)",
+		new:  &ChannelMigration{Spec: spec},
+		old:  nil,
+	}, {
+		name: "good (finalize)",
+		new:  &ChannelMigration{Spec: finalized},
+		old:  &ChannelMigration{Spec: spec},
+	}, {
+		name:      "bad (channel change)",
+		new:       &ChannelMigration{Spec: moved},
+		old:       &ChannelMigration{Spec: spec},
+		wantError: true,
+	}, {
+		name:      "bad (type)",
+		new:       &ChannelMigration{},
+		old:       &Channel{},
+		wantError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if (got != nil) != test.wantError {
+				t.Errorf("CheckImmutableFields() = %v, wanted error: %v", got, test.wantError)
+			}
+		})
+	}
+}
@@ -75,6 +75,38 @@ type ClusterChannelProvisionerStatus struct {
 	// was last reconciled by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ArgumentsSchema is the JSON Schema of the arguments accepted by the provisioner. The
+	// arguments of Channels are validated against it when they are created or updated. Channels
+	// accept any arguments if it is not set.
+	// +optional
+	ArgumentsSchema *runtime.RawExtension `json:"argumentsSchema,omitempty"`
+
+	// Capabilities describes the guarantees of the Channels of the provisioner, so that users and
+	// webhooks can check that a provisioner is suitable before creating Channels.
+	// +optional
+	Capabilities *ClusterChannelProvisionerCapabilities `json:"capabilities,omitempty"`
+}
+
+// ClusterChannelProvisionerCapabilities describes the guarantees of the Channels of a provisioner.
+type ClusterChannelProvisionerCapabilities struct {
+	// OrderedDelivery is true if the events of a Channel are delivered to each subscriber in the
+	// order the Channel received them. Provisioners partitioning Channels only order the events
+	// of each partition.
+	OrderedDelivery bool `json:"orderedDelivery"`
+
+	// Persistence is true if the events are stored durably until they are delivered, so that they
+	// are not lost when the dispatcher restarts.
+	Persistence bool `json:"persistence"`
+
+	// MaxEventSize is the size in bytes of the largest event the Channels accept, without a claim
+	// check. The size of events is not limited if it is not set.
+	// +optional
+	MaxEventSize *int64 `json:"maxEventSize,omitempty"`
+
+	// Arguments are the names of the arguments the Channels support.
+	// +optional
+	Arguments []string `json:"arguments,omitempty"`
 }
 
 const (
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+// DeliverySpec specifies how events are delivered to a subscriber that fails to accept them.
+type DeliverySpec struct {
+	// DeadLetterSink is the addressable that receives the events that could not be delivered
+	// once all the retries are exhausted. If it is not specified, those events are dropped.
+	// +optional
+	DeadLetterSink *SubscriberSpec `json:"deadLetterSink,omitempty"`
+
+	// Retry is the number of times delivery is retried after the first attempt fails, at most
+	// MaxRetry. Defaults to 0.
+	// +optional
+	Retry int32 `json:"retry,omitempty"`
+
+	// BackoffPolicy is how the delay between retries grows. Defaults to exponential.
+	// +optional
+	BackoffPolicy BackoffPolicyType `json:"backoffPolicy,omitempty"`
+
+	// BackoffDelay is the delay before the first retry, as a duration string such as '500ms' or
+	// '2s'. Defaults to 1s.
+	// +optional
+	BackoffDelay string `json:"backoffDelay,omitempty"`
+
+	// PermanentStatusCodes are the HTTP status codes of the subscriber's responses that mean the
+	// event will never be accepted, such as 400 or 422. Deliveries failing with them are not
+	// retried, and the event is sent straight to the DeadLetterSink. Defaults to the codes the
+	// Broker filter is configured with, none unless set: every failure is retried.
+	// +optional
+	PermanentStatusCodes []int32 `json:"permanentStatusCodes,omitempty"`
+}
+
+// MaxRetry is the largest Retry. The Broker filter retries while it handles the event, so the
+// retries of a delivery must end in a bounded time.
+const MaxRetry = 20
+
+// BackoffPolicyType is how the delay between retries grows.
+type BackoffPolicyType string
+
+const (
+	// BackoffPolicyLinear waits backoffDelay times the number of the retry before each retry.
+	BackoffPolicyLinear BackoffPolicyType = "linear"
+
+	// BackoffPolicyExponential doubles the delay after each retry.
+	BackoffPolicyExponential BackoffPolicyType = "exponential"
+)
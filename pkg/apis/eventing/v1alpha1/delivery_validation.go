@@ -0,0 +1,93 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/knative/pkg/apis"
+)
+
+func (ds *DeliverySpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ds.DeadLetterSink != nil {
+		if fe := isValidBrokerSubscriberSpec(ds.DeadLetterSink); fe != nil {
+			errs = errs.Also(fe.ViaField("deadLetterSink"))
+		}
+	}
+
+	if ds.Retry < 0 || ds.Retry > MaxRetry {
+		fe := apis.ErrInvalidValue(fmt.Sprintf("%d", ds.Retry), "retry")
+		fe.Details = fmt.Sprintf("must be between 0 and %d", MaxRetry)
+		errs = errs.Also(fe)
+	}
+
+	switch ds.BackoffPolicy {
+	case "", BackoffPolicyLinear, BackoffPolicyExponential:
+	default:
+		fe := apis.ErrInvalidValue(string(ds.BackoffPolicy), "backoffPolicy")
+		fe.Details = fmt.Sprintf("must be %q or %q", BackoffPolicyLinear, BackoffPolicyExponential)
+		errs = errs.Also(fe)
+	}
+
+	if ds.BackoffDelay != "" {
+		if d, err := time.ParseDuration(ds.BackoffDelay); err != nil || d <= 0 {
+			fe := apis.ErrInvalidValue(ds.BackoffDelay, "backoffDelay")
+			fe.Details = "must be a positive duration, such as 500ms or 2s"
+			errs = errs.Also(fe)
+		}
+	}
+	for i, code := range ds.PermanentStatusCodes {
+		if code < 100 || code > 599 || (code >= 200 && code < 300) {
+			fe := apis.ErrInvalidValue(fmt.Sprintf("%d", code), fmt.Sprintf("permanentStatusCodes[%d]", i))
+			fe.Details = "must be an HTTP status code of a failure, outside of 2xx"
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
+// isValidManagedSubscriberSpec checks s, which is the subscriber of a Subscription created by a
+// controller for the resource it belongs to. Its auth is disallowed, as the admission of the
+// Subscriptions could then not review the access of the requester to the Secrets it references.
+func isValidManagedSubscriberSpec(s SubscriberSpec) *apis.FieldError {
+	errs := isValidSubscriberSpec(s)
+	if s.Auth != nil {
+		fe := apis.ErrDisallowedFields("auth")
+		fe.Details = "only the subscribers of Subscriptions may authenticate"
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+// isValidBrokerSubscriberSpec checks s, which the Broker filter delivers to.
+func isValidBrokerSubscriberSpec(s *SubscriberSpec) *apis.FieldError {
+	if isSubscriberSpecNilOrEmpty(s) {
+		return apis.ErrMissingField(apis.CurrentField)
+	}
+	errs := isValidSubscriberSpec(*s)
+	if s.Auth != nil {
+		fe := apis.ErrDisallowedFields("auth")
+		fe.Details = "the Broker filter does not authenticate to subscribers"
+		errs = errs.Also(fe)
+	}
+	if fe := isHTTPSubscriberProtocol(s.Protocol); fe != nil {
+		fe.Details = "the Broker filter delivers events with HTTP"
+		errs = errs.Also(fe)
+	}
+	return errs
+}
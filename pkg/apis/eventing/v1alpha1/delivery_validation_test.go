@@ -0,0 +1,155 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeliverySpecValidation(t *testing.T) {
+	dlq := "http://dlq.example.com/"
+	tests := []struct {
+		name string
+		ds   *DeliverySpec
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		ds:   &DeliverySpec{},
+		want: nil,
+	}, {
+		name: "valid",
+		ds: &DeliverySpec{
+			DeadLetterSink:       &SubscriberSpec{DNSName: &dlq},
+			Retry:                3,
+			BackoffPolicy:        BackoffPolicyLinear,
+			BackoffDelay:         "500ms",
+			PermanentStatusCodes: []int32{400, 422},
+		},
+		want: nil,
+	}, {
+		name: "empty dead-letter sink",
+		ds: &DeliverySpec{
+			DeadLetterSink: &SubscriberSpec{},
+		},
+		want: apis.ErrMissingField("deadLetterSink"),
+	}, {
+		name: "dead-letter sink with auth",
+		ds: &DeliverySpec{
+			DeadLetterSink: &SubscriberSpec{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "dlq",
+				},
+				Auth: &eventingduck.SubscriberAuth{
+					BearerToken: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "token"},
+						Key:                  "token",
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("deadLetterSink.auth")
+			fe.Details = "the Broker filter does not authenticate to subscribers"
+			return fe
+		}(),
+	}, {
+		name: "negative retry",
+		ds: &DeliverySpec{
+			Retry: -1,
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("-1", "retry")
+			fe.Details = "must be between 0 and 20"
+			return fe
+		}(),
+	}, {
+		name: "too many retries",
+		ds: &DeliverySpec{
+			Retry: MaxRetry + 1,
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("21", "retry")
+			fe.Details = "must be between 0 and 20"
+			return fe
+		}(),
+	}, {
+		name: "unknown backoff policy",
+		ds: &DeliverySpec{
+			BackoffPolicy: "random",
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("random", "backoffPolicy")
+			fe.Details = `must be "linear" or "exponential"`
+			return fe
+		}(),
+	}, {
+		name: "invalid backoff delay",
+		ds: &DeliverySpec{
+			BackoffDelay: "soon",
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("soon", "backoffDelay")
+			fe.Details = "must be a positive duration, such as 500ms or 2s"
+			return fe
+		}(),
+	}, {
+		name: "negative backoff delay",
+		ds: &DeliverySpec{
+			BackoffDelay: "-1s",
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("-1s", "backoffDelay")
+			fe.Details = "must be a positive duration, such as 500ms or 2s"
+			return fe
+		}(),
+	}, {
+		name: "successful permanent status code",
+		ds: &DeliverySpec{
+			PermanentStatusCodes: []int32{400, 202},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("202", "permanentStatusCodes[1]")
+			fe.Details = "must be an HTTP status code of a failure, outside of 2xx"
+			return fe
+		}(),
+	}, {
+		name: "invalid permanent status code",
+		ds: &DeliverySpec{
+			PermanentStatusCodes: []int32{4000},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("4000", "permanentStatusCodes[0]")
+			fe.Details = "must be an HTTP status code of a failure, outside of 2xx"
+			return fe
+		}(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.ds.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	"github.com/knative/pkg/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventType registers a type of event that can be sent to a Broker, along with the schema of its
+// data. EventTypes are served by the discovery endpoint, so that UIs and code generators can find
+// the events available in a namespace.
+type EventType struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the event type being registered.
+	Spec EventTypeSpec `json:"spec,omitempty"`
+}
+
+// Check that EventType can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*EventType)(nil)
+var _ apis.Defaultable = (*EventType)(nil)
+var _ apis.Immutable = (*EventType)(nil)
+var _ runtime.Object = (*EventType)(nil)
+var _ webhook.GenericCRD = (*EventType)(nil)
+
+// EventTypeSpec describes a type of event sent to a Broker.
+type EventTypeSpec struct {
+	// Type is the CloudEvents type of the events, such as 'dev.knative.kafka.event'.
+	Type string `json:"type"`
+
+	// Source is the CloudEvents source of the events. If it is not specified, the events may have
+	// any source.
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// Broker is the name of the Broker, in the EventType's namespace, that the events are sent
+	// to. Defaults to 'default'.
+	// +optional
+	Broker string `json:"broker,omitempty"`
+
+	// Schema references the JSON Schema that the data of the events conforms to.
+	// +optional
+	Schema *eventingduck.SubscriberSchema `json:"schema,omitempty"`
+
+	// Description is a human readable description of the events.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventTypeList is a collection of EventTypes.
+type EventTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventType `json:"items"`
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+)
+
+func (et *EventType) Validate() *apis.FieldError {
+	return et.Spec.Validate().ViaField("spec")
+}
+
+func (ets *EventTypeSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ets.Type == "" {
+		errs = errs.Also(apis.ErrMissingField("type"))
+	}
+	if ets.Broker == "" {
+		errs = errs.Also(apis.ErrMissingField("broker"))
+	}
+	if ets.Schema != nil {
+		if fe := isValidSubscriberSchema(*ets.Schema); fe != nil {
+			errs = errs.Also(fe.ViaField("schema"))
+		}
+	}
+	return errs
+}
+
+func (current *EventType) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*EventType)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not an EventType"}
+	}
+	if original.Spec.Broker != current.Spec.Broker {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.broker"},
+		}
+	}
+	return nil
+}
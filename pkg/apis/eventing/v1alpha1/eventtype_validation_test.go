@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEventTypeValidation(t *testing.T) {
+	tests := []CRDTest{{
+		name: "valid",
+		cr: &EventType{
+			Spec: EventTypeSpec{
+				Type:   "dev.knative.foo",
+				Source: "bar",
+				Broker: "default",
+				Schema: &eventingduck.SubscriberSchema{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+						Key:                  "foo.json",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "no schema",
+		cr: &EventType{
+			Spec: EventTypeSpec{
+				Type:   "dev.knative.foo",
+				Broker: "default",
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing type",
+		cr: &EventType{
+			Spec: EventTypeSpec{
+				Broker: "default",
+			},
+		},
+		want: apis.ErrMissingField("spec.type"),
+	}, {
+		name: "missing broker",
+		cr: &EventType{
+			Spec: EventTypeSpec{
+				Type: "dev.knative.foo",
+			},
+		},
+		want: apis.ErrMissingField("spec.broker"),
+	}, {
+		name: "empty schema",
+		cr: &EventType{
+			Spec: EventTypeSpec{
+				Type:   "dev.knative.foo",
+				Broker: "default",
+				Schema: &eventingduck.SubscriberSchema{},
+			},
+		},
+		want: apis.ErrMissingOneOf("spec.schema.configMapKeyRef", "spec.schema.registry"),
+	}, {
+		name: "invalid registry",
+		cr: &EventType{
+			Spec: EventTypeSpec{
+				Type:   "dev.knative.foo",
+				Broker: "default",
+				Schema: &eventingduck.SubscriberSchema{
+					Registry: &eventingduck.SchemaRegistryRef{URL: "http://registry"},
+				},
+			},
+		},
+		want: apis.ErrMissingField("spec.schema.registry.subject"),
+	}}
+
+	doValidateTest(t, tests)
+}
+
+func TestEventTypeImmutableFields(t *testing.T) {
+	tests := []struct {
+		name string
+		new  apis.Immutable
+		old  apis.Immutable
+		want *apis.FieldError
+	}{{
+		name: "good (new)",
+		new:  &EventType{Spec: EventTypeSpec{Broker: "foo"}},
+		old:  nil,
+		want: nil,
+	}, {
+		name: "good (no broker change)",
+		new:  &EventType{Spec: EventTypeSpec{Broker: "foo", Description: "bar"}},
+		old:  &EventType{Spec: EventTypeSpec{Broker: "foo"}},
+		want: nil,
+	}, {
+		name: "bad (broker change)",
+		new:  &EventType{Spec: EventTypeSpec{Broker: "foo"}},
+		old:  &EventType{Spec: EventTypeSpec{Broker: "bar"}},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.broker"},
+		},
+	}, {
+		name: "bad (type)",
+		new:  &EventType{},
+		old:  &Channel{},
+		want: &apis.FieldError{
+			Message: "The provided resource was not an EventType",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckImmutableFields (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -26,16 +26,30 @@ func TestTypesImplements(t *testing.T) {
 		instance interface{}
 		iface    duck.Implementable
 	}{
+		// Broker
+		{instance: &Broker{}, iface: &duckv1alpha1.Conditions{}},
+		{instance: &Broker{}, iface: &duckv1alpha1.Addressable{}},
 		// Channel
 		{instance: &Channel{}, iface: &duckv1alpha1.Conditions{}},
 		{instance: &Channel{}, iface: &emptyGen},
 		{instance: &Channel{}, iface: &eventingduck.Subscribable{}},
 		{instance: &Channel{}, iface: &duckv1alpha1.Addressable{}},
+		// ChannelMigration
+		{instance: &ChannelMigration{}, iface: &duckv1alpha1.Conditions{}},
+		{instance: &ChannelMigration{}, iface: &duckv1alpha1.Addressable{}},
 		// ClusterChannelProvisioner
 		{instance: &ClusterChannelProvisioner{}, iface: &duckv1alpha1.Conditions{}},
+		// Parallel
+		{instance: &Parallel{}, iface: &duckv1alpha1.Conditions{}},
+		{instance: &Parallel{}, iface: &duckv1alpha1.Addressable{}},
+		// Sequence
+		{instance: &Sequence{}, iface: &duckv1alpha1.Conditions{}},
+		{instance: &Sequence{}, iface: &duckv1alpha1.Addressable{}},
 		// Subscription
 		{instance: &Subscription{}, iface: &duckv1alpha1.Conditions{}},
 		{instance: &Subscription{}, iface: &emptyGen},
+		// Trigger
+		{instance: &Trigger{}, iface: &duckv1alpha1.Conditions{}},
 	}
 	for _, tc := range testCases {
 		if err := duck.VerifyType(tc.instance, tc.iface); err != nil {
@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Parallel is an Addressable set of branches. Every event sent to the Parallel is delivered to
+// the subscriber of each branch whose filter it matches, and the replies of the subscribers are
+// sent to the reply of their branch, or else to the reply of the Parallel. The events are held in
+// a Channel created by the Parallel.
+type Parallel struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Parallel.
+	Spec ParallelSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Parallel. This data may be out of
+	// date.
+	// +optional
+	Status ParallelStatus `json:"status,omitempty"`
+}
+
+// Check that Parallel can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Parallel)(nil)
+var _ apis.Defaultable = (*Parallel)(nil)
+var _ apis.Immutable = (*Parallel)(nil)
+var _ runtime.Object = (*Parallel)(nil)
+var _ webhook.GenericCRD = (*Parallel)(nil)
+
+// ParallelSpec specifies the branches of a Parallel, the Channel that holds its events and where
+// the replies of the branches are sent.
+type ParallelSpec struct {
+	// Branches are the branches every event is delivered to.
+	Branches []ParallelBranch `json:"branches"`
+
+	// ChannelTemplate is the spec of the Channel the Parallel creates to hold its events. If it
+	// is not specified, the Channel is created with the default template of the namespace.
+	// +optional
+	ChannelTemplate *ChannelSpec `json:"channelTemplate,omitempty"`
+
+	// Reply specifies the Channel the replies of the branches that do not specify their own
+	// are sent to. If it is not specified, those replies are dropped.
+	// +optional
+	Reply *ReplyStrategy `json:"reply,omitempty"`
+}
+
+// ParallelBranch specifies which events a branch of a Parallel receives, the subscriber they are
+// delivered to and where its replies are sent.
+type ParallelBranch struct {
+	// Filter selects the events that are delivered to the subscriber. If it is not specified,
+	// every event is delivered.
+	// +optional
+	Filter *SubscriptionFilter `json:"filter,omitempty"`
+
+	// Subscriber is the addressable that receives the events of the branch.
+	Subscriber SubscriberSpec `json:"subscriber"`
+
+	// Reply specifies the Channel the replies of the subscriber are sent to. If it is not
+	// specified, the reply of the Parallel is used.
+	// +optional
+	Reply *ReplyStrategy `json:"reply,omitempty"`
+
+	// OnError specifies where the events that the subscriber fails to accept are sent, with the
+	// failure in their extensions, instead of failing their delivery. The other branches are
+	// not affected.
+	// +optional
+	OnError *SubscriberSpec `json:"onError,omitempty"`
+}
+
+var parallelCondSet = duckv1alpha1.NewLivingConditionSet(ParallelConditionChannelReady, ParallelConditionSubscriptionsReady, ParallelConditionAddressable)
+
+// ParallelStatus represents the current state of a Parallel.
+type ParallelStatus struct {
+	// ObservedGeneration is the most recent generation observed for this Parallel.
+	// It corresponds to the Parallel's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Parallel is Addressable. It exposes the address of the Channel that holds
+	// its events.
+	Address duckv1alpha1.Addressable `json:"address,omitempty"`
+
+	// Branches are the states of the branches, in the order of spec.branches.
+	// +optional
+	Branches []ParallelBranchStatus `json:"branches,omitempty"`
+
+	// Represents the latest available observations of a parallel's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ParallelBranchStatus represents the current state of a branch of a Parallel.
+type ParallelBranchStatus struct {
+	// Subscription is the name of the Subscription that delivers the events of the branch.
+	Subscription string `json:"subscription"`
+
+	// Ready is True when the Subscription of the branch is ready.
+	Ready corev1.ConditionStatus `json:"ready"`
+}
+
+const (
+	// ParallelConditionReady has status True when events sent to the
+	// Parallel are delivered to all its branches.
+	ParallelConditionReady = duckv1alpha1.ConditionReady
+
+	// ParallelConditionChannelReady has status True when the Channel holding
+	// the Parallel's events is ready.
+	ParallelConditionChannelReady duckv1alpha1.ConditionType = "ChannelReady"
+
+	// ParallelConditionSubscriptionsReady has status True when the
+	// Subscriptions of every branch are ready.
+	ParallelConditionSubscriptionsReady duckv1alpha1.ConditionType = "SubscriptionsReady"
+
+	// ParallelConditionAddressable has status true when this Parallel meets
+	// the Addressable contract and has a non-empty hostname.
+	ParallelConditionAddressable duckv1alpha1.ConditionType = "Addressable"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ps *ParallelStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return parallelCondSet.Manage(ps).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ps *ParallelStatus) IsReady() bool {
+	return parallelCondSet.Manage(ps).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ps *ParallelStatus) InitializeConditions() {
+	parallelCondSet.Manage(ps).InitializeConditions()
+}
+
+// MarkChannelReady sets ParallelConditionChannelReady condition to True state.
+func (ps *ParallelStatus) MarkChannelReady() {
+	parallelCondSet.Manage(ps).MarkTrue(ParallelConditionChannelReady)
+}
+
+// MarkChannelNotReady sets ParallelConditionChannelReady condition to False state.
+func (ps *ParallelStatus) MarkChannelNotReady(reason, messageFormat string, messageA ...interface{}) {
+	parallelCondSet.Manage(ps).MarkFalse(ParallelConditionChannelReady, reason, messageFormat, messageA...)
+}
+
+// MarkSubscriptionsReady sets ParallelConditionSubscriptionsReady condition to True state.
+func (ps *ParallelStatus) MarkSubscriptionsReady() {
+	parallelCondSet.Manage(ps).MarkTrue(ParallelConditionSubscriptionsReady)
+}
+
+// MarkSubscriptionsNotReady sets ParallelConditionSubscriptionsReady condition to False state.
+func (ps *ParallelStatus) MarkSubscriptionsNotReady(reason, messageFormat string, messageA ...interface{}) {
+	parallelCondSet.Manage(ps).MarkFalse(ParallelConditionSubscriptionsReady, reason, messageFormat, messageA...)
+}
+
+// SetAddress makes this Parallel addressable by setting the hostname. It also
+// sets the ParallelConditionAddressable to true.
+func (ps *ParallelStatus) SetAddress(hostname string) {
+	ps.Address.Hostname = hostname
+	if hostname != "" {
+		parallelCondSet.Manage(ps).MarkTrue(ParallelConditionAddressable)
+	} else {
+		parallelCondSet.Manage(ps).MarkFalse(ParallelConditionAddressable, "emptyHostname", "hostname is the empty string")
+	}
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ParallelList is a collection of Parallels.
+type ParallelList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Parallel `json:"items"`
+}
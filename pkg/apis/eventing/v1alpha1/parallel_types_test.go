@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParallelInitializeConditions(t *testing.T) {
+	ps := &ParallelStatus{}
+	ps.InitializeConditions()
+	want := &ParallelStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   ParallelConditionAddressable,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ParallelConditionChannelReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ParallelConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ParallelConditionSubscriptionsReady,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, ps, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestParallelIsReady(t *testing.T) {
+	tests := []struct {
+		name              string
+		markChannel       bool
+		markSubscriptions bool
+		address           string
+		wantReady         bool
+	}{{
+		name:              "all happy",
+		markChannel:       true,
+		markSubscriptions: true,
+		address:           "hostname",
+		wantReady:         true,
+	}, {
+		name:              "channel sad",
+		markChannel:       false,
+		markSubscriptions: true,
+		address:           "hostname",
+		wantReady:         false,
+	}, {
+		name:              "subscriptions sad",
+		markChannel:       true,
+		markSubscriptions: false,
+		address:           "hostname",
+		wantReady:         false,
+	}, {
+		name:              "no address",
+		markChannel:       true,
+		markSubscriptions: true,
+		wantReady:         false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ps := &ParallelStatus{}
+			ps.InitializeConditions()
+			if test.markChannel {
+				ps.MarkChannelReady()
+			} else {
+				ps.MarkChannelNotReady("NotReady", "testing")
+			}
+			if test.markSubscriptions {
+				ps.MarkSubscriptionsReady()
+			} else {
+				ps.MarkSubscriptionsNotReady("NotReady", "testing")
+			}
+			ps.SetAddress(test.address)
+			if got := ps.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
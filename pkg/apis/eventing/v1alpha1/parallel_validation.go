@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+)
+
+func (p *Parallel) Validate() *apis.FieldError {
+	return p.Spec.Validate().ViaField("spec")
+}
+
+func (ps *ParallelSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if len(ps.Branches) == 0 {
+		fe := apis.ErrMissingField("branches")
+		fe.Details = "the Parallel must have at least one branch"
+		errs = errs.Also(fe)
+	}
+	for i, branch := range ps.Branches {
+		if fe := branch.Validate(); fe != nil {
+			errs = errs.Also(fe.ViaFieldIndex("branches", i))
+		}
+	}
+	if ct := ps.ChannelTemplate; ct != nil {
+		// The subscribers of the Parallel's Channel are managed by the Parallel.
+		if ct.Subscribable != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("subscribable").ViaField("channelTemplate"))
+		}
+	}
+	if !isReplyStrategyNilOrEmpty(ps.Reply) {
+		if fe := isValidReply(*ps.Reply); fe != nil {
+			errs = errs.Also(fe.ViaField("reply"))
+		}
+	}
+	return errs
+}
+
+func (pb *ParallelBranch) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if pb.Filter != nil {
+		if fe := isValidFilter(*pb.Filter); fe != nil {
+			errs = errs.Also(fe.ViaField("filter"))
+		}
+	}
+	if isSubscriberSpecNilOrEmpty(&pb.Subscriber) {
+		errs = errs.Also(apis.ErrMissingField("subscriber"))
+	} else if fe := isValidManagedSubscriberSpec(pb.Subscriber); fe != nil {
+		errs = errs.Also(fe.ViaField("subscriber"))
+	}
+	if !isReplyStrategyNilOrEmpty(pb.Reply) {
+		if fe := isValidReply(*pb.Reply); fe != nil {
+			errs = errs.Also(fe.ViaField("reply"))
+		}
+	}
+	if pb.OnError != nil {
+		if fe := isValidOnError(pb.OnError); fe != nil {
+			errs = errs.Also(fe.ViaField("onError"))
+		}
+	}
+	return errs
+}
+
+func (current *Parallel) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*Parallel)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a Parallel"}
+	}
+	if diff := cmp.Diff(original.Spec.ChannelTemplate, current.Spec.ChannelTemplate); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.channelTemplate"},
+		}
+	}
+	return nil
+}
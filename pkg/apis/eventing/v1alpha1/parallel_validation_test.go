@@ -0,0 +1,215 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParallelValidation(t *testing.T) {
+	dnsName := "http://branch.example.com/"
+	reply := &ReplyStrategy{
+		Channel: &corev1.ObjectReference{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "Channel",
+			Name:       "reply",
+		},
+	}
+	tests := []CRDTest{{
+		name: "valid",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Filter:     &SubscriptionFilter{Expression: "type = 'com.example.created'"},
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+					Reply:      reply,
+				}, {
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+					OnError:    &SubscriberSpec{DNSName: &dnsName},
+				}},
+				Reply: reply,
+			},
+		},
+		want: nil,
+	}, {
+		name: "no branches",
+		cr:   &Parallel{},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.branches")
+			fe.Details = "the Parallel must have at least one branch"
+			return fe
+		}(),
+	}, {
+		name: "branch without subscriber",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+				}, {}},
+			},
+		},
+		want: apis.ErrMissingField("spec.branches[1].subscriber"),
+	}, {
+		name: "branch with auth",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Subscriber: SubscriberSpec{
+						DNSName: &dnsName,
+						Auth: &eventingduck.SubscriberAuth{
+							BearerToken: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "token"},
+								Key:                  "token",
+							},
+						},
+					},
+				}},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("spec.branches[0].subscriber.auth")
+			fe.Details = "only the subscribers of Subscriptions may authenticate"
+			return fe
+		}(),
+	}, {
+		name: "invalid filter",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Filter:     &SubscriptionFilter{Expression: "type ="},
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+				}},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("type =", "spec.branches[0].filter.expression")
+			fe.Details = isValidFilter(SubscriptionFilter{Expression: "type ="}).Details
+			return fe
+		}(),
+	}, {
+		name: "branch reply without a kind",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+					Reply: &ReplyStrategy{
+						Channel: &corev1.ObjectReference{
+							APIVersion: "v1",
+							Name:       "reply",
+						},
+					},
+				}},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.branches[0].reply.channel.kind")
+			return fe
+		}(),
+	}, {
+		name: "empty branch onError",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+					OnError:    &SubscriberSpec{},
+				}},
+			},
+		},
+		want: apis.ErrMissingField("spec.branches[0].onError.ref", "spec.branches[0].onError.dnsName"),
+	}, {
+		name: "channel template with subscribers",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Subscriber: SubscriberSpec{DNSName: &dnsName},
+				}},
+				ChannelTemplate: &ChannelSpec{
+					Subscribable: &eventingduck.Subscribable{
+						Subscribers: []eventingduck.ChannelSubscriberSpec{{
+							SubscriberURI: "subscriberendpoint",
+						}},
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.channelTemplate.subscribable"),
+	}}
+
+	doValidateTest(t, tests)
+}
+
+func TestParallelImmutableFields(t *testing.T) {
+	template := &ChannelSpec{
+		Provisioner: &corev1.ObjectReference{
+			Name: "foo",
+		},
+	}
+	dnsName := "http://branch.example.com/"
+	tests := []struct {
+		name string
+		new  apis.Immutable
+		old  apis.Immutable
+		want *apis.FieldError
+	}{{
+		name: "good (new)",
+		new:  &Parallel{},
+		old:  nil,
+		want: nil,
+	}, {
+		name: "good (branches change)",
+		new:  &Parallel{Spec: ParallelSpec{ChannelTemplate: template, Branches: []ParallelBranch{{Subscriber: SubscriberSpec{DNSName: &dnsName}}}}},
+		old:  &Parallel{Spec: ParallelSpec{ChannelTemplate: template.DeepCopy()}},
+		want: nil,
+	}, {
+		name: "bad (channel template change)",
+		new: &Parallel{
+			Spec: ParallelSpec{
+				ChannelTemplate: &ChannelSpec{
+					Provisioner: &corev1.ObjectReference{
+						Name: "bar",
+					},
+				},
+			},
+		},
+		old: &Parallel{Spec: ParallelSpec{ChannelTemplate: template}},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.channelTemplate"},
+		},
+	}, {
+		name: "bad (type)",
+		new:  &Parallel{},
+		old:  &Channel{},
+		want: &apis.FieldError{
+			Message: "The provided resource was not a Parallel",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckImmutableFields (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
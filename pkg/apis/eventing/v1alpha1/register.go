@@ -45,12 +45,24 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Broker{},
+		&BrokerList{},
 		&Channel{},
 		&ChannelList{},
+		&ChannelMigration{},
+		&ChannelMigrationList{},
 		&ClusterChannelProvisioner{},
 		&ClusterChannelProvisionerList{},
+		&EventType{},
+		&EventTypeList{},
+		&Parallel{},
+		&ParallelList{},
+		&Sequence{},
+		&SequenceList{},
 		&Subscription{},
 		&SubscriptionList{},
+		&Trigger{},
+		&TriggerList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -55,12 +55,24 @@ func TestKnownTypes(t *testing.T) {
 	types := scheme.KnownTypes(SchemeGroupVersion)
 
 	for _, name := range []string{
+		"Broker",
+		"BrokerList",
 		"Channel",
 		"ChannelList",
+		"ChannelMigration",
+		"ChannelMigrationList",
 		"ClusterChannelProvisioner",
 		"ClusterChannelProvisionerList",
+		"EventType",
+		"EventTypeList",
+		"Parallel",
+		"ParallelList",
+		"Sequence",
+		"SequenceList",
 		"Subscription",
 		"SubscriptionList",
+		"Trigger",
+		"TriggerList",
 	} {
 		if _, ok := types[name]; !ok {
 			t.Errorf("Did not find %q as registered type", name)
@@ -0,0 +1,178 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Sequence is an Addressable pipeline of subscribers. Events sent to the Sequence are delivered
+// to its first step, the reply of every step is delivered to the next one, and the reply of the
+// last step is sent to the Sequence's reply. Every step reads its events from a Channel created
+// by the Sequence.
+type Sequence struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Sequence.
+	Spec SequenceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Sequence. This data may be out of
+	// date.
+	// +optional
+	Status SequenceStatus `json:"status,omitempty"`
+}
+
+// Check that Sequence can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Sequence)(nil)
+var _ apis.Defaultable = (*Sequence)(nil)
+var _ apis.Immutable = (*Sequence)(nil)
+var _ runtime.Object = (*Sequence)(nil)
+var _ webhook.GenericCRD = (*Sequence)(nil)
+
+// SequenceSpec specifies the steps of a Sequence, the Channels between them and where the reply
+// of the last step is sent.
+type SequenceSpec struct {
+	// Steps are the subscribers the events go through, in order. Each step receives the reply
+	// of the previous one.
+	Steps []SequenceStep `json:"steps"`
+
+	// ChannelTemplate is the spec of the Channels the Sequence creates in front of each step. If
+	// it is not specified, the Channels are created with the default template of the namespace.
+	// +optional
+	ChannelTemplate *ChannelSpec `json:"channelTemplate,omitempty"`
+
+	// Reply specifies the Channel the replies of the last step are sent to. If it is not
+	// specified, those replies are dropped.
+	// +optional
+	Reply *ReplyStrategy `json:"reply,omitempty"`
+}
+
+// SequenceStep is a subscriber of a Sequence.
+type SequenceStep struct {
+	SubscriberSpec `json:",inline"`
+
+	// OnError specifies where the events that the step fails to accept are sent, with the
+	// failure in their extensions, instead of stalling the Sequence. Those events do not go
+	// through the rest of the Sequence.
+	// +optional
+	OnError *SubscriberSpec `json:"onError,omitempty"`
+}
+
+var sequenceCondSet = duckv1alpha1.NewLivingConditionSet(SequenceConditionChannelsReady, SequenceConditionSubscriptionsReady, SequenceConditionAddressable)
+
+// SequenceStatus represents the current state of a Sequence.
+type SequenceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this Sequence.
+	// It corresponds to the Sequence's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Sequence is Addressable. It exposes the address of the Channel of its first
+	// step.
+	Address duckv1alpha1.Addressable `json:"address,omitempty"`
+
+	// Represents the latest available observations of a sequence's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// SequenceConditionReady has status True when events sent to the
+	// Sequence go through all its steps.
+	SequenceConditionReady = duckv1alpha1.ConditionReady
+
+	// SequenceConditionChannelsReady has status True when the Channels in
+	// front of every step are ready.
+	SequenceConditionChannelsReady duckv1alpha1.ConditionType = "ChannelsReady"
+
+	// SequenceConditionSubscriptionsReady has status True when the
+	// Subscriptions of every step are ready.
+	SequenceConditionSubscriptionsReady duckv1alpha1.ConditionType = "SubscriptionsReady"
+
+	// SequenceConditionAddressable has status true when this Sequence meets
+	// the Addressable contract and has a non-empty hostname.
+	SequenceConditionAddressable duckv1alpha1.ConditionType = "Addressable"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *SequenceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return sequenceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *SequenceStatus) IsReady() bool {
+	return sequenceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *SequenceStatus) InitializeConditions() {
+	sequenceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkChannelsReady sets SequenceConditionChannelsReady condition to True state.
+func (ss *SequenceStatus) MarkChannelsReady() {
+	sequenceCondSet.Manage(ss).MarkTrue(SequenceConditionChannelsReady)
+}
+
+// MarkChannelsNotReady sets SequenceConditionChannelsReady condition to False state.
+func (ss *SequenceStatus) MarkChannelsNotReady(reason, messageFormat string, messageA ...interface{}) {
+	sequenceCondSet.Manage(ss).MarkFalse(SequenceConditionChannelsReady, reason, messageFormat, messageA...)
+}
+
+// MarkSubscriptionsReady sets SequenceConditionSubscriptionsReady condition to True state.
+func (ss *SequenceStatus) MarkSubscriptionsReady() {
+	sequenceCondSet.Manage(ss).MarkTrue(SequenceConditionSubscriptionsReady)
+}
+
+// MarkSubscriptionsNotReady sets SequenceConditionSubscriptionsReady condition to False state.
+func (ss *SequenceStatus) MarkSubscriptionsNotReady(reason, messageFormat string, messageA ...interface{}) {
+	sequenceCondSet.Manage(ss).MarkFalse(SequenceConditionSubscriptionsReady, reason, messageFormat, messageA...)
+}
+
+// SetAddress makes this Sequence addressable by setting the hostname. It also
+// sets the SequenceConditionAddressable to true.
+func (ss *SequenceStatus) SetAddress(hostname string) {
+	ss.Address.Hostname = hostname
+	if hostname != "" {
+		sequenceCondSet.Manage(ss).MarkTrue(SequenceConditionAddressable)
+	} else {
+		sequenceCondSet.Manage(ss).MarkFalse(SequenceConditionAddressable, "emptyHostname", "hostname is the empty string")
+	}
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SequenceList is a collection of Sequences.
+type SequenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Sequence `json:"items"`
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSequenceInitializeConditions(t *testing.T) {
+	ss := &SequenceStatus{}
+	ss.InitializeConditions()
+	want := &SequenceStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   SequenceConditionAddressable,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   SequenceConditionChannelsReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   SequenceConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   SequenceConditionSubscriptionsReady,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, ss, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestSequenceIsReady(t *testing.T) {
+	tests := []struct {
+		name              string
+		markChannels      bool
+		markSubscriptions bool
+		address           string
+		wantReady         bool
+	}{{
+		name:              "all happy",
+		markChannels:      true,
+		markSubscriptions: true,
+		address:           "hostname",
+		wantReady:         true,
+	}, {
+		name:              "channels sad",
+		markChannels:      false,
+		markSubscriptions: true,
+		address:           "hostname",
+		wantReady:         false,
+	}, {
+		name:              "subscriptions sad",
+		markChannels:      true,
+		markSubscriptions: false,
+		address:           "hostname",
+		wantReady:         false,
+	}, {
+		name:              "no address",
+		markChannels:      true,
+		markSubscriptions: true,
+		wantReady:         false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ss := &SequenceStatus{}
+			ss.InitializeConditions()
+			if test.markChannels {
+				ss.MarkChannelsReady()
+			} else {
+				ss.MarkChannelsNotReady("NotReady", "testing")
+			}
+			if test.markSubscriptions {
+				ss.MarkSubscriptionsReady()
+			} else {
+				ss.MarkSubscriptionsNotReady("NotReady", "testing")
+			}
+			ss.SetAddress(test.address)
+			if got := ss.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+)
+
+func (s *Sequence) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *SequenceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if len(ss.Steps) == 0 {
+		fe := apis.ErrMissingField("steps")
+		fe.Details = "the Sequence must have at least one step"
+		errs = errs.Also(fe)
+	}
+	for i, step := range ss.Steps {
+		if isSubscriberSpecNilOrEmpty(&step.SubscriberSpec) {
+			errs = errs.Also(apis.ErrMissingField(apis.CurrentField).ViaFieldIndex("steps", i))
+		} else if fe := isValidManagedSubscriberSpec(step.SubscriberSpec); fe != nil {
+			errs = errs.Also(fe.ViaFieldIndex("steps", i))
+		}
+		if step.OnError != nil {
+			if fe := isValidOnError(step.OnError); fe != nil {
+				errs = errs.Also(fe.ViaField("onError").ViaFieldIndex("steps", i))
+			}
+		}
+	}
+	if ct := ss.ChannelTemplate; ct != nil {
+		// The subscribers of the Sequence's Channels are managed by the Sequence.
+		if ct.Subscribable != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("subscribable").ViaField("channelTemplate"))
+		}
+	}
+	if !isReplyStrategyNilOrEmpty(ss.Reply) {
+		if fe := isValidReply(*ss.Reply); fe != nil {
+			errs = errs.Also(fe.ViaField("reply"))
+		}
+	}
+	return errs
+}
+
+func (current *Sequence) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*Sequence)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a Sequence"}
+	}
+	if diff := cmp.Diff(original.Spec.ChannelTemplate, current.Spec.ChannelTemplate); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.channelTemplate"},
+		}
+	}
+	return nil
+}
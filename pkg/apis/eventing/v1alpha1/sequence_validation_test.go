@@ -0,0 +1,215 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSequenceValidation(t *testing.T) {
+	dnsName := "http://step.example.com/"
+	step := SequenceStep{SubscriberSpec: SubscriberSpec{DNSName: &dnsName}}
+	tests := []CRDTest{{
+		name: "valid",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{step, step},
+				Reply: &ReplyStrategy{
+					Channel: &corev1.ObjectReference{
+						APIVersion: SchemeGroupVersion.String(),
+						Kind:       "Channel",
+						Name:       "reply",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "no steps",
+		cr:   &Sequence{},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.steps")
+			fe.Details = "the Sequence must have at least one step"
+			return fe
+		}(),
+	}, {
+		name: "empty step",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{step, {}},
+			},
+		},
+		want: apis.ErrMissingField("spec.steps[1]"),
+	}, {
+		name: "invalid step",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{{
+					SubscriberSpec: SubscriberSpec{
+						DNSName: &dnsName,
+						Ref: &corev1.ObjectReference{
+							APIVersion: "v1",
+							Kind:       "Service",
+							Name:       "step",
+						},
+					},
+				}},
+			},
+		},
+		want: apis.ErrMultipleOneOf("spec.steps[0].ref", "spec.steps[0].dnsName"),
+	}, {
+		name: "step with auth",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{{
+					SubscriberSpec: SubscriberSpec{
+						DNSName: &dnsName,
+						Auth: &eventingduck.SubscriberAuth{
+							BearerToken: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "token"},
+								Key:                  "token",
+							},
+						},
+					},
+				}},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("spec.steps[0].auth")
+			fe.Details = "only the subscribers of Subscriptions may authenticate"
+			return fe
+		}(),
+	}, {
+		name: "step with onError",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{{
+					SubscriberSpec: SubscriberSpec{DNSName: &dnsName},
+					OnError:        &SubscriberSpec{DNSName: &dnsName},
+				}},
+			},
+		},
+		want: nil,
+	}, {
+		name: "empty onError",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{step, {
+					SubscriberSpec: SubscriberSpec{DNSName: &dnsName},
+					OnError:        &SubscriberSpec{},
+				}},
+			},
+		},
+		want: apis.ErrMissingField("spec.steps[1].onError.ref", "spec.steps[1].onError.dnsName"),
+	}, {
+		name: "channel template with subscribers",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{step},
+				ChannelTemplate: &ChannelSpec{
+					Subscribable: &eventingduck.Subscribable{
+						Subscribers: []eventingduck.ChannelSubscriberSpec{{
+							SubscriberURI: "subscriberendpoint",
+						}},
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.channelTemplate.subscribable"),
+	}, {
+		name: "reply without a kind",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []SequenceStep{step},
+				Reply: &ReplyStrategy{
+					Channel: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Name:       "reply",
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.reply.channel.kind")
+			return fe
+		}(),
+	}}
+
+	doValidateTest(t, tests)
+}
+
+func TestSequenceImmutableFields(t *testing.T) {
+	template := &ChannelSpec{
+		Provisioner: &corev1.ObjectReference{
+			Name: "foo",
+		},
+	}
+	dnsName := "http://step.example.com/"
+	tests := []struct {
+		name string
+		new  apis.Immutable
+		old  apis.Immutable
+		want *apis.FieldError
+	}{{
+		name: "good (new)",
+		new:  &Sequence{},
+		old:  nil,
+		want: nil,
+	}, {
+		name: "good (steps change)",
+		new:  &Sequence{Spec: SequenceSpec{ChannelTemplate: template, Steps: []SequenceStep{{SubscriberSpec: SubscriberSpec{DNSName: &dnsName}}}}},
+		old:  &Sequence{Spec: SequenceSpec{ChannelTemplate: template.DeepCopy()}},
+		want: nil,
+	}, {
+		name: "bad (channel template change)",
+		new: &Sequence{
+			Spec: SequenceSpec{
+				ChannelTemplate: &ChannelSpec{
+					Provisioner: &corev1.ObjectReference{
+						Name: "bar",
+					},
+				},
+			},
+		},
+		old: &Sequence{Spec: SequenceSpec{ChannelTemplate: template}},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.channelTemplate"},
+		},
+	}, {
+		name: "bad (type)",
+		new:  &Sequence{},
+		old:  &Channel{},
+		want: &apis.FieldError{
+			Message: "The provided resource was not a Sequence",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckImmutableFields (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -30,24 +30,13 @@ func isChannelEmpty(f corev1.ObjectReference) bool {
 }
 
 // Valid from only contains the following fields:
-// - Kind       == 'Channel'
-// - APIVersion == 'eventing.knative.dev/v1alpha1'
+// - Kind       == not empty
+// - APIVersion == not empty
 // - Name       == not empty
+// The referenced object may be of any kind that is Subscribable, which is checked when the
+// Subscription is reconciled.
 func isValidChannel(f corev1.ObjectReference) *apis.FieldError {
-	errs := isValidObjectReference(f)
-
-	if f.Kind != "Channel" {
-		fe := apis.ErrInvalidValue(f.Kind, "kind")
-		fe.Paths = []string{"kind"}
-		fe.Details = "only 'Channel' kind is allowed"
-		errs = errs.Also(fe)
-	}
-	if f.APIVersion != "eventing.knative.dev/v1alpha1" {
-		fe := apis.ErrInvalidValue(f.APIVersion, "apiVersion")
-		fe.Details = "only eventing.knative.dev/v1alpha1 is allowed for apiVersion"
-		errs = errs.Also(fe)
-	}
-	return errs
+	return isValidObjectReference(f)
 }
 
 func isValidObjectReference(f corev1.ObjectReference) *apis.FieldError {
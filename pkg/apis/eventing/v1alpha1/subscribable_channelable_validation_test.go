@@ -30,31 +30,21 @@ var validationTests = []struct {
 	want *apis.FieldError
 }{
 	{
-		name: "invalid kind",
+		name: "subscribable of another kind",
 		ref: corev1.ObjectReference{
 			Name:       "boaty-mcboatface",
-			APIVersion: "eventing.knative.dev/v1alpha1",
+			APIVersion: "messaging.example.com/v1",
 			Kind:       "Strait",
 		},
-		want: &apis.FieldError{
-			Message: "invalid value \"Strait\"",
-			Paths:   []string{"kind"},
-			Details: "only 'Channel' kind is allowed",
-		},
+		want: nil,
 	},
 	{
-		name: "invalid api version",
+		name: "missing kind",
 		ref: corev1.ObjectReference{
 			Name:       "boaty-mcboatface",
-			APIVersion: "eventing.knative.dev/v1alpha2",
-			Kind:       "Channel",
-		},
-		want: &apis.FieldError{
-			Message: `invalid value "eventing.knative.dev/v1alpha2"`,
-			Paths:   []string{"apiVersion"},
-			Details: "only eventing.knative.dev/v1alpha1 " +
-				"is allowed for apiVersion",
+			APIVersion: "eventing.knative.dev/v1alpha1",
 		},
+		want: apis.ErrMissingField("kind"),
 	},
 	{
 		name: "valid channel",
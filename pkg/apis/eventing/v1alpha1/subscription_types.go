@@ -17,12 +17,14 @@
 package v1alpha1
 
 import (
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/apis"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/webhook"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -68,15 +70,14 @@ type SubscriptionSpec struct {
 	Generation int64 `json:"generation,omitempty"`
 
 	// Reference to a channel that will be used to create the subscription
-	// for receiving events. The channel must have spec.subscriptions
-	// list which will then be modified accordingly.
+	// for receiving events. The channel may be of any kind that is
+	// Subscribable: its spec.subscribable.subscribers list is modified
+	// accordingly.
 	//
 	// You can specify only the following fields of the ObjectReference:
 	//   - Kind
 	//   - APIVersion
 	//   - Name
-	// Kind must be "Channel" and APIVersion must be
-	// "eventing.knative.dev/v1alpha1"
 	//
 	// This field is immutable. We have no good answer on what happens to
 	// the events that are currently in the channel being consumed from
@@ -97,6 +98,69 @@ type SubscriptionSpec struct {
 	// the Subscriber target.
 	// +optional
 	Reply *ReplyStrategy `json:"reply,omitempty"`
+
+	// Filter specifies (optionally) which events from the Channel are
+	// delivered to the Subscriber. Events that do not match are dropped
+	// for this Subscription only.
+	// +optional
+	Filter *SubscriptionFilter `json:"filter,omitempty"`
+
+	// Transform specifies (optionally) how the events from the Channel
+	// are rewritten before they are delivered to the Subscriber.
+	// +optional
+	Transform *SubscriptionTransform `json:"transform,omitempty"`
+
+	// Schema specifies (optionally) the JSON Schema that the data of the
+	// events from the Channel must conform to. Events that do not conform
+	// are not delivered to the Subscriber.
+	// +optional
+	Schema *SubscriptionSchema `json:"schema,omitempty"`
+
+	// OnError specifies (optionally) where the events that the Subscriber
+	// fails to accept are sent, instead of failing their delivery. The
+	// failure is described in the knativeerrordest, knativeerrorcode and
+	// knativeerrordata extensions of the events.
+	// +optional
+	OnError *SubscriberSpec `json:"onError,omitempty"`
+
+	// Paused temporarily stops delivery to the Subscriber and Reply,
+	// without deleting the Subscription. Channels that persist events
+	// keep them, along with this Subscription's position, until the
+	// Subscription is resumed. Non-durable channels drop the events
+	// sent while the Subscription is paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// SubscriptionFilter selects the events that are delivered to a Subscription.
+type SubscriptionFilter struct {
+	// Expression is a CloudEvents SQL expression evaluated against the
+	// context attributes of each event, for example:
+	//   type = 'com.example.created' AND source LIKE '/orders/%'
+	// See pkg/filter for the supported subset of the language.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+}
+
+// SubscriptionTransform rewrites the events delivered to a Subscription.
+type SubscriptionTransform struct {
+	// Template is a Go template executed with the context attributes of
+	// each event and its data, whose output is the event delivered
+	// instead, in the JSON structured format of CloudEvents 1.0, e.g.
+	//   {"specversion": "1.0", "type": "com.example.v2", "source": {{json .source}},
+	//    "id": {{json .id}}, "data": {"total": {{json .data.total}}}}
+	// See pkg/transform for the data and functions available to it.
+	Template string `json:"template"`
+}
+
+// SubscriptionSchema validates the data of the events delivered to a Subscription.
+type SubscriptionSchema struct {
+	eventingduck.SubscriberSchema `json:",inline"`
+
+	// DeadLetterSink receives the events whose data does not conform to
+	// the schema. If it is not specified, those events are dropped.
+	// +optional
+	DeadLetterSink *SubscriberSpec `json:"deadLetterSink,omitempty"`
 }
 
 // SubscriberSpec specifies the reference to an object that's expected to
@@ -141,19 +205,49 @@ type SubscriberSpec struct {
 	// http://myexternalhandler.example.com/foo/bar
 	// +optional
 	DNSName *string `json:"dnsName,omitempty"`
+
+	// Port is the port of the Service to deliver to, either its number
+	// or its name. It may only be set when Ref is a Kubernetes Service
+	// (apiVersion v1, kind Service). Defaults to port 80.
+	// +optional
+	Port *intstr.IntOrString `json:"port,omitempty"`
+
+	// Path is the URI path on the subscriber that events are delivered
+	// to, for example /events. It may only be set along with Ref, as a
+	// DNSName already includes its path. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Auth specifies (optionally) the credentials that are attached to
+	// every delivery to the subscriber, for subscribers that sit behind an
+	// authenticating gateway.
+	// +optional
+	Auth *eventingduck.SubscriberAuth `json:"auth,omitempty"`
+
+	// Protocol is the protocol events are delivered to the subscriber
+	// with, either HTTP, gRPC, AMQP, MQTT or SSE. gRPC subscribers
+	// implement the knative.eventing.v1alpha1.Subscriber service and receive
+	// the events in the CloudEvents protobuf format. AMQP subscribers are
+	// AMQP 1.0 containers: the events are sent to the node addressed by the
+	// path of their URI, and they do not reply. MQTT subscribers are MQTT
+	// brokers: the events are published to the topic of the path of their
+	// URI, and they do not reply either. SSE subscribers have neither Ref
+	// nor DNSName: they connect to the dispatcher of the channel and the
+	// events are pushed to them as Server-Sent Events. Defaults to HTTP.
+	// +optional
+	Protocol eventingduck.DeliveryProtocol `json:"protocol,omitempty"`
 }
 
 // ReplyStrategy specifies the handling of the SubscriberSpec's returned replies.
 // If no SubscriberSpec is specified, the identity function is assumed.
 type ReplyStrategy struct {
-	// This object must be a Channel.
+	// This object may be of any kind that is Addressable, such as a
+	// Channel. Replies are sent to its status.address.
 	//
 	// You can specify only the following fields of the ObjectReference:
 	//   - Kind
 	//   - APIVersion
 	//   - Name
-	// Kind must be "Channel" and APIVersion must be
-	// "eventing.knative.dev/v1alpha1"
 	// +optional
 	Channel *corev1.ObjectReference `json:"channel,omitempty"`
 }
@@ -181,6 +275,12 @@ type SubscriptionStatusPhysicalSubscription struct {
 
 	// ReplyURI is the fully resolved URI for the spec.reply.
 	ReplyURI string `json:"replyURI,omitEmpty"`
+
+	// DeadLetterSinkURI is the fully resolved URI for the spec.schema.deadLetterSink.
+	DeadLetterSinkURI string `json:"deadLetterSinkURI,omitEmpty"`
+
+	// OnErrorURI is the fully resolved URI for the spec.onError.
+	OnErrorURI string `json:"onErrorURI,omitEmpty"`
 }
 
 const (
@@ -194,6 +294,15 @@ const (
 	// SubscriptionConditionChannelReady has status True when controller has successfully added a
 	// subscription to the spec.channel resource.
 	SubscriptionConditionChannelReady duckv1alpha1.ConditionType = "ChannelReady"
+
+	// SubscriptionConditionSubscriberReachable has status True when the last probe of the resolved
+	// subscriber URI got a response. It is informational and does not affect the Ready condition.
+	SubscriptionConditionSubscriberReachable duckv1alpha1.ConditionType = "SubscriberReachable"
+
+	// SubscriptionConditionCredentialsProvided has status True when the Secrets referenced by the
+	// auth of the subscriber hold the referenced keys. The deliveries to the subscriber fail while
+	// it is False. It is informational and does not affect the Ready condition.
+	SubscriptionConditionCredentialsProvided duckv1alpha1.ConditionType = "CredentialsProvided"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -221,6 +330,26 @@ func (ss *SubscriptionStatus) MarkChannelReady() {
 	subCondSet.Manage(ss).MarkTrue(SubscriptionConditionChannelReady)
 }
 
+// MarkSubscriberReachable sets the SubscriberReachable condition to True state.
+func (ss *SubscriptionStatus) MarkSubscriberReachable() {
+	subCondSet.Manage(ss).MarkTrue(SubscriptionConditionSubscriberReachable)
+}
+
+// MarkSubscriberUnreachable sets the SubscriberReachable condition to False state.
+func (ss *SubscriptionStatus) MarkSubscriberUnreachable(reason, messageFormat string, messageA ...interface{}) {
+	subCondSet.Manage(ss).MarkFalse(SubscriptionConditionSubscriberReachable, reason, messageFormat, messageA...)
+}
+
+// MarkCredentialsProvided sets the CredentialsProvided condition to True state.
+func (ss *SubscriptionStatus) MarkCredentialsProvided() {
+	subCondSet.Manage(ss).MarkTrue(SubscriptionConditionCredentialsProvided)
+}
+
+// MarkNoCredentials sets the CredentialsProvided condition to False state.
+func (ss *SubscriptionStatus) MarkNoCredentials(reason, messageFormat string, messageA ...interface{}) {
+	subCondSet.Manage(ss).MarkFalse(SubscriptionConditionCredentialsProvided, reason, messageFormat, messageA...)
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // SubscriptionList returned in list operations
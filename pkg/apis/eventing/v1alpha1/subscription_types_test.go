@@ -168,13 +168,67 @@ func TestSubscriptionInitializeConditions(t *testing.T) {
 	}
 }
 
+func TestSubscriptionSubscriberReachable(t *testing.T) {
+	ss := &SubscriptionStatus{}
+	ss.InitializeConditions()
+	if c := ss.GetCondition(SubscriptionConditionSubscriberReachable); c != nil {
+		t.Errorf("SubscriberReachable should not be initialized, got %v", c)
+	}
+
+	ss.MarkSubscriberUnreachable("ProbeFailed", "probe of %q failed", "http://example.com/")
+	c := ss.GetCondition(SubscriptionConditionSubscriberReachable)
+	if c == nil || !c.IsFalse() || c.Reason != "ProbeFailed" || c.Message != `probe of "http://example.com/" failed` {
+		t.Errorf("unexpected SubscriberReachable condition: %v", c)
+	}
+	if c.Severity != duckv1alpha1.ConditionSeverityInfo {
+		t.Errorf("unexpected SubscriberReachable severity: %v", c.Severity)
+	}
+	if ready := ss.GetCondition(SubscriptionConditionReady); ready.IsFalse() {
+		t.Errorf("an unreachable subscriber should not make the Subscription not ready, got %v", ready)
+	}
+
+	ss.MarkSubscriberReachable()
+	if c := ss.GetCondition(SubscriptionConditionSubscriberReachable); c == nil || !c.IsTrue() {
+		t.Errorf("unexpected SubscriberReachable condition: %v", c)
+	}
+}
+
+func TestSubscriptionCredentialsProvided(t *testing.T) {
+	ss := &SubscriptionStatus{}
+	ss.InitializeConditions()
+	if c := ss.GetCondition(SubscriptionConditionCredentialsProvided); c != nil {
+		t.Errorf("CredentialsProvided should not be initialized, got %v", c)
+	}
+
+	ss.MarkNoCredentials("SecretNotFound", "secret %q not found", "token")
+	c := ss.GetCondition(SubscriptionConditionCredentialsProvided)
+	if c == nil || !c.IsFalse() || c.Reason != "SecretNotFound" || c.Message != `secret "token" not found` {
+		t.Errorf("unexpected CredentialsProvided condition: %v", c)
+	}
+	if ready := ss.GetCondition(SubscriptionConditionReady); ready.IsFalse() {
+		t.Errorf("missing credentials should not make the Subscription not ready, got %v", ready)
+	}
+
+	ss.MarkCredentialsProvided()
+	if c := ss.GetCondition(SubscriptionConditionCredentialsProvided); c == nil || !c.IsTrue() {
+		t.Errorf("unexpected CredentialsProvided condition: %v", c)
+	}
+}
+
 func TestSubscriptionIsReady(t *testing.T) {
 	tests := []struct {
-		name             string
-		markResolved     bool
-		markChannelReady bool
-		wantReady        bool
+		name               string
+		markResolved       bool
+		markChannelReady   bool
+		markSubscriberDown bool
+		wantReady          bool
 	}{{
+		name:               "all happy, subscriber unreachable",
+		markResolved:       true,
+		markChannelReady:   true,
+		markSubscriberDown: true,
+		wantReady:          true,
+	}, {
 		name:             "all happy",
 		markResolved:     true,
 		markChannelReady: true,
@@ -204,6 +258,9 @@ func TestSubscriptionIsReady(t *testing.T) {
 			if test.markChannelReady {
 				ss.MarkChannelReady()
 			}
+			if test.markSubscriberDown {
+				ss.MarkSubscriberUnreachable("ProbeFailed", "connection refused")
+			}
 			got := ss.IsReady()
 			if test.wantReady != got {
 				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
@@ -17,11 +17,20 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/filter"
+	"github.com/knative/eventing/pkg/transform"
 	"github.com/knative/pkg/apis"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func (s *Subscription) Validate() *apis.FieldError {
@@ -52,6 +61,22 @@ func (ss *SubscriptionSpec) Validate() *apis.FieldError {
 		if fe := isValidSubscriberSpec(*ss.Subscriber); fe != nil {
 			errs = errs.Also(fe.ViaField("subscriber"))
 		}
+	} else if ss.Subscriber != nil {
+		if ss.Subscriber.Auth != nil {
+			fe := apis.ErrMissingField("ref", "dnsName")
+			fe.Details = "auth requires a subscriber to authenticate to"
+			errs = errs.Also(fe.ViaField("subscriber"))
+		}
+		if ss.Subscriber.Port != nil || ss.Subscriber.Path != "" {
+			fe := apis.ErrMissingField("ref")
+			fe.Details = "port and path require a subscriber ref"
+			errs = errs.Also(fe.ViaField("subscriber"))
+		}
+		if ss.Subscriber.Protocol != "" {
+			fe := apis.ErrMissingField("ref", "dnsName")
+			fe.Details = "protocol requires a subscriber to deliver to"
+			errs = errs.Also(fe.ViaField("subscriber"))
+		}
 	}
 
 	if !missingReply {
@@ -60,17 +85,125 @@ func (ss *SubscriptionSpec) Validate() *apis.FieldError {
 		}
 	}
 
+	if ss.Filter != nil {
+		if fe := isValidFilter(*ss.Filter); fe != nil {
+			errs = errs.Also(fe.ViaField("filter"))
+		}
+	}
+
+	if ss.Schema != nil {
+		if fe := isValidSchema(*ss.Schema); fe != nil {
+			errs = errs.Also(fe.ViaField("schema"))
+		}
+	}
+
+	if ss.Transform != nil {
+		if fe := isValidTransform(*ss.Transform); fe != nil {
+			errs = errs.Also(fe.ViaField("transform"))
+		}
+	}
+
+	if ss.OnError != nil {
+		if fe := isValidOnError(ss.OnError); fe != nil {
+			errs = errs.Also(fe.ViaField("onError"))
+		}
+	}
+
+	return errs
+}
+
+// isValidOnError validates the destination of the events a subscriber fails to accept.
+func isValidOnError(s *SubscriberSpec) *apis.FieldError {
+	if isSubscriberSpecNilOrEmpty(s) {
+		return apis.ErrMissingField("ref", "dnsName")
+	}
+	errs := isValidSubscriberSpec(*s)
+	if s.Auth != nil {
+		fe := apis.ErrDisallowedFields("auth")
+		fe.Details = "the deliveries to the error destination are not authenticated"
+		errs = errs.Also(fe)
+	}
+	if fe := isHTTPSubscriberProtocol(s.Protocol); fe != nil {
+		fe.Details = "events are delivered to the error destination with HTTP"
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+func isValidSchema(s SubscriptionSchema) *apis.FieldError {
+	errs := isValidSubscriberSchema(s.SubscriberSchema)
+	if s.DeadLetterSink != nil {
+		if fe := isValidSubscriberSpec(*s.DeadLetterSink); fe != nil {
+			errs = errs.Also(fe.ViaField("deadLetterSink"))
+		}
+		if fe := isHTTPSubscriberProtocol(s.DeadLetterSink.Protocol); fe != nil {
+			fe.Details = "events are delivered to the dead letter sink with HTTP"
+			errs = errs.Also(fe.ViaField("deadLetterSink"))
+		}
+	}
+	return errs
+}
+
+func isValidSubscriberSchema(s eventingduck.SubscriberSchema) *apis.FieldError {
+	var errs *apis.FieldError
+	switch {
+	case s.ConfigMapKeyRef == nil && s.Registry == nil:
+		errs = errs.Also(apis.ErrMissingOneOf("configMapKeyRef", "registry"))
+	case s.ConfigMapKeyRef != nil && s.Registry != nil:
+		errs = errs.Also(apis.ErrMultipleOneOf("configMapKeyRef", "registry"))
+	case s.ConfigMapKeyRef != nil:
+		if s.ConfigMapKeyRef.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("configMapKeyRef.name"))
+		}
+		if s.ConfigMapKeyRef.Key == "" {
+			errs = errs.Also(apis.ErrMissingField("configMapKeyRef.key"))
+		}
+	case s.Registry != nil:
+		if u, err := url.Parse(s.Registry.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fe := apis.ErrInvalidValue(s.Registry.URL, "registry.url")
+			fe.Details = "the registry URL must be an absolute http or https URL"
+			errs = errs.Also(fe)
+		}
+		if s.Registry.Subject == "" {
+			errs = errs.Also(apis.ErrMissingField("registry.subject"))
+		}
+	}
 	return errs
 }
 
+func isValidTransform(t SubscriptionTransform) *apis.FieldError {
+	if t.Template == "" {
+		return apis.ErrMissingField("template")
+	}
+	if _, err := transform.Parse(t.Template); err != nil {
+		fe := apis.ErrInvalidValue(t.Template, "template")
+		fe.Details = err.Error()
+		return fe
+	}
+	return nil
+}
+
+func isValidFilter(f SubscriptionFilter) *apis.FieldError {
+	if _, err := filter.Parse(f.Expression); err != nil {
+		fe := apis.ErrInvalidValue(f.Expression, "expression")
+		fe.Details = err.Error()
+		return fe
+	}
+	return nil
+}
+
 func isSubscriberSpecNilOrEmpty(s *SubscriberSpec) bool {
 	return s == nil || equality.Semantic.DeepEqual(s, &SubscriberSpec{}) ||
-		(equality.Semantic.DeepEqual(s.Ref, &corev1.ObjectReference{}) && s.DNSName == nil)
+		((s.Ref == nil || equality.Semantic.DeepEqual(s.Ref, &corev1.ObjectReference{})) && s.DNSName == nil &&
+			s.Protocol != eventingduck.SSEDeliveryProtocol)
 
 }
 
 func isValidSubscriberSpec(s SubscriberSpec) *apis.FieldError {
 	var errs *apis.FieldError
+	if s.Protocol == eventingduck.SSEDeliveryProtocol {
+		return isValidPushSubscriberSpec(s)
+	}
 	if s.DNSName != nil && *s.DNSName != "" && s.Ref != nil && !equality.Semantic.DeepEqual(s.Ref, &corev1.ObjectReference{}) {
 		errs = errs.Also(apis.ErrMultipleOneOf("ref", "dnsName"))
 	}
@@ -82,6 +215,155 @@ func isValidSubscriberSpec(s SubscriberSpec) *apis.FieldError {
 			errs = errs.Also(fe.ViaField("ref"))
 		}
 	}
+
+	if s.Port != nil {
+		if fe := isValidSubscriberPort(s); fe != nil {
+			errs = errs.Also(fe.ViaField("port"))
+		}
+	}
+
+	if s.Path != "" {
+		if fe := isValidSubscriberPath(s); fe != nil {
+			errs = errs.Also(fe.ViaField("path"))
+		}
+	}
+
+	if s.Auth != nil {
+		if fe := isValidSubscriberAuth(*s.Auth); fe != nil {
+			errs = errs.Also(fe.ViaField("auth"))
+		}
+	}
+
+	switch s.Protocol {
+	case "", eventingduck.HTTPDeliveryProtocol, eventingduck.GRPCDeliveryProtocol, eventingduck.AMQPDeliveryProtocol, eventingduck.MQTTDeliveryProtocol:
+	default:
+		fe := apis.ErrInvalidValue(string(s.Protocol), "protocol")
+		fe.Details = fmt.Sprintf("must be %q, %q, %q, %q or %q", eventingduck.HTTPDeliveryProtocol, eventingduck.GRPCDeliveryProtocol, eventingduck.AMQPDeliveryProtocol, eventingduck.MQTTDeliveryProtocol, eventingduck.SSEDeliveryProtocol)
+		errs = errs.Also(fe)
+	}
+	return errs
+}
+
+// isValidPushSubscriberSpec checks that s, whose events are pushed to the consumers connected to
+// the dispatcher, does not reference an endpoint.
+func isValidPushSubscriberSpec(s SubscriberSpec) *apis.FieldError {
+	var disallowed []string
+	if s.Ref != nil && !equality.Semantic.DeepEqual(s.Ref, &corev1.ObjectReference{}) {
+		disallowed = append(disallowed, "ref")
+	}
+	if s.DNSName != nil {
+		disallowed = append(disallowed, "dnsName")
+	}
+	if s.Port != nil {
+		disallowed = append(disallowed, "port")
+	}
+	if s.Path != "" {
+		disallowed = append(disallowed, "path")
+	}
+	if s.Auth != nil {
+		disallowed = append(disallowed, "auth")
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+	fe := apis.ErrDisallowedFields(disallowed...)
+	fe.Details = "the consumers of SSE subscribers connect to the dispatcher"
+	return fe
+}
+
+// isHTTPSubscriberProtocol returns an error when p is set to another protocol than HTTP, for the
+// destinations only delivered to with HTTP.
+func isHTTPSubscriberProtocol(p eventingduck.DeliveryProtocol) *apis.FieldError {
+	if p == "" || p == eventingduck.HTTPDeliveryProtocol {
+		return nil
+	}
+	return apis.ErrDisallowedFields("protocol")
+}
+
+// isValidSubscriberPort checks that s.Port is only set for a Kubernetes Service, and is either a
+// valid port number or a valid port name.
+func isValidSubscriberPort(s SubscriberSpec) *apis.FieldError {
+	if s.Ref == nil || s.Ref.APIVersion != "v1" || s.Ref.Kind != "Service" {
+		fe := apis.ErrDisallowedFields(apis.CurrentField)
+		fe.Details = "port may only be set when ref is a Kubernetes Service"
+		return fe
+	}
+	var msgs []string
+	if s.Port.Type == intstr.Int {
+		msgs = validation.IsValidPortNum(s.Port.IntValue())
+	} else {
+		msgs = validation.IsValidPortName(s.Port.StrVal)
+	}
+	if len(msgs) > 0 {
+		fe := apis.ErrInvalidValue(s.Port.String(), apis.CurrentField)
+		fe.Details = strings.Join(msgs, ", ")
+		return fe
+	}
+	return nil
+}
+
+// isValidSubscriberPath checks that s.Path is an absolute URI path, and that it is not combined
+// with a DNSName.
+func isValidSubscriberPath(s SubscriberSpec) *apis.FieldError {
+	if s.DNSName != nil && *s.DNSName != "" {
+		fe := apis.ErrDisallowedFields(apis.CurrentField)
+		fe.Details = "the path of a dnsName subscriber is part of the dnsName"
+		return fe
+	}
+	u, err := url.Parse(s.Path)
+	if err != nil || !strings.HasPrefix(s.Path, "/") || u.Host != "" || u.RawQuery != "" || u.Fragment != "" {
+		fe := apis.ErrInvalidValue(s.Path, apis.CurrentField)
+		fe.Details = "path must be an absolute URI path, without a query or fragment"
+		return fe
+	}
+	return nil
+}
+
+func isValidSubscriberAuth(a eventingduck.SubscriberAuth) *apis.FieldError {
+	var errs *apis.FieldError
+	var set []string
+	if a.BearerToken != nil {
+		set = append(set, "bearerToken")
+		errs = errs.Also(isValidSecretKeySelector(*a.BearerToken).ViaField("bearerToken"))
+	}
+	if a.Basic != nil {
+		set = append(set, "basic")
+		errs = errs.Also(isValidSecretKeySelector(a.Basic.Username).ViaField("basic", "username"))
+		errs = errs.Also(isValidSecretKeySelector(a.Basic.Password).ViaField("basic", "password"))
+	}
+	if a.OIDC != nil {
+		set = append(set, "oidc")
+		if u, err := url.Parse(a.OIDC.TokenURL); a.OIDC.TokenURL == "" {
+			errs = errs.Also(apis.ErrMissingField("tokenURL").ViaField("oidc"))
+		} else if err != nil || !u.IsAbs() || (u.Scheme != "https" && u.Scheme != "http") {
+			errs = errs.Also(apis.ErrInvalidValue(a.OIDC.TokenURL, "tokenURL").ViaField("oidc"))
+		}
+		errs = errs.Also(isValidSecretKeySelector(a.OIDC.ClientID).ViaField("oidc", "clientID"))
+		errs = errs.Also(isValidSecretKeySelector(a.OIDC.ClientSecret).ViaField("oidc", "clientSecret"))
+	}
+	if a.ClientCertificate != nil && a.ClientCertificate.SecretName == "" {
+		errs = errs.Also(apis.ErrMissingField("secretName").ViaField("clientCertificate"))
+	}
+	switch len(set) {
+	case 0:
+		if a.ClientCertificate == nil {
+			errs = errs.Also(apis.ErrMissingOneOf("bearerToken", "basic", "oidc", "clientCertificate"))
+		}
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf(set...))
+	}
+	return errs
+}
+
+func isValidSecretKeySelector(s corev1.SecretKeySelector) *apis.FieldError {
+	var errs *apis.FieldError
+	if s.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+	if s.Key == "" {
+		errs = errs.Also(apis.ErrMissingField("key"))
+	}
 	return errs
 }
 
@@ -89,21 +371,12 @@ func isReplyStrategyNilOrEmpty(r *ReplyStrategy) bool {
 	return r == nil || equality.Semantic.DeepEqual(r, &ReplyStrategy{}) || equality.Semantic.DeepEqual(r.Channel, &corev1.ObjectReference{})
 }
 
+// isValidReply validates the reference to the object replies are sent to. It may be of any kind
+// that is Addressable, which is checked when the Subscription is reconciled.
 func isValidReply(r ReplyStrategy) *apis.FieldError {
 	if fe := isValidObjectReference(*r.Channel); fe != nil {
 		return fe.ViaField("channel")
 	}
-	if r.Channel.Kind != "Channel" {
-		fe := apis.ErrInvalidValue(r.Channel.Kind, "kind")
-		fe.Paths = []string{"kind"}
-		fe.Details = "only 'Channel' kind is allowed"
-		return fe
-	}
-	if r.Channel.APIVersion != "eventing.knative.dev/v1alpha1" {
-		fe := apis.ErrInvalidValue(r.Channel.APIVersion, "apiVersion")
-		fe.Details = "only eventing.knative.dev/v1alpha1 is allowed for apiVersion"
-		return fe
-	}
 	return nil
 }
 
@@ -116,8 +389,8 @@ func (current *Subscription) CheckImmutableFields(og apis.Immutable) *apis.Field
 		return nil
 	}
 
-	// Only Subscriber and Reply are mutable.
-	ignoreArguments := cmpopts.IgnoreFields(SubscriptionSpec{}, "Subscriber", "Reply")
+	// Only Subscriber, Reply, Filter, Transform, Schema, OnError and Paused are mutable.
+	ignoreArguments := cmpopts.IgnoreFields(SubscriptionSpec{}, "Subscriber", "Reply", "Filter", "Transform", "Schema", "OnError", "Paused")
 	if diff := cmp.Diff(original.Spec, current.Spec, ignoreArguments); diff != "" {
 		return &apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
@@ -19,8 +19,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	"github.com/knative/pkg/apis"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -61,6 +63,23 @@ func getValidSubscriberSpec() *SubscriberSpec {
 	}
 }
 
+func getValidK8sServiceSubscriberSpec() *SubscriberSpec {
+	return &SubscriberSpec{
+		Ref: &corev1.ObjectReference{
+			Name:       subscriberName,
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+	}
+}
+
+func getValidSecretKeySelector() *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "subscriber-credentials"},
+		Key:                  "token",
+	}
+}
+
 type DummyImmutableType struct{}
 
 func (d *DummyImmutableType) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
@@ -146,6 +165,500 @@ func TestSubscriptionSpecValidation(t *testing.T) {
 			fe.Details = "the Subscription must reference at least one of (reply or a subscriber)"
 			return fe
 		}(),
+	}, {
+		name: "valid filter",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Filter: &SubscriptionFilter{
+				Expression: "type = 'com.example.created' AND source LIKE '/orders/%'",
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid filter",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Filter: &SubscriptionFilter{
+				Expression: "type = ",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("type = ", "filter.expression")
+			fe.Details = "unexpected end of expression"
+			return fe
+		}(),
+	}, {
+		name: "valid transform",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Transform: &SubscriptionTransform{
+				Template: `{"specversion": "1.0", "type": "com.example.v2", "data": {{json .data}}}`,
+			},
+		},
+		want: nil,
+	}, {
+		name: "empty transform",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Transform:  &SubscriptionTransform{},
+		},
+		want: apis.ErrMissingField("transform.template"),
+	}, {
+		name: "invalid transform",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Transform: &SubscriptionTransform{
+				Template: "{{.type",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("{{.type", "transform.template")
+			fe.Details = "template: transform:1: unclosed action"
+			return fe
+		}(),
+	}, {
+		name: "valid onError",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			OnError:    getValidSubscriberSpec(),
+		},
+		want: nil,
+	}, {
+		name: "empty onError",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			OnError:    &SubscriberSpec{},
+		},
+		want: apis.ErrMissingField("onError.ref", "onError.dnsName"),
+	}, {
+		name: "onError with auth",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			OnError: &SubscriberSpec{
+				DNSName: &dnsName,
+				Auth: &eventingduck.SubscriberAuth{
+					BearerToken: getValidSecretKeySelector(),
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("onError.auth")
+			fe.Details = "the deliveries to the error destination are not authenticated"
+			return fe
+		}(),
+	}, {
+		name: "valid ConfigMap schema",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Schema: &SubscriptionSchema{
+				SubscriberSchema: eventingduck.SubscriberSchema{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+						Key:                  "order.json",
+					},
+				},
+				DeadLetterSink: getValidSubscriberSpec(),
+			},
+		},
+		want: nil,
+	}, {
+		name: "valid registry schema",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Schema: &SubscriptionSchema{
+				SubscriberSchema: eventingduck.SubscriberSchema{
+					Registry: &eventingduck.SchemaRegistryRef{
+						URL:     "http://schema-registry.kafka:8081",
+						Subject: "orders-value",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "empty schema",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Schema:     &SubscriptionSchema{},
+		},
+		want: apis.ErrMissingOneOf("schema.configMapKeyRef", "schema.registry"),
+	}, {
+		name: "schema with both sources",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Schema: &SubscriptionSchema{
+				SubscriberSchema: eventingduck.SubscriberSchema{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+						Key:                  "order.json",
+					},
+					Registry: &eventingduck.SchemaRegistryRef{
+						URL:     "http://schema-registry.kafka:8081",
+						Subject: "orders-value",
+					},
+				},
+			},
+		},
+		want: apis.ErrMultipleOneOf("schema.configMapKeyRef", "schema.registry"),
+	}, {
+		name: "invalid registry schema",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			Schema: &SubscriptionSchema{
+				SubscriberSchema: eventingduck.SubscriberSchema{
+					Registry: &eventingduck.SchemaRegistryRef{
+						URL: "schema-registry.kafka",
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("schema-registry.kafka", "schema.registry.url")
+			fe.Details = "the registry URL must be an absolute http or https URL"
+			return fe.Also(apis.ErrMissingField("schema.registry.subject"))
+		}(),
+	}, {
+		name: "valid bearer token auth",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					BearerToken: getValidSecretKeySelector(),
+				}
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "valid OIDC auth",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					OIDC: &eventingduck.OIDCClientCredentials{
+						TokenURL:     "https://auth.example.com/token",
+						ClientID:     *getValidSecretKeySelector(),
+						ClientSecret: *getValidSecretKeySelector(),
+					},
+				}
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "auth with multiple credentials",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					BearerToken: getValidSecretKeySelector(),
+					Basic: &eventingduck.BasicAuth{
+						Username: *getValidSecretKeySelector(),
+						Password: *getValidSecretKeySelector(),
+					},
+				}
+				return s
+			}(),
+		},
+		want: apis.ErrMultipleOneOf("subscriber.auth.bearerToken", "subscriber.auth.basic"),
+	}, {
+		name: "auth without credentials",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{}
+				return s
+			}(),
+		},
+		want: apis.ErrMissingOneOf("subscriber.auth.bearerToken", "subscriber.auth.basic", "subscriber.auth.oidc", "subscriber.auth.clientCertificate"),
+	}, {
+		name: "valid client certificate with bearer token auth",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					BearerToken:       getValidSecretKeySelector(),
+					ClientCertificate: &eventingduck.ClientCertificate{SecretName: "client-tls"},
+				}
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "client certificate without secret name",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					ClientCertificate: &eventingduck.ClientCertificate{},
+				}
+				return s
+			}(),
+		},
+		want: apis.ErrMissingField("subscriber.auth.clientCertificate.secretName"),
+	}, {
+		name: "auth with incomplete secret reference",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					BearerToken: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+					},
+				}
+				return s
+			}(),
+		},
+		want: apis.ErrMissingField("subscriber.auth.bearerToken.key"),
+	}, {
+		name: "auth with invalid OIDC token URL",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Auth = &eventingduck.SubscriberAuth{
+					OIDC: &eventingduck.OIDCClientCredentials{
+						TokenURL:     "/token",
+						ClientID:     *getValidSecretKeySelector(),
+						ClientSecret: *getValidSecretKeySelector(),
+					},
+				}
+				return s
+			}(),
+		},
+		want: apis.ErrInvalidValue("/token", "subscriber.auth.oidc.tokenURL"),
+	}, {
+		name: "auth without subscriber",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: &SubscriberSpec{
+				Auth: &eventingduck.SubscriberAuth{
+					BearerToken: getValidSecretKeySelector(),
+				},
+			},
+			Reply: getValidReplyStrategy(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("subscriber.ref", "subscriber.dnsName")
+			fe.Details = "auth requires a subscriber to authenticate to"
+			return fe
+		}(),
+	}, {
+		name: "valid gRPC protocol",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Protocol = eventingduck.GRPCDeliveryProtocol
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "valid AMQP protocol",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Protocol = eventingduck.AMQPDeliveryProtocol
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "valid MQTT protocol",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Protocol = eventingduck.MQTTDeliveryProtocol
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "invalid protocol",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Protocol = "STOMP"
+				return s
+			}(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("STOMP", "subscriber.protocol")
+			fe.Details = `must be "HTTP", "gRPC", "AMQP", "MQTT" or "SSE"`
+			return fe
+		}(),
+	}, {
+		name: "onError with gRPC protocol",
+		c: &SubscriptionSpec{
+			Channel:    getValidChannelRef(),
+			Subscriber: getValidSubscriberSpec(),
+			OnError: &SubscriberSpec{
+				DNSName:  &dnsName,
+				Protocol: eventingduck.GRPCDeliveryProtocol,
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("onError.protocol")
+			fe.Details = "events are delivered to the error destination with HTTP"
+			return fe
+		}(),
+	}, {
+		name: "valid SSE protocol",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: &SubscriberSpec{
+				Protocol: eventingduck.SSEDeliveryProtocol,
+			},
+		},
+		want: nil,
+	}, {
+		name: "SSE protocol with dnsName",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: &SubscriberSpec{
+				DNSName:  &dnsName,
+				Protocol: eventingduck.SSEDeliveryProtocol,
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("subscriber.dnsName")
+			fe.Details = "the consumers of SSE subscribers connect to the dispatcher"
+			return fe
+		}(),
+	}, {
+		name: "protocol without subscriber",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: &SubscriberSpec{
+				Protocol: eventingduck.GRPCDeliveryProtocol,
+			},
+			Reply: getValidReplyStrategy(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("subscriber.ref", "subscriber.dnsName")
+			fe.Details = "protocol requires a subscriber to deliver to"
+			return fe
+		}(),
+	}, {
+		name: "valid K8s Service port and path",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidK8sServiceSubscriberSpec()
+				s.Port = &[]intstr.IntOrString{intstr.FromInt(8443)}[0]
+				s.Path = "/events"
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "valid K8s Service named port",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidK8sServiceSubscriberSpec()
+				s.Port = &[]intstr.IntOrString{intstr.FromString("https")}[0]
+				return s
+			}(),
+		},
+		want: nil,
+	}, {
+		name: "port out of range",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidK8sServiceSubscriberSpec()
+				s.Port = &[]intstr.IntOrString{intstr.FromInt(70000)}[0]
+				return s
+			}(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("70000", "subscriber.port")
+			fe.Details = "must be between 1 and 65535, inclusive"
+			return fe
+		}(),
+	}, {
+		name: "port on a non-Service ref",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Port = &[]intstr.IntOrString{intstr.FromInt(8443)}[0]
+				return s
+			}(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("subscriber.port")
+			fe.Details = "port may only be set when ref is a Kubernetes Service"
+			return fe
+		}(),
+	}, {
+		name: "relative path",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: func() *SubscriberSpec {
+				s := getValidSubscriberSpec()
+				s.Path = "events?a=b"
+				return s
+			}(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("events?a=b", "subscriber.path")
+			fe.Details = "path must be an absolute URI path, without a query or fragment"
+			return fe
+		}(),
+	}, {
+		name: "path with dnsName",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: &SubscriberSpec{
+				DNSName: &[]string{"example.com"}[0],
+				Path:    "/events",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("subscriber.path")
+			fe.Details = "the path of a dnsName subscriber is part of the dnsName"
+			return fe
+		}(),
+	}, {
+		name: "path without subscriber",
+		c: &SubscriptionSpec{
+			Channel: getValidChannelRef(),
+			Subscriber: &SubscriberSpec{
+				Path: "/events",
+			},
+			Reply: getValidReplyStrategy(),
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("subscriber.ref")
+			fe.Details = "port and path require a subscriber ref"
+			return fe
+		}(),
 	}, {
 		name: "missing Reply",
 		c: &SubscriptionSpec{
@@ -307,6 +820,38 @@ func TestSubscriptionImmutable(t *testing.T) {
 			},
 		},
 		want: nil,
+	}, {
+		name: "valid, paused",
+		c: &Subscription{
+			Spec: SubscriptionSpec{
+				Channel:    getValidChannelRef(),
+				Subscriber: getValidSubscriberSpec(),
+				Paused:     true,
+			},
+		},
+		og: &Subscription{
+			Spec: SubscriptionSpec{
+				Channel:    getValidChannelRef(),
+				Subscriber: getValidSubscriberSpec(),
+			},
+		},
+		want: nil,
+	}, {
+		name: "valid, onError changed",
+		c: &Subscription{
+			Spec: SubscriptionSpec{
+				Channel:    getValidChannelRef(),
+				Subscriber: getValidSubscriberSpec(),
+				OnError:    getValidSubscriberSpec(),
+			},
+		},
+		og: &Subscription{
+			Spec: SubscriptionSpec{
+				Channel:    getValidChannelRef(),
+				Subscriber: getValidSubscriberSpec(),
+			},
+		},
+		want: nil,
 	}, {
 		name: "valid, have Reply, remove and replace with Subscriber",
 		c: &Subscription{
@@ -419,9 +964,8 @@ func TestValidChannel(t *testing.T) {
 			Kind: channelKind,
 		},
 		want: func() *apis.FieldError {
-			fe := apis.ErrInvalidValue("", "apiVersion")
-			fe.Details = "only eventing.knative.dev/v1alpha1 is allowed for apiVersion"
-			return apis.ErrMissingField("apiVersion").Also(fe)
+			fe := apis.ErrMissingField("apiVersion")
+			return fe
 		}(),
 	}, {
 		name: "missing kind",
@@ -430,34 +974,17 @@ func TestValidChannel(t *testing.T) {
 			APIVersion: channelAPIVersion,
 		},
 		want: func() *apis.FieldError {
-			fe := apis.ErrInvalidValue("", "kind")
-			fe.Details = "only 'Channel' kind is allowed"
-			return apis.ErrMissingField("kind").Also(fe)
-		}(),
-	}, {
-		name: "invalid kind",
-		c: corev1.ObjectReference{
-			Name:       channelName,
-			APIVersion: channelAPIVersion,
-			Kind:       "subscription",
-		},
-		want: func() *apis.FieldError {
-			fe := apis.ErrInvalidValue("subscription", "kind")
-			fe.Details = "only 'Channel' kind is allowed"
+			fe := apis.ErrMissingField("kind")
 			return fe
 		}(),
 	}, {
-		name: "invalid apiVersion",
+		name: "subscribable of another kind",
 		c: corev1.ObjectReference{
 			Name:       channelName,
-			APIVersion: "wrongapiversion",
-			Kind:       channelKind,
+			APIVersion: "messaging.example.com/v1",
+			Kind:       "Queue",
 		},
-		want: func() *apis.FieldError {
-			fe := apis.ErrInvalidValue("wrongapiversion", "apiVersion")
-			fe.Details = "only eventing.knative.dev/v1alpha1 is allowed for apiVersion"
-			return fe
-		}(),
+		want: nil,
 	}, {
 		name: "extra field, namespace",
 		c: corev1.ObjectReference{
@@ -658,33 +1185,15 @@ func TestValidReply(t *testing.T) {
 			return fe
 		}(),
 	}, {
-		name: "invalid kind",
+		name: "addressable of another kind",
 		r: ReplyStrategy{
 			Channel: &corev1.ObjectReference{
 				Name:       channelName,
-				APIVersion: channelAPIVersion,
-				Kind:       "subscription",
+				APIVersion: "serving.knative.dev/v1alpha1",
+				Kind:       "Service",
 			},
 		},
-		want: func() *apis.FieldError {
-			fe := apis.ErrInvalidValue("subscription", "kind")
-			fe.Details = "only 'Channel' kind is allowed"
-			return fe
-		}(),
-	}, {
-		name: "invalid apiVersion",
-		r: ReplyStrategy{
-			Channel: &corev1.ObjectReference{
-				Name:       channelName,
-				APIVersion: "wrongapiversion",
-				Kind:       channelKind,
-			},
-		},
-		want: func() *apis.FieldError {
-			fe := apis.ErrInvalidValue("wrongapiversion", "apiVersion")
-			fe.Details = "only eventing.knative.dev/v1alpha1 is allowed for apiVersion"
-			return fe
-		}(),
+		want: nil,
 	}, {
 		name: "extra field, namespace",
 		r: ReplyStrategy{
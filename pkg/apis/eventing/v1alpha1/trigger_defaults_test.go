@@ -0,0 +1,46 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTriggerDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		broker string
+		want   string
+	}{{
+		name: "no broker",
+		want: DefaultBrokerName,
+	}, {
+		name:   "broker",
+		broker: "foo",
+		want:   "foo",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{Spec: TriggerSpec{Broker: test.broker}}
+			tr.SetDefaults()
+			if diff := cmp.Diff(test.want, tr.Spec.Broker); diff != "" {
+				t.Errorf("unexpected broker (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Trigger delivers the events sent to a Broker that match its filter to a subscriber.
+type Trigger struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Trigger.
+	Spec TriggerSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Trigger. This data may be out of
+	// date.
+	// +optional
+	Status TriggerStatus `json:"status,omitempty"`
+}
+
+// Check that Trigger can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Trigger)(nil)
+var _ apis.Defaultable = (*Trigger)(nil)
+var _ apis.Immutable = (*Trigger)(nil)
+var _ runtime.Object = (*Trigger)(nil)
+var _ webhook.GenericCRD = (*Trigger)(nil)
+
+// TriggerSpec specifies the Broker a Trigger receives events from, which of them it is interested
+// in and where they are delivered.
+type TriggerSpec struct {
+	// Broker is the name of the Broker, in the Trigger's namespace, that the Trigger receives
+	// events from. Defaults to 'default'.
+	// +optional
+	Broker string `json:"broker,omitempty"`
+
+	// Filter selects the events that are delivered to the subscriber. If it is not specified,
+	// every event is delivered.
+	// +optional
+	Filter *TriggerFilter `json:"filter,omitempty"`
+
+	// Subscriber is the addressable that receives the events.
+	Subscriber *SubscriberSpec `json:"subscriber,omitempty"`
+
+	// Delivery specifies how events are delivered to the subscriber if it fails to accept them.
+	// If it is not specified, the delivery of the Broker is used.
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+}
+
+// TriggerFilter selects events by their context attributes.
+type TriggerFilter struct {
+	// Attributes maps context attribute names, such as 'type' and 'source', to the value the
+	// attribute must have. An event matches if it has every listed attribute with exactly the
+	// listed value.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+var triggerCondSet = duckv1alpha1.NewLivingConditionSet(TriggerConditionBrokerExists, TriggerConditionSubscribed)
+
+// TriggerStatus represents the current state of a Trigger.
+type TriggerStatus struct {
+	// ObservedGeneration is the most recent generation observed for this Trigger.
+	// It corresponds to the Trigger's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SubscriberURI is the resolved URI of the Trigger's subscriber.
+	// +optional
+	SubscriberURI string `json:"subscriberURI,omitempty"`
+
+	// DeadLetterSinkURI is the resolved URI of the dead-letter sink that applies to the Trigger.
+	// +optional
+	DeadLetterSinkURI string `json:"deadLetterSinkURI,omitempty"`
+
+	// Represents the latest available observations of a trigger's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// TriggerConditionReady has status True when events sent to the Broker
+	// are delivered to the Trigger's subscriber.
+	TriggerConditionReady = duckv1alpha1.ConditionReady
+
+	// TriggerConditionBrokerExists has status True when the Trigger's Broker
+	// exists.
+	TriggerConditionBrokerExists duckv1alpha1.ConditionType = "BrokerExists"
+
+	// TriggerConditionSubscribed has status True when the Trigger's subscriber
+	// has been resolved and the Trigger is subscribed to the Broker's Channel.
+	TriggerConditionSubscribed duckv1alpha1.ConditionType = "Subscribed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ts *TriggerStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return triggerCondSet.Manage(ts).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ts *TriggerStatus) IsReady() bool {
+	return triggerCondSet.Manage(ts).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ts *TriggerStatus) InitializeConditions() {
+	triggerCondSet.Manage(ts).InitializeConditions()
+}
+
+// MarkBrokerExists sets TriggerConditionBrokerExists condition to True state.
+func (ts *TriggerStatus) MarkBrokerExists() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionBrokerExists)
+}
+
+// MarkBrokerDoesNotExist sets TriggerConditionBrokerExists condition to False state.
+func (ts *TriggerStatus) MarkBrokerDoesNotExist(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionBrokerExists, reason, messageFormat, messageA...)
+}
+
+// MarkSubscribed sets TriggerConditionSubscribed condition to True state.
+func (ts *TriggerStatus) MarkSubscribed() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionSubscribed)
+}
+
+// MarkNotSubscribed sets TriggerConditionSubscribed condition to False state.
+func (ts *TriggerStatus) MarkNotSubscribed(reason, messageFormat string, messageA ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionSubscribed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TriggerList is a collection of Triggers.
+type TriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Trigger `json:"items"`
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTriggerInitializeConditions(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	want := &TriggerStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   TriggerConditionBrokerExists,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   TriggerConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   TriggerConditionSubscribed,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, ts, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestTriggerIsReady(t *testing.T) {
+	tests := []struct {
+		name           string
+		markBroker     bool
+		markSubscribed bool
+		wantReady      bool
+	}{{
+		name:           "all happy",
+		markBroker:     true,
+		markSubscribed: true,
+		wantReady:      true,
+	}, {
+		name:           "broker sad",
+		markBroker:     false,
+		markSubscribed: true,
+		wantReady:      false,
+	}, {
+		name:           "subscribed sad",
+		markBroker:     true,
+		markSubscribed: false,
+		wantReady:      false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts := &TriggerStatus{}
+			ts.InitializeConditions()
+			if test.markBroker {
+				ts.MarkBrokerExists()
+			} else {
+				ts.MarkBrokerDoesNotExist("DoesNotExist", "testing")
+			}
+			if test.markSubscribed {
+				ts.MarkSubscribed()
+			} else {
+				ts.MarkNotSubscribed("NotSubscribed", "testing")
+			}
+			if got := ts.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
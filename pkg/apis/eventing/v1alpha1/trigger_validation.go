@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"github.com/knative/eventing/pkg/filter"
+	"github.com/knative/pkg/apis"
+)
+
+func (t *Trigger) Validate() *apis.FieldError {
+	return t.Spec.Validate().ViaField("spec")
+}
+
+func (ts *TriggerSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ts.Broker == "" {
+		errs = errs.Also(apis.ErrMissingField("broker"))
+	}
+
+	if ts.Filter != nil {
+		for name, value := range ts.Filter.Attributes {
+			if !filter.IsValidAttributeName(name) {
+				fe := apis.ErrInvalidValue(name, "attributes")
+				fe.Details = "attribute names must consist of lower-case letters and digits"
+				errs = errs.Also(fe.ViaField("filter"))
+			} else if value == "" {
+				errs = errs.Also(apis.ErrMissingField(name).ViaField("attributes").ViaField("filter"))
+			}
+		}
+	}
+
+	if isSubscriberSpecNilOrEmpty(ts.Subscriber) {
+		fe := apis.ErrMissingField("subscriber")
+		fe.Details = "the Trigger must reference a subscriber"
+		errs = errs.Also(fe)
+	} else if fe := isValidBrokerSubscriberSpec(ts.Subscriber); fe != nil {
+		errs = errs.Also(fe.ViaField("subscriber"))
+	}
+
+	if ts.Delivery != nil {
+		if fe := ts.Delivery.Validate(); fe != nil {
+			errs = errs.Also(fe.ViaField("delivery"))
+		}
+	}
+
+	return errs
+}
+
+func (current *Trigger) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*Trigger)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a Trigger"}
+	}
+	if original.Spec.Broker != current.Spec.Broker {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.broker"},
+		}
+	}
+	return nil
+}
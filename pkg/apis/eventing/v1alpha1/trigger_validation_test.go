@@ -0,0 +1,192 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestTriggerValidation(t *testing.T) {
+	targetURI := "http://example.com"
+	subscriber := &SubscriberSpec{DNSName: &targetURI}
+	tests := []CRDTest{{
+		name: "valid",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker: "default",
+				Filter: &TriggerFilter{
+					Attributes: map[string]string{
+						"type":   "dev.knative.foo",
+						"source": "bar",
+					},
+				},
+				Subscriber: subscriber,
+			},
+		},
+		want: nil,
+	}, {
+		name: "no filter",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker:     "default",
+				Subscriber: subscriber,
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing broker",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Subscriber: subscriber,
+			},
+		},
+		want: apis.ErrMissingField("spec.broker"),
+	}, {
+		name: "missing subscriber",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker: "default",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.subscriber")
+			fe.Details = "the Trigger must reference a subscriber"
+			return fe
+		}(),
+	}, {
+		name: "invalid attribute name",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker: "default",
+				Filter: &TriggerFilter{
+					Attributes: map[string]string{
+						"Type": "dev.knative.foo",
+					},
+				},
+				Subscriber: subscriber,
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("Type", "spec.filter.attributes")
+			fe.Details = "attribute names must consist of lower-case letters and digits"
+			return fe
+		}(),
+	}, {
+		name: "empty attribute value",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker: "default",
+				Filter: &TriggerFilter{
+					Attributes: map[string]string{
+						"type": "",
+					},
+				},
+				Subscriber: subscriber,
+			},
+		},
+		want: apis.ErrMissingField("spec.filter.attributes.type"),
+	}, {
+		name: "subscriber auth",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker: "default",
+				Subscriber: &SubscriberSpec{
+					DNSName: &targetURI,
+					Auth: &eventingduck.SubscriberAuth{
+						BearerToken: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "token"},
+							Key:                  "token",
+						},
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("spec.subscriber.auth")
+			fe.Details = "the Broker filter does not authenticate to subscribers"
+			return fe
+		}(),
+	}, {
+		name: "invalid delivery",
+		cr: &Trigger{
+			Spec: TriggerSpec{
+				Broker:     "default",
+				Subscriber: subscriber,
+				Delivery: &DeliverySpec{
+					BackoffDelay: "soon",
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("soon", "spec.delivery.backoffDelay")
+			fe.Details = "must be a positive duration, such as 500ms or 2s"
+			return fe
+		}(),
+	}}
+
+	doValidateTest(t, tests)
+}
+
+func TestTriggerImmutableFields(t *testing.T) {
+	tests := []struct {
+		name string
+		new  apis.Immutable
+		old  apis.Immutable
+		want *apis.FieldError
+	}{{
+		name: "good (new)",
+		new:  &Trigger{Spec: TriggerSpec{Broker: "foo"}},
+		old:  nil,
+		want: nil,
+	}, {
+		name: "good (no broker change)",
+		new: &Trigger{Spec: TriggerSpec{
+			Broker: "foo",
+			Filter: &TriggerFilter{Attributes: map[string]string{"type": "bar"}},
+		}},
+		old:  &Trigger{Spec: TriggerSpec{Broker: "foo"}},
+		want: nil,
+	}, {
+		name: "bad (broker change)",
+		new:  &Trigger{Spec: TriggerSpec{Broker: "foo"}},
+		old:  &Trigger{Spec: TriggerSpec{Broker: "bar"}},
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.broker"},
+		},
+	}, {
+		name: "bad (type)",
+		new:  &Trigger{},
+		old:  &Channel{},
+		want: &apis.FieldError{
+			Message: "The provided resource was not a Trigger",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckImmutableFields (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
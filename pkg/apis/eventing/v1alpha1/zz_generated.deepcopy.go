@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -25,10 +26,11 @@ import (
 	apis_duck_v1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Channel) DeepCopyInto(out *Channel) {
+func (in *Broker) DeepCopyInto(out *Broker) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -37,18 +39,18 @@ func (in *Channel) DeepCopyInto(out *Channel) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Channel.
-func (in *Channel) DeepCopy() *Channel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broker.
+func (in *Broker) DeepCopy() *Broker {
 	if in == nil {
 		return nil
 	}
-	out := new(Channel)
+	out := new(Broker)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Channel) DeepCopyObject() runtime.Object {
+func (in *Broker) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -56,13 +58,13 @@ func (in *Channel) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelList) DeepCopyInto(out *ChannelList) {
+func (in *BrokerList) DeepCopyInto(out *BrokerList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Channel, len(*in))
+		*out = make([]Broker, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -70,18 +72,18 @@ func (in *ChannelList) DeepCopyInto(out *ChannelList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelList.
-func (in *ChannelList) DeepCopy() *ChannelList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerList.
+func (in *BrokerList) DeepCopy() *BrokerList {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelList)
+	out := new(BrokerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ChannelList) DeepCopyObject() runtime.Object {
+func (in *BrokerList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -89,50 +91,41 @@ func (in *ChannelList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
+func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
 	*out = *in
-	if in.Provisioner != nil {
-		in, out := &in.Provisioner, &out.Provisioner
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(v1.ObjectReference)
-			**out = **in
-		}
-	}
-	if in.Arguments != nil {
-		in, out := &in.Arguments, &out.Arguments
+	if in.ChannelTemplate != nil {
+		in, out := &in.ChannelTemplate, &out.ChannelTemplate
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(runtime.RawExtension)
+			*out = new(ChannelSpec)
 			(*in).DeepCopyInto(*out)
 		}
 	}
-	if in.Subscribable != nil {
-		in, out := &in.Subscribable, &out.Subscribable
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(duck_v1alpha1.Subscribable)
+			*out = new(DeliverySpec)
 			(*in).DeepCopyInto(*out)
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
-func (in *ChannelSpec) DeepCopy() *ChannelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSpec.
+func (in *BrokerSpec) DeepCopy() *BrokerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelSpec)
+	out := new(BrokerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
+func (in *BrokerStatus) DeepCopyInto(out *BrokerStatus) {
 	*out = *in
 	out.Address = in.Address
 	if in.Conditions != nil {
@@ -145,38 +138,38 @@ func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelStatus.
-func (in *ChannelStatus) DeepCopy() *ChannelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerStatus.
+func (in *BrokerStatus) DeepCopy() *BrokerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelStatus)
+	out := new(BrokerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterChannelProvisioner) DeepCopyInto(out *ClusterChannelProvisioner) {
+func (in *Channel) DeepCopyInto(out *Channel) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisioner.
-func (in *ClusterChannelProvisioner) DeepCopy() *ClusterChannelProvisioner {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Channel.
+func (in *Channel) DeepCopy() *Channel {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterChannelProvisioner)
+	out := new(Channel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterChannelProvisioner) DeepCopyObject() runtime.Object {
+func (in *Channel) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -184,13 +177,13 @@ func (in *ClusterChannelProvisioner) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterChannelProvisionerList) DeepCopyInto(out *ClusterChannelProvisionerList) {
+func (in *ChannelList) DeepCopyInto(out *ChannelList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterChannelProvisioner, len(*in))
+		*out = make([]Channel, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -198,18 +191,18 @@ func (in *ClusterChannelProvisionerList) DeepCopyInto(out *ClusterChannelProvisi
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerList.
-func (in *ClusterChannelProvisionerList) DeepCopy() *ClusterChannelProvisionerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelList.
+func (in *ChannelList) DeepCopy() *ChannelList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterChannelProvisionerList)
+	out := new(ChannelList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterChannelProvisionerList) DeepCopyObject() runtime.Object {
+func (in *ChannelList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -217,27 +210,41 @@ func (in *ClusterChannelProvisionerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterChannelProvisionerSpec) DeepCopyInto(out *ClusterChannelProvisionerSpec) {
+func (in *ChannelMigration) DeepCopyInto(out *ChannelMigration) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerSpec.
-func (in *ClusterChannelProvisionerSpec) DeepCopy() *ClusterChannelProvisionerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMigration.
+func (in *ChannelMigration) DeepCopy() *ChannelMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterChannelProvisionerSpec)
+	out := new(ChannelMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterChannelProvisionerStatus) DeepCopyInto(out *ClusterChannelProvisionerStatus) {
+func (in *ChannelMigrationList) DeepCopyInto(out *ChannelMigrationList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelMigration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -245,21 +252,29 @@ func (in *ClusterChannelProvisionerStatus) DeepCopyInto(out *ClusterChannelProvi
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerStatus.
-func (in *ClusterChannelProvisionerStatus) DeepCopy() *ClusterChannelProvisionerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMigrationList.
+func (in *ChannelMigrationList) DeepCopy() *ChannelMigrationList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterChannelProvisionerStatus)
+	out := new(ChannelMigrationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplyStrategy) DeepCopyInto(out *ReplyStrategy) {
+func (in *ChannelMigrationSpec) DeepCopyInto(out *ChannelMigrationSpec) {
 	*out = *in
-	if in.Channel != nil {
-		in, out := &in.Channel, &out.Channel
+	if in.Provisioner != nil {
+		in, out := &in.Provisioner, &out.Provisioner
 		if *in == nil {
 			*out = nil
 		} else {
@@ -267,24 +282,62 @@ func (in *ReplyStrategy) DeepCopyInto(out *ReplyStrategy) {
 			**out = **in
 		}
 	}
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(runtime.RawExtension)
+			(*in).DeepCopyInto(*out)
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplyStrategy.
-func (in *ReplyStrategy) DeepCopy() *ReplyStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMigrationSpec.
+func (in *ChannelMigrationSpec) DeepCopy() *ChannelMigrationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplyStrategy)
+	out := new(ChannelMigrationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubscriberSpec) DeepCopyInto(out *SubscriberSpec) {
+func (in *ChannelMigrationStatus) DeepCopyInto(out *ChannelMigrationStatus) {
 	*out = *in
-	if in.Ref != nil {
-		in, out := &in.Ref, &out.Ref
+	out.Address = in.Address
+	if in.MovedSubscriptions != nil {
+		in, out := &in.MovedSubscriptions, &out.MovedSubscriptions
+		*out = make([]MovedSubscription, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMigrationStatus.
+func (in *ChannelMigrationStatus) DeepCopy() *ChannelMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
+	*out = *in
+	if in.Provisioner != nil {
+		in, out := &in.Provisioner, &out.Provisioner
 		if *in == nil {
 			*out = nil
 		} else {
@@ -292,83 +345,83 @@ func (in *SubscriberSpec) DeepCopyInto(out *SubscriberSpec) {
 			**out = **in
 		}
 	}
-	if in.DNSName != nil {
-		in, out := &in.DNSName, &out.DNSName
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(string)
-			**out = **in
+			*out = new(runtime.RawExtension)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Subscribable != nil {
+		in, out := &in.Subscribable, &out.Subscribable
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(duck_v1alpha1.Subscribable)
+			(*in).DeepCopyInto(*out)
 		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberSpec.
-func (in *SubscriberSpec) DeepCopy() *SubscriberSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
+func (in *ChannelSpec) DeepCopy() *ChannelSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SubscriberSpec)
+	out := new(ChannelSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Subscription) DeepCopyInto(out *Subscription) {
+func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Address = in.Address
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
-func (in *Subscription) DeepCopy() *Subscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelStatus.
+func (in *ChannelStatus) DeepCopy() *ChannelStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Subscription)
+	out := new(ChannelStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Subscription) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubscriptionList) DeepCopyInto(out *SubscriptionList) {
+func (in *ClusterChannelProvisioner) DeepCopyInto(out *ClusterChannelProvisioner) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Subscription, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionList.
-func (in *SubscriptionList) DeepCopy() *SubscriptionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisioner.
+func (in *ClusterChannelProvisioner) DeepCopy() *ClusterChannelProvisioner {
 	if in == nil {
 		return nil
 	}
-	out := new(SubscriptionList)
+	out := new(ClusterChannelProvisioner)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SubscriptionList) DeepCopyObject() runtime.Object {
+func (in *ClusterChannelProvisioner) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -376,72 +429,882 @@ func (in *SubscriptionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
+func (in *ClusterChannelProvisionerCapabilities) DeepCopyInto(out *ClusterChannelProvisionerCapabilities) {
 	*out = *in
-	out.Channel = in.Channel
-	if in.Subscriber != nil {
-		in, out := &in.Subscriber, &out.Subscriber
+	if in.MaxEventSize != nil {
+		in, out := &in.MaxEventSize, &out.MaxEventSize
 		if *in == nil {
 			*out = nil
 		} else {
-			*out = new(SubscriberSpec)
-			(*in).DeepCopyInto(*out)
+			*out = new(int64)
+			**out = **in
 		}
 	}
-	if in.Reply != nil {
-		in, out := &in.Reply, &out.Reply
-		if *in == nil {
-			*out = nil
-		} else {
-			*out = new(ReplyStrategy)
-			(*in).DeepCopyInto(*out)
-		}
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
-func (in *SubscriptionSpec) DeepCopy() *SubscriptionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerCapabilities.
+func (in *ClusterChannelProvisionerCapabilities) DeepCopy() *ClusterChannelProvisionerCapabilities {
 	if in == nil {
 		return nil
 	}
-	out := new(SubscriptionSpec)
+	out := new(ClusterChannelProvisionerCapabilities)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
+func (in *ClusterChannelProvisionerList) DeepCopyInto(out *ClusterChannelProvisionerList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterChannelProvisioner, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.PhysicalSubscription = in.PhysicalSubscription
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
-func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerList.
+func (in *ClusterChannelProvisionerList) DeepCopy() *ClusterChannelProvisionerList {
 	if in == nil {
 		return nil
 	}
-	out := new(SubscriptionStatus)
+	out := new(ClusterChannelProvisionerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterChannelProvisionerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubscriptionStatusPhysicalSubscription) DeepCopyInto(out *SubscriptionStatusPhysicalSubscription) {
+func (in *ClusterChannelProvisionerSpec) DeepCopyInto(out *ClusterChannelProvisionerSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatusPhysicalSubscription.
-func (in *SubscriptionStatusPhysicalSubscription) DeepCopy() *SubscriptionStatusPhysicalSubscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerSpec.
+func (in *ClusterChannelProvisionerSpec) DeepCopy() *ClusterChannelProvisionerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterChannelProvisionerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterChannelProvisionerStatus) DeepCopyInto(out *ClusterChannelProvisionerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ArgumentsSchema != nil {
+		in, out := &in.ArgumentsSchema, &out.ArgumentsSchema
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(runtime.RawExtension)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ClusterChannelProvisionerCapabilities)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelProvisionerStatus.
+func (in *ClusterChannelProvisionerStatus) DeepCopy() *ClusterChannelProvisionerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterChannelProvisionerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliverySpec) DeepCopyInto(out *DeliverySpec) {
+	*out = *in
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.PermanentStatusCodes != nil {
+		in, out := &in.PermanentStatusCodes, &out.PermanentStatusCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliverySpec.
+func (in *DeliverySpec) DeepCopy() *DeliverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventType) DeepCopyInto(out *EventType) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventType.
+func (in *EventType) DeepCopy() *EventType {
+	if in == nil {
+		return nil
+	}
+	out := new(EventType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventType) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTypeList) DeepCopyInto(out *EventTypeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EventType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTypeList.
+func (in *EventTypeList) DeepCopy() *EventTypeList {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTypeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventTypeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTypeSpec) DeepCopyInto(out *EventTypeSpec) {
+	*out = *in
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(duck_v1alpha1.SubscriberSchema)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTypeSpec.
+func (in *EventTypeSpec) DeepCopy() *EventTypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MovedSubscription) DeepCopyInto(out *MovedSubscription) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MovedSubscription.
+func (in *MovedSubscription) DeepCopy() *MovedSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(MovedSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Parallel) DeepCopyInto(out *Parallel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parallel.
+func (in *Parallel) DeepCopy() *Parallel {
+	if in == nil {
+		return nil
+	}
+	out := new(Parallel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Parallel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParallelBranch) DeepCopyInto(out *ParallelBranch) {
+	*out = *in
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriptionFilter)
+			**out = **in
+		}
+	}
+	in.Subscriber.DeepCopyInto(&out.Subscriber)
+	if in.Reply != nil {
+		in, out := &in.Reply, &out.Reply
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ReplyStrategy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.OnError != nil {
+		in, out := &in.OnError, &out.OnError
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParallelBranch.
+func (in *ParallelBranch) DeepCopy() *ParallelBranch {
+	if in == nil {
+		return nil
+	}
+	out := new(ParallelBranch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParallelBranchStatus) DeepCopyInto(out *ParallelBranchStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParallelBranchStatus.
+func (in *ParallelBranchStatus) DeepCopy() *ParallelBranchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ParallelBranchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParallelList) DeepCopyInto(out *ParallelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Parallel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParallelList.
+func (in *ParallelList) DeepCopy() *ParallelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ParallelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ParallelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParallelSpec) DeepCopyInto(out *ParallelSpec) {
+	*out = *in
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]ParallelBranch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ChannelTemplate != nil {
+		in, out := &in.ChannelTemplate, &out.ChannelTemplate
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ChannelSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Reply != nil {
+		in, out := &in.Reply, &out.Reply
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ReplyStrategy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParallelSpec.
+func (in *ParallelSpec) DeepCopy() *ParallelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ParallelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParallelStatus) DeepCopyInto(out *ParallelStatus) {
+	*out = *in
+	out.Address = in.Address
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]ParallelBranchStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParallelStatus.
+func (in *ParallelStatus) DeepCopy() *ParallelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ParallelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplyStrategy) DeepCopyInto(out *ReplyStrategy) {
+	*out = *in
+	if in.Channel != nil {
+		in, out := &in.Channel, &out.Channel
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ObjectReference)
+			**out = **in
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplyStrategy.
+func (in *ReplyStrategy) DeepCopy() *ReplyStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplyStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sequence) DeepCopyInto(out *Sequence) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sequence.
+func (in *Sequence) DeepCopy() *Sequence {
+	if in == nil {
+		return nil
+	}
+	out := new(Sequence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Sequence) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SequenceList) DeepCopyInto(out *SequenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Sequence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SequenceList.
+func (in *SequenceList) DeepCopy() *SequenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(SequenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SequenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SequenceSpec) DeepCopyInto(out *SequenceSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]SequenceStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ChannelTemplate != nil {
+		in, out := &in.ChannelTemplate, &out.ChannelTemplate
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ChannelSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Reply != nil {
+		in, out := &in.Reply, &out.Reply
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ReplyStrategy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SequenceSpec.
+func (in *SequenceSpec) DeepCopy() *SequenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SequenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SequenceStatus) DeepCopyInto(out *SequenceStatus) {
+	*out = *in
+	out.Address = in.Address
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SequenceStatus.
+func (in *SequenceStatus) DeepCopy() *SequenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SequenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SequenceStep) DeepCopyInto(out *SequenceStep) {
+	*out = *in
+	in.SubscriberSpec.DeepCopyInto(&out.SubscriberSpec)
+	if in.OnError != nil {
+		in, out := &in.OnError, &out.OnError
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SequenceStep.
+func (in *SequenceStep) DeepCopy() *SequenceStep {
+	if in == nil {
+		return nil
+	}
+	out := new(SequenceStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriberSpec) DeepCopyInto(out *SubscriberSpec) {
+	*out = *in
+	if in.Ref != nil {
+		in, out := &in.Ref, &out.Ref
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(v1.ObjectReference)
+			**out = **in
+		}
+	}
+	if in.DNSName != nil {
+		in, out := &in.DNSName, &out.DNSName
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(string)
+			**out = **in
+		}
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(intstr.IntOrString)
+			**out = **in
+		}
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(duck_v1alpha1.SubscriberAuth)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberSpec.
+func (in *SubscriberSpec) DeepCopy() *SubscriberSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriberSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subscription) DeepCopyInto(out *Subscription) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
+func (in *Subscription) DeepCopy() *Subscription {
+	if in == nil {
+		return nil
+	}
+	out := new(Subscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Subscription) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionFilter) DeepCopyInto(out *SubscriptionFilter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionFilter.
+func (in *SubscriptionFilter) DeepCopy() *SubscriptionFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionList) DeepCopyInto(out *SubscriptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Subscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionList.
+func (in *SubscriptionList) DeepCopy() *SubscriptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionSchema) DeepCopyInto(out *SubscriptionSchema) {
+	*out = *in
+	in.SubscriberSchema.DeepCopyInto(&out.SubscriberSchema)
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSchema.
+func (in *SubscriptionSchema) DeepCopy() *SubscriptionSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
+	*out = *in
+	out.Channel = in.Channel
+	if in.Subscriber != nil {
+		in, out := &in.Subscriber, &out.Subscriber
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Reply != nil {
+		in, out := &in.Reply, &out.Reply
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(ReplyStrategy)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriptionFilter)
+			**out = **in
+		}
+	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriptionTransform)
+			**out = **in
+		}
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriptionSchema)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.OnError != nil {
+		in, out := &in.OnError, &out.OnError
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
+func (in *SubscriptionSpec) DeepCopy() *SubscriptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.PhysicalSubscription = in.PhysicalSubscription
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
+func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionStatusPhysicalSubscription) DeepCopyInto(out *SubscriptionStatusPhysicalSubscription) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatusPhysicalSubscription.
+func (in *SubscriptionStatusPhysicalSubscription) DeepCopy() *SubscriptionStatusPhysicalSubscription {
 	if in == nil {
 		return nil
 	}
@@ -449,3 +1312,169 @@ func (in *SubscriptionStatusPhysicalSubscription) DeepCopy() *SubscriptionStatus
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTransform) DeepCopyInto(out *SubscriptionTransform) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTransform.
+func (in *SubscriptionTransform) DeepCopy() *SubscriptionTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Trigger) DeepCopyInto(out *Trigger) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Trigger.
+func (in *Trigger) DeepCopy() *Trigger {
+	if in == nil {
+		return nil
+	}
+	out := new(Trigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Trigger) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerFilter) DeepCopyInto(out *TriggerFilter) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerFilter.
+func (in *TriggerFilter) DeepCopy() *TriggerFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerList) DeepCopyInto(out *TriggerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Trigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerList.
+func (in *TriggerList) DeepCopy() *TriggerList {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TriggerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerSpec) DeepCopyInto(out *TriggerSpec) {
+	*out = *in
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(TriggerFilter)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Subscriber != nil {
+		in, out := &in.Subscriber, &out.Subscriber
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(SubscriberSpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		if *in == nil {
+			*out = nil
+		} else {
+			*out = new(DeliverySpec)
+			(*in).DeepCopyInto(*out)
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerSpec.
+func (in *TriggerSpec) DeepCopy() *TriggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerStatus) DeepCopyInto(out *TriggerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apis_duck_v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerStatus.
+func (in *TriggerStatus) DeepCopy() *TriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// ConvertFrom sets c to the v1beta1 version of the v1alpha1 Channel source.
+func (c *Channel) ConvertFrom(source *v1alpha1.Channel) error {
+	c.ObjectMeta = source.ObjectMeta
+	c.Spec = ChannelSpec{
+		Generation:   source.Spec.Generation,
+		Provisioner:  source.Spec.Provisioner,
+		Arguments:    source.Spec.Arguments,
+		Subscribable: source.Spec.Subscribable,
+	}
+	c.Status = ChannelStatus{
+		ObservedGeneration: source.Status.ObservedGeneration,
+		Conditions:         source.Status.Conditions,
+	}
+	if source.Status.Address.Hostname != "" {
+		c.Status.Address.URL = (&url.URL{Scheme: "http", Host: source.Status.Address.Hostname}).String()
+	}
+	return nil
+}
+
+// ConvertTo sets sink to the v1alpha1 version of c.
+func (c *Channel) ConvertTo(sink *v1alpha1.Channel) error {
+	sink.ObjectMeta = c.ObjectMeta
+	sink.Spec = v1alpha1.ChannelSpec{
+		Generation:   c.Spec.Generation,
+		Provisioner:  c.Spec.Provisioner,
+		Arguments:    c.Spec.Arguments,
+		Subscribable: c.Spec.Subscribable,
+	}
+	sink.Status = v1alpha1.ChannelStatus{
+		ObservedGeneration: c.Status.ObservedGeneration,
+		Conditions:         c.Status.Conditions,
+	}
+	if c.Status.Address.URL != "" {
+		u, err := url.Parse(c.Status.Address.URL)
+		if err != nil {
+			return fmt.Errorf("invalid status.address.url: %v", err)
+		}
+		sink.Status.Address.Hostname = u.Host
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestChannelConversion(t *testing.T) {
+	alpha := &v1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo",
+		},
+		Spec: v1alpha1.ChannelSpec{
+			Generation: 2,
+			Provisioner: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "ClusterChannelProvisioner",
+				Name:       "in-memory-channel",
+			},
+			Arguments: &runtime.RawExtension{Raw: []byte(`{"foo":"bar"}`)},
+			Subscribable: &eventingduck.Subscribable{
+				Subscribers: []eventingduck.ChannelSubscriberSpec{{
+					SubscriberURI: "http://subscriber.default.svc.cluster.local/",
+				}},
+			},
+		},
+		Status: v1alpha1.ChannelStatus{
+			ObservedGeneration: 2,
+			Address: duckv1alpha1.Addressable{
+				Hostname: "foo-channel.default.svc.cluster.local",
+			},
+			Conditions: duckv1alpha1.Conditions{{
+				Type:   v1alpha1.ChannelConditionReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+
+	beta := &Channel{}
+	if err := beta.ConvertFrom(alpha); err != nil {
+		t.Fatalf("Unexpected error converting from v1alpha1: %v", err)
+	}
+	if want := "http://foo-channel.default.svc.cluster.local"; beta.Status.Address.URL != want {
+		t.Errorf("Unexpected status.address.url. Expected %q, got %q", want, beta.Status.Address.URL)
+	}
+
+	got := &v1alpha1.Channel{}
+	if err := beta.ConvertTo(got); err != nil {
+		t.Fatalf("Unexpected error converting to v1alpha1: %v", err)
+	}
+	if diff := cmp.Diff(alpha, got); diff != "" {
+		t.Errorf("Unexpected round trip (-want +got): %s", diff)
+	}
+}
+
+func TestChannelConversionInvalidURL(t *testing.T) {
+	beta := &Channel{
+		Status: ChannelStatus{
+			Address: Addressable{URL: "http://%zz"},
+		},
+	}
+	if err := beta.ConvertTo(&v1alpha1.Channel{}); err == nil {
+		t.Error("Expected an error converting an invalid URL, got nil")
+	}
+}
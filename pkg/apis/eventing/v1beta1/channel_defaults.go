@@ -0,0 +1,39 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// SetDefaults defaults c as its v1alpha1 version, so that both versions share the same defaults.
+func (c *Channel) SetDefaults() {
+	alpha := c.withoutStatus()
+	alpha.SetDefaults()
+	defaulted := &Channel{}
+	defaulted.ConvertFrom(alpha)
+	c.Spec = defaulted.Spec
+}
+
+// withoutStatus returns the v1alpha1 version of c without its status. The conversion of the spec
+// cannot fail.
+func (c *Channel) withoutStatus() *v1alpha1.Channel {
+	alpha := &v1alpha1.Channel{}
+	spec := &Channel{ObjectMeta: c.ObjectMeta, Spec: c.Spec}
+	spec.DeepCopy().ConvertTo(alpha)
+	return alpha
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/knative/pkg/apis"
+)
+
+// Validate validates c as its v1alpha1 version, so that both versions accept the same Channels.
+func (c *Channel) Validate() *apis.FieldError {
+	return c.withoutStatus().Validate()
+}
+
+// CheckImmutableFields checks the immutable fields of c as its v1alpha1 version.
+func (c *Channel) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*Channel)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a Channel"}
+	}
+	return c.withoutStatus().CheckImmutableFields(original.withoutStatus())
+}
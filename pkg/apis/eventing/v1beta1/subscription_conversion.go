@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// ConvertFrom sets s to the v1beta1 version of the v1alpha1 Subscription source. The dead letter
+// sink of the schema and the error destination move to the delivery spec.
+func (s *Subscription) ConvertFrom(source *v1alpha1.Subscription) error {
+	s.ObjectMeta = source.ObjectMeta
+	s.Spec = SubscriptionSpec{
+		Generation: source.Spec.Generation,
+		Channel:    source.Spec.Channel,
+		Subscriber: destinationFrom(source.Spec.Subscriber),
+		Filter:     source.Spec.Filter,
+		Transform:  source.Spec.Transform,
+		Paused:     source.Spec.Paused,
+	}
+	if source.Spec.Reply != nil {
+		s.Spec.Reply = source.Spec.Reply.Channel
+	}
+	if source.Spec.Schema != nil {
+		schema := source.Spec.Schema.SubscriberSchema
+		s.Spec.Schema = &schema
+		if source.Spec.Schema.DeadLetterSink != nil {
+			s.Spec.Delivery = &DeliverySpec{
+				DeadLetterSink: destinationFrom(source.Spec.Schema.DeadLetterSink),
+			}
+		}
+	}
+	if source.Spec.OnError != nil {
+		if s.Spec.Delivery == nil {
+			s.Spec.Delivery = &DeliverySpec{}
+		}
+		s.Spec.Delivery.OnError = destinationFrom(source.Spec.OnError)
+	}
+	s.Status = SubscriptionStatus{
+		Conditions:           source.Status.Conditions,
+		PhysicalSubscription: source.Status.PhysicalSubscription,
+	}
+	return nil
+}
+
+// ConvertTo sets sink to the v1alpha1 version of s.
+func (s *Subscription) ConvertTo(sink *v1alpha1.Subscription) error {
+	sink.ObjectMeta = s.ObjectMeta
+	sink.Spec = v1alpha1.SubscriptionSpec{
+		Generation: s.Spec.Generation,
+		Channel:    s.Spec.Channel,
+		Subscriber: s.Spec.Subscriber.subscriberSpec(),
+		Filter:     s.Spec.Filter,
+		Transform:  s.Spec.Transform,
+		Paused:     s.Spec.Paused,
+	}
+	if s.Spec.Reply != nil {
+		sink.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: s.Spec.Reply}
+	}
+	var deadLetterSink *Destination
+	if s.Spec.Delivery != nil {
+		deadLetterSink = s.Spec.Delivery.DeadLetterSink
+		sink.Spec.OnError = s.Spec.Delivery.OnError.subscriberSpec()
+	}
+	if s.Spec.Schema != nil || deadLetterSink != nil {
+		sink.Spec.Schema = &v1alpha1.SubscriptionSchema{
+			DeadLetterSink: deadLetterSink.subscriberSpec(),
+		}
+		if s.Spec.Schema != nil {
+			sink.Spec.Schema.SubscriberSchema = *s.Spec.Schema
+		}
+	}
+	sink.Status = v1alpha1.SubscriptionStatus{
+		Conditions:           s.Status.Conditions,
+		PhysicalSubscription: s.Status.PhysicalSubscription,
+	}
+	return nil
+}
+
+func destinationFrom(s *v1alpha1.SubscriberSpec) *Destination {
+	if s == nil {
+		return nil
+	}
+	return &Destination{
+		Ref:      s.Ref,
+		URI:      s.DNSName,
+		Port:     s.Port,
+		Path:     s.Path,
+		Auth:     s.Auth,
+		Protocol: s.Protocol,
+	}
+}
+
+// subscriberSpec returns the v1alpha1 version of d.
+func (d *Destination) subscriberSpec() *v1alpha1.SubscriberSpec {
+	if d == nil {
+		return nil
+	}
+	return &v1alpha1.SubscriberSpec{
+		Ref:      d.Ref,
+		DNSName:  d.URI,
+		Port:     d.Port,
+		Path:     d.Path,
+		Auth:     d.Auth,
+		Protocol: d.Protocol,
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSubscriptionConversion(t *testing.T) {
+	dnsName := "http://subscriber.example.com/"
+	deadLetter := "http://dead-letter.example.com/"
+	onError := "http://on-error.example.com/"
+	amqpURI := "amqp://broker.example.com/orders"
+	mqttURI := "mqtt://broker.example.com/orders"
+	channel := corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Channel",
+		Name:       "foo",
+	}
+	reply := &corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Channel",
+		Name:       "bar",
+	}
+	schema := eventingduck.SubscriberSchema{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+			Key:                  "order.json",
+		},
+	}
+
+	testCases := map[string]struct {
+		alpha *v1alpha1.Subscription
+		beta  *Subscription
+	}{
+		"subscriber and reply": {
+			alpha: &v1alpha1.Subscription{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sub"},
+				Spec: v1alpha1.SubscriptionSpec{
+					Generation: 1,
+					Channel:    channel,
+					Subscriber: &v1alpha1.SubscriberSpec{DNSName: &dnsName, Path: "/events"},
+					Reply:      &v1alpha1.ReplyStrategy{Channel: reply},
+					Filter:     &v1alpha1.SubscriptionFilter{Expression: "type = 'foo'"},
+					Paused:     true,
+				},
+				Status: v1alpha1.SubscriptionStatus{
+					PhysicalSubscription: v1alpha1.SubscriptionStatusPhysicalSubscription{
+						SubscriberURI: dnsName,
+					},
+				},
+			},
+			beta: &Subscription{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sub"},
+				Spec: SubscriptionSpec{
+					Generation: 1,
+					Channel:    channel,
+					Subscriber: &Destination{URI: &dnsName, Path: "/events"},
+					Reply:      reply,
+					Filter:     &v1alpha1.SubscriptionFilter{Expression: "type = 'foo'"},
+					Paused:     true,
+				},
+				Status: SubscriptionStatus{
+					PhysicalSubscription: v1alpha1.SubscriptionStatusPhysicalSubscription{
+						SubscriberURI: dnsName,
+					},
+				},
+			},
+		},
+		"schema and dead letter sink": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel: channel,
+					Schema: &v1alpha1.SubscriptionSchema{
+						SubscriberSchema: schema,
+						DeadLetterSink:   &v1alpha1.SubscriberSpec{DNSName: &deadLetter},
+					},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel: channel,
+					Schema:  &schema,
+					Delivery: &DeliverySpec{
+						DeadLetterSink: &Destination{URI: &deadLetter},
+					},
+				},
+			},
+		},
+		"onError": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &v1alpha1.SubscriberSpec{DNSName: &dnsName},
+					OnError:    &v1alpha1.SubscriberSpec{DNSName: &onError},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &Destination{URI: &dnsName},
+					Delivery: &DeliverySpec{
+						OnError: &Destination{URI: &onError},
+					},
+				},
+			},
+		},
+		"schema, dead letter sink and onError": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel: channel,
+					Schema: &v1alpha1.SubscriptionSchema{
+						SubscriberSchema: schema,
+						DeadLetterSink:   &v1alpha1.SubscriberSpec{DNSName: &deadLetter},
+					},
+					OnError: &v1alpha1.SubscriberSpec{DNSName: &onError},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel: channel,
+					Schema:  &schema,
+					Delivery: &DeliverySpec{
+						DeadLetterSink: &Destination{URI: &deadLetter},
+						OnError:        &Destination{URI: &onError},
+					},
+				},
+			},
+		},
+		"gRPC subscriber": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &v1alpha1.SubscriberSpec{DNSName: &dnsName, Protocol: eventingduck.GRPCDeliveryProtocol},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &Destination{URI: &dnsName, Protocol: eventingduck.GRPCDeliveryProtocol},
+				},
+			},
+		},
+		"AMQP subscriber": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &v1alpha1.SubscriberSpec{DNSName: &amqpURI, Protocol: eventingduck.AMQPDeliveryProtocol},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &Destination{URI: &amqpURI, Protocol: eventingduck.AMQPDeliveryProtocol},
+				},
+			},
+		},
+		"MQTT subscriber": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &v1alpha1.SubscriberSpec{DNSName: &mqttURI, Protocol: eventingduck.MQTTDeliveryProtocol},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &Destination{URI: &mqttURI, Protocol: eventingduck.MQTTDeliveryProtocol},
+				},
+			},
+		},
+		"SSE subscriber": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &v1alpha1.SubscriberSpec{Protocol: eventingduck.SSEDeliveryProtocol},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel:    channel,
+					Subscriber: &Destination{Protocol: eventingduck.SSEDeliveryProtocol},
+				},
+			},
+		},
+		"schema without dead letter sink": {
+			alpha: &v1alpha1.Subscription{
+				Spec: v1alpha1.SubscriptionSpec{
+					Channel: channel,
+					Schema: &v1alpha1.SubscriptionSchema{
+						SubscriberSchema: schema,
+					},
+				},
+			},
+			beta: &Subscription{
+				Spec: SubscriptionSpec{
+					Channel: channel,
+					Schema:  &schema,
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			beta := &Subscription{}
+			if err := beta.ConvertFrom(tc.alpha); err != nil {
+				t.Fatalf("Unexpected error converting from v1alpha1: %v", err)
+			}
+			if diff := cmp.Diff(tc.beta, beta); diff != "" {
+				t.Errorf("Unexpected v1beta1 Subscription (-want +got): %s", diff)
+			}
+			alpha := &v1alpha1.Subscription{}
+			if err := beta.ConvertTo(alpha); err != nil {
+				t.Fatalf("Unexpected error converting to v1alpha1: %v", err)
+			}
+			if diff := cmp.Diff(tc.alpha, alpha); diff != "" {
+				t.Errorf("Unexpected round trip (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionValidation(t *testing.T) {
+	deadLetter := "http://dead-letter.example.com/"
+	s := &Subscription{
+		Spec: SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "foo",
+			},
+			Subscriber: &Destination{URI: &deadLetter},
+			Delivery: &DeliverySpec{
+				DeadLetterSink: &Destination{URI: &deadLetter},
+			},
+		},
+	}
+	err := s.Validate()
+	if err == nil {
+		t.Fatal("Expected an error for a dead letter sink without schema, got nil")
+	}
+	want := "a dead letter sink requires a schema: spec.delivery.deadLetterSink\nOnly the events whose data does not conform to the schema are sent to the dead letter sink for now."
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Errorf("Unexpected error (-want +got): %s", diff)
+	}
+}
+
+func TestSubscriptionValidationSSE(t *testing.T) {
+	s := &Subscription{
+		Spec: SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "foo",
+			},
+			// SSE subscribers connect to the dispatcher, they have neither ref nor uri.
+			Subscriber: &Destination{Protocol: eventingduck.SSEDeliveryProtocol},
+		},
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Unexpected error for an SSE subscriber: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// SetDefaults defaults s as its v1alpha1 version, so that both versions share the same defaults.
+func (s *Subscription) SetDefaults() {
+	alpha := s.alpha()
+	alpha.SetDefaults()
+	defaulted := &Subscription{}
+	defaulted.ConvertFrom(alpha)
+	s.Spec = defaulted.Spec
+}
+
+// alpha returns the v1alpha1 version of s. The conversion of Subscriptions cannot fail.
+func (s *Subscription) alpha() *v1alpha1.Subscription {
+	alpha := &v1alpha1.Subscription{}
+	s.DeepCopy().ConvertTo(alpha)
+	return alpha
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Subscription routes events received on a Channel to a DNS name and
+// corresponds to the subscriptions.channels.knative.dev CRD.
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              SubscriptionSpec   `json:"spec"`
+	Status            SubscriptionStatus `json:"status,omitempty"`
+}
+
+// Check that Subscription can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*Subscription)(nil)
+var _ apis.Defaultable = (*Subscription)(nil)
+var _ apis.Immutable = (*Subscription)(nil)
+var _ runtime.Object = (*Subscription)(nil)
+var _ webhook.GenericCRD = (*Subscription)(nil)
+
+// SubscriptionSpec specifies the Channel for incoming events, a Subscriber target
+// for processing those events, where to put the result of the processing and how
+// the events are delivered. It has the same semantics as the v1alpha1 SubscriptionSpec.
+type SubscriptionSpec struct {
+	// Generation is bumped by the webhook when the spec changes, as in v1alpha1.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// Channel is the Channel the events are received from. This field is
+	// immutable.
+	Channel corev1.ObjectReference `json:"channel"`
+
+	// Subscriber is the (optional) destination the events from the Channel
+	// are delivered to.
+	// +optional
+	Subscriber *Destination `json:"subscriber,omitempty"`
+
+	// Reply is the (optional) Channel the events returned by the Subscriber
+	// are sent to.
+	// +optional
+	Reply *corev1.ObjectReference `json:"reply,omitempty"`
+
+	// Filter specifies (optionally) which events from the Channel are
+	// delivered to the Subscriber.
+	// +optional
+	Filter *v1alpha1.SubscriptionFilter `json:"filter,omitempty"`
+
+	// Transform specifies (optionally) how the events from the Channel
+	// are rewritten before they are delivered to the Subscriber.
+	// +optional
+	Transform *v1alpha1.SubscriptionTransform `json:"transform,omitempty"`
+
+	// Schema specifies (optionally) the JSON Schema that the data of the
+	// events from the Channel must conform to.
+	// +optional
+	Schema *eventingduck.SubscriberSchema `json:"schema,omitempty"`
+
+	// Delivery specifies (optionally) what happens to the events that
+	// cannot be delivered to the Subscriber.
+	// +optional
+	Delivery *DeliverySpec `json:"delivery,omitempty"`
+
+	// Paused temporarily stops delivery to the Subscriber and Reply,
+	// without deleting the Subscription.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// DeliverySpec specifies what happens to the events that cannot be delivered to a subscriber.
+type DeliverySpec struct {
+	// DeadLetterSink receives the events that cannot be delivered. The
+	// Channels only send the events whose data does not conform to the
+	// Schema there for now, so it requires a Schema.
+	// +optional
+	DeadLetterSink *Destination `json:"deadLetterSink,omitempty"`
+
+	// OnError receives the events that the subscriber fails to accept,
+	// with the failure in their extensions, instead of failing their
+	// delivery.
+	// +optional
+	OnError *Destination `json:"onError,omitempty"`
+}
+
+// Destination is either a reference to an Addressable object or a URI events are delivered to.
+type Destination struct {
+	// Ref is a reference to an object implementing the Addressable duck type.
+	// +optional
+	Ref *corev1.ObjectReference `json:"ref,omitempty"`
+
+	// URI is a 'known' endpoint where no resolving is done, e.g.
+	// http://myexternalhandler.example.com/foo/bar.
+	// +optional
+	URI *string `json:"uri,omitempty"`
+
+	// Port is the port of the Service to deliver to, either its number
+	// or its name. It may only be set when Ref is a Kubernetes Service.
+	// +optional
+	Port *intstr.IntOrString `json:"port,omitempty"`
+
+	// Path is the URI path on the object referenced by Ref that events
+	// are delivered to.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Auth specifies (optionally) the credentials that are attached to
+	// every delivery.
+	// +optional
+	Auth *eventingduck.SubscriberAuth `json:"auth,omitempty"`
+
+	// Protocol is the protocol events are delivered with, either HTTP,
+	// gRPC, AMQP, MQTT or SSE, as for the v1alpha1 subscriber. SSE
+	// subscribers have neither Ref nor URI. Defaults to HTTP.
+	// +optional
+	Protocol eventingduck.DeliveryProtocol `json:"protocol,omitempty"`
+}
+
+// SubscriptionStatus (computed) for a subscription
+type SubscriptionStatus struct {
+	// Represents the latest available observations of a subscription's current state.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// PhysicalSubscription is the fully resolved values that this Subscription represents.
+	PhysicalSubscription v1alpha1.SubscriptionStatusPhysicalSubscription `json:"physicalSubscription,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SubscriptionList returned in list operations
+type SubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Subscription `json:"items"`
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/knative/pkg/apis"
+)
+
+// Validate validates s as its v1alpha1 version, so that both versions accept the same
+// Subscriptions. The errors of the fields that were renamed refer to their v1alpha1 name.
+func (s *Subscription) Validate() *apis.FieldError {
+	if s.Spec.Delivery != nil && s.Spec.Delivery.DeadLetterSink != nil && s.Spec.Schema == nil {
+		return &apis.FieldError{
+			Message: "a dead letter sink requires a schema",
+			Paths:   []string{"spec.delivery.deadLetterSink"},
+			Details: "Only the events whose data does not conform to the schema are sent to the dead letter sink for now.",
+		}
+	}
+	return s.alpha().Validate()
+}
+
+// CheckImmutableFields checks the immutable fields of s as its v1alpha1 version.
+func (s *Subscription) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*Subscription)
+	if !ok {
+		return &apis.FieldError{Message: "The provided original was not a Subscription"}
+	}
+	return s.alpha().CheckImmutableFields(original.alpha())
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha3 contains the ServiceEntry of the Istio networking.istio.io/v1alpha3 API, used to
+// declare the external subscribers to the mesh. knative/pkg declares the other types of the group
+// it uses, but not this one.
+
+// +k8s:deepcopy-gen=package
+// +groupName=networking.istio.io
+package v1alpha3
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceEntry adds hosts to the service registry of the mesh, so that its workloads may reach
+// them when the mesh only allows traffic to the registered services.
+type ServiceEntry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceEntrySpec `json:"spec"`
+}
+
+// ServiceEntryLocation is whether the hosts of a ServiceEntry are part of the mesh.
+type ServiceEntryLocation string
+
+const (
+	// LocationMeshExternal hosts are outside the mesh, and are not reached with mutual TLS.
+	LocationMeshExternal ServiceEntryLocation = "MESH_EXTERNAL"
+	// LocationMeshInternal hosts are part of the mesh.
+	LocationMeshInternal ServiceEntryLocation = "MESH_INTERNAL"
+)
+
+// ServiceEntryResolution is how the proxies resolve the addresses of the hosts of a ServiceEntry.
+type ServiceEntryResolution string
+
+const (
+	// ResolutionNone forwards connections to the address the workload connected to.
+	ResolutionNone ServiceEntryResolution = "NONE"
+	// ResolutionStatic uses the endpoints of the ServiceEntry.
+	ResolutionStatic ServiceEntryResolution = "STATIC"
+	// ResolutionDNS resolves the hosts with DNS.
+	ResolutionDNS ServiceEntryResolution = "DNS"
+)
+
+// ServiceEntrySpec lists the hosts of a ServiceEntry and the ports they are reached on.
+type ServiceEntrySpec struct {
+	// Hosts are the DNS names of the service, which may have a wildcard prefix.
+	Hosts []string `json:"hosts"`
+
+	// Ports are the ports of the service.
+	Ports []Port `json:"ports"`
+
+	// Location is MESH_EXTERNAL when not set.
+	Location ServiceEntryLocation `json:"location,omitempty"`
+
+	// Resolution is NONE when not set.
+	Resolution ServiceEntryResolution `json:"resolution,omitempty"`
+
+	// ExportTo lists the namespaces the ServiceEntry applies to. It applies to every namespace
+	// when not set.
+	ExportTo []string `json:"exportTo,omitempty"`
+}
+
+// Port is a port of a ServiceEntry.
+type Port struct {
+	Number int32 `json:"number"`
+
+	// Protocol is one of HTTP, HTTPS, HTTP2, GRPC, MONGO or TCP.
+	Protocol string `json:"protocol"`
+
+	// Name is unique among the ports of the ServiceEntry.
+	Name string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceEntryList is a list of ServiceEntry resources
+type ServiceEntryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ServiceEntry `json:"items"`
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuthorizationPolicy enables access control on the workloads of its namespace.
+type AuthorizationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AuthorizationPolicySpec `json:"spec"`
+}
+
+// AuthorizationPolicyAction is the action taken on the requests matching the rules of an
+// AuthorizationPolicy.
+type AuthorizationPolicyAction string
+
+const (
+	// ActionAllow allows the requests matching a rule. When an ALLOW policy applies to a workload,
+	// the requests that do not match any ALLOW policy are denied.
+	ActionAllow AuthorizationPolicyAction = "ALLOW"
+	// ActionDeny denies the requests matching a rule, whatever the ALLOW policies.
+	ActionDeny AuthorizationPolicyAction = "DENY"
+)
+
+// AuthorizationPolicySpec selects the workloads a policy applies to, and the requests it matches.
+type AuthorizationPolicySpec struct {
+	// Selector selects the pods the policy applies to. If not set, the policy applies to every
+	// workload in its namespace.
+	Selector *WorkloadSelector `json:"selector,omitempty"`
+
+	// Rules match requests. A request matches the policy if it matches any rule.
+	Rules []Rule `json:"rules,omitempty"`
+
+	// Action is ALLOW when not set.
+	Action AuthorizationPolicyAction `json:"action,omitempty"`
+}
+
+// WorkloadSelector selects pods by their labels.
+type WorkloadSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// Rule matches requests from a list of sources performing a list of operations. A request matches
+// the rule if it matches any source and any operation, and a missing list matches any request.
+type Rule struct {
+	From []RuleFrom `json:"from,omitempty"`
+	To   []RuleTo   `json:"to,omitempty"`
+}
+
+// RuleFrom includes a source.
+type RuleFrom struct {
+	Source Source `json:"source"`
+}
+
+// Source specifies the source identities of a request. A request matches the source if it matches
+// every field that is set.
+type Source struct {
+	// Principals are the peer identities of the mutual TLS connection, such as
+	// cluster.local/ns/default/sa/sleep.
+	Principals    []string `json:"principals,omitempty"`
+	NotPrincipals []string `json:"notPrincipals,omitempty"`
+
+	// Namespaces are the namespaces of the peers of the mutual TLS connection.
+	Namespaces    []string `json:"namespaces,omitempty"`
+	NotNamespaces []string `json:"notNamespaces,omitempty"`
+}
+
+// RuleTo includes an operation.
+type RuleTo struct {
+	Operation Operation `json:"operation"`
+}
+
+// Operation specifies the operation of a request. A request matches the operation if it matches
+// every field that is set.
+type Operation struct {
+	Hosts   []string `json:"hosts,omitempty"`
+	Methods []string `json:"methods,omitempty"`
+	Paths   []string `json:"paths,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AuthorizationPolicyList is a list of AuthorizationPolicy resources
+type AuthorizationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AuthorizationPolicy `json:"items"`
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains the subset of the Istio security.istio.io/v1beta1 API used to restrict
+// who may send events to channels. The Istio client libraries are not vendored, so the types are
+// declared here, as knative/pkg does for the networking.istio.io types.
+
+// +k8s:deepcopy-gen=package
+// +groupName=security.istio.io
+package v1beta1
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ApiServerSource watches Kubernetes resources and sends an event to a sink every time one of
+// them is added, updated or deleted.
+type ApiServerSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the ApiServerSource.
+	Spec ApiServerSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the ApiServerSource. This data may be out of
+	// date.
+	// +optional
+	Status ApiServerSourceStatus `json:"status,omitempty"`
+}
+
+// Check that ApiServerSource can be validated and can be defaulted.
+var _ apis.Validatable = (*ApiServerSource)(nil)
+var _ apis.Defaultable = (*ApiServerSource)(nil)
+var _ runtime.Object = (*ApiServerSource)(nil)
+var _ webhook.GenericCRD = (*ApiServerSource)(nil)
+
+// ApiServerSourceMode selects what the events of an ApiServerSource carry.
+type ApiServerSourceMode string
+
+const (
+	// ApiServerSourceModeResource sends the whole resource in the data of the events.
+	ApiServerSourceModeResource ApiServerSourceMode = "Resource"
+
+	// ApiServerSourceModeRef sends a reference to the resource in the data of the events.
+	ApiServerSourceModeRef ApiServerSourceMode = "Ref"
+)
+
+const (
+	// ApiServerSourceAddEventType, ApiServerSourceUpdateEventType and
+	// ApiServerSourceDeleteEventType are the types of the events sent in Resource mode.
+	ApiServerSourceAddEventType    = "dev.knative.apiserver.resource.add"
+	ApiServerSourceUpdateEventType = "dev.knative.apiserver.resource.update"
+	ApiServerSourceDeleteEventType = "dev.knative.apiserver.resource.delete"
+
+	// ApiServerSourceAddRefEventType, ApiServerSourceUpdateRefEventType and
+	// ApiServerSourceDeleteRefEventType are the types of the events sent in Ref mode.
+	ApiServerSourceAddRefEventType    = "dev.knative.apiserver.ref.add"
+	ApiServerSourceUpdateRefEventType = "dev.knative.apiserver.ref.update"
+	ApiServerSourceDeleteRefEventType = "dev.knative.apiserver.ref.delete"
+)
+
+// ApiServerSourceSpec specifies the resources an ApiServerSource watches, what its events carry,
+// and where they are sent.
+type ApiServerSourceSpec struct {
+	// Resources are the kinds of resources that are watched.
+	Resources []ApiServerResource `json:"resources,omitempty"`
+
+	// Namespaces are the namespaces in which the resources are watched. Defaults to the
+	// namespace of the ApiServerSource.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Mode selects whether the events carry the whole resource, 'Resource', or a reference to
+	// it, 'Ref'. Defaults to 'Resource'.
+	// +optional
+	Mode ApiServerSourceMode `json:"mode,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as. If it is
+	// not specified, a ServiceAccount is created. Either way, it is granted the permission to
+	// watch the resources.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the ApiServerSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+// ApiServerResource selects the resources of one kind that are watched.
+type ApiServerResource struct {
+	// APIVersion and Kind identify the kind of the resources, such as 'v1' and 'Pod'.
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+
+	// LabelSelector restricts the watched resources to the ones with matching labels. If it is
+	// not specified, every resource of the kind is watched.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+var apiServerSourceCondSet = duckv1alpha1.NewLivingConditionSet(ApiServerSourceConditionSinkProvided, ApiServerSourceConditionDeployed)
+
+// ApiServerSourceStatus represents the current state of an ApiServerSource.
+type ApiServerSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this ApiServerSource.
+	// It corresponds to the ApiServerSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the ApiServerSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Represents the latest available observations of an api server source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ApiServerSourceConditionReady has status True when the receive adapter
+	// is watching the resources and sending events to the sink.
+	ApiServerSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// ApiServerSourceConditionSinkProvided has status True when the
+	// ApiServerSource's sink has been resolved.
+	ApiServerSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// ApiServerSourceConditionDeployed has status True when the receive
+	// adapter is allowed to watch the resources and its Deployment has
+	// available replicas.
+	ApiServerSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *ApiServerSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return apiServerSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *ApiServerSourceStatus) IsReady() bool {
+	return apiServerSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *ApiServerSourceStatus) InitializeConditions() {
+	apiServerSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets ApiServerSourceConditionSinkProvided condition to True state, and records the
+// URI of the sink.
+func (ss *ApiServerSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	apiServerSourceCondSet.Manage(ss).MarkTrue(ApiServerSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets ApiServerSourceConditionSinkProvided condition to False state.
+func (ss *ApiServerSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	apiServerSourceCondSet.Manage(ss).MarkFalse(ApiServerSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets ApiServerSourceConditionDeployed condition to True state.
+func (ss *ApiServerSourceStatus) MarkDeployed() {
+	apiServerSourceCondSet.Manage(ss).MarkTrue(ApiServerSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets ApiServerSourceConditionDeployed condition to False state.
+func (ss *ApiServerSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	apiServerSourceCondSet.Manage(ss).MarkFalse(ApiServerSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// WatchedNamespaces returns the namespaces in which the resources are watched.
+func (s *ApiServerSource) WatchedNamespaces() []string {
+	if len(s.Spec.Namespaces) == 0 {
+		return []string{s.Namespace}
+	}
+	return s.Spec.Namespaces
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ApiServerSourceList is a collection of ApiServerSources.
+type ApiServerSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApiServerSource `json:"items"`
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApiServerSourceInitializeConditions(t *testing.T) {
+	ss := &ApiServerSourceStatus{}
+	ss.InitializeConditions()
+	want := &ApiServerSourceStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   ApiServerSourceConditionDeployed,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ApiServerSourceConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   ApiServerSourceConditionSinkProvided,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, ss, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestApiServerSourceIsReady(t *testing.T) {
+	tests := []struct {
+		name         string
+		markSink     bool
+		markDeployed bool
+		wantReady    bool
+	}{{
+		name:         "all happy",
+		markSink:     true,
+		markDeployed: true,
+		wantReady:    true,
+	}, {
+		name:         "sink sad",
+		markSink:     false,
+		markDeployed: true,
+		wantReady:    false,
+	}, {
+		name:         "deployed sad",
+		markSink:     true,
+		markDeployed: false,
+		wantReady:    false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ss := &ApiServerSourceStatus{}
+			ss.InitializeConditions()
+			if test.markSink {
+				ss.MarkSink("http://example.com/")
+			} else {
+				ss.MarkNoSink("NotFound", "testing")
+			}
+			if test.markDeployed {
+				ss.MarkDeployed()
+			} else {
+				ss.MarkNotDeployed("NotDeployed", "testing")
+			}
+			if got := ss.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
+
+func TestApiServerSourceWatchedNamespaces(t *testing.T) {
+	s := &ApiServerSource{ObjectMeta: metav1.ObjectMeta{Namespace: "source-namespace"}}
+	if diff := cmp.Diff([]string{"source-namespace"}, s.WatchedNamespaces()); diff != "" {
+		t.Errorf("unexpected default namespaces (-want, +got) = %v", diff)
+	}
+	s.Spec.Namespaces = []string{"a", "b"}
+	if diff := cmp.Diff([]string{"a", "b"}, s.WatchedNamespaces()); diff != "" {
+		t.Errorf("unexpected namespaces (-want, +got) = %v", diff)
+	}
+}
+
+func TestApiServerSourceSetDefaults(t *testing.T) {
+	s := &ApiServerSource{}
+	s.SetDefaults()
+	if s.Spec.Mode != ApiServerSourceModeResource {
+		t.Errorf("unexpected default mode: want %q, got %q", ApiServerSourceModeResource, s.Spec.Mode)
+	}
+	s.Spec.Mode = ApiServerSourceModeRef
+	s.SetDefaults()
+	if s.Spec.Mode != ApiServerSourceModeRef {
+		t.Errorf("mode was overridden: want %q, got %q", ApiServerSourceModeRef, s.Spec.Mode)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func (s *ApiServerSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *ApiServerSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if len(ss.Resources) == 0 {
+		errs = errs.Also(apis.ErrMissingField("resources"))
+	}
+	for i, r := range ss.Resources {
+		if fe := r.Validate(); fe != nil {
+			errs = errs.Also(fe.ViaFieldIndex("resources", i))
+		}
+	}
+	for i, ns := range ss.Namespaces {
+		if len(validation.IsDNS1123Label(ns)) != 0 {
+			errs = errs.Also(apis.ErrInvalidValue(ns, apis.CurrentField).ViaFieldIndex("namespaces", i))
+		}
+	}
+	switch ss.Mode {
+	case "", ApiServerSourceModeResource, ApiServerSourceModeRef:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(string(ss.Mode), "mode"))
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+func (r *ApiServerResource) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if r.APIVersion == "" {
+		errs = errs.Also(apis.ErrMissingField("apiVersion"))
+	} else if _, err := schema.ParseGroupVersion(r.APIVersion); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(r.APIVersion, "apiVersion"))
+	}
+	if r.Kind == "" {
+		errs = errs.Also(apis.ErrMissingField("kind"))
+	}
+	if r.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.LabelSelector); err != nil {
+			fe := apis.ErrInvalidValue(r.LabelSelector.String(), "labelSelector")
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
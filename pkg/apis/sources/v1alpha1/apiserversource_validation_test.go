@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApiServerSourceValidation(t *testing.T) {
+	sink := &corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Broker",
+		Name:       "default",
+	}
+	pods := []ApiServerResource{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+	}}
+	tests := []struct {
+		name string
+		cr   *ApiServerSource
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		cr: &ApiServerSource{
+			Spec: ApiServerSourceSpec{
+				Resources: []ApiServerResource{{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "foo"},
+					},
+				}},
+				Namespaces: []string{"default", "other"},
+				Mode:       ApiServerSourceModeRef,
+				Sink:       sink,
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing resources",
+		cr: &ApiServerSource{
+			Spec: ApiServerSourceSpec{
+				Sink: sink,
+			},
+		},
+		want: apis.ErrMissingField("spec.resources"),
+	}, {
+		name: "invalid resource",
+		cr: &ApiServerSource{
+			Spec: ApiServerSourceSpec{
+				Resources: []ApiServerResource{{
+					APIVersion: "a/b/c",
+				}},
+				Sink: sink,
+			},
+		},
+		want: apis.ErrInvalidValue("a/b/c", "spec.resources[0].apiVersion").Also(
+			apis.ErrMissingField("spec.resources[0].kind")),
+	}, {
+		name: "invalid namespace",
+		cr: &ApiServerSource{
+			Spec: ApiServerSourceSpec{
+				Resources:  pods,
+				Namespaces: []string{"Not_A_Namespace"},
+				Sink:       sink,
+			},
+		},
+		want: apis.ErrInvalidValue("Not_A_Namespace", "spec.namespaces[0]"),
+	}, {
+		name: "invalid mode",
+		cr: &ApiServerSource{
+			Spec: ApiServerSourceSpec{
+				Resources: pods,
+				Mode:      "Everything",
+				Sink:      sink,
+			},
+		},
+		want: apis.ErrInvalidValue("Everything", "spec.mode"),
+	}, {
+		name: "missing sink",
+		cr: &ApiServerSource{
+			Spec: ApiServerSourceSpec{
+				Resources: pods,
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AwsSqsSource receives the messages of an AWS SQS queue and sends them to a sink as events.
+type AwsSqsSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the AwsSqsSource.
+	Spec AwsSqsSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the AwsSqsSource. This data may be out of
+	// date.
+	// +optional
+	Status AwsSqsSourceStatus `json:"status,omitempty"`
+}
+
+// Check that AwsSqsSource can be validated and can be defaulted.
+var _ apis.Validatable = (*AwsSqsSource)(nil)
+var _ apis.Defaultable = (*AwsSqsSource)(nil)
+var _ runtime.Object = (*AwsSqsSource)(nil)
+var _ webhook.GenericCRD = (*AwsSqsSource)(nil)
+
+const (
+	// AwsSqsEventType is the type of the events sent by AwsSqsSources.
+	AwsSqsEventType = "aws.sqs.message"
+
+	// DefaultAwsSqsConcurrency is the number of messages received and sent at a time by default.
+	DefaultAwsSqsConcurrency = 1
+
+	// MaxAwsSqsConcurrency is the largest number of messages that can be received and sent at a
+	// time.
+	MaxAwsSqsConcurrency = 100
+
+	// MaxAwsSqsVisibilityTimeoutSeconds is the longest visibility timeout SQS allows.
+	MaxAwsSqsVisibilityTimeoutSeconds = 12 * 60 * 60
+)
+
+// AwsSqsSourceSpec specifies the SQS queue an AwsSqsSource receives messages from, and where they
+// are sent.
+type AwsSqsSourceSpec struct {
+	// QueueURL is the URL of the SQS queue, such as
+	// 'https://sqs.us-east-1.amazonaws.com/123456789012/orders'.
+	QueueURL string `json:"queueUrl,omitempty"`
+
+	// Region is the AWS region of the queue. Defaults to the region in the host of QueueURL.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// AccessKeyID selects the key of a Secret holding the ID of the AWS access key the queue is
+	// read with.
+	AccessKeyID *corev1.SecretKeySelector `json:"accessKeyId,omitempty"`
+
+	// SecretAccessKey selects the key of a Secret holding the AWS secret access key.
+	SecretAccessKey *corev1.SecretKeySelector `json:"secretAccessKey,omitempty"`
+
+	// Concurrency is the number of messages that are received and sent to the sink at a time.
+	// Defaults to 1.
+	// +optional
+	Concurrency *int32 `json:"concurrency,omitempty"`
+
+	// VisibilityTimeoutSeconds is how long, in seconds, a received message is hidden from the
+	// other consumers of the queue while it is sent to the sink. The receive adapter extends it for
+	// as long as the sink takes to respond. Defaults to the visibility timeout of the queue.
+	// +optional
+	VisibilityTimeoutSeconds *int32 `json:"visibilityTimeoutSeconds,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the AwsSqsSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+var awsSqsSourceCondSet = duckv1alpha1.NewLivingConditionSet(AwsSqsSourceConditionSinkProvided, AwsSqsSourceConditionDeployed)
+
+// AwsSqsSourceStatus represents the current state of a AwsSqsSource.
+type AwsSqsSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this AwsSqsSource.
+	// It corresponds to the AwsSqsSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the AwsSqsSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Represents the latest available observations of a AWS SQS source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// AwsSqsSourceConditionReady has status True when the receive adapter is
+	// receiving the messages of the queue and sending them to the sink.
+	AwsSqsSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// AwsSqsSourceConditionSinkProvided has status True when the
+	// AwsSqsSource's sink has been resolved.
+	AwsSqsSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// AwsSqsSourceConditionDeployed has status True when the Deployment of
+	// the receive adapter has available replicas.
+	AwsSqsSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *AwsSqsSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return awsSqsSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *AwsSqsSourceStatus) IsReady() bool {
+	return awsSqsSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *AwsSqsSourceStatus) InitializeConditions() {
+	awsSqsSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets AwsSqsSourceConditionSinkProvided condition to True state, and records the URI of
+// the sink.
+func (ss *AwsSqsSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	awsSqsSourceCondSet.Manage(ss).MarkTrue(AwsSqsSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets AwsSqsSourceConditionSinkProvided condition to False state.
+func (ss *AwsSqsSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	awsSqsSourceCondSet.Manage(ss).MarkFalse(AwsSqsSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets AwsSqsSourceConditionDeployed condition to True state.
+func (ss *AwsSqsSourceStatus) MarkDeployed() {
+	awsSqsSourceCondSet.Manage(ss).MarkTrue(AwsSqsSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets AwsSqsSourceConditionDeployed condition to False state.
+func (ss *AwsSqsSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	awsSqsSourceCondSet.Manage(ss).MarkFalse(AwsSqsSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AwsSqsSourceList is a collection of AwsSqsSources.
+type AwsSqsSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AwsSqsSource `json:"items"`
+}
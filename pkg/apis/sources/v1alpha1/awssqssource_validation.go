@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+)
+
+func (s *AwsSqsSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *AwsSqsSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.QueueURL == "" {
+		errs = errs.Also(apis.ErrMissingField("queueUrl"))
+	} else if u, err := url.Parse(ss.QueueURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = errs.Also(apis.ErrInvalidValue(ss.QueueURL, "queueUrl"))
+	} else if ss.Region == "" && AwsSqsRegion(u.Host) == "" {
+		fe := apis.ErrMissingField("region")
+		fe.Details = "the region cannot be read from the host of the queue URL"
+		errs = errs.Also(fe)
+	}
+	errs = errs.Also(validateRequiredSecret(ss.AccessKeyID, "accessKeyId"))
+	errs = errs.Also(validateRequiredSecret(ss.SecretAccessKey, "secretAccessKey"))
+	if ss.Concurrency != nil && (*ss.Concurrency < 1 || *ss.Concurrency > MaxAwsSqsConcurrency) {
+		fe := apis.ErrInvalidValue(fmt.Sprintf("%d", *ss.Concurrency), "concurrency")
+		fe.Details = fmt.Sprintf("the concurrency must be between 1 and %d", MaxAwsSqsConcurrency)
+		errs = errs.Also(fe)
+	}
+	if t := ss.VisibilityTimeoutSeconds; t != nil && (*t < 1 || *t > MaxAwsSqsVisibilityTimeoutSeconds) {
+		fe := apis.ErrInvalidValue(fmt.Sprintf("%d", *t), "visibilityTimeoutSeconds")
+		fe.Details = fmt.Sprintf("the visibility timeout must be between 1 and %d seconds", MaxAwsSqsVisibilityTimeoutSeconds)
+		errs = errs.Also(fe)
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+// AwsSqsRegion returns the AWS region in host, the host of an SQS queue URL such as
+// 'sqs.us-east-1.amazonaws.com', or "" if host is not an SQS endpoint.
+func AwsSqsRegion(host string) string {
+	parts := strings.Split(host, ".")
+	if len(parts) < 4 || parts[0] != "sqs" || parts[2] != "amazonaws" {
+		return ""
+	}
+	return parts[1]
+}
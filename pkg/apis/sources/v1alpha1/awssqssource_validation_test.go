@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAwsSqsSourceValidation(t *testing.T) {
+	secret := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "aws-credentials"},
+			Key:                  key,
+		}
+	}
+	int32Ptr := func(i int32) *int32 {
+		return &i
+	}
+	valid := func() AwsSqsSourceSpec {
+		return AwsSqsSourceSpec{
+			QueueURL:        "https://sqs.us-east-1.amazonaws.com/123456789012/orders",
+			AccessKeyID:     secret("access-key-id"),
+			SecretAccessKey: secret("secret-access-key"),
+			Sink: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "orders",
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		spec func(*AwsSqsSourceSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: func(*AwsSqsSourceSpec) {},
+		want: nil,
+	}, {
+		name: "valid with concurrency and visibility timeout",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.Concurrency = int32Ptr(10)
+			s.VisibilityTimeoutSeconds = int32Ptr(60)
+		},
+		want: nil,
+	}, {
+		name: "custom endpoint with region",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.QueueURL = "https://sqs.example.com/123456789012/orders"
+			s.Region = "us-west-2"
+		},
+		want: nil,
+	}, {
+		name: "custom endpoint without region",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.QueueURL = "https://sqs.example.com/123456789012/orders"
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.region")
+			fe.Details = "the region cannot be read from the host of the queue URL"
+			return fe
+		}(),
+	}, {
+		name: "missing queue URL and credentials",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.QueueURL = ""
+			s.AccessKeyID = nil
+			s.SecretAccessKey = secret("")
+		},
+		want: apis.ErrMissingField("spec.accessKeyId", "spec.queueUrl", "spec.secretAccessKey.key"),
+	}, {
+		name: "queue URL without https",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.QueueURL = "sqs.us-east-1.amazonaws.com/123456789012/orders"
+		},
+		want: apis.ErrInvalidValue("sqs.us-east-1.amazonaws.com/123456789012/orders", "spec.queueUrl"),
+	}, {
+		name: "concurrency out of bounds",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.Concurrency = int32Ptr(0)
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("0", "spec.concurrency")
+			fe.Details = "the concurrency must be between 1 and 100"
+			return fe
+		}(),
+	}, {
+		name: "visibility timeout out of bounds",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.VisibilityTimeoutSeconds = int32Ptr(43201)
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("43201", "spec.visibilityTimeoutSeconds")
+			fe.Details = "the visibility timeout must be between 1 and 43200 seconds"
+			return fe
+		}(),
+	}, {
+		name: "missing sink",
+		spec: func(s *AwsSqsSourceSpec) {
+			s.Sink = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &AwsSqsSource{Spec: valid()}
+			test.spec(&cr.Spec)
+			got := cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestAwsSqsRegion(t *testing.T) {
+	tests := map[string]string{
+		"sqs.us-east-1.amazonaws.com":     "us-east-1",
+		"sqs.cn-north-1.amazonaws.com.cn": "cn-north-1",
+		"sqs.example.com":                 "",
+		"queue.amazonaws.com":             "",
+	}
+	for host, want := range tests {
+		if got := AwsSqsRegion(host); got != want {
+			t.Errorf("AwsSqsRegion(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
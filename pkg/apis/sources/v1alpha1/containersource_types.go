@@ -0,0 +1,158 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContainerSource runs a user-supplied container image that sends events to a sink.
+type ContainerSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the ContainerSource.
+	Spec ContainerSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the ContainerSource. This data may be out of
+	// date.
+	// +optional
+	Status ContainerSourceStatus `json:"status,omitempty"`
+}
+
+// Check that ContainerSource can be validated and can be defaulted.
+var _ apis.Validatable = (*ContainerSource)(nil)
+var _ apis.Defaultable = (*ContainerSource)(nil)
+var _ runtime.Object = (*ContainerSource)(nil)
+var _ webhook.GenericCRD = (*ContainerSource)(nil)
+
+// ContainerSourceSpec specifies the container that a ContainerSource runs, and where the
+// container sends its events.
+type ContainerSourceSpec struct {
+	// Image is the image of the container.
+	Image string `json:"image,omitempty"`
+
+	// Args are the arguments of the container. The URI of the sink is appended to them as
+	// '--sink=<uri>'.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is the environment of the container. The URI of the sink is added to it as SINK_URI.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the container runs as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the ContainerSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+var containerSourceCondSet = duckv1alpha1.NewLivingConditionSet(ContainerSourceConditionSinkProvided, ContainerSourceConditionDeployed)
+
+// ContainerSourceStatus represents the current state of a ContainerSource.
+type ContainerSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this ContainerSource.
+	// It corresponds to the ContainerSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the ContainerSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Represents the latest available observations of a container source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// ContainerSourceConditionReady has status True when the container is
+	// running and sending events to the sink.
+	ContainerSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// ContainerSourceConditionSinkProvided has status True when the
+	// ContainerSource's sink has been resolved.
+	ContainerSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// ContainerSourceConditionDeployed has status True when the Deployment
+	// running the container has available replicas.
+	ContainerSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *ContainerSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return containerSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *ContainerSourceStatus) IsReady() bool {
+	return containerSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *ContainerSourceStatus) InitializeConditions() {
+	containerSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets ContainerSourceConditionSinkProvided condition to True state, and records the
+// URI of the sink.
+func (ss *ContainerSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	containerSourceCondSet.Manage(ss).MarkTrue(ContainerSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets ContainerSourceConditionSinkProvided condition to False state.
+func (ss *ContainerSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	containerSourceCondSet.Manage(ss).MarkFalse(ContainerSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets ContainerSourceConditionDeployed condition to True state.
+func (ss *ContainerSourceStatus) MarkDeployed() {
+	containerSourceCondSet.Manage(ss).MarkTrue(ContainerSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets ContainerSourceConditionDeployed condition to False state.
+func (ss *ContainerSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	containerSourceCondSet.Manage(ss).MarkFalse(ContainerSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContainerSourceList is a collection of ContainerSources.
+type ContainerSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContainerSource `json:"items"`
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func (s *ContainerSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *ContainerSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.Image == "" {
+		errs = errs.Also(apis.ErrMissingField("image"))
+	}
+	for i, e := range ss.Env {
+		if e.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("name").ViaFieldIndex("env", i))
+		}
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+// validateSink checks that sink is a complete reference to an object.
+func validateSink(sink *corev1.ObjectReference) *apis.FieldError {
+	var errs *apis.FieldError
+	if sink.APIVersion == "" {
+		errs = errs.Also(apis.ErrMissingField("apiVersion"))
+	}
+	if sink.Kind == "" {
+		errs = errs.Also(apis.ErrMissingField("kind"))
+	}
+	if sink.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+	return errs
+}
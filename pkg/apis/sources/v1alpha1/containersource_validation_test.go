@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestContainerSourceValidation(t *testing.T) {
+	sink := &corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Broker",
+		Name:       "default",
+	}
+	tests := []struct {
+		name string
+		cr   *ContainerSource
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		cr: &ContainerSource{
+			Spec: ContainerSourceSpec{
+				Image: "example.com/heartbeats",
+				Args:  []string{"--period=1"},
+				Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+				Sink:  sink,
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing image",
+		cr: &ContainerSource{
+			Spec: ContainerSourceSpec{
+				Sink: sink,
+			},
+		},
+		want: apis.ErrMissingField("spec.image"),
+	}, {
+		name: "missing sink",
+		cr: &ContainerSource{
+			Spec: ContainerSourceSpec{
+				Image: "example.com/heartbeats",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}, {
+		name: "incomplete sink",
+		cr: &ContainerSource{
+			Spec: ContainerSourceSpec{
+				Image: "example.com/heartbeats",
+				Sink:  &corev1.ObjectReference{Name: "default"},
+			},
+		},
+		want: apis.ErrMissingField("spec.sink.apiVersion", "spec.sink.kind"),
+	}, {
+		name: "env var without a name",
+		cr: &ContainerSource{
+			Spec: ContainerSourceSpec{
+				Image: "example.com/heartbeats",
+				Env:   []corev1.EnvVar{{Value: "bar"}},
+				Sink:  sink,
+			},
+		},
+		want: apis.ErrMissingField("spec.env[0].name"),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronJobSource sends an event with fixed data to a sink on a cron schedule.
+type CronJobSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the CronJobSource.
+	Spec CronJobSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the CronJobSource. This data may be out of
+	// date.
+	// +optional
+	Status CronJobSourceStatus `json:"status,omitempty"`
+}
+
+// Check that CronJobSource can be validated and can be defaulted.
+var _ apis.Validatable = (*CronJobSource)(nil)
+var _ apis.Defaultable = (*CronJobSource)(nil)
+var _ runtime.Object = (*CronJobSource)(nil)
+var _ webhook.GenericCRD = (*CronJobSource)(nil)
+
+const (
+	// CronJobEventType is the type of the events sent by CronJobSources.
+	CronJobEventType = "dev.knative.cronjob.event"
+)
+
+// CronJobSourceSpec specifies the schedule of a CronJobSource, the data of its events, and where
+// they are sent.
+type CronJobSourceSpec struct {
+	// Schedule is the cron schedule of the events, in the standard five-field format, such as
+	// '*/5 * * * *', or one of the descriptors '@hourly', '@daily' and '@every <duration>'.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Data is the data of the events. It is sent as JSON if it is valid JSON, and as a JSON string
+	// otherwise.
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as. To report
+	// the last time it fired, it must be allowed to update the CronJobSource.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the CronJobSource's namespace, that receives the
+	// events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+var cronJobSourceCondSet = duckv1alpha1.NewLivingConditionSet(CronJobSourceConditionValidSchedule, CronJobSourceConditionSinkProvided, CronJobSourceConditionDeployed)
+
+// CronJobSourceStatus represents the current state of a CronJobSource.
+type CronJobSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this CronJobSource.
+	// It corresponds to the CronJobSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the CronJobSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// LastScheduleTime is the last time an event was delivered to the sink. It is set by the
+	// receive adapter.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Represents the latest available observations of a cron job source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// CronJobSourceConditionReady has status True when the receive adapter is
+	// running and sending events to the sink.
+	CronJobSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// CronJobSourceConditionValidSchedule has status True when the schedule
+	// of the CronJobSource can be parsed.
+	CronJobSourceConditionValidSchedule duckv1alpha1.ConditionType = "ValidSchedule"
+
+	// CronJobSourceConditionSinkProvided has status True when the
+	// CronJobSource's sink has been resolved.
+	CronJobSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// CronJobSourceConditionDeployed has status True when the Deployment
+	// running the receive adapter has available replicas.
+	CronJobSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *CronJobSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return cronJobSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *CronJobSourceStatus) IsReady() bool {
+	return cronJobSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *CronJobSourceStatus) InitializeConditions() {
+	cronJobSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSchedule sets CronJobSourceConditionValidSchedule condition to True state.
+func (ss *CronJobSourceStatus) MarkSchedule() {
+	cronJobSourceCondSet.Manage(ss).MarkTrue(CronJobSourceConditionValidSchedule)
+}
+
+// MarkInvalidSchedule sets CronJobSourceConditionValidSchedule condition to False state.
+func (ss *CronJobSourceStatus) MarkInvalidSchedule(reason, messageFormat string, messageA ...interface{}) {
+	cronJobSourceCondSet.Manage(ss).MarkFalse(CronJobSourceConditionValidSchedule, reason, messageFormat, messageA...)
+}
+
+// MarkSink sets CronJobSourceConditionSinkProvided condition to True state, and records the URI
+// of the sink.
+func (ss *CronJobSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	cronJobSourceCondSet.Manage(ss).MarkTrue(CronJobSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets CronJobSourceConditionSinkProvided condition to False state.
+func (ss *CronJobSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	cronJobSourceCondSet.Manage(ss).MarkFalse(CronJobSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets CronJobSourceConditionDeployed condition to True state.
+func (ss *CronJobSourceStatus) MarkDeployed() {
+	cronJobSourceCondSet.Manage(ss).MarkTrue(CronJobSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets CronJobSourceConditionDeployed condition to False state.
+func (ss *CronJobSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	cronJobSourceCondSet.Manage(ss).MarkFalse(CronJobSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronJobSourceList is a collection of CronJobSources.
+type CronJobSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronJobSource `json:"items"`
+}
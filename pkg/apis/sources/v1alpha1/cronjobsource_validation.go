@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/eventing/pkg/cron"
+	"github.com/knative/pkg/apis"
+)
+
+func (s *CronJobSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *CronJobSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.Schedule == "" {
+		errs = errs.Also(apis.ErrMissingField("schedule"))
+	} else if _, err := cron.Parse(ss.Schedule); err != nil {
+		fe := apis.ErrInvalidValue(ss.Schedule, "schedule")
+		fe.Details = err.Error()
+		errs = errs.Also(fe)
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCronJobSourceValidation(t *testing.T) {
+	sink := &corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Broker",
+		Name:       "default",
+	}
+	tests := []struct {
+		name string
+		cr   *CronJobSource
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		cr: &CronJobSource{
+			Spec: CronJobSourceSpec{
+				Schedule: "*/2 * * * *",
+				Data:     `{"message": "Hello world!"}`,
+				Sink:     sink,
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing schedule",
+		cr: &CronJobSource{
+			Spec: CronJobSourceSpec{
+				Sink: sink,
+			},
+		},
+		want: apis.ErrMissingField("spec.schedule"),
+	}, {
+		name: "invalid schedule",
+		cr: &CronJobSource{
+			Spec: CronJobSourceSpec{
+				Schedule: "* * *",
+				Sink:     sink,
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("* * *", "spec.schedule")
+			fe.Details = `expected 5 fields, found 3 in "* * *"`
+			return fe
+		}(),
+	}, {
+		name: "missing sink",
+		cr: &CronJobSource{
+			Spec: CronJobSourceSpec{
+				Schedule: "@hourly",
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitHubSource registers a webhook on a GitHub repository, and sends the events it receives to a
+// sink.
+type GitHubSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the GitHubSource.
+	Spec GitHubSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the GitHubSource. This data may be out of
+	// date.
+	// +optional
+	Status GitHubSourceStatus `json:"status,omitempty"`
+}
+
+// Check that GitHubSource can be validated and can be defaulted.
+var _ apis.Validatable = (*GitHubSource)(nil)
+var _ apis.Defaultable = (*GitHubSource)(nil)
+var _ runtime.Object = (*GitHubSource)(nil)
+var _ webhook.GenericCRD = (*GitHubSource)(nil)
+
+const (
+	// GitHubEventTypePrefix is the prefix of the types of the events sent by GitHubSources. The
+	// type of an event is the prefix followed by the GitHub event name, such as
+	// 'dev.knative.source.github.push'.
+	GitHubEventTypePrefix = "dev.knative.source.github"
+
+	// GitHubEventSourcePrefix is the prefix of the source of the events sent by GitHubSources.
+	// The source of an event is the prefix followed by the owner and repository.
+	GitHubEventSourcePrefix = "https://github.com"
+)
+
+// GitHubSourceSpec specifies the GitHub repository and events a GitHubSource subscribes to, and
+// where the events are sent.
+type GitHubSourceSpec struct {
+	// OwnerAndRepository is the GitHub repository, such as 'knative/eventing'.
+	OwnerAndRepository string `json:"ownerAndRepository,omitempty"`
+
+	// EventTypes are the names of the GitHub events the webhook subscribes to, such as 'push'
+	// and 'pull_request'.
+	EventTypes []string `json:"eventTypes,omitempty"`
+
+	// AccessToken selects the key of a Secret holding a GitHub personal access token, allowed
+	// to manage the webhooks of the repository.
+	AccessToken *corev1.SecretKeySelector `json:"accessToken,omitempty"`
+
+	// SecretToken selects the key of a Secret holding the secret the webhook requests are
+	// signed with.
+	SecretToken *corev1.SecretKeySelector `json:"secretToken,omitempty"`
+
+	// GitHubAPIURL is the base URL of the GitHub API. Defaults to 'https://api.github.com'. It is
+	// set for GitHub Enterprise.
+	// +optional
+	GitHubAPIURL string `json:"githubAPIURL,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the GitHubSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+var gitHubSourceCondSet = duckv1alpha1.NewLivingConditionSet(
+	GitHubSourceConditionSecretsProvided,
+	GitHubSourceConditionSinkProvided,
+	GitHubSourceConditionDeployed,
+	GitHubSourceConditionWebhookRegistered)
+
+// GitHubSourceStatus represents the current state of a GitHubSource.
+type GitHubSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this GitHubSource.
+	// It corresponds to the GitHubSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the GitHubSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// WebhookIDKey is the ID of the webhook registered on the repository. It is used to delete
+	// the webhook when the GitHubSource is deleted.
+	// +optional
+	WebhookIDKey string `json:"webhookIDKey,omitempty"`
+
+	// Represents the latest available observations of a github source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// GitHubSourceConditionReady has status True when the webhook is
+	// registered and its events are sent to the sink.
+	GitHubSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// GitHubSourceConditionSecretsProvided has status True when the access
+	// token and the secret token have been read.
+	GitHubSourceConditionSecretsProvided duckv1alpha1.ConditionType = "SecretsProvided"
+
+	// GitHubSourceConditionSinkProvided has status True when the
+	// GitHubSource's sink has been resolved.
+	GitHubSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// GitHubSourceConditionDeployed has status True when the Deployment of
+	// the receive adapter has available replicas.
+	GitHubSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+
+	// GitHubSourceConditionWebhookRegistered has status True when the
+	// webhook is registered on the repository.
+	GitHubSourceConditionWebhookRegistered duckv1alpha1.ConditionType = "WebhookRegistered"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *GitHubSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return gitHubSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *GitHubSourceStatus) IsReady() bool {
+	return gitHubSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *GitHubSourceStatus) InitializeConditions() {
+	gitHubSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSecrets sets GitHubSourceConditionSecretsProvided condition to True state.
+func (ss *GitHubSourceStatus) MarkSecrets() {
+	gitHubSourceCondSet.Manage(ss).MarkTrue(GitHubSourceConditionSecretsProvided)
+}
+
+// MarkNoSecrets sets GitHubSourceConditionSecretsProvided condition to False state.
+func (ss *GitHubSourceStatus) MarkNoSecrets(reason, messageFormat string, messageA ...interface{}) {
+	gitHubSourceCondSet.Manage(ss).MarkFalse(GitHubSourceConditionSecretsProvided, reason, messageFormat, messageA...)
+}
+
+// MarkSink sets GitHubSourceConditionSinkProvided condition to True state, and records the URI
+// of the sink.
+func (ss *GitHubSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	gitHubSourceCondSet.Manage(ss).MarkTrue(GitHubSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets GitHubSourceConditionSinkProvided condition to False state.
+func (ss *GitHubSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	gitHubSourceCondSet.Manage(ss).MarkFalse(GitHubSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets GitHubSourceConditionDeployed condition to True state.
+func (ss *GitHubSourceStatus) MarkDeployed() {
+	gitHubSourceCondSet.Manage(ss).MarkTrue(GitHubSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets GitHubSourceConditionDeployed condition to False state.
+func (ss *GitHubSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	gitHubSourceCondSet.Manage(ss).MarkFalse(GitHubSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// MarkWebhook sets GitHubSourceConditionWebhookRegistered condition to True state, and records
+// the ID of the webhook.
+func (ss *GitHubSourceStatus) MarkWebhook(id string) {
+	ss.WebhookIDKey = id
+	gitHubSourceCondSet.Manage(ss).MarkTrue(GitHubSourceConditionWebhookRegistered)
+}
+
+// MarkNoWebhook sets GitHubSourceConditionWebhookRegistered condition to False state.
+func (ss *GitHubSourceStatus) MarkNoWebhook(reason, messageFormat string, messageA ...interface{}) {
+	gitHubSourceCondSet.Manage(ss).MarkFalse(GitHubSourceConditionWebhookRegistered, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitHubSourceList is a collection of GitHubSources.
+type GitHubSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitHubSource `json:"items"`
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGitHubSourceInitializeConditions(t *testing.T) {
+	ss := &GitHubSourceStatus{}
+	ss.InitializeConditions()
+	want := &GitHubSourceStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   GitHubSourceConditionDeployed,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   GitHubSourceConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   GitHubSourceConditionSecretsProvided,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   GitHubSourceConditionSinkProvided,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   GitHubSourceConditionWebhookRegistered,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, ss, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestGitHubSourceIsReady(t *testing.T) {
+	tests := []struct {
+		name         string
+		markSecrets  bool
+		markSink     bool
+		markDeployed bool
+		markWebhook  bool
+		wantReady    bool
+	}{{
+		name:         "all happy",
+		markSecrets:  true,
+		markSink:     true,
+		markDeployed: true,
+		markWebhook:  true,
+		wantReady:    true,
+	}, {
+		name:         "secrets sad",
+		markSecrets:  false,
+		markSink:     true,
+		markDeployed: true,
+		markWebhook:  true,
+		wantReady:    false,
+	}, {
+		name:         "sink sad",
+		markSecrets:  true,
+		markSink:     false,
+		markDeployed: true,
+		markWebhook:  true,
+		wantReady:    false,
+	}, {
+		name:         "deployed sad",
+		markSecrets:  true,
+		markSink:     true,
+		markDeployed: false,
+		markWebhook:  true,
+		wantReady:    false,
+	}, {
+		name:         "webhook sad",
+		markSecrets:  true,
+		markSink:     true,
+		markDeployed: true,
+		markWebhook:  false,
+		wantReady:    false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ss := &GitHubSourceStatus{}
+			ss.InitializeConditions()
+			if test.markSecrets {
+				ss.MarkSecrets()
+			} else {
+				ss.MarkNoSecrets("NotFound", "testing")
+			}
+			if test.markSink {
+				ss.MarkSink("http://example.com/")
+			} else {
+				ss.MarkNoSink("NotFound", "testing")
+			}
+			if test.markDeployed {
+				ss.MarkDeployed()
+			} else {
+				ss.MarkNotDeployed("NotDeployed", "testing")
+			}
+			if test.markWebhook {
+				ss.MarkWebhook("7")
+			} else {
+				ss.MarkNoWebhook("CreationFailed", "testing")
+			}
+			if got := ss.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
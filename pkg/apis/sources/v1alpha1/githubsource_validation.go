@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func (s *GitHubSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *GitHubSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.OwnerAndRepository == "" {
+		errs = errs.Also(apis.ErrMissingField("ownerAndRepository"))
+	} else if parts := strings.Split(ss.OwnerAndRepository, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		fe := apis.ErrInvalidValue(ss.OwnerAndRepository, "ownerAndRepository")
+		fe.Details = "expected the form 'owner/repository'"
+		errs = errs.Also(fe)
+	}
+	if len(ss.EventTypes) == 0 {
+		errs = errs.Also(apis.ErrMissingField("eventTypes"))
+	}
+	for i, et := range ss.EventTypes {
+		if et == "" {
+			errs = errs.Also(apis.ErrInvalidValue(et, apis.CurrentField).ViaFieldIndex("eventTypes", i))
+		}
+	}
+	if ss.AccessToken == nil {
+		errs = errs.Also(apis.ErrMissingField("accessToken"))
+	} else if fe := validateSecretKeySelector(ss.AccessToken); fe != nil {
+		errs = errs.Also(fe.ViaField("accessToken"))
+	}
+	if ss.SecretToken == nil {
+		errs = errs.Also(apis.ErrMissingField("secretToken"))
+	} else if fe := validateSecretKeySelector(ss.SecretToken); fe != nil {
+		errs = errs.Also(fe.ViaField("secretToken"))
+	}
+	if ss.GitHubAPIURL != "" {
+		if u, err := url.Parse(ss.GitHubAPIURL); err != nil || !u.IsAbs() {
+			errs = errs.Also(apis.ErrInvalidValue(ss.GitHubAPIURL, "githubAPIURL"))
+		}
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+func validateSecretKeySelector(s *corev1.SecretKeySelector) *apis.FieldError {
+	var errs *apis.FieldError
+	if s.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+	if s.Key == "" {
+		errs = errs.Also(apis.ErrMissingField("key"))
+	}
+	return errs
+}
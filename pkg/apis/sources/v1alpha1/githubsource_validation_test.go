@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGitHubSourceValidation(t *testing.T) {
+	sink := &corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Broker",
+		Name:       "default",
+	}
+	secret := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "github-secret"},
+			Key:                  key,
+		}
+	}
+	valid := func() GitHubSourceSpec {
+		return GitHubSourceSpec{
+			OwnerAndRepository: "knative/eventing",
+			EventTypes:         []string{"push", "pull_request"},
+			AccessToken:        secret("accessToken"),
+			SecretToken:        secret("secretToken"),
+			Sink:               sink,
+		}
+	}
+	tests := []struct {
+		name string
+		spec func(*GitHubSourceSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: func(*GitHubSourceSpec) {},
+		want: nil,
+	}, {
+		name: "valid for GitHub Enterprise",
+		spec: func(s *GitHubSourceSpec) {
+			s.GitHubAPIURL = "https://github.example.com/api/v3/"
+		},
+		want: nil,
+	}, {
+		name: "missing repository",
+		spec: func(s *GitHubSourceSpec) {
+			s.OwnerAndRepository = ""
+		},
+		want: apis.ErrMissingField("spec.ownerAndRepository"),
+	}, {
+		name: "invalid repository",
+		spec: func(s *GitHubSourceSpec) {
+			s.OwnerAndRepository = "knative"
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("knative", "spec.ownerAndRepository")
+			fe.Details = "expected the form 'owner/repository'"
+			return fe
+		}(),
+	}, {
+		name: "missing event types",
+		spec: func(s *GitHubSourceSpec) {
+			s.EventTypes = nil
+		},
+		want: apis.ErrMissingField("spec.eventTypes"),
+	}, {
+		name: "empty event type",
+		spec: func(s *GitHubSourceSpec) {
+			s.EventTypes = []string{"push", ""}
+		},
+		want: apis.ErrInvalidValue("", "spec.eventTypes[1]"),
+	}, {
+		name: "missing tokens",
+		spec: func(s *GitHubSourceSpec) {
+			s.AccessToken = nil
+			s.SecretToken = secret("")
+		},
+		want: apis.ErrMissingField("spec.accessToken", "spec.secretToken.key"),
+	}, {
+		name: "invalid API URL",
+		spec: func(s *GitHubSourceSpec) {
+			s.GitHubAPIURL = "api.github.com"
+		},
+		want: apis.ErrInvalidValue("api.github.com", "spec.githubAPIURL"),
+	}, {
+		name: "missing sink",
+		spec: func(s *GitHubSourceSpec) {
+			s.Sink = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &GitHubSource{Spec: valid()}
+			test.spec(&cr.Spec)
+			got := cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/knative/pkg/apis/duck"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+)
+
+func TestTypesImplements(t *testing.T) {
+	testCases := []struct {
+		instance interface{}
+		iface    duck.Implementable
+	}{
+		// ApiServerSource
+		{instance: &ApiServerSource{}, iface: &duckv1alpha1.Conditions{}},
+		// AwsSqsSource
+		{instance: &AwsSqsSource{}, iface: &duckv1alpha1.Conditions{}},
+		// ContainerSource
+		{instance: &ContainerSource{}, iface: &duckv1alpha1.Conditions{}},
+		// CronJobSource
+		{instance: &CronJobSource{}, iface: &duckv1alpha1.Conditions{}},
+		// GitHubSource
+		{instance: &GitHubSource{}, iface: &duckv1alpha1.Conditions{}},
+		// KafkaSource
+		{instance: &KafkaSource{}, iface: &duckv1alpha1.Conditions{}},
+		// MQTTSource
+		{instance: &MQTTSource{}, iface: &duckv1alpha1.Conditions{}},
+		// SinkBinding
+		{instance: &SinkBinding{}, iface: &duckv1alpha1.Conditions{}},
+		// WebhookSource
+		{instance: &WebhookSource{}, iface: &duckv1alpha1.Conditions{}},
+	}
+	for _, tc := range testCases {
+		if err := duck.VerifyType(tc.instance, tc.iface); err != nil {
+			t.Error(err)
+		}
+	}
+}
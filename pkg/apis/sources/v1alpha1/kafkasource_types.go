@@ -0,0 +1,215 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KafkaSource consumes the records of Kafka topics and sends them to a sink as events.
+type KafkaSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the KafkaSource.
+	Spec KafkaSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the KafkaSource. This data may be out of
+	// date.
+	// +optional
+	Status KafkaSourceStatus `json:"status,omitempty"`
+}
+
+// Check that KafkaSource can be validated and can be defaulted.
+var _ apis.Validatable = (*KafkaSource)(nil)
+var _ apis.Defaultable = (*KafkaSource)(nil)
+var _ runtime.Object = (*KafkaSource)(nil)
+var _ webhook.GenericCRD = (*KafkaSource)(nil)
+
+const (
+	// KafkaEventType is the type of the events sent by KafkaSources.
+	KafkaEventType = "dev.knative.kafka.event"
+
+	// DefaultKafkaMaxRetries is the number of times a record the sink does not accept is sent
+	// again by default.
+	DefaultKafkaMaxRetries = 10
+)
+
+// KafkaSourceSpec specifies the Kafka topics a KafkaSource consumes, and where their records are
+// sent.
+type KafkaSourceSpec struct {
+	// BootstrapServers are the addresses of the Kafka brokers the consumer connects to first,
+	// such as 'my-cluster-kafka-bootstrap.kafka:9092'.
+	BootstrapServers []string `json:"bootstrapServers,omitempty"`
+
+	// Topics are the Kafka topics that are consumed.
+	Topics []string `json:"topics,omitempty"`
+
+	// ConsumerGroup is the Kafka consumer group of the consumer. The consumer resumes from the
+	// offsets committed by the group.
+	ConsumerGroup string `json:"consumerGroup,omitempty"`
+
+	// Net configures the authentication and encryption of the connections to Kafka.
+	// +optional
+	Net KafkaSourceNetSpec `json:"net,omitempty"`
+
+	// MaxRetries is the number of times a record the sink does not accept is sent again before
+	// it is dropped. The records the sink rejects with a permanent status, such as 400 Bad
+	// Request, are dropped without being sent again. Defaults to 10.
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the KafkaSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+// KafkaSourceNetSpec configures the connections to Kafka.
+type KafkaSourceNetSpec struct {
+	// +optional
+	SASL KafkaSourceSASLSpec `json:"sasl,omitempty"`
+	// +optional
+	TLS KafkaSourceTLSSpec `json:"tls,omitempty"`
+}
+
+// KafkaSourceSASLSpec configures the SASL/PLAIN authentication to Kafka.
+type KafkaSourceSASLSpec struct {
+	Enable bool `json:"enable,omitempty"`
+
+	// User selects the key of a Secret holding the SASL user.
+	// +optional
+	User *corev1.SecretKeySelector `json:"user,omitempty"`
+
+	// Password selects the key of a Secret holding the SASL password.
+	// +optional
+	Password *corev1.SecretKeySelector `json:"password,omitempty"`
+}
+
+// KafkaSourceTLSSpec configures the TLS connections to Kafka.
+type KafkaSourceTLSSpec struct {
+	Enable bool `json:"enable,omitempty"`
+
+	// Cert selects the key of a Secret holding the PEM certificate of the client, for mutual TLS.
+	// +optional
+	Cert *corev1.SecretKeySelector `json:"cert,omitempty"`
+
+	// Key selects the key of a Secret holding the PEM private key of the client, for mutual TLS.
+	// +optional
+	Key *corev1.SecretKeySelector `json:"key,omitempty"`
+
+	// CACert selects the key of a Secret holding the PEM certificate of the authority that
+	// signed the certificates of the brokers. Defaults to the system authorities.
+	// +optional
+	CACert *corev1.SecretKeySelector `json:"caCert,omitempty"`
+}
+
+var kafkaSourceCondSet = duckv1alpha1.NewLivingConditionSet(KafkaSourceConditionSinkProvided, KafkaSourceConditionDeployed)
+
+// KafkaSourceStatus represents the current state of a KafkaSource.
+type KafkaSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this KafkaSource.
+	// It corresponds to the KafkaSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the KafkaSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Represents the latest available observations of a kafka source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// KafkaSourceConditionReady has status True when the receive adapter is
+	// consuming the topics and sending their records to the sink.
+	KafkaSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// KafkaSourceConditionSinkProvided has status True when the
+	// KafkaSource's sink has been resolved.
+	KafkaSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// KafkaSourceConditionDeployed has status True when the Deployment of
+	// the receive adapter has available replicas.
+	KafkaSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *KafkaSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return kafkaSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *KafkaSourceStatus) IsReady() bool {
+	return kafkaSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *KafkaSourceStatus) InitializeConditions() {
+	kafkaSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets KafkaSourceConditionSinkProvided condition to True state, and records the URI of
+// the sink.
+func (ss *KafkaSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	kafkaSourceCondSet.Manage(ss).MarkTrue(KafkaSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets KafkaSourceConditionSinkProvided condition to False state.
+func (ss *KafkaSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	kafkaSourceCondSet.Manage(ss).MarkFalse(KafkaSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets KafkaSourceConditionDeployed condition to True state.
+func (ss *KafkaSourceStatus) MarkDeployed() {
+	kafkaSourceCondSet.Manage(ss).MarkTrue(KafkaSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets KafkaSourceConditionDeployed condition to False state.
+func (ss *KafkaSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	kafkaSourceCondSet.Manage(ss).MarkFalse(KafkaSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KafkaSourceList is a collection of KafkaSources.
+type KafkaSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafkaSource `json:"items"`
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func (s *KafkaSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *KafkaSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if len(ss.BootstrapServers) == 0 {
+		errs = errs.Also(apis.ErrMissingField("bootstrapServers"))
+	}
+	for i, server := range ss.BootstrapServers {
+		if server == "" {
+			errs = errs.Also(apis.ErrInvalidValue(server, apis.CurrentField).ViaFieldIndex("bootstrapServers", i))
+		}
+	}
+	if len(ss.Topics) == 0 {
+		errs = errs.Also(apis.ErrMissingField("topics"))
+	}
+	for i, topic := range ss.Topics {
+		if topic == "" {
+			errs = errs.Also(apis.ErrInvalidValue(topic, apis.CurrentField).ViaFieldIndex("topics", i))
+		}
+	}
+	if ss.ConsumerGroup == "" {
+		errs = errs.Also(apis.ErrMissingField("consumerGroup"))
+	}
+	errs = errs.Also(ss.Net.Validate().ViaField("net"))
+	if ss.MaxRetries != nil && *ss.MaxRetries < 0 {
+		fe := apis.ErrInvalidValue(fmt.Sprintf("%d", *ss.MaxRetries), "maxRetries")
+		fe.Details = "the number of retries must not be negative"
+		errs = errs.Also(fe)
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+func (ns *KafkaSourceNetSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ns.SASL.Enable {
+		errs = errs.Also(validateRequiredSecret(ns.SASL.User, "user").ViaField("sasl"))
+		errs = errs.Also(validateRequiredSecret(ns.SASL.Password, "password").ViaField("sasl"))
+	}
+	if ns.TLS.Enable {
+		if (ns.TLS.Cert == nil) != (ns.TLS.Key == nil) {
+			fe := apis.ErrMissingField("tls.cert", "tls.key")
+			fe.Details = "the client certificate and key are set together"
+			errs = errs.Also(fe)
+		}
+		errs = errs.Also(validateOptionalSecret(ns.TLS.Cert, "cert").ViaField("tls"))
+		errs = errs.Also(validateOptionalSecret(ns.TLS.Key, "key").ViaField("tls"))
+		errs = errs.Also(validateOptionalSecret(ns.TLS.CACert, "caCert").ViaField("tls"))
+	}
+	return errs
+}
+
+// validateRequiredSecret validates the required secret key selector s of field.
+func validateRequiredSecret(s *corev1.SecretKeySelector, field string) *apis.FieldError {
+	if s == nil {
+		return apis.ErrMissingField(field)
+	}
+	return validateOptionalSecret(s, field)
+}
+
+// validateOptionalSecret validates the secret key selector s of field, if it is set.
+func validateOptionalSecret(s *corev1.SecretKeySelector, field string) *apis.FieldError {
+	if s == nil {
+		return nil
+	}
+	if fe := validateSecretKeySelector(s); fe != nil {
+		return fe.ViaField(field)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestKafkaSourceValidation(t *testing.T) {
+	secret := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "kafka-secret"},
+			Key:                  key,
+		}
+	}
+	valid := func() KafkaSourceSpec {
+		return KafkaSourceSpec{
+			BootstrapServers: []string{"my-cluster-kafka-bootstrap.kafka:9092"},
+			Topics:           []string{"orders"},
+			ConsumerGroup:    "orders-source",
+			Sink: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "orders",
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		spec func(*KafkaSourceSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: func(*KafkaSourceSpec) {},
+		want: nil,
+	}, {
+		name: "valid with SASL and mutual TLS",
+		spec: func(s *KafkaSourceSpec) {
+			s.Net.SASL = KafkaSourceSASLSpec{Enable: true, User: secret("user"), Password: secret("password")}
+			s.Net.TLS = KafkaSourceTLSSpec{Enable: true, Cert: secret("tls.crt"), Key: secret("tls.key"), CACert: secret("ca.crt")}
+		},
+		want: nil,
+	}, {
+		name: "missing servers, topics and consumer group",
+		spec: func(s *KafkaSourceSpec) {
+			s.BootstrapServers = nil
+			s.Topics = nil
+			s.ConsumerGroup = ""
+		},
+		want: apis.ErrMissingField("spec.bootstrapServers", "spec.consumerGroup", "spec.topics"),
+	}, {
+		name: "empty topic",
+		spec: func(s *KafkaSourceSpec) {
+			s.Topics = []string{"orders", ""}
+		},
+		want: apis.ErrInvalidValue("", "spec.topics[1]"),
+	}, {
+		name: "SASL without credentials",
+		spec: func(s *KafkaSourceSpec) {
+			s.Net.SASL = KafkaSourceSASLSpec{Enable: true, User: secret("")}
+		},
+		want: apis.ErrMissingField("spec.net.sasl.password", "spec.net.sasl.user.key"),
+	}, {
+		name: "TLS certificate without key",
+		spec: func(s *KafkaSourceSpec) {
+			s.Net.TLS = KafkaSourceTLSSpec{Enable: true, Cert: secret("tls.crt")}
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.net.tls.cert", "spec.net.tls.key")
+			fe.Details = "the client certificate and key are set together"
+			return fe
+		}(),
+	}, {
+		name: "negative max retries",
+		spec: func(s *KafkaSourceSpec) {
+			retries := int32(-1)
+			s.MaxRetries = &retries
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("-1", "spec.maxRetries")
+			fe.Details = "the number of retries must not be negative"
+			return fe
+		}(),
+	}, {
+		name: "disabled SASL is not validated",
+		spec: func(s *KafkaSourceSpec) {
+			s.Net.SASL = KafkaSourceSASLSpec{User: secret("")}
+		},
+		want: nil,
+	}, {
+		name: "missing sink",
+		spec: func(s *KafkaSourceSpec) {
+			s.Sink = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &KafkaSource{Spec: valid()}
+			test.spec(&cr.Spec)
+			got := cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+func (s *MQTTSource) SetDefaults() {
+	s.Spec.SetDefaults()
+}
+
+func (ss *MQTTSourceSpec) SetDefaults() {
+	if ss.QoS == nil {
+		qos := int32(DefaultMQTTQoS)
+		ss.QoS = &qos
+	}
+	for i := range ss.Topics {
+		if ss.Topics[i].EventType == "" {
+			ss.Topics[i].EventType = MQTTEventType
+		}
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MQTTSource subscribes to the topics of an MQTT broker and sends their messages to a sink as
+// events.
+type MQTTSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the MQTTSource.
+	Spec MQTTSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the MQTTSource. This data may be out of
+	// date.
+	// +optional
+	Status MQTTSourceStatus `json:"status,omitempty"`
+}
+
+// Check that MQTTSource can be validated and can be defaulted.
+var _ apis.Validatable = (*MQTTSource)(nil)
+var _ apis.Defaultable = (*MQTTSource)(nil)
+var _ runtime.Object = (*MQTTSource)(nil)
+var _ webhook.GenericCRD = (*MQTTSource)(nil)
+
+const (
+	// MQTTEventType is the type of the events sent by MQTTSources by default.
+	MQTTEventType = "dev.knative.mqtt.message"
+
+	// MQTTTopicExtension is the CloudEvents extension holding the topic of the message of an
+	// event.
+	MQTTTopicExtension = "topic"
+
+	// DefaultMQTTQoS is the MQTT quality of service of the subscriptions by default.
+	DefaultMQTTQoS = 1
+)
+
+// MQTTSourceSpec specifies the MQTT broker and topics an MQTTSource subscribes to, and where their
+// messages are sent.
+type MQTTSourceSpec struct {
+	// Broker is the address of the MQTT broker, such as 'mosquitto.iot:1883'.
+	Broker string `json:"broker,omitempty"`
+
+	// Topics are the topic filters the source subscribes to, and the type of the events of their
+	// messages.
+	Topics []MQTTSourceTopic `json:"topics,omitempty"`
+
+	// ClientID is the MQTT client identifier of the receive adapter. The broker keeps the
+	// subscriptions and the undelivered messages of the client while it is disconnected.
+	// Defaults to '{namespace}.{name}'.
+	// +optional
+	ClientID string `json:"clientId,omitempty"`
+
+	// QoS is the MQTT quality of service of the subscriptions, 0 (at most once) or 1 (at least
+	// once). Defaults to 1.
+	// +optional
+	QoS *int32 `json:"qos,omitempty"`
+
+	// Net configures the authentication and encryption of the connection to the broker.
+	// +optional
+	Net MQTTSourceNetSpec `json:"net,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the MQTTSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+// MQTTSourceTopic is a topic filter an MQTTSource subscribes to.
+type MQTTSourceTopic struct {
+	// Filter is the MQTT topic filter, such as 'sensors/+/temperature'.
+	Filter string `json:"filter,omitempty"`
+
+	// EventType is the type of the events of the messages whose topic matches Filter. Defaults
+	// to 'dev.knative.mqtt.message'.
+	// +optional
+	EventType string `json:"eventType,omitempty"`
+}
+
+// MQTTSourceNetSpec configures the connection to the MQTT broker.
+type MQTTSourceNetSpec struct {
+	// User selects the key of a Secret holding the user name the client connects with.
+	// +optional
+	User *corev1.SecretKeySelector `json:"user,omitempty"`
+
+	// Password selects the key of a Secret holding the password of the user.
+	// +optional
+	Password *corev1.SecretKeySelector `json:"password,omitempty"`
+
+	// +optional
+	TLS MQTTSourceTLSSpec `json:"tls,omitempty"`
+}
+
+// MQTTSourceTLSSpec configures the TLS connection to the MQTT broker.
+type MQTTSourceTLSSpec struct {
+	Enable bool `json:"enable,omitempty"`
+
+	// Cert selects the key of a Secret holding the PEM certificate of the client, for mutual TLS.
+	// +optional
+	Cert *corev1.SecretKeySelector `json:"cert,omitempty"`
+
+	// Key selects the key of a Secret holding the PEM private key of the client, for mutual TLS.
+	// +optional
+	Key *corev1.SecretKeySelector `json:"key,omitempty"`
+
+	// CACert selects the key of a Secret holding the PEM certificate of the authority that
+	// signed the certificate of the broker. Defaults to the system authorities.
+	// +optional
+	CACert *corev1.SecretKeySelector `json:"caCert,omitempty"`
+}
+
+var mqttSourceCondSet = duckv1alpha1.NewLivingConditionSet(MQTTSourceConditionSinkProvided, MQTTSourceConditionDeployed)
+
+// MQTTSourceStatus represents the current state of an MQTTSource.
+type MQTTSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this MQTTSource.
+	// It corresponds to the MQTTSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the MQTTSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Represents the latest available observations of an MQTT source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// MQTTSourceConditionReady has status True when the receive adapter is
+	// subscribed to the topics and sending their messages to the sink.
+	MQTTSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// MQTTSourceConditionSinkProvided has status True when the
+	// MQTTSource's sink has been resolved.
+	MQTTSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// MQTTSourceConditionDeployed has status True when the Deployment of
+	// the receive adapter has available replicas.
+	MQTTSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *MQTTSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return mqttSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *MQTTSourceStatus) IsReady() bool {
+	return mqttSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *MQTTSourceStatus) InitializeConditions() {
+	mqttSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets MQTTSourceConditionSinkProvided condition to True state, and records the URI of
+// the sink.
+func (ss *MQTTSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	mqttSourceCondSet.Manage(ss).MarkTrue(MQTTSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets MQTTSourceConditionSinkProvided condition to False state.
+func (ss *MQTTSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	mqttSourceCondSet.Manage(ss).MarkFalse(MQTTSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets MQTTSourceConditionDeployed condition to True state.
+func (ss *MQTTSourceStatus) MarkDeployed() {
+	mqttSourceCondSet.Manage(ss).MarkTrue(MQTTSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets MQTTSourceConditionDeployed condition to False state.
+func (ss *MQTTSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	mqttSourceCondSet.Manage(ss).MarkFalse(MQTTSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MQTTSourceList is a collection of MQTTSources.
+type MQTTSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MQTTSource `json:"items"`
+}
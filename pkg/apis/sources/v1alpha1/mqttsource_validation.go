@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+)
+
+func (s *MQTTSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *MQTTSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.Broker == "" {
+		errs = errs.Also(apis.ErrMissingField("broker"))
+	} else if _, _, err := net.SplitHostPort(ss.Broker); err != nil {
+		fe := apis.ErrInvalidValue(ss.Broker, "broker")
+		fe.Details = "the broker must be a host and a port"
+		errs = errs.Also(fe)
+	}
+	if len(ss.Topics) == 0 {
+		errs = errs.Also(apis.ErrMissingField("topics"))
+	}
+	for i, topic := range ss.Topics {
+		if topic.Filter == "" {
+			errs = errs.Also(apis.ErrMissingField("filter").ViaFieldIndex("topics", i))
+		} else if !validTopicFilter(topic.Filter) {
+			errs = errs.Also(apis.ErrInvalidValue(topic.Filter, "filter").ViaFieldIndex("topics", i))
+		}
+	}
+	if ss.QoS != nil && (*ss.QoS < 0 || *ss.QoS > 1) {
+		fe := apis.ErrInvalidValue(fmt.Sprintf("%d", *ss.QoS), "qos")
+		fe.Details = "the quality of service must be 0 or 1"
+		errs = errs.Also(fe)
+	}
+	errs = errs.Also(ss.Net.Validate().ViaField("net"))
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+func (ns *MQTTSourceNetSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ns.Password != nil && ns.User == nil {
+		fe := apis.ErrMissingField("user")
+		fe.Details = "a password is set with a user"
+		errs = errs.Also(fe)
+	}
+	errs = errs.Also(validateOptionalSecret(ns.User, "user"))
+	errs = errs.Also(validateOptionalSecret(ns.Password, "password"))
+	if ns.TLS.Enable {
+		if (ns.TLS.Cert == nil) != (ns.TLS.Key == nil) {
+			fe := apis.ErrMissingField("tls.cert", "tls.key")
+			fe.Details = "the client certificate and key are set together"
+			errs = errs.Also(fe)
+		}
+		errs = errs.Also(validateOptionalSecret(ns.TLS.Cert, "cert").ViaField("tls"))
+		errs = errs.Also(validateOptionalSecret(ns.TLS.Key, "key").ViaField("tls"))
+		errs = errs.Also(validateOptionalSecret(ns.TLS.CACert, "caCert").ViaField("tls"))
+	}
+	return errs
+}
+
+// validTopicFilter returns true if filter is a valid MQTT topic filter, whose multi-level wildcard
+// '#' can only be its last level, and whose single-level wildcards '+' are whole levels.
+func validTopicFilter(filter string) bool {
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
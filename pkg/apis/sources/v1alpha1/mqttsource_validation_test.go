@@ -0,0 +1,155 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMQTTSourceValidation(t *testing.T) {
+	secret := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "mqtt-secret"},
+			Key:                  key,
+		}
+	}
+	valid := func() MQTTSourceSpec {
+		return MQTTSourceSpec{
+			Broker: "mosquitto.iot:1883",
+			Topics: []MQTTSourceTopic{{Filter: "sensors/+/temperature"}, {Filter: "alarms/#"}},
+			Sink: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "readings",
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		spec func(*MQTTSourceSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: func(*MQTTSourceSpec) {},
+		want: nil,
+	}, {
+		name: "valid with credentials and mutual TLS",
+		spec: func(s *MQTTSourceSpec) {
+			s.Broker = "mosquitto.iot:8883"
+			s.Net.User = secret("user")
+			s.Net.Password = secret("password")
+			s.Net.TLS = MQTTSourceTLSSpec{Enable: true, Cert: secret("tls.crt"), Key: secret("tls.key"), CACert: secret("ca.crt")}
+		},
+		want: nil,
+	}, {
+		name: "missing broker and topics",
+		spec: func(s *MQTTSourceSpec) {
+			s.Broker = ""
+			s.Topics = nil
+		},
+		want: apis.ErrMissingField("spec.broker", "spec.topics"),
+	}, {
+		name: "broker without port",
+		spec: func(s *MQTTSourceSpec) {
+			s.Broker = "mosquitto.iot"
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("mosquitto.iot", "spec.broker")
+			fe.Details = "the broker must be a host and a port"
+			return fe
+		}(),
+	}, {
+		name: "invalid topic filters",
+		spec: func(s *MQTTSourceSpec) {
+			s.Topics = []MQTTSourceTopic{{Filter: "sensors/#/temperature"}, {Filter: "sensors/a+"}, {}}
+		},
+		want: apis.ErrInvalidValue("sensors/#/temperature", "spec.topics[0].filter").Also(
+			apis.ErrInvalidValue("sensors/a+", "spec.topics[1].filter")).Also(
+			apis.ErrMissingField("spec.topics[2].filter")),
+	}, {
+		name: "unsupported QoS",
+		spec: func(s *MQTTSourceSpec) {
+			qos := int32(2)
+			s.QoS = &qos
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("2", "spec.qos")
+			fe.Details = "the quality of service must be 0 or 1"
+			return fe
+		}(),
+	}, {
+		name: "password without user",
+		spec: func(s *MQTTSourceSpec) {
+			s.Net.Password = secret("password")
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.net.user")
+			fe.Details = "a password is set with a user"
+			return fe
+		}(),
+	}, {
+		name: "TLS certificate without key",
+		spec: func(s *MQTTSourceSpec) {
+			s.Net.TLS = MQTTSourceTLSSpec{Enable: true, Cert: secret("tls.crt")}
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.net.tls.cert", "spec.net.tls.key")
+			fe.Details = "the client certificate and key are set together"
+			return fe
+		}(),
+	}, {
+		name: "missing sink",
+		spec: func(s *MQTTSourceSpec) {
+			s.Sink = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &MQTTSource{Spec: valid()}
+			test.spec(&cr.Spec)
+			got := cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestMQTTSourceDefaults(t *testing.T) {
+	s := &MQTTSource{Spec: MQTTSourceSpec{
+		Topics: []MQTTSourceTopic{{Filter: "sensors/#"}, {Filter: "alarms/#", EventType: "com.example.alarm"}},
+	}}
+	s.SetDefaults()
+	if s.Spec.QoS == nil || *s.Spec.QoS != 1 {
+		t.Errorf("Unexpected QoS %v", s.Spec.QoS)
+	}
+	if got := s.Spec.Topics[0].EventType; got != MQTTEventType {
+		t.Errorf("Unexpected default event type %q", got)
+	}
+	if got := s.Spec.Topics[1].EventType; got != "com.example.alarm" {
+		t.Errorf("Unexpected event type %q", got)
+	}
+}
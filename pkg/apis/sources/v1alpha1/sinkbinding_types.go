@@ -0,0 +1,162 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SinkBinding injects the URI of a sink into the containers of a workload, so that the workload
+// can send events to the sink without a source controller of its own.
+type SinkBinding struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the SinkBinding.
+	Spec SinkBindingSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the SinkBinding. This data may be out of
+	// date.
+	// +optional
+	Status SinkBindingStatus `json:"status,omitempty"`
+}
+
+// Check that SinkBinding can be validated, can be defaulted, and has immutable fields.
+var _ apis.Validatable = (*SinkBinding)(nil)
+var _ apis.Defaultable = (*SinkBinding)(nil)
+var _ apis.Immutable = (*SinkBinding)(nil)
+var _ runtime.Object = (*SinkBinding)(nil)
+var _ webhook.GenericCRD = (*SinkBinding)(nil)
+
+const (
+	// SinkBindingEnvVar is the environment variable of the containers of the subject of a
+	// SinkBinding that holds the URI of the sink.
+	SinkBindingEnvVar = "K_SINK"
+)
+
+// SinkBindingSubjectKinds are the kinds of workload that can be the subject of a SinkBinding.
+var SinkBindingSubjectKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+}
+
+// SinkBindingSpec specifies the workload a SinkBinding binds, and the sink it is bound to.
+type SinkBindingSpec struct {
+	// Subject is a reference to the workload, in the SinkBinding's namespace, whose containers
+	// are given the URI of the sink. It must be a Deployment, a StatefulSet or a Job, and cannot
+	// be changed.
+	Subject corev1.ObjectReference `json:"subject,omitempty"`
+
+	// Sink is a reference to the addressable, in the SinkBinding's namespace, whose URI is given
+	// to the subject.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+var sinkBindingCondSet = duckv1alpha1.NewLivingConditionSet(SinkBindingConditionSinkProvided, SinkBindingConditionBound)
+
+// SinkBindingStatus represents the current state of a SinkBinding.
+type SinkBindingStatus struct {
+	// ObservedGeneration is the most recent generation observed for this SinkBinding.
+	// It corresponds to the SinkBinding's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the SinkBinding's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Represents the latest available observations of a sink binding's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// SinkBindingConditionReady has status True when the containers of the
+	// subject are given the URI of the sink.
+	SinkBindingConditionReady = duckv1alpha1.ConditionReady
+
+	// SinkBindingConditionSinkProvided has status True when the
+	// SinkBinding's sink has been resolved.
+	SinkBindingConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// SinkBindingConditionBound has status True when the pod template of the
+	// subject gives the URI of the sink to its containers.
+	SinkBindingConditionBound duckv1alpha1.ConditionType = "Bound"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *SinkBindingStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return sinkBindingCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *SinkBindingStatus) IsReady() bool {
+	return sinkBindingCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *SinkBindingStatus) InitializeConditions() {
+	sinkBindingCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets SinkBindingConditionSinkProvided condition to True state, and records the URI
+// of the sink.
+func (ss *SinkBindingStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	sinkBindingCondSet.Manage(ss).MarkTrue(SinkBindingConditionSinkProvided)
+}
+
+// MarkNoSink sets SinkBindingConditionSinkProvided condition to False state.
+func (ss *SinkBindingStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	sinkBindingCondSet.Manage(ss).MarkFalse(SinkBindingConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkBound sets SinkBindingConditionBound condition to True state.
+func (ss *SinkBindingStatus) MarkBound() {
+	sinkBindingCondSet.Manage(ss).MarkTrue(SinkBindingConditionBound)
+}
+
+// MarkNotBound sets SinkBindingConditionBound condition to False state.
+func (ss *SinkBindingStatus) MarkNotBound(reason, messageFormat string, messageA ...interface{}) {
+	sinkBindingCondSet.Manage(ss).MarkFalse(SinkBindingConditionBound, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SinkBindingList is a collection of SinkBindings.
+type SinkBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SinkBinding `json:"items"`
+}
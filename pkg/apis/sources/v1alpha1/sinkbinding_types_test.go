@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSinkBindingInitializeConditions(t *testing.T) {
+	bs := &SinkBindingStatus{}
+	bs.InitializeConditions()
+	want := &SinkBindingStatus{
+		Conditions: []duckv1alpha1.Condition{{
+			Type:   SinkBindingConditionBound,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   SinkBindingConditionReady,
+			Status: corev1.ConditionUnknown,
+		}, {
+			Type:   SinkBindingConditionSinkProvided,
+			Status: corev1.ConditionUnknown,
+		}},
+	}
+	if diff := cmp.Diff(want, bs, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestSinkBindingIsReady(t *testing.T) {
+	tests := []struct {
+		name      string
+		markSink  bool
+		markBound bool
+		wantReady bool
+	}{{
+		name:      "all happy",
+		markSink:  true,
+		markBound: true,
+		wantReady: true,
+	}, {
+		name:      "sink sad",
+		markSink:  false,
+		markBound: true,
+		wantReady: false,
+	}, {
+		name:      "bound sad",
+		markSink:  true,
+		markBound: false,
+		wantReady: false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bs := &SinkBindingStatus{}
+			bs.InitializeConditions()
+			if test.markSink {
+				bs.MarkSink("http://example.com/")
+			} else {
+				bs.MarkNoSink("NotFound", "testing")
+			}
+			if test.markBound {
+				bs.MarkBound()
+			} else {
+				bs.MarkNotBound("NotBound", "testing")
+			}
+			if got := bs.IsReady(); test.wantReady != got {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
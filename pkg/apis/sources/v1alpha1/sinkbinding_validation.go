@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func (b *SinkBinding) Validate() *apis.FieldError {
+	return b.Spec.Validate().ViaField("spec")
+}
+
+func (bs *SinkBindingSpec) Validate() *apis.FieldError {
+	errs := validateSubject(&bs.Subject).ViaField("subject")
+	if bs.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the binding must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(bs.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
+
+// validateSubject validates the subject of a SinkBinding, which must be one of the
+// SinkBindingSubjectKinds.
+func validateSubject(subject *corev1.ObjectReference) *apis.FieldError {
+	var errs *apis.FieldError
+	if subject.APIVersion == "" {
+		errs = errs.Also(apis.ErrMissingField("apiVersion"))
+	}
+	if subject.Kind == "" {
+		errs = errs.Also(apis.ErrMissingField("kind"))
+	}
+	if subject.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+	if subject.Namespace != "" {
+		fe := apis.ErrDisallowedFields("namespace")
+		fe.Details = "the subject must be in the namespace of the binding"
+		errs = errs.Also(fe)
+	}
+	if errs != nil {
+		return errs
+	}
+	gvk := schema.FromAPIVersionAndKind(subject.APIVersion, subject.Kind)
+	var supported []string
+	for _, k := range SinkBindingSubjectKinds {
+		if k == gvk {
+			return nil
+		}
+		supported = append(supported, fmt.Sprintf("%s %s", k.GroupVersion(), k.Kind))
+	}
+	fe := apis.ErrInvalidValue(fmt.Sprintf("%s %s", subject.APIVersion, subject.Kind), "kind")
+	fe.Details = "the subject must be one of " + strings.Join(supported, ", ")
+	return fe
+}
+
+func (current *SinkBinding) CheckImmutableFields(og apis.Immutable) *apis.FieldError {
+	if og == nil {
+		return nil
+	}
+	original, ok := og.(*SinkBinding)
+	if !ok {
+		return &apis.FieldError{Message: "The provided resource was not a SinkBinding"}
+	}
+	if diff := cmp.Diff(original.Spec.Subject, current.Spec.Subject); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.subject"},
+		}
+	}
+	return nil
+}
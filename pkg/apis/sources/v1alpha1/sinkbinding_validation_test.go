@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSinkBindingValidation(t *testing.T) {
+	valid := func() SinkBindingSpec {
+		return SinkBindingSpec{
+			Subject: corev1.ObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "emitter",
+			},
+			Sink: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "events",
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		spec func(*SinkBindingSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: func(*SinkBindingSpec) {},
+		want: nil,
+	}, {
+		name: "valid job",
+		spec: func(s *SinkBindingSpec) {
+			s.Subject = corev1.ObjectReference{APIVersion: "batch/v1", Kind: "Job", Name: "emitter"}
+		},
+		want: nil,
+	}, {
+		name: "missing subject",
+		spec: func(s *SinkBindingSpec) {
+			s.Subject = corev1.ObjectReference{}
+		},
+		want: apis.ErrMissingField("spec.subject.apiVersion", "spec.subject.kind", "spec.subject.name"),
+	}, {
+		name: "subject in another namespace",
+		spec: func(s *SinkBindingSpec) {
+			s.Subject.Namespace = "other"
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrDisallowedFields("spec.subject.namespace")
+			fe.Details = "the subject must be in the namespace of the binding"
+			return fe
+		}(),
+	}, {
+		name: "unsupported subject",
+		spec: func(s *SinkBindingSpec) {
+			s.Subject.Kind = "DaemonSet"
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("apps/v1 DaemonSet", "spec.subject.kind")
+			fe.Details = "the subject must be one of apps/v1 Deployment, apps/v1 StatefulSet, batch/v1 Job"
+			return fe
+		}(),
+	}, {
+		name: "missing sink",
+		spec: func(s *SinkBindingSpec) {
+			s.Sink = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the binding must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &SinkBinding{Spec: valid()}
+			test.spec(&b.Spec)
+			got := b.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestSinkBindingImmutableFields(t *testing.T) {
+	binding := func(subject string) *SinkBinding {
+		return &SinkBinding{
+			Spec: SinkBindingSpec{
+				Subject: corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: subject},
+				Sink:    &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "sink"},
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		new  *SinkBinding
+		old  apis.Immutable
+		want *apis.FieldError
+	}{{
+		name: "create",
+		new:  binding("emitter"),
+		old:  nil,
+		want: nil,
+	}, {
+		name: "sink changed",
+		new: func() *SinkBinding {
+			b := binding("emitter")
+			b.Spec.Sink.Name = "other"
+			return b
+		}(),
+		old:  binding("emitter"),
+		want: nil,
+	}, {
+		name: "subject changed",
+		new:  binding("other"),
+		old:  binding("emitter"),
+		want: &apis.FieldError{
+			Message: "Immutable fields changed",
+			Paths:   []string{"spec.subject"},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.new.CheckImmutableFields(test.old)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("CheckImmutableFields (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
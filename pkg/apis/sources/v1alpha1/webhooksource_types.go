@@ -0,0 +1,174 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"github.com/knative/pkg/apis"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebhookSource exposes an HTTPS endpoint outside of the cluster, and sends the JSON payloads posted
+// to it to a sink as events.
+type WebhookSource struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the WebhookSource.
+	Spec WebhookSourceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the WebhookSource. This data may be out of
+	// date.
+	// +optional
+	Status WebhookSourceStatus `json:"status,omitempty"`
+}
+
+// Check that WebhookSource can be validated and can be defaulted.
+var _ apis.Validatable = (*WebhookSource)(nil)
+var _ apis.Defaultable = (*WebhookSource)(nil)
+var _ runtime.Object = (*WebhookSource)(nil)
+var _ webhook.GenericCRD = (*WebhookSource)(nil)
+
+const (
+	// DefaultWebhookSignatureHeader is the header holding the signature of the payloads by default.
+	DefaultWebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookSourceSpec specifies the events a WebhookSource makes of the payloads posted to its
+// endpoint, and where they are sent.
+type WebhookSourceSpec struct {
+	// EventType is the type of the events, such as 'com.example.order.created'.
+	EventType string `json:"eventType,omitempty"`
+
+	// EventSource is the source of the events. Defaults to the URL of the endpoint.
+	// +optional
+	EventSource string `json:"eventSource,omitempty"`
+
+	// Secret selects the key of a Secret holding the secret the payloads are signed with. If it
+	// is set, only the requests with the HMAC-SHA256 signature of their payload, as
+	// 'sha256={hex signature}', in SignatureHeader are accepted.
+	// +optional
+	Secret *corev1.SecretKeySelector `json:"secret,omitempty"`
+
+	// SignatureHeader is the header holding the signature of the payloads. Defaults to
+	// 'X-Webhook-Signature'.
+	// +optional
+	SignatureHeader string `json:"signatureHeader,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount the receive adapter runs as.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Sink is a reference to the addressable, in the WebhookSource's namespace, that receives
+	// the events.
+	Sink *corev1.ObjectReference `json:"sink,omitempty"`
+}
+
+var webhookSourceCondSet = duckv1alpha1.NewLivingConditionSet(WebhookSourceConditionSinkProvided, WebhookSourceConditionDeployed)
+
+// WebhookSourceStatus represents the current state of a WebhookSource.
+type WebhookSourceStatus struct {
+	// ObservedGeneration is the most recent generation observed for this WebhookSource.
+	// It corresponds to the WebhookSource's generation, which is updated on mutation by
+	// the API Server.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SinkURI is the resolved URI of the WebhookSource's sink.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// URL is the URL of the endpoint the payloads are posted to.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Represents the latest available observations of a webhook source's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions duckv1alpha1.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// WebhookSourceConditionReady has status True when the receive adapter is
+	// accepting the payloads posted to the endpoint and sending them to the sink.
+	WebhookSourceConditionReady = duckv1alpha1.ConditionReady
+
+	// WebhookSourceConditionSinkProvided has status True when the
+	// WebhookSource's sink has been resolved.
+	WebhookSourceConditionSinkProvided duckv1alpha1.ConditionType = "SinkProvided"
+
+	// WebhookSourceConditionDeployed has status True when the Deployment of
+	// the receive adapter has available replicas.
+	WebhookSourceConditionDeployed duckv1alpha1.ConditionType = "Deployed"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ss *WebhookSourceStatus) GetCondition(t duckv1alpha1.ConditionType) *duckv1alpha1.Condition {
+	return webhookSourceCondSet.Manage(ss).GetCondition(t)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *WebhookSourceStatus) IsReady() bool {
+	return webhookSourceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *WebhookSourceStatus) InitializeConditions() {
+	webhookSourceCondSet.Manage(ss).InitializeConditions()
+}
+
+// MarkSink sets WebhookSourceConditionSinkProvided condition to True state, and records the URI of
+// the sink.
+func (ss *WebhookSourceStatus) MarkSink(uri string) {
+	ss.SinkURI = uri
+	webhookSourceCondSet.Manage(ss).MarkTrue(WebhookSourceConditionSinkProvided)
+}
+
+// MarkNoSink sets WebhookSourceConditionSinkProvided condition to False state.
+func (ss *WebhookSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	ss.SinkURI = ""
+	webhookSourceCondSet.Manage(ss).MarkFalse(WebhookSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// MarkDeployed sets WebhookSourceConditionDeployed condition to True state.
+func (ss *WebhookSourceStatus) MarkDeployed() {
+	webhookSourceCondSet.Manage(ss).MarkTrue(WebhookSourceConditionDeployed)
+}
+
+// MarkNotDeployed sets WebhookSourceConditionDeployed condition to False state.
+func (ss *WebhookSourceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	webhookSourceCondSet.Manage(ss).MarkFalse(WebhookSourceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebhookSourceList is a collection of WebhookSources.
+type WebhookSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WebhookSource `json:"items"`
+}
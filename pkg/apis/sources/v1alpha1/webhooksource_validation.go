@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"regexp"
+
+	"github.com/knative/pkg/apis"
+)
+
+// headerNameRegexp matches the names of HTTP headers.
+var headerNameRegexp = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+func (s *WebhookSource) Validate() *apis.FieldError {
+	return s.Spec.Validate().ViaField("spec")
+}
+
+func (ss *WebhookSourceSpec) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if ss.EventType == "" {
+		errs = errs.Also(apis.ErrMissingField("eventType"))
+	}
+	errs = errs.Also(validateOptionalSecret(ss.Secret, "secret"))
+	if ss.SignatureHeader != "" && !headerNameRegexp.MatchString(ss.SignatureHeader) {
+		errs = errs.Also(apis.ErrInvalidValue(ss.SignatureHeader, "signatureHeader"))
+	}
+	if ss.Sink == nil {
+		fe := apis.ErrMissingField("sink")
+		fe.Details = "the source must reference a sink"
+		errs = errs.Also(fe)
+	} else if fe := validateSink(ss.Sink); fe != nil {
+		errs = errs.Also(fe.ViaField("sink"))
+	}
+	return errs
+}
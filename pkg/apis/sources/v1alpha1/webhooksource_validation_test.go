@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWebhookSourceValidation(t *testing.T) {
+	valid := func() WebhookSourceSpec {
+		return WebhookSourceSpec{
+			EventType: "com.example.order.created",
+			Sink: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Channel",
+				Name:       "orders",
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		spec func(*WebhookSourceSpec)
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: func(*WebhookSourceSpec) {},
+		want: nil,
+	}, {
+		name: "valid with signature",
+		spec: func(s *WebhookSourceSpec) {
+			s.Secret = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "webhook-secret"},
+				Key:                  "secret",
+			}
+			s.SignatureHeader = "X-Shop-Hmac-Sha256"
+		},
+		want: nil,
+	}, {
+		name: "missing event type",
+		spec: func(s *WebhookSourceSpec) {
+			s.EventType = ""
+		},
+		want: apis.ErrMissingField("spec.eventType"),
+	}, {
+		name: "secret without key",
+		spec: func(s *WebhookSourceSpec) {
+			s.Secret = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "webhook-secret"},
+			}
+		},
+		want: apis.ErrMissingField("spec.secret.key"),
+	}, {
+		name: "invalid signature header",
+		spec: func(s *WebhookSourceSpec) {
+			s.SignatureHeader = "X Signature"
+		},
+		want: apis.ErrInvalidValue("X Signature", "spec.signatureHeader"),
+	}, {
+		name: "missing sink",
+		spec: func(s *WebhookSourceSpec) {
+			s.Sink = nil
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMissingField("spec.sink")
+			fe.Details = "the source must reference a sink"
+			return fe
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &WebhookSource{Spec: valid()}
+			test.spec(&cr.Spec)
+			got := cr.Validate()
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attribution implements a mutating admission webhook recording who created and who last
+// modified event routing resources, in their eventing.knative.dev/creator and
+// eventing.knative.dev/lastModifier annotations.
+package attribution
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/knative/eventing/pkg/admission"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingv1beta1 "github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	"github.com/knative/eventing/pkg/system"
+	"github.com/mattbaird/jsonpatch"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CreatorAnnotation is the name of the user who created the resource.
+	CreatorAnnotation = "eventing.knative.dev/creator"
+	// LastModifierAnnotation is the name of the user who last changed the spec of the resource.
+	LastModifierAnnotation = "eventing.knative.dev/lastModifier"
+)
+
+// systemServiceAccountPrefix prefixes the names of the service accounts of the system namespace,
+// those of the controllers and dispatchers.
+var systemServiceAccountPrefix = "system:serviceaccount:" + system.Namespace + ":"
+
+// Resources are the resources of the eventing.knative.dev group whose changes are attributed, in
+// both their v1alpha1 and v1beta1 versions.
+var Resources = []string{"channels", "subscriptions"}
+
+// Webhook is a mutating admission webhook annotating Resources with the user who created them
+// and the user who last changed their spec. The annotations cannot be set by the users: they are
+// overwritten on creation and restored on updates. The updates made by the service accounts of the
+// system namespace, such as the subscription controller keeping spec.subscribable of the Channels
+// in sync with their Subscriptions, do not change the last modifier.
+type Webhook struct {
+	Client  kubernetes.Interface
+	Options admission.Options
+	Logger  *zap.Logger
+}
+
+// Run registers the Webhook and serves admission requests until stop is closed.
+func (wh *Webhook) Run(stop <-chan struct{}) error {
+	return admission.Serve(wh.Logger, wh.Options, wh.register, wh, stop)
+}
+
+// register registers the Webhook as a mutating webhook of the Resources, trusting caCert.
+func (wh *Webhook) register(caCert []byte) error {
+	return admission.RegisterMutating(wh.Client, wh.Options, []admissionregistrationv1beta1.RuleWithOperations{{
+		Operations: []admissionregistrationv1beta1.OperationType{
+			admissionregistrationv1beta1.Create,
+			admissionregistrationv1beta1.Update,
+		},
+		Rule: admissionregistrationv1beta1.Rule{
+			APIGroups:   []string{eventingv1alpha1.SchemeGroupVersion.Group},
+			APIVersions: []string{eventingv1alpha1.SchemeGroupVersion.Version, eventingv1beta1.SchemeGroupVersion.Version},
+			Resources:   Resources,
+		},
+	}}, caCert)
+}
+
+// ServeHTTP implements the admission webhook.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admission.Handler(wh.admit).ServeHTTP(w, r)
+}
+
+// object is the part of the admitted resources the Webhook reads.
+type object struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     json.RawMessage   `json:"spec,omitempty"`
+}
+
+// admit annotates the resource in request with the user making the request. The resource is always
+// admitted.
+func (wh *Webhook) admit(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	allowed := &admissionv1beta1.AdmissionResponse{Allowed: true}
+	if request.Operation != admissionv1beta1.Create && request.Operation != admissionv1beta1.Update {
+		return allowed
+	}
+	var obj object
+	if err := json.Unmarshal(request.Object.Raw, &obj); err != nil {
+		wh.Logger.Error("Failed to decode the object", zap.Any("kind", request.Kind), zap.Error(err))
+		return allowed
+	}
+
+	user := request.UserInfo.Username
+	want := map[string]string{
+		CreatorAnnotation:      user,
+		LastModifierAnnotation: user,
+	}
+	if request.Operation == admissionv1beta1.Update {
+		var old object
+		if err := json.Unmarshal(request.OldObject.Raw, &old); err != nil {
+			wh.Logger.Error("Failed to decode the old object", zap.Any("kind", request.Kind), zap.Error(err))
+			return allowed
+		}
+		// The creator never changes, and the last modifier only changes with the spec, so that
+		// the updates made by the controllers, such as adding finalizers, are not attributed.
+		// The controllers also change the spec, those changes are not attributed either.
+		want[CreatorAnnotation] = old.Metadata.Annotations[CreatorAnnotation]
+		if bytes.Equal(compact(old.Spec), compact(obj.Spec)) || strings.HasPrefix(user, systemServiceAccountPrefix) {
+			want[LastModifierAnnotation] = old.Metadata.Annotations[LastModifierAnnotation]
+		}
+	}
+
+	patch := annotationsPatch(obj.Metadata.Annotations, want)
+	if len(patch) == 0 {
+		return allowed
+	}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		wh.Logger.Error("Failed to encode the patch", zap.Error(err))
+		return allowed
+	}
+	patchType := admissionv1beta1.PatchTypeJSONPatch
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     raw,
+		PatchType: &patchType,
+	}
+}
+
+// compact returns raw without insignificant whitespace, so that equal specs are equal bytes.
+func compact(raw json.RawMessage) []byte {
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return raw
+	}
+	return b.Bytes()
+}
+
+// annotationsPatch returns the JSON patch setting the annotations to the values in want, removing
+// those whose value is empty.
+func annotationsPatch(annotations map[string]string, want map[string]string) []jsonpatch.JsonPatchOperation {
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var patch []jsonpatch.JsonPatchOperation
+	if annotations == nil {
+		value := make(map[string]interface{})
+		for _, k := range keys {
+			if want[k] != "" {
+				value[k] = want[k]
+			}
+		}
+		if len(value) > 0 {
+			patch = append(patch, jsonpatch.NewPatch("add", "/metadata/annotations", value))
+		}
+		return patch
+	}
+	for _, k := range keys {
+		v, ok := annotations[k]
+		path := "/metadata/annotations/" + escape(k)
+		switch {
+		case want[k] == "" && ok:
+			patch = append(patch, jsonpatch.NewPatch("remove", path, nil))
+		case want[k] != "" && v != want[k]:
+			patch = append(patch, jsonpatch.NewPatch("add", path, want[k]))
+		}
+	}
+	return patch
+}
+
+// escape escapes key to be a JSON pointer reference token.
+func escape(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
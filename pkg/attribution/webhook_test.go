@@ -0,0 +1,283 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attribution
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingv1beta1 "github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	"github.com/mattbaird/jsonpatch"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	testNS     = "testnamespace"
+	user       = "alice@example.com"
+	controller = "system:serviceaccount:knative-eventing:eventing-controller"
+)
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation admissionv1beta1.Operation
+		user      string
+		object    runtime.Object
+		oldObject runtime.Object
+		wantPatch []jsonpatch.JsonPatchOperation
+	}{{
+		name:      "create",
+		operation: admissionv1beta1.Create,
+		object:    channel(nil, "in-memory"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations",
+			Value: map[string]interface{}{
+				CreatorAnnotation:      user,
+				LastModifierAnnotation: user,
+			},
+		}},
+	}, {
+		name:      "create with annotations",
+		operation: admissionv1beta1.Create,
+		object:    channel(map[string]string{"team": "orders", CreatorAnnotation: "mallory"}, "in-memory"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1creator",
+			Value:     user,
+		}, {
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1lastModifier",
+			Value:     user,
+		}},
+	}, {
+		name:      "create subscription",
+		operation: admissionv1beta1.Create,
+		object: &eventingv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "sub"},
+			Spec: eventingv1alpha1.SubscriptionSpec{
+				Channel: corev1.ObjectReference{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Channel", Name: "channel"},
+			},
+		},
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations",
+			Value: map[string]interface{}{
+				CreatorAnnotation:      user,
+				LastModifierAnnotation: user,
+			},
+		}},
+	}, {
+		name:      "spec updated",
+		operation: admissionv1beta1.Update,
+		object:    channel(attributed("bob", "bob"), "kafka"),
+		oldObject: channel(attributed("bob", "bob"), "in-memory"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1lastModifier",
+			Value:     user,
+		}},
+	}, {
+		name:      "spec unchanged",
+		operation: admissionv1beta1.Update,
+		object:    channel(attributed("bob", "carol"), "in-memory"),
+		oldObject: channel(attributed("bob", "carol"), "in-memory"),
+	}, {
+		name:      "subscribers patched by the controller",
+		operation: admissionv1beta1.Update,
+		user:      controller,
+		object:    subscribedChannel(attributed("bob", "carol"), "sub"),
+		oldObject: channel(attributed("bob", "carol"), "in-memory"),
+	}, {
+		name:      "attribution removed by the controller",
+		operation: admissionv1beta1.Update,
+		user:      controller,
+		object:    subscribedChannel(nil, "sub"),
+		oldObject: channel(attributed("bob", "carol"), "in-memory"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations",
+			Value: map[string]interface{}{
+				CreatorAnnotation:      "bob",
+				LastModifierAnnotation: "carol",
+			},
+		}},
+	}, {
+		name:      "attribution changed by the user",
+		operation: admissionv1beta1.Update,
+		object:    channel(attributed(user, user), "in-memory"),
+		oldObject: channel(attributed("bob", "carol"), "in-memory"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1creator",
+			Value:     "bob",
+		}, {
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1lastModifier",
+			Value:     "carol",
+		}},
+	}, {
+		name:      "created before the webhook",
+		operation: admissionv1beta1.Update,
+		object:    channel(map[string]string{CreatorAnnotation: user}, "kafka"),
+		oldObject: channel(nil, "in-memory"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "remove",
+			Path:      "/metadata/annotations/eventing.knative.dev~1creator",
+		}, {
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1lastModifier",
+			Value:     user,
+		}},
+	}, {
+		name:      "v1beta1 spec update",
+		operation: admissionv1beta1.Update,
+		object:    betaSubscription(attributed("bob@example.com", "bob@example.com"), "/v2"),
+		oldObject: betaSubscription(attributed("bob@example.com", "bob@example.com"), "/v1"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations/eventing.knative.dev~1lastModifier",
+			Value:     user,
+		}},
+	}, {
+		name:      "delete",
+		operation: admissionv1beta1.Delete,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wh := &Webhook{Logger: zap.NewNop()}
+			username := test.user
+			if username == "" {
+				username = user
+			}
+
+			request := &admissionv1beta1.AdmissionRequest{
+				UID:       types.UID("test-uid"),
+				Kind:      metav1.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1alpha1", Kind: "Channel"},
+				Namespace: testNS,
+				Operation: test.operation,
+				UserInfo:  authenticationv1.UserInfo{Username: username},
+			}
+			if test.object != nil {
+				raw, err := json.Marshal(test.object)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.Object.Raw = raw
+			}
+			if test.oldObject != nil {
+				raw, err := json.Marshal(test.oldObject)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.OldObject.Raw = raw
+			}
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: request})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			wh.ServeHTTP(rec, req)
+
+			var review admissionv1beta1.AdmissionReview
+			if err := json.NewDecoder(rec.Body).Decode(&review); err != nil {
+				t.Fatalf("could not decode the response: %v", err)
+			}
+			response := review.Response
+			if !response.Allowed {
+				t.Error("the object was not admitted")
+			}
+			if response.UID != request.UID {
+				t.Errorf("unexpected UID: want %q, got %q", request.UID, response.UID)
+			}
+			var patch []jsonpatch.JsonPatchOperation
+			if response.Patch != nil {
+				if err := json.Unmarshal(response.Patch, &patch); err != nil {
+					t.Fatalf("could not decode the patch: %v", err)
+				}
+			}
+			if diff := cmp.Diff(test.wantPatch, patch); diff != "" {
+				t.Errorf("unexpected patch (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTPContentType(t *testing.T) {
+	wh := &Webhook{Logger: zap.NewNop()}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(nil))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	wh.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("unexpected status: want %d, got %d", http.StatusUnsupportedMediaType, rec.Code)
+	}
+}
+
+func attributed(creator, lastModifier string) map[string]string {
+	return map[string]string{
+		CreatorAnnotation:      creator,
+		LastModifierAnnotation: lastModifier,
+	}
+}
+
+func channel(annotations map[string]string, provisioner string) *eventingv1alpha1.Channel {
+	return &eventingv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "channel", Annotations: annotations},
+		Spec: eventingv1alpha1.ChannelSpec{
+			Provisioner: &corev1.ObjectReference{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "ClusterChannelProvisioner", Name: provisioner},
+		},
+	}
+}
+
+// subscribedChannel returns an in-memory Channel with the subscriber of the Subscription sub, as
+// patched by the subscription controller.
+func subscribedChannel(annotations map[string]string, sub string) *eventingv1alpha1.Channel {
+	c := channel(annotations, "in-memory")
+	c.Spec.Subscribable = &eventingduck.Subscribable{
+		Subscribers: []eventingduck.ChannelSubscriberSpec{{
+			Ref:           &corev1.ObjectReference{Namespace: testNS, Name: sub},
+			SubscriberURI: "http://subscriber.example.com/",
+		}},
+	}
+	return c
+}
+
+func betaSubscription(annotations map[string]string, path string) *eventingv1beta1.Subscription {
+	uri := "http://subscriber.example.com"
+	return &eventingv1beta1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "subscription", Annotations: annotations},
+		Spec: eventingv1beta1.SubscriptionSpec{
+			Channel:    corev1.ObjectReference{APIVersion: "eventing.knative.dev/v1beta1", Kind: "Channel", Name: "channel"},
+			Subscriber: &eventingv1beta1.Destination{URI: &uri, Path: path},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authz authorizes the requests made to the HTTP endpoints of the dispatchers and
+// brokers with the Kubernetes API, so that access to them is granted with the usual RBAC rules.
+package authz
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// ErrUnauthenticated is returned by an Authorizer when the request has no valid credentials.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned by an Authorizer when the requester may not perform the action.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Authorizer checks that the requester of req may perform the action described by attrs. It
+// returns ErrUnauthenticated or ErrForbidden if not.
+type Authorizer func(req *http.Request, attrs authorizationv1.ResourceAttributes) error
+
+// KubeAuthorizer returns an Authorizer authenticating the bearer token of the requests with a
+// TokenReview, and checking with a SubjectAccessReview that its user may perform the action.
+func KubeAuthorizer(kc kubernetes.Interface) Authorizer {
+	return func(req *http.Request, attrs authorizationv1.ResourceAttributes) error {
+		auth := req.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return ErrUnauthenticated
+		}
+		tr, err := kc.AuthenticationV1().TokenReviews().Create(&authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimPrefix(auth, "Bearer ")},
+		})
+		if err != nil {
+			return err
+		}
+		if !tr.Status.Authenticated {
+			return ErrUnauthenticated
+		}
+
+		user := tr.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for k, v := range user.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+		sar, err := kc.AuthorizationV1().SubjectAccessReviews().Create(&authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &attrs,
+				User:               user.Username,
+				Groups:             user.Groups,
+				Extra:              extra,
+				UID:                user.UID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if !sar.Status.Allowed {
+			return ErrForbidden
+		}
+		return nil
+	}
+}
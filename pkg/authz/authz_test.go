@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestKubeAuthorizer(t *testing.T) {
+	attrs := authorizationv1.ResourceAttributes{
+		Namespace:   "default",
+		Verb:        "get",
+		Group:       "eventing.knative.dev",
+		Resource:    "channels",
+		Subresource: "tap",
+		Name:        "c",
+	}
+
+	testCases := map[string]struct {
+		header        string
+		authenticated bool
+		allowed       bool
+		err           error
+	}{
+		"no token": {
+			err: ErrUnauthenticated,
+		},
+		"invalid token": {
+			header: "Bearer invalid",
+			err:    ErrUnauthenticated,
+		},
+		"forbidden": {
+			header:        "Bearer token",
+			authenticated: true,
+			err:           ErrForbidden,
+		},
+		"allowed": {
+			header:        "Bearer token",
+			authenticated: true,
+			allowed:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var sar *authorizationv1.SubjectAccessReview
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/apis/authentication.k8s.io/v1/tokenreviews":
+					tr := &authenticationv1.TokenReview{}
+					json.NewDecoder(r.Body).Decode(tr)
+					tr.Status.Authenticated = tc.authenticated && tr.Spec.Token == "token"
+					tr.Status.User = authenticationv1.UserInfo{
+						Username: "alice",
+						Groups:   []string{"developers"},
+						Extra:    map[string]authenticationv1.ExtraValue{"scopes": {"all"}},
+					}
+					json.NewEncoder(w).Encode(tr)
+				case "/apis/authorization.k8s.io/v1/subjectaccessreviews":
+					sar = &authorizationv1.SubjectAccessReview{}
+					json.NewDecoder(r.Body).Decode(sar)
+					res := *sar
+					res.Status.Allowed = tc.allowed
+					json.NewEncoder(w).Encode(&res)
+				default:
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer s.Close()
+			kc, err := kubernetes.NewForConfig(&rest.Config{Host: s.URL})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			err = KubeAuthorizer(kc)(req, attrs)
+			if err != tc.err {
+				t.Errorf("Expected error %v, got %v", tc.err, err)
+			}
+
+			if !tc.authenticated {
+				return
+			}
+			want := authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &attrs,
+				User:               "alice",
+				Groups:             []string{"developers"},
+				Extra:              map[string]authorizationv1.ExtraValue{"scopes": {"all"}},
+			}
+			if sar == nil {
+				t.Fatal("No SubjectAccessReview was created")
+			}
+			if diff := cmp.Diff(want, sar.Spec); diff != "" {
+				t.Errorf("Unexpected SubjectAccessReview (-want +got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package discovery serves the EventTypes registered for the Brokers of a namespace, along with
+// the JSON Schemas of their data, so that UIs and code generators can find the events available
+// without reading the cluster's resources. Discovering the events of a namespace requires the
+// permission to list its EventTypes.
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/knative/eventing/pkg/apis/eventing"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/authz"
+	listers "github.com/knative/eventing/pkg/client/listers/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+)
+
+// Catalog is the response of the discovery endpoint: the EventTypes registered in a namespace,
+// or for one of its Brokers.
+type Catalog struct {
+	Namespace string `json:"namespace"`
+	// Broker is empty when the catalog lists the EventTypes of every Broker of the namespace.
+	Broker     string  `json:"broker,omitempty"`
+	EventTypes []Entry `json:"eventTypes"`
+}
+
+// Entry describes one registered EventType.
+type Entry struct {
+	Name        string `json:"name"`
+	Broker      string `json:"broker"`
+	Type        string `json:"type"`
+	Source      string `json:"source,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON Schema of the data of the events, if one is registered and can be read.
+	// Otherwise SchemaError explains why it cannot be read.
+	Schema      json.RawMessage `json:"schema,omitempty"`
+	SchemaError string          `json:"schemaError,omitempty"`
+}
+
+// Handler serves the catalogs of the EventTypes in its lister.
+type Handler struct {
+	eventTypes listers.EventTypeLister
+	schemas    *schema.Resolver
+	authorize  authz.Authorizer
+	logger     *zap.Logger
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a Handler serving the EventTypes of eventTypes, with the schemas read by
+// schemas, to the requesters allowed by authorize to list the EventTypes of the namespace.
+func NewHandler(eventTypes listers.EventTypeLister, schemas *schema.Resolver, authorize authz.Authorizer, logger *zap.Logger) *Handler {
+	return &Handler{
+		eventTypes: eventTypes,
+		schemas:    schemas,
+		authorize:  authorize,
+		logger:     logger,
+	}
+}
+
+// ServeHTTP serves the catalog of the namespace /namespaces/<namespace>/eventtypes, or of the
+// Broker /namespaces/<namespace>/brokers/<broker>/eventtypes, as JSON.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var namespace, broker string
+	switch {
+	case len(parts) == 3 && parts[0] == "namespaces" && parts[1] != "" && parts[2] == "eventtypes":
+		namespace = parts[1]
+	case len(parts) == 5 && parts[0] == "namespaces" && parts[1] != "" && parts[2] == "brokers" && parts[3] != "" && parts[4] == "eventtypes":
+		namespace, broker = parts[1], parts[3]
+	default:
+		http.Error(w, "the path must be /namespaces/<namespace>/eventtypes or /namespaces/<namespace>/brokers/<broker>/eventtypes", http.StatusNotFound)
+		return
+	}
+
+	err := h.authorize(req, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "list",
+		Group:     eventing.GroupName,
+		Resource:  "eventtypes",
+	})
+	switch err {
+	case nil:
+	case authz.ErrUnauthenticated:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case authz.ErrForbidden:
+		http.Error(w, fmt.Sprintf("not allowed to list the eventtypes of namespace %s", namespace), http.StatusForbidden)
+		return
+	default:
+		h.logger.Error("Unable to authorize the discovery", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	catalog, err := h.catalog(namespace, broker)
+	if err != nil {
+		h.logger.Error("Unable to list the eventtypes", zap.String("namespace", namespace), zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(catalog); err != nil {
+		h.logger.Warn("Unable to write the catalog", zap.Error(err))
+	}
+}
+
+// catalog lists the EventTypes of namespace, only those of broker if it is not empty, sorted by
+// Broker, type and name.
+func (h *Handler) catalog(namespace, broker string) (*Catalog, error) {
+	ets, err := h.eventTypes.EventTypes(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	catalog := &Catalog{
+		Namespace:  namespace,
+		Broker:     broker,
+		EventTypes: make([]Entry, 0, len(ets)),
+	}
+	for _, et := range ets {
+		if broker != "" && et.Spec.Broker != broker {
+			continue
+		}
+		catalog.EventTypes = append(catalog.EventTypes, h.entry(et))
+	}
+	sort.Slice(catalog.EventTypes, func(i, j int) bool {
+		a, b := catalog.EventTypes[i], catalog.EventTypes[j]
+		if a.Broker != b.Broker {
+			return a.Broker < b.Broker
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+	return catalog, nil
+}
+
+func (h *Handler) entry(et *v1alpha1.EventType) Entry {
+	e := Entry{
+		Name:        et.Name,
+		Broker:      et.Spec.Broker,
+		Type:        et.Spec.Type,
+		Source:      et.Spec.Source,
+		Description: et.Spec.Description,
+	}
+	if et.Spec.Schema != nil {
+		// A schema that cannot be read does not hide the EventType.
+		doc, err := h.schemas.Document(et.Namespace, *et.Spec.Schema)
+		if err != nil {
+			e.SchemaError = err.Error()
+		} else {
+			e.Schema = doc
+		}
+	}
+	return e
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/authz"
+	listers "github.com/knative/eventing/pkg/client/listers/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+)
+
+const (
+	testNS = "test-namespace"
+
+	orderSchema = `{"type":"object","required":["id"]}`
+)
+
+func eventType(namespace, name, broker, typ string, s *eventingduck.SubscriberSchema) *v1alpha1.EventType {
+	return &v1alpha1.EventType{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1alpha1.EventTypeSpec{
+			Type:   typ,
+			Source: "/orders",
+			Broker: broker,
+			Schema: s,
+		},
+	}
+}
+
+func configMapSchema(key string) *eventingduck.SubscriberSchema {
+	return &eventingduck.SubscriberSchema{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+			Key:                  key,
+		},
+	}
+}
+
+func getConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	if namespace != testNS || name != "schemas" {
+		return nil, errors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+	return &corev1.ConfigMap{Data: map[string]string{"order.json": orderSchema}}, nil
+}
+
+func newHandler(t *testing.T, authorize authz.Authorizer) *Handler {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, et := range []*v1alpha1.EventType{
+		eventType(testNS, "order-created", "default", "com.example.order.created", configMapSchema("order.json")),
+		eventType(testNS, "order-cancelled", "default", "com.example.order.cancelled", configMapSchema("missing.json")),
+		eventType(testNS, "payment", "payments", "com.example.payment", nil),
+		eventType("other-namespace", "order-created", "default", "com.example.order.created", nil),
+	} {
+		if err := indexer.Add(et); err != nil {
+			t.Fatalf("Unable to add EventType: %v", err)
+		}
+	}
+	return NewHandler(listers.NewEventTypeLister(indexer), schema.NewResolver(getConfigMap), authorize, zap.NewNop())
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	testCases := map[string]struct {
+		method   string
+		path     string
+		err      error
+		wantCode int
+	}{
+		"not a GET": {
+			method:   http.MethodPost,
+			path:     "/namespaces/test-namespace/eventtypes",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		"invalid path": {
+			path:     "/namespaces/test-namespace/brokers/default",
+			wantCode: http.StatusNotFound,
+		},
+		"unauthenticated": {
+			path:     "/namespaces/test-namespace/eventtypes",
+			err:      authz.ErrUnauthenticated,
+			wantCode: http.StatusUnauthorized,
+		},
+		"forbidden": {
+			path:     "/namespaces/test-namespace/brokers/default/eventtypes",
+			err:      authz.ErrForbidden,
+			wantCode: http.StatusForbidden,
+		},
+		"allowed": {
+			path:     "/namespaces/test-namespace/eventtypes",
+			wantCode: http.StatusOK,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if tc.method == "" {
+				tc.method = http.MethodGet
+			}
+			want := authorizationv1.ResourceAttributes{
+				Namespace: testNS,
+				Verb:      "list",
+				Group:     "eventing.knative.dev",
+				Resource:  "eventtypes",
+			}
+			h := newHandler(t, func(_ *http.Request, attrs authorizationv1.ResourceAttributes) error {
+				if attrs != want {
+					t.Errorf("Unexpected attributes authorized. Expected %+v. Actual %+v", want, attrs)
+				}
+				return tc.err
+			})
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.wantCode {
+				t.Errorf("Unexpected status code. Expected %v. Actual %v", tc.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandlerCatalog(t *testing.T) {
+	orderCreated := Entry{
+		Name:   "order-created",
+		Broker: "default",
+		Type:   "com.example.order.created",
+		Source: "/orders",
+		Schema: json.RawMessage(orderSchema),
+	}
+	orderCancelled := Entry{
+		Name:        "order-cancelled",
+		Broker:      "default",
+		Type:        "com.example.order.cancelled",
+		Source:      "/orders",
+		SchemaError: `configmap test-namespace/schemas does not contain key "missing.json"`,
+	}
+	payment := Entry{
+		Name:   "payment",
+		Broker: "payments",
+		Type:   "com.example.payment",
+		Source: "/orders",
+	}
+	testCases := map[string]struct {
+		path string
+		want Catalog
+	}{
+		"namespace": {
+			path: "/namespaces/test-namespace/eventtypes",
+			want: Catalog{
+				Namespace:  testNS,
+				EventTypes: []Entry{orderCancelled, orderCreated, payment},
+			},
+		},
+		"broker": {
+			path: "/namespaces/test-namespace/brokers/default/eventtypes",
+			want: Catalog{
+				Namespace:  testNS,
+				Broker:     "default",
+				EventTypes: []Entry{orderCancelled, orderCreated},
+			},
+		},
+		"no eventtypes": {
+			path: "/namespaces/test-namespace/brokers/unknown/eventtypes",
+			want: Catalog{
+				Namespace:  testNS,
+				Broker:     "unknown",
+				EventTypes: []Entry{},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			h := newHandler(t, func(*http.Request, authorizationv1.ResourceAttributes) error {
+				return nil
+			})
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Unexpected status code. Expected 200. Actual %v", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Unexpected content type. Expected application/json. Actual %q", ct)
+			}
+			var got Catalog
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unable to decode the catalog: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected catalog (-want, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ConfigKey is the key in the filter's ConfigMap that contains the routes.
+	ConfigKey = "brokerFilterConfig"
+
+	// PermanentStatusCodesEnv is the comma separated list of the HTTP status codes of the
+	// subscribers that are not retried by the routes that do not set their own, e.g. 400,422.
+	PermanentStatusCodesEnv = "PERMANENT_STATUS_CODES"
+)
+
+// PermanentStatusCodesFromEnv returns the status codes set by PermanentStatusCodesEnv, none if it
+// is not set.
+func PermanentStatusCodesFromEnv() ([]int32, error) {
+	v := os.Getenv(PermanentStatusCodesEnv)
+	if v == "" {
+		return nil, nil
+	}
+	var codes []int32
+	for _, c := range strings.Split(v, ",") {
+		code, err := strconv.ParseInt(strings.TrimSpace(c), 10, 32)
+		if err != nil || code < 100 || code > 599 || (code >= 200 && code < 300) {
+			return nil, fmt.Errorf("invalid %s %q, it must list HTTP status codes of failures", PermanentStatusCodesEnv, v)
+		}
+		codes = append(codes, int32(code))
+	}
+	return codes, nil
+}
+
+// Config is the configuration of a Broker's filter.
+type Config struct {
+	Routes []Route `json:"routes"`
+}
+
+// Route describes where the events that arrive for one route are delivered. Events arrive for a
+// route at the path /triggers/<namespace>/<name>.
+type Route struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Filter is an expression understood by the filter package. Only events it matches are
+	// delivered. An empty filter matches every event.
+	Filter string `json:"filter,omitempty"`
+
+	SubscriberURI string `json:"subscriberURI,omitempty"`
+	ReplyURI      string `json:"replyURI,omitempty"`
+
+	// Retry is the number of times delivery to the subscriber is retried after it fails.
+	Retry int32 `json:"retry,omitempty"`
+	// BackoffPolicy is either linear or exponential, the default.
+	BackoffPolicy string `json:"backoffPolicy,omitempty"`
+	// BackoffDelay is the delay before the first retry, as a Go duration. Defaults to 1s.
+	BackoffDelay string `json:"backoffDelay,omitempty"`
+	// DeadLetterURI receives the events that could not be delivered to the subscriber once the
+	// retries are exhausted. If it is empty, those events are rejected.
+	DeadLetterURI string `json:"deadLetterURI,omitempty"`
+	// PermanentStatusCodes are the HTTP status codes of the subscriber that are not retried, the
+	// event being sent straight to the DeadLetterURI. If it is empty, the codes the Handler is
+	// created with apply.
+	PermanentStatusCodes []int32 `json:"permanentStatusCodes,omitempty"`
+}
+
+// NewConfig parses the data of the filter's ConfigMap into a Config.
+// orig == NewConfig(SerializeConfig(orig))
+func NewConfig(data map[string]string) (*Config, error) {
+	str, present := data[ConfigKey]
+	if !present {
+		return nil, fmt.Errorf("expected key not found: %v", ConfigKey)
+	}
+	config := &Config{}
+	if err := json.Unmarshal([]byte(str), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SerializeConfig generates the ConfigMap data equivalent to config.
+// orig == NewConfig(SerializeConfig(orig))
+func SerializeConfig(config Config) (map[string]string, error) {
+	jb, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		ConfigKey: string(jb),
+	}, nil
+}
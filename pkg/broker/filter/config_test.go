@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package filter
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	orig := Config{
+		Routes: []Route{{
+			Namespace:     "test-namespace",
+			Name:          "trigger",
+			Filter:        "type = 'com.example.someevent'",
+			SubscriberURI: "http://subscriber.test-namespace.svc.cluster.local/",
+			ReplyURI:      "http://reply.test-namespace.svc.cluster.local/",
+		}},
+	}
+	data, err := SerializeConfig(orig)
+	if err != nil {
+		t.Fatalf("Unexpected error serializing: %v", err)
+	}
+	got, err := NewConfig(data)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing: %v", err)
+	}
+	if diff := cmp.Diff(&orig, got); diff != "" {
+		t.Errorf("Unexpected config (-want, +got): %v", diff)
+	}
+}
+
+func TestNewConfig_Errors(t *testing.T) {
+	testCases := map[string]map[string]string{
+		"missing key":  {},
+		"invalid json": {ConfigKey: "{"},
+	}
+	for n, data := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if _, err := NewConfig(data); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestPermanentStatusCodesFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		env     string
+		want    []int32
+		wantErr bool
+	}{
+		"unset":        {},
+		"single":       {env: "422", want: []int32{422}},
+		"list":         {env: "400, 422,501", want: []int32{400, 422, 501}},
+		"not a number": {env: "400,bad", wantErr: true},
+		"success":      {env: "202", wantErr: true},
+		"out of range": {env: "4000", wantErr: true},
+	}
+	defer os.Unsetenv(PermanentStatusCodesEnv)
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(PermanentStatusCodesEnv, tc.env)
+			got, err := PermanentStatusCodesFromEnv()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unexpected error. Expected %v, actual %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected status codes (-want, +got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package filter provides the http.Handler of a Broker's filter. The filter receives every event
+// sent to the Broker, once per route, and delivers it to the route's subscriber if the event
+// matches the route's filter.
+package filter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/knative/eventing/pkg/filter"
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+)
+
+const (
+	// routePathPrefix is the prefix of the path that events for a route are sent to.
+	routePathPrefix = "/triggers/"
+
+	backoffPolicyLinear      = "linear"
+	backoffPolicyExponential = "exponential"
+
+	// defaultBackoffDelay is the delay before the first retry of routes that do not set one.
+	defaultBackoffDelay = time.Second
+	// maxBackoffDelay is the longest delay before a retry, however many retries came before.
+	maxBackoffDelay = 5 * time.Minute
+	// maxRetryDuration bounds the time the deliveries of an event are retried, so that they end
+	// before the 1 minute the fanout of the Channel waits for the Broker to respond.
+	maxRetryDuration = 45 * time.Second
+)
+
+// RoutePath returns the path that events for the route namespace/name are sent to.
+func RoutePath(namespace, name string) string {
+	return fmt.Sprintf("%s%s/%s", routePathPrefix, namespace, name)
+}
+
+// Handler routes the events sent to it, according to its Config.
+type Handler struct {
+	// routes holds a map[string]*compiledRoute, keyed by the path of the route. It is replaced
+	// as a whole when the config is updated.
+	routes     atomic.Value
+	dispatcher provisioners.Dispatcher
+	// after waits between retries. It is replaced in tests.
+	after func(time.Duration) <-chan time.Time
+	// permanentStatusCodes are the status codes that are not retried for the routes that do not
+	// set their own.
+	permanentStatusCodes []int32
+	// dispatcherOptions configure the dispatcher of the Handler.
+	dispatcherOptions []provisioners.DispatcherOption
+
+	logger *zap.Logger
+}
+
+var _ http.Handler = &Handler{}
+
+type compiledRoute struct {
+	Route
+	filter       filter.Expression
+	backoffDelay time.Duration
+	// permanent holds the status codes of the subscriber that are not retried.
+	permanent map[int]bool
+}
+
+// Option configures optional behavior of a Handler.
+type Option func(*Handler)
+
+// WithPermanentStatusCodes makes the Handler send the events that subscribers fail to accept with
+// one of codes straight to the dead-letter URI of their route, rather than retrying them, unless
+// the route sets its own codes.
+func WithPermanentStatusCodes(codes []int32) Option {
+	return func(h *Handler) {
+		h.permanentStatusCodes = codes
+	}
+}
+
+// WithReplyExtensions makes the Handler copy the extensions names of the events it delivers to the
+// replies of the subscribers that do not set them.
+func WithReplyExtensions(names []string) Option {
+	return func(h *Handler) {
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithReplyExtensions(names...))
+	}
+}
+
+// NewHandler creates a Handler without any routes.
+func NewHandler(logger *zap.Logger, opts ...Option) *Handler {
+	h := &Handler{
+		after:  time.After,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.dispatcher = provisioners.NewMessageDispatcher(logger.Sugar(), h.dispatcherOptions...)
+	h.routes.Store(map[string]*compiledRoute{})
+	return h
+}
+
+// UpdateConfig replaces the routes of the Handler with those in config. If any route is invalid,
+// an error is returned and the existing routes are kept.
+func (h *Handler) UpdateConfig(config *Config) error {
+	routes := make(map[string]*compiledRoute, len(config.Routes))
+	for _, r := range config.Routes {
+		f, err := filter.Parse(r.Filter)
+		if err != nil {
+			return fmt.Errorf("invalid filter for route %s/%s: %v", r.Namespace, r.Name, err)
+		}
+		cr := &compiledRoute{Route: r, filter: f, backoffDelay: defaultBackoffDelay, permanent: map[int]bool{}}
+		switch r.BackoffPolicy {
+		case "", backoffPolicyLinear, backoffPolicyExponential:
+		default:
+			return fmt.Errorf("invalid backoff policy for route %s/%s: %q", r.Namespace, r.Name, r.BackoffPolicy)
+		}
+		if r.BackoffDelay != "" {
+			if cr.backoffDelay, err = time.ParseDuration(r.BackoffDelay); err != nil {
+				return fmt.Errorf("invalid backoff delay for route %s/%s: %v", r.Namespace, r.Name, err)
+			}
+		}
+		codes := r.PermanentStatusCodes
+		if len(codes) == 0 {
+			codes = h.permanentStatusCodes
+		}
+		for _, code := range codes {
+			cr.permanent[int(code)] = true
+		}
+		routes[RoutePath(r.Namespace, r.Name)] = cr
+	}
+	h.routes.Store(routes)
+	return nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, routePathPrefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	route, ok := h.routes.Load().(map[string]*compiledRoute)[r.URL.Path]
+	if !ok {
+		h.logger.Info("Received an event for an unknown route", zap.String("path", r.URL.Path))
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	receiver := provisioners.NewMessageReceiver(func(_ provisioners.ChannelReference, m *provisioners.Message) error {
+		return h.deliver(r.Context(), route, m)
+	}, h.logger.Sugar())
+	receiver.HandleRequest(w, r)
+}
+
+// deliver sends m to the subscriber of route if it matches the route's filter. Failed deliveries
+// are retried as configured by the route for at most maxRetryDuration, unless the subscriber
+// answered with a permanent status code, and then sent to its dead-letter URI, with extensions
+// describing the failure. The retries stop without dead-lettering m if ctx is done, as the sender
+// of m no longer waits for the result.
+func (h *Handler) deliver(ctx context.Context, route *compiledRoute, m *provisioners.Message) error {
+	if !route.filter.Matches(m.Attributes()) {
+		// Not being interested in the event is a successful delivery.
+		return nil
+	}
+	defaults := provisioners.DispatchDefaults{
+		Namespace:    route.Namespace,
+		DeadLetter:   route.DeadLetterURI,
+		Subscription: route.Namespace + "/" + route.Name,
+	}
+	deadline := time.Now().Add(maxRetryDuration)
+	err := h.dispatcher.DispatchMessage(m, route.SubscriberURI, route.ReplyURI, defaults)
+	attempts := 1
+	for retry := int32(1); err != nil && retry <= route.Retry; retry++ {
+		if route.isPermanent(err) {
+			h.logger.Info("Not retrying a permanent failure", zap.String("route", RoutePath(route.Namespace, route.Name)), zap.Error(err))
+			break
+		}
+		delay := route.backoff(retry)
+		if time.Now().Add(delay).After(deadline) {
+			h.logger.Info("Not retrying after the retry duration", zap.String("route", RoutePath(route.Namespace, route.Name)), zap.Int32("retry", retry), zap.Duration("delay", delay), zap.Error(err))
+			break
+		}
+		h.logger.Info("Retrying delivery", zap.String("route", RoutePath(route.Namespace, route.Name)), zap.Int32("retry", retry), zap.Duration("delay", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			h.logger.Info("Not retrying an event whose request was canceled", zap.String("route", RoutePath(route.Namespace, route.Name)), zap.Error(err))
+			return fmt.Errorf("delivery canceled: %v", err)
+		case <-h.after(delay):
+		}
+		retryDefaults := defaults
+		retryDefaults.Redelivery = true
+		retryDefaults.Attempt = attempts + 1
+		err = h.dispatcher.DispatchMessage(m, route.SubscriberURI, route.ReplyURI, retryDefaults)
+		attempts++
+	}
+	if err == nil || route.DeadLetterURI == "" {
+		return err
+	}
+	h.logger.Warn("Delivery failed, sending the event to the dead-letter URI", zap.String("route", RoutePath(route.Namespace, route.Name)), zap.Error(err))
+	return h.dispatcher.DispatchMessage(m.WithDeliveryFailure(route.SubscriberURI, err, attempts), route.DeadLetterURI, "", defaults)
+}
+
+// isPermanent returns whether the subscriber of r failed with a status code that is not retried.
+// The events the subscriber explicitly nacked are always retried.
+func (r *compiledRoute) isPermanent(err error) bool {
+	code, ok := provisioners.ResponseStatusCode(err)
+	return ok && r.permanent[code] && !provisioners.IsNack(err)
+}
+
+// backoff returns the delay before the given retry, counting from 1. It grows up to
+// maxBackoffDelay, without overflowing.
+func (r *compiledRoute) backoff(retry int32) time.Duration {
+	if r.backoffDelay <= 0 {
+		return 0
+	}
+	if r.backoffDelay >= maxBackoffDelay {
+		return maxBackoffDelay
+	}
+	if r.BackoffPolicy == backoffPolicyLinear {
+		if time.Duration(retry) > maxBackoffDelay/r.backoffDelay {
+			return maxBackoffDelay
+		}
+		return r.backoffDelay * time.Duration(retry)
+	}
+	shift := uint(retry - 1)
+	if shift >= 63 || r.backoffDelay > maxBackoffDelay>>shift {
+		return maxBackoffDelay
+	}
+	return r.backoffDelay << shift
+}
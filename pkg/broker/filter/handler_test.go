@@ -0,0 +1,460 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+const (
+	testNS    = "test-namespace"
+	eventType = "com.example.someevent"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	testCases := map[string]struct {
+		method       string
+		path         string
+		filter       string
+		noSubscriber bool
+		subscriber   func(http.ResponseWriter, *http.Request)
+		reply        func(http.ResponseWriter, *http.Request)
+		retry        int32
+		backoffDelay string
+		deadLetter   func(http.ResponseWriter, *http.Request)
+		// permanent is set on the route, defaultPermanent on the handler.
+		permanent        []int32
+		defaultPermanent []int32
+		expectedStatus   int
+		wantDelivered    bool
+		wantReplied      bool
+		// wantAttempts is the number of deliveries to the subscriber, if wantDelivered is set.
+		// Defaults to 1.
+		wantAttempts     int
+		wantDeadLettered bool
+	}{
+		"delivered": {
+			expectedStatus: http.StatusAccepted,
+			wantDelivered:  true,
+		},
+		"delivered and replied": {
+			subscriber: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("response"))
+			},
+			reply:          func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus: http.StatusAccepted,
+			wantDelivered:  true,
+			wantReplied:    true,
+		},
+		"reply only": {
+			noSubscriber:   true,
+			reply:          func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus: http.StatusAccepted,
+			wantReplied:    true,
+		},
+		"matching filter": {
+			filter:         "type = '" + eventType + "'",
+			expectedStatus: http.StatusAccepted,
+			wantDelivered:  true,
+		},
+		"filtered out": {
+			filter:         "type = 'com.example.otherevent'",
+			expectedStatus: http.StatusAccepted,
+		},
+		"subscriber fails": {
+			subscriber:     func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			expectedStatus: http.StatusInternalServerError,
+			wantDelivered:  true,
+		},
+		"subscriber fails, retried": {
+			subscriber:     func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			retry:          2,
+			expectedStatus: http.StatusInternalServerError,
+			wantDelivered:  true,
+			wantAttempts:   3,
+		},
+		"subscriber fails, dead-lettered": {
+			subscriber:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			retry:            1,
+			deadLetter:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus:   http.StatusAccepted,
+			wantDelivered:    true,
+			wantAttempts:     2,
+			wantDeadLettered: true,
+		},
+		"subscriber and dead-letter fail": {
+			subscriber:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			deadLetter:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			expectedStatus:   http.StatusInternalServerError,
+			wantDelivered:    true,
+			wantDeadLettered: true,
+		},
+		"permanent failure, dead-lettered": {
+			subscriber:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusUnprocessableEntity) },
+			retry:            3,
+			permanent:        []int32{http.StatusBadRequest, http.StatusUnprocessableEntity},
+			deadLetter:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus:   http.StatusAccepted,
+			wantDelivered:    true,
+			wantDeadLettered: true,
+		},
+		"other failure with permanent codes, retried": {
+			subscriber:     func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			retry:          2,
+			permanent:      []int32{http.StatusUnprocessableEntity},
+			expectedStatus: http.StatusInternalServerError,
+			wantDelivered:  true,
+			wantAttempts:   3,
+		},
+		"default permanent failure": {
+			subscriber:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadRequest) },
+			retry:            3,
+			defaultPermanent: []int32{http.StatusBadRequest},
+			expectedStatus:   http.StatusInternalServerError,
+			wantDelivered:    true,
+		},
+		"route permanent codes override the default": {
+			subscriber:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadRequest) },
+			retry:            1,
+			permanent:        []int32{http.StatusUnprocessableEntity},
+			defaultPermanent: []int32{http.StatusBadRequest},
+			expectedStatus:   http.StatusInternalServerError,
+			wantDelivered:    true,
+			wantAttempts:     2,
+		},
+		"nacked permanent failure, retried": {
+			subscriber: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(provisioners.DeliveryResultHeader, provisioners.DeliveryResultNack)
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			},
+			retry:          2,
+			permanent:      []int32{http.StatusUnprocessableEntity},
+			expectedStatus: http.StatusInternalServerError,
+			wantDelivered:  true,
+			wantAttempts:   3,
+		},
+		"dropped, neither retried nor dead-lettered": {
+			subscriber: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(provisioners.DeliveryResultHeader, provisioners.DeliveryResultDrop)
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			retry:          2,
+			deadLetter:     func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus: http.StatusAccepted,
+			wantDelivered:  true,
+		},
+		"retries bounded by the retry duration": {
+			subscriber:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			retry:            3,
+			backoffDelay:     "30s",
+			deadLetter:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus:   http.StatusAccepted,
+			wantDelivered:    true,
+			wantAttempts:     2,
+			wantDeadLettered: true,
+		},
+		"retry succeeds": {
+			subscriber: func() func(http.ResponseWriter, *http.Request) {
+				failed := false
+				return func(w http.ResponseWriter, _ *http.Request) {
+					if !failed {
+						failed = true
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					w.WriteHeader(http.StatusAccepted)
+				}
+			}(),
+			retry:          3,
+			deadLetter:     func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) },
+			expectedStatus: http.StatusAccepted,
+			wantDelivered:  true,
+			wantAttempts:   2,
+		},
+		"unknown route": {
+			path:           RoutePath(testNS, "other"),
+			expectedStatus: http.StatusNotFound,
+		},
+		"not a route": {
+			path:           "/",
+			expectedStatus: http.StatusNotFound,
+		},
+		"wrong method": {
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			attempts, replied, deadLettered := 0, false, false
+			subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if tc.subscriber != nil {
+					tc.subscriber(w, r)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer subscriber.Close()
+			reply := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				replied = true
+				tc.reply(w, r)
+			}))
+			defer reply.Close()
+			var deadLetterHeaders http.Header
+			deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deadLettered = true
+				deadLetterHeaders = r.Header
+				tc.deadLetter(w, r)
+			}))
+			defer deadLetter.Close()
+
+			route := Route{
+				Namespace: testNS,
+				Name:      "trigger",
+				Filter:    tc.filter,
+				Retry:     tc.retry,
+
+				BackoffDelay: tc.backoffDelay,
+
+				PermanentStatusCodes: tc.permanent,
+			}
+			if !tc.noSubscriber {
+				route.SubscriberURI = subscriber.URL
+			}
+			if tc.reply != nil {
+				route.ReplyURI = reply.URL
+			}
+			if tc.deadLetter != nil {
+				route.DeadLetterURI = deadLetter.URL
+			}
+			h := NewHandler(zap.NewNop(), WithPermanentStatusCodes(tc.defaultPermanent))
+			h.after = func(time.Duration) <-chan time.Time {
+				c := make(chan time.Time, 1)
+				c <- time.Time{}
+				return c
+			}
+			if err := h.UpdateConfig(&Config{Routes: []Route{route}}); err != nil {
+				t.Fatalf("Unexpected error updating config: %v", err)
+			}
+
+			method := http.MethodPost
+			if tc.method != "" {
+				method = tc.method
+			}
+			path := RoutePath(testNS, "trigger")
+			if tc.path != "" {
+				path = tc.path
+			}
+			req := httptest.NewRequest(method, "http://broker-filter.test-namespace.svc.cluster.local"+path, strings.NewReader("{}"))
+			req.Header.Set("Ce-Specversion", "0.2")
+			req.Header.Set("Ce-Type", eventType)
+			req.Header.Set("Ce-Id", "1234")
+			req.Header.Set("Ce-Source", "/test")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Unexpected status code. Expected %v, actual %v", tc.expectedStatus, w.Code)
+			}
+			if delivered := attempts > 0; delivered != tc.wantDelivered {
+				t.Errorf("Unexpected delivery. Expected %v, actual %v", tc.wantDelivered, delivered)
+			}
+			if tc.wantDelivered {
+				wantAttempts := tc.wantAttempts
+				if wantAttempts == 0 {
+					wantAttempts = 1
+				}
+				if attempts != wantAttempts {
+					t.Errorf("Unexpected delivery attempts. Expected %v, actual %v", wantAttempts, attempts)
+				}
+			}
+			if deadLettered != tc.wantDeadLettered {
+				t.Errorf("Unexpected dead-letter delivery. Expected %v, actual %v", tc.wantDeadLettered, deadLettered)
+			}
+			if deadLettered {
+				wantAttempts := tc.wantAttempts
+				if wantAttempts == 0 {
+					wantAttempts = 1
+				}
+				if got := deadLetterHeaders.Get("Ce-Knativeerrorattempts"); got != strconv.Itoa(wantAttempts) {
+					t.Errorf("Unexpected dead-letter attempts. Expected %v, actual %q", wantAttempts, got)
+				}
+				if got := deadLetterHeaders.Get("Ce-Knativeerrordest"); got != subscriber.URL {
+					t.Errorf("Unexpected dead-letter destination. Expected %q, actual %q", subscriber.URL, got)
+				}
+				if deadLetterHeaders.Get("Ce-Knativeerrorcode") == "" || deadLetterHeaders.Get("Ce-Knativeerrordata") == "" {
+					t.Errorf("Expected the failure in the dead-letter event: %v", deadLetterHeaders)
+				}
+			}
+			if replied != tc.wantReplied {
+				t.Errorf("Unexpected reply. Expected %v, actual %v", tc.wantReplied, replied)
+			}
+		})
+	}
+}
+
+func TestHandler_ServeHTTPCanceledWhileRetrying(t *testing.T) {
+	attempts, deadLettered := 0, false
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer subscriber.Close()
+	deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLettered = true
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetter.Close()
+
+	h := NewHandler(zap.NewNop())
+	// The retry would never be due, so only the cancellation of the request ends the wait.
+	h.after = func(time.Duration) <-chan time.Time { return nil }
+	route := Route{
+		Namespace:     testNS,
+		Name:          "trigger",
+		Retry:         3,
+		SubscriberURI: subscriber.URL,
+		DeadLetterURI: deadLetter.URL,
+	}
+	if err := h.UpdateConfig(&Config{Routes: []Route{route}}); err != nil {
+		t.Fatalf("Unexpected error updating config: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "http://broker-filter.test-namespace.svc.cluster.local"+RoutePath(testNS, "trigger"), strings.NewReader("{}")).WithContext(ctx)
+	req.Header.Set("Ce-Specversion", "0.2")
+	req.Header.Set("Ce-Type", eventType)
+	req.Header.Set("Ce-Id", "1234")
+	req.Header.Set("Ce-Source", "/test")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status code. Expected %v, actual %v", http.StatusInternalServerError, w.Code)
+	}
+	if attempts != 1 {
+		t.Errorf("Unexpected delivery attempts. Expected 1, actual %v", attempts)
+	}
+	if deadLettered {
+		t.Error("Unexpected dead-letter delivery of a canceled event")
+	}
+}
+
+func TestHandler_UpdateConfigInvalidFilter(t *testing.T) {
+	h := NewHandler(zap.NewNop())
+	valid := &Config{Routes: []Route{{Namespace: testNS, Name: "valid"}}}
+	if err := h.UpdateConfig(valid); err != nil {
+		t.Fatalf("Unexpected error updating config: %v", err)
+	}
+	invalid := &Config{Routes: []Route{{Namespace: testNS, Name: "invalid", Filter: "type ="}}}
+	if err := h.UpdateConfig(invalid); err == nil {
+		t.Fatalf("Expected an error for an invalid filter")
+	}
+
+	// The previous routes are kept.
+	req := httptest.NewRequest(http.MethodPost, "http://broker-filter.test-namespace"+RoutePath(testNS, "valid"), strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Unexpected status code. Expected %v, actual %v", http.StatusAccepted, w.Code)
+	}
+}
+
+func TestHandler_UpdateConfigInvalidDelivery(t *testing.T) {
+	for n, r := range map[string]Route{
+		"backoff policy": {Namespace: testNS, Name: "invalid", BackoffPolicy: "random"},
+		"backoff delay":  {Namespace: testNS, Name: "invalid", BackoffDelay: "soon"},
+	} {
+		t.Run(n, func(t *testing.T) {
+			h := NewHandler(zap.NewNop())
+			if err := h.UpdateConfig(&Config{Routes: []Route{r}}); err == nil {
+				t.Errorf("Expected an error for an invalid %s", n)
+			}
+		})
+	}
+}
+
+func TestCompiledRoute_Backoff(t *testing.T) {
+	testCases := map[string]struct {
+		policy string
+		delay  string
+		// retries are the retries of the delays in want, 1, 2, 3... if unset.
+		retries []int32
+		want    []time.Duration
+	}{
+		"default": {
+			want: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		"exponential": {
+			policy: backoffPolicyExponential,
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		"linear": {
+			policy: backoffPolicyLinear,
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond},
+		},
+		"exponential capped": {
+			policy:  backoffPolicyExponential,
+			retries: []int32{12, 13, 64, 65, 1000},
+			want:    []time.Duration{204800 * time.Millisecond, maxBackoffDelay, maxBackoffDelay, maxBackoffDelay, maxBackoffDelay},
+		},
+		"linear capped": {
+			policy:  backoffPolicyLinear,
+			retries: []int32{3000, 3001, 1 << 30},
+			want:    []time.Duration{maxBackoffDelay, maxBackoffDelay, maxBackoffDelay},
+		},
+		"delay longer than the cap": {
+			delay: "1h",
+			want:  []time.Duration{maxBackoffDelay, maxBackoffDelay},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			h := NewHandler(zap.NewNop())
+			delay := tc.delay
+			if delay == "" {
+				delay = "100ms"
+			}
+			r := Route{Namespace: testNS, Name: "trigger", BackoffPolicy: tc.policy, BackoffDelay: delay}
+			if err := h.UpdateConfig(&Config{Routes: []Route{r}}); err != nil {
+				t.Fatalf("Unexpected error updating config: %v", err)
+			}
+			route := h.routes.Load().(map[string]*compiledRoute)[RoutePath(testNS, "trigger")]
+			for i, want := range tc.want {
+				retry := int32(i + 1)
+				if tc.retries != nil {
+					retry = tc.retries[i]
+				}
+				if got := route.backoff(retry); got != want {
+					t.Errorf("Unexpected backoff before retry %d. Expected %v, actual %v", retry, want, got)
+				}
+			}
+		})
+	}
+}
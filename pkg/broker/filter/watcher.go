@@ -0,0 +1,39 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package filter
+
+import (
+	"github.com/knative/eventing/pkg/broker"
+	"go.uber.org/zap"
+)
+
+const (
+	// ConfigDir is the mount path of the filter's ConfigMap volume.
+	ConfigDir = "/etc/config/broker-filter"
+)
+
+// NewConfigWatcher reads the config in dir and applies it to h. The caller is responsible for
+// calling Start(<-chan) on the returned watcher to keep applying changes, likely via a
+// controller-runtime Manager.
+func NewConfigWatcher(logger *zap.Logger, dir string, h *Handler) (*broker.ConfigWatcher, error) {
+	return broker.NewConfigWatcher(logger, dir, func(data map[string]string) error {
+		config, err := NewConfig(data)
+		if err != nil {
+			return err
+		}
+		return h.UpdateConfig(config)
+	})
+}
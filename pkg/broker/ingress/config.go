@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ingress
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ConfigKey is the key in the multi-tenant ingress's ConfigMap that contains the routes.
+	ConfigKey = "brokerIngressConfig"
+)
+
+// Config is the configuration of a multi-tenant ingress, which serves many Brokers.
+type Config struct {
+	Routes []Route `json:"routes"`
+}
+
+// Route describes where the events sent to one Broker are written.
+type Route struct {
+	// Namespace and Name identify the Service that the events for the Broker are sent to, as in
+	// the host name Name.Namespace.svc.cluster.local.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	ChannelURI string `json:"channelURI"`
+}
+
+// NewConfig parses the data of the ingress's ConfigMap into a Config.
+// orig == NewConfig(SerializeConfig(orig))
+func NewConfig(data map[string]string) (*Config, error) {
+	str, present := data[ConfigKey]
+	if !present {
+		return nil, fmt.Errorf("expected key not found: %v", ConfigKey)
+	}
+	config := &Config{}
+	if err := json.Unmarshal([]byte(str), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SerializeConfig generates the ConfigMap data equivalent to config.
+// orig == NewConfig(SerializeConfig(orig))
+func SerializeConfig(config Config) (map[string]string, error) {
+	jb, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		ConfigKey: string(jb),
+	}, nil
+}
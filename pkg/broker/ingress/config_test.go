@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ingress
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	orig := Config{
+		Routes: []Route{{
+			Namespace:  "test-namespace",
+			Name:       "default-broker-ingress",
+			ChannelURI: "http://default-broker-channel.test-namespace.svc.cluster.local/",
+		}},
+	}
+	data, err := SerializeConfig(orig)
+	if err != nil {
+		t.Fatalf("Unexpected error serializing: %v", err)
+	}
+	got, err := NewConfig(data)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing: %v", err)
+	}
+	if diff := cmp.Diff(&orig, got); diff != "" {
+		t.Errorf("Unexpected config (-want, +got): %v", diff)
+	}
+}
+
+func TestNewConfig_Errors(t *testing.T) {
+	testCases := map[string]map[string]string{
+		"missing key":  {},
+		"invalid json": {ConfigKey: "{"},
+	}
+	for n, data := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if _, err := NewConfig(data); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package ingress provides the http.Handler of a Broker's ingress. It accepts the events sent to
+// the Broker and writes them to the Broker's Channel.
+package ingress
+
+import (
+	"net/http"
+
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+)
+
+// Handler writes the events sent to it to a Channel.
+type Handler struct {
+	channelURI string
+	receiver   *provisioners.MessageReceiver
+	dispatcher provisioners.Dispatcher
+
+	logger *zap.Logger
+}
+
+var _ http.Handler = &Handler{}
+
+// NewHandler creates a Handler that writes events to the Channel at channelURI.
+func NewHandler(logger *zap.Logger, channelURI string) *Handler {
+	h := &Handler{
+		channelURI: channelURI,
+		dispatcher: provisioners.NewMessageDispatcher(logger.Sugar()),
+		logger:     logger,
+	}
+	h.receiver = provisioners.NewMessageReceiver(func(_ provisioners.ChannelReference, m *provisioners.Message) error {
+		return h.dispatcher.DispatchMessage(m, h.channelURI, "", provisioners.DispatchDefaults{})
+	}, logger.Sugar())
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	h.receiver.HandleRequest(w, r)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ingress
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	testCases := map[string]struct {
+		method         string
+		path           string
+		channel        func(http.ResponseWriter, *http.Request)
+		expectedStatus int
+		wantForwarded  bool
+	}{
+		"forwarded": {
+			expectedStatus: http.StatusAccepted,
+			wantForwarded:  true,
+		},
+		"channel fails": {
+			channel:        func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) },
+			expectedStatus: http.StatusInternalServerError,
+			wantForwarded:  true,
+		},
+		"wrong path": {
+			path:           "/foo",
+			expectedStatus: http.StatusNotFound,
+		},
+		"wrong method": {
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var forwarded string
+			channel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				forwarded = string(b)
+				if tc.channel != nil {
+					tc.channel(w, r)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer channel.Close()
+
+			method := http.MethodPost
+			if tc.method != "" {
+				method = tc.method
+			}
+			path := "/"
+			if tc.path != "" {
+				path = tc.path
+			}
+			req := httptest.NewRequest(method, "http://default-broker.test-namespace.svc.cluster.local"+path, strings.NewReader("event"))
+			w := httptest.NewRecorder()
+			NewHandler(zap.NewNop(), channel.URL).ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Unexpected status code. Expected %v, actual %v", tc.expectedStatus, w.Code)
+			}
+			if got := forwarded == "event"; got != tc.wantForwarded {
+				t.Errorf("Unexpected forwarding. Expected %v, actual %v (%q)", tc.wantForwarded, got, forwarded)
+			}
+		})
+	}
+}
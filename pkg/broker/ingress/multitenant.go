@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ingress
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/knative/eventing/pkg/broker"
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+)
+
+const (
+	// ConfigDir is the mount path of the multi-tenant ingress's ConfigMap volume.
+	ConfigDir = "/etc/config/broker-ingress"
+)
+
+// MultiTenantHandler writes the events sent to it to the Channel of the Broker they are addressed
+// to, identified by the host of the request, according to its Config.
+type MultiTenantHandler struct {
+	// channelURIs holds a map[provisioners.ChannelReference]string. It is replaced as a whole
+	// when the config is updated.
+	channelURIs atomic.Value
+	receiver    *provisioners.MessageReceiver
+	dispatcher  provisioners.Dispatcher
+
+	logger *zap.Logger
+}
+
+var _ http.Handler = &MultiTenantHandler{}
+
+// NewMultiTenantHandler creates a MultiTenantHandler without any routes.
+func NewMultiTenantHandler(logger *zap.Logger) *MultiTenantHandler {
+	h := &MultiTenantHandler{
+		dispatcher: provisioners.NewMessageDispatcher(logger.Sugar()),
+		logger:     logger,
+	}
+	h.channelURIs.Store(map[provisioners.ChannelReference]string{})
+	h.receiver = provisioners.NewMessageReceiver(func(ref provisioners.ChannelReference, m *provisioners.Message) error {
+		channelURI, ok := h.channelURIs.Load().(map[provisioners.ChannelReference]string)[ref]
+		if !ok {
+			h.logger.Info("Received an event for an unknown broker", zap.String("host", ref.String()))
+			return provisioners.ErrUnknownChannel
+		}
+		return h.dispatcher.DispatchMessage(m, channelURI, "", provisioners.DispatchDefaults{})
+	}, logger.Sugar())
+	return h
+}
+
+// UpdateConfig replaces the routes of the MultiTenantHandler with those in config.
+func (h *MultiTenantHandler) UpdateConfig(config *Config) {
+	channelURIs := make(map[provisioners.ChannelReference]string, len(config.Routes))
+	for _, r := range config.Routes {
+		channelURIs[provisioners.ChannelReference{Namespace: r.Namespace, Name: r.Name}] = r.ChannelURI
+	}
+	h.channelURIs.Store(channelURIs)
+}
+
+func (h *MultiTenantHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	h.receiver.HandleRequest(w, r)
+}
+
+// NewConfigWatcher reads the config in dir and applies it to h. The caller is responsible for
+// calling Start(<-chan) on the returned watcher to keep applying changes.
+func NewConfigWatcher(logger *zap.Logger, dir string, h *MultiTenantHandler) (*broker.ConfigWatcher, error) {
+	return broker.NewConfigWatcher(logger, dir, func(data map[string]string) error {
+		config, err := NewConfig(data)
+		if err != nil {
+			return err
+		}
+		h.UpdateConfig(config)
+		return nil
+	})
+}
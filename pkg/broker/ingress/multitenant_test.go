@@ -0,0 +1,107 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package ingress
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMultiTenantHandler_ServeHTTP(t *testing.T) {
+	testCases := map[string]struct {
+		method         string
+		path           string
+		host           string
+		expectedStatus int
+		wantForwarded  string
+	}{
+		"first broker": {
+			host:           "first-broker-ingress.test-namespace.svc.cluster.local",
+			expectedStatus: http.StatusAccepted,
+			wantForwarded:  "first",
+		},
+		"second broker": {
+			host:           "second-broker-ingress.other-namespace.svc.cluster.local",
+			expectedStatus: http.StatusAccepted,
+			wantForwarded:  "second",
+		},
+		"unknown broker": {
+			host:           "first-broker-ingress.other-namespace.svc.cluster.local",
+			expectedStatus: http.StatusNotFound,
+		},
+		"wrong path": {
+			host:           "first-broker-ingress.test-namespace.svc.cluster.local",
+			path:           "/foo",
+			expectedStatus: http.StatusNotFound,
+		},
+		"wrong method": {
+			host:           "first-broker-ingress.test-namespace.svc.cluster.local",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var forwarded string
+			newChannel := func(name string) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					ioutil.ReadAll(r.Body)
+					forwarded = name
+					w.WriteHeader(http.StatusAccepted)
+				}))
+			}
+			first := newChannel("first")
+			defer first.Close()
+			second := newChannel("second")
+			defer second.Close()
+
+			h := NewMultiTenantHandler(zap.NewNop())
+			h.UpdateConfig(&Config{Routes: []Route{{
+				Namespace:  "test-namespace",
+				Name:       "first-broker-ingress",
+				ChannelURI: first.URL,
+			}, {
+				Namespace:  "other-namespace",
+				Name:       "second-broker-ingress",
+				ChannelURI: second.URL,
+			}}})
+
+			method := http.MethodPost
+			if tc.method != "" {
+				method = tc.method
+			}
+			path := "/"
+			if tc.path != "" {
+				path = tc.path
+			}
+			req := httptest.NewRequest(method, "http://"+tc.host+path, strings.NewReader("event"))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Unexpected status code. Expected %v, actual %v", tc.expectedStatus, w.Code)
+			}
+			if forwarded != tc.wantForwarded {
+				t.Errorf("Unexpected forwarding. Expected %q, actual %q", tc.wantForwarded, forwarded)
+			}
+		})
+	}
+}
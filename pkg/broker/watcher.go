@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package broker contains the code shared by the ingress and the filter of Brokers.
+package broker
+
+import (
+	"errors"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/knative/pkg/configmap"
+	"go.uber.org/zap"
+)
+
+// ConfigWatcher monitors an attached ConfigMap volume and applies its data when the ConfigMap
+// changes.
+type ConfigWatcher struct {
+	logger *zap.Logger
+	dir    string
+	update func(map[string]string) error
+}
+
+// NewConfigWatcher reads the ConfigMap data in dir and applies it with update. The caller is
+// responsible for calling Start(<-chan) on the returned watcher to keep applying changes, likely
+// via a controller-runtime Manager.
+func NewConfigWatcher(logger *zap.Logger, dir string, update func(map[string]string) error) (*ConfigWatcher, error) {
+	cw := &ConfigWatcher{
+		logger: logger,
+		dir:    dir,
+		update: update,
+	}
+	if err := cw.updateConfig(); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *ConfigWatcher) updateConfig() error {
+	data, err := configmap.Load(cw.dir)
+	if err != nil {
+		return err
+	}
+	return cw.update(data)
+}
+
+func (cw *ConfigWatcher) Start(stopCh <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(cw.dir); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return errors.New("watcher.Events channel closed")
+			}
+			if err := cw.updateConfig(); err != nil {
+				cw.logger.Error("Unable to update config", zap.Error(err))
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return errors.New("watcher.Errors channel closed")
+			}
+			cw.logger.Error("watcher.Errors", zap.Error(err))
+		case <-stopCh:
+			return watcher.Close()
+		}
+	}
+}
@@ -49,13 +49,17 @@ type Config struct {
 	ClusterDefault *corev1.ObjectReference `json:"clusterDefault,omitempty"`
 }
 
-// ChannelDefaulter adds a default ClusterChannelProvisioner to Channels that do not have any
-// provisioner specified. The default is stored in a ConfigMap and can be updated at runtime.
+// ChannelDefaulter adds a default ClusterChannelProvisioner and arguments to Channels that do not
+// have any provisioner specified. The defaults are stored in ConfigMaps and can be updated at
+// runtime.
 type ChannelDefaulter struct {
 	// The current default ClusterChannelProvisioner to set. This should only be accessed via
 	// getConfig() and setConfig(), as they correctly enforce the type we require (*Config).
 	config atomic.Value
-	logger *zap.Logger
+	// The current default Channel templates, which take precedence over config. This should only
+	// be accessed via getTemplates() and setTemplates() (*TemplatesConfig).
+	templates atomic.Value
+	logger    *zap.Logger
 }
 
 var _ eventingv1alpha1.ChannelProvisionerDefaulter = &ChannelDefaulter{}
@@ -65,6 +69,7 @@ var _ eventingv1alpha1.ChannelProvisionerDefaulter = &ChannelDefaulter{}
 // channelDefaulter := channeldefaulter.New(logger)
 // eventingv1alpha1.ChannelDefaulterSingleton = channelDefaulter
 // configMapWatcher.Watch(channeldefaulter.ConfigMapName, channelDefaulter.UpdateConfigMap)
+// configMapWatcher.Watch(channeldefaulter.TemplatesConfigMapName, channelDefaulter.UpdateTemplatesConfigMap)
 func New(logger *zap.Logger) *ChannelDefaulter {
 	return &ChannelDefaulter{
 		logger: logger.With(zap.String("role", "channelDefaulter")),
@@ -122,20 +127,37 @@ func (cd *ChannelDefaulter) GetDefault(c *eventingv1alpha1.Channel) (*corev1.Obj
 		return nil, nil
 	}
 	config := cd.getConfig()
-	if config == nil {
-		return nil, nil
-	}
+	templates := cd.getTemplates()
 
 	// TODO Don't use a single default, instead use the Channel's arguments to determine the type of
 	// Channel to use (e.g. it can say whether it needs to be persistent, strictly ordered, etc.).
-	dp := getDefaultProvisioner(config, c.Namespace)
-	cd.logger.Info("Defaulting the ClusterChannelProvisioner", zap.Any("defaultClusterChannelProvisioner", dp))
-	return dp, nil
+	t := getDefaultTemplate(templates, config, c.Namespace)
+	if t == nil || t.Provisioner == nil {
+		return nil, nil
+	}
+	cd.logger.Info("Defaulting the ClusterChannelProvisioner", zap.Any("defaultClusterChannelProvisioner", t.Provisioner))
+	// The config is shared by every Channel, return a copy the caller can modify.
+	return t.Provisioner.DeepCopy(), t.Arguments.DeepCopy()
 }
 
-func getDefaultProvisioner(config *Config, namespace string) *corev1.ObjectReference {
-	if dp, ok := config.NamespaceDefaults[namespace]; ok {
-		return dp
+// getDefaultTemplate returns the template of namespace. The namespace defaults of templates and
+// config take precedence over their cluster defaults, and templates takes precedence over config.
+func getDefaultTemplate(templates *TemplatesConfig, config *Config, namespace string) *ChannelTemplate {
+	if templates != nil {
+		if t, ok := templates.NamespaceDefaults[namespace]; ok {
+			return t
+		}
 	}
-	return config.ClusterDefault
+	if config != nil {
+		if dp, ok := config.NamespaceDefaults[namespace]; ok {
+			return &ChannelTemplate{Provisioner: dp}
+		}
+	}
+	if templates != nil && templates.ClusterDefault != nil {
+		return templates.ClusterDefault
+	}
+	if config != nil && config.ClusterDefault != nil {
+		return &ChannelTemplate{Provisioner: config.ClusterDefault}
+	}
+	return nil
 }
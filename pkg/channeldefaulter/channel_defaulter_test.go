@@ -86,6 +86,15 @@ func TestChannelDefaulter_getDefaultProvider(t *testing.T) {
 			},
 			expectedProv: configWithNamespace.NamespaceDefaults[testNamespace],
 		},
+		"other namespace cluster defaulted": {
+			config: configWithNamespace,
+			channel: &eventingv1alpha1.Channel{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "other-namespace",
+				},
+			},
+			expectedProv: configWithNamespace.ClusterDefault,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -107,6 +116,17 @@ func TestChannelDefaulter_getDefaultProvider(t *testing.T) {
 	}
 }
 
+func TestChannelDefaulter_GetDefaultReturnsCopy(t *testing.T) {
+	cd := New(zap.NewNop())
+	cd.setConfig(configWithNamespace)
+	prov, _ := cd.GetDefault(&eventingv1alpha1.Channel{})
+	prov.Name = "modified"
+	prov, _ = cd.GetDefault(&eventingv1alpha1.Channel{})
+	if diff := cmp.Diff(configWithNamespace.ClusterDefault, prov); diff != "" {
+		t.Fatalf("Unexpected provisioner (-want, +got): %s", diff)
+	}
+}
+
 func TestChannelDefaulter_UpdateConfigMap(t *testing.T) {
 	testCases := map[string]struct {
 		initialConfig        *corev1.ConfigMap
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channeldefaulter
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// TemplatesConfigMapName is the name of the ConfigMap that contains the default Channel
+	// templates of the cluster and of each namespace.
+	TemplatesConfigMapName = "config-default-channel"
+
+	// templatesKey is the key in the ConfigMap to get the default Channel templates.
+	templatesKey = "default-channel-templates"
+)
+
+// ChannelTemplate is the provisioner and arguments given to the Channels that do not specify
+// any provisioner.
+type ChannelTemplate struct {
+	// Provisioner is the provisioner of the defaulted Channels.
+	Provisioner *corev1.ObjectReference `json:"provisioner"`
+	// Arguments are the arguments passed to Provisioner for the defaulted Channels.
+	// +optional
+	Arguments *runtime.RawExtension `json:"arguments,omitempty"`
+}
+
+// TemplatesConfig is the data structure serialized to YAML in the config-default-channel
+// ConfigMap. Unlike Config, it uses the same field names as the Channel spec, so that arguments
+// can be given in their usual form. When a Channel needs to be defaulted, the template of the
+// Channel's namespace in NamespaceDefaults is used if there is one, otherwise ClusterDefault is.
+type TemplatesConfig struct {
+	// NamespaceDefaults are the default Channel templates of each namespace. namespace is the
+	// key, the value is the template to use.
+	NamespaceDefaults map[string]*ChannelTemplate `json:"namespaceDefaults,omitempty"`
+	// ClusterDefault is the default Channel template of all the namespaces that are not in
+	// NamespaceDefaults.
+	ClusterDefault *ChannelTemplate `json:"clusterDefault,omitempty"`
+}
+
+// UpdateTemplatesConfigMap reads in the config-default-channel ConfigMap and updates the
+// internal default Channel templates to use. Invalid templates are ignored, leaving the
+// previous ones in place.
+func (cd *ChannelDefaulter) UpdateTemplatesConfigMap(cm *corev1.ConfigMap) {
+	if cm == nil {
+		cd.logger.Info("UpdateTemplatesConfigMap on a nil map")
+		return
+	}
+	templates, present := cm.Data[templatesKey]
+	if !present {
+		cd.logger.Info("ConfigMap is missing key", zap.String("key", templatesKey), zap.Any("configMap", cm))
+		return
+	}
+
+	if templates == "" {
+		cd.logger.Info("ConfigMap's value was the empty string, ignoring it.", zap.Any("configMap", cm))
+		return
+	}
+
+	config := TemplatesConfig{}
+	if err := yaml.Unmarshal([]byte(templates), &config); err != nil {
+		cd.logger.Error("ConfigMap's value could not be unmarshaled.", zap.Error(err), zap.Any("configMap", cm))
+		return
+	}
+	if err := config.validate(); err != nil {
+		cd.logger.Error("ConfigMap's value is invalid.", zap.Error(err), zap.Any("configMap", cm))
+		return
+	}
+
+	cd.logger.Info("Updated channelDefaulter templates", zap.Any("templates", config))
+	cd.setTemplates(&config)
+}
+
+// validate checks that every template of c has a provisioner.
+func (c *TemplatesConfig) validate() error {
+	if c.ClusterDefault != nil && c.ClusterDefault.Provisioner == nil {
+		return fmt.Errorf("clusterDefault is missing a provisioner")
+	}
+	for ns, t := range c.NamespaceDefaults {
+		if t == nil || t.Provisioner == nil {
+			return fmt.Errorf("namespaceDefaults of %q is missing a provisioner", ns)
+		}
+	}
+	return nil
+}
+
+// setTemplates is a typed wrapper around templates.
+func (cd *ChannelDefaulter) setTemplates(config *TemplatesConfig) {
+	cd.templates.Store(config)
+}
+
+// getTemplates is a typed wrapper around templates.
+func (cd *ChannelDefaulter) getTemplates() *TemplatesConfig {
+	if config, ok := cd.templates.Load().(*TemplatesConfig); ok {
+		return config
+	}
+	return nil
+}
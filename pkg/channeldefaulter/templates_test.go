@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channeldefaulter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const templatesYaml = `
+clusterDefault:
+  provisioner:
+    apiVersion: eventing.knative.dev/v1alpha1
+    kind: ClusterChannelProvisioner
+    name: cluster-template
+namespaceDefaults:
+  test-namespace:
+    provisioner:
+      apiVersion: eventing.knative.dev/v1alpha1
+      kind: ClusterChannelProvisioner
+      name: namespace-template
+    arguments:
+      replicas: 3
+`
+
+func provisioner(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "ClusterChannelProvisioner",
+		Name:       name,
+	}
+}
+
+func TestChannelDefaulter_Templates(t *testing.T) {
+	testCases := map[string]struct {
+		config       *Config
+		templates    string
+		namespace    string
+		expectedProv *corev1.ObjectReference
+		expectedArgs *runtime.RawExtension
+	}{
+		"cluster template": {
+			templates:    templatesYaml,
+			namespace:    "other-namespace",
+			expectedProv: provisioner("cluster-template"),
+		},
+		"namespace template": {
+			templates:    templatesYaml,
+			namespace:    testNamespace,
+			expectedProv: provisioner("namespace-template"),
+			expectedArgs: &runtime.RawExtension{Raw: []byte(`{"replicas":3}`)},
+		},
+		"namespace template takes precedence over namespace default": {
+			config:       configWithNamespace,
+			templates:    templatesYaml,
+			namespace:    testNamespace,
+			expectedProv: provisioner("namespace-template"),
+			expectedArgs: &runtime.RawExtension{Raw: []byte(`{"replicas":3}`)},
+		},
+		"namespace default takes precedence over cluster template": {
+			config: configWithNamespace,
+			templates: `
+clusterDefault:
+  provisioner:
+    name: cluster-template
+`,
+			namespace:    testNamespace,
+			expectedProv: configWithNamespace.NamespaceDefaults[testNamespace],
+		},
+		"cluster template takes precedence over cluster default": {
+			config:       configWithNamespace,
+			templates:    templatesYaml,
+			namespace:    "other-namespace",
+			expectedProv: provisioner("cluster-template"),
+		},
+		"cluster default without templates": {
+			config:       configWithNamespace,
+			templates:    "{}",
+			namespace:    "other-namespace",
+			expectedProv: configWithNamespace.ClusterDefault,
+		},
+		"template without provisioner is ignored": {
+			config: configWithNamespace,
+			templates: `
+namespaceDefaults:
+  test-namespace:
+    arguments:
+      replicas: 3
+`,
+			namespace:    testNamespace,
+			expectedProv: configWithNamespace.NamespaceDefaults[testNamespace],
+		},
+		"bad yaml is ignored": {
+			templates: "{foo",
+			namespace: testNamespace,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cd := New(zap.NewNop())
+			if tc.config != nil {
+				cd.setConfig(tc.config)
+			}
+			cd.UpdateTemplatesConfigMap(&corev1.ConfigMap{
+				Data: map[string]string{
+					templatesKey: tc.templates,
+				},
+			})
+			prov, args := cd.GetDefault(&eventingv1alpha1.Channel{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: tc.namespace,
+				},
+			})
+			if diff := cmp.Diff(tc.expectedProv, prov); diff != "" {
+				t.Errorf("Unexpected provisioner (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedArgs, args); diff != "" {
+				t.Errorf("Unexpected args (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestChannelDefaulter_UpdateTemplatesConfigMapKeepsPrevious(t *testing.T) {
+	cd := New(zap.NewNop())
+	cd.UpdateTemplatesConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{
+			templatesKey: templatesYaml,
+		},
+	})
+	cd.UpdateTemplatesConfigMap(nil)
+	cd.UpdateTemplatesConfigMap(&corev1.ConfigMap{})
+	cd.UpdateTemplatesConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{
+			templatesKey: "",
+		},
+	})
+	prov, _ := cd.GetDefault(&eventingv1alpha1.Channel{})
+	if diff := cmp.Diff(provisioner("cluster-template"), prov); diff != "" {
+		t.Fatalf("Unexpected provisioner (-want, +got): %s", diff)
+	}
+}
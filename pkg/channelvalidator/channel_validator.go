@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channelvalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/knative/pkg/apis"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	listers "github.com/knative/eventing/pkg/client/listers/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/jsonschema"
+)
+
+// ChannelValidator validates the arguments of Channels against the JSON Schema or the capabilities
+// published by their ClusterChannelProvisioner in its status. Channels whose provisioner does not
+// exist yet or publishes neither are accepted, their arguments are checked when they are
+// reconciled.
+type ChannelValidator struct {
+	lister listers.ClusterChannelProvisionerLister
+	logger *zap.Logger
+
+	lock sync.Mutex
+	// schemas caches the compiled schemas by ClusterChannelProvisioner name.
+	schemas map[string]cachedSchema
+}
+
+// cachedSchema is a compiled schema and the resourceVersion of the ClusterChannelProvisioner it
+// was read from.
+type cachedSchema struct {
+	resourceVersion string
+	schema          *jsonschema.Schema
+}
+
+var _ eventingv1alpha1.ChannelArgumentsValidator = &ChannelValidator{}
+
+// New creates a new ChannelValidator. The caller is expected to set this as the global singleton.
+//
+// eventingv1alpha1.ChannelArgumentsValidatorSingleton = channelvalidator.New(lister, logger)
+func New(lister listers.ClusterChannelProvisionerLister, logger *zap.Logger) *ChannelValidator {
+	return &ChannelValidator{
+		lister:  lister,
+		logger:  logger.With(zap.String("role", "channelValidator")),
+		schemas: make(map[string]cachedSchema),
+	}
+}
+
+// ValidateArguments validates the arguments of c against the schema of its provisioner. If the
+// provisioner does not publish a schema, but publishes its capabilities, the arguments of c must be
+// among the supported arguments.
+func (cv *ChannelValidator) ValidateArguments(c *eventingv1alpha1.Channel) *apis.FieldError {
+	p := c.Spec.Provisioner
+	if p == nil || p.Kind != "ClusterChannelProvisioner" {
+		return nil
+	}
+	ccp, err := cv.lister.Get(p.Name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		cv.logger.Error("Unable to get the provisioner", zap.String("provisioner", p.Name), zap.Error(err))
+		return nil
+	}
+
+	args := []byte("{}")
+	if c.Spec.Arguments != nil && len(c.Spec.Arguments.Raw) > 0 {
+		args = c.Spec.Arguments.Raw
+	}
+
+	schema, err := cv.schema(ccp)
+	if err != nil {
+		cv.logger.Error("Unable to read the arguments schema", zap.String("provisioner", p.Name), zap.Error(err))
+		return nil
+	}
+	if schema == nil {
+		return unsupportedArguments(ccp, args)
+	}
+	if err := schema.Validate(args); err != nil {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("invalid arguments for provisioner %q", p.Name),
+			Paths:   []string{apis.CurrentField},
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// unsupportedArguments returns an error if args has arguments that are not among the arguments
+// supported by ccp. All arguments are accepted if ccp does not publish its capabilities.
+func unsupportedArguments(ccp *eventingv1alpha1.ClusterChannelProvisioner, args []byte) *apis.FieldError {
+	capabilities := ccp.Status.Capabilities
+	if capabilities == nil {
+		return nil
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(args, &values); err != nil {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("invalid arguments for provisioner %q", ccp.Name),
+			Paths:   []string{apis.CurrentField},
+			Details: err.Error(),
+		}
+	}
+	supported := sets.NewString(capabilities.Arguments...)
+	var unsupported []string
+	for name := range values {
+		if !supported.Has(name) {
+			unsupported = append(unsupported, name)
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	sort.Strings(unsupported)
+	details := "The provisioner does not support any argument."
+	if supported.Len() > 0 {
+		details = fmt.Sprintf("The supported arguments are: %s.", strings.Join(supported.List(), ", "))
+	}
+	return &apis.FieldError{
+		Message: fmt.Sprintf("arguments not supported by provisioner %q: %s", ccp.Name, strings.Join(unsupported, ", ")),
+		Paths:   []string{apis.CurrentField},
+		Details: details,
+	}
+}
+
+// schema returns the compiled arguments schema of ccp, or nil if it does not publish one.
+func (cv *ChannelValidator) schema(ccp *eventingv1alpha1.ClusterChannelProvisioner) (*jsonschema.Schema, error) {
+	if ccp.Status.ArgumentsSchema == nil || len(ccp.Status.ArgumentsSchema.Raw) == 0 {
+		return nil, nil
+	}
+	name := ccp.Name
+
+	cv.lock.Lock()
+	defer cv.lock.Unlock()
+	if cached, ok := cv.schemas[name]; ok && cached.resourceVersion == ccp.ResourceVersion {
+		return cached.schema, nil
+	}
+	compiled, err := jsonschema.Compile(ccp.Status.ArgumentsSchema.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile the arguments schema: %v", err)
+	}
+	cv.schemas[name] = cachedSchema{resourceVersion: ccp.ResourceVersion, schema: compiled}
+	return compiled, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channelvalidator
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	listers "github.com/knative/eventing/pkg/client/listers/eventing/v1alpha1"
+)
+
+const (
+	testSchema = `{
+		"type": "object",
+		"properties": {"partitions": {"type": "integer", "minimum": 1}},
+		"additionalProperties": false
+	}`
+)
+
+func TestValidateArguments(t *testing.T) {
+	testCases := map[string]struct {
+		provisioners []*eventingv1alpha1.ClusterChannelProvisioner
+		provisioner  *corev1.ObjectReference
+		arguments    string
+		wantErr      string
+	}{
+		"no provisioner": {
+			arguments: `{"partitions": 0}`,
+		},
+		"not a ClusterChannelProvisioner": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", testSchema)},
+			provisioner:  &corev1.ObjectReference{Kind: "Other", Name: "foo"},
+			arguments:    `{"partitions": 0}`,
+		},
+		"provisioner not found": {
+			provisioner: ref("foo"),
+			arguments:   `{"partitions": 0}`,
+		},
+		"no schema": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", "")},
+			provisioner:  ref("foo"),
+			arguments:    `{"partitions": 0}`,
+		},
+		"invalid schema": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", `{"type": 1}`)},
+			provisioner:  ref("foo"),
+			arguments:    `{"partitions": 0}`,
+		},
+		"valid arguments": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", testSchema)},
+			provisioner:  ref("foo"),
+			arguments:    `{"partitions": 3}`,
+		},
+		"no arguments": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", testSchema)},
+			provisioner:  ref("foo"),
+		},
+		"invalid value": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", testSchema)},
+			provisioner:  ref("foo"),
+			arguments:    `{"partitions": 0}`,
+			wantErr:      `invalid arguments for provisioner "foo"`,
+		},
+		"unknown argument": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{ccp("foo", testSchema)},
+			provisioner:  ref("foo"),
+			arguments:    `{"replicas": 3}`,
+			wantErr:      `invalid arguments for provisioner "foo"`,
+		},
+		"supported arguments": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{capable("foo", "partitions", "replicas")},
+			provisioner:  ref("foo"),
+			arguments:    `{"partitions": 0}`,
+		},
+		"unsupported arguments": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{capable("foo", "partitions")},
+			provisioner:  ref("foo"),
+			arguments:    `{"replicas": 3, "acks": "all", "partitions": 1}`,
+			wantErr:      `arguments not supported by provisioner "foo": acks, replicas`,
+		},
+		"no supported arguments": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{capable("foo")},
+			provisioner:  ref("foo"),
+			arguments:    `{"replicas": 3}`,
+			wantErr:      `arguments not supported by provisioner "foo": replicas`,
+		},
+		"no arguments without supported arguments": {
+			provisioners: []*eventingv1alpha1.ClusterChannelProvisioner{capable("foo")},
+			provisioner:  ref("foo"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, p := range tc.provisioners {
+				indexer.Add(p)
+			}
+			cv := New(listers.NewClusterChannelProvisionerLister(indexer), zap.NewNop())
+
+			c := &eventingv1alpha1.Channel{
+				Spec: eventingv1alpha1.ChannelSpec{
+					Provisioner: tc.provisioner,
+				},
+			}
+			if tc.arguments != "" {
+				c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(tc.arguments)}
+			}
+			err := cv.ValidateArguments(c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error %q, got nil", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected error %q, got %q", tc.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestSchemaCache(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	p := ccp("foo", testSchema)
+	indexer.Add(p)
+	cv := New(listers.NewClusterChannelProvisionerLister(indexer), zap.NewNop())
+
+	c := &eventingv1alpha1.Channel{
+		Spec: eventingv1alpha1.ChannelSpec{
+			Provisioner: ref("foo"),
+			Arguments:   &runtime.RawExtension{Raw: []byte(`{"replicas": 3}`)},
+		},
+	}
+	if err := cv.ValidateArguments(c); err == nil {
+		t.Fatal("Expected an error before the schema changed")
+	}
+
+	// A new version of the provisioner replaces the cached schema.
+	updated := ccp("foo", `{"type": "object"}`)
+	updated.ResourceVersion = "2"
+	indexer.Update(updated)
+	if err := cv.ValidateArguments(c); err != nil {
+		t.Errorf("Unexpected error after the schema changed: %v", err)
+	}
+}
+
+func ref(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "ClusterChannelProvisioner",
+		Name:       name,
+	}
+}
+
+func ccp(name, schema string) *eventingv1alpha1.ClusterChannelProvisioner {
+	p := &eventingv1alpha1.ClusterChannelProvisioner{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			ResourceVersion: "1",
+		},
+	}
+	if schema != "" {
+		p.Status.ArgumentsSchema = &runtime.RawExtension{Raw: []byte(schema)}
+	}
+	return p
+}
+
+func capable(name string, arguments ...string) *eventingv1alpha1.ClusterChannelProvisioner {
+	p := ccp(name, "")
+	p.Status.Capabilities = &eventingv1alpha1.ClusterChannelProvisionerCapabilities{
+		Arguments: arguments,
+	}
+	return p
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cli implements the subcommands of the kubectl-eventing plugin.
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knative/eventing/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// maxHops is the largest number of Services and VirtualServices followed to find the workload
+// behind an address.
+const maxHops = 5
+
+var (
+	serviceResource        = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	virtualServiceResource = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1alpha3", Resource: "virtualservices"}
+)
+
+// Resolver resolves the addresses of the addressable eventing objects.
+type Resolver struct {
+	Eventing versioned.Interface
+	Dynamic  dynamic.Interface
+
+	// Proxy reads path from port of a pod through the API server. The metrics of the dispatchers
+	// are not read if it is nil.
+	Proxy func(namespace, pod string, port int, path string) ([]byte, error)
+}
+
+// Hostname returns the hostname in the status of the object of kind (channel, broker, sequence or
+// parallel) named name in namespace. It returns an error if the object is not addressable yet.
+func (r *Resolver) Hostname(kind, namespace, name string) (string, error) {
+	var hostname string
+	switch strings.ToLower(kind) {
+	case "channel":
+		c, err := r.Eventing.EventingV1alpha1().Channels(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		hostname = c.Status.Address.Hostname
+	case "broker":
+		b, err := r.Eventing.EventingV1alpha1().Brokers(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		hostname = b.Status.Address.Hostname
+	case "sequence":
+		s, err := r.Eventing.EventingV1alpha1().Sequences(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		hostname = s.Status.Address.Hostname
+	case "parallel":
+		p, err := r.Eventing.EventingV1alpha1().Parallels(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		hostname = p.Status.Address.Hostname
+	default:
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+	if hostname == "" {
+		return "", fmt.Errorf("%s %s/%s is not addressable yet", kind, namespace, name)
+	}
+	return hostname, nil
+}
+
+// Target is the workload the events sent to an address are delivered to.
+type Target struct {
+	// Host is the Host header the events are sent with, which the workload routes them by.
+	Host string
+
+	// Namespace, Service and Port name the Service of the workload, which a port-forward reaches
+	// it through.
+	Namespace string
+	Service   string
+	Port      int64
+}
+
+// Target returns the workload behind hostname, the in-cluster hostname of a Service. It follows
+// ExternalName Services, and the VirtualServices routing the Services without a selector, such as
+// those of the Channels.
+func (r *Resolver) Target(hostname string) (*Target, error) {
+	t := &Target{Host: hostname}
+	for i := 0; i < maxHops; i++ {
+		name, namespace, err := serviceName(hostname)
+		if err != nil {
+			return nil, err
+		}
+		svc, err := r.Dynamic.Resource(serviceResource).Namespace(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if externalName, _, _ := unstructured.NestedString(svc.Object, "spec", "externalName"); externalName != "" {
+			hostname = externalName
+			continue
+		}
+		if selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector"); len(selector) > 0 {
+			t.Namespace, t.Service = namespace, name
+			if t.Port == 0 {
+				t.Port = firstPort(svc)
+			}
+			return t, nil
+		}
+		vs, err := r.Dynamic.Resource(virtualServiceResource).Namespace(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Service %s/%s has no selector, and no VirtualService routes it: %v", namespace, name, err)
+		}
+		if hostname, err = route(vs, t); err != nil {
+			return nil, fmt.Errorf("VirtualService %s/%s: %v", namespace, name, err)
+		}
+	}
+	return nil, fmt.Errorf("%s is not served by a workload after %d hops", t.Host, maxHops)
+}
+
+// route returns the destination host of the first HTTP route of the VirtualService vs, and sets
+// the Host and Port of t to the authority it rewrites and the port it routes to, if any.
+func route(vs *unstructured.Unstructured, t *Target) (string, error) {
+	routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+	if len(routes) == 0 {
+		return "", fmt.Errorf("no HTTP route")
+	}
+	r, _ := routes[0].(map[string]interface{})
+	if authority, _, _ := unstructured.NestedString(r, "rewrite", "authority"); authority != "" {
+		t.Host = authority
+	}
+	destinations, _, _ := unstructured.NestedSlice(r, "route")
+	if len(destinations) == 0 {
+		return "", fmt.Errorf("no destination")
+	}
+	d, _ := destinations[0].(map[string]interface{})
+	host, _, _ := unstructured.NestedString(d, "destination", "host")
+	if host == "" {
+		return "", fmt.Errorf("no destination host")
+	}
+	if port, ok, _ := unstructured.NestedInt64(d, "destination", "port", "number"); ok {
+		t.Port = port
+	}
+	return host, nil
+}
+
+// firstPort returns the first port of the Service svc, or 80 if it has none.
+func firstPort(svc *unstructured.Unstructured) int64 {
+	ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+	if len(ports) > 0 {
+		if p, ok := ports[0].(map[string]interface{}); ok {
+			if port, ok, _ := unstructured.NestedInt64(p, "port"); ok {
+				return port
+			}
+		}
+	}
+	return 80
+}
+
+// serviceName returns the name and namespace of the Service whose in-cluster hostname is hostname,
+// such as broker.default.svc.cluster.local.
+func serviceName(hostname string) (string, string, error) {
+	parts := strings.Split(hostname, ".")
+	if len(parts) < 3 || parts[2] != "svc" {
+		return "", "", fmt.Errorf("%q is not the hostname of a Service", hostname)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const testNS = "test-namespace"
+
+func TestHostname(t *testing.T) {
+	ready := &v1alpha1.Channel{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "ready"}}
+	ready.Status.SetAddress("ready-channel.test-namespace.svc.cluster.local")
+	pending := &v1alpha1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "pending"}}
+	r := &Resolver{Eventing: fake.NewSimpleClientset(ready, pending)}
+
+	testCases := map[string]struct {
+		kind    string
+		name    string
+		want    string
+		wantErr bool
+	}{
+		"addressable": {
+			kind: "Channel",
+			name: "ready",
+			want: "ready-channel.test-namespace.svc.cluster.local",
+		},
+		"not addressable yet": {
+			kind:    "broker",
+			name:    "pending",
+			wantErr: true,
+		},
+		"not found": {
+			kind:    "sequence",
+			name:    "missing",
+			wantErr: true,
+		},
+		"unsupported kind": {
+			kind:    "service",
+			name:    "ready",
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := r.Hostname(tc.kind, testNS, tc.name)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("Unexpected hostname. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTarget(t *testing.T) {
+	objects := []runtime.Object{
+		// The Service of a Channel, routed to the dispatcher by a VirtualService.
+		service(testNS, "foo-channel", nil),
+		virtualService(testNS, "foo-channel", "foo.test-namespace.channels.cluster.local", "in-memory-channel-dispatcher.knative-eventing.svc.cluster.local", 80),
+		service("knative-eventing", "in-memory-channel-dispatcher", map[string]interface{}{"selector": map[string]interface{}{"app": "dispatcher"}}),
+		// The Service of a Broker pointing to a shared ingress.
+		service(testNS, "default-broker", map[string]interface{}{"externalName": "broker-ingress.knative-eventing.svc.cluster.local"}),
+		service("knative-eventing", "broker-ingress", map[string]interface{}{
+			"selector": map[string]interface{}{"app": "broker-ingress"},
+			"ports":    []interface{}{map[string]interface{}{"port": int64(8080)}},
+		}),
+		// A Service without a selector nor VirtualService.
+		service(testNS, "orphan", nil),
+		// A loop of ExternalName Services.
+		service(testNS, "loop", map[string]interface{}{"externalName": "loop.test-namespace.svc.cluster.local"}),
+	}
+	r := &Resolver{Dynamic: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)}
+
+	testCases := map[string]struct {
+		hostname string
+		want     *Target
+		wantErr  bool
+	}{
+		"channel": {
+			hostname: "foo-channel.test-namespace.svc.cluster.local",
+			want: &Target{
+				Host:      "foo.test-namespace.channels.cluster.local",
+				Namespace: "knative-eventing",
+				Service:   "in-memory-channel-dispatcher",
+				Port:      80,
+			},
+		},
+		"broker": {
+			hostname: "default-broker.test-namespace.svc.cluster.local",
+			want: &Target{
+				Host:      "default-broker.test-namespace.svc.cluster.local",
+				Namespace: "knative-eventing",
+				Service:   "broker-ingress",
+				Port:      8080,
+			},
+		},
+		"no route": {
+			hostname: "orphan.test-namespace.svc.cluster.local",
+			wantErr:  true,
+		},
+		"loop": {
+			hostname: "loop.test-namespace.svc.cluster.local",
+			wantErr:  true,
+		},
+		"not a Service": {
+			hostname: "example.com",
+			wantErr:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := r.Target(tc.hostname)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected target (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func service(namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": spec,
+	}}
+}
+
+func virtualService(namespace, name, authority, destination string, port int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "VirtualService",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]interface{}{
+			"http": []interface{}{map[string]interface{}{
+				"rewrite": map[string]interface{}{"authority": authority},
+				"route": []interface{}{map[string]interface{}{
+					"destination": map[string]interface{}{
+						"host": destination,
+						"port": map[string]interface{}{"number": port},
+					},
+				}},
+			}},
+		},
+	}}
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// lastAppliedAnnotation is the annotation of kubectl apply, which is not exported because it
+// records the objects as they were applied to the source cluster.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Bundle is a portable snapshot of the Channels and Subscriptions of a cluster. It is serialized as
+// a v1 List, which kubectl apply also accepts.
+type Bundle struct {
+	Channels      []v1alpha1.Channel
+	Subscriptions []v1alpha1.Subscription
+
+	// Provisioners are the names of the ClusterChannelProvisioners of the Channels. They are not
+	// part of the bundle, but must be installed in the cluster it is imported into.
+	Provisioners []string
+
+	// Skipped are the namespace/name of the Channels and Subscriptions controlled by another object,
+	// such as a Broker or a Sequence, which recreates them. They are not part of the bundle.
+	Skipped []string
+}
+
+// Export snapshots the Channels and Subscriptions of namespace, or of all namespaces if it is
+// empty. Only their metadata and spec are kept: the status, and the subscribers of the Channels,
+// are rebuilt by the controllers once the bundle is imported.
+func (r *Resolver) Export(namespace string) (*Bundle, error) {
+	b := &Bundle{}
+	provisioners := map[string]bool{}
+	channels, err := r.Eventing.EventingV1alpha1().Channels(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range channels.Items {
+		if metav1.GetControllerOf(&c) != nil {
+			b.Skipped = append(b.Skipped, "Channel "+c.Namespace+"/"+c.Name)
+			continue
+		}
+		exported := v1alpha1.Channel{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Channel"},
+			ObjectMeta: exportedMeta(c.ObjectMeta),
+			Spec: v1alpha1.ChannelSpec{
+				Provisioner: c.Spec.Provisioner,
+				Arguments:   c.Spec.Arguments,
+			},
+		}
+		if p := c.Spec.Provisioner; p != nil && !provisioners[p.Name] {
+			provisioners[p.Name] = true
+			b.Provisioners = append(b.Provisioners, p.Name)
+		}
+		b.Channels = append(b.Channels, exported)
+	}
+	subscriptions, err := r.Eventing.EventingV1alpha1().Subscriptions(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range subscriptions.Items {
+		if metav1.GetControllerOf(&s) != nil {
+			b.Skipped = append(b.Skipped, "Subscription "+s.Namespace+"/"+s.Name)
+			continue
+		}
+		exported := v1alpha1.Subscription{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Subscription"},
+			ObjectMeta: exportedMeta(s.ObjectMeta),
+			Spec:       s.Spec,
+		}
+		exported.Spec.Generation = 0
+		b.Subscriptions = append(b.Subscriptions, exported)
+	}
+	sort.Strings(b.Provisioners)
+	return b, nil
+}
+
+// exportedMeta returns the portable part of meta: the fields set by the API server and the
+// controllers of the source cluster are dropped.
+func exportedMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	exported := metav1.ObjectMeta{
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Labels:    meta.Labels,
+	}
+	for k, v := range meta.Annotations {
+		if k == lastAppliedAnnotation {
+			continue
+		}
+		if exported.Annotations == nil {
+			exported.Annotations = map[string]string{}
+		}
+		exported.Annotations[k] = v
+	}
+	return exported
+}
+
+// bundleList is the serialized form of a Bundle.
+type bundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	Items           []json.RawMessage `json:"items"`
+}
+
+// MarshalBundle serializes b as a YAML v1 List of its Channels followed by its Subscriptions.
+func MarshalBundle(b *Bundle) ([]byte, error) {
+	list := bundleList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"}}
+	var objects []interface{}
+	for i := range b.Channels {
+		objects = append(objects, &b.Channels[i])
+	}
+	for i := range b.Subscriptions {
+		objects = append(objects, &b.Subscriptions[i])
+	}
+	for _, o := range objects {
+		item, err := json.Marshal(o)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+	return yaml.Marshal(list)
+}
+
+// UnmarshalBundle parses a bundle serialized by MarshalBundle. The ClusterChannelProvisioners of the
+// bundle are those of its Channels.
+func UnmarshalBundle(data []byte) (*Bundle, error) {
+	var list bundleList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %v", err)
+	}
+	b := &Bundle{}
+	provisioners := map[string]bool{}
+	for i, item := range list.Items {
+		var meta metav1.TypeMeta
+		if err := json.Unmarshal(item, &meta); err != nil {
+			return nil, fmt.Errorf("invalid item %d of the bundle: %v", i, err)
+		}
+		if meta.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+			return nil, fmt.Errorf("unsupported apiVersion %q of item %d of the bundle", meta.APIVersion, i)
+		}
+		switch meta.Kind {
+		case "Channel":
+			var c v1alpha1.Channel
+			if err := json.Unmarshal(item, &c); err != nil {
+				return nil, fmt.Errorf("invalid Channel %d of the bundle: %v", i, err)
+			}
+			if p := c.Spec.Provisioner; p != nil && !provisioners[p.Name] {
+				provisioners[p.Name] = true
+				b.Provisioners = append(b.Provisioners, p.Name)
+			}
+			b.Channels = append(b.Channels, c)
+		case "Subscription":
+			var s v1alpha1.Subscription
+			if err := json.Unmarshal(item, &s); err != nil {
+				return nil, fmt.Errorf("invalid Subscription %d of the bundle: %v", i, err)
+			}
+			b.Subscriptions = append(b.Subscriptions, s)
+		default:
+			return nil, fmt.Errorf("unsupported kind %q of item %d of the bundle", meta.Kind, i)
+		}
+	}
+	sort.Strings(b.Provisioners)
+	return b, nil
+}
+
+// ImportResult lists the namespace/name of the objects of a bundle by what Import did with them.
+type ImportResult struct {
+	Created []string
+	Updated []string
+	// Unchanged are the objects that already existed and were not overwritten.
+	Unchanged []string
+}
+
+// Import creates the Channels of b, then its Subscriptions. Existing objects keep their spec,
+// unless overwrite is set. Nothing is created if a ClusterChannelProvisioner of the bundle is not
+// installed.
+func (r *Resolver) Import(b *Bundle, overwrite bool) (*ImportResult, error) {
+	for _, p := range b.Provisioners {
+		if _, err := r.Eventing.EventingV1alpha1().ClusterChannelProvisioners().Get(p, metav1.GetOptions{}); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("the ClusterChannelProvisioner %q of the bundle is not installed", p)
+			}
+			return nil, err
+		}
+	}
+
+	result := &ImportResult{}
+	channels := r.Eventing.EventingV1alpha1().Channels
+	for i := range b.Channels {
+		c := b.Channels[i].DeepCopy()
+		key := "Channel " + c.Namespace + "/" + c.Name
+		_, err := channels(c.Namespace).Create(c)
+		switch {
+		case err == nil:
+			result.Created = append(result.Created, key)
+		case !errors.IsAlreadyExists(err):
+			return result, fmt.Errorf("failed to create %s: %v", key, err)
+		case !overwrite:
+			result.Unchanged = append(result.Unchanged, key)
+		default:
+			existing, err := channels(c.Namespace).Get(c.Name, metav1.GetOptions{})
+			if err != nil {
+				return result, err
+			}
+			existing.Labels, existing.Annotations = c.Labels, c.Annotations
+			existing.Spec.Provisioner, existing.Spec.Arguments = c.Spec.Provisioner, c.Spec.Arguments
+			if _, err := channels(c.Namespace).Update(existing); err != nil {
+				return result, fmt.Errorf("failed to update %s: %v", key, err)
+			}
+			result.Updated = append(result.Updated, key)
+		}
+	}
+
+	subscriptions := r.Eventing.EventingV1alpha1().Subscriptions
+	for i := range b.Subscriptions {
+		s := b.Subscriptions[i].DeepCopy()
+		key := "Subscription " + s.Namespace + "/" + s.Name
+		_, err := subscriptions(s.Namespace).Create(s)
+		switch {
+		case err == nil:
+			result.Created = append(result.Created, key)
+		case !errors.IsAlreadyExists(err):
+			return result, fmt.Errorf("failed to create %s: %v", key, err)
+		case !overwrite:
+			result.Unchanged = append(result.Unchanged, key)
+		default:
+			existing, err := subscriptions(s.Namespace).Get(s.Name, metav1.GetOptions{})
+			if err != nil {
+				return result, err
+			}
+			existing.Labels, existing.Annotations = s.Labels, s.Annotations
+			generation := existing.Spec.Generation
+			existing.Spec = s.Spec
+			existing.Spec.Generation = generation
+			if _, err := subscriptions(s.Namespace).Update(existing); err != nil {
+				return result, fmt.Errorf("failed to update %s: %v", key, err)
+			}
+			result.Updated = append(result.Updated, key)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestExportImport(t *testing.T) {
+	c := &v1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNS,
+			Name:            "foo",
+			UID:             "foo-uid",
+			ResourceVersion: "7",
+			Labels:          map[string]string{"team": "a"},
+			Annotations: map[string]string{
+				"eventing.knative.dev/partitionKey": "subject",
+				lastAppliedAnnotation:               "{}",
+			},
+		},
+		Spec: v1alpha1.ChannelSpec{
+			Provisioner: &corev1.ObjectReference{Name: "kafka"},
+			Arguments:   &runtime.RawExtension{Raw: []byte(`{"partitions":3}`)},
+		},
+	}
+	c.Status.SetAddress("foo-channel.test-namespace.svc.cluster.local")
+	owned := &v1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNS,
+			Name:            "broker-channel",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(c, v1alpha1.SchemeGroupVersion.WithKind("Broker"))},
+		},
+		Spec: v1alpha1.ChannelSpec{Provisioner: &corev1.ObjectReference{Name: "in-memory-channel"}},
+	}
+	dnsName := "http://subscriber.test-namespace.svc.cluster.local/"
+	s := &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "sub", UID: "sub-uid"},
+		Spec: v1alpha1.SubscriptionSpec{
+			Generation: 2,
+			Channel:    corev1.ObjectReference{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Channel", Name: "foo"},
+			Subscriber: &v1alpha1.SubscriberSpec{DNSName: &dnsName},
+		},
+	}
+	source := &Resolver{Eventing: fake.NewSimpleClientset(c, owned, s)}
+
+	bundle, err := source.Export(testNS)
+	if err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+	if diff := cmp.Diff([]string{"kafka"}, bundle.Provisioners); diff != "" {
+		t.Errorf("Unexpected provisioners (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff([]string{"Channel test-namespace/broker-channel"}, bundle.Skipped); diff != "" {
+		t.Errorf("Unexpected skipped objects (-want, +got) = %v", diff)
+	}
+	data, err := MarshalBundle(bundle)
+	if err != nil {
+		t.Fatalf("MarshalBundle() = %v", err)
+	}
+	bundle, err = UnmarshalBundle(data)
+	if err != nil {
+		t.Fatalf("UnmarshalBundle() = %v", err)
+	}
+
+	// The bundle cannot be imported before its provisioners are installed.
+	target := &Resolver{Eventing: fake.NewSimpleClientset()}
+	if _, err := target.Import(bundle, false); err == nil {
+		t.Fatalf("Expected an error importing a bundle with a missing provisioner")
+	}
+
+	ccp := &v1alpha1.ClusterChannelProvisioner{ObjectMeta: metav1.ObjectMeta{Name: "kafka"}}
+	existing := &v1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "sub"}}
+	target = &Resolver{Eventing: fake.NewSimpleClientset(ccp, existing)}
+	result, err := target.Import(bundle, false)
+	if err != nil {
+		t.Fatalf("Import() = %v", err)
+	}
+	want := &ImportResult{
+		Created:   []string{"Channel test-namespace/foo"},
+		Unchanged: []string{"Subscription test-namespace/sub"},
+	}
+	if diff := cmp.Diff(want, result); diff != "" {
+		t.Errorf("Unexpected result (-want, +got) = %v", diff)
+	}
+	imported, err := target.Eventing.EventingV1alpha1().Channels(testNS).Get("foo", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error getting the imported Channel: %v", err)
+	}
+	wantChannel := &v1alpha1.Channel{
+		TypeMeta: metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Channel"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   testNS,
+			Name:        "foo",
+			Labels:      map[string]string{"team": "a"},
+			Annotations: map[string]string{"eventing.knative.dev/partitionKey": "subject"},
+		},
+		Spec: c.Spec,
+	}
+	if diff := cmp.Diff(wantChannel, imported); diff != "" {
+		t.Errorf("Unexpected Channel (-want, +got) = %v", diff)
+	}
+
+	result, err = target.Import(bundle, true)
+	if err != nil {
+		t.Fatalf("Import() = %v", err)
+	}
+	want = &ImportResult{
+		Updated: []string{"Channel test-namespace/foo", "Subscription test-namespace/sub"},
+	}
+	if diff := cmp.Diff(want, result); diff != "" {
+		t.Errorf("Unexpected result (-want, +got) = %v", diff)
+	}
+	sub, err := target.Eventing.EventingV1alpha1().Subscriptions(testNS).Get("sub", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error getting the imported Subscription: %v", err)
+	}
+	wantSpec := s.Spec
+	wantSpec.Generation = 0
+	if diff := cmp.Diff(wantSpec, sub.Spec); diff != "" {
+		t.Errorf("Unexpected Subscription spec (-want, +got) = %v", diff)
+	}
+}
+
+func TestUnmarshalBundleInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"not yaml":            "{",
+		"unsupported kind":    "apiVersion: v1\nkind: List\nitems:\n- apiVersion: eventing.knative.dev/v1alpha1\n  kind: Broker\n",
+		"unsupported version": "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: Channel\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := UnmarshalBundle([]byte(data)); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
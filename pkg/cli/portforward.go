@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// portForwardTimeout is how long PortForward waits for kubectl to listen.
+const portForwardTimeout = 30 * time.Second
+
+// forwardingFrom matches the line kubectl port-forward prints once it listens, and captures the
+// local address.
+var forwardingFrom = regexp.MustCompile(`^Forwarding from (127\.0\.0\.1:\d+) ->`)
+
+// PortForward forwards a local port to the Service of t by running kubectl port-forward, with the
+// extra kubectl flags args, such as --kubeconfig. It returns the local address and a function
+// stopping the forward.
+func PortForward(kubectl string, args []string, t *Target) (string, func(), error) {
+	args = append(append([]string{}, args...),
+		"port-forward", "--namespace", t.Namespace, "service/"+t.Service, ":"+strconv.FormatInt(t.Port, 10))
+	cmd := exec.Command(kubectl, args...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", nil, fmt.Errorf("unable to run %s: %v", kubectl, err)
+	}
+	stop := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+
+	addr := make(chan string, 1)
+	go func() {
+		addr <- localAddress(stdout)
+		// Keep reading, so that kubectl does not block writing.
+		io.Copy(ioutil.Discard, stdout)
+	}()
+	select {
+	case a := <-addr:
+		if a == "" {
+			stop()
+			return "", nil, fmt.Errorf("kubectl port-forward to service/%s in %s exited", t.Service, t.Namespace)
+		}
+		return a, stop, nil
+	case <-time.After(portForwardTimeout):
+		stop()
+		return "", nil, fmt.Errorf("timed out waiting for kubectl port-forward to service/%s in %s", t.Service, t.Namespace)
+	}
+}
+
+// localAddress returns the local address in the output r of kubectl port-forward, or the empty
+// string if it ends before printing one.
+func localAddress(r io.Reader) string {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if m := forwardingFrom.FindStringSubmatch(s.Text()); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
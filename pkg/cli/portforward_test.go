@@ -0,0 +1,44 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocalAddress(t *testing.T) {
+	testCases := map[string]struct {
+		output string
+		want   string
+	}{
+		"listening": {
+			output: "Forwarding from 127.0.0.1:35421 -> 8080\nForwarding from [::1]:35421 -> 8080\n",
+			want:   "127.0.0.1:35421",
+		},
+		"exited": {
+			output: "error: services \"foo\" not found\n",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := localAddress(strings.NewReader(tc.output)); got != tc.want {
+				t.Errorf("Unexpected address. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
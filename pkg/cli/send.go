@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/knative/pkg/cloudevents"
+)
+
+// maxResponseBody is the largest body of a response that Send returns. Longer bodies are
+// truncated.
+const maxResponseBody = 64 << 10
+
+// Event is a test event.
+type Event struct {
+	ID     string
+	Type   string
+	Source string
+	// Data is the data of the event, a JSON document.
+	Data string
+}
+
+// Send sends event to url in the binary content mode, with host as the Host header if it is not
+// empty. It returns the response, whose body is read and closed, or an error if it could not be
+// sent.
+func Send(client *http.Client, url, host string, event Event) (*http.Response, []byte, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+		return nil, nil, fmt.Errorf("the data of the event must be JSON: %v", err)
+	}
+	req, err := cloudevents.Binary.NewRequest(url, data, eventContext(event))
+	if err != nil {
+		return nil, nil, err
+	}
+	if host != "" {
+		req.Host = host
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	return res, body, err
+}
+
+// eventContext returns the context of event, with a generated ID and the hostname as the source if
+// they are not set.
+func eventContext(event Event) cloudevents.EventContext {
+	ctx := cloudevents.EventContext{
+		CloudEventsVersion: cloudevents.CloudEventsVersion,
+		EventID:            event.ID,
+		EventType:          event.Type,
+		Source:             event.Source,
+		EventTime:          time.Now().UTC(),
+	}
+	if ctx.EventID == "" {
+		ctx.EventID = uuid.New().String()
+	}
+	if ctx.Source == "" {
+		var err error
+		if ctx.Source, err = os.Hostname(); err != nil {
+			ctx.Source = "localhost"
+		}
+	}
+	return ctx
+}
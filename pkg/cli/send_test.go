@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		got, body = r, string(b)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("accepted"))
+	}))
+	defer server.Close()
+
+	event := Event{ID: "1234", Type: "dev.knative.test", Source: "/test", Data: `{"hello": "world"}`}
+	res, resBody, err := Send(http.DefaultClient, server.URL, "foo.test-namespace.channels.cluster.local", event)
+	if err != nil {
+		t.Fatalf("Unexpected error from Send: %v", err)
+	}
+	if res.StatusCode != http.StatusAccepted || string(resBody) != "accepted" {
+		t.Errorf("Unexpected response %s %q", res.Status, resBody)
+	}
+	if got.Host != "foo.test-namespace.channels.cluster.local" {
+		t.Errorf("Unexpected Host %q", got.Host)
+	}
+	for h, want := range map[string]string{
+		"Ce-Eventid":   "1234",
+		"Ce-Eventtype": "dev.knative.test",
+		"Ce-Source":    "/test",
+		"Content-Type": "application/json",
+	} {
+		if v := got.Header.Get(h); v != want {
+			t.Errorf("Unexpected %s header. Expected %q. Actual %q", h, want, v)
+		}
+	}
+	if !strings.Contains(body, `"hello":"world"`) {
+		t.Errorf("Unexpected body %q", body)
+	}
+}
+
+func TestSendInvalidData(t *testing.T) {
+	if _, _, err := Send(http.DefaultClient, "http://localhost/", "", Event{Data: "not json"}); err == nil {
+		t.Errorf("Expected an error sending data that is not JSON")
+	}
+}
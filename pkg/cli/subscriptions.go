@@ -0,0 +1,251 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The metrics of the dispatchers read by InspectChannel.
+const (
+	deliveredMetric = "channel_dispatcher_events_delivered_total"
+	failuresMetric  = "channel_dispatcher_delivery_failures_total"
+	retriesMetric   = "channel_dispatcher_delivery_retries_total"
+	backlogMetric   = "channel_dispatcher_backlog_events"
+)
+
+var endpointsResource = schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}
+
+// ChannelReport describes a Channel, its subscribers and the backlog of its dispatchers.
+type ChannelReport struct {
+	Namespace   string
+	Name        string
+	Provisioner string
+	Hostname    string
+	// Ready is the status of the Ready condition of the Channel.
+	Ready corev1.ConditionStatus
+
+	Subscribers []SubscriberReport
+
+	// Backlog is the number of events waiting to be delivered to the subscribers, for the
+	// provisioners that report it. It is nil if they do not, or their metrics were not read.
+	Backlog *int64
+
+	// MetricsError is why the metrics of the dispatchers could not be read, if they were not.
+	MetricsError error
+}
+
+// SubscriberReport describes a subscriber of a Channel.
+type SubscriberReport struct {
+	eventingduck.ChannelSubscriberSpec
+
+	// Ready and Reason are the status and reason of the Ready condition of the Subscription of the
+	// subscriber. Ready is empty if the Subscription is unknown.
+	Ready  corev1.ConditionStatus
+	Reason string
+
+	// Deliveries counts the deliveries of the dispatchers to the subscriber. It is nil if their
+	// metrics were not read, or are not labeled by subscription.
+	Deliveries *DeliveryCounts
+}
+
+// DeliveryCounts counts the deliveries of events to a subscriber since the dispatchers started.
+type DeliveryCounts struct {
+	Delivered float64
+	Failures  float64
+	Retries   float64
+}
+
+// InspectChannel reports the subscribers of the Channel name in namespace, from its spec and the
+// status of their Subscriptions. If r.Proxy is set, it also reads the delivery metrics and backlog
+// of the Channel from the dispatchers behind its address, on metricsPort.
+func (r *Resolver) InspectChannel(namespace, name string, metricsPort int) (*ChannelReport, error) {
+	c, err := r.Eventing.EventingV1alpha1().Channels(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	report := &ChannelReport{
+		Namespace: namespace,
+		Name:      name,
+		Hostname:  c.Status.Address.Hostname,
+		Ready:     corev1.ConditionUnknown,
+	}
+	if c.Spec.Provisioner != nil {
+		report.Provisioner = c.Spec.Provisioner.Name
+	}
+	if cond := c.Status.GetCondition(v1alpha1.ChannelConditionReady); cond != nil {
+		report.Ready = cond.Status
+	}
+	if c.Spec.Subscribable != nil {
+		for _, sub := range c.Spec.Subscribable.Subscribers {
+			s, err := r.inspectSubscriber(sub)
+			if err != nil {
+				return nil, err
+			}
+			report.Subscribers = append(report.Subscribers, s)
+		}
+	}
+	if r.Proxy != nil && report.Hostname != "" {
+		report.MetricsError = r.readMetrics(report, metricsPort)
+	}
+	return report, nil
+}
+
+// inspectSubscriber reports the subscriber sub, with the status of its Subscription.
+func (r *Resolver) inspectSubscriber(sub eventingduck.ChannelSubscriberSpec) (SubscriberReport, error) {
+	s := SubscriberReport{ChannelSubscriberSpec: sub}
+	if sub.Ref == nil {
+		return s, nil
+	}
+	subscription, err := r.Eventing.EventingV1alpha1().Subscriptions(sub.Ref.Namespace).Get(sub.Ref.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		s.Reason = "SubscriptionNotFound"
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	s.Ready = corev1.ConditionUnknown
+	if cond := subscription.Status.GetCondition(v1alpha1.SubscriptionConditionReady); cond != nil {
+		s.Ready, s.Reason = cond.Status, cond.Reason
+	}
+	return s, nil
+}
+
+// readMetrics adds the delivery metrics and backlog of the Channel of report, read from every pod
+// behind the Service of its address, to report.
+func (r *Resolver) readMetrics(report *ChannelReport, port int) error {
+	t, err := r.Target(report.Hostname)
+	if err != nil {
+		return err
+	}
+	pods, err := r.pods(t)
+	if err != nil {
+		return err
+	}
+	channelRef := provisioners.ChannelReference{Namespace: report.Namespace, Name: report.Name}
+	channel := channelRef.String()
+	var families []map[string]*dto.MetricFamily
+	for _, pod := range pods {
+		raw, err := r.Proxy(t.Namespace, pod, port, "metrics")
+		if err != nil {
+			return fmt.Errorf("unable to read the metrics of pod %s/%s: %v", t.Namespace, pod, err)
+		}
+		var parser expfmt.TextParser
+		mf, err := parser.TextToMetricFamilies(bytes.NewReader(raw))
+		if err != nil {
+			return fmt.Errorf("unable to parse the metrics of pod %s/%s: %v", t.Namespace, pod, err)
+		}
+		families = append(families, mf)
+	}
+	if len(families) == 0 {
+		return fmt.Errorf("no ready pod behind service %s/%s", t.Namespace, t.Service)
+	}
+
+	// The dispatchers each deliver the events of a subset of the Channels, so the series of the
+	// pods are summed.
+	counts := make(map[string]*DeliveryCounts)
+	count := func(subscription string) *DeliveryCounts {
+		if counts[subscription] == nil {
+			counts[subscription] = &DeliveryCounts{}
+		}
+		return counts[subscription]
+	}
+	for _, mf := range families {
+		for _, m := range channelSeries(mf[deliveredMetric], channel) {
+			count(label(m, "subscription")).Delivered += m.GetCounter().GetValue()
+		}
+		for _, m := range channelSeries(mf[failuresMetric], channel) {
+			count(label(m, "subscription")).Failures += m.GetCounter().GetValue()
+		}
+		for _, m := range channelSeries(mf[retriesMetric], channel) {
+			count(label(m, "subscription")).Retries += m.GetCounter().GetValue()
+		}
+		for _, m := range channelSeries(mf[backlogMetric], channel) {
+			backlog := int64(m.GetGauge().GetValue())
+			if report.Backlog != nil {
+				backlog += *report.Backlog
+			}
+			report.Backlog = &backlog
+		}
+	}
+	for i := range report.Subscribers {
+		if ref := report.Subscribers[i].Ref; ref != nil {
+			report.Subscribers[i].Deliveries = counts[ref.Namespace+"/"+ref.Name]
+		}
+	}
+	return nil
+}
+
+// pods returns the names of the ready pods behind the Service of t, from its Endpoints.
+func (r *Resolver) pods(t *Target) ([]string, error) {
+	endpoints, err := r.Dynamic.Resource(endpointsResource).Namespace(t.Namespace).Get(t.Service, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	subsets, _, _ := unstructured.NestedSlice(endpoints.Object, "subsets")
+	var pods []string
+	for _, subset := range subsets {
+		addresses, _, _ := unstructured.NestedSlice(subset.(map[string]interface{}), "addresses")
+		for _, address := range addresses {
+			ref, _, _ := unstructured.NestedMap(address.(map[string]interface{}), "targetRef")
+			if kind, _, _ := unstructured.NestedString(ref, "kind"); kind != "Pod" {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(ref, "name"); name != "" {
+				pods = append(pods, name)
+			}
+		}
+	}
+	return pods, nil
+}
+
+// channelSeries returns the series of the metric family mf labeled with channel.
+func channelSeries(mf *dto.MetricFamily, channel string) []*dto.Metric {
+	if mf == nil {
+		return nil
+	}
+	var series []*dto.Metric
+	for _, m := range mf.Metric {
+		if label(m, "channel") == channel {
+			series = append(series, m)
+		}
+	}
+	return series
+}
+
+// label returns the value of the label name of m, or the empty string if it has none.
+func label(m *dto.Metric, name string) string {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/client/clientset/versioned/fake"
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const testMetrics = `# TYPE channel_dispatcher_events_delivered_total counter
+channel_dispatcher_events_delivered_total{namespace="test-namespace",channel="test-namespace/foo",subscription="test-namespace/ready"} 3
+channel_dispatcher_events_delivered_total{namespace="test-namespace",channel="test-namespace/bar",subscription="test-namespace/other"} 7
+# TYPE channel_dispatcher_delivery_failures_total counter
+channel_dispatcher_delivery_failures_total{namespace="test-namespace",channel="test-namespace/foo",subscription="test-namespace/unreachable",code="503"} 2
+# TYPE channel_dispatcher_delivery_retries_total counter
+channel_dispatcher_delivery_retries_total{namespace="test-namespace",channel="test-namespace/foo",subscription="test-namespace/unreachable"} 4
+# TYPE channel_dispatcher_backlog_events gauge
+channel_dispatcher_backlog_events{namespace="test-namespace",channel="test-namespace/foo"} 5
+channel_dispatcher_backlog_events{namespace="test-namespace",channel="test-namespace/bar"} 11
+`
+
+func TestInspectChannel(t *testing.T) {
+	c := &v1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "foo"},
+		Spec: v1alpha1.ChannelSpec{
+			Provisioner: &corev1.ObjectReference{Name: "in-memory-channel"},
+			Subscribable: &eventingduck.Subscribable{
+				Subscribers: []eventingduck.ChannelSubscriberSpec{
+					subscriber("ready", "http://ready.test-namespace.svc.cluster.local/"),
+					subscriber("unreachable", "http://unreachable.test-namespace.svc.cluster.local/"),
+					subscriber("deleted", "http://deleted.test-namespace.svc.cluster.local/"),
+				},
+			},
+		},
+	}
+	c.Status.InitializeConditions()
+	c.Status.MarkProvisioned()
+	c.Status.SetAddress("foo-channel.test-namespace.svc.cluster.local")
+
+	ready := &v1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "ready"}}
+	ready.Status.MarkReferencesResolved()
+	ready.Status.MarkChannelReady()
+	ready.Status.MarkSubscriberReachable()
+	unreachable := &v1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "unreachable"}}
+	unreachable.Status.Conditions = duckv1alpha1.Conditions{{
+		Type:   v1alpha1.SubscriptionConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: "Unreachable",
+	}}
+
+	objects := []runtime.Object{
+		service(testNS, "foo-channel", nil),
+		virtualService(testNS, "foo-channel", "foo.test-namespace.channels.cluster.local", "in-memory-channel-dispatcher.knative-eventing.svc.cluster.local", 80),
+		service("knative-eventing", "in-memory-channel-dispatcher", map[string]interface{}{"selector": map[string]interface{}{"app": "dispatcher"}}),
+		endpoints("knative-eventing", "in-memory-channel-dispatcher", "dispatcher-1", "dispatcher-2"),
+	}
+
+	testCases := map[string]struct {
+		proxy func(namespace, pod string, port int, path string) ([]byte, error)
+		want  *ChannelReport
+	}{
+		"without metrics": {
+			want: &ChannelReport{
+				Namespace:   testNS,
+				Name:        "foo",
+				Provisioner: "in-memory-channel",
+				Hostname:    "foo-channel.test-namespace.svc.cluster.local",
+				Ready:       corev1.ConditionTrue,
+				Subscribers: []SubscriberReport{{
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[0],
+					Ready:                 corev1.ConditionTrue,
+				}, {
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[1],
+					Ready:                 corev1.ConditionFalse,
+					Reason:                "Unreachable",
+				}, {
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[2],
+					Reason:                "SubscriptionNotFound",
+				}},
+			},
+		},
+		"with metrics": {
+			proxy: func(namespace, pod string, port int, path string) ([]byte, error) {
+				if namespace != "knative-eventing" || (pod != "dispatcher-1" && pod != "dispatcher-2") || port != 9090 || path != "metrics" {
+					t.Errorf("Unexpected proxy to %s/%s:%d/%s", namespace, pod, port, path)
+				}
+				return []byte(testMetrics), nil
+			},
+			want: &ChannelReport{
+				Namespace:   testNS,
+				Name:        "foo",
+				Provisioner: "in-memory-channel",
+				Hostname:    "foo-channel.test-namespace.svc.cluster.local",
+				Ready:       corev1.ConditionTrue,
+				Subscribers: []SubscriberReport{{
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[0],
+					Ready:                 corev1.ConditionTrue,
+					Deliveries:            &DeliveryCounts{Delivered: 6},
+				}, {
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[1],
+					Ready:                 corev1.ConditionFalse,
+					Reason:                "Unreachable",
+					Deliveries:            &DeliveryCounts{Failures: 4, Retries: 8},
+				}, {
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[2],
+					Reason:                "SubscriptionNotFound",
+				}},
+				Backlog: int64Ptr(10),
+			},
+		},
+		"metrics unavailable": {
+			proxy: func(namespace, pod string, port int, path string) ([]byte, error) {
+				return nil, errors.New("connection refused")
+			},
+			want: &ChannelReport{
+				Namespace:   testNS,
+				Name:        "foo",
+				Provisioner: "in-memory-channel",
+				Hostname:    "foo-channel.test-namespace.svc.cluster.local",
+				Ready:       corev1.ConditionTrue,
+				Subscribers: []SubscriberReport{{
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[0],
+					Ready:                 corev1.ConditionTrue,
+				}, {
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[1],
+					Ready:                 corev1.ConditionFalse,
+					Reason:                "Unreachable",
+				}, {
+					ChannelSubscriberSpec: c.Spec.Subscribable.Subscribers[2],
+					Reason:                "SubscriptionNotFound",
+				}},
+				MetricsError: errors.New("unable to read the metrics of pod knative-eventing/dispatcher-1: connection refused"),
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			r := &Resolver{
+				Eventing: fake.NewSimpleClientset(c, ready, unreachable),
+				Dynamic:  dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...),
+				Proxy:    tc.proxy,
+			}
+			got, err := r.InspectChannel(testNS, "foo", 9090)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(ChannelReport{}, "MetricsError")); diff != "" {
+				t.Errorf("Unexpected report (-want +got): %s", diff)
+			}
+			if (tc.want.MetricsError == nil) != (got.MetricsError == nil) {
+				t.Fatalf("Unexpected metrics error. Expected %v. Actual %v", tc.want.MetricsError, got.MetricsError)
+			}
+			if tc.want.MetricsError != nil && tc.want.MetricsError.Error() != got.MetricsError.Error() {
+				t.Errorf("Unexpected metrics error. Expected %q. Actual %q", tc.want.MetricsError, got.MetricsError)
+			}
+		})
+	}
+}
+
+func TestInspectChannelNotFound(t *testing.T) {
+	r := &Resolver{Eventing: fake.NewSimpleClientset()}
+	if _, err := r.InspectChannel(testNS, "missing", 9090); err == nil {
+		t.Errorf("Expected an error inspecting a missing Channel")
+	}
+}
+
+func subscriber(name, uri string) eventingduck.ChannelSubscriberSpec {
+	return eventingduck.ChannelSubscriberSpec{
+		Ref:           &corev1.ObjectReference{Namespace: testNS, Name: name},
+		SubscriberURI: uri,
+	}
+}
+
+func endpoints(namespace, name string, pods ...string) *unstructured.Unstructured {
+	var addresses []interface{}
+	for i, pod := range pods {
+		addresses = append(addresses, map[string]interface{}{
+			"ip":        fmt.Sprintf("10.0.0.%d", i+1),
+			"targetRef": map[string]interface{}{"kind": "Pod", "namespace": namespace, "name": pod},
+		})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Endpoints",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"subsets": []interface{}{map[string]interface{}{"addresses": addresses}},
+	}}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -22,6 +22,10 @@ import (
 	clientset "github.com/knative/eventing/pkg/client/clientset/versioned"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/eventing/v1alpha1"
 	fakeeventingv1alpha1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/eventing/v1alpha1/fake"
+	eventingv1beta1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/eventing/v1beta1"
+	fakeeventingv1beta1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/eventing/v1beta1/fake"
+	sourcesv1alpha1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/sources/v1alpha1"
+	fakesourcesv1alpha1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/sources/v1alpha1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -76,7 +80,22 @@ func (c *Clientset) EventingV1alpha1() eventingv1alpha1.EventingV1alpha1Interfac
 	return &fakeeventingv1alpha1.FakeEventingV1alpha1{Fake: &c.Fake}
 }
 
-// Eventing retrieves the EventingV1alpha1Client
-func (c *Clientset) Eventing() eventingv1alpha1.EventingV1alpha1Interface {
-	return &fakeeventingv1alpha1.FakeEventingV1alpha1{Fake: &c.Fake}
+// EventingV1beta1 retrieves the EventingV1beta1Client
+func (c *Clientset) EventingV1beta1() eventingv1beta1.EventingV1beta1Interface {
+	return &fakeeventingv1beta1.FakeEventingV1beta1{Fake: &c.Fake}
+}
+
+// Eventing retrieves the EventingV1beta1Client
+func (c *Clientset) Eventing() eventingv1beta1.EventingV1beta1Interface {
+	return &fakeeventingv1beta1.FakeEventingV1beta1{Fake: &c.Fake}
+}
+
+// SourcesV1alpha1 retrieves the SourcesV1alpha1Client
+func (c *Clientset) SourcesV1alpha1() sourcesv1alpha1.SourcesV1alpha1Interface {
+	return &fakesourcesv1alpha1.FakeSourcesV1alpha1{Fake: &c.Fake}
+}
+
+// Sources retrieves the SourcesV1alpha1Client
+func (c *Clientset) Sources() sourcesv1alpha1.SourcesV1alpha1Interface {
+	return &fakesourcesv1alpha1.FakeSourcesV1alpha1{Fake: &c.Fake}
 }
@@ -20,6 +20,8 @@ package scheme
 
 import (
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingv1beta1 "github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	sourcesv1alpha1 "github.com/knative/eventing/pkg/apis/sources/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -51,4 +53,6 @@ func init() {
 // correctly.
 func AddToScheme(scheme *runtime.Scheme) {
 	eventingv1alpha1.AddToScheme(scheme)
+	eventingv1beta1.AddToScheme(scheme)
+	sourcesv1alpha1.AddToScheme(scheme)
 }
@@ -0,0 +1,157 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	scheme "github.com/knative/eventing/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ChannelMigrationsGetter has a method to return a ChannelMigrationInterface.
+// A group's client should implement this interface.
+type ChannelMigrationsGetter interface {
+	ChannelMigrations(namespace string) ChannelMigrationInterface
+}
+
+// ChannelMigrationInterface has methods to work with ChannelMigration resources.
+type ChannelMigrationInterface interface {
+	Create(*v1alpha1.ChannelMigration) (*v1alpha1.ChannelMigration, error)
+	Update(*v1alpha1.ChannelMigration) (*v1alpha1.ChannelMigration, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.ChannelMigration, error)
+	List(opts v1.ListOptions) (*v1alpha1.ChannelMigrationList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ChannelMigration, err error)
+	ChannelMigrationExpansion
+}
+
+// channelMigrations implements ChannelMigrationInterface
+type channelMigrations struct {
+	client rest.Interface
+	ns     string
+}
+
+// newChannelMigrations returns a ChannelMigrations
+func newChannelMigrations(c *EventingV1alpha1Client, namespace string) *channelMigrations {
+	return &channelMigrations{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the channelMigration, and returns the corresponding channelMigration object, and an error if there is any.
+func (c *channelMigrations) Get(name string, options v1.GetOptions) (result *v1alpha1.ChannelMigration, err error) {
+	result = &v1alpha1.ChannelMigration{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ChannelMigrations that match those selectors.
+func (c *channelMigrations) List(opts v1.ListOptions) (result *v1alpha1.ChannelMigrationList, err error) {
+	result = &v1alpha1.ChannelMigrationList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested channelMigrations.
+func (c *channelMigrations) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a channelMigration and creates it.  Returns the server's representation of the channelMigration, and an error, if there is any.
+func (c *channelMigrations) Create(channelMigration *v1alpha1.ChannelMigration) (result *v1alpha1.ChannelMigration, err error) {
+	result = &v1alpha1.ChannelMigration{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		Body(channelMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a channelMigration and updates it. Returns the server's representation of the channelMigration, and an error, if there is any.
+func (c *channelMigrations) Update(channelMigration *v1alpha1.ChannelMigration) (result *v1alpha1.ChannelMigration, err error) {
+	result = &v1alpha1.ChannelMigration{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		Name(channelMigration.Name).
+		Body(channelMigration).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the channelMigration and deletes it. Returns an error if one occurs.
+func (c *channelMigrations) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *channelMigrations) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched channelMigration.
+func (c *channelMigrations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ChannelMigration, err error) {
+	result = &v1alpha1.ChannelMigration{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("channelmigrations").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -27,9 +27,15 @@ import (
 
 type EventingV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	BrokersGetter
 	ChannelsGetter
+	ChannelMigrationsGetter
 	ClusterChannelProvisionersGetter
+	EventTypesGetter
+	ParallelsGetter
+	SequencesGetter
 	SubscriptionsGetter
+	TriggersGetter
 }
 
 // EventingV1alpha1Client is used to interact with features provided by the eventing.knative.dev group.
@@ -37,18 +43,42 @@ type EventingV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *EventingV1alpha1Client) Brokers(namespace string) BrokerInterface {
+	return newBrokers(c, namespace)
+}
+
 func (c *EventingV1alpha1Client) Channels(namespace string) ChannelInterface {
 	return newChannels(c, namespace)
 }
 
+func (c *EventingV1alpha1Client) ChannelMigrations(namespace string) ChannelMigrationInterface {
+	return newChannelMigrations(c, namespace)
+}
+
 func (c *EventingV1alpha1Client) ClusterChannelProvisioners() ClusterChannelProvisionerInterface {
 	return newClusterChannelProvisioners(c)
 }
 
+func (c *EventingV1alpha1Client) EventTypes(namespace string) EventTypeInterface {
+	return newEventTypes(c, namespace)
+}
+
+func (c *EventingV1alpha1Client) Parallels(namespace string) ParallelInterface {
+	return newParallels(c, namespace)
+}
+
+func (c *EventingV1alpha1Client) Sequences(namespace string) SequenceInterface {
+	return newSequences(c, namespace)
+}
+
 func (c *EventingV1alpha1Client) Subscriptions(namespace string) SubscriptionInterface {
 	return newSubscriptions(c, namespace)
 }
 
+func (c *EventingV1alpha1Client) Triggers(namespace string) TriggerInterface {
+	return newTriggers(c, namespace)
+}
+
 // NewForConfig creates a new EventingV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*EventingV1alpha1Client, error) {
 	config := *c
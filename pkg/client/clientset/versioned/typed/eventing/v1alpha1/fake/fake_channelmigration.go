@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeChannelMigrations implements ChannelMigrationInterface
+type FakeChannelMigrations struct {
+	Fake *FakeEventingV1alpha1
+	ns   string
+}
+
+var channelmigrationsResource = schema.GroupVersionResource{Group: "eventing.knative.dev", Version: "v1alpha1", Resource: "channelmigrations"}
+
+var channelmigrationsKind = schema.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1alpha1", Kind: "ChannelMigration"}
+
+// Get takes name of the channelMigration, and returns the corresponding channelMigration object, and an error if there is any.
+func (c *FakeChannelMigrations) Get(name string, options v1.GetOptions) (result *v1alpha1.ChannelMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(channelmigrationsResource, c.ns, name), &v1alpha1.ChannelMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ChannelMigration), err
+}
+
+// List takes label and field selectors, and returns the list of ChannelMigrations that match those selectors.
+func (c *FakeChannelMigrations) List(opts v1.ListOptions) (result *v1alpha1.ChannelMigrationList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(channelmigrationsResource, channelmigrationsKind, c.ns, opts), &v1alpha1.ChannelMigrationList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ChannelMigrationList{ListMeta: obj.(*v1alpha1.ChannelMigrationList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ChannelMigrationList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested channelMigrations.
+func (c *FakeChannelMigrations) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(channelmigrationsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a channelMigration and creates it.  Returns the server's representation of the channelMigration, and an error, if there is any.
+func (c *FakeChannelMigrations) Create(channelMigration *v1alpha1.ChannelMigration) (result *v1alpha1.ChannelMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(channelmigrationsResource, c.ns, channelMigration), &v1alpha1.ChannelMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ChannelMigration), err
+}
+
+// Update takes the representation of a channelMigration and updates it. Returns the server's representation of the channelMigration, and an error, if there is any.
+func (c *FakeChannelMigrations) Update(channelMigration *v1alpha1.ChannelMigration) (result *v1alpha1.ChannelMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(channelmigrationsResource, c.ns, channelMigration), &v1alpha1.ChannelMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ChannelMigration), err
+}
+
+// Delete takes name of the channelMigration and deletes it. Returns an error if one occurs.
+func (c *FakeChannelMigrations) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(channelmigrationsResource, c.ns, name), &v1alpha1.ChannelMigration{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeChannelMigrations) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(channelmigrationsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ChannelMigrationList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched channelMigration.
+func (c *FakeChannelMigrations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ChannelMigration, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(channelmigrationsResource, c.ns, name, data, subresources...), &v1alpha1.ChannelMigration{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ChannelMigration), err
+}
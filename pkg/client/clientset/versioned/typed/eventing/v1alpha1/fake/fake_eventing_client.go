@@ -28,18 +28,42 @@ type FakeEventingV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeEventingV1alpha1) Brokers(namespace string) v1alpha1.BrokerInterface {
+	return &FakeBrokers{c, namespace}
+}
+
 func (c *FakeEventingV1alpha1) Channels(namespace string) v1alpha1.ChannelInterface {
 	return &FakeChannels{c, namespace}
 }
 
+func (c *FakeEventingV1alpha1) ChannelMigrations(namespace string) v1alpha1.ChannelMigrationInterface {
+	return &FakeChannelMigrations{c, namespace}
+}
+
 func (c *FakeEventingV1alpha1) ClusterChannelProvisioners() v1alpha1.ClusterChannelProvisionerInterface {
 	return &FakeClusterChannelProvisioners{c}
 }
 
+func (c *FakeEventingV1alpha1) EventTypes(namespace string) v1alpha1.EventTypeInterface {
+	return &FakeEventTypes{c, namespace}
+}
+
+func (c *FakeEventingV1alpha1) Parallels(namespace string) v1alpha1.ParallelInterface {
+	return &FakeParallels{c, namespace}
+}
+
+func (c *FakeEventingV1alpha1) Sequences(namespace string) v1alpha1.SequenceInterface {
+	return &FakeSequences{c, namespace}
+}
+
 func (c *FakeEventingV1alpha1) Subscriptions(namespace string) v1alpha1.SubscriptionInterface {
 	return &FakeSubscriptions{c, namespace}
 }
 
+func (c *FakeEventingV1alpha1) Triggers(namespace string) v1alpha1.TriggerInterface {
+	return &FakeTriggers{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeEventingV1alpha1) RESTClient() rest.Interface {
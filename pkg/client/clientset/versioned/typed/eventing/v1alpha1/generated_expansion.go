@@ -18,8 +18,20 @@ limitations under the License.
 
 package v1alpha1
 
+type BrokerExpansion interface{}
+
 type ChannelExpansion interface{}
 
+type ChannelMigrationExpansion interface{}
+
 type ClusterChannelProvisionerExpansion interface{}
 
+type EventTypeExpansion interface{}
+
+type ParallelExpansion interface{}
+
+type SequenceExpansion interface{}
+
 type SubscriptionExpansion interface{}
+
+type TriggerExpansion interface{}
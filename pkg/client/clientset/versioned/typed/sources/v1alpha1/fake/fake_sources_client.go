@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/knative/eventing/pkg/client/clientset/versioned/typed/sources/v1alpha1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeSourcesV1alpha1 struct {
+	*testing.Fake
+}
+
+func (c *FakeSourcesV1alpha1) ApiServerSources(namespace string) v1alpha1.ApiServerSourceInterface {
+	return &FakeApiServerSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) AwsSqsSources(namespace string) v1alpha1.AwsSqsSourceInterface {
+	return &FakeAwsSqsSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) ContainerSources(namespace string) v1alpha1.ContainerSourceInterface {
+	return &FakeContainerSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) CronJobSources(namespace string) v1alpha1.CronJobSourceInterface {
+	return &FakeCronJobSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) GitHubSources(namespace string) v1alpha1.GitHubSourceInterface {
+	return &FakeGitHubSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) KafkaSources(namespace string) v1alpha1.KafkaSourceInterface {
+	return &FakeKafkaSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) MQTTSources(namespace string) v1alpha1.MQTTSourceInterface {
+	return &FakeMQTTSources{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) SinkBindings(namespace string) v1alpha1.SinkBindingInterface {
+	return &FakeSinkBindings{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) WebhookSources(namespace string) v1alpha1.WebhookSourceInterface {
+	return &FakeWebhookSources{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeSourcesV1alpha1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
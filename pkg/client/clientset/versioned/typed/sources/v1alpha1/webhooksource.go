@@ -0,0 +1,157 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	scheme "github.com/knative/eventing/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// WebhookSourcesGetter has a method to return a WebhookSourceInterface.
+// A group's client should implement this interface.
+type WebhookSourcesGetter interface {
+	WebhookSources(namespace string) WebhookSourceInterface
+}
+
+// WebhookSourceInterface has methods to work with WebhookSource resources.
+type WebhookSourceInterface interface {
+	Create(*v1alpha1.WebhookSource) (*v1alpha1.WebhookSource, error)
+	Update(*v1alpha1.WebhookSource) (*v1alpha1.WebhookSource, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.WebhookSource, error)
+	List(opts v1.ListOptions) (*v1alpha1.WebhookSourceList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.WebhookSource, err error)
+	WebhookSourceExpansion
+}
+
+// webhookSources implements WebhookSourceInterface
+type webhookSources struct {
+	client rest.Interface
+	ns     string
+}
+
+// newWebhookSources returns a WebhookSources
+func newWebhookSources(c *SourcesV1alpha1Client, namespace string) *webhookSources {
+	return &webhookSources{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the webhookSource, and returns the corresponding webhookSource object, and an error if there is any.
+func (c *webhookSources) Get(name string, options v1.GetOptions) (result *v1alpha1.WebhookSource, err error) {
+	result = &v1alpha1.WebhookSource{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of WebhookSources that match those selectors.
+func (c *webhookSources) List(opts v1.ListOptions) (result *v1alpha1.WebhookSourceList, err error) {
+	result = &v1alpha1.WebhookSourceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested webhookSources.
+func (c *webhookSources) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch()
+}
+
+// Create takes the representation of a webhookSource and creates it.  Returns the server's representation of the webhookSource, and an error, if there is any.
+func (c *webhookSources) Create(webhookSource *v1alpha1.WebhookSource) (result *v1alpha1.WebhookSource, err error) {
+	result = &v1alpha1.WebhookSource{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		Body(webhookSource).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a webhookSource and updates it. Returns the server's representation of the webhookSource, and an error, if there is any.
+func (c *webhookSources) Update(webhookSource *v1alpha1.WebhookSource) (result *v1alpha1.WebhookSource, err error) {
+	result = &v1alpha1.WebhookSource{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		Name(webhookSource.Name).
+		Body(webhookSource).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the webhookSource and deletes it. Returns an error if one occurs.
+func (c *webhookSources) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *webhookSources) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("webhooksources").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched webhookSource.
+func (c *webhookSources) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.WebhookSource, err error) {
+	result = &v1alpha1.WebhookSource{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("webhooksources").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
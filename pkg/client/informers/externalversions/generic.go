@@ -22,6 +22,8 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	v1beta1 "github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	sources_v1alpha1 "github.com/knative/eventing/pkg/apis/sources/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -53,12 +55,50 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=eventing.knative.dev, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("brokers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().Brokers().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("channels"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().Channels().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("channelmigrations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().ChannelMigrations().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterchannelprovisioners"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().ClusterChannelProvisioners().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("eventtypes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().EventTypes().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("parallels"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().Parallels().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("sequences"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().Sequences().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("subscriptions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().Subscriptions().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("triggers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1alpha1().Triggers().Informer()}, nil
+
+		// Group=eventing.knative.dev, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("channels"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1beta1().Channels().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("subscriptions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Eventing().V1beta1().Subscriptions().Informer()}, nil
+
+		// Group=sources.eventing.knative.dev, Version=v1alpha1
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("apiserversources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().ApiServerSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("awssqssources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().AwsSqsSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("containersources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().ContainerSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("cronjobsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().CronJobSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("githubsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().GitHubSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("kafkasources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().KafkaSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("mqttsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().MQTTSources().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("sinkbindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().SinkBindings().Informer()}, nil
+	case sources_v1alpha1.SchemeGroupVersion.WithResource("webhooksources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().WebhookSources().Informer()}, nil
 
 	}
 
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	internalinterfaces "github.com/knative/eventing/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// ApiServerSources returns a ApiServerSourceInformer.
+	ApiServerSources() ApiServerSourceInformer
+	// AwsSqsSources returns a AwsSqsSourceInformer.
+	AwsSqsSources() AwsSqsSourceInformer
+	// ContainerSources returns a ContainerSourceInformer.
+	ContainerSources() ContainerSourceInformer
+	// CronJobSources returns a CronJobSourceInformer.
+	CronJobSources() CronJobSourceInformer
+	// GitHubSources returns a GitHubSourceInformer.
+	GitHubSources() GitHubSourceInformer
+	// KafkaSources returns a KafkaSourceInformer.
+	KafkaSources() KafkaSourceInformer
+	// MQTTSources returns a MQTTSourceInformer.
+	MQTTSources() MQTTSourceInformer
+	// SinkBindings returns a SinkBindingInformer.
+	SinkBindings() SinkBindingInformer
+	// WebhookSources returns a WebhookSourceInformer.
+	WebhookSources() WebhookSourceInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// ApiServerSources returns a ApiServerSourceInformer.
+func (v *version) ApiServerSources() ApiServerSourceInformer {
+	return &apiServerSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// AwsSqsSources returns a AwsSqsSourceInformer.
+func (v *version) AwsSqsSources() AwsSqsSourceInformer {
+	return &awsSqsSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ContainerSources returns a ContainerSourceInformer.
+func (v *version) ContainerSources() ContainerSourceInformer {
+	return &containerSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CronJobSources returns a CronJobSourceInformer.
+func (v *version) CronJobSources() CronJobSourceInformer {
+	return &cronJobSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// GitHubSources returns a GitHubSourceInformer.
+func (v *version) GitHubSources() GitHubSourceInformer {
+	return &gitHubSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KafkaSources returns a KafkaSourceInformer.
+func (v *version) KafkaSources() KafkaSourceInformer {
+	return &kafkaSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// MQTTSources returns a MQTTSourceInformer.
+func (v *version) MQTTSources() MQTTSourceInformer {
+	return &mQTTSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SinkBindings returns a SinkBindingInformer.
+func (v *version) SinkBindings() SinkBindingInformer {
+	return &sinkBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// WebhookSources returns a WebhookSourceInformer.
+func (v *version) WebhookSources() WebhookSourceInformer {
+	return &webhookSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
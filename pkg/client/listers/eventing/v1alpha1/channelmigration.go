@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ChannelMigrationLister helps list ChannelMigrations.
+type ChannelMigrationLister interface {
+	// List lists all ChannelMigrations in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.ChannelMigration, err error)
+	// ChannelMigrations returns an object that can list and get ChannelMigrations.
+	ChannelMigrations(namespace string) ChannelMigrationNamespaceLister
+	ChannelMigrationListerExpansion
+}
+
+// channelMigrationLister implements the ChannelMigrationLister interface.
+type channelMigrationLister struct {
+	indexer cache.Indexer
+}
+
+// NewChannelMigrationLister returns a new ChannelMigrationLister.
+func NewChannelMigrationLister(indexer cache.Indexer) ChannelMigrationLister {
+	return &channelMigrationLister{indexer: indexer}
+}
+
+// List lists all ChannelMigrations in the indexer.
+func (s *channelMigrationLister) List(selector labels.Selector) (ret []*v1alpha1.ChannelMigration, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ChannelMigration))
+	})
+	return ret, err
+}
+
+// ChannelMigrations returns an object that can list and get ChannelMigrations.
+func (s *channelMigrationLister) ChannelMigrations(namespace string) ChannelMigrationNamespaceLister {
+	return channelMigrationNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ChannelMigrationNamespaceLister helps list and get ChannelMigrations.
+type ChannelMigrationNamespaceLister interface {
+	// List lists all ChannelMigrations in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.ChannelMigration, err error)
+	// Get retrieves the ChannelMigration from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.ChannelMigration, error)
+	ChannelMigrationNamespaceListerExpansion
+}
+
+// channelMigrationNamespaceLister implements the ChannelMigrationNamespaceLister
+// interface.
+type channelMigrationNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ChannelMigrations in the indexer for a given namespace.
+func (s channelMigrationNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ChannelMigration, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ChannelMigration))
+	})
+	return ret, err
+}
+
+// Get retrieves the ChannelMigration from the indexer for a given namespace and name.
+func (s channelMigrationNamespaceLister) Get(name string) (*v1alpha1.ChannelMigration, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("channelmigration"), name)
+	}
+	return obj.(*v1alpha1.ChannelMigration), nil
+}
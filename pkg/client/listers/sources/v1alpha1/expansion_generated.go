@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// ApiServerSourceListerExpansion allows custom methods to be added to
+// ApiServerSourceLister.
+type ApiServerSourceListerExpansion interface{}
+
+// ApiServerSourceNamespaceListerExpansion allows custom methods to be added to
+// ApiServerSourceNamespaceLister.
+type ApiServerSourceNamespaceListerExpansion interface{}
+
+// AwsSqsSourceListerExpansion allows custom methods to be added to
+// AwsSqsSourceLister.
+type AwsSqsSourceListerExpansion interface{}
+
+// AwsSqsSourceNamespaceListerExpansion allows custom methods to be added to
+// AwsSqsSourceNamespaceLister.
+type AwsSqsSourceNamespaceListerExpansion interface{}
+
+// ContainerSourceListerExpansion allows custom methods to be added to
+// ContainerSourceLister.
+type ContainerSourceListerExpansion interface{}
+
+// ContainerSourceNamespaceListerExpansion allows custom methods to be added to
+// ContainerSourceNamespaceLister.
+type ContainerSourceNamespaceListerExpansion interface{}
+
+// CronJobSourceListerExpansion allows custom methods to be added to
+// CronJobSourceLister.
+type CronJobSourceListerExpansion interface{}
+
+// CronJobSourceNamespaceListerExpansion allows custom methods to be added to
+// CronJobSourceNamespaceLister.
+type CronJobSourceNamespaceListerExpansion interface{}
+
+// GitHubSourceListerExpansion allows custom methods to be added to
+// GitHubSourceLister.
+type GitHubSourceListerExpansion interface{}
+
+// GitHubSourceNamespaceListerExpansion allows custom methods to be added to
+// GitHubSourceNamespaceLister.
+type GitHubSourceNamespaceListerExpansion interface{}
+
+// KafkaSourceListerExpansion allows custom methods to be added to
+// KafkaSourceLister.
+type KafkaSourceListerExpansion interface{}
+
+// KafkaSourceNamespaceListerExpansion allows custom methods to be added to
+// KafkaSourceNamespaceLister.
+type KafkaSourceNamespaceListerExpansion interface{}
+
+// MQTTSourceListerExpansion allows custom methods to be added to
+// MQTTSourceLister.
+type MQTTSourceListerExpansion interface{}
+
+// MQTTSourceNamespaceListerExpansion allows custom methods to be added to
+// MQTTSourceNamespaceLister.
+type MQTTSourceNamespaceListerExpansion interface{}
+
+// SinkBindingListerExpansion allows custom methods to be added to
+// SinkBindingLister.
+type SinkBindingListerExpansion interface{}
+
+// SinkBindingNamespaceListerExpansion allows custom methods to be added to
+// SinkBindingNamespaceLister.
+type SinkBindingNamespaceListerExpansion interface{}
+
+// WebhookSourceListerExpansion allows custom methods to be added to
+// WebhookSourceLister.
+type WebhookSourceListerExpansion interface{}
+
+// WebhookSourceNamespaceListerExpansion allows custom methods to be added to
+// WebhookSourceNamespaceLister.
+type WebhookSourceNamespaceListerExpansion interface{}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// BrokerClassPredicate returns a predicate that only accepts the events of the Brokers of one of
+// classes. Every implementation of Brokers watches Brokers with the predicate of its classes, so
+// that each Broker is reconciled by a single controller, selected by its BrokerClassAnnotationKey
+// annotation.
+func BrokerClassPredicate(classes ...string) predicate.Funcs {
+	accept := func(m metav1.Object) bool {
+		b := &v1alpha1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: m.GetAnnotations()}}
+		for _, class := range classes {
+			if b.Class() == class {
+				return true
+			}
+		}
+		return false
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return accept(e.Meta)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return accept(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return accept(e.MetaNew)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return accept(e.Meta)
+		},
+	}
+}
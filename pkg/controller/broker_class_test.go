@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestBrokerClassPredicate(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"no annotation": {
+			want: true,
+		},
+		"channel-based": {
+			annotations: map[string]string{v1alpha1.BrokerClassAnnotationKey: v1alpha1.ChannelBasedBrokerClass},
+			want:        true,
+		},
+		"multi-tenant": {
+			annotations: map[string]string{v1alpha1.BrokerClassAnnotationKey: v1alpha1.MultiTenantChannelBasedBrokerClass},
+			want:        true,
+		},
+		"other class": {
+			annotations: map[string]string{v1alpha1.BrokerClassAnnotationKey: "KafkaBroker"},
+			want:        false,
+		},
+	}
+	p := BrokerClassPredicate(v1alpha1.ChannelBasedBrokerClass, v1alpha1.MultiTenantChannelBasedBrokerClass)
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			b := &v1alpha1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := p.Create(event.CreateEvent{Meta: b, Object: b}); got != tc.want {
+				t.Errorf("Unexpected create result. Expected %v, actual %v", tc.want, got)
+			}
+			if got := p.Update(event.UpdateEvent{MetaOld: b, ObjectOld: b, MetaNew: b, ObjectNew: b}); got != tc.want {
+				t.Errorf("Unexpected update result. Expected %v, actual %v", tc.want, got)
+			}
+			if got := p.Delete(event.DeleteEvent{Meta: b, Object: b}); got != tc.want {
+				t.Errorf("Unexpected delete result. Expected %v, actual %v", tc.want, got)
+			}
+			if got := p.Generic(event.GenericEvent{Meta: b, Object: b}); got != tc.want {
+				t.Errorf("Unexpected generic result. Expected %v, actual %v", tc.want, got)
+			}
+		})
+	}
+}
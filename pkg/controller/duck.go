@@ -0,0 +1,83 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"fmt"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	duckapis "github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// GetSubscribable reads the Subscribable portion of the object referenced by ref in namespace. The
+// object may be of any kind that has a spec.subscribable.
+func GetSubscribable(dc dynamic.Interface, namespace string, ref corev1.ObjectReference) (*eventingduck.Channel, error) {
+	obj, err := getObjectReference(dc, namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	return eventingduck.SubscribableFromUnstructured(obj)
+}
+
+// PatchSubscribable replaces the subscribers of the object referenced by ref in namespace with
+// subs.
+func PatchSubscribable(dc dynamic.Interface, namespace string, ref corev1.ObjectReference, subs *eventingduck.Subscribable) error {
+	original, err := GetSubscribable(dc, namespace, ref)
+	if err != nil {
+		return err
+	}
+	patch, err := original.SubscribersPatch(subs)
+	if err != nil {
+		return err
+	}
+	rc, err := resourceInterface(dc, namespace, ref)
+	if err != nil {
+		return err
+	}
+	_, err = rc.Patch(original.Name, types.JSONPatchType, patch)
+	return err
+}
+
+// ResolveAddressable returns the hostname in the status.address of the object referenced by ref in
+// namespace. The object may be of any kind that is Addressable.
+func ResolveAddressable(dc dynamic.Interface, namespace string, ref corev1.ObjectReference) (string, error) {
+	obj, err := getObjectReference(dc, namespace, ref)
+	if err != nil {
+		return "", err
+	}
+	return eventingduck.AddressFromUnstructured(obj)
+}
+
+func getObjectReference(dc dynamic.Interface, namespace string, ref corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	rc, err := resourceInterface(dc, namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	return rc.Get(ref.Name, metav1.GetOptions{})
+}
+
+func resourceInterface(dc dynamic.Interface, namespace string, ref corev1.ObjectReference) (dynamic.ResourceInterface, error) {
+	rc := dc.Resource(duckapis.KindToResource(ref.GroupVersionKind()))
+	if rc == nil {
+		return nil, fmt.Errorf("failed to create dynamic client resource")
+	}
+	return rc.Namespace(namespace), nil
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/broker/ingress"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/eventing/broker/resources"
+	"github.com/knative/eventing/pkg/system"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileMultiTenant reconciles the rest of the multi-tenant Broker b, once its Channel is
+// ready. Instead of a Deployment of its own, b gets Services in its namespace that alias the
+// shared ingress and filter, and routes in their shared ConfigMap.
+func (r *reconciler) reconcileMultiTenant(ctx context.Context, b *v1alpha1.Broker) error {
+	if _, err := r.reconcileService(ctx, resources.MakeMultiTenantFilterService(b)); err != nil {
+		glog.Warningf("Failed to reconcile the filter of broker %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkFilterNotReady("FilterFailure", "%v", err)
+		return err
+	}
+	svc, err := r.reconcileService(ctx, resources.MakeMultiTenantIngressService(b))
+	if err != nil {
+		glog.Warningf("Failed to reconcile the ingress of broker %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkIngressNotReady("IngressFailure", "%v", err)
+		return err
+	}
+
+	if err := r.syncMultiTenantConfig(ctx); err != nil {
+		glog.Warningf("Failed to sync the multi-tenant config for broker %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkFilterNotReady("ConfigFailure", "%v", err)
+		b.Status.MarkIngressNotReady("ConfigFailure", "%v", err)
+		return err
+	}
+	b.Status.MarkFilterReady()
+	b.Status.MarkIngressReady()
+	b.Status.SetAddress(controller.ServiceHostName(svc.Name, svc.Namespace))
+	return nil
+}
+
+// resyncMultiTenantConfig syncs the shared ConfigMap of the multi-tenant Brokers, if it exists.
+// It is used when a Broker of unknown class has been deleted.
+func (r *reconciler) resyncMultiTenantConfig(ctx context.Context) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: system.Namespace, Name: resources.MultiTenantConfigMapName}, cm)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.syncMultiTenantConfig(ctx)
+}
+
+// syncMultiTenantConfig creates or updates the ConfigMap holding the routes of the shared ingress
+// and filter. It is rebuilt from all the multi-tenant Brokers whose Channel is ready, so that
+// every Broker's reconciliation converges to the same content.
+func (r *reconciler) syncMultiTenantConfig(ctx context.Context) error {
+	brokers, err := r.listBrokers(ctx, "")
+	if err != nil {
+		return err
+	}
+	sort.Slice(brokers, func(i, j int) bool {
+		if brokers[i].Namespace != brokers[j].Namespace {
+			return brokers[i].Namespace < brokers[j].Namespace
+		}
+		return brokers[i].Name < brokers[j].Name
+	})
+
+	ingressConfig := ingress.Config{Routes: []ingress.Route{}}
+	filterConfig := filter.Config{Routes: []filter.Route{}}
+	for i := range brokers {
+		b := &brokers[i]
+		if b.Class() != v1alpha1.MultiTenantChannelBasedBrokerClass || b.DeletionTimestamp != nil {
+			continue
+		}
+		c := &v1alpha1.Channel{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: resources.ChannelName(b.Name)}, c)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !c.Status.IsReady() || c.Status.Address.Hostname == "" {
+			continue
+		}
+		ingressConfig.Routes = append(ingressConfig.Routes, ingress.Route{
+			Namespace:  b.Namespace,
+			Name:       resources.IngressName(b.Name),
+			ChannelURI: fmt.Sprintf("http://%s/", c.Status.Address.Hostname),
+		})
+
+		fc, err := r.filterConfig(ctx, b)
+		if err != nil {
+			return err
+		}
+		filterConfig.Routes = append(filterConfig.Routes, fc.Routes...)
+	}
+
+	data, err := ingress.SerializeConfig(ingressConfig)
+	if err != nil {
+		return err
+	}
+	filterData, err := filter.SerializeConfig(filterConfig)
+	if err != nil {
+		return err
+	}
+	for k, v := range filterData {
+		data[k] = v
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: system.Namespace, Name: resources.MultiTenantConfigMapName}, cm)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, resources.MakeMultiTenantConfigMap(data))
+	}
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(data, cm.Data) {
+		cm.Data = data
+		return r.client.Update(ctx, cm)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package broker
+
+import (
+	"os"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingcontroller "github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "broker-controller"
+
+	// ingressImageEnvVar and filterImageEnvVar name the environment variables holding the images
+	// of the Broker data plane.
+	ingressImageEnvVar = "BROKER_INGRESS_IMAGE"
+	filterImageEnvVar  = "BROKER_FILTER_IMAGE"
+)
+
+type reconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+
+	ingressImage string
+	filterImage  string
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a Broker controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Setup a new controller to Reconcile Brokers.
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, &reconciler{
+		recorder:     mgr.GetRecorder(controllerAgentName),
+		ingressImage: os.Getenv(ingressImageEnvVar),
+		filterImage:  os.Getenv(filterImageEnvVar),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the events of channel-based Brokers, multi-tenant or not, and enqueue Broker object key.
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Broker{}}, &handler.EnqueueRequestForObject{}, eventingcontroller.BrokerClassPredicate(v1alpha1.ChannelBasedBrokerClass, v1alpha1.MultiTenantChannelBasedBrokerClass))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch all the resources that the Broker reconciles.
+	for _, t := range []runtime.Object{&v1alpha1.Channel{}, &appsv1.Deployment{}, &corev1.Service{}, &corev1.ConfigMap{}} {
+		err = c.Watch(&source.Kind{Type: t}, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.Broker{}, IsController: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Watch Triggers and enqueue the key of their Broker, whose filter routes events to them.
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Trigger{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(mapTriggerToBroker)})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func mapTriggerToBroker(o handler.MapObject) []reconcile.Request {
+	t, ok := o.Object.(*v1alpha1.Trigger)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: t.Namespace,
+			Name:      t.Spec.Broker,
+		},
+	}}
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
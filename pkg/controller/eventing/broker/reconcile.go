@@ -0,0 +1,340 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/eventing/broker/resources"
+	eventfilter "github.com/knative/eventing/pkg/filter"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Broker resource
+// with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling broker %v", request)
+	ctx := context.TODO()
+	broker := &v1alpha1.Broker{}
+	err := r.client.Get(ctx, request.NamespacedName, broker)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find broker %v\n", request)
+		// The Broker may have been a multi-tenant one, whose routes must be removed from the
+		// shared ingress and filter.
+		return reconcile.Result{}, r.resyncMultiTenantConfig(ctx)
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch Broker %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	if !isChannelBased(broker) {
+		// The Broker is reconciled by the controller of its class. It was enqueued for one of
+		// its Triggers.
+		return reconcile.Result{}, nil
+	}
+
+	// Reconcile this copy of the Broker and then write back any status
+	// updates regardless of whether the reconcile error out.
+	broker = broker.DeepCopy()
+	err = r.reconcile(ctx, broker)
+	if updateStatusErr := r.updateStatus(ctx, broker); updateStatusErr != nil {
+		glog.Warningf("Failed to update broker status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, b *v1alpha1.Broker) error {
+	b.Status.InitializeConditions()
+
+	if b.DeletionTimestamp != nil {
+		// Everything the Broker created is owned by it and will be garbage collected.
+		return nil
+	}
+
+	c, err := r.reconcileChannel(ctx, b)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the Channel of broker %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkChannelNotReady("ChannelFailure", "%v", err)
+		return err
+	}
+	if !c.Status.IsReady() || c.Status.Address.Hostname == "" {
+		// The ingress needs the address of the Channel. The Broker is reconciled again when
+		// the Channel changes.
+		b.Status.MarkChannelNotReady("ChannelNotReady", "Channel %s is not ready", c.Name)
+		return nil
+	}
+	b.Status.MarkChannelReady()
+
+	if b.Class() == v1alpha1.MultiTenantChannelBasedBrokerClass {
+		return r.reconcileMultiTenant(ctx, b)
+	}
+
+	if err := r.reconcileFilter(ctx, b); err != nil {
+		glog.Warningf("Failed to reconcile the filter of broker %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkFilterNotReady("FilterFailure", "%v", err)
+		return err
+	}
+	b.Status.MarkFilterReady()
+
+	svc, err := r.reconcileIngress(ctx, b, c)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the ingress of broker %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkIngressNotReady("IngressFailure", "%v", err)
+		return err
+	}
+	b.Status.MarkIngressReady()
+	b.Status.SetAddress(controller.ServiceHostName(svc.Name, svc.Namespace))
+	return nil
+}
+
+// isChannelBased returns true if b is of one of the classes reconciled by this controller.
+func isChannelBased(b *v1alpha1.Broker) bool {
+	class := b.Class()
+	return class == v1alpha1.ChannelBasedBrokerClass || class == v1alpha1.MultiTenantChannelBasedBrokerClass
+}
+
+// reconcileChannel creates the Channel of b if it does not exist yet. The Channel is not updated,
+// as its template cannot change.
+func (r *reconciler) reconcileChannel(ctx context.Context, b *v1alpha1.Broker) (*v1alpha1.Channel, error) {
+	c := &v1alpha1.Channel{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: resources.ChannelName(b.Name)}, c)
+	if errors.IsNotFound(err) {
+		c = resources.MakeChannel(b)
+		err = r.client.Create(ctx, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *reconciler) reconcileFilter(ctx context.Context, b *v1alpha1.Broker) error {
+	if err := r.reconcileFilterConfigMap(ctx, b); err != nil {
+		return err
+	}
+	if err := r.reconcileDeployment(ctx, resources.MakeFilterDeployment(b, r.filterImage)); err != nil {
+		return err
+	}
+	_, err := r.reconcileService(ctx, resources.MakeFilterService(b))
+	return err
+}
+
+// reconcileFilterConfigMap creates or updates the ConfigMap holding the routes of the filter of b.
+// There is one route for every Trigger of b whose subscriber has been resolved.
+func (r *reconciler) reconcileFilterConfigMap(ctx context.Context, b *v1alpha1.Broker) error {
+	config, err := r.filterConfig(ctx, b)
+	if err != nil {
+		return err
+	}
+	data, err := filter.SerializeConfig(*config)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: resources.FilterName(b.Name)}, cm)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, resources.MakeFilterConfigMap(b, data))
+	}
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(data, cm.Data) {
+		cm.Data = data
+		return r.client.Update(ctx, cm)
+	}
+	return nil
+}
+
+// filterConfig returns the routes of the filter of b, sorted by the name of their Trigger.
+func (r *reconciler) filterConfig(ctx context.Context, b *v1alpha1.Broker) (*filter.Config, error) {
+	triggers, err := r.listTriggers(ctx, b.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	config := &filter.Config{}
+	// Replies are sent back into the Broker, so that Triggers can react to them.
+	replyURI := controller.DomainToURL(controller.ServiceHostName(resources.IngressName(b.Name), b.Namespace))
+	for _, t := range triggers {
+		if t.Spec.Broker != b.Name || t.DeletionTimestamp != nil || t.Status.SubscriberURI == "" {
+			continue
+		}
+		route := filter.Route{
+			Namespace:     t.Namespace,
+			Name:          t.Name,
+			SubscriberURI: t.Status.SubscriberURI,
+			ReplyURI:      replyURI,
+			DeadLetterURI: t.Status.DeadLetterSinkURI,
+		}
+		if t.Spec.Filter != nil {
+			route.Filter = eventfilter.AttributesExpression(t.Spec.Filter.Attributes)
+		}
+		// The delivery of the Trigger overrides the one of the Broker.
+		delivery := t.Spec.Delivery
+		if delivery == nil {
+			delivery = b.Spec.Delivery
+		}
+		if delivery != nil {
+			route.Retry = delivery.Retry
+			route.BackoffPolicy = string(delivery.BackoffPolicy)
+			route.BackoffDelay = delivery.BackoffDelay
+			route.PermanentStatusCodes = delivery.PermanentStatusCodes
+		}
+		config.Routes = append(config.Routes, route)
+	}
+	sort.Slice(config.Routes, func(i, j int) bool {
+		return config.Routes[i].Name < config.Routes[j].Name
+	})
+	return config, nil
+}
+
+func (r *reconciler) listBrokers(ctx context.Context, namespace string) ([]v1alpha1.Broker, error) {
+	brokers := make([]v1alpha1.Broker, 0)
+
+	opts := &client.ListOptions{
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Broker",
+			},
+		},
+		Namespace: namespace,
+	}
+	for {
+		bl := &v1alpha1.BrokerList{}
+		if err := r.client.List(ctx, opts, bl); err != nil {
+			return nil, err
+		}
+		brokers = append(brokers, bl.Items...)
+		if bl.Continue != "" {
+			opts.Raw.Continue = bl.Continue
+		} else {
+			return brokers, nil
+		}
+	}
+}
+
+func (r *reconciler) listTriggers(ctx context.Context, namespace string) ([]v1alpha1.Trigger, error) {
+	triggers := make([]v1alpha1.Trigger, 0)
+
+	opts := &client.ListOptions{
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Trigger",
+			},
+		},
+		Namespace: namespace,
+	}
+	for {
+		tl := &v1alpha1.TriggerList{}
+		if err := r.client.List(ctx, opts, tl); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, tl.Items...)
+		if tl.Continue != "" {
+			opts.Raw.Continue = tl.Continue
+		} else {
+			return triggers, nil
+		}
+	}
+}
+
+func (r *reconciler) reconcileIngress(ctx context.Context, b *v1alpha1.Broker, c *v1alpha1.Channel) (*corev1.Service, error) {
+	channelURI := fmt.Sprintf("http://%s/", c.Status.Address.Hostname)
+	if err := r.reconcileDeployment(ctx, resources.MakeIngressDeployment(b, r.ingressImage, channelURI)); err != nil {
+		return nil, err
+	}
+	return r.reconcileService(ctx, resources.MakeIngressService(b))
+}
+
+// reconcileDeployment creates d, or updates the spec of the existing Deployment to match it.
+func (r *reconciler) reconcileDeployment(ctx context.Context, d *appsv1.Deployment) error {
+	current := &appsv1.Deployment{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, d)
+	}
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+// reconcileService creates svc, or updates the spec of the existing Service to match it.
+func (r *reconciler) reconcileService(ctx context.Context, svc *corev1.Service) (*corev1.Service, error) {
+	current := &corev1.Service{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: svc.Namespace, Name: svc.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, svc); err != nil {
+			return nil, err
+		}
+		return svc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// spec.clusterIP is immutable and is set on existing services. If we don't set this
+	// to the same value, we will encounter an error while updating.
+	svc.Spec.ClusterIP = current.Spec.ClusterIP
+	if !equality.Semantic.DeepDerivative(svc.Spec, current.Spec) {
+		current.Spec = svc.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, b *v1alpha1.Broker) error {
+	current := &v1alpha1.Broker{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, b.Status) {
+		return nil
+	}
+	current.Status = b.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the Broker resource.
+	return r.client.Update(ctx, current)
+}
@@ -0,0 +1,616 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/broker/ingress"
+	"github.com/knative/eventing/pkg/controller/eventing/broker/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS     = "test-namespace"
+	brokerName = "test-broker"
+	brokerUID  = "test-uid"
+
+	ingressImage = "ingress-image"
+	filterImage  = "filter-image"
+
+	channelHostname = "test-broker-broker-channel.test-namespace.svc.cluster.local"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Broker not found",
+		},
+		{
+			Name: "Error getting Broker",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Broker{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Broker being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingBroker(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingBroker(),
+			},
+			WantAbsent: []runtime.Object{
+				makeChannel(),
+			},
+		},
+		{
+			Name: "Broker of another class",
+			InitialState: []runtime.Object{
+				makeBrokerOfClass("KafkaBroker"),
+			},
+			WantPresent: []runtime.Object{
+				makeBrokerOfClass("KafkaBroker"),
+			},
+			WantAbsent: []runtime.Object{
+				makeChannel(),
+			},
+		},
+		{
+			Name: "Channel creation fails",
+			InitialState: []runtime.Object{
+				makeBroker(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Channel{}),
+			},
+			WantPresent: []runtime.Object{
+				makeBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+					s.MarkChannelNotReady("ChannelFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel created, not ready yet",
+			InitialState: []runtime.Object{
+				makeBroker(),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(),
+				makeBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+					s.MarkChannelNotReady("ChannelNotReady", "Channel test-broker-broker is not ready")
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeIngressService(),
+			},
+		},
+		{
+			Name: "Channel created from template",
+			InitialState: []runtime.Object{
+				makeBrokerWithChannelTemplate(),
+			},
+			WantPresent: []runtime.Object{
+				makeChannelFromTemplate(),
+			},
+		},
+		{
+			Name: "Filter Deployment creation fails",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&appsv1.Deployment{}),
+			},
+			WantPresent: []runtime.Object{
+				makeFilterConfigMap(),
+				makeBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+					s.MarkChannelReady()
+					s.MarkFilterNotReady("FilterFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Ingress Service get fails",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+				makeFilterConfigMap(),
+				makeFilterDeployment(),
+				makeFilterService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGettingService(resources.IngressName(brokerName)),
+			},
+			WantPresent: []runtime.Object{
+				makeIngressDeployment(),
+				makeBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+					s.MarkChannelReady()
+					s.MarkFilterReady()
+					s.MarkIngressNotReady("IngressFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Broker ready",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyBroker(),
+				makeFilterConfigMap(),
+				makeFilterDeployment(),
+				makeFilterService(),
+				makeIngressDeployment(),
+				makeIngressService(),
+			},
+		},
+		{
+			Name: "Existing resources are updated",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+				makeExistingFilterConfigMap(),
+				withImage(makeFilterDeployment(), "old-filter-image"),
+				makeFilterService(),
+				withImage(makeIngressDeployment(), "old-ingress-image"),
+				makeIngressService(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyBroker(),
+				// The stale route is removed, as its Trigger does not exist.
+				makeFilterConfigMap(),
+				makeFilterDeployment(),
+				makeIngressDeployment(),
+			},
+		},
+		{
+			Name: "Listing Triggers fails",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockLists: errorListing(&v1alpha1.TriggerList{}),
+			},
+			WantPresent: []runtime.Object{
+				makeBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+					s.MarkChannelReady()
+					s.MarkFilterNotReady("FilterFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Filter routes to Triggers",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+				makeFilterConfigMap(),
+				makeTrigger("b-trigger", brokerName, "http://b.example.com/", map[string]string{"type": "dev.knative.foo"}),
+				makeTrigger("a-trigger", brokerName, "http://a.example.com/", nil),
+				// Not resolved yet.
+				makeTrigger("unresolved-trigger", brokerName, "", nil),
+				// Routed by another Broker.
+				makeTrigger("other-trigger", "other-broker", "http://other.example.com/", nil),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyBroker(),
+				makeFilterConfigMapWithRoutes(`{"routes":[` +
+					`{"namespace":"test-namespace","name":"a-trigger","subscriberURI":"http://a.example.com/","replyURI":"http://test-broker-broker-ingress.test-namespace.svc.cluster.local/"},` +
+					`{"namespace":"test-namespace","name":"b-trigger","filter":"type = 'dev.knative.foo'","subscriberURI":"http://b.example.com/","replyURI":"http://test-broker-broker-ingress.test-namespace.svc.cluster.local/"}]}`),
+			},
+		},
+		{
+			Name: "Filter routes use the delivery of the Broker unless overridden",
+			InitialState: []runtime.Object{
+				makeBrokerWithDelivery(&v1alpha1.DeliverySpec{Retry: 2, BackoffDelay: "2s", PermanentStatusCodes: []int32{400, 422}}),
+				makeReadyChannel(),
+				makeFilterConfigMap(),
+				withDeadLetterSinkURI(makeTrigger("a-trigger", brokerName, "http://a.example.com/", nil), "http://dlq.example.com/"),
+				withDelivery(makeTrigger("b-trigger", brokerName, "http://b.example.com/", nil), &v1alpha1.DeliverySpec{Retry: 5, BackoffPolicy: v1alpha1.BackoffPolicyLinear}),
+			},
+			WantPresent: []runtime.Object{
+				makeFilterConfigMapWithRoutes(`{"routes":[` +
+					`{"namespace":"test-namespace","name":"a-trigger","subscriberURI":"http://a.example.com/","replyURI":"http://test-broker-broker-ingress.test-namespace.svc.cluster.local/","retry":2,"backoffDelay":"2s","deadLetterURI":"http://dlq.example.com/","permanentStatusCodes":[400,422]},` +
+					`{"namespace":"test-namespace","name":"b-trigger","subscriberURI":"http://b.example.com/","replyURI":"http://test-broker-broker-ingress.test-namespace.svc.cluster.local/","retry":5,"backoffPolicy":"linear"}]}`),
+			},
+		},
+		{
+			Name: "Multi-tenant Broker ready",
+			InitialState: []runtime.Object{
+				makeMultiTenantBroker(),
+				makeReadyChannel(),
+				makeTrigger("a-trigger", brokerName, "http://a.example.com/", nil),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyMultiTenantBroker(),
+				makeMultiTenantFilterService(),
+				makeMultiTenantIngressService(),
+				makeMultiTenantConfigMap(
+					`{"routes":[{"namespace":"test-namespace","name":"test-broker-broker-ingress","channelURI":"http://test-broker-broker-channel.test-namespace.svc.cluster.local/"}]}`,
+					`{"routes":[{"namespace":"test-namespace","name":"a-trigger","subscriberURI":"http://a.example.com/","replyURI":"http://test-broker-broker-ingress.test-namespace.svc.cluster.local/"}]}`),
+			},
+			WantAbsent: []runtime.Object{
+				makeFilterDeployment(),
+				makeIngressDeployment(),
+			},
+		},
+		{
+			Name: "Multi-tenant config update fails",
+			InitialState: []runtime.Object{
+				makeMultiTenantBroker(),
+				makeReadyChannel(),
+				makeMultiTenantConfigMap(`{"routes":[]}`, `{"routes":[]}`),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&corev1.ConfigMap{}),
+			},
+			WantPresent: []runtime.Object{
+				makeMultiTenantBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+					s.MarkChannelReady()
+					s.MarkFilterNotReady("ConfigFailure", testErrorMessage)
+					s.MarkIngressNotReady("ConfigFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Deleted multi-tenant Broker is removed from the shared config",
+			InitialState: []runtime.Object{
+				makeMultiTenantConfigMap(
+					`{"routes":[{"namespace":"test-namespace","name":"test-broker-broker-ingress","channelURI":"http://test-broker-broker-channel.test-namespace.svc.cluster.local/"}]}`,
+					`{"routes":[]}`),
+			},
+			WantPresent: []runtime.Object{
+				makeMultiTenantConfigMap(`{"routes":[]}`, `{"routes":[]}`),
+			},
+		},
+		{
+			Name: "Updating Broker status fails",
+			InitialState: []runtime.Object{
+				makeBroker(),
+				makeReadyChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.Broker{}),
+			},
+			WantPresent: []runtime.Object{
+				makeIngressService(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:       c,
+			recorder:     recorder,
+			ingressImage: ingressImage,
+			filterImage:  filterImage,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, brokerName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeBroker() *v1alpha1.Broker {
+	return &v1alpha1.Broker{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Broker",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      brokerName,
+			UID:       brokerUID,
+		},
+	}
+}
+
+func makeBrokerOfClass(class string) *v1alpha1.Broker {
+	b := makeBroker()
+	b.Annotations = map[string]string{v1alpha1.BrokerClassAnnotationKey: class}
+	return b
+}
+
+func makeBrokerWithStatus(f func(*v1alpha1.BrokerStatus)) *v1alpha1.Broker {
+	b := makeBroker()
+	b.Status.InitializeConditions()
+	f(&b.Status)
+	return b
+}
+
+func makeReadyBroker() *v1alpha1.Broker {
+	return makeBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+		s.MarkChannelReady()
+		s.MarkFilterReady()
+		s.MarkIngressReady()
+		s.SetAddress("test-broker-broker-ingress.test-namespace.svc.cluster.local")
+	})
+}
+
+func makeMultiTenantBroker() *v1alpha1.Broker {
+	return makeBrokerOfClass(v1alpha1.MultiTenantChannelBasedBrokerClass)
+}
+
+func makeMultiTenantBrokerWithStatus(f func(*v1alpha1.BrokerStatus)) *v1alpha1.Broker {
+	b := makeMultiTenantBroker()
+	b.Status.InitializeConditions()
+	f(&b.Status)
+	return b
+}
+
+func makeReadyMultiTenantBroker() *v1alpha1.Broker {
+	return makeMultiTenantBrokerWithStatus(func(s *v1alpha1.BrokerStatus) {
+		s.MarkChannelReady()
+		s.MarkFilterReady()
+		s.MarkIngressReady()
+		s.SetAddress("test-broker-broker-ingress.test-namespace.svc.cluster.local")
+	})
+}
+
+func makeDeletingBroker() *v1alpha1.Broker {
+	b := makeBrokerWithStatus(func(*v1alpha1.BrokerStatus) {})
+	b.DeletionTimestamp = &deletionTime
+	return b
+}
+
+func makeBrokerWithDelivery(delivery *v1alpha1.DeliverySpec) *v1alpha1.Broker {
+	b := makeBroker()
+	b.Spec.Delivery = delivery
+	return b
+}
+
+func makeBrokerWithChannelTemplate() *v1alpha1.Broker {
+	b := makeBroker()
+	b.Spec.ChannelTemplate = &v1alpha1.ChannelSpec{
+		Provisioner: &corev1.ObjectReference{
+			Name: "my-provisioner",
+		},
+	}
+	return b
+}
+
+func makeChannel() *v1alpha1.Channel {
+	c := resources.MakeChannel(makeBroker())
+	c.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+	}
+	return c
+}
+
+func makeChannelFromTemplate() *v1alpha1.Channel {
+	c := makeChannel()
+	c.Spec.Provisioner = &corev1.ObjectReference{
+		Name: "my-provisioner",
+	}
+	return c
+}
+
+func makeReadyChannel() *v1alpha1.Channel {
+	c := makeChannel()
+	c.Status.InitializeConditions()
+	c.Status.MarkProvisioned()
+	c.Status.SetAddress(channelHostname)
+	return c
+}
+
+func makeFilterConfigMap() *corev1.ConfigMap {
+	data, err := filter.SerializeConfig(filter.Config{})
+	if err != nil {
+		panic(err)
+	}
+	cm := resources.MakeFilterConfigMap(makeBroker(), data)
+	cm.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+	return cm
+}
+
+func makeExistingFilterConfigMap() *corev1.ConfigMap {
+	return makeFilterConfigMapWithRoutes(`{"routes":[{"namespace":"test-namespace","name":"trigger"}]}`)
+}
+
+func makeFilterConfigMapWithRoutes(routes string) *corev1.ConfigMap {
+	cm := makeFilterConfigMap()
+	cm.Data = map[string]string{filter.ConfigKey: routes}
+	return cm
+}
+
+func makeTrigger(name, broker, subscriberURI string, attributes map[string]string) *v1alpha1.Trigger {
+	t := &v1alpha1.Trigger{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Trigger",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      name,
+		},
+		Spec: v1alpha1.TriggerSpec{
+			Broker: broker,
+		},
+		Status: v1alpha1.TriggerStatus{
+			SubscriberURI: subscriberURI,
+		},
+	}
+	if attributes != nil {
+		t.Spec.Filter = &v1alpha1.TriggerFilter{Attributes: attributes}
+	}
+	return t
+}
+
+func makeFilterDeployment() *appsv1.Deployment {
+	d := resources.MakeFilterDeployment(makeBroker(), filterImage)
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	return d
+}
+
+func makeFilterService() *corev1.Service {
+	svc := resources.MakeFilterService(makeBroker())
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	return svc
+}
+
+func makeIngressDeployment() *appsv1.Deployment {
+	d := resources.MakeIngressDeployment(makeBroker(), ingressImage, "http://"+channelHostname+"/")
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	return d
+}
+
+func makeIngressService() *corev1.Service {
+	svc := resources.MakeIngressService(makeBroker())
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	return svc
+}
+
+func makeMultiTenantFilterService() *corev1.Service {
+	svc := resources.MakeMultiTenantFilterService(makeMultiTenantBroker())
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	return svc
+}
+
+func makeMultiTenantIngressService() *corev1.Service {
+	svc := resources.MakeMultiTenantIngressService(makeMultiTenantBroker())
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	return svc
+}
+
+func makeMultiTenantConfigMap(ingressRoutes, filterRoutes string) *corev1.ConfigMap {
+	cm := resources.MakeMultiTenantConfigMap(map[string]string{
+		ingress.ConfigKey: ingressRoutes,
+		filter.ConfigKey:  filterRoutes,
+	})
+	cm.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+	return cm
+}
+
+func withDelivery(t *v1alpha1.Trigger, delivery *v1alpha1.DeliverySpec) *v1alpha1.Trigger {
+	t.Spec.Delivery = delivery
+	return t
+}
+
+func withDeadLetterSinkURI(t *v1alpha1.Trigger, uri string) *v1alpha1.Trigger {
+	t.Status.DeadLetterSinkURI = uri
+	return t
+}
+
+func withImage(d *appsv1.Deployment, image string) *appsv1.Deployment {
+	d.Spec.Template.Spec.Containers[0].Image = image
+	return d
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorGettingService(name string) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, key client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if _, ok := obj.(*corev1.Service); ok && key.Name == name {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorListing(t runtime.Object) []controllertesting.MockList {
+	return []controllertesting.MockList{
+		func(_ client.Client, _ context.Context, _ *client.ListOptions, list runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", list) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	"github.com/knative/eventing/pkg/provisioners"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	filterConfigVolumeName = "broker-filter-config"
+)
+
+// MakeFilterDeployment creates the Deployment of the filter of b. The filter reads its routes from
+// the ConfigMap created by MakeFilterConfigMap.
+func MakeFilterDeployment(b *v1alpha1.Broker, image string) *appsv1.Deployment {
+	labels := roleLabels(b.Name, filterRole)
+	return &appsv1.Deployment{
+		ObjectMeta: objectMeta(b, FilterName(b.Name), labels),
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "filter",
+						Image: image,
+						Ports: []corev1.ContainerPort{{
+							Name:          provisioners.PortName,
+							ContainerPort: provisioners.MessageReceiverPort,
+						}},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      filterConfigVolumeName,
+							MountPath: filter.ConfigDir,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: filterConfigVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: FilterName(b.Name),
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// MakeFilterService creates the Service that the Broker's Channel delivers events to.
+func MakeFilterService(b *v1alpha1.Broker) *corev1.Service {
+	return makeService(b, FilterName(b.Name), filterRole)
+}
+
+// MakeFilterConfigMap creates the ConfigMap holding the routes of the filter of b.
+func MakeFilterConfigMap(b *v1alpha1.Broker, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: objectMeta(b, FilterName(b.Name), Labels(b.Name)),
+		Data:       data,
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeIngressDeployment creates the Deployment of the ingress of b, which writes events to the
+// Channel at channelURI.
+func MakeIngressDeployment(b *v1alpha1.Broker, image, channelURI string) *appsv1.Deployment {
+	labels := roleLabels(b.Name, ingressRole)
+	return &appsv1.Deployment{
+		ObjectMeta: objectMeta(b, IngressName(b.Name), labels),
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "ingress",
+						Image: image,
+						Env: []corev1.EnvVar{{
+							Name:  "CHANNEL",
+							Value: channelURI,
+						}},
+						Ports: []corev1.ContainerPort{{
+							Name:          provisioners.PortName,
+							ContainerPort: provisioners.MessageReceiverPort,
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// MakeIngressService creates the Service that the events sent to b are addressed to.
+func MakeIngressService(b *v1alpha1.Broker) *corev1.Service {
+	return makeService(b, IngressName(b.Name), ingressRole)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/system"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MultiTenantIngressName is the name of the ingress Deployment and Service, in the system
+	// namespace, shared by the multi-tenant Brokers.
+	MultiTenantIngressName = "broker-ingress"
+
+	// MultiTenantFilterName is the name of the filter Deployment and Service, in the system
+	// namespace, shared by the multi-tenant Brokers.
+	MultiTenantFilterName = "broker-filter"
+
+	// MultiTenantConfigMapName is the name of the ConfigMap, in the system namespace, holding the
+	// routes of the shared ingress and filter.
+	MultiTenantConfigMapName = "mt-broker-config"
+)
+
+// MakeMultiTenantIngressService creates the Service that the events sent to the multi-tenant
+// Broker b are addressed to. It is an alias of the shared ingress, which recognizes b by the host
+// name of the Service.
+func MakeMultiTenantIngressService(b *v1alpha1.Broker) *corev1.Service {
+	return makeExternalNameService(b, IngressName(b.Name), ingressRole, MultiTenantIngressName)
+}
+
+// MakeMultiTenantFilterService creates the Service that the Channel of the multi-tenant Broker b
+// delivers events to. It is an alias of the shared filter.
+func MakeMultiTenantFilterService(b *v1alpha1.Broker) *corev1.Service {
+	return makeExternalNameService(b, FilterName(b.Name), filterRole, MultiTenantFilterName)
+}
+
+func makeExternalNameService(b *v1alpha1.Broker, name, role, target string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: objectMeta(b, name, roleLabels(b.Name, role)),
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: controller.ServiceHostName(target, system.Namespace),
+		},
+	}
+}
+
+// MakeMultiTenantConfigMap creates the ConfigMap holding the routes of the shared ingress and
+// filter.
+func MakeMultiTenantConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      MultiTenantConfigMapName,
+		},
+		Data: data,
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package resources creates the Kubernetes objects that make up a Broker.
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// BrokerLabelKey is the label that identifies the Broker that an object belongs to.
+	BrokerLabelKey = "eventing.knative.dev/broker"
+
+	// BrokerRoleLabelKey is the label that identifies the part of the Broker that a Deployment or
+	// Service implements.
+	BrokerRoleLabelKey = "eventing.knative.dev/brokerRole"
+
+	ingressRole = "ingress"
+	filterRole  = "filter"
+)
+
+// ChannelName returns the name of the Channel that holds the events of the Broker brokerName.
+func ChannelName(brokerName string) string {
+	return fmt.Sprintf("%s-broker", brokerName)
+}
+
+// IngressName returns the name of the ingress Deployment and Service of the Broker brokerName.
+func IngressName(brokerName string) string {
+	return fmt.Sprintf("%s-broker-ingress", brokerName)
+}
+
+// FilterName returns the name of the filter Deployment, Service and ConfigMap of the Broker
+// brokerName.
+func FilterName(brokerName string) string {
+	return fmt.Sprintf("%s-broker-filter", brokerName)
+}
+
+// Labels returns the labels of every object created for the Broker brokerName.
+func Labels(brokerName string) map[string]string {
+	return map[string]string{
+		BrokerLabelKey: brokerName,
+	}
+}
+
+func roleLabels(brokerName, role string) map[string]string {
+	labels := Labels(brokerName)
+	labels[BrokerRoleLabelKey] = role
+	return labels
+}
+
+func objectMeta(b *v1alpha1.Broker, name string, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: b.Namespace,
+		Name:      name,
+		Labels:    labels,
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(b, v1alpha1.SchemeGroupVersion.WithKind("Broker")),
+		},
+	}
+}
+
+// makeService creates a Service that exposes the Deployment of role on the standard HTTP port.
+func makeService(b *v1alpha1.Broker, name, role string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: objectMeta(b, name, roleLabels(b.Name, role)),
+		Spec: corev1.ServiceSpec{
+			Selector: roleLabels(b.Name, role),
+			Ports: []corev1.ServicePort{{
+				Name:       provisioners.PortName,
+				Port:       provisioners.PortNumber,
+				TargetPort: intstr.FromInt(provisioners.MessageReceiverPort),
+			}},
+		},
+	}
+}
+
+// MakeChannel creates the Channel that holds the events of b.
+func MakeChannel(b *v1alpha1.Broker) *v1alpha1.Channel {
+	c := &v1alpha1.Channel{
+		ObjectMeta: objectMeta(b, ChannelName(b.Name), Labels(b.Name)),
+	}
+	if b.Spec.ChannelTemplate != nil {
+		c.Spec = *b.Spec.ChannelTemplate.DeepCopy()
+	}
+	// A Channel without provisioner gets the default template of its namespace.
+	c.SetDefaults()
+	return c
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channelmigration
+
+import (
+	"context"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/channelmigration/resources"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "channel-migration-controller"
+)
+
+type reconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a ChannelMigration controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Setup a new controller to Reconcile ChannelMigrations.
+	r := &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+	}
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, r))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch ChannelMigration events and enqueue ChannelMigration object key.
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.ChannelMigration{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the objects created by ChannelMigrations, which are not owned by them, and the
+	// migrated Channels and their Subscriptions.
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Channel{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return r.migrationsOf(o.Meta, o.Meta.GetName())
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Subscription{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			sub, ok := o.Object.(*v1alpha1.Subscription)
+			if !ok {
+				return nil
+			}
+			return r.migrationsOf(o.Meta, sub.Spec.Channel.Name)
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// migrationsOf returns the requests of the ChannelMigration that created o, if any, and of the
+// ChannelMigrations of the Channel channelName in the namespace of o.
+func (r *reconciler) migrationsOf(o metav1.Object, channelName string) []reconcile.Request {
+	var requests []reconcile.Request
+	if name, ok := o.GetLabels()[resources.ChannelMigrationLabelKey]; ok {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: name},
+		})
+	}
+	ml := &v1alpha1.ChannelMigrationList{}
+	if err := r.client.List(context.TODO(), &client.ListOptions{Namespace: o.GetNamespace()}, ml); err != nil {
+		return requests
+	}
+	for _, m := range ml.Items {
+		if m.Spec.Channel == channelName {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: m.Namespace, Name: m.Name},
+			})
+		}
+	}
+	return requests
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
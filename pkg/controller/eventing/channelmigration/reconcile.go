@@ -0,0 +1,301 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channelmigration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/channelmigration/resources"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the ChannelMigration
+// resource with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling channel migration %v", request)
+	ctx := context.TODO()
+	migration := &v1alpha1.ChannelMigration{}
+	err := r.client.Get(ctx, request.NamespacedName, migration)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find channel migration %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch ChannelMigration %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the ChannelMigration and then write back any status
+	// updates regardless of whether the reconcile error out.
+	migration = migration.DeepCopy()
+	err = r.reconcile(ctx, migration)
+	if updateStatusErr := r.updateStatus(ctx, migration); updateStatusErr != nil {
+		glog.Warningf("Failed to update channel migration status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+// reconcile moves the subscribers of the migrated Channel of m to its target Channel:
+//
+//  1. it creates the target Channel;
+//  2. it mirrors the events of the migrated Channel to the target Channel, so that the subscribers
+//     moved to the target Channel keep receiving the events sent to the migrated Channel;
+//  3. it copies the Subscriptions of the migrated Channel to the target Channel and, once all the
+//     copies are ready, deletes the original Subscriptions;
+//  4. when m is finalized, it deletes the mirror and the migrated Channel.
+//
+// The subscribers receive every event at least once: the events sent while both a Subscription
+// and its copy exist are delivered twice.
+func (r *reconciler) reconcile(ctx context.Context, m *v1alpha1.ChannelMigration) error {
+	m.Status.InitializeConditions()
+
+	if m.DeletionTimestamp != nil {
+		// The objects created by the ChannelMigration are not owned by it, deleting an
+		// unfinished migration leaves the Subscriptions where they are.
+		return nil
+	}
+
+	if m.Status.IsFinalized() {
+		// The migrated Channel and the mirror are deleted.
+		return nil
+	}
+
+	migrated := &v1alpha1.Channel{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: m.Spec.Channel}, migrated)
+	if errors.IsNotFound(err) {
+		m.Status.MarkNotMirroring("ChannelNotFound", "Channel %s does not exist", m.Spec.Channel)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	target, err := r.reconcileTargetChannel(ctx, m)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the target Channel of channel migration %s/%s: %v", m.Namespace, m.Name, err)
+		m.Status.MarkTargetChannelNotReady("ChannelFailure", "%v", err)
+		return err
+	}
+	if !target.Status.IsReady() || target.Status.Address.Hostname == "" {
+		m.Status.MarkTargetChannelNotReady("ChannelNotReady", "Channel %s is not ready", target.Name)
+	} else {
+		m.Status.MarkTargetChannelReady(target.Status.Address.Hostname)
+	}
+
+	mirror, err := r.reconcileMirror(ctx, m)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the mirror of channel migration %s/%s: %v", m.Namespace, m.Name, err)
+		m.Status.MarkNotMirroring("SubscriptionFailure", "%v", err)
+		return err
+	}
+	if !mirror.Status.IsReady() {
+		m.Status.MarkNotMirroring("SubscriptionNotReady", "Subscription %s is not ready", mirror.Name)
+	} else {
+		m.Status.MarkMirroring()
+	}
+
+	// The Subscriptions are moved once the events of the migrated Channel reach the target
+	// Channel, the moved subscribers would miss them otherwise. The moved Subscriptions are
+	// reconciled again when the target Channel or the mirror change.
+	if !m.Status.GetCondition(v1alpha1.ChannelMigrationConditionTargetChannelReady).IsTrue() ||
+		!m.Status.GetCondition(v1alpha1.ChannelMigrationConditionMirroring).IsTrue() {
+		m.Status.MarkSubscriptionsNotMoved("NotMirroring", "the events of Channel %s are not mirrored to Channel %s yet", m.Spec.Channel, target.Name)
+		return nil
+	}
+
+	if err := r.moveSubscriptions(ctx, m); err != nil {
+		glog.Warningf("Failed to move the Subscriptions of channel migration %s/%s: %v", m.Namespace, m.Name, err)
+		m.Status.MarkSubscriptionsNotMoved("SubscriptionFailure", "%v", err)
+		return err
+	}
+
+	if !m.Spec.Finalize || !m.Status.IsReady() {
+		return nil
+	}
+	return r.finalize(ctx, m, migrated, mirror)
+}
+
+// reconcileTargetChannel creates the target Channel of m if it does not exist yet. The Channel is
+// not updated, as the spec of m cannot change.
+func (r *reconciler) reconcileTargetChannel(ctx context.Context, m *v1alpha1.ChannelMigration) (*v1alpha1.Channel, error) {
+	c := &v1alpha1.Channel{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: resources.TargetChannelName(m.Name)}, c)
+	if errors.IsNotFound(err) {
+		c = resources.MakeTargetChannel(m)
+		err = r.client.Create(ctx, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.Labels[resources.ChannelMigrationLabelKey] != m.Name {
+		return nil, fmt.Errorf("Channel %s was not created by the ChannelMigration", c.Name)
+	}
+	return c, nil
+}
+
+// reconcileMirror creates the Subscription delivering the events of the migrated Channel of m to
+// its target Channel if it does not exist yet.
+func (r *reconciler) reconcileMirror(ctx context.Context, m *v1alpha1.ChannelMigration) (*v1alpha1.Subscription, error) {
+	sub := &v1alpha1.Subscription{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: resources.MirrorSubscriptionName(m.Name)}, sub)
+	if errors.IsNotFound(err) {
+		sub = resources.MakeMirrorSubscription(m)
+		err = r.client.Create(ctx, sub)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sub.Labels[resources.ChannelMigrationLabelKey] != m.Name {
+		return nil, fmt.Errorf("Subscription %s was not created by the ChannelMigration", sub.Name)
+	}
+	return sub, nil
+}
+
+// moveSubscriptions moves the Subscriptions of the migrated Channel of m to its target Channel,
+// and sends the replies to the migrated Channel to the target Channel. The Subscriptions managed
+// by another resource, such as a Trigger or a Sequence, are left to that resource.
+func (r *reconciler) moveSubscriptions(ctx context.Context, m *v1alpha1.ChannelMigration) error {
+	sl := &v1alpha1.SubscriptionList{}
+	opts := &client.ListOptions{
+		Namespace: m.Namespace,
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Subscription",
+			},
+		},
+	}
+	if err := r.client.List(ctx, opts, sl); err != nil {
+		return err
+	}
+
+	var originals []*v1alpha1.Subscription
+	var managed []string
+	for i := range sl.Items {
+		sub := &sl.Items[i]
+		if sub.Labels[resources.ChannelMigrationLabelKey] == m.Name {
+			continue
+		}
+		onMigrated := resources.RefersTo(m, &sub.Spec.Channel)
+		repliesToMigrated := sub.Spec.Reply != nil && resources.RefersTo(m, sub.Spec.Reply.Channel)
+		if !onMigrated && !repliesToMigrated {
+			continue
+		}
+		if owner := metav1.GetControllerOf(sub); owner != nil {
+			managed = append(managed, fmt.Sprintf("%s (%s %s)", sub.Name, owner.Kind, owner.Name))
+			continue
+		}
+		if onMigrated {
+			originals = append(originals, sub)
+			continue
+		}
+		// The Subscriptions of other Channels replying to the migrated Channel.
+		resources.RetargetReply(m, &sub.Spec)
+		if err := r.client.Update(ctx, sub); err != nil {
+			return err
+		}
+	}
+
+	// Every Subscription is copied before any is deleted, and deleted once its copy is ready,
+	// so that the subscribers do not miss the events sent meanwhile.
+	for _, sub := range originals {
+		moved, err := r.reconcileMovedSubscription(ctx, m, sub)
+		if err != nil {
+			return err
+		}
+		if !moved.Status.IsReady() {
+			m.Status.MarkSubscriptionsNotMoved("SubscriptionNotReady", "Subscription %s is not ready", moved.Name)
+			return nil
+		}
+	}
+	for _, sub := range originals {
+		if err := r.client.Delete(ctx, sub); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		m.Status.MovedSubscriptions = append(m.Status.MovedSubscriptions, v1alpha1.MovedSubscription{
+			From: sub.Name,
+			To:   resources.MovedSubscriptionName(sub.Name, m.Name),
+		})
+	}
+
+	if len(managed) > 0 {
+		m.Status.MarkSubscriptionsNotMoved("ManagedSubscriptions", "Subscriptions %s are managed by another resource and are not moved", strings.Join(managed, ", "))
+		return nil
+	}
+	m.Status.MarkSubscriptionsMoved()
+	return nil
+}
+
+// reconcileMovedSubscription creates the copy of sub on the target Channel of m if it does not
+// exist yet.
+func (r *reconciler) reconcileMovedSubscription(ctx context.Context, m *v1alpha1.ChannelMigration, sub *v1alpha1.Subscription) (*v1alpha1.Subscription, error) {
+	moved := &v1alpha1.Subscription{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: resources.MovedSubscriptionName(sub.Name, m.Name)}, moved)
+	if errors.IsNotFound(err) {
+		moved = resources.MakeMovedSubscription(m, sub)
+		err = r.client.Create(ctx, moved)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if moved.Labels[resources.ChannelMigrationLabelKey] != m.Name || moved.Labels[resources.MigratedFromLabelKey] != sub.Name {
+		return nil, fmt.Errorf("Subscription %s was not created by the ChannelMigration", moved.Name)
+	}
+	return moved, nil
+}
+
+// finalize deletes the mirror and the migrated Channel of m. The producers must send their events
+// to the target Channel by then, the events sent to the migrated Channel are lost.
+func (r *reconciler) finalize(ctx context.Context, m *v1alpha1.ChannelMigration, migrated *v1alpha1.Channel, mirror *v1alpha1.Subscription) error {
+	if err := r.client.Delete(ctx, mirror); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err := r.client.Delete(ctx, migrated); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	m.Status.MarkFinalized()
+	return nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, m *v1alpha1.ChannelMigration) error {
+	current := &v1alpha1.ChannelMigration{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: m.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, m.Status) {
+		return nil
+	}
+	current.Status = m.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the ChannelMigration resource.
+	return r.client.Update(ctx, current)
+}
@@ -0,0 +1,550 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channelmigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/channelmigration/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS           = "test-namespace"
+	migrationName    = "test-migration"
+	migratedName     = "test-channel"
+	subscriptionName = "test-subscription"
+
+	targetHostname = "test-migration-channel.test-namespace.svc.cluster.local"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+
+	subscriberURI = "http://subscriber.example.com/"
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "ChannelMigration not found",
+		},
+		{
+			Name: "Error getting ChannelMigration",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.ChannelMigration{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "ChannelMigration being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingMigration(),
+				makeMigratedChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingMigration(),
+			},
+			WantAbsent: []runtime.Object{
+				makeTargetChannel(),
+				makeMirror(),
+			},
+		},
+		{
+			Name: "Migrated Channel not found",
+			InitialState: []runtime.Object{
+				makeMigration(),
+			},
+			WantPresent: []runtime.Object{
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkNotMirroring("ChannelNotFound", "Channel test-channel does not exist")
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeTargetChannel(),
+			},
+		},
+		{
+			Name: "Target Channel and mirror created, not ready yet",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+				makeSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeTargetChannel(),
+				makeMirror(),
+				makeSubscription(),
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelNotReady("ChannelNotReady", "Channel test-migration is not ready")
+					s.MarkNotMirroring("SubscriptionNotReady", "Subscription test-migration-mirror is not ready")
+					s.MarkSubscriptionsNotMoved("NotMirroring", "the events of Channel test-channel are not mirrored to Channel test-migration yet")
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeMovedSubscription(),
+			},
+		},
+		{
+			Name: "Target Channel creation fails",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Channel{}),
+			},
+			WantPresent: []runtime.Object{
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelNotReady("ChannelFailure", testErrorMessage)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeMirror(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Existing Channel was not created by the ChannelMigration",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+				func() *v1alpha1.Channel {
+					c := makeTargetChannel()
+					c.Labels = nil
+					return c
+				}(),
+			},
+			WantPresent: []runtime.Object{
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelNotReady("ChannelFailure", "Channel test-migration was not created by the ChannelMigration")
+				}),
+			},
+			WantErrMsg: "Channel test-migration was not created by the ChannelMigration",
+		},
+		{
+			Name: "Mirror creation fails",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Subscription{}),
+			},
+			WantPresent: []runtime.Object{
+				makeTargetChannel(),
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelNotReady("ChannelNotReady", "Channel test-migration is not ready")
+					s.MarkNotMirroring("SubscriptionFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Subscriptions copied, originals kept until the copies are ready",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+				makeReadyTargetChannel(),
+				makeReadyMirror(),
+				makeSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+				makeMovedSubscription(),
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelReady(targetHostname)
+					s.MarkMirroring()
+					s.MarkSubscriptionsNotMoved("SubscriptionNotReady", "Subscription test-subscription-test-migration is not ready")
+				}),
+			},
+		},
+		{
+			Name: "Originals deleted once the copies are ready",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+				makeReadyTargetChannel(),
+				makeReadyMirror(),
+				makeSubscription(),
+				makeReadyMovedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeMigratedChannel(),
+				makeReadyMirror(),
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelReady(targetHostname)
+					s.MarkMirroring()
+					s.MarkSubscriptionsMoved()
+					s.MovedSubscriptions = []v1alpha1.MovedSubscription{{
+						From: subscriptionName,
+						To:   "test-subscription-test-migration",
+					}}
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Replies to the migrated Channel are sent to the target Channel",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+				makeReadyTargetChannel(),
+				makeReadyMirror(),
+				makeReplyingSubscription(migratedName),
+			},
+			WantPresent: []runtime.Object{
+				makeReplyingSubscription(migrationName),
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelReady(targetHostname)
+					s.MarkMirroring()
+					s.MarkSubscriptionsMoved()
+				}),
+			},
+		},
+		{
+			Name: "Subscriptions managed by another resource are not moved",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+				makeReadyTargetChannel(),
+				makeReadyMirror(),
+				makeManagedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeManagedSubscription(),
+				makeMigrationWithStatus(func(s *v1alpha1.ChannelMigrationStatus) {
+					s.MarkTargetChannelReady(targetHostname)
+					s.MarkMirroring()
+					s.MarkSubscriptionsNotMoved("ManagedSubscriptions", "Subscriptions test-subscription (Trigger test-trigger) are managed by another resource and are not moved")
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeMovedSubscription(),
+			},
+		},
+		{
+			Name: "Managed Subscriptions prevent finalizing",
+			InitialState: []runtime.Object{
+				makeFinalizingMigration(),
+				makeMigratedChannel(),
+				makeReadyTargetChannel(),
+				makeReadyMirror(),
+				makeManagedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeMigratedChannel(),
+				makeReadyMirror(),
+			},
+		},
+		{
+			Name: "Finalizing deletes the mirror and the migrated Channel",
+			InitialState: []runtime.Object{
+				makeFinalizingMigration(),
+				makeMigratedChannel(),
+				makeReadyTargetChannel(),
+				makeReadyMirror(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyTargetChannel(),
+				func() *v1alpha1.ChannelMigration {
+					m := makeFinalizingMigration()
+					m.Status.InitializeConditions()
+					m.Status.MarkTargetChannelReady(targetHostname)
+					m.Status.MarkMirroring()
+					m.Status.MarkSubscriptionsMoved()
+					m.Status.MarkFinalized()
+					return m
+				}(),
+			},
+			WantAbsent: []runtime.Object{
+				makeMigratedChannel(),
+				makeMirror(),
+			},
+		},
+		{
+			Name: "Finalized ChannelMigration is left as is",
+			InitialState: []runtime.Object{
+				makeFinalizedMigration(),
+			},
+			WantPresent: []runtime.Object{
+				makeFinalizedMigration(),
+			},
+			WantAbsent: []runtime.Object{
+				makeMirror(),
+			},
+		},
+		{
+			Name: "Updating ChannelMigration status fails",
+			InitialState: []runtime.Object{
+				makeMigration(),
+				makeMigratedChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.ChannelMigration{}),
+			},
+			WantPresent: []runtime.Object{
+				makeTargetChannel(),
+				makeMirror(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, migrationName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeMigration() *v1alpha1.ChannelMigration {
+	return &v1alpha1.ChannelMigration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ChannelMigration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      migrationName,
+		},
+		Spec: v1alpha1.ChannelMigrationSpec{
+			Channel: migratedName,
+			Provisioner: &corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "ClusterChannelProvisioner",
+				Name:       "kafka",
+			},
+		},
+	}
+}
+
+func makeMigrationWithStatus(f func(*v1alpha1.ChannelMigrationStatus)) *v1alpha1.ChannelMigration {
+	m := makeMigration()
+	m.Status.InitializeConditions()
+	f(&m.Status)
+	return m
+}
+
+func makeDeletingMigration() *v1alpha1.ChannelMigration {
+	m := makeMigrationWithStatus(func(*v1alpha1.ChannelMigrationStatus) {})
+	m.DeletionTimestamp = &deletionTime
+	return m
+}
+
+func makeFinalizingMigration() *v1alpha1.ChannelMigration {
+	m := makeMigration()
+	m.Spec.Finalize = true
+	return m
+}
+
+func makeFinalizedMigration() *v1alpha1.ChannelMigration {
+	m := makeFinalizingMigration()
+	m.Status.InitializeConditions()
+	m.Status.MarkFinalized()
+	return m
+}
+
+func channelReference(name string) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Name:       name,
+	}
+}
+
+func makeMigratedChannel() *v1alpha1.Channel {
+	return &v1alpha1.Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Channel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      migratedName,
+		},
+		Spec: v1alpha1.ChannelSpec{
+			Provisioner: &corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "ClusterChannelProvisioner",
+				Name:       "in-memory",
+			},
+		},
+	}
+}
+
+func makeTargetChannel() *v1alpha1.Channel {
+	c := resources.MakeTargetChannel(makeMigration())
+	c.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+	}
+	return c
+}
+
+func makeReadyTargetChannel() *v1alpha1.Channel {
+	c := makeTargetChannel()
+	c.Status.InitializeConditions()
+	c.Status.MarkProvisioned()
+	c.Status.SetAddress(targetHostname)
+	return c
+}
+
+func makeMirror() *v1alpha1.Subscription {
+	s := resources.MakeMirrorSubscription(makeMigration())
+	s.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+	return s
+}
+
+func makeReady(s *v1alpha1.Subscription) *v1alpha1.Subscription {
+	s.Status.InitializeConditions()
+	s.Status.MarkReferencesResolved()
+	s.Status.MarkChannelReady()
+	return s
+}
+
+func makeReadyMirror() *v1alpha1.Subscription {
+	return makeReady(makeMirror())
+}
+
+func makeSubscription() *v1alpha1.Subscription {
+	return &v1alpha1.Subscription{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Subscription",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      subscriptionName,
+			Labels:    map[string]string{"app": "test"},
+		},
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel: channelReference(migratedName),
+			Subscriber: &v1alpha1.SubscriberSpec{
+				DNSName: &subscriberURI,
+			},
+		},
+	}
+}
+
+func makeMovedSubscription() *v1alpha1.Subscription {
+	s := resources.MakeMovedSubscription(makeMigration(), makeSubscription())
+	s.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+	return s
+}
+
+func makeReadyMovedSubscription() *v1alpha1.Subscription {
+	return makeReady(makeMovedSubscription())
+}
+
+func makeReplyingSubscription(reply string) *v1alpha1.Subscription {
+	s := makeSubscription()
+	s.Spec.Channel = channelReference("other-channel")
+	replyRef := channelReference(reply)
+	s.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: &replyRef}
+	return s
+}
+
+func makeManagedSubscription() *v1alpha1.Subscription {
+	s := makeSubscription()
+	s.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Trigger",
+		Name:       "test-trigger",
+		Controller: &[]bool{true}[0],
+	}}
+	return s
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resources creates the Channel and Subscriptions of a ChannelMigration.
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ChannelMigrationLabelKey is the label that identifies the ChannelMigration that an object
+	// was created by. The objects are not owned by the ChannelMigration, so that they outlive it.
+	ChannelMigrationLabelKey = "eventing.knative.dev/channelMigration"
+
+	// MigratedFromLabelKey is the label of the Subscriptions moved to the target Channel. Its
+	// value is the name of the Subscription of the migrated Channel they replace.
+	MigratedFromLabelKey = "eventing.knative.dev/migratedFrom"
+)
+
+// TargetChannelName returns the name of the target Channel of the ChannelMigration migrationName.
+func TargetChannelName(migrationName string) string {
+	return migrationName
+}
+
+// MirrorSubscriptionName returns the name of the Subscription delivering the events of the
+// migrated Channel to the target Channel of the ChannelMigration migrationName.
+func MirrorSubscriptionName(migrationName string) string {
+	return fmt.Sprintf("%s-mirror", migrationName)
+}
+
+// MovedSubscriptionName returns the name of the Subscription replacing the Subscription
+// subscriptionName on the target Channel of the ChannelMigration migrationName.
+func MovedSubscriptionName(subscriptionName, migrationName string) string {
+	return fmt.Sprintf("%s-%s", subscriptionName, migrationName)
+}
+
+// Labels returns the labels of every object created for the ChannelMigration migrationName.
+func Labels(migrationName string) map[string]string {
+	return map[string]string{
+		ChannelMigrationLabelKey: migrationName,
+	}
+}
+
+// MakeTargetChannel creates the Channel of the new provisioner of m.
+func MakeTargetChannel(m *v1alpha1.ChannelMigration) *v1alpha1.Channel {
+	return &v1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: m.Namespace,
+			Name:      TargetChannelName(m.Name),
+			Labels:    Labels(m.Name),
+		},
+		Spec: v1alpha1.ChannelSpec{
+			Provisioner: m.Spec.Provisioner.DeepCopy(),
+			Arguments:   m.Spec.Arguments.DeepCopy(),
+		},
+	}
+}
+
+// MakeMirrorSubscription creates the Subscription delivering the events of the migrated Channel
+// of m to its target Channel, so that the subscribers moved to the target Channel receive the
+// events sent to the migrated Channel.
+func MakeMirrorSubscription(m *v1alpha1.ChannelMigration) *v1alpha1.Subscription {
+	target := channelReference(TargetChannelName(m.Name))
+	return &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: m.Namespace,
+			Name:      MirrorSubscriptionName(m.Name),
+			Labels:    Labels(m.Name),
+		},
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel: channelReference(m.Spec.Channel),
+			Subscriber: &v1alpha1.SubscriberSpec{
+				Ref: &target,
+			},
+		},
+	}
+}
+
+// MakeMovedSubscription creates the copy of sub on the target Channel of m. Its replies to the
+// migrated Channel are sent to the target Channel.
+func MakeMovedSubscription(m *v1alpha1.ChannelMigration, sub *v1alpha1.Subscription) *v1alpha1.Subscription {
+	moved := &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   sub.Namespace,
+			Name:        MovedSubscriptionName(sub.Name, m.Name),
+			Labels:      make(map[string]string),
+			Annotations: sub.DeepCopy().Annotations,
+		},
+		Spec: *sub.Spec.DeepCopy(),
+	}
+	for k, v := range sub.Labels {
+		moved.Labels[k] = v
+	}
+	moved.Labels[ChannelMigrationLabelKey] = m.Name
+	moved.Labels[MigratedFromLabelKey] = sub.Name
+	moved.Spec.Channel = channelReference(TargetChannelName(m.Name))
+	RetargetReply(m, &moved.Spec)
+	return moved
+}
+
+// RefersTo returns true if ref is a reference to the migrated Channel of m.
+func RefersTo(m *v1alpha1.ChannelMigration, ref *corev1.ObjectReference) bool {
+	return ref != nil && ref.Kind == "Channel" && ref.APIVersion == v1alpha1.SchemeGroupVersion.String() && ref.Name == m.Spec.Channel
+}
+
+// RetargetReply sends the replies of spec to the target Channel of m, instead of its migrated
+// Channel. It returns true if spec was changed.
+func RetargetReply(m *v1alpha1.ChannelMigration, spec *v1alpha1.SubscriptionSpec) bool {
+	if spec.Reply == nil || !RefersTo(m, spec.Reply.Channel) {
+		return false
+	}
+	spec.Reply.Channel.Name = TargetChannelName(m.Name)
+	return true
+}
+
+func channelReference(name string) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Name:       name,
+	}
+}
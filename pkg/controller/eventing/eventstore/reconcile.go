@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventstore implements the controller subscribing the event store to the Channels
+// annotated for archival.
+package eventstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/eventstore"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/system"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ArchiveAnnotation opts a Channel in to having its events archived by the event store.
+	ArchiveAnnotation = "eventing.knative.dev/archive"
+
+	// ArchiveEnabledValue is the value of ArchiveAnnotation that enables the archival.
+	ArchiveEnabledValue = "true"
+
+	// ArchivedChannelLabelKey is the label set on the archive Subscriptions, to the name of their
+	// Channel.
+	ArchivedChannelLabelKey = "eventing.knative.dev/archivedChannel"
+
+	// ServiceName is the name of the Service of the event store, in the system namespace.
+	ServiceName = "eventstore"
+
+	subscriptionCreated = "ArchiveSubscriptionCreated"
+	subscriptionUpdated = "ArchiveSubscriptionUpdated"
+	subscriptionDeleted = "ArchiveSubscriptionDeleted"
+)
+
+// Reconcile creates the archive Subscription of the Channel in request if it is annotated for
+// archival, and deletes it otherwise.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling channel %v", request)
+	ctx := context.TODO()
+	c := &v1alpha1.Channel{}
+	err := r.client.Get(ctx, request.NamespacedName, c)
+
+	if errors.IsNotFound(err) {
+		// The archive Subscription is deleted along with the Channel that owns it.
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch Channel %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.reconcileSubscription(ctx, c)
+}
+
+// reconcileSubscription makes the archive Subscription of c match the archive annotation of c.
+// Subscriptions of that name that c does not control are left alone.
+func (r *reconciler) reconcileSubscription(ctx context.Context, c *v1alpha1.Channel) error {
+	archived := c.Annotations[ArchiveAnnotation] == ArchiveEnabledValue && c.DeletionTimestamp == nil
+
+	sub := &v1alpha1.Subscription{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: SubscriptionName(c.Name)}, sub)
+	if errors.IsNotFound(err) {
+		if !archived {
+			return nil
+		}
+		sub = newSubscription(c)
+		if err := r.client.Create(ctx, sub); err != nil {
+			glog.Warningf("Failed to create the archive subscription of channel %s/%s: %v", c.Namespace, c.Name, err)
+			return err
+		}
+		r.recorder.Eventf(c, corev1.EventTypeNormal, subscriptionCreated, "Archive Subscription %q created", sub.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(sub, c) {
+		glog.Warningf("Subscription %s/%s is not the archive subscription of channel %s", sub.Namespace, sub.Name, c.Name)
+		return nil
+	}
+
+	if !archived {
+		if err := r.client.Delete(ctx, sub); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.recorder.Eventf(c, corev1.EventTypeNormal, subscriptionDeleted, "Archive Subscription %q deleted", sub.Name)
+		return nil
+	}
+
+	expected := newSubscription(c)
+	if equality.Semantic.DeepEqual(sub.Spec.Channel, expected.Spec.Channel) &&
+		equality.Semantic.DeepEqual(sub.Spec.Subscriber, expected.Spec.Subscriber) &&
+		sub.Spec.Reply == nil {
+		return nil
+	}
+	sub.Spec = expected.Spec
+	if err := r.client.Update(ctx, sub); err != nil {
+		return err
+	}
+	r.recorder.Eventf(c, corev1.EventTypeNormal, subscriptionUpdated, "Archive Subscription %q updated", sub.Name)
+	return nil
+}
+
+// SubscriptionName returns the name of the Subscription delivering the events of the Channel
+// channelName to the event store.
+func SubscriptionName(channelName string) string {
+	return fmt.Sprintf("%s-archive", channelName)
+}
+
+// SubscriberURI returns the URI the events of c are delivered to in the event store.
+func SubscriberURI(c *v1alpha1.Channel) string {
+	return fmt.Sprintf("http://%s%s", controller.ServiceHostName(ServiceName, system.Namespace),
+		eventstore.ChannelPath(provisioners.ChannelReference{Namespace: c.Namespace, Name: c.Name}))
+}
+
+func newSubscription(c *v1alpha1.Channel) *v1alpha1.Subscription {
+	uri := SubscriberURI(c)
+	return &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.Namespace,
+			Name:      SubscriptionName(c.Name),
+			Labels: map[string]string{
+				ArchivedChannelLabelKey: c.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(c, v1alpha1.SchemeGroupVersion.WithKind("Channel")),
+			},
+		},
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Channel",
+				Name:       c.Name,
+			},
+			Subscriber: &v1alpha1.SubscriberSpec{
+				DNSName: &uri,
+			},
+		},
+	}
+}
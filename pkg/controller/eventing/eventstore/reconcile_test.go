@@ -0,0 +1,281 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS      = "test-namespace"
+	channelName = "test-channel"
+	channelUID  = "test-uid"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Channel not found",
+		},
+		{
+			Name: "Error getting Channel",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Channel{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Channel not annotated",
+			InitialState: []runtime.Object{
+				makeChannel(nil),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Subscription created",
+			InitialState: []runtime.Object{
+				makeArchivedChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Subscription up to date",
+			InitialState: []runtime.Object{
+				makeArchivedChannel(),
+				makeSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Subscription drifted",
+			InitialState: []runtime.Object{
+				makeArchivedChannel(),
+				makeDriftedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Annotation removed",
+			InitialState: []runtime.Object{
+				makeChannel(map[string]string{ArchiveAnnotation: "false"}),
+				makeSubscription(),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Channel being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingChannel(),
+				makeSubscription(),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Subscription of the user",
+			InitialState: []runtime.Object{
+				makeChannel(nil),
+				makeUserSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeUserSubscription(),
+			},
+		},
+		{
+			Name: "Error getting Subscription",
+			InitialState: []runtime.Object{
+				makeArchivedChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Subscription{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Subscription creation fails",
+			InitialState: []runtime.Object{
+				makeArchivedChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Subscription{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Subscription update fails",
+			InitialState: []runtime.Object{
+				makeArchivedChannel(),
+				makeDriftedSubscription(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.Subscription{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, channelName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func TestSubscriberURI(t *testing.T) {
+	want := "http://eventstore.knative-eventing.svc.cluster.local/test-namespace/test-channel"
+	if got := SubscriberURI(makeArchivedChannel()); got != want {
+		t.Errorf("Unexpected subscriber URI. Expected %q. Actual %q", want, got)
+	}
+}
+
+func makeChannel(annotations map[string]string) *v1alpha1.Channel {
+	return &v1alpha1.Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Channel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   testNS,
+			Name:        channelName,
+			UID:         channelUID,
+			Annotations: annotations,
+		},
+	}
+}
+
+func makeArchivedChannel() *v1alpha1.Channel {
+	return makeChannel(map[string]string{ArchiveAnnotation: ArchiveEnabledValue})
+}
+
+func makeDeletingChannel() *v1alpha1.Channel {
+	c := makeArchivedChannel()
+	c.DeletionTimestamp = &deletionTime
+	return c
+}
+
+func makeSubscription() *v1alpha1.Subscription {
+	s := newSubscription(makeArchivedChannel())
+	s.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+	return s
+}
+
+func makeDriftedSubscription() *v1alpha1.Subscription {
+	s := makeSubscription()
+	uri := "http://example.com/"
+	s.Spec.Subscriber.DNSName = &uri
+	return s
+}
+
+// makeUserSubscription returns a Subscription with the name of the archive Subscription, created
+// by the user rather than by the controller.
+func makeUserSubscription() *v1alpha1.Subscription {
+	s := makeDriftedSubscription()
+	s.Labels = nil
+	s.OwnerReferences = nil
+	return s
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners/conformance"
+	"github.com/knative/eventing/pkg/sidecar/configmap"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"github.com/knative/eventing/pkg/sidecar/swappable"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// inMemoryProvisioner runs the in-memory provisioner in-process: its reconciler against a fake
+// client, and its dispatcher behind a test server, configured from the ConfigMap the reconciler
+// writes.
+type inMemoryProvisioner struct {
+	// mu serializes the reconciliations, as the controller does.
+	mu      sync.Mutex
+	client  client.Client
+	r       *reconciler
+	handler *swappable.Handler
+	server  *httptest.Server
+}
+
+var _ conformance.Provisioner = (*inMemoryProvisioner)(nil)
+
+func newInMemoryProvisioner(t *testing.T) *inMemoryProvisioner {
+	handler, err := swappable.NewEmptyHandler(zap.NewNop())
+	if err != nil {
+		t.Fatalf("Unable to create the dispatcher: %v", err)
+	}
+	c := fake.NewFakeClient()
+	return &inMemoryProvisioner{
+		client: c,
+		r: &reconciler{
+			client:       c,
+			recorder:     record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName}),
+			logger:       zap.NewNop(),
+			configMapKey: types.NamespacedName{Namespace: cmNamespace, Name: cmName},
+		},
+		handler: handler,
+		server:  httptest.NewServer(handler),
+	}
+}
+
+func (p *inMemoryProvisioner) Apply(ctx context.Context, c *eventingv1alpha1.Channel) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := &eventingv1alpha1.Channel{}
+	err := p.client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.Name}, current)
+	if errors.IsNotFound(err) {
+		err = p.client.Create(ctx, c.DeepCopy())
+	} else if err == nil {
+		current.Spec = c.Spec
+		err = p.client.Update(ctx, current)
+	}
+	if err != nil {
+		return err
+	}
+	return p.reconcile(c)
+}
+
+func (p *inMemoryProvisioner) Get(ctx context.Context, namespace, name string) (*eventingv1alpha1.Channel, error) {
+	c := &eventingv1alpha1.Channel{}
+	err := p.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, c)
+	return c, err
+}
+
+// Delete marks c deleted and reconciles it, then removes it once its finalizer is removed, as
+// the API server would.
+func (p *inMemoryProvisioner) Delete(ctx context.Context, c *eventingv1alpha1.Channel) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := &eventingv1alpha1.Channel{}
+	err := p.client.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.Name}, current)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	now := metav1.NewTime(time.Now())
+	current.DeletionTimestamp = &now
+	if err := p.client.Update(ctx, current); err != nil {
+		return err
+	}
+	if err := p.reconcile(current); err != nil {
+		return err
+	}
+	current, err = p.Get(ctx, c.Namespace, c.Name)
+	if err != nil {
+		return err
+	}
+	if len(current.Finalizers) > 0 {
+		return fmt.Errorf("the finalizers %v were not removed", current.Finalizers)
+	}
+	return p.client.Delete(ctx, current)
+}
+
+func (p *inMemoryProvisioner) Deprovisioned(ctx context.Context, c *eventingv1alpha1.Channel) error {
+	if _, err := p.Get(ctx, c.Namespace, c.Name); !errors.IsNotFound(err) {
+		return fmt.Errorf("the Channel still exists: %v", err)
+	}
+	config, err := p.config(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cc := range config.ChannelConfigs {
+		if cc.Namespace == c.Namespace && cc.Name == c.Name {
+			return fmt.Errorf("the dispatcher config still has the Channel")
+		}
+	}
+	return nil
+}
+
+// Client sends every request to the dispatcher, rewriting its Host header like the VirtualService
+// of the Channel does in the mesh.
+func (p *inMemoryProvisioner) Client() *http.Client {
+	return &http.Client{
+		Transport: meshTransport{
+			client: p.client,
+			next: &http.Transport{
+				Dial: func(network, _ string) (net.Conn, error) {
+					return net.Dial(network, p.server.Listener.Addr().String())
+				},
+			},
+		},
+	}
+}
+
+// meshTransport routes requests through the VirtualServices of their host.
+type meshTransport struct {
+	client client.Client
+	next   http.RoundTripper
+}
+
+func (t meshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	chunks := strings.Split(req.URL.Hostname(), ".")
+	if len(chunks) < 2 {
+		return nil, fmt.Errorf("bad host %q", req.URL.Host)
+	}
+	vsl := &istiov1alpha3.VirtualServiceList{}
+	opts := &client.ListOptions{
+		Namespace: chunks[1],
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: istiov1alpha3.SchemeGroupVersion.String(),
+				Kind:       "VirtualService",
+			},
+		},
+	}
+	if err := t.client.List(req.Context(), opts, vsl); err != nil {
+		return nil, err
+	}
+	for _, vs := range vsl.Items {
+		for _, h := range vs.Spec.Hosts {
+			if h == req.URL.Hostname() && len(vs.Spec.Http) > 0 && vs.Spec.Http[0].Rewrite != nil {
+				r := new(http.Request)
+				*r = *req
+				r.Host = vs.Spec.Http[0].Rewrite.Authority
+				return t.next.RoundTrip(r)
+			}
+		}
+	}
+	return nil, fmt.Errorf("no VirtualService routes %q", req.URL.Host)
+}
+
+// reconcile reconciles c and loads the ConfigMap written by the reconciler in the dispatcher.
+func (p *inMemoryProvisioner) reconcile(c *eventingv1alpha1.Channel) error {
+	ctx := context.TODO()
+	if _, err := p.r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: c.Namespace, Name: c.Name}}); err != nil {
+		return err
+	}
+	config, err := p.config(ctx)
+	if err != nil {
+		return err
+	}
+	return p.handler.UpdateConfig(config)
+}
+
+func (p *inMemoryProvisioner) config(ctx context.Context) (*multichannelfanout.Config, error) {
+	cm := &corev1.ConfigMap{}
+	if err := p.client.Get(ctx, p.r.configMapKey, cm); err != nil {
+		return nil, err
+	}
+	return configmap.NewFanoutConfig(zap.NewNop(), cm.Data)
+}
+
+func TestConformance(t *testing.T) {
+	p := newInMemoryProvisioner(t)
+	defer p.server.Close()
+	s := &conformance.Suite{
+		Provisioner: p,
+		Namespace:   cNamespace,
+		Ref: &corev1.ObjectReference{
+			Name: ccpName,
+		},
+		Timeout:  10 * time.Second,
+		Interval: 10 * time.Millisecond,
+	}
+	s.Run(t)
+}
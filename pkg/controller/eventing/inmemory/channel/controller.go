@@ -17,7 +17,12 @@ limitations under the License.
 package channel
 
 import (
+	"fmt"
+
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	util "github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/sidecar/sharding"
 	"github.com/knative/eventing/pkg/system"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"go.uber.org/zap"
@@ -51,13 +56,30 @@ var (
 func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Controller, error) {
 	// Setup a new controller to Reconcile Channels that belong to this Cluster Provisioner
 	// (in-memory channels).
+	shards, err := sharding.ShardsFromEnv()
+	if err != nil {
+		logger.Error("Invalid number of dispatcher shards.", zap.Error(err))
+		return nil, err
+	}
+	scope, err := util.DispatcherScopeFromEnv()
+	if err != nil {
+		logger.Error("Invalid dispatcher scope.", zap.Error(err))
+		return nil, err
+	}
+	if scope == util.NamespaceDispatcherScope && shards > 1 {
+		err := fmt.Errorf("%s cannot be set with %s=%s", sharding.ShardsEnv, util.DispatcherScopeEnv, scope)
+		logger.Error("Invalid dispatcher scope.", zap.Error(err))
+		return nil, err
+	}
 	r := &reconciler{
 		configMapKey: defaultConfigMapKey,
 		recorder:     mgr.GetRecorder(controllerAgentName),
 		logger:       logger,
+		shards:       shards,
+		scope:        scope,
 	}
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: r,
+		Reconciler: metrics.InstrumentReconciler(controllerAgentName, r),
 	})
 	if err != nil {
 		logger.Error("Unable to create controller.", zap.Error(err))
@@ -91,5 +113,10 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		return nil, err
 	}
 
+	// Watch the AuthorizationPolicies of Channels, when Istio security is installed.
+	if err := util.WatchAuthorizationPolicies(c); err != nil {
+		logger.Warn("Not watching AuthorizationPolicies.", zap.Error(err))
+	}
+
 	return c, nil
 }
@@ -0,0 +1,156 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	ccpcontroller "github.com/knative/eventing/pkg/controller/eventing/inmemory/clusterchannelprovisioner"
+	util "github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/system"
+)
+
+const (
+	// dispatcherName is the name of the dispatcher StatefulSet in the knative-eventing namespace.
+	// With util.NamespaceDispatcherScope, its pod template is the template of the dispatcher
+	// Deployments of the namespaces, which are named after it like their ServiceAccount and
+	// RoleBinding.
+	dispatcherName = "in-memory-channel-dispatcher"
+
+	// configMapNamespaceFlag is the flag of the dispatcher selecting the namespace of its ConfigMap.
+	configMapNamespaceFlag = "--config_map_namespace="
+)
+
+// reconcileNamespaceDispatcher creates or updates the dispatcher of the namespace of c, and the
+// Service routing to it, from the pod template of the dispatcher of the knative-eventing namespace.
+func (r *reconciler) reconcileNamespaceDispatcher(ctx context.Context, c *eventingv1alpha1.Channel) (*corev1.Service, error) {
+	template := &appsv1.StatefulSet{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: system.Namespace, Name: dispatcherName}, template); err != nil {
+		return nil, fmt.Errorf("unable to get the dispatcher template: %v", err)
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: c.Namespace, Name: dispatcherName}}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: sa.Namespace, Name: sa.Name}, &corev1.ServiceAccount{}); errors.IsNotFound(err) {
+		err = r.client.Create(ctx, sa)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	rb := newDispatcherRoleBinding(c.Namespace)
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: rb.Namespace, Name: rb.Name}, &rbacv1.RoleBinding{}); errors.IsNotFound(err) {
+		err = r.client.Create(ctx, rb)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	d := newDispatcherDeployment(template, c.Namespace)
+	current := &appsv1.Deployment{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		err = r.client.Create(ctx, d)
+	} else if err == nil && !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		err = r.client.Update(ctx, current)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return util.CreateNamespaceDispatcherService(ctx, r.client, c)
+}
+
+// deleteNamespaceDispatcher deletes the dispatcher of namespace and its Service, once the namespace
+// has no in-memory Channel left. Its ServiceAccount and RoleBinding are kept.
+func (r *reconciler) deleteNamespaceDispatcher(ctx context.Context, namespace string) error {
+	for _, o := range []runtime.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: dispatcherName}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: dispatcherName}},
+	} {
+		if err := r.client.Delete(ctx, o); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDispatcherRoleBinding creates the RoleBinding granting the ClusterRole of the dispatcher to
+// the ServiceAccount of the dispatcher of namespace.
+func newDispatcherRoleBinding(namespace string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      dispatcherName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     dispatcherName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Namespace: namespace,
+			Name:      dispatcherName,
+		}},
+	}
+}
+
+// newDispatcherDeployment creates the dispatcher Deployment of namespace from the pod template of
+// the dispatcher StatefulSet template. It reads the ConfigMap of namespace, and runs as the
+// ServiceAccount of namespace.
+func newDispatcherDeployment(template *appsv1.StatefulSet, namespace string) *appsv1.Deployment {
+	replicas := int32(1)
+	podTemplate := *template.Spec.Template.DeepCopy()
+	podTemplate.Spec.ServiceAccountName = dispatcherName
+	for i := range podTemplate.Spec.Containers {
+		args := podTemplate.Spec.Containers[i].Args
+		for j, arg := range args {
+			if strings.HasPrefix(arg, configMapNamespaceFlag) {
+				args[j] = configMapNamespaceFlag + namespace
+			}
+		}
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      dispatcherName,
+			Labels:    util.DispatcherLabels(ccpcontroller.Name),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: template.Spec.Selector.DeepCopy(),
+			Template: podTemplate,
+		},
+	}
+}
@@ -35,6 +35,8 @@ import (
 	"github.com/knative/eventing/pkg/sidecar/configmap"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"github.com/knative/eventing/pkg/sidecar/sharding"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 )
 
 const (
@@ -47,6 +49,12 @@ type reconciler struct {
 	logger   *zap.Logger
 
 	configMapKey client.ObjectKey
+	// shards is the number of replicas of the dispatcher the channels are spread across.
+	shards int
+	// scope is where the dispatchers of the channels run. With util.NamespaceDispatcherScope, each
+	// namespace has its own dispatcher and ConfigMap, named like those of the knative-eventing
+	// namespace.
+	scope util.DispatcherScope
 }
 
 // Verify the struct implements reconcile.Reconciler
@@ -59,7 +67,7 @@ func (r *reconciler) InjectClient(c client.Client) error {
 
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	// TODO: use this to store the logger and set a deadline
-	ctx := context.TODO()
+	ctx := util.WithEventRecorder(context.TODO(), r.recorder)
 	logger := r.logger.With(zap.Any("request", request))
 
 	c := &eventingv1alpha1.Channel{}
@@ -123,14 +131,25 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 	// 3. The configuration of all Channel subscriptions.
 
 	// We always need to sync the Channel config, so do it first.
-	if err := r.syncChannelConfig(ctx); err != nil {
+	if err := r.syncChannelConfig(ctx, c.Namespace); err != nil {
 		logger.Info("Error updating syncing the Channel config", zap.Error(err))
 		return err
 	}
 
 	if c.DeletionTimestamp != nil {
 		// K8s garbage collection will delete the K8s service and VirtualService for this channel.
-		// We use a finalizer to ensure the channel config has been synced.
+		// We use a finalizer to ensure the channel config has been synced, and that the
+		// AuthorizationPolicy, which is not garbage collected, is deleted.
+		if err := util.DeleteAuthorizationPolicy(ctx, r.client, c); err != nil {
+			logger.Info("Error deleting the AuthorizationPolicy of the Channel", zap.Error(err))
+			return err
+		}
+		if r.scope == util.NamespaceDispatcherScope {
+			if err := r.deleteUnusedDispatcher(ctx, c); err != nil {
+				logger.Info("Error deleting the dispatcher of the namespace", zap.Error(err))
+				return err
+			}
+		}
 		util.RemoveFinalizer(c, finalizerName)
 		return nil
 	}
@@ -150,7 +169,7 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 
 	c.Status.SetAddress(controller.ServiceHostName(svc.Name, svc.Namespace))
 
-	virtualService, err := util.CreateVirtualService(ctx, r.client, c)
+	virtualService, err := r.createVirtualService(ctx, c)
 
 	if err != nil {
 		logger.Info("Error creating the Virtual Service for the Channel", zap.Error(err))
@@ -163,22 +182,74 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 		logger.Warn("VirtualService not owned by Channel", zap.Any("channel", c), zap.Any("virtualService", virtualService))
 	}
 
+	if _, err := util.CreateAuthorizationPolicy(ctx, r.client, c); err != nil {
+		logger.Info("Error creating the AuthorizationPolicy for the Channel", zap.Error(err))
+		return err
+	}
+
 	c.Status.MarkProvisioned()
 	return nil
 }
 
-func (r *reconciler) syncChannelConfig(ctx context.Context) error {
-	channels, err := r.listAllChannels(ctx)
+// createVirtualService creates the VirtualService of the Channel, routing its events to the
+// dispatcher of its namespace with util.NamespaceDispatcherScope, or to the dispatcher replica of
+// its shard when the dispatcher is sharded.
+func (r *reconciler) createVirtualService(ctx context.Context, c *eventingv1alpha1.Channel) (*istiov1alpha3.VirtualService, error) {
+	if r.scope == util.NamespaceDispatcherScope {
+		if _, err := r.reconcileNamespaceDispatcher(ctx, c); err != nil {
+			return nil, err
+		}
+		return util.CreateVirtualServiceWithDestination(ctx, r.client, c, util.NamespaceDispatcherHost(c))
+	}
+	if r.shards <= 1 {
+		return util.CreateVirtualService(ctx, r.client, c)
+	}
+	svc, err := util.CreateDispatcherShardService(ctx, r.client, c, sharding.Shard(c.Namespace, c.Name, r.shards))
+	if err != nil {
+		return nil, err
+	}
+	return util.CreateVirtualServiceWithDestination(ctx, r.client, c, controller.ServiceHostName(svc.Name, svc.Namespace))
+}
+
+// syncChannelConfig writes the config of the dispatcher of the channels of namespace: the
+// dispatcher of the namespace with util.NamespaceDispatcherScope, or the dispatcher of every
+// namespace.
+func (r *reconciler) syncChannelConfig(ctx context.Context, namespace string) error {
+	configMapKey := r.configMapKey
+	if r.scope == util.NamespaceDispatcherScope {
+		configMapKey = client.ObjectKey{Namespace: namespace, Name: r.configMapKey.Name}
+	} else {
+		namespace = ""
+	}
+	channels, err := r.listAllChannels(ctx, namespace)
 	if err != nil {
 		r.logger.Info("Unable to list channels", zap.Error(err))
 		return err
 	}
 	config := multiChannelFanoutConfig(channels)
-	return r.writeConfigMap(ctx, config)
+	if r.shards > 1 {
+		config.Shards = r.shards
+	}
+	return r.writeConfigMap(ctx, configMapKey, config)
+}
+
+// deleteUnusedDispatcher deletes the dispatcher of the namespace of c, which is being deleted, if
+// no other in-memory Channel of the namespace remains.
+func (r *reconciler) deleteUnusedDispatcher(ctx context.Context, c *eventingv1alpha1.Channel) error {
+	channels, err := r.listAllChannels(ctx, c.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, other := range channels {
+		if other.Name != c.Name && other.DeletionTimestamp == nil {
+			return nil
+		}
+	}
+	return r.deleteNamespaceDispatcher(ctx, c.Namespace)
 }
 
-func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfanout.Config) error {
-	logger := r.logger.With(zap.Any("configMap", r.configMapKey))
+func (r *reconciler) writeConfigMap(ctx context.Context, configMapKey client.ObjectKey, config *multichannelfanout.Config) error {
+	logger := r.logger.With(zap.Any("configMap", configMapKey))
 
 	updated, err := configmap.SerializeConfig(*config)
 	if err != nil {
@@ -187,9 +258,9 @@ func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfan
 	}
 
 	cm := &corev1.ConfigMap{}
-	err = r.client.Get(ctx, r.configMapKey, cm)
+	err = r.client.Get(ctx, configMapKey, cm)
 	if errors.IsNotFound(err) {
-		cm = r.createNewConfigMap(updated)
+		cm = createNewConfigMap(configMapKey, updated)
 		err = r.client.Create(ctx, cm)
 	}
 	if err != nil {
@@ -206,11 +277,11 @@ func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfan
 	return r.client.Update(ctx, cm)
 }
 
-func (r *reconciler) createNewConfigMap(data map[string]string) *corev1.ConfigMap {
+func createNewConfigMap(configMapKey client.ObjectKey, data map[string]string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.configMapKey.Namespace,
-			Name:      r.configMapKey.Name,
+			Namespace: configMapKey.Namespace,
+			Name:      configMapKey.Name,
 		},
 		Data: data,
 	}
@@ -219,6 +290,10 @@ func (r *reconciler) createNewConfigMap(data map[string]string) *corev1.ConfigMa
 func multiChannelFanoutConfig(channels []eventingv1alpha1.Channel) *multichannelfanout.Config {
 	cc := make([]multichannelfanout.ChannelConfig, 0)
 	for _, c := range channels {
+		// The Channels being deleted stop accepting events before their finalizer is removed.
+		if c.DeletionTimestamp != nil {
+			continue
+		}
 		channelConfig := multichannelfanout.ChannelConfig{
 			Namespace: c.Namespace,
 			Name:      c.Name,
@@ -235,10 +310,12 @@ func multiChannelFanoutConfig(channels []eventingv1alpha1.Channel) *multichannel
 	}
 }
 
-func (r *reconciler) listAllChannels(ctx context.Context) ([]eventingv1alpha1.Channel, error) {
+// listAllChannels lists the in-memory Channels of namespace, or of every namespace if it is empty.
+func (r *reconciler) listAllChannels(ctx context.Context, namespace string) ([]eventingv1alpha1.Channel, error) {
 	channels := make([]eventingv1alpha1.Channel, 0)
 
 	opts := &client.ListOptions{
+		Namespace: namespace,
 		// TODO this is here because the fake client needs it. Remove this when it's no longer
 		// needed.
 		Raw: &metav1.ListOptions{
@@ -26,17 +26,23 @@ import (
 	"github.com/google/go-cmp/cmp"
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
 	util "github.com/knative/eventing/pkg/provisioners"
 	"github.com/knative/eventing/pkg/sidecar/configmap"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"github.com/knative/eventing/pkg/sidecar/sharding"
+	"github.com/knative/eventing/pkg/system"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -180,7 +186,10 @@ func init() {
 	// Add types to scheme.
 	eventingv1alpha1.AddToScheme(scheme.Scheme)
 	corev1.AddToScheme(scheme.Scheme)
+	appsv1.AddToScheme(scheme.Scheme)
+	rbacv1.AddToScheme(scheme.Scheme)
 	istiov1alpha3.AddToScheme(scheme.Scheme)
+	securityv1beta1.AddToScheme(scheme.Scheme)
 }
 
 func TestInjectClient(t *testing.T) {
@@ -258,6 +267,19 @@ func TestReconcile(t *testing.T) {
 				makeDeletingChannelWithoutFinalizer(),
 			},
 		},
+		{
+			Name: "Channel deleted - AuthorizationPolicy deleted",
+			InitialState: []runtime.Object{
+				makeDeletingChannel(),
+				makeAuthorizationPolicy(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingChannelWithoutFinalizer(),
+			},
+			WantAbsent: []runtime.Object{
+				makeAuthorizationPolicy(),
+			},
+		},
 		{
 			Name: "Channel config sync fails - can't list Channels",
 			InitialState: []runtime.Object{
@@ -495,6 +517,135 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileSharded(t *testing.T) {
+	const shards = 4
+	shard := sharding.Shard(cNamespace, cName, shards)
+	tc := controllertesting.TestCase{
+		Name: "Channel reconcile successful - routed to the dispatcher of its shard",
+		InitialState: []runtime.Object{
+			makeChannel(),
+			makeConfigMap(),
+		},
+		Mocks: controllertesting.Mocks{
+			MockLists: (&paginatedChannelsListStruct{channels: channels}).MockLists(),
+			MockUpdates: verifyConfigMapData(multichannelfanout.Config{
+				ChannelConfigs: channelsConfig.ChannelConfigs,
+				Shards:         shards,
+			}),
+		},
+		WantPresent: []runtime.Object{
+			makeReadyChannel(),
+			makeK8sService(),
+			makeDispatcherShardService(shard),
+			makeShardedVirtualService(shard),
+			makeConfigMapWithVerifyConfigMapData(),
+		},
+		ReconcileKey: fmt.Sprintf("/%s", cName),
+		IgnoreTimes:  true,
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	c := tc.GetClient()
+	r := &reconciler{
+		client:   c,
+		recorder: recorder,
+		logger:   zap.NewNop(),
+		configMapKey: types.NamespacedName{
+			Namespace: cmNamespace,
+			Name:      cmName,
+		},
+		shards: shards,
+	}
+	t.Run(tc.Name, tc.Runner(t, r, c))
+}
+
+func TestReconcileNamespaceScope(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Channel reconcile successful - routed to the dispatcher of its namespace",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeDispatcherTemplate(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyChannel(),
+				makeK8sService(),
+				makeNamespaceVirtualService(),
+				makeNamespaceDispatcherService(),
+				makeNamespaceDispatcherDeployment(),
+				makeNamespaceDispatcherServiceAccount(),
+				makeNamespaceDispatcherRoleBinding(),
+			},
+		},
+		{
+			Name: "Channel reconcile - dispatcher template not found",
+			InitialState: []runtime.Object{
+				makeChannel(),
+			},
+			WantAbsent: []runtime.Object{
+				makeNamespaceDispatcherDeployment(),
+			},
+			WantErrMsg: `unable to get the dispatcher template: statefulsets.apps "in-memory-channel-dispatcher" not found`,
+		},
+		{
+			Name: "Channel reconcile - outdated dispatcher updated",
+			InitialState: []runtime.Object{
+				makeChannel(),
+				makeDispatcherTemplate(),
+				makeOutdatedNamespaceDispatcherDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeNamespaceDispatcherDeployment(),
+			},
+		},
+		{
+			Name: "Channel deleted - dispatcher of the namespace deleted",
+			InitialState: []runtime.Object{
+				makeDeletingChannel(),
+				makeNamespaceDispatcherService(),
+				makeNamespaceDispatcherDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingChannelWithoutFinalizer(),
+			},
+			WantAbsent: []runtime.Object{
+				makeNamespaceDispatcherService(),
+				makeNamespaceDispatcherDeployment(),
+			},
+		},
+		{
+			Name: "Channel deleted - dispatcher of the namespace kept for the other Channels",
+			InitialState: []runtime.Object{
+				makeDeletingChannel(),
+				makeChannelNamed("other-channel"),
+				makeNamespaceDispatcherService(),
+				makeNamespaceDispatcherDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingChannelWithoutFinalizer(),
+				makeNamespaceDispatcherService(),
+				makeNamespaceDispatcherDeployment(),
+			},
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+			logger:   zap.NewNop(),
+			configMapKey: types.NamespacedName{
+				Namespace: system.Namespace,
+				Name:      cmName,
+			},
+			scope: util.NamespaceDispatcherScope,
+		}
+		tc.ReconcileKey = fmt.Sprintf("%s/%s", cNamespace, cName)
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
 func makeChannel() *eventingv1alpha1.Channel {
 	c := &eventingv1alpha1.Channel{
 		TypeMeta: metav1.TypeMeta{
@@ -516,6 +667,13 @@ func makeChannel() *eventingv1alpha1.Channel {
 	return c
 }
 
+func makeChannelNamed(name string) *eventingv1alpha1.Channel {
+	c := makeChannel()
+	c.Name = name
+	c.UID = types.UID(name)
+	return c
+}
+
 func makeChannelWithFinalizerAndAddress() *eventingv1alpha1.Channel {
 	c := makeChannelWithFinalizer()
 	c.Status.SetAddress(fmt.Sprintf("%s-channel.%s.svc.cluster.local", c.Name, c.Namespace))
@@ -672,12 +830,221 @@ func makeVirtualService() *istiov1alpha3.VirtualService {
 	}
 }
 
+func makeShardedVirtualService(shard int) *istiov1alpha3.VirtualService {
+	vs := makeVirtualService()
+	vs.Spec.Http[0].Route[0].Destination.Host = fmt.Sprintf("in-memory-channel-dispatcher-%d.knative-eventing.svc.cluster.local", shard)
+	return vs
+}
+
+func makeDispatcherShardService(shard int) *corev1.Service {
+	name := fmt.Sprintf("in-memory-channel-dispatcher-%d", shard)
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: system.Namespace,
+			Labels: map[string]string{
+				"clusterChannelProvisioner": ccpName,
+				"role":                      "dispatcher",
+				"provisioner":               ccpName,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"clusterChannelProvisioner":          ccpName,
+				"role":                               "dispatcher",
+				"statefulset.kubernetes.io/pod-name": name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+}
+
+func makeNamespaceVirtualService() *istiov1alpha3.VirtualService {
+	vs := makeVirtualService()
+	vs.Spec.Http[0].Route[0].Destination.Host = fmt.Sprintf("in-memory-channel-dispatcher.%s.svc.cluster.local", cNamespace)
+	return vs
+}
+
+func makeNamespaceDispatcherService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "in-memory-channel-dispatcher",
+			Namespace: cNamespace,
+			Labels: map[string]string{
+				"clusterChannelProvisioner": ccpName,
+				"role":                      "dispatcher",
+				"provisioner":               ccpName,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"clusterChannelProvisioner": ccpName,
+				"role":                      "dispatcher",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+}
+
+func makeDispatcherPodTemplate(serviceAccountName, configMapNamespace string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"clusterChannelProvisioner": ccpName,
+				"role":                      "dispatcher",
+			},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccountName,
+			Containers: []corev1.Container{{
+				Name:  "dispatcher",
+				Image: "fanoutsidecar",
+				Args: []string{
+					"--sidecar_port=8080",
+					"--config_map_namespace=" + configMapNamespace,
+					"--config_map_name=" + cmName,
+				},
+			}},
+		},
+	}
+}
+
+func makeDispatcherTemplate() *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      "in-memory-channel-dispatcher",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"clusterChannelProvisioner": ccpName,
+					"role":                      "dispatcher",
+				},
+			},
+			Template: makeDispatcherPodTemplate("in-memory-channel-dispatcher", system.Namespace),
+		},
+	}
+}
+
+func makeNamespaceDispatcherDeployment() *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cNamespace,
+			Name:      "in-memory-channel-dispatcher",
+			Labels: map[string]string{
+				"clusterChannelProvisioner": ccpName,
+				"role":                      "dispatcher",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"clusterChannelProvisioner": ccpName,
+					"role":                      "dispatcher",
+				},
+			},
+			Template: makeDispatcherPodTemplate("in-memory-channel-dispatcher", cNamespace),
+		},
+	}
+}
+
+func makeOutdatedNamespaceDispatcherDeployment() *appsv1.Deployment {
+	d := makeNamespaceDispatcherDeployment()
+	d.Spec.Template.Spec.Containers[0].Image = "old-fanoutsidecar"
+	return d
+}
+
+func makeNamespaceDispatcherServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cNamespace,
+			Name:      "in-memory-channel-dispatcher",
+		},
+	}
+}
+
+func makeNamespaceDispatcherRoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cNamespace,
+			Name:      "in-memory-channel-dispatcher",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "in-memory-channel-dispatcher",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Namespace: cNamespace,
+			Name:      "in-memory-channel-dispatcher",
+		}},
+	}
+}
+
 func makeVirtualServiceNowOwnedByChannel() *istiov1alpha3.VirtualService {
 	vs := makeVirtualService()
 	vs.OwnerReferences = nil
 	return vs
 }
 
+func makeAuthorizationPolicy() *securityv1beta1.AuthorizationPolicy {
+	return &securityv1beta1.AuthorizationPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: securityv1beta1.SchemeGroupVersion.String(),
+			Kind:       "AuthorizationPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      util.ChannelAuthorizationPolicyName(cName, cNamespace),
+			Labels: map[string]string{
+				"channel":          cName,
+				"channelNamespace": cNamespace,
+				"provisioner":      ccpName,
+			},
+		},
+	}
+}
+
 func errorOnSecondChannelGet() []controllertesting.MockGet {
 	passThrough := []controllertesting.MockGet{
 		func(innerClient client.Client, ctx context.Context, key client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
@@ -58,7 +58,7 @@ func (r *reconciler) InjectClient(c client.Client) error {
 
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	//TODO use this to store the logger and set a deadline
-	ctx := context.TODO()
+	ctx := util.WithEventRecorder(context.TODO(), r.recorder)
 	logger := r.logger.With(zap.Any("request", request))
 
 	// Workaround until https://github.com/kubernetes-sigs/controller-runtime/issues/214 is fixed.
@@ -126,9 +126,10 @@ func shouldReconcile(namespace, name string) bool {
 func (r *reconciler) reconcile(ctx context.Context, ccp *eventingv1alpha1.ClusterChannelProvisioner) error {
 	logger := r.logger.With(zap.Any("clusterChannelProvisioner", ccp))
 
-	// We are syncing one thing.
+	// We are syncing two things.
 	// 1. The K8s Service to talk to all in-memory Channels.
 	//     - There is a single K8s Service for all requests going any in-memory Channel.
+	// 2. The NetworkPolicy restricting ingress to the dispatcher.
 
 	if ccp.DeletionTimestamp != nil {
 		// K8s garbage collection will delete the dispatcher service, once this ClusterChannelProvisioner
@@ -148,6 +149,11 @@ func (r *reconciler) reconcile(ctx context.Context, ccp *eventingv1alpha1.Cluste
 		logger.Warn("ClusterChannelProvisioner's K8s Service is not owned by the ClusterChannelProvisioner", zap.Any("clusterChannelProvisioner", ccp), zap.Any("service", svc))
 	}
 
+	if _, err := util.CreateDispatcherNetworkPolicy(ctx, r.client, ccp); err != nil {
+		logger.Info("Error creating the ClusterChannelProvisioner's NetworkPolicy", zap.Error(err))
+		return err
+	}
+
 	// The name of the svc has changed since version 0.2.1. Hence, delete old dispatcher service (in-memory-channel-clusterbus)
 	// that was created previously in version 0.2.0 to ensure backwards compatibility.
 	err = r.deleteOldDispatcherService(ctx, ccp)
@@ -156,10 +162,18 @@ func (r *reconciler) reconcile(ctx context.Context, ccp *eventingv1alpha1.Cluste
 		return err
 	}
 
+	ccp.Status.Capabilities = Capabilities()
 	ccp.Status.MarkReady()
 	return nil
 }
 
+// Capabilities returns the capabilities of in-memory Channels, published in the status of the
+// ClusterChannelProvisioner. Events are delivered concurrently, and lost when the dispatcher
+// restarts.
+func Capabilities() *eventingv1alpha1.ClusterChannelProvisionerCapabilities {
+	return &eventingv1alpha1.ClusterChannelProvisionerCapabilities{}
+}
+
 func (r *reconciler) deleteOldDispatcherService(ctx context.Context, ccp *eventingv1alpha1.ClusterChannelProvisioner) error {
 	svcName := fmt.Sprintf("%s-clusterbus", ccp.Name)
 	svcKey := types.NamespacedName{
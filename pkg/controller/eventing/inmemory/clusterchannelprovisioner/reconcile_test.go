@@ -288,6 +288,7 @@ func makeReadyClusterChannelProvisioner() *eventingv1alpha1.ClusterChannelProvis
 		Status:   corev1.ConditionTrue,
 		Severity: duckv1alpha1.ConditionSeverityError,
 	}}
+	ccp.Status.Capabilities = &eventingv1alpha1.ClusterChannelProvisionerCapabilities{}
 	return ccp
 }
 
@@ -316,7 +317,11 @@ func makeK8sService() *corev1.Service {
 					BlockOwnerDeletion: &truePointer,
 				},
 			},
-			Labels: util.DispatcherLabels(Name),
+			Labels: map[string]string{
+				"clusterChannelProvisioner": Name,
+				"role":                      "dispatcher",
+				"provisioner":               Name,
+			},
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: util.DispatcherLabels(Name),
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package namespace
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "namespace-controller"
+)
+
+type reconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a controller that creates the default Broker in the Namespaces
+// labeled for injection.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Setup a new controller to Reconcile Namespaces.
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch Namespace events and enqueue Namespace object key.
+	if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the default Brokers, so that they are recreated if they are deleted.
+	// Watch default Brokers, so that they are recreated if they are deleted.
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Broker{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(namespaceOf),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// namespaceOf returns the request of the Namespace of o if it is a default Broker. Namespaces are
+// cluster scoped, so unlike the requests of EnqueueRequestForOwner, it has no namespace.
+func namespaceOf(o handler.MapObject) []reconcile.Request {
+	if o.Meta.GetName() != v1alpha1.DefaultBrokerName {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: o.Meta.GetNamespace()}}}
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package namespace
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// InjectionLabelKey is the label that opts a Namespace in to getting a default Broker.
+	InjectionLabelKey = "knative-eventing-injection"
+
+	// InjectionEnabledLabelValue is the value of InjectionLabelKey that enables injection.
+	InjectionEnabledLabelValue = "enabled"
+
+	// InjectedLabelKey is the label set on the Brokers created by this controller.
+	InjectedLabelKey = "eventing.knative.dev/namespaceInjected"
+
+	brokerCreated = "BrokerCreated"
+)
+
+// Reconcile creates the default Broker in the Namespace in request, if the Namespace is labeled
+// for injection and the Broker does not exist.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling namespace %v", request)
+	ctx := context.TODO()
+	ns := &corev1.Namespace{}
+	err := r.client.Get(ctx, request.NamespacedName, ns)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find namespace %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch Namespace %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	if ns.Labels[InjectionLabelKey] != InjectionEnabledLabelValue {
+		// Brokers already created in the Namespace are left in place when the label is removed.
+		return reconcile.Result{}, nil
+	}
+	if ns.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.reconcileBroker(ctx, ns)
+}
+
+// reconcileBroker creates the default Broker in ns if it does not exist.
+func (r *reconciler) reconcileBroker(ctx context.Context, ns *corev1.Namespace) error {
+	b := &v1alpha1.Broker{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: ns.Name, Name: v1alpha1.DefaultBrokerName}, b)
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	b = newDefaultBroker(ns)
+	if err := r.client.Create(ctx, b); err != nil {
+		glog.Warningf("Failed to create the default broker in namespace %s: %v", ns.Name, err)
+		return err
+	}
+	r.recorder.Eventf(ns, corev1.EventTypeNormal, brokerCreated, "Default Broker created")
+	return nil
+}
+
+func newDefaultBroker(ns *corev1.Namespace) *v1alpha1.Broker {
+	return &v1alpha1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns.Name,
+			Name:      v1alpha1.DefaultBrokerName,
+			Labels: map[string]string{
+				InjectedLabelKey: "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ns, corev1.SchemeGroupVersion.WithKind("Namespace")),
+			},
+		},
+	}
+}
@@ -0,0 +1,261 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package namespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testNS = "test-namespace"
+	nsUID  = "test-uid"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Namespace not found",
+		},
+		{
+			Name: "Error getting Namespace",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&corev1.Namespace{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Namespace not labeled",
+			InitialState: []runtime.Object{
+				makeNamespace(nil),
+			},
+			WantAbsent: []runtime.Object{
+				makeBroker(),
+			},
+		},
+		{
+			Name: "Namespace injection disabled",
+			InitialState: []runtime.Object{
+				makeNamespace(map[string]string{InjectionLabelKey: "disabled"}),
+			},
+			WantAbsent: []runtime.Object{
+				makeBroker(),
+			},
+		},
+		{
+			Name: "Namespace being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingNamespace(),
+			},
+			WantAbsent: []runtime.Object{
+				makeBroker(),
+			},
+		},
+		{
+			Name: "Broker created",
+			InitialState: []runtime.Object{
+				makeEnabledNamespace(),
+			},
+			WantPresent: []runtime.Object{
+				makeBroker(),
+			},
+		},
+		{
+			Name: "Broker exists",
+			InitialState: []runtime.Object{
+				makeEnabledNamespace(),
+				makeExistingBroker(),
+			},
+			WantPresent: []runtime.Object{
+				makeExistingBroker(),
+			},
+		},
+		{
+			Name: "Error getting Broker",
+			InitialState: []runtime.Object{
+				makeEnabledNamespace(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Broker{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Broker creation fails",
+			InitialState: []runtime.Object{
+				makeEnabledNamespace(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Broker{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = testNS
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func TestReconcile_BrokerDeleted(t *testing.T) {
+	c := fake.NewFakeClient(makeEnabledNamespace())
+	r := &reconciler{
+		client:   c,
+		recorder: record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName}),
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: testNS}}
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b := &v1alpha1.Broker{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: testNS, Name: v1alpha1.DefaultBrokerName}, b); err != nil {
+		t.Fatalf("Unexpected error getting the Broker: %v", err)
+	}
+	if err := c.Delete(context.TODO(), b); err != nil {
+		t.Fatalf("Unexpected error deleting the Broker: %v", err)
+	}
+
+	// The deletion of the Broker enqueues its Namespace.
+	requests := namespaceOf(handler.MapObject{Meta: b, Object: b})
+	if len(requests) != 1 || requests[0] != request {
+		t.Fatalf("Unexpected requests %v. Expected: %v", requests, request)
+	}
+	if _, err := r.Reconcile(requests[0]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.Get(context.TODO(), client.ObjectKey{Namespace: testNS, Name: v1alpha1.DefaultBrokerName}, &v1alpha1.Broker{}); err != nil {
+		t.Errorf("The Broker was not recreated: %v", err)
+	}
+}
+
+func TestNamespaceOf(t *testing.T) {
+	other := makeBroker()
+	other.Name = "other"
+	if requests := namespaceOf(handler.MapObject{Meta: other, Object: other}); len(requests) != 0 {
+		t.Errorf("Unexpected requests for a Broker that is not the default: %v", requests)
+	}
+}
+
+func makeNamespace(labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testNS,
+			UID:    nsUID,
+			Labels: labels,
+		},
+	}
+}
+
+func makeEnabledNamespace() *corev1.Namespace {
+	return makeNamespace(map[string]string{InjectionLabelKey: InjectionEnabledLabelValue})
+}
+
+func makeDeletingNamespace() *corev1.Namespace {
+	ns := makeEnabledNamespace()
+	ns.DeletionTimestamp = &deletionTime
+	return ns
+}
+
+func makeBroker() *v1alpha1.Broker {
+	b := newDefaultBroker(makeEnabledNamespace())
+	b.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Broker",
+	}
+	return b
+}
+
+// makeExistingBroker returns a default Broker created by the user rather than by the controller.
+func makeExistingBroker() *v1alpha1.Broker {
+	b := makeBroker()
+	b.Labels = nil
+	b.OwnerReferences = nil
+	return b
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package parallel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/parallel/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Parallel resource
+// with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling parallel %v", request)
+	ctx := context.TODO()
+	parallel := &v1alpha1.Parallel{}
+	err := r.client.Get(ctx, request.NamespacedName, parallel)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find parallel %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch Parallel %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the Parallel and then write back any status
+	// updates regardless of whether the reconcile error out.
+	parallel = parallel.DeepCopy()
+	err = r.reconcile(ctx, parallel)
+	if updateStatusErr := r.updateStatus(ctx, parallel); updateStatusErr != nil {
+		glog.Warningf("Failed to update parallel status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, p *v1alpha1.Parallel) error {
+	p.Status.InitializeConditions()
+
+	if p.DeletionTimestamp != nil {
+		// The Channel and Subscriptions are owned by the Parallel and will be garbage
+		// collected.
+		return nil
+	}
+
+	c, err := r.reconcileChannel(ctx, p)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the Channel of parallel %s/%s: %v", p.Namespace, p.Name, err)
+		p.Status.MarkChannelNotReady("ChannelFailure", "%v", err)
+		return err
+	}
+	if !c.Status.IsReady() || c.Status.Address.Hostname == "" {
+		// The Parallel is reconciled again when the Channel changes.
+		p.Status.MarkChannelNotReady("ChannelNotReady", "Channel %s is not ready", c.Name)
+	} else {
+		p.Status.MarkChannelReady()
+	}
+	p.Status.SetAddress(c.Status.Address.Hostname)
+
+	branches := make([]v1alpha1.ParallelBranchStatus, len(p.Spec.Branches))
+	for i := range p.Spec.Branches {
+		sub, err := r.reconcileSubscription(ctx, p, i)
+		if err != nil {
+			glog.Warningf("Failed to reconcile the Subscription of branch %d of parallel %s/%s: %v", i, p.Namespace, p.Name, err)
+			p.Status.MarkSubscriptionsNotReady("SubscriptionFailure", "%v", err)
+			return err
+		}
+		branches[i] = branchStatus(sub)
+	}
+	p.Status.Branches = branches
+
+	if err := r.deleteRemovedBranches(ctx, p); err != nil {
+		glog.Warningf("Failed to delete the removed branches of parallel %s/%s: %v", p.Namespace, p.Name, err)
+		p.Status.MarkSubscriptionsNotReady("SubscriptionFailure", "%v", err)
+		return err
+	}
+
+	// The Parallel is reconciled again when its Subscriptions change.
+	for _, b := range branches {
+		if b.Ready != corev1.ConditionTrue {
+			p.Status.MarkSubscriptionsNotReady("SubscriptionNotReady", "Subscription %s is not ready", b.Subscription)
+			return nil
+		}
+	}
+	p.Status.MarkSubscriptionsReady()
+	return nil
+}
+
+// branchStatus returns the state of the branch whose Subscription is sub.
+func branchStatus(sub *v1alpha1.Subscription) v1alpha1.ParallelBranchStatus {
+	status := v1alpha1.ParallelBranchStatus{
+		Subscription: sub.Name,
+		Ready:        corev1.ConditionUnknown,
+	}
+	if cond := sub.Status.GetCondition(v1alpha1.SubscriptionConditionReady); cond != nil {
+		status.Ready = cond.Status
+	}
+	return status
+}
+
+// reconcileChannel creates the Channel of p if it does not exist yet. The Channel is not updated,
+// as its template cannot change.
+func (r *reconciler) reconcileChannel(ctx context.Context, p *v1alpha1.Parallel) (*v1alpha1.Channel, error) {
+	c := &v1alpha1.Channel{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: p.Namespace, Name: resources.ChannelName(p.Name)}, c)
+	if errors.IsNotFound(err) {
+		c = resources.MakeChannel(p)
+		err = r.client.Create(ctx, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(c, p) {
+		return nil, fmt.Errorf("Channel %s is not owned by the Parallel", c.Name)
+	}
+	return c, nil
+}
+
+// reconcileSubscription creates the Subscription of the branch at index branch of p, or updates
+// the filter, subscriber and reply of the existing Subscription to match it.
+func (r *reconciler) reconcileSubscription(ctx context.Context, p *v1alpha1.Parallel, branch int) (*v1alpha1.Subscription, error) {
+	sub := resources.MakeSubscription(p, branch)
+	current := &v1alpha1.Subscription{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: sub.Namespace, Name: sub.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, sub); err != nil {
+			return nil, err
+		}
+		return sub, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, p) {
+		return nil, fmt.Errorf("Subscription %s is not owned by the Parallel", current.Name)
+	}
+	if !equality.Semantic.DeepEqual(sub.Spec.Filter, current.Spec.Filter) ||
+		!equality.Semantic.DeepEqual(sub.Spec.Subscriber, current.Spec.Subscriber) ||
+		!equality.Semantic.DeepEqual(sub.Spec.Reply, current.Spec.Reply) ||
+		!equality.Semantic.DeepEqual(sub.Spec.OnError, current.Spec.OnError) {
+		current.Spec.Filter = sub.Spec.Filter
+		current.Spec.Subscriber = sub.Spec.Subscriber
+		current.Spec.Reply = sub.Spec.Reply
+		current.Spec.OnError = sub.Spec.OnError
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// deleteRemovedBranches deletes the Subscriptions owned by p that belong to none of its branches.
+func (r *reconciler) deleteRemovedBranches(ctx context.Context, p *v1alpha1.Parallel) error {
+	names := sets.NewString()
+	for i := range p.Spec.Branches {
+		names.Insert(resources.SubscriptionName(p.Name, i))
+	}
+
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(resources.Labels(p.Name)),
+		Namespace:     p.Namespace,
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Subscription",
+			},
+		},
+	}
+	sl := &v1alpha1.SubscriptionList{}
+	if err := r.client.List(ctx, opts, sl); err != nil {
+		return err
+	}
+	for i := range sl.Items {
+		sub := &sl.Items[i]
+		if names.Has(sub.Name) || !metav1.IsControlledBy(sub, p) {
+			continue
+		}
+		if err := r.client.Delete(ctx, sub); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, p *v1alpha1.Parallel) error {
+	current := &v1alpha1.Parallel{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: p.Namespace, Name: p.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, p.Status) {
+		return nil
+	}
+	current.Status = p.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the Parallel resource.
+	return r.client.Update(ctx, current)
+}
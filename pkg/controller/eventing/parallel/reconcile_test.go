@@ -0,0 +1,448 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/parallel/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS       = "test-namespace"
+	parallelName = "test-parallel"
+	parallelUID  = "test-uid"
+
+	channelHostname = "test-parallel-parallel-channel.test-namespace.svc.cluster.local"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+
+	firstBranchURI = "http://first.example.com/"
+	onErrorURI     = "http://on-error.example.com/"
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Parallel not found",
+		},
+		{
+			Name: "Error getting Parallel",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Parallel{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Parallel being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingParallel(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingParallel(),
+			},
+			WantAbsent: []runtime.Object{
+				makeChannel(),
+				makeSubscription(0),
+			},
+		},
+		{
+			Name: "Channel and Subscriptions created, not ready yet",
+			InitialState: []runtime.Object{
+				makeParallel(),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(),
+				makeSubscription(0),
+				makeSubscription(1),
+				makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+					s.MarkChannelNotReady("ChannelNotReady", "Channel test-parallel-parallel is not ready")
+					s.SetAddress("")
+					s.Branches = []v1alpha1.ParallelBranchStatus{
+						{Subscription: "test-parallel-parallel-0", Ready: corev1.ConditionUnknown},
+						{Subscription: "test-parallel-parallel-1", Ready: corev1.ConditionUnknown},
+					}
+					s.MarkSubscriptionsNotReady("SubscriptionNotReady", "Subscription test-parallel-parallel-0 is not ready")
+				}),
+			},
+		},
+		{
+			Name: "Replies go to the reply of the branch, or else of the Parallel",
+			InitialState: []runtime.Object{
+				makeParallel(),
+			},
+			WantPresent: []runtime.Object{
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(0)
+					s.Spec.Filter = &v1alpha1.SubscriptionFilter{Expression: "type = 'com.example.created'"}
+					s.Spec.Subscriber = &v1alpha1.SubscriberSpec{DNSName: &firstBranchURI}
+					s.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: channelReference("first-reply")}
+					s.Spec.OnError = &v1alpha1.SubscriberSpec{DNSName: &onErrorURI}
+					return s
+				}(),
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(1)
+					s.Spec.Filter = nil
+					s.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: channelReference("reply")}
+					s.Spec.OnError = nil
+					return s
+				}(),
+			},
+		},
+		{
+			Name: "Channel creation fails",
+			InitialState: []runtime.Object{
+				makeParallel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Channel{}),
+			},
+			WantPresent: []runtime.Object{
+				makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+					s.MarkChannelNotReady("ChannelFailure", testErrorMessage)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(0),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Existing Channel is not owned by the Parallel",
+			InitialState: []runtime.Object{
+				makeParallel(),
+				makeUnownedChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+					s.MarkChannelNotReady("ChannelFailure", "Channel test-parallel-parallel is not owned by the Parallel")
+				}),
+			},
+			WantErrMsg: "Channel test-parallel-parallel is not owned by the Parallel",
+		},
+		{
+			Name: "Subscription creation fails",
+			InitialState: []runtime.Object{
+				makeParallel(),
+				makeReadyChannel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Subscription{}),
+			},
+			WantPresent: []runtime.Object{
+				makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+					s.MarkChannelReady()
+					s.SetAddress(channelHostname)
+					s.MarkSubscriptionsNotReady("SubscriptionFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Existing Subscription is updated",
+			InitialState: []runtime.Object{
+				makeParallel(),
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(0)
+					s.Spec.Filter = nil
+					s.Spec.Reply = nil
+					s.Spec.OnError = nil
+					return s
+				}(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(0),
+			},
+		},
+		{
+			Name: "Existing Subscription is not owned by the Parallel",
+			InitialState: []runtime.Object{
+				makeParallel(),
+				makeReadyChannel(),
+				makeUnownedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+					s.MarkChannelReady()
+					s.SetAddress(channelHostname)
+					s.MarkSubscriptionsNotReady("SubscriptionFailure", "Subscription test-parallel-parallel-0 is not owned by the Parallel")
+				}),
+			},
+			WantErrMsg: "Subscription test-parallel-parallel-0 is not owned by the Parallel",
+		},
+		{
+			Name: "Removed branches are deleted",
+			InitialState: []runtime.Object{
+				makeParallelWithBranches(1),
+				makeChannel(),
+				makeSubscription(0),
+				makeSubscription(1),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(1),
+			},
+		},
+		{
+			Name: "Branch not ready",
+			InitialState: []runtime.Object{
+				makeParallel(),
+				makeReadyChannel(),
+				makeReadySubscription(0),
+				makeSubscription(1),
+			},
+			WantPresent: []runtime.Object{
+				makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+					s.MarkChannelReady()
+					s.SetAddress(channelHostname)
+					s.Branches = []v1alpha1.ParallelBranchStatus{
+						{Subscription: "test-parallel-parallel-0", Ready: corev1.ConditionTrue},
+						{Subscription: "test-parallel-parallel-1", Ready: corev1.ConditionUnknown},
+					}
+					s.MarkSubscriptionsNotReady("SubscriptionNotReady", "Subscription test-parallel-parallel-1 is not ready")
+				}),
+			},
+		},
+		{
+			Name: "Parallel ready",
+			InitialState: []runtime.Object{
+				makeParallel(),
+				makeReadyChannel(),
+				makeReadySubscription(0),
+				makeReadySubscription(1),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyParallel(),
+			},
+		},
+		{
+			Name: "Updating Parallel status fails",
+			InitialState: []runtime.Object{
+				makeParallel(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.Parallel{}),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(),
+				makeSubscription(0),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, parallelName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeParallel() *v1alpha1.Parallel {
+	return &v1alpha1.Parallel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Parallel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      parallelName,
+			UID:       parallelUID,
+		},
+		Spec: v1alpha1.ParallelSpec{
+			Branches: []v1alpha1.ParallelBranch{{
+				Filter:     &v1alpha1.SubscriptionFilter{Expression: "type = 'com.example.created'"},
+				Subscriber: v1alpha1.SubscriberSpec{DNSName: &firstBranchURI},
+				Reply: &v1alpha1.ReplyStrategy{
+					Channel: channelReference("first-reply"),
+				},
+				OnError: &v1alpha1.SubscriberSpec{DNSName: &onErrorURI},
+			}, {
+				Subscriber: v1alpha1.SubscriberSpec{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "Service",
+						Name:       "second",
+					},
+				},
+			}},
+			Reply: &v1alpha1.ReplyStrategy{
+				Channel: channelReference("reply"),
+			},
+		},
+	}
+}
+
+func makeParallelWithBranches(n int) *v1alpha1.Parallel {
+	p := makeParallel()
+	p.Spec.Branches = p.Spec.Branches[:n]
+	return p
+}
+
+func makeParallelWithStatus(f func(*v1alpha1.ParallelStatus)) *v1alpha1.Parallel {
+	p := makeParallel()
+	p.Status.InitializeConditions()
+	f(&p.Status)
+	return p
+}
+
+func makeReadyParallel() *v1alpha1.Parallel {
+	return makeParallelWithStatus(func(s *v1alpha1.ParallelStatus) {
+		s.MarkChannelReady()
+		s.SetAddress(channelHostname)
+		s.Branches = []v1alpha1.ParallelBranchStatus{
+			{Subscription: "test-parallel-parallel-0", Ready: corev1.ConditionTrue},
+			{Subscription: "test-parallel-parallel-1", Ready: corev1.ConditionTrue},
+		}
+		s.MarkSubscriptionsReady()
+	})
+}
+
+func makeDeletingParallel() *v1alpha1.Parallel {
+	p := makeParallelWithStatus(func(*v1alpha1.ParallelStatus) {})
+	p.DeletionTimestamp = &deletionTime
+	return p
+}
+
+func channelReference(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Name:       name,
+	}
+}
+
+func makeChannel() *v1alpha1.Channel {
+	c := resources.MakeChannel(makeParallel())
+	c.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+	}
+	return c
+}
+
+func makeReadyChannel() *v1alpha1.Channel {
+	c := makeChannel()
+	c.Status.InitializeConditions()
+	c.Status.MarkProvisioned()
+	c.Status.SetAddress(channelHostname)
+	return c
+}
+
+func makeUnownedChannel() *v1alpha1.Channel {
+	c := makeChannel()
+	c.OwnerReferences = nil
+	return c
+}
+
+func makeSubscription(branch int) *v1alpha1.Subscription {
+	s := resources.MakeSubscription(makeParallel(), branch)
+	s.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+	return s
+}
+
+func makeReadySubscription(branch int) *v1alpha1.Subscription {
+	s := makeSubscription(branch)
+	s.Status.InitializeConditions()
+	s.Status.MarkReferencesResolved()
+	s.Status.MarkChannelReady()
+	return s
+}
+
+func makeUnownedSubscription() *v1alpha1.Subscription {
+	s := makeSubscription(0)
+	s.OwnerReferences = nil
+	return s
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
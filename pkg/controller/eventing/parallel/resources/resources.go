@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package resources creates the Channel and Subscriptions that make up a Parallel.
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ParallelLabelKey is the label that identifies the Parallel that an object belongs to.
+	ParallelLabelKey = "eventing.knative.dev/parallel"
+)
+
+// ChannelName returns the name of the Channel that holds the events of the Parallel
+// parallelName.
+func ChannelName(parallelName string) string {
+	return fmt.Sprintf("%s-parallel", parallelName)
+}
+
+// SubscriptionName returns the name of the Subscription of the branch at index branch of the
+// Parallel parallelName.
+func SubscriptionName(parallelName string, branch int) string {
+	return fmt.Sprintf("%s-parallel-%d", parallelName, branch)
+}
+
+// Labels returns the labels of every object created for the Parallel parallelName.
+func Labels(parallelName string) map[string]string {
+	return map[string]string{
+		ParallelLabelKey: parallelName,
+	}
+}
+
+func objectMeta(p *v1alpha1.Parallel, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: p.Namespace,
+		Name:      name,
+		Labels:    Labels(p.Name),
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(p, v1alpha1.SchemeGroupVersion.WithKind("Parallel")),
+		},
+	}
+}
+
+// MakeChannel creates the Channel that holds the events of p.
+func MakeChannel(p *v1alpha1.Parallel) *v1alpha1.Channel {
+	c := &v1alpha1.Channel{
+		ObjectMeta: objectMeta(p, ChannelName(p.Name)),
+	}
+	if p.Spec.ChannelTemplate != nil {
+		c.Spec = *p.Spec.ChannelTemplate.DeepCopy()
+	}
+	// A Channel without provisioner gets the default template of its namespace.
+	c.SetDefaults()
+	return c
+}
+
+// MakeSubscription creates the Subscription that delivers the events of the Channel of p that
+// match the filter of the branch at index branch to the branch's subscriber, and its replies to
+// the reply of the branch, or else to the reply of p.
+func MakeSubscription(p *v1alpha1.Parallel, branch int) *v1alpha1.Subscription {
+	b := p.Spec.Branches[branch]
+	sub := &v1alpha1.Subscription{
+		ObjectMeta: objectMeta(p, SubscriptionName(p.Name, branch)),
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Channel",
+				Name:       ChannelName(p.Name),
+			},
+			Subscriber: b.Subscriber.DeepCopy(),
+			Filter:     b.Filter.DeepCopy(),
+			OnError:    b.OnError.DeepCopy(),
+		},
+	}
+	if b.Reply != nil {
+		sub.Spec.Reply = b.Reply.DeepCopy()
+	} else if p.Spec.Reply != nil {
+		sub.Spec.Reply = p.Spec.Reply.DeepCopy()
+	}
+	return sub
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sequence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/sequence/resources"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Sequence resource
+// with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling sequence %v", request)
+	ctx := context.TODO()
+	sequence := &v1alpha1.Sequence{}
+	err := r.client.Get(ctx, request.NamespacedName, sequence)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find sequence %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch Sequence %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the Sequence and then write back any status
+	// updates regardless of whether the reconcile error out.
+	sequence = sequence.DeepCopy()
+	err = r.reconcile(ctx, sequence)
+	if updateStatusErr := r.updateStatus(ctx, sequence); updateStatusErr != nil {
+		glog.Warningf("Failed to update sequence status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, s *v1alpha1.Sequence) error {
+	s.Status.InitializeConditions()
+
+	if s.DeletionTimestamp != nil {
+		// The Channels and Subscriptions are owned by the Sequence and will be garbage
+		// collected.
+		return nil
+	}
+
+	if len(s.Spec.Steps) == 0 {
+		// The webhook rejects Sequences without steps.
+		s.Status.MarkChannelsNotReady("NoSteps", "the Sequence has no steps")
+		return nil
+	}
+
+	channels := make([]*v1alpha1.Channel, len(s.Spec.Steps))
+	for i := range s.Spec.Steps {
+		c, err := r.reconcileChannel(ctx, s, i)
+		if err != nil {
+			glog.Warningf("Failed to reconcile the Channel of step %d of sequence %s/%s: %v", i, s.Namespace, s.Name, err)
+			s.Status.MarkChannelsNotReady("ChannelFailure", "%v", err)
+			return err
+		}
+		channels[i] = c
+	}
+
+	subs := make([]*v1alpha1.Subscription, len(s.Spec.Steps))
+	for i := range s.Spec.Steps {
+		sub, err := r.reconcileSubscription(ctx, s, i)
+		if err != nil {
+			glog.Warningf("Failed to reconcile the Subscription of step %d of sequence %s/%s: %v", i, s.Namespace, s.Name, err)
+			s.Status.MarkSubscriptionsNotReady("SubscriptionFailure", "%v", err)
+			return err
+		}
+		subs[i] = sub
+	}
+
+	// The Channels and Subscriptions of the steps that were removed from the Sequence are
+	// deleted once the remaining steps are wired, so that events in flight reach the reply.
+	if err := r.deleteRemovedSteps(ctx, s); err != nil {
+		glog.Warningf("Failed to delete the removed steps of sequence %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkSubscriptionsNotReady("SubscriptionFailure", "%v", err)
+		return err
+	}
+
+	// The Sequence is reconciled again when its Channels and Subscriptions change.
+	s.Status.MarkChannelsReady()
+	for _, c := range channels {
+		if !c.Status.IsReady() || c.Status.Address.Hostname == "" {
+			s.Status.MarkChannelsNotReady("ChannelNotReady", "Channel %s is not ready", c.Name)
+			break
+		}
+	}
+	s.Status.MarkSubscriptionsReady()
+	for _, sub := range subs {
+		if !sub.Status.IsReady() {
+			s.Status.MarkSubscriptionsNotReady("SubscriptionNotReady", "Subscription %s is not ready", sub.Name)
+			break
+		}
+	}
+	s.Status.SetAddress(channels[0].Status.Address.Hostname)
+	return nil
+}
+
+// reconcileChannel creates the Channel of the step at index step of s if it does not exist yet.
+// The Channel is not updated, as its template cannot change.
+func (r *reconciler) reconcileChannel(ctx context.Context, s *v1alpha1.Sequence, step int) (*v1alpha1.Channel, error) {
+	c := &v1alpha1.Channel{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: s.Namespace, Name: resources.ChannelName(s.Name, step)}, c)
+	if errors.IsNotFound(err) {
+		c = resources.MakeChannel(s, step)
+		err = r.client.Create(ctx, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(c, s) {
+		return nil, fmt.Errorf("Channel %s is not owned by the Sequence", c.Name)
+	}
+	return c, nil
+}
+
+// reconcileSubscription creates the Subscription of the step at index step of s, or updates the
+// subscriber and reply of the existing Subscription to match it.
+func (r *reconciler) reconcileSubscription(ctx context.Context, s *v1alpha1.Sequence, step int) (*v1alpha1.Subscription, error) {
+	sub := resources.MakeSubscription(s, step)
+	current := &v1alpha1.Subscription{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: sub.Namespace, Name: sub.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, sub); err != nil {
+			return nil, err
+		}
+		return sub, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return nil, fmt.Errorf("Subscription %s is not owned by the Sequence", current.Name)
+	}
+	if !equality.Semantic.DeepEqual(sub.Spec.Subscriber, current.Spec.Subscriber) ||
+		!equality.Semantic.DeepEqual(sub.Spec.Reply, current.Spec.Reply) ||
+		!equality.Semantic.DeepEqual(sub.Spec.OnError, current.Spec.OnError) {
+		current.Spec.Subscriber = sub.Spec.Subscriber
+		current.Spec.Reply = sub.Spec.Reply
+		current.Spec.OnError = sub.Spec.OnError
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// deleteRemovedSteps deletes the Subscriptions and Channels owned by s that belong to none of its
+// steps.
+func (r *reconciler) deleteRemovedSteps(ctx context.Context, s *v1alpha1.Sequence) error {
+	subNames := sets.NewString()
+	channelNames := sets.NewString()
+	for i := range s.Spec.Steps {
+		subNames.Insert(resources.SubscriptionName(s.Name, i))
+		channelNames.Insert(resources.ChannelName(s.Name, i))
+	}
+
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(resources.Labels(s.Name)),
+		Namespace:     s.Namespace,
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Subscription",
+			},
+		},
+	}
+	sl := &v1alpha1.SubscriptionList{}
+	if err := r.client.List(ctx, opts, sl); err != nil {
+		return err
+	}
+	for i := range sl.Items {
+		sub := &sl.Items[i]
+		if subNames.Has(sub.Name) || !metav1.IsControlledBy(sub, s) {
+			continue
+		}
+		if err := r.client.Delete(ctx, sub); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	opts.Raw.TypeMeta.Kind = "Channel"
+	cl := &v1alpha1.ChannelList{}
+	if err := r.client.List(ctx, opts, cl); err != nil {
+		return err
+	}
+	for i := range cl.Items {
+		c := &cl.Items[i]
+		if channelNames.Has(c.Name) || !metav1.IsControlledBy(c, s) {
+			continue
+		}
+		if err := r.client.Delete(ctx, c); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, s *v1alpha1.Sequence) error {
+	current := &v1alpha1.Sequence{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: s.Namespace, Name: s.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, s.Status) {
+		return nil
+	}
+	current.Status = s.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the Sequence resource.
+	return r.client.Update(ctx, current)
+}
@@ -0,0 +1,427 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sequence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/sequence/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS       = "test-namespace"
+	sequenceName = "test-sequence"
+	sequenceUID  = "test-uid"
+
+	channelHostname = "test-sequence-sequence-0-channel.test-namespace.svc.cluster.local"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+
+	firstStepURI = "http://first.example.com/"
+	onErrorURI   = "http://on-error.example.com/"
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Sequence not found",
+		},
+		{
+			Name: "Error getting Sequence",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Sequence{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Sequence being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingSequence(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingSequence(),
+			},
+			WantAbsent: []runtime.Object{
+				makeChannel(0),
+				makeSubscription(0),
+			},
+		},
+		{
+			Name: "Channels and Subscriptions created, not ready yet",
+			InitialState: []runtime.Object{
+				makeSequence(),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(0),
+				makeChannel(1),
+				makeSubscription(0),
+				makeSubscription(1),
+				makeSequenceWithStatus(func(s *v1alpha1.SequenceStatus) {
+					s.MarkChannelsNotReady("ChannelNotReady", "Channel test-sequence-sequence-0 is not ready")
+					s.MarkSubscriptionsNotReady("SubscriptionNotReady", "Subscription test-sequence-sequence-0 is not ready")
+					s.SetAddress("")
+				}),
+			},
+		},
+		{
+			Name: "Replies of each step go to the next step, and of the last step to the reply",
+			InitialState: []runtime.Object{
+				makeSequence(),
+			},
+			WantPresent: []runtime.Object{
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(0)
+					s.Spec.Subscriber = &v1alpha1.SubscriberSpec{DNSName: &firstStepURI}
+					s.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: channelReference("test-sequence-sequence-1")}
+					s.Spec.OnError = &v1alpha1.SubscriberSpec{DNSName: &onErrorURI}
+					return s
+				}(),
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(1)
+					s.Spec.Channel = *channelReference("test-sequence-sequence-1")
+					s.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: channelReference("reply")}
+					s.Spec.OnError = nil
+					return s
+				}(),
+			},
+		},
+		{
+			Name: "Channel creation fails",
+			InitialState: []runtime.Object{
+				makeSequence(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Channel{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSequenceWithStatus(func(s *v1alpha1.SequenceStatus) {
+					s.MarkChannelsNotReady("ChannelFailure", testErrorMessage)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(0),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Existing Channel is not owned by the Sequence",
+			InitialState: []runtime.Object{
+				makeSequence(),
+				makeUnownedChannel(),
+			},
+			WantPresent: []runtime.Object{
+				makeSequenceWithStatus(func(s *v1alpha1.SequenceStatus) {
+					s.MarkChannelsNotReady("ChannelFailure", "Channel test-sequence-sequence-0 is not owned by the Sequence")
+				}),
+			},
+			WantErrMsg: "Channel test-sequence-sequence-0 is not owned by the Sequence",
+		},
+		{
+			Name: "Subscription creation fails",
+			InitialState: []runtime.Object{
+				makeSequence(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Subscription{}),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(0),
+				makeChannel(1),
+				makeSequenceWithStatus(func(s *v1alpha1.SequenceStatus) {
+					s.MarkSubscriptionsNotReady("SubscriptionFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Existing Subscription is updated",
+			InitialState: []runtime.Object{
+				makeSequence(),
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(1)
+					s.Spec.Reply = nil
+					return s
+				}(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(1),
+			},
+		},
+		{
+			Name: "Existing Subscription gets the onError of its step",
+			InitialState: []runtime.Object{
+				makeSequence(),
+				func() *v1alpha1.Subscription {
+					s := makeSubscription(0)
+					s.Spec.OnError = nil
+					return s
+				}(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(0),
+			},
+		},
+		{
+			Name: "Existing Subscription is not owned by the Sequence",
+			InitialState: []runtime.Object{
+				makeSequence(),
+				makeUnownedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeSequenceWithStatus(func(s *v1alpha1.SequenceStatus) {
+					s.MarkSubscriptionsNotReady("SubscriptionFailure", "Subscription test-sequence-sequence-0 is not owned by the Sequence")
+				}),
+			},
+			WantErrMsg: "Subscription test-sequence-sequence-0 is not owned by the Sequence",
+		},
+		{
+			Name: "Removed steps are deleted",
+			InitialState: []runtime.Object{
+				makeSequenceWithSteps(1),
+				makeChannel(0),
+				makeChannel(1),
+				makeSubscription(0),
+				makeSubscription(1),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(0),
+			},
+			WantAbsent: []runtime.Object{
+				makeChannel(1),
+				makeSubscription(1),
+			},
+		},
+		{
+			Name: "Sequence ready",
+			InitialState: []runtime.Object{
+				makeSequence(),
+				makeReadyChannel(0),
+				makeReadyChannel(1),
+				makeReadySubscription(0),
+				makeReadySubscription(1),
+			},
+			WantPresent: []runtime.Object{
+				makeSequenceWithStatus(func(s *v1alpha1.SequenceStatus) {
+					s.MarkChannelsReady()
+					s.MarkSubscriptionsReady()
+					s.SetAddress(channelHostname)
+				}),
+			},
+		},
+		{
+			Name: "Updating Sequence status fails",
+			InitialState: []runtime.Object{
+				makeSequence(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.Sequence{}),
+			},
+			WantPresent: []runtime.Object{
+				makeChannel(0),
+				makeSubscription(0),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:   c,
+			recorder: recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, sequenceName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeSequence() *v1alpha1.Sequence {
+	return &v1alpha1.Sequence{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Sequence",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sequenceName,
+			UID:       sequenceUID,
+		},
+		Spec: v1alpha1.SequenceSpec{
+			Steps: []v1alpha1.SequenceStep{{
+				SubscriberSpec: v1alpha1.SubscriberSpec{
+					DNSName: &firstStepURI,
+				},
+				OnError: &v1alpha1.SubscriberSpec{
+					DNSName: &onErrorURI,
+				},
+			}, {
+				SubscriberSpec: v1alpha1.SubscriberSpec{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "Service",
+						Name:       "second",
+					},
+				},
+			}},
+			Reply: &v1alpha1.ReplyStrategy{
+				Channel: channelReference("reply"),
+			},
+		},
+	}
+}
+
+func makeSequenceWithSteps(n int) *v1alpha1.Sequence {
+	s := makeSequence()
+	s.Spec.Steps = s.Spec.Steps[:n]
+	return s
+}
+
+func makeSequenceWithStatus(f func(*v1alpha1.SequenceStatus)) *v1alpha1.Sequence {
+	s := makeSequence()
+	s.Status.InitializeConditions()
+	f(&s.Status)
+	return s
+}
+
+func makeDeletingSequence() *v1alpha1.Sequence {
+	s := makeSequenceWithStatus(func(*v1alpha1.SequenceStatus) {})
+	s.DeletionTimestamp = &deletionTime
+	return s
+}
+
+func channelReference(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Name:       name,
+	}
+}
+
+func makeChannel(step int) *v1alpha1.Channel {
+	c := resources.MakeChannel(makeSequence(), step)
+	c.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+	}
+	return c
+}
+
+func makeReadyChannel(step int) *v1alpha1.Channel {
+	c := makeChannel(step)
+	c.Status.InitializeConditions()
+	c.Status.MarkProvisioned()
+	c.Status.SetAddress(fmt.Sprintf("%s-channel.%s.svc.cluster.local", c.Name, testNS))
+	return c
+}
+
+func makeUnownedChannel() *v1alpha1.Channel {
+	c := makeChannel(0)
+	c.OwnerReferences = nil
+	return c
+}
+
+func makeSubscription(step int) *v1alpha1.Subscription {
+	s := resources.MakeSubscription(makeSequence(), step)
+	s.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+	return s
+}
+
+func makeReadySubscription(step int) *v1alpha1.Subscription {
+	s := makeSubscription(step)
+	s.Status.InitializeConditions()
+	s.Status.MarkReferencesResolved()
+	s.Status.MarkChannelReady()
+	return s
+}
+
+func makeUnownedSubscription() *v1alpha1.Subscription {
+	s := makeSubscription(0)
+	s.OwnerReferences = nil
+	return s
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
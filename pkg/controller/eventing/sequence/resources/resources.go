@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package resources creates the Channels and Subscriptions that make up a Sequence.
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SequenceLabelKey is the label that identifies the Sequence that an object belongs to.
+	SequenceLabelKey = "eventing.knative.dev/sequence"
+)
+
+// ChannelName returns the name of the Channel in front of the step at index step of the Sequence
+// sequenceName.
+func ChannelName(sequenceName string, step int) string {
+	return fmt.Sprintf("%s-sequence-%d", sequenceName, step)
+}
+
+// SubscriptionName returns the name of the Subscription that delivers the events of the step at
+// index step of the Sequence sequenceName.
+func SubscriptionName(sequenceName string, step int) string {
+	return fmt.Sprintf("%s-sequence-%d", sequenceName, step)
+}
+
+// Labels returns the labels of every object created for the Sequence sequenceName.
+func Labels(sequenceName string) map[string]string {
+	return map[string]string{
+		SequenceLabelKey: sequenceName,
+	}
+}
+
+func objectMeta(s *v1alpha1.Sequence, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: s.Namespace,
+		Name:      name,
+		Labels:    Labels(s.Name),
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("Sequence")),
+		},
+	}
+}
+
+// MakeChannel creates the Channel in front of the step at index step of s.
+func MakeChannel(s *v1alpha1.Sequence, step int) *v1alpha1.Channel {
+	c := &v1alpha1.Channel{
+		ObjectMeta: objectMeta(s, ChannelName(s.Name, step)),
+	}
+	if s.Spec.ChannelTemplate != nil {
+		c.Spec = *s.Spec.ChannelTemplate.DeepCopy()
+	}
+	// A Channel without provisioner gets the default template of its namespace.
+	c.SetDefaults()
+	return c
+}
+
+// MakeSubscription creates the Subscription that delivers the events of the Channel of the step
+// at index step of s to the step's subscriber, and its replies to the Channel of the next step,
+// or to the reply of s after the last step. The events the subscriber fails to accept are sent
+// to the step's onError, if any.
+func MakeSubscription(s *v1alpha1.Sequence, step int) *v1alpha1.Subscription {
+	sub := &v1alpha1.Subscription{
+		ObjectMeta: objectMeta(s, SubscriptionName(s.Name, step)),
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel:    channelReference(ChannelName(s.Name, step)),
+			Subscriber: s.Spec.Steps[step].SubscriberSpec.DeepCopy(),
+			OnError:    s.Spec.Steps[step].OnError.DeepCopy(),
+		},
+	}
+	if step+1 < len(s.Spec.Steps) {
+		next := channelReference(ChannelName(s.Name, step+1))
+		sub.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: &next}
+	} else if s.Spec.Reply != nil {
+		sub.Spec.Reply = s.Spec.Reply.DeepCopy()
+	}
+	return sub
+}
+
+func channelReference(name string) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Channel",
+		Name:       name,
+	}
+}
@@ -0,0 +1,448 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	networkingv1alpha3 "github.com/knative/eventing/pkg/apis/istio/networking/v1alpha3"
+	"github.com/knative/eventing/pkg/system"
+)
+
+const (
+	// EgressConfigMapName is the name of the ConfigMap in the system namespace configuring how the
+	// external subscribers of the Subscriptions are declared to the mesh or to the NetworkPolicies.
+	EgressConfigMapName = "config-egress"
+
+	egressModeKey          = "mode"
+	egressClusterDomainKey = "cluster-domain"
+
+	// Reasons of the Events emitted on Subscriptions for their egress.
+	egressReconcileFailed = "EgressReconcileFailed"
+	egressCreated         = "EgressCreated"
+	egressUpdated         = "EgressUpdated"
+	egressDeleted         = "EgressDeleted"
+)
+
+// egressMode is how the external hosts of the Subscriptions are made reachable.
+type egressMode string
+
+const (
+	// egressModeNone does not declare the external hosts, for clusters allowing egress.
+	egressModeNone egressMode = "none"
+	// egressModeServiceEntry creates an Istio ServiceEntry of the external hosts of each
+	// Subscription, for meshes only allowing traffic to the registered services.
+	egressModeServiceEntry egressMode = "service-entry"
+	// egressModeNetworkPolicy creates a NetworkPolicy allowing the dispatchers of the system
+	// namespace to reach the addresses of the external hosts of each Subscription, for clusters
+	// denying egress by default.
+	egressModeNetworkPolicy egressMode = "network-policy"
+)
+
+// egressConfig is read from the EgressConfigMapName ConfigMap.
+type egressConfig struct {
+	mode egressMode
+	// clusterDomain is the domain of the Services of the cluster, whose hosts are not external.
+	clusterDomain string
+}
+
+// defaultEgressConfig returns the config used when the ConfigMap does not exist.
+func defaultEgressConfig() *egressConfig {
+	return &egressConfig{
+		mode:          egressModeNone,
+		clusterDomain: "cluster.local",
+	}
+}
+
+// newEgressConfigFromConfigMap creates an egressConfig from cm. The keys that are not set keep
+// their default value.
+func newEgressConfigFromConfigMap(cm *corev1.ConfigMap) (*egressConfig, error) {
+	config := defaultEgressConfig()
+	if v, ok := cm.Data[egressModeKey]; ok {
+		switch mode := egressMode(strings.TrimSpace(v)); mode {
+		case egressModeNone, egressModeServiceEntry, egressModeNetworkPolicy:
+			config.mode = mode
+		default:
+			return nil, fmt.Errorf("invalid %s %q: must be %q, %q or %q", egressModeKey, v, egressModeNone, egressModeServiceEntry, egressModeNetworkPolicy)
+		}
+	}
+	if v, ok := cm.Data[egressClusterDomainKey]; ok {
+		if v = strings.Trim(strings.TrimSpace(v), "."); v != "" {
+			config.clusterDomain = v
+		}
+	}
+	return config, nil
+}
+
+// getEgressConfig reads the egressConfig from its ConfigMap, if it exists.
+func (r *reconciler) getEgressConfig(ctx context.Context) (*egressConfig, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: system.Namespace, Name: EgressConfigMapName}, cm)
+	if errors.IsNotFound(err) {
+		return defaultEgressConfig(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return newEgressConfigFromConfigMap(cm)
+}
+
+// egressHost is a host outside the cluster the dispatchers send the events of a Subscription to.
+type egressHost struct {
+	host  string
+	port  int32
+	https bool
+}
+
+// externalHosts returns the hosts of the resolved URIs of sub that are outside the cluster, sorted
+// and without duplicates.
+func externalHosts(sub *v1alpha1.Subscription, config *egressConfig) []egressHost {
+	ps := sub.Status.PhysicalSubscription
+	seen := make(map[egressHost]bool)
+	var hosts []egressHost
+	for _, uri := range []string{ps.SubscriberURI, ps.ReplyURI, ps.DeadLetterSinkURI, ps.OnErrorURI} {
+		h, ok := parseEgressHost(uri)
+		if !ok || !isExternalHost(h.host, config.clusterDomain) || seen[h] {
+			continue
+		}
+		seen[h] = true
+		hosts = append(hosts, h)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].host != hosts[j].host {
+			return hosts[i].host < hosts[j].host
+		}
+		return hosts[i].port < hosts[j].port
+	})
+	return hosts
+}
+
+// parseEgressHost parses the host and port of uri, which is a URL or, as the DNSName of a
+// subscriber may be, a bare host.
+func parseEgressHost(uri string) (egressHost, bool) {
+	if uri == "" {
+		return egressHost{}, false
+	}
+	if !strings.Contains(uri, "://") {
+		uri = "http://" + uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Hostname() == "" {
+		return egressHost{}, false
+	}
+	h := egressHost{
+		host:  strings.ToLower(u.Hostname()),
+		port:  80,
+		https: u.Scheme == "https",
+	}
+	if h.https {
+		h.port = 443
+	}
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return egressHost{}, false
+		}
+		h.port = int32(port)
+	}
+	return h, true
+}
+
+// isExternalHost returns whether host is outside the cluster: it is neither a short name nor the
+// name of a Service of the cluster.
+func isExternalHost(host, clusterDomain string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	host = strings.TrimSuffix(host, ".")
+	return strings.Contains(host, ".") &&
+		!strings.HasSuffix(host, ".svc") &&
+		!strings.HasSuffix(host, "."+clusterDomain) &&
+		host != clusterDomain
+}
+
+// reconcileEgress declares the external hosts of sub as configured by the EgressConfigMapName
+// ConfigMap, and removes the declarations of the other modes.
+func (r *reconciler) reconcileEgress(ctx context.Context, sub *v1alpha1.Subscription) error {
+	config, err := r.getEgressConfig(ctx)
+	if err != nil {
+		r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to read the egress config: %v", err)
+		return err
+	}
+	hosts := externalHosts(sub, config)
+
+	var se *networkingv1alpha3.ServiceEntry
+	if config.mode == egressModeServiceEntry {
+		se = newEgressServiceEntry(sub, hosts)
+	}
+	if err := r.reconcileEgressServiceEntry(ctx, sub, se); err != nil {
+		return err
+	}
+
+	var np *networkingv1.NetworkPolicy
+	if config.mode == egressModeNetworkPolicy {
+		np, err = r.newEgressNetworkPolicy(sub, hosts)
+		if err != nil {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to resolve the external hosts: %v", err)
+			return err
+		}
+		if np != nil {
+			// The NetworkPolicy is not garbage collected with sub, so it is deleted by its finalizer.
+			addFinalizer(sub)
+		}
+	}
+	return r.reconcileEgressNetworkPolicy(ctx, sub, np)
+}
+
+// deleteEgress deletes the NetworkPolicy of sub, which is being deleted. Its ServiceEntry is
+// garbage collected with it.
+func (r *reconciler) deleteEgress(ctx context.Context, sub *v1alpha1.Subscription) error {
+	return r.reconcileEgressNetworkPolicy(ctx, sub, nil)
+}
+
+// egressServiceEntryName is the name of the ServiceEntry of the Subscription subName, in its
+// namespace.
+func egressServiceEntryName(subName string) string {
+	return fmt.Sprintf("%s-egress", subName)
+}
+
+// egressNetworkPolicyName is the name of the NetworkPolicy of a Subscription, in the system
+// namespace.
+func egressNetworkPolicyName(subName, namespace string) string {
+	return fmt.Sprintf("%s-%s-egress", namespace, subName)
+}
+
+// newEgressServiceEntry creates the ServiceEntry of the external hosts of sub, or returns nil if
+// it has none.
+func newEgressServiceEntry(sub *v1alpha1.Subscription, hosts []egressHost) *networkingv1alpha3.ServiceEntry {
+	spec := networkingv1alpha3.ServiceEntrySpec{
+		Location:   networkingv1alpha3.LocationMeshExternal,
+		Resolution: networkingv1alpha3.ResolutionDNS,
+	}
+	ports := make(map[int32]bool)
+	for _, h := range hosts {
+		// Istio registers the IP addresses of the mesh with the addresses of the ServiceEntries,
+		// not their hosts.
+		if net.ParseIP(h.host) != nil {
+			continue
+		}
+		if len(spec.Hosts) == 0 || spec.Hosts[len(spec.Hosts)-1] != h.host {
+			spec.Hosts = append(spec.Hosts, h.host)
+		}
+		if ports[h.port] {
+			continue
+		}
+		ports[h.port] = true
+		protocol := "HTTP"
+		if h.https {
+			protocol = "HTTPS"
+		}
+		spec.Ports = append(spec.Ports, networkingv1alpha3.Port{
+			Number:   h.port,
+			Protocol: protocol,
+			Name:     fmt.Sprintf("%s-%d", strings.ToLower(protocol), h.port),
+		})
+	}
+	if len(spec.Hosts) == 0 {
+		return nil
+	}
+	return &networkingv1alpha3.ServiceEntry{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sub.Namespace,
+			Name:      egressServiceEntryName(sub.Name),
+			Labels:    egressLabels(sub),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(sub, v1alpha1.SchemeGroupVersion.WithKind("Subscription")),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+// newEgressNetworkPolicy creates the NetworkPolicy allowing the dispatchers of the system namespace
+// to reach the addresses of the external hosts of sub, or returns nil if it has none. The hosts
+// are resolved every time sub is reconciled.
+func (r *reconciler) newEgressNetworkPolicy(sub *v1alpha1.Subscription, hosts []egressHost) (*networkingv1.NetworkPolicy, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+	lookupIP := r.lookupIP
+	if lookupIP == nil {
+		lookupIP = net.LookupIP
+	}
+	tcp := corev1.ProtocolTCP
+	var egress []networkingv1.NetworkPolicyEgressRule
+	for _, h := range hosts {
+		ips := []net.IP{net.ParseIP(h.host)}
+		if ips[0] == nil {
+			var err error
+			if ips, err = lookupIP(h.host); err != nil {
+				return nil, err
+			}
+		}
+		port := intstr.FromInt(int(h.port))
+		rule := networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+		}
+		for _, ip := range ips {
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: fmt.Sprintf("%s/%d", ip, bits)},
+			})
+		}
+		egress = append(egress, rule)
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      egressNetworkPolicyName(sub.Name, sub.Namespace),
+			Labels:    egressLabels(sub),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "dispatcher"}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}, nil
+}
+
+// egressLabels are the labels of the egress objects of sub.
+func egressLabels(sub *v1alpha1.Subscription) map[string]string {
+	return map[string]string{
+		"subscription":          sub.Name,
+		"subscriptionNamespace": sub.Namespace,
+	}
+}
+
+// isEgressOf returns whether o holds the egress of sub, rather than being an object of the same
+// name created by someone else.
+func isEgressOf(o metav1.Object, sub *v1alpha1.Subscription) bool {
+	return equality.Semantic.DeepEqual(o.GetLabels(), egressLabels(sub))
+}
+
+// reconcileEgressServiceEntry creates or updates the ServiceEntry of sub, or deletes it if expected
+// is nil. Nothing is done when Istio is not installed.
+func (r *reconciler) reconcileEgressServiceEntry(ctx context.Context, sub *v1alpha1.Subscription, expected *networkingv1alpha3.ServiceEntry) error {
+	name := egressServiceEntryName(sub.Name)
+	current := &networkingv1alpha3.ServiceEntry{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: sub.Namespace, Name: name}, current)
+	if meta.IsNoMatchError(err) && expected == nil {
+		return nil
+	} else if errors.IsNotFound(err) {
+		if expected == nil {
+			return nil
+		}
+		if err := r.client.Create(ctx, expected); err != nil {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to create ServiceEntry %q: %v", name, err)
+			return err
+		}
+		r.recorder.Eventf(sub, corev1.EventTypeNormal, egressCreated, "Created ServiceEntry %q", name)
+		return nil
+	} else if err != nil {
+		r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to get ServiceEntry %q: %v", name, err)
+		return err
+	}
+
+	if !metav1.IsControlledBy(current, sub) {
+		if expected != nil {
+			glog.Warningf("ServiceEntry %s/%s is not owned by Subscription %s", sub.Namespace, name, sub.Name)
+		}
+		return nil
+	}
+	if expected == nil {
+		if err := r.client.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to delete ServiceEntry %q: %v", name, err)
+			return err
+		}
+		r.recorder.Eventf(sub, corev1.EventTypeNormal, egressDeleted, "Deleted ServiceEntry %q", name)
+		return nil
+	}
+	if !equality.Semantic.DeepEqual(expected.Spec, current.Spec) {
+		current.Spec = expected.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to update ServiceEntry %q: %v", name, err)
+			return err
+		}
+		r.recorder.Eventf(sub, corev1.EventTypeNormal, egressUpdated, "Updated ServiceEntry %q", name)
+	}
+	return nil
+}
+
+// reconcileEgressNetworkPolicy creates or updates the NetworkPolicy of sub, or deletes it if
+// expected is nil.
+func (r *reconciler) reconcileEgressNetworkPolicy(ctx context.Context, sub *v1alpha1.Subscription, expected *networkingv1.NetworkPolicy) error {
+	name := egressNetworkPolicyName(sub.Name, sub.Namespace)
+	current := &networkingv1.NetworkPolicy{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: system.Namespace, Name: name}, current)
+	if errors.IsNotFound(err) {
+		if expected == nil {
+			return nil
+		}
+		if err := r.client.Create(ctx, expected); err != nil {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to create NetworkPolicy %q: %v", name, err)
+			return err
+		}
+		r.recorder.Eventf(sub, corev1.EventTypeNormal, egressCreated, "Created NetworkPolicy %q", name)
+		return nil
+	} else if err != nil {
+		r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to get NetworkPolicy %q: %v", name, err)
+		return err
+	}
+
+	if !isEgressOf(current, sub) {
+		if expected != nil {
+			glog.Warningf("NetworkPolicy %s/%s is not the egress of Subscription %s/%s", system.Namespace, name, sub.Namespace, sub.Name)
+		}
+		return nil
+	}
+	if expected == nil {
+		if err := r.client.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to delete NetworkPolicy %q: %v", name, err)
+			return err
+		}
+		r.recorder.Eventf(sub, corev1.EventTypeNormal, egressDeleted, "Deleted NetworkPolicy %q", name)
+		return nil
+	}
+	if !equality.Semantic.DeepEqual(expected.Spec, current.Spec) {
+		current.Spec = expected.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			r.recorder.Eventf(sub, corev1.EventTypeWarning, egressReconcileFailed, "Failed to update NetworkPolicy %q: %v", name, err)
+			return err
+		}
+		r.recorder.Eventf(sub, corev1.EventTypeNormal, egressUpdated, "Updated NetworkPolicy %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	networkingv1alpha3 "github.com/knative/eventing/pkg/apis/istio/networking/v1alpha3"
+	"github.com/knative/eventing/pkg/system"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewEgressConfigFromConfigMap(t *testing.T) {
+	testCases := map[string]struct {
+		data    map[string]string
+		want    *egressConfig
+		wantErr bool
+	}{
+		"empty": {
+			want: defaultEgressConfig(),
+		},
+		"service entries": {
+			data: map[string]string{"mode": "service-entry", "cluster-domain": "example.internal."},
+			want: &egressConfig{mode: egressModeServiceEntry, clusterDomain: "example.internal"},
+		},
+		"network policies": {
+			data: map[string]string{"mode": " network-policy "},
+			want: &egressConfig{mode: egressModeNetworkPolicy, clusterDomain: "cluster.local"},
+		},
+		"invalid mode": {
+			data:    map[string]string{"mode": "firewall"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := newEgressConfigFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v, actual %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(egressConfig{})); diff != "" {
+				t.Errorf("Unexpected config (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestExternalHosts(t *testing.T) {
+	sub := egressSubscription()
+	sub.Status.PhysicalSubscription.ReplyURI = "http://reply.mynamespace.svc.cluster.local/"
+	sub.Status.PhysicalSubscription.DeadLetterSinkURI = "hooks.example.com:8443"
+	sub.Status.PhysicalSubscription.OnErrorURI = "http://10.1.2.3/errors"
+
+	want := []egressHost{
+		{host: "10.1.2.3", port: 80},
+		{host: "api.example.com", port: 443, https: true},
+		{host: "hooks.example.com", port: 8443},
+	}
+	got := externalHosts(sub, defaultEgressConfig())
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(egressHost{})); diff != "" {
+		t.Errorf("Unexpected hosts (-want +got): %s", diff)
+	}
+
+	for _, host := range []string{"myfunction", "myfunction.mynamespace.svc", "myfunction.mynamespace.svc.cluster.local"} {
+		if isExternalHost(host, "cluster.local") {
+			t.Errorf("Expected %q to be in the cluster", host)
+		}
+	}
+}
+
+func TestReconcileEgress(t *testing.T) {
+	testCases := map[string]struct {
+		mode        egressMode
+		subscriber  string
+		existing    []runtime.Object
+		wantSE      *networkingv1alpha3.ServiceEntry
+		wantNP      *networkingv1.NetworkPolicy
+		wantFinal   bool
+		wantErr     bool
+		lookupError error
+	}{
+		"none": {
+			mode:       egressModeNone,
+			subscriber: "https://api.example.com/hook",
+		},
+		"service entry created": {
+			mode:       egressModeServiceEntry,
+			subscriber: "https://api.example.com/hook",
+			wantSE:     egressServiceEntry(),
+		},
+		"service entry updated": {
+			mode:       egressModeServiceEntry,
+			subscriber: "https://api.example.com/hook",
+			existing:   []runtime.Object{egressServiceEntryWithHost("old.example.com")},
+			wantSE:     egressServiceEntry(),
+		},
+		"service entry deleted with the external subscriber": {
+			mode:       egressModeServiceEntry,
+			subscriber: "http://myfunction.mynamespace.svc.cluster.local/",
+			existing:   []runtime.Object{egressServiceEntry()},
+		},
+		"service entry deleted with the mode": {
+			mode:       egressModeNone,
+			subscriber: "https://api.example.com/hook",
+			existing:   []runtime.Object{egressServiceEntry()},
+		},
+		"network policy created": {
+			mode:       egressModeNetworkPolicy,
+			subscriber: "https://api.example.com/hook",
+			wantNP:     egressNetworkPolicy(),
+			wantFinal:  true,
+		},
+		"network policy deleted with the mode": {
+			mode:       egressModeServiceEntry,
+			subscriber: "https://api.example.com/hook",
+			existing:   []runtime.Object{egressNetworkPolicy()},
+			wantSE:     egressServiceEntry(),
+		},
+		"network policy host not resolved": {
+			mode:        egressModeNetworkPolicy,
+			subscriber:  "https://api.example.com/hook",
+			lookupError: fmt.Errorf("no such host"),
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace, Name: EgressConfigMapName},
+				Data:       map[string]string{"mode": string(tc.mode)},
+			}
+			c := fake.NewFakeClient(append(tc.existing, cm)...)
+			r := &reconciler{
+				client:   c,
+				recorder: record.NewFakeRecorder(10),
+				lookupIP: func(host string) ([]net.IP, error) {
+					if tc.lookupError != nil {
+						return nil, tc.lookupError
+					}
+					return []net.IP{net.ParseIP("203.0.113.7"), net.ParseIP("2001:db8::7")}, nil
+				},
+			}
+			sub := egressSubscription()
+			sub.Status.PhysicalSubscription.SubscriberURI = tc.subscriber
+
+			err := r.reconcileEgress(context.TODO(), sub)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v, actual %v", tc.wantErr, err)
+			}
+
+			se := &networkingv1alpha3.ServiceEntry{}
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNS, Name: "testsubscription-egress"}, se)
+			verifyEgressObject(t, tc.wantSE != nil, tc.wantSE, se, err)
+			np := &networkingv1.NetworkPolicy{}
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: system.Namespace, Name: "testnamespace-testsubscription-egress"}, np)
+			verifyEgressObject(t, tc.wantNP != nil, tc.wantNP, np, err)
+
+			if final := len(sub.Finalizers) > 0; final != tc.wantFinal {
+				t.Errorf("Unexpected finalizer. Expected %v, actual %v", tc.wantFinal, final)
+			}
+		})
+	}
+}
+
+func TestDeleteEgress(t *testing.T) {
+	c := fake.NewFakeClient(egressNetworkPolicy())
+	r := &reconciler{
+		client:   c,
+		recorder: record.NewFakeRecorder(10),
+	}
+	if err := r.deleteEgress(context.TODO(), egressSubscription()); err != nil {
+		t.Fatalf("Unexpected error deleting the egress: %v", err)
+	}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: system.Namespace, Name: "testnamespace-testsubscription-egress"}, &networkingv1.NetworkPolicy{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected the NetworkPolicy to be deleted, got %v", err)
+	}
+}
+
+func verifyEgressObject(t *testing.T, wantPresent bool, want, got runtime.Object, err error) {
+	t.Helper()
+	if !wantPresent {
+		if !errors.IsNotFound(err) {
+			t.Errorf("Expected %T to be absent, got %v", got, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Unable to get %T: %v", got, err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreTypes(metav1.TypeMeta{}), cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion")); diff != "" {
+		t.Errorf("Unexpected %T (-want +got): %s", got, diff)
+	}
+}
+
+func egressSubscription() *eventingv1alpha1.Subscription {
+	sub := &eventingv1alpha1.Subscription{
+		TypeMeta:   subscriptionType(),
+		ObjectMeta: om(testNS, subscriptionName),
+	}
+	sub.UID = "test-uid"
+	sub.Status.PhysicalSubscription.SubscriberURI = "https://api.example.com/hook"
+	return sub
+}
+
+func egressServiceEntryWithHost(host string) *networkingv1alpha3.ServiceEntry {
+	return &networkingv1alpha3.ServiceEntry{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      "testsubscription-egress",
+			Labels: map[string]string{
+				"subscription":          subscriptionName,
+				"subscriptionNamespace": testNS,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         eventingv1alpha1.SchemeGroupVersion.String(),
+				Kind:               "Subscription",
+				Name:               subscriptionName,
+				UID:                "test-uid",
+				Controller:         &trueVal,
+				BlockOwnerDeletion: &trueVal,
+			}},
+		},
+		Spec: networkingv1alpha3.ServiceEntrySpec{
+			Hosts: []string{host},
+			Ports: []networkingv1alpha3.Port{{
+				Number:   443,
+				Protocol: "HTTPS",
+				Name:     "https-443",
+			}},
+			Location:   networkingv1alpha3.LocationMeshExternal,
+			Resolution: networkingv1alpha3.ResolutionDNS,
+		},
+	}
+}
+
+func egressServiceEntry() *networkingv1alpha3.ServiceEntry {
+	return egressServiceEntryWithHost("api.example.com")
+}
+
+func egressNetworkPolicy() *networkingv1.NetworkPolicy {
+	tcp := corev1.ProtocolTCP
+	port := intstr.FromInt(443)
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      "testnamespace-testsubscription-egress",
+			Labels: map[string]string{
+				"subscription":          subscriptionName,
+				"subscriptionNamespace": testNS,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "dispatcher"}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "203.0.113.7/32"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "2001:db8::7/128"}},
+				},
+			}},
+		},
+	}
+}
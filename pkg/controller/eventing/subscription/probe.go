@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const (
+	// subscriberProbeInterval is how often the subscriber of a Subscription is probed.
+	subscriberProbeInterval = 1 * time.Minute
+
+	// subscriberProbeTimeout is how long a probe waits for the subscriber to respond.
+	subscriberProbeTimeout = 5 * time.Second
+
+	// maxConcurrentProbes is how many subscribers are probed at the same time.
+	maxConcurrentProbes = 16
+)
+
+// subscriberProber returns an error if the subscriber at uri cannot be reached.
+type subscriberProber func(uri string) error
+
+// newHTTPProber creates a subscriberProber that sends an OPTIONS request to the subscriber. Any
+// response counts as reachable, as subscribers are not required to handle OPTIONS, except for the
+// responses a proxy sends when there is nothing behind it.
+func newHTTPProber() subscriberProber {
+	client := &http.Client{Timeout: subscriberProbeTimeout}
+	return func(uri string) error {
+		req, err := http.NewRequest(http.MethodOptions, uri, nil)
+		if err != nil {
+			return err
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		switch res.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return fmt.Errorf("unexpected response status %d", res.StatusCode)
+		}
+		return nil
+	}
+}
+
+// probeResult is the result of probing the subscriber at uri.
+type probeResult struct {
+	uri string
+	err error
+}
+
+// subscriberProbes probes subscribers in the background, so that unreachable subscribers do not
+// block the reconcile loop for the duration of the probe timeout.
+type subscriberProbes struct {
+	probe subscriberProber
+	// run runs a probe. It starts a goroutine, except in tests.
+	run func(func())
+	// changed is sent the Subscriptions whose probe result changed, so that they are reconciled
+	// again. Nothing is sent if it is nil.
+	changed chan<- event.GenericEvent
+	// sem limits the number of concurrent probes.
+	sem chan struct{}
+
+	mu      sync.Mutex
+	results map[types.NamespacedName]probeResult
+	probing map[types.NamespacedName]bool
+}
+
+func newSubscriberProbes(probe subscriberProber, changed chan<- event.GenericEvent) *subscriberProbes {
+	return &subscriberProbes{
+		probe:   probe,
+		run:     func(f func()) { go f() },
+		changed: changed,
+		sem:     make(chan struct{}, maxConcurrentProbes),
+		results: make(map[types.NamespacedName]probeResult),
+		probing: make(map[types.NamespacedName]bool),
+	}
+}
+
+// Result returns the result of the last completed probe of the subscriber at uri of subscription,
+// or nil if there is none yet. A new probe is started unless one is already in progress.
+func (p *subscriberProbes) Result(subscription *v1alpha1.Subscription, uri string) *probeResult {
+	key := types.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Name}
+	p.mu.Lock()
+	if !p.probing[key] {
+		p.probing[key] = true
+		s := subscription.DeepCopy()
+		p.mu.Unlock()
+		p.run(func() { p.probeSubscriber(key, s, uri) })
+		p.mu.Lock()
+	}
+	defer p.mu.Unlock()
+	if r, ok := p.results[key]; ok && r.uri == uri {
+		return &r
+	}
+	return nil
+}
+
+// Forget drops the probe results of the Subscription named key.
+func (p *subscriberProbes) Forget(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.results, key)
+}
+
+func (p *subscriberProbes) probeSubscriber(key types.NamespacedName, subscription *v1alpha1.Subscription, uri string) {
+	p.sem <- struct{}{}
+	err := p.probe(uri)
+	<-p.sem
+
+	p.mu.Lock()
+	old, ok := p.results[key]
+	p.results[key] = probeResult{uri: uri, err: err}
+	delete(p.probing, key)
+	p.mu.Unlock()
+
+	changed := !ok || old.uri != uri || (old.err == nil) != (err == nil)
+	if changed && p.changed != nil {
+		p.changed <- event.GenericEvent{Meta: subscription, Object: subscription}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestHTTPProber(t *testing.T) {
+	testCases := map[string]struct {
+		status  int
+		closed  bool
+		wantErr bool
+	}{
+		"ok": {
+			status: http.StatusOK,
+		},
+		"method not allowed": {
+			status: http.StatusMethodNotAllowed,
+		},
+		"not found": {
+			status: http.StatusNotFound,
+		},
+		"service unavailable": {
+			status:  http.StatusServiceUnavailable,
+			wantErr: true,
+		},
+		"bad gateway": {
+			status:  http.StatusBadGateway,
+			wantErr: true,
+		},
+		"connection refused": {
+			closed:  true,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodOptions {
+					t.Errorf("Unexpected method %q", r.Method)
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+			if tc.closed {
+				server.Close()
+			}
+
+			err := newHTTPProber()(server.URL + "/")
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error. Expected %v, actual %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSubscriberProbes(t *testing.T) {
+	subscription := &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: subscriptionName},
+	}
+	release := make(chan error)
+	changed := make(chan event.GenericEvent)
+	p := newSubscriberProbes(func(string) error { return <-release }, changed)
+
+	// The first probe runs in the background, so there is no result yet.
+	if r := p.Result(subscription, "http://a/"); r != nil {
+		t.Fatalf("Unexpected result before the probe completed: %v", r)
+	}
+	// No second probe is started while the first is in progress.
+	if r := p.Result(subscription, "http://a/"); r != nil {
+		t.Fatalf("Unexpected result before the probe completed: %v", r)
+	}
+
+	release <- errors.New("connection refused")
+	assertChanged(t, changed)
+	if r := p.Result(subscription, "http://a/"); r == nil || r.err == nil {
+		t.Fatalf("Expected a failed probe, actual %v", r)
+	}
+
+	// The same result again does not reconcile the Subscription.
+	release <- errors.New("connection reset")
+	select {
+	case e := <-changed:
+		t.Fatalf("Unexpected reconcile of %v", e.Meta.GetName())
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The result for another URI is not returned.
+	if r := p.Result(subscription, "http://b/"); r != nil {
+		t.Fatalf("Unexpected result for another URI: %v", r)
+	}
+	release <- nil
+	assertChanged(t, changed)
+	if r := p.Result(subscription, "http://b/"); r == nil || r.err != nil {
+		t.Fatalf("Expected a successful probe, actual %v", r)
+	}
+	release <- nil
+}
+
+func assertChanged(t *testing.T, changed <-chan event.GenericEvent) {
+	t.Helper()
+	select {
+	case e := <-changed:
+		if e.Meta.GetName() != subscriptionName {
+			t.Errorf("Unexpected reconcile of %q", e.Meta.GetName())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the Subscription to be reconciled")
+	}
+}
@@ -17,12 +17,17 @@ limitations under the License.
 package subscription
 
 import (
+	"net"
+
 	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -40,6 +45,14 @@ type reconciler struct {
 	restConfig    *rest.Config
 	dynamicClient dynamic.Interface
 	recorder      record.EventRecorder
+
+	// probes checks in the background that the resolved subscriber URI is reachable. Probing is
+	// disabled if it is nil.
+	probes *subscriberProbes
+
+	// lookupIP resolves the external hosts of the NetworkPolicies of the egressModeNetworkPolicy
+	// mode. net.LookupIP is used if it is nil.
+	lookupIP func(host string) ([]net.IP, error)
 }
 
 // Verify the struct implements reconcile.Reconciler
@@ -47,12 +60,14 @@ var _ reconcile.Reconciler = &reconciler{}
 
 // ProvideController returns a Subscription controller.
 func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Subscriptions are reconciled again when the result of probing their subscriber changes.
+	probed := make(chan event.GenericEvent)
+
 	// Setup a new controller to Reconcile Subscriptions.
-	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: &reconciler{
-			recorder: mgr.GetRecorder(controllerAgentName),
-		},
-	})
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+		probes:   newSubscriberProbes(newHTTPProber(), probed),
+	}))
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +77,10 @@ func ProvideController(mgr manager.Manager) (controller.Controller, error) {
 		return nil, err
 	}
 
+	if err := c.Watch(&source.Channel{Source: probed}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -19,29 +19,35 @@ package subscription
 import (
 	"context"
 	"fmt"
-	"net/url"
 
 	"github.com/golang/glog"
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/controller"
-	duckapis "github.com/knative/pkg/apis"
-	"github.com/knative/pkg/apis/duck"
-	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners/auth"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
 	finalizerName = controllerAgentName
+
+	// Reasons of the Events emitted on Subscriptions.
+	channelReferenceFetchFailed = "ChannelReferenceFetchFailed"
+	subscriberResolveFailed     = "SubscriberResolveFailed"
+	replyResolveFailed          = "ReplyResolveFailed"
+	deadLetterSinkResolveFailed = "DeadLetterSinkResolveFailed"
+	onErrorResolveFailed        = "OnErrorResolveFailed"
+	credentialsNotFound         = "CredentialsNotFound"
+	physicalChannelSyncFailed   = "PhysicalChannelSyncFailed"
+	subscriptionAdded           = "SubscriptionAdded"
+	subscriptionRemoved         = "SubscriptionRemoved"
 )
 
 // Reconcile compares the actual state with the desired, and attempts to
@@ -54,6 +60,9 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 
 	if errors.IsNotFound(err) {
 		glog.Errorf("could not find subscription %v\n", request)
+		if r.probes != nil {
+			r.probes.Forget(request.NamespacedName)
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -70,6 +79,12 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		return reconcile.Result{}, updateStatusErr
 	}
 
+	if err == nil && r.probes != nil && subscription.DeletionTimestamp == nil &&
+		subscription.Status.PhysicalSubscription.SubscriberURI != "" {
+		// Requeue to probe the subscriber again.
+		return reconcile.Result{RequeueAfter: subscriberProbeInterval}, nil
+	}
+
 	// Requeue if the resource is not ready:
 	return reconcile.Result{}, err
 }
@@ -93,25 +108,33 @@ func (r *reconciler) reconcile(subscription *v1alpha1.Subscription) error {
 			err := r.syncPhysicalChannel(subscription, true)
 			if err != nil {
 				glog.Warningf("Failed to sync physical from Channel : %s", err)
+				r.recorder.Eventf(subscription, corev1.EventTypeWarning, physicalChannelSyncFailed, "Failed to remove the subscription from Channel %q: %v", subscription.Spec.Channel.Name, err)
 				return err
 			}
+			r.recorder.Eventf(subscription, corev1.EventTypeNormal, subscriptionRemoved, "Removed the subscription from Channel %q", subscription.Spec.Channel.Name)
+		}
+		if err := r.deleteEgress(context.TODO(), subscription); err != nil {
+			glog.Warningf("Failed to delete the egress of the subscription: %s", err)
+			return err
 		}
 		removeFinalizer(subscription)
 		return nil
 	}
 
-	// Verify that `channel` exists.
-	_, err = r.fetchObjectReference(subscription.Namespace, &subscription.Spec.Channel)
+	// Verify that `channel` exists. It may be of any kind that is Subscribable.
+	_, err = controller.GetSubscribable(r.dynamicClient, subscription.Namespace, subscription.Spec.Channel)
 	if err != nil {
 		glog.Warningf("Failed to validate `channel` exists: %+v, %v", subscription.Spec.Channel, err)
+		r.recorder.Eventf(subscription, corev1.EventTypeWarning, channelReferenceFetchFailed, "Failed to get Channel %q: %v", subscription.Spec.Channel.Name, err)
 		return err
 	}
 
 	subscriberURI := ""
-	if !isNilOrEmptySubscriber(subscription.Spec.Subscriber) {
+	if !isNilOrEmptySubscriber(subscription.Spec.Subscriber) && !isPushSubscriber(subscription.Spec.Subscriber) {
 		subscriberURI, err = r.resolveSubscriberSpec(subscription.Namespace, *subscription.Spec.Subscriber)
 		if err != nil {
 			glog.Warningf("Failed to resolve Subscriber %+v : %s", *subscription.Spec.Subscriber, err)
+			r.recorder.Eventf(subscription, corev1.EventTypeWarning, subscriberResolveFailed, "Failed to resolve the subscriber: %v", err)
 			return err
 		}
 		if subscriberURI == "" {
@@ -119,6 +142,14 @@ func (r *reconciler) reconcile(subscription *v1alpha1.Subscription) error {
 		}
 		subscription.Status.PhysicalSubscription.SubscriberURI = subscriberURI
 		glog.Infof("Resolved subscriber to: %q", subscriberURI)
+
+		if r.probes != nil {
+			r.probe(subscription, subscriberURI)
+		}
+	}
+
+	if auth := subscriberAuth(subscription.Spec.Subscriber); auth != nil {
+		r.checkCredentials(subscription, auth)
 	}
 
 	replyURI := ""
@@ -126,6 +157,7 @@ func (r *reconciler) reconcile(subscription *v1alpha1.Subscription) error {
 		replyURI, err = r.resolveResult(subscription.Namespace, *subscription.Spec.Reply)
 		if err != nil {
 			glog.Warningf("Failed to resolve Result %v : %v", subscription.Spec.Reply, err)
+			r.recorder.Eventf(subscription, corev1.EventTypeWarning, replyResolveFailed, "Failed to resolve the reply: %v", err)
 			return err
 		}
 		if replyURI == "" {
@@ -136,17 +168,57 @@ func (r *reconciler) reconcile(subscription *v1alpha1.Subscription) error {
 		glog.Infof("Resolved reply to: %q", replyURI)
 	}
 
+	if subscription.Spec.Schema != nil && !isNilOrEmptySubscriber(subscription.Spec.Schema.DeadLetterSink) {
+		deadLetterSinkURI, err := r.resolveSubscriberSpec(subscription.Namespace, *subscription.Spec.Schema.DeadLetterSink)
+		if err != nil {
+			glog.Warningf("Failed to resolve dead letter sink %+v : %s", *subscription.Spec.Schema.DeadLetterSink, err)
+			r.recorder.Eventf(subscription, corev1.EventTypeWarning, deadLetterSinkResolveFailed, "Failed to resolve the dead letter sink: %v", err)
+			return err
+		}
+		if deadLetterSinkURI == "" {
+			return fmt.Errorf("could not get domain from dead letter sink (is it not targetable?)")
+		}
+		subscription.Status.PhysicalSubscription.DeadLetterSinkURI = deadLetterSinkURI
+		glog.Infof("Resolved dead letter sink to: %q", deadLetterSinkURI)
+	}
+
+	if !isNilOrEmptySubscriber(subscription.Spec.OnError) {
+		onErrorURI, err := r.resolveSubscriberSpec(subscription.Namespace, *subscription.Spec.OnError)
+		if err != nil {
+			glog.Warningf("Failed to resolve onError %+v : %s", *subscription.Spec.OnError, err)
+			r.recorder.Eventf(subscription, corev1.EventTypeWarning, onErrorResolveFailed, "Failed to resolve the error destination: %v", err)
+			return err
+		}
+		if onErrorURI == "" {
+			return fmt.Errorf("could not get domain from onError (is it not targetable?)")
+		}
+		subscription.Status.PhysicalSubscription.OnErrorURI = onErrorURI
+		glog.Infof("Resolved onError to: %q", onErrorURI)
+	} else {
+		subscription.Status.PhysicalSubscription.OnErrorURI = ""
+	}
+
 	// Everything that was supposed to be resolved was, so flip the status bit on that.
 	subscription.Status.MarkReferencesResolved()
 
+	// Declare the external subscribers before the channel sends them events.
+	if err := r.reconcileEgress(context.TODO(), subscription); err != nil {
+		glog.Warningf("Failed to reconcile the egress of the subscription: %s", err)
+		return err
+	}
+
 	// Ok, now that we have the Channel and at least one of the Call/Result, let's reconcile
 	// the Channel with this information.
 	err = r.syncPhysicalChannel(subscription, false)
 	if err != nil {
 		glog.Warningf("Failed to sync physical Channel : %s", err)
+		r.recorder.Eventf(subscription, corev1.EventTypeWarning, physicalChannelSyncFailed, "Failed to add the subscription to Channel %q: %v", subscription.Spec.Channel.Name, err)
 		return err
 	}
 	// Everything went well, set the fact that subscriptions have been modified
+	if c := subscription.Status.GetCondition(v1alpha1.SubscriptionConditionChannelReady); c == nil || !c.IsTrue() {
+		r.recorder.Eventf(subscription, corev1.EventTypeNormal, subscriptionAdded, "Added the subscription to Channel %q", subscription.Spec.Channel.Name)
+	}
 	subscription.Status.MarkChannelReady()
 	addFinalizer(subscription)
 	return nil
@@ -156,6 +228,12 @@ func isNilOrEmptySubscriber(sub *v1alpha1.SubscriberSpec) bool {
 	return sub == nil || equality.Semantic.DeepEqual(sub, &v1alpha1.SubscriberSpec{})
 }
 
+// isPushSubscriber returns true if the events are pushed to the consumers of sub connected to the
+// dispatcher, so it has no endpoint to resolve.
+func isPushSubscriber(sub *v1alpha1.SubscriberSpec) bool {
+	return sub != nil && sub.Protocol == eventingduck.SSEDeliveryProtocol
+}
+
 func isNilOrEmptyReply(reply *v1alpha1.ReplyStrategy) bool {
 	return reply == nil || equality.Semantic.DeepEqual(reply, &v1alpha1.ReplyStrategy{})
 }
@@ -192,87 +270,101 @@ func (r *reconciler) updateStatus(subscription *v1alpha1.Subscription) (*v1alpha
 	return newSubscription, nil
 }
 
-// resolveSubscriberSpec resolves the Spec.Call object. If it's an
-// ObjectReference will resolve the object and treat it as a Callable. If
-// it's DNSName then it's used as is.
-// TODO: Once Service Routes, etc. support Callable, use that.
-//
-func (r *reconciler) resolveSubscriberSpec(namespace string, s v1alpha1.SubscriberSpec) (string, error) {
-	if s.DNSName != nil && *s.DNSName != "" {
-		return *s.DNSName, nil
-	}
-
-	// K8s services are special cased. They can be called, even though they do not satisfy the
-	// Callable interface.
-	if s.Ref != nil && s.Ref.APIVersion == "v1" && s.Ref.Kind == "Service" {
-		svc := &corev1.Service{}
-		svcKey := types.NamespacedName{
-			Namespace: namespace,
-			Name:      s.Ref.Name,
-		}
-		err := r.client.Get(context.TODO(), svcKey, svc)
-		if err != nil {
-			glog.Warningf("Failed to fetch SubscriberSpec target as a K8s Service %+v: %s", s.Ref, err)
-			return "", err
-		}
-		return domainToURL(controller.ServiceHostName(svc.Name, svc.Namespace)), nil
-	}
+// probe reflects the result of the last probe of the subscriber in the SubscriberReachable
+// condition, and probes it again in the background. An event is recorded when the subscriber
+// becomes unreachable.
+func (r *reconciler) probe(subscription *v1alpha1.Subscription, subscriberURI string) {
+	result := r.probes.Result(subscription, subscriberURI)
+	if result == nil {
+		// Not probed yet, the Subscription is reconciled again once it is.
+		return
+	}
+	err := result.err
+	if err == nil {
+		subscription.Status.MarkSubscriberReachable()
+		return
+	}
+	glog.Warningf("Failed to probe subscriber %q: %v", subscriberURI, err)
+	if c := subscription.Status.GetCondition(v1alpha1.SubscriptionConditionSubscriberReachable); c == nil || !c.IsFalse() {
+		r.recorder.Eventf(subscription, corev1.EventTypeWarning, "SubscriberUnreachable", "Subscriber %q is unreachable: %v", subscriberURI, err)
+	}
+	subscription.Status.MarkSubscriberUnreachable("ProbeFailed", "Probing subscriber %q failed: %v", subscriberURI, err)
+}
 
-	obj, err := r.fetchObjectReference(namespace, s.Ref)
-	if err != nil {
-		glog.Warningf("Failed to fetch SubscriberSpec target %+v: %s", s.Ref, err)
-		return "", err
-	}
-	t := duckv1alpha1.AddressableType{}
-	err = duck.FromUnstructured(obj, &t)
-	if err != nil {
-		glog.Warningf("Failed to deserialize legacy target: %s", err)
-		return "", err
+// checkCredentials reflects whether the Secrets holding the credentials of the subscriber exist
+// and contain the referenced keys in the CredentialsProvided condition. Dispatchers fail the
+// deliveries they cannot authenticate, so an event is recorded when the credentials go missing.
+func (r *reconciler) checkCredentials(subscription *v1alpha1.Subscription, auth *eventingduck.SubscriberAuth) {
+	err := r.credentialsExist(subscription.Namespace, auth)
+	if err == nil {
+		subscription.Status.MarkCredentialsProvided()
+		return
 	}
-
-	if t.Status.Address != nil {
-		return domainToURL(t.Status.Address.Hostname), nil
+	glog.Warningf("Failed to find the credentials of the subscriber: %v", err)
+	if c := subscription.Status.GetCondition(v1alpha1.SubscriptionConditionCredentialsProvided); c == nil || !c.IsFalse() {
+		r.recorder.Eventf(subscription, corev1.EventTypeWarning, credentialsNotFound, "Deliveries to the subscriber will fail: %v", err)
 	}
-	return "", fmt.Errorf("status does not contain address")
+	subscription.Status.MarkNoCredentials(credentialsNotFound, "%v", err)
 }
 
-// resolveResult resolves the Spec.Result object.
-func (r *reconciler) resolveResult(namespace string, replyStrategy v1alpha1.ReplyStrategy) (string, error) {
-	obj, err := r.fetchObjectReference(namespace, replyStrategy.Channel)
-	if err != nil {
-		glog.Warningf("Failed to fetch ReplyStrategy channel %+v: %s", replyStrategy, err)
-		return "", err
+// credentialsExist returns an error if a Secret or key referenced by a is missing, or if a Secret
+// is not labelled for the dispatchers to use it.
+func (r *reconciler) credentialsExist(namespace string, a *eventingduck.SubscriberAuth) error {
+	var keys []corev1.SecretKeySelector
+	if a.BearerToken != nil {
+		keys = append(keys, *a.BearerToken)
 	}
-	s := duckv1alpha1.AddressableType{}
-	err = duck.FromUnstructured(obj, &s)
-	if err != nil {
-		glog.Warningf("Failed to deserialize Addressable target: %s", err)
-		return "", err
+	if a.Basic != nil {
+		keys = append(keys, a.Basic.Username, a.Basic.Password)
+	}
+	if a.OIDC != nil {
+		keys = append(keys, a.OIDC.ClientID, a.OIDC.ClientSecret)
+	}
+	if a.ClientCertificate != nil {
+		for _, k := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			keys = append(keys, corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: a.ClientCertificate.SecretName},
+				Key:                  k,
+			})
+		}
 	}
-	if s.Status.Address != nil {
-		return domainToURL(s.Status.Address.Hostname), nil
+
+	for _, k := range keys {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: k.Name}, secret); err != nil {
+			return fmt.Errorf("failed to get secret %q: %v", k.Name, err)
+		}
+		if _, ok := secret.Data[k.Key]; !ok {
+			return fmt.Errorf("secret %q does not contain key %q", k.Name, k.Key)
+		}
+		if secret.Labels[auth.SecretLabel] != "true" {
+			return fmt.Errorf("secret %q is not labelled %s=true", k.Name, auth.SecretLabel)
+		}
 	}
-	return "", fmt.Errorf("status does not contain address")
+	return nil
 }
 
-// fetchObjectReference fetches an object based on ObjectReference.
-func (r *reconciler) fetchObjectReference(namespace string, ref *corev1.ObjectReference) (duck.Marshalable, error) {
-	resourceClient, err := r.CreateResourceInterface(namespace, ref)
+// resolveSubscriberSpec resolves the Spec.Call object. If it's an
+// ObjectReference will resolve the object and treat it as a Callable. If
+// it's DNSName then it's used as is.
+// TODO: Once Service Routes, etc. support Callable, use that.
+//
+func (r *reconciler) resolveSubscriberSpec(namespace string, s v1alpha1.SubscriberSpec) (string, error) {
+	uri, err := controller.ResolveSubscriberSpec(context.TODO(), r.client, r.dynamicClient, namespace, s)
 	if err != nil {
-		glog.Warningf("failed to create dynamic client resource: %v", err)
-		return nil, err
+		glog.Warningf("Failed to resolve SubscriberSpec %+v: %s", s, err)
 	}
-
-	return resourceClient.Get(ref.Name, metav1.GetOptions{})
+	return uri, err
 }
 
-func domainToURL(domain string) string {
-	u := url.URL{
-		Scheme: "http",
-		Host:   domain,
-		Path:   "/",
+// resolveResult resolves the Spec.Result object, which may be of any kind that is Addressable.
+func (r *reconciler) resolveResult(namespace string, replyStrategy v1alpha1.ReplyStrategy) (string, error) {
+	hostname, err := controller.ResolveAddressable(r.dynamicClient, namespace, *replyStrategy.Channel)
+	if err != nil {
+		glog.Warningf("Failed to resolve ReplyStrategy channel %+v: %s", replyStrategy, err)
+		return "", err
 	}
-	return u.String()
+	return controller.DomainToURL(hostname), nil
 }
 
 func (r *reconciler) syncPhysicalChannel(sub *v1alpha1.Subscription, isDeleted bool) error {
@@ -292,7 +384,7 @@ func (r *reconciler) syncPhysicalChannel(sub *v1alpha1.Subscription, isDeleted b
 	}
 	subscribable := r.createSubscribable(subs)
 
-	if patchErr := r.patchPhysicalFrom(sub.Namespace, sub.Spec.Channel, subscribable); patchErr != nil {
+	if patchErr := controller.PatchSubscribable(r.dynamicClient, sub.Namespace, sub.Spec.Channel, subscribable); patchErr != nil {
 		if isDeleted && errors.IsNotFound(patchErr) {
 			glog.Infof("could not find channel %v\n", sub.Spec.Channel)
 			return nil
@@ -343,7 +435,7 @@ func (r *reconciler) listAllSubscriptionsWithPhysicalChannel(sub *v1alpha1.Subsc
 func (r *reconciler) createSubscribable(subs []v1alpha1.Subscription) *eventingduck.Subscribable {
 	rv := &eventingduck.Subscribable{}
 	for _, sub := range subs {
-		if sub.Status.PhysicalSubscription.SubscriberURI != "" || sub.Status.PhysicalSubscription.ReplyURI != "" {
+		if sub.Status.PhysicalSubscription.SubscriberURI != "" || sub.Status.PhysicalSubscription.ReplyURI != "" || isPushSubscriber(sub.Spec.Subscriber) {
 			rv.Subscribers = append(rv.Subscribers, eventingduck.ChannelSubscriberSpec{
 				Ref: &corev1.ObjectReference{
 					APIVersion: sub.APIVersion,
@@ -354,61 +446,62 @@ func (r *reconciler) createSubscribable(subs []v1alpha1.Subscription) *eventingd
 				},
 				SubscriberURI: sub.Status.PhysicalSubscription.SubscriberURI,
 				ReplyURI:      sub.Status.PhysicalSubscription.ReplyURI,
+				Filter:        filterExpression(sub.Spec.Filter),
+				Transform:     transformTemplate(sub.Spec.Transform),
+				Auth:          subscriberAuth(sub.Spec.Subscriber),
+				Schema:        subscriberSchema(sub.Spec.Schema),
+				DeadLetterURI: sub.Status.PhysicalSubscription.DeadLetterSinkURI,
+				ErrorURI:      sub.Status.PhysicalSubscription.OnErrorURI,
+				Paused:        sub.Spec.Paused,
+				Protocol:      subscriberProtocol(sub.Spec.Subscriber),
 			})
 		}
 	}
 	return rv
 }
 
-func (r *reconciler) patchPhysicalFrom(namespace string, physicalFrom corev1.ObjectReference, subs *eventingduck.Subscribable) error {
-	// First get the original object and convert it to only the bits we care about
-	s, err := r.fetchObjectReference(namespace, &physicalFrom)
-	if err != nil {
-		return err
-	}
-	original := eventingduck.Channel{}
-	err = duck.FromUnstructured(s, &original)
-	if err != nil {
-		return err
+// filterExpression returns the expression of f, or the empty string (which matches everything) if
+// there is no filter.
+func filterExpression(f *v1alpha1.SubscriptionFilter) string {
+	if f == nil {
+		return ""
 	}
+	return f.Expression
+}
 
-	after := original.DeepCopy()
-	after.Spec.Subscribable = subs
-
-	patch, err := duck.CreatePatch(original, after)
-	if err != nil {
-		return err
+// transformTemplate returns the template of t, or the empty string (which leaves events unchanged)
+// if there is no transform.
+func transformTemplate(t *v1alpha1.SubscriptionTransform) string {
+	if t == nil {
+		return ""
 	}
+	return t.Template
+}
 
-	patchBytes, err := patch.MarshalJSON()
-	if err != nil {
-		glog.Warningf("failed to marshal json patch: %s", err)
-		return err
+// subscriberAuth returns the credentials for deliveries to the subscriber, if any.
+func subscriberAuth(s *v1alpha1.SubscriberSpec) *eventingduck.SubscriberAuth {
+	if s == nil {
+		return nil
 	}
+	return s.Auth
+}
 
-	resourceClient, err := r.CreateResourceInterface(namespace, &physicalFrom)
-	if err != nil {
-		glog.Warningf("failed to create dynamic client resource: %v", err)
-		return err
+// subscriberProtocol returns the protocol of the deliveries to the subscriber, or the empty string
+// (HTTP) if there is no subscriber.
+func subscriberProtocol(s *v1alpha1.SubscriberSpec) eventingduck.DeliveryProtocol {
+	if s == nil {
+		return ""
 	}
-	patched, err := resourceClient.Patch(original.Name, types.JSONPatchType, patchBytes)
-	if err != nil {
-		glog.Warningf("Failed to patch the object: %s", err)
-		glog.Warningf("Patch was: %+v", patch)
-		return err
-	}
-	glog.Warningf("Patched resource: %+v", patched)
-	return nil
+	return s.Protocol
 }
 
-func (r *reconciler) CreateResourceInterface(namespace string, ref *corev1.ObjectReference) (dynamic.ResourceInterface, error) {
-	rc := r.dynamicClient.Resource(duckapis.KindToResource(ref.GroupVersionKind()))
-
-	if rc == nil {
-		return nil, fmt.Errorf("failed to create dynamic client resource")
+// subscriberSchema returns the schema the data of the events delivered to the subscriber must
+// conform to, if any.
+func subscriberSchema(s *v1alpha1.SubscriptionSchema) *eventingduck.SubscriberSchema {
+	if s == nil {
+		return nil
 	}
-	return rc.Namespace(namespace), nil
-
+	return &s.SubscriberSchema
 }
 
 func addFinalizer(sub *v1alpha1.Subscription) {
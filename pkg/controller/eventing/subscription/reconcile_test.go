@@ -17,17 +17,23 @@ limitations under the License.
 package subscription
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	networkingv1alpha3 "github.com/knative/eventing/pkg/apis/istio/networking/v1alpha3"
+	"github.com/knative/eventing/pkg/controller"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	"github.com/knative/eventing/pkg/provisioners/auth"
 	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -44,28 +50,34 @@ var (
 )
 
 const (
-	fromChannelName     = "fromchannel"
-	resultChannelName   = "resultchannel"
-	sourceName          = "source"
-	routeName           = "subscriberroute"
-	channelKind         = "Channel"
-	routeKind           = "Route"
-	sourceKind          = "Source"
-	subscriptionKind    = "Subscription"
-	targetDNS           = "myfunction.mynamespace.svc.cluster.local"
-	sinkableDNS         = "myresultchannel.mynamespace.svc.cluster.local"
-	eventType           = "myeventtype"
-	subscriptionName    = "testsubscription"
-	testNS              = "testnamespace"
-	k8sServiceName      = "testk8sservice"
-	k8sServiceDNS       = "testk8sservice.testnamespace.svc.cluster.local"
-	otherAddressableDNS = "other-sinkable-channel.mynamespace.svc.cluster.local"
+	fromChannelName       = "fromchannel"
+	resultChannelName     = "resultchannel"
+	sourceName            = "source"
+	routeName             = "subscriberroute"
+	channelKind           = "Channel"
+	routeKind             = "Route"
+	sourceKind            = "Source"
+	subscriptionKind      = "Subscription"
+	targetDNS             = "myfunction.mynamespace.svc.cluster.local"
+	sinkableDNS           = "myresultchannel.mynamespace.svc.cluster.local"
+	eventType             = "myeventtype"
+	subscriptionName      = "testsubscription"
+	testNS                = "testnamespace"
+	k8sServiceName        = "testk8sservice"
+	k8sServiceDNS         = "testk8sservice.testnamespace.svc.cluster.local"
+	otherAddressableDNS   = "other-sinkable-channel.mynamespace.svc.cluster.local"
+	credentialsSecretName = "subscriber-credentials"
+
+	// probeErr is the OtherTestData key of the error returned by the subscriber prober. The
+	// prober is disabled if it is not present.
+	probeErr = "probeErr"
 )
 
 func init() {
 	// Add types to scheme
 	eventingv1alpha1.AddToScheme(scheme.Scheme)
 	duckv1alpha1.AddToScheme(scheme.Scheme)
+	networkingv1alpha3.AddToScheme(scheme.Scheme)
 }
 
 var testCases = []controllertesting.TestCase{
@@ -553,6 +565,408 @@ var testCases = []controllertesting.TestCase{
 				},
 			},
 		},
+	}, {
+		Name: "new subscription to K8s Service: subscriber reachable",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService(),
+			getK8sService(),
+		},
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().ReferencesResolved().PhysicalSubscriber(k8sServiceDNS).Reply().SubscriberReachable(),
+		},
+		WantErrMsg: "invalid JSON document",
+		Scheme:     scheme.Scheme,
+		OtherTestData: map[string]interface{}{
+			probeErr: nil,
+		},
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+			// Subscriber (using K8s Service)
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      k8sServiceName,
+					},
+				},
+			},
+			// Reply channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      resultChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+					"status": map[string]interface{}{
+						"address": map[string]interface{}{
+							"hostname": sinkableDNS,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Name: "new subscription to K8s Service: new subscription to K8s Service: credentials provided",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService().BearerToken(),
+			getK8sService(),
+			getCredentialsSecret(),
+		},
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().BearerToken().ReferencesResolved().PhysicalSubscriber(k8sServiceDNS).Reply().CredentialsProvided(),
+		},
+		WantErrMsg: "invalid JSON document",
+		Scheme:     scheme.Scheme,
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+			// Subscriber (using K8s Service)
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      k8sServiceName,
+					},
+				},
+			},
+			// Reply channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      resultChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+					"status": map[string]interface{}{
+						"address": map[string]interface{}{
+							"hostname": sinkableDNS,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Name: "new subscription to K8s Service: new subscription to K8s Service: credentials not found",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService().BearerToken(),
+			getK8sService(),
+		},
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().BearerToken().ReferencesResolved().PhysicalSubscriber(k8sServiceDNS).Reply().NoCredentials(`failed to get secret "subscriber-credentials": secrets "subscriber-credentials" not found`),
+		},
+		WantErrMsg: "invalid JSON document",
+		Scheme:     scheme.Scheme,
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+			// Subscriber (using K8s Service)
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      k8sServiceName,
+					},
+				},
+			},
+			// Reply channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      resultChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+					"status": map[string]interface{}{
+						"address": map[string]interface{}{
+							"hostname": sinkableDNS,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Name: "new subscription to K8s Service: new subscription to K8s Service: credentials not labelled",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService().BearerToken(),
+			getK8sService(),
+			getUnlabelledCredentialsSecret(),
+		},
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().BearerToken().ReferencesResolved().PhysicalSubscriber(k8sServiceDNS).Reply().NoCredentials(`secret "subscriber-credentials" is not labelled eventing.knative.dev/subscriber-auth=true`),
+		},
+		WantErrMsg: "invalid JSON document",
+		Scheme:     scheme.Scheme,
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+			// Subscriber (using K8s Service)
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      k8sServiceName,
+					},
+				},
+			},
+			// Reply channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      resultChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+					"status": map[string]interface{}{
+						"address": map[string]interface{}{
+							"hostname": sinkableDNS,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Name: "new subscription to K8s Service: subscriber unreachable",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService(),
+			getK8sService(),
+		},
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().ReferencesResolved().PhysicalSubscriber(k8sServiceDNS).Reply().SubscriberUnreachable(k8sServiceDNS, errors.New("connection refused")),
+		},
+		WantErrMsg: "invalid JSON document",
+		Scheme:     scheme.Scheme,
+		OtherTestData: map[string]interface{}{
+			probeErr: errors.New("connection refused"),
+		},
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+			// Subscriber (using K8s Service)
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      k8sServiceName,
+					},
+				},
+			},
+			// Reply channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      resultChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+					"status": map[string]interface{}{
+						"address": map[string]interface{}{
+							"hostname": sinkableDNS,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Name: "new subscription to K8s Service port and path: adds status, all targets resolved, subscribers modified",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService().ServicePortAndPath(intstr.FromString("https"), "/events"),
+			getK8sService(),
+		},
+		// TODO: JSON patch is not working on the fake, see
+		// https://github.com/kubernetes/client-go/issues/478. Marking this as expecting a specific
+		// failure for now, until upstream is fixed.
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().ServicePortAndPath(intstr.FromString("https"), "/events").ReferencesResolved().PhysicalSubscriberURI("http://" + k8sServiceDNS + ":8443/events").Reply(),
+		},
+		WantErrMsg: "invalid JSON document",
+		Scheme:     scheme.Scheme,
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+			// Subscriber (using K8s Service)
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      k8sServiceName,
+					},
+				},
+			},
+			// Reply channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      resultChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+					"status": map[string]interface{}{
+						"address": map[string]interface{}{
+							"hostname": sinkableDNS,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		Name: "new subscription to K8s Service port that is not exposed: fails with no port found",
+		InitialState: []runtime.Object{
+			Subscription().ToK8sService().ServicePortAndPath(intstr.FromInt(9000), ""),
+			getK8sService(),
+		},
+		WantResult: reconcile.Result{},
+		WantPresent: []runtime.Object{
+			Subscription().ToK8sService().ServicePortAndPath(intstr.FromInt(9000), "").UnknownConditions(),
+		},
+		WantErrMsg: "service testnamespace/testk8sservice does not expose port 9000",
+		Scheme:     scheme.Scheme,
+		Objects: []runtime.Object{
+			// Source channel
+			&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": eventingv1alpha1.SchemeGroupVersion.String(),
+					"kind":       channelKind,
+					"metadata": map[string]interface{}{
+						"namespace": testNS,
+						"name":      fromChannelName,
+					},
+					"spec": map[string]interface{}{
+						"subscribable": map[string]interface{}{},
+					},
+				},
+			},
+		},
 	}, {
 		Name: "new subscription with from channel: adds status, all targets resolved, subscribers modified",
 		InitialState: []runtime.Object{
@@ -797,6 +1211,16 @@ func TestAllCases(t *testing.T) {
 			restConfig:    &rest.Config{},
 			recorder:      recorder,
 		}
+		if result, ok := tc.OtherTestData[probeErr]; ok {
+			r.probes = newSubscriberProbes(func(string) error {
+				if result == nil {
+					return nil
+				}
+				return result.(error)
+			}, nil)
+			// Probe synchronously, so that the first reconcile sees the result.
+			r.probes.run = func(f func()) { f() }
+		}
 		tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, subscriptionName)
 		tc.IgnoreTimes = true
 		t.Run(tc.Name, tc.Runner(t, r, c))
@@ -859,6 +1283,70 @@ func TestFinalizers(t *testing.T) {
 	}
 }
 
+func TestCreateSubscribable(t *testing.T) {
+	filtered := Subscription().PhysicalSubscriber(targetDNS).Reply().Subscription
+	filtered.Spec.Filter = &eventingv1alpha1.SubscriptionFilter{
+		Expression: "type = 'com.example.created'",
+	}
+	filtered.Spec.Transform = &eventingv1alpha1.SubscriptionTransform{
+		Template: `{"specversion": "1.0", "data": {{json .data}}}`,
+	}
+	filtered.Spec.Schema = &eventingv1alpha1.SubscriptionSchema{
+		SubscriberSchema: eventingduck.SubscriberSchema{
+			Registry: &eventingduck.SchemaRegistryRef{
+				URL:     "http://schema-registry.kafka:8081",
+				Subject: "orders-value",
+			},
+		},
+	}
+	filtered.Status.PhysicalSubscription.DeadLetterSinkURI = "http://dead-letter.test.svc.cluster.local/"
+	filtered.Status.PhysicalSubscription.OnErrorURI = "http://on-error.test.svc.cluster.local/"
+	filtered.Spec.Paused = true
+	unresolved := Subscription().Subscription
+	pushed := Subscription().Subscription
+	pushed.Name = "pushed"
+	pushed.Spec.Subscriber = &eventingv1alpha1.SubscriberSpec{Protocol: eventingduck.SSEDeliveryProtocol}
+
+	r := &reconciler{}
+	got := r.createSubscribable([]eventingv1alpha1.Subscription{*filtered, *unresolved, *pushed})
+	want := &eventingduck.Subscribable{
+		Subscribers: []eventingduck.ChannelSubscriberSpec{{
+			Ref: &corev1.ObjectReference{
+				APIVersion: filtered.APIVersion,
+				Kind:       filtered.Kind,
+				Namespace:  filtered.Namespace,
+				Name:       filtered.Name,
+				UID:        filtered.UID,
+			},
+			SubscriberURI: controller.DomainToURL(targetDNS),
+			ReplyURI:      controller.DomainToURL(sinkableDNS),
+			Filter:        "type = 'com.example.created'",
+			Transform:     `{"specversion": "1.0", "data": {{json .data}}}`,
+			Schema: &eventingduck.SubscriberSchema{
+				Registry: &eventingduck.SchemaRegistryRef{
+					URL:     "http://schema-registry.kafka:8081",
+					Subject: "orders-value",
+				},
+			},
+			DeadLetterURI: "http://dead-letter.test.svc.cluster.local/",
+			ErrorURI:      "http://on-error.test.svc.cluster.local/",
+			Paused:        true,
+		}, {
+			Ref: &corev1.ObjectReference{
+				APIVersion: pushed.APIVersion,
+				Kind:       pushed.Kind,
+				Namespace:  pushed.Namespace,
+				Name:       pushed.Name,
+				UID:        pushed.UID,
+			},
+			Protocol: eventingduck.SSEDeliveryProtocol,
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected subscribable (-want +got): %v", diff)
+	}
+}
+
 func getNewFromChannel() *eventingv1alpha1.Channel {
 	return getNewChannel(fromChannelName)
 }
@@ -957,13 +1445,54 @@ func (s *SubscriptionBuilder) ToK8sService() *SubscriptionBuilder {
 	return s
 }
 
+func (s *SubscriptionBuilder) ServicePortAndPath(port intstr.IntOrString, path string) *SubscriptionBuilder {
+	s.Spec.Subscriber.Port = &port
+	s.Spec.Subscriber.Path = path
+	return s
+}
+
 func (s *SubscriptionBuilder) UnknownConditions() *SubscriptionBuilder {
 	s.Status.InitializeConditions()
 	return s
 }
 
 func (s *SubscriptionBuilder) PhysicalSubscriber(dns string) *SubscriptionBuilder {
-	s.Status.PhysicalSubscription.SubscriberURI = domainToURL(dns)
+	s.Status.PhysicalSubscription.SubscriberURI = controller.DomainToURL(dns)
+	return s
+}
+
+func (s *SubscriptionBuilder) PhysicalSubscriberURI(uri string) *SubscriptionBuilder {
+	s.Status.PhysicalSubscription.SubscriberURI = uri
+	return s
+}
+
+func (s *SubscriptionBuilder) SubscriberReachable() *SubscriptionBuilder {
+	s.Status.MarkSubscriberReachable()
+	return s
+}
+
+func (s *SubscriptionBuilder) SubscriberUnreachable(dns string, err error) *SubscriptionBuilder {
+	s.Status.MarkSubscriberUnreachable("ProbeFailed", "Probing subscriber %q failed: %v", controller.DomainToURL(dns), err)
+	return s
+}
+
+func (s *SubscriptionBuilder) BearerToken() *SubscriptionBuilder {
+	s.Spec.Subscriber.Auth = &eventingduck.SubscriberAuth{
+		BearerToken: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: credentialsSecretName},
+			Key:                  "token",
+		},
+	}
+	return s
+}
+
+func (s *SubscriptionBuilder) CredentialsProvided() *SubscriptionBuilder {
+	s.Status.MarkCredentialsProvided()
+	return s
+}
+
+func (s *SubscriptionBuilder) NoCredentials(msg string) *SubscriptionBuilder {
+	s.Status.MarkNoCredentials("CredentialsNotFound", "%s", msg)
 	return s
 }
 
@@ -974,7 +1503,7 @@ func (s *SubscriptionBuilder) ReferencesResolved() *SubscriptionBuilder {
 }
 
 func (s *SubscriptionBuilder) Reply() *SubscriptionBuilder {
-	s.Status.PhysicalSubscription.ReplyURI = domainToURL(sinkableDNS)
+	s.Status.PhysicalSubscription.ReplyURI = controller.DomainToURL(sinkableDNS)
 	return s
 }
 
@@ -1020,6 +1549,29 @@ func subscriptionType() metav1.TypeMeta {
 	}
 }
 
+func getCredentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      credentialsSecretName,
+			Labels:    map[string]string{auth.SecretLabel: "true"},
+		},
+		Data: map[string][]byte{
+			"token": []byte("secret-token"),
+		},
+	}
+}
+
+func getUnlabelledCredentialsSecret() *corev1.Secret {
+	s := getCredentialsSecret()
+	s.Labels = nil
+	return s
+}
+
 func getK8sService() *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -1030,6 +1582,15 @@ func getK8sService() *corev1.Service {
 			Namespace: testNS,
 			Name:      k8sServiceName,
 		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{
+				Name: "http",
+				Port: 80,
+			}, {
+				Name: "https",
+				Port: 8443,
+			}},
+		},
 	}
 }
 
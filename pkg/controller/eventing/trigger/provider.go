@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package trigger
+
+import (
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingcontroller "github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "trigger-controller"
+)
+
+type reconciler struct {
+	client        client.Client
+	restConfig    *rest.Config
+	dynamicClient dynamic.Interface
+	recorder      record.EventRecorder
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a Trigger controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Setup a new controller to Reconcile Triggers.
+	r := &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+	}
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, r))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch Trigger events and enqueue Trigger object key.
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.Trigger{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the Subscriptions owned by Triggers.
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Subscription{}}, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.Trigger{}, IsController: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch channel-based Brokers and enqueue the keys of their Triggers, whose BrokerExists
+	// condition follows the Broker.
+	err = c.Watch(&source.Kind{Type: &v1alpha1.Broker{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: &mapBrokerToTriggers{r: r}}, eventingcontroller.BrokerClassPredicate(v1alpha1.ChannelBasedBrokerClass, v1alpha1.MultiTenantChannelBasedBrokerClass))
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+type mapBrokerToTriggers struct {
+	r *reconciler
+}
+
+// Map returns the requests of the Triggers of the Broker in o.
+func (m *mapBrokerToTriggers) Map(o handler.MapObject) []reconcile.Request {
+	triggers, err := m.r.listTriggers(o.Meta.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, t := range triggers {
+		if t.Spec.Broker == o.Meta.GetName() {
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: t.Namespace, Name: t.Name},
+			})
+		}
+	}
+	return reqs
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+func (r *reconciler) InjectConfig(c *rest.Config) error {
+	r.restConfig = c
+	var err error
+	r.dynamicClient, err = dynamic.NewForConfig(c)
+	return err
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package trigger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/eventing/trigger/resources"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Trigger resource
+// with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling trigger %v", request)
+	ctx := context.TODO()
+	trigger := &v1alpha1.Trigger{}
+	err := r.client.Get(ctx, request.NamespacedName, trigger)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find trigger %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch Trigger %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	if channelBased, err := r.isChannelBased(ctx, trigger); err != nil || !channelBased {
+		// Triggers of other classes of Broker are reconciled by the controller of that class.
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the Trigger and then write back any status
+	// updates regardless of whether the reconcile error out.
+	trigger = trigger.DeepCopy()
+	err = r.reconcile(ctx, trigger)
+	if updateStatusErr := r.updateStatus(ctx, trigger); updateStatusErr != nil {
+		glog.Warningf("Failed to update trigger status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+// isChannelBased returns true if the Broker of t is a channel-based Broker, multi-tenant or not,
+// or does not exist. In the latter case, this controller reports that the Broker does not exist.
+func (r *reconciler) isChannelBased(ctx context.Context, t *v1alpha1.Trigger) (bool, error) {
+	b := &v1alpha1.Broker{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: t.Namespace, Name: t.Spec.Broker}, b)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	class := b.Class()
+	return class == v1alpha1.ChannelBasedBrokerClass || class == v1alpha1.MultiTenantChannelBasedBrokerClass, nil
+}
+
+func (r *reconciler) reconcile(ctx context.Context, t *v1alpha1.Trigger) error {
+	t.Status.InitializeConditions()
+
+	if t.DeletionTimestamp != nil {
+		// The Subscription is owned by the Trigger and will be garbage collected. The Broker
+		// removes the Trigger's route when it sees the Trigger go away.
+		return nil
+	}
+
+	b := &v1alpha1.Broker{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: t.Namespace, Name: t.Spec.Broker}, b)
+	if errors.IsNotFound(err) {
+		// The Trigger is reconciled again when the Broker is created.
+		t.Status.MarkBrokerDoesNotExist("BrokerDoesNotExist", "Broker %s does not exist", t.Spec.Broker)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	t.Status.MarkBrokerExists()
+
+	if t.Spec.Subscriber == nil {
+		t.Status.SubscriberURI = ""
+		t.Status.MarkNotSubscribed("SubscriberMissing", "the Trigger does not have a subscriber")
+		return nil
+	}
+	subscriberURI, err := controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, t.Namespace, *t.Spec.Subscriber)
+	if err != nil {
+		glog.Warningf("Failed to resolve the subscriber of trigger %s/%s: %v", t.Namespace, t.Name, err)
+		t.Status.SubscriberURI = ""
+		t.Status.MarkNotSubscribed("SubscriberResolveFailed", "%v", err)
+		return err
+	}
+	t.Status.SubscriberURI = subscriberURI
+
+	deadLetterSinkURI, err := r.resolveDeadLetterSink(ctx, t, b)
+	if err != nil {
+		glog.Warningf("Failed to resolve the dead-letter sink of trigger %s/%s: %v", t.Namespace, t.Name, err)
+		t.Status.DeadLetterSinkURI = ""
+		t.Status.MarkNotSubscribed("DeadLetterSinkResolveFailed", "%v", err)
+		return err
+	}
+	t.Status.DeadLetterSinkURI = deadLetterSinkURI
+
+	sub, err := r.reconcileSubscription(ctx, t)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the Subscription of trigger %s/%s: %v", t.Namespace, t.Name, err)
+		t.Status.MarkNotSubscribed("SubscriptionFailure", "%v", err)
+		return err
+	}
+	if !sub.Status.IsReady() {
+		// The Trigger is reconciled again when the Subscription changes.
+		t.Status.MarkNotSubscribed("SubscriptionNotReady", "Subscription %s is not ready", sub.Name)
+		return nil
+	}
+	t.Status.MarkSubscribed()
+	return nil
+}
+
+// resolveDeadLetterSink resolves the dead-letter sink of the delivery that applies to t: its own,
+// or else the one of its Broker b. It returns an empty URI if there is no dead-letter sink.
+func (r *reconciler) resolveDeadLetterSink(ctx context.Context, t *v1alpha1.Trigger, b *v1alpha1.Broker) (string, error) {
+	delivery := t.Spec.Delivery
+	if delivery == nil {
+		delivery = b.Spec.Delivery
+	}
+	if delivery == nil || delivery.DeadLetterSink == nil {
+		return "", nil
+	}
+	// The dead-letter sink of the Broker is in the same namespace as the Trigger.
+	return controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, t.Namespace, *delivery.DeadLetterSink)
+}
+
+// reconcileSubscription creates the Subscription of t, or updates the subscriber of the existing
+// Subscription to match it.
+func (r *reconciler) reconcileSubscription(ctx context.Context, t *v1alpha1.Trigger) (*v1alpha1.Subscription, error) {
+	sub := resources.MakeSubscription(t)
+	current := &v1alpha1.Subscription{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: sub.Namespace, Name: sub.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, sub); err != nil {
+			return nil, err
+		}
+		return sub, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, t) {
+		return nil, fmt.Errorf("Subscription %s is not owned by the Trigger", current.Name)
+	}
+	if !equality.Semantic.DeepDerivative(sub.Spec.Subscriber, current.Spec.Subscriber) {
+		current.Spec.Subscriber = sub.Spec.Subscriber
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func (r *reconciler) listTriggers(namespace string) ([]v1alpha1.Trigger, error) {
+	triggers := make([]v1alpha1.Trigger, 0)
+
+	opts := &client.ListOptions{
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Trigger",
+			},
+		},
+		Namespace: namespace,
+	}
+	ctx := context.TODO()
+	for {
+		tl := &v1alpha1.TriggerList{}
+		if err := r.client.List(ctx, opts, tl); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, tl.Items...)
+		if tl.Continue != "" {
+			opts.Raw.Continue = tl.Continue
+		} else {
+			return triggers, nil
+		}
+	}
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, t *v1alpha1.Trigger) error {
+	current := &v1alpha1.Trigger{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: t.Namespace, Name: t.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, t.Status) {
+		return nil
+	}
+	current.Status = t.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the Trigger resource.
+	return r.client.Update(ctx, current)
+}
@@ -0,0 +1,508 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/eventing/trigger/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+const (
+	testNS      = "test-namespace"
+	triggerName = "test-trigger"
+	triggerUID  = "test-uid"
+	brokerName  = "test-broker"
+
+	subscriberServiceName = "subscriber"
+	subscriberURI         = "http://subscriber.test-namespace.svc.cluster.local/"
+	deadLetterServiceName = "dlq"
+	deadLetterSinkURI     = "http://dlq.test-namespace.svc.cluster.local/"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "Trigger not found",
+		},
+		{
+			Name: "Error getting Trigger",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Trigger{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Trigger being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingTrigger(),
+				makeBroker(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingTrigger(),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Broker does not exist",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+			},
+			WantPresent: []runtime.Object{
+				makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.MarkBrokerDoesNotExist("BrokerDoesNotExist", "Broker test-broker does not exist")
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Error getting Broker",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.Broker{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Broker of another class",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBrokerOfClass("KafkaBroker"),
+				makeSubscriberService(),
+			},
+			WantPresent: []runtime.Object{
+				// The status is left to the controller of the class.
+				makeTrigger(),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Broker is multi-tenant",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBrokerOfClass(v1alpha1.MultiTenantChannelBasedBrokerClass),
+				makeSubscriberService(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Subscriber cannot be resolved",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+			},
+			WantPresent: []runtime.Object{
+				makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.MarkBrokerExists()
+					s.MarkNotSubscribed("SubscriberResolveFailed", `services "subscriber" not found`)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+			WantErrMsg: `services "subscriber" not found`,
+		},
+		{
+			Name: "Subscription creation fails",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+				makeSubscriberService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&v1alpha1.Subscription{}),
+			},
+			WantPresent: []runtime.Object{
+				makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.MarkBrokerExists()
+					s.MarkNotSubscribed("SubscriptionFailure", testErrorMessage)
+					s.SubscriberURI = subscriberURI
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Subscription created, not ready yet",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+				makeSubscriberService(),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+				makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.MarkBrokerExists()
+					s.MarkNotSubscribed("SubscriptionNotReady", "Subscription test-trigger-trigger is not ready")
+					s.SubscriberURI = subscriberURI
+				}),
+			},
+		},
+		{
+			Name: "Subscription ready",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+				makeSubscriberService(),
+				makeReadySubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyTrigger(),
+			},
+		},
+		{
+			Name: "Dead-letter sink of the Broker",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBrokerWithDeadLetterSink(deadLetterServiceName),
+				makeSubscriberService(),
+				makeService(deadLetterServiceName),
+				makeReadySubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.DeadLetterSinkURI = deadLetterSinkURI
+				}),
+			},
+		},
+		{
+			Name: "Delivery of the Trigger overrides the Broker",
+			InitialState: []runtime.Object{
+				makeTriggerWithDelivery(&v1alpha1.DeliverySpec{Retry: 3}),
+				makeBrokerWithDeadLetterSink(deadLetterServiceName),
+				makeSubscriberService(),
+				makeService(deadLetterServiceName),
+				makeReadySubscription(),
+			},
+			WantPresent: []runtime.Object{
+				func() *v1alpha1.Trigger {
+					t := makeReadyTrigger()
+					t.Spec.Delivery = &v1alpha1.DeliverySpec{Retry: 3}
+					return t
+				}(),
+			},
+		},
+		{
+			Name: "Dead-letter sink cannot be resolved",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBrokerWithDeadLetterSink(deadLetterServiceName),
+				makeSubscriberService(),
+			},
+			WantPresent: []runtime.Object{
+				makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.MarkBrokerExists()
+					s.MarkNotSubscribed("DeadLetterSinkResolveFailed", `services "dlq" not found`)
+					s.SubscriberURI = subscriberURI
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeSubscription(),
+			},
+			WantErrMsg: `services "dlq" not found`,
+		},
+		{
+			Name: "Existing Subscription is updated",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+				makeSubscriberService(),
+				makeSubscriptionWithPath("/old"),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+			},
+		},
+		{
+			Name: "Existing Subscription is not owned by the Trigger",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+				makeSubscriberService(),
+				makeUnownedSubscription(),
+			},
+			WantPresent: []runtime.Object{
+				makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+					s.MarkBrokerExists()
+					s.MarkNotSubscribed("SubscriptionFailure", "Subscription test-trigger-trigger is not owned by the Trigger")
+					s.SubscriberURI = subscriberURI
+				}),
+			},
+			WantErrMsg: "Subscription test-trigger-trigger is not owned by the Trigger",
+		},
+		{
+			Name: "Updating Trigger status fails",
+			InitialState: []runtime.Object{
+				makeTrigger(),
+				makeBroker(),
+				makeSubscriberService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.Trigger{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSubscription(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:        c,
+			dynamicClient: tc.GetDynamicClient(),
+			restConfig:    &rest.Config{},
+			recorder:      recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, triggerName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func TestMapBrokerToTriggers(t *testing.T) {
+	other := makeTrigger()
+	other.Name = "other-trigger"
+	other.Spec.Broker = "other-broker"
+	c := fake.NewFakeClient(makeTrigger(), other)
+	m := &mapBrokerToTriggers{r: &reconciler{client: c}}
+
+	b := makeBroker()
+	reqs := m.Map(handler.MapObject{Meta: b, Object: b})
+	if len(reqs) != 1 || reqs[0].Namespace != testNS || reqs[0].Name != triggerName {
+		t.Errorf("Unexpected requests: %v", reqs)
+	}
+}
+
+func makeTrigger() *v1alpha1.Trigger {
+	return &v1alpha1.Trigger{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Trigger",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      triggerName,
+			UID:       triggerUID,
+		},
+		Spec: v1alpha1.TriggerSpec{
+			Broker: brokerName,
+			Filter: &v1alpha1.TriggerFilter{
+				Attributes: map[string]string{
+					"type": "dev.knative.foo",
+				},
+			},
+			Subscriber: &v1alpha1.SubscriberSpec{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       subscriberServiceName,
+				},
+			},
+		},
+	}
+}
+
+func makeTriggerWithStatus(f func(*v1alpha1.TriggerStatus)) *v1alpha1.Trigger {
+	t := makeTrigger()
+	t.Status.InitializeConditions()
+	f(&t.Status)
+	return t
+}
+
+func makeTriggerWithDelivery(delivery *v1alpha1.DeliverySpec) *v1alpha1.Trigger {
+	t := makeTrigger()
+	t.Spec.Delivery = delivery
+	return t
+}
+
+func makeReadyTrigger() *v1alpha1.Trigger {
+	return makeReadyTriggerWithStatus(func(*v1alpha1.TriggerStatus) {})
+}
+
+func makeReadyTriggerWithStatus(f func(*v1alpha1.TriggerStatus)) *v1alpha1.Trigger {
+	return makeTriggerWithStatus(func(s *v1alpha1.TriggerStatus) {
+		s.MarkBrokerExists()
+		s.MarkSubscribed()
+		s.SubscriberURI = subscriberURI
+		f(s)
+	})
+}
+
+func makeDeletingTrigger() *v1alpha1.Trigger {
+	t := makeTriggerWithStatus(func(*v1alpha1.TriggerStatus) {})
+	t.DeletionTimestamp = &deletionTime
+	return t
+}
+
+func makeBroker() *v1alpha1.Broker {
+	return &v1alpha1.Broker{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Broker",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      brokerName,
+		},
+	}
+}
+
+func makeBrokerOfClass(class string) *v1alpha1.Broker {
+	b := makeBroker()
+	b.Annotations = map[string]string{v1alpha1.BrokerClassAnnotationKey: class}
+	return b
+}
+
+func makeBrokerWithDeadLetterSink(name string) *v1alpha1.Broker {
+	b := makeBroker()
+	b.Spec.Delivery = &v1alpha1.DeliverySpec{
+		DeadLetterSink: &v1alpha1.SubscriberSpec{
+			Ref: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       name,
+			},
+		},
+	}
+	return b
+}
+
+func makeSubscriberService() *corev1.Service {
+	return makeService(subscriberServiceName)
+}
+
+func makeService(name string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      name,
+		},
+	}
+}
+
+func makeSubscription() *v1alpha1.Subscription {
+	s := resources.MakeSubscription(makeTrigger())
+	s.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+	}
+	return s
+}
+
+func makeSubscriptionWithPath(path string) *v1alpha1.Subscription {
+	s := makeSubscription()
+	s.Spec.Subscriber.Path = path
+	return s
+}
+
+func makeReadySubscription() *v1alpha1.Subscription {
+	s := makeSubscription()
+	s.Status.InitializeConditions()
+	s.Status.MarkReferencesResolved()
+	s.Status.MarkChannelReady()
+	return s
+}
+
+func makeUnownedSubscription() *v1alpha1.Subscription {
+	s := makeSubscription()
+	s.OwnerReferences = nil
+	return s
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
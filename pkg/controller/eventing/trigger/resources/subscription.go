@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/broker/filter"
+	brokerresources "github.com/knative/eventing/pkg/controller/eventing/broker/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TriggerLabelKey is the label that identifies the Trigger that a Subscription belongs to.
+	TriggerLabelKey = "eventing.knative.dev/trigger"
+)
+
+// SubscriptionName returns the name of the Subscription of the Trigger triggerName.
+func SubscriptionName(triggerName string) string {
+	return fmt.Sprintf("%s-trigger", triggerName)
+}
+
+// MakeSubscription returns the Subscription that delivers the events of the Broker of t to the
+// route for t in the Broker's filter.
+func MakeSubscription(t *v1alpha1.Trigger) *v1alpha1.Subscription {
+	return &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: t.Namespace,
+			Name:      SubscriptionName(t.Name),
+			Labels: map[string]string{
+				brokerresources.BrokerLabelKey: t.Spec.Broker,
+				TriggerLabelKey:                t.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(t, v1alpha1.SchemeGroupVersion.WithKind("Trigger")),
+			},
+		},
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Channel",
+				Name:       brokerresources.ChannelName(t.Spec.Broker),
+			},
+			Subscriber: &v1alpha1.SubscriberSpec{
+				Ref: &corev1.ObjectReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       brokerresources.FilterName(t.Spec.Broker),
+				},
+				Path: filter.RoutePath(t.Namespace, t.Name),
+			},
+		},
+	}
+}
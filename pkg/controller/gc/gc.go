@@ -0,0 +1,178 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc deletes the Services and VirtualServices of Channels that no longer exist. They are
+// owned by their Channel and garbage collected with it, but are orphaned when the Channel is
+// deleted without propagation or when the Channel CRD is deleted and recreated.
+package gc
+
+import (
+	"context"
+	"time"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// channelLabel and provisionerLabel are the labels of the Services and VirtualServices of
+	// Channels, see provisioners.CreateK8sService and provisioners.CreateVirtualService.
+	channelLabel     = "channel"
+	provisionerLabel = "provisioner"
+)
+
+// Sweeper periodically deletes the Services and VirtualServices of Channels that no longer exist.
+// It is a manager.Runnable, sweeping while it is started.
+type Sweeper struct {
+	client   client.Client
+	logger   *zap.Logger
+	interval time.Duration
+	dryRun   bool
+}
+
+// NewSweeper creates a Sweeper sweeping every interval. In dry run, the orphaned objects are
+// logged instead of deleted.
+func NewSweeper(c client.Client, interval time.Duration, dryRun bool, logger *zap.Logger) *Sweeper {
+	return &Sweeper{
+		client:   c,
+		logger:   logger,
+		interval: interval,
+		dryRun:   dryRun,
+	}
+}
+
+// Start sweeps every interval until stopCh is closed.
+func (s *Sweeper) Start(stopCh <-chan struct{}) error {
+	wait.Until(func() {
+		if _, err := s.Sweep(context.TODO()); err != nil {
+			s.logger.Error("Unable to sweep the orphaned objects of Channels", zap.Error(err))
+		}
+	}, s.interval, stopCh)
+	return nil
+}
+
+// Sweep deletes the Services and VirtualServices of Channels that no longer exist, or only logs
+// them in dry run. It returns the number of orphaned objects found.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	var orphans []orphan
+
+	sl := &corev1.ServiceList{}
+	if err := s.client.List(ctx, listOptions("v1", "Service"), sl); err != nil {
+		return 0, err
+	}
+	for i := range sl.Items {
+		svc := &sl.Items[i]
+		orphaned, err := s.isOrphaned(ctx, svc, provisioners.ChannelServiceName)
+		if err != nil {
+			return 0, err
+		}
+		if orphaned {
+			orphans = append(orphans, orphan{kind: "Service", obj: svc})
+		}
+	}
+
+	vsl := &istiov1alpha3.VirtualServiceList{}
+	if err := s.client.List(ctx, listOptions(istiov1alpha3.SchemeGroupVersion.String(), "VirtualService"), vsl); err != nil {
+		return 0, err
+	}
+	for i := range vsl.Items {
+		vs := &vsl.Items[i]
+		orphaned, err := s.isOrphaned(ctx, vs, provisioners.ChannelVirtualServiceName)
+		if err != nil {
+			return 0, err
+		}
+		if orphaned {
+			orphans = append(orphans, orphan{kind: "VirtualService", obj: vs})
+		}
+	}
+
+	for _, o := range orphans {
+		m := o.obj.(metav1.Object)
+		logger := s.logger.With(
+			zap.String("kind", o.kind),
+			zap.String("namespace", m.GetNamespace()),
+			zap.String("name", m.GetName()),
+			zap.String("channel", m.GetLabels()[channelLabel]))
+		if s.dryRun {
+			logger.Info("Found an orphaned object of a deleted Channel, not deleting it in dry run")
+			continue
+		}
+		if err := s.client.Delete(ctx, o.obj); err != nil && !errors.IsNotFound(err) {
+			return 0, err
+		}
+		logger.Info("Deleted an orphaned object of a deleted Channel")
+	}
+	return len(orphans), nil
+}
+
+// orphan is an object of a Channel that no longer exists.
+type orphan struct {
+	kind string
+	obj  runtime.Object
+}
+
+// isOrphaned returns true if o is named by name after its Channel, and the Channel does not exist
+// or is a new Channel with the same name as the Channel o was created for. The other objects with
+// the labels of Channels were not created by the provisioners, and are left alone.
+func (s *Sweeper) isOrphaned(ctx context.Context, o metav1.Object, name func(string) string) (bool, error) {
+	channelName := o.GetLabels()[channelLabel]
+	if o.GetName() != name(channelName) {
+		return false, nil
+	}
+	c := &eventingv1alpha1.Channel{}
+	err := s.client.Get(ctx, client.ObjectKey{Namespace: o.GetNamespace(), Name: channelName}, c)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if owner := metav1.GetControllerOf(o); owner != nil && owner.Kind == "Channel" && owner.UID != c.UID {
+		return true, nil
+	}
+	return false, nil
+}
+
+// listOptions selects the objects of kind in every namespace labeled with a Channel and a
+// provisioner.
+func listOptions(apiVersion, kind string) *client.ListOptions {
+	selector := labels.NewSelector()
+	for _, key := range []string{channelLabel, provisionerLabel} {
+		r, _ := labels.NewRequirement(key, selection.Exists, nil)
+		selector = selector.Add(*r)
+	}
+	return &client.ListOptions{
+		LabelSelector: selector,
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiVersion,
+				Kind:       kind,
+			},
+		},
+	}
+}
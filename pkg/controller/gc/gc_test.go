@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"testing"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNS = "test-namespace"
+
+func init() {
+	// Add types to scheme.
+	istiov1alpha3.AddToScheme(scheme.Scheme)
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func channel(name string, uid types.UID) *eventingv1alpha1.Channel {
+	return &eventingv1alpha1.Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Channel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      name,
+			UID:       uid,
+		},
+	}
+}
+
+func objectMeta(channelName string, owner types.UID) metav1.ObjectMeta {
+	om := metav1.ObjectMeta{
+		Namespace: testNS,
+		Name:      channelName + "-channel",
+		Labels: map[string]string{
+			channelLabel:     channelName,
+			provisionerLabel: "in-memory",
+		},
+	}
+	if owner != "" {
+		om.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Channel",
+			Name:       channelName,
+			UID:        owner,
+			Controller: &[]bool{true}[0],
+		}}
+	}
+	return om
+}
+
+func service(channelName string, owner types.UID) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: objectMeta(channelName, owner),
+	}
+}
+
+func virtualService(channelName string, owner types.UID) *istiov1alpha3.VirtualService {
+	return &istiov1alpha3.VirtualService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: istiov1alpha3.SchemeGroupVersion.String(),
+			Kind:       "VirtualService",
+		},
+		ObjectMeta: objectMeta(channelName, owner),
+	}
+}
+
+func TestSweep(t *testing.T) {
+	unrelated := service("app", "")
+	unrelated.Name = "app"
+
+	present := []runtime.Object{
+		// The objects of an existing Channel.
+		service("live", "live-uid"),
+		virtualService("live", "live-uid"),
+		// The owner references were removed, but the Channel exists.
+		service("unowned", ""),
+		// Not named after the Channel in its labels.
+		unrelated,
+	}
+	orphaned := []runtime.Object{
+		// The Channel was deleted.
+		service("deleted", "deleted-uid"),
+		virtualService("deleted", "deleted-uid"),
+		// The Channel was deleted, and the owner references were removed.
+		service("orphaned", ""),
+	}
+	stale := []runtime.Object{
+		// The Channel was recreated with the same name.
+		service("recreated", "old-uid"),
+		virtualService("recreated", "old-uid"),
+	}
+	channels := []runtime.Object{
+		channel("live", "live-uid"),
+		channel("unowned", "unowned-uid"),
+		channel("recreated", "new-uid"),
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		initial := append(append(append(append([]runtime.Object{}, channels...), present...), orphaned...), stale...)
+		c := fake.NewFakeClient(initial...)
+		s := NewSweeper(c, 0, dryRun, zap.NewNop())
+
+		n, err := s.Sweep(context.TODO())
+		if err != nil {
+			t.Fatalf("Unexpected error sweeping: %v", err)
+		}
+		if want := len(orphaned) + len(stale); n != want {
+			t.Errorf("Unexpected number of orphans in dry run %v. Expected %d. Actual %d", dryRun, want, n)
+		}
+
+		for _, o := range present {
+			if !exists(t, c, o) {
+				t.Errorf("Expected %v to be kept", o.(metav1.Object).GetName())
+			}
+		}
+		for _, o := range append(orphaned, stale...) {
+			if exists(t, c, o) == !dryRun {
+				t.Errorf("Expected %T %v to be deleted: %v", o, o.(metav1.Object).GetName(), !dryRun)
+			}
+		}
+	}
+}
+
+func exists(t *testing.T, c client.Client, o runtime.Object) bool {
+	t.Helper()
+	m := o.(metav1.Object)
+	current := o.DeepCopyObject()
+	err := c.Get(context.TODO(), client.ObjectKey{Namespace: m.GetNamespace(), Name: m.GetName()}, current)
+	if errors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error getting %v: %v", m.GetName(), err)
+	}
+	return true
+}
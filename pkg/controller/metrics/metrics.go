@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exports Prometheus metrics about the reconcile loops of the controllers: how
+// long reconciles take, how many fail, and the depth and latency of the work queues feeding them.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "controller",
+		Name:      "reconcile_duration_seconds",
+		Help:      "The time a reconcile takes, by controller.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"controller"})
+
+	reconciles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "controller",
+		Name:      "reconciles_total",
+		Help:      "The number of reconciles, by controller.",
+	}, []string{"controller"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "controller",
+		Name:      "reconcile_errors_total",
+		Help:      "The number of reconciles that returned an error, by controller.",
+	}, []string{"controller"})
+
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "controller",
+		Subsystem: "workqueue",
+		Name:      "depth",
+		Help:      "The number of keys waiting to be reconciled, by controller.",
+	}, []string{"controller"})
+
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "controller",
+		Subsystem: "workqueue",
+		Name:      "adds_total",
+		Help:      "The number of keys added to the work queue, by controller.",
+	}, []string{"controller"})
+
+	workqueueLatency = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace: "controller",
+		Subsystem: "workqueue",
+		Name:      "queue_latency_microseconds",
+		Help:      "The time keys wait in the work queue before being reconciled, by controller.",
+	}, []string{"controller"})
+
+	workqueueWorkDuration = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace: "controller",
+		Subsystem: "workqueue",
+		Name:      "work_duration_microseconds",
+		Help:      "The time the keys taken from the work queue are processed for, by controller.",
+	}, []string{"controller"})
+
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "controller",
+		Subsystem: "workqueue",
+		Name:      "retries_total",
+		Help:      "The number of keys requeued with a rate limit, by controller.",
+	}, []string{"controller"})
+)
+
+func init() {
+	prometheus.MustRegister(reconcileDuration, reconciles, reconcileErrors, workqueueDepth,
+		workqueueAdds, workqueueLatency, workqueueWorkDuration, workqueueRetries)
+	// controller-runtime names the work queue of each controller after it, so the work queue
+	// metrics are labeled by controller too.
+	workqueue.SetProvider(workqueueMetricsProvider{})
+}
+
+// InstrumentReconciler returns a Reconciler delegating to r that records the duration and the
+// errors of the reconciles of the controller named name. Dependencies injected by the manager into
+// the returned Reconciler are injected into r.
+func InstrumentReconciler(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &instrumentedReconciler{name: name, reconciler: r}
+}
+
+type instrumentedReconciler struct {
+	name       string
+	reconciler reconcile.Reconciler
+}
+
+var _ inject.Injector = &instrumentedReconciler{}
+
+func (r *instrumentedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := r.reconciler.Reconcile(request)
+	reconcileDuration.WithLabelValues(r.name).Observe(time.Since(start).Seconds())
+	reconciles.WithLabelValues(r.name).Inc()
+	if err != nil {
+		reconcileErrors.WithLabelValues(r.name).Inc()
+	}
+	return result, err
+}
+
+// InjectFunc injects the dependencies of the wrapped Reconciler, such as its client.
+func (r *instrumentedReconciler) InjectFunc(f inject.Func) error {
+	return f(r.reconciler)
+}
+
+// workqueueMetricsProvider provides the metrics of the work queues of the controllers.
+type workqueueMetricsProvider struct{}
+
+func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueAdds.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.SummaryMetric {
+	return workqueueLatency.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.SummaryMetric {
+	return workqueueWorkDuration.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueRetries.WithLabelValues(name)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+type fakeReconciler struct {
+	err    error
+	client client.Client
+}
+
+func (r *fakeReconciler) Reconcile(reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, r.err
+}
+
+func (r *fakeReconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatalf("Unable to read the metric: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestInstrumentReconciler(t *testing.T) {
+	name := "test-controller"
+	fr := &fakeReconciler{}
+	r := InstrumentReconciler(name, fr)
+
+	if _, err := r.Reconcile(reconcile.Request{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	fr.err = errors.New("test induced error")
+	if _, err := r.Reconcile(reconcile.Request{}); err != fr.err {
+		t.Errorf("Unexpected error. Expected %v. Actual %v", fr.err, err)
+	}
+
+	if got := counterValue(t, reconciles.WithLabelValues(name)); got != 2 {
+		t.Errorf("Unexpected number of reconciles. Expected 2. Actual %v", got)
+	}
+	if got := counterValue(t, reconcileErrors.WithLabelValues(name)); got != 1 {
+		t.Errorf("Unexpected number of reconcile errors. Expected 1. Actual %v", got)
+	}
+}
+
+func TestInstrumentReconcilerInjection(t *testing.T) {
+	fr := &fakeReconciler{}
+	c := fake.NewFakeClient()
+	injected, err := inject.InjectorInto(func(i interface{}) error {
+		_, err := inject.ClientInto(c, i)
+		return err
+	}, InstrumentReconciler("test-controller", fr))
+	if err != nil || !injected {
+		t.Fatalf("Unable to inject the dependencies: %v", err)
+	}
+	if fr.client != c {
+		t.Errorf("The client was not injected into the wrapped reconciler")
+	}
+}
+
+func TestWorkqueueMetrics(t *testing.T) {
+	name := "test-queue-controller"
+	q := workqueue.NewNamed(name)
+	defer q.ShutDown()
+	q.Add("key")
+	q.Add("other-key")
+
+	if got := counterValue(t, workqueueAdds.WithLabelValues(name)); got != 2 {
+		t.Errorf("Unexpected number of adds. Expected 2. Actual %v", got)
+	}
+	m := &dto.Metric{}
+	if err := workqueueDepth.WithLabelValues(name).Write(m); err != nil {
+		t.Fatalf("Unable to read the metric: %v", err)
+	}
+	if got := m.GetGauge().GetValue(); got != 2 {
+		t.Errorf("Unexpected depth. Expected 2. Actual %v", got)
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaced
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// multiNamespaceCache is a cache.Cache of the namespaced objects of a list of namespaces, with an
+// informer cache per namespace. Cluster scoped objects, such as ClusterChannelProvisioners, are
+// read from a cluster wide cache.
+type multiNamespaceCache struct {
+	scheme  *runtime.Scheme
+	mapper  meta.RESTMapper
+	cluster cache.Cache
+	// namespaces maps the watched namespaces to their cache.
+	namespaces map[string]cache.Cache
+}
+
+var _ cache.Cache = (*multiNamespaceCache)(nil)
+
+// newMultiNamespaceCache creates a cache of the objects in namespaces. The caches of the
+// namespaces list and watch through a config scoping the requests to their namespace.
+func newMultiNamespaceCache(config *rest.Config, opts cache.Options, namespaces []string) (*multiNamespaceCache, error) {
+	cluster, err := cache.New(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	c := &multiNamespaceCache{
+		scheme:     opts.Scheme,
+		mapper:     opts.Mapper,
+		cluster:    cluster,
+		namespaces: make(map[string]cache.Cache, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		nsCache, err := cache.New(scopedConfig(config, ns), opts)
+		if err != nil {
+			return nil, err
+		}
+		c.namespaces[ns] = nsCache
+	}
+	return c, nil
+}
+
+// scopedConfig returns a copy of config whose requests to collections of all namespaces, such as
+// /apis/eventing.knative.dev/v1alpha1/channels, are sent to the collections of namespace instead.
+func scopedConfig(config *rest.Config, namespace string) *rest.Config {
+	scoped := rest.CopyConfig(config)
+	wrap := scoped.WrapTransport
+	scoped.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrap != nil {
+			rt = wrap(rt)
+		}
+		return &namespaceRoundTripper{namespace: namespace, next: rt}
+	}
+	return scoped
+}
+
+// namespaceRoundTripper scopes the requests to collections of all namespaces to a namespace.
+type namespaceRoundTripper struct {
+	namespace string
+	next      http.RoundTripper
+}
+
+func (rt *namespaceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if path, ok := scopePath(req.URL.Path, rt.namespace); ok {
+		req = req.WithContext(req.Context())
+		u := *req.URL
+		u.Path = path
+		req.URL = &u
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// scopePath inserts namespaces/<namespace> before the resource of path, a request to a collection
+// of all namespaces such as /api/v1/configmaps or /apis/<group>/<version>/<resource>. It returns
+// false for any other path.
+func scopePath(path, namespace string) (string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var prefix int
+	switch {
+	case len(parts) == 3 && parts[0] == "api":
+		prefix = 2
+	case len(parts) == 4 && parts[0] == "apis":
+		prefix = 3
+	default:
+		return "", false
+	}
+	if parts[prefix] == "namespaces" {
+		return "", false
+	}
+	scoped := append(append(append([]string{}, parts[:prefix]...), "namespaces", namespace), parts[prefix:]...)
+	return "/" + strings.Join(scoped, "/"), true
+}
+
+// gvkFor returns the GroupVersionKind of obj, or of the items of obj if it is a list.
+func (c *multiNamespaceCache) gvkFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return gvk, err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	return gvk, nil
+}
+
+// isNamespaced returns whether objects of kind gvk are namespaced.
+func (c *multiNamespaceCache) isNamespaced(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// cacheFor returns the cache of the objects of kind gvk in namespace.
+func (c *multiNamespaceCache) cacheFor(gvk schema.GroupVersionKind, namespace string) (cache.Cache, error) {
+	namespaced, err := c.isNamespaced(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return c.cluster, nil
+	}
+	nsCache, ok := c.namespaces[namespace]
+	if !ok {
+		return nil, fmt.Errorf("namespace %q is not watched", namespace)
+	}
+	return nsCache, nil
+}
+
+// Get implements client.Reader.
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := c.gvkFor(obj)
+	if err != nil {
+		return err
+	}
+	objCache, err := c.cacheFor(gvk, key.Namespace)
+	if err != nil {
+		return err
+	}
+	return objCache.Get(ctx, key, obj)
+}
+
+// List implements client.Reader. Lists of all namespaces merge the lists of every watched
+// namespace.
+func (c *multiNamespaceCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	gvk, err := c.gvkFor(list)
+	if err != nil {
+		return err
+	}
+	namespaced, err := c.isNamespaced(gvk)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.List(ctx, opts, list)
+	}
+	if opts != nil && opts.Namespace != "" {
+		objCache, err := c.cacheFor(gvk, opts.Namespace)
+		if err != nil {
+			return err
+		}
+		return objCache.List(ctx, opts, list)
+	}
+	var items []runtime.Object
+	for _, nsCache := range c.namespaces {
+		nsList := list.DeepCopyObject()
+		if err := nsCache.List(ctx, opts, nsList); err != nil {
+			return err
+		}
+		nsItems, err := meta.ExtractList(nsList)
+		if err != nil {
+			return err
+		}
+		items = append(items, nsItems...)
+	}
+	return meta.SetList(list, items)
+}
+
+// GetInformer implements cache.Informers.
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	gvk, err := c.gvkFor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return c.informerFor(gvk, func(informers cache.Informers) (toolscache.SharedIndexInformer, error) {
+		return informers.GetInformer(obj)
+	})
+}
+
+// GetInformerForKind implements cache.Informers.
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	return c.informerFor(gvk, func(informers cache.Informers) (toolscache.SharedIndexInformer, error) {
+		return informers.GetInformerForKind(gvk)
+	})
+}
+
+// informerFor returns the informer of the objects of kind gvk, which combines the informers of
+// every watched namespace for namespaced objects.
+func (c *multiNamespaceCache) informerFor(gvk schema.GroupVersionKind, get func(cache.Informers) (toolscache.SharedIndexInformer, error)) (toolscache.SharedIndexInformer, error) {
+	namespaced, err := c.isNamespaced(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return get(c.cluster)
+	}
+	informer := &multiNamespaceInformer{}
+	for _, nsCache := range c.namespaces {
+		i, err := get(nsCache)
+		if err != nil {
+			return nil, err
+		}
+		informer.informers = append(informer.informers, i)
+	}
+	informer.SharedIndexInformer = informer.informers[0]
+	return informer, nil
+}
+
+// Start implements cache.Informers.
+func (c *multiNamespaceCache) Start(stop <-chan struct{}) error {
+	errs := make(chan error, len(c.namespaces)+1)
+	for _, informers := range c.all() {
+		go func(informers cache.Cache) {
+			errs <- informers.Start(stop)
+		}(informers)
+	}
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+		return nil
+	}
+}
+
+// WaitForCacheSync implements cache.Informers.
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	for _, informers := range c.all() {
+		if !informers.WaitForCacheSync(stop) {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexField implements client.FieldIndexer.
+func (c *multiNamespaceCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	for _, informers := range c.all() {
+		if err := informers.IndexField(obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// all returns the cluster cache and the caches of the namespaces.
+func (c *multiNamespaceCache) all() []cache.Cache {
+	caches := []cache.Cache{c.cluster}
+	for _, nsCache := range c.namespaces {
+		caches = append(caches, nsCache)
+	}
+	return caches
+}
+
+// multiNamespaceInformer combines the informers of the objects of a kind in several namespaces.
+// Event handlers are added to every informer, the other methods are those of the first one.
+type multiNamespaceInformer struct {
+	toolscache.SharedIndexInformer
+	informers []toolscache.SharedIndexInformer
+}
+
+// AddEventHandler implements toolscache.SharedInformer.
+func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+// AddEventHandlerWithResyncPeriod implements toolscache.SharedInformer.
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+// HasSynced implements toolscache.SharedInformer.
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaced
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/knative/eventing/pkg/system"
+)
+
+func TestScopePath(t *testing.T) {
+	testCases := map[string]string{
+		"/api/v1/configmaps":                                        "/api/v1/namespaces/team-a/configmaps",
+		"/apis/eventing.knative.dev/v1alpha1/channels":              "/apis/eventing.knative.dev/v1alpha1/namespaces/team-a/channels",
+		"/apis/eventing.knative.dev/v1alpha1/channels/":             "/apis/eventing.knative.dev/v1alpha1/namespaces/team-a/channels",
+		"/api/v1/namespaces/other/configmaps":                       "",
+		"/apis/eventing.knative.dev/v1alpha1/namespaces/o/channels": "",
+		"/api/v1/namespaces":                                        "",
+		"/api":                                                      "",
+		"/apis/eventing.knative.dev/v1alpha1":                       "",
+	}
+	for path, want := range testCases {
+		t.Run(path, func(t *testing.T) {
+			got, ok := scopePath(path, "team-a")
+			if ok != (want != "") || got != want {
+				t.Errorf("Unexpected scoped path. Expected %q. Actual %q, %v", want, got, ok)
+			}
+		})
+	}
+}
+
+type recordingRoundTripper struct {
+	paths []string
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.paths = append(rt.paths, req.URL.Path)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestScopedConfig(t *testing.T) {
+	recorder := &recordingRoundTripper{}
+	config := scopedConfig(&rest.Config{}, "team-a")
+	rt := config.WrapTransport(recorder)
+	for _, path := range []string{"/api/v1/configmaps", "/api/v1/namespaces/team-b/configmaps/c"} {
+		req := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: path}}
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.URL.Path != path {
+			t.Errorf("The request was modified: %q", req.URL.Path)
+		}
+	}
+	want := []string{"/api/v1/namespaces/team-a/configmaps", "/api/v1/namespaces/team-b/configmaps/c"}
+	if diff := cmp.Diff(want, recorder.paths); diff != "" {
+		t.Errorf("Unexpected paths (-want +got): %s", diff)
+	}
+}
+
+func TestParseNamespaces(t *testing.T) {
+	testCases := map[string][]string{
+		"":                      nil,
+		" , ":                   nil,
+		"team-a":                {"team-a", system.Namespace},
+		"team-a, team-b,":       {"team-a", "team-b", system.Namespace},
+		"team-a,team-a":         {"team-a", system.Namespace},
+		system.Namespace:        {system.Namespace},
+		"a," + system.Namespace: {"a", system.Namespace},
+	}
+	for s, want := range testCases {
+		t.Run(s, func(t *testing.T) {
+			if diff := cmp.Diff(want, ParseNamespaces(s)); diff != "" {
+				t.Errorf("Unexpected namespaces (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+// fakeCache is a cache.Cache reading from a fake client.
+type fakeCache struct {
+	client.Client
+}
+
+func (c *fakeCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	return nil, nil
+}
+
+func (c *fakeCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	return nil, nil
+}
+
+func (c *fakeCache) Start(stop <-chan struct{}) error {
+	return nil
+}
+
+func (c *fakeCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return true
+}
+
+func (c *fakeCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	return nil
+}
+
+func TestMultiNamespaceCache(t *testing.T) {
+	configMap := func(ns, name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+
+	// Each cache only has the objects of its namespace, as its requests are scoped to it.
+	c := &multiNamespaceCache{
+		scheme:  scheme.Scheme,
+		mapper:  mapper,
+		cluster: &fakeCache{fake.NewFakeClient(node, configMap("team-c", "c"))},
+		namespaces: map[string]cache.Cache{
+			"team-a": &fakeCache{fake.NewFakeClient(configMap("team-a", "a"))},
+			"team-b": &fakeCache{fake.NewFakeClient(configMap("team-b", "b1"), configMap("team-b", "b2"))},
+		},
+	}
+	ctx := context.TODO()
+
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "a"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Unexpected error getting a ConfigMap of a watched namespace: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "team-c", Name: "c"}, &corev1.ConfigMap{}); err == nil {
+		t.Errorf("Expected an error getting a ConfigMap of a namespace that is not watched")
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "node"}, &corev1.Node{}); err != nil {
+		t.Errorf("Unexpected error getting a cluster scoped object: %v", err)
+	}
+
+	names := func(list *corev1.ConfigMapList) []string {
+		var names []string
+		for _, cm := range list.Items {
+			names = append(names, cm.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	// The fake client needs the kind to list in the raw options.
+	listOptions := func(namespace, kind string) *client.ListOptions {
+		return &client.ListOptions{
+			Namespace: namespace,
+			Raw:       &metav1.ListOptions{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: kind}},
+		}
+	}
+	all := &corev1.ConfigMapList{}
+	if err := c.List(ctx, listOptions("", "ConfigMap"), all); err != nil {
+		t.Fatalf("Unexpected error listing all ConfigMaps: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b1", "b2"}, names(all)); diff != "" {
+		t.Errorf("Unexpected ConfigMaps (-want +got): %s", diff)
+	}
+	teamB := &corev1.ConfigMapList{}
+	if err := c.List(ctx, listOptions("team-b", "ConfigMap"), teamB); err != nil {
+		t.Fatalf("Unexpected error listing the ConfigMaps of a namespace: %v", err)
+	}
+	if diff := cmp.Diff([]string{"b1", "b2"}, names(teamB)); diff != "" {
+		t.Errorf("Unexpected ConfigMaps (-want +got): %s", diff)
+	}
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, listOptions("", "Node"), nodes); err != nil || len(nodes.Items) != 1 {
+		t.Errorf("Unexpected cluster scoped list: %v, %v", nodes.Items, err)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package namespaced runs controllers that only watch the objects of a list of namespaces, so that
+// they can be granted Roles in those namespaces instead of ClusterRoles. Cluster scoped objects,
+// such as ClusterChannelProvisioners, are still watched cluster wide. The objects of some resources
+// can also be restricted to the objects matching selectors, to bound the memory of the caches.
+package namespaced
+
+import (
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+
+	"github.com/knative/eventing/pkg/system"
+)
+
+// NamespacesEnv is the environment variable listing, separated by commas, the namespaces watched by
+// the controllers. They watch every namespace when it is not set.
+const NamespacesEnv = "WATCH_NAMESPACES"
+
+// NamespacesFromEnv returns the namespaces listed by NamespacesEnv.
+func NamespacesFromEnv() []string {
+	return ParseNamespaces(os.Getenv(NamespacesEnv))
+}
+
+// ParseNamespaces parses a list of namespaces separated by commas. The system namespace, where the
+// dispatchers and their configuration live, is added to non empty lists.
+func ParseNamespaces(s string) []string {
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, ns := range strings.Split(s, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	if len(namespaces) > 0 && !seen[system.Namespace] {
+		namespaces = append(namespaces, system.Namespace)
+	}
+	return namespaces
+}
+
+// NewManager returns a manager.Manager whose controllers only watch and read the namespaced objects
+// of namespaces, and the objects matching selectors of their resources. It is a plain manager
+// watching every namespace when namespaces is empty and there are no selectors.
+func NewManager(config *rest.Config, options manager.Options, namespaces []string, selectors ...Selector) (manager.Manager, error) {
+	mgr, err := manager.New(config, options)
+	if err != nil || (len(namespaces) == 0 && len(selectors) == 0) {
+		return mgr, err
+	}
+	cacheConfig := config
+	if len(selectors) > 0 {
+		cacheConfig = selectedConfig(config, selectors)
+	}
+	opts := cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		Resync: options.SyncPeriod,
+	}
+	var c cache.Cache
+	if len(namespaces) > 0 {
+		c, err = newMultiNamespaceCache(cacheConfig, opts, namespaces)
+	} else {
+		c, err = cache.New(cacheConfig, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	writer, err := client.New(config, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+	var reader client.Reader = c
+	if len(selectors) > 0 {
+		resources := make(map[schema.GroupResource]bool, len(selectors))
+		for _, s := range selectors {
+			resources[s.Resource] = true
+		}
+		reader = &selectedReader{
+			Reader:    c,
+			api:       writer,
+			scheme:    mgr.GetScheme(),
+			mapper:    mgr.GetRESTMapper(),
+			resources: resources,
+		}
+	}
+	return &namespacedManager{
+		Manager: mgr,
+		cache:   c,
+		client:  client.DelegatingClient{Reader: reader, Writer: writer, StatusClient: writer},
+	}, nil
+}
+
+// namespacedManager is a manager.Manager whose cache and client are replaced by a cache of the
+// watched namespaces or of the selected objects.
+type namespacedManager struct {
+	manager.Manager
+	cache  cache.Cache
+	client client.Client
+}
+
+// SetFields injects the dependencies of the manager into i. The sources keep the first cache
+// injected into them, while the other dependencies are overwritten, so the cache is injected first
+// and the client last.
+func (m *namespacedManager) SetFields(i interface{}) error {
+	if _, err := inject.CacheInto(m.cache, i); err != nil {
+		return err
+	}
+	if err := m.Manager.SetFields(i); err != nil {
+		return err
+	}
+	if _, err := inject.ClientInto(m.client, i); err != nil {
+		return err
+	}
+	_, err := inject.InjectorInto(m.SetFields, i)
+	return err
+}
+
+// Add adds r to the manager, with the dependencies of m.
+func (m *namespacedManager) Add(r manager.Runnable) error {
+	if err := m.Manager.Add(r); err != nil {
+		return err
+	}
+	return m.SetFields(r)
+}
+
+// Start starts the cache of m, waits for it to sync, then starts the manager.
+func (m *namespacedManager) Start(stop <-chan struct{}) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- m.cache.Start(stop)
+	}()
+	m.cache.WaitForCacheSync(stop)
+	go func() {
+		errs <- m.Manager.Start(stop)
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+		return nil
+	}
+}
+
+// GetCache returns the cache of the watched objects.
+func (m *namespacedManager) GetCache() cache.Cache {
+	return m.cache
+}
+
+// GetClient returns a client reading from the cache of the watched objects.
+func (m *namespacedManager) GetClient() client.Client {
+	return m.client
+}
+
+// GetFieldIndexer returns the cache of the watched objects.
+func (m *namespacedManager) GetFieldIndexer() client.FieldIndexer {
+	return m.cache
+}
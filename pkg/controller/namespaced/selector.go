@@ -0,0 +1,156 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaced
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Selector restricts the objects of a resource that the controllers watch and cache to the objects
+// matching a label selector and a field selector, so that the controllers of large clusters do not
+// cache all of them.
+type Selector struct {
+	// Resource is the resource whose objects are selected, e.g. services in the core group.
+	Resource schema.GroupResource
+	// Labels is the label selector of the objects, e.g. provisioner=kafka.
+	Labels string
+	// Fields is the field selector of the objects, e.g. metadata.namespace=knative-eventing.
+	Fields string
+}
+
+// selectedConfig returns a copy of config whose requests listing or watching the collections of
+// the resources of selectors only return the objects they select.
+func selectedConfig(config *rest.Config, selectors []Selector) *rest.Config {
+	selected := rest.CopyConfig(config)
+	wrap := selected.WrapTransport
+	selected.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrap != nil {
+			rt = wrap(rt)
+		}
+		return &selectorRoundTripper{selectors: selectors, next: rt}
+	}
+	return selected
+}
+
+// selectorRoundTripper adds the label and field selectors of the selected resources to the
+// requests to their collections.
+type selectorRoundTripper struct {
+	selectors []Selector
+	next      http.RoundTripper
+}
+
+func (rt *selectorRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return rt.next.RoundTrip(req)
+	}
+	resource, ok := collectionResource(req.URL.Path)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+	for _, s := range rt.selectors {
+		if s.Resource != resource {
+			continue
+		}
+		u := *req.URL
+		q := u.Query()
+		addSelector(q, "labelSelector", s.Labels)
+		addSelector(q, "fieldSelector", s.Fields)
+		u.RawQuery = q.Encode()
+		req = req.WithContext(req.Context())
+		req.URL = &u
+		break
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// addSelector adds selector to the selector of the query parameter key, which must match both.
+func addSelector(q map[string][]string, key, selector string) {
+	if selector == "" {
+		return
+	}
+	if current := strings.Join(q[key], ","); current != "" {
+		selector = current + "," + selector
+	}
+	q[key] = []string{selector}
+}
+
+// collectionResource returns the resource of path, a request to a collection of all namespaces or
+// of a namespace such as /api/v1/services or /apis/<group>/<version>/namespaces/<ns>/<resource>.
+// It returns false for any other path.
+func collectionResource(path string) (schema.GroupResource, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var group string
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		group = parts[1]
+		parts = parts[3:]
+	default:
+		return schema.GroupResource{}, false
+	}
+	if len(parts) == 3 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+	if len(parts) != 1 || parts[0] == "namespaces" {
+		return schema.GroupResource{}, false
+	}
+	return schema.GroupResource{Group: group, Resource: parts[0]}, true
+}
+
+// selectedReader reads the objects from a cache of the objects matching selectors. The objects of
+// the selected resources that are not in the cache, such as the objects created before the labels
+// they are selected by, are read from the API server instead.
+type selectedReader struct {
+	client.Reader
+	api       client.Reader
+	scheme    *runtime.Scheme
+	mapper    meta.RESTMapper
+	resources map[schema.GroupResource]bool
+}
+
+// Get implements client.Reader.
+func (r *selectedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	err := r.Reader.Get(ctx, key, obj)
+	if !errors.IsNotFound(err) || !r.isSelected(obj) {
+		return err
+	}
+	return r.api.Get(ctx, key, obj)
+}
+
+// isSelected returns whether obj is an object of a selected resource.
+func (r *selectedReader) isSelected(obj runtime.Object) bool {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return false
+	}
+	mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false
+	}
+	return r.resources[mapping.Resource.GroupResource()]
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespaced
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCollectionResource(t *testing.T) {
+	services := schema.GroupResource{Resource: "services"}
+	virtualServices := schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"}
+	testCases := map[string]*schema.GroupResource{
+		"/api/v1/services":                                                       &services,
+		"/api/v1/namespaces/team-a/services":                                     &services,
+		"/apis/networking.istio.io/v1alpha3/virtualservices":                     &virtualServices,
+		"/apis/networking.istio.io/v1alpha3/namespaces/team-a/virtualservices/":  &virtualServices,
+		"/api/v1/namespaces/team-a/services/s":                                   nil,
+		"/apis/networking.istio.io/v1alpha3/namespaces/team-a/virtualservices/v": nil,
+		"/api/v1/namespaces":                                                     nil,
+		"/api/v1/namespaces/team-a":                                              nil,
+		"/api":                                                                   nil,
+		"/apis/networking.istio.io/v1alpha3":                                     nil,
+	}
+	for path, want := range testCases {
+		t.Run(path, func(t *testing.T) {
+			got, ok := collectionResource(path)
+			if ok != (want != nil) {
+				t.Fatalf("Unexpected collection. Expected %v. Actual %v", want != nil, ok)
+			}
+			if want != nil && got != *want {
+				t.Errorf("Unexpected resource. Expected %v. Actual %v", *want, got)
+			}
+		})
+	}
+}
+
+type queryRecordingRoundTripper struct {
+	queries map[string]url.Values
+}
+
+func (rt *queryRecordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.queries[req.Method+" "+req.URL.Path] = req.URL.Query()
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestSelectedConfig(t *testing.T) {
+	recorder := &queryRecordingRoundTripper{queries: make(map[string]url.Values)}
+	config := selectedConfig(&rest.Config{}, []Selector{{
+		Resource: schema.GroupResource{Resource: "services"},
+		Labels:   "provisioner=kafka",
+	}, {
+		Resource: schema.GroupResource{Resource: "configmaps"},
+		Fields:   "metadata.namespace=knative-eventing",
+	}})
+	rt := config.WrapTransport(recorder)
+	requests := []struct {
+		method string
+		url    string
+	}{
+		{http.MethodGet, "/api/v1/services?watch=true"},
+		{http.MethodGet, "/api/v1/namespaces/team-a/configmaps?labelSelector=a%3Db"},
+		{http.MethodGet, "/api/v1/namespaces/team-a/services/s"},
+		{http.MethodGet, "/api/v1/secrets"},
+		{http.MethodPost, "/api/v1/namespaces/team-a/services"},
+	}
+	for _, r := range requests {
+		u, err := url.Parse(r.url)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := rt.RoundTrip(&http.Request{Method: r.method, URL: u}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	want := map[string]url.Values{
+		"GET /api/v1/services": {
+			"watch":         {"true"},
+			"labelSelector": {"provisioner=kafka"},
+		},
+		"GET /api/v1/namespaces/team-a/configmaps": {
+			"labelSelector": {"a=b"},
+			"fieldSelector": {"metadata.namespace=knative-eventing"},
+		},
+		"GET /api/v1/namespaces/team-a/services/s": {},
+		"GET /api/v1/secrets":                      {},
+		"POST /api/v1/namespaces/team-a/services":  {},
+	}
+	if diff := cmp.Diff(want, recorder.queries); diff != "" {
+		t.Errorf("Unexpected queries (-want +got): %s", diff)
+	}
+}
+
+func TestSelectedReader(t *testing.T) {
+	service := func(name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name}}
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "unselected"}}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	r := &selectedReader{
+		Reader:    fake.NewFakeClient(service("selected")),
+		api:       fake.NewFakeClient(service("selected"), service("unselected"), configMap),
+		scheme:    scheme.Scheme,
+		mapper:    mapper,
+		resources: map[schema.GroupResource]bool{{Resource: "services"}: true},
+	}
+
+	// The selected objects are read from the cache.
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "selected"}, &corev1.Service{}); err != nil {
+		t.Errorf("Unexpected error getting a cached Service: %v", err)
+	}
+	// The objects of the selected resources that are not cached are read from the API server.
+	got := &corev1.Service{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "unselected"}, got); err != nil {
+		t.Errorf("Unexpected error getting an uncached Service: %v", err)
+	}
+	if got.Name != "unselected" {
+		t.Errorf("Unexpected Service: %v", got)
+	}
+	// The objects of the other resources are only read from the cache.
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "unselected"}, &corev1.ConfigMap{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Unexpected error getting an uncached ConfigMap. Expected NotFound. Actual %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ProvideController returns the controller named controllerAgentName of the sources of k, which
+// reconciles them with a Reconciler when they or their receive adapter Deployment change.
+func ProvideController(mgr manager.Manager, controllerAgentName string, k Kind) (controller.Controller, error) {
+	// Setup a new controller to Reconcile the sources.
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, &Reconciler{Kind: k}))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the sources and enqueue their object key.
+	if err := c.Watch(&source.Kind{Type: k.New()}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the Deployments owned by the sources.
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{OwnerType: k.New(), IsController: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package adapter reconciles the sources whose events are sent to their sink by a single
+// Deployment, the receive adapter, such as ContainerSources and AwsSqsSources. The sources only
+// describe their kind, and their receive adapter Deployment.
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Source is a source reconciled by a Reconciler, such as a *v1alpha1.AwsSqsSource.
+type Source interface {
+	runtime.Object
+	metav1.Object
+}
+
+// Status is the status of a Source, with the conditions of its sink and of its receive adapter.
+type Status interface {
+	InitializeConditions()
+	MarkSink(uri string)
+	MarkNoSink(reason, messageFormat string, messageA ...interface{})
+	MarkDeployed()
+	MarkNotDeployed(reason, messageFormat string, messageA ...interface{})
+}
+
+// Kind gives a Reconciler access to the sources of one kind. Its methods are only called with
+// sources returned by New.
+type Kind interface {
+	// Name returns the name of the kind, such as "AwsSqsSource".
+	Name() string
+	// New returns an empty source of the kind.
+	New() Source
+	// Sink returns the reference to the addressable that receives the events of s.
+	Sink(s Source) *corev1.ObjectReference
+	// Status returns the status of s.
+	Status(s Source) Status
+	// CopyStatus sets the status of dst to the status of src.
+	CopyStatus(dst, src Source)
+	// ReceiveAdapter returns the Deployment of the receive adapter of s, which sends the events
+	// of s to sinkURI. The Deployment must be controlled by s.
+	ReceiveAdapter(s Source, sinkURI string) *appsv1.Deployment
+}
+
+// Reconciler resolves the sink of the sources of Kind, and creates their receive adapter
+// Deployment, or updates it to match the source.
+type Reconciler struct {
+	Kind Kind
+
+	// The clients are injected by the manager of the controller.
+	Client        client.Client
+	DynamicClient dynamic.Interface
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &Reconciler{}
+
+func (r *Reconciler) InjectClient(c client.Client) error {
+	r.Client = c
+	return nil
+}
+
+func (r *Reconciler) InjectConfig(c *rest.Config) error {
+	var err error
+	r.DynamicClient, err = dynamic.NewForConfig(c)
+	return err
+}
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the source with the
+// current status of the resource.
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	kind := r.Kind.Name()
+	glog.Infof("Reconciling %s %v", kind, request)
+	ctx := context.TODO()
+	source := r.Kind.New()
+	err := r.Client.Get(ctx, request.NamespacedName, source)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find %s %v\n", kind, request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch %s %v for %+v\n", kind, err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the source and then write back any status
+	// updates regardless of whether the reconcile error out.
+	source = source.DeepCopyObject().(Source)
+	err = r.reconcile(ctx, source)
+	if updateStatusErr := r.updateStatus(ctx, source); updateStatusErr != nil {
+		glog.Warningf("Failed to update %s status: %v", kind, updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, s Source) error {
+	kind := r.Kind.Name()
+	status := r.Kind.Status(s)
+	status.InitializeConditions()
+
+	if s.GetDeletionTimestamp() != nil {
+		// The receive adapter is owned by the source and will be garbage collected.
+		return nil
+	}
+
+	// The sink is resolved like the subscriber of a Subscription.
+	sinkURI, err := controller.ResolveSubscriberSpec(ctx, r.Client, r.DynamicClient, s.GetNamespace(), eventingv1alpha1.SubscriberSpec{Ref: r.Kind.Sink(s)})
+	if err != nil {
+		glog.Warningf("Failed to resolve the sink of %s %s/%s: %v", kind, s.GetNamespace(), s.GetName(), err)
+		status.MarkNoSink("SinkResolveFailed", "%v", err)
+		return err
+	}
+	status.MarkSink(sinkURI)
+
+	d, err := r.reconcileReceiveAdapter(ctx, s, sinkURI)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the receive adapter of %s %s/%s: %v", kind, s.GetNamespace(), s.GetName(), err)
+		status.MarkNotDeployed("DeploymentFailure", "%v", err)
+		return err
+	}
+	if d.Status.AvailableReplicas == 0 {
+		// The source is reconciled again when the Deployment changes.
+		status.MarkNotDeployed("DeploymentUnavailable", "Deployment %s has no available replicas", d.Name)
+		return nil
+	}
+	status.MarkDeployed()
+	return nil
+}
+
+// reconcileReceiveAdapter creates the Deployment of the receive adapter of s, or updates the spec
+// of the existing Deployment to match it.
+func (r *Reconciler) reconcileReceiveAdapter(ctx context.Context, s Source, sinkURI string) (*appsv1.Deployment, error) {
+	d := r.Kind.ReceiveAdapter(s, sinkURI)
+	current := &appsv1.Deployment{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.Client.Create(ctx, d); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return nil, fmt.Errorf("Deployment %s is not owned by the %s", current.Name, r.Kind.Name())
+	}
+	if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		if err := r.Client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, s Source) error {
+	current := r.Kind.New()
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: s.GetNamespace(), Name: s.GetName()}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(r.Kind.Status(current), r.Kind.Status(s)) {
+		return nil
+	}
+	r.Kind.CopyStatus(current, s)
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the source.
+	return r.Client.Update(ctx, current)
+}
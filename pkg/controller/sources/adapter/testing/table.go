@@ -0,0 +1,289 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package testing holds the fixtures of the tests of the sources reconciled by an
+// adapter.Reconciler.
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/controller/sources/adapter"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TestNS     = "test-namespace"
+	SourceName = "test-source"
+	SourceUID  = "test-uid"
+
+	SinkServiceName = "sink"
+	SinkURI         = "http://sink.test-namespace.svc.cluster.local/"
+
+	TestErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+// Sink returns the reference to the sink Service of the sources of the tests.
+func Sink() *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Name:       SinkServiceName,
+	}
+}
+
+// RunReconcileTests runs the tests of the Reconciler of the sources of k. makeSource returns the
+// source of the tests, with its TypeMeta, named SourceName in TestNS, with the UID SourceUID and
+// Sink as its sink.
+func RunReconcileTests(t *testing.T, k adapter.Kind, makeSource func() adapter.Source) {
+	name := k.Name()
+
+	makeSourceWithStatus := func(f func(adapter.Status)) adapter.Source {
+		s := makeSource()
+		status := k.Status(s)
+		status.InitializeConditions()
+		f(status)
+		return s
+	}
+	makeReadySource := func() adapter.Source {
+		return makeSourceWithStatus(func(s adapter.Status) {
+			s.MarkSink(SinkURI)
+			s.MarkDeployed()
+		})
+	}
+	makeDeletingSource := func() adapter.Source {
+		s := makeSourceWithStatus(func(adapter.Status) {})
+		s.SetDeletionTimestamp(&deletionTime)
+		return s
+	}
+	makeDeployment := func() *appsv1.Deployment {
+		d := k.ReceiveAdapter(makeSource(), SinkURI)
+		d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+		return d
+	}
+	makeAvailableDeployment := func() *appsv1.Deployment {
+		d := makeDeployment()
+		d.Status.AvailableReplicas = 1
+		return d
+	}
+	makeUnownedDeployment := func() *appsv1.Deployment {
+		d := makeDeployment()
+		d.OwnerReferences = nil
+		return d
+	}
+	withImage := func(d *appsv1.Deployment, image string) *appsv1.Deployment {
+		d.Spec.Template.Spec.Containers[0].Image = image
+		return d
+	}
+	deploymentName := makeDeployment().Name
+
+	testCases := []controllertesting.TestCase{
+		{
+			Name: name + " not found",
+		},
+		{
+			Name: "Error getting " + name,
+			Mocks: controllertesting.Mocks{
+				MockGets: ErrorGetting(makeSource()),
+			},
+			WantErrMsg: TestErrorMessage,
+		},
+		{
+			Name: name + " being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingSource(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingSource(),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+		},
+		{
+			Name: "Sink cannot be resolved",
+			InitialState: []runtime.Object{
+				makeSource(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s adapter.Status) {
+					s.MarkNoSink("SinkResolveFailed", `services "sink" not found`)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: `services "sink" not found`,
+		},
+		{
+			Name: "Deployment creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: ErrorCreating(&appsv1.Deployment{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s adapter.Status) {
+					s.MarkSink(SinkURI)
+					s.MarkNotDeployed("DeploymentFailure", TestErrorMessage)
+				}),
+			},
+			WantErrMsg: TestErrorMessage,
+		},
+		{
+			Name: "Deployment created, not available yet",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+				makeSourceWithStatus(func(s adapter.Status) {
+					s.MarkSink(SinkURI)
+					s.MarkNotDeployed("DeploymentUnavailable", "Deployment %s has no available replicas", deploymentName)
+				}),
+			},
+		},
+		{
+			Name: name + " ready",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+			},
+		},
+		{
+			Name: "Existing Deployment is updated",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				withImage(makeAvailableDeployment(), "example.com/old"),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+				makeAvailableDeployment(),
+			},
+		},
+		{
+			Name: "Deployment not owned by the " + name,
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeUnownedDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s adapter.Status) {
+					s.MarkSink(SinkURI)
+					s.MarkNotDeployed("DeploymentFailure", "Deployment %s is not owned by the %s", deploymentName, name)
+				}),
+			},
+			WantErrMsg: fmt.Sprintf("Deployment %s is not owned by the %s", deploymentName, name),
+		},
+		{
+			Name: "Updating " + name + " status fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: ErrorUpdating(makeSource()),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: TestErrorMessage,
+		},
+	}
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &adapter.Reconciler{
+			Kind:          k,
+			Client:        c,
+			DynamicClient: tc.GetDynamicClient(),
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", TestNS, SourceName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeSinkService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: TestNS,
+			Name:      SinkServiceName,
+		},
+	}
+}
+
+// ErrorGetting returns the mocks failing to get the objects of the type of t.
+func ErrorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(TestErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+// ErrorCreating returns the mocks failing to create the objects of the type of t.
+func ErrorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(TestErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+// ErrorUpdating returns the mocks failing to update the objects of the type of t.
+func ErrorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(TestErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
@@ -0,0 +1,290 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apiserversource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/sources/apiserversource/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// finalizerName is the finalizer that makes the controller delete the Roles and RoleBindings
+	// of an ApiServerSource in the other namespaces, which cannot be owned by it.
+	finalizerName = controllerAgentName
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the ApiServerSource
+// resource with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling api server source %v", request)
+	ctx := context.TODO()
+	source := &v1alpha1.ApiServerSource{}
+	err := r.client.Get(ctx, request.NamespacedName, source)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find api server source %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch ApiServerSource %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the ApiServerSource and then write back any status
+	// updates regardless of whether the reconcile error out.
+	source = source.DeepCopy()
+	err = r.reconcile(ctx, source)
+	if updateStatusErr := r.updateStatus(ctx, source); updateStatusErr != nil {
+		glog.Warningf("Failed to update api server source status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, s *v1alpha1.ApiServerSource) error {
+	s.Status.InitializeConditions()
+
+	if s.DeletionTimestamp != nil {
+		// The receive adapter, the ServiceAccount and the RBAC objects in the namespace of the
+		// ApiServerSource are owned by it and will be garbage collected.
+		if err := r.deleteRBAC(ctx, s, sets.NewString(s.Namespace)); err != nil {
+			return err
+		}
+		removeFinalizer(s)
+		return nil
+	}
+	addFinalizer(s)
+
+	// The sink is resolved like the subscriber of a Subscription.
+	sinkURI, err := controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, s.Namespace, eventingv1alpha1.SubscriberSpec{Ref: s.Spec.Sink})
+	if err != nil {
+		glog.Warningf("Failed to resolve the sink of api server source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNoSink("SinkResolveFailed", "%v", err)
+		return err
+	}
+	s.Status.MarkSink(sinkURI)
+
+	if err := r.reconcileRBAC(ctx, s); err != nil {
+		glog.Warningf("Failed to reconcile the RBAC of api server source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNotDeployed("RBACFailure", "%v", err)
+		return err
+	}
+
+	d, err := r.reconcileReceiveAdapter(ctx, s, sinkURI)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the receive adapter of api server source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNotDeployed("DeploymentFailure", "%v", err)
+		return err
+	}
+	if d.Status.AvailableReplicas == 0 {
+		// The ApiServerSource is reconciled again when the Deployment changes.
+		s.Status.MarkNotDeployed("DeploymentUnavailable", "Deployment %s has no available replicas", d.Name)
+		return nil
+	}
+	s.Status.MarkDeployed()
+	return nil
+}
+
+// reconcileRBAC allows the receive adapter of s to watch its resources. It creates the
+// ServiceAccount of s if s does not specify one, and a Role and a RoleBinding in every watched
+// namespace. The Roles and RoleBindings in the namespaces that are no longer watched are deleted.
+func (r *reconciler) reconcileRBAC(ctx context.Context, s *v1alpha1.ApiServerSource) error {
+	if s.Spec.ServiceAccountName == "" {
+		sa := resources.MakeServiceAccount(s)
+		current := &corev1.ServiceAccount{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: sa.Namespace, Name: sa.Name}, current)
+		if errors.IsNotFound(err) {
+			err = r.client.Create(ctx, sa)
+		} else if err == nil && !metav1.IsControlledBy(current, s) {
+			err = fmt.Errorf("ServiceAccount %s is not owned by the ApiServerSource", current.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	namespaces := s.WatchedNamespaces()
+	for _, ns := range namespaces {
+		if err := r.reconcileRole(ctx, resources.MakeRole(s, ns)); err != nil {
+			return err
+		}
+		if err := r.reconcileRoleBinding(ctx, resources.MakeRoleBinding(s, ns)); err != nil {
+			return err
+		}
+	}
+	return r.deleteRBAC(ctx, s, sets.NewString(namespaces...))
+}
+
+// reconcileRole creates the Role role, or updates the rules of the existing Role to match it.
+func (r *reconciler) reconcileRole(ctx context.Context, role *rbacv1.Role) error {
+	current := &rbacv1.Role{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: role.Namespace, Name: role.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, role)
+	}
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(role.Rules, current.Rules) {
+		current.Rules = role.Rules
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+// reconcileRoleBinding creates the RoleBinding rb, or updates the subjects of the existing
+// RoleBinding to match it. The role of a RoleBinding cannot be changed.
+func (r *reconciler) reconcileRoleBinding(ctx context.Context, rb *rbacv1.RoleBinding) error {
+	current := &rbacv1.RoleBinding{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: rb.Namespace, Name: rb.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, rb)
+	}
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(rb.Subjects, current.Subjects) {
+		current.Subjects = rb.Subjects
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+// deleteRBAC deletes the Roles and RoleBindings of s, except the ones in the namespaces keep.
+func (r *reconciler) deleteRBAC(ctx context.Context, s *v1alpha1.ApiServerSource, keep sets.String) error {
+	sourceLabels := resources.Labels(s)
+	opts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(sourceLabels),
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "RoleBinding",
+			},
+		},
+	}
+	bindings := &rbacv1.RoleBindingList{}
+	if err := r.client.List(ctx, opts, bindings); err != nil {
+		return err
+	}
+	for i := range bindings.Items {
+		rb := &bindings.Items[i]
+		if keep.Has(rb.Namespace) || !ownedBy(rb.Labels, sourceLabels) {
+			continue
+		}
+		if err := r.client.Delete(ctx, rb); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	opts.Raw.TypeMeta.Kind = "Role"
+	roles := &rbacv1.RoleList{}
+	if err := r.client.List(ctx, opts, roles); err != nil {
+		return err
+	}
+	for i := range roles.Items {
+		role := &roles.Items[i]
+		if keep.Has(role.Namespace) || !ownedBy(role.Labels, sourceLabels) {
+			continue
+		}
+		if err := r.client.Delete(ctx, role); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ownedBy returns true if objectLabels include all of sourceLabels.
+func ownedBy(objectLabels, sourceLabels map[string]string) bool {
+	return labels.SelectorFromSet(sourceLabels).Matches(labels.Set(objectLabels))
+}
+
+// reconcileReceiveAdapter creates the Deployment of the receive adapter of s, or updates the spec
+// of the existing Deployment to match it.
+func (r *reconciler) reconcileReceiveAdapter(ctx context.Context, s *v1alpha1.ApiServerSource, sinkURI string) (*appsv1.Deployment, error) {
+	d, err := resources.MakeReceiveAdapter(s, r.adapterImage, resources.ServiceAccountName(s), sinkURI)
+	if err != nil {
+		return nil, err
+	}
+	current := &appsv1.Deployment{}
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, d); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return nil, fmt.Errorf("Deployment %s is not owned by the ApiServerSource", current.Name)
+	}
+	if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, s *v1alpha1.ApiServerSource) error {
+	current := &v1alpha1.ApiServerSource{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: s.Namespace, Name: s.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, s.Status) && equality.Semantic.DeepEqual(current.Finalizers, s.Finalizers) {
+		return nil
+	}
+	current.Status = s.Status
+	current.Finalizers = s.Finalizers
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the ApiServerSource resource.
+	return r.client.Update(ctx, current)
+}
+
+func addFinalizer(s *v1alpha1.ApiServerSource) {
+	finalizers := sets.NewString(s.Finalizers...)
+	finalizers.Insert(finalizerName)
+	s.Finalizers = finalizers.List()
+}
+
+func removeFinalizer(s *v1alpha1.ApiServerSource) {
+	finalizers := sets.NewString(s.Finalizers...)
+	finalizers.Delete(finalizerName)
+	s.Finalizers = finalizers.List()
+}
@@ -0,0 +1,490 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package apiserversource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/sources/apiserversource/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS     = "test-namespace"
+	otherNS    = "other-namespace"
+	sourceName = "test-source"
+	sourceUID  = "test-uid"
+
+	adapterImage    = "adapter-image"
+	sinkServiceName = "sink"
+	sinkURI         = "http://sink.test-namespace.svc.cluster.local/"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "ApiServerSource not found",
+		},
+		{
+			Name: "Error getting ApiServerSource",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.ApiServerSource{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "ApiServerSource being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingSource(),
+				makeSinkService(),
+				makeRole(otherNS),
+				makeRoleBinding(otherNS),
+			},
+			WantPresent: []runtime.Object{
+				withoutFinalizer(makeDeletingSource()),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+				makeRole(otherNS),
+				makeRoleBinding(otherNS),
+			},
+		},
+		{
+			Name: "Sink cannot be resolved",
+			InitialState: []runtime.Object{
+				makeSource(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkNoSink("SinkResolveFailed", `services "sink" not found`)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeServiceAccount(),
+				makeDeployment(),
+			},
+			WantErrMsg: `services "sink" not found`,
+		},
+		{
+			Name: "ServiceAccount creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&corev1.ServiceAccount{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("RBACFailure", testErrorMessage)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Role creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&rbacv1.Role{}),
+			},
+			WantPresent: []runtime.Object{
+				makeServiceAccount(),
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("RBACFailure", testErrorMessage)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "ServiceAccount not owned by the ApiServerSource",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeUnownedServiceAccount(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("RBACFailure", "ServiceAccount test-source-apiserversource is not owned by the ApiServerSource")
+				}),
+			},
+			WantErrMsg: "ServiceAccount test-source-apiserversource is not owned by the ApiServerSource",
+		},
+		{
+			Name: "Deployment creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&appsv1.Deployment{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Deployment created, not available yet",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeServiceAccount(),
+				makeRole(testNS),
+				makeRoleBinding(testNS),
+				makeDeployment(),
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentUnavailable", "Deployment test-source-apiserversource has no available replicas")
+				}),
+			},
+		},
+		{
+			Name: "ApiServerSource ready",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+			},
+		},
+		{
+			Name: "ApiServerSource with a ServiceAccount",
+			InitialState: []runtime.Object{
+				withServiceAccountName(makeSource()),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				withSubject(makeRoleBinding(testNS), "watcher"),
+				withServiceAccount(makeDeployment(), "watcher"),
+			},
+			WantAbsent: []runtime.Object{
+				makeServiceAccount(),
+			},
+		},
+		{
+			Name: "RBAC follows the watched namespaces",
+			InitialState: []runtime.Object{
+				withNamespaces(makeSource(), otherNS),
+				makeSinkService(),
+				makeRole(testNS),
+				makeRoleBinding(testNS),
+				withRules(makeRole(otherNS), nil),
+			},
+			WantPresent: []runtime.Object{
+				makeRole(otherNS),
+				makeRoleBinding(otherNS),
+			},
+			WantAbsent: []runtime.Object{
+				makeRole(testNS),
+				makeRoleBinding(testNS),
+			},
+		},
+		{
+			Name: "Existing Deployment is updated",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				withImage(makeAvailableDeployment(), "example.com/old"),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+				makeAvailableDeployment(),
+			},
+		},
+		{
+			Name: "Deployment not owned by the ApiServerSource",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeUnownedDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", "Deployment test-source-apiserversource is not owned by the ApiServerSource")
+				}),
+			},
+			WantErrMsg: "Deployment test-source-apiserversource is not owned by the ApiServerSource",
+		},
+		{
+			Name: "Updating ApiServerSource status fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.ApiServerSource{}),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:        c,
+			dynamicClient: tc.GetDynamicClient(),
+			restConfig:    &rest.Config{},
+			recorder:      recorder,
+			adapterImage:  adapterImage,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, sourceName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeSource() *v1alpha1.ApiServerSource {
+	return &v1alpha1.ApiServerSource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ApiServerSource",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sourceName,
+			UID:       sourceUID,
+		},
+		Spec: v1alpha1.ApiServerSourceSpec{
+			Resources: []v1alpha1.ApiServerResource{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+			}, {
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "foo"},
+				},
+			}},
+			Mode: v1alpha1.ApiServerSourceModeResource,
+			Sink: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       sinkServiceName,
+			},
+		},
+	}
+}
+
+func withNamespaces(s *v1alpha1.ApiServerSource, namespaces ...string) *v1alpha1.ApiServerSource {
+	s.Spec.Namespaces = namespaces
+	return s
+}
+
+func withServiceAccountName(s *v1alpha1.ApiServerSource) *v1alpha1.ApiServerSource {
+	s.Spec.ServiceAccountName = "watcher"
+	return s
+}
+
+func makeSourceWithStatus(f func(*v1alpha1.ApiServerSourceStatus)) *v1alpha1.ApiServerSource {
+	return withStatus(makeSource(), f)
+}
+
+func withStatus(s *v1alpha1.ApiServerSource, f func(*v1alpha1.ApiServerSourceStatus)) *v1alpha1.ApiServerSource {
+	s.Finalizers = []string{finalizerName}
+	s.Status.InitializeConditions()
+	f(&s.Status)
+	return s
+}
+
+func makeReadySource() *v1alpha1.ApiServerSource {
+	return makeSourceWithStatus(func(s *v1alpha1.ApiServerSourceStatus) {
+		s.MarkSink(sinkURI)
+		s.MarkDeployed()
+	})
+}
+
+func makeDeletingSource() *v1alpha1.ApiServerSource {
+	s := withNamespaces(makeSourceWithStatus(func(*v1alpha1.ApiServerSourceStatus) {}), testNS, otherNS)
+	s.DeletionTimestamp = &deletionTime
+	return s
+}
+
+func withoutFinalizer(s *v1alpha1.ApiServerSource) *v1alpha1.ApiServerSource {
+	s.Finalizers = nil
+	return s
+}
+
+func makeSinkService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sinkServiceName,
+		},
+	}
+}
+
+func makeServiceAccount() *corev1.ServiceAccount {
+	sa := resources.MakeServiceAccount(makeSource())
+	sa.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"}
+	return sa
+}
+
+func makeUnownedServiceAccount() *corev1.ServiceAccount {
+	sa := makeServiceAccount()
+	sa.OwnerReferences = nil
+	return sa
+}
+
+func makeRole(namespace string) *rbacv1.Role {
+	role := resources.MakeRole(makeSource(), namespace)
+	role.TypeMeta = metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"}
+	return role
+}
+
+func withRules(role *rbacv1.Role, rules []rbacv1.PolicyRule) *rbacv1.Role {
+	role.Rules = rules
+	return role
+}
+
+func makeRoleBinding(namespace string) *rbacv1.RoleBinding {
+	rb := resources.MakeRoleBinding(makeSource(), namespace)
+	rb.TypeMeta = metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"}
+	return rb
+}
+
+func withSubject(rb *rbacv1.RoleBinding, serviceAccountName string) *rbacv1.RoleBinding {
+	rb.Subjects[0].Name = serviceAccountName
+	return rb
+}
+
+func makeDeployment() *appsv1.Deployment {
+	s := makeSource()
+	d, err := resources.MakeReceiveAdapter(s, adapterImage, resources.ServiceAccountName(s), sinkURI)
+	if err != nil {
+		panic(err)
+	}
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	return d
+}
+
+func makeAvailableDeployment() *appsv1.Deployment {
+	d := makeDeployment()
+	d.Status.AvailableReplicas = 1
+	return d
+}
+
+func makeUnownedDeployment() *appsv1.Deployment {
+	d := makeDeployment()
+	d.OwnerReferences = nil
+	return d
+}
+
+func withImage(d *appsv1.Deployment, image string) *appsv1.Deployment {
+	d.Spec.Template.Spec.Containers[0].Image = image
+	return d
+}
+
+func withServiceAccount(d *appsv1.Deployment, serviceAccountName string) *appsv1.Deployment {
+	d.Spec.Template.Spec.ServiceAccountName = serviceAccountName
+	return d
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
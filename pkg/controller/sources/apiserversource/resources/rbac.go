@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ServiceAccountName returns the name of the ServiceAccount the receive adapter of s runs as.
+func ServiceAccountName(s *v1alpha1.ApiServerSource) string {
+	if s.Spec.ServiceAccountName != "" {
+		return s.Spec.ServiceAccountName
+	}
+	return fmt.Sprintf("%s-apiserversource", s.Name)
+}
+
+// RoleName returns the name of the Role, and of its RoleBinding, that allow the receive adapter
+// of s to watch the resources in each namespace. It includes the namespace of s, because the Role
+// can live in another namespace.
+func RoleName(s *v1alpha1.ApiServerSource) string {
+	return fmt.Sprintf("apiserversource-%s-%s", s.Namespace, s.Name)
+}
+
+// MakeServiceAccount creates the ServiceAccount the receive adapter of s runs as, when s does not
+// specify one.
+func MakeServiceAccount(s *v1alpha1.ApiServerSource) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      ServiceAccountName(s),
+			Labels:    Labels(s),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("ApiServerSource")),
+			},
+		},
+	}
+}
+
+// MakeRole creates the Role that allows the receive adapter of s to watch the resources in
+// namespace.
+func MakeRole(s *v1alpha1.ApiServerSource, namespace string) *rbacv1.Role {
+	var rules []rbacv1.PolicyRule
+	for _, r := range AdapterResources(s) {
+		gv, _ := schema.ParseGroupVersion(r.APIVersion)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{gv.Group},
+			Resources: []string{r.Resource},
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
+	return &rbacv1.Role{
+		ObjectMeta: rbacObjectMeta(s, namespace),
+		Rules:      rules,
+	}
+}
+
+// MakeRoleBinding creates the RoleBinding that grants the Role of s in namespace to the
+// ServiceAccount of s.
+func MakeRoleBinding(s *v1alpha1.ApiServerSource, namespace string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: rbacObjectMeta(s, namespace),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     RoleName(s),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Namespace: s.Namespace,
+			Name:      ServiceAccountName(s),
+		}},
+	}
+}
+
+// rbacObjectMeta returns the metadata of the Role and RoleBinding of s in namespace. Only the ones
+// in the namespace of s can be owned by it, the others are deleted by the controller.
+func rbacObjectMeta(s *v1alpha1.ApiServerSource, namespace string) metav1.ObjectMeta {
+	om := metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      RoleName(s),
+		Labels:    Labels(s),
+	}
+	if namespace == s.Namespace {
+		om.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("ApiServerSource")),
+		}
+	}
+	return om
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/knative/eventing/pkg/adapter/apiserversource"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	duckapis "github.com/knative/pkg/apis"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// SourceLabelKey is the label that identifies the ApiServerSource that an object belongs to.
+	SourceLabelKey = "sources.eventing.knative.dev/apiServerSource"
+
+	// SourceNamespaceLabelKey is the label that identifies the namespace of the ApiServerSource
+	// that an object belongs to. The RBAC objects of an ApiServerSource can live in other
+	// namespaces.
+	SourceNamespaceLabelKey = "sources.eventing.knative.dev/apiServerSourceNamespace"
+)
+
+// ReceiveAdapterName returns the name of the Deployment running the receive adapter of the
+// ApiServerSource sourceName.
+func ReceiveAdapterName(sourceName string) string {
+	return fmt.Sprintf("%s-apiserversource", sourceName)
+}
+
+// Labels returns the labels of every object created for s.
+func Labels(s *v1alpha1.ApiServerSource) map[string]string {
+	return map[string]string{
+		SourceLabelKey:          s.Name,
+		SourceNamespaceLabelKey: s.Namespace,
+	}
+}
+
+// AdapterResources returns the resources watched by the receive adapter of s.
+func AdapterResources(s *v1alpha1.ApiServerSource) []apiserversource.Resource {
+	resources := make([]apiserversource.Resource, 0, len(s.Spec.Resources))
+	for _, r := range s.Spec.Resources {
+		gvk := schema.FromAPIVersionAndKind(r.APIVersion, r.Kind)
+		ar := apiserversource.Resource{
+			APIVersion: r.APIVersion,
+			Kind:       r.Kind,
+			Resource:   duckapis.KindToResource(gvk).Resource,
+		}
+		if r.LabelSelector != nil {
+			// The selector was validated by the webhook.
+			if selector, err := metav1.LabelSelectorAsSelector(r.LabelSelector); err == nil {
+				ar.LabelSelector = selector.String()
+			}
+		}
+		resources = append(resources, ar)
+	}
+	return resources
+}
+
+// MakeReceiveAdapter creates the Deployment running the receive adapter of s, which runs as the
+// ServiceAccount serviceAccountName and sends the events of s to sinkURI.
+func MakeReceiveAdapter(s *v1alpha1.ApiServerSource, image, serviceAccountName, sinkURI string) (*appsv1.Deployment, error) {
+	resources, err := json.Marshal(AdapterResources(s))
+	if err != nil {
+		return nil, err
+	}
+	labels := map[string]string{
+		SourceLabelKey: s.Name,
+	}
+	// A single replica, so that each event is sent once.
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      ReceiveAdapterName(s.Name),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("ApiServerSource")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "receive-adapter",
+						Image: image,
+						Env: []corev1.EnvVar{{
+							Name:  "RESOURCES",
+							Value: string(resources),
+						}, {
+							Name:  "NAMESPACES",
+							Value: strings.Join(s.WatchedNamespaces(), ","),
+						}, {
+							Name:  "MODE",
+							Value: string(s.Spec.Mode),
+						}, {
+							Name:  "SINK_URI",
+							Value: sinkURI,
+						}},
+					}},
+				},
+			},
+		},
+	}, nil
+}
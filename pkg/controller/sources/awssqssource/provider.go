@@ -0,0 +1,39 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package awssqssource
+
+import (
+	"os"
+
+	"github.com/knative/eventing/pkg/controller/sources/adapter"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "aws-sqs-source-controller"
+
+	// adapterImageEnvVar names the environment variable holding the image of the receive adapter.
+	adapterImageEnvVar = "AWS_SQS_SOURCE_IMAGE"
+)
+
+// ProvideController returns a AwsSqsSource controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	return adapter.ProvideController(mgr, controllerAgentName, kind{adapterImage: os.Getenv(adapterImageEnvVar)})
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SourceLabelKey is the label that identifies the AwsSqsSource that an object belongs to.
+	SourceLabelKey = "sources.eventing.knative.dev/awsSqsSource"
+)
+
+// ReceiveAdapterName returns the name of the Deployment running the receive adapter of the
+// AwsSqsSource sourceName.
+func ReceiveAdapterName(sourceName string) string {
+	return fmt.Sprintf("%s-awssqssource", sourceName)
+}
+
+// Labels returns the labels of every object created for the AwsSqsSource sourceName.
+func Labels(sourceName string) map[string]string {
+	return map[string]string{
+		SourceLabelKey: sourceName,
+	}
+}
+
+// MakeReceiveAdapter creates the Deployment running the receive adapter of s, which sends the
+// events of s to sinkURI.
+func MakeReceiveAdapter(s *v1alpha1.AwsSqsSource, image, sinkURI string) *appsv1.Deployment {
+	labels := Labels(s.Name)
+	// The receive adapter sends Concurrency messages at a time by itself.
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      ReceiveAdapterName(s.Name),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("AwsSqsSource")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: s.Spec.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "receive-adapter",
+						Image: image,
+						Env:   env(s, sinkURI),
+					}},
+				},
+			},
+		},
+	}
+}
+
+// env returns the environment of the receive adapter of s. The credentials are read from their
+// Secrets.
+func env(s *v1alpha1.AwsSqsSource, sinkURI string) []corev1.EnvVar {
+	env := []corev1.EnvVar{{
+		Name:  "AWS_SQS_URL",
+		Value: s.Spec.QueueURL,
+	}, {
+		Name:  "AWS_REGION",
+		Value: region(s),
+	}, {
+		Name:  "SQS_CONCURRENCY",
+		Value: strconv.Itoa(concurrency(s)),
+	}, {
+		Name:  "SINK_URI",
+		Value: sinkURI,
+	}, {
+		Name:  "NAMESPACE",
+		Value: s.Namespace,
+	}, {
+		Name:  "NAME",
+		Value: s.Name,
+	}}
+	if s.Spec.VisibilityTimeoutSeconds != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "SQS_VISIBILITY_TIMEOUT",
+			Value: strconv.Itoa(int(*s.Spec.VisibilityTimeoutSeconds)),
+		})
+	}
+	env = appendSecretEnv(env, "AWS_ACCESS_KEY_ID", s.Spec.AccessKeyID)
+	env = appendSecretEnv(env, "AWS_SECRET_ACCESS_KEY", s.Spec.SecretAccessKey)
+	return env
+}
+
+// region returns the AWS region of the queue of s.
+func region(s *v1alpha1.AwsSqsSource) string {
+	if s.Spec.Region != "" {
+		return s.Spec.Region
+	}
+	if u, err := url.Parse(s.Spec.QueueURL); err == nil {
+		return v1alpha1.AwsSqsRegion(u.Host)
+	}
+	return ""
+}
+
+// concurrency returns the number of messages the receive adapter of s sends at a time.
+func concurrency(s *v1alpha1.AwsSqsSource) int {
+	if s.Spec.Concurrency == nil {
+		return v1alpha1.DefaultAwsSqsConcurrency
+	}
+	return int(*s.Spec.Concurrency)
+}
+
+// appendSecretEnv appends the environment variable name, read from secret, to env if secret is
+// set.
+func appendSecretEnv(env []corev1.EnvVar, name string, secret *corev1.SecretKeySelector) []corev1.EnvVar {
+	if secret == nil {
+		return env
+	}
+	return append(env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: secret,
+		},
+	})
+}
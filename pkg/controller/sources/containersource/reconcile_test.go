@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package containersource
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/sources/adapter"
+	adaptertesting "github.com/knative/eventing/pkg/controller/sources/adapter/testing"
+	"github.com/knative/eventing/pkg/controller/sources/containersource/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	image = "example.com/heartbeats"
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestReconcile(t *testing.T) {
+	adaptertesting.RunReconcileTests(t, kind{}, func() adapter.Source {
+		return makeSource()
+	})
+}
+
+func TestMakeDeploymentInjectsSink(t *testing.T) {
+	s := makeSource()
+	s.Spec.Args = []string{"--period=1"}
+	s.Spec.Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+	c := resources.MakeDeployment(s, adaptertesting.SinkURI).Spec.Template.Spec.Containers[0]
+
+	wantArgs := []string{"--period=1", "--sink=" + adaptertesting.SinkURI}
+	if fmt.Sprint(c.Args) != fmt.Sprint(wantArgs) {
+		t.Errorf("Unexpected args. Expected: %v. Actual: %v", wantArgs, c.Args)
+	}
+	wantEnv := []corev1.EnvVar{{Name: "FOO", Value: "bar"}, {Name: resources.SinkEnvVarName, Value: adaptertesting.SinkURI}}
+	if fmt.Sprint(c.Env) != fmt.Sprint(wantEnv) {
+		t.Errorf("Unexpected env. Expected: %v. Actual: %v", wantEnv, c.Env)
+	}
+	if len(s.Spec.Args) != 1 || len(s.Spec.Env) != 1 {
+		t.Errorf("The spec of the ContainerSource was modified: %+v", s.Spec)
+	}
+}
+
+func makeSource() *v1alpha1.ContainerSource {
+	return &v1alpha1.ContainerSource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ContainerSource",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: adaptertesting.TestNS,
+			Name:      adaptertesting.SourceName,
+			UID:       adaptertesting.SourceUID,
+		},
+		Spec: v1alpha1.ContainerSourceSpec{
+			Image: image,
+			Sink:  adaptertesting.Sink(),
+		},
+	}
+}
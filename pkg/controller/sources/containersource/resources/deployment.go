@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SourceLabelKey is the label that identifies the ContainerSource that an object belongs to.
+	SourceLabelKey = "sources.eventing.knative.dev/containerSource"
+
+	// SinkEnvVarName is the environment variable holding the URI of the sink in the container.
+	SinkEnvVarName = "SINK_URI"
+
+	// sinkArgPrefix prefixes the argument holding the URI of the sink in the container.
+	sinkArgPrefix = "--sink="
+)
+
+// DeploymentName returns the name of the Deployment running the container of the ContainerSource
+// sourceName.
+func DeploymentName(sourceName string) string {
+	return fmt.Sprintf("%s-containersource", sourceName)
+}
+
+// Labels returns the labels of every object created for the ContainerSource sourceName.
+func Labels(sourceName string) map[string]string {
+	return map[string]string{
+		SourceLabelKey: sourceName,
+	}
+}
+
+// MakeDeployment creates the Deployment running the container of s. The container is told the URI
+// of the sink, sinkURI, both by an argument and by an environment variable.
+func MakeDeployment(s *v1alpha1.ContainerSource, sinkURI string) *appsv1.Deployment {
+	labels := Labels(s.Name)
+	args := append(append([]string{}, s.Spec.Args...), sinkArgPrefix+sinkURI)
+	env := append(append([]corev1.EnvVar{}, s.Spec.Env...), corev1.EnvVar{
+		Name:  SinkEnvVarName,
+		Value: sinkURI,
+	})
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      DeploymentName(s.Name),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("ContainerSource")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: s.Spec.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "source",
+						Image: s.Spec.Image,
+						Args:  args,
+						Env:   env,
+					}},
+				},
+			},
+		},
+	}
+}
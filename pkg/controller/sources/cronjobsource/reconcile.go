@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cronjobsource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/sources/cronjobsource/resources"
+	"github.com/knative/eventing/pkg/cron"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the CronJobSource
+// resource with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling cron job source %v", request)
+	ctx := context.TODO()
+	source := &v1alpha1.CronJobSource{}
+	err := r.client.Get(ctx, request.NamespacedName, source)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find cron job source %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch CronJobSource %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the CronJobSource and then write back any status
+	// updates regardless of whether the reconcile error out.
+	source = source.DeepCopy()
+	err = r.reconcile(ctx, source)
+	if updateStatusErr := r.updateStatus(ctx, source); updateStatusErr != nil {
+		glog.Warningf("Failed to update cron job source status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, s *v1alpha1.CronJobSource) error {
+	s.Status.InitializeConditions()
+
+	if s.DeletionTimestamp != nil {
+		// The receive adapter is owned by the CronJobSource and will be garbage collected.
+		return nil
+	}
+
+	if _, err := cron.Parse(s.Spec.Schedule); err != nil {
+		// The CronJobSource is reconciled again when the schedule is fixed.
+		s.Status.MarkInvalidSchedule("InvalidSchedule", "%v", err)
+		return nil
+	}
+	s.Status.MarkSchedule()
+
+	// The sink is resolved like the subscriber of a Subscription.
+	sinkURI, err := controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, s.Namespace, eventingv1alpha1.SubscriberSpec{Ref: s.Spec.Sink})
+	if err != nil {
+		glog.Warningf("Failed to resolve the sink of cron job source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNoSink("SinkResolveFailed", "%v", err)
+		return err
+	}
+	s.Status.MarkSink(sinkURI)
+
+	d, err := r.reconcileReceiveAdapter(ctx, s, sinkURI)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the receive adapter of cron job source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNotDeployed("DeploymentFailure", "%v", err)
+		return err
+	}
+	if d.Status.AvailableReplicas == 0 {
+		// The CronJobSource is reconciled again when the Deployment changes.
+		s.Status.MarkNotDeployed("DeploymentUnavailable", "Deployment %s has no available replicas", d.Name)
+		return nil
+	}
+	s.Status.MarkDeployed()
+	return nil
+}
+
+// reconcileReceiveAdapter creates the Deployment of the receive adapter of s, or updates the spec
+// of the existing Deployment to match it.
+func (r *reconciler) reconcileReceiveAdapter(ctx context.Context, s *v1alpha1.CronJobSource, sinkURI string) (*appsv1.Deployment, error) {
+	d := resources.MakeReceiveAdapter(s, r.adapterImage, sinkURI)
+	current := &appsv1.Deployment{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, d); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return nil, fmt.Errorf("Deployment %s is not owned by the CronJobSource", current.Name)
+	}
+	if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, s *v1alpha1.CronJobSource) error {
+	current := &v1alpha1.CronJobSource{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: s.Namespace, Name: s.Name}, current); err != nil {
+		return err
+	}
+	// The last schedule time is owned by the receive adapter, which may have updated it since
+	// the CronJobSource was read.
+	s.Status.LastScheduleTime = current.Status.LastScheduleTime
+	if equality.Semantic.DeepEqual(current.Status, s.Status) {
+		return nil
+	}
+	current.Status = s.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the CronJobSource resource.
+	return r.client.Update(ctx, current)
+}
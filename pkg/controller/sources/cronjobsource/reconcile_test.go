@@ -0,0 +1,370 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cronjobsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/sources/cronjobsource/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS     = "test-namespace"
+	sourceName = "test-source"
+	sourceUID  = "test-uid"
+
+	adapterImage    = "adapter-image"
+	schedule        = "*/2 * * * *"
+	sinkServiceName = "sink"
+	sinkURI         = "http://sink.test-namespace.svc.cluster.local/"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+
+	lastScheduleTime = metav1.Date(2019, time.January, 7, 11, 0, 0, 0, time.UTC)
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "CronJobSource not found",
+		},
+		{
+			Name: "Error getting CronJobSource",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.CronJobSource{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "CronJobSource being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingSource(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingSource(),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+		},
+		{
+			Name: "Invalid schedule",
+			InitialState: []runtime.Object{
+				makeSourceWithSchedule("* * *"),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				withStatus(makeSourceWithSchedule("* * *"), func(s *v1alpha1.CronJobSourceStatus) {
+					s.MarkInvalidSchedule("InvalidSchedule", `expected 5 fields, found 3 in "* * *"`)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+		},
+		{
+			Name: "Sink cannot be resolved",
+			InitialState: []runtime.Object{
+				makeSource(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.CronJobSourceStatus) {
+					s.MarkSchedule()
+					s.MarkNoSink("SinkResolveFailed", `services "sink" not found`)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: `services "sink" not found`,
+		},
+		{
+			Name: "Deployment creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&appsv1.Deployment{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.CronJobSourceStatus) {
+					s.MarkSchedule()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Deployment created, not available yet",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+				makeSourceWithStatus(func(s *v1alpha1.CronJobSourceStatus) {
+					s.MarkSchedule()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentUnavailable", "Deployment test-source-cronjobsource has no available replicas")
+				}),
+			},
+		},
+		{
+			Name: "CronJobSource ready",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+			},
+		},
+		{
+			Name: "Existing Deployment is updated",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				withImage(makeAvailableDeployment(), "example.com/old"),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+				makeAvailableDeployment(),
+			},
+		},
+		{
+			Name: "Deployment not owned by the CronJobSource",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				makeUnownedDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.CronJobSourceStatus) {
+					s.MarkSchedule()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", "Deployment test-source-cronjobsource is not owned by the CronJobSource")
+				}),
+			},
+			WantErrMsg: "Deployment test-source-cronjobsource is not owned by the CronJobSource",
+		},
+		{
+			Name: "Last schedule time is kept",
+			InitialState: []runtime.Object{
+				withLastScheduleTime(makeSource()),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				withLastScheduleTime(makeReadySource()),
+			},
+		},
+		{
+			Name: "Updating CronJobSource status fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.CronJobSource{}),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:        c,
+			dynamicClient: tc.GetDynamicClient(),
+			restConfig:    &rest.Config{},
+			recorder:      recorder,
+			adapterImage:  adapterImage,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, sourceName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeSource() *v1alpha1.CronJobSource {
+	return &v1alpha1.CronJobSource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "CronJobSource",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sourceName,
+			UID:       sourceUID,
+		},
+		Spec: v1alpha1.CronJobSourceSpec{
+			Schedule: schedule,
+			Data:     `{"message": "Hello world!"}`,
+			Sink: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       sinkServiceName,
+			},
+		},
+	}
+}
+
+func makeSourceWithSchedule(schedule string) *v1alpha1.CronJobSource {
+	s := makeSource()
+	s.Spec.Schedule = schedule
+	return s
+}
+
+func makeSourceWithStatus(f func(*v1alpha1.CronJobSourceStatus)) *v1alpha1.CronJobSource {
+	return withStatus(makeSource(), f)
+}
+
+func withStatus(s *v1alpha1.CronJobSource, f func(*v1alpha1.CronJobSourceStatus)) *v1alpha1.CronJobSource {
+	s.Status.InitializeConditions()
+	f(&s.Status)
+	return s
+}
+
+func withLastScheduleTime(s *v1alpha1.CronJobSource) *v1alpha1.CronJobSource {
+	s.Status.LastScheduleTime = &lastScheduleTime
+	return s
+}
+
+func makeReadySource() *v1alpha1.CronJobSource {
+	return makeSourceWithStatus(func(s *v1alpha1.CronJobSourceStatus) {
+		s.MarkSchedule()
+		s.MarkSink(sinkURI)
+		s.MarkDeployed()
+	})
+}
+
+func makeDeletingSource() *v1alpha1.CronJobSource {
+	s := makeSourceWithStatus(func(*v1alpha1.CronJobSourceStatus) {})
+	s.DeletionTimestamp = &deletionTime
+	return s
+}
+
+func makeSinkService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sinkServiceName,
+		},
+	}
+}
+
+func makeDeployment() *appsv1.Deployment {
+	d := resources.MakeReceiveAdapter(makeSource(), adapterImage, sinkURI)
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	return d
+}
+
+func makeAvailableDeployment() *appsv1.Deployment {
+	d := makeDeployment()
+	d.Status.AvailableReplicas = 1
+	return d
+}
+
+func makeUnownedDeployment() *appsv1.Deployment {
+	d := makeDeployment()
+	d.OwnerReferences = nil
+	return d
+}
+
+func withImage(d *appsv1.Deployment, image string) *appsv1.Deployment {
+	d.Spec.Template.Spec.Containers[0].Image = image
+	return d
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
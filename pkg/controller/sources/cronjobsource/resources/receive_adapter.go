@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SourceLabelKey is the label that identifies the CronJobSource that an object belongs to.
+	SourceLabelKey = "sources.eventing.knative.dev/cronJobSource"
+)
+
+// ReceiveAdapterName returns the name of the Deployment running the receive adapter of the
+// CronJobSource sourceName.
+func ReceiveAdapterName(sourceName string) string {
+	return fmt.Sprintf("%s-cronjobsource", sourceName)
+}
+
+// Labels returns the labels of every object created for the CronJobSource sourceName.
+func Labels(sourceName string) map[string]string {
+	return map[string]string{
+		SourceLabelKey: sourceName,
+	}
+}
+
+// MakeReceiveAdapter creates the Deployment running the receive adapter of s, which sends the
+// events of s to sinkURI.
+func MakeReceiveAdapter(s *v1alpha1.CronJobSource, image, sinkURI string) *appsv1.Deployment {
+	labels := Labels(s.Name)
+	// A single replica, so that each event is sent once.
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      ReceiveAdapterName(s.Name),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("CronJobSource")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: s.Spec.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "receive-adapter",
+						Image: image,
+						Env: []corev1.EnvVar{{
+							Name:  "SCHEDULE",
+							Value: s.Spec.Schedule,
+						}, {
+							Name:  "DATA",
+							Value: s.Spec.Data,
+						}, {
+							Name:  "SINK_URI",
+							Value: sinkURI,
+						}, {
+							Name:  "NAMESPACE",
+							Value: s.Namespace,
+						}, {
+							Name:  "NAME",
+							Value: s.Name,
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
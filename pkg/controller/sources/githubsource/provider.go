@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package githubsource
+
+import (
+	"os"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "github-source-controller"
+
+	// adapterImageEnvVar names the environment variable holding the image of the receive adapter.
+	adapterImageEnvVar = "GITHUB_SOURCE_IMAGE"
+
+	// domainEnvVar names the environment variable holding the domain of the webhook hosts.
+	domainEnvVar = "GITHUB_SOURCE_DOMAIN"
+
+	// gatewayEnvVar names the environment variable holding the Istio gateway that receives the
+	// webhook requests.
+	gatewayEnvVar = "GITHUB_SOURCE_GATEWAY"
+)
+
+type reconciler struct {
+	client        client.Client
+	restConfig    *rest.Config
+	dynamicClient dynamic.Interface
+	recorder      record.EventRecorder
+
+	adapterImage  string
+	domain        string
+	gateway       string
+	webhookClient webhookClient
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a GitHubSource controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Setup a new controller to Reconcile GitHubSources.
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, &reconciler{
+		recorder:      mgr.GetRecorder(controllerAgentName),
+		adapterImage:  os.Getenv(adapterImageEnvVar),
+		domain:        os.Getenv(domainEnvVar),
+		gateway:       os.Getenv(gatewayEnvVar),
+		webhookClient: &gitHubWebhookClient{},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch GitHubSource events and enqueue GitHubSource object key.
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.GitHubSource{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the Deployments owned by GitHubSources.
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.GitHubSource{}, IsController: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the Services owned by GitHubSources.
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.GitHubSource{}, IsController: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the VirtualServices owned by GitHubSources.
+	err = c.Watch(&source.Kind{Type: &istiov1alpha3.VirtualService{}}, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.GitHubSource{}, IsController: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+func (r *reconciler) InjectConfig(c *rest.Config) error {
+	r.restConfig = c
+	var err error
+	r.dynamicClient, err = dynamic.NewForConfig(c)
+	return err
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package githubsource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/sources/githubsource/resources"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// finalizerName is the finalizer that makes the controller delete the webhook of a
+	// GitHubSource from the repository.
+	finalizerName = controllerAgentName
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the GitHubSource
+// resource with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling github source %v", request)
+	ctx := context.TODO()
+	source := &v1alpha1.GitHubSource{}
+	err := r.client.Get(ctx, request.NamespacedName, source)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find github source %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch GitHubSource %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the GitHubSource and then write back any status
+	// updates regardless of whether the reconcile error out.
+	source = source.DeepCopy()
+	err = r.reconcile(ctx, source)
+	if updateStatusErr := r.updateStatus(ctx, source); updateStatusErr != nil {
+		glog.Warningf("Failed to update github source status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, s *v1alpha1.GitHubSource) error {
+	s.Status.InitializeConditions()
+
+	if s.DeletionTimestamp != nil {
+		// The receive adapter is owned by the GitHubSource and will be garbage collected.
+		if err := r.deleteWebhook(ctx, s); err != nil {
+			return err
+		}
+		removeFinalizer(s)
+		return nil
+	}
+	addFinalizer(s)
+
+	accessToken, err := r.secretValue(ctx, s.Namespace, s.Spec.AccessToken)
+	if err != nil {
+		s.Status.MarkNoSecrets("AccessTokenNotFound", "%v", err)
+		return err
+	}
+	secretToken, err := r.secretValue(ctx, s.Namespace, s.Spec.SecretToken)
+	if err != nil {
+		s.Status.MarkNoSecrets("SecretTokenNotFound", "%v", err)
+		return err
+	}
+	s.Status.MarkSecrets()
+
+	// The sink is resolved like the subscriber of a Subscription.
+	sinkURI, err := controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, s.Namespace, eventingv1alpha1.SubscriberSpec{Ref: s.Spec.Sink})
+	if err != nil {
+		glog.Warningf("Failed to resolve the sink of github source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNoSink("SinkResolveFailed", "%v", err)
+		return err
+	}
+	s.Status.MarkSink(sinkURI)
+
+	d, err := r.reconcileReceiveAdapter(ctx, s, sinkURI)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the receive adapter of github source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNotDeployed("DeploymentFailure", "%v", err)
+		return err
+	}
+	if d.Status.AvailableReplicas == 0 {
+		// The GitHubSource is reconciled again when the Deployment changes. The webhook is
+		// registered once the receive adapter can answer the ping of GitHub.
+		s.Status.MarkNotDeployed("DeploymentUnavailable", "Deployment %s has no available replicas", d.Name)
+		return nil
+	}
+	s.Status.MarkDeployed()
+
+	if s.Status.WebhookIDKey != "" {
+		s.Status.MarkWebhook(s.Status.WebhookIDKey)
+		return nil
+	}
+	id, err := r.webhookClient.Create(ctx, &hookOptions{
+		apiURL:             s.Spec.GitHubAPIURL,
+		accessToken:        accessToken,
+		ownerAndRepository: s.Spec.OwnerAndRepository,
+		url:                fmt.Sprintf("http://%s", resources.WebhookHost(s, r.domain)),
+		secretToken:        secretToken,
+		events:             s.Spec.EventTypes,
+	})
+	if err != nil {
+		glog.Warningf("Failed to register the webhook of github source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNoWebhook("WebhookCreationFailed", "%v", err)
+		return err
+	}
+	s.Status.MarkWebhook(id)
+	return nil
+}
+
+// deleteWebhook deletes the webhook of s from the repository.
+func (r *reconciler) deleteWebhook(ctx context.Context, s *v1alpha1.GitHubSource) error {
+	if s.Status.WebhookIDKey == "" {
+		return nil
+	}
+	accessToken, err := r.secretValue(ctx, s.Namespace, s.Spec.AccessToken)
+	if err != nil {
+		// Without the access token the webhook cannot be deleted, and it has to be deleted
+		// manually.
+		glog.Warningf("Unable to delete the webhook %s of github source %s/%s: %v", s.Status.WebhookIDKey, s.Namespace, s.Name, err)
+		return nil
+	}
+	options := &hookOptions{
+		apiURL:             s.Spec.GitHubAPIURL,
+		accessToken:        accessToken,
+		ownerAndRepository: s.Spec.OwnerAndRepository,
+	}
+	if err := r.webhookClient.Delete(ctx, options, s.Status.WebhookIDKey); err != nil {
+		return err
+	}
+	s.Status.WebhookIDKey = ""
+	return nil
+}
+
+// secretValue returns the value of the key of a Secret in namespace selected by selector.
+func (r *reconciler) secretValue(ctx context.Context, namespace string, selector *corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %s", selector.Name, selector.Key)
+	}
+	return string(value), nil
+}
+
+// reconcileReceiveAdapter creates the Deployment, Service and VirtualService of the receive adapter
+// of s, or updates the existing ones to match them. It returns the Deployment.
+func (r *reconciler) reconcileReceiveAdapter(ctx context.Context, s *v1alpha1.GitHubSource, sinkURI string) (*appsv1.Deployment, error) {
+	d := resources.MakeReceiveAdapter(s, r.adapterImage, sinkURI)
+	current := &appsv1.Deployment{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, d); err != nil {
+			return nil, err
+		}
+		current = d
+	} else if err != nil {
+		return nil, err
+	} else if !metav1.IsControlledBy(current, s) {
+		return nil, fmt.Errorf("Deployment %s is not owned by the GitHubSource", current.Name)
+	} else if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.reconcileService(ctx, s, resources.MakeService(s)); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileVirtualService(ctx, s, resources.MakeVirtualService(s, r.gateway, r.domain)); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// reconcileService creates the Service svc, or updates the ports and selector of the existing
+// Service to match it.
+func (r *reconciler) reconcileService(ctx context.Context, s *v1alpha1.GitHubSource, svc *corev1.Service) error {
+	current := &corev1.Service{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: svc.Namespace, Name: svc.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, svc)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return fmt.Errorf("Service %s is not owned by the GitHubSource", current.Name)
+	}
+	// The cluster IP is assigned by the API server and cannot be changed.
+	if !equality.Semantic.DeepDerivative(svc.Spec, current.Spec) {
+		current.Spec.Ports = svc.Spec.Ports
+		current.Spec.Selector = svc.Spec.Selector
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+// reconcileVirtualService creates the VirtualService vs, or updates the spec of the existing
+// VirtualService to match it.
+func (r *reconciler) reconcileVirtualService(ctx context.Context, s *v1alpha1.GitHubSource, vs *istiov1alpha3.VirtualService) error {
+	current := &istiov1alpha3.VirtualService{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: vs.Namespace, Name: vs.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, vs)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return fmt.Errorf("VirtualService %s is not owned by the GitHubSource", current.Name)
+	}
+	if !equality.Semantic.DeepDerivative(vs.Spec, current.Spec) {
+		current.Spec = vs.Spec
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, s *v1alpha1.GitHubSource) error {
+	current := &v1alpha1.GitHubSource{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: s.Namespace, Name: s.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, s.Status) && equality.Semantic.DeepEqual(current.Finalizers, s.Finalizers) {
+		return nil
+	}
+	current.Status = s.Status
+	current.Finalizers = s.Finalizers
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the GitHubSource resource.
+	return r.client.Update(ctx, current)
+}
+
+func addFinalizer(s *v1alpha1.GitHubSource) {
+	finalizers := sets.NewString(s.Finalizers...)
+	finalizers.Insert(finalizerName)
+	s.Finalizers = finalizers.List()
+}
+
+func removeFinalizer(s *v1alpha1.GitHubSource) {
+	finalizers := sets.NewString(s.Finalizers...)
+	finalizers.Delete(finalizerName)
+	s.Finalizers = finalizers.List()
+}
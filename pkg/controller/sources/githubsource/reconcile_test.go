@@ -0,0 +1,524 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package githubsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/sources/githubsource/resources"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNS     = "test-namespace"
+	sourceName = "test-source"
+	sourceUID  = "test-uid"
+
+	adapterImage    = "adapter-image"
+	domain          = "example.com"
+	gateway         = "knative-ingress-gateway.knative-serving.svc.cluster.local"
+	secretName      = "github-secret"
+	accessToken     = "access-token"
+	secretToken     = "secret-token"
+	webhookID       = "1234"
+	sinkServiceName = "sink"
+	sinkURI         = "http://sink.test-namespace.svc.cluster.local/"
+
+	// invalidAccessToken is rejected by the fake GitHub.
+	invalidAccessToken = "invalid-token"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+	istiov1alpha3.AddToScheme(scheme.Scheme)
+}
+
+// fakeWebhookClient registers webhooks unless the access token is invalidAccessToken.
+type fakeWebhookClient struct{}
+
+func (*fakeWebhookClient) Create(_ context.Context, options *hookOptions) (string, error) {
+	if options.accessToken == invalidAccessToken {
+		return "", errors.New("unable to create the webhook: 401 Unauthorized: Bad credentials")
+	}
+	if options.url != "http://test-source-githubsource.test-namespace.example.com" || options.secretToken != secretToken {
+		return "", fmt.Errorf("unexpected webhook options %+v", options)
+	}
+	return webhookID, nil
+}
+
+func (*fakeWebhookClient) Delete(_ context.Context, options *hookOptions, hookID string) error {
+	if options.accessToken == invalidAccessToken {
+		return errors.New("unable to delete the webhook: 401 Unauthorized: Bad credentials")
+	}
+	return nil
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "GitHubSource not found",
+		},
+		{
+			Name: "Error getting GitHubSource",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.GitHubSource{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "GitHubSource being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingSource(),
+				makeSecret(accessToken),
+			},
+			WantPresent: []runtime.Object{
+				withoutFinalizer(withWebhookIDKey(makeDeletingSource(), "")),
+			},
+		},
+		{
+			Name: "Deleting the webhook fails",
+			InitialState: []runtime.Object{
+				makeDeletingSource(),
+				makeSecret(invalidAccessToken),
+			},
+			WantPresent: []runtime.Object{
+				makeDeletingSource(),
+			},
+			WantErrMsg: "unable to delete the webhook: 401 Unauthorized: Bad credentials",
+		},
+		{
+			Name: "GitHubSource being deleted without its access token",
+			InitialState: []runtime.Object{
+				makeDeletingSource(),
+			},
+			WantPresent: []runtime.Object{
+				withoutFinalizer(makeDeletingSource()),
+			},
+		},
+		{
+			Name: "Access token not found",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkNoSecrets("AccessTokenNotFound", `secrets "github-secret" not found`)
+				}),
+			},
+			WantErrMsg: `secrets "github-secret" not found`,
+		},
+		{
+			Name: "Secret token not found",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSinkService(),
+				withoutSecretToken(makeSecret(accessToken)),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkNoSecrets("SecretTokenNotFound", "secret github-secret has no key secretToken")
+				}),
+			},
+			WantErrMsg: "secret github-secret has no key secretToken",
+		},
+		{
+			Name: "Sink cannot be resolved",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkNoSink("SinkResolveFailed", `services "sink" not found`)
+				}),
+			},
+			WantAbsent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: `services "sink" not found`,
+		},
+		{
+			Name: "Deployment creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&appsv1.Deployment{}),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "VirtualService creation fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockCreates: errorCreating(&istiov1alpha3.VirtualService{}),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+				makeService(),
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", testErrorMessage)
+				}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Receive adapter created, not available yet",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+				makeService(),
+				makeVirtualService(),
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentUnavailable", "Deployment test-source-githubsource has no available replicas")
+				}),
+			},
+		},
+		{
+			Name: "Webhook registered",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+			},
+		},
+		{
+			Name: "Webhook registration fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(invalidAccessToken),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkSink(sinkURI)
+					s.MarkDeployed()
+					s.MarkNoWebhook("WebhookCreationFailed", "unable to create the webhook: 401 Unauthorized: Bad credentials")
+				}),
+			},
+			WantErrMsg: "unable to create the webhook: 401 Unauthorized: Bad credentials",
+		},
+		{
+			Name: "Registered webhook is kept",
+			InitialState: []runtime.Object{
+				withWebhookIDKey(makeSource(), "5678"),
+				// The webhook would fail to be registered again.
+				makeSecret(invalidAccessToken),
+				makeSinkService(),
+				makeAvailableDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				withWebhookIDKey(makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkSink(sinkURI)
+					s.MarkDeployed()
+					s.MarkWebhook("5678")
+				}), "5678"),
+			},
+		},
+		{
+			Name: "Existing Deployment is updated",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+				withImage(makeAvailableDeployment(), "example.com/old"),
+			},
+			WantPresent: []runtime.Object{
+				makeReadySource(),
+				makeAvailableDeployment(),
+			},
+		},
+		{
+			Name: "Deployment not owned by the GitHubSource",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+				makeUnownedDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+					s.MarkSecrets()
+					s.MarkSink(sinkURI)
+					s.MarkNotDeployed("DeploymentFailure", "Deployment test-source-githubsource is not owned by the GitHubSource")
+				}),
+			},
+			WantErrMsg: "Deployment test-source-githubsource is not owned by the GitHubSource",
+		},
+		{
+			Name: "Updating GitHubSource status fails",
+			InitialState: []runtime.Object{
+				makeSource(),
+				makeSecret(accessToken),
+				makeSinkService(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.GitHubSource{}),
+			},
+			WantPresent: []runtime.Object{
+				makeDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:        c,
+			dynamicClient: tc.GetDynamicClient(),
+			restConfig:    &rest.Config{},
+			recorder:      recorder,
+			adapterImage:  adapterImage,
+			domain:        domain,
+			gateway:       gateway,
+			webhookClient: &fakeWebhookClient{},
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, sourceName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func makeSource() *v1alpha1.GitHubSource {
+	return &v1alpha1.GitHubSource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "GitHubSource",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sourceName,
+			UID:       sourceUID,
+		},
+		Spec: v1alpha1.GitHubSourceSpec{
+			OwnerAndRepository: "knative/eventing",
+			EventTypes:         []string{"push", "pull_request"},
+			AccessToken: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "accessToken",
+			},
+			SecretToken: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "secretToken",
+			},
+			Sink: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       sinkServiceName,
+			},
+		},
+	}
+}
+
+func withWebhookIDKey(s *v1alpha1.GitHubSource, id string) *v1alpha1.GitHubSource {
+	s.Status.WebhookIDKey = id
+	return s
+}
+
+func makeSourceWithStatus(f func(*v1alpha1.GitHubSourceStatus)) *v1alpha1.GitHubSource {
+	return withStatus(makeSource(), f)
+}
+
+func withStatus(s *v1alpha1.GitHubSource, f func(*v1alpha1.GitHubSourceStatus)) *v1alpha1.GitHubSource {
+	s.Finalizers = []string{finalizerName}
+	s.Status.InitializeConditions()
+	f(&s.Status)
+	return s
+}
+
+func makeReadySource() *v1alpha1.GitHubSource {
+	return makeSourceWithStatus(func(s *v1alpha1.GitHubSourceStatus) {
+		s.MarkSecrets()
+		s.MarkSink(sinkURI)
+		s.MarkDeployed()
+		s.MarkWebhook(webhookID)
+	})
+}
+
+func makeDeletingSource() *v1alpha1.GitHubSource {
+	s := withWebhookIDKey(makeSourceWithStatus(func(*v1alpha1.GitHubSourceStatus) {}), webhookID)
+	s.DeletionTimestamp = &deletionTime
+	return s
+}
+
+func withoutFinalizer(s *v1alpha1.GitHubSource) *v1alpha1.GitHubSource {
+	s.Finalizers = nil
+	return s
+}
+
+func makeSecret(accessToken string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      secretName,
+		},
+		Data: map[string][]byte{
+			"accessToken": []byte(accessToken),
+			"secretToken": []byte(secretToken),
+		},
+	}
+}
+
+func withoutSecretToken(secret *corev1.Secret) *corev1.Secret {
+	delete(secret.Data, "secretToken")
+	return secret
+}
+
+func makeSinkService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sinkServiceName,
+		},
+	}
+}
+
+func makeDeployment() *appsv1.Deployment {
+	d := resources.MakeReceiveAdapter(makeSource(), adapterImage, sinkURI)
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	return d
+}
+
+func makeAvailableDeployment() *appsv1.Deployment {
+	d := makeDeployment()
+	d.Status.AvailableReplicas = 1
+	return d
+}
+
+func makeUnownedDeployment() *appsv1.Deployment {
+	d := makeDeployment()
+	d.OwnerReferences = nil
+	return d
+}
+
+func withImage(d *appsv1.Deployment, image string) *appsv1.Deployment {
+	d.Spec.Template.Spec.Containers[0].Image = image
+	return d
+}
+
+func makeService() *corev1.Service {
+	svc := resources.MakeService(makeSource())
+	svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+	return svc
+}
+
+func makeVirtualService() *istiov1alpha3.VirtualService {
+	vs := resources.MakeVirtualService(makeSource(), gateway, domain)
+	vs.TypeMeta = metav1.TypeMeta{APIVersion: istiov1alpha3.SchemeGroupVersion.String(), Kind: "VirtualService"}
+	return vs
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorCreating(t runtime.Object) []controllertesting.MockCreate {
+	return []controllertesting.MockCreate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package githubsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultGitHubAPIURL is the base URL of the API of github.com.
+	defaultGitHubAPIURL = "https://api.github.com"
+)
+
+// hookOptions describe a webhook of a GitHub repository.
+type hookOptions struct {
+	// apiURL is the base URL of the GitHub API.
+	apiURL             string
+	accessToken        string
+	ownerAndRepository string
+
+	// url is the URL the webhook requests are sent to.
+	url         string
+	secretToken string
+	events      []string
+}
+
+// webhookClient manages the webhooks of GitHub repositories.
+type webhookClient interface {
+	// Create registers the webhook described by options and returns its ID.
+	Create(ctx context.Context, options *hookOptions) (string, error)
+	// Delete deletes the webhook hookID. It is not an error if the webhook does not exist.
+	Delete(ctx context.Context, options *hookOptions, hookID string) error
+}
+
+// gitHubWebhookClient is a webhookClient calling the GitHub REST API.
+type gitHubWebhookClient struct {
+	client *http.Client
+}
+
+var _ webhookClient = (*gitHubWebhookClient)(nil)
+
+type hookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret"`
+	InsecureSSL string `json:"insecure_ssl"`
+}
+
+type hook struct {
+	ID     int64      `json:"id,omitempty"`
+	Name   string     `json:"name,omitempty"`
+	Active bool       `json:"active"`
+	Events []string   `json:"events,omitempty"`
+	Config hookConfig `json:"config"`
+}
+
+func (c *gitHubWebhookClient) Create(ctx context.Context, options *hookOptions) (string, error) {
+	body, err := json.Marshal(hook{
+		Name:   "web",
+		Active: true,
+		Events: options.events,
+		Config: hookConfig{
+			URL:         options.url,
+			ContentType: "json",
+			Secret:      options.secretToken,
+			InsecureSSL: "0",
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	res, err := c.do(ctx, http.MethodPost, hooksURL(options), options.accessToken, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return "", responseError("create", res)
+	}
+	created := hook{}
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(created.ID, 10), nil
+}
+
+func (c *gitHubWebhookClient) Delete(ctx context.Context, options *hookOptions, hookID string) error {
+	res, err := c.do(ctx, http.MethodDelete, hooksURL(options)+"/"+hookID, options.accessToken, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotFound {
+		return responseError("delete", res)
+	}
+	return nil
+}
+
+// do sends a request authenticated with accessToken to the GitHub API.
+func (c *gitHubWebhookClient) do(ctx context.Context, method, url, accessToken string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+accessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := c.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// hooksURL returns the URL of the webhooks of the repository in options.
+func hooksURL(options *hookOptions) string {
+	apiURL := options.apiURL
+	if apiURL == "" {
+		apiURL = defaultGitHubAPIURL
+	}
+	return fmt.Sprintf("%s/repos/%s/hooks", strings.TrimSuffix(apiURL, "/"), options.ownerAndRepository)
+}
+
+// responseError returns the error of an unexpected response to the op request, including the
+// message of GitHub.
+func responseError(op string, res *http.Response) error {
+	msg := struct {
+		Message string `json:"message"`
+	}{}
+	json.NewDecoder(io.LimitReader(res.Body, 1<<16)).Decode(&msg)
+	if msg.Message == "" {
+		return fmt.Errorf("unable to %s the webhook: %s", op, res.Status)
+	}
+	return fmt.Errorf("unable to %s the webhook: %s: %s", op, res.Status, msg.Message)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package githubsource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGitHubWebhookClient(t *testing.T) {
+	var got hook
+	deleted := ""
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "token access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/knative/eventing/hooks":
+			json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1234,"name":"web"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/knative/eventing/hooks/1234":
+			deleted = "1234"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	c := &gitHubWebhookClient{}
+	options := &hookOptions{
+		apiURL:             api.URL + "/",
+		accessToken:        "access-token",
+		ownerAndRepository: "knative/eventing",
+		url:                "http://source-githubsource.default.example.com",
+		secretToken:        "secret-token",
+		events:             []string{"push"},
+	}
+	ctx := context.TODO()
+	id, err := c.Create(ctx, options)
+	if err != nil {
+		t.Fatalf("Unexpected error creating the webhook: %v", err)
+	}
+	if id != "1234" {
+		t.Errorf("Unexpected webhook ID %q", id)
+	}
+	want := hook{
+		Name:   "web",
+		Active: true,
+		Events: []string{"push"},
+		Config: hookConfig{
+			URL:         "http://source-githubsource.default.example.com",
+			ContentType: "json",
+			Secret:      "secret-token",
+			InsecureSSL: "0",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected webhook (-want, +got): %v", diff)
+	}
+
+	if err := c.Delete(ctx, options, "1234"); err != nil {
+		t.Errorf("Unexpected error deleting the webhook: %v", err)
+	}
+	if deleted != "1234" {
+		t.Errorf("The webhook was not deleted")
+	}
+	// Deleting a webhook that does not exist succeeds.
+	if err := c.Delete(ctx, options, "5678"); err != nil {
+		t.Errorf("Unexpected error deleting a missing webhook: %v", err)
+	}
+
+	options.accessToken = "wrong"
+	_, err = c.Create(ctx, options)
+	if want := "unable to create the webhook: 401 Unauthorized: Bad credentials"; err == nil || err.Error() != want {
+		t.Errorf("Unexpected error. Expected: %q. Actual: %v", want, err)
+	}
+}
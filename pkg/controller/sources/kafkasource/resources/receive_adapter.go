@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SourceLabelKey is the label that identifies the KafkaSource that an object belongs to.
+	SourceLabelKey = "sources.eventing.knative.dev/kafkaSource"
+)
+
+// ReceiveAdapterName returns the name of the Deployment running the receive adapter of the
+// KafkaSource sourceName.
+func ReceiveAdapterName(sourceName string) string {
+	return fmt.Sprintf("%s-kafkasource", sourceName)
+}
+
+// Labels returns the labels of every object created for the KafkaSource sourceName.
+func Labels(sourceName string) map[string]string {
+	return map[string]string{
+		SourceLabelKey: sourceName,
+	}
+}
+
+// MakeReceiveAdapter creates the Deployment running the receive adapter of s, which sends the
+// events of s to sinkURI.
+func MakeReceiveAdapter(s *v1alpha1.KafkaSource, image, sinkURI string) *appsv1.Deployment {
+	labels := Labels(s.Name)
+	// More replicas would join the consumer group and share the partitions of the topics.
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      ReceiveAdapterName(s.Name),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("KafkaSource")),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: s.Spec.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "receive-adapter",
+						Image: image,
+						Env:   env(s, sinkURI),
+					}},
+				},
+			},
+		},
+	}
+}
+
+// env returns the environment of the receive adapter of s. The credentials are read from their
+// Secrets.
+func env(s *v1alpha1.KafkaSource, sinkURI string) []corev1.EnvVar {
+	env := []corev1.EnvVar{{
+		Name:  "KAFKA_BOOTSTRAP_SERVERS",
+		Value: strings.Join(s.Spec.BootstrapServers, ","),
+	}, {
+		Name:  "KAFKA_TOPICS",
+		Value: strings.Join(s.Spec.Topics, ","),
+	}, {
+		Name:  "KAFKA_CONSUMER_GROUP",
+		Value: s.Spec.ConsumerGroup,
+	}, {
+		Name:  "KAFKA_NET_SASL_ENABLE",
+		Value: strconv.FormatBool(s.Spec.Net.SASL.Enable),
+	}, {
+		Name:  "KAFKA_NET_TLS_ENABLE",
+		Value: strconv.FormatBool(s.Spec.Net.TLS.Enable),
+	}, {
+		Name:  "KAFKA_MAX_RETRIES",
+		Value: strconv.Itoa(maxRetries(s)),
+	}, {
+		Name:  "SINK_URI",
+		Value: sinkURI,
+	}, {
+		Name:  "NAMESPACE",
+		Value: s.Namespace,
+	}, {
+		Name:  "NAME",
+		Value: s.Name,
+	}}
+	if s.Spec.Net.SASL.Enable {
+		env = appendSecretEnv(env, "KAFKA_NET_SASL_USER", s.Spec.Net.SASL.User)
+		env = appendSecretEnv(env, "KAFKA_NET_SASL_PASSWORD", s.Spec.Net.SASL.Password)
+	}
+	if s.Spec.Net.TLS.Enable {
+		env = appendSecretEnv(env, "KAFKA_NET_TLS_CERT", s.Spec.Net.TLS.Cert)
+		env = appendSecretEnv(env, "KAFKA_NET_TLS_KEY", s.Spec.Net.TLS.Key)
+		env = appendSecretEnv(env, "KAFKA_NET_TLS_CA_CERT", s.Spec.Net.TLS.CACert)
+	}
+	return env
+}
+
+// maxRetries returns the number of times the receive adapter of s sends a record again.
+func maxRetries(s *v1alpha1.KafkaSource) int {
+	if s.Spec.MaxRetries == nil {
+		return v1alpha1.DefaultKafkaMaxRetries
+	}
+	return int(*s.Spec.MaxRetries)
+}
+
+// appendSecretEnv appends the environment variable name, read from secret, to env if secret is
+// set.
+func appendSecretEnv(env []corev1.EnvVar, name string, secret *corev1.SecretKeySelector) []corev1.EnvVar {
+	if secret == nil {
+		return env
+	}
+	return append(env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: secret,
+		},
+	})
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mqttsource
+
+import (
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/sources/adapter"
+	"github.com/knative/eventing/pkg/controller/sources/mqttsource/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// kind gives the adapter.Reconciler access to the MQTTSources, whose receive adapter subscribes to
+// their topics.
+type kind struct {
+	adapterImage string
+}
+
+// Verify the struct implements adapter.Kind
+var _ adapter.Kind = kind{}
+
+func (kind) Name() string {
+	return "MQTTSource"
+}
+
+func (kind) New() adapter.Source {
+	return &v1alpha1.MQTTSource{}
+}
+
+func (kind) Sink(s adapter.Source) *corev1.ObjectReference {
+	return s.(*v1alpha1.MQTTSource).Spec.Sink
+}
+
+func (kind) Status(s adapter.Source) adapter.Status {
+	return &s.(*v1alpha1.MQTTSource).Status
+}
+
+func (kind) CopyStatus(dst, src adapter.Source) {
+	dst.(*v1alpha1.MQTTSource).Status = src.(*v1alpha1.MQTTSource).Status
+}
+
+func (k kind) ReceiveAdapter(s adapter.Source, sinkURI string) *appsv1.Deployment {
+	return resources.MakeReceiveAdapter(s.(*v1alpha1.MQTTSource), k.adapterImage, sinkURI)
+}
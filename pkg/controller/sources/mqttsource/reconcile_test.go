@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mqttsource
+
+import (
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/sources/adapter"
+	adaptertesting "github.com/knative/eventing/pkg/controller/sources/adapter/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	adapterImage = "adapter-image"
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestReconcile(t *testing.T) {
+	adaptertesting.RunReconcileTests(t, kind{adapterImage: adapterImage}, func() adapter.Source {
+		return makeSource()
+	})
+}
+
+func makeSource() *v1alpha1.MQTTSource {
+	return &v1alpha1.MQTTSource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "MQTTSource",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: adaptertesting.TestNS,
+			Name:      adaptertesting.SourceName,
+			UID:       adaptertesting.SourceUID,
+		},
+		Spec: v1alpha1.MQTTSourceSpec{
+			Broker: "mosquitto.iot:1883",
+			Topics: []v1alpha1.MQTTSourceTopic{{Filter: "sensors/#"}},
+			Sink:   adaptertesting.Sink(),
+		},
+	}
+}
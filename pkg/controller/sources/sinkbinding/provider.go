@@ -0,0 +1,116 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sinkbinding
+
+import (
+	"context"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/controller/tuning"
+	"github.com/knative/eventing/pkg/sinkbinding"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "sink-binding-controller"
+)
+
+type reconciler struct {
+	client        client.Client
+	restConfig    *rest.Config
+	dynamicClient dynamic.Interface
+	recorder      record.EventRecorder
+}
+
+// Verify the struct implements reconcile.Reconciler
+var _ reconcile.Reconciler = &reconciler{}
+
+// ProvideController returns a SinkBinding controller.
+func ProvideController(mgr manager.Manager) (controller.Controller, error) {
+	// Setup a new controller to Reconcile SinkBindings.
+	r := &reconciler{
+		recorder: mgr.GetRecorder(controllerAgentName),
+	}
+	c, err := tuning.NewController(controllerAgentName, mgr, metrics.InstrumentReconciler(controllerAgentName, r))
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch SinkBinding events and enqueue SinkBinding object key.
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.SinkBinding{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+
+	// Watch the kinds of subject, so that the SinkBindings of a subject are reconciled when it is
+	// created or changed.
+	for _, gvk := range v1alpha1.SinkBindingSubjectKinds {
+		subject, _ := sinkbinding.NewSubject(gvk)
+		err = c.Watch(&source.Kind{Type: subject}, &handler.EnqueueRequestsFromMapFunc{ToRequests: &mapSubjectToBindings{r: r, gvk: gvk}})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+type mapSubjectToBindings struct {
+	r   *reconciler
+	gvk schema.GroupVersionKind
+}
+
+// Map returns the requests of the SinkBindings whose subject is o.
+func (m *mapSubjectToBindings) Map(o handler.MapObject) []reconcile.Request {
+	bindings, err := m.r.listBindings(context.TODO(), o.Meta.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	var reqs []reconcile.Request
+	for i := range bindings {
+		b := &bindings[i]
+		if sinkbinding.IsSubject(b, m.gvk, o.Meta.GetName()) {
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: b.Namespace, Name: b.Name},
+			})
+		}
+	}
+	return reqs
+}
+
+func (r *reconciler) InjectClient(c client.Client) error {
+	r.client = c
+	return nil
+}
+
+func (r *reconciler) InjectConfig(c *rest.Config) error {
+	r.restConfig = c
+	var err error
+	r.dynamicClient, err = dynamic.NewForConfig(c)
+	return err
+}
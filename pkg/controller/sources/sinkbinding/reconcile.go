@@ -0,0 +1,211 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sinkbinding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/sinkbinding"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// finalizerName is the finalizer that makes the controller unbind the subject of a
+	// SinkBinding before the SinkBinding is deleted.
+	finalizerName = controllerAgentName
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the SinkBinding
+// resource with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling sink binding %v", request)
+	ctx := context.TODO()
+	binding := &v1alpha1.SinkBinding{}
+	err := r.client.Get(ctx, request.NamespacedName, binding)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find sink binding %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch SinkBinding %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the SinkBinding and then write back any status
+	// updates regardless of whether the reconcile error out.
+	binding = binding.DeepCopy()
+	err = r.reconcile(ctx, binding)
+	if updateStatusErr := r.updateStatus(ctx, binding); updateStatusErr != nil {
+		glog.Warningf("Failed to update sink binding status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, b *v1alpha1.SinkBinding) error {
+	b.Status.InitializeConditions()
+
+	if b.DeletionTimestamp != nil {
+		if err := r.unbind(ctx, b); err != nil {
+			glog.Warningf("Failed to unbind the subject of sink binding %s/%s: %v", b.Namespace, b.Name, err)
+			return err
+		}
+		removeFinalizer(b)
+		return nil
+	}
+	addFinalizer(b)
+
+	// The sink is resolved like the subscriber of a Subscription.
+	sinkURI, err := controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, b.Namespace, eventingv1alpha1.SubscriberSpec{Ref: b.Spec.Sink})
+	if err != nil {
+		glog.Warningf("Failed to resolve the sink of sink binding %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkNoSink("SinkResolveFailed", "%v", err)
+		return err
+	}
+	b.Status.MarkSink(sinkURI)
+
+	subject, err := r.getSubject(ctx, b)
+	if errors.IsNotFound(err) {
+		// The SinkBinding is reconciled again when the subject is created.
+		b.Status.MarkNotBound("SubjectNotFound", "%s %s does not exist", b.Spec.Subject.Kind, b.Spec.Subject.Name)
+		return nil
+	}
+	if err != nil {
+		b.Status.MarkNotBound("SubjectGetFailed", "%v", err)
+		return err
+	}
+	if !sinkbinding.Bind(sinkbinding.PodSpec(subject), sinkURI) {
+		b.Status.MarkBound()
+		return nil
+	}
+	if _, ok := subject.(*batchv1.Job); ok {
+		// The pod template of a Job cannot be updated. Jobs are bound by the webhook when they
+		// are created.
+		b.Status.MarkNotBound("SubjectImmutable", "Job %s was created before it could be bound, and must be created again", b.Spec.Subject.Name)
+		return nil
+	}
+	if err := r.client.Update(ctx, subject); err != nil {
+		glog.Warningf("Failed to bind the subject of sink binding %s/%s: %v", b.Namespace, b.Name, err)
+		b.Status.MarkNotBound("SubjectUpdateFailed", "%v", err)
+		return err
+	}
+	b.Status.MarkBound()
+	return nil
+}
+
+// getSubject returns the subject of b.
+func (r *reconciler) getSubject(ctx context.Context, b *v1alpha1.SinkBinding) (runtime.Object, error) {
+	gvk := schema.FromAPIVersionAndKind(b.Spec.Subject.APIVersion, b.Spec.Subject.Kind)
+	subject, ok := sinkbinding.NewSubject(gvk)
+	if !ok {
+		return nil, fmt.Errorf("%s %s cannot be bound", b.Spec.Subject.APIVersion, b.Spec.Subject.Kind)
+	}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Spec.Subject.Name}, subject); err != nil {
+		return nil, err
+	}
+	return subject, nil
+}
+
+// unbind removes the URI of the sink from the subject of b. Jobs cannot be updated and are left
+// bound.
+func (r *reconciler) unbind(ctx context.Context, b *v1alpha1.SinkBinding) error {
+	subject, err := r.getSubject(ctx, b)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := subject.(*batchv1.Job); ok {
+		return nil
+	}
+	if !sinkbinding.Unbind(sinkbinding.PodSpec(subject)) {
+		return nil
+	}
+	return r.client.Update(ctx, subject)
+}
+
+// listBindings returns the SinkBindings in namespace.
+func (r *reconciler) listBindings(ctx context.Context, namespace string) ([]v1alpha1.SinkBinding, error) {
+	bindings := make([]v1alpha1.SinkBinding, 0)
+
+	opts := &client.ListOptions{
+		// TODO this is here because the fake client needs it. Remove this when it's no longer
+		// needed.
+		Raw: &metav1.ListOptions{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "SinkBinding",
+			},
+		},
+		Namespace: namespace,
+	}
+	for {
+		bl := &v1alpha1.SinkBindingList{}
+		if err := r.client.List(ctx, opts, bl); err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, bl.Items...)
+		if bl.Continue == "" {
+			return bindings, nil
+		}
+		opts.Raw.Continue = bl.Continue
+	}
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, b *v1alpha1.SinkBinding) error {
+	current := &v1alpha1.SinkBinding{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, b.Status) && equality.Semantic.DeepEqual(current.Finalizers, b.Finalizers) {
+		return nil
+	}
+	current.Status = b.Status
+	current.Finalizers = b.Finalizers
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the SinkBinding resource.
+	return r.client.Update(ctx, current)
+}
+
+func addFinalizer(b *v1alpha1.SinkBinding) {
+	finalizers := sets.NewString(b.Finalizers...)
+	finalizers.Insert(finalizerName)
+	b.Finalizers = finalizers.List()
+}
+
+func removeFinalizer(b *v1alpha1.SinkBinding) {
+	finalizers := sets.NewString(b.Finalizers...)
+	finalizers.Delete(finalizerName)
+	b.Finalizers = finalizers.List()
+}
@@ -0,0 +1,424 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sinkbinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	controllertesting "github.com/knative/eventing/pkg/controller/testing"
+	"github.com/knative/eventing/pkg/sinkbinding"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testNS      = "test-namespace"
+	bindingName = "test-binding"
+	bindingUID  = "test-uid"
+	subjectName = "emitter"
+
+	sinkServiceName = "sink"
+	sinkURI         = "http://sink.test-namespace.svc.cluster.local/"
+
+	testErrorMessage = "test induced error"
+)
+
+var (
+	// deletionTime is used when objects are marked as deleted. Rfc3339Copy()
+	// truncates to seconds to match the loss of precision during serialization.
+	deletionTime = metav1.Now().Rfc3339Copy()
+)
+
+func init() {
+	// Add types to scheme.
+	v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestInjectClient(t *testing.T) {
+	r := &reconciler{}
+	n := fake.NewFakeClient()
+	if err := r.InjectClient(n); err != nil {
+		t.Errorf("Unexpected error injecting the client: %v", err)
+	}
+	if n != r.client {
+		t.Errorf("Unexpected client. Expected: '%v'. Actual: '%v'", n, r.client)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []controllertesting.TestCase{
+		{
+			Name: "SinkBinding not found",
+		},
+		{
+			Name: "Error getting SinkBinding",
+			Mocks: controllertesting.Mocks{
+				MockGets: errorGetting(&v1alpha1.SinkBinding{}),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "SinkBinding being deleted",
+			InitialState: []runtime.Object{
+				makeDeletingBinding(),
+				makeSinkService(),
+				makeBoundDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				withoutFinalizer(makeDeletingBinding()),
+				makeDeployment(),
+			},
+		},
+		{
+			Name: "SinkBinding of a Job being deleted",
+			InitialState: []runtime.Object{
+				withJobSubject(makeDeletingBinding()),
+				makeSinkService(),
+				makeBoundJob(),
+			},
+			WantPresent: []runtime.Object{
+				withoutFinalizer(withJobSubject(makeDeletingBinding())),
+				makeBoundJob(),
+			},
+		},
+		{
+			Name: "SinkBinding being deleted after its subject",
+			InitialState: []runtime.Object{
+				makeDeletingBinding(),
+			},
+			WantPresent: []runtime.Object{
+				withoutFinalizer(makeDeletingBinding()),
+			},
+		},
+		{
+			Name: "Sink cannot be resolved",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeBindingWithStatus(func(s *v1alpha1.SinkBindingStatus) {
+					s.MarkNoSink("SinkResolveFailed", `services "sink" not found`)
+				}),
+				makeDeployment(),
+			},
+			WantErrMsg: `services "sink" not found`,
+		},
+		{
+			Name: "Subject not found",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeSinkService(),
+			},
+			WantPresent: []runtime.Object{
+				makeBindingWithStatus(func(s *v1alpha1.SinkBindingStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotBound("SubjectNotFound", "Deployment emitter does not exist")
+				}),
+			},
+		},
+		{
+			Name: "Deployment is bound",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeSinkService(),
+				makeDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyBinding(),
+				makeBoundDeployment(),
+			},
+		},
+		{
+			Name: "Deployment bound to another sink is bound again",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeSinkService(),
+				withSinkURI(makeDeployment(), "http://old.test-namespace.svc.cluster.local/"),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyBinding(),
+				makeBoundDeployment(),
+			},
+		},
+		{
+			Name: "Deployment already bound",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeSinkService(),
+				makeBoundDeployment(),
+			},
+			WantPresent: []runtime.Object{
+				makeReadyBinding(),
+				makeBoundDeployment(),
+			},
+		},
+		{
+			Name: "Deployment update fails",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeSinkService(),
+				makeDeployment(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&appsv1.Deployment{}),
+			},
+			WantPresent: []runtime.Object{
+				makeBindingWithStatus(func(s *v1alpha1.SinkBindingStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotBound("SubjectUpdateFailed", testErrorMessage)
+				}),
+				makeDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+		{
+			Name: "Job bound when it was created",
+			InitialState: []runtime.Object{
+				withJobSubject(makeBinding()),
+				makeSinkService(),
+				makeBoundJob(),
+			},
+			WantPresent: []runtime.Object{
+				withJobSubject(makeReadyBinding()),
+				makeBoundJob(),
+			},
+		},
+		{
+			Name: "Job created before it could be bound",
+			InitialState: []runtime.Object{
+				withJobSubject(makeBinding()),
+				makeSinkService(),
+				makeJob(),
+			},
+			WantPresent: []runtime.Object{
+				withJobSubject(makeBindingWithStatus(func(s *v1alpha1.SinkBindingStatus) {
+					s.MarkSink(sinkURI)
+					s.MarkNotBound("SubjectImmutable", "Job emitter was created before it could be bound, and must be created again")
+				})),
+				makeJob(),
+			},
+		},
+		{
+			Name: "Updating SinkBinding status fails",
+			InitialState: []runtime.Object{
+				makeBinding(),
+				makeSinkService(),
+				makeDeployment(),
+			},
+			Mocks: controllertesting.Mocks{
+				MockUpdates: errorUpdating(&v1alpha1.SinkBinding{}),
+			},
+			WantPresent: []runtime.Object{
+				makeBoundDeployment(),
+			},
+			WantErrMsg: testErrorMessage,
+		},
+	}
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	for _, tc := range testCases {
+		c := tc.GetClient()
+		r := &reconciler{
+			client:        c,
+			dynamicClient: tc.GetDynamicClient(),
+			restConfig:    &rest.Config{},
+			recorder:      recorder,
+		}
+		if tc.ReconcileKey == "" {
+			tc.ReconcileKey = fmt.Sprintf("%s/%s", testNS, bindingName)
+		}
+		tc.IgnoreTimes = true
+		t.Run(tc.Name, tc.Runner(t, r, c))
+	}
+}
+
+func TestMapSubjectToBindings(t *testing.T) {
+	other := withName(makeBinding(), "other-binding")
+	other.Spec.Subject.Name = "other"
+	c := fake.NewFakeClient(
+		makeBinding(),
+		withName(withJobSubject(makeBinding()), "job-binding"),
+		other,
+	)
+	m := &mapSubjectToBindings{
+		r:   &reconciler{client: c},
+		gvk: appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	}
+	d := makeDeployment()
+	got := m.Map(handler.MapObject{Meta: d, Object: d})
+	want := []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: testNS, Name: bindingName},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected requests (-want, +got) = %v", diff)
+	}
+}
+
+func makeBinding() *v1alpha1.SinkBinding {
+	return &v1alpha1.SinkBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "SinkBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      bindingName,
+			UID:       bindingUID,
+		},
+		Spec: v1alpha1.SinkBindingSpec{
+			Subject: corev1.ObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       subjectName,
+			},
+			Sink: &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Name:       sinkServiceName,
+			},
+		},
+	}
+}
+
+func makeBindingWithStatus(f func(*v1alpha1.SinkBindingStatus)) *v1alpha1.SinkBinding {
+	b := makeBinding()
+	b.Finalizers = []string{finalizerName}
+	b.Status.InitializeConditions()
+	f(&b.Status)
+	return b
+}
+
+func makeReadyBinding() *v1alpha1.SinkBinding {
+	return makeBindingWithStatus(func(s *v1alpha1.SinkBindingStatus) {
+		s.MarkSink(sinkURI)
+		s.MarkBound()
+	})
+}
+
+func makeDeletingBinding() *v1alpha1.SinkBinding {
+	b := makeReadyBinding()
+	b.DeletionTimestamp = &deletionTime
+	return b
+}
+
+func withoutFinalizer(b *v1alpha1.SinkBinding) *v1alpha1.SinkBinding {
+	b.Finalizers = nil
+	return b
+}
+
+func withJobSubject(b *v1alpha1.SinkBinding) *v1alpha1.SinkBinding {
+	b.Spec.Subject.APIVersion = "batch/v1"
+	b.Spec.Subject.Kind = "Job"
+	return b
+}
+
+func withName(b *v1alpha1.SinkBinding, name string) *v1alpha1.SinkBinding {
+	b.Name = name
+	return b
+}
+
+func makeSinkService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sinkServiceName,
+		},
+	}
+}
+
+func podTemplate() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "emitter", Image: "emitter-image"}},
+		},
+	}
+}
+
+func makeDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      subjectName,
+		},
+		Spec: appsv1.DeploymentSpec{Template: podTemplate()},
+	}
+}
+
+func makeBoundDeployment() *appsv1.Deployment {
+	return withSinkURI(makeDeployment(), sinkURI)
+}
+
+func withSinkURI(d *appsv1.Deployment, uri string) *appsv1.Deployment {
+	sinkbinding.Bind(&d.Spec.Template.Spec, uri)
+	return d
+}
+
+func makeJob() *batchv1.Job {
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      subjectName,
+		},
+		Spec: batchv1.JobSpec{Template: podTemplate()},
+	}
+}
+
+func makeBoundJob() *batchv1.Job {
+	j := makeJob()
+	sinkbinding.Bind(&j.Spec.Template.Spec, sinkURI)
+	return j
+}
+
+func errorGetting(t runtime.Object) []controllertesting.MockGet {
+	return []controllertesting.MockGet{
+		func(_ client.Client, _ context.Context, _ client.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
+
+func errorUpdating(t runtime.Object) []controllertesting.MockUpdate {
+	return []controllertesting.MockUpdate{
+		func(_ client.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", t) {
+				return controllertesting.Handled, errors.New(testErrorMessage)
+			}
+			return controllertesting.Unhandled, nil
+		},
+	}
+}
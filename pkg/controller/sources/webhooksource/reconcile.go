@@ -0,0 +1,188 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package webhooksource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/controller/sources/webhooksource/resources"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the WebhookSource
+// resource with the current status of the resource.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	glog.Infof("Reconciling webhook source %v", request)
+	ctx := context.TODO()
+	source := &v1alpha1.WebhookSource{}
+	err := r.client.Get(ctx, request.NamespacedName, source)
+
+	if errors.IsNotFound(err) {
+		glog.Errorf("could not find webhook source %v\n", request)
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		glog.Errorf("could not fetch WebhookSource %v for %+v\n", err, request)
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile this copy of the WebhookSource and then write back any status
+	// updates regardless of whether the reconcile error out.
+	source = source.DeepCopy()
+	err = r.reconcile(ctx, source)
+	if updateStatusErr := r.updateStatus(ctx, source); updateStatusErr != nil {
+		glog.Warningf("Failed to update webhook source status: %v", updateStatusErr)
+		return reconcile.Result{}, updateStatusErr
+	}
+
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, s *v1alpha1.WebhookSource) error {
+	s.Status.InitializeConditions()
+
+	if s.DeletionTimestamp != nil {
+		// The receive adapter is owned by the WebhookSource and will be garbage collected.
+		return nil
+	}
+
+	// The sink is resolved like the subscriber of a Subscription.
+	sinkURI, err := controller.ResolveSubscriberSpec(ctx, r.client, r.dynamicClient, s.Namespace, eventingv1alpha1.SubscriberSpec{Ref: s.Spec.Sink})
+	if err != nil {
+		glog.Warningf("Failed to resolve the sink of webhook source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNoSink("SinkResolveFailed", "%v", err)
+		return err
+	}
+	s.Status.MarkSink(sinkURI)
+	s.Status.URL = resources.URL(s, r.domain)
+
+	d, err := r.reconcileReceiveAdapter(ctx, s, sinkURI)
+	if err != nil {
+		glog.Warningf("Failed to reconcile the receive adapter of webhook source %s/%s: %v", s.Namespace, s.Name, err)
+		s.Status.MarkNotDeployed("DeploymentFailure", "%v", err)
+		return err
+	}
+	if d.Status.AvailableReplicas == 0 {
+		// The WebhookSource is reconciled again when the Deployment changes.
+		s.Status.MarkNotDeployed("DeploymentUnavailable", "Deployment %s has no available replicas", d.Name)
+		return nil
+	}
+	s.Status.MarkDeployed()
+	return nil
+}
+
+// reconcileReceiveAdapter creates the Deployment, Service and VirtualService of the receive adapter
+// of s, or updates the existing ones to match them. It returns the Deployment.
+func (r *reconciler) reconcileReceiveAdapter(ctx context.Context, s *v1alpha1.WebhookSource, sinkURI string) (*appsv1.Deployment, error) {
+	d := resources.MakeReceiveAdapter(s, r.adapterImage, sinkURI, r.domain)
+	current := &appsv1.Deployment{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: d.Namespace, Name: d.Name}, current)
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, d); err != nil {
+			return nil, err
+		}
+		current = d
+	} else if err != nil {
+		return nil, err
+	} else if !metav1.IsControlledBy(current, s) {
+		return nil, fmt.Errorf("Deployment %s is not owned by the WebhookSource", current.Name)
+	} else if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
+		current.Spec = d.Spec
+		if err := r.client.Update(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.reconcileService(ctx, s, resources.MakeService(s)); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileVirtualService(ctx, s, resources.MakeVirtualService(s, r.gateway, r.domain)); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// reconcileService creates the Service svc, or updates the ports and selector of the existing
+// Service to match it.
+func (r *reconciler) reconcileService(ctx context.Context, s *v1alpha1.WebhookSource, svc *corev1.Service) error {
+	current := &corev1.Service{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: svc.Namespace, Name: svc.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, svc)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return fmt.Errorf("Service %s is not owned by the WebhookSource", current.Name)
+	}
+	// The cluster IP is assigned by the API server and cannot be changed.
+	if !equality.Semantic.DeepDerivative(svc.Spec, current.Spec) {
+		current.Spec.Ports = svc.Spec.Ports
+		current.Spec.Selector = svc.Spec.Selector
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+// reconcileVirtualService creates the VirtualService vs, or updates the spec of the existing
+// VirtualService to match it.
+func (r *reconciler) reconcileVirtualService(ctx context.Context, s *v1alpha1.WebhookSource, vs *istiov1alpha3.VirtualService) error {
+	current := &istiov1alpha3.VirtualService{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: vs.Namespace, Name: vs.Name}, current)
+	if errors.IsNotFound(err) {
+		return r.client.Create(ctx, vs)
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(current, s) {
+		return fmt.Errorf("VirtualService %s is not owned by the WebhookSource", current.Name)
+	}
+	if !equality.Semantic.DeepDerivative(vs.Spec, current.Spec) {
+		current.Spec = vs.Spec
+		return r.client.Update(ctx, current)
+	}
+	return nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, s *v1alpha1.WebhookSource) error {
+	current := &v1alpha1.WebhookSource{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: s.Namespace, Name: s.Name}, current); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(current.Status, s.Status) {
+		return nil
+	}
+	current.Status = s.Status
+	// Until #38113 is merged, we must use Update instead of UpdateStatus to
+	// update the Status block of the WebhookSource resource.
+	return r.client.Update(ctx, current)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resources
+
+import (
+	"fmt"
+
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// SourceLabelKey is the label that identifies the WebhookSource that an object belongs to.
+	SourceLabelKey = "sources.eventing.knative.dev/webhookSource"
+
+	// adapterPort is the port the receive adapter listens on.
+	adapterPort = 8080
+)
+
+// ReceiveAdapterName returns the name of the Deployment, Service and VirtualService of the receive
+// adapter of the WebhookSource sourceName.
+func ReceiveAdapterName(sourceName string) string {
+	return fmt.Sprintf("%s-webhooksource", sourceName)
+}
+
+// Labels returns the labels of every object created for the WebhookSource sourceName.
+func Labels(sourceName string) map[string]string {
+	return map[string]string{
+		SourceLabelKey: sourceName,
+	}
+}
+
+// Host returns the host name, in domain, at which the receive adapter of s accepts the posted
+// payloads.
+func Host(s *v1alpha1.WebhookSource, domain string) string {
+	return fmt.Sprintf("%s.%s.%s", ReceiveAdapterName(s.Name), s.Namespace, domain)
+}
+
+// URL returns the URL of the endpoint of s in domain. TLS is terminated by the gateway.
+func URL(s *v1alpha1.WebhookSource, domain string) string {
+	return fmt.Sprintf("https://%s", Host(s, domain))
+}
+
+func ownerReferences(s *v1alpha1.WebhookSource) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		*metav1.NewControllerRef(s, v1alpha1.SchemeGroupVersion.WithKind("WebhookSource")),
+	}
+}
+
+// MakeReceiveAdapter creates the Deployment running the receive adapter of s, which sends the
+// events of s to sinkURI. The endpoint of s is in domain.
+func MakeReceiveAdapter(s *v1alpha1.WebhookSource, image, sinkURI, domain string) *appsv1.Deployment {
+	labels := Labels(s.Name)
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       s.Namespace,
+			Name:            ReceiveAdapterName(s.Name),
+			Labels:          labels,
+			OwnerReferences: ownerReferences(s),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: s.Spec.ServiceAccountName,
+					Containers: []corev1.Container{{
+						Name:  "receive-adapter",
+						Image: image,
+						Ports: []corev1.ContainerPort{{
+							Name:          "http",
+							ContainerPort: adapterPort,
+						}},
+						Env: env(s, sinkURI, domain),
+					}},
+				},
+			},
+		},
+	}
+}
+
+// env returns the environment of the receive adapter of s. The secret is read from its Secret.
+func env(s *v1alpha1.WebhookSource, sinkURI, domain string) []corev1.EnvVar {
+	eventSource := s.Spec.EventSource
+	if eventSource == "" {
+		eventSource = URL(s, domain)
+	}
+	env := []corev1.EnvVar{{
+		Name:  "EVENT_TYPE",
+		Value: s.Spec.EventType,
+	}, {
+		Name:  "EVENT_SOURCE",
+		Value: eventSource,
+	}, {
+		Name:  "SIGNATURE_HEADER",
+		Value: s.Spec.SignatureHeader,
+	}, {
+		Name:  "SINK_URI",
+		Value: sinkURI,
+	}, {
+		Name:  "NAMESPACE",
+		Value: s.Namespace,
+	}, {
+		Name:  "NAME",
+		Value: s.Name,
+	}}
+	if s.Spec.Secret != nil {
+		env = append(env, corev1.EnvVar{
+			Name: "SECRET",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: s.Spec.Secret,
+			},
+		})
+	}
+	return env
+}
+
+// MakeService creates the Service in front of the receive adapter of s.
+func MakeService(s *v1alpha1.WebhookSource) *corev1.Service {
+	labels := Labels(s.Name)
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       s.Namespace,
+			Name:            ReceiveAdapterName(s.Name),
+			Labels:          labels,
+			OwnerReferences: ownerReferences(s),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(adapterPort),
+			}},
+		},
+	}
+}
+
+// MakeVirtualService creates the VirtualService that routes the requests for the host of s in
+// domain, received by gateway, to the Service of its receive adapter.
+func MakeVirtualService(s *v1alpha1.WebhookSource, gateway, domain string) *istiov1alpha3.VirtualService {
+	return &istiov1alpha3.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       s.Namespace,
+			Name:            ReceiveAdapterName(s.Name),
+			Labels:          Labels(s.Name),
+			OwnerReferences: ownerReferences(s),
+		},
+		Spec: istiov1alpha3.VirtualServiceSpec{
+			Gateways: []string{gateway},
+			Hosts:    []string{Host(s, domain)},
+			Http: []istiov1alpha3.HTTPRoute{{
+				Route: []istiov1alpha3.DestinationWeight{{
+					Destination: istiov1alpha3.Destination{
+						Host: controller.ServiceHostName(ReceiveAdapterName(s.Name), s.Namespace),
+						Port: istiov1alpha3.PortSelector{
+							Number: 80,
+						},
+					},
+				}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status coalesces the updates of the status of the objects reconciled by the controllers,
+// so that thousands of objects reconciling at once do not overwhelm the API server: the updates of
+// an object within a window are written once, with its latest status, updates that would not change
+// the status are skipped, and the writes are rate limited.
+package status
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WindowEnv is the environment variable setting how long the updates of the status of an
+	// object are coalesced before the status is written.
+	WindowEnv = "STATUS_UPDATE_WINDOW"
+	// QPSEnv is the environment variable setting how many statuses are written per second.
+	QPSEnv = "STATUS_UPDATE_QPS"
+	// BurstEnv is the environment variable setting how many statuses can be written at once, above
+	// the QPSEnv rate.
+	BurstEnv = "STATUS_UPDATE_BURST"
+
+	defaultWindow = time.Second
+	defaultQPS    = 20
+	defaultBurst  = 50
+)
+
+// Options configures a Coalescer.
+type Options struct {
+	// Window is how long the updates of the status of an object are coalesced.
+	Window time.Duration
+	// QPS and Burst rate limit the writes of statuses.
+	QPS   float64
+	Burst int
+}
+
+// DefaultOptions returns the default Options: a window of one second, and 20 writes per second with
+// bursts of 50.
+func DefaultOptions() Options {
+	return Options{
+		Window: defaultWindow,
+		QPS:    defaultQPS,
+		Burst:  defaultBurst,
+	}
+}
+
+// OptionsFromEnv returns the Options set by WindowEnv, QPSEnv and BurstEnv, or their defaults.
+func OptionsFromEnv() (Options, error) {
+	opts := DefaultOptions()
+	if v := os.Getenv(WindowEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return opts, fmt.Errorf("%s must be a duration, got %q", WindowEnv, v)
+		}
+		opts.Window = d
+	}
+	if v := os.Getenv(QPSEnv); v != "" {
+		qps, err := strconv.ParseFloat(v, 64)
+		if err != nil || qps <= 0 {
+			return opts, fmt.Errorf("%s must be a positive number, got %q", QPSEnv, v)
+		}
+		opts.QPS = qps
+	}
+	if v := os.Getenv(BurstEnv); v != "" {
+		burst, err := strconv.Atoi(v)
+		if err != nil || burst <= 0 {
+			return opts, fmt.Errorf("%s must be a positive integer, got %q", BurstEnv, v)
+		}
+		opts.Burst = burst
+	}
+	return opts, nil
+}
+
+// key identifies an object whose status is updated.
+type key struct {
+	typ reflect.Type
+	types.NamespacedName
+}
+
+// Coalescer writes the statuses of objects through the status subresource, coalescing the updates
+// of each object. It is a manager.Runnable, writing the statuses while it is started.
+type Coalescer struct {
+	client  client.Client
+	logger  *zap.Logger
+	window  time.Duration
+	limiter *rate.Limiter
+	queue   workqueue.RateLimitingInterface
+
+	mu sync.Mutex
+	// pending holds the latest status of the objects waiting to be written.
+	pending map[key]runtime.Object
+}
+
+// NewCoalescer creates a Coalescer writing the statuses with c.
+func NewCoalescer(c client.Client, opts Options, logger *zap.Logger) *Coalescer {
+	return &Coalescer{
+		client:  c,
+		logger:  logger.With(zap.String("component", "status-coalescer")),
+		window:  opts.Window,
+		limiter: rate.NewLimiter(rate.Limit(opts.QPS), opts.Burst),
+		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "status-updates"),
+		pending: make(map[key]runtime.Object),
+	}
+}
+
+// Update schedules the update of the status of obj, a pointer to an object with a Status field, to
+// the status of obj. It is written after the window, unless another update of obj replaces it in
+// the meantime.
+func (c *Coalescer) Update(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if _, err := statusOf(obj); err != nil {
+		return err
+	}
+	k := key{
+		typ:            reflect.TypeOf(obj),
+		NamespacedName: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()},
+	}
+	c.mu.Lock()
+	c.pending[k] = obj.DeepCopyObject()
+	c.mu.Unlock()
+	c.queue.AddAfter(k, c.window)
+	return nil
+}
+
+// Start writes the statuses until stop is closed.
+func (c *Coalescer) Start(stop <-chan struct{}) error {
+	go func() {
+		<-stop
+		c.queue.ShutDown()
+	}()
+	for c.processNextItem() {
+	}
+	return nil
+}
+
+func (c *Coalescer) processNextItem() bool {
+	item, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(item)
+
+	k := item.(key)
+	c.mu.Lock()
+	desired, ok := c.pending[k]
+	delete(c.pending, k)
+	c.mu.Unlock()
+	if !ok {
+		c.queue.Forget(item)
+		return true
+	}
+
+	if err := c.write(k, desired); err != nil {
+		c.logger.Info("Error updating the status, retrying", zap.Any("object", k.NamespacedName), zap.Error(err))
+		c.mu.Lock()
+		// Retry with the status that failed, unless a newer one was given in the meantime.
+		if _, newer := c.pending[k]; !newer {
+			c.pending[k] = desired
+		}
+		c.mu.Unlock()
+		c.queue.AddRateLimited(item)
+		return true
+	}
+	c.queue.Forget(item)
+	return true
+}
+
+// write updates the status of the object of k to the status of desired, unless it has it already.
+func (c *Coalescer) write(k key, desired runtime.Object) error {
+	ctx := context.TODO()
+	current := desired.DeepCopyObject()
+	if err := c.client.Get(ctx, k.NamespacedName, current); err != nil {
+		if errors.IsNotFound(err) {
+			// The object was deleted, there is no status to update anymore.
+			return nil
+		}
+		return err
+	}
+	want, err := statusOf(desired)
+	if err != nil {
+		return err
+	}
+	got, err := statusOf(current)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(want.Interface(), got.Interface()) {
+		return nil
+	}
+	got.Set(want)
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.client.Status().Update(ctx, current)
+}
+
+// statusOf returns the Status field of obj.
+func statusOf(obj runtime.Object) (reflect.Value, error) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%T is not a pointer to a struct", obj)
+	}
+	status := v.Elem().FieldByName("Status")
+	if !status.IsValid() {
+		return reflect.Value{}, fmt.Errorf("%T has no Status field", obj)
+	}
+	return status, nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// countingClient counts the status updates, and fails the first failures of them.
+type countingClient struct {
+	client.Client
+	mu       sync.Mutex
+	updates  int
+	failures int
+}
+
+func (c *countingClient) Status() client.StatusWriter {
+	return c
+}
+
+func (c *countingClient) Update(ctx context.Context, obj runtime.Object) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("test induced error")
+	}
+	c.updates++
+	return c.Client.Status().Update(ctx, obj)
+}
+
+func (c *countingClient) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.updates
+}
+
+func pod(phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+// startCoalescer starts a Coalescer writing with c, until the returned func is called.
+func startCoalescer(c client.Client) (*Coalescer, func()) {
+	coalescer := NewCoalescer(c, Options{Window: 10 * time.Millisecond, QPS: 100, Burst: 10}, zap.NewNop())
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		coalescer.Start(stop)
+		close(done)
+	}()
+	return coalescer, func() {
+		close(stop)
+		<-done
+	}
+}
+
+// waitFor polls cond until it is true, failing t after a few seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 500; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the status updates")
+}
+
+func phaseOf(t *testing.T, c client.Client) corev1.PodPhase {
+	t.Helper()
+	got := &corev1.Pod{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: "pod"}, got); err != nil {
+		t.Fatalf("Unexpected error getting the pod: %v", err)
+	}
+	return got.Status.Phase
+}
+
+func TestCoalescer(t *testing.T) {
+	c := &countingClient{Client: fake.NewFakeClient(pod(corev1.PodPending))}
+	coalescer, stop := startCoalescer(c)
+	defer stop()
+
+	// The updates within the window are written once, with the latest status.
+	for _, phase := range []corev1.PodPhase{corev1.PodRunning, corev1.PodFailed, corev1.PodSucceeded} {
+		if err := coalescer.Update(pod(phase)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	waitFor(t, func() bool { return c.count() > 0 })
+	time.Sleep(50 * time.Millisecond)
+	if c.count() != 1 {
+		t.Errorf("Unexpected number of status updates. Expected 1. Actual %d", c.count())
+	}
+	if phase := phaseOf(t, c); phase != corev1.PodSucceeded {
+		t.Errorf("Unexpected phase. Expected %v. Actual %v", corev1.PodSucceeded, phase)
+	}
+
+	// Updates to the current status are skipped.
+	if err := coalescer.Update(pod(corev1.PodSucceeded)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if c.count() != 1 {
+		t.Errorf("Unexpected number of status updates. Expected 1. Actual %d", c.count())
+	}
+}
+
+func TestCoalescerRetries(t *testing.T) {
+	c := &countingClient{Client: fake.NewFakeClient(pod(corev1.PodPending)), failures: 2}
+	coalescer, stop := startCoalescer(c)
+	defer stop()
+
+	if err := coalescer.Update(pod(corev1.PodRunning)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitFor(t, func() bool { return c.count() > 0 })
+	if phase := phaseOf(t, c); phase != corev1.PodRunning {
+		t.Errorf("Unexpected phase. Expected %v. Actual %v", corev1.PodRunning, phase)
+	}
+}
+
+func TestCoalescerDeleted(t *testing.T) {
+	c := &countingClient{Client: fake.NewFakeClient()}
+	coalescer, stop := startCoalescer(c)
+	defer stop()
+
+	if err := coalescer.Update(pod(corev1.PodRunning)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if c.count() != 0 {
+		t.Errorf("Unexpected status update of a deleted object")
+	}
+	if n := coalescer.queue.Len(); n != 0 {
+		t.Errorf("Unexpected retries of a deleted object: %d", n)
+	}
+}
+
+func TestCoalescerRejectsObjectsWithoutStatus(t *testing.T) {
+	coalescer := NewCoalescer(fake.NewFakeClient(), DefaultOptions(), zap.NewNop())
+	if err := coalescer.Update(&corev1.ConfigMap{}); err == nil {
+		t.Error("Expected an error updating the status of an object without status")
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		env     map[string]string
+		want    Options
+		wantErr bool
+	}{
+		"defaults": {
+			want: DefaultOptions(),
+		},
+		"set": {
+			env:  map[string]string{WindowEnv: "5s", QPSEnv: "2.5", BurstEnv: "7"},
+			want: Options{Window: 5 * time.Second, QPS: 2.5, Burst: 7},
+		},
+		"no window": {
+			env:  map[string]string{WindowEnv: "0s"},
+			want: Options{Window: 0, QPS: defaultQPS, Burst: defaultBurst},
+		},
+		"invalid window": {
+			env:     map[string]string{WindowEnv: "soon"},
+			wantErr: true,
+		},
+		"invalid qps": {
+			env:     map[string]string{QPSEnv: "0"},
+			wantErr: true,
+		},
+		"invalid burst": {
+			env:     map[string]string{BurstEnv: "-1"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			got, err := OptionsFromEnv()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected options (-want +got): %s", diff)
+			}
+		})
+	}
+}
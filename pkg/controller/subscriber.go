@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveSubscriberSpec resolves s, whose references are in namespace, to the URI that events are
+// delivered to.
+func ResolveSubscriberSpec(ctx context.Context, c client.Client, dc dynamic.Interface, namespace string, s v1alpha1.SubscriberSpec) (string, error) {
+	if s.DNSName != nil && *s.DNSName != "" {
+		return *s.DNSName, nil
+	}
+
+	// K8s services are special cased. They can be called, even though they do not satisfy the
+	// Callable interface.
+	if s.Ref != nil && s.Ref.APIVersion == "v1" && s.Ref.Kind == "Service" {
+		svc := &corev1.Service{}
+		svcKey := types.NamespacedName{
+			Namespace: namespace,
+			Name:      s.Ref.Name,
+		}
+		err := c.Get(ctx, svcKey, svc)
+		if err != nil {
+			return "", err
+		}
+		host := ServiceHostName(svc.Name, svc.Namespace)
+		if s.Port != nil {
+			port, err := servicePort(svc, *s.Port)
+			if err != nil {
+				return "", err
+			}
+			if port != 80 {
+				host = net.JoinHostPort(host, strconv.Itoa(int(port)))
+			}
+		}
+		return DomainAndPathToURL(host, s.Path), nil
+	}
+
+	if s.Ref == nil {
+		return "", fmt.Errorf("subscriber has neither a dnsName nor a ref")
+	}
+	hostname, err := ResolveAddressable(dc, namespace, *s.Ref)
+	if err != nil {
+		return "", err
+	}
+	return DomainAndPathToURL(hostname, s.Path), nil
+}
+
+// servicePort finds the port of svc that matches port, either by number or by name.
+func servicePort(svc *corev1.Service, port intstr.IntOrString) (int32, error) {
+	for _, p := range svc.Spec.Ports {
+		if port.Type == intstr.Int && p.Port == port.IntVal {
+			return p.Port, nil
+		}
+		if port.Type == intstr.String && p.Name == port.StrVal {
+			return p.Port, nil
+		}
+	}
+	return 0, fmt.Errorf("service %s/%s does not expose port %s", svc.Namespace, svc.Name, port.String())
+}
+
+// DomainToURL returns the HTTP URL of the root path of domain.
+func DomainToURL(domain string) string {
+	return DomainAndPathToURL(domain, "")
+}
+
+// DomainAndPathToURL is like DomainToURL, but uses path instead of the root path if it is not
+// empty.
+func DomainAndPathToURL(domain, path string) string {
+	if path == "" {
+		path = "/"
+	}
+	u := url.URL{
+		Scheme: "http",
+		Host:   domain,
+		Path:   path,
+	}
+	return u.String()
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// Addressable returns an object of kind in apiVersion named name in namespace, whose
+// status.address has hostname. With an empty hostname, the object is not addressable yet.
+func Addressable(apiVersion, kind, namespace, name, hostname string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+	}}
+	if hostname != "" {
+		obj.Object["status"] = map[string]interface{}{
+			"address": map[string]interface{}{
+				"hostname": hostname,
+			},
+		}
+	}
+	return obj
+}
+
+// Ref returns the reference to obj, as a Subscription or a source would hold it.
+func Ref(obj *unstructured.Unstructured) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+	}
+}
+
+// NewAddressableResolver returns a dynamic client serving objs, such as those made by
+// Addressable, so that controller.ResolveAddressable and controller.ResolveSubscriberSpec
+// resolve references to them without a cluster.
+func NewAddressableResolver(objs ...runtime.Object) dynamic.Interface {
+	return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/knative/eventing/pkg/controller"
+)
+
+func TestNewAddressableResolver(t *testing.T) {
+	ready := Addressable("serving.knative.dev/v1alpha1", "Service", "test-namespace", "ready", "ready.test-namespace.example.com")
+	pending := Addressable("eventing.knative.dev/v1alpha1", "Channel", "test-namespace", "pending", "")
+	dc := NewAddressableResolver(ready, pending)
+
+	hostname, err := controller.ResolveAddressable(dc, "test-namespace", Ref(ready))
+	if err != nil {
+		t.Fatalf("Unexpected error resolving an addressable object: %v", err)
+	}
+	if hostname != "ready.test-namespace.example.com" {
+		t.Errorf("Unexpected hostname. Expected %q. Actual %q", "ready.test-namespace.example.com", hostname)
+	}
+
+	if _, err := controller.ResolveAddressable(dc, "test-namespace", Ref(pending)); err == nil {
+		t.Error("Expected an error resolving an object without address")
+	}
+	if _, err := controller.ResolveAddressable(dc, "other-namespace", Ref(ready)); err == nil {
+		t.Error("Expected an error resolving an object of another namespace")
+	}
+}
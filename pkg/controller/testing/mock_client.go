@@ -136,18 +136,39 @@ func (m *MockClient) Delete(ctx context.Context, obj runtime.Object, opts ...cli
 }
 
 func (m *MockClient) Update(ctx context.Context, obj runtime.Object) error {
+	if handled, err := m.mockUpdate(ctx, obj); handled == Handled {
+		return err
+	}
+	return m.innerClient.Update(ctx, obj)
+}
+
+// mockUpdate runs the MockUpdates on an update of obj, or of its status.
+func (m *MockClient) mockUpdate(ctx context.Context, obj runtime.Object) (MockHandled, error) {
 	for i, mockUpdate := range m.mocks.MockUpdates {
 		handled, err := mockUpdate(m.innerClient, ctx, obj)
 		if handled == Handled {
 			if len(m.mocks.MockUpdates) > 1 {
 				m.mocks.MockUpdates = append(m.mocks.MockUpdates[:i], m.mocks.MockUpdates[i+1:]...)
 			}
-			return err
+			return Handled, err
 		}
 	}
-	return m.innerClient.Update(ctx, obj)
+	return Unhandled, nil
 }
 
+// Status returns a client.StatusWriter running the MockUpdates, as the updates of the status of
+// objects are mocked like the updates of whole objects.
 func (m *MockClient) Status() client.StatusWriter {
-	return m.innerClient.Status()
+	return &mockStatusWriter{m: m}
+}
+
+type mockStatusWriter struct {
+	m *MockClient
+}
+
+func (w *mockStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	if handled, err := w.m.mockUpdate(ctx, obj); handled == Handled {
+		return err
+	}
+	return w.m.innerClient.Status().Update(ctx, obj)
 }
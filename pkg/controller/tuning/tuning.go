@@ -0,0 +1,216 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tuning sets the concurrency, the requeue rate limiting and the resync period of the
+// controllers, so that operators can tune the throughput of the control plane on large clusters.
+package tuning
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Options tunes the controllers. The defaults are those of controller-runtime.
+type Options struct {
+	// Workers is how many objects each controller reconciles concurrently.
+	Workers int
+	// ControllerWorkers overrides Workers for the controllers it names.
+	ControllerWorkers map[string]int
+	// BaseDelay and MaxDelay bound the exponential backoff of requeuing an object whose
+	// reconciliation failed.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// QPS and Burst limit how many failed objects each controller requeues per second.
+	QPS   float64
+	Burst int
+	// ResyncPeriod is how often all the watched objects are reconciled again.
+	ResyncPeriod time.Duration
+}
+
+// DefaultOptions returns the default Options.
+func DefaultOptions() Options {
+	return Options{
+		Workers:           1,
+		ControllerWorkers: map[string]int{},
+		BaseDelay:         5 * time.Millisecond,
+		MaxDelay:          1000 * time.Second,
+		QPS:               10,
+		Burst:             100,
+		ResyncPeriod:      10 * time.Hour,
+	}
+}
+
+// AddFlags registers the flags setting o on fs.
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	if o.ControllerWorkers == nil {
+		o.ControllerWorkers = map[string]int{}
+	}
+	fs.IntVar(&o.Workers, "workers", o.Workers, "Number of objects each controller reconciles concurrently.")
+	fs.Var(workersFlag(o.ControllerWorkers), "controllerWorkers", "Comma separated list of controller=workers overriding --workers for the named controllers, e.g. subscription-controller=8.")
+	fs.DurationVar(&o.BaseDelay, "rateLimiterBaseDelay", o.BaseDelay, "Initial delay before requeuing an object whose reconciliation failed. The delay doubles with each failure.")
+	fs.DurationVar(&o.MaxDelay, "rateLimiterMaxDelay", o.MaxDelay, "Maximum delay before requeuing an object whose reconciliation failed.")
+	fs.Float64Var(&o.QPS, "rateLimiterQPS", o.QPS, "Number of failed objects each controller requeues per second.")
+	fs.IntVar(&o.Burst, "rateLimiterBurst", o.Burst, "Number of failed objects each controller requeues at once, above --rateLimiterQPS.")
+	fs.DurationVar(&o.ResyncPeriod, "resyncPeriod", o.ResyncPeriod, "How often all the watched objects are reconciled again.")
+}
+
+// Validate returns an error if o cannot tune the controllers.
+func (o Options) Validate() error {
+	if o.Workers <= 0 {
+		return fmt.Errorf("workers must be positive, got %d", o.Workers)
+	}
+	for name, n := range o.ControllerWorkers {
+		if n <= 0 {
+			return fmt.Errorf("workers of %q must be positive, got %d", name, n)
+		}
+	}
+	if o.BaseDelay <= 0 || o.MaxDelay < o.BaseDelay {
+		return fmt.Errorf("rate limiter delays must be positive, and the maximum at least the base, got %v and %v", o.BaseDelay, o.MaxDelay)
+	}
+	if o.QPS <= 0 || o.Burst <= 0 {
+		return fmt.Errorf("rate limiter QPS and burst must be positive, got %v and %d", o.QPS, o.Burst)
+	}
+	if o.ResyncPeriod <= 0 {
+		return fmt.Errorf("resync period must be positive, got %v", o.ResyncPeriod)
+	}
+	return nil
+}
+
+// WorkersOf returns how many objects the named controller reconciles concurrently.
+func (o Options) WorkersOf(name string) int {
+	if n, ok := o.ControllerWorkers[name]; ok {
+		return n
+	}
+	return o.Workers
+}
+
+// RateLimiter returns the rate limiter of the work queues of the controllers: the longest of an
+// exponential backoff per object and an overall token bucket, as workqueue.DefaultControllerRateLimiter.
+func (o Options) RateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(o.BaseDelay, o.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(o.QPS), o.Burst)},
+	)
+}
+
+// ManagerOptions returns opts with the resync period of o.
+func (o Options) ManagerOptions(opts manager.Options) manager.Options {
+	resync := o.ResyncPeriod
+	opts.SyncPeriod = &resync
+	return opts
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultOptions()
+)
+
+// Configure makes NewController tune the controllers it creates with o.
+func Configure(o Options) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = o
+	return nil
+}
+
+// NewController returns a new controller.Controller named name, reconciling with r, tuned by the
+// Options of Configure. It must be called before the controller watches any source.
+func NewController(name string, mgr manager.Manager, r reconcile.Reconciler) (controller.Controller, error) {
+	mu.RLock()
+	o := current
+	mu.RUnlock()
+
+	c, err := controller.New(name, mgr, controller.Options{
+		MaxConcurrentReconciles: o.WorkersOf(name),
+		Reconciler:              r,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := setQueue(c, workqueue.NewNamedRateLimitingQueue(o.RateLimiter(), name)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+var queueType = reflect.TypeOf((*workqueue.RateLimitingInterface)(nil)).Elem()
+
+// setQueue replaces the work queue of c with queue. The vendored controller-runtime always creates
+// the queue of a controller with the default rate limiter, but exports it in the Queue field of the
+// controller it returns.
+func setQueue(c controller.Controller, queue workqueue.RateLimitingInterface) error {
+	v := reflect.ValueOf(c)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	var f reflect.Value
+	if v.Kind() == reflect.Struct {
+		f = v.FieldByName("Queue")
+	}
+	if !f.IsValid() || !f.CanSet() || f.Type() != queueType {
+		return fmt.Errorf("cannot set the work queue of controller %T", c)
+	}
+	if old, ok := f.Interface().(workqueue.RateLimitingInterface); ok && old != nil {
+		old.ShutDown()
+	}
+	f.Set(reflect.ValueOf(queue))
+	return nil
+}
+
+// workersFlag is a flag.Value of comma separated controller=workers pairs.
+type workersFlag map[string]int
+
+func (f workersFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, n := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f workersFlag) Set(v string) error {
+	for _, pair := range strings.Split(v, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("expected controller=workers, got %q", pair)
+		}
+		n, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return fmt.Errorf("expected controller=workers, got %q", pair)
+		}
+		f[kv[0]] = n
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"flag"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestAddFlags(t *testing.T) {
+	o := DefaultOptions()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	o.AddFlags(fs)
+	err := fs.Parse([]string{
+		"--workers=4",
+		"--controllerWorkers=subscription-controller=16,broker-controller=2",
+		"--rateLimiterBaseDelay=10ms",
+		"--rateLimiterMaxDelay=5m",
+		"--rateLimiterQPS=50",
+		"--rateLimiterBurst=500",
+		"--resyncPeriod=1h",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the flags: %v", err)
+	}
+	want := Options{
+		Workers:           4,
+		ControllerWorkers: map[string]int{"subscription-controller": 16, "broker-controller": 2},
+		BaseDelay:         10 * time.Millisecond,
+		MaxDelay:          5 * time.Minute,
+		QPS:               50,
+		Burst:             500,
+		ResyncPeriod:      time.Hour,
+	}
+	if diff := cmp.Diff(want, o); diff != "" {
+		t.Errorf("Unexpected options (-want +got): %s", diff)
+	}
+	if err := o.Validate(); err != nil {
+		t.Errorf("Unexpected error validating the options: %v", err)
+	}
+	if n := o.WorkersOf("subscription-controller"); n != 16 {
+		t.Errorf("Unexpected workers of subscription-controller. Expected 16. Actual %d", n)
+	}
+	if n := o.WorkersOf("trigger-controller"); n != 4 {
+		t.Errorf("Unexpected workers of trigger-controller. Expected 4. Actual %d", n)
+	}
+	if got := o.ManagerOptions(manager.Options{}).SyncPeriod; got == nil || *got != time.Hour {
+		t.Errorf("Unexpected sync period. Expected %v. Actual %v", time.Hour, got)
+	}
+}
+
+func TestAddFlags_InvalidControllerWorkers(t *testing.T) {
+	for _, v := range []string{"subscription-controller", "=2", "subscription-controller=many"} {
+		o := DefaultOptions()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		o.AddFlags(fs)
+		if err := fs.Parse([]string{"--controllerWorkers=" + v}); err == nil {
+			t.Errorf("Expected an error parsing --controllerWorkers=%s", v)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]func(*Options){
+		"no workers":            func(o *Options) { o.Workers = 0 },
+		"no controller workers": func(o *Options) { o.ControllerWorkers["broker-controller"] = 0 },
+		"no base delay":         func(o *Options) { o.BaseDelay = 0 },
+		"max below base delay":  func(o *Options) { o.MaxDelay = o.BaseDelay / 2 },
+		"no qps":                func(o *Options) { o.QPS = 0 },
+		"no burst":              func(o *Options) { o.Burst = 0 },
+		"no resync":             func(o *Options) { o.ResyncPeriod = 0 },
+	}
+	if err := DefaultOptions().Validate(); err != nil {
+		t.Errorf("Unexpected error validating the default options: %v", err)
+	}
+	for n, mutate := range testCases {
+		t.Run(n, func(t *testing.T) {
+			o := DefaultOptions()
+			mutate(&o)
+			if err := o.Validate(); err == nil {
+				t.Error("Expected an error")
+			}
+			if err := Configure(o); err == nil {
+				t.Error("Expected an error configuring invalid options")
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	o := DefaultOptions()
+	o.BaseDelay = 10 * time.Millisecond
+	o.MaxDelay = 40 * time.Millisecond
+	rl := o.RateLimiter()
+	for _, want := range []time.Duration{10, 20, 40, 40} {
+		if got := rl.When("item"); got != want*time.Millisecond {
+			t.Errorf("Unexpected delay. Expected %v. Actual %v", want*time.Millisecond, got)
+		}
+	}
+}
+
+func TestNewController(t *testing.T) {
+	mgr, err := manager.New(&rest.Config{Host: "http://localhost:1"}, manager.Options{
+		MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+			return meta.NewDefaultRESTMapper(nil), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating the manager: %v", err)
+	}
+	o := DefaultOptions()
+	o.ControllerWorkers["test-controller"] = 3
+	if err := Configure(o); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer Configure(DefaultOptions())
+
+	c, err := NewController("test-controller", mgr, reconcile.Func(func(reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating the controller: %v", err)
+	}
+	v := reflect.ValueOf(c).Elem()
+	if n := v.FieldByName("MaxConcurrentReconciles").Int(); n != 3 {
+		t.Errorf("Unexpected workers. Expected 3. Actual %d", n)
+	}
+	queue := v.FieldByName("Queue").Interface().(workqueue.RateLimitingInterface)
+	if queue.ShuttingDown() {
+		t.Error("Unexpected work queue shutting down")
+	}
+}
+
+func TestSetQueue_Unsupported(t *testing.T) {
+	if err := setQueue(fakeController{}, workqueue.NewRateLimitingQueue(DefaultOptions().RateLimiter())); err == nil {
+		t.Error("Expected an error setting the work queue of a controller without a Queue field")
+	}
+}
+
+type fakeController struct {
+	controller.Controller
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The apiextensions.k8s.io/v1beta1 ConversionReview types, which are not part of the API packages
+// vendored in this repository.
+
+// ConversionReview describes a conversion request and response.
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// Request describes the attributes for the conversion request.
+	Request *ConversionRequest `json:"request,omitempty"`
+	// Response describes the attributes for the conversion response.
+	Response *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest describes the objects to convert and the version to convert them to.
+type ConversionRequest struct {
+	// UID identifies the conversion call, it is copied to the response.
+	UID types.UID `json:"uid"`
+	// DesiredAPIVersion is the version to convert the objects to, e.g. "eventing.knative.dev/v1beta1".
+	DesiredAPIVersion string `json:"desiredAPIVersion"`
+	// Objects are the objects to convert.
+	Objects []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse holds the converted objects, in the order of the request.
+type ConversionResponse struct {
+	// UID is the UID of the request.
+	UID types.UID `json:"uid"`
+	// ConvertedObjects are the converted objects.
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	// Result is Success, or Failure with the reason of the failure.
+	Result metav1.Status `json:"result"`
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/knative/eventing/pkg/admission"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+)
+
+const (
+	// path is the path the API server sends ConversionReviews to.
+	path = "/convert"
+)
+
+var (
+	// CRDs are the CustomResourceDefinitions whose versions are converted by the Webhook.
+	CRDs = []string{
+		"channels.eventing.knative.dev",
+		"subscriptions.eventing.knative.dev",
+	}
+
+	crdResource = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1beta1",
+		Resource: "customresourcedefinitions",
+	}
+)
+
+// Options configures the Webhook.
+type Options struct {
+	// ServiceName is the name of the Service, in Namespace, that routes to the Webhook.
+	ServiceName string
+
+	// Namespace is the namespace of the Service.
+	Namespace string
+
+	// Port is the port the Webhook listens on.
+	Port int
+}
+
+// Webhook converts Channels and Subscriptions between v1alpha1, the version they are stored as,
+// and v1beta1, so that both versions can be used while clients migrate to v1beta1.
+type Webhook struct {
+	// Client patches the CustomResourceDefinitions.
+	Client  dynamic.Interface
+	Options Options
+	Logger  *zap.Logger
+}
+
+// Run configures the CustomResourceDefinitions to use the Webhook and serves conversion requests
+// until stop is closed. The certificates of the Webhook are generated each time it starts.
+func (wh *Webhook) Run(stop <-chan struct{}) error {
+	opts := admission.Options{
+		ServiceName: wh.Options.ServiceName,
+		Namespace:   wh.Options.Namespace,
+		Port:        wh.Options.Port,
+	}
+	return admission.Serve(wh.Logger, opts, wh.register, wh, stop)
+}
+
+// register sets the conversion strategy of the CustomResourceDefinitions to the Webhook, trusting
+// caCert.
+func (wh *Webhook) register(caCert []byte) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			// Webhook conversion requires the unknown fields to be pruned, the CRDs declare the
+			// fields that are kept.
+			"preserveUnknownFields": false,
+			"conversion": map[string]interface{}{
+				"strategy": "Webhook",
+				"webhookClientConfig": map[string]interface{}{
+					"service": map[string]interface{}{
+						"namespace": wh.Options.Namespace,
+						"name":      wh.Options.ServiceName,
+						"path":      path,
+					},
+					"caBundle": caCert,
+				},
+				"conversionReviewVersions": []string{"v1beta1"},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, crd := range CRDs {
+		if _, err := wh.Client.Resource(crdResource).Patch(crd, types.MergePatchType, patch); err != nil {
+			return fmt.Errorf("unable to configure the conversion of %s: %v", crd, err)
+		}
+	}
+	return nil
+}
+
+// ServeHTTP implements the conversion webhook.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid Content-Type, want `application/json`", http.StatusUnsupportedMediaType)
+		return
+	}
+	var review ConversionReview
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("could not decode body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "the review has no request", http.StatusBadRequest)
+		return
+	}
+
+	response := ConversionReview{
+		TypeMeta: review.TypeMeta,
+		Response: wh.convertAll(review.Request),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// convertAll converts the objects of request. It fails if any of them cannot be converted.
+func (wh *Webhook) convertAll(request *ConversionRequest) *ConversionResponse {
+	response := &ConversionResponse{
+		UID:    request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, obj := range request.Objects {
+		converted, err := convert(obj.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			wh.Logger.Error("Failed to convert an object", zap.String("desiredAPIVersion", request.DesiredAPIVersion), zap.Error(err))
+			return &ConversionResponse{
+				UID: request.UID,
+				Result: metav1.Status{
+					Status:  metav1.StatusFailure,
+					Message: err.Error(),
+				},
+			}
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	return response
+}
+
+// convert converts the JSON object raw to apiVersion.
+func convert(raw []byte, apiVersion string) ([]byte, error) {
+	var tm metav1.TypeMeta
+	if err := json.Unmarshal(raw, &tm); err != nil {
+		return nil, err
+	}
+	if tm.APIVersion == apiVersion {
+		return raw, nil
+	}
+
+	var converted runtime.Object
+	var err error
+	switch tm.Kind {
+	case "Channel":
+		converted, err = convertChannel(raw, tm.APIVersion, apiVersion)
+	case "Subscription":
+		converted, err = convertSubscription(raw, tm.APIVersion, apiVersion)
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", tm.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	converted.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, tm.Kind))
+	return json.Marshal(converted)
+}
+
+func convertChannel(raw []byte, from, to string) (runtime.Object, error) {
+	switch {
+	case from == v1alpha1.SchemeGroupVersion.String() && to == v1beta1.SchemeGroupVersion.String():
+		in, out := &v1alpha1.Channel{}, &v1beta1.Channel{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return out, out.ConvertFrom(in)
+	case from == v1beta1.SchemeGroupVersion.String() && to == v1alpha1.SchemeGroupVersion.String():
+		in, out := &v1beta1.Channel{}, &v1alpha1.Channel{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return out, in.ConvertTo(out)
+	default:
+		return nil, fmt.Errorf("unsupported conversion of Channel from %q to %q", from, to)
+	}
+}
+
+func convertSubscription(raw []byte, from, to string) (runtime.Object, error) {
+	switch {
+	case from == v1alpha1.SchemeGroupVersion.String() && to == v1beta1.SchemeGroupVersion.String():
+		in, out := &v1alpha1.Subscription{}, &v1beta1.Subscription{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return out, out.ConvertFrom(in)
+	case from == v1beta1.SchemeGroupVersion.String() && to == v1alpha1.SchemeGroupVersion.String():
+		in, out := &v1beta1.Subscription{}, &v1alpha1.Subscription{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		return out, in.ConvertTo(out)
+	default:
+		return nil, fmt.Errorf("unsupported conversion of Subscription from %q to %q", from, to)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	duckv1alpha1 "github.com/knative/pkg/apis/duck/v1alpha1"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+)
+
+func TestServeHTTP(t *testing.T) {
+	alpha := &v1alpha1.Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "eventing.knative.dev/v1alpha1",
+			Kind:       "Channel",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo",
+		},
+		Spec: v1alpha1.ChannelSpec{
+			Provisioner: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "ClusterChannelProvisioner",
+				Name:       "in-memory-channel",
+			},
+		},
+		Status: v1alpha1.ChannelStatus{
+			Address: duckv1alpha1.Addressable{
+				Hostname: "foo-channel.default.svc.cluster.local",
+			},
+		},
+	}
+	beta := &v1beta1.Channel{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "eventing.knative.dev/v1beta1",
+			Kind:       "Channel",
+		},
+		ObjectMeta: alpha.ObjectMeta,
+		Spec: v1beta1.ChannelSpec{
+			Provisioner: alpha.Spec.Provisioner,
+		},
+		Status: v1beta1.ChannelStatus{
+			Address: v1beta1.Addressable{
+				URL: "http://foo-channel.default.svc.cluster.local",
+			},
+		},
+	}
+	subscription := &v1alpha1.Subscription{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "eventing.knative.dev/v1alpha1",
+			Kind:       "Subscription",
+		},
+		Spec: v1alpha1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{Name: "foo"},
+		},
+	}
+
+	testCases := map[string]struct {
+		objects    []runtime.Object
+		apiVersion string
+		want       []runtime.Object
+		wantErr    bool
+	}{
+		"v1alpha1 to v1beta1": {
+			objects:    []runtime.Object{alpha},
+			apiVersion: "eventing.knative.dev/v1beta1",
+			want:       []runtime.Object{beta},
+		},
+		"v1beta1 to v1alpha1": {
+			objects:    []runtime.Object{beta},
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			want:       []runtime.Object{alpha},
+		},
+		"same version": {
+			objects:    []runtime.Object{alpha, subscription},
+			apiVersion: "eventing.knative.dev/v1alpha1",
+			want:       []runtime.Object{alpha, subscription},
+		},
+		"unsupported version": {
+			objects:    []runtime.Object{alpha},
+			apiVersion: "eventing.knative.dev/v2",
+			wantErr:    true,
+		},
+		"unsupported kind": {
+			objects:    []runtime.Object{&v1alpha1.Broker{TypeMeta: metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Broker"}}},
+			apiVersion: "eventing.knative.dev/v1beta1",
+			wantErr:    true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			request := &ConversionRequest{
+				UID:               "some-uid",
+				DesiredAPIVersion: tc.apiVersion,
+			}
+			for _, obj := range tc.objects {
+				request.Objects = append(request.Objects, runtime.RawExtension{Raw: marshal(t, obj)})
+			}
+			body := marshal(t, &ConversionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "ConversionReview"},
+				Request:  request,
+			})
+
+			wh := &Webhook{Logger: zap.NewNop()}
+			r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			wh.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Unexpected status code %d: %s", w.Code, w.Body.String())
+			}
+
+			var review ConversionReview
+			if err := json.Unmarshal(w.Body.Bytes(), &review); err != nil {
+				t.Fatalf("Unable to decode the response: %v", err)
+			}
+			if review.Kind != "ConversionReview" || review.Response == nil {
+				t.Fatalf("Unexpected review: %+v", review)
+			}
+			if review.Response.UID != request.UID {
+				t.Errorf("Unexpected UID. Expected %q, got %q", request.UID, review.Response.UID)
+			}
+			if tc.wantErr {
+				if review.Response.Result.Status != metav1.StatusFailure {
+					t.Errorf("Expected a failure, got %+v", review.Response.Result)
+				}
+				return
+			}
+			if review.Response.Result.Status != metav1.StatusSuccess {
+				t.Fatalf("Expected a success, got %+v", review.Response.Result)
+			}
+			var want []json.RawMessage
+			for _, obj := range tc.want {
+				want = append(want, marshal(t, obj))
+			}
+			var got []json.RawMessage
+			for _, obj := range review.Response.ConvertedObjects {
+				got = append(got, obj.Raw)
+			}
+			if diff := cmp.Diff(toMaps(t, want), toMaps(t, got)); diff != "" {
+				t.Errorf("Unexpected converted objects (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTPInvalidRequest(t *testing.T) {
+	wh := &Webhook{Logger: zap.NewNop()}
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(`{}`)))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	wh.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Unexpected status code. Expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	patched := map[string]map[string]interface{}{}
+	client.PrependReactor("patch", "customresourcedefinitions", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clientgotesting.PatchAction)
+		var p map[string]interface{}
+		if err := json.Unmarshal(patch.GetPatch(), &p); err != nil {
+			t.Fatalf("Unable to decode the patch: %v", err)
+		}
+		patched[patch.GetName()] = p
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	wh := &Webhook{
+		Client: client,
+		Options: Options{
+			ServiceName: "conversion-webhook",
+			Namespace:   "knative-eventing",
+		},
+		Logger: zap.NewNop(),
+	}
+	if err := wh.register([]byte("ca")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"preserveUnknownFields": false,
+			"conversion": map[string]interface{}{
+				"strategy": "Webhook",
+				"webhookClientConfig": map[string]interface{}{
+					"service": map[string]interface{}{
+						"namespace": "knative-eventing",
+						"name":      "conversion-webhook",
+						"path":      "/convert",
+					},
+					"caBundle": "Y2E=",
+				},
+				"conversionReviewVersions": []interface{}{"v1beta1"},
+			},
+		},
+	}
+	for _, crd := range CRDs {
+		if diff := cmp.Diff(want, patched[crd]); diff != "" {
+			t.Errorf("Unexpected patch of %s (-want +got): %s", crd, diff)
+		}
+	}
+}
+
+func marshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Unable to marshal %v: %v", v, err)
+	}
+	return b
+}
+
+// toMaps decodes JSON objects, so that they are compared regardless of their formatting.
+func toMaps(t *testing.T, objects []json.RawMessage) []map[string]interface{} {
+	t.Helper()
+	var maps []map[string]interface{}
+	for _, obj := range objects {
+		var m map[string]interface{}
+		if err := json.Unmarshal(obj, &m); err != nil {
+			t.Fatalf("Unable to decode %s: %v", obj, err)
+		}
+		maps = append(maps, m)
+	}
+	return maps
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cron parses cron schedules and computes when they fire.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the times at which a cron schedule fires.
+type Schedule interface {
+	// Next returns the first time after t at which the schedule fires, or the zero time if it
+	// never fires.
+	Next(t time.Time) time.Time
+}
+
+// descriptors maps the predefined schedules to their standard form.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+const everyPrefix = "@every "
+
+type bounds struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minutes = bounds{name: "minute", min: 0, max: 59}
+	hours   = bounds{name: "hour", min: 0, max: 23}
+	doms    = bounds{name: "day of month", min: 1, max: 31}
+	months  = bounds{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Both 0 and 7 are Sunday.
+	dows = bounds{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Parse parses spec, which is either five space-separated fields (minute, hour, day of month,
+// month and day of week), one of the descriptors such as '@hourly', or '@every <duration>'.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, everyPrefix) {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, everyPrefix)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in %q: %v", spec, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("the duration in %q must be at least one second", spec)
+		}
+		return &everySchedule{every: d}, nil
+	}
+	if strings.HasPrefix(spec, "@") {
+		std, ok := descriptors[spec]
+		if !ok {
+			return nil, fmt.Errorf("unknown descriptor %q", spec)
+		}
+		spec = std
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d in %q", len(fields), spec)
+	}
+	s := &fieldsSchedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], doms); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dows); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domRestricted = !startsWithStar(fields[2])
+	s.dowRestricted = !startsWithStar(fields[4])
+	return s, nil
+}
+
+// startsWithStar returns true if a part of field starts with '*', such as '*' or '*/2'. As in
+// robfig/cron, such a day field does not restrict the days matched by the other day field.
+func startsWithStar(field string) bool {
+	for _, part := range strings.Split(field, ",") {
+		if strings.HasPrefix(part, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into a set of bits.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		r, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || n == 0 {
+				return 0, fmt.Errorf("invalid step in %s %q", b.name, part)
+			}
+			r, step = part[:i], uint(n)
+		}
+		var start, end uint
+		switch {
+		case r == "*":
+			start, end = b.min, b.max
+		case strings.Contains(r, "-"):
+			i := strings.Index(r, "-")
+			var err error
+			if start, err = parseValue(r[:i], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(r[i+1:], b); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range in %s %q", b.name, part)
+			}
+		default:
+			var err error
+			if start, err = parseValue(r, b); err != nil {
+				return 0, err
+			}
+			end = start
+			if step > 1 {
+				// 'a/n' means every n starting at a.
+				end = b.max
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (uint, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || uint(n) < b.min || uint(n) > b.max {
+		return 0, fmt.Errorf("invalid %s %q, must be between %d and %d", b.name, s, b.min, b.max)
+	}
+	return uint(n), nil
+}
+
+// fieldsSchedule is a schedule made of the five standard fields. Each field is a set of bits, one
+// for each value that it matches.
+type fieldsSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// As in the standard cron, when both the day of month and the day of week are restricted, a
+	// day matches if either of them does. A day field starting with '*' is not restricted.
+	domRestricted, dowRestricted bool
+}
+
+// maxYears bounds the search for the next time, so that schedules that never fire, such as
+// February 30th, terminate.
+const maxYears = 5
+
+func (s *fieldsSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + maxYears
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *fieldsSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// everySchedule fires at a fixed interval.
+type everySchedule struct {
+	every time.Duration
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.every - time.Duration(t.Nanosecond()))
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@sometimes",
+		"@every soon",
+		"@every 1ms",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Expected an error parsing %q", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// A Monday.
+	from := time.Date(2019, time.January, 7, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{{
+		spec: "* * * * *",
+		want: time.Date(2019, time.January, 7, 10, 31, 0, 0, time.UTC),
+	}, {
+		spec: "*/15 * * * *",
+		want: time.Date(2019, time.January, 7, 10, 45, 0, 0, time.UTC),
+	}, {
+		spec: "5/20 * * * *",
+		want: time.Date(2019, time.January, 7, 10, 45, 0, 0, time.UTC),
+	}, {
+		spec: "0 9-17 * * *",
+		want: time.Date(2019, time.January, 7, 11, 0, 0, 0, time.UTC),
+	}, {
+		spec: "0,30 8 * * *",
+		want: time.Date(2019, time.January, 8, 8, 0, 0, 0, time.UTC),
+	}, {
+		spec: "0 0 * * fri",
+		want: time.Date(2019, time.January, 11, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec: "0 0 * * 7",
+		want: time.Date(2019, time.January, 13, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec: "0 0 1 mar *",
+		want: time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}, {
+		// Either the 15th or a Wednesday.
+		spec: "0 0 15 * wed",
+		want: time.Date(2019, time.January, 9, 0, 0, 0, 0, time.UTC),
+	}, {
+		// A Monday on an odd day, as a day of month starting with '*' does not restrict.
+		spec: "0 0 */2 * 1",
+		want: time.Date(2019, time.January, 21, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec: "0 0 29 2 *",
+		want: time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec: "@hourly",
+		want: time.Date(2019, time.January, 7, 11, 0, 0, 0, time.UTC),
+	}, {
+		spec: "@every 90s",
+		want: time.Date(2019, time.January, 7, 10, 31, 45, 0, time.UTC),
+	}, {
+		// Never fires.
+		spec: "0 0 30 2 *",
+		want: time.Time{},
+	}}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			s, err := Parse(test.spec)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing: %v", err)
+			}
+			if got := s.Next(from); !got.Equal(test.want) {
+				t.Errorf("Unexpected next time. Expected: %v. Actual: %v", test.want, got)
+			}
+		})
+	}
+}
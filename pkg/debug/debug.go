@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debug serves the runtime debug endpoints of the controllers and dispatchers: the pprof
+// profiles, the expvar variables and the stacks of all the goroutines. They are only served on the
+// port they are explicitly enabled on, so that performance issues can be diagnosed in production
+// without rebuilding the images.
+package debug
+
+import (
+	"context"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	runtimepprof "runtime/pprof"
+)
+
+const (
+	// PortEnv is the environment variable holding the port the debug endpoints are served on. They
+	// are not served if it is not set.
+	PortEnv = "DEBUG_PORT"
+
+	// HostEnv is the environment variable holding the host the debug endpoints are bound to. They
+	// are bound to localhost if it is not set, so that they are only reachable from within the pod,
+	// e.g. with kubectl port-forward.
+	HostEnv = "DEBUG_HOST"
+)
+
+// Addr returns the address set by PortEnv and HostEnv to serve the debug endpoints on, or "" if
+// they are not served.
+func Addr() string {
+	port := os.Getenv(PortEnv)
+	if port == "" {
+		return ""
+	}
+	host := os.Getenv(HostEnv)
+	if host == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Handler returns the handler serving the debug endpoints:
+//   /debug/pprof/           the index of the pprof profiles, e.g. /debug/pprof/heap
+//   /debug/pprof/profile    a CPU profile, for ?seconds=30 by default
+//   /debug/pprof/trace      an execution trace, for ?seconds=1 by default
+//   /debug/vars             the expvar variables, including the memory statistics
+//   /debug/goroutines       the stacks of all the goroutines, as in a crash
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+	})
+	return mux
+}
+
+// Serve serves Handler on addr until stopCh is closed.
+func Serve(addr string, stopCh <-chan struct{}) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: Handler(),
+	}
+	go func() {
+		<-stopCh
+		srv.Shutdown(context.Background())
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
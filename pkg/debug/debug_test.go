@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	testCases := map[string]struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		"pprof index": {
+			path:         "/debug/pprof/",
+			expectedCode: http.StatusOK,
+			expectedBody: "goroutine",
+		},
+		"heap profile": {
+			path:         "/debug/pprof/heap?debug=1",
+			expectedCode: http.StatusOK,
+			expectedBody: "heap profile",
+		},
+		"expvar": {
+			path:         "/debug/vars",
+			expectedCode: http.StatusOK,
+			expectedBody: `"memstats"`,
+		},
+		"goroutines": {
+			path:         "/debug/goroutines",
+			expectedCode: http.StatusOK,
+			expectedBody: "TestHandler",
+		},
+		"unknown": {
+			path:         "/metrics",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+	h := Handler()
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != tc.expectedCode {
+				t.Fatalf("Unexpected status code. Expected %v. Actual %v", tc.expectedCode, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tc.expectedBody) {
+				t.Errorf("Expected the body to contain %q. Actual %q", tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAddr(t *testing.T) {
+	testCases := map[string]struct {
+		port string
+		host string
+		want string
+	}{
+		"not served": {},
+		"localhost by default": {
+			port: "8008",
+			want: "localhost:8008",
+		},
+		"host": {
+			port: "8008",
+			host: "0.0.0.0",
+			want: "0.0.0.0:8008",
+		},
+		"host without port": {
+			host: "0.0.0.0",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(PortEnv, tc.port)
+			os.Setenv(HostEnv, tc.host)
+			defer os.Unsetenv(PortEnv)
+			defer os.Unsetenv(HostEnv)
+			if got := Addr(); got != tc.want {
+				t.Errorf("Unexpected address. Expected %q, actual %q", tc.want, got)
+			}
+		})
+	}
+}
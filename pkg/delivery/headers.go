@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package delivery defines the HTTP headers of the deliveries of events to subscribers, and of
+// their responses, for the authors of sinks. It has no dependencies, so that sinks can import it
+// alone.
+package delivery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// IdempotencyKeyHeader is the header of every delivery to a subscriber holding a key that is
+	// the same for every attempt to deliver an event to a subscription, and differs for other
+	// events and subscriptions. Subscribers deduplicate retried deliveries by remembering the keys
+	// of the events they processed. It is not set for the events without an id.
+	IdempotencyKeyHeader = "Knative-Idempotency-Key"
+
+	// AttemptHeader is the header of every delivery to a subscriber holding the number of the
+	// attempt to deliver the event to the subscription, counting from 1. Subscribers may process
+	// the first attempts, which are not retries, without looking their idempotency key up.
+	AttemptHeader = "Knative-Delivery-Attempt"
+
+	// ResultHeader is the header of the response by which a subscriber explicitly tells the
+	// dispatcher what to do with the event, whatever the status code of the response.
+	ResultHeader = "Knative-Delivery-Result"
+	// ResultNack asks for the event to be redelivered later. The delivery fails, even with a 2xx
+	// status code, and is not routed to an error destination.
+	ResultNack = "nack"
+	// ResultDrop asks for the event to be dropped. The delivery succeeds, even with a non-2xx
+	// status code, so the event is neither retried nor sent to a dead letter, and the response is
+	// not forwarded as a reply.
+	ResultDrop = "drop"
+)
+
+// IdempotencyKey returns the idempotency key of the deliveries of the event with the given source
+// and id to subscription, the namespace/name of the Subscription or Trigger, or "" if the event
+// has no id.
+func IdempotencyKey(subscription, source, id string) string {
+	if id == "" {
+		return ""
+	}
+	h := sha256.Sum256([]byte(subscription + "\n" + source + "\n" + id))
+	return hex.EncodeToString(h[:16])
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import "testing"
+
+func TestIdempotencyKey(t *testing.T) {
+	key := IdempotencyKey("ns/sub", "/orders", "1234")
+	if len(key) != 32 {
+		t.Errorf("Unexpected key length %d: %q", len(key), key)
+	}
+	if got := IdempotencyKey("ns/sub", "/orders", "1234"); got != key {
+		t.Errorf("Unstable key. Expected %q, actual %q", key, got)
+	}
+	for _, other := range [][3]string{
+		{"ns/other", "/orders", "1234"},
+		{"ns/sub", "/invoices", "1234"},
+		{"ns/sub", "/orders", "5678"},
+		// The parts are separated, so that they cannot be shifted between each other.
+		{"ns/sub\n/orders", "", "1234"},
+	} {
+		if got := IdempotencyKey(other[0], other[1], other[2]); got == key {
+			t.Errorf("Same key for %q", other)
+		}
+	}
+	if got := IdempotencyKey("ns/sub", "/orders", ""); got != "" {
+		t.Errorf("Unexpected key for an event without id: %q", got)
+	}
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventstore archives the events of Channels to object storage, so that they can be
+// replayed or analyzed offline. Events are written in batches, as CloudEvents in the JSON format,
+// one per line, under keys partitioned Hive-style by channel, date, type and source:
+//
+//	<prefix>/namespace=<ns>/channel=<name>/date=<yyyy-mm-dd>/type=<type>/source=<source>/<hhmmss>-<uuid>.json
+//
+// which query engines such as Athena, BigQuery or Spark read as a partitioned table.
+package eventstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/claimcheck"
+)
+
+// The environment variables configuring the event store.
+const (
+	// EndpointEnv is the URL of the S3 API, e.g. https://s3.us-east-1.amazonaws.com,
+	// http://minio.minio:9000 or https://storage.googleapis.com.
+	EndpointEnv = "EVENT_STORE_ENDPOINT"
+	// BucketEnv is the bucket the events are stored in.
+	BucketEnv = "EVENT_STORE_BUCKET"
+	// RegionEnv is the region of the bucket. Defaults to us-east-1.
+	RegionEnv = "EVENT_STORE_REGION"
+	// AccessKeyIDEnv and SecretAccessKeyEnv are the credentials used to sign requests.
+	AccessKeyIDEnv     = "EVENT_STORE_ACCESS_KEY_ID"
+	SecretAccessKeyEnv = "EVENT_STORE_SECRET_ACCESS_KEY"
+	// PrefixEnv is the prefix of the keys of the events. Defaults to events.
+	PrefixEnv = "EVENT_STORE_PREFIX"
+	// FlushIntervalEnv is how long events are batched before they are written. Defaults to 1s.
+	FlushIntervalEnv = "EVENT_STORE_FLUSH_INTERVAL"
+	// MaxBatchEnv is the number of events written at once at most. Defaults to 1000.
+	MaxBatchEnv = "EVENT_STORE_MAX_BATCH"
+	// RetentionDaysEnv is the number of days the events are kept, after the day they were
+	// archived. Defaults to 0, which keeps them forever.
+	RetentionDaysEnv = "EVENT_STORE_RETENTION_DAYS"
+
+	defaultRegion        = "us-east-1"
+	defaultPrefix        = "events"
+	defaultFlushInterval = time.Second
+	defaultMaxBatch      = 1000
+
+	// defaultPartition is the value of the partitions of the events missing their attribute, as
+	// named by Hive.
+	defaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+	dateLayout = "2006-01-02"
+)
+
+// ObjectStore stores the batches of archived events.
+type ObjectStore interface {
+	// Put stores data under key.
+	Put(key string, data []byte) error
+	// List returns the keys starting with prefix.
+	List(prefix string) ([]string, error)
+	// Delete deletes the object key.
+	Delete(key string) error
+}
+
+var _ ObjectStore = (*claimcheck.S3Store)(nil)
+
+// Config is the configuration of the event store.
+type Config struct {
+	Store         ObjectStore
+	Prefix        string
+	FlushInterval time.Duration
+	MaxBatch      int
+	RetentionDays int
+}
+
+// FromEnv returns the event store configured by the environment variables of the process.
+func FromEnv() (*Config, error) {
+	for _, env := range []string{EndpointEnv, BucketEnv, AccessKeyIDEnv, SecretAccessKeyEnv} {
+		if os.Getenv(env) == "" {
+			return nil, fmt.Errorf("%s must be set", env)
+		}
+	}
+	region := os.Getenv(RegionEnv)
+	if region == "" {
+		region = defaultRegion
+	}
+	store, err := claimcheck.NewS3Store(os.Getenv(EndpointEnv), os.Getenv(BucketEnv), region, os.Getenv(AccessKeyIDEnv), os.Getenv(SecretAccessKeyEnv))
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{
+		Store:         store,
+		Prefix:        strings.Trim(os.Getenv(PrefixEnv), "/"),
+		FlushInterval: defaultFlushInterval,
+		MaxBatch:      defaultMaxBatch,
+	}
+	if c.Prefix == "" {
+		c.Prefix = defaultPrefix
+	}
+	if v := os.Getenv(FlushIntervalEnv); v != "" {
+		if c.FlushInterval, err = time.ParseDuration(v); err != nil || c.FlushInterval <= 0 {
+			return nil, fmt.Errorf("invalid %s %q, it must be a positive duration", FlushIntervalEnv, v)
+		}
+	}
+	if v := os.Getenv(MaxBatchEnv); v != "" {
+		if c.MaxBatch, err = strconv.Atoi(v); err != nil || c.MaxBatch <= 0 {
+			return nil, fmt.Errorf("invalid %s %q, it must be a positive number", MaxBatchEnv, v)
+		}
+	}
+	if v := os.Getenv(RetentionDaysEnv); v != "" {
+		if c.RetentionDays, err = strconv.Atoi(v); err != nil || c.RetentionDays < 0 {
+			return nil, fmt.Errorf("invalid %s %q, it must be a number of days", RetentionDaysEnv, v)
+		}
+	}
+	return c, nil
+}
+
+// InvalidEventError is returned by Archive for the messages that cannot be archived.
+type InvalidEventError struct {
+	Err error
+}
+
+func (e *InvalidEventError) Error() string {
+	return fmt.Sprintf("invalid event: %v", e.Err)
+}
+
+// Archiver writes events to an ObjectStore. The events archived concurrently are written
+// together, one object per partition, once FlushInterval elapsed or MaxBatch events are waiting.
+type Archiver struct {
+	config Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	mutex sync.Mutex
+	// pending is the batch the next events are added to, if any.
+	pending *batch
+}
+
+// batch is a set of events written at once.
+type batch struct {
+	// events are the lines of the objects of the batch, by partition.
+	events map[string][][]byte
+	size   int
+	// errs are the errors writing the objects of the batch, by partition. They are set before
+	// done is closed.
+	errs map[string]error
+	done chan struct{}
+}
+
+// NewArchiver creates an Archiver writing events as configured by config.
+func NewArchiver(config Config, logger *zap.Logger) *Archiver {
+	return &Archiver{
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Archive writes message, received on channel, to the store. It returns once the batch it was
+// added to is written, so that the event is acknowledged only once it is stored.
+func (a *Archiver) Archive(channel provisioners.ChannelReference, message *provisioners.Message) error {
+	event, err := encode(message)
+	if err != nil {
+		return &InvalidEventError{Err: err}
+	}
+	attrs := message.Attributes()
+	partition := a.partition(channel, attrs["type"], attrs["source"])
+
+	a.mutex.Lock()
+	b := a.pending
+	if b == nil {
+		b = &batch{
+			events: make(map[string][][]byte),
+			errs:   make(map[string]error),
+			done:   make(chan struct{}),
+		}
+		a.pending = b
+		time.AfterFunc(a.config.FlushInterval, func() {
+			a.flushPending(b)
+		})
+	}
+	b.events[partition] = append(b.events[partition], event)
+	b.size++
+	full := b.size >= a.config.MaxBatch
+	if full {
+		a.pending = nil
+	}
+	a.mutex.Unlock()
+
+	if full {
+		a.flush(b)
+	}
+	<-b.done
+	return b.errs[partition]
+}
+
+// flushPending writes b if it is still pending.
+func (a *Archiver) flushPending(b *batch) {
+	a.mutex.Lock()
+	if a.pending != b {
+		a.mutex.Unlock()
+		return
+	}
+	a.pending = nil
+	a.mutex.Unlock()
+	a.flush(b)
+}
+
+// flush writes the events of b, one object per partition.
+func (a *Archiver) flush(b *batch) {
+	now := a.now().UTC()
+	for partition, events := range b.events {
+		key := fmt.Sprintf("%s/%s-%s.json", partition, now.Format("150405"), uuid.New().String())
+		data := append(bytes.Join(events, []byte("\n")), '\n')
+		if err := a.config.Store.Put(key, data); err != nil {
+			a.logger.Error("Unable to archive the events", zap.Error(err), zap.String("key", key), zap.Int("events", len(events)))
+			b.errs[partition] = err
+		}
+	}
+	close(b.done)
+}
+
+// partition returns the prefix of the keys of the events of type and source archived now on
+// channel.
+func (a *Archiver) partition(channel provisioners.ChannelReference, eventType, source string) string {
+	return strings.Join([]string{
+		a.config.Prefix,
+		"namespace=" + partitionValue(channel.Namespace),
+		"channel=" + partitionValue(channel.Name),
+		"date=" + a.now().UTC().Format(dateLayout),
+		"type=" + partitionValue(eventType),
+		"source=" + partitionValue(source),
+	}, "/")
+}
+
+// partitionValue escapes v for a key segment, in which / and = are reserved.
+func partitionValue(v string) string {
+	if v == "" {
+		return defaultPartition
+	}
+	return strings.Replace(url.QueryEscape(v), "+", "%20", -1)
+}
+
+// encode returns message as a CloudEvent in the JSON format, on a single line. Messages in the
+// binary content mode are converted, with their data kept as JSON when it is, as a string when it
+// is text, and in data_base64 otherwise.
+func encode(message *provisioners.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if isStructured(message) {
+		if err := json.Compact(&buf, message.Payload); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	attrs := message.Attributes()
+	event := make(map[string]interface{}, len(attrs)+1)
+	for k, v := range attrs {
+		event[k] = v
+	}
+	if len(message.Payload) > 0 {
+		switch {
+		case isJSON(attrs["datacontenttype"]) && json.Valid(message.Payload):
+			if err := json.Compact(&buf, message.Payload); err != nil {
+				return nil, err
+			}
+			event["data"] = json.RawMessage(buf.Bytes())
+		case utf8.Valid(message.Payload):
+			event["data"] = string(message.Payload)
+		default:
+			event["data_base64"] = base64.StdEncoding.EncodeToString(message.Payload)
+		}
+	}
+	return json.Marshal(event)
+}
+
+// isStructured returns true if message is in the structured content mode.
+func isStructured(message *provisioners.Message) bool {
+	for k, v := range message.Headers {
+		if strings.ToLower(k) == "content-type" {
+			return strings.HasPrefix(strings.ToLower(v), "application/cloudevents+json")
+		}
+	}
+	return false
+}
+
+// isJSON returns true if contentType is JSON, or has no media type, which defaults to JSON.
+func isJSON(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "" || mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Expire deletes the events archived more than RetentionDays days before today, and returns the
+// number of objects deleted. It does nothing if RetentionDays is 0.
+func (a *Archiver) Expire() (int, error) {
+	if a.config.RetentionDays == 0 {
+		return 0, nil
+	}
+	keys, err := a.config.Store.List(a.config.Prefix + "/")
+	if err != nil {
+		return 0, err
+	}
+	oldest := a.now().UTC().AddDate(0, 0, -a.config.RetentionDays).Format(dateLayout)
+	deleted := 0
+	for _, key := range keys {
+		date := keyDate(key)
+		// Dates in this layout sort chronologically.
+		if date == "" || date >= oldest {
+			continue
+		}
+		if err := a.config.Store.Delete(key); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// RunRetention expires the events every interval, until stopCh is closed.
+func (a *Archiver) RunRetention(interval time.Duration, stopCh <-chan struct{}) {
+	if a.config.RetentionDays == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := a.Expire()
+		if err != nil {
+			a.logger.Error("Unable to expire the archived events", zap.Error(err), zap.Int("deleted", deleted))
+		} else if deleted > 0 {
+			a.logger.Info("Expired archived events", zap.Int("deleted", deleted), zap.Int("retentionDays", a.config.RetentionDays))
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// keyDate returns the date partition of key, or the empty string if it has none.
+func keyDate(key string) string {
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, "date=") {
+			date := strings.TrimPrefix(segment, "date=")
+			if _, err := time.Parse(dateLayout, date); err != nil {
+				return ""
+			}
+			return date
+		}
+	}
+	return ""
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventstore
+
+import (
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// fakeStore keeps objects in memory.
+type fakeStore struct {
+	lock    sync.Mutex
+	objects map[string]string
+	err     error
+}
+
+func newFakeStore(keys ...string) *fakeStore {
+	s := &fakeStore{objects: make(map[string]string)}
+	for _, key := range keys {
+		s.objects[key] = ""
+	}
+	return s
+}
+
+func (s *fakeStore) Put(key string, data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.objects[key] = string(data)
+	return nil
+}
+
+func (s *fakeStore) List(prefix string) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, s.err
+}
+
+func (s *fakeStore) Delete(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.objects, key)
+	return s.err
+}
+
+// partitions returns the objects of the store by partition, ignoring their generated names.
+func (s *fakeStore) partitions() map[string][]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	partitions := make(map[string][]string)
+	for key, data := range s.objects {
+		lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+		partitions[path.Dir(key)] = append(partitions[path.Dir(key)], lines...)
+	}
+	for _, lines := range partitions {
+		sort.Strings(lines)
+	}
+	return partitions
+}
+
+var testChannel = provisioners.ChannelReference{Namespace: "test-namespace", Name: "test-channel"}
+
+func testArchiver(store ObjectStore, maxBatch int) *Archiver {
+	a := NewArchiver(Config{
+		Store:         store,
+		Prefix:        "events",
+		FlushInterval: 10 * time.Millisecond,
+		MaxBatch:      maxBatch,
+		RetentionDays: 7,
+	}, zap.NewNop())
+	a.now = func() time.Time {
+		return time.Date(2019, 1, 10, 3, 4, 5, 0, time.UTC)
+	}
+	return a
+}
+
+func binaryEvent(id, eventType, source, contentType, data string) *provisioners.Message {
+	headers := map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          id,
+		"ce-type":        eventType,
+		"ce-source":      source,
+	}
+	if contentType != "" {
+		headers["content-type"] = contentType
+	}
+	return &provisioners.Message{Headers: headers, Payload: []byte(data)}
+}
+
+func TestArchive(t *testing.T) {
+	store := newFakeStore()
+	a := testArchiver(store, 100)
+
+	messages := []*provisioners.Message{
+		binaryEvent("1", "com.example.created", "https://example.com/orders", "application/json", `{"order": 1}`),
+		binaryEvent("2", "com.example.created", "https://example.com/orders", "text/plain", "two"),
+		binaryEvent("3", "com.example.deleted", "https://example.com/orders", "application/octet-stream", "\xff\xfe"),
+		{
+			Headers: map[string]string{"content-type": "application/cloudevents+json"},
+			Payload: []byte(`{
+				"specversion": "1.0",
+				"id": "4",
+				"type": "com.example.created",
+				"source": "https://example.com/orders",
+				"data": {"order": 4}
+			}`),
+		},
+		{Headers: map[string]string{}, Payload: []byte(`{"not": "a cloudevent"}`)},
+	}
+	var wg sync.WaitGroup
+	for _, m := range messages {
+		wg.Add(1)
+		go func(m *provisioners.Message) {
+			defer wg.Done()
+			if err := a.Archive(testChannel, m); err != nil {
+				t.Errorf("Unexpected error archiving: %v", err)
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	prefix := "events/namespace=test-namespace/channel=test-channel/date=2019-01-10/"
+	want := map[string][]string{
+		prefix + "type=com.example.created/source=https%3A%2F%2Fexample.com%2Forders": {
+			`{"data":"two","datacontenttype":"text/plain","id":"2","source":"https://example.com/orders","specversion":"1.0","type":"com.example.created"}`,
+			`{"data":{"order":1},"datacontenttype":"application/json","id":"1","source":"https://example.com/orders","specversion":"1.0","type":"com.example.created"}`,
+			`{"specversion":"1.0","id":"4","type":"com.example.created","source":"https://example.com/orders","data":{"order":4}}`,
+		},
+		prefix + "type=com.example.deleted/source=https%3A%2F%2Fexample.com%2Forders": {
+			`{"data_base64":"//4=","datacontenttype":"application/octet-stream","id":"3","source":"https://example.com/orders","specversion":"1.0","type":"com.example.deleted"}`,
+		},
+		prefix + "type=__HIVE_DEFAULT_PARTITION__/source=__HIVE_DEFAULT_PARTITION__": {
+			`{"data":{"not":"a cloudevent"}}`,
+		},
+	}
+	if diff := cmp.Diff(want, store.partitions()); diff != "" {
+		t.Errorf("Unexpected archive (-want +got): %s", diff)
+	}
+	if len(store.objects) != 3 {
+		t.Errorf("Expected the events to be written in one object per partition, got %v", store.objects)
+	}
+}
+
+func TestArchiveMaxBatch(t *testing.T) {
+	store := newFakeStore()
+	a := testArchiver(store, 2)
+	// A long interval, so that batches are only written once full.
+	a.config.FlushInterval = time.Hour
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := a.Archive(testChannel, binaryEvent("1", "type", "source", "text/plain", "data")); err != nil {
+				t.Errorf("Unexpected error archiving: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(store.objects) != 2 {
+		t.Errorf("Expected 2 batches, got %v", store.objects)
+	}
+}
+
+func TestArchiveErrors(t *testing.T) {
+	store := newFakeStore()
+	store.err = errors.New("unavailable")
+	a := testArchiver(store, 100)
+	if err := a.Archive(testChannel, binaryEvent("1", "type", "source", "", "data")); err != store.err {
+		t.Errorf("Expected the error of the store, got %v", err)
+	}
+
+	invalid := &provisioners.Message{
+		Headers: map[string]string{"content-type": "application/cloudevents+json"},
+		Payload: []byte("{"),
+	}
+	if _, ok := a.Archive(testChannel, invalid).(*InvalidEventError); !ok {
+		t.Errorf("Expected an InvalidEventError archiving an invalid structured event")
+	}
+}
+
+func TestExpire(t *testing.T) {
+	store := newFakeStore(
+		"events/namespace=ns/channel=c/date=2019-01-01/type=t/source=s/1.json",
+		"events/namespace=ns/channel=c/date=2019-01-02/type=t/source=s/2.json",
+		"events/namespace=ns/channel=c/date=2019-01-03/type=t/source=s/3.json",
+		"events/namespace=ns/channel=c/date=2019-01-10/type=t/source=s/4.json",
+		"events/unpartitioned.json",
+		"other/date=2019-01-01/5.json",
+	)
+	a := testArchiver(store, 100)
+
+	deleted, err := a.Expire()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 objects to be deleted, got %d", deleted)
+	}
+	var keys []string
+	for key := range store.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	want := []string{
+		"events/namespace=ns/channel=c/date=2019-01-03/type=t/source=s/3.json",
+		"events/namespace=ns/channel=c/date=2019-01-10/type=t/source=s/4.json",
+		"events/unpartitioned.json",
+		"other/date=2019-01-01/5.json",
+	}
+	if diff := cmp.Diff(want, keys); diff != "" {
+		t.Errorf("Unexpected objects (-want +got): %s", diff)
+	}
+
+	a.config.RetentionDays = 0
+	if deleted, err := a.Expire(); deleted != 0 || err != nil {
+		t.Errorf("Expected nothing to expire without retention, got %d, %v", deleted, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	envs := []string{EndpointEnv, BucketEnv, RegionEnv, AccessKeyIDEnv, SecretAccessKeyEnv, PrefixEnv, FlushIntervalEnv, MaxBatchEnv, RetentionDaysEnv}
+	reset := func() {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+	}
+	reset()
+	defer reset()
+
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error when the store is not configured")
+	}
+
+	os.Setenv(EndpointEnv, "http://minio.minio:9000")
+	os.Setenv(BucketEnv, "archive")
+	os.Setenv(AccessKeyIDEnv, "access")
+	os.Setenv(SecretAccessKeyEnv, "secret")
+	c, err := FromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Prefix != defaultPrefix || c.FlushInterval != defaultFlushInterval || c.MaxBatch != defaultMaxBatch || c.RetentionDays != 0 {
+		t.Errorf("Unexpected defaults %+v", c)
+	}
+
+	os.Setenv(PrefixEnv, "/archive/")
+	os.Setenv(FlushIntervalEnv, "5s")
+	os.Setenv(MaxBatchEnv, "50")
+	os.Setenv(RetentionDaysEnv, "30")
+	if c, err = FromEnv(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Prefix != "archive" || c.FlushInterval != 5*time.Second || c.MaxBatch != 50 || c.RetentionDays != 30 {
+		t.Errorf("Unexpected config %+v", c)
+	}
+
+	for env, value := range map[string]string{
+		FlushIntervalEnv: "0s",
+		MaxBatchEnv:      "many",
+		RetentionDaysEnv: "-1",
+	} {
+		os.Setenv(env, value)
+		if _, err := FromEnv(); err == nil {
+			t.Errorf("Expected an error for %s=%q", env, value)
+		}
+		reset()
+		os.Setenv(EndpointEnv, "http://minio.minio:9000")
+		os.Setenv(BucketEnv, "archive")
+		os.Setenv(AccessKeyIDEnv, "access")
+		os.Setenv(SecretAccessKeyEnv, "secret")
+	}
+}
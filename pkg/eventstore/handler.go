@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventstore
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// Handler archives the events delivered to /<namespace>/<name> as events of that Channel. The
+// archive Subscriptions of the Channels deliver to these paths.
+type Handler struct {
+	archiver    *Archiver
+	maxBodySize int64
+	logger      *zap.Logger
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a Handler archiving events with archiver. Events whose body is larger than
+// maxBodySize bytes are rejected, unless it is 0.
+func NewHandler(archiver *Archiver, maxBodySize int64, logger *zap.Logger) *Handler {
+	return &Handler{
+		archiver:    archiver,
+		maxBodySize: maxBodySize,
+		logger:      logger,
+	}
+}
+
+// ServeHTTP responds with:
+//
+//	202 - the event is archived
+//	400 - the event is not a CloudEvent that can be archived
+//	404 - the path is not the path of a Channel
+//	413 - the body of the event is larger than the maximum size
+//	500 - the event could not be written to the store
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	channel, ok := ChannelFromPath(r.URL.Path)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var body io.Reader = r.Body
+	if h.maxBodySize > 0 {
+		body = io.LimitReader(r.Body, h.maxBodySize+1)
+	}
+	payload, err := ioutil.ReadAll(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if h.maxBodySize > 0 && int64(len(payload)) > h.maxBodySize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	message := &provisioners.Message{
+		Headers: make(map[string]string),
+		Payload: payload,
+	}
+	for k, v := range r.Header {
+		name := strings.ToLower(k)
+		if name == "content-type" || strings.HasPrefix(name, "ce-") {
+			message.Headers[name] = v[0]
+		}
+	}
+	if err := message.ToCloudEventsSpecVersion(); err != nil {
+		h.reject(w, err)
+		return
+	}
+	if err := h.archiver.Archive(channel, message); err != nil {
+		if _, ok := err.(*InvalidEventError); ok {
+			h.reject(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) reject(w http.ResponseWriter, err error) {
+	h.logger.Info("Rejected the event", zap.Error(err))
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte(err.Error()))
+}
+
+// ChannelPath returns the path of the Handler archiving the events of channel.
+func ChannelPath(channel provisioners.ChannelReference) string {
+	return "/" + channel.Namespace + "/" + channel.Name
+}
+
+// ChannelFromPath returns the Channel whose events are archived at path.
+func ChannelFromPath(path string) (provisioners.ChannelReference, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return provisioners.ChannelReference{}, false
+	}
+	return provisioners.ChannelReference{Namespace: parts[0], Name: parts[1]}, true
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventstore
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	testCases := map[string]struct {
+		method   string
+		path     string
+		headers  map[string]string
+		body     string
+		storeErr error
+		want     int
+		wantKeys int
+	}{
+		"archived": {
+			path:     "/test-namespace/test-channel",
+			headers:  map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1", "Ce-Type": "type", "Ce-Source": "source"},
+			body:     "data",
+			want:     http.StatusAccepted,
+			wantKeys: 1,
+		},
+		"legacy spec version": {
+			path:     "/test-namespace/test-channel",
+			headers:  map[string]string{"Ce-Cloudeventsversion": "0.1", "Ce-Eventid": "1", "Ce-Eventtype": "type", "Ce-Source": "source"},
+			want:     http.StatusAccepted,
+			wantKeys: 1,
+		},
+		"unsupported spec version": {
+			path:    "/test-namespace/test-channel",
+			headers: map[string]string{"Ce-Specversion": "2.0"},
+			want:    http.StatusBadRequest,
+		},
+		"invalid structured event": {
+			path:    "/test-namespace/test-channel",
+			headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+			body:    "{",
+			want:    http.StatusBadRequest,
+		},
+		"too large": {
+			path: "/test-namespace/test-channel",
+			body: strings.Repeat("x", 11),
+			want: http.StatusRequestEntityTooLarge,
+		},
+		"store unavailable": {
+			path:     "/test-namespace/test-channel",
+			body:     "data",
+			storeErr: errors.New("unavailable"),
+			want:     http.StatusInternalServerError,
+		},
+		"not a channel": {
+			path: "/test-namespace",
+			want: http.StatusNotFound,
+		},
+		"not a POST": {
+			method: http.MethodGet,
+			path:   "/test-namespace/test-channel",
+			want:   http.StatusMethodNotAllowed,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			store := newFakeStore()
+			store.err = tc.storeErr
+			h := NewHandler(testArchiver(store, 1), 10, zap.NewNop())
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "http://eventstore.knative-eventing.svc.cluster.local"+tc.path, strings.NewReader(tc.body))
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("Unexpected status. Expected %d. Actual %d", tc.want, w.Code)
+			}
+			if len(store.objects) != tc.wantKeys {
+				t.Errorf("Unexpected objects. Expected %d. Actual %v", tc.wantKeys, store.objects)
+			}
+		})
+	}
+}
+
+func TestChannelPath(t *testing.T) {
+	path := ChannelPath(testChannel)
+	if path != "/test-namespace/test-channel" {
+		t.Errorf("Unexpected path %q", path)
+	}
+	channel, ok := ChannelFromPath(path)
+	if !ok || channel != testChannel {
+		t.Errorf("Unexpected channel %v, %v", channel, ok)
+	}
+	for _, invalid := range []string{"/", "/test-namespace", "/a/b/c", "//b"} {
+		if _, ok := ChannelFromPath(invalid); ok {
+			t.Errorf("Expected %q not to be the path of a channel", invalid)
+		}
+	}
+}
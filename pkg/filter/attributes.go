@@ -0,0 +1,57 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package filter
+
+import (
+	"sort"
+	"strings"
+)
+
+// IsValidAttributeName returns true if name can be used as an identifier in an expression. Names
+// follow the CloudEvents naming convention: lower-case letters and digits only. Keywords are not
+// allowed.
+func IsValidAttributeName(name string) bool {
+	if name == "" || keywords[strings.ToUpper(name)] {
+		return false
+	}
+	for _, c := range name {
+		if !('a' <= c && c <= 'z') && !('0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// AttributesExpression returns an expression that matches the events that have every attribute
+// in attrs with exactly the given value. The names in attrs must be valid attribute names. An
+// empty attrs results in an expression that matches every event.
+func AttributesExpression(attrs map[string]string) string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	terms := make([]string, 0, len(names))
+	for _, name := range names {
+		terms = append(terms, name+" = "+quote(attrs[name]))
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// quote returns s as a single-quoted string literal.
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package filter
+
+import "testing"
+
+func TestIsValidAttributeName(t *testing.T) {
+	testCases := map[string]bool{
+		"type":     true,
+		"source":   true,
+		"myext123": true,
+		"":         false,
+		"Type":     false,
+		"my-ext":   false,
+		"my_ext":   false,
+		"exists":   false,
+		"and":      false,
+	}
+	for name, want := range testCases {
+		if got := IsValidAttributeName(name); got != want {
+			t.Errorf("IsValidAttributeName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestAttributesExpression(t *testing.T) {
+	testCases := map[string]struct {
+		attrs     map[string]string
+		wantExpr  string
+		matches   map[string]string
+		unmatched map[string]string
+	}{
+		"empty": {
+			wantExpr: "",
+			matches:  map[string]string{"type": "foo"},
+		},
+		"single": {
+			attrs:     map[string]string{"type": "com.example.someevent"},
+			wantExpr:  "type = 'com.example.someevent'",
+			matches:   map[string]string{"type": "com.example.someevent", "source": "/foo"},
+			unmatched: map[string]string{"type": "com.example.otherevent"},
+		},
+		"multiple": {
+			attrs:     map[string]string{"type": "com.example.someevent", "source": "/foo"},
+			wantExpr:  "source = '/foo' AND type = 'com.example.someevent'",
+			matches:   map[string]string{"type": "com.example.someevent", "source": "/foo"},
+			unmatched: map[string]string{"type": "com.example.someevent"},
+		},
+		"quotes": {
+			attrs:     map[string]string{"subject": "it's"},
+			wantExpr:  "subject = 'it''s'",
+			matches:   map[string]string{"subject": "it's"},
+			unmatched: map[string]string{"subject": "its"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			expr := AttributesExpression(tc.attrs)
+			if expr != tc.wantExpr {
+				t.Errorf("Unexpected expression. Expected %q, actual %q", tc.wantExpr, expr)
+			}
+			e, err := Parse(expr)
+			if err != nil {
+				t.Fatalf("Unable to parse %q: %v", expr, err)
+			}
+			if !e.Matches(tc.matches) {
+				t.Errorf("Expected %q to match %v", expr, tc.matches)
+			}
+			if tc.unmatched != nil && e.Matches(tc.unmatched) {
+				t.Errorf("Expected %q not to match %v", expr, tc.unmatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filter implements a subset of the CloudEvents SQL expression language (CESQL) that is
+// evaluated against the context attributes of an event.
+//
+// The supported grammar is:
+//
+//   expression := or
+//   or         := and ( "OR" and )*
+//   and        := not ( "AND" not )*
+//   not        := "NOT" not | comparison
+//   comparison := "EXISTS" identifier
+//               | value ( ( "=" | "!=" | "<>" | "<" | "<=" | ">" | ">=" ) value )?
+//               | value "NOT"? "LIKE" string
+//   value      := identifier | string | integer | "TRUE" | "FALSE" | "(" expression ")"
+//
+// Identifiers refer to context attributes (e.g. type, source, subject or any extension). Keywords
+// are case insensitive. Strings may be quoted with either single or double quotes. A reference to
+// an attribute that is not present on the event makes the enclosing comparison false.
+//
+// Expressions are meant to be parsed once, when the configuration that contains them is loaded,
+// and then evaluated for every event.
+package filter
@@ -0,0 +1,406 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expression is a parsed filter expression.
+type Expression interface {
+	// Matches returns true if the event described by attrs satisfies the expression. attrs is a
+	// map from lower-case context attribute names to their string values.
+	Matches(attrs map[string]string) bool
+}
+
+// Parse parses expr into an Expression. An empty expression matches every event.
+func Parse(expr string) (Expression, error) {
+	if strings.TrimSpace(expr) == "" {
+		return matchAll{}, nil
+	}
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %v", t)
+	}
+	return &expression{root: n}, nil
+}
+
+type matchAll struct{}
+
+func (matchAll) Matches(map[string]string) bool {
+	return true
+}
+
+type expression struct {
+	root node
+}
+
+func (e *expression) Matches(attrs map[string]string) bool {
+	v := e.root.eval(attrs)
+	return v.kind == kindBool && v.b
+}
+
+type valueKind int
+
+const (
+	// kindMissing is the result of referencing an attribute that is not present, or of any
+	// operation on such a value.
+	kindMissing valueKind = iota
+	kindString
+	kindInteger
+	kindBool
+)
+
+type value struct {
+	kind valueKind
+	s    string
+	i    int64
+	b    bool
+}
+
+var missing = value{kind: kindMissing}
+
+func boolValue(b bool) value {
+	return value{kind: kindBool, b: b}
+}
+
+// asInteger converts v into an integer, if possible. Attribute values are always strings, so
+// comparing an attribute with an integer literal requires this conversion.
+func (v value) asInteger() (int64, bool) {
+	switch v.kind {
+	case kindInteger:
+		return v.i, true
+	case kindString:
+		i, err := strconv.ParseInt(v.s, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func (v value) asString() string {
+	switch v.kind {
+	case kindInteger:
+		return strconv.FormatInt(v.i, 10)
+	case kindBool:
+		return strconv.FormatBool(v.b)
+	}
+	return v.s
+}
+
+type node interface {
+	eval(attrs map[string]string) value
+}
+
+type literalNode struct {
+	v value
+}
+
+func (n *literalNode) eval(map[string]string) value {
+	return n.v
+}
+
+type attributeNode struct {
+	name string
+}
+
+func (n *attributeNode) eval(attrs map[string]string) value {
+	if s, ok := attrs[n.name]; ok {
+		return value{kind: kindString, s: s}
+	}
+	return missing
+}
+
+type existsNode struct {
+	name string
+}
+
+func (n *existsNode) eval(attrs map[string]string) value {
+	_, ok := attrs[n.name]
+	return boolValue(ok)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(attrs map[string]string) value {
+	v := n.operand.eval(attrs)
+	if v.kind != kindBool {
+		return missing
+	}
+	return boolValue(!v.b)
+}
+
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicalNode) eval(attrs map[string]string) value {
+	l := n.left.eval(attrs)
+	lb := l.kind == kindBool && l.b
+	// Short circuit.
+	if n.and && !lb {
+		return boolValue(false)
+	}
+	if !n.and && lb {
+		return boolValue(true)
+	}
+	r := n.right.eval(attrs)
+	return boolValue(r.kind == kindBool && r.b)
+}
+
+type comparisonNode struct {
+	op          string
+	left, right node
+}
+
+func (n *comparisonNode) eval(attrs map[string]string) value {
+	l := n.left.eval(attrs)
+	r := n.right.eval(attrs)
+	if l.kind == kindMissing || r.kind == kindMissing {
+		return missing
+	}
+
+	var cmp int
+	if l.kind == kindInteger || r.kind == kindInteger {
+		li, lok := l.asInteger()
+		ri, rok := r.asInteger()
+		if !lok || !rok {
+			return missing
+		}
+		switch {
+		case li < ri:
+			cmp = -1
+		case li > ri:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(l.asString(), r.asString())
+	}
+
+	switch n.op {
+	case "=":
+		return boolValue(cmp == 0)
+	case "!=", "<>":
+		return boolValue(cmp != 0)
+	case "<":
+		return boolValue(cmp < 0)
+	case "<=":
+		return boolValue(cmp <= 0)
+	case ">":
+		return boolValue(cmp > 0)
+	case ">=":
+		return boolValue(cmp >= 0)
+	}
+	return missing
+}
+
+type likeNode struct {
+	operand node
+	pattern *regexp.Regexp
+	negate  bool
+}
+
+func (n *likeNode) eval(attrs map[string]string) value {
+	v := n.operand.eval(attrs)
+	if v.kind == kindMissing {
+		return missing
+	}
+	return boolValue(n.pattern.MatchString(v.asString()) != n.negate)
+}
+
+// likePattern converts a LIKE pattern into an anchored regular expression. '%' matches any
+// sequence of characters, '_' matches exactly one character and '\' escapes the next character.
+func likePattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile("(?s)" + b.String())
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(k string) bool {
+	t := p.peek()
+	return t.kind == tokenKeyword && t.value == k
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	if p.isKeyword("EXISTS") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenIdentifier {
+			return nil, fmt.Errorf("expected an attribute name after EXISTS, found %v", t)
+		}
+		return &existsNode{name: t.value}, nil
+	}
+
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	negate := false
+	if p.isKeyword("NOT") {
+		p.next()
+		if !p.isKeyword("LIKE") {
+			return nil, fmt.Errorf("expected LIKE, found %v", p.peek())
+		}
+		negate = true
+	}
+	if p.isKeyword("LIKE") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenString {
+			return nil, fmt.Errorf("expected a string pattern after LIKE, found %v", t)
+		}
+		re, err := likePattern(t.value)
+		if err != nil {
+			return nil, err
+		}
+		return &likeNode{operand: left, pattern: re, negate: negate}, nil
+	}
+
+	if t := p.peek(); t.kind == tokenOperator {
+		p.next()
+		right, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonNode{op: t.value, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseValue() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenIdentifier:
+		return &attributeNode{name: t.value}, nil
+	case tokenString:
+		return &literalNode{v: value{kind: kindString, s: t.value}}, nil
+	case tokenInteger:
+		i, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %v", t)
+		}
+		return &literalNode{v: value{kind: kindInteger, i: i}}, nil
+	case tokenKeyword:
+		switch t.value {
+		case "TRUE":
+			return &literalNode{v: boolValue(true)}, nil
+		case "FALSE":
+			return &literalNode{v: boolValue(false)}, nil
+		}
+	case tokenLeftParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokenRightParen {
+			return nil, fmt.Errorf("expected ')', found %v", c)
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("unexpected %v", t)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"testing"
+)
+
+var attrs = map[string]string{
+	"type":        "com.example.created",
+	"source":      "/orders/1234",
+	"id":          "A234-1234-1234",
+	"specversion": "0.2",
+	"priority":    "7",
+	"quoted":      "it's",
+}
+
+func TestParseAndMatch(t *testing.T) {
+	testCases := map[string]struct {
+		expr string
+		want bool
+	}{
+		"empty":                   {expr: "", want: true},
+		"whitespace":              {expr: "   ", want: true},
+		"equal":                   {expr: "type = 'com.example.created'", want: true},
+		"equal double quotes":     {expr: `type = "com.example.created"`, want: true},
+		"not equal":               {expr: "type != 'com.example.created'", want: false},
+		"not equal alt":           {expr: "type <> 'com.example.deleted'", want: true},
+		"case insensitive":        {expr: "TYPE = 'com.example.created' and Source = '/orders/1234'", want: true},
+		"missing attribute":       {expr: "subject = 'foo'", want: false},
+		"not missing attribute":   {expr: "NOT (subject = 'foo')", want: false},
+		"missing attribute or":    {expr: "subject = 'foo' OR type = 'com.example.created'", want: true},
+		"exists":                  {expr: "EXISTS source", want: true},
+		"not exists":              {expr: "NOT EXISTS subject", want: true},
+		"integer compare":         {expr: "priority > 5", want: true},
+		"integer compare false":   {expr: "priority >= 10", want: false},
+		"integer vs non-integer":  {expr: "type > 5", want: false},
+		"string compare":          {expr: "specversion < '1.0'", want: true},
+		"like prefix":             {expr: "source LIKE '/orders/%'", want: true},
+		"like single char":        {expr: "specversion LIKE '0._'", want: true},
+		"like no match":           {expr: "source LIKE '/users/%'", want: false},
+		"not like":                {expr: "source NOT LIKE '/users/%'", want: true},
+		"like escaped":            {expr: `id LIKE 'A234\-%'`, want: true},
+		"like escaped percent":    {expr: `type LIKE 'com\%'`, want: false},
+		"escaped quote":           {expr: "quoted = 'it''s'", want: true},
+		"precedence":              {expr: "type = 'x' AND source = 'y' OR id = 'A234-1234-1234'", want: true},
+		"parentheses":             {expr: "type = 'x' AND (source = 'y' OR id = 'A234-1234-1234')", want: false},
+		"boolean literal":         {expr: "TRUE", want: true},
+		"negated boolean literal": {expr: "NOT FALSE", want: true},
+		"non-boolean result":      {expr: "type", want: false},
+		"negative integer":        {expr: "priority > -1", want: true},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing %q: %v", tc.expr, err)
+			}
+			if got := e.Matches(attrs); got != tc.want {
+				t.Errorf("Matches(%q) = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]string{
+		"dangling operator":    "type = ",
+		"dangling and":         "type = 'a' AND",
+		"unterminated string":  "type = 'a",
+		"unbalanced paren":     "(type = 'a'",
+		"extra paren":          "type = 'a')",
+		"bad character":        "type = 'a' ; drop",
+		"lone bang":            "type ! 'a'",
+		"exists literal":       "EXISTS 'type'",
+		"like without pattern": "type LIKE source",
+		"not without like":     "type NOT 'a'",
+		"two values":           "type source",
+		"lone minus":           "priority > -",
+	}
+	for n, expr := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Errorf("Expected an error parsing %q", expr)
+			}
+		})
+	}
+}
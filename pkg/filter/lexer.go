@@ -0,0 +1,150 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenKeyword
+	tokenString
+	tokenInteger
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+// keywords are stored upper-cased, identifiers that match one of them (case insensitively) are
+// lexed as tokenKeyword.
+var keywords = map[string]bool{
+	"AND":    true,
+	"OR":     true,
+	"NOT":    true,
+	"LIKE":   true,
+	"EXISTS": true,
+	"TRUE":   true,
+	"FALSE":  true,
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.value, t.pos)
+}
+
+// lex splits the expression into tokens. The returned slice always ends with a tokenEOF.
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, value: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, value: ")", pos: i})
+			i++
+		case c == '\'' || c == '"':
+			s, n, err := lexString(expr[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: s, pos: i})
+			i += n
+		case strings.ContainsRune("=!<>", c):
+			op := string(c)
+			if i+1 < len(expr) {
+				two := expr[i : i+2]
+				if two == "!=" || two == "<>" || two == "<=" || two == ">=" {
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+			i += len(op)
+		case c == '-' || unicode.IsDigit(c):
+			start := i
+			i++
+			for i < len(expr) && unicode.IsDigit(rune(expr[i])) {
+				i++
+			}
+			if expr[start:i] == "-" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, start)
+			}
+			tokens = append(tokens, token{kind: tokenInteger, value: expr[start:i], pos: start})
+		case isIdentifierRune(c):
+			start := i
+			for i < len(expr) && isIdentifierRune(rune(expr[i])) {
+				i++
+			}
+			word := expr[start:i]
+			if upper := strings.ToUpper(word); keywords[upper] {
+				tokens = append(tokens, token{kind: tokenKeyword, value: upper, pos: start})
+			} else {
+				tokens = append(tokens, token{kind: tokenIdentifier, value: strings.ToLower(word), pos: start})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+// lexString reads a quoted string from the start of s. It returns the unquoted value and the
+// number of bytes consumed. A quote character is escaped by doubling it or by a backslash. Other
+// backslashes are kept, so they can escape LIKE wildcards.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\'):
+			i++
+			b.WriteByte(s[i])
+		case c == quote && i+1 < len(s) && s[i+1] == quote:
+			i++
+			b.WriteByte(quote)
+		case c == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isIdentifierRune(c rune) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema validates JSON documents against a subset of JSON Schema (draft 7).
+//
+// The supported keywords are:
+//
+//   type, enum, const
+//   properties, required, additionalProperties, minProperties, maxProperties
+//   items, minItems, maxItems, uniqueItems
+//   minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//   minLength, maxLength, pattern
+//   allOf, anyOf, oneOf, not
+//
+// The annotations $schema, $id, $comment, title, description, default, examples, readOnly and
+// writeOnly are accepted and ignored. Schemas using any other keyword, including $ref and format,
+// are rejected by Compile rather than silently validating less than they declare. Schemas are
+// meant to be compiled once and then used to validate many documents.
+package jsonschema
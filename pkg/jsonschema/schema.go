@@ -0,0 +1,488 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	// boolean is set for the schemas true and false, which accept and reject every document.
+	boolean *bool
+
+	types []string
+	enum  []interface{}
+	cnst  *interface{}
+
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	minProperties        *int
+	maxProperties        *int
+
+	items       *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+// ValidationError is returned when a document does not conform to a Schema.
+type ValidationError struct {
+	// Problems describes each way the document does not conform, prefixed with the JSON pointer
+	// of the offending value.
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "the document does not conform to the schema: " + strings.Join(e.Problems, "; ")
+}
+
+// Compile compiles the JSON Schema schema.
+func Compile(schema []byte) (*Schema, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(schema))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return compile(v, "")
+}
+
+func compile(v interface{}, path string) (*Schema, error) {
+	if b, ok := v.(bool); ok {
+		return &Schema{boolean: &b}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: a schema must be an object or a boolean", pointer(path))
+	}
+	s := &Schema{}
+	var err error
+	for k, kv := range m {
+		kpath := path + "/" + k
+		switch k {
+		case "type":
+			switch t := kv.(type) {
+			case string:
+				s.types = []string{t}
+			case []interface{}:
+				for _, e := range t {
+					str, ok := e.(string)
+					if !ok {
+						return nil, fmt.Errorf("%s: types must be strings", pointer(kpath))
+					}
+					s.types = append(s.types, str)
+				}
+			default:
+				return nil, fmt.Errorf("%s: must be a string or an array", pointer(kpath))
+			}
+		case "enum":
+			e, ok := kv.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array", pointer(kpath))
+			}
+			s.enum = e
+		case "const":
+			c := kv
+			s.cnst = &c
+		case "properties":
+			p, ok := kv.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an object", pointer(kpath))
+			}
+			s.properties = make(map[string]*Schema, len(p))
+			for name, ps := range p {
+				if s.properties[name], err = compile(ps, kpath+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			r, ok := kv.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array", pointer(kpath))
+			}
+			for _, e := range r {
+				str, ok := e.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s: property names must be strings", pointer(kpath))
+				}
+				s.required = append(s.required, str)
+			}
+		case "additionalProperties":
+			if s.additionalProperties, err = compile(kv, kpath); err != nil {
+				return nil, err
+			}
+		case "items":
+			if s.items, err = compile(kv, kpath); err != nil {
+				return nil, err
+			}
+		case "not":
+			if s.not, err = compile(kv, kpath); err != nil {
+				return nil, err
+			}
+		case "allOf", "anyOf", "oneOf":
+			a, ok := kv.([]interface{})
+			if !ok || len(a) == 0 {
+				return nil, fmt.Errorf("%s: must be a non-empty array", pointer(kpath))
+			}
+			schemas := make([]*Schema, len(a))
+			for i, e := range a {
+				if schemas[i], err = compile(e, fmt.Sprintf("%s/%d", kpath, i)); err != nil {
+					return nil, err
+				}
+			}
+			switch k {
+			case "allOf":
+				s.allOf = schemas
+			case "anyOf":
+				s.anyOf = schemas
+			case "oneOf":
+				s.oneOf = schemas
+			}
+		case "uniqueItems":
+			b, ok := kv.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a boolean", pointer(kpath))
+			}
+			s.uniqueItems = b
+		case "pattern":
+			str, ok := kv.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string", pointer(kpath))
+			}
+			if s.pattern, err = regexp.Compile(str); err != nil {
+				return nil, fmt.Errorf("%s: %v", pointer(kpath), err)
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf":
+			f, ok := number(kv)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a number", pointer(kpath))
+			}
+			switch k {
+			case "minimum":
+				s.minimum = &f
+			case "maximum":
+				s.maximum = &f
+			case "exclusiveMinimum":
+				s.exclusiveMinimum = &f
+			case "exclusiveMaximum":
+				s.exclusiveMaximum = &f
+			case "multipleOf":
+				s.multipleOf = &f
+			}
+		case "minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties":
+			f, ok := number(kv)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return nil, fmt.Errorf("%s: must be a non-negative integer", pointer(kpath))
+			}
+			n := int(f)
+			switch k {
+			case "minLength":
+				s.minLength = &n
+			case "maxLength":
+				s.maxLength = &n
+			case "minItems":
+				s.minItems = &n
+			case "maxItems":
+				s.maxItems = &n
+			case "minProperties":
+				s.minProperties = &n
+			case "maxProperties":
+				s.maxProperties = &n
+			}
+		case "$schema", "$id", "$comment", "title", "description", "default", "examples", "readOnly", "writeOnly":
+			// Annotations, which do not affect validation.
+		default:
+			// Documents must not be accepted because a keyword constraining them is ignored.
+			return nil, fmt.Errorf("%s: unsupported keyword %q", pointer(kpath), k)
+		}
+	}
+	return s, nil
+}
+
+// Validate returns a ValidationError if the JSON document doc does not conform to the schema.
+func (s *Schema) Validate(doc []byte) error {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return &ValidationError{Problems: []string{"the document is not valid JSON"}}
+	}
+	if problems := s.validate(v, ""); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *Schema) validate(v interface{}, path string) []string {
+	if s.boolean != nil {
+		if *s.boolean {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: no value is allowed", pointer(path))}
+	}
+
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, pointer(path)+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.types) > 0 && !hasType(v, s.types) {
+		add("expected %s, got %s", strings.Join(s.types, " or "), typeOf(v))
+		// The other keywords would only repeat the type mismatch.
+		return problems
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("not one of the allowed values")
+		}
+	}
+	if s.cnst != nil && !equal(v, *s.cnst) {
+		add("not the allowed value")
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := t[name]; !ok {
+				add("missing required property %q", name)
+			}
+		}
+		if s.minProperties != nil && len(t) < *s.minProperties {
+			add("expected at least %d properties", *s.minProperties)
+		}
+		if s.maxProperties != nil && len(t) > *s.maxProperties {
+			add("expected at most %d properties", *s.maxProperties)
+		}
+		names := make([]string, 0, len(t))
+		for name := range t {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ppath := path + "/" + escape(name)
+			if ps, ok := s.properties[name]; ok {
+				problems = append(problems, ps.validate(t[name], ppath)...)
+			} else if s.additionalProperties != nil {
+				if s.additionalProperties.boolean != nil && !*s.additionalProperties.boolean {
+					add("unexpected property %q", name)
+					continue
+				}
+				problems = append(problems, s.additionalProperties.validate(t[name], ppath)...)
+			}
+		}
+	case []interface{}:
+		if s.minItems != nil && len(t) < *s.minItems {
+			add("expected at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(t) > *s.maxItems {
+			add("expected at most %d items", *s.maxItems)
+		}
+		if s.uniqueItems {
+			for i := range t {
+				for j := i + 1; j < len(t); j++ {
+					if equal(t[i], t[j]) {
+						add("items %d and %d are equal", i, j)
+					}
+				}
+			}
+		}
+		if s.items != nil {
+			for i, e := range t {
+				problems = append(problems, s.items.validate(e, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+	case json.Number:
+		f, _ := t.Float64()
+		if s.minimum != nil && f < *s.minimum {
+			add("expected at least %v", *s.minimum)
+		}
+		if s.maximum != nil && f > *s.maximum {
+			add("expected at most %v", *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum {
+			add("expected more than %v", *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum {
+			add("expected less than %v", *s.exclusiveMaximum)
+		}
+		if s.multipleOf != nil && *s.multipleOf != 0 {
+			if q := f / *s.multipleOf; q != math.Trunc(q) {
+				add("expected a multiple of %v", *s.multipleOf)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(t)
+		if s.minLength != nil && n < *s.minLength {
+			add("expected at least %d characters", *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			add("expected at most %d characters", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(t) {
+			add("does not match the pattern %q", s.pattern.String())
+		}
+	}
+
+	for _, sub := range s.allOf {
+		problems = append(problems, sub.validate(v, path)...)
+	}
+	if s.anyOf != nil && count(s.anyOf, v, path) == 0 {
+		add("does not match any of the allowed schemas")
+	}
+	if s.oneOf != nil {
+		if n := count(s.oneOf, v, path); n != 1 {
+			add("matches %d of the schemas, expected exactly one", n)
+		}
+	}
+	if s.not != nil && len(s.not.validate(v, path)) == 0 {
+		add("matches a disallowed schema")
+	}
+	return problems
+}
+
+// count returns the number of schemas v conforms to.
+func count(schemas []*Schema, v interface{}, path string) int {
+	n := 0
+	for _, s := range schemas {
+		if len(s.validate(v, path)) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func hasType(v interface{}, types []string) bool {
+	actual := typeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if f, err := t.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func number(v interface{}) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+// equal compares JSON values, with numbers compared by value.
+func equal(a, b interface{}) bool {
+	if an, ok := a.(json.Number); ok {
+		bn, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, _ := an.Float64()
+		bf, _ := bn.Float64()
+		return af == bf
+	}
+	switch at := a.(type) {
+	case []interface{}:
+		bt, ok := b.([]interface{})
+		if !ok || len(at) != len(bt) {
+			return false
+		}
+		for i := range at {
+			if !equal(at[i], bt[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bt, ok := b.(map[string]interface{})
+		if !ok || len(at) != len(bt) {
+			return false
+		}
+		for k, av := range at {
+			bv, ok := bt[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
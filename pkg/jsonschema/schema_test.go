@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["id", "total"],
+	"properties": {
+		"id": {"type": "string", "pattern": "^ord-[0-9]+$"},
+		"total": {"type": "number", "minimum": 0},
+		"currency": {"enum": ["EUR", "USD"]},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"type": "object", "required": ["sku"]}
+		}
+	},
+	"additionalProperties": false
+}`
+
+func TestCompile(t *testing.T) {
+	testCases := map[string]string{
+		"not JSON":        `{`,
+		"not an object":   `"string"`,
+		"invalid type":    `{"type": 3}`,
+		"invalid pattern": `{"pattern": "("}`,
+		"invalid minimum": `{"minimum": "0"}`,
+		"invalid length":  `{"minLength": -1}`,
+		"empty anyOf":     `{"anyOf": []}`,
+		"nested invalid":  `{"properties": {"id": 3}}`,
+		"ref":             `{"properties": {"id": {"$ref": "#/definitions/id"}}}`,
+		"format":          `{"type": "string", "format": "date-time"}`,
+		"unknown keyword": `{"typ": "string"}`,
+	}
+	for n, schema := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if _, err := Compile([]byte(schema)); err == nil {
+				t.Errorf("Expected an error compiling %s", schema)
+			}
+		})
+	}
+}
+
+func TestCompileAnnotations(t *testing.T) {
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "https://example.com/order.json",
+		"$comment": "orders",
+		"title": "Order",
+		"description": "An order.",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "default": "", "examples": ["1"], "readOnly": true, "writeOnly": false}
+		}
+	}`
+	if _, err := Compile([]byte(schema)); err != nil {
+		t.Errorf("Compile() = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		schema string
+		doc    string
+		want   []string
+	}{
+		"valid": {
+			schema: orderSchema,
+			doc:    `{"id": "ord-1", "total": 10.5, "currency": "EUR", "items": [{"sku": "a"}]}`,
+		},
+		"invalid": {
+			schema: orderSchema,
+			doc:    `{"id": "1", "total": -1, "currency": "GBP", "items": [{}], "card": "4111"}`,
+			want: []string{
+				`/: unexpected property "card"`,
+				`/currency: not one of the allowed values`,
+				`/id: does not match the pattern "^ord-[0-9]+$"`,
+				`/items/0: missing required property "sku"`,
+				`/total: expected at least 0`,
+			},
+		},
+		"missing required": {
+			schema: orderSchema,
+			doc:    `{}`,
+			want: []string{
+				`/: missing required property "id"`,
+				`/: missing required property "total"`,
+			},
+		},
+		"wrong type": {
+			schema: orderSchema,
+			doc:    `[]`,
+			want:   []string{`/: expected object, got array`},
+		},
+		"not JSON": {
+			schema: orderSchema,
+			doc:    `<xml/>`,
+			want:   []string{"the document is not valid JSON"},
+		},
+		"integer is a number": {
+			schema: `{"type": "number", "multipleOf": 2}`,
+			doc:    `4`,
+		},
+		"not an integer": {
+			schema: `{"type": "integer"}`,
+			doc:    `4.5`,
+			want:   []string{`/: expected integer, got number`},
+		},
+		"string length": {
+			schema: `{"type": "string", "minLength": 2, "maxLength": 3}`,
+			doc:    `"€"`,
+			want:   []string{`/: expected at least 2 characters`},
+		},
+		"unique items": {
+			schema: `{"uniqueItems": true}`,
+			doc:    `[1, 1.0, 2]`,
+			want:   []string{`/: items 0 and 1 are equal`},
+		},
+		"oneOf": {
+			schema: `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`,
+			doc:    `1`,
+			want:   []string{`/: matches 2 of the schemas, expected exactly one`},
+		},
+		"anyOf": {
+			schema: `{"anyOf": [{"type": "string"}, {"type": "null"}]}`,
+			doc:    `null`,
+		},
+		"not": {
+			schema: `{"not": {"const": "secret"}}`,
+			doc:    `"secret"`,
+			want:   []string{`/: matches a disallowed schema`},
+		},
+		"false": {
+			schema: `{"properties": {"card": false}}`,
+			doc:    `{"card": "4111"}`,
+			want:   []string{`/card: no value is allowed`},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s, err := Compile([]byte(tc.schema))
+			if err != nil {
+				t.Fatalf("Unexpected error compiling the schema: %v", err)
+			}
+			var got []string
+			if err := s.Validate([]byte(tc.doc)); err != nil {
+				got = err.(*ValidationError).Problems
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected problems (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leaderelection runs a single replica of a component at a time, so that components can
+// run several replicas for availability without reconciling the same objects concurrently. The
+// election is held on a ConfigMap.
+package leaderelection
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/knative/eventing/pkg/system"
+)
+
+const (
+	// EnabledEnv is the environment variable enabling leader election, when set to true.
+	EnabledEnv = "LEADER_ELECTION"
+	// LeaseNameEnv is the environment variable holding the name of the ConfigMap the election is
+	// held on.
+	LeaseNameEnv = "LEADER_ELECTION_LEASE_NAME"
+	// LeaseNamespaceEnv is the environment variable holding the namespace of the ConfigMap the
+	// election is held on.
+	LeaseNamespaceEnv = "LEADER_ELECTION_LEASE_NAMESPACE"
+	// LeaseDurationEnv is the environment variable holding the duration the other replicas wait
+	// before taking over from a leader that stopped renewing its lease, e.g. 15s.
+	LeaseDurationEnv = "LEADER_ELECTION_LEASE_DURATION"
+	// RenewDeadlineEnv is the environment variable holding the duration the leader tries to renew
+	// its lease before it stops leading, e.g. 10s.
+	RenewDeadlineEnv = "LEADER_ELECTION_RENEW_DEADLINE"
+	// RetryPeriodEnv is the environment variable holding the duration between two attempts to
+	// acquire or renew the lease, e.g. 2s.
+	RetryPeriodEnv = "LEADER_ELECTION_RETRY_PERIOD"
+
+	// DefaultLeaseDuration is the LeaseDuration when LeaseDurationEnv is not set.
+	DefaultLeaseDuration = 15 * time.Second
+	// DefaultRenewDeadline is the RenewDeadline when RenewDeadlineEnv is not set.
+	DefaultRenewDeadline = 10 * time.Second
+	// DefaultRetryPeriod is the RetryPeriod when RetryPeriodEnv is not set.
+	DefaultRetryPeriod = 2 * time.Second
+)
+
+// errLeadershipLost is returned by Run when the replica stops leading before it is stopped.
+var errLeadershipLost = errors.New("the leader election was lost")
+
+// Config configures the leader election of a component.
+type Config struct {
+	// Enabled makes a single replica of the component run at a time.
+	Enabled bool
+
+	// LeaseName and LeaseNamespace are the name and namespace of the ConfigMap the election is
+	// held on.
+	LeaseName      string
+	LeaseNamespace string
+
+	// LeaseDuration, RenewDeadline and RetryPeriod time the election, as in the leader election
+	// of client-go. LeaseDuration must be greater than RenewDeadline, and RenewDeadline greater
+	// than 1.2 RetryPeriod.
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// NewConfig returns a disabled Config of the election named name, in the system namespace, with
+// the default durations.
+func NewConfig(name string) Config {
+	return Config{
+		LeaseName:      name,
+		LeaseNamespace: system.Namespace,
+		LeaseDuration:  DefaultLeaseDuration,
+		RenewDeadline:  DefaultRenewDeadline,
+		RetryPeriod:    DefaultRetryPeriod,
+	}
+}
+
+// ConfigFromEnv reads the Config of the component named name from the environment. The lease is
+// named name and lives in the system namespace unless LeaseNameEnv and LeaseNamespaceEnv are set.
+func ConfigFromEnv(name string) (Config, error) {
+	config := NewConfig(name)
+	var err error
+	if v := os.Getenv(EnabledEnv); v != "" {
+		if config.Enabled, err = strconv.ParseBool(v); err != nil {
+			return config, fmt.Errorf("invalid %s %q: %v", EnabledEnv, v, err)
+		}
+	}
+	if v := os.Getenv(LeaseNameEnv); v != "" {
+		config.LeaseName = v
+	}
+	if v := os.Getenv(LeaseNamespaceEnv); v != "" {
+		config.LeaseNamespace = v
+	}
+	for env, d := range map[string]*time.Duration{
+		LeaseDurationEnv: &config.LeaseDuration,
+		RenewDeadlineEnv: &config.RenewDeadline,
+		RetryPeriodEnv:   &config.RetryPeriod,
+	} {
+		if v := os.Getenv(env); v != "" {
+			if *d, err = time.ParseDuration(v); err != nil || *d <= 0 {
+				return config, fmt.Errorf("invalid %s %q: it must be a positive duration", env, v)
+			}
+		}
+	}
+	if config.LeaseDuration <= config.RenewDeadline {
+		return config, fmt.Errorf("%s must be greater than %s", LeaseDurationEnv, RenewDeadlineEnv)
+	}
+	if float64(config.RenewDeadline) <= leaderelection.JitterFactor*float64(config.RetryPeriod) {
+		return config, fmt.Errorf("%s must be greater than %v times %s", RenewDeadlineEnv, leaderelection.JitterFactor, RetryPeriodEnv)
+	}
+	return config, nil
+}
+
+// NewLock returns the lock of the election configured by config, held by the replica identity.
+// The transitions of the leadership are recorded as events of the ConfigMap.
+func NewLock(config Config, client kubernetes.Interface, identity string) (resourcelock.Interface, error) {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events(config.LeaseNamespace)})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: config.LeaseName})
+	return resourcelock.New(resourcelock.ConfigMapsResourceLock, config.LeaseNamespace, config.LeaseName,
+		client.CoreV1(), resourcelock.ResourceLockConfig{Identity: identity, EventRecorder: recorder})
+}
+
+// RunManager starts mgr and runs it until stopCh is closed. If leader election is enabled in
+// config, mgr only runs while the replica leads the election, and an error is returned if it stops
+// leading before stopCh is closed, so that the replica is restarted and campaigns again.
+func RunManager(stopCh <-chan struct{}, config Config, mgr manager.Manager, logger *zap.Logger) error {
+	if !config.Enabled {
+		return mgr.Start(stopCh)
+	}
+	client, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	lock, err := NewLock(config, client, identity)
+	if err != nil {
+		return err
+	}
+	return Run(stopCh, config, lock, logger, mgr.Start)
+}
+
+// Run runs start while the replica holds lock, and until stopCh is closed. It returns an error if
+// the replica stops leading before stopCh is closed.
+func Run(stopCh <-chan struct{}, config Config, lock resourcelock.Interface, logger *zap.Logger, start func(<-chan struct{}) error) error {
+	started := make(chan struct{})
+	errCh := make(chan error, 1)
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: config.LeaseDuration,
+		RenewDeadline: config.RenewDeadline,
+		RetryPeriod:   config.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(lost <-chan struct{}) {
+				logger.Info("Started leading", zap.String("lock", lock.Describe()), zap.String("identity", lock.Identity()))
+				close(started)
+				stop := make(chan struct{})
+				go func() {
+					select {
+					case <-stopCh:
+					case <-lost:
+					}
+					close(stop)
+				}()
+				err := start(stop)
+				select {
+				case <-stopCh:
+				default:
+					if err == nil {
+						err = errLeadershipLost
+					}
+				}
+				errCh <- err
+			},
+			OnStoppedLeading: func() {
+				logger.Info("Stopped leading", zap.String("lock", lock.Describe()), zap.String("identity", lock.Identity()))
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	// The elector cannot be stopped, and stops with the process.
+	go le.Run()
+	select {
+	case err := <-errCh:
+		return err
+	case <-stopCh:
+	}
+	select {
+	case <-started:
+		// The component is stopping.
+		return <-errCh
+	default:
+		return nil
+	}
+}
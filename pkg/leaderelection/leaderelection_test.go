@@ -0,0 +1,210 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package leaderelection
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// fakeLock is an in-memory resourcelock.Interface.
+type fakeLock struct {
+	identity string
+
+	mu     sync.Mutex
+	record *resourcelock.LeaderElectionRecord
+}
+
+var _ resourcelock.Interface = (*fakeLock)(nil)
+
+func (l *fakeLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.record == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "lock")
+	}
+	r := *l.record
+	return &r, nil
+}
+
+func (l *fakeLock) Create(ler resourcelock.LeaderElectionRecord) error {
+	return l.Update(ler)
+}
+
+func (l *fakeLock) Update(ler resourcelock.LeaderElectionRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.record = &ler
+	return nil
+}
+
+func (l *fakeLock) RecordEvent(string) {}
+
+func (l *fakeLock) Identity() string {
+	return l.identity
+}
+
+func (l *fakeLock) Describe() string {
+	return "fake/lock"
+}
+
+func TestRun(t *testing.T) {
+	lock := &fakeLock{identity: "replica-0"}
+	stopCh := make(chan struct{})
+	started := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Run(stopCh, NewConfig("lock"), lock, zap.NewNop(), func(stop <-chan struct{}) error {
+			close(started)
+			<-stop
+			return nil
+		})
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the component was not started")
+	}
+	close(stopCh)
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the component did not stop")
+	}
+}
+
+func TestRunNotLeading(t *testing.T) {
+	now := metav1.Now()
+	lock := &fakeLock{
+		identity: "replica-1",
+		record: &resourcelock.LeaderElectionRecord{
+			HolderIdentity:       "replica-0",
+			LeaseDurationSeconds: 15,
+			AcquireTime:          now,
+			RenewTime:            now,
+		},
+	}
+	stopCh := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Run(stopCh, NewConfig("lock"), lock, zap.NewNop(), func(<-chan struct{}) error {
+			t.Error("the component was started without leading")
+			return nil
+		})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	close(stopCh)
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the election did not stop")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{{
+		name: "defaults",
+		want: Config{
+			LeaseName:      "controller",
+			LeaseNamespace: "knative-eventing",
+			LeaseDuration:  15 * time.Second,
+			RenewDeadline:  10 * time.Second,
+			RetryPeriod:    2 * time.Second,
+		},
+	}, {
+		name: "configured",
+		env: map[string]string{
+			EnabledEnv:        "true",
+			LeaseNameEnv:      "lease",
+			LeaseNamespaceEnv: "leases",
+			LeaseDurationEnv:  "1m",
+			RenewDeadlineEnv:  "40s",
+			RetryPeriodEnv:    "5s",
+		},
+		want: Config{
+			Enabled:        true,
+			LeaseName:      "lease",
+			LeaseNamespace: "leases",
+			LeaseDuration:  time.Minute,
+			RenewDeadline:  40 * time.Second,
+			RetryPeriod:    5 * time.Second,
+		},
+	}, {
+		name:    "invalid enabled",
+		env:     map[string]string{EnabledEnv: "maybe"},
+		wantErr: true,
+	}, {
+		name:    "invalid duration",
+		env:     map[string]string{RetryPeriodEnv: "-1s"},
+		wantErr: true,
+	}, {
+		name:    "lease shorter than the renew deadline",
+		env:     map[string]string{LeaseDurationEnv: "10s"},
+		wantErr: true,
+	}, {
+		name:    "renew deadline shorter than the retry period",
+		env:     map[string]string{RetryPeriodEnv: "9s"},
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, env := range []string{EnabledEnv, LeaseNameEnv, LeaseNamespaceEnv, LeaseDurationEnv, RenewDeadlineEnv, RetryPeriodEnv} {
+				os.Unsetenv(env)
+			}
+			for k, v := range test.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			got, err := ConfigFromEnv("controller")
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected config (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
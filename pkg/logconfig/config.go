@@ -24,10 +24,36 @@ const (
 	//
 	// loglevel.controller: "info"
 	// loglevel.webhook: "info"
+	// loglevel.in-memory-channel-dispatcher: "info"
+	// ...
 
 	// Controller is the name of the override key used inside of the logging config for Controller.
 	Controller = "controller"
 
 	// Webhook is the name of the override key used inside of the logging config for Webhook Controller.
 	Webhook = "webhook"
+
+	// InMemoryController is the name of the override key for the in-memory provisioner controller.
+	InMemoryController = "in-memory-channel-controller"
+
+	// InMemoryDispatcher is the name of the override key for the in-memory dispatcher.
+	InMemoryDispatcher = "in-memory-channel-dispatcher"
+
+	// KafkaController is the name of the override key for the Kafka provisioner controller.
+	KafkaController = "kafka-channel-controller"
+
+	// KafkaDispatcher is the name of the override key for the Kafka dispatcher.
+	KafkaDispatcher = "kafka-channel-dispatcher"
+
+	// GcpPubSubController is the name of the override key for the GCP PubSub provisioner controller.
+	GcpPubSubController = "gcp-pubsub-channel-controller"
+
+	// GcpPubSubDispatcher is the name of the override key for the GCP PubSub dispatcher.
+	GcpPubSubDispatcher = "gcp-pubsub-channel-dispatcher"
+
+	// NatssController is the name of the override key for the NATSS provisioner controller.
+	NatssController = "natss-controller"
+
+	// NatssDispatcher is the name of the override key for the NATSS dispatcher.
+	NatssDispatcher = "natss-dispatcher"
 )
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logconfig
+
+import (
+	"github.com/knative/pkg/configmap"
+	"github.com/knative/pkg/logging"
+	"github.com/knative/pkg/logging/logkey"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NewLogger creates the logger of a component from the config-logging ConfigMap in namespace.
+// The level of the logger follows the loglevel.<component> key of the ConfigMap until stopCh
+// is closed, so it can be changed without restarting the component. If the ConfigMap can not
+// be read, an Info level production logger is used until the ConfigMap is created.
+func NewLogger(kc kubernetes.Interface, namespace, component string, stopCh <-chan struct{}) *zap.SugaredLogger {
+	cm, err := kc.CoreV1().ConfigMaps(namespace).Get(ConfigName, metav1.GetOptions{})
+	if err != nil {
+		cm = nil
+	}
+	logger, atomicLevel, configErr := newLoggerFromConfigMap(cm, component)
+	if err == nil {
+		err = configErr
+	}
+	if err != nil {
+		logger.Warnw("Unable to read the logging config map, using the default configuration", zap.Error(err))
+	}
+
+	cmw := configmap.NewInformedWatcher(kc, namespace)
+	cmw.Watch(ConfigName, logging.UpdateLevelFromConfigMap(logger, atomicLevel, component, component))
+	if err := cmw.Start(stopCh); err != nil {
+		logger.Warnw("The log level is not updated until the logging config map is created", zap.Error(err))
+	}
+	return logger
+}
+
+// newLoggerFromConfigMap creates the logger of a component from cm. The default configuration
+// is used if cm is nil or invalid.
+func newLoggerFromConfigMap(cm *corev1.ConfigMap, component string) (*zap.SugaredLogger, zap.AtomicLevel, error) {
+	config := &logging.Config{}
+	var err error
+	if cm != nil {
+		if config, err = logging.NewConfigFromConfigMap(cm, component); err != nil {
+			config = &logging.Config{}
+		}
+	}
+	logger, atomicLevel := logging.NewLoggerFromConfig(config, component)
+	return logger.With(zap.String(logkey.ControllerType, component)), atomicLevel, err
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logconfig
+
+import (
+	"testing"
+
+	"github.com/knative/pkg/logging"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testComponent = "test-component"
+)
+
+func makeConfigMap(level string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      ConfigName,
+		},
+		Data: map[string]string{
+			"zap-logger-config":         `{"level": "info", "outputPaths": ["stdout"], "errorOutputPaths": ["stderr"], "encoding": "json"}`,
+			"loglevel." + testComponent: level,
+		},
+	}
+}
+
+func TestNewLoggerFromConfigMap(t *testing.T) {
+	testCases := map[string]struct {
+		cm        *corev1.ConfigMap
+		wantErr   bool
+		wantDebug bool
+	}{
+		"no config map": {},
+		"debug": {
+			cm:        makeConfigMap("debug"),
+			wantDebug: true,
+		},
+		"info": {
+			cm: makeConfigMap("info"),
+		},
+		"invalid level": {
+			cm:      makeConfigMap("verbose"),
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			logger, _, err := newLoggerFromConfigMap(tc.cm, testComponent)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			core := logger.Desugar().Core()
+			if !core.Enabled(zap.InfoLevel) {
+				t.Errorf("Expected Info to be enabled")
+			}
+			if debug := core.Enabled(zap.DebugLevel); debug != tc.wantDebug {
+				t.Errorf("Unexpected Debug. Expected %v. Actual %v", tc.wantDebug, debug)
+			}
+		})
+	}
+}
+
+func TestNewLoggerFromConfigMap_Reload(t *testing.T) {
+	logger, atomicLevel, err := newLoggerFromConfigMap(makeConfigMap("debug"), testComponent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	update := logging.UpdateLevelFromConfigMap(logger, atomicLevel, testComponent, testComponent)
+
+	update(makeConfigMap("error"))
+	if logger.Desugar().Core().Enabled(zap.WarnLevel) {
+		t.Errorf("Expected the level to be updated to Error")
+	}
+	update(makeConfigMap("debug"))
+	if !logger.Desugar().Core().Enabled(zap.DebugLevel) {
+		t.Errorf("Expected the level to be updated to Debug")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacequota
+
+import (
+	"encoding/json"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	yaml "gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap, in the namespace of the webhook, that contains
+	// the quotas of the namespaces. It is not in the namespaces themselves, so that their users
+	// cannot raise their own quotas.
+	ConfigMapName = "config-namespace-quotas"
+
+	// quotasKey is the key in the ConfigMap of the serialized Config.
+	quotasKey = "quotas"
+
+	// defaultCapacity is the capacity of the Channels whose provisioner has no capacity argument,
+	// or that do not set it.
+	defaultCapacity = 1
+)
+
+// Quota limits the eventing resources of a namespace. Nil fields are not limited.
+type Quota struct {
+	// Channels is the largest number of Channels in the namespace.
+	Channels *int64 `yaml:"channels,omitempty"`
+	// Subscriptions is the largest number of Subscriptions in the namespace.
+	Subscriptions *int64 `yaml:"subscriptions,omitempty"`
+	// Capacity is the largest total capacity of the Channels in the namespace, such as the number
+	// of partitions of their Kafka topics. See Config.CapacityArguments.
+	Capacity *int64 `yaml:"capacity,omitempty"`
+}
+
+// Config is the data structure serialized to YAML in the ConfigMap.
+type Config struct {
+	// ClusterDefault is the quota of the namespaces. Its fields are overridden by those set in the
+	// quota of a namespace in NamespaceQuotas.
+	ClusterDefault *Quota `yaml:"clusterDefault,omitempty"`
+	// NamespaceQuotas are the quotas of the namespaces, by name.
+	NamespaceQuotas map[string]*Quota `yaml:"namespaceQuotas,omitempty"`
+	// CapacityArguments are the numeric arguments of the Channels of each ClusterChannelProvisioner,
+	// by name, counted as their capacity. The capacity of the Channels of the other provisioners,
+	// or that do not set the argument, is 1.
+	CapacityArguments map[string]string `yaml:"capacityArguments,omitempty"`
+}
+
+// NewConfigFromConfigMap parses the Config of cm.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict([]byte(cm.Data[quotasKey]), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// quota returns the quota of namespace.
+func (c *Config) quota(namespace string) Quota {
+	var q Quota
+	if c.ClusterDefault != nil {
+		q = *c.ClusterDefault
+	}
+	if nq := c.NamespaceQuotas[namespace]; nq != nil {
+		if nq.Channels != nil {
+			q.Channels = nq.Channels
+		}
+		if nq.Subscriptions != nil {
+			q.Subscriptions = nq.Subscriptions
+		}
+		if nq.Capacity != nil {
+			q.Capacity = nq.Capacity
+		}
+	}
+	return q
+}
+
+// capacity returns the capacity of channel, the value of the capacity argument of its provisioner.
+func (c *Config) capacity(channel *v1alpha1.Channel) int64 {
+	if channel.Spec.Provisioner == nil || channel.Spec.Arguments == nil {
+		return defaultCapacity
+	}
+	arg, ok := c.CapacityArguments[channel.Spec.Provisioner.Name]
+	if !ok {
+		return defaultCapacity
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(channel.Spec.Arguments.Raw, &args); err != nil {
+		return defaultCapacity
+	}
+	if v, ok := args[arg].(float64); ok && v > 0 {
+		return int64(v)
+	}
+	return defaultCapacity
+}
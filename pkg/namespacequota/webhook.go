@@ -0,0 +1,220 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package namespacequota implements a validating admission webhook limiting the number of Channels
+// and Subscriptions, and the total capacity of the Channels, of each namespace, so that a tenant
+// cannot exhaust the dispatchers shared by the namespaces of a cluster.
+package namespacequota
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/knative/eventing/pkg/admission"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	listers "github.com/knative/eventing/pkg/client/listers/eventing/v1alpha1"
+)
+
+// Webhook is a validating admission webhook rejecting the Channels and Subscriptions that would
+// exceed the quota of their namespace. The objects of the namespace are counted from the caches of
+// the listers, so objects created concurrently may exceed the quota briefly. Nothing is limited
+// until the quotas are configured with UpdateConfigMap.
+type Webhook struct {
+	// Client registers the Webhook.
+	Client        kubernetes.Interface
+	Channels      listers.ChannelLister
+	Subscriptions listers.SubscriptionLister
+	Options       admission.Options
+	Logger        *zap.Logger
+
+	// config holds the current *Config.
+	config atomic.Value
+}
+
+// UpdateConfigMap replaces the quotas with those of cm, the ConfigMapName ConfigMap. Invalid
+// quotas are logged and ignored.
+func (wh *Webhook) UpdateConfigMap(cm *corev1.ConfigMap) {
+	config, err := NewConfigFromConfigMap(cm)
+	if err != nil {
+		wh.Logger.Error("Invalid namespace quotas, keeping the previous ones", zap.Error(err))
+		return
+	}
+	wh.Logger.Info("Updated the namespace quotas", zap.Any("config", config))
+	wh.config.Store(config)
+}
+
+func (wh *Webhook) getConfig() *Config {
+	if config, ok := wh.config.Load().(*Config); ok {
+		return config
+	}
+	return &Config{}
+}
+
+// Run registers the Webhook and serves admission requests until stop is closed.
+func (wh *Webhook) Run(stop <-chan struct{}) error {
+	return admission.Serve(wh.Logger, wh.Options, wh.register, wh, stop)
+}
+
+// register registers the Webhook as a validating webhook of the Channels and Subscriptions,
+// trusting caCert.
+func (wh *Webhook) register(caCert []byte) error {
+	return admission.RegisterValidating(wh.Client, wh.Options, []admissionregistrationv1beta1.RuleWithOperations{{
+		Operations: []admissionregistrationv1beta1.OperationType{
+			admissionregistrationv1beta1.Create,
+			admissionregistrationv1beta1.Update,
+		},
+		Rule: admissionregistrationv1beta1.Rule{
+			APIGroups:   []string{v1alpha1.SchemeGroupVersion.Group},
+			APIVersions: []string{v1alpha1.SchemeGroupVersion.Version, v1beta1.SchemeGroupVersion.Version},
+			Resources:   []string{"channels", "subscriptions"},
+		},
+	}}, caCert)
+}
+
+// ServeHTTP implements the admission webhook.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admission.Handler(wh.admit).ServeHTTP(w, r)
+}
+
+// admit rejects the object in request if it would exceed the quota of its namespace. Only the
+// creation of Channels and Subscriptions, and the updates raising the capacity of Channels, are
+// checked, so that namespaces over their quota can still update and delete their objects.
+func (wh *Webhook) admit(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	allowed := &admissionv1beta1.AdmissionResponse{Allowed: true}
+	if request.Operation != admissionv1beta1.Create && request.Operation != admissionv1beta1.Update {
+		return allowed
+	}
+	config := wh.getConfig()
+	quota := config.quota(request.Namespace)
+
+	var err error
+	switch request.Kind.Kind {
+	case "Channel":
+		if quota.Channels == nil && quota.Capacity == nil {
+			return allowed
+		}
+		var c, old *v1alpha1.Channel
+		if c, err = decodeChannel(request.Kind.Version, request.Object.Raw); err != nil {
+			wh.Logger.Error("Failed to decode the Channel", zap.Any("kind", request.Kind), zap.Error(err))
+			return allowed
+		}
+		if request.Operation == admissionv1beta1.Update {
+			if old, err = decodeChannel(request.Kind.Version, request.OldObject.Raw); err != nil {
+				wh.Logger.Error("Failed to decode the old Channel", zap.Any("kind", request.Kind), zap.Error(err))
+				return allowed
+			}
+		}
+		err = wh.checkChannel(config, quota, request.Namespace, c, old)
+	case "Subscription":
+		if quota.Subscriptions == nil || request.Operation != admissionv1beta1.Create {
+			return allowed
+		}
+		err = wh.checkSubscription(quota, request.Namespace, request.Name)
+	default:
+		return allowed
+	}
+
+	if err != nil {
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+				Reason:  metav1.StatusReasonForbidden,
+				Code:    http.StatusForbidden,
+			},
+		}
+	}
+	return allowed
+}
+
+// checkChannel returns an error if creating c in namespace, or updating old to c, exceeds quota.
+func (wh *Webhook) checkChannel(config *Config, quota Quota, namespace string, c, old *v1alpha1.Channel) error {
+	if c.DeletionTimestamp != nil {
+		return nil
+	}
+	capacity := config.capacity(c)
+	if old != nil && capacity <= config.capacity(old) {
+		return nil
+	}
+	channels, err := wh.Channels.Channels(namespace).List(labels.Everything())
+	if err != nil {
+		wh.Logger.Warn("Failed to list the Channels", zap.String("namespace", namespace), zap.Error(err))
+		return nil
+	}
+	var count int64 = 1
+	for _, existing := range channels {
+		if existing.Name == c.Name {
+			continue
+		}
+		count++
+		capacity += config.capacity(existing)
+	}
+	if old == nil && quota.Channels != nil && count > *quota.Channels {
+		return fmt.Errorf("exceeded quota of namespace %q: at most %d Channels are allowed", namespace, *quota.Channels)
+	}
+	if quota.Capacity != nil && capacity > *quota.Capacity {
+		return fmt.Errorf("exceeded quota of namespace %q: the Channels would have a capacity of %d, at most %d is allowed", namespace, capacity, *quota.Capacity)
+	}
+	return nil
+}
+
+// checkSubscription returns an error if creating the Subscription name in namespace exceeds quota.
+func (wh *Webhook) checkSubscription(quota Quota, namespace, name string) error {
+	subscriptions, err := wh.Subscriptions.Subscriptions(namespace).List(labels.Everything())
+	if err != nil {
+		wh.Logger.Warn("Failed to list the Subscriptions", zap.String("namespace", namespace), zap.Error(err))
+		return nil
+	}
+	var count int64 = 1
+	for _, existing := range subscriptions {
+		if existing.Name != name {
+			count++
+		}
+	}
+	if count > *quota.Subscriptions {
+		return fmt.Errorf("exceeded quota of namespace %q: at most %d Subscriptions are allowed", namespace, *quota.Subscriptions)
+	}
+	return nil
+}
+
+// decodeChannel decodes the Channel raw of version, as a v1alpha1 Channel.
+func decodeChannel(version string, raw []byte) (*v1alpha1.Channel, error) {
+	c := &v1alpha1.Channel{}
+	switch version {
+	case v1alpha1.SchemeGroupVersion.Version:
+		return c, json.Unmarshal(raw, c)
+	case v1beta1.SchemeGroupVersion.Version:
+		beta := &v1beta1.Channel{}
+		if err := json.Unmarshal(raw, beta); err != nil {
+			return nil, err
+		}
+		return c, beta.ConvertTo(c)
+	default:
+		return nil, fmt.Errorf("unsupported version %q", version)
+	}
+}
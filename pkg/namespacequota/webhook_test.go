@@ -0,0 +1,219 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacequota
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	listers "github.com/knative/eventing/pkg/client/listers/eventing/v1alpha1"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	testNS  = "testnamespace"
+	otherNS = "othernamespace"
+
+	testQuotas = `
+clusterDefault:
+  channels: 2
+  subscriptions: 1
+namespaceQuotas:
+  testnamespace:
+    channels: 3
+    capacity: 6
+capacityArguments:
+  kafka: NumPartitions
+`
+)
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation admissionv1beta1.Operation
+		kind      string
+		namespace string
+		object    runtime.Object
+		oldObject runtime.Object
+		wantError string
+	}{{
+		name:      "channel within quota",
+		operation: admissionv1beta1.Create,
+		kind:      "Channel",
+		object:    channel(testNS, "new", 2),
+	}, {
+		name:      "too many channels",
+		operation: admissionv1beta1.Create,
+		kind:      "Channel",
+		object:    channel(otherNS, "new", 0),
+		namespace: otherNS,
+		wantError: `exceeded quota of namespace "othernamespace": at most 2 Channels are allowed`,
+	}, {
+		name:      "capacity exceeded",
+		operation: admissionv1beta1.Create,
+		kind:      "Channel",
+		object:    channel(testNS, "new", 3),
+		wantError: `the Channels would have a capacity of 7, at most 6 is allowed`,
+	}, {
+		name:      "capacity raised",
+		operation: admissionv1beta1.Update,
+		kind:      "Channel",
+		object:    channel(testNS, "kafka", 6),
+		oldObject: channel(testNS, "kafka", 3),
+		wantError: `the Channels would have a capacity of 7, at most 6 is allowed`,
+	}, {
+		name:      "capacity lowered",
+		operation: admissionv1beta1.Update,
+		kind:      "Channel",
+		object:    channel(otherNS, "kafka", 1),
+		oldObject: channel(otherNS, "kafka", 3),
+		namespace: otherNS,
+	}, {
+		name:      "too many subscriptions",
+		operation: admissionv1beta1.Create,
+		kind:      "Subscription",
+		object:    subscription(testNS, "new"),
+		wantError: `exceeded quota of namespace "testnamespace": at most 1 Subscriptions are allowed`,
+	}, {
+		name:      "subscription updated",
+		operation: admissionv1beta1.Update,
+		kind:      "Subscription",
+		object:    subscription(testNS, "sub"),
+		oldObject: subscription(testNS, "sub"),
+	}, {
+		name:      "delete",
+		operation: admissionv1beta1.Delete,
+		kind:      "Channel",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			channels := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			subscriptions := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, c := range []*v1alpha1.Channel{
+				channel(testNS, "kafka", 3),
+				channel(testNS, "in-memory", 0),
+				channel(otherNS, "kafka", 3),
+				channel(otherNS, "in-memory", 0),
+			} {
+				channels.Add(c)
+			}
+			subscriptions.Add(subscription(testNS, "sub"))
+			wh := &Webhook{
+				Channels:      listers.NewChannelLister(channels),
+				Subscriptions: listers.NewSubscriptionLister(subscriptions),
+				Logger:        zap.NewNop(),
+			}
+			wh.UpdateConfigMap(&corev1.ConfigMap{Data: map[string]string{quotasKey: testQuotas}})
+
+			namespace := test.namespace
+			if namespace == "" {
+				namespace = testNS
+			}
+			request := &admissionv1beta1.AdmissionRequest{
+				UID:       types.UID("test-uid"),
+				Kind:      metav1.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1alpha1", Kind: test.kind},
+				Namespace: namespace,
+				Operation: test.operation,
+			}
+			if test.object != nil {
+				raw, err := json.Marshal(test.object)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.Object.Raw = raw
+				request.Name = test.object.(metav1.Object).GetName()
+			}
+			if test.oldObject != nil {
+				raw, err := json.Marshal(test.oldObject)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.OldObject.Raw = raw
+			}
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: request})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			wh.ServeHTTP(rec, req)
+
+			var review admissionv1beta1.AdmissionReview
+			if err := json.NewDecoder(rec.Body).Decode(&review); err != nil {
+				t.Fatalf("could not decode the response: %v", err)
+			}
+			response := review.Response
+			if response.UID != request.UID {
+				t.Errorf("unexpected UID: want %q, got %q", request.UID, response.UID)
+			}
+			if test.wantError == "" {
+				if !response.Allowed {
+					t.Errorf("the object was not admitted: %v", response.Result.Message)
+				}
+				return
+			}
+			if response.Allowed {
+				t.Fatal("the object was admitted")
+			}
+			if !strings.Contains(response.Result.Message, test.wantError) {
+				t.Errorf("unexpected error: want %q, got %q", test.wantError, response.Result.Message)
+			}
+		})
+	}
+}
+
+func TestUpdateConfigMapInvalid(t *testing.T) {
+	wh := &Webhook{Logger: zap.NewNop()}
+	wh.UpdateConfigMap(&corev1.ConfigMap{Data: map[string]string{quotasKey: testQuotas}})
+	wh.UpdateConfigMap(&corev1.ConfigMap{Data: map[string]string{quotasKey: "clusterDefault:\n  chanels: 1\n"}})
+	if q := wh.getConfig().quota(otherNS); q.Channels == nil || *q.Channels != 2 {
+		t.Errorf("Expected the invalid quotas to keep the previous ones, got %+v", q)
+	}
+}
+
+// channel returns a Channel of the kafka provisioner with partitions partitions, or of the
+// in-memory-channel provisioner if partitions is 0.
+func channel(namespace, name string, partitions int) *v1alpha1.Channel {
+	c := &v1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: v1alpha1.ChannelSpec{
+			Provisioner: &corev1.ObjectReference{Name: "in-memory-channel"},
+		},
+	}
+	if partitions > 0 {
+		c.Spec.Provisioner.Name = "kafka"
+		raw, _ := json.Marshal(map[string]int{"NumPartitions": partitions})
+		c.Spec.Arguments = &runtime.RawExtension{Raw: raw}
+	}
+	return c
+}
+
+func subscription(namespace, name string) *v1alpha1.Subscription {
+	return &v1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"net/url"
+	"time"
+)
+
+// AuditEventType is the CloudEvents type of the audit records sent to an audit Channel. Deliveries
+// of events of this type are not audited, so that auditing the audit Channel does not loop.
+const AuditEventType = "dev.knative.eventing.delivery.audit"
+
+// The outcomes of a delivery attempt.
+const (
+	AuditDelivered = "delivered"
+	AuditFailed    = "failed"
+)
+
+// AuditRecord is the record of an attempt to deliver an event to a subscriber.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+
+	// EventID, Source and Type are the CloudEvents attributes of the event delivered.
+	EventID string `json:"eventID,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Type    string `json:"type,omitempty"`
+
+	// Channel and Subscription are the namespace/name of the Channel and of the Subscription the
+	// event is delivered for, if any. Subscriber is the URL the event is delivered to.
+	Channel      string `json:"channel,omitempty"`
+	Subscription string `json:"subscription,omitempty"`
+	Subscriber   string `json:"subscriber"`
+
+	// Outcome is either AuditDelivered or AuditFailed. StatusCode is the HTTP status code of the
+	// response to a failed attempt, if the subscriber responded.
+	Outcome    string `json:"outcome"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// LatencyMillis is the time the subscriber took to respond, in milliseconds.
+	LatencyMillis float64 `json:"latencyMs"`
+	Redelivery    bool    `json:"redelivery,omitempty"`
+}
+
+// AuditSink stores AuditRecords. Record is called for every delivery attempt of a
+// MessageDispatcher, so it must not block for long. Failing to store a record does not fail the
+// delivery.
+type AuditSink interface {
+	Record(AuditRecord)
+}
+
+// WithAuditSink makes the MessageDispatcher record every attempt to deliver a message to a
+// destination in sink.
+func WithAuditSink(sink AuditSink) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.audit = sink
+	}
+}
+
+// auditDelivery records the delivery of message to destination in the audit sink of the
+// dispatcher, if it has one.
+func (d *MessageDispatcher) auditDelivery(message *Message, destination *url.URL, defaults DispatchDefaults, start time.Time, latency time.Duration, err error) {
+	if d.audit == nil {
+		return
+	}
+	attrs := message.Attributes()
+	if attrs["type"] == AuditEventType {
+		return
+	}
+	r := AuditRecord{
+		Time:          start.UTC(),
+		EventID:       attrs["id"],
+		Source:        attrs["source"],
+		Type:          attrs["type"],
+		Channel:       defaults.Channel,
+		Subscription:  defaults.Subscription,
+		Subscriber:    destination.String(),
+		Outcome:       AuditDelivered,
+		LatencyMillis: float64(latency) / float64(time.Millisecond),
+		Redelivery:    defaults.Redelivery,
+	}
+	if err != nil {
+		r.Outcome = AuditFailed
+		r.Error = err.Error()
+		if re, ok := err.(*responseError); ok {
+			r.StatusCode = re.statusCode
+		}
+	}
+	d.audit.Record(r)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit provides the provisioners.AuditSinks dispatchers record their delivery attempts
+// in: JSON lines written to stdout or to a file, or CloudEvents sent to a dedicated audit Channel.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+const (
+	// SinkEnv enables the audit of the deliveries of a dispatcher. Its value is where the records
+	// are sent: "stdout", a file URL such as file:///var/log/audit.json, or the http(s) URL of
+	// the audit Channel.
+	SinkEnv = "AUDIT_SINK"
+
+	// auditEventSource is the CloudEvents source of the records sent to an audit Channel.
+	auditEventSource = "/apis/eventing.knative.dev/v1alpha1/dispatchers"
+
+	// channelSinkBufferSize is the number of records waiting to be sent to an audit Channel.
+	// Records are dropped when the buffer is full, rather than slowing deliveries down.
+	channelSinkBufferSize = 1000
+
+	channelSinkTimeout = 10 * time.Second
+)
+
+// FromEnv returns the AuditSink configured by the environment variables of the process, or nil if
+// deliveries are not audited. Records sent to an audit Channel stop being sent when stopCh is
+// closed.
+func FromEnv(logger *zap.Logger, stopCh <-chan struct{}) (provisioners.AuditSink, error) {
+	sink := os.Getenv(SinkEnv)
+	switch {
+	case sink == "":
+		return nil, nil
+	case sink == "stdout":
+		return NewWriterSink(os.Stdout, logger), nil
+	case strings.HasPrefix(sink, "file://"):
+		return NewFileSink(strings.TrimPrefix(sink, "file://"), logger)
+	case strings.HasPrefix(sink, "http://"), strings.HasPrefix(sink, "https://"):
+		return NewChannelSink(sink, logger, stopCh), nil
+	}
+	return nil, fmt.Errorf("invalid %s %q, it must be stdout, a file:// URL or an http(s):// URL", SinkEnv, sink)
+}
+
+// WriterSink writes every record as a line of JSON.
+type WriterSink struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	logger  *zap.Logger
+}
+
+var _ provisioners.AuditSink = (*WriterSink)(nil)
+
+// NewWriterSink creates a WriterSink writing to w.
+func NewWriterSink(w io.Writer, logger *zap.Logger) *WriterSink {
+	return &WriterSink{
+		encoder: json.NewEncoder(w),
+		logger:  logger,
+	}
+}
+
+// NewFileSink creates a WriterSink appending to the file at path, which is created if needed.
+func NewFileSink(path string, logger *zap.Logger) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the audit file: %v", err)
+	}
+	return NewWriterSink(f, logger), nil
+}
+
+// Record implements provisioners.AuditSink.
+func (s *WriterSink) Record(r provisioners.AuditRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.encoder.Encode(r); err != nil {
+		s.logger.Error("Unable to write the audit record", zap.Error(err), zap.String("eventID", r.EventID))
+	}
+}
+
+// ChannelSink sends every record as a CloudEvent of type provisioners.AuditEventType to a Channel.
+// Records are sent asynchronously, in the order they are recorded.
+type ChannelSink struct {
+	address    string
+	records    chan provisioners.AuditRecord
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+var _ provisioners.AuditSink = (*ChannelSink)(nil)
+
+// NewChannelSink creates a ChannelSink sending records to the Channel at address until stopCh is
+// closed.
+func NewChannelSink(address string, logger *zap.Logger, stopCh <-chan struct{}) *ChannelSink {
+	s := &ChannelSink{
+		address:    address,
+		records:    make(chan provisioners.AuditRecord, channelSinkBufferSize),
+		httpClient: &http.Client{Timeout: channelSinkTimeout},
+		logger:     logger,
+	}
+	go s.run(stopCh)
+	return s
+}
+
+// Record implements provisioners.AuditSink.
+func (s *ChannelSink) Record(r provisioners.AuditRecord) {
+	select {
+	case s.records <- r:
+	default:
+		s.logger.Warn("Dropping the audit record, the audit channel is not keeping up", zap.String("eventID", r.EventID))
+	}
+}
+
+func (s *ChannelSink) run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case r := <-s.records:
+			if err := s.send(r); err != nil {
+				s.logger.Error("Unable to send the audit record", zap.Error(err), zap.String("eventID", r.EventID))
+			}
+		}
+	}
+}
+
+func (s *ChannelSink) send(r provisioners.AuditRecord) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", uuid.New().String())
+	req.Header.Set("Ce-Type", provisioners.AuditEventType)
+	req.Header.Set("Ce-Source", auditEventSource)
+	req.Header.Set("Ce-Time", r.Time.Format(time.RFC3339Nano))
+	if r.Channel != "" {
+		req.Header.Set("Ce-Subject", r.Channel)
+	}
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected HTTP response, expected 2xx, got %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var testRecord = provisioners.AuditRecord{
+	Time:          time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+	EventID:       "event-1",
+	Source:        "/test",
+	Type:          "dev.knative.test",
+	Channel:       "test-ns/channel",
+	Subscription:  "test-ns/sub",
+	Subscriber:    "http://subscriber.test-ns.svc.cluster.local/",
+	Outcome:       provisioners.AuditDelivered,
+	LatencyMillis: 12.5,
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWriterSink(&buf, zap.NewNop())
+	s.Record(testRecord)
+	s.Record(testRecord)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected number of lines. Expected 2. Actual %d", len(lines))
+	}
+	var got provisioners.AuditRecord
+	if err := json.Unmarshal(lines[0], &got); err != nil {
+		t.Fatalf("Unable to unmarshal the record: %v", err)
+	}
+	if diff := cmp.Diff(testRecord, got); diff != "" {
+		t.Errorf("Unexpected record (-want +got): %s", diff)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.json")
+
+	for i := 0; i < 2; i++ {
+		s, err := NewFileSink(path, zap.NewNop())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		s.Record(testRecord)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read the audit file: %v", err)
+	}
+	if lines := bytes.Count(b, []byte("\n")); lines != 2 {
+		t.Errorf("Expected the records to be appended. Expected 2 lines. Actual %d", lines)
+	}
+}
+
+func TestChannelSink(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		requests <- r
+		bodies <- b
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	s := NewChannelSink(server.URL, zap.NewNop(), stopCh)
+	s.Record(testRecord)
+
+	select {
+	case r := <-requests:
+		if got := r.Header.Get("Ce-Type"); got != provisioners.AuditEventType {
+			t.Errorf("Unexpected type. Expected %q. Actual %q", provisioners.AuditEventType, got)
+		}
+		if got := r.Header.Get("Ce-Subject"); got != testRecord.Channel {
+			t.Errorf("Unexpected subject. Expected %q. Actual %q", testRecord.Channel, got)
+		}
+		if r.Header.Get("Ce-Id") == "" {
+			t.Errorf("Expected the event to have an id")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("The record was not sent to the channel")
+	}
+	var got provisioners.AuditRecord
+	if err := json.Unmarshal(<-bodies, &got); err != nil {
+		t.Fatalf("Unable to unmarshal the record: %v", err)
+	}
+	if diff := cmp.Diff(testRecord, got); diff != "" {
+		t.Errorf("Unexpected record (-want +got): %s", diff)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := map[string]struct {
+		sink     string
+		wantNil  bool
+		wantType interface{}
+		wantErr  bool
+	}{
+		"not set": {
+			wantNil: true,
+		},
+		"stdout": {
+			sink:     "stdout",
+			wantType: &WriterSink{},
+		},
+		"file": {
+			sink:     "file://" + filepath.Join(dir, "audit.json"),
+			wantType: &WriterSink{},
+		},
+		"channel": {
+			sink:     "http://audit-channel.test-ns.svc.cluster.local/",
+			wantType: &ChannelSink{},
+		},
+		"invalid": {
+			sink:    "kafka://audit",
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(SinkEnv, tc.sink)
+			defer os.Unsetenv(SinkEnv)
+
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			sink, err := FromEnv(zap.NewNop(), stopCh)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if tc.wantNil {
+				if sink != nil {
+					t.Errorf("Expected no sink. Actual %T", sink)
+				}
+				return
+			}
+			if got, want := fmt.Sprintf("%T", sink), fmt.Sprintf("%T", tc.wantType); got != want {
+				t.Errorf("Unexpected sink. Expected %s. Actual %s", want, got)
+			}
+		})
+	}
+}
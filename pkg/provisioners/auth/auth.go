@@ -0,0 +1,311 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth builds provisioners.Authenticators from the SubscriberAuth of a subscription. The
+// credentials are read from Secrets in the subscription's namespace when a delivery is made, so
+// rotated credentials are picked up without reconfiguring the dispatcher.
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SecretLabel labels the Secrets holding the credentials of subscribers. The dispatchers only
+	// send the values of the Secrets whose label is "true", so that a Secret is never sent to a
+	// subscriber unless it was meant to be.
+	SecretLabel = "eventing.knative.dev/subscriber-auth"
+
+	// secretTTL is how long a Secret that was read is reused before it is read again.
+	secretTTL = 1 * time.Minute
+)
+
+// errUnresolvable is the error of the deliveries to subscribers whose credentials cannot be
+// resolved, which are failed rather than sent without credentials.
+var errUnresolvable = errors.New("the credentials of the subscriber cannot be resolved by this dispatcher")
+
+// SecretGetter reads a Secret.
+type SecretGetter func(namespace, name string) (*corev1.Secret, error)
+
+// KubeSecretGetter returns a SecretGetter that reads Secrets from the Kubernetes API.
+func KubeSecretGetter(kc kubernetes.Interface) SecretGetter {
+	return func(namespace, name string) (*corev1.Secret, error) {
+		return kc.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	}
+}
+
+// Resolver creates Authenticators. It caches the Secrets it reads and the access tokens it
+// obtains, so it should be shared by all the deliveries of a dispatcher.
+type Resolver struct {
+	getSecret  SecretGetter
+	httpClient *http.Client
+	now        func() time.Time
+
+	lock         sync.Mutex
+	secrets      map[string]cachedSecret
+	tokenSources map[string]oauth2.TokenSource
+	tlsConfigs   map[string]*tls.Config
+}
+
+type cachedSecret struct {
+	secret  *corev1.Secret
+	fetched time.Time
+}
+
+// NewResolver creates a Resolver that reads Secrets using getSecret.
+func NewResolver(getSecret SecretGetter) *Resolver {
+	return &Resolver{
+		getSecret:    getSecret,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		now:          time.Now,
+		secrets:      make(map[string]cachedSecret),
+		tokenSources: make(map[string]oauth2.TokenSource),
+		tlsConfigs:   make(map[string]*tls.Config),
+	}
+}
+
+// Authenticator returns an Authenticator for a subscriber in namespace. It returns nil if a is
+// nil, meaning that no credentials are needed. If r is nil or namespace is empty, the credentials
+// cannot be resolved, and the returned Authenticator fails every delivery.
+func (r *Resolver) Authenticator(namespace string, a *eventingduck.SubscriberAuth) *Authenticator {
+	if a == nil {
+		return nil
+	}
+	if r == nil || namespace == "" {
+		return &Authenticator{err: errUnresolvable}
+	}
+	return &Authenticator{
+		resolver:  r,
+		namespace: namespace,
+		auth:      *a,
+	}
+}
+
+// Authenticator adds the credentials described by a SubscriberAuth to requests. It implements
+// provisioners.TLSAuthenticator.
+type Authenticator struct {
+	resolver  *Resolver
+	namespace string
+	auth      eventingduck.SubscriberAuth
+	// err fails every delivery, as the credentials cannot be resolved.
+	err error
+}
+
+// Authenticate sets the Authorization header of req.
+func (a *Authenticator) Authenticate(req *http.Request) error {
+	if a.err != nil {
+		return a.err
+	}
+	switch {
+	case a.auth.BearerToken != nil:
+		token, err := a.resolver.secretValue(a.namespace, *a.auth.BearerToken)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case a.auth.Basic != nil:
+		user, err := a.resolver.secretValue(a.namespace, a.auth.Basic.Username)
+		if err != nil {
+			return err
+		}
+		password, err := a.resolver.secretValue(a.namespace, a.auth.Basic.Password)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(user, password)
+	case a.auth.OIDC != nil:
+		token, err := a.resolver.accessToken(a.namespace, *a.auth.OIDC)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case a.auth.ClientCertificate != nil:
+		// The credentials are presented when the connection is established.
+	default:
+		return fmt.Errorf("no credentials configured")
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS config presenting the client certificate of the subscriber, or nil if
+// it has none.
+func (a *Authenticator) TLSConfig() *tls.Config {
+	if a.err != nil || a.auth.ClientCertificate == nil {
+		return nil
+	}
+	return a.resolver.tlsConfig(a.namespace, a.auth.ClientCertificate.SecretName)
+}
+
+// tlsConfig returns the TLS config of the client certificate in the named Secret. There is a
+// single config per Secret, so that the dispatcher reuses its connections.
+func (r *Resolver) tlsConfig(namespace, name string) *tls.Config {
+	key := namespace + "/" + name
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if c, ok := r.tlsConfigs[key]; ok {
+		return c
+	}
+	c := newTLSConfig(func() (map[string][]byte, error) {
+		secret, err := r.secret(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return secret.Data, nil
+	})
+	r.tlsConfigs[key] = c
+	return c
+}
+
+// newTLSConfig creates a TLS config presenting the client certificate in the tls.crt and tls.key
+// returned by load, and verifying the server against its ca.crt if there is one. load is called
+// during every handshake, rather than when the config is created, so that the same config can be
+// used while the certificate is rotated.
+func newTLSConfig(load func() (map[string][]byte, error)) *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			data, err := load()
+			if err != nil {
+				return nil, err
+			}
+			cert, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate: %v", err)
+			}
+			return &cert, nil
+		},
+		// The certificate authorities may be rotated too, so the certificate of the server is
+		// verified by VerifyConnection instead.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			data, err := load()
+			if err != nil {
+				return err
+			}
+			return verifyServer(cs, data[corev1.ServiceAccountRootCAKey])
+		},
+	}
+}
+
+// verifyServer verifies the certificate of the server of cs against the PEM encoded certificate
+// authorities in ca, or against the system roots if ca is empty.
+func verifyServer(cs tls.ConnectionState, ca []byte) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("the server did not present a certificate")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	if len(ca) > 0 {
+		opts.Roots = x509.NewCertPool()
+		if !opts.Roots.AppendCertsFromPEM(ca) {
+			return fmt.Errorf("no valid certificate authority in %s", corev1.ServiceAccountRootCAKey)
+		}
+	}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func (r *Resolver) secretValue(namespace string, s corev1.SecretKeySelector) (string, error) {
+	secret, err := r.secret(namespace, s.Name)
+	if err != nil {
+		return "", err
+	}
+	v, ok := secret.Data[s.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s does not contain key %q", namespace, s.Name, s.Key)
+	}
+	return strings.TrimSpace(string(v)), nil
+}
+
+// secret returns the named Secret, reading it again if the cached one is older than secretTTL. It
+// fails if the Secret is not labelled with SecretLabel.
+func (r *Resolver) secret(namespace, name string) (*corev1.Secret, error) {
+	key := namespace + "/" + name
+	now := r.now()
+
+	r.lock.Lock()
+	cached, ok := r.secrets[key]
+	r.lock.Unlock()
+
+	if !ok || now.Sub(cached.fetched) > secretTTL {
+		secret, err := r.getSecret(namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret %s: %v", key, err)
+		}
+		cached = cachedSecret{secret: secret, fetched: now}
+		r.lock.Lock()
+		r.secrets[key] = cached
+		r.lock.Unlock()
+	}
+	if cached.secret.Labels[SecretLabel] != "true" {
+		return nil, fmt.Errorf("secret %s is not labelled %s=true", key, SecretLabel)
+	}
+	return cached.secret, nil
+}
+
+// accessToken returns the access token of the client, obtained with the client credentials flow.
+// The token is reused until it is about to expire.
+func (r *Resolver) accessToken(namespace string, c eventingduck.OIDCClientCredentials) (string, error) {
+	clientID, err := r.secretValue(namespace, c.ClientID)
+	if err != nil {
+		return "", err
+	}
+	clientSecret, err := r.secretValue(namespace, c.ClientSecret)
+	if err != nil {
+		return "", err
+	}
+
+	// The secret is part of the key so that a rotated secret is used right away.
+	key := strings.Join([]string{c.TokenURL, clientID, clientSecret, strings.Join(c.Scopes, " ")}, "\n")
+	r.lock.Lock()
+	ts, ok := r.tokenSources[key]
+	if !ok {
+		config := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     c.TokenURL,
+			Scopes:       c.Scopes,
+		}
+		ts = config.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, r.httpClient))
+		r.tokenSources[key] = ts
+	}
+	r.lock.Unlock()
+
+	token, err := ts.Token()
+	if err != nil {
+		return "", fmt.Errorf("unable to obtain an access token: %v", err)
+	}
+	return token.AccessToken, nil
+}
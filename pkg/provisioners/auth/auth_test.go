@@ -0,0 +1,456 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNS     = "test-namespace"
+	secretName = "subscriber-credentials"
+	// unlabelledSecretName is served without SecretLabel.
+	unlabelledSecretName = "unlabelled-credentials"
+)
+
+var secretLabels = map[string]string{SecretLabel: "true"}
+
+func selector(key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+		Key:                  key,
+	}
+}
+
+func credentialsSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      secretName,
+			Labels:    secretLabels,
+		},
+		Data: map[string][]byte{
+			"token":        []byte("s3cr3t\n"),
+			"username":     []byte("user"),
+			"password":     []byte("pass"),
+			"clientID":     []byte("my-client"),
+			"clientSecret": []byte("my-secret"),
+		},
+	}
+}
+
+// getSecret serves credentialsSecret, and a copy of it without SecretLabel.
+func getSecret(namespace, name string) (*corev1.Secret, error) {
+	s := credentialsSecret()
+	if name == unlabelledSecretName {
+		s.Name, s.Labels = unlabelledSecretName, nil
+	}
+	if namespace != s.Namespace || name != s.Name {
+		return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+	}
+	return s, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	testCases := map[string]struct {
+		auth       *eventingduck.SubscriberAuth
+		wantHeader string
+		wantErr    bool
+	}{
+		"bearer token": {
+			auth: &eventingduck.SubscriberAuth{
+				BearerToken: &[]corev1.SecretKeySelector{selector("token")}[0],
+			},
+			wantHeader: "Bearer s3cr3t",
+		},
+		"basic": {
+			auth: &eventingduck.SubscriberAuth{
+				Basic: &eventingduck.BasicAuth{
+					Username: selector("username"),
+					Password: selector("password"),
+				},
+			},
+			wantHeader: "Basic dXNlcjpwYXNz",
+		},
+		"missing key": {
+			auth: &eventingduck.SubscriberAuth{
+				BearerToken: &[]corev1.SecretKeySelector{selector("nope")}[0],
+			},
+			wantErr: true,
+		},
+		"missing secret": {
+			auth: &eventingduck.SubscriberAuth{
+				BearerToken: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "nope"},
+					Key:                  "token",
+				},
+			},
+			wantErr: true,
+		},
+		"unlabelled secret": {
+			auth: &eventingduck.SubscriberAuth{
+				BearerToken: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: unlabelledSecretName},
+					Key:                  "token",
+				},
+			},
+			wantErr: true,
+		},
+		"nothing configured": {
+			auth:    &eventingduck.SubscriberAuth{},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			r := NewResolver(getSecret)
+			req := httptest.NewRequest(http.MethodPost, "http://subscriber/", nil)
+			err := r.Authenticator(testNS, tc.auth).Authenticate(req)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v, actual %v", tc.wantErr, err)
+			}
+			if got := req.Header.Get("Authorization"); got != tc.wantHeader {
+				t.Errorf("Unexpected Authorization header. Expected %q, actual %q", tc.wantHeader, got)
+			}
+		})
+	}
+}
+
+func TestNilAuthenticator(t *testing.T) {
+	r := NewResolver(nil)
+	if a := r.Authenticator(testNS, nil); a != nil {
+		t.Errorf("Expected a nil Authenticator, actual %v", a)
+	}
+}
+
+func TestUnresolvableAuthenticator(t *testing.T) {
+	a := &eventingduck.SubscriberAuth{BearerToken: &corev1.SecretKeySelector{}}
+	for n, authenticator := range map[string]*Authenticator{
+		"no resolver":  (*Resolver)(nil).Authenticator(testNS, a),
+		"no namespace": NewResolver(getSecret).Authenticator("", a),
+	} {
+		t.Run(n, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://subscriber/", nil)
+			if err := authenticator.Authenticate(req); err == nil {
+				t.Error("Expected the delivery to fail")
+			}
+			if got := req.Header.Get("Authorization"); got != "" {
+				t.Errorf("Unexpected Authorization header %q", got)
+			}
+			if authenticator.TLSConfig() != nil {
+				t.Error("Unexpected TLS config")
+			}
+		})
+	}
+}
+
+func TestAuthenticate_OIDC(t *testing.T) {
+	requests := 0
+	// The tokens expiring within the expiry delta of the token source are not reused.
+	expiresIn := 5
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "my-client" || secret != "my-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("scope") != "events.write" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "bearer", "expires_in": %d}`, requests, expiresIn)
+	}))
+	defer tokenServer.Close()
+
+	r := NewResolver(getSecret)
+	a := r.Authenticator(testNS, &eventingduck.SubscriberAuth{
+		OIDC: &eventingduck.OIDCClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     selector("clientID"),
+			ClientSecret: selector("clientSecret"),
+			Scopes:       []string{"events.write"},
+		},
+	})
+
+	authenticate := func() string {
+		req := httptest.NewRequest(http.MethodPost, "http://subscriber/", nil)
+		if err := a.Authenticate(req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	if got, want := authenticate(), "Bearer token-1"; got != want {
+		t.Errorf("Unexpected Authorization header. Expected %q, actual %q", want, got)
+	}
+	expiresIn = 60
+	if got, want := authenticate(), "Bearer token-2"; got != want {
+		t.Errorf("Unexpected Authorization header. Expected %q, actual %q", want, got)
+	}
+	// The token is cached until it is about to expire.
+	if got, want := authenticate(), "Bearer token-2"; got != want {
+		t.Errorf("Unexpected Authorization header. Expected %q, actual %q", want, got)
+	}
+}
+
+func TestAuthenticate_OIDCRejected(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer tokenServer.Close()
+
+	r := NewResolver(getSecret)
+	a := r.Authenticator(testNS, &eventingduck.SubscriberAuth{
+		OIDC: &eventingduck.OIDCClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     selector("clientID"),
+			ClientSecret: selector("clientSecret"),
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "http://subscriber/", nil)
+	if err := a.Authenticate(req); err == nil {
+		t.Errorf("Expected an error when the token endpoint rejects the client")
+	}
+}
+
+func TestSecretsAreReread(t *testing.T) {
+	secret := credentialsSecret()
+	now := time.Now()
+	r := NewResolver(func(_, _ string) (*corev1.Secret, error) {
+		return secret.DeepCopy(), nil
+	})
+	r.now = func() time.Time { return now }
+	a := r.Authenticator(testNS, &eventingduck.SubscriberAuth{
+		BearerToken: &[]corev1.SecretKeySelector{selector("token")}[0],
+	})
+
+	authenticate := func() string {
+		req := httptest.NewRequest(http.MethodPost, "http://subscriber/", nil)
+		if err := a.Authenticate(req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	authenticate()
+	secret.Data["token"] = []byte("rotated")
+	if got, want := authenticate(), "Bearer s3cr3t"; got != want {
+		t.Errorf("Unexpected Authorization header. Expected %q, actual %q", want, got)
+	}
+	now = now.Add(2 * secretTTL)
+	if got, want := authenticate(), "Bearer rotated"; got != want {
+		t.Errorf("Unexpected Authorization header. Expected %q, actual %q", want, got)
+	}
+}
+
+// clientCertificate creates a self-signed client certificate, returning it PEM encoded along with
+// its key.
+func clientCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dispatcher"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unable to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unable to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unable to marshal key: %v", err)
+	}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// mutualTLSServer starts a server that requires the client certificate cert.
+func mutualTLSServer(cert *x509.Certificate) *httptest.Server {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	s.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: x509.NewCertPool()}
+	s.TLS.ClientCAs.AddCert(cert)
+	s.StartTLS()
+	return s
+}
+
+func TestAuthenticatorClientCertificate(t *testing.T) {
+	cert, certPEM, keyPEM := clientCertificate(t)
+	s := mutualTLSServer(cert)
+	defer s.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+
+	testCases := map[string]struct {
+		data    map[string][]byte
+		wantErr bool
+	}{
+		"certificate and CA": {
+			data: map[string][]byte{
+				corev1.TLSCertKey:              certPEM,
+				corev1.TLSPrivateKeyKey:        keyPEM,
+				corev1.ServiceAccountRootCAKey: serverCA,
+			},
+		},
+		"server not trusted by the system roots": {
+			data: map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			},
+			wantErr: true,
+		},
+		"server not trusted by the CA": {
+			data: map[string][]byte{
+				corev1.TLSCertKey:              certPEM,
+				corev1.TLSPrivateKeyKey:        keyPEM,
+				corev1.ServiceAccountRootCAKey: certPEM,
+			},
+			wantErr: true,
+		},
+		"no certificate": {
+			data: map[string][]byte{
+				corev1.ServiceAccountRootCAKey: serverCA,
+			},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			r := NewResolver(func(_, _ string) (*corev1.Secret, error) {
+				return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: secretLabels}, Data: tc.data}, nil
+			})
+			a := r.Authenticator(testNS, &eventingduck.SubscriberAuth{
+				ClientCertificate: &eventingduck.ClientCertificate{SecretName: "client-tls"},
+			})
+			req := httptest.NewRequest(http.MethodPost, s.URL, nil)
+			req.RequestURI = ""
+			if err := a.Authenticate(req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			c := &http.Client{Transport: &http.Transport{TLSClientConfig: a.TLSConfig()}}
+			res, err := c.Do(req)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, the request succeeded with %d", res.StatusCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			res.Body.Close()
+			if res.StatusCode != http.StatusAccepted {
+				t.Errorf("Unexpected status code. Expected %d, actual %d", http.StatusAccepted, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorTLSConfigIsShared(t *testing.T) {
+	r := NewResolver(getSecret)
+	auth := &eventingduck.SubscriberAuth{
+		ClientCertificate: &eventingduck.ClientCertificate{SecretName: "client-tls"},
+	}
+	if a, b := r.Authenticator(testNS, auth).TLSConfig(), r.Authenticator(testNS, auth).TLSConfig(); a != b {
+		t.Errorf("Expected the subscribers with the same client certificate to share a TLS config")
+	}
+	if c := r.Authenticator("other", auth).TLSConfig(); c == r.Authenticator(testNS, auth).TLSConfig() {
+		t.Errorf("Expected a TLS config per namespace")
+	}
+	bearer := r.Authenticator(testNS, &eventingduck.SubscriberAuth{
+		BearerToken: &[]corev1.SecretKeySelector{selector("token")}[0],
+	})
+	if c := bearer.TLSConfig(); c != nil {
+		t.Errorf("Expected no TLS config without a client certificate, got %v", c)
+	}
+}
+
+func TestTLSConfigFromEnv(t *testing.T) {
+	cert, certPEM, keyPEM := clientCertificate(t)
+	s := mutualTLSServer(cert)
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "client-certificate")
+	if err != nil {
+		t.Fatalf("Unable to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	for key, data := range map[string][]byte{
+		corev1.TLSCertKey:              certPEM,
+		corev1.TLSPrivateKeyKey:        keyPEM,
+		corev1.ServiceAccountRootCAKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}),
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, key), data, 0600); err != nil {
+			t.Fatalf("Unable to write %s: %v", key, err)
+		}
+	}
+
+	os.Setenv(ClientCertificateDirEnv, "")
+	if c, err := TLSConfigFromEnv(); c != nil || err != nil {
+		t.Errorf("Expected no TLS config when %s is unset, got %v, %v", ClientCertificateDirEnv, c, err)
+	}
+
+	os.Setenv(ClientCertificateDirEnv, dir)
+	defer os.Unsetenv(ClientCertificateDirEnv)
+	config, err := TLSConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(s.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		t.Errorf("Unexpected status code. Expected %d, actual %d", http.StatusAccepted, res.StatusCode)
+	}
+
+	os.Remove(filepath.Join(dir, corev1.TLSPrivateKeyKey))
+	if _, err := TLSConfigFromEnv(); err == nil {
+		t.Errorf("Expected an error without a private key")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ClientCertificateDirEnv is the environment variable naming the directory of the client
+// certificate a dispatcher presents to every subscriber, typically a mounted Secret of type
+// kubernetes.io/tls. The directory holds tls.crt, tls.key and optionally ca.crt.
+const ClientCertificateDirEnv = "CLIENT_CERTIFICATE_DIR"
+
+// TLSConfigFromEnv returns the TLS config presenting the client certificate configured by the
+// environment variables of the process, or nil if there is none. The files are read during every
+// handshake, so a rotated certificate is picked up without restarting the dispatcher.
+func TLSConfigFromEnv() (*tls.Config, error) {
+	dir := os.Getenv(ClientCertificateDirEnv)
+	if dir == "" {
+		return nil, nil
+	}
+	load := func() (map[string][]byte, error) {
+		data := make(map[string][]byte)
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, corev1.ServiceAccountRootCAKey} {
+			b, err := ioutil.ReadFile(filepath.Join(dir, key))
+			if os.IsNotExist(err) && key == corev1.ServiceAccountRootCAKey {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			data[key] = b
+		}
+		return data, nil
+	}
+	// Fail fast on a missing or invalid certificate, rather than on the first delivery.
+	data, err := load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the client certificate: %v", err)
+	}
+	if _, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey]); err != nil {
+		return nil, fmt.Errorf("invalid client certificate in %s: %v", dir, err)
+	}
+	return newTLSConfig(load), nil
+}
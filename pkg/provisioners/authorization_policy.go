@@ -0,0 +1,225 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
+	kncontroller "github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/system"
+)
+
+const (
+	// AllowedNamespacesAnnotation is the annotation of a Channel listing, separated by commas, the
+	// namespaces whose workloads may send events to the Channel.
+	AllowedNamespacesAnnotation = "eventing.knative.dev/allowedNamespaces"
+	// AllowedServiceAccountsAnnotation is the annotation of a Channel listing, separated by commas,
+	// the <namespace>/<name> of the service accounts that may send events to the Channel.
+	AllowedServiceAccountsAnnotation = "eventing.knative.dev/allowedServiceAccounts"
+
+	// authorizationPolicyChannelLabel and authorizationPolicyNamespaceLabel identify the Channel of
+	// an AuthorizationPolicy. Owner references cannot cross namespaces, so they are used instead to
+	// reconcile the Channel when its AuthorizationPolicy changes.
+	authorizationPolicyChannelLabel   = "channel"
+	authorizationPolicyNamespaceLabel = "channelNamespace"
+
+	// trustDomain is the Istio trust domain of the service account principals.
+	trustDomain = "cluster.local"
+)
+
+// ChannelAuthorizationPolicyName returns the name of the AuthorizationPolicy of a Channel, in the
+// system namespace.
+func ChannelAuthorizationPolicyName(channelName, namespace string) string {
+	return fmt.Sprintf("%s-%s-channel", namespace, channelName)
+}
+
+// CreateAuthorizationPolicy creates or updates the Istio AuthorizationPolicy restricting who may
+// send events to channel, as listed by its AllowedNamespacesAnnotation and
+// AllowedServiceAccountsAnnotation. The policy applies to the dispatcher of the channel, so it is
+// in the system namespace. When the channel has neither annotation, its AuthorizationPolicy is
+// deleted. Nothing is done and nil is returned when Istio security is not installed.
+func CreateAuthorizationPolicy(ctx context.Context, client runtimeClient.Client, channel *eventingv1alpha1.Channel) (*securityv1beta1.AuthorizationPolicy, error) {
+	name := ChannelAuthorizationPolicyName(channel.Name, channel.Namespace)
+	expected, err := newAuthorizationPolicy(channel)
+	if err != nil {
+		recordEvent(ctx, channel, corev1.EventTypeWarning, AuthorizationPolicyReconcileFailed, "Invalid AuthorizationPolicy %q: %v", name, err)
+		return nil, err
+	}
+
+	current := &securityv1beta1.AuthorizationPolicy{}
+	err = client.Get(ctx, types.NamespacedName{Namespace: system.Namespace, Name: name}, current)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	} else if err != nil && !k8serrors.IsNotFound(err) {
+		recordEvent(ctx, channel, corev1.EventTypeWarning, AuthorizationPolicyReconcileFailed, "Failed to get AuthorizationPolicy %q: %v", name, err)
+		return nil, err
+	}
+	exists := err == nil
+
+	if expected == nil {
+		if exists && isChannelAuthorizationPolicy(current, channel) {
+			if err := client.Delete(ctx, current); err != nil && !k8serrors.IsNotFound(err) {
+				recordEvent(ctx, channel, corev1.EventTypeWarning, AuthorizationPolicyReconcileFailed, "Failed to delete AuthorizationPolicy %q: %v", name, err)
+				return nil, err
+			}
+			recordEvent(ctx, channel, corev1.EventTypeNormal, AuthorizationPolicyDeleted, "Deleted AuthorizationPolicy %q", name)
+		}
+		return nil, nil
+	}
+
+	if !exists {
+		if err := client.Create(ctx, expected); err != nil {
+			recordEvent(ctx, channel, corev1.EventTypeWarning, AuthorizationPolicyReconcileFailed, "Failed to create AuthorizationPolicy %q: %v", name, err)
+			return nil, err
+		}
+		recordEvent(ctx, channel, corev1.EventTypeNormal, AuthorizationPolicyCreated, "Created AuthorizationPolicy %q", name)
+		return expected, nil
+	}
+
+	if !equality.Semantic.DeepEqual(expected.Spec, current.Spec) || !equality.Semantic.DeepEqual(expected.Labels, current.Labels) {
+		current.Spec = expected.Spec
+		current.Labels = expected.Labels
+		if err := client.Update(ctx, current); err != nil {
+			recordEvent(ctx, channel, corev1.EventTypeWarning, AuthorizationPolicyReconcileFailed, "Failed to update AuthorizationPolicy %q: %v", name, err)
+			return nil, err
+		}
+		recordEvent(ctx, channel, corev1.EventTypeNormal, AuthorizationPolicyUpdated, "Updated AuthorizationPolicy %q", name)
+	}
+	return current, nil
+}
+
+// DeleteAuthorizationPolicy deletes the AuthorizationPolicy of channel, if it has one. It is called
+// when channel is deleted, as the AuthorizationPolicy is not garbage collected with it.
+func DeleteAuthorizationPolicy(ctx context.Context, client runtimeClient.Client, channel *eventingv1alpha1.Channel) error {
+	name := ChannelAuthorizationPolicyName(channel.Name, channel.Namespace)
+	current := &securityv1beta1.AuthorizationPolicy{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: system.Namespace, Name: name}, current)
+	if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !isChannelAuthorizationPolicy(current, channel) {
+		return nil
+	}
+	if err := client.Delete(ctx, current); err != nil && !k8serrors.IsNotFound(err) {
+		recordEvent(ctx, channel, corev1.EventTypeWarning, AuthorizationPolicyReconcileFailed, "Failed to delete AuthorizationPolicy %q: %v", name, err)
+		return err
+	}
+	recordEvent(ctx, channel, corev1.EventTypeNormal, AuthorizationPolicyDeleted, "Deleted AuthorizationPolicy %q", name)
+	return nil
+}
+
+// isChannelAuthorizationPolicy returns whether ap was created for channel.
+func isChannelAuthorizationPolicy(ap *securityv1beta1.AuthorizationPolicy, channel *eventingv1alpha1.Channel) bool {
+	return ap.Labels[authorizationPolicyChannelLabel] == channel.Name && ap.Labels[authorizationPolicyNamespaceLabel] == channel.Namespace
+}
+
+// newAuthorizationPolicy creates the AuthorizationPolicy of channel, or returns nil if it does not
+// restrict who may send events to it. The policy denies the POST requests to the hosts of the
+// channel that come neither from an allowed namespace nor from an allowed service account, and
+// leaves the other channels of the dispatcher alone.
+func newAuthorizationPolicy(channel *eventingv1alpha1.Channel) (*securityv1beta1.AuthorizationPolicy, error) {
+	namespaces := splitList(channel.Annotations[AllowedNamespacesAnnotation])
+	var principals []string
+	for _, sa := range splitList(channel.Annotations[AllowedServiceAccountsAnnotation]) {
+		parts := strings.Split(sa, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid %s %q: expected <namespace>/<name>", AllowedServiceAccountsAnnotation, sa)
+		}
+		principals = append(principals, fmt.Sprintf("%s/ns/%s/sa/%s", trustDomain, parts[0], parts[1]))
+	}
+	if len(namespaces) == 0 && len(principals) == 0 {
+		return nil, nil
+	}
+	return &securityv1beta1.AuthorizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ChannelAuthorizationPolicyName(channel.Name, channel.Namespace),
+			Namespace: system.Namespace,
+			Labels: map[string]string{
+				authorizationPolicyChannelLabel:   channel.Name,
+				authorizationPolicyNamespaceLabel: channel.Namespace,
+				"provisioner":                     channel.Spec.Provisioner.Name,
+			},
+		},
+		Spec: securityv1beta1.AuthorizationPolicySpec{
+			Selector: &securityv1beta1.WorkloadSelector{
+				MatchLabels: DispatcherLabels(channel.Spec.Provisioner.Name),
+			},
+			Action: securityv1beta1.ActionDeny,
+			Rules: []securityv1beta1.Rule{{
+				From: []securityv1beta1.RuleFrom{{
+					Source: securityv1beta1.Source{
+						NotNamespaces: namespaces,
+						NotPrincipals: principals,
+					},
+				}},
+				To: []securityv1beta1.RuleTo{{
+					Operation: securityv1beta1.Operation{
+						Hosts: []string{
+							ChannelHostName(channel.Name, channel.Namespace),
+							kncontroller.ServiceHostName(ChannelServiceName(channel.Name), channel.Namespace),
+						},
+						Methods: []string{"POST"},
+					},
+				}},
+			}},
+		},
+	}, nil
+}
+
+// splitList splits a list separated by commas, dropping the empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// WatchAuthorizationPolicies makes c reconcile the Channels whose AuthorizationPolicy changes. It
+// returns an error when Istio security is not installed.
+func WatchAuthorizationPolicies(c controller.Controller) error {
+	return c.Watch(&source.Kind{
+		Type: &securityv1beta1.AuthorizationPolicy{},
+	}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+		labels := o.Meta.GetLabels()
+		name, namespace := labels[authorizationPolicyChannelLabel], labels[authorizationPolicyNamespaceLabel]
+		if o.Meta.GetNamespace() != system.Namespace || name == "" || namespace == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+	})})
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
+	"github.com/knative/eventing/pkg/system"
+)
+
+func init() {
+	// Add types to scheme.
+	securityv1beta1.AddToScheme(scheme.Scheme)
+}
+
+// noIstioClient is a client of a cluster without Istio security.
+type noIstioClient struct {
+	runtimeClient.Client
+}
+
+func (c *noIstioClient) Get(ctx context.Context, key runtimeClient.ObjectKey, obj runtime.Object) error {
+	if _, ok := obj.(*securityv1beta1.AuthorizationPolicy); ok {
+		return &meta.NoKindMatchError{GroupKind: securityv1beta1.Kind("AuthorizationPolicy")}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func TestCreateAuthorizationPolicy(t *testing.T) {
+	apKey := runtimeClient.ObjectKey{Namespace: system.Namespace, Name: ChannelAuthorizationPolicyName(channelName, testNS)}
+	annotated := func(annotations map[string]string) *eventingv1alpha1.Channel {
+		c := getNewChannel()
+		c.Annotations = annotations
+		return c
+	}
+	testCases := map[string]struct {
+		channel  *eventingv1alpha1.Channel
+		objects  []runtime.Object
+		noIstio  bool
+		want     *securityv1beta1.AuthorizationPolicy
+		wantNoAP bool
+		wantErr  bool
+	}{
+		"not restricted": {
+			channel:  getNewChannel(),
+			wantNoAP: true,
+		},
+		"restricted": {
+			channel: annotated(map[string]string{
+				AllowedNamespacesAnnotation:      "producers, team-a",
+				AllowedServiceAccountsAnnotation: "ci/deployer",
+			}),
+			want: makeAuthorizationPolicy([]string{"producers", "team-a"}, []string{"cluster.local/ns/ci/sa/deployer"}),
+		},
+		"modified": {
+			channel: annotated(map[string]string{AllowedNamespacesAnnotation: "producers"}),
+			objects: []runtime.Object{makeAuthorizationPolicy([]string{"other"}, nil)},
+			want:    makeAuthorizationPolicy([]string{"producers"}, nil),
+		},
+		"no longer restricted": {
+			channel:  getNewChannel(),
+			objects:  []runtime.Object{makeAuthorizationPolicy([]string{"producers"}, nil)},
+			wantNoAP: true,
+		},
+		"not the channel's": {
+			channel: getNewChannel(),
+			objects: []runtime.Object{func() *securityv1beta1.AuthorizationPolicy {
+				ap := makeAuthorizationPolicy([]string{"producers"}, nil)
+				ap.Labels = nil
+				return ap
+			}()},
+			want: func() *securityv1beta1.AuthorizationPolicy {
+				ap := makeAuthorizationPolicy([]string{"producers"}, nil)
+				ap.Labels = nil
+				return ap
+			}(),
+		},
+		"invalid service account": {
+			channel:  annotated(map[string]string{AllowedServiceAccountsAnnotation: "deployer"}),
+			wantNoAP: true,
+			wantErr:  true,
+		},
+		"no istio": {
+			channel:  annotated(map[string]string{AllowedNamespacesAnnotation: "producers"}),
+			noIstio:  true,
+			wantNoAP: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var client runtimeClient.Client = fake.NewFakeClient(tc.objects...)
+			if tc.noIstio {
+				client = &noIstioClient{client}
+			}
+			_, err := CreateAuthorizationPolicy(context.TODO(), client, tc.channel)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error. Expected error %v, actual %v", tc.wantErr, err)
+			}
+
+			got := &securityv1beta1.AuthorizationPolicy{}
+			err = client.Get(context.TODO(), apKey, got)
+			if tc.noIstio {
+				if !meta.IsNoMatchError(err) {
+					t.Errorf("Expected a no match error, got %v", err)
+				}
+				return
+			}
+			if tc.wantNoAP {
+				if !k8serrors.IsNotFound(err) {
+					t.Errorf("Expected no AuthorizationPolicy, got %v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unable to get the AuthorizationPolicy: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.Spec, got.Spec); diff != "" {
+				t.Errorf("Unexpected AuthorizationPolicy spec (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.Labels, got.Labels); diff != "" {
+				t.Errorf("Unexpected AuthorizationPolicy labels (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDeleteAuthorizationPolicy(t *testing.T) {
+	apKey := runtimeClient.ObjectKey{Namespace: system.Namespace, Name: ChannelAuthorizationPolicyName(channelName, testNS)}
+	client := fake.NewFakeClient(makeAuthorizationPolicy([]string{"producers"}, nil))
+	if err := DeleteAuthorizationPolicy(context.TODO(), client, getNewChannel()); err != nil {
+		t.Fatalf("Unexpected error deleting the AuthorizationPolicy: %v", err)
+	}
+	if err := client.Get(context.TODO(), apKey, &securityv1beta1.AuthorizationPolicy{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("Expected the AuthorizationPolicy to be deleted, got %v", err)
+	}
+	if err := DeleteAuthorizationPolicy(context.TODO(), client, getNewChannel()); err != nil {
+		t.Errorf("Unexpected error deleting a missing AuthorizationPolicy: %v", err)
+	}
+	if err := DeleteAuthorizationPolicy(context.TODO(), &noIstioClient{client}, getNewChannel()); err != nil {
+		t.Errorf("Unexpected error deleting without Istio: %v", err)
+	}
+}
+
+func makeAuthorizationPolicy(namespaces, principals []string) *securityv1beta1.AuthorizationPolicy {
+	return &securityv1beta1.AuthorizationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      ChannelAuthorizationPolicyName(channelName, testNS),
+			Labels: map[string]string{
+				"channel":          channelName,
+				"channelNamespace": testNS,
+				"provisioner":      clusterChannelProvisionerName,
+			},
+		},
+		Spec: securityv1beta1.AuthorizationPolicySpec{
+			Selector: &securityv1beta1.WorkloadSelector{MatchLabels: DispatcherLabels(clusterChannelProvisionerName)},
+			Action:   securityv1beta1.ActionDeny,
+			Rules: []securityv1beta1.Rule{{
+				From: []securityv1beta1.RuleFrom{{
+					Source: securityv1beta1.Source{NotNamespaces: namespaces, NotPrincipals: principals},
+				}},
+				To: []securityv1beta1.RuleTo{{
+					Operation: securityv1beta1.Operation{
+						Hosts: []string{
+							"test-channel.test-namespace.channels.cluster.local",
+							"test-channel-channel.test-namespace.svc.cluster.local",
+						},
+						Methods: []string{"POST"},
+					},
+				}},
+			}},
+		},
+	}
+}
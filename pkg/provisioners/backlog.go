@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BacklogReporter is implemented by the dispatchers that know how many events of each channel are
+// waiting to be delivered.
+type BacklogReporter interface {
+	// Backlog returns the number of events of each channel that are waiting to be delivered. An
+	// event is counted once for every subscription it has not been delivered to yet.
+	Backlog() map[ChannelReference]int64
+}
+
+var backlogDesc = prometheus.NewDesc(
+	"channel_dispatcher_backlog_events",
+	"The number of events waiting to be delivered to subscribers, by namespace and channel.",
+	[]string{"namespace", "channel"}, nil)
+
+// backlogCollector exports the backlog of a BacklogReporter as a gauge.
+type backlogCollector struct {
+	reporter BacklogReporter
+}
+
+func (c *backlogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- backlogDesc
+}
+
+func (c *backlogCollector) Collect(ch chan<- prometheus.Metric) {
+	// The backlog of the channels is summed by namespace when the channel label is collapsed.
+	backlog := make(map[[2]string]int64)
+	for channel, n := range c.reporter.Backlog() {
+		namespace, name := ChannelMetricLabels(channel)
+		backlog[[2]string{namespace, name}] += n
+	}
+	for labels, n := range backlog {
+		ch <- prometheus.MustNewConstMetric(backlogDesc, prometheus.GaugeValue, float64(n), labels[0], labels[1])
+	}
+}
+
+// RegisterBacklog exports the backlog reported by r in the channel_dispatcher_backlog_events
+// metric. Only one BacklogReporter can be registered.
+func RegisterBacklog(r BacklogReporter) error {
+	return prometheus.Register(&backlogCollector{reporter: r})
+}
+
+// BacklogCounter is a BacklogReporter for the dispatchers that hold the events waiting to be
+// delivered, and count them as they come and go. Its methods are safe to call on a nil
+// BacklogCounter, which counts nothing.
+type BacklogCounter struct {
+	mu     sync.Mutex
+	counts map[ChannelReference]int64
+}
+
+var _ BacklogReporter = &BacklogCounter{}
+
+// NewBacklogCounter creates an empty BacklogCounter.
+func NewBacklogCounter() *BacklogCounter {
+	return &BacklogCounter{
+		counts: make(map[ChannelReference]int64),
+	}
+}
+
+// Add adds delta to the backlog of channel. It is negative when events are delivered.
+func (c *BacklogCounter) Add(channel ChannelReference, delta int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := c.counts[channel] + delta; n != 0 {
+		c.counts[channel] = n
+	} else {
+		delete(c.counts, channel)
+	}
+}
+
+// Backlog returns the number of events of each channel that were added and not removed yet.
+// Channels without backlog are omitted.
+func (c *BacklogCounter) Backlog() map[ChannelReference]int64 {
+	backlog := make(map[ChannelReference]int64)
+	if c == nil {
+		return backlog
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for channel, n := range c.counts {
+		backlog[channel] = n
+	}
+	return backlog
+}
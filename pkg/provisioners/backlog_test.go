@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestBacklogCounter(t *testing.T) {
+	c1 := ChannelReference{Namespace: "test-ns", Name: "c1"}
+	c2 := ChannelReference{Namespace: "test-ns", Name: "c2"}
+
+	backlog := NewBacklogCounter()
+	backlog.Add(c1, 1)
+	backlog.Add(c1, 1)
+	backlog.Add(c2, 1)
+	backlog.Add(c2, -1)
+	want := map[ChannelReference]int64{c1: 2}
+	if diff := cmp.Diff(want, backlog.Backlog()); diff != "" {
+		t.Errorf("Unexpected backlog (-want, +got) = %v", diff)
+	}
+
+	var nilCounter *BacklogCounter
+	nilCounter.Add(c1, 1)
+	if got := nilCounter.Backlog(); len(got) != 0 {
+		t.Errorf("Unexpected backlog of a nil counter: %v", got)
+	}
+}
+
+func TestBacklogCollector(t *testing.T) {
+	backlog := NewBacklogCounter()
+	backlog.Add(ChannelReference{Namespace: "test-ns", Name: "c1"}, 3)
+
+	ch := make(chan prometheus.Metric, 1)
+	(&backlogCollector{reporter: backlog}).Collect(ch)
+	close(ch)
+	m := &dto.Metric{}
+	if err := (<-ch).Write(m); err != nil {
+		t.Fatalf("Unable to read the metric: %v", err)
+	}
+	if got := m.GetGauge().GetValue(); got != 3 {
+		t.Errorf("Unexpected backlog. Expected 3. Actual %v", got)
+	}
+	if got := m.GetLabel()[0].GetValue(); got != "test-ns/c1" {
+		t.Errorf("Unexpected channel label. Expected %q. Actual %q", "test-ns/c1", got)
+	}
+}
+
+func TestBacklogCollectorNamespaceGranularity(t *testing.T) {
+	if err := SetMetricsGranularity(string(NamespaceGranularity)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer SetMetricsGranularity("")
+
+	backlog := NewBacklogCounter()
+	backlog.Add(ChannelReference{Namespace: "test-ns", Name: "c1"}, 3)
+	backlog.Add(ChannelReference{Namespace: "test-ns", Name: "c2"}, 2)
+
+	ch := make(chan prometheus.Metric, 2)
+	(&backlogCollector{reporter: backlog}).Collect(ch)
+	close(ch)
+	if len(ch) != 1 {
+		t.Fatalf("Expected the backlog of the channels to be summed. Got %d metrics", len(ch))
+	}
+	m := &dto.Metric{}
+	if err := (<-ch).Write(m); err != nil {
+		t.Fatalf("Unable to read the metric: %v", err)
+	}
+	if got := m.GetGauge().GetValue(); got != 5 {
+		t.Errorf("Unexpected backlog. Expected 5. Actual %v", got)
+	}
+	labels := map[string]string{}
+	for _, l := range m.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	if diff := cmp.Diff(map[string]string{"namespace": "test-ns", "channel": ""}, labels); diff != "" {
+		t.Errorf("Unexpected labels (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/system"
+)
+
+// CacheSelectors returns the selectors of the objects the controller of the provisioner ccpName
+// watches, so that it does not cache the objects of every other provisioner and application in the
+// cluster: the Services and VirtualServices labeled by its name, and the NetworkPolicies,
+// AuthorizationPolicies and ConfigMaps of the system namespace.
+func CacheSelectors(ccpName string) []namespaced.Selector {
+	provisioner := fmt.Sprintf("provisioner=%s", ccpName)
+	systemNamespace := fmt.Sprintf("metadata.namespace=%s", system.Namespace)
+	return []namespaced.Selector{{
+		Resource: schema.GroupResource{Resource: "services"},
+		Labels:   provisioner,
+	}, {
+		Resource: schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"},
+		Labels:   provisioner,
+	}, {
+		Resource: schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"},
+		Fields:   systemNamespace,
+	}, {
+		Resource: schema.GroupResource{Group: "security.istio.io", Resource: "authorizationpolicies"},
+		Fields:   systemNamespace,
+	}, {
+		Resource: schema.GroupResource{Resource: "configmaps"},
+		Fields:   systemNamespace,
+	}}
+}
@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -15,7 +16,6 @@ import (
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/controller"
-	"github.com/knative/eventing/pkg/system"
 	"k8s.io/apimachinery/pkg/api/equality"
 )
 
@@ -54,32 +54,46 @@ func CreateK8sService(ctx context.Context, client runtimeClient.Client, c *event
 		Namespace: c.Namespace,
 		Name:      ChannelServiceName(c.Name),
 	}
-	return createK8sService(ctx, client, svcKey, newK8sService(c))
+	return createK8sService(ctx, client, c, svcKey, newK8sService(c))
 }
 
-func createK8sService(ctx context.Context, client runtimeClient.Client, svcKey types.NamespacedName, svc *corev1.Service) (*corev1.Service, error) {
+// createK8sService creates or updates the Service svc of owner, emitting Events on owner.
+func createK8sService(ctx context.Context, client runtimeClient.Client, owner runtime.Object, svcKey types.NamespacedName, svc *corev1.Service) (*corev1.Service, error) {
 	current := &corev1.Service{}
 	err := client.Get(ctx, svcKey, current)
 
 	if k8serrors.IsNotFound(err) {
 		err = client.Create(ctx, svc)
 		if err != nil {
+			recordEvent(ctx, owner, corev1.EventTypeWarning, ServiceReconcileFailed, "Failed to create Service %q: %v", svcKey.Name, err)
 			return nil, err
 		}
+		recordEvent(ctx, owner, corev1.EventTypeNormal, ServiceCreated, "Created Service %q", svcKey.Name)
 		return svc, nil
 	} else if err != nil {
+		recordEvent(ctx, owner, corev1.EventTypeWarning, ServiceReconcileFailed, "Failed to get Service %q: %v", svcKey.Name, err)
 		return nil, err
 	}
 
 	// spec.clusterIP is immutable and is set on existing services. If we don't set this
 	// to the same value, we will encounter an error while updating.
 	svc.Spec.ClusterIP = current.Spec.ClusterIP
-	if !equality.Semantic.DeepDerivative(svc.Spec, current.Spec) {
+	// The labels are added to, rather than replaced, as the controllers may select the Services
+	// they watch by labels that older versions did not set.
+	if !equality.Semantic.DeepDerivative(svc.Spec, current.Spec) || !equality.Semantic.DeepDerivative(svc.Labels, current.Labels) {
 		current.Spec = svc.Spec
+		if current.Labels == nil {
+			current.Labels = make(map[string]string, len(svc.Labels))
+		}
+		for k, v := range svc.Labels {
+			current.Labels[k] = v
+		}
 		err = client.Update(ctx, current)
 		if err != nil {
+			recordEvent(ctx, owner, corev1.EventTypeWarning, ServiceReconcileFailed, "Failed to update Service %q: %v", svcKey.Name, err)
 			return nil, err
 		}
+		recordEvent(ctx, owner, corev1.EventTypeNormal, ServiceUpdated, "Updated Service %q", svcKey.Name)
 	}
 	return current, nil
 }
@@ -94,35 +108,51 @@ func getVirtualService(ctx context.Context, client runtimeClient.Client, c *even
 	return vs, err
 }
 
+// CreateVirtualService creates or updates the VirtualService of channel, routing its events to the
+// dispatcher Service of its provisioner in the knative-eventing namespace. Use
+// CreateVirtualServiceWithDestination with a DestinationHost to route them elsewhere.
 func CreateVirtualService(ctx context.Context, client runtimeClient.Client, channel *eventingv1alpha1.Channel) (*istiov1alpha3.VirtualService, error) {
+	return CreateVirtualServiceWithDestination(ctx, client, channel, ClusterDispatcherHost(channel))
+}
+
+// CreateVirtualServiceWithDestination creates or updates the VirtualService of channel, routing
+// its events to destinationHost rather than to the dispatcher Service of its provisioner.
+func CreateVirtualServiceWithDestination(ctx context.Context, client runtimeClient.Client, channel *eventingv1alpha1.Channel, destinationHost string) (*istiov1alpha3.VirtualService, error) {
 	virtualService, err := getVirtualService(ctx, client, channel)
 
 	// If the resource doesn't exist, we'll create it
 	if k8serrors.IsNotFound(err) {
-		virtualService = newVirtualService(channel)
+		virtualService = newVirtualService(channel, destinationHost)
 		err = client.Create(ctx, virtualService)
 		if err != nil {
+			recordEvent(ctx, channel, corev1.EventTypeWarning, VirtualServiceReconcileFailed, "Failed to create VirtualService %q: %v", virtualService.Name, err)
 			return nil, err
 		}
+		recordEvent(ctx, channel, corev1.EventTypeNormal, VirtualServiceCreated, "Created VirtualService %q", virtualService.Name)
 		return virtualService, nil
 	} else if err != nil {
+		recordEvent(ctx, channel, corev1.EventTypeWarning, VirtualServiceReconcileFailed, "Failed to get VirtualService %q: %v", ChannelVirtualServiceName(channel.Name), err)
 		return nil, err
 	}
 
 	// Update VirtualService if it has changed. This is possible since in version 0.2.0, the destinationHost in
 	// spec.HTTP.Route for the dispatcher was changed from *-clusterbus to *-dispatcher. Even otherwise, this
 	// reconciliation is useful for the future mutations to the object.
-	expected := newVirtualService(channel)
+	expected := newVirtualService(channel, destinationHost)
 	if !equality.Semantic.DeepDerivative(expected.Spec, virtualService.Spec) {
 		virtualService.Spec = expected.Spec
 		err := client.Update(ctx, virtualService)
 		if err != nil {
+			recordEvent(ctx, channel, corev1.EventTypeWarning, VirtualServiceReconcileFailed, "Failed to update VirtualService %q: %v", virtualService.Name, err)
 			return nil, err
 		}
+		recordEvent(ctx, channel, corev1.EventTypeNormal, VirtualServiceUpdated, "Updated VirtualService %q", virtualService.Name)
 	}
 	return virtualService, nil
 }
 
+// UpdateChannel updates the finalizers and the status of the Channel to those of u. The status is
+// written through the status subresource, by the status Coalescer when one is set.
 func UpdateChannel(ctx context.Context, client runtimeClient.Client, u *eventingv1alpha1.Channel) error {
 	channel := &eventingv1alpha1.Channel{}
 	err := client.Get(ctx, runtimeClient.ObjectKey{Namespace: u.Namespace, Name: u.Name}, channel)
@@ -130,19 +160,19 @@ func UpdateChannel(ctx context.Context, client runtimeClient.Client, u *eventing
 		return err
 	}
 
-	updated := false
 	if !equality.Semantic.DeepEqual(channel.Finalizers, u.Finalizers) {
 		channel.SetFinalizers(u.ObjectMeta.Finalizers)
-		updated = true
+		if err := client.Update(ctx, channel); err != nil {
+			return err
+		}
 	}
 
 	if !equality.Semantic.DeepEqual(channel.Status, u.Status) {
+		if statusCoalescer != nil {
+			return statusCoalescer.Update(u)
+		}
 		channel.Status = u.Status
-		updated = true
-	}
-
-	if updated {
-		return client.Update(ctx, channel)
+		return client.Status().Update(ctx, channel)
 	}
 	return nil
 }
@@ -182,12 +212,11 @@ func newK8sService(c *eventingv1alpha1.Channel) *corev1.Service {
 // newVirtualService creates a new VirtualService for a Channel resource. It also sets the
 // appropriate OwnerReferences on the resource so handleObject can discover the Channel resource
 // that 'owns' it. As well as being garbage collected when the Channel is deleted.
-func newVirtualService(channel *eventingv1alpha1.Channel) *istiov1alpha3.VirtualService {
+func newVirtualService(channel *eventingv1alpha1.Channel, destinationHost string) *istiov1alpha3.VirtualService {
 	labels := map[string]string{
 		"channel":     channel.Name,
 		"provisioner": channel.Spec.Provisioner.Name,
 	}
-	destinationHost := controller.ServiceHostName(ChannelDispatcherServiceName(channel.Spec.Provisioner.Name), system.Namespace)
 	return &istiov1alpha3.VirtualService{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ChannelVirtualServiceName(channel.Name),
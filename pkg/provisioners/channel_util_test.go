@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -91,6 +92,23 @@ func TestChannelUtils(t *testing.T) {
 			return got, err
 		},
 		want: makeVirtualService(),
+	}, {
+		name: "CreateVirtualServiceWithDestination",
+		f: func() (metav1.Object, error) {
+			existing := makeVirtualService()
+			client := fake.NewFakeClient(existing)
+			destHost := fmt.Sprintf("%s-dispatcher-2.knative-eventing.svc.cluster.local", clusterChannelProvisionerName)
+			CreateVirtualServiceWithDestination(context.TODO(), client, getNewChannel(), destHost)
+
+			got := &istiov1alpha3.VirtualService{}
+			err := client.Get(context.TODO(), runtimeClient.ObjectKey{Namespace: testNS, Name: fmt.Sprintf("%s-channel", channelName)}, got)
+			return got, err
+		},
+		want: func() metav1.Object {
+			vs := makeVirtualService()
+			vs.Spec.Http[0].Route[0].Destination.Host = fmt.Sprintf("%s-dispatcher-2.knative-eventing.svc.cluster.local", clusterChannelProvisionerName)
+			return vs
+		}(),
 	}, {
 		name: "UpdateChannel",
 		f: func() (metav1.Object, error) {
@@ -134,12 +152,14 @@ func TestCreateK8sService(t *testing.T) {
 		update   controllertesting.MockUpdate
 		expected *corev1.Service
 		err      error
+		event    string
 	}{
 		"error getting svc": {
 			get: func(_ runtimeClient.Client, _ context.Context, _ runtimeClient.ObjectKey, _ runtime.Object) (controllertesting.MockHandled, error) {
 				return controllertesting.Handled, testInducedError
 			},
-			err: testInducedError,
+			err:   testInducedError,
+			event: `Warning ServiceReconcileFailed Failed to get Service "test-channel-channel": test-induced-error`,
 		},
 		"not found - create error": {
 			get: func(_ runtimeClient.Client, _ context.Context, _ runtimeClient.ObjectKey, _ runtime.Object) (controllertesting.MockHandled, error) {
@@ -148,7 +168,8 @@ func TestCreateK8sService(t *testing.T) {
 			create: func(_ runtimeClient.Client, _ context.Context, _ runtime.Object) (controllertesting.MockHandled, error) {
 				return controllertesting.Handled, testInducedError
 			},
-			err: testInducedError,
+			err:   testInducedError,
+			event: `Warning ServiceReconcileFailed Failed to create Service "test-channel-channel": test-induced-error`,
 		},
 		"not found - create succeeds": {
 			get: func(_ runtimeClient.Client, _ context.Context, _ runtimeClient.ObjectKey, _ runtime.Object) (controllertesting.MockHandled, error) {
@@ -160,6 +181,7 @@ func TestCreateK8sService(t *testing.T) {
 				return controllertesting.Handled, nil
 			},
 			expected: makeTamperedK8sService(),
+			event:    `Normal ServiceCreated Created Service "test-channel-channel"`,
 		},
 		"different spec - update fails": {
 			get: func(_ runtimeClient.Client, _ context.Context, _ runtimeClient.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
@@ -172,7 +194,8 @@ func TestCreateK8sService(t *testing.T) {
 			update: func(_ runtimeClient.Client, _ context.Context, obj runtime.Object) (controllertesting.MockHandled, error) {
 				return controllertesting.Handled, testInducedError
 			},
-			err: testInducedError,
+			err:   testInducedError,
+			event: `Warning ServiceReconcileFailed Failed to update Service "test-channel-channel": test-induced-error`,
 		},
 		"different spec - update succeeds": {
 			get: func(_ runtimeClient.Client, _ context.Context, _ runtimeClient.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
@@ -191,6 +214,7 @@ func TestCreateK8sService(t *testing.T) {
 				return controllertesting.Handled, nil
 			},
 			expected: makeTamperedK8sService(),
+			event:    `Normal ServiceUpdated Updated Service "test-channel-channel"`,
 		},
 		"found doesn't need altering": {
 			get: func(_ runtimeClient.Client, _ context.Context, _ runtimeClient.ObjectKey, obj runtime.Object) (controllertesting.MockHandled, error) {
@@ -214,13 +238,23 @@ func TestCreateK8sService(t *testing.T) {
 				MockCreates: []controllertesting.MockCreate{tc.create},
 				MockUpdates: []controllertesting.MockUpdate{tc.update},
 			})
-			svc, err := CreateK8sService(context.TODO(), client, getNewChannel())
+			recorder := record.NewFakeRecorder(1)
+			ctx := WithEventRecorder(context.TODO(), recorder)
+			svc, err := CreateK8sService(ctx, client, getNewChannel())
 			if tc.err != err {
 				t.Fatalf("Unexpected error. Expected '%s', actual '%v'", tc.err, err)
 			}
 			if diff := cmp.Diff(tc.expected, svc); diff != "" {
 				t.Fatalf("Unexpected service (-want +got): %s", diff)
 			}
+			var event string
+			select {
+			case event = <-recorder.Events:
+			default:
+			}
+			if diff := cmp.Diff(tc.event, event); diff != "" {
+				t.Errorf("Unexpected event (-want +got): %s", diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,181 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claimcheck provides the provisioners.ClaimCheckStores keeping the data of oversized
+// events in object storage. S3Store speaks the S3 API, which is also served by MinIO and, with
+// HMAC keys, by Google Cloud Storage. It is also the object storage of the event store.
+package claimcheck
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// The environment variables configuring the claim check of a dispatcher.
+const (
+	// ThresholdEnv enables the claim check. Its value is the size in bytes above which the data
+	// of events is offloaded.
+	ThresholdEnv = "CLAIM_CHECK_THRESHOLD"
+	// EndpointEnv is the URL of the S3 API, e.g. https://s3.us-east-1.amazonaws.com,
+	// http://minio.minio:9000 or https://storage.googleapis.com.
+	EndpointEnv = "CLAIM_CHECK_ENDPOINT"
+	// BucketEnv is the bucket the data is stored in.
+	BucketEnv = "CLAIM_CHECK_BUCKET"
+	// RegionEnv is the region of the bucket. Defaults to us-east-1.
+	RegionEnv = "CLAIM_CHECK_REGION"
+	// AccessKeyIDEnv and SecretAccessKeyEnv are the credentials used to sign requests.
+	AccessKeyIDEnv     = "CLAIM_CHECK_ACCESS_KEY_ID"
+	SecretAccessKeyEnv = "CLAIM_CHECK_SECRET_ACCESS_KEY"
+
+	defaultRegion = "us-east-1"
+)
+
+// Config is the claim check of a dispatcher.
+type Config struct {
+	// Store keeps the data of the oversized events.
+	Store provisioners.ClaimCheckStore
+	// Threshold is the size in bytes above which the data of events is offloaded.
+	Threshold int
+}
+
+// FromEnv returns the claim check configured by the environment variables of the process, or nil
+// if ThresholdEnv is not set.
+func FromEnv() (*Config, error) {
+	threshold, ok := os.LookupEnv(ThresholdEnv)
+	if !ok {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(threshold)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid %s %q", ThresholdEnv, threshold)
+	}
+	for _, env := range []string{EndpointEnv, BucketEnv, AccessKeyIDEnv, SecretAccessKeyEnv} {
+		if os.Getenv(env) == "" {
+			return nil, fmt.Errorf("%s must be set when %s is set", env, ThresholdEnv)
+		}
+	}
+	region := os.Getenv(RegionEnv)
+	if region == "" {
+		region = defaultRegion
+	}
+	store, err := NewS3Store(os.Getenv(EndpointEnv), os.Getenv(BucketEnv), region, os.Getenv(AccessKeyIDEnv), os.Getenv(SecretAccessKeyEnv))
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Store: store, Threshold: n}, nil
+}
+
+// S3Store stores data as the objects of a bucket, using path-style requests. Claim checks are
+// never deleted by the store, as every subscriber of a channel reads them; a lifecycle rule on the
+// bucket should expire them.
+type S3Store struct {
+	bucket string
+	region string
+	client *s3.S3
+}
+
+var _ provisioners.ClaimCheckStore = (*S3Store)(nil)
+
+// NewS3Store creates an S3Store for the bucket served at endpoint.
+func NewS3Store(endpoint, bucket, region, accessKeyID, secretAccessKey string) (*S3Store, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q, it must be an absolute http or https URL", endpoint)
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:         aws.String(endpoint),
+		Region:           aws.String(region),
+		Credentials:      credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
+		S3ForcePathStyle: aws.Bool(true),
+		HTTPClient:       &http.Client{Timeout: 30 * time.Second},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &S3Store{
+		bucket: bucket,
+		region: region,
+		client: s3.New(sess),
+	}, nil
+}
+
+// Put uploads data as the object key.
+func (s *S3Store) Put(key string, data []byte) error {
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to upload object %q: %v", key, err)
+	}
+	return nil
+}
+
+// Get downloads the object key.
+func (s *S3Store) Get(key string) ([]byte, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to download object %q: %v", key, err)
+	}
+	defer out.Body.Close()
+	return ioutil.ReadAll(out.Body)
+}
+
+// List returns the keys of the objects whose key starts with prefix, in lexicographic order.
+func (s *S3Store) List(prefix string) ([]string, error) {
+	var keys []string
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, o := range page.Contents {
+			keys = append(keys, aws.StringValue(o.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list objects %q: %v", prefix, err)
+	}
+	return keys, nil
+}
+
+// Delete deletes the object key. Deleting an object that does not exist is not an error.
+func (s *S3Store) Delete(key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete object %q: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claimcheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 serves the objects of a bucket, checking that requests are signed.
+type fakeS3 struct {
+	t      *testing.T
+	lock   sync.Mutex
+	object map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || !strings.Contains(auth, "/eu-west-1/s3/aws4_request") {
+		f.t.Errorf("Unexpected Authorization header %q", auth)
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	if got, want := r.Header.Get("X-Amz-Content-Sha256"), hex.EncodeToString(sum[:]); got != want {
+		f.t.Errorf("Unexpected X-Amz-Content-Sha256 header %q, expected %q", got, want)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.object[r.URL.Path] = body
+	case http.MethodDelete:
+		delete(f.object, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") == "2" {
+			f.list(w, r)
+			return
+		}
+		data, ok := f.object[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}
+}
+
+// listBucketResult is the response of ListObjectsV2.
+type listBucketResult struct {
+	Contents []struct {
+		Key string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// list serves ListObjectsV2, two keys at a time.
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	bucket := strings.TrimSuffix(r.URL.Path, "/") + "/"
+	var keys []string
+	for path := range f.object {
+		key := strings.TrimPrefix(path, bucket)
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) && key > r.URL.Query().Get("continuation-token") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	result := listBucketResult{}
+	if len(keys) > 2 {
+		keys = keys[:2]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[1]
+	}
+	for _, key := range keys {
+		result.Contents = append(result.Contents, struct{ Key string }{key})
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{t: t, object: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := NewS3Store(server.URL, "events", "eu-west-1", "access", "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error creating the store: %v", err)
+	}
+
+	if err := s.Put("1234", []byte("data")); err != nil {
+		t.Fatalf("Unexpected error from Put: %v", err)
+	}
+	if _, ok := fake.object["/events/1234"]; !ok {
+		t.Errorf("Expected the object to be uploaded to the bucket, got %v", fake.object)
+	}
+	data, err := s.Get("1234")
+	if err != nil {
+		t.Fatalf("Unexpected error from Get: %v", err)
+	}
+	if string(data) != "data" {
+		t.Errorf("Unexpected data %q", data)
+	}
+	if _, err := s.Get("unknown"); err == nil {
+		t.Errorf("Expected an error getting an unknown object")
+	}
+}
+
+func TestS3StoreListAndDelete(t *testing.T) {
+	fake := &fakeS3{t: t, object: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s, err := NewS3Store(server.URL, "events", "eu-west-1", "access", "secret")
+	if err != nil {
+		t.Fatalf("Unexpected error creating the store: %v", err)
+	}
+
+	keys := []string{
+		"archive/date=2019-01-01/source=https:%2F%2Fexample.com/1.json",
+		"archive/date=2019-01-01/source=https:%2F%2Fexample.com/2.json",
+		"archive/date=2019-01-02/source=https:%2F%2Fexample.com/3.json",
+		"other/4.json",
+		"with space/5.json",
+		"with+plus/6.json",
+	}
+	for _, key := range keys {
+		if err := s.Put(key, []byte("data")); err != nil {
+			t.Fatalf("Unexpected error from Put: %v", err)
+		}
+	}
+	got, err := s.List("archive/")
+	if err != nil {
+		t.Fatalf("Unexpected error from List: %v", err)
+	}
+	if want := keys[:3]; !reflect.DeepEqual(want, got) {
+		t.Errorf("Unexpected keys. Expected %v. Actual %v", want, got)
+	}
+
+	// Spaces and plus signs are escaped differently in queries and forms.
+	for _, key := range keys[4:] {
+		prefix := strings.SplitAfter(key, "/")[0]
+		got, err := s.List(prefix)
+		if err != nil {
+			t.Fatalf("Unexpected error from List(%q): %v", prefix, err)
+		}
+		if want := []string{key}; !reflect.DeepEqual(want, got) {
+			t.Errorf("Unexpected keys for %q. Expected %v. Actual %v", prefix, want, got)
+		}
+	}
+
+	if err := s.Delete(keys[0]); err != nil {
+		t.Fatalf("Unexpected error from Delete: %v", err)
+	}
+	if _, err := s.Get(keys[0]); err == nil {
+		t.Errorf("Expected the object to be deleted")
+	}
+}
+
+func TestNewS3StoreInvalidEndpoint(t *testing.T) {
+	if _, err := NewS3Store("minio:9000", "events", "us-east-1", "access", "secret"); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	envs := []string{ThresholdEnv, EndpointEnv, BucketEnv, RegionEnv, AccessKeyIDEnv, SecretAccessKeyEnv}
+	reset := func() {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+	}
+	reset()
+	defer reset()
+
+	if c, err := FromEnv(); c != nil || err != nil {
+		t.Errorf("Expected no claim check, got %v, %v", c, err)
+	}
+
+	os.Setenv(ThresholdEnv, "1000000")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error when the store is not configured")
+	}
+
+	os.Setenv(EndpointEnv, "http://minio.minio:9000")
+	os.Setenv(BucketEnv, "events")
+	os.Setenv(AccessKeyIDEnv, "access")
+	os.Setenv(SecretAccessKeyEnv, "secret")
+	c, err := FromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Threshold != 1000000 {
+		t.Errorf("Unexpected threshold %d", c.Threshold)
+	}
+	if s := c.Store.(*S3Store); s.region != defaultRegion || s.bucket != "events" {
+		t.Errorf("Unexpected store %+v", s)
+	}
+
+	os.Setenv(ThresholdEnv, "large")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error for an invalid threshold")
+	}
+}
@@ -0,0 +1,527 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: cloudevent.proto
+
+package cloudeventspb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type CloudEvent struct {
+	Id          string                               `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Source      string                               `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
+	SpecVersion string                               `protobuf:"bytes,3,opt,name=spec_version,json=specVersion" json:"spec_version,omitempty"`
+	Type        string                               `protobuf:"bytes,4,opt,name=type" json:"type,omitempty"`
+	Attributes  map[string]*CloudEventAttributeValue `protobuf:"bytes,5,rep,name=attributes" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Types that are valid to be assigned to Data:
+	//	*CloudEvent_BinaryData
+	//	*CloudEvent_TextData
+	Data                 isCloudEvent_Data `protobuf_oneof:"data"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CloudEvent) Reset()         { *m = CloudEvent{} }
+func (m *CloudEvent) String() string { return proto.CompactTextString(m) }
+func (*CloudEvent) ProtoMessage()    {}
+func (*CloudEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cloudevent_782f82ba79b4ce68, []int{0}
+}
+func (m *CloudEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloudEvent.Unmarshal(m, b)
+}
+func (m *CloudEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloudEvent.Marshal(b, m, deterministic)
+}
+func (dst *CloudEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloudEvent.Merge(dst, src)
+}
+func (m *CloudEvent) XXX_Size() int {
+	return xxx_messageInfo_CloudEvent.Size(m)
+}
+func (m *CloudEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloudEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloudEvent proto.InternalMessageInfo
+
+type isCloudEvent_Data interface {
+	isCloudEvent_Data()
+}
+
+type CloudEvent_BinaryData struct {
+	BinaryData []byte `protobuf:"bytes,6,opt,name=binary_data,json=binaryData,proto3,oneof"`
+}
+type CloudEvent_TextData struct {
+	TextData string `protobuf:"bytes,7,opt,name=text_data,json=textData,oneof"`
+}
+
+func (*CloudEvent_BinaryData) isCloudEvent_Data() {}
+func (*CloudEvent_TextData) isCloudEvent_Data()   {}
+
+func (m *CloudEvent) GetData() isCloudEvent_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *CloudEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CloudEvent) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *CloudEvent) GetSpecVersion() string {
+	if m != nil {
+		return m.SpecVersion
+	}
+	return ""
+}
+
+func (m *CloudEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *CloudEvent) GetAttributes() map[string]*CloudEventAttributeValue {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *CloudEvent) GetBinaryData() []byte {
+	if x, ok := m.GetData().(*CloudEvent_BinaryData); ok {
+		return x.BinaryData
+	}
+	return nil
+}
+
+func (m *CloudEvent) GetTextData() string {
+	if x, ok := m.GetData().(*CloudEvent_TextData); ok {
+		return x.TextData
+	}
+	return ""
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CloudEvent) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CloudEvent_OneofMarshaler, _CloudEvent_OneofUnmarshaler, _CloudEvent_OneofSizer, []interface{}{
+		(*CloudEvent_BinaryData)(nil),
+		(*CloudEvent_TextData)(nil),
+	}
+}
+
+func _CloudEvent_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CloudEvent)
+	// data
+	switch x := m.Data.(type) {
+	case *CloudEvent_BinaryData:
+		b.EncodeVarint(6<<3 | proto.WireBytes)
+		b.EncodeRawBytes(x.BinaryData)
+	case *CloudEvent_TextData:
+		b.EncodeVarint(7<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.TextData)
+	case nil:
+	default:
+		return fmt.Errorf("CloudEvent.Data has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CloudEvent_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CloudEvent)
+	switch tag {
+	case 6: // data.binary_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeRawBytes(true)
+		m.Data = &CloudEvent_BinaryData{x}
+		return true, err
+	case 7: // data.text_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Data = &CloudEvent_TextData{x}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CloudEvent_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CloudEvent)
+	// data
+	switch x := m.Data.(type) {
+	case *CloudEvent_BinaryData:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.BinaryData)))
+		n += len(x.BinaryData)
+	case *CloudEvent_TextData:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.TextData)))
+		n += len(x.TextData)
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type CloudEventAttributeValue struct {
+	// Types that are valid to be assigned to Attr:
+	//	*CloudEventAttributeValue_CeBoolean
+	//	*CloudEventAttributeValue_CeInteger
+	//	*CloudEventAttributeValue_CeString
+	//	*CloudEventAttributeValue_CeBytes
+	//	*CloudEventAttributeValue_CeUri
+	//	*CloudEventAttributeValue_CeUriRef
+	//	*CloudEventAttributeValue_CeTimestamp
+	Attr                 isCloudEventAttributeValue_Attr `protobuf_oneof:"attr"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *CloudEventAttributeValue) Reset()         { *m = CloudEventAttributeValue{} }
+func (m *CloudEventAttributeValue) String() string { return proto.CompactTextString(m) }
+func (*CloudEventAttributeValue) ProtoMessage()    {}
+func (*CloudEventAttributeValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cloudevent_782f82ba79b4ce68, []int{1}
+}
+func (m *CloudEventAttributeValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloudEventAttributeValue.Unmarshal(m, b)
+}
+func (m *CloudEventAttributeValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloudEventAttributeValue.Marshal(b, m, deterministic)
+}
+func (dst *CloudEventAttributeValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloudEventAttributeValue.Merge(dst, src)
+}
+func (m *CloudEventAttributeValue) XXX_Size() int {
+	return xxx_messageInfo_CloudEventAttributeValue.Size(m)
+}
+func (m *CloudEventAttributeValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloudEventAttributeValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloudEventAttributeValue proto.InternalMessageInfo
+
+type isCloudEventAttributeValue_Attr interface {
+	isCloudEventAttributeValue_Attr()
+}
+
+type CloudEventAttributeValue_CeBoolean struct {
+	CeBoolean bool `protobuf:"varint,1,opt,name=ce_boolean,json=ceBoolean,oneof"`
+}
+type CloudEventAttributeValue_CeInteger struct {
+	CeInteger int32 `protobuf:"varint,2,opt,name=ce_integer,json=ceInteger,oneof"`
+}
+type CloudEventAttributeValue_CeString struct {
+	CeString string `protobuf:"bytes,4,opt,name=ce_string,json=ceString,oneof"`
+}
+type CloudEventAttributeValue_CeBytes struct {
+	CeBytes []byte `protobuf:"bytes,5,opt,name=ce_bytes,json=ceBytes,proto3,oneof"`
+}
+type CloudEventAttributeValue_CeUri struct {
+	CeUri string `protobuf:"bytes,6,opt,name=ce_uri,json=ceUri,oneof"`
+}
+type CloudEventAttributeValue_CeUriRef struct {
+	CeUriRef string `protobuf:"bytes,7,opt,name=ce_uri_ref,json=ceUriRef,oneof"`
+}
+type CloudEventAttributeValue_CeTimestamp struct {
+	CeTimestamp *timestamp.Timestamp `protobuf:"bytes,8,opt,name=ce_timestamp,json=ceTimestamp,oneof"`
+}
+
+func (*CloudEventAttributeValue_CeBoolean) isCloudEventAttributeValue_Attr()   {}
+func (*CloudEventAttributeValue_CeInteger) isCloudEventAttributeValue_Attr()   {}
+func (*CloudEventAttributeValue_CeString) isCloudEventAttributeValue_Attr()    {}
+func (*CloudEventAttributeValue_CeBytes) isCloudEventAttributeValue_Attr()     {}
+func (*CloudEventAttributeValue_CeUri) isCloudEventAttributeValue_Attr()       {}
+func (*CloudEventAttributeValue_CeUriRef) isCloudEventAttributeValue_Attr()    {}
+func (*CloudEventAttributeValue_CeTimestamp) isCloudEventAttributeValue_Attr() {}
+
+func (m *CloudEventAttributeValue) GetAttr() isCloudEventAttributeValue_Attr {
+	if m != nil {
+		return m.Attr
+	}
+	return nil
+}
+
+func (m *CloudEventAttributeValue) GetCeBoolean() bool {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeBoolean); ok {
+		return x.CeBoolean
+	}
+	return false
+}
+
+func (m *CloudEventAttributeValue) GetCeInteger() int32 {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeInteger); ok {
+		return x.CeInteger
+	}
+	return 0
+}
+
+func (m *CloudEventAttributeValue) GetCeString() string {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeString); ok {
+		return x.CeString
+	}
+	return ""
+}
+
+func (m *CloudEventAttributeValue) GetCeBytes() []byte {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeBytes); ok {
+		return x.CeBytes
+	}
+	return nil
+}
+
+func (m *CloudEventAttributeValue) GetCeUri() string {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeUri); ok {
+		return x.CeUri
+	}
+	return ""
+}
+
+func (m *CloudEventAttributeValue) GetCeUriRef() string {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeUriRef); ok {
+		return x.CeUriRef
+	}
+	return ""
+}
+
+func (m *CloudEventAttributeValue) GetCeTimestamp() *timestamp.Timestamp {
+	if x, ok := m.GetAttr().(*CloudEventAttributeValue_CeTimestamp); ok {
+		return x.CeTimestamp
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CloudEventAttributeValue) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CloudEventAttributeValue_OneofMarshaler, _CloudEventAttributeValue_OneofUnmarshaler, _CloudEventAttributeValue_OneofSizer, []interface{}{
+		(*CloudEventAttributeValue_CeBoolean)(nil),
+		(*CloudEventAttributeValue_CeInteger)(nil),
+		(*CloudEventAttributeValue_CeString)(nil),
+		(*CloudEventAttributeValue_CeBytes)(nil),
+		(*CloudEventAttributeValue_CeUri)(nil),
+		(*CloudEventAttributeValue_CeUriRef)(nil),
+		(*CloudEventAttributeValue_CeTimestamp)(nil),
+	}
+}
+
+func _CloudEventAttributeValue_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CloudEventAttributeValue)
+	// attr
+	switch x := m.Attr.(type) {
+	case *CloudEventAttributeValue_CeBoolean:
+		t := uint64(0)
+		if x.CeBoolean {
+			t = 1
+		}
+		b.EncodeVarint(1<<3 | proto.WireVarint)
+		b.EncodeVarint(t)
+	case *CloudEventAttributeValue_CeInteger:
+		b.EncodeVarint(2<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.CeInteger))
+	case *CloudEventAttributeValue_CeString:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.CeString)
+	case *CloudEventAttributeValue_CeBytes:
+		b.EncodeVarint(5<<3 | proto.WireBytes)
+		b.EncodeRawBytes(x.CeBytes)
+	case *CloudEventAttributeValue_CeUri:
+		b.EncodeVarint(6<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.CeUri)
+	case *CloudEventAttributeValue_CeUriRef:
+		b.EncodeVarint(7<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.CeUriRef)
+	case *CloudEventAttributeValue_CeTimestamp:
+		b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CeTimestamp); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("CloudEventAttributeValue.Attr has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CloudEventAttributeValue_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CloudEventAttributeValue)
+	switch tag {
+	case 1: // attr.ce_boolean
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Attr = &CloudEventAttributeValue_CeBoolean{x != 0}
+		return true, err
+	case 2: // attr.ce_integer
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Attr = &CloudEventAttributeValue_CeInteger{int32(x)}
+		return true, err
+	case 4: // attr.ce_string
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Attr = &CloudEventAttributeValue_CeString{x}
+		return true, err
+	case 5: // attr.ce_bytes
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeRawBytes(true)
+		m.Attr = &CloudEventAttributeValue_CeBytes{x}
+		return true, err
+	case 6: // attr.ce_uri
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Attr = &CloudEventAttributeValue_CeUri{x}
+		return true, err
+	case 7: // attr.ce_uri_ref
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Attr = &CloudEventAttributeValue_CeUriRef{x}
+		return true, err
+	case 8: // attr.ce_timestamp
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(timestamp.Timestamp)
+		err := b.DecodeMessage(msg)
+		m.Attr = &CloudEventAttributeValue_CeTimestamp{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CloudEventAttributeValue_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CloudEventAttributeValue)
+	// attr
+	switch x := m.Attr.(type) {
+	case *CloudEventAttributeValue_CeBoolean:
+		n += 1 // tag and wire
+		n += 1
+	case *CloudEventAttributeValue_CeInteger:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(x.CeInteger))
+	case *CloudEventAttributeValue_CeString:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.CeString)))
+		n += len(x.CeString)
+	case *CloudEventAttributeValue_CeBytes:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.CeBytes)))
+		n += len(x.CeBytes)
+	case *CloudEventAttributeValue_CeUri:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.CeUri)))
+		n += len(x.CeUri)
+	case *CloudEventAttributeValue_CeUriRef:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.CeUriRef)))
+		n += len(x.CeUriRef)
+	case *CloudEventAttributeValue_CeTimestamp:
+		s := proto.Size(x.CeTimestamp)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+func init() {
+	proto.RegisterType((*CloudEvent)(nil), "io.cloudevents.v1.CloudEvent")
+	proto.RegisterMapType((map[string]*CloudEventAttributeValue)(nil), "io.cloudevents.v1.CloudEvent.AttributesEntry")
+	proto.RegisterType((*CloudEventAttributeValue)(nil), "io.cloudevents.v1.CloudEventAttributeValue")
+}
+
+func init() { proto.RegisterFile("cloudevent.proto", fileDescriptor_cloudevent_782f82ba79b4ce68) }
+
+var fileDescriptor_cloudevent_782f82ba79b4ce68 = []byte{
+	// 440 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0xc1, 0x6e, 0x13, 0x31,
+	0x10, 0x4d, 0x36, 0xc9, 0x36, 0x99, 0x0d, 0xb4, 0xcc, 0x01, 0xac, 0x20, 0x68, 0xda, 0x53, 0x24,
+	0x84, 0x2b, 0xca, 0x05, 0x71, 0x41, 0x0d, 0x54, 0x0a, 0x07, 0x2e, 0x0b, 0xf4, 0xc0, 0x65, 0xe5,
+	0x75, 0x26, 0x91, 0x21, 0x5d, 0xaf, 0xbc, 0xde, 0x88, 0xfd, 0x15, 0xbe, 0x92, 0x4f, 0x40, 0xb6,
+	0x93, 0x4d, 0x05, 0xa2, 0x37, 0xfb, 0xcd, 0x7b, 0x9e, 0xe7, 0x37, 0x03, 0x27, 0x72, 0xa3, 0xeb,
+	0x25, 0x6d, 0xa9, 0xb0, 0xbc, 0x34, 0xda, 0x6a, 0x7c, 0xa4, 0x34, 0x3f, 0x80, 0x15, 0xdf, 0xbe,
+	0x9a, 0x9c, 0xae, 0xb5, 0x5e, 0x6f, 0xe8, 0xc2, 0x13, 0xf2, 0x7a, 0x75, 0x61, 0xd5, 0x2d, 0x55,
+	0x56, 0xdc, 0x96, 0x41, 0x73, 0xfe, 0x3b, 0x02, 0x78, 0xef, 0x34, 0xd7, 0x4e, 0x83, 0x0f, 0x21,
+	0x52, 0x4b, 0xd6, 0x9d, 0x76, 0x67, 0xa3, 0x34, 0x52, 0x4b, 0x7c, 0x0c, 0x71, 0xa5, 0x6b, 0x23,
+	0x89, 0x45, 0x1e, 0xdb, 0xdd, 0xf0, 0x0c, 0xc6, 0x55, 0x49, 0x32, 0xdb, 0x92, 0xa9, 0x94, 0x2e,
+	0x58, 0xcf, 0x57, 0x13, 0x87, 0xdd, 0x04, 0x08, 0x11, 0xfa, 0xb6, 0x29, 0x89, 0xf5, 0x7d, 0xc9,
+	0x9f, 0xf1, 0x13, 0x80, 0xb0, 0xd6, 0xa8, 0xbc, 0xb6, 0x54, 0xb1, 0xc1, 0xb4, 0x37, 0x4b, 0x2e,
+	0x5f, 0xf2, 0x7f, 0x6c, 0xf3, 0x83, 0x23, 0x7e, 0xd5, 0xf2, 0xaf, 0x0b, 0x6b, 0x9a, 0xf4, 0xce,
+	0x03, 0x78, 0x06, 0x49, 0xae, 0x0a, 0x61, 0x9a, 0x6c, 0x29, 0xac, 0x60, 0xf1, 0xb4, 0x3b, 0x1b,
+	0x2f, 0x3a, 0x29, 0x04, 0xf0, 0x83, 0xb0, 0x02, 0x9f, 0xc1, 0xc8, 0xd2, 0x4f, 0x1b, 0x08, 0x47,
+	0xce, 0xca, 0xa2, 0x93, 0x0e, 0x1d, 0xe4, 0xca, 0x93, 0xef, 0x70, 0xfc, 0x57, 0x03, 0x3c, 0x81,
+	0xde, 0x0f, 0x6a, 0x76, 0x19, 0xb8, 0x23, 0x5e, 0xc1, 0x60, 0x2b, 0x36, 0x75, 0xc8, 0x20, 0xb9,
+	0x7c, 0x71, 0xaf, 0xe1, 0xf6, 0xb9, 0x1b, 0x27, 0x49, 0x83, 0xf2, 0x6d, 0xf4, 0xa6, 0x3b, 0x8f,
+	0xa1, 0xef, 0x5c, 0x9c, 0xff, 0x8a, 0x80, 0xfd, 0x8f, 0x8f, 0xa7, 0x00, 0x92, 0xb2, 0x5c, 0xeb,
+	0x0d, 0x89, 0xc2, 0x9b, 0x18, 0x2e, 0x3a, 0xe9, 0x48, 0xd2, 0x3c, 0x40, 0x3b, 0x82, 0x2a, 0x2c,
+	0xad, 0xc9, 0x78, 0x47, 0x83, 0x40, 0xf8, 0x18, 0x20, 0xf7, 0x63, 0x49, 0x59, 0x65, 0x8d, 0x2a,
+	0xd6, 0xac, 0xbf, 0xff, 0xb1, 0xa4, 0xcf, 0x1e, 0xc1, 0xa7, 0x30, 0x74, 0x0d, 0x9a, 0x30, 0x80,
+	0x10, 0xd8, 0x91, 0xa4, 0xb9, 0x03, 0xf0, 0x09, 0xc4, 0x92, 0xb2, 0xda, 0x28, 0x16, 0xef, 0x84,
+	0x03, 0x49, 0x5f, 0x8d, 0xc2, 0xe7, 0xbe, 0x6b, 0x6d, 0x54, 0x66, 0x68, 0x75, 0xc8, 0xd1, 0x17,
+	0x53, 0x5a, 0xe1, 0x3b, 0x18, 0x4b, 0xca, 0xda, 0xe5, 0x62, 0x43, 0x9f, 0xd4, 0x84, 0x87, 0xf5,
+	0xe3, 0xfb, 0xf5, 0xe3, 0x5f, 0xf6, 0x8c, 0x45, 0x27, 0x4d, 0x24, 0xb5, 0x57, 0x17, 0x8e, 0x1b,
+	0xec, 0xfc, 0xf8, 0xdb, 0x83, 0x3b, 0xd1, 0x96, 0x79, 0x1e, 0x7b, 0xed, 0xeb, 0x3f, 0x03, 0x00,
+	0x56, 0x01, 0x7b, 0x50, 0xef, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: subscriber.proto
+
+package cloudeventspb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type DeliverResponse struct {
+	// The event the subscriber replies with, if any.
+	Reply                *CloudEvent `protobuf:"bytes,1,opt,name=reply" json:"reply,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *DeliverResponse) Reset()         { *m = DeliverResponse{} }
+func (m *DeliverResponse) String() string { return proto.CompactTextString(m) }
+func (*DeliverResponse) ProtoMessage()    {}
+func (*DeliverResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_subscriber_88ccb79b131be5f5, []int{0}
+}
+func (m *DeliverResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliverResponse.Unmarshal(m, b)
+}
+func (m *DeliverResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliverResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeliverResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliverResponse.Merge(dst, src)
+}
+func (m *DeliverResponse) XXX_Size() int {
+	return xxx_messageInfo_DeliverResponse.Size(m)
+}
+func (m *DeliverResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliverResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliverResponse proto.InternalMessageInfo
+
+func (m *DeliverResponse) GetReply() *CloudEvent {
+	if m != nil {
+		return m.Reply
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DeliverResponse)(nil), "knative.eventing.v1alpha1.DeliverResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Subscriber service
+
+type SubscriberClient interface {
+	// Deliver delivers an event to the subscriber, which fails with an error
+	// status if it does not accept it.
+	Deliver(ctx context.Context, in *CloudEvent, opts ...grpc.CallOption) (*DeliverResponse, error)
+}
+
+type subscriberClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSubscriberClient(cc *grpc.ClientConn) SubscriberClient {
+	return &subscriberClient{cc}
+}
+
+func (c *subscriberClient) Deliver(ctx context.Context, in *CloudEvent, opts ...grpc.CallOption) (*DeliverResponse, error) {
+	out := new(DeliverResponse)
+	err := grpc.Invoke(ctx, "/knative.eventing.v1alpha1.Subscriber/Deliver", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Subscriber service
+
+type SubscriberServer interface {
+	// Deliver delivers an event to the subscriber, which fails with an error
+	// status if it does not accept it.
+	Deliver(context.Context, *CloudEvent) (*DeliverResponse, error)
+}
+
+func RegisterSubscriberServer(s *grpc.Server, srv SubscriberServer) {
+	s.RegisterService(&_Subscriber_serviceDesc, srv)
+}
+
+func _Subscriber_Deliver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloudEvent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriberServer).Deliver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/knative.eventing.v1alpha1.Subscriber/Deliver",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriberServer).Deliver(ctx, req.(*CloudEvent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Subscriber_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "knative.eventing.v1alpha1.Subscriber",
+	HandlerType: (*SubscriberServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Deliver",
+			Handler:    _Subscriber_Deliver_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "subscriber.proto",
+}
+
+func init() { proto.RegisterFile("subscriber.proto", fileDescriptor_subscriber_88ccb79b131be5f5) }
+
+var fileDescriptor_subscriber_88ccb79b131be5f5 = []byte{
+	// 171 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x28, 0x2e, 0x4d, 0x2a,
+	0x4e, 0x2e, 0xca, 0x4c, 0x4a, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0xcc, 0xce,
+	0x4b, 0x2c, 0xc9, 0x2c, 0x4b, 0xd5, 0x4b, 0x2d, 0x4b, 0xcd, 0x2b, 0xc9, 0xcc, 0x4b, 0xd7, 0x2b,
+	0x33, 0x4c, 0xcc, 0x29, 0xc8, 0x48, 0x34, 0x94, 0x12, 0x48, 0xce, 0xc9, 0x2f, 0x4d, 0x01, 0x8b,
+	0x43, 0x14, 0x2b, 0xb9, 0x71, 0xf1, 0xbb, 0xa4, 0xe6, 0x64, 0x96, 0xa5, 0x16, 0x05, 0xa5, 0x16,
+	0x17, 0xe4, 0xe7, 0x15, 0xa7, 0x0a, 0x19, 0x73, 0xb1, 0x16, 0xa5, 0x16, 0xe4, 0x54, 0x4a, 0x30,
+	0x2a, 0x30, 0x6a, 0x70, 0x1b, 0xc9, 0xea, 0x65, 0xe6, 0xeb, 0x21, 0xf4, 0x15, 0xeb, 0x95, 0x19,
+	0xea, 0x39, 0x83, 0xb8, 0xae, 0x20, 0x6e, 0x10, 0x44, 0xad, 0x51, 0x12, 0x17, 0x57, 0x30, 0xdc,
+	0x21, 0x42, 0x21, 0x5c, 0xec, 0x50, 0x53, 0x85, 0xf0, 0x6b, 0x97, 0xd2, 0xd2, 0xc3, 0xe9, 0x5a,
+	0x3d, 0x34, 0x87, 0x39, 0xf1, 0x47, 0xf1, 0x22, 0x19, 0x54, 0x90, 0x94, 0xc4, 0x06, 0xf6, 0x83,
+	0x31, 0x60, 0x00, 0x86, 0x12, 0x1d, 0xe7, 0x04, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/client/clientset/versioned"
+	"github.com/knative/eventing/pkg/provisioners"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterProvisioner is the Provisioner of a live cluster, whose controllers reconcile the
+// Channels. The suite must run where the addresses of the Channels resolve, and where the
+// dispatchers reach its subscribers, such as in a pod of the cluster.
+type ClusterProvisioner struct {
+	// Eventing is the client of the Channels.
+	Eventing versioned.Interface
+
+	// Kube is the client of the Services backing the Channels.
+	Kube kubernetes.Interface
+
+	// HTTPClient is the client events are sent with. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Provisioner = (*ClusterProvisioner)(nil)
+
+// Apply implements Provisioner.
+func (p *ClusterProvisioner) Apply(_ context.Context, c *eventingv1alpha1.Channel) error {
+	channels := p.Eventing.EventingV1alpha1().Channels(c.Namespace)
+	current, err := channels.Get(c.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = channels.Create(c)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	current.Spec = c.Spec
+	_, err = channels.Update(current)
+	return err
+}
+
+// Get implements Provisioner.
+func (p *ClusterProvisioner) Get(_ context.Context, namespace, name string) (*eventingv1alpha1.Channel, error) {
+	return p.Eventing.EventingV1alpha1().Channels(namespace).Get(name, metav1.GetOptions{})
+}
+
+// Delete implements Provisioner.
+func (p *ClusterProvisioner) Delete(_ context.Context, c *eventingv1alpha1.Channel) error {
+	err := p.Eventing.EventingV1alpha1().Channels(c.Namespace).Delete(c.Name, &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Deprovisioned implements Provisioner. The Channel is deprovisioned once its finalizers are
+// removed and its Service is garbage collected.
+func (p *ClusterProvisioner) Deprovisioned(_ context.Context, c *eventingv1alpha1.Channel) error {
+	_, err := p.Eventing.EventingV1alpha1().Channels(c.Namespace).Get(c.Name, metav1.GetOptions{})
+	if err == nil {
+		return fmt.Errorf("the Channel %s/%s still exists", c.Namespace, c.Name)
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	svc := provisioners.ChannelServiceName(c.Name)
+	_, err = p.Kube.CoreV1().Services(c.Namespace).Get(svc, metav1.GetOptions{})
+	if err == nil {
+		return fmt.Errorf("the Service %s/%s of the Channel still exists", c.Namespace, svc)
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Client implements Provisioner.
+func (p *ClusterProvisioner) Client() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return http.DefaultClient
+}
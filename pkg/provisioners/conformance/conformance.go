@@ -0,0 +1,358 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance is a test suite exercising any implementation of a channel provisioner. It
+// checks that the Channels of the provisioner become addressable, fan their events out to every
+// subscriber, eventually deliver the events their subscribers failed to accept, deliver every
+// accepted event at least once, and are cleaned up once deleted.
+//
+// Provisioners run it from a test, either in-process against fakes or against a live cluster with
+// ClusterProvisioner:
+//
+//	func TestConformance(t *testing.T) {
+//		s := &conformance.Suite{
+//			Provisioner: myProvisioner,
+//			Namespace:   "default",
+//			Ref:         &corev1.ObjectReference{...},
+//		}
+//		s.Run(t)
+//	}
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// DefaultTimeout is the default time the suite waits for a provisioner to converge.
+	DefaultTimeout = time.Minute
+
+	// DefaultInterval is the default time between the checks of the suite while it waits.
+	DefaultInterval = 100 * time.Millisecond
+
+	// eventCount is the number of events sent by the at-least-once test.
+	eventCount = 20
+)
+
+// Provisioner is the implementation of a channel provisioner exercised by a Suite.
+type Provisioner interface {
+	// Apply creates c, or updates the spec of the existing Channel named like c, and lets the
+	// provisioner reconcile it.
+	Apply(ctx context.Context, c *eventingv1alpha1.Channel) error
+
+	// Get returns the current state of the Channel named name in namespace.
+	Get(ctx context.Context, namespace, name string) (*eventingv1alpha1.Channel, error)
+
+	// Delete deletes c and lets the provisioner reconcile its deletion.
+	Delete(ctx context.Context, c *eventingv1alpha1.Channel) error
+
+	// Deprovisioned returns nil once nothing provisioned for c remains, and an error describing
+	// what remains otherwise.
+	Deprovisioned(ctx context.Context, c *eventingv1alpha1.Channel) error
+
+	// Client returns the client events are sent to the addresses of the Channels with.
+	Client() *http.Client
+}
+
+// Suite is the conformance test suite of a channel provisioner.
+type Suite struct {
+	// Provisioner is the implementation under test.
+	Provisioner Provisioner
+
+	// Namespace is the namespace the Channels of the suite are created in.
+	Namespace string
+
+	// Ref is the provisioner the Channels of the suite reference.
+	Ref *corev1.ObjectReference
+
+	// Arguments are the arguments of the Channels of the suite, if any.
+	Arguments *runtime.RawExtension
+
+	// Timeout is the time the suite waits for the provisioner to converge. It defaults to
+	// DefaultTimeout.
+	Timeout time.Duration
+
+	// Interval is the time between the checks of the suite while it waits. It defaults to
+	// DefaultInterval.
+	Interval time.Duration
+
+	// NewSubscriber starts the subscribers the Channels deliver their events to. It defaults to
+	// StartSubscriber, whose servers listen on the loopback interface; implementations running
+	// their dispatchers elsewhere set it to start subscribers they can reach.
+	NewSubscriber func(t *testing.T, fail FailurePolicy) *Subscriber
+}
+
+// Run runs every test of the suite as a subtest of t.
+func (s *Suite) Run(t *testing.T) {
+	t.Run("Addressable", s.TestAddressable)
+	t.Run("Fanout", s.TestFanout)
+	t.Run("Retry", s.TestRetry)
+	t.Run("AtLeastOnce", s.TestAtLeastOnce)
+	t.Run("Deletion", s.TestDeletion)
+}
+
+// TestAddressable checks that a Channel becomes ready with an address accepting events.
+func (s *Suite) TestAddressable(t *testing.T) {
+	c := s.provision(t, "addressable")
+	defer s.cleanup(t, c)
+
+	if c.Status.Address.Hostname == "" {
+		t.Fatalf("Channel %s/%s is ready without an address", c.Namespace, c.Name)
+	}
+	s.send(t, c, "addressable")
+}
+
+// TestFanout checks that every subscriber of a Channel receives its events.
+func (s *Suite) TestFanout(t *testing.T) {
+	subs := []*Subscriber{
+		s.newSubscriber(t, nil),
+		s.newSubscriber(t, nil),
+		s.newSubscriber(t, nil),
+	}
+	for _, sub := range subs {
+		defer sub.Close()
+	}
+	c := s.provision(t, "fanout", subs...)
+	defer s.cleanup(t, c)
+
+	s.send(t, c, "fanout")
+	for i, sub := range subs {
+		s.await(t, fmt.Sprintf("subscriber %d to receive the event", i), func() bool {
+			return sub.Accepted("fanout") > 0
+		})
+	}
+}
+
+// TestRetry checks that an event a subscriber fails to accept is eventually delivered to it,
+// either because the provisioner redelivers it, or because it rejects the event so that its
+// producer, here the suite, sends it again.
+func (s *Suite) TestRetry(t *testing.T) {
+	sub := s.newSubscriber(t, FailFirst(2))
+	defer sub.Close()
+	c := s.provision(t, "retry", sub)
+	defer s.cleanup(t, c)
+
+	s.send(t, c, "retry")
+	s.await(t, "the subscriber to accept the event", func() bool {
+		return sub.Accepted("retry") > 0
+	})
+	if sub.Received("retry") < 3 {
+		t.Errorf("The event was delivered %d times, expected at least 3", sub.Received("retry"))
+	}
+}
+
+// TestAtLeastOnce checks that every event accepted by a Channel is delivered at least once to
+// each of its subscribers, even when they fail intermittently.
+func (s *Suite) TestAtLeastOnce(t *testing.T) {
+	subs := []*Subscriber{
+		s.newSubscriber(t, nil),
+		s.newSubscriber(t, FailEvery(3)),
+	}
+	for _, sub := range subs {
+		defer sub.Close()
+	}
+	c := s.provision(t, "at-least-once", subs...)
+	defer s.cleanup(t, c)
+
+	var wg sync.WaitGroup
+	for i := 0; i < eventCount; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if err := s.sendUntilAccepted(c, id); err != nil {
+				t.Errorf("The Channel did not accept the event %s: %v", id, err)
+			}
+		}(fmt.Sprintf("at-least-once-%d", i))
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	for i, sub := range subs {
+		s.await(t, fmt.Sprintf("subscriber %d to accept every event", i), func() bool {
+			for j := 0; j < eventCount; j++ {
+				if sub.Accepted(fmt.Sprintf("at-least-once-%d", j)) == 0 {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// TestDeletion checks that a deleted Channel is deprovisioned, and that its address stops
+// accepting events.
+func (s *Suite) TestDeletion(t *testing.T) {
+	sub := s.newSubscriber(t, nil)
+	defer sub.Close()
+	c := s.provision(t, "deletion", sub)
+	s.send(t, c, "deletion")
+
+	ctx := context.Background()
+	if err := s.Provisioner.Delete(ctx, c); err != nil {
+		t.Fatalf("Unable to delete Channel %s/%s: %v", c.Namespace, c.Name, err)
+	}
+	var remaining error
+	s.await(t, "the Channel to be deprovisioned", func() bool {
+		remaining = s.Provisioner.Deprovisioned(ctx, c)
+		return remaining == nil
+	}, func() string {
+		return fmt.Sprint(remaining)
+	})
+	s.await(t, "the address of the Channel to stop accepting events", func() bool {
+		return s.sendOnce(c, "deletion-after") != nil
+	})
+	if n := sub.Received("deletion-after"); n != 0 {
+		t.Errorf("The subscriber received %d events sent after the deletion of the Channel", n)
+	}
+}
+
+// provision creates a Channel named after the test and subscribed by subs, and waits for it to
+// be ready.
+func (s *Suite) provision(t *testing.T, name string, subs ...*Subscriber) *eventingv1alpha1.Channel {
+	t.Helper()
+	c := &eventingv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.Namespace,
+			Name:      "conformance-" + name,
+		},
+		Spec: eventingv1alpha1.ChannelSpec{
+			Provisioner: s.Ref.DeepCopy(),
+			Arguments:   s.Arguments.DeepCopy(),
+		},
+	}
+	if len(subs) > 0 {
+		c.Spec.Subscribable = &eventingduck.Subscribable{}
+		for _, sub := range subs {
+			c.Spec.Subscribable.Subscribers = append(c.Spec.Subscribable.Subscribers, eventingduck.ChannelSubscriberSpec{
+				SubscriberURI: sub.URI(),
+			})
+		}
+	}
+
+	ctx := context.Background()
+	if err := s.Provisioner.Apply(ctx, c); err != nil {
+		t.Fatalf("Unable to apply Channel %s/%s: %v", c.Namespace, c.Name, err)
+	}
+	var current *eventingv1alpha1.Channel
+	s.await(t, "the Channel to be ready", func() bool {
+		var err error
+		current, err = s.Provisioner.Get(ctx, c.Namespace, c.Name)
+		return err == nil && current.Status.IsReady()
+	})
+	return current
+}
+
+// cleanup deletes c, ignoring errors as the test already failed or succeeded.
+func (s *Suite) cleanup(t *testing.T, c *eventingv1alpha1.Channel) {
+	if err := s.Provisioner.Delete(context.Background(), c); err != nil {
+		t.Logf("Unable to delete Channel %s/%s: %v", c.Namespace, c.Name, err)
+	}
+}
+
+// newSubscriber starts a subscriber failing according to fail. The caller closes it.
+func (s *Suite) newSubscriber(t *testing.T, fail FailurePolicy) *Subscriber {
+	if s.NewSubscriber != nil {
+		return s.NewSubscriber(t, fail)
+	}
+	return StartSubscriber(t, fail)
+}
+
+// send sends the event id to c until it is accepted, failing t if it is not within the timeout
+// of s.
+func (s *Suite) send(t *testing.T, c *eventingv1alpha1.Channel, id string) {
+	t.Helper()
+	if err := s.sendUntilAccepted(c, id); err != nil {
+		t.Fatalf("The Channel did not accept the event %s: %v", id, err)
+	}
+}
+
+// sendUntilAccepted sends the event id to c until it is accepted, as a producer retrying rejected
+// events would. It returns the last error if the event is not accepted within the timeout of s.
+func (s *Suite) sendUntilAccepted(c *eventingv1alpha1.Channel, id string) error {
+	var err error
+	if pollErr := s.poll(func() bool {
+		err = s.sendOnce(c, id)
+		return err == nil
+	}); pollErr != nil {
+		return err
+	}
+	return nil
+}
+
+// sendOnce sends the event id to c in binary mode, returning an error unless it is accepted.
+func (s *Suite) sendOnce(c *eventingv1alpha1.Channel, id string) error {
+	req, err := http.NewRequest(http.MethodPost, "http://"+c.Status.Address.Hostname+"/", bytes.NewBufferString(`{"conformance":true}`))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", provisioners.CloudEventsSpecVersion)
+	req.Header.Set("Ce-Id", id)
+	req.Header.Set("Ce-Type", "dev.knative.eventing.conformance")
+	req.Header.Set("Ce-Source", "/conformance")
+	res, err := s.Provisioner.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// await polls condition until it is true, failing t if it is not within the timeout of s. The
+// optional detail describes the last failed check.
+func (s *Suite) await(t *testing.T, what string, condition func() bool, detail ...func() string) {
+	t.Helper()
+	if err := s.poll(condition); err != nil {
+		msg := fmt.Sprintf("Timed out waiting for %s", what)
+		for _, d := range detail {
+			msg += ": " + d()
+		}
+		t.Fatal(msg)
+	}
+}
+
+// poll polls condition until it is true, returning an error if it is not within the timeout of s.
+func (s *Suite) poll(condition func() bool) error {
+	interval, timeout := s.Interval, s.Timeout
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return wait.PollImmediate(interval, timeout, func() (bool, error) {
+		return condition(), nil
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// FailurePolicy decides whether a subscriber fails the delivery of the event id. attempt is the
+// number of times the event was delivered to the subscriber, including this delivery.
+type FailurePolicy func(id string, attempt int) bool
+
+// FailFirst fails the first n deliveries of every event.
+func FailFirst(n int) FailurePolicy {
+	return func(_ string, attempt int) bool {
+		return attempt <= n
+	}
+}
+
+// FailEvery fails every nth delivery received by the subscriber, whatever its event.
+func FailEvery(n int) FailurePolicy {
+	var count int32
+	return func(string, int) bool {
+		return atomic.AddInt32(&count, 1)%int32(n) == 0
+	}
+}
+
+// Subscriber is a subscriber of the Channels of a Suite, recording the events delivered to it.
+type Subscriber struct {
+	uri   string
+	fail  FailurePolicy
+	close func()
+
+	mu       sync.Mutex
+	received map[string]int
+	accepted map[string]int
+}
+
+// StartSubscriber starts a Subscriber listening on the loopback interface. Deliveries are failed
+// according to fail, which may be nil to accept every event.
+func StartSubscriber(_ *testing.T, fail FailurePolicy) *Subscriber {
+	s := NewSubscriber("", fail, nil)
+	srv := httptest.NewServer(s)
+	s.uri = srv.URL + "/"
+	s.close = srv.Close
+	return s
+}
+
+// NewSubscriber creates a Subscriber whose events are delivered to uri, for implementations
+// serving it on their own. Deliveries are failed according to fail, which may be nil to accept
+// every event. close, if not nil, is called once the test using the Subscriber completes.
+func NewSubscriber(uri string, fail FailurePolicy, close func()) *Subscriber {
+	return &Subscriber{
+		uri:      uri,
+		fail:     fail,
+		close:    close,
+		received: make(map[string]int),
+		accepted: make(map[string]int),
+	}
+}
+
+// Close stops s.
+func (s *Subscriber) Close() {
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// URI is the subscriber URI of s.
+func (s *Subscriber) URI() string {
+	return s.uri
+}
+
+// Received returns the number of times the event id was delivered to s, including failed
+// deliveries.
+func (s *Subscriber) Received(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received[id]
+}
+
+// Accepted returns the number of times s accepted the event id.
+func (s *Subscriber) Accepted(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted[id]
+}
+
+// ServeHTTP records the binary mode event of r, failing it according to the FailurePolicy of s.
+func (s *Subscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	io.Copy(ioutil.Discard, r.Body)
+	id := r.Header.Get("Ce-Id")
+
+	s.mu.Lock()
+	s.received[id]++
+	attempt := s.received[id]
+	s.mu.Unlock()
+
+	if s.fail != nil && s.fail(id, attempt) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	s.mu.Lock()
+	s.accepted[id]++
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dedup suppresses the redelivery of events to a subscriber. An event is identified by its
+// id and source, and is not dispatched again to a subscriber it was dispatched to within a window.
+// Together with idempotent subscribers, this approximates exactly-once delivery over channels
+// whose backends deliver at least once.
+package dedup
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultSize is the number of events a Window remembers when no size is configured.
+const DefaultSize = 10000
+
+var messagesDeduplicated = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "channel",
+	Subsystem: "dispatcher",
+	Name:      "events_deduplicated_total",
+	Help:      "The number of redelivered events that were not dispatched again.",
+})
+
+func init() {
+	prometheus.MustRegister(messagesDeduplicated)
+}
+
+// Window remembers the events dispatched to each subscriber for a duration. It is bounded: when
+// it is full, the least recently dispatched event is forgotten. A nil *Window deduplicates
+// nothing.
+type Window struct {
+	size     int
+	duration time.Duration
+	now      func() time.Time
+
+	lock    sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key        string
+	dispatched time.Time
+}
+
+// NewWindow creates a Window remembering at most size events for duration. If size is not
+// positive, DefaultSize is used.
+func NewWindow(size int, duration time.Duration) *Window {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Window{
+		size:     size,
+		duration: duration,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Dispatch calls dispatch, unless an event with the id and source of m was successfully
+// dispatched to subscriber within the window. Events are only remembered when dispatch succeeds,
+// so failed deliveries are still retried. Events without an id are always dispatched.
+func (w *Window) Dispatch(subscriber string, m *provisioners.Message, dispatch func() error) error {
+	if w == nil {
+		return dispatch()
+	}
+	attrs := m.Attributes()
+	if attrs["id"] == "" {
+		return dispatch()
+	}
+	key := strings.Join([]string{subscriber, attrs["source"], attrs["id"]}, "\n")
+	if w.seen(key) {
+		messagesDeduplicated.Inc()
+		return nil
+	}
+	if err := dispatch(); err != nil {
+		return err
+	}
+	w.add(key)
+	return nil
+}
+
+func (w *Window) seen(key string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	e, ok := w.entries[key]
+	if !ok {
+		return false
+	}
+	if w.now().Sub(e.Value.(*entry).dispatched) > w.duration {
+		w.order.Remove(e)
+		delete(w.entries, key)
+		return false
+	}
+	return true
+}
+
+func (w *Window) add(key string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	now := w.now()
+	if e, ok := w.entries[key]; ok {
+		e.Value.(*entry).dispatched = now
+		w.order.MoveToFront(e)
+		return
+	}
+	w.entries[key] = w.order.PushFront(&entry{key: key, dispatched: now})
+	for w.order.Len() > w.size {
+		oldest := w.order.Back()
+		w.order.Remove(oldest)
+		delete(w.entries, oldest.Value.(*entry).key)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+func event(id, source string) *provisioners.Message {
+	return &provisioners.Message{
+		Headers: map[string]string{
+			"ce-specversion": "1.0",
+			"ce-id":          id,
+			"ce-source":      source,
+			"ce-type":        "com.example.order",
+		},
+	}
+}
+
+// dispatches counts the calls to dispatch, which fails when err is set.
+type dispatches struct {
+	count int
+	err   error
+}
+
+func (d *dispatches) dispatch() error {
+	d.count++
+	return d.err
+}
+
+func TestDispatch(t *testing.T) {
+	testCases := map[string]struct {
+		first  *provisioners.Message
+		second *provisioners.Message
+		// secondSubscriber is the subscriber of the second event. It defaults to the subscriber of
+		// the first.
+		secondSubscriber string
+		advance          time.Duration
+		firstErr         error
+		want             int
+	}{
+		"duplicate": {
+			first:  event("1", "/orders"),
+			second: event("1", "/orders"),
+			want:   1,
+		},
+		"different id": {
+			first:  event("1", "/orders"),
+			second: event("2", "/orders"),
+			want:   2,
+		},
+		"different source": {
+			first:  event("1", "/orders"),
+			second: event("1", "/payments"),
+			want:   2,
+		},
+		"different subscriber": {
+			first:            event("1", "/orders"),
+			second:           event("1", "/orders"),
+			secondSubscriber: "other",
+			want:             2,
+		},
+		"outside the window": {
+			first:   event("1", "/orders"),
+			second:  event("1", "/orders"),
+			advance: 2 * time.Minute,
+			want:    2,
+		},
+		"first dispatch failed": {
+			first:    event("1", "/orders"),
+			second:   event("1", "/orders"),
+			firstErr: errors.New("unavailable"),
+			want:     2,
+		},
+		"no id": {
+			first:  &provisioners.Message{Headers: map[string]string{"ce-specversion": "1.0"}},
+			second: &provisioners.Message{Headers: map[string]string{"ce-specversion": "1.0"}},
+			want:   2,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			now := time.Now()
+			w := NewWindow(0, time.Minute)
+			w.now = func() time.Time {
+				return now
+			}
+			d := &dispatches{err: tc.firstErr}
+			if err := w.Dispatch("subscriber", tc.first, d.dispatch); err != tc.firstErr {
+				t.Errorf("Unexpected error, expected %v, got %v", tc.firstErr, err)
+			}
+			d.err = nil
+			now = now.Add(tc.advance)
+			subscriber := tc.secondSubscriber
+			if subscriber == "" {
+				subscriber = "subscriber"
+			}
+			if err := w.Dispatch(subscriber, tc.second, d.dispatch); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if d.count != tc.want {
+				t.Errorf("Unexpected dispatches, expected %d, got %d", tc.want, d.count)
+			}
+		})
+	}
+}
+
+func TestDispatchBounded(t *testing.T) {
+	w := NewWindow(2, time.Minute)
+	d := &dispatches{}
+	for _, id := range []string{"1", "2", "3", "1"} {
+		w.Dispatch("subscriber", event(id, "/orders"), d.dispatch)
+	}
+	// The window only remembers two events, so "1" was forgotten when "3" was dispatched.
+	if d.count != 4 {
+		t.Errorf("Unexpected dispatches, expected 4, got %d", d.count)
+	}
+}
+
+func TestDispatchNilWindow(t *testing.T) {
+	var w *Window
+	d := &dispatches{}
+	w.Dispatch("subscriber", event("1", "/orders"), d.dispatch)
+	w.Dispatch("subscriber", event("1", "/orders"), d.dispatch)
+	if d.count != 2 {
+		t.Errorf("Unexpected dispatches, expected 2, got %d", d.count)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller"
+	"github.com/knative/eventing/pkg/system"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DispatcherScopeEnv is the environment variable of the channel controllers selecting where the
+// dispatchers of their Channels run, a DispatcherScope. It defaults to ClusterDispatcherScope.
+const DispatcherScopeEnv = "DISPATCHER_SCOPE"
+
+// DispatcherScope is where the dispatchers of the Channels of a provisioner run.
+type DispatcherScope string
+
+const (
+	// ClusterDispatcherScope dispatches the events of every Channel in the dispatcher of the
+	// provisioner in the knative-eventing namespace.
+	ClusterDispatcherScope DispatcherScope = "cluster"
+	// NamespaceDispatcherScope dispatches the events of the Channels of each namespace in a
+	// dispatcher running in that namespace, isolating the data plane of the tenants.
+	NamespaceDispatcherScope DispatcherScope = "namespace"
+)
+
+// DispatcherScopeFromEnv returns the DispatcherScope set by DispatcherScopeEnv.
+func DispatcherScopeFromEnv() (DispatcherScope, error) {
+	switch v := DispatcherScope(os.Getenv(DispatcherScopeEnv)); v {
+	case "", ClusterDispatcherScope:
+		return ClusterDispatcherScope, nil
+	case NamespaceDispatcherScope:
+		return v, nil
+	default:
+		return "", fmt.Errorf("%s must be %q or %q, got %q", DispatcherScopeEnv, ClusterDispatcherScope, NamespaceDispatcherScope, v)
+	}
+}
+
+// DestinationHost returns the DestinationHost routing the events of the Channels to their
+// dispatcher in scope.
+func (s DispatcherScope) DestinationHost() DestinationHost {
+	if s == NamespaceDispatcherScope {
+		return NamespaceDispatcherHost
+	}
+	return ClusterDispatcherHost
+}
+
+// DestinationHost returns the host the VirtualService of c routes its events to.
+type DestinationHost func(c *eventingv1alpha1.Channel) string
+
+// ClusterDispatcherHost routes the events of c to the dispatcher Service of its provisioner in the
+// knative-eventing namespace.
+func ClusterDispatcherHost(c *eventingv1alpha1.Channel) string {
+	return controller.ServiceHostName(ChannelDispatcherServiceName(c.Spec.Provisioner.Name), system.Namespace)
+}
+
+// NamespaceDispatcherHost routes the events of c to the dispatcher Service of its provisioner in the
+// namespace of c.
+func NamespaceDispatcherHost(c *eventingv1alpha1.Channel) string {
+	return controller.ServiceHostName(ChannelDispatcherServiceName(c.Spec.Provisioner.Name), c.Namespace)
+}
+
+// CreateNamespaceDispatcherService creates or updates the Service of the dispatcher of the
+// provisioner of c in the namespace of c, used by NamespaceDispatcherScope. It is shared by the
+// Channels of the namespace, so it is not owned by c, which Events are recorded on.
+func CreateNamespaceDispatcherService(ctx context.Context, client runtimeClient.Client, c *eventingv1alpha1.Channel) (*corev1.Service, error) {
+	svcKey := types.NamespacedName{
+		Namespace: c.Namespace,
+		Name:      ChannelDispatcherServiceName(c.Spec.Provisioner.Name),
+	}
+	return createK8sService(ctx, client, c, svcKey, newNamespaceDispatcherService(c.Spec.Provisioner.Name, c.Namespace))
+}
+
+// newNamespaceDispatcherService creates a new Service for the dispatcher of the
+// ClusterChannelProvisioner ccpName in namespace.
+func newNamespaceDispatcherService(ccpName, namespace string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ChannelDispatcherServiceName(ccpName),
+			Namespace: namespace,
+			Labels:    dispatcherServiceLabels(ccpName),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: DispatcherLabels(ccpName),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestDispatcherScopeFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		env     string
+		want    DispatcherScope
+		wantErr bool
+	}{
+		"unset": {
+			want: ClusterDispatcherScope,
+		},
+		"cluster": {
+			env:  "cluster",
+			want: ClusterDispatcherScope,
+		},
+		"namespace": {
+			env:  "namespace",
+			want: NamespaceDispatcherScope,
+		},
+		"invalid": {
+			env:     "node",
+			wantErr: true,
+		},
+	}
+	defer os.Unsetenv(DispatcherScopeEnv)
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(DispatcherScopeEnv, tc.env)
+			got, err := DispatcherScopeFromEnv()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("Unexpected scope. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDispatcherScopeDestinationHost(t *testing.T) {
+	c := getNewChannel()
+	testCases := map[DispatcherScope]string{
+		"":                       fmt.Sprintf("%s-dispatcher.knative-eventing.svc.cluster.local", clusterChannelProvisionerName),
+		ClusterDispatcherScope:   fmt.Sprintf("%s-dispatcher.knative-eventing.svc.cluster.local", clusterChannelProvisionerName),
+		NamespaceDispatcherScope: fmt.Sprintf("%s-dispatcher.%s.svc.cluster.local", clusterChannelProvisionerName, testNS),
+	}
+	for scope, want := range testCases {
+		if got := scope.DestinationHost()(c); got != want {
+			t.Errorf("Unexpected host of scope %q. Expected %q. Actual %q", scope, want, got)
+		}
+	}
+}
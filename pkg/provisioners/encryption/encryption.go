@@ -0,0 +1,301 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package encryption provides the provisioners.Encrypter of the durable channels. Envelope
+// encrypts the data of every event with AES-256-GCM under a data key, which is itself encrypted by
+// a key encryption key: a LocalKey read from a Secret, or a CloudKMSKey.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// The environment variables configuring the encryption of a dispatcher. At most one of them may be
+// set.
+const (
+	// KeyFileEnv is the path of a file, typically mounted from a Secret, holding base64 encoded
+	// AES keys of 16, 24 or 32 bytes, one per line. The first key encrypts, all of them decrypt, so
+	// that the key can be rotated by adding a new first line.
+	KeyFileEnv = "ENCRYPTION_KEY_FILE"
+	// KMSKeyEnv is the resource name of a Cloud KMS key,
+	// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>.
+	KMSKeyEnv = "ENCRYPTION_KMS_KEY"
+)
+
+const (
+	// envelopeVersion is the first byte of the ciphertexts of an Envelope.
+	envelopeVersion = 1
+
+	// dataKeyTTL is how long a data key is used before a new one is generated, so that the key
+	// encryption key, which may be remote, is not used for every event.
+	dataKeyTTL = 5 * time.Minute
+
+	// maxCachedDataKeys bounds the number of decrypted data keys an Envelope keeps.
+	maxCachedDataKeys = 1024
+)
+
+// KeyEncryptionKey encrypts the data keys of an Envelope.
+type KeyEncryptionKey interface {
+	// WrapKey encrypts a data key.
+	WrapKey(key []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key encrypted by WrapKey.
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// Envelope encrypts data with AES-256-GCM under data keys encrypted by a KeyEncryptionKey. The
+// encrypted data key is part of the ciphertext, so the key encryption key is all that is needed
+// to decrypt it.
+type Envelope struct {
+	kek KeyEncryptionKey
+	now func() time.Time
+
+	lock      sync.Mutex
+	current   *dataKey
+	unwrapped map[string]cipher.AEAD
+}
+
+var _ provisioners.Encrypter = (*Envelope)(nil)
+
+type dataKey struct {
+	aead    cipher.AEAD
+	wrapped []byte
+	created time.Time
+}
+
+// NewEnvelope creates an Envelope whose data keys are encrypted by kek.
+func NewEnvelope(kek KeyEncryptionKey) *Envelope {
+	return &Envelope{
+		kek:       kek,
+		now:       time.Now,
+		unwrapped: make(map[string]cipher.AEAD),
+	}
+}
+
+// Encrypt encrypts plaintext under the current data key. The ciphertext is the version byte, the
+// length of the encrypted data key as two bytes and the encrypted data key, followed by the nonce
+// and the sealed plaintext.
+func (e *Envelope) Encrypt(plaintext []byte) ([]byte, error) {
+	key, err := e.dataKey()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 3, 3+len(key.wrapped)+len(nonce)+len(plaintext)+key.aead.Overhead())
+	out[0] = envelopeVersion
+	binary.BigEndian.PutUint16(out[1:3], uint16(len(key.wrapped)))
+	out = append(out, key.wrapped...)
+	out = append(out, nonce...)
+	return key.aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts a ciphertext created by Encrypt.
+func (e *Envelope) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 3 || ciphertext[0] != envelopeVersion {
+		return nil, errors.New("unsupported ciphertext")
+	}
+	n := int(binary.BigEndian.Uint16(ciphertext[1:3]))
+	if len(ciphertext) < 3+n {
+		return nil, errors.New("truncated ciphertext")
+	}
+	wrapped, rest := ciphertext[3:3+n], ciphertext[3+n:]
+	aead, err := e.unwrap(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("truncated ciphertext")
+	}
+	return aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], nil)
+}
+
+// dataKey returns the current data key, generating a new one if it is older than dataKeyTTL.
+func (e *Envelope) dataKey() (*dataKey, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	now := e.now()
+	if e.current != nil && now.Sub(e.current.created) < dataKeyTTL {
+		return e.current, nil
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	wrapped, err := e.kek.WrapKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt the data key: %v", err)
+	}
+	if len(wrapped) > 0xffff {
+		return nil, errors.New("the encrypted data key is too long")
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	e.current = &dataKey{aead: aead, wrapped: wrapped, created: now}
+	return e.current, nil
+}
+
+// unwrap returns the data key encrypted as wrapped, decrypting it with the key encryption key if
+// it is not cached.
+func (e *Envelope) unwrap(wrapped []byte) (cipher.AEAD, error) {
+	e.lock.Lock()
+	aead, ok := e.unwrapped[string(wrapped)]
+	e.lock.Unlock()
+	if ok {
+		return aead, nil
+	}
+	key, err := e.kek.UnwrapKey(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the data key: %v", err)
+	}
+	if aead, err = newAEAD(key); err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	if len(e.unwrapped) >= maxCachedDataKeys {
+		e.unwrapped = make(map[string]cipher.AEAD)
+	}
+	e.unwrapped[string(wrapped)] = aead
+	e.lock.Unlock()
+	return aead, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// LocalKey is a KeyEncryptionKey made of AES keys. The first key wraps data keys, and any of them
+// unwraps them.
+type LocalKey struct {
+	keys []localKey
+}
+
+type localKey struct {
+	id   []byte
+	aead cipher.AEAD
+}
+
+// localKeyIDSize is the size of the prefix identifying the key that wrapped a data key.
+const localKeyIDSize = 4
+
+var _ KeyEncryptionKey = (*LocalKey)(nil)
+
+// NewLocalKey creates a LocalKey from AES keys of 16, 24 or 32 bytes.
+func NewLocalKey(keys ...[]byte) (*LocalKey, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no key")
+	}
+	l := &LocalKey{}
+	for _, k := range keys {
+		aead, err := newAEAD(k)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(k)
+		l.keys = append(l.keys, localKey{id: sum[:localKeyIDSize], aead: aead})
+	}
+	return l, nil
+}
+
+// WrapKey encrypts key with the first key, prefixing it with the ID of that key and the nonce.
+func (l *LocalKey) WrapKey(key []byte) ([]byte, error) {
+	k := l.keys[0]
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := append(append([]byte{}, k.id...), nonce...)
+	return k.aead.Seal(out, nonce, key, nil), nil
+}
+
+// UnwrapKey decrypts a key wrapped by WrapKey, with the key it was wrapped with.
+func (l *LocalKey) UnwrapKey(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < localKeyIDSize {
+		return nil, errors.New("truncated key")
+	}
+	for _, k := range l.keys {
+		if !bytes.Equal(wrapped[:localKeyIDSize], k.id) {
+			continue
+		}
+		rest := wrapped[localKeyIDSize:]
+		if len(rest) < k.aead.NonceSize() {
+			return nil, errors.New("truncated key")
+		}
+		return k.aead.Open(nil, rest[:k.aead.NonceSize()], rest[k.aead.NonceSize():], nil)
+	}
+	return nil, errors.New("the key was wrapped with an unknown key")
+}
+
+// FromEnv returns the Envelope configured by the environment variables of the process, or nil if
+// none is set.
+func FromEnv() (*Envelope, error) {
+	keyFile, kmsKey := os.Getenv(KeyFileEnv), os.Getenv(KMSKeyEnv)
+	switch {
+	case keyFile != "" && kmsKey != "":
+		return nil, fmt.Errorf("only one of %s and %s may be set", KeyFileEnv, KMSKeyEnv)
+	case keyFile != "":
+		b, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %v", KeyFileEnv, err)
+		}
+		var keys [][]byte
+		for _, line := range strings.Split(string(b), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			k, err := base64.StdEncoding.DecodeString(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key in %s: %v", keyFile, err)
+			}
+			keys = append(keys, k)
+		}
+		kek, err := NewLocalKey(keys...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in %s: %v", keyFile, err)
+		}
+		return NewEnvelope(kek), nil
+	case kmsKey != "":
+		kek, err := NewCloudKMSKey(kmsKey)
+		if err != nil {
+			return nil, err
+		}
+		return NewEnvelope(kek), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// countingKey is a KeyEncryptionKey counting the data keys it wraps.
+type countingKey struct {
+	KeyEncryptionKey
+	wrapped int
+}
+
+func (k *countingKey) WrapKey(key []byte) ([]byte, error) {
+	k.wrapped++
+	return k.KeyEncryptionKey.WrapKey(key)
+}
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestEnvelope(t *testing.T) {
+	kek, err := NewLocalKey(testKey(1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	counting := &countingKey{KeyEncryptionKey: kek}
+	now := time.Now()
+	e := NewEnvelope(counting)
+	e.now = func() time.Time { return now }
+
+	plaintext := []byte(`{"total": 10}`)
+	encrypt := func() []byte {
+		ciphertext, err := e.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Unexpected error encrypting: %v", err)
+		}
+		if bytes.Contains(ciphertext, plaintext) {
+			t.Fatalf("The ciphertext contains the plaintext: %q", ciphertext)
+		}
+		got, err := NewEnvelope(kek).Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Unexpected error decrypting: %v", err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("Unexpected plaintext. Expected %q, actual %q", plaintext, got)
+		}
+		return ciphertext
+	}
+
+	first := encrypt()
+	if second := encrypt(); bytes.Equal(first, second) {
+		t.Errorf("Expected a new nonce for every ciphertext")
+	}
+	if counting.wrapped != 1 {
+		t.Errorf("Expected the data key to be reused, %d keys were wrapped", counting.wrapped)
+	}
+	now = now.Add(dataKeyTTL)
+	encrypt()
+	if counting.wrapped != 2 {
+		t.Errorf("Expected a new data key after %v, %d keys were wrapped", dataKeyTTL, counting.wrapped)
+	}
+
+	tampered := append([]byte{}, first...)
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := e.Decrypt(tampered); err == nil {
+		t.Errorf("Expected an error decrypting a tampered ciphertext")
+	}
+	if _, err := e.Decrypt(first[:10]); err == nil {
+		t.Errorf("Expected an error decrypting a truncated ciphertext")
+	}
+}
+
+func TestLocalKeyRotation(t *testing.T) {
+	old, err := NewLocalKey(testKey(1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rotated, err := NewLocalKey(testKey(2), testKey(1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	unknown, err := NewLocalKey(testKey(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ciphertext, err := NewEnvelope(old).Encrypt([]byte("data"))
+	if err != nil {
+		t.Fatalf("Unexpected error encrypting: %v", err)
+	}
+	if got, err := NewEnvelope(rotated).Decrypt(ciphertext); err != nil || string(got) != "data" {
+		t.Errorf("Expected the data encrypted with the previous key to be decrypted, got %q, %v", got, err)
+	}
+	if _, err := NewEnvelope(unknown).Decrypt(ciphertext); err == nil {
+		t.Errorf("Expected an error decrypting with an unknown key")
+	}
+	if _, err := NewLocalKey([]byte("short")); err == nil {
+		t.Errorf("Expected an error creating a key of an invalid size")
+	}
+}
+
+func TestCloudKMSKey(t *testing.T) {
+	const name = "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// The fake key reverses the bytes of the base64 encoding.
+		reverse := func(s string) string {
+			b := []byte(s)
+			for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+			return base64.StdEncoding.EncodeToString(b)
+		}
+		decode := func(s string) string {
+			b, _ := base64.StdEncoding.DecodeString(s)
+			return string(b)
+		}
+		switch r.URL.Path {
+		case "/v1/" + name + ":encrypt":
+			json.NewEncoder(w).Encode(map[string]string{"ciphertext": reverse(decode(req["plaintext"]))})
+		case "/v1/" + name + ":decrypt":
+			json.NewEncoder(w).Encode(map[string]string{"plaintext": reverse(decode(req["ciphertext"]))})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	service, err := cloudkms.New(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("Unable to create the Cloud KMS client: %v", err)
+	}
+	service.BasePath = s.URL + "/"
+	k := &CloudKMSKey{name: name, keys: cloudkms.NewProjectsLocationsKeyRingsCryptoKeysService(service)}
+	e := NewEnvelope(k)
+	ciphertext, err := e.Encrypt([]byte("data"))
+	if err != nil {
+		t.Fatalf("Unexpected error encrypting: %v", err)
+	}
+	if got, err := NewEnvelope(k).Decrypt(ciphertext); err != nil || string(got) != "data" {
+		t.Errorf("Unexpected decrypted data %q, %v", got, err)
+	}
+
+	k.name = "projects/p/locations/global/keyRings/r/cryptoKeys/unknown"
+	if _, err := NewEnvelope(k).Encrypt([]byte("data")); err == nil {
+		t.Errorf("Expected an error when Cloud KMS rejects the request")
+	}
+	if _, err := NewCloudKMSKey("my-key"); err == nil {
+		t.Errorf("Expected an error for an invalid key name")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	if err != nil {
+		t.Fatalf("Unable to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "keys")
+	keys := base64.StdEncoding.EncodeToString(testKey(2)) + "\n" + base64.StdEncoding.EncodeToString(testKey(1)) + "\n"
+	if err := ioutil.WriteFile(keyFile, []byte(keys), 0600); err != nil {
+		t.Fatalf("Unable to write the keys: %v", err)
+	}
+	defer os.Unsetenv(KeyFileEnv)
+	defer os.Unsetenv(KMSKeyEnv)
+
+	if e, err := FromEnv(); e != nil || err != nil {
+		t.Errorf("Expected no Envelope when no key is configured, got %v, %v", e, err)
+	}
+
+	os.Setenv(KeyFileEnv, keyFile)
+	e, err := FromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	old, _ := NewLocalKey(testKey(1))
+	ciphertext, _ := NewEnvelope(old).Encrypt([]byte("data"))
+	if got, err := e.Decrypt(ciphertext); err != nil || string(got) != "data" {
+		t.Errorf("Expected the data encrypted with the second key to be decrypted, got %q, %v", got, err)
+	}
+
+	os.Setenv(KMSKeyEnv, "projects/p/locations/global/keyRings/r/cryptoKeys/k")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error when both %s and %s are set", KeyFileEnv, KMSKeyEnv)
+	}
+	os.Unsetenv(KMSKeyEnv)
+
+	if err := ioutil.WriteFile(keyFile, []byte("not base64!"), 0600); err != nil {
+		t.Fatalf("Unable to write the keys: %v", err)
+	}
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error for an invalid key file")
+	}
+}
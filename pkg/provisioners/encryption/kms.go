@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	"golang.org/x/oauth2/google"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+)
+
+// cloudKMSKeyName matches the resource names of Cloud KMS keys.
+var cloudKMSKeyName = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// CloudKMSKey is a KeyEncryptionKey held by Cloud KMS. It authenticates with the Application
+// Default Credentials of the process.
+type CloudKMSKey struct {
+	name string
+	keys *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+}
+
+var _ KeyEncryptionKey = (*CloudKMSKey)(nil)
+
+// NewCloudKMSKey creates a CloudKMSKey for the key with the resource name name.
+func NewCloudKMSKey(name string) (*CloudKMSKey, error) {
+	if !cloudKMSKeyName.MatchString(name) {
+		return nil, fmt.Errorf("invalid Cloud KMS key %q, expected projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>", name)
+	}
+	c, err := google.DefaultClient(context.Background(), cloudkms.CloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the Cloud KMS client: %v", err)
+	}
+	s, err := cloudkms.New(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the Cloud KMS client: %v", err)
+	}
+	return &CloudKMSKey{
+		name: name,
+		keys: cloudkms.NewProjectsLocationsKeyRingsCryptoKeysService(s),
+	}, nil
+}
+
+// WrapKey encrypts key with the Cloud KMS key.
+func (k *CloudKMSKey) WrapKey(key []byte) ([]byte, error) {
+	res, err := k.keys.Encrypt(k.name, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(key),
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt with Cloud KMS: %v", err)
+	}
+	return base64.StdEncoding.DecodeString(res.Ciphertext)
+}
+
+// UnwrapKey decrypts a key encrypted by WrapKey. Cloud KMS finds the version of the key it was
+// encrypted with, so keys keep being unwrapped after the Cloud KMS key is rotated.
+func (k *CloudKMSKey) UnwrapKey(wrapped []byte) ([]byte, error) {
+	res, err := k.keys.Decrypt(k.name, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt with Cloud KMS: %v", err)
+	}
+	return base64.StdEncoding.DecodeString(res.Plaintext)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// The reasons of the Kubernetes Events emitted on Channels and ClusterChannelProvisioners for the
+// resources reconciled on their behalf.
+const (
+	ServiceCreated                     = "ServiceCreated"
+	ServiceUpdated                     = "ServiceUpdated"
+	ServiceReconcileFailed             = "ServiceReconcileFailed"
+	VirtualServiceCreated              = "VirtualServiceCreated"
+	VirtualServiceUpdated              = "VirtualServiceUpdated"
+	VirtualServiceReconcileFailed      = "VirtualServiceReconcileFailed"
+	NetworkPolicyCreated               = "NetworkPolicyCreated"
+	NetworkPolicyUpdated               = "NetworkPolicyUpdated"
+	NetworkPolicyDeleted               = "NetworkPolicyDeleted"
+	NetworkPolicyReconcileFailed       = "NetworkPolicyReconcileFailed"
+	AuthorizationPolicyCreated         = "AuthorizationPolicyCreated"
+	AuthorizationPolicyUpdated         = "AuthorizationPolicyUpdated"
+	AuthorizationPolicyDeleted         = "AuthorizationPolicyDeleted"
+	AuthorizationPolicyReconcileFailed = "AuthorizationPolicyReconcileFailed"
+)
+
+type eventRecorderKey struct{}
+
+// WithEventRecorder returns a copy of ctx in which the helpers of this package, such as
+// CreateK8sService and CreateVirtualService, emit Kubernetes Events with recorder on the objects
+// they reconcile resources for.
+func WithEventRecorder(ctx context.Context, recorder record.EventRecorder) context.Context {
+	return context.WithValue(ctx, eventRecorderKey{}, recorder)
+}
+
+// recordEvent emits an Event on object with the EventRecorder of ctx, if it has one.
+func recordEvent(ctx context.Context, object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if recorder, ok := ctx.Value(eventRecorderKey{}).(record.EventRecorder); ok && recorder != nil {
+		recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faults injects delivery faults into a dispatcher: latency, dropped, duplicated and
+// reordered deliveries, at configurable rates. It is meant for test environments only, to validate
+// that the subscribers of a channel are idempotent and tolerate the failures of production
+// channels before they meet them.
+package faults
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// The environment variables configuring the faults. Faults are not injected unless one of the
+// rates is set.
+const (
+	// LatencyEnv is how long the deliveries hit by LatencyRateEnv are delayed, e.g. 500ms.
+	LatencyEnv = "FAULT_LATENCY"
+	// LatencyRateEnv is the fraction, between 0 and 1, of the deliveries that are delayed.
+	LatencyRateEnv = "FAULT_LATENCY_RATE"
+	// DropRateEnv is the fraction of the deliveries that are acknowledged without being made.
+	DropRateEnv = "FAULT_DROP_RATE"
+	// DuplicateRateEnv is the fraction of the successful deliveries that are made a second time.
+	DuplicateRateEnv = "FAULT_DUPLICATE_RATE"
+	// ReorderRateEnv is the fraction of the deliveries that are acknowledged right away and made
+	// after ReorderDelayEnv, so that the events sent after them overtake them.
+	ReorderRateEnv = "FAULT_REORDER_RATE"
+	// ReorderDelayEnv is how long the reordered deliveries are held, e.g. 2s.
+	ReorderDelayEnv = "FAULT_REORDER_DELAY"
+)
+
+const (
+	defaultLatency      = 1 * time.Second
+	defaultReorderDelay = 1 * time.Second
+)
+
+// Config holds the rates of the faults, each between 0, never, and 1, every delivery.
+type Config struct {
+	Latency       time.Duration
+	LatencyRate   float64
+	DropRate      float64
+	DuplicateRate float64
+	ReorderRate   float64
+	ReorderDelay  time.Duration
+}
+
+// Validate returns an error if a rate of c is not between 0 and 1, or a duration is negative.
+func (c Config) Validate() error {
+	rates := map[string]float64{
+		"latency":   c.LatencyRate,
+		"drop":      c.DropRate,
+		"duplicate": c.DuplicateRate,
+		"reorder":   c.ReorderRate,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("the %s rate must be between 0 and 1, not %v", name, rate)
+		}
+	}
+	if c.Latency < 0 || c.ReorderDelay < 0 {
+		return fmt.Errorf("the latency and the reorder delay must not be negative")
+	}
+	return nil
+}
+
+// enabled returns whether c injects any fault.
+func (c Config) enabled() bool {
+	return c.LatencyRate > 0 || c.DropRate > 0 || c.DuplicateRate > 0 || c.ReorderRate > 0
+}
+
+// FromEnv returns the Injector configured by the environment variables of the process, or nil if
+// no fault is injected.
+func FromEnv(logger *zap.Logger) (*Injector, error) {
+	c := Config{
+		Latency:      defaultLatency,
+		ReorderDelay: defaultReorderDelay,
+	}
+	var err error
+	if c.Latency, err = durationFromEnv(LatencyEnv, c.Latency); err != nil {
+		return nil, err
+	}
+	if c.ReorderDelay, err = durationFromEnv(ReorderDelayEnv, c.ReorderDelay); err != nil {
+		return nil, err
+	}
+	rates := map[string]*float64{
+		LatencyRateEnv:   &c.LatencyRate,
+		DropRateEnv:      &c.DropRate,
+		DuplicateRateEnv: &c.DuplicateRate,
+		ReorderRateEnv:   &c.ReorderRate,
+	}
+	for env, rate := range rates {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		if *rate, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", env, v, err)
+		}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if !c.enabled() {
+		return nil, nil
+	}
+	return NewInjector(c, logger, time.Now().UnixNano()), nil
+}
+
+func durationFromEnv(env string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(env)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %v", env, v, err)
+	}
+	return d, nil
+}
+
+// Injector injects the faults of its Config into the deliveries made through Deliver. A nil
+// Injector makes the deliveries unchanged.
+type Injector struct {
+	config Config
+	logger *zap.Logger
+
+	// sleep waits for d. It is replaced in the tests.
+	sleep func(d time.Duration)
+
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+// NewInjector creates an Injector of the faults of c, drawing them from a source of randomness
+// seeded with seed.
+func NewInjector(c Config, logger *zap.Logger, seed int64) *Injector {
+	logger.Warn("Injecting delivery faults, this dispatcher must not be used in production", zap.Any("faults", c))
+	return &Injector{
+		config: c,
+		logger: logger,
+		sleep:  time.Sleep,
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// hit returns whether a fault of the given rate is injected into a delivery.
+func (i *Injector) hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.rand.Float64() < rate
+}
+
+// Deliver makes the delivery deliver, of the event m, with the faults of i. A dropped delivery is
+// not made, and reported successful. A delayed delivery is made after the latency. A reordered
+// delivery is reported successful right away and made after the reorder delay, its errors are only
+// logged. A duplicated delivery is made a second time when it succeeds.
+func (i *Injector) Deliver(m *provisioners.Message, deliver func() error) error {
+	if i == nil {
+		return deliver()
+	}
+	id := m.Attributes()["id"]
+	if i.hit(i.config.DropRate) {
+		i.logger.Info("Dropping the delivery", zap.String("eventID", id))
+		return nil
+	}
+	if i.hit(i.config.LatencyRate) {
+		i.logger.Debug("Delaying the delivery", zap.String("eventID", id), zap.Duration("latency", i.config.Latency))
+		i.sleep(i.config.Latency)
+	}
+	duplicate := i.hit(i.config.DuplicateRate)
+	if i.hit(i.config.ReorderRate) {
+		i.logger.Info("Reordering the delivery", zap.String("eventID", id), zap.Duration("delay", i.config.ReorderDelay))
+		go func() {
+			i.sleep(i.config.ReorderDelay)
+			if err := i.deliver(id, deliver, duplicate); err != nil {
+				i.logger.Error("Reordered delivery failed", zap.String("eventID", id), zap.Error(err))
+			}
+		}()
+		return nil
+	}
+	return i.deliver(id, deliver, duplicate)
+}
+
+func (i *Injector) deliver(id string, deliver func() error, duplicate bool) error {
+	if err := deliver(); err != nil || !duplicate {
+		return err
+	}
+	i.logger.Info("Duplicating the delivery", zap.String("eventID", id))
+	return deliver()
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var message = &provisioners.Message{
+	Headers: map[string]string{"Ce-Id": "1234"},
+}
+
+// counter counts the deliveries made through it, and fails them with err.
+type counter struct {
+	calls int
+	err   error
+}
+
+func (c *counter) deliver() error {
+	c.calls++
+	return c.err
+}
+
+func newTestInjector(c Config) (*Injector, *[]time.Duration) {
+	i := NewInjector(c, zap.NewNop(), 1)
+	var slept []time.Duration
+	i.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+	}
+	return i, &slept
+}
+
+func TestDeliver(t *testing.T) {
+	deliveryErr := errors.New("test delivery error")
+	testCases := map[string]struct {
+		config    Config
+		err       error
+		wantCalls int
+		wantErr   error
+		wantSlept []time.Duration
+	}{
+		"no faults": {
+			wantCalls: 1,
+		},
+		"no faults, failed": {
+			err:       deliveryErr,
+			wantCalls: 1,
+			wantErr:   deliveryErr,
+		},
+		"dropped": {
+			config:    Config{DropRate: 1},
+			err:       deliveryErr,
+			wantCalls: 0,
+		},
+		"delayed": {
+			config:    Config{Latency: time.Second, LatencyRate: 1},
+			wantCalls: 1,
+			wantSlept: []time.Duration{time.Second},
+		},
+		"duplicated": {
+			config:    Config{DuplicateRate: 1},
+			wantCalls: 2,
+		},
+		"duplicated, failed": {
+			config:    Config{DuplicateRate: 1},
+			err:       deliveryErr,
+			wantCalls: 1,
+			wantErr:   deliveryErr,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			i, slept := newTestInjector(tc.config)
+			c := &counter{err: tc.err}
+			if err := i.Deliver(message, c.deliver); err != tc.wantErr {
+				t.Errorf("Unexpected error. Expected %v, actual %v", tc.wantErr, err)
+			}
+			if c.calls != tc.wantCalls {
+				t.Errorf("Unexpected deliveries. Expected %d, actual %d", tc.wantCalls, c.calls)
+			}
+			if diff := cmp.Diff(tc.wantSlept, *slept); diff != "" {
+				t.Errorf("Unexpected sleeps (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDeliver_NilInjector(t *testing.T) {
+	var i *Injector
+	c := &counter{}
+	if err := i.Deliver(message, c.deliver); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if c.calls != 1 {
+		t.Errorf("Unexpected deliveries. Expected 1, actual %d", c.calls)
+	}
+}
+
+func TestDeliver_Reordered(t *testing.T) {
+	i := NewInjector(Config{ReorderRate: 1, ReorderDelay: 10 * time.Millisecond}, zap.NewNop(), 1)
+	delivered := make(chan struct{})
+	err := i.Deliver(message, func() error {
+		close(delivered)
+		return errors.New("only logged")
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	select {
+	case <-delivered:
+		t.Fatal("Expected the delivery to be held")
+	default:
+	}
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the delivery to be made after the reorder delay")
+	}
+}
+
+func TestDeliver_Rate(t *testing.T) {
+	i, _ := newTestInjector(Config{DropRate: 0.3})
+	c := &counter{}
+	for n := 0; n < 1000; n++ {
+		i.Deliver(message, c.deliver)
+	}
+	// 700 deliveries are expected to be made, give or take.
+	if c.calls < 650 || c.calls > 750 {
+		t.Errorf("Unexpected deliveries. Expected about 700, actual %d", c.calls)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		env     map[string]string
+		want    *Config
+		wantErr bool
+	}{
+		"unset": {},
+		"zero rates": {
+			env: map[string]string{DropRateEnv: "0", LatencyEnv: "2s"},
+		},
+		"rates": {
+			env: map[string]string{
+				LatencyEnv:       "200ms",
+				LatencyRateEnv:   "0.5",
+				DropRateEnv:      "0.1",
+				DuplicateRateEnv: "0.2",
+				ReorderRateEnv:   "0.3",
+			},
+			want: &Config{
+				Latency:       200 * time.Millisecond,
+				LatencyRate:   0.5,
+				DropRate:      0.1,
+				DuplicateRate: 0.2,
+				ReorderRate:   0.3,
+				ReorderDelay:  defaultReorderDelay,
+			},
+		},
+		"invalid rate": {
+			env:     map[string]string{DropRateEnv: "often"},
+			wantErr: true,
+		},
+		"rate out of range": {
+			env:     map[string]string{DuplicateRateEnv: "1.5"},
+			wantErr: true,
+		},
+		"invalid duration": {
+			env:     map[string]string{ReorderRateEnv: "0.1", ReorderDelayEnv: "soon"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			i, err := FromEnv(zap.NewNop())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.want == nil {
+				if i != nil {
+					t.Errorf("Expected no Injector, got %+v", i.config)
+				}
+				return
+			}
+			if i == nil {
+				t.Fatal("Expected an Injector")
+			}
+			if diff := cmp.Diff(*tc.want, i.config); diff != "" {
+				t.Errorf("Unexpected config (-want +got): %s", diff)
+			}
+		})
+	}
+}
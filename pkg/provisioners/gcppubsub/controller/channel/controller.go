@@ -18,6 +18,8 @@ package channel
 
 import (
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	util "github.com/knative/eventing/pkg/provisioners"
 	pubsubutil "github.com/knative/eventing/pkg/provisioners/gcppubsub/util"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"go.uber.org/zap"
@@ -50,7 +52,7 @@ func ProvideController(defaultGcpProject string, defaultSecret *corev1.ObjectRef
 			pubSubClientCreator: pubsubutil.GcpPubSubClientCreator,
 		}
 		c, err := controller.New(controllerAgentName, mgr, controller.Options{
-			Reconciler: r,
+			Reconciler: metrics.InstrumentReconciler(controllerAgentName, r),
 		})
 		if err != nil {
 			return nil, err
@@ -83,6 +85,11 @@ func ProvideController(defaultGcpProject string, defaultSecret *corev1.ObjectRef
 			return nil, err
 		}
 
+		// Watch the AuthorizationPolicies of Channels, when Istio security is installed.
+		if err := util.WatchAuthorizationPolicies(c); err != nil {
+			logger.Warn("Not watching AuthorizationPolicies.", zap.Error(err))
+		}
+
 		return c, nil
 	}
 }
@@ -38,6 +38,14 @@ import (
 
 const (
 	finalizerName = controllerAgentName
+
+	// Reasons of the Events emitted on Channels.
+	topicCreated             = "TopicCreated"
+	topicCreateFailed        = "TopicCreateFailed"
+	topicDeleted             = "TopicDeleted"
+	topicDeleteFailed        = "TopicDeleteFailed"
+	subscriptionCreated      = "SubscriptionCreated"
+	subscriptionCreateFailed = "SubscriptionCreateFailed"
 )
 
 // reconciler reconciles GCP-PubSub Channels by creating the K8s Service and Istio VirtualService
@@ -52,14 +60,17 @@ type reconciler struct {
 
 	// Note that for all the default* parameters below, these must be kept in lock-step with the
 	// GCP PubSub Dispatcher's reconciler.
-	// Eventually, individual Channels should be allowed to specify different projects and secrets,
-	// but for now all Channels use the same project and secret.
+	// Individual Channels can specify a different project in their arguments, but for now all
+	// Channels use the same secret.
 
-	// defaultGcpProject is the GCP project ID where PubSub Topics and Subscriptions are created.
+	// defaultGcpProject is the GCP project ID where PubSub Topics and Subscriptions are created,
+	// unless the Channel specifies another one.
 	defaultGcpProject string
 	// defaultSecret and defaultSecretKey are the K8s Secret and key in that secret that contain a
 	// JSON format GCP service account token, see
 	// https://cloud.google.com/iam/docs/creating-managing-service-account-keys#iam-service-account-keys-create-gcloud
+	// If defaultSecret is nil, the Application Default Credentials are used instead, e.g. those of
+	// GKE Workload Identity.
 	defaultSecret    *v1.ObjectReference
 	defaultSecretKey string
 }
@@ -73,7 +84,7 @@ func (r *reconciler) InjectClient(c client.Client) error {
 }
 
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	ctx := context.TODO()
+	ctx := util.WithEventRecorder(context.TODO(), r.recorder)
 	ctx = logging.WithLogger(ctx, r.logger.With(zap.Any("request", request)).Sugar())
 
 	c := &eventingv1alpha1.Channel{}
@@ -148,13 +159,25 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 		return false, err
 	}
 
+	args, argsErr := pubsubutil.GetChannelArguments(c)
+
 	if c.DeletionTimestamp != nil {
 		// K8s garbage collection will delete the K8s service and VirtualService for this channel.
-		err = r.deleteSubscriptions(ctx, c, gcpCreds, r.defaultGcpProject)
+		if err := util.DeleteAuthorizationPolicy(ctx, r.client, c); err != nil {
+			return false, err
+		}
+		// A Channel with invalid arguments was never provisioned in another project than the
+		// default one, so its Topic and Subscriptions, if any, are looked for in the default
+		// project.
+		gcpProject := args.Project(r.defaultGcpProject)
+		if argsErr != nil {
+			gcpProject = r.defaultGcpProject
+		}
+		err = r.deleteSubscriptions(ctx, c, gcpCreds, gcpProject)
 		if err != nil {
 			return false, err
 		}
-		err = r.deleteTopic(ctx, c, gcpCreds, r.defaultGcpProject)
+		err = r.deleteTopic(ctx, c, gcpCreds, gcpProject)
 		if err != nil {
 			return false, err
 		}
@@ -179,12 +202,23 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 		return false, err
 	}
 
-	topic, err := r.createTopic(ctx, c, gcpCreds, r.defaultGcpProject)
+	if _, err := util.CreateAuthorizationPolicy(ctx, r.client, c); err != nil {
+		logging.FromContext(ctx).Info("Error creating the AuthorizationPolicy for the Channel", zap.Error(err))
+		return false, err
+	}
+
+	if argsErr != nil {
+		logging.FromContext(ctx).Info("Invalid Channel arguments", zap.Error(argsErr))
+		return false, argsErr
+	}
+	gcpProject := args.Project(r.defaultGcpProject)
+
+	topic, err := r.createTopic(ctx, c, gcpCreds, gcpProject)
 	if err != nil {
 		return false, err
 	}
 
-	err = r.createSubscriptions(ctx, c, gcpCreds, r.defaultGcpProject, topic)
+	err = r.createSubscriptions(ctx, c, gcpCreds, gcpProject, topic)
 	if err != nil {
 		return false, err
 	}
@@ -243,8 +277,10 @@ func (r *reconciler) createTopic(ctx context.Context, c *eventingv1alpha1.Channe
 	createdTopic, err := psc.CreateTopic(ctx, topic.ID())
 	if err != nil {
 		logging.FromContext(ctx).Info("Unable to create topic", zap.Error(err))
+		r.recorder.Eventf(c, v1.EventTypeWarning, topicCreateFailed, "Failed to create topic %q: %v", topic.ID(), err)
 		return nil, err
 	}
+	r.recorder.Eventf(c, v1.EventTypeNormal, topicCreated, "Created topic %q", topic.ID())
 	return createdTopic, nil
 }
 
@@ -267,46 +303,54 @@ func (r *reconciler) deleteTopic(ctx context.Context, c *eventingv1alpha1.Channe
 	err = topic.Delete(ctx)
 	if err != nil {
 		logging.FromContext(ctx).Info("Topic deletion failed", zap.Error(err))
+		r.recorder.Eventf(c, v1.EventTypeWarning, topicDeleteFailed, "Failed to delete topic %q: %v", topic.ID(), err)
 		return err
 	}
+	r.recorder.Eventf(c, v1.EventTypeNormal, topicDeleted, "Deleted topic %q", topic.ID())
 	return nil
 }
 
 func (r *reconciler) createSubscriptions(ctx context.Context, c *eventingv1alpha1.Channel, gcpCreds *google.Credentials, gcpProject string, topic pubsubutil.PubSubTopic) error {
 	if c.Spec.Subscribable != nil {
 		for _, sub := range c.Spec.Subscribable.Subscribers {
-			_, err := r.createSubscription(ctx, gcpCreds, gcpProject, topic, &sub)
+			created, err := r.createSubscription(ctx, gcpCreds, gcpProject, topic, &sub)
 			if err != nil {
 				logging.FromContext(ctx).Info("Unable to create subscribers", zap.Error(err), zap.Any("channelSubscriber", sub))
+				r.recorder.Eventf(c, v1.EventTypeWarning, subscriptionCreateFailed, "Failed to create subscription %q: %v", pubsubutil.GenerateSubName(&sub), err)
 				return err
 			}
+			if created {
+				r.recorder.Eventf(c, v1.EventTypeNormal, subscriptionCreated, "Created subscription %q", pubsubutil.GenerateSubName(&sub))
+			}
 		}
 	}
 	return nil
 }
 
-func (r *reconciler) createSubscription(ctx context.Context, gcpCreds *google.Credentials, gcpProject string, topic pubsubutil.PubSubTopic, cs *eventduck.ChannelSubscriberSpec) (pubsubutil.PubSubSubscription, error) {
+// createSubscription creates the GCP PubSub Subscription of cs, if it does not exist yet. The
+// returned boolean is true if the Subscription was created.
+func (r *reconciler) createSubscription(ctx context.Context, gcpCreds *google.Credentials, gcpProject string, topic pubsubutil.PubSubTopic, cs *eventduck.ChannelSubscriberSpec) (bool, error) {
 	psc, err := r.pubSubClientCreator(ctx, gcpCreds, gcpProject)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	sub := psc.SubscriptionInProject(pubsubutil.GenerateSubName(cs), gcpProject)
 	exists, err := sub.Exists(ctx)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	if exists {
 		logging.FromContext(ctx).Debug("Reusing existing subscription.")
-		return sub, nil
+		return false, nil
 	}
 
 	createdSub, err := psc.CreateSubscription(ctx, sub.ID(), topic)
 	if err != nil {
 		logging.FromContext(ctx).Info("Error creating new subscription", zap.Error(err))
-	} else {
-		logging.FromContext(ctx).Info("Created new subscription", zap.Any("subscription", createdSub))
+		return false, err
 	}
-	return createdSub, err
+	logging.FromContext(ctx).Info("Created new subscription", zap.Any("subscription", createdSub))
+	return true, nil
 }
 
 func (r *reconciler) deleteSubscriptions(ctx context.Context, c *eventingv1alpha1.Channel, gcpCreds *google.Credentials, gcpProject string) error {
@@ -31,6 +31,7 @@ import (
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/util/fakepubsub"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
 	util "github.com/knative/eventing/pkg/provisioners"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
@@ -88,6 +89,7 @@ func init() {
 	eventingv1alpha1.AddToScheme(scheme.Scheme)
 	corev1.AddToScheme(scheme.Scheme)
 	istiov1alpha3.AddToScheme(scheme.Scheme)
+	securityv1beta1.AddToScheme(scheme.Scheme)
 }
 
 func TestInjectClient(t *testing.T) {
@@ -593,6 +595,16 @@ func TestReconcile(t *testing.T) {
 				makeChannelWithSubscribersAndFinalizerAndAddress(),
 			},
 		},
+		{
+			Name: "Invalid arguments",
+			InitialState: []runtime.Object{
+				makeChannelWithSubscribersAndFinalizerAndArguments(`{"GcpProject": "Not_A_Project"}`),
+				makeK8sService(),
+				makeVirtualService(),
+				testcreds.MakeSecretWithCreds(),
+			},
+			WantErrMsg: `invalid GcpProject "Not_A_Project", it must be a GCP project ID`,
+		},
 		{
 			Name: "Create Subscriptions - create succeeds",
 			InitialState: []runtime.Object{
@@ -653,6 +665,62 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileGcpProject(t *testing.T) {
+	testCases := map[string]struct {
+		channel *eventingv1alpha1.Channel
+		want    string
+	}{
+		"default project": {
+			channel: makeChannelWithSubscribersAndFinalizer(),
+			want:    gcpProject,
+		},
+		"project override": {
+			channel: makeChannelWithSubscribersAndFinalizerAndArguments(`{"GcpProject": "other-project"}`),
+			want:    "other-project",
+		},
+		"delete with project override": {
+			channel: withArguments(makeDeletingChannelWithSubscribers(), `{"GcpProject": "other-project"}`),
+			want:    "other-project",
+		},
+		"delete with invalid arguments": {
+			channel: withArguments(makeDeletingChannelWithSubscribers(), `{"GcpProject": "Other_Project"}`),
+			want:    gcpProject,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			created := &fakepubsub.CreatedData{}
+			r := &reconciler{
+				client:   fake.NewFakeClient(tc.channel, makeK8sService(), makeVirtualService(), testcreds.MakeSecretWithCreds()),
+				recorder: record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName}),
+				logger:   zap.NewNop(),
+
+				pubSubClientCreator: fakepubsub.Creator(fakepubsub.CreatorData{
+					ClientData: fakepubsub.ClientData{
+						SubscriptionData: fakepubsub.SubscriptionData{Exists: true},
+						TopicData:        fakepubsub.TopicData{Exists: true},
+						Created:          created,
+					},
+				}),
+				defaultGcpProject: gcpProject,
+				defaultSecret:     testcreds.Secret,
+				defaultSecretKey:  testcreds.SecretKey,
+			}
+			if _, err := r.reconcile(context.TODO(), tc.channel); err != nil {
+				t.Fatalf("Unexpected error reconciling the Channel: %v", err)
+			}
+			if len(created.Projects) == 0 {
+				t.Fatal("Expected PubSub clients to be created")
+			}
+			for _, project := range created.Projects {
+				if project != tc.want {
+					t.Errorf("Unexpected project. Expected %q. Actual %q", tc.want, project)
+				}
+			}
+		})
+	}
+}
+
 func makeChannel() *eventingv1alpha1.Channel {
 	c := &eventingv1alpha1.Channel{
 		TypeMeta: metav1.TypeMeta{
@@ -723,6 +791,15 @@ func makeChannelWithSubscribersAndFinalizerAndAddress() *eventingv1alpha1.Channe
 	return c
 }
 
+func makeChannelWithSubscribersAndFinalizerAndArguments(args string) *eventingv1alpha1.Channel {
+	return withArguments(makeChannelWithSubscribersAndFinalizer(), args)
+}
+
+func withArguments(c *eventingv1alpha1.Channel, args string) *eventingv1alpha1.Channel {
+	c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(args)}
+	return c
+}
+
 func makeChannelWithFinalizer() *eventingv1alpha1.Channel {
 	c := makeChannel()
 	c.Finalizers = []string{finalizerName}
@@ -18,6 +18,8 @@ package clusterchannelprovisioner
 
 import (
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	util "github.com/knative/eventing/pkg/provisioners"
 	"go.uber.org/zap"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -42,7 +44,7 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		logger:   logger,
 	}
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: r,
+		Reconciler: metrics.InstrumentReconciler(controllerAgentName, r),
 	})
 	if err != nil {
 		logger.Error("Unable to create controller.", zap.Error(err))
@@ -58,5 +60,11 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		return nil, err
 	}
 
+	// Watch the NetworkPolicy of the dispatcher and its config.
+	if err := util.WatchDispatcherNetworkPolicy(c, Name); err != nil {
+		logger.Error("Unable to watch NetworkPolicies.", zap.Error(err))
+		return nil, err
+	}
+
 	return c, nil
 }
@@ -24,6 +24,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -35,6 +36,14 @@ import (
 const (
 	// Name is the name of the GCP PubSub ClusterChannelProvisioner.
 	Name = "gcp-pubsub"
+
+	// ArgumentsSchema is the JSON Schema of the arguments of gcp-pubsub Channels, see
+	// util.ChannelArguments, published in the status of the ClusterChannelProvisioner so that the
+	// webhook rejects invalid arguments.
+	ArgumentsSchema = `{"type":"object",` +
+		`"properties":{` +
+		`"GcpProject":{"type":"string","pattern":"^[a-z][a-z0-9-]{4,28}[a-z0-9]$"}},` +
+		`"additionalProperties":false}`
 )
 
 type reconciler struct {
@@ -52,7 +61,13 @@ func (r *reconciler) InjectClient(c client.Client) error {
 }
 
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	ctx := context.TODO()
+	// Workaround until https://github.com/kubernetes-sigs/controller-runtime/issues/214 is fixed.
+	// The reconcile requests will include a namespace if they are triggered because of changes to the
+	// objects owned by this ClusterChannelProvisioner (e.g NetworkPolicy). Since ClusterChannelProvisioner is
+	// cluster-scoped we need to unset the namespace or otherwise the provisioner object cannot be looked up.
+	request.NamespacedName.Namespace = ""
+
+	ctx := util.WithEventRecorder(context.TODO(), r.recorder)
 	ctx = logging.WithLogger(ctx, r.logger.With(zap.Any("request", request)).Sugar())
 
 	ccp := &eventingv1alpha1.ClusterChannelProvisioner{}
@@ -112,12 +127,32 @@ func shouldReconcile(namespace, name string) bool {
 }
 
 func (r *reconciler) reconcile(ctx context.Context, ccp *eventingv1alpha1.ClusterChannelProvisioner) error {
-	// We are syncing nothing! Just mark it ready.
+	// We are only syncing the NetworkPolicy restricting ingress to the dispatcher, whose Service is
+	// part of the install.
 
 	if ccp.DeletionTimestamp != nil {
 		return nil
 	}
 
+	if _, err := util.CreateDispatcherNetworkPolicy(ctx, r.client, ccp); err != nil {
+		r.logger.Info("Error creating the ClusterChannelProvisioner's NetworkPolicy", zap.Error(err))
+		return err
+	}
+
+	ccp.Status.ArgumentsSchema = &runtime.RawExtension{Raw: []byte(ArgumentsSchema)}
+	ccp.Status.Capabilities = Capabilities()
 	ccp.Status.MarkReady()
 	return nil
 }
+
+// Capabilities returns the capabilities of gcp-pubsub Channels, published in the status of the
+// ClusterChannelProvisioner. The events are not ordered, see the Ordering section of the README.
+func Capabilities() *eventingv1alpha1.ClusterChannelProvisionerCapabilities {
+	// The size limit of GCP PubSub messages.
+	size := int64(10 * 1024 * 1024)
+	return &eventingv1alpha1.ClusterChannelProvisionerCapabilities{
+		Persistence:  true,
+		MaxEventSize: &size,
+		Arguments:    []string{"GcpProject"},
+	}
+}
@@ -221,6 +221,13 @@ func makeReadyClusterChannelProvisioner() *eventingv1alpha1.ClusterChannelProvis
 		Status:   corev1.ConditionTrue,
 		Severity: duckv1alpha1.ConditionSeverityError,
 	}}
+	maxEventSize := int64(10485760)
+	ccp.Status.ArgumentsSchema = &runtime.RawExtension{Raw: []byte(ArgumentsSchema)}
+	ccp.Status.Capabilities = &eventingv1alpha1.ClusterChannelProvisionerCapabilities{
+		Persistence:  true,
+		MaxEventSize: &maxEventSize,
+		Arguments:    []string{"GcpProject"},
+	}
 	return ccp
 }
 
@@ -21,15 +21,22 @@ import (
 	"log"
 	"os"
 
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/system"
 	"k8s.io/api/core/v1"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
+	"github.com/knative/eventing/pkg/leaderelection"
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/controller/channel"
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/controller/clusterchannelprovisioner"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"github.com/knative/pkg/signals"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -46,16 +53,25 @@ const (
 // ClusterChannelProvisioner itself and Channels that use the 'gcp-pubsub' provisioner. It does not
 // handle the anything at the data layer.
 func main() {
-	logConfig := provisioners.NewLoggingConfig()
-	logger := provisioners.NewProvisionerLoggerFromConfig(logConfig)
+	flag.Parse()
+
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	cfg := config.GetConfigOrDie()
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("Unable to create the kubernetes client: %v", err)
+	}
+
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.GcpPubSubController, stopCh)
 	defer logger.Sync()
 	logger = logger.With(
 		zap.String("eventing.knative.dev/clusterChannelProvisioner", clusterchannelprovisioner.Name),
 		zap.String("eventing.knative.dev/clusterChannelProvisionerComponent", "Controller"),
 	)
-	flag.Parse()
 
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	mgr, err := namespaced.NewManager(cfg, manager.Options{}, namespaced.NamespacesFromEnv(), provisioners.CacheSelectors(clusterchannelprovisioner.Name)...)
 	if err != nil {
 		logger.Fatal("Error starting up.", zap.Error(err))
 	}
@@ -63,6 +79,13 @@ func main() {
 	// Add custom types to this array to get them into the manager's scheme.
 	eventingv1alpha1.AddToScheme(mgr.GetScheme())
 	istiov1alpha3.AddToScheme(mgr.GetScheme())
+	securityv1beta1.AddToScheme(mgr.GetScheme())
+
+	// Coalesce the status updates of the Channels, so that reconciling many of them at once does
+	// not overwhelm the API server.
+	if err := provisioners.CoalesceStatusUpdates(mgr, logger.Desugar()); err != nil {
+		logger.Fatal("Invalid status update configuration", zap.Error(err))
+	}
 
 	// The controllers for both the ClusterChannelProvisioner and the Channels created by that
 	// ClusterChannelProvisioner run in this process.
@@ -72,22 +95,35 @@ func main() {
 	}
 
 	defaultGcpProject := getRequiredEnv(defaultGcpProjectEnv)
-	defaultSecret := v1.ObjectReference{
-		APIVersion: v1.SchemeGroupVersion.String(),
-		Kind:       "Secret",
-		Namespace:  getRequiredEnv(defaultSecretNamespaceEnv),
-		Name:       getRequiredEnv(defaultSecretNameEnv),
-	}
-	defaultSecretKey := getRequiredEnv(defaultSecretKeyEnv)
-	_, err = channel.ProvideController(defaultGcpProject, &defaultSecret, defaultSecretKey)(mgr, logger.Desugar())
+	defaultSecret, defaultSecretKey := getDefaultSecret()
+	_, err = channel.ProvideController(defaultGcpProject, defaultSecret, defaultSecretKey)(mgr, logger.Desugar())
 	if err != nil {
 		logger.Fatal("Unable to create Channel controller", zap.Error(err))
 	}
 
-	// set up signals so we handle the first shutdown signal gracefully
-	stopCh := signals.SetupSignalHandler()
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		go func() {
+			if err := provisioners.ServeMetrics(":"+port, stopCh); err != nil {
+				logger.Error("Unable to serve the metrics", zap.Error(err))
+			}
+		}()
+	}
+
+	if addr := debug.Addr(); addr != "" {
+		go func() {
+			if err := debug.Serve(addr, stopCh); err != nil {
+				logger.Error("Unable to serve the debug endpoints", zap.Error(err))
+			}
+		}()
+	}
+
+	election, err := leaderelection.ConfigFromEnv("gcp-pubsub-channel-controller")
+	if err != nil {
+		logger.Fatal("Invalid leader election configuration", zap.Error(err))
+	}
+
 	// Start blocks forever.
-	err = mgr.Start(stopCh)
+	err = leaderelection.RunManager(stopCh, election, mgr, logger.Desugar())
 	if err != nil {
 		logger.Fatal("Manager.Start() returned an error", zap.Error(err))
 	}
@@ -100,3 +136,18 @@ func getRequiredEnv(envKey string) string {
 	}
 	return val
 }
+
+// getDefaultSecret returns the Secret and key containing the GCP credentials. If no Secret is
+// configured, it returns a nil Secret and the Application Default Credentials are used instead,
+// e.g. those of GKE Workload Identity.
+func getDefaultSecret() (*v1.ObjectReference, string) {
+	if _, defined := os.LookupEnv(defaultSecretNameEnv); !defined {
+		return nil, ""
+	}
+	return &v1.ObjectReference{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       "Secret",
+		Namespace:  getRequiredEnv(defaultSecretNamespaceEnv),
+		Name:       getRequiredEnv(defaultSecretNameEnv),
+	}, getRequiredEnv(defaultSecretKeyEnv)
+}
@@ -20,8 +20,23 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/amqp/ingress"
+	"github.com/knative/eventing/pkg/provisioners/audit"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/claimcheck"
+	"github.com/knative/eventing/pkg/provisioners/dedup"
+	"github.com/knative/eventing/pkg/provisioners/encryption"
+	"github.com/knative/eventing/pkg/provisioners/push"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+	"github.com/knative/eventing/pkg/provisioners/signing"
+	"github.com/knative/eventing/pkg/provisioners/tap"
 	"k8s.io/api/core/v1"
 
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/dispatcher/dispatcher"
@@ -31,8 +46,11 @@ import (
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/controller/clusterchannelprovisioner"
+	"github.com/knative/eventing/pkg/system"
+	"github.com/knative/eventing/pkg/tracing"
 	"github.com/knative/pkg/signals"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -42,6 +60,12 @@ const (
 	defaultSecretNamespaceEnv = "DEFAULT_SECRET_NAMESPACE"
 	defaultSecretNameEnv      = "DEFAULT_SECRET_NAME"
 	defaultSecretKeyEnv       = "DEFAULT_SECRET_KEY"
+
+	// dedupWindowEnv optionally enables the deduplication of redelivered events. Its value is the
+	// duration events are remembered for, e.g. 10m.
+	dedupWindowEnv = "DEDUP_WINDOW"
+	// dedupSizeEnv is the number of events remembered for deduplication.
+	dedupSizeEnv = "DEDUP_SIZE"
 )
 
 // This is the main method for the GCP PubSub Channel dispatcher. It handles all the data-plane
@@ -49,55 +73,166 @@ const (
 // (via the receiver below) and watches all GCP PubSub Subscriptions (via the dispatcher below),
 // sending events out when any are available.
 func main() {
-	logConfig := provisioners.NewLoggingConfig()
-	logger := provisioners.NewProvisionerLoggerFromConfig(logConfig)
+	flag.Parse()
+
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	mgr, err := namespaced.NewManager(config.GetConfigOrDie(), manager.Options{}, namespaced.NamespacesFromEnv())
+	if err != nil {
+		log.Fatalf("Error starting up: %v", err)
+	}
+
+	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.Fatalf("Unable to create the kubernetes client: %v", err)
+	}
+
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.GcpPubSubDispatcher, stopCh)
 	defer logger.Sync()
 	logger = logger.With(
 		zap.String("eventing.knative.dev/clusterChannelProvisioner", clusterchannelprovisioner.Name),
 		zap.String("eventing.knative.dev/clusterChannelProvisionerComponent", "Dispatcher"),
 	)
-	flag.Parse()
 
 	logger.Info("Starting...")
 
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
-	if err != nil {
-		logger.Fatal("Error starting up.", zap.Error(err))
-	}
-
 	// Add custom types to this array to get them into the manager's scheme.
 	eventingv1alpha1.AddToScheme(mgr.GetScheme())
 
 	defaultGcpProject := getRequiredEnv(defaultGcpProjectEnv)
-	defaultSecret := v1.ObjectReference{
-		APIVersion: v1.SchemeGroupVersion.String(),
-		Kind:       "Secret",
-		Namespace:  getRequiredEnv(defaultSecretNamespaceEnv),
-		Name:       getRequiredEnv(defaultSecretNameEnv),
+	defaultSecret, defaultSecretKey := getDefaultSecret()
+
+	if err := provisioners.SetMetricsGranularity(os.Getenv(provisioners.MetricsGranularityEnv)); err != nil {
+		logger.Fatal("Invalid metrics configuration", zap.Error(err))
 	}
-	defaultSecretKey := getRequiredEnv(defaultSecretKeyEnv)
 
 	// We are running both the receiver (takes messages in from the cluster and writes them to
 	// PubSub) and the dispatcher (takes messages in PubSub and sends them in cluster) in this
 	// binary.
 
-	_, mr := receiver.New(logger.Desugar(), mgr.GetClient(), util.GcpPubSubClientCreator, defaultGcpProject, &defaultSecret, defaultSecretKey)
-	err = mgr.Add(mr)
+	// Events larger than the GCP PubSub message size limit can be carried by offloading their data
+	// with a claim check.
+	var receiverOpts []provisioners.ReceiverOption
+	var dispatcherOpts []provisioners.DispatcherOption
+	claimCheck, err := claimcheck.FromEnv()
 	if err != nil {
-		logger.Fatal("Unable to add the MessageReceiver to the manager", zap.Error(err))
+		logger.Fatal("Invalid claim check configuration", zap.Error(err))
+	}
+	if claimCheck != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithClaimCheck(claimCheck.Store, claimCheck.Threshold))
+		dispatcherOpts = append(dispatcherOpts, provisioners.WithClaimCheckStore(claimCheck.Store))
 	}
 
-	// TODO Move this to just before mgr.Start(). We need to pass the stopCh to dispatcher.New
-	// because of https://github.com/kubernetes-sigs/controller-runtime/issues/103.
+	// The data of events can be encrypted, so that it is not stored in plaintext by GCP PubSub.
+	encrypter, err := encryption.FromEnv()
+	if err != nil {
+		logger.Fatal("Invalid encryption configuration", zap.Error(err))
+	}
+	if encrypter != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithEncryption(encrypter))
+		dispatcherOpts = append(dispatcherOpts, provisioners.WithDecryption(encrypter))
+	}
 
-	// set up signals so we handle the first shutdown signal gracefully
-	stopCh := signals.SetupSignalHandler()
+	maxBodySize, err := provisioners.MaxBodySizeFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid maximum body size", zap.Error(err))
+	}
+	receiverOpts = append(receiverOpts, provisioners.WithMaxBodySize(maxBodySize))
+	dispatcherOpts = append(dispatcherOpts, provisioners.WithMaxReplySize(maxBodySize))
+
+	// Events can be signed at the ingress, so that the subscribers can authenticate their origin,
+	// and their signature verified before they are delivered.
+	signer, err := signing.SignerFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid signing configuration", zap.Error(err))
+	}
+	if signer != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithSigning(signer))
+	}
+	verifier, err := signing.VerifierFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid signature verification configuration", zap.Error(err))
+	}
+	if verifier != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithSignedMessageVerification(verifier))
+		dispatcherOpts = append(dispatcherOpts, provisioners.WithSignatureVerification(verifier))
+	}
+
+	auditSink, err := audit.FromEnv(logger.Desugar(), stopCh)
+	if err != nil {
+		logger.Fatal("Invalid audit configuration", zap.Error(err))
+	}
+	if auditSink != nil {
+		dispatcherOpts = append(dispatcherOpts, provisioners.WithAuditSink(auditSink))
+	}
+
+	tlsConfig, err := auth.TLSConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid client certificate configuration", zap.Error(err))
+	}
+	if tlsConfig != nil {
+		dispatcherOpts = append(dispatcherOpts, provisioners.WithTLSConfig(tlsConfig))
+	}
+
+	tapHub, err := tap.FromEnv(kc, logger.Desugar(), stopCh)
+	if err != nil {
+		logger.Fatal("Invalid tap configuration", zap.Error(err))
+	}
+	if tapHub != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithTap(tapHub))
+	}
+	pushHub, err := push.FromEnv(kc, logger.Desugar(), stopCh)
+	if err != nil {
+		logger.Fatal("Invalid push configuration", zap.Error(err))
+	}
+	if pushHub != nil {
+		dispatcherOpts = append(dispatcherOpts, provisioners.WithPusher(pushHub))
+	}
+	if err := ingress.FromEnv(provisioners.MessageReceiverPort, logger.Desugar(), stopCh); err != nil {
+		logger.Fatal("Invalid AMQP configuration", zap.Error(err))
+	}
+
+	_, mr := receiver.New(logger.Desugar(), mgr.GetClient(), util.GcpPubSubClientCreator, defaultGcpProject, defaultSecret, defaultSecretKey, receiverOpts...)
+	err = mgr.Add(mr)
+	if err != nil {
+		logger.Fatal("Unable to add the MessageReceiver to the manager", zap.Error(err))
+	}
 
-	_, err = dispatcher.New(mgr, logger.Desugar(), defaultGcpProject, &defaultSecret, defaultSecretKey, stopCh)
+	_, ready, err := dispatcher.New(mgr, logger.Desugar(), defaultGcpProject, defaultSecret, defaultSecretKey, getDedupWindow(), schema.NewResolver(schema.KubeConfigMapGetter(kc)), auth.NewResolver(auth.KubeSecretGetter(kc)), stopCh, dispatcherOpts...)
 	if err != nil {
 		logger.Fatal("Unable to create the dispatcher", zap.Error(err))
 	}
 
+	if err := tracing.WatchConfigMap(kc, system.Namespace, logconfig.GcpPubSubDispatcher, logger, stopCh); err != nil {
+		logger.Warn("Spans are not exported until the tracing config map is created", zap.Error(err))
+	}
+
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		go func() {
+			if err := provisioners.ServeMetrics(":"+port, stopCh); err != nil {
+				logger.Error("Unable to serve the metrics", zap.Error(err))
+			}
+		}()
+	}
+
+	if addr := debug.Addr(); addr != "" {
+		go func() {
+			if err := debug.Serve(addr, stopCh); err != nil {
+				logger.Error("Unable to serve the debug endpoints", zap.Error(err))
+			}
+		}()
+	}
+
+	if port := os.Getenv(provisioners.HealthPortEnv); port != "" {
+		go func() {
+			checks := map[string]provisioners.ReadinessCheck{"gcp-pubsub": ready}
+			if err := provisioners.ServeHealth(":"+port, checks, stopCh); err != nil {
+				logger.Error("Unable to serve the health endpoints", zap.Error(err))
+			}
+		}()
+	}
+
 	// Start blocks forever.
 	logger.Info("Manager starting...")
 	err = mgr.Start(stopCh)
@@ -106,6 +241,26 @@ func main() {
 	}
 }
 
+// getDedupWindow returns the Window deduplicating redelivered events, or nil if deduplication is
+// not enabled.
+func getDedupWindow() *dedup.Window {
+	window, defined := os.LookupEnv(dedupWindowEnv)
+	if !defined {
+		return nil
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid duration in environment variable '%s': %q", dedupWindowEnv, window)
+	}
+	size := 0
+	if s, defined := os.LookupEnv(dedupSizeEnv); defined {
+		if size, err = strconv.Atoi(s); err != nil || size <= 0 {
+			log.Fatalf("invalid size in environment variable '%s': %q", dedupSizeEnv, s)
+		}
+	}
+	return dedup.NewWindow(size, d)
+}
+
 func getRequiredEnv(envKey string) string {
 	val, defined := os.LookupEnv(envKey)
 	if !defined {
@@ -113,3 +268,18 @@ func getRequiredEnv(envKey string) string {
 	}
 	return val
 }
+
+// getDefaultSecret returns the Secret and key containing the GCP credentials. If no Secret is
+// configured, it returns a nil Secret and the Application Default Credentials are used instead,
+// e.g. those of GKE Workload Identity.
+func getDefaultSecret() (*v1.ObjectReference, string) {
+	if _, defined := os.LookupEnv(defaultSecretNameEnv); !defined {
+		return nil, ""
+	}
+	return &v1.ObjectReference{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       "Secret",
+		Namespace:  getRequiredEnv(defaultSecretNamespaceEnv),
+		Name:       getRequiredEnv(defaultSecretNameEnv),
+	}, getRequiredEnv(defaultSecretKeyEnv)
+}
@@ -24,7 +24,10 @@ import (
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/dedup"
 	pubsubutil "github.com/knative/eventing/pkg/provisioners/gcppubsub/util"
+	"github.com/knative/eventing/pkg/provisioners/schema"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -41,8 +44,12 @@ const (
 
 // New returns a Controller that represents the dispatcher portion (messages from GCP PubSub are
 // sent into the cluster) of the GCP PubSub dispatcher. We use a reconcile loop to watch all
-// Channels and notice changes to them.
-func New(mgr manager.Manager, logger *zap.Logger, defaultGcpProject string, defaultSecret *corev1.ObjectReference, defaultSecretKey string, stopCh <-chan struct{}) (controller.Controller, error) {
+// Channels and notice changes to them. If dedupWindow is not nil, events redelivered by GCP PubSub
+// are not dispatched again to the subscribers that accepted them. schemaResolver and authResolver
+// read the schemas and the credentials of the subscribers. The MessageDispatcher sending the
+// events to the subscribers is configured with opts. The returned ReadinessCheck fails while any
+// subscription cannot receive messages from GCP PubSub.
+func New(mgr manager.Manager, logger *zap.Logger, defaultGcpProject string, defaultSecret *corev1.ObjectReference, defaultSecretKey string, dedupWindow *dedup.Window, schemaResolver *schema.Resolver, authResolver *auth.Resolver, stopCh <-chan struct{}, opts ...provisioners.DispatcherOption) (controller.Controller, provisioners.ReadinessCheck, error) {
 	// reconcileChan is used when the dispatcher itself needs to force reconciliation of a Channel.
 	reconcileChan := make(chan event.GenericEvent)
 
@@ -52,24 +59,33 @@ func New(mgr manager.Manager, logger *zap.Logger, defaultGcpProject string, defa
 		recorder: mgr.GetRecorder(controllerAgentName),
 		logger:   logger,
 
-		dispatcher:    provisioners.NewMessageDispatcher(logger.Sugar()),
+		dispatcher:    provisioners.NewMessageDispatcher(logger.Sugar(), opts...),
 		reconcileChan: reconcileChan,
 
 		defaultGcpProject:   defaultGcpProject,
 		defaultSecret:       defaultSecret,
 		defaultSecretKey:    defaultSecretKey,
 		pubSubClientCreator: pubsubutil.GcpPubSubClientCreator,
+		dedup:               dedupWindow,
+		schemaResolver:      schemaResolver,
+		authResolver:        authResolver,
+		backlog:             provisioners.NewBacklogCounter(),
 
 		subscriptionsLock: sync.Mutex{},
 		subscriptions:     map[channelName]map[subscriptionName]context.CancelFunc{},
 	}
 
+	if err := provisioners.RegisterBacklog(r.backlog); err != nil {
+		logger.Error("Unable to register the backlog metric.", zap.Error(err))
+		return nil, nil, err
+	}
+
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
 		Reconciler: r,
 	})
 	if err != nil {
 		logger.Error("Unable to create controller.", zap.Error(err))
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Watch Channels.
@@ -78,7 +94,7 @@ func New(mgr manager.Manager, logger *zap.Logger, defaultGcpProject string, defa
 	}, &handler.EnqueueRequestForObject{})
 	if err != nil {
 		logger.Error("Unable to watch Channels.", zap.Error(err), zap.Any("type", &eventingv1alpha1.Channel{}))
-		return nil, err
+		return nil, nil, err
 	}
 
 	// The PubSub library may fail when receiving messages. If it does so, then we need to reconcile
@@ -95,8 +111,8 @@ func New(mgr manager.Manager, logger *zap.Logger, defaultGcpProject string, defa
 	err = c.Watch(src, &handler.EnqueueRequestForObject{})
 	if err != nil {
 		logger.Error("Unable to watch the reconcile Channel", zap.Error(err))
-		return nil, err
+		return nil, nil, err
 	}
 
-	return c, nil
+	return c, r.Ready, nil
 }
@@ -18,11 +18,15 @@ package dispatcher
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/dedup"
+	"github.com/knative/eventing/pkg/provisioners/schema"
 
 	"github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 
@@ -63,16 +67,33 @@ type reconciler struct {
 
 	pubSubClientCreator pubsubutil.PubSubClientCreator
 
+	// dedup suppresses the redelivery of events to subscribers. It is nil when deduplication is
+	// disabled.
+	dedup *dedup.Window
+
+	// schemaResolver reads the schemas of the subscribers that declare one. The events delivered to
+	// them are not validated if it is nil.
+	schemaResolver *schema.Resolver
+
+	// authResolver obtains the credentials of the subscribers that declare auth. The deliveries to
+	// them fail if it is nil.
+	authResolver *auth.Resolver
+
+	// backlog counts the messages received from GCP PubSub and not acknowledged yet, by Channel.
+	backlog *provisioners.BacklogCounter
+
 	// Note that for all the default* parameters below, these must be kept in lock-step with the
 	// GCP PubSub Dispatcher's reconciler.
-	// Eventually, individual Channels should be allowed to specify different projects and secrets,
-	// but for now all Channels use the same project and secret.
+	// Individual Channels can specify a different project in their arguments, but for now all
+	// Channels use the same secret.
 
-	// defaultGcpProject is the GCP project ID where PubSub Topics and Subscriptions are created.
+	// defaultGcpProject is the GCP project ID where PubSub Topics and Subscriptions are created,
+	// unless the Channel specifies another one.
 	defaultGcpProject string
 	// defaultSecret and defaultSecretKey are the K8s Secret and key in that secret that contain a
 	// JSON format GCP service account token, see
 	// https://cloud.google.com/iam/docs/creating-managing-service-account-keys#iam-service-account-keys-create-gcloud
+	// If defaultSecret is nil, the Application Default Credentials are used instead.
 	defaultSecret    *v1.ObjectReference
 	defaultSecretKey string
 
@@ -81,6 +102,10 @@ type reconciler struct {
 	// function must be called when we no longer want that subscription to be active. Logically it
 	// is a map from Channel name to Subscription name to CancelFunc.
 	subscriptions map[channelName]map[subscriptionName]context.CancelFunc
+	// receiveErrors contains the last error of the subscriptions that are failing to receive
+	// messages from GCP PubSub, until they are polled successfully again. It is also guarded by
+	// subscriptionsLock.
+	receiveErrors map[channelName]map[subscriptionName]error
 }
 
 // Verify the struct implements reconcile.Reconciler
@@ -205,11 +230,46 @@ func (r *reconciler) stopAllSubscriptionsUnderLock(ctx context.Context, c *event
 		}
 	}
 	delete(r.subscriptions, channelKey)
+	delete(r.receiveErrors, channelKey)
+}
+
+// setReceiveErrorUnderLock records that the subscription failed to receive messages with err, or
+// that it is receiving messages if err is nil.
+// Note that it can only be called if reconciler.subscriptionsLock is held.
+func (r *reconciler) setReceiveErrorUnderLock(channelKey channelName, subKey subscriptionName, err error) {
+	if err == nil {
+		delete(r.receiveErrors[channelKey], subKey)
+		if len(r.receiveErrors[channelKey]) == 0 {
+			delete(r.receiveErrors, channelKey)
+		}
+		return
+	}
+	if r.receiveErrors == nil {
+		r.receiveErrors = make(map[channelName]map[subscriptionName]error)
+	}
+	if r.receiveErrors[channelKey] == nil {
+		r.receiveErrors[channelKey] = make(map[subscriptionName]error)
+	}
+	r.receiveErrors[channelKey][subKey] = err
+}
+
+// Ready returns an error if any subscription is failing to receive messages from GCP PubSub, e.g.
+// because GCP PubSub is unreachable or the credentials are invalid.
+func (r *reconciler) Ready() error {
+	r.subscriptionsLock.Lock()
+	defer r.subscriptionsLock.Unlock()
+	for channelKey, subs := range r.receiveErrors {
+		for subKey, err := range subs {
+			return fmt.Errorf("subscription %v of channel %v cannot receive from GCP PubSub: %v", subKey, channelKey, err)
+		}
+	}
+	return nil
 }
 
 // syncSubscriptions ensures all subscribers of the Channel have a background Goroutine that is
 // polling the GCP PubSub Subscriptions representing it. It also removes listeners from Subscribers
-// that no longer exist.
+// that no longer exist or that are paused. The GCP PubSub Subscription of a paused subscriber is
+// kept, so messages accumulate in it until the subscriber is resumed.
 func (r *reconciler) syncSubscriptions(ctx context.Context, c *eventingv1alpha1.Channel) error {
 	r.subscriptionsLock.Lock()
 	defer r.subscriptionsLock.Unlock()
@@ -221,7 +281,12 @@ func (r *reconciler) syncSubscriptions(ctx context.Context, c *eventingv1alpha1.
 		return nil
 	}
 
+	active := 0
 	for _, subscriber := range subscribers.Subscribers {
+		if subscriber.Paused {
+			continue
+		}
+		active++
 		err := r.createSubscriptionUnderLock(loggingWith(ctx, zap.Any("subscriber", subscriber)), c, &subscriber)
 		if err != nil {
 			return err
@@ -231,7 +296,7 @@ func (r *reconciler) syncSubscriptions(ctx context.Context, c *eventingv1alpha1.
 	// Now remove all subscriptions that are no longer present.
 	channelKey := key(c)
 	activeSubscribers := r.subscriptions[channelKey]
-	if len(subscribers.Subscribers) == len(activeSubscribers) {
+	if active == len(activeSubscribers) {
 		return nil
 	}
 
@@ -241,7 +306,9 @@ func (r *reconciler) syncSubscriptions(ctx context.Context, c *eventingv1alpha1.
 		subsToDelete[sub] = empty{}
 	}
 	for _, sub := range subscribers.Subscribers {
-		delete(subsToDelete, subscriptionKey(&sub))
+		if !sub.Paused {
+			delete(subsToDelete, subscriptionKey(&sub))
+		}
 	}
 	for subToDelete := range subsToDelete {
 		r.subscriptions[channelKey][subToDelete]()
@@ -265,18 +332,26 @@ func (r *reconciler) createSubscriptionUnderLock(ctx context.Context, c *eventin
 	ctxWithCancel, cancelFunc := context.WithCancel(ctx)
 	r.subscriptions[channelKey][subKey] = cancelFunc
 
-	gcpProject := r.defaultGcpProject
+	args, err := pubsubutil.GetChannelArguments(c)
+	if err != nil {
+		r.setReceiveErrorUnderLock(channelKey, subKey, err)
+		return err
+	}
+	gcpProject := args.Project(r.defaultGcpProject)
 	creds, err := pubsubutil.GetCredentials(ctx, r.client, r.defaultSecret, r.defaultSecretKey)
 	if err != nil {
+		r.setReceiveErrorUnderLock(channelKey, subKey, err)
 		return err
 	}
 	psc, err := r.pubSubClientCreator(ctxWithCancel, creds, gcpProject)
 	if err != nil {
+		r.setReceiveErrorUnderLock(channelKey, subKey, err)
 		return err
 	}
 
 	// receiveMessageBlocking blocks, so run it in a goroutine.
 	go r.receiveMessagesBlocking(ctxWithCancel, c, sub.DeepCopy(), gcpProject, psc)
+	r.setReceiveErrorUnderLock(channelKey, subKey, nil)
 
 	return nil
 }
@@ -286,8 +361,21 @@ func (r *reconciler) createSubscriptionUnderLock(ctx context.Context, c *eventin
 // reconciler.reconcileChan.
 func (r *reconciler) receiveMessagesBlocking(ctxWithCancel context.Context, c *eventingv1alpha1.Channel, sub *v1alpha1.ChannelSubscriberSpec, gcpProject string, psc pubsubutil.PubSubClient) {
 	subscription := psc.SubscriptionInProject(pubsubutil.GenerateSubName(sub), gcpProject)
+	channelRef := provisioners.ChannelReference{Namespace: c.Namespace, Name: c.Name}
+	logger := logging.FromContext(ctxWithCancel).Desugar()
 	defaults := provisioners.DispatchDefaults{
-		Namespace: c.Namespace,
+		Namespace:               c.Namespace,
+		OnError:                 sub.ErrorURI,
+		Channel:                 channelRef.String(),
+		Subscription:            subscriptionKey(sub).String(),
+		Protocol:                sub.Protocol,
+		Filter:                  provisioners.SubscriberFilter(logger, *sub),
+		Validator:               r.schemaResolver.SubscriberValidator(logger, *sub),
+		Transform:               provisioners.SubscriberTransform(logger, *sub),
+		NonConformingDeadLetter: sub.DeadLetterURI,
+	}
+	if sub.Auth != nil {
+		defaults.Auth = r.authResolver.Authenticator(c.Namespace, sub.Auth)
 	}
 	channelKey := key(c)
 	subKey := subscriptionKey(sub)
@@ -295,7 +383,7 @@ func (r *reconciler) receiveMessagesBlocking(ctxWithCancel context.Context, c *e
 	logging.FromContext(ctxWithCancel).Info("subscription.Receive start")
 	receiveErr := subscription.Receive(
 		ctxWithCancel,
-		receiveFunc(logging.FromContext(ctxWithCancel), sub, defaults, r.dispatcher))
+		receiveFunc(logging.FromContext(ctxWithCancel), channelRef, sub, defaults, r.dispatcher, r.dedup, r.backlog))
 	// We want to minimize holding the lock. r.reconcileChan may block, so definitely do not do
 	// it under lock. But, to prevent a race condition, we must delete from r.subscriptions
 	// before using r.reconcileChan.
@@ -307,6 +395,9 @@ func (r *reconciler) receiveMessagesBlocking(ctxWithCancel context.Context, c *e
 		// r.subscriptions, then we don't need to delete anything.
 		if subMap, present := r.subscriptions[channelKey]; present {
 			delete(subMap, subKey)
+			if receiveErr != nil {
+				r.setReceiveErrorUnderLock(channelKey, subKey, receiveErr)
+			}
 		}
 	}()
 
@@ -320,13 +411,18 @@ func (r *reconciler) receiveMessagesBlocking(ctxWithCancel context.Context, c *e
 	}
 }
 
-func receiveFunc(logger *zap.SugaredLogger, sub *v1alpha1.ChannelSubscriberSpec, defaults provisioners.DispatchDefaults, dispatcher provisioners.Dispatcher) func(context.Context, pubsubutil.PubSubMessage) {
+func receiveFunc(logger *zap.SugaredLogger, channel provisioners.ChannelReference, sub *v1alpha1.ChannelSubscriberSpec, defaults provisioners.DispatchDefaults, dispatcher provisioners.Dispatcher, window *dedup.Window, backlog *provisioners.BacklogCounter) func(context.Context, pubsubutil.PubSubMessage) {
+	subscriber := subscriptionKey(sub).String()
 	return func(ctx context.Context, msg pubsubutil.PubSubMessage) {
+		backlog.Add(channel, 1)
+		defer backlog.Add(channel, -1)
 		message := &provisioners.Message{
 			Headers: msg.Attributes(),
 			Payload: msg.Data(),
 		}
-		err := dispatcher.DispatchMessage(message, sub.SubscriberURI, sub.ReplyURI, defaults)
+		err := window.Dispatch(subscriber, message, func() error {
+			return dispatcher.DispatchMessage(message, sub.SubscriberURI, sub.ReplyURI, defaults)
+		})
 		if err != nil {
 			logger.Error("Message dispatch failed", zap.Error(err), zap.String("pubSubMessageId", msg.ID()))
 			msg.Nack()
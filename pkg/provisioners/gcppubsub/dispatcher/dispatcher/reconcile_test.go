@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -27,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/dedup"
 
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
@@ -36,6 +39,7 @@ import (
 
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/util/fakepubsub"
 
+	"github.com/google/go-cmp/cmp"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
 	"go.uber.org/zap"
@@ -70,6 +74,8 @@ var (
 	// truncates to seconds to match the loss of precision during serialization.
 	deletionTime = metav1.Now().Rfc3339Copy()
 
+	channelRef = provisioners.ChannelReference{Namespace: cNamespace, Name: cName}
+
 	subscribers = &v1alpha1.Subscribable{
 		Subscribers: []v1alpha1.ChannelSubscriberSpec{
 			{
@@ -286,6 +292,28 @@ func TestReconcile(t *testing.T) {
 				makeChannelWithSubscribersAndFinalizer(),
 			},
 		},
+		{
+			Name: "Stop paused Subscriptions",
+			InitialState: []runtime.Object{
+				makeChannelWithPausedSubscriberAndFinalizer(),
+				testcreds.MakeSecretWithCreds(),
+			},
+			OtherTestData: map[string]interface{}{
+				pscData: fakepubsub.CreatorData{
+					ClientData: fakepubsub.ClientData{
+						SubscriptionData: fakepubsub.SubscriptionData{
+							ReceiveErr: errors.New(testErrorMessage),
+						},
+					},
+				},
+				shouldBeCanceled: map[channelName]subscriptionName{
+					key(makeChannel()): {Namespace: subscribers.Subscribers[0].Ref.Namespace, Name: subscribers.Subscribers[0].Ref.Name},
+				},
+			},
+			WantPresent: []runtime.Object{
+				makeChannelWithPausedSubscriberAndFinalizer(),
+			},
+		},
 		{
 			Name: "Channel update fails",
 			InitialState: []runtime.Object{
@@ -350,6 +378,95 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestReady(t *testing.T) {
+	r := &reconciler{
+		subscriptions: map[channelName]map[subscriptionName]context.CancelFunc{},
+	}
+	channelKey := channelName{Namespace: cNamespace, Name: cName}
+	subKey := subscriptionName{Namespace: cNamespace, Name: "sub-name"}
+
+	if err := r.Ready(); err != nil {
+		t.Errorf("Unexpected error without subscriptions: %v", err)
+	}
+	r.setReceiveErrorUnderLock(channelKey, subKey, errors.New(testErrorMessage))
+	if err := r.Ready(); err == nil {
+		t.Error("Expected an error while the subscription cannot receive messages")
+	}
+	r.setReceiveErrorUnderLock(channelKey, subKey, nil)
+	if err := r.Ready(); err != nil {
+		t.Errorf("Unexpected error once the subscription receives messages again: %v", err)
+	}
+
+	r.setReceiveErrorUnderLock(channelKey, subKey, errors.New(testErrorMessage))
+	r.stopAllSubscriptions(context.TODO(), makeChannel())
+	if err := r.Ready(); err != nil {
+		t.Errorf("Unexpected error once the channel is deleted: %v", err)
+	}
+}
+
+func TestCreateSubscriptionGcpProject(t *testing.T) {
+	testCases := map[string]struct {
+		args    string
+		want    []string
+		wantErr bool
+	}{
+		"default project": {
+			want: []string{gcpProject},
+		},
+		"project override": {
+			args: `{"GcpProject": "other-project"}`,
+			want: []string{"other-project"},
+		},
+		"invalid arguments": {
+			args:    `{"GcpProject": "Other_Project"}`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			created := &fakepubsub.CreatedData{}
+			r := &reconciler{
+				client: fake.NewFakeClient(testcreds.MakeSecretWithCreds()),
+				logger: zap.NewNop(),
+
+				reconcileChan: make(chan event.GenericEvent, 1),
+				pubSubClientCreator: fakepubsub.Creator(fakepubsub.CreatorData{
+					ClientData: fakepubsub.ClientData{Created: created},
+				}),
+				defaultGcpProject: gcpProject,
+				defaultSecret:     testcreds.Secret,
+				defaultSecretKey:  testcreds.SecretKey,
+
+				subscriptions: map[channelName]map[subscriptionName]context.CancelFunc{},
+			}
+			c := makeChannelWithSubscribersAndFinalizer()
+			if tc.args != "" {
+				c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(tc.args)}
+			}
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			r.subscriptionsLock.Lock()
+			err := r.createSubscriptionUnderLock(ctx, c, &c.Spec.Subscribable.Subscribers[0])
+			r.subscriptionsLock.Unlock()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error.")
+				}
+				if r.Ready() == nil {
+					t.Error("Expected the subscription not to be ready")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, created.Projects); diff != "" {
+				t.Errorf("unexpected projects (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
 func TestReceiveFunc(t *testing.T) {
 	testCases := map[string]struct {
 		ack           bool
@@ -366,13 +483,17 @@ func TestReceiveFunc(t *testing.T) {
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
 			sub := &v1alpha1.ChannelSubscriberSpec{
+				Ref: &corev1.ObjectReference{
+					Namespace: cNamespace,
+					Name:      "sub-name",
+				},
 				SubscriberURI: "subscriber-uri",
 				ReplyURI:      "reply-uri",
 			}
 			defaults := provisioners.DispatchDefaults{
 				Namespace: cNamespace,
 			}
-			rf := receiveFunc(zap.NewNop().Sugar(), sub, defaults, &fakeDispatcher{err: tc.dispatcherErr})
+			rf := receiveFunc(zap.NewNop().Sugar(), channelRef, sub, defaults, &fakeDispatcher{err: tc.dispatcherErr}, nil, nil)
 			msg := fakepubsub.Message{}
 			rf(context.TODO(), &msg)
 
@@ -392,6 +513,109 @@ func TestReceiveFunc(t *testing.T) {
 	}
 }
 
+func TestReceiveFuncDeliveryResult(t *testing.T) {
+	testCases := map[string]struct {
+		status int
+		result string
+		ack    bool
+	}{
+		"nacked": {
+			status: http.StatusAccepted,
+			result: provisioners.DeliveryResultNack,
+		},
+		"dropped": {
+			status: http.StatusBadRequest,
+			result: provisioners.DeliveryResultDrop,
+			ack:    true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(provisioners.DeliveryResultHeader, tc.result)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+			sub := &v1alpha1.ChannelSubscriberSpec{
+				Ref: &corev1.ObjectReference{
+					Namespace: cNamespace,
+					Name:      "sub-name",
+				},
+				SubscriberURI: server.URL,
+			}
+			defaults := provisioners.DispatchDefaults{
+				Namespace: cNamespace,
+			}
+			d := provisioners.NewMessageDispatcher(zap.NewNop().Sugar())
+			rf := receiveFunc(zap.NewNop().Sugar(), channelRef, sub, defaults, d, nil, nil)
+			msg := fakepubsub.Message{}
+			rf(context.TODO(), &msg)
+
+			if msg.MessageData.Ack != tc.ack || msg.MessageData.Nack == tc.ack {
+				t.Errorf("Unexpected outcome. Expected Ack %v, actual Ack %v and Nack %v", tc.ack, msg.MessageData.Ack, msg.MessageData.Nack)
+			}
+		})
+	}
+}
+
+func TestReceiveFuncDedup(t *testing.T) {
+	sub := &v1alpha1.ChannelSubscriberSpec{
+		Ref: &corev1.ObjectReference{
+			Namespace: cNamespace,
+			Name:      "sub-name",
+		},
+		SubscriberURI: "subscriber-uri",
+	}
+	defaults := provisioners.DispatchDefaults{
+		Namespace: cNamespace,
+	}
+	d := &fakeDispatcher{}
+	rf := receiveFunc(zap.NewNop().Sugar(), channelRef, sub, defaults, d, dedup.NewWindow(10, time.Minute), nil)
+	for i := 0; i < 2; i++ {
+		msg := fakepubsub.Message{
+			MessageData: fakepubsub.MessageData{
+				Attributes: map[string]string{
+					"ce-specversion": "1.0",
+					"ce-id":          "1234",
+					"ce-source":      "/orders",
+				},
+			},
+		}
+		rf(context.TODO(), &msg)
+		if !msg.MessageData.Ack {
+			t.Errorf("Message %d should have been Acked. It wasn't.", i)
+		}
+	}
+	if d.dispatched != 1 {
+		t.Errorf("Expected the redelivered message not to be dispatched. Dispatched %d times.", d.dispatched)
+	}
+}
+
+func TestReceiveFuncBacklog(t *testing.T) {
+	sub := &v1alpha1.ChannelSubscriberSpec{
+		Ref: &corev1.ObjectReference{
+			Namespace: cNamespace,
+			Name:      "sub-name",
+		},
+		SubscriberURI: "subscriber-uri",
+	}
+	backlog := provisioners.NewBacklogCounter()
+	var during int64
+	d := &fakeDispatcher{
+		onDispatch: func() {
+			during = backlog.Backlog()[channelRef]
+		},
+	}
+	rf := receiveFunc(zap.NewNop().Sugar(), channelRef, sub, provisioners.DispatchDefaults{}, d, nil, backlog)
+	rf(context.TODO(), &fakepubsub.Message{})
+	if during != 1 {
+		t.Errorf("Expected a backlog of 1 while the message is dispatched. Actual %d", during)
+	}
+	if after := backlog.Backlog()[channelRef]; after != 0 {
+		t.Errorf("Expected no backlog once the message is acknowledged. Actual %d", after)
+	}
+}
+
 func makeChannel() *eventingv1alpha1.Channel {
 	c := &eventingv1alpha1.Channel{
 		TypeMeta: metav1.TypeMeta{
@@ -445,6 +669,13 @@ func makeChannelWithSubscribersAndFinalizer() *eventingv1alpha1.Channel {
 	return c
 }
 
+func makeChannelWithPausedSubscriberAndFinalizer() *eventingv1alpha1.Channel {
+	c := makeChannelWithFinalizer()
+	c.Spec.Subscribable = subscribers.DeepCopy()
+	c.Spec.Subscribable.Subscribers[0].Paused = true
+	return c
+}
+
 func makeChannelWithFinalizer() *eventingv1alpha1.Channel {
 	c := makeChannel()
 	c.Finalizers = []string{finalizerName}
@@ -538,9 +769,16 @@ func (cc *cancelChecker) verify(t *testing.T, _ *controllertesting.TestCase) {
 }
 
 type fakeDispatcher struct {
-	err error
+	err        error
+	dispatched int
+	// onDispatch, if set, is called by DispatchMessage.
+	onDispatch func()
 }
 
 func (d *fakeDispatcher) DispatchMessage(_ *provisioners.Message, _, _ string, _ provisioners.DispatchDefaults) error {
+	d.dispatched++
+	if d.onDispatch != nil {
+		d.onDispatch()
+	}
 	return d.err
 }
@@ -19,8 +19,11 @@ package receiver
 import (
 	"context"
 
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/util"
@@ -37,21 +40,26 @@ type Receiver struct {
 
 	// Note that for all the default* parameters below, these must be kept in lock-step with the
 	// GCP PubSub Dispatcher's reconciler.
-	// Eventually, individual Channels should be allowed to specify different projects and secrets,
-	// but for now all Channels use the same project and secret.
+	// Individual Channels can specify a different project in their arguments, but for now all
+	// Channels use the same secret.
 
-	// defaultGcpProject is the GCP project ID where PubSub Topics and Subscriptions are created.
+	// defaultGcpProject is the GCP project ID where PubSub Topics and Subscriptions are created,
+	// unless the Channel specifies another one.
 	defaultGcpProject string
 	// defaultSecret and defaultSecretKey are the K8s Secret and key in that secret that contain a
 	// JSON format GCP service account token, see
 	// https://cloud.google.com/iam/docs/creating-managing-service-account-keys#iam-service-account-keys-create-gcloud
+	// If defaultSecret is nil, the Application Default Credentials are used instead.
 	defaultSecret    *v1.ObjectReference
 	defaultSecretKey string
+
+	// receiverOptions configure the MessageReceiver.
+	receiverOptions []provisioners.ReceiverOption
 }
 
 // New creates a new Receiver and its associated MessageReceiver. The caller is responsible for
-// Start()ing the returned MessageReceiver.
-func New(logger *zap.Logger, client client.Client, pubSubClientCreator util.PubSubClientCreator, defaultGcpProject string, defaultSecret *v1.ObjectReference, defaultSecretKey string) (*Receiver, *provisioners.MessageReceiver) {
+// Start()ing the returned MessageReceiver, which is configured with opts.
+func New(logger *zap.Logger, client client.Client, pubSubClientCreator util.PubSubClientCreator, defaultGcpProject string, defaultSecret *v1.ObjectReference, defaultSecretKey string, opts ...provisioners.ReceiverOption) (*Receiver, *provisioners.MessageReceiver) {
 	r := &Receiver{
 		logger: logger,
 		client: client,
@@ -61,12 +69,14 @@ func New(logger *zap.Logger, client client.Client, pubSubClientCreator util.PubS
 		defaultGcpProject: defaultGcpProject,
 		defaultSecret:     defaultSecret,
 		defaultSecretKey:  defaultSecretKey,
+
+		receiverOptions: opts,
 	}
 	return r, r.newMessageReceiver()
 }
 
 func (r *Receiver) newMessageReceiver() *provisioners.MessageReceiver {
-	return provisioners.NewMessageReceiver(r.sendEventToTopic, r.logger.Sugar())
+	return provisioners.NewMessageReceiver(r.sendEventToTopic, r.logger.Sugar(), r.receiverOptions...)
 }
 
 // sendEventToTopic sends a message to the Cloud Pub/Sub Topic backing the Channel.
@@ -80,7 +90,12 @@ func (r *Receiver) sendEventToTopic(channel provisioners.ChannelReference, messa
 		return err
 	}
 
-	psc, err := r.pubSubClientCreator(ctx, creds, r.defaultGcpProject)
+	args, err := r.channelArguments(ctx, channel)
+	if err != nil {
+		r.logger.Error("Failed to get the Channel arguments", zap.Error(err))
+		return err
+	}
+	psc, err := r.pubSubClientCreator(ctx, creds, args.Project(r.defaultGcpProject))
 	if err != nil {
 		r.logger.Error("Failed to create PubSub client", zap.Error(err))
 		return err
@@ -103,3 +118,17 @@ func (r *Receiver) sendEventToTopic(channel provisioners.ChannelReference, messa
 	r.logger.Debug("Published a message", zap.String("topicName", topicName), zap.String("pubSubMessageId", id))
 	return nil
 }
+
+// channelArguments returns the arguments of channel. A Channel that is not found has no arguments,
+// its events are published to its Topic if it still exists.
+func (r *Receiver) channelArguments(ctx context.Context, channel provisioners.ChannelReference) (util.ChannelArguments, error) {
+	c := &eventingv1alpha1.Channel{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}, c)
+	if errors.IsNotFound(err) {
+		return util.ChannelArguments{}, nil
+	}
+	if err != nil {
+		return util.ChannelArguments{}, err
+	}
+	return util.GetChannelArguments(c)
+}
@@ -22,10 +22,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners/gcppubsub/util/fakepubsub"
 	"go.uber.org/zap"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -51,10 +55,15 @@ const (
 )
 
 func TestReceiver(t *testing.T) {
+	// The receiver gets the Channel of each event for its arguments. The Channels of these test
+	// cases do not exist, so they have no arguments.
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
 	testCases := map[string]struct {
 		initialState []runtime.Object
 		pubSubData   fakepubsub.CreatorData
-		expectedErr  bool
+		// defaultCredentials uses the Application Default Credentials rather than a Secret.
+		defaultCredentials bool
+		expectedErr        bool
 	}{
 		"credential fails": {
 			initialState: []runtime.Object{
@@ -91,16 +100,28 @@ func TestReceiver(t *testing.T) {
 				testcreds.MakeSecretWithCreds(),
 			},
 		},
+		"Publish succeeds with the default credentials": {
+			defaultCredentials: true,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
+			secret, secretKey := testcreds.Secret, testcreds.SecretKey
+			if tc.defaultCredentials {
+				restore, err := testcreds.UseDefaultCredentials()
+				if err != nil {
+					t.Fatalf("Unable to set the default credentials: %v", err)
+				}
+				defer restore()
+				secret, secretKey = nil, ""
+			}
 			_, mr := New(
 				zap.NewNop(),
 				fake.NewFakeClient(tc.initialState...),
 				fakepubsub.Creator(tc.pubSubData),
 				gcpProject,
-				testcreds.Secret,
-				testcreds.SecretKey)
+				secret,
+				secretKey)
 			resp := httptest.NewRecorder()
 			req := httptest.NewRequest("POST", "/", strings.NewReader(validMessage))
 			mr.HandleRequest(resp, req)
@@ -116,3 +137,53 @@ func TestReceiver(t *testing.T) {
 		})
 	}
 }
+
+func TestReceiverGcpProject(t *testing.T) {
+	eventingv1alpha1.AddToScheme(scheme.Scheme)
+	testCases := map[string]struct {
+		args string
+		want string
+	}{
+		"default project": {
+			want: gcpProject,
+		},
+		"project override": {
+			args: `{"GcpProject": "other-project"}`,
+			want: "other-project",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			// The host of the request is example.com, the Channel example in namespace com.
+			channel := &eventingv1alpha1.Channel{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "com", Name: "example"},
+			}
+			if tc.args != "" {
+				channel.Spec.Arguments = &runtime.RawExtension{Raw: []byte(tc.args)}
+			}
+			created := &fakepubsub.CreatedData{}
+			_, mr := New(
+				zap.NewNop(),
+				fake.NewFakeClient(testcreds.MakeSecretWithCreds(), channel),
+				fakepubsub.Creator(fakepubsub.CreatorData{
+					ClientData: fakepubsub.ClientData{Created: created},
+				}),
+				gcpProject,
+				testcreds.Secret,
+				testcreds.SecretKey)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", strings.NewReader("data"))
+			req.Header.Set("ce-specversion", "1.0")
+			req.Header.Set("ce-id", "A234-1234-1234")
+			req.Header.Set("ce-source", "/mycontext")
+			req.Header.Set("ce-type", "com.example.someevent")
+			mr.HandleRequest(resp, req)
+			if resp.Result().StatusCode < 200 || resp.Result().StatusCode >= 300 {
+				t.Fatalf("Expected success. Actual: %v", resp.Result())
+			}
+			if diff := cmp.Diff([]string{tc.want}, created.Projects); diff != "" {
+				t.Errorf("unexpected projects (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// projectIDRegexp matches GCP project IDs.
+var projectIDRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+
+// ChannelArguments are the arguments of gcp-pubsub Channels.
+type ChannelArguments struct {
+	// GcpProject is the ID of the GCP project of the GCP PubSub Topic and Subscriptions of the
+	// Channel. The default project of the provisioner is used if it is empty.
+	GcpProject string `json:"GcpProject,omitempty"`
+}
+
+// GetChannelArguments returns the arguments of c. Channels without arguments have the zero
+// ChannelArguments.
+func GetChannelArguments(c *eventingv1alpha1.Channel) (ChannelArguments, error) {
+	var args ChannelArguments
+	if c.Spec.Arguments == nil || len(c.Spec.Arguments.Raw) == 0 {
+		return args, nil
+	}
+	if err := json.Unmarshal(c.Spec.Arguments.Raw, &args); err != nil {
+		return args, fmt.Errorf("error unmarshalling arguments: %v", err)
+	}
+	if args.GcpProject != "" && !projectIDRegexp.MatchString(args.GcpProject) {
+		return args, fmt.Errorf("invalid GcpProject %q, it must be a GCP project ID", args.GcpProject)
+	}
+	return args, nil
+}
+
+// Project returns the GCP project of the Channel, defaultProject if it does not override it.
+func (a ChannelArguments) Project(defaultProject string) string {
+	if a.GcpProject != "" {
+		return a.GcpProject
+	}
+	return defaultProject
+}
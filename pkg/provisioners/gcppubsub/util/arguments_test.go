@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+func TestGetChannelArguments(t *testing.T) {
+	testCases := map[string]struct {
+		args    string
+		want    ChannelArguments
+		wantErr bool
+	}{
+		"no arguments": {},
+		"project override": {
+			args: `{"GcpProject": "other-project"}`,
+			want: ChannelArguments{GcpProject: "other-project"},
+		},
+		"invalid project": {
+			args:    `{"GcpProject": "Other_Project"}`,
+			wantErr: true,
+		},
+		"invalid json": {
+			args:    `{"GcpProject": 1}`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c := &eventingv1alpha1.Channel{}
+			if tc.args != "" {
+				c.Spec.Arguments = &runtime.RawExtension{Raw: []byte(tc.args)}
+			}
+			got, err := GetChannelArguments(c)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error. Got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Unexpected arguments. Expected %+v. Actual %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	if got := (ChannelArguments{}).Project("default-project"); got != "default-project" {
+		t.Errorf("Unexpected project. Expected %q. Actual %q", "default-project", got)
+	}
+	if got := (ChannelArguments{GcpProject: "other-project"}).Project("default-project"); got != "other-project" {
+		t.Errorf("Unexpected project. Expected %q. Actual %q", "other-project", got)
+	}
+}
@@ -31,8 +31,13 @@ import (
 )
 
 // GetCredentials gets GCP credentials from a secretRef. The credentials must be stored in JSON format
-// in the secretRef.
+// in the secretRef. If secretRef is nil, the Application Default Credentials are used instead, such
+// as the GCP service account bound to the K8s service account of the Pod by GKE Workload Identity.
 func GetCredentials(ctx context.Context, client client.Client, secretRef *v1.ObjectReference, key string) (*google.Credentials, error) {
+	if secretRef == nil {
+		return getDefaultCredentials(ctx)
+	}
+
 	secret := &v1.Secret{}
 	err := client.Get(ctx, types.NamespacedName{Namespace: secretRef.Namespace, Name: secretRef.Name}, secret)
 	if err != nil {
@@ -53,3 +58,14 @@ func GetCredentials(ctx context.Context, client client.Client, secretRef *v1.Obj
 	}
 	return creds, nil
 }
+
+// getDefaultCredentials gets the Application Default Credentials, see
+// https://cloud.google.com/docs/authentication/production#providing_credentials_to_your_application
+func getDefaultCredentials(ctx context.Context) (*google.Credentials, error) {
+	creds, err := google.FindDefaultCredentials(ctx, pubsub.ScopePubSub)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to find the default GCP credential", zap.Error(err))
+		return nil, err
+	}
+	return creds, nil
+}
@@ -81,3 +81,20 @@ func TestGetCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDefaultCredentials(t *testing.T) {
+	restore, err := testcreds.UseDefaultCredentials()
+	if err != nil {
+		t.Fatalf("Unable to set the default credentials: %v", err)
+	}
+	defer restore()
+
+	// Without a Secret, the Application Default Credentials are used and no Secret is read.
+	creds, err := GetCredentials(context.TODO(), fake.NewFakeClient(), nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds == nil {
+		t.Fatal("creds should have been non-nil")
+	}
+}
@@ -42,7 +42,10 @@ func Creator(value interface{}) util.PubSubClientCreator {
 			return nil, data.ClientCreateErr
 		}
 	}
-	return func(_ context.Context, credentials *google.Credentials, _ string) (util.PubSubClient, error) {
+	return func(_ context.Context, credentials *google.Credentials, project string) (util.PubSubClient, error) {
+		if data.ClientData.Created != nil {
+			data.ClientData.Created.Projects = append(data.ClientData.Created.Projects, project)
+		}
 		return &Client{
 			Data: data.ClientData,
 		}, nil
@@ -54,6 +57,15 @@ type ClientData struct {
 	CreateSubErr     error
 	TopicData        TopicData
 	CreateTopicErr   error
+
+	// Created records the Clients and Subscriptions created, if it is set.
+	Created *CreatedData
+}
+
+// CreatedData records the projects of the Clients created by a Creator.
+type CreatedData struct {
+	// Projects holds the project of each created Client, in order.
+	Projects []string
 }
 
 type Client struct {
@@ -183,6 +195,8 @@ func (r *PublishResult) Get(ctx context.Context) (serverID string, err error) {
 type MessageData struct {
 	Ack  bool
 	Nack bool
+	// Attributes replaces the default attributes of the Message, if set.
+	Attributes map[string]string
 }
 
 type Message struct {
@@ -200,6 +214,9 @@ func (m *Message) Data() []byte {
 }
 
 func (m *Message) Attributes() map[string]string {
+	if m.MessageData.Attributes != nil {
+		return m.MessageData.Attributes
+	}
 	return map[string]string{
 		"test": "attributes",
 	}
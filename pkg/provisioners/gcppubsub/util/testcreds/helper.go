@@ -17,6 +17,10 @@ limitations under the License.
 package testcreds
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -65,3 +69,27 @@ func MakeSecretWithInvalidCreds() *v1.Secret {
 	secret.Data[SecretKey] = []byte("")
 	return secret
 }
+
+// UseDefaultCredentials makes the Application Default Credentials those of MakeSecretWithCreds,
+// until the returned function is called. Only tests should be calling this.
+func UseDefaultCredentials() (func(), error) {
+	dir, err := ioutil.TempDir("", "creds")
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, SecretKey)
+	if err := ioutil.WriteFile(file, MakeSecretWithCreds().Data[SecretKey], 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	previous, defined := os.LookupEnv("GOOGLE_APPLICATION_CREDENTIALS")
+	os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", file)
+	return func() {
+		if defined {
+			os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", previous)
+		} else {
+			os.Unsetenv("GOOGLE_APPLICATION_CREDENTIALS")
+		}
+		os.RemoveAll(dir)
+	}, nil
+}
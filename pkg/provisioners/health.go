@@ -0,0 +1,83 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HealthPortEnv is the environment variable holding the port dispatchers serve their health
+// endpoints on. The health endpoints are not served if it is not set.
+const HealthPortEnv = "HEALTH_PORT"
+
+// ReadinessCheck reports whether a dispatcher can serve traffic, e.g. whether its backend is
+// reachable. It returns nil when the dispatcher is ready.
+type ReadinessCheck func() error
+
+// HealthHandler returns the handler serving the health endpoints of a dispatcher. /healthz always
+// succeeds while the process is able to serve HTTP requests and is meant for liveness probes.
+// /readyz succeeds only if all the checks pass and is meant for readiness probes, so that
+// Kubernetes stops routing to dispatcher replicas that lost their backend.
+func HealthHandler(checks map[string]ReadinessCheck) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		names := make([]string, 0, len(checks))
+		for name := range checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var failures []string
+		for _, name := range names {
+			if err := checks[name](); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+		if len(failures) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, strings.Join(failures, "\n"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// ServeHealth serves HealthHandler on addr until stopCh is closed.
+func ServeHealth(addr string, checks map[string]ReadinessCheck, stopCh <-chan struct{}) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: HealthHandler(checks),
+	}
+	go func() {
+		<-stopCh
+		srv.Shutdown(context.Background())
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
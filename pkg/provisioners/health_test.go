@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	testCases := map[string]struct {
+		path     string
+		checks   map[string]ReadinessCheck
+		wantCode int
+		wantBody string
+	}{
+		"liveness": {
+			path: "/healthz",
+			checks: map[string]ReadinessCheck{
+				"broker": func() error { return errors.New("unreachable") },
+			},
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		"ready without checks": {
+			path:     "/readyz",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		"ready": {
+			path: "/readyz",
+			checks: map[string]ReadinessCheck{
+				"broker": func() error { return nil },
+			},
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		"not ready": {
+			path: "/readyz",
+			checks: map[string]ReadinessCheck{
+				"broker":   func() error { return errors.New("unreachable") },
+				"consumer": func() error { return nil },
+				"config":   func() error { return errors.New("not loaded") },
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "broker: unreachable\nconfig: not loaded",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HealthHandler(tc.checks).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != tc.wantCode {
+				t.Errorf("Unexpected status code. Expected %d, actual %d", tc.wantCode, w.Code)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tc.wantBody {
+				t.Errorf("Unexpected body. Expected %q, actual %q", tc.wantBody, body)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"flag"
+	"log"
 	"os"
 
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
+	"github.com/knative/pkg/configmap"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -15,26 +18,41 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 
 	eventingv1alpha "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/leaderelection"
+	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/provisioners"
 	provisionerController "github.com/knative/eventing/pkg/provisioners/kafka/controller"
 	"github.com/knative/eventing/pkg/provisioners/kafka/controller/channel"
+	"github.com/knative/eventing/pkg/system"
 )
 
 // SchemeFunc adds types to a Scheme.
 type SchemeFunc func(*runtime.Scheme) error
 
 // ProvideFunc adds a controller to a Manager.
-type ProvideFunc func(mgr manager.Manager, config *provisionerController.KafkaProvisionerConfig, logger *zap.Logger) (controller.Controller, error)
+type ProvideFunc func(mgr manager.Manager, configs *provisionerController.ConfigStore, logger *zap.Logger) (controller.Controller, error)
 
 func main() {
 	flag.Parse()
 	logf.SetLogger(logf.ZapLogger(false))
 
-	logger := provisioners.NewProvisionerLoggerFromConfig(provisioners.NewLoggingConfig())
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	cfg := config.GetConfigOrDie()
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("unable to create the kubernetes client: %v", err)
+	}
+
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.KafkaController, stopCh)
 	defer logger.Sync()
 
 	// Setup a Manager
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	mgr, err := namespaced.NewManager(cfg, manager.Options{}, namespaced.NamespacesFromEnv(), provisioners.CacheSelectors(provisionerController.Name)...)
 	if err != nil {
 		logger.Error(err, "unable to run controller manager")
 		os.Exit(1)
@@ -44,11 +62,19 @@ func main() {
 	schemeFuncs := []SchemeFunc{
 		eventingv1alpha.AddToScheme,
 		istiov1alpha3.AddToScheme,
+		securityv1beta1.AddToScheme,
 	}
 	for _, schemeFunc := range schemeFuncs {
 		schemeFunc(mgr.GetScheme())
 	}
 
+	// Coalesce the status updates of the Channels, so that reconciling many of them at once does
+	// not overwhelm the API server.
+	if err := provisioners.CoalesceStatusUpdates(mgr, logger.Desugar()); err != nil {
+		logger.Error("Invalid status update configuration", zap.Error(err))
+		os.Exit(1)
+	}
+
 	// Add each controller's ProvideController func to this list to have the
 	// manager run it.
 	providers := []ProvideFunc{
@@ -56,7 +82,6 @@ func main() {
 		channel.ProvideController,
 	}
 
-	// TODO the underlying config map needs to be watched and the config should be reloaded if there is a change.
 	provisionerConfig, err := provisionerController.GetProvisionerConfig("/etc/config-provisioner")
 
 	if err != nil {
@@ -64,15 +89,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The controllers load the config for every reconciliation, so that changes of its ConfigMap
+	// are applied without restarting them.
+	configs := provisionerController.NewConfigStore(provisionerConfig)
+	configMapWatcher := configmap.NewInformedWatcher(kc, system.Namespace)
+	provisionerController.WatchProvisionerConfig(configMapWatcher, logger.Desugar(), configs.Store)
+	if err := configMapWatcher.Start(stopCh); err != nil {
+		logger.Error("Unable to watch the provisioner config", zap.Error(err))
+		os.Exit(1)
+	}
+
 	for _, provider := range providers {
-		if _, err := provider(mgr, provisionerConfig, logger.Desugar()); err != nil {
+		if _, err := provider(mgr, configs, logger.Desugar()); err != nil {
 			logger.Error(err, "unable to run controller manager")
 			os.Exit(1)
 		}
 	}
 
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		go func() {
+			if err := provisioners.ServeMetrics(":"+port, stopCh); err != nil {
+				logger.Error("Unable to serve the metrics", zap.Error(err))
+			}
+		}()
+	}
+
+	if addr := debug.Addr(); addr != "" {
+		go func() {
+			if err := debug.Serve(addr, stopCh); err != nil {
+				logger.Error("Unable to serve the debug endpoints", zap.Error(err))
+			}
+		}()
+	}
+
+	election, err := leaderelection.ConfigFromEnv("kafka-channel-controller")
+	if err != nil {
+		logger.Fatal("Invalid leader election configuration", zap.Error(err))
+	}
+
 	// Start blocks forever.
-	err = mgr.Start(signals.SetupSignalHandler())
+	err = leaderelection.RunManager(stopCh, election, mgr, logger.Desugar())
 	if err != nil {
 		logger.Fatal("Manager.Start() returned an error", zap.Error(err))
 	}
@@ -21,17 +21,34 @@ import (
 	"log"
 	"os"
 
+	"github.com/knative/pkg/configmap"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/logconfig"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/amqp/ingress"
+	"github.com/knative/eventing/pkg/provisioners/audit"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/claimcheck"
+	"github.com/knative/eventing/pkg/provisioners/encryption"
 	provisionerController "github.com/knative/eventing/pkg/provisioners/kafka/controller"
 	"github.com/knative/eventing/pkg/provisioners/kafka/dispatcher"
+	"github.com/knative/eventing/pkg/provisioners/push"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+	"github.com/knative/eventing/pkg/provisioners/signing"
+	"github.com/knative/eventing/pkg/provisioners/tap"
 	"github.com/knative/eventing/pkg/sidecar/configmap/watcher"
 	"github.com/knative/eventing/pkg/system"
+	"github.com/knative/eventing/pkg/tracing"
 )
 
 func main() {
@@ -45,29 +62,163 @@ func main() {
 		configMapNamespace = system.Namespace
 	}
 
-	logger, err := zap.NewProduction()
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	mgr, err := namespaced.NewManager(config.GetConfigOrDie(), manager.Options{}, namespaced.NamespacesFromEnv())
+	if err != nil {
+		log.Fatalf("unable to create manager: %v", err)
+	}
+
+	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
-		log.Fatalf("unable to create logger: %v", err)
+		log.Fatalf("unable to create kubernetes client: %v", err)
 	}
 
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.KafkaDispatcher, stopCh).Desugar()
+
 	provisionerConfig, err := provisionerController.GetProvisionerConfig("/etc/config-provisioner")
 	if err != nil {
 		logger.Fatal("unable to load provisioner config", zap.Error(err))
 	}
 
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
-	if err != nil {
-		logger.Fatal("unable to create manager.", zap.Error(err))
+	if err := provisioners.SetMetricsGranularity(os.Getenv(provisioners.MetricsGranularityEnv)); err != nil {
+		logger.Fatal("invalid metrics configuration", zap.Error(err))
 	}
 
-	kafkaDispatcher, err := dispatcher.NewDispatcher(provisionerConfig.Brokers, logger)
+	var opts []dispatcher.Option
+	if provisionerConfig.DedupWindow > 0 {
+		opts = append(opts, dispatcher.WithDedupWindow(provisionerConfig.DedupWindow, provisionerConfig.DedupSize))
+	}
+	opts = append(opts, dispatcher.WithOffsets(provisionerConfig.OffsetCommitInterval, provisionerConfig.InitialOffset))
+	opts = append(opts, dispatcher.WithRebalanceTimeout(provisionerConfig.RebalanceTimeout))
+	// Events larger than the maximum message size of the Kafka brokers can be carried by offloading
+	// their data with a claim check.
+	claimCheck, err := claimcheck.FromEnv()
+	if err != nil {
+		logger.Fatal("invalid claim check configuration", zap.Error(err))
+	}
+	if claimCheck != nil {
+		opts = append(opts, dispatcher.WithClaimCheck(claimCheck.Store, claimCheck.Threshold))
+	}
+	// The data of events can be encrypted, so that it is not stored in plaintext by the brokers.
+	encrypter, err := encryption.FromEnv()
+	if err != nil {
+		logger.Fatal("invalid encryption configuration", zap.Error(err))
+	}
+	if encrypter != nil {
+		opts = append(opts, dispatcher.WithEncryption(encrypter))
+	}
+	maxBodySize, err := provisioners.MaxBodySizeFromEnv()
+	if err != nil {
+		logger.Fatal("invalid maximum body size", zap.Error(err))
+	}
+	opts = append(opts, dispatcher.WithMaxBodySize(maxBodySize))
+	// Events can be signed at the ingress, so that the subscribers can authenticate their origin,
+	// and their signature verified before they are delivered.
+	signer, err := signing.SignerFromEnv()
+	if err != nil {
+		logger.Fatal("invalid signing configuration", zap.Error(err))
+	}
+	if signer != nil {
+		opts = append(opts, dispatcher.WithSigning(signer))
+	}
+	verifier, err := signing.VerifierFromEnv()
+	if err != nil {
+		logger.Fatal("invalid signature verification configuration", zap.Error(err))
+	}
+	if verifier != nil {
+		opts = append(opts, dispatcher.WithSignatureVerification(verifier))
+	}
+	auditSink, err := audit.FromEnv(logger, stopCh)
+	if err != nil {
+		logger.Fatal("invalid audit configuration", zap.Error(err))
+	}
+	if auditSink != nil {
+		opts = append(opts, dispatcher.WithAuditSink(auditSink))
+	}
+	opts = append(opts, dispatcher.WithSchemaResolver(schema.NewResolver(schema.KubeConfigMapGetter(kc))))
+	opts = append(opts, dispatcher.WithAuthResolver(auth.NewResolver(auth.KubeSecretGetter(kc))))
+	tlsConfig, err := auth.TLSConfigFromEnv()
+	if err != nil {
+		logger.Fatal("invalid client certificate configuration", zap.Error(err))
+	}
+	if tlsConfig != nil {
+		opts = append(opts, dispatcher.WithTLSConfig(tlsConfig))
+	}
+	tapHub, err := tap.FromEnv(kc, logger, stopCh)
+	if err != nil {
+		logger.Fatal("invalid tap configuration", zap.Error(err))
+	}
+	if tapHub != nil {
+		opts = append(opts, dispatcher.WithTap(tapHub))
+	}
+	pushHub, err := push.FromEnv(kc, logger, stopCh)
+	if err != nil {
+		logger.Fatal("invalid push configuration", zap.Error(err))
+	}
+	if pushHub != nil {
+		opts = append(opts, dispatcher.WithPusher(pushHub))
+	}
+	if err := ingress.FromEnv(provisioners.MessageReceiverPort, logger, stopCh); err != nil {
+		logger.Fatal("Invalid AMQP configuration", zap.Error(err))
+	}
+	// The credentials of the brokers are watched, so that the clients are rebuilt with the rotated
+	// credentials.
+	var secret *corev1.Secret
+	if provisionerConfig.CredentialsSecret != "" {
+		secret, err = kc.CoreV1().Secrets(system.Namespace).Get(provisionerConfig.CredentialsSecret, metav1.GetOptions{})
+		if err != nil {
+			logger.Fatal("unable to read the kafka credentials", zap.Error(err))
+		}
+	}
+	creds, err := provisionerController.NewCredentials(provisionerConfig, secret)
+	if err != nil {
+		logger.Fatal("invalid kafka credentials", zap.Error(err))
+	}
+	opts = append(opts, dispatcher.WithCredentials(creds))
+	kafkaDispatcher, err := dispatcher.NewDispatcher(provisionerConfig.Brokers, logger, opts...)
 	if err != nil {
 		logger.Fatal("unable to create kafka dispatcher.", zap.Error(err))
 	}
+	if provisionerConfig.CredentialsSecret != "" {
+		// The rotated credentials keep the security settings the dispatcher started with.
+		mgr.Add(dispatcher.NewCredentialsWatcher(logger, kc, system.Namespace, provisionerConfig.CredentialsSecret, func(creds *provisionerController.Credentials) error {
+			creds.Security = provisionerConfig.Security
+			return kafkaDispatcher.UpdateCredentials(creds)
+		}))
+	}
 
-	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
-	if err != nil {
-		logger.Fatal("unable to create kubernetes client.", zap.Error(err))
+	// The bootstrap servers, the offset configuration and the deduplication window are applied
+	// without restarting the dispatcher when the provisioner config changes.
+	provisionerConfigWatcher := configmap.NewInformedWatcher(kc, system.Namespace)
+	provisionerController.WatchProvisionerConfig(provisionerConfigWatcher, logger, func(config *provisionerController.KafkaProvisionerConfig) {
+		if config.CredentialsSecret != provisionerConfig.CredentialsSecret {
+			logger.Warn("The credentials Secret changed, restart the dispatcher to watch it", zap.String("secret", config.CredentialsSecret))
+		}
+		if config.Security != provisionerConfig.Security {
+			logger.Warn("The security settings of the brokers changed, restart the dispatcher to apply them", zap.Any("security", config.Security))
+		}
+		if err := kafkaDispatcher.UpdateProvisionerConfig(config); err != nil {
+			logger.Error("Unable to apply the provisioner config", zap.Error(err))
+		}
+	})
+	if err := provisionerConfigWatcher.Start(stopCh); err != nil {
+		logger.Fatal("unable to watch the provisioner config", zap.Error(err))
+	}
+
+	// The offsets of the subscriptions can be read, and reset to replay or skip events.
+	if err := dispatcher.ServeOffsetsFromEnv(kafkaDispatcher, kc, logger, stopCh); err != nil {
+		logger.Fatal("invalid offsets API configuration", zap.Error(err))
+	}
+
+	if err := provisioners.RegisterBacklog(kafkaDispatcher); err != nil {
+		logger.Fatal("unable to register the backlog metric", zap.Error(err))
+	}
+	// The lag of the consumer groups is exported for the autoscalers scaling the replicas with the
+	// backlog of the subscriptions, such as KEDA.
+	if err := dispatcher.RegisterConsumerLag(kafkaDispatcher); err != nil {
+		logger.Fatal("unable to register the consumer lag metric", zap.Error(err))
 	}
 
 	cmw, err := watcher.NewWatcher(logger, kc, configMapNamespace, configMapName, kafkaDispatcher.UpdateConfig)
@@ -76,8 +227,9 @@ func main() {
 	}
 	mgr.Add(cmw)
 
-	// set up signals so we handle the first shutdown signal gracefully
-	stopCh := signals.SetupSignalHandler()
+	if err := tracing.WatchConfigMap(kc, system.Namespace, logconfig.KafkaDispatcher, logger.Sugar(), stopCh); err != nil {
+		logger.Warn("Spans are not exported until the tracing config map is created", zap.Error(err))
+	}
 
 	// Start both the manager (which notices ConfigMap changes) and the HTTP server.
 	var g errgroup.Group
@@ -91,6 +243,25 @@ func main() {
 		return kafkaDispatcher.Start(stopCh)
 	})
 
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		g.Go(func() error {
+			return provisioners.ServeMetrics(":"+port, stopCh)
+		})
+	}
+
+	if addr := debug.Addr(); addr != "" {
+		g.Go(func() error {
+			return debug.Serve(addr, stopCh)
+		})
+	}
+
+	if port := os.Getenv(provisioners.HealthPortEnv); port != "" {
+		checks := map[string]provisioners.ReadinessCheck{"kafka": kafkaDispatcher.Ready}
+		g.Go(func() error {
+			return provisioners.ServeHealth(":"+port, checks, stopCh)
+		})
+	}
+
 	err = g.Wait()
 	if err != nil {
 		logger.Error("Either the kafka message receiver or the ConfigMap noticer failed.", zap.Error(err))
@@ -22,6 +22,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -31,6 +32,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	util "github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
 	common "github.com/knative/eventing/pkg/provisioners/kafka/controller"
 	"github.com/knative/eventing/pkg/system"
 )
@@ -52,8 +56,15 @@ type reconciler struct {
 	client       client.Client
 	recorder     record.EventRecorder
 	logger       *zap.Logger
-	config       *common.KafkaProvisionerConfig
+	configs      *common.ConfigStore
 	configMapKey client.ObjectKey
+	// scope is where the dispatchers of the channels run. With util.NamespaceDispatcherScope, the
+	// dispatcher of each namespace reads a ConfigMap of its namespace named like the one of the
+	// knative-eventing namespace.
+	scope util.DispatcherScope
+	// getSecret reads the credentials of the brokers. They are not read through the cache of the
+	// manager, which would hold every Secret.
+	getSecret auth.SecretGetter
 	// Using a shared kafkaClusterAdmin does not work currently because of an issue with
 	// Shopify/sarama, see https://github.com/Shopify/sarama/issues/1162.
 	kafkaClusterAdmin sarama.ClusterAdmin
@@ -63,15 +74,28 @@ type reconciler struct {
 var _ reconcile.Reconciler = &reconciler{}
 
 // ProvideController returns a Channel controller.
-func ProvideController(mgr manager.Manager, config *common.KafkaProvisionerConfig, logger *zap.Logger) (controller.Controller, error) {
+func ProvideController(mgr manager.Manager, configs *common.ConfigStore, logger *zap.Logger) (controller.Controller, error) {
+	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := util.DispatcherScopeFromEnv()
+	if err != nil {
+		logger.Error("Invalid dispatcher scope.", zap.Error(err))
+		return nil, err
+	}
+
 	// Setup a new controller to Reconcile Channel.
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: &reconciler{
+		Reconciler: metrics.InstrumentReconciler(controllerAgentName, &reconciler{
 			recorder:     mgr.GetRecorder(controllerAgentName),
 			logger:       logger,
-			config:       config,
+			configs:      configs,
 			configMapKey: defaultConfigMapKey,
-		},
+			scope:        scope,
+			getSecret:    auth.KubeSecretGetter(kc),
+		}),
 	})
 	if err != nil {
 		return nil, err
@@ -96,6 +120,11 @@ func ProvideController(mgr manager.Manager, config *common.KafkaProvisionerConfi
 		return nil, err
 	}
 
+	// Watch the AuthorizationPolicies of Channels, when Istio security is installed.
+	if err := util.WatchAuthorizationPolicies(c); err != nil {
+		logger.Warn("not watching AuthorizationPolicies.", zap.Error(err))
+	}
+
 	return c, nil
 }
 
@@ -20,6 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"go.uber.org/zap"
@@ -38,6 +41,7 @@ import (
 	"github.com/knative/eventing/pkg/sidecar/configmap"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"github.com/knative/eventing/pkg/system"
 	"k8s.io/apimachinery/pkg/api/equality"
 )
 
@@ -45,17 +49,44 @@ const (
 	finalizerName = controllerAgentName
 
 	DefaultNumPartitions = 1
+
+	// Reasons of the Events emitted on Channels.
+	topicCreated      = "TopicCreated"
+	topicCreateFailed = "TopicCreateFailed"
+	topicDeleted      = "TopicDeleted"
+	topicDeleteFailed = "TopicDeleteFailed"
+	topicRetained     = "TopicRetained"
+	topicRetainFailed = "TopicRetainFailed"
+	topicOrphaned     = "TopicOrphaned"
+
+	// The policies of the topic of a Channel when the Channel is deleted.
+	topicDeletionPolicyDelete = "Delete"
+	topicDeletionPolicyRetain = "Retain"
+	topicDeletionPolicyOrphan = "Orphan"
 )
 
 type channelArgs struct {
 	NumPartitions int32
+	// PartitionKey is the CloudEvents attribute, e.g. subject or an extension, whose value is the
+	// key of the Kafka messages holding the events. Events with the same key are written to the
+	// same partition, so they are delivered in order.
+	PartitionKey string
+	// TopicDeletionPolicy is what happens to the topic when the channel is deleted: Delete, the
+	// default, deletes it, Retain keeps its events until they are TopicRetentionDays old, and
+	// Orphan keeps the topic and its events.
+	TopicDeletionPolicy string
+	// TopicRetentionDays is how long the events of the topic are kept with the Retain policy.
+	TopicRetentionDays int32
 }
 
+// attributeNameRegexp matches the names of CloudEvents attributes.
+var attributeNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
 // Reconcile compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Channel resource
 // with the current status of the resource.
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	ctx := context.TODO()
+	ctx := util.WithEventRecorder(context.TODO(), r.recorder)
 	r.logger.Info("Reconciling channel", zap.Any("request", request))
 	channel := &eventingv1alpha1.Channel{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, channel)
@@ -119,7 +150,7 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 func (r *reconciler) reconcile(ctx context.Context, channel *eventingv1alpha1.Channel) (bool, error) {
 
 	// We always need to sync the Channel config, so do it first.
-	if err := r.syncChannelConfig(ctx); err != nil {
+	if err := r.syncChannelConfig(ctx, channel.Namespace); err != nil {
 		r.logger.Info("error updating syncing the Channel config", zap.Error(err))
 		return false, err
 	}
@@ -130,8 +161,13 @@ func (r *reconciler) reconcile(ctx context.Context, channel *eventingv1alpha1.Ch
 	// used to pass a fake admin client in the tests.
 	kafkaClusterAdmin := r.kafkaClusterAdmin
 	if kafkaClusterAdmin == nil {
-		var err error
-		kafkaClusterAdmin, err = createKafkaAdminClient(r.config)
+		config := r.configs.Load()
+		creds, err := r.credentials(config)
+		if err != nil {
+			r.logger.Error("unable to read the kafka credentials", zap.Error(err))
+			return false, err
+		}
+		kafkaClusterAdmin, err = createKafkaAdminClient(config, creds)
 		if err != nil {
 			r.logger.Fatal("unable to build kafka admin client", zap.Error(err))
 			return false, err
@@ -150,6 +186,9 @@ func (r *reconciler) reconcile(ctx context.Context, channel *eventingv1alpha1.Ch
 		if err := r.deprovisionChannel(channel, kafkaClusterAdmin); err != nil {
 			return false, err
 		}
+		if err := util.DeleteAuthorizationPolicy(ctx, r.client, channel); err != nil {
+			return false, err
+		}
 		util.RemoveFinalizer(channel, finalizerName)
 		return false, nil
 	}
@@ -180,7 +219,7 @@ func (r *reconciler) reconcile(ctx context.Context, channel *eventingv1alpha1.Ch
 
 	channel.Status.SetAddress(eventingController.ServiceHostName(svc.Name, svc.Namespace))
 
-	virtualService, err := util.CreateVirtualService(ctx, r.client, channel)
+	virtualService, err := util.CreateVirtualServiceWithDestination(ctx, r.client, channel, r.scope.DestinationHost()(channel))
 
 	if err != nil {
 		r.logger.Info("error creating the Virtual Service for the Channel", zap.Error(err))
@@ -193,6 +232,11 @@ func (r *reconciler) reconcile(ctx context.Context, channel *eventingv1alpha1.Ch
 		r.logger.Warn("VirtualService not owned by Channel", zap.Any("channel", channel), zap.Any("virtualService", virtualService))
 	}
 
+	if _, err := util.CreateAuthorizationPolicy(ctx, r.client, channel); err != nil {
+		r.logger.Info("error creating the AuthorizationPolicy for the Channel", zap.Error(err))
+		return false, err
+	}
+
 	channel.Status.MarkProvisioned()
 
 	// close the connection
@@ -231,14 +275,35 @@ func (r *reconciler) provisionChannel(channel *eventingv1alpha1.Channel, kafkaCl
 		return nil
 	} else if err != nil {
 		r.logger.Error("error creating topic", zap.String("topic", topicName), zap.Error(err))
+		r.recorder.Eventf(channel, corev1.EventTypeWarning, topicCreateFailed, "Failed to create topic %q: %v", topicName, err)
 	} else {
 		r.logger.Info("successfully created topic", zap.String("topic", topicName))
+		r.recorder.Eventf(channel, corev1.EventTypeNormal, topicCreated, "Created topic %q with %d partitions", topicName, arguments.NumPartitions)
 	}
 	return err
 }
 
+// deprovisionChannel applies the deletion policy of the channel to its topic. Channels with invalid
+// arguments delete their topic, as the arguments were validated when the channel was created.
 func (r *reconciler) deprovisionChannel(channel *eventingv1alpha1.Channel, kafkaClusterAdmin sarama.ClusterAdmin) error {
 	topicName := topicUtils.TopicName(controller.KafkaChannelSeparator, channel.Namespace, channel.Name)
+
+	var arguments channelArgs
+	if channel.Spec.Arguments != nil {
+		var err error
+		if arguments, err = unmarshalArguments(channel.Spec.Arguments.Raw); err != nil {
+			r.logger.Warn("invalid channel arguments, deleting the topic", zap.String("topic", topicName), zap.Error(err))
+		}
+	}
+	switch arguments.TopicDeletionPolicy {
+	case topicDeletionPolicyOrphan:
+		r.logger.Info("keeping topic on kafka cluster", zap.String("topic", topicName))
+		r.recorder.Eventf(channel, corev1.EventTypeNormal, topicOrphaned, "Kept topic %q", topicName)
+		return nil
+	case topicDeletionPolicyRetain:
+		return r.retainTopic(channel, topicName, arguments.TopicRetentionDays, kafkaClusterAdmin)
+	}
+
 	r.logger.Info("deleting topic on kafka cluster", zap.String("topic", topicName))
 
 	err := kafkaClusterAdmin.DeleteTopic(topicName)
@@ -246,8 +311,28 @@ func (r *reconciler) deprovisionChannel(channel *eventingv1alpha1.Channel, kafka
 		return nil
 	} else if err != nil {
 		r.logger.Error("error deleting topic", zap.String("topic", topicName), zap.Error(err))
+		r.recorder.Eventf(channel, corev1.EventTypeWarning, topicDeleteFailed, "Failed to delete topic %q: %v", topicName, err)
 	} else {
 		r.logger.Info("successfully deleted topic %s", zap.String("topic", topicName))
+		r.recorder.Eventf(channel, corev1.EventTypeNormal, topicDeleted, "Deleted topic %q", topicName)
+	}
+	return err
+}
+
+// retainTopic keeps the topic of channel, setting its retention so that Kafka deletes its events
+// once they are days old.
+func (r *reconciler) retainTopic(channel *eventingv1alpha1.Channel, topicName string, days int32, kafkaClusterAdmin sarama.ClusterAdmin) error {
+	r.logger.Info("retaining topic on kafka cluster", zap.String("topic", topicName), zap.Int32("days", days))
+	retention := strconv.FormatInt(int64(days)*int64(24*time.Hour/time.Millisecond), 10)
+	err := kafkaClusterAdmin.AlterConfig(sarama.TopicResource, topicName, map[string]*string{"retention.ms": &retention}, false)
+	if err == sarama.ErrUnknownTopicOrPartition {
+		return nil
+	} else if err != nil {
+		r.logger.Error("error retaining topic", zap.String("topic", topicName), zap.Error(err))
+		r.recorder.Eventf(channel, corev1.EventTypeWarning, topicRetainFailed, "Failed to set the retention of topic %q: %v", topicName, err)
+	} else {
+		r.logger.Info("successfully retained topic", zap.String("topic", topicName))
+		r.recorder.Eventf(channel, corev1.EventTypeNormal, topicRetained, "Kept topic %q with a retention of %d days", topicName, days)
 	}
 	return err
 }
@@ -263,18 +348,27 @@ func (r *reconciler) getClusterChannelProvisioner() (*eventingv1alpha1.ClusterCh
 	return clusterChannelProvisioner, nil
 }
 
-func (r *reconciler) syncChannelConfig(ctx context.Context) error {
-	channels, err := r.listAllChannels(ctx)
+// syncChannelConfig writes the config of the dispatcher of the channels of namespace: the
+// dispatcher of the namespace with util.NamespaceDispatcherScope, or the dispatcher of every
+// namespace.
+func (r *reconciler) syncChannelConfig(ctx context.Context, namespace string) error {
+	configMapKey := r.configMapKey
+	if r.scope == util.NamespaceDispatcherScope {
+		configMapKey = client.ObjectKey{Namespace: namespace, Name: r.configMapKey.Name}
+	} else {
+		namespace = ""
+	}
+	channels, err := r.listAllChannels(ctx, namespace)
 	if err != nil {
 		r.logger.Info("Unable to list channels", zap.Error(err))
 		return err
 	}
 	config := multiChannelFanoutConfig(channels)
-	return r.writeConfigMap(ctx, config)
+	return r.writeConfigMap(ctx, configMapKey, config)
 }
 
-func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfanout.Config) error {
-	logger := r.logger.With(zap.Any("configMap", r.configMapKey))
+func (r *reconciler) writeConfigMap(ctx context.Context, configMapKey client.ObjectKey, config *multichannelfanout.Config) error {
+	logger := r.logger.With(zap.Any("configMap", configMapKey))
 
 	updated, err := configmap.SerializeConfig(*config)
 	if err != nil {
@@ -283,9 +377,9 @@ func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfan
 	}
 
 	cm := &corev1.ConfigMap{}
-	err = r.client.Get(ctx, r.configMapKey, cm)
+	err = r.client.Get(ctx, configMapKey, cm)
 	if errors.IsNotFound(err) {
-		cm = r.createNewConfigMap(updated)
+		cm = createNewConfigMap(configMapKey, updated)
 		err = r.client.Create(ctx, cm)
 		if err != nil {
 			logger.Info("Unable to create ConfigMap", zap.Error(err))
@@ -306,11 +400,11 @@ func (r *reconciler) writeConfigMap(ctx context.Context, config *multichannelfan
 	return r.client.Update(ctx, cm)
 }
 
-func (r *reconciler) createNewConfigMap(data map[string]string) *corev1.ConfigMap {
+func createNewConfigMap(configMapKey client.ObjectKey, data map[string]string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: r.configMapKey.Namespace,
-			Name:      r.configMapKey.Name,
+			Namespace: configMapKey.Namespace,
+			Name:      configMapKey.Name,
 		},
 		Data: data,
 	}
@@ -323,6 +417,12 @@ func multiChannelFanoutConfig(channels []eventingv1alpha1.Channel) *multichannel
 			Namespace: c.Namespace,
 			Name:      c.Name,
 		}
+		if c.Spec.Arguments != nil {
+			// Channels with invalid arguments are not provisioned, so their events are not keyed.
+			if arguments, err := unmarshalArguments(c.Spec.Arguments.Raw); err == nil {
+				channelConfig.PartitionKey = arguments.PartitionKey
+			}
+		}
 		if c.Spec.Subscribable != nil {
 			channelConfig.FanoutConfig = fanout.Config{
 				Subscriptions: c.Spec.Subscribable.Subscribers,
@@ -335,7 +435,8 @@ func multiChannelFanoutConfig(channels []eventingv1alpha1.Channel) *multichannel
 	}
 }
 
-func (r *reconciler) listAllChannels(ctx context.Context) ([]eventingv1alpha1.Channel, error) {
+// listAllChannels lists the Kafka Channels of namespace, or of every namespace if it is empty.
+func (r *reconciler) listAllChannels(ctx context.Context, namespace string) ([]eventingv1alpha1.Channel, error) {
 	clusterChannelProvisioner, err := r.getClusterChannelProvisioner()
 	if err != nil {
 		return nil, err
@@ -344,6 +445,7 @@ func (r *reconciler) listAllChannels(ctx context.Context) ([]eventingv1alpha1.Ch
 	channels := make([]eventingv1alpha1.Channel, 0)
 
 	opts := &client.ListOptions{
+		Namespace: namespace,
 		// TODO this is here because the fake client needs it. Remove this when it's no longer
 		// needed.
 		Raw: &metav1.ListOptions{
@@ -372,10 +474,26 @@ func (r *reconciler) listAllChannels(ctx context.Context) ([]eventingv1alpha1.Ch
 	}
 }
 
-func createKafkaAdminClient(config *controller.KafkaProvisionerConfig) (sarama.ClusterAdmin, error) {
+// credentials reads the credentials of the brokers, or returns nil if they need none. They are read
+// for every reconciliation, so that rotated credentials are used without restarting the controller.
+func (r *reconciler) credentials(config *controller.KafkaProvisionerConfig) (*controller.Credentials, error) {
+	var secret *corev1.Secret
+	if config.CredentialsSecret != "" {
+		var err error
+		if secret, err = r.getSecret(system.Namespace, config.CredentialsSecret); err != nil {
+			return nil, err
+		}
+	}
+	return controller.NewCredentials(config, secret)
+}
+
+func createKafkaAdminClient(config *controller.KafkaProvisionerConfig, creds *controller.Credentials) (sarama.ClusterAdmin, error) {
 	saramaConf := sarama.NewConfig()
 	saramaConf.Version = sarama.V1_1_0_0
 	saramaConf.ClientID = controllerAgentName
+	if err := creds.Apply(saramaConf); err != nil {
+		return nil, err
+	}
 	return sarama.NewClusterAdmin(config.Brokers, saramaConf)
 }
 
@@ -387,5 +505,20 @@ func unmarshalArguments(bytes []byte) (channelArgs, error) {
 			return arguments, fmt.Errorf("error unmarshalling arguments: %s", err)
 		}
 	}
+	if arguments.PartitionKey != "" && !attributeNameRegexp.MatchString(arguments.PartitionKey) {
+		return arguments, fmt.Errorf("invalid PartitionKey %q, it must be the name of a CloudEvents attribute", arguments.PartitionKey)
+	}
+	switch arguments.TopicDeletionPolicy {
+	case "", topicDeletionPolicyDelete, topicDeletionPolicyOrphan:
+		if arguments.TopicRetentionDays != 0 {
+			return arguments, fmt.Errorf("TopicRetentionDays is only allowed with the %s TopicDeletionPolicy", topicDeletionPolicyRetain)
+		}
+	case topicDeletionPolicyRetain:
+		if arguments.TopicRetentionDays <= 0 {
+			return arguments, fmt.Errorf("the %s TopicDeletionPolicy requires a positive TopicRetentionDays", topicDeletionPolicyRetain)
+		}
+	default:
+		return arguments, fmt.Errorf("invalid TopicDeletionPolicy %q, it must be %s, %s or %s", arguments.TopicDeletionPolicy, topicDeletionPolicyDelete, topicDeletionPolicyRetain, topicDeletionPolicyOrphan)
+	}
 	return arguments, nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/google/go-cmp/cmp"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
 	"github.com/knative/eventing/pkg/provisioners"
 	util "github.com/knative/eventing/pkg/provisioners"
@@ -60,6 +61,7 @@ func init() {
 	// Add types to scheme
 	eventingv1alpha1.AddToScheme(scheme.Scheme)
 	istiov1alpha3.AddToScheme(scheme.Scheme)
+	securityv1beta1.AddToScheme(scheme.Scheme)
 }
 
 var mockFetchError = controllertesting.Mocks{
@@ -77,6 +79,7 @@ var mockFetchError = controllertesting.Mocks{
 type mockClusterAdmin struct {
 	mockCreateTopicFunc func(topic string, detail *sarama.TopicDetail, validateOnly bool) error
 	mockDeleteTopicFunc func(topic string) error
+	mockAlterConfigFunc func(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error
 }
 
 func (ca *mockClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
@@ -110,6 +113,9 @@ func (ca *mockClusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sa
 }
 
 func (ca *mockClusterAdmin) AlterConfig(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error {
+	if ca.mockAlterConfigFunc != nil {
+		return ca.mockAlterConfigFunc(resourceType, name, entries, validateOnly)
+	}
 	return nil
 }
 
@@ -231,7 +237,7 @@ func TestAllCases(t *testing.T) {
 			client:            c,
 			recorder:          recorder,
 			logger:            logger.Desugar(),
-			config:            getControllerConfig(),
+			configs:           controller.NewConfigStore(getControllerConfig()),
 			kafkaClusterAdmin: &mockClusterAdmin{},
 		}
 		t.Logf("Running test %s", tc.Name)
@@ -247,6 +253,7 @@ func TestProvisionChannel(t *testing.T) {
 		wantTopicDetail *sarama.TopicDetail
 		mockError       error
 		wantError       string
+		wantEvent       string
 	}{
 		{
 			name:          "provision with no channel arguments - uses default",
@@ -256,6 +263,7 @@ func TestProvisionChannel(t *testing.T) {
 				ReplicationFactor: 1,
 				NumPartitions:     1,
 			},
+			wantEvent: fmt.Sprintf(`Normal TopicCreated Created topic "%s.%s.%s" with 1 partitions`, topicPrefix, testNS, channelName),
 		},
 		{
 			name:          "provision with unknown channel arguments - uses default",
@@ -265,6 +273,7 @@ func TestProvisionChannel(t *testing.T) {
 				ReplicationFactor: 1,
 				NumPartitions:     1,
 			},
+			wantEvent: fmt.Sprintf(`Normal TopicCreated Created topic "%s.%s.%s" with 1 partitions`, topicPrefix, testNS, channelName),
 		},
 		{
 			name:      "provision with invalid channel arguments - errors",
@@ -295,6 +304,47 @@ func TestProvisionChannel(t *testing.T) {
 				ReplicationFactor: 1,
 				NumPartitions:     2,
 			},
+			wantEvent: fmt.Sprintf(`Normal TopicCreated Created topic "%s.%s.%s" with 2 partitions`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:          "provision with partition key",
+			c:             getNewChannelWithArgs(channelName, map[string]interface{}{argumentNumPartitions: 2, "PartitionKey": "subject"}),
+			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantTopicDetail: &sarama.TopicDetail{
+				ReplicationFactor: 1,
+				NumPartitions:     2,
+			},
+			wantEvent: fmt.Sprintf(`Normal TopicCreated Created topic "%s.%s.%s" with 2 partitions`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:      "provision with invalid partition key - errors",
+			c:         getNewChannelWithArgs(channelName, map[string]interface{}{"PartitionKey": "Subject"}),
+			wantError: `invalid PartitionKey "Subject", it must be the name of a CloudEvents attribute`,
+		},
+		{
+			name:          "provision with topic deletion policy",
+			c:             getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Retain", "TopicRetentionDays": 7}),
+			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantTopicDetail: &sarama.TopicDetail{
+				ReplicationFactor: 1,
+				NumPartitions:     1,
+			},
+			wantEvent: fmt.Sprintf(`Normal TopicCreated Created topic "%s.%s.%s" with 1 partitions`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:      "provision with invalid topic deletion policy - errors",
+			c:         getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Archive"}),
+			wantError: `invalid TopicDeletionPolicy "Archive", it must be Delete, Retain or Orphan`,
+		},
+		{
+			name:      "provision with retain policy without retention - errors",
+			c:         getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Retain"}),
+			wantError: "the Retain TopicDeletionPolicy requires a positive TopicRetentionDays",
+		},
+		{
+			name:      "provision with retention without retain policy - errors",
+			c:         getNewChannelWithArgs(channelName, map[string]interface{}{"TopicRetentionDays": 7}),
+			wantError: "TopicRetentionDays is only allowed with the Retain TopicDeletionPolicy",
 		},
 		{
 			name:          "provision but topic already exists - no error",
@@ -316,13 +366,16 @@ func TestProvisionChannel(t *testing.T) {
 			},
 			mockError: fmt.Errorf("unknown sarama error"),
 			wantError: "unknown sarama error",
+			wantEvent: fmt.Sprintf(`Warning TopicCreateFailed Failed to create topic "%s.%s.%s": unknown sarama error`, topicPrefix, testNS, channelName),
 		}}
 
 	for _, tc := range provisionTestCases {
 		t.Logf("running test %s", tc.name)
 		logger := provisioners.NewProvisionerLoggerFromConfig(provisioners.NewLoggingConfig())
+		recorder := record.NewFakeRecorder(1)
 		r := &reconciler{
-			logger: logger.Desugar(),
+			recorder: recorder,
+			logger:   logger.Desugar(),
 		}
 		kafkaClusterAdmin := &mockClusterAdmin{
 			mockCreateTopicFunc: func(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
@@ -339,6 +392,9 @@ func TestProvisionChannel(t *testing.T) {
 		if diff := cmp.Diff(tc.wantError, got); diff != "" {
 			t.Errorf("unexpected error (-want, +got) = %v", diff)
 		}
+		if diff := cmp.Diff(tc.wantEvent, recordedEvent(recorder)); diff != "" {
+			t.Errorf("unexpected event (-want, +got) = %v", diff)
+		}
 	}
 }
 
@@ -347,8 +403,10 @@ func TestDeprovisionChannel(t *testing.T) {
 		name          string
 		c             *eventingv1alpha1.Channel
 		wantTopicName string
+		wantRetention string
 		mockError     error
 		wantError     string
+		wantEvent     string
 	}{
 		{
 			name:          "deprovision channel - unknown error",
@@ -356,6 +414,7 @@ func TestDeprovisionChannel(t *testing.T) {
 			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
 			mockError:     fmt.Errorf("unknown sarama error"),
 			wantError:     "unknown sarama error",
+			wantEvent:     fmt.Sprintf(`Warning TopicDeleteFailed Failed to delete topic "%s.%s.%s": unknown sarama error`, topicPrefix, testNS, channelName),
 		},
 		{
 			name:          "deprovision channel - topic already deleted",
@@ -367,22 +426,77 @@ func TestDeprovisionChannel(t *testing.T) {
 			name:          "deprovision channel - success",
 			c:             getNewChannel(channelName, clusterChannelProvisionerName),
 			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantEvent:     fmt.Sprintf(`Normal TopicDeleted Deleted topic "%s.%s.%s"`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:          "deprovision channel - delete policy",
+			c:             getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Delete"}),
+			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantEvent:     fmt.Sprintf(`Normal TopicDeleted Deleted topic "%s.%s.%s"`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:      "deprovision channel - orphan policy",
+			c:         getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Orphan"}),
+			wantEvent: fmt.Sprintf(`Normal TopicOrphaned Kept topic "%s.%s.%s"`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:          "deprovision channel - retain policy",
+			c:             getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Retain", "TopicRetentionDays": 7}),
+			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantRetention: "604800000",
+			wantEvent:     fmt.Sprintf(`Normal TopicRetained Kept topic "%s.%s.%s" with a retention of 7 days`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:          "deprovision channel - retain policy error",
+			c:             getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Retain", "TopicRetentionDays": 7}),
+			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantRetention: "604800000",
+			mockError:     fmt.Errorf("unknown sarama error"),
+			wantError:     "unknown sarama error",
+			wantEvent:     fmt.Sprintf(`Warning TopicRetainFailed Failed to set the retention of topic "%s.%s.%s": unknown sarama error`, topicPrefix, testNS, channelName),
+		},
+		{
+			name:          "deprovision channel - invalid arguments delete the topic",
+			c:             getNewChannelWithArgs(channelName, map[string]interface{}{"TopicDeletionPolicy": "Archive"}),
+			wantTopicName: fmt.Sprintf("%s.%s.%s", topicPrefix, testNS, channelName),
+			wantEvent:     fmt.Sprintf(`Normal TopicDeleted Deleted topic "%s.%s.%s"`, topicPrefix, testNS, channelName),
 		}}
 
 	for _, tc := range deprovisionTestCases {
 		t.Logf("running test %s", tc.name)
 		logger := provisioners.NewProvisionerLoggerFromConfig(provisioners.NewLoggingConfig())
+		recorder := record.NewFakeRecorder(1)
 		r := &reconciler{
-			logger: logger.Desugar()}
+			recorder: recorder,
+			logger:   logger.Desugar(),
+		}
+		var deleted, altered bool
 		kafkaClusterAdmin := &mockClusterAdmin{
 			mockDeleteTopicFunc: func(topic string) error {
+				deleted = true
 				if topic != tc.wantTopicName {
 					t.Errorf("expected topic name: %+v got: %+v", tc.wantTopicName, topic)
 				}
 				return tc.mockError
+			},
+			mockAlterConfigFunc: func(resourceType sarama.ConfigResourceType, name string, entries map[string]*string, validateOnly bool) error {
+				altered = true
+				if resourceType != sarama.TopicResource || name != tc.wantTopicName {
+					t.Errorf("expected topic name: %+v got: %v %+v", tc.wantTopicName, resourceType, name)
+				}
+				if retention := entries["retention.ms"]; retention == nil || *retention != tc.wantRetention {
+					t.Errorf("expected retention.ms %q, got %v", tc.wantRetention, entries)
+				}
+				return tc.mockError
 			}}
 
 		err := r.deprovisionChannel(tc.c, kafkaClusterAdmin)
+		if wantDeleted := tc.wantTopicName != "" && tc.wantRetention == ""; deleted != wantDeleted {
+			t.Errorf("expected topic deleted: %v got: %v", wantDeleted, deleted)
+		}
+		if wantAltered := tc.wantRetention != ""; altered != wantAltered {
+			t.Errorf("expected topic retention set: %v got: %v", wantAltered, altered)
+		}
 		var got string
 		if err != nil {
 			got = err.Error()
@@ -390,6 +504,73 @@ func TestDeprovisionChannel(t *testing.T) {
 		if diff := cmp.Diff(tc.wantError, got); diff != "" {
 			t.Errorf("unexpected error (-want, +got) = %v", diff)
 		}
+		if diff := cmp.Diff(tc.wantEvent, recordedEvent(recorder)); diff != "" {
+			t.Errorf("unexpected event (-want, +got) = %v", diff)
+		}
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	secrets := map[string]*corev1.Secret{
+		"kafka-credentials": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "kafka-credentials"},
+			Data:       map[string][]byte{"user": []byte("kafka"), "password": []byte("secret")},
+		},
+		"invalid-credentials": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "invalid-credentials"},
+			Data:       map[string][]byte{"ca.crt": []byte("invalid")},
+		},
+	}
+	getSecret := func(namespace, name string) (*corev1.Secret, error) {
+		if secret, ok := secrets[name]; ok && namespace == "knative-eventing" {
+			return secret, nil
+		}
+		return nil, fmt.Errorf("secret %s/%s not found", namespace, name)
+	}
+
+	testCases := []struct {
+		name      string
+		secret    string
+		wantCreds *controller.Credentials
+		wantError bool
+	}{{
+		name: "no credentials",
+	}, {
+		name:      "credentials",
+		secret:    "kafka-credentials",
+		wantCreds: &controller.Credentials{User: "kafka", Password: "secret"},
+	}, {
+		name:      "missing secret",
+		secret:    "missing",
+		wantError: true,
+	}, {
+		name:      "invalid credentials",
+		secret:    "invalid-credentials",
+		wantError: true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &reconciler{
+				getSecret: getSecret,
+			}
+			creds, err := r.credentials(&controller.KafkaProvisionerConfig{CredentialsSecret: tc.secret})
+			if (err != nil) != tc.wantError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantCreds, creds); diff != "" {
+				t.Errorf("unexpected credentials (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+// recordedEvent returns the Event recorded by recorder, if any.
+func recordedEvent(recorder *record.FakeRecorder) string {
+	select {
+	case e := <-recorder.Events:
+		return e
+	default:
+		return ""
 	}
 }
 
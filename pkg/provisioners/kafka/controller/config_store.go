@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync/atomic"
+
+	"github.com/knative/pkg/configmap"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConfigStore holds the current KafkaProvisionerConfig. It is replaced when the
+// ProvisionerConfigMapName ConfigMap changes, so the reconcilers load it for every reconciliation.
+type ConfigStore struct {
+	config atomic.Value
+}
+
+// NewConfigStore creates a ConfigStore holding config.
+func NewConfigStore(config *KafkaProvisionerConfig) *ConfigStore {
+	s := &ConfigStore{}
+	s.Store(config)
+	return s
+}
+
+// Load returns the current config. It must not be modified.
+func (s *ConfigStore) Load() *KafkaProvisionerConfig {
+	return s.config.Load().(*KafkaProvisionerConfig)
+}
+
+// Store replaces the current config.
+func (s *ConfigStore) Store(config *KafkaProvisionerConfig) {
+	s.config.Store(config)
+}
+
+// WatchProvisionerConfig makes w call update with the config of the ProvisionerConfigMapName
+// ConfigMap whenever it changes. Invalid configs are logged and not applied, so the previous config
+// stays in use until the ConfigMap is fixed.
+func WatchProvisionerConfig(w configmap.Watcher, logger *zap.Logger, update func(*KafkaProvisionerConfig)) {
+	w.Watch(ProvisionerConfigMapName, func(cm *corev1.ConfigMap) {
+		config, err := NewProvisionerConfigFromMap(cm.Data)
+		if err != nil {
+			logger.Error("Invalid provisioner config, keeping the previous one", zap.Error(err))
+			return
+		}
+		update(config)
+	})
+}
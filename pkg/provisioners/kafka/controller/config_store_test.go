@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/pkg/configmap"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWatchProvisionerConfig(t *testing.T) {
+	store := NewConfigStore(&KafkaProvisionerConfig{Brokers: []string{"old:9092"}})
+	w := &configmap.ManualWatcher{}
+	WatchProvisionerConfig(w, zap.NewNop(), store.Store)
+
+	w.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ProvisionerConfigMapName},
+		Data:       map[string]string{BrokerConfigMapKey: "new:9092"},
+	})
+	want := &KafkaProvisionerConfig{Brokers: []string{"new:9092"}}
+	if diff := cmp.Diff(want, store.Load()); diff != "" {
+		t.Errorf("unexpected config (-want, +got) = %v", diff)
+	}
+
+	// An invalid config keeps the previous one.
+	w.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ProvisionerConfigMapName},
+		Data:       map[string]string{BrokerConfigMapKey: ""},
+	})
+	if diff := cmp.Diff(want, store.Load()); diff != "" {
+		t.Errorf("unexpected config (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CredentialsUserKey and CredentialsPasswordKey are the keys of the SASL/PLAIN user and
+	// password in the credentials Secret.
+	CredentialsUserKey     = "user"
+	CredentialsPasswordKey = "password"
+
+	// The SASL mechanisms of the brokers. Only SASLMechanismPlain is supported by the Kafka client
+	// of the provisioner, the SCRAM mechanisms are rejected rather than failing to connect.
+	SASLMechanismPlain       = "PLAIN"
+	SASLMechanismSCRAMSHA256 = "SCRAM-SHA-256"
+	SASLMechanismSCRAMSHA512 = "SCRAM-SHA-512"
+)
+
+// Security holds the settings of the connections to the Kafka brokers that are not secret, set in
+// the provisioner ConfigMap.
+type Security struct {
+	// TLS makes the connections to the brokers use TLS even if the credentials hold no certificate,
+	// verifying the brokers with the certificate authorities of the system.
+	TLS bool
+	// SASLMechanism is the SASL mechanism of the brokers, SASLMechanismPlain if it is empty.
+	SASLMechanism string
+	// DisableSASLHandshake skips the Kafka SASL handshake, for the SASL proxies that are not Kafka
+	// brokers.
+	DisableSASLHandshake bool
+}
+
+// Validate returns an error if the settings are not supported.
+func (s Security) Validate() error {
+	switch s.SASLMechanism {
+	case "", SASLMechanismPlain:
+		return nil
+	case SASLMechanismSCRAMSHA256, SASLMechanismSCRAMSHA512:
+		return fmt.Errorf("SASL mechanism %s is not supported by the Kafka client, only %s is", s.SASLMechanism, SASLMechanismPlain)
+	}
+	return fmt.Errorf("unknown SASL mechanism %q", s.SASLMechanism)
+}
+
+// Credentials authenticate the clients of the provisioner to the Kafka brokers.
+type Credentials struct {
+	// User and Password authenticate with SASL, if User is not empty.
+	User     string
+	Password string
+	// CACert holds the PEM encoded certificate authorities verifying the brokers. The connections
+	// to the brokers use TLS when it, or a client certificate, is set.
+	CACert []byte
+	// ClientCert and ClientKey are the PEM encoded client certificate presented to the brokers.
+	ClientCert []byte
+	ClientKey  []byte
+
+	// Security holds the settings of the connections of the provisioner ConfigMap.
+	Security
+}
+
+// NewCredentials returns the credentials of the brokers of config: those in secret, which is nil if
+// config names no credentials Secret, with the security settings of config. It returns nil if the
+// connections to the brokers need neither credentials nor settings.
+func NewCredentials(config *KafkaProvisionerConfig, secret *corev1.Secret) (*Credentials, error) {
+	creds := &Credentials{}
+	if secret != nil {
+		var err error
+		if creds, err = CredentialsFromSecret(secret); err != nil {
+			return nil, err
+		}
+	} else if config.Security == (Security{}) {
+		return nil, nil
+	}
+	creds.Security = config.Security
+	if creds.SASLMechanism != "" && creds.User == "" {
+		return nil, fmt.Errorf("SASL mechanism %s requires the %s and %s of the credentials secret", creds.SASLMechanism, CredentialsUserKey, CredentialsPasswordKey)
+	}
+	return creds, nil
+}
+
+// CredentialsFromSecret reads the Credentials in secret: the SASL/PLAIN credentials in its user
+// and password keys, the certificate authorities of the brokers in ca.crt, and the client
+// certificate in tls.crt and tls.key. All are optional.
+func CredentialsFromSecret(secret *corev1.Secret) (*Credentials, error) {
+	creds := &Credentials{
+		User:       strings.TrimSpace(string(secret.Data[CredentialsUserKey])),
+		Password:   strings.TrimSpace(string(secret.Data[CredentialsPasswordKey])),
+		CACert:     secret.Data[corev1.ServiceAccountRootCAKey],
+		ClientCert: secret.Data[corev1.TLSCertKey],
+		ClientKey:  secret.Data[corev1.TLSPrivateKeyKey],
+	}
+	// Invalid credentials are rejected here, rather than when the clients are rebuilt.
+	if _, err := creds.tlsConfig(); err != nil {
+		return nil, fmt.Errorf("invalid credentials in secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	return creds, nil
+}
+
+// Apply configures conf to authenticate with the credentials. It leaves conf unchanged if c is
+// nil.
+func (c *Credentials) Apply(conf *sarama.Config) error {
+	if c == nil {
+		return nil
+	}
+	if err := c.Security.Validate(); err != nil {
+		return err
+	}
+	if c.User != "" {
+		conf.Net.SASL.Enable = true
+		conf.Net.SASL.Handshake = !c.DisableSASLHandshake
+		conf.Net.SASL.User = c.User
+		conf.Net.SASL.Password = c.Password
+	}
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	if tlsConfig == nil && c.TLS {
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig != nil {
+		conf.Net.TLS.Enable = true
+		conf.Net.TLS.Config = tlsConfig
+	}
+	return nil
+}
+
+// tlsConfig returns the TLS config of the connections to the brokers, or nil if they do not use
+// TLS.
+func (c *Credentials) tlsConfig() (*tls.Config, error) {
+	if len(c.CACert) == 0 && len(c.ClientCert) == 0 && len(c.ClientKey) == 0 {
+		return nil, nil
+	}
+	config := &tls.Config{}
+	if len(c.CACert) > 0 {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(c.CACert) {
+			return nil, fmt.Errorf("no valid certificate authority in %s", corev1.ServiceAccountRootCAKey)
+		}
+	}
+	if len(c.ClientCert) > 0 || len(c.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
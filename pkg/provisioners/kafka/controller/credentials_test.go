@@ -0,0 +1,239 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// selfSignedCertificate returns a PEM encoded self-signed certificate and its key.
+func selfSignedCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kafka"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unable to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unable to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCredentials(t *testing.T) {
+	cert, key := selfSignedCertificate(t)
+	testCases := map[string]struct {
+		data      map[string][]byte
+		err       string
+		wantSASL  bool
+		wantTLS   bool
+		wantCerts int
+	}{
+		"empty": {
+			data: map[string][]byte{},
+		},
+		"sasl": {
+			data:     map[string][]byte{"user": []byte("kafka\n"), "password": []byte("secret")},
+			wantSASL: true,
+		},
+		"certificate authority": {
+			data:    map[string][]byte{"ca.crt": cert},
+			wantTLS: true,
+		},
+		"client certificate": {
+			data:      map[string][]byte{"ca.crt": cert, "tls.crt": cert, "tls.key": key, "user": []byte("kafka")},
+			wantSASL:  true,
+			wantTLS:   true,
+			wantCerts: 1,
+		},
+		"invalid certificate authority": {
+			data: map[string][]byte{"ca.crt": []byte("invalid")},
+			err:  "no valid certificate authority in ca.crt",
+		},
+		"client certificate without key": {
+			data: map[string][]byte{"tls.crt": cert},
+			err:  "invalid client certificate",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "kafka-credentials"},
+				Data:       tc.data,
+			}
+			creds, err := CredentialsFromSecret(secret)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("Expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			conf := sarama.NewConfig()
+			if err := creds.Apply(conf); err != nil {
+				t.Fatalf("Unexpected error applying the credentials: %v", err)
+			}
+			if conf.Net.SASL.Enable != tc.wantSASL {
+				t.Errorf("Unexpected SASL. Expected %v. Actual %v", tc.wantSASL, conf.Net.SASL.Enable)
+			}
+			if tc.wantSASL && (conf.Net.SASL.User != "kafka" || conf.Net.SASL.Password != string(tc.data["password"])) {
+				t.Errorf("Unexpected SASL credentials: %q, %q", conf.Net.SASL.User, conf.Net.SASL.Password)
+			}
+			if conf.Net.TLS.Enable != tc.wantTLS {
+				t.Errorf("Unexpected TLS. Expected %v. Actual %v", tc.wantTLS, conf.Net.TLS.Enable)
+			}
+			if tc.wantTLS && len(conf.Net.TLS.Config.Certificates) != tc.wantCerts {
+				t.Errorf("Unexpected client certificates. Expected %d. Actual %d", tc.wantCerts, len(conf.Net.TLS.Config.Certificates))
+			}
+		})
+	}
+}
+
+func TestApplyNilCredentials(t *testing.T) {
+	var creds *Credentials
+	conf := sarama.NewConfig()
+	if err := creds.Apply(conf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conf.Net.SASL.Enable || conf.Net.TLS.Enable {
+		t.Errorf("Expected the config to be unchanged")
+	}
+}
+
+func TestNewCredentials(t *testing.T) {
+	cert, _ := selfSignedCertificate(t)
+	testCases := map[string]struct {
+		security        Security
+		data            map[string][]byte
+		err             string
+		wantNil         bool
+		wantSASL        bool
+		wantHandshake   bool
+		wantTLS         bool
+		wantCustomRoots bool
+	}{
+		"nothing": {
+			wantNil: true,
+		},
+		"tls with the system certificate authorities": {
+			security: Security{TLS: true},
+			wantTLS:  true,
+		},
+		"tls with a certificate authority": {
+			security:        Security{TLS: true},
+			data:            map[string][]byte{"ca.crt": cert},
+			wantTLS:         true,
+			wantCustomRoots: true,
+		},
+		"sasl plain over tls": {
+			security:      Security{TLS: true, SASLMechanism: SASLMechanismPlain},
+			data:          map[string][]byte{"user": []byte("kafka"), "password": []byte("secret")},
+			wantSASL:      true,
+			wantHandshake: true,
+			wantTLS:       true,
+		},
+		"sasl without handshake": {
+			security: Security{DisableSASLHandshake: true},
+			data:     map[string][]byte{"user": []byte("kafka"), "password": []byte("secret")},
+			wantSASL: true,
+		},
+		"sasl mechanism without user": {
+			security: Security{SASLMechanism: SASLMechanismPlain},
+			data:     map[string][]byte{},
+			err:      "SASL mechanism PLAIN requires the user and password of the credentials secret",
+		},
+		"sasl mechanism without secret": {
+			security: Security{SASLMechanism: SASLMechanismPlain},
+			err:      "SASL mechanism PLAIN requires the user and password of the credentials secret",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var secret *corev1.Secret
+			if tc.data != nil {
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "kafka-credentials"},
+					Data:       tc.data,
+				}
+			}
+			creds, err := NewCredentials(&KafkaProvisionerConfig{Security: tc.security}, secret)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (creds == nil) != tc.wantNil {
+				t.Fatalf("Unexpected credentials: %+v", creds)
+			}
+
+			conf := sarama.NewConfig()
+			if err := creds.Apply(conf); err != nil {
+				t.Fatalf("Unexpected error applying the credentials: %v", err)
+			}
+			if conf.Net.SASL.Enable != tc.wantSASL {
+				t.Errorf("Unexpected SASL. Expected %v. Actual %v", tc.wantSASL, conf.Net.SASL.Enable)
+			}
+			if tc.wantSASL && conf.Net.SASL.Handshake != tc.wantHandshake {
+				t.Errorf("Unexpected SASL handshake. Expected %v. Actual %v", tc.wantHandshake, conf.Net.SASL.Handshake)
+			}
+			if conf.Net.TLS.Enable != tc.wantTLS {
+				t.Errorf("Unexpected TLS. Expected %v. Actual %v", tc.wantTLS, conf.Net.TLS.Enable)
+			}
+			if tc.wantTLS && (conf.Net.TLS.Config.RootCAs != nil) != tc.wantCustomRoots {
+				t.Errorf("Unexpected certificate authorities. Expected custom %v. Actual %v", tc.wantCustomRoots, conf.Net.TLS.Config.RootCAs)
+			}
+		})
+	}
+}
+
+func TestApplyUnsupportedSASLMechanism(t *testing.T) {
+	creds := &Credentials{User: "kafka", Password: "secret", Security: Security{SASLMechanism: SASLMechanismSCRAMSHA256}}
+	if err := creds.Apply(sarama.NewConfig()); err == nil {
+		t.Errorf("Expected SCRAM to be rejected")
+	}
+}
@@ -29,6 +29,8 @@ import (
 
 	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	util "github.com/knative/eventing/pkg/provisioners"
 )
 
 const (
@@ -45,21 +47,21 @@ type reconciler struct {
 	client   client.Client
 	recorder record.EventRecorder
 	logger   *zap.Logger
-	config   *KafkaProvisionerConfig
+	configs  *ConfigStore
 }
 
 // Verify the struct implements reconcile.Reconciler
 var _ reconcile.Reconciler = &reconciler{}
 
 // ProvideController returns a Provisioner controller.
-func ProvideController(mgr manager.Manager, config *KafkaProvisionerConfig, logger *zap.Logger) (controller.Controller, error) {
+func ProvideController(mgr manager.Manager, configs *ConfigStore, logger *zap.Logger) (controller.Controller, error) {
 	// Setup a new controller to Reconcile Provisioners.
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: &reconciler{
+		Reconciler: metrics.InstrumentReconciler(controllerAgentName, &reconciler{
 			recorder: mgr.GetRecorder(controllerAgentName),
 			logger:   logger,
-			config:   config,
-		},
+			configs:  configs,
+		}),
 	})
 	if err != nil {
 		return nil, err
@@ -77,6 +79,12 @@ func ProvideController(mgr manager.Manager, config *KafkaProvisionerConfig, logg
 		return nil, err
 	}
 
+	// Watch the NetworkPolicy of the dispatcher and its config.
+	if err := util.WatchDispatcherNetworkPolicy(c, Name); err != nil {
+		logger.Error("unable to watch NetworkPolicies.", zap.Error(err))
+		return nil, err
+	}
+
 	return c, nil
 }
 
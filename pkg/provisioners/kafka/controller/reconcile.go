@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -34,13 +35,38 @@ import (
 const (
 	// Name is the name of the kafka ClusterChannelProvisioner.
 	Name = "kafka"
+
+	// ArgumentsSchema is the JSON Schema of the arguments of kafka Channels, published in the
+	// status of the ClusterChannelProvisioner so that the webhook rejects invalid arguments.
+	ArgumentsSchema = `{"type":"object",` +
+		`"properties":{` +
+		`"NumPartitions":{"type":"integer","minimum":1,"maximum":2147483647},` +
+		`"PartitionKey":{"type":"string","pattern":"^[a-z0-9]+$"},` +
+		`"TopicDeletionPolicy":{"type":"string","enum":["Delete","Retain","Orphan"]},` +
+		`"TopicRetentionDays":{"type":"integer","minimum":1,"maximum":2147483647}},` +
+		`"additionalProperties":false}`
+
+	// maxEventSize is the default size limit of Kafka messages, message.max.bytes.
+	maxEventSize = 1000012
 )
 
+// Capabilities returns the capabilities of kafka Channels, published in the status of the
+// ClusterChannelProvisioner. Events are delivered in order within each partition.
+func Capabilities() *v1alpha1.ClusterChannelProvisionerCapabilities {
+	size := int64(maxEventSize)
+	return &v1alpha1.ClusterChannelProvisionerCapabilities{
+		OrderedDelivery: true,
+		Persistence:     true,
+		MaxEventSize:    &size,
+		Arguments:       []string{"NumPartitions", "PartitionKey", "TopicDeletionPolicy", "TopicRetentionDays"},
+	}
+}
+
 // Reconcile compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Provisioner resource
 // with the current status of the resource.
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	ctx := context.TODO()
+	ctx := util.WithEventRecorder(context.TODO(), r.recorder)
 	r.logger.Info("reconciling ClusterChannelProvisioner", zap.Any("request", request))
 
 	// Workaround until https://github.com/kubernetes-sigs/controller-runtime/issues/214 is fixed.
@@ -113,6 +139,14 @@ func (r *reconciler) reconcile(ctx context.Context, provisioner *v1alpha1.Cluste
 		r.logger.Warn("ClusterChannelProvisioner's K8s Service is not owned by the ClusterChannelProvisioner", zap.Any("clusterChannelProvisioner", provisioner), zap.Any("service", svc))
 	}
 
+	if _, err := util.CreateDispatcherNetworkPolicy(ctx, r.client, provisioner); err != nil {
+		r.logger.Info("error creating the ClusterProvisioner's NetworkPolicy", zap.Error(err))
+		return err
+	}
+
+	provisioner.Status.ArgumentsSchema = &runtime.RawExtension{Raw: []byte(ArgumentsSchema)}
+	provisioner.Status.Capabilities = Capabilities()
+
 	// Update Status as Ready
 	provisioner.Status.MarkReady()
 
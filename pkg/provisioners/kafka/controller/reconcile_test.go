@@ -141,7 +141,7 @@ func TestAllCases(t *testing.T) {
 			client:   c,
 			recorder: recorder,
 			logger:   logger.Desugar(),
-			config:   getControllerConfig(),
+			configs:  NewConfigStore(getControllerConfig()),
 		}
 		t.Logf("Running test %s", tc.Name)
 		t.Run(tc.Name, tc.Runner(t, r, c))
@@ -165,10 +165,18 @@ func getNewClusterChannelProvisioner(name string, reconcileKind string) *eventin
 
 func GetNewChannelClusterChannelProvisionerReady(name string) *eventingv1alpha1.ClusterChannelProvisioner {
 	c := GetNewChannelClusterChannelProvisioner(name)
+	maxEventSize := int64(1000012)
 	c.Status = eventingv1alpha1.ClusterChannelProvisionerStatus{
 		Conditions: []duckv1alpha1.Condition{
 			ClusterChannelProvisionerConditionReady,
 		},
+		ArgumentsSchema: &runtime.RawExtension{Raw: []byte(ArgumentsSchema)},
+		Capabilities: &eventingv1alpha1.ClusterChannelProvisionerCapabilities{
+			OrderedDelivery: true,
+			Persistence:     true,
+			MaxEventSize:    &maxEventSize,
+			Arguments:       []string{"NumPartitions", "PartitionKey", "TopicDeletionPolicy", "TopicRetentionDays"},
+		},
 	}
 	return c
 }
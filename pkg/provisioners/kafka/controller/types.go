@@ -1,5 +1,27 @@
 package controller
 
+import "time"
+
 type KafkaProvisionerConfig struct {
 	Brokers []string
+	// DedupWindow is how long dispatched events are remembered to suppress their redelivery. Zero
+	// disables deduplication.
+	DedupWindow time.Duration
+	// DedupSize is the number of dispatched events remembered to suppress their redelivery.
+	DedupSize int
+	// OffsetCommitInterval is how often the consumers commit the offsets of the dispatched events.
+	// Zero keeps the default of the Kafka client, one second.
+	OffsetCommitInterval time.Duration
+	// InitialOffset is where the consumer group of a new subscription starts consuming the topic of
+	// its channel, InitialOffsetNewest or InitialOffsetOldest. Empty means InitialOffsetNewest.
+	InitialOffset string
+	// RebalanceTimeout is how long the consumers wait, when the partitions are rebalanced, for the
+	// events being dispatched to be delivered and their offsets committed before releasing their
+	// partitions. Zero keeps the default of the Kafka client, 100ms.
+	RebalanceTimeout time.Duration
+	// CredentialsSecret is the name of the Secret, in the namespace of the provisioner, holding
+	// the credentials authenticating to the brokers. Empty if the brokers need no credentials.
+	CredentialsSecret string
+	// Security holds the settings of the connections to the brokers that are not secret.
+	Security Security
 }
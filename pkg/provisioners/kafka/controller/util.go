@@ -2,14 +2,52 @@ package controller
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/knative/pkg/configmap"
 )
 
 const (
+	// ProvisionerConfigMapName is the name of the ConfigMap in the knative-eventing namespace
+	// configuring the provisioner, which is mounted at /etc/config-provisioner. Its changes are
+	// applied without restarting the controller and the dispatcher, see WatchProvisionerConfig.
+	ProvisionerConfigMapName = "kafka-channel-controller-config"
+
 	BrokerConfigMapKey    = "bootstrap_servers"
 	KafkaChannelSeparator = "."
+
+	// DedupWindowConfigMapKey enables the deduplication of redelivered events. Its value is the
+	// duration events are remembered for, e.g. 10m.
+	DedupWindowConfigMapKey = "dedup_window"
+	// DedupSizeConfigMapKey is the number of events remembered for deduplication.
+	DedupSizeConfigMapKey = "dedup_size"
+	// CredentialsSecretConfigMapKey is the name of the Secret holding the credentials of the
+	// brokers. See CredentialsFromSecret for its keys.
+	CredentialsSecretConfigMapKey = "credentials_secret"
+	// TLSConfigMapKey makes the connections to the brokers use TLS when it is "true", even if the
+	// credentials Secret holds no certificate.
+	TLSConfigMapKey = "tls_enabled"
+	// SASLMechanismConfigMapKey is the SASL mechanism of the brokers, PLAIN by default.
+	SASLMechanismConfigMapKey = "sasl_mechanism"
+	// SASLHandshakeConfigMapKey skips the Kafka SASL handshake when it is "false", for the SASL
+	// proxies that are not Kafka brokers.
+	SASLHandshakeConfigMapKey = "sasl_handshake"
+	// OffsetCommitIntervalConfigMapKey is how often the consumers commit their offsets, e.g. 5s.
+	OffsetCommitIntervalConfigMapKey = "offset_commit_interval"
+	// InitialOffsetConfigMapKey is where new subscriptions start consuming their channel, "newest"
+	// or "oldest".
+	InitialOffsetConfigMapKey = "initial_offset"
+	// RebalanceTimeoutConfigMapKey is how long the consumers wait for the events being dispatched
+	// before releasing their partitions to a rebalance, e.g. 10s.
+	RebalanceTimeoutConfigMapKey = "rebalance_timeout"
+
+	// InitialOffsetNewest starts new subscriptions with the events sent after they are created.
+	InitialOffsetNewest = "newest"
+	// InitialOffsetOldest starts new subscriptions with the oldest events retained by the topic of
+	// their channel.
+	InitialOffsetOldest = "oldest"
 )
 
 // GetProvisionerConfig returns the details of the associated ClusterChannelProvisioner object
@@ -18,23 +56,88 @@ func GetProvisionerConfig(path string) (*KafkaProvisionerConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error loading provisioner configuration: %s", err)
 	}
+	return NewProvisionerConfigFromMap(configMap)
+}
 
+// NewProvisionerConfigFromMap creates a KafkaProvisionerConfig from the data of the
+// ProvisionerConfigMapName ConfigMap.
+func NewProvisionerConfigFromMap(configMap map[string]string) (*KafkaProvisionerConfig, error) {
 	if len(configMap) == 0 {
 		return nil, fmt.Errorf("missing provisioner configuration")
 	}
 
 	config := &KafkaProvisionerConfig{}
 
-	if brokers, ok := configMap[BrokerConfigMapKey]; ok {
-		bootstrapServers := strings.Split(brokers, ",")
-		for _, s := range bootstrapServers {
-			if len(s) == 0 {
-				return nil, fmt.Errorf("empty %s value in provisioner configuration", BrokerConfigMapKey)
-			}
+	brokers, ok := configMap[BrokerConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("missing key %s in provisioner configuration", BrokerConfigMapKey)
+	}
+	bootstrapServers := strings.Split(brokers, ",")
+	for _, s := range bootstrapServers {
+		if len(s) == 0 {
+			return nil, fmt.Errorf("empty %s value in provisioner configuration", BrokerConfigMapKey)
 		}
-		config.Brokers = bootstrapServers
-		return config, nil
 	}
+	config.Brokers = bootstrapServers
 
-	return nil, fmt.Errorf("missing key %s in provisioner configuration", BrokerConfigMapKey)
+	if window, ok := configMap[DedupWindowConfigMapKey]; ok {
+		d, err := time.ParseDuration(strings.TrimSpace(window))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", DedupWindowConfigMapKey, window)
+		}
+		config.DedupWindow = d
+	}
+	if size, ok := configMap[DedupSizeConfigMapKey]; ok {
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", DedupSizeConfigMapKey, size)
+		}
+		config.DedupSize = n
+	}
+	if interval, ok := configMap[OffsetCommitIntervalConfigMapKey]; ok {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", OffsetCommitIntervalConfigMapKey, interval)
+		}
+		config.OffsetCommitInterval = d
+	}
+	if initial, ok := configMap[InitialOffsetConfigMapKey]; ok {
+		switch v := strings.ToLower(strings.TrimSpace(initial)); v {
+		case InitialOffsetNewest, InitialOffsetOldest:
+			config.InitialOffset = v
+		default:
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", InitialOffsetConfigMapKey, initial)
+		}
+	}
+	if timeout, ok := configMap[RebalanceTimeoutConfigMapKey]; ok {
+		d, err := time.ParseDuration(strings.TrimSpace(timeout))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", RebalanceTimeoutConfigMapKey, timeout)
+		}
+		config.RebalanceTimeout = d
+	}
+	if secret, ok := configMap[CredentialsSecretConfigMapKey]; ok {
+		config.CredentialsSecret = strings.TrimSpace(secret)
+	}
+	if v, ok := configMap[TLSConfigMapKey]; ok {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", TLSConfigMapKey, v)
+		}
+		config.Security.TLS = b
+	}
+	if v, ok := configMap[SASLMechanismConfigMapKey]; ok {
+		config.Security.SASLMechanism = strings.ToUpper(strings.TrimSpace(v))
+	}
+	if v, ok := configMap[SASLHandshakeConfigMapKey]; ok {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q in provisioner configuration", SASLHandshakeConfigMapKey, v)
+		}
+		config.Security.DisableSASLHandshake = !b
+	}
+	if err := config.Security.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s in provisioner configuration: %v", SASLMechanismConfigMapKey, err)
+	}
+	return config, nil
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -52,6 +53,89 @@ func TestGetProvisionerConfigBrokers(t *testing.T) {
 				Brokers: []string{"kafkabroker1.kafka:9092", "kafkabroker2.kafka:9092"},
 			},
 		},
+		{
+			name: "dedup window",
+			data: map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "dedup_window": "10m", "dedup_size": "5000"},
+			expected: &KafkaProvisionerConfig{
+				Brokers:     []string{"kafkabroker.kafka:9092"},
+				DedupWindow: 10 * time.Minute,
+				DedupSize:   5000,
+			},
+		},
+		{
+			name: "offsets",
+			data: map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "offset_commit_interval": "5s", "initial_offset": "Oldest"},
+			expected: &KafkaProvisionerConfig{
+				Brokers:              []string{"kafkabroker.kafka:9092"},
+				OffsetCommitInterval: 5 * time.Second,
+				InitialOffset:        "oldest",
+			},
+		},
+		{
+			name: "rebalance timeout",
+			data: map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "rebalance_timeout": "10s"},
+			expected: &KafkaProvisionerConfig{
+				Brokers:          []string{"kafkabroker.kafka:9092"},
+				RebalanceTimeout: 10 * time.Second,
+			},
+		},
+		{
+			name: "credentials secret",
+			data: map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "credentials_secret": "kafka-credentials"},
+			expected: &KafkaProvisionerConfig{
+				Brokers:           []string{"kafkabroker.kafka:9092"},
+				CredentialsSecret: "kafka-credentials",
+			},
+		},
+		{
+			name: "security",
+			data: map[string]string{"bootstrap_servers": "kafkabroker.kafka:9093", "credentials_secret": "kafka-credentials", "tls_enabled": "true", "sasl_mechanism": "plain", "sasl_handshake": "false"},
+			expected: &KafkaProvisionerConfig{
+				Brokers:           []string{"kafkabroker.kafka:9093"},
+				CredentialsSecret: "kafka-credentials",
+				Security:          Security{TLS: true, SASLMechanism: "PLAIN", DisableSASLHandshake: true},
+			},
+		},
+		{
+			name:     "invalid tls_enabled",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "tls_enabled": "yes please"},
+			getError: `invalid tls_enabled value "yes please" in provisioner configuration`,
+		},
+		{
+			name:     "unsupported sasl_mechanism",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "sasl_mechanism": "SCRAM-SHA-512"},
+			getError: "invalid sasl_mechanism in provisioner configuration: SASL mechanism SCRAM-SHA-512 is not supported by the Kafka client, only PLAIN is",
+		},
+		{
+			name:     "unknown sasl_mechanism",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "sasl_mechanism": "GSSAPI"},
+			getError: `invalid sasl_mechanism in provisioner configuration: unknown SASL mechanism "GSSAPI"`,
+		},
+		{
+			name:     "invalid dedup window",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "dedup_window": "10"},
+			getError: `invalid dedup_window value "10" in provisioner configuration`,
+		},
+		{
+			name:     "invalid offset commit interval",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "offset_commit_interval": "0s"},
+			getError: `invalid offset_commit_interval value "0s" in provisioner configuration`,
+		},
+		{
+			name:     "invalid initial offset",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "initial_offset": "earliest"},
+			getError: `invalid initial_offset value "earliest" in provisioner configuration`,
+		},
+		{
+			name:     "invalid rebalance timeout",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "rebalance_timeout": "soon"},
+			getError: `invalid rebalance_timeout value "soon" in provisioner configuration`,
+		},
+		{
+			name:     "invalid dedup size",
+			data:     map[string]string{"bootstrap_servers": "kafkabroker.kafka:9092", "dedup_size": "-1"},
+			getError: `invalid dedup_size value "-1" in provisioner configuration`,
+		},
 	}
 
 	for _, tc := range testCases {
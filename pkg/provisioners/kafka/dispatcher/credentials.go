@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/knative/eventing/pkg/provisioners/kafka/controller"
+)
+
+// NewCredentialsWatcher returns a Runnable watching the credentials Secret namespace/name, and
+// calling update with the credentials it holds whenever it changes.
+func NewCredentialsWatcher(logger *zap.Logger, kc kubernetes.Interface, namespace, name string, update func(*controller.Credentials) error) manager.Runnable {
+	// Only the credentials Secret is watched, rather than all the Secrets of the namespace.
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(kc, 0,
+		kubeinformers.WithNamespace(namespace),
+		kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	informer := factory.Core().V1().Secrets().Informer()
+
+	updated := func(obj interface{}) {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		creds, err := controller.CredentialsFromSecret(secret)
+		if err != nil {
+			logger.Error("Invalid kafka credentials", zap.Error(err))
+			return
+		}
+		if err := update(creds); err != nil {
+			logger.Error("Unable to update the kafka credentials", zap.Error(err))
+		}
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: updated,
+		UpdateFunc: func(_, obj interface{}) {
+			updated(obj)
+		},
+	})
+
+	return manager.RunnableFunc(func(stopCh <-chan struct{}) error {
+		informer.Run(stopCh)
+		return nil
+	})
+}
@@ -16,10 +16,13 @@ limitations under the License.
 package dispatcher
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Shopify/sarama"
 	cluster "github.com/bsm/sarama-cluster"
@@ -28,42 +31,290 @@ import (
 
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/dedup"
 	"github.com/knative/eventing/pkg/provisioners/kafka/controller"
+	"github.com/knative/eventing/pkg/provisioners/schema"
 	topicUtils "github.com/knative/eventing/pkg/provisioners/utils"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
 )
 
+const (
+	// defaultNackRedeliveries and defaultNackBackoff are the redeliveries of the messages that
+	// subscribers explicitly nack, unless WithNackRedelivery sets them.
+	defaultNackRedeliveries = 5
+	defaultNackBackoff      = time.Second
+
+	// kafkaCloudEventsPrefix prefixes the record headers holding the CloudEvents attributes, per the
+	// CloudEvents Kafka binding, rather than the ce- prefix of the HTTP binding.
+	kafkaCloudEventsPrefix = "ce_"
+	httpCloudEventsPrefix  = "ce-"
+
+	// partitionKeyExtension is the CloudEvents extension the Kafka binding maps to the key of the
+	// records.
+	partitionKeyExtension = "partitionkey"
+)
+
 type KafkaDispatcher struct {
 	config     atomic.Value
 	updateLock sync.Mutex
+	// partitionKeys holds a map[provisioners.ChannelReference]string from the channels whose
+	// events are keyed to the CloudEvents attribute keying them.
+	partitionKeys atomic.Value
+	// subscriberDefaults holds a map[subscription]provisioners.DispatchDefaults from the
+	// subscriptions to the filter, the validator, the transform and the authenticator of the
+	// deliveries to them, the validator and the authenticator created by schemaResolver and
+	// authResolver.
+	subscriberDefaults atomic.Value
+	schemaResolver     *schema.Resolver
+	authResolver       *auth.Resolver
 
 	receiver   *provisioners.MessageReceiver
 	dispatcher *provisioners.MessageDispatcher
 
+	// producerLock guards kafkaClient and kafkaAsyncProducer, which are replaced when the
+	// credentials are rotated.
+	producerLock sync.RWMutex
+	// kafkaClient is the client of the producer, used to check that the brokers are reachable.
+	kafkaClient        sarama.Client
 	kafkaAsyncProducer sarama.AsyncProducer
 	kafkaConsumers     map[provisioners.ChannelReference]map[subscription]KafkaConsumer
 	kafkaCluster       KafkaCluster
 
+	// brokers are the bootstrap servers of the Kafka cluster.
+	brokers []string
+	// credentials authenticate the clients to the brokers. They are nil if the brokers need none.
+	credentials *controller.Credentials
+	// offsets configures how the consumers start consuming and commit their offsets.
+	offsets offsetConfig
+	// connect creates the clients of the given brokers authenticated with the given credentials,
+	// and the consumers of the cluster with the given offset configuration.
+	connect func([]string, *controller.Credentials, offsetConfig) (sarama.Client, sarama.AsyncProducer, KafkaCluster, error)
+
+	// dedup holds the *dedup.Window suppressing the redelivery of events to subscriptions. It is
+	// nil when deduplication is disabled. It is replaced when the provisioner config changes, with
+	// dedupWindow and dedupSize guarded by updateLock.
+	dedup       atomic.Value
+	dedupWindow time.Duration
+	dedupSize   int
+
+	// nackRedeliveries is the number of times the messages that subscribers explicitly nack are
+	// redelivered, waiting nackBackoff before the first redelivery and doubling it for the next
+	// ones. The partition of the message is not consumed further in the meantime.
+	nackRedeliveries int
+	nackBackoff      time.Duration
+
+	// receiverOptions and dispatcherOptions configure the MessageReceiver writing events to Kafka
+	// and the MessageDispatcher sending them to subscribers.
+	receiverOptions   []provisioners.ReceiverOption
+	dispatcherOptions []provisioners.DispatcherOption
+
 	logger *zap.Logger
 }
 
+// Option configures optional behavior of a KafkaDispatcher.
+type Option func(*KafkaDispatcher)
+
+// WithDedupWindow makes the dispatcher skip the events already dispatched to a subscription
+// within window, such as the events redelivered after a consumer group rebalance. At most size
+// events are remembered.
+func WithDedupWindow(window time.Duration, size int) Option {
+	return func(d *KafkaDispatcher) {
+		d.setDedupWindow(window, size)
+	}
+}
+
+// WithNackRedelivery makes the dispatcher redeliver the messages that subscribers explicitly nack
+// up to redeliveries times, waiting backoff before the first redelivery and doubling it for the
+// next ones, rather than the defaults.
+func WithNackRedelivery(redeliveries int, backoff time.Duration) Option {
+	return func(d *KafkaDispatcher) {
+		d.nackRedeliveries = redeliveries
+		d.nackBackoff = backoff
+	}
+}
+
+// WithSchemaResolver makes the dispatcher validate the events delivered to subscribers that
+// declare a schema, using r to read it. Without it, these events are not validated.
+func WithSchemaResolver(r *schema.Resolver) Option {
+	return func(d *KafkaDispatcher) {
+		d.schemaResolver = r
+	}
+}
+
+// WithAuthResolver makes the dispatcher attach credentials to deliveries to subscribers that
+// declare auth, using r to obtain them. Without it, the deliveries to these subscribers fail.
+func WithAuthResolver(r *auth.Resolver) Option {
+	return func(d *KafkaDispatcher) {
+		d.authResolver = r
+	}
+}
+
+// WithOffsets makes the consumers commit their offsets every commitInterval rather than every
+// second, and the consumer groups of new subscriptions start with the oldest events of their
+// channel when initial is controller.InitialOffsetOldest.
+func WithOffsets(commitInterval time.Duration, initial string) Option {
+	return func(d *KafkaDispatcher) {
+		d.offsets.commitInterval, d.offsets.initial = commitInterval, initial
+	}
+}
+
+// WithRebalanceTimeout makes the consumers wait up to timeout, when the partitions are rebalanced,
+// for the events being dispatched to be delivered and their offsets committed before releasing
+// their partitions, rather than 100ms, so that the replicas that claim them do not deliver these
+// events again.
+func WithRebalanceTimeout(timeout time.Duration) Option {
+	return func(d *KafkaDispatcher) {
+		d.offsets.rebalanceTimeout = timeout
+	}
+}
+
+// offsetConfig configures where the consumer groups of new subscriptions start consuming, how
+// often the consumers commit their offsets, and how long they wait for the events being dispatched
+// before releasing their partitions to a rebalance.
+type offsetConfig struct {
+	commitInterval   time.Duration
+	initial          string
+	rebalanceTimeout time.Duration
+}
+
+// KafkaConsumer is the member of the consumer group of a subscription in this dispatcher. The
+// partitions of the topic of the channel are balanced between the members of the group, one in
+// each replica of the dispatcher, and rebalanced when replicas come and go. Each partition claimed
+// by the member is consumed separately.
 type KafkaConsumer interface {
-	Messages() <-chan *sarama.ConsumerMessage
+	// Partitions returns the partitions claimed by the member, as they are claimed. The messages
+	// of a partition are closed when the partition is released.
+	Partitions() <-chan cluster.PartitionConsumer
+	// Notifications returns the rebalances of the consumer group.
+	Notifications() <-chan *cluster.Notification
 	MarkOffset(msg *sarama.ConsumerMessage, metadata string)
+	HighWaterMarks() map[string]map[int32]int64
 	Close() (err error)
 }
 
+// lagConsumer is a KafkaConsumer that remembers the offsets it marked, to compute its lag, and the
+// outcome of the last rebalance of its group.
+type lagConsumer struct {
+	KafkaConsumer
+
+	mu sync.Mutex
+	// next holds the offset of the next message to dispatch, by topic and partition, for the
+	// partitions claimed by the consumer.
+	next map[string]map[int32]int64
+	// rebalanceErr is set when the last rebalance of the consumer group failed.
+	rebalanceErr error
+
+	// stop is closed to stop dispatching the messages of the consumer. done is closed once no
+	// more partitions are consumed, and partitions waits for the message being dispatched in
+	// each partition, if any, to be dispatched and marked.
+	stop       chan struct{}
+	done       chan struct{}
+	partitions sync.WaitGroup
+}
+
+func newLagConsumer(consumer KafkaConsumer) *lagConsumer {
+	return &lagConsumer{
+		KafkaConsumer: consumer,
+		next:          make(map[string]map[int32]int64),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Close stops dispatching messages, waiting for the messages being dispatched, so that their offsets
+// are committed when the consumer is closed.
+func (c *lagConsumer) Close() error {
+	close(c.stop)
+	<-c.done
+	c.partitions.Wait()
+	return c.KafkaConsumer.Close()
+}
+
+// rebalanced records the outcome of a rebalance of the consumer group. The partitions released by
+// the consumer are not part of its lag anymore, as they are consumed by other replicas.
+func (c *lagConsumer) rebalanced(n *cluster.Notification) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch n.Type {
+	case cluster.RebalanceError:
+		c.rebalanceErr = errors.New("the rebalance of the consumer group failed")
+	case cluster.RebalanceOK:
+		c.rebalanceErr = nil
+		for topic, partitions := range n.Released {
+			for _, partition := range partitions {
+				delete(c.next[topic], partition)
+			}
+		}
+	}
+}
+
+// err returns an error if the last rebalance of the consumer group failed.
+func (c *lagConsumer) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rebalanceErr
+}
+
+func (c *lagConsumer) MarkOffset(msg *sarama.ConsumerMessage, metadata string) {
+	c.KafkaConsumer.MarkOffset(msg, metadata)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	partitions, ok := c.next[msg.Topic]
+	if !ok {
+		partitions = make(map[int32]int64)
+		c.next[msg.Topic] = partitions
+	}
+	partitions[msg.Partition] = msg.Offset + 1
+}
+
+// lag returns the number of messages written to the partitions the consumer dispatched messages
+// of, and not dispatched yet.
+func (c *lagConsumer) lag() int64 {
+	hwms := c.HighWaterMarks()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var lag int64
+	for topic, partitions := range c.next {
+		for partition, next := range partitions {
+			if hwm := hwms[topic][partition]; hwm > next {
+				lag += hwm - next
+			}
+		}
+	}
+	return lag
+}
+
 type KafkaCluster interface {
 	NewConsumer(groupID string, topics []string) (KafkaConsumer, error)
 }
 
 type saramaCluster struct {
 	kafkaBrokers []string
+	credentials  *controller.Credentials
+	offsets      offsetConfig
 }
 
 func (c *saramaCluster) NewConsumer(groupID string, topics []string) (KafkaConsumer, error) {
 	consumerConfig := cluster.NewConfig()
 	consumerConfig.Version = sarama.V1_1_0_0
+	// Consume each claimed partition separately, so that a slow partition does not hold the others.
+	consumerConfig.Group.Mode = cluster.ConsumerModePartitions
+	consumerConfig.Group.Return.Notifications = true
+	if c.offsets.commitInterval > 0 {
+		consumerConfig.Consumer.Offsets.CommitInterval = c.offsets.commitInterval
+	}
+	if c.offsets.initial == controller.InitialOffsetOldest {
+		consumerConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+	if c.offsets.rebalanceTimeout > 0 {
+		// The partitions are released, and their offsets committed, once the consumer dwelled
+		// for the timeout.
+		consumerConfig.Group.Offsets.Synchronization.DwellTime = c.offsets.rebalanceTimeout
+	}
+	if err := c.credentials.Apply(&consumerConfig.Config); err != nil {
+		return nil, err
+	}
 	return cluster.NewConsumer(c.kafkaBrokers, groupID, topics, consumerConfig)
 }
 
@@ -72,6 +323,8 @@ type subscription struct {
 	Name          string
 	SubscriberURI string
 	ReplyURI      string
+	ErrorURI      string
+	Protocol      eventingduck.DeliveryProtocol
 }
 
 // ConfigDiffs diffs the new config with the existing config. If there are no differences, then the
@@ -92,6 +345,25 @@ func (d *KafkaDispatcher) UpdateConfig(config *multichannelfanout.Config) error
 	if diff := d.ConfigDiff(config); diff != "" {
 		d.logger.Info("Updating config (-old +new)", zap.String("diff", diff))
 
+		// The defaults are replaced before the consumers of new subscriptions are started, so that
+		// their first deliveries are filtered, validated, transformed and authenticated.
+		subscriberDefaults := make(map[subscription]provisioners.DispatchDefaults)
+		for _, cc := range config.ChannelConfigs {
+			for _, subSpec := range cc.FanoutConfig.Subscriptions {
+				defaults := provisioners.DispatchDefaults{
+					Filter:                  provisioners.SubscriberFilter(d.logger, subSpec),
+					Validator:               d.schemaResolver.SubscriberValidator(d.logger, subSpec),
+					Transform:               provisioners.SubscriberTransform(d.logger, subSpec),
+					NonConformingDeadLetter: subSpec.DeadLetterURI,
+				}
+				if subSpec.Auth != nil {
+					defaults.Auth = d.authResolver.Authenticator(subSpec.Ref.Namespace, subSpec.Auth)
+				}
+				subscriberDefaults[newSubscription(subSpec)] = defaults
+			}
+		}
+		d.subscriberDefaults.Store(subscriberDefaults)
+
 		newSubs := make(map[subscription]bool)
 
 		// Subscribe to new subscriptions
@@ -101,6 +373,11 @@ func (d *KafkaDispatcher) UpdateConfig(config *multichannelfanout.Config) error
 				Namespace: cc.Namespace,
 			}
 			for _, subSpec := range cc.FanoutConfig.Subscriptions {
+				if subSpec.Paused {
+					// Paused subscriptions have no consumer. Their consumer group keeps its
+					// committed offset, so delivery resumes where it stopped.
+					continue
+				}
 				sub := newSubscription(subSpec)
 				if _, ok := d.kafkaConsumers[channelRef][sub]; ok {
 					// subscribe can be called multiple times for the same subscription,
@@ -127,36 +404,290 @@ func (d *KafkaDispatcher) UpdateConfig(config *multichannelfanout.Config) error
 			}
 		}
 
+		partitionKeys := make(map[provisioners.ChannelReference]string)
+		for _, cc := range config.ChannelConfigs {
+			if cc.PartitionKey != "" {
+				partitionKeys[provisioners.ChannelReference{Namespace: cc.Namespace, Name: cc.Name}] = cc.PartitionKey
+			}
+		}
+		d.partitionKeys.Store(partitionKeys)
+
 		// Update the config so that it can be used for comparison during next sync
 		d.setConfig(config)
 	}
 	return nil
 }
 
-// Run starts the kafka dispatcher's message processing.
+// Run starts the kafka dispatcher's message processing. Once stopCh is closed, the consumers
+// finish dispatching their current message and leave their consumer groups, so that the offsets of
+// the dispatched events are committed and the partitions of a replica removed by a scale down are
+// claimed by the remaining replicas right away, rather than once its session times out.
 func (d *KafkaDispatcher) Start(stopCh <-chan struct{}) error {
 	if d.receiver == nil {
 		return fmt.Errorf("message receiver is not set")
 	}
 
-	if d.kafkaAsyncProducer == nil {
+	if d.producer() == nil {
 		return fmt.Errorf("kafkaAsyncProducer is not set")
 	}
 
-	go func() {
-		for {
-			select {
-			case e := <-d.kafkaAsyncProducer.Errors():
-				d.logger.Warn("Got", zap.Error(e))
-			case s := <-d.kafkaAsyncProducer.Successes():
-				d.logger.Info("Sent", zap.Any("success", s))
-			case <-stopCh:
+	err := d.receiver.Start(stopCh)
+	d.closeConsumers()
+	return err
+}
+
+// closeConsumers closes the consumers of all the subscriptions.
+func (d *KafkaDispatcher) closeConsumers() {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+	for channelRef, subMap := range d.kafkaConsumers {
+		for sub := range subMap {
+			if err := d.unsubscribe(channelRef, sub); err != nil {
+				d.logger.Warn("Unable to close the consumer", zap.Any("subscription", sub), zap.Error(err))
+			}
+		}
+	}
+}
+
+// logProducer logs the results of the messages written by producer, until it is closed.
+func (d *KafkaDispatcher) logProducer(producer sarama.AsyncProducer) {
+	for {
+		select {
+		case e, ok := <-producer.Errors():
+			if !ok {
 				return
 			}
+			d.logger.Warn("Got", zap.Error(e))
+		case s, ok := <-producer.Successes():
+			if !ok {
+				return
+			}
+			d.logger.Info("Sent", zap.Any("success", s))
 		}
-	}()
+	}
+}
+
+// producer returns the current producer. It may be replaced when the credentials are rotated.
+func (d *KafkaDispatcher) producer() sarama.AsyncProducer {
+	d.producerLock.RLock()
+	defer d.producerLock.RUnlock()
+	return d.kafkaAsyncProducer
+}
+
+// produce writes message to Kafka. Rotating the credentials waits for the messages being written,
+// so that they are not written to a closed producer.
+func (d *KafkaDispatcher) produce(message *sarama.ProducerMessage) {
+	d.producerLock.RLock()
+	defer d.producerLock.RUnlock()
+	d.kafkaAsyncProducer.Input() <- message
+}
+
+// UpdateCredentials rebuilds the Kafka clients with creds, if they changed. The events buffered by
+// the previous producer are flushed, and each consumer finishes dispatching its current message and
+// commits its offsets before it is replaced, so no event is lost or redelivered by the rotation. The
+// previous clients are kept if the new ones cannot be created.
+func (d *KafkaDispatcher) UpdateCredentials(creds *controller.Credentials) error {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+
+	if cmp.Equal(d.credentials, creds) {
+		return nil
+	}
+	d.logger.Info("Rotating the Kafka credentials")
+	if err := d.reconnect(d.brokers, creds, d.offsets); err != nil {
+		return fmt.Errorf("unable to connect to kafka with the new credentials: %v", err)
+	}
+	return nil
+}
+
+// UpdateProvisionerConfig applies the changes of the provisioner config: the clients are rebuilt
+// like in UpdateCredentials if the bootstrap servers or the offset configuration changed, and the
+// deduplication window is replaced if it changed. The events remembered by the previous window are
+// forgotten.
+func (d *KafkaDispatcher) UpdateProvisionerConfig(config *controller.KafkaProvisionerConfig) error {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+
+	if d.dedupWindow != config.DedupWindow || d.dedupSize != config.DedupSize {
+		d.logger.Info("Updating the deduplication window", zap.Duration("window", config.DedupWindow), zap.Int("size", config.DedupSize))
+		d.setDedupWindow(config.DedupWindow, config.DedupSize)
+	}
+	offsets := offsetConfig{commitInterval: config.OffsetCommitInterval, initial: config.InitialOffset, rebalanceTimeout: config.RebalanceTimeout}
+	if cmp.Equal(d.brokers, config.Brokers) && d.offsets == offsets {
+		return nil
+	}
+	d.logger.Info("Updating the Kafka bootstrap servers and offset configuration", zap.Strings("brokers", config.Brokers),
+		zap.Duration("offsetCommitInterval", offsets.commitInterval), zap.String("initialOffset", offsets.initial),
+		zap.Duration("rebalanceTimeout", offsets.rebalanceTimeout))
+	if err := d.reconnect(config.Brokers, d.credentials, offsets); err != nil {
+		return fmt.Errorf("unable to connect to the new kafka brokers: %v", err)
+	}
+	return nil
+}
+
+func (d *KafkaDispatcher) setDedupWindow(window time.Duration, size int) {
+	d.dedupWindow, d.dedupSize = window, size
+	var w *dedup.Window
+	if window > 0 {
+		w = dedup.NewWindow(size, window)
+	}
+	d.dedup.Store(w)
+}
+
+func (d *KafkaDispatcher) getDedupWindow() *dedup.Window {
+	w, _ := d.dedup.Load().(*dedup.Window)
+	return w
+}
+
+// reconnect replaces the Kafka clients with clients of brokers authenticated with creds, whose
+// consumers are configured with offsets. It must be called with updateLock held.
+func (d *KafkaDispatcher) reconnect(brokers []string, creds *controller.Credentials, offsets offsetConfig) error {
+	client, producer, kafkaCluster, err := d.connect(brokers, creds, offsets)
+	if err != nil {
+		return err
+	}
+	d.brokers, d.credentials, d.offsets = brokers, creds, offsets
+
+	d.producerLock.Lock()
+	oldClient, oldProducer := d.kafkaClient, d.kafkaAsyncProducer
+	d.kafkaClient, d.kafkaAsyncProducer = client, producer
+	d.producerLock.Unlock()
+	go d.logProducer(producer)
+	if oldProducer != nil {
+		if err := oldProducer.Close(); err != nil {
+			d.logger.Warn("Unable to flush the events of the previous producer", zap.Error(err))
+		}
+	}
+	if oldClient != nil {
+		if err := oldClient.Close(); err != nil {
+			d.logger.Warn("Unable to close the previous kafka client", zap.Error(err))
+		}
+	}
+
+	d.kafkaCluster = kafkaCluster
+	type consumerRef struct {
+		channel provisioners.ChannelReference
+		sub     subscription
+	}
+	var consumers []consumerRef
+	for channelRef, subMap := range d.kafkaConsumers {
+		for sub := range subMap {
+			consumers = append(consumers, consumerRef{channel: channelRef, sub: sub})
+		}
+	}
+	for _, c := range consumers {
+		if err := d.unsubscribe(c.channel, c.sub); err != nil {
+			d.logger.Warn("Unable to close the previous consumer", zap.Any("subscription", c.sub), zap.Error(err))
+		}
+		if err := d.subscribe(c.channel, c.sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectBrokers creates the client and the producer writing to brokers, and the cluster creating
+// consumers of brokers configured with offsets, authenticated with creds.
+func connectBrokers(brokers []string, creds *controller.Credentials, offsets offsetConfig) (sarama.Client, sarama.AsyncProducer, KafkaCluster, error) {
+	conf := sarama.NewConfig()
+	conf.Version = sarama.V1_1_0_0
+	conf.ClientID = controller.Name + "-dispatcher"
+	if err := creds.Apply(conf); err != nil {
+		return nil, nil, nil, err
+	}
+	client, err := sarama.NewClient(brokers, conf)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to create kafka client: %v", err)
+	}
+
+	producer, err := sarama.NewAsyncProducerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, nil, nil, fmt.Errorf("unable to create kafka producer: %v", err)
+	}
+	return client, producer, &saramaCluster{kafkaBrokers: brokers, credentials: creds, offsets: offsets}, nil
+}
+
+// WithCredentials makes the dispatcher authenticate to the brokers with creds.
+func WithCredentials(creds *controller.Credentials) Option {
+	return func(d *KafkaDispatcher) {
+		d.credentials = creds
+	}
+}
+
+// WithClaimCheck makes the dispatcher move the data of the events larger than threshold bytes to
+// store, rather than writing it to Kafka, and restore it before the events are delivered.
+func WithClaimCheck(store provisioners.ClaimCheckStore, threshold int) Option {
+	return func(d *KafkaDispatcher) {
+		d.receiverOptions = append(d.receiverOptions, provisioners.WithClaimCheck(store, threshold))
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithClaimCheckStore(store))
+	}
+}
+
+// WithEncryption makes the dispatcher encrypt the data of the events with e before writing them to
+// Kafka, and decrypt it before the events are delivered.
+func WithEncryption(e provisioners.Encrypter) Option {
+	return func(d *KafkaDispatcher) {
+		d.receiverOptions = append(d.receiverOptions, provisioners.WithEncryption(e))
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithDecryption(e))
+	}
+}
+
+// WithAuditSink makes the dispatcher record every attempt to deliver an event to a subscriber in
+// sink.
+func WithAuditSink(sink provisioners.AuditSink) Option {
+	return func(d *KafkaDispatcher) {
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithAuditSink(sink))
+	}
+}
+
+// WithTLSConfig makes the dispatcher use config for the TLS connections to the subscribers.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(d *KafkaDispatcher) {
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithTLSConfig(config))
+	}
+}
+
+// WithMaxBodySize makes the dispatcher reject the events whose body is larger than max bytes, and
+// fail the deliveries whose reply is. Bodies are unbounded when max is 0.
+func WithMaxBodySize(max int64) Option {
+	return func(d *KafkaDispatcher) {
+		d.receiverOptions = append(d.receiverOptions, provisioners.WithMaxBodySize(max))
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithMaxReplySize(max))
+	}
+}
+
+// WithSigning makes the dispatcher sign the events that are not signed yet with s before writing
+// them to Kafka.
+func WithSigning(s provisioners.Signer) Option {
+	return func(d *KafkaDispatcher) {
+		d.receiverOptions = append(d.receiverOptions, provisioners.WithSigning(s))
+	}
+}
+
+// WithSignatureVerification makes the dispatcher verify the signature of the events with v when it
+// receives them, if they are already signed, and when it reads them from Kafka before sending them
+// to the subscribers.
+func WithSignatureVerification(v provisioners.Verifier) Option {
+	return func(d *KafkaDispatcher) {
+		d.receiverOptions = append(d.receiverOptions, provisioners.WithSignedMessageVerification(v))
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithSignatureVerification(v))
+	}
+}
 
-	return d.receiver.Start(stopCh)
+// WithTap makes the dispatcher publish a copy of every event written to Kafka to tap.
+func WithTap(tap provisioners.Tap) Option {
+	return func(d *KafkaDispatcher) {
+		d.receiverOptions = append(d.receiverOptions, provisioners.WithTap(tap))
+	}
+}
+
+// WithPusher makes the dispatcher push the events of the subscriptions with the SSE protocol to
+// their consumers connected to p.
+func WithPusher(p provisioners.Pusher) Option {
+	return func(d *KafkaDispatcher) {
+		d.dispatcherOptions = append(d.dispatcherOptions, provisioners.WithPusher(p))
+	}
 }
 
 func (d *KafkaDispatcher) subscribe(channelRef provisioners.ChannelReference, sub subscription) error {
@@ -165,13 +696,13 @@ func (d *KafkaDispatcher) subscribe(channelRef provisioners.ChannelReference, su
 
 	topicName := topicUtils.TopicName(controller.KafkaChannelSeparator, channelRef.Namespace, channelRef.Name)
 
-	group := fmt.Sprintf("%s.%s.%s", controller.Name, sub.Namespace, sub.Name)
-	consumer, err := d.kafkaCluster.NewConsumer(group, []string{topicName})
+	kafkaConsumer, err := d.kafkaCluster.NewConsumer(consumerGroup(sub.Namespace, sub.Name), []string{topicName})
 	if err != nil {
 		// we can not create a consumer - logging that, with reason
 		d.logger.Info("Could not create proper consumer", zap.Error(err))
 		return err
 	}
+	consumer := newLagConsumer(kafkaConsumer)
 
 	channelMap, ok := d.kafkaConsumers[channelRef]
 	if !ok {
@@ -181,27 +712,74 @@ func (d *KafkaDispatcher) subscribe(channelRef provisioners.ChannelReference, su
 	channelMap[sub] = consumer
 
 	go func() {
+		defer close(consumer.done)
+		notifications := consumer.Notifications()
 		for {
-			msg, more := <-consumer.Messages()
-			if more {
-				d.logger.Info("Dispatching a message for subscription", zap.Any("channelRef", channelRef), zap.Any("subscription", sub))
-				message := fromKafkaMessage(msg)
-				err := d.dispatchMessage(message, sub)
-				if err != nil {
-					d.logger.Warn("Got error trying to dispatch message", zap.Error(err))
+			select {
+			case partition, more := <-consumer.Partitions():
+				if !more {
+					return
+				}
+				d.logger.Info("Consuming a partition for subscription", zap.Any("channelRef", channelRef), zap.Any("subscription", sub), zap.Int32("partition", partition.Partition()))
+				consumer.partitions.Add(1)
+				go func() {
+					defer consumer.partitions.Done()
+					d.consumePartition(channelRef, sub, consumer, partition)
+				}()
+			case n, more := <-notifications:
+				if !more {
+					notifications = nil
+					continue
 				}
-				// TODO: handle errors with pluggable strategy
-				consumer.MarkOffset(msg, "") // Mark message as processed
-			} else {
-				break
+				d.logger.Info("Consumer group rebalanced", zap.Any("subscription", sub), zap.String("type", n.Type.String()),
+					zap.Any("claimed", n.Claimed), zap.Any("released", n.Released), zap.Any("current", n.Current))
+				consumer.rebalanced(n)
+			case <-consumer.stop:
+				d.logger.Info("Consumer for subscription stopped", zap.Any("channelRef", channelRef), zap.Any("subscription", sub))
+				return
 			}
 		}
-		d.logger.Info("Consumer for subscription stopped", zap.Any("channelRef", channelRef), zap.Any("subscription", sub))
 	}()
 
 	return nil
 }
 
+// consumePartition dispatches the messages of partition to sub, in order, until the partition is
+// released by a rebalance or consumer is stopped.
+func (d *KafkaDispatcher) consumePartition(channelRef provisioners.ChannelReference, sub subscription, consumer *lagConsumer, partition cluster.PartitionConsumer) {
+	for {
+		select {
+		case msg, more := <-partition.Messages():
+			if !more {
+				d.logger.Info("Partition released for subscription", zap.Any("subscription", sub), zap.Int32("partition", partition.Partition()))
+				return
+			}
+			d.logger.Info("Dispatching a message for subscription", zap.Any("channelRef", channelRef), zap.Any("subscription", sub))
+			message := fromKafkaMessage(msg)
+			err := d.dispatchMessage(channelRef, message, sub, 1)
+			for redelivery := 1; provisioners.IsNack(err) && redelivery <= d.nackRedeliveries; redelivery++ {
+				delay := d.nackBackoff << uint(redelivery-1)
+				d.logger.Info("Redelivering a nacked message", zap.Any("subscription", sub), zap.Int("redelivery", redelivery), zap.Duration("delay", delay))
+				select {
+				case <-time.After(delay):
+				case <-consumer.stop:
+					// The message is not marked, so it is redelivered once the partition is
+					// consumed again.
+					return
+				}
+				err = d.dispatchMessage(channelRef, message, sub, redelivery+1)
+			}
+			if err != nil {
+				d.logger.Warn("Got error trying to dispatch message", zap.Error(err))
+			}
+			// TODO: handle errors with pluggable strategy
+			consumer.MarkOffset(msg, "") // Mark message as processed
+		case <-consumer.stop:
+			return
+		}
+	}
+}
+
 func (d *KafkaDispatcher) unsubscribe(channel provisioners.ChannelReference, sub subscription) error {
 	d.logger.Info("Unsubscribing from channel", zap.Any("channel", channel), zap.Any("subscription", sub))
 	if consumer, ok := d.kafkaConsumers[channel][sub]; ok {
@@ -211,10 +789,87 @@ func (d *KafkaDispatcher) unsubscribe(channel provisioners.ChannelReference, sub
 	return nil
 }
 
-// dispatchMessage sends the request to exactly one subscription. It handles both the `call` and
-// the `sink` portions of the subscription.
-func (d *KafkaDispatcher) dispatchMessage(m *provisioners.Message, sub subscription) error {
-	return d.dispatcher.DispatchMessage(m, sub.SubscriberURI, sub.ReplyURI, provisioners.DispatchDefaults{})
+// Backlog returns the lag of the consumers of each channel: the number of messages in the topic of
+// the channel that were not dispatched to each subscription yet.
+func (d *KafkaDispatcher) Backlog() map[provisioners.ChannelReference]int64 {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+	backlog := make(map[provisioners.ChannelReference]int64)
+	for channelRef, consumers := range d.kafkaConsumers {
+		for _, consumer := range consumers {
+			if c, ok := consumer.(*lagConsumer); ok {
+				backlog[channelRef] += c.lag()
+			}
+		}
+	}
+	return backlog
+}
+
+// Ready returns an error if the replica of the dispatcher cannot serve traffic: the Kafka brokers
+// are not reachable, a subscription that is not paused has no consumer in its consumer group, or
+// the last rebalance of the consumer group failed. A replica that was not assigned any partition,
+// when there are more replicas than partitions, is ready.
+func (d *KafkaDispatcher) Ready() error {
+	d.producerLock.RLock()
+	client := d.kafkaClient
+	d.producerLock.RUnlock()
+	if client != nil {
+		if err := client.RefreshMetadata(); err != nil {
+			return fmt.Errorf("kafka brokers unreachable: %v", err)
+		}
+	}
+
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+	for _, cc := range d.getConfig().ChannelConfigs {
+		channelRef := provisioners.ChannelReference{Namespace: cc.Namespace, Name: cc.Name}
+		for _, subSpec := range cc.FanoutConfig.Subscriptions {
+			if subSpec.Paused {
+				continue
+			}
+			sub := newSubscription(subSpec)
+			consumer, ok := d.kafkaConsumers[channelRef][sub]
+			if !ok {
+				return fmt.Errorf("no consumer for subscription %s/%s of channel %s", sub.Namespace, sub.Name, channelRef.String())
+			}
+			if c, ok := consumer.(*lagConsumer); ok {
+				if err := c.err(); err != nil {
+					return fmt.Errorf("consumer for subscription %s/%s of channel %s: %v", sub.Namespace, sub.Name, channelRef.String(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dispatchMessage sends the request of channel to exactly one subscription, as the given attempt
+// to deliver it. It handles both the `call` and the `sink` portions of the subscription.
+func (d *KafkaDispatcher) dispatchMessage(channel provisioners.ChannelReference, m *provisioners.Message, sub subscription, attempt int) error {
+	subscriber := sub.Namespace + "/" + sub.Name
+	return d.getDedupWindow().Dispatch(subscriber, m, func() error {
+		subscriberDefaults, _ := d.subscriberDefaults.Load().(map[subscription]provisioners.DispatchDefaults)
+		defaults := subscriberDefaults[sub]
+		defaults.OnError = sub.ErrorURI
+		defaults.Channel = channel.String()
+		defaults.Subscription = subscriber
+		defaults.Protocol = sub.Protocol
+		defaults.Redelivery = attempt > 1
+		defaults.Attempt = attempt
+		return d.dispatcher.DispatchMessage(m, sub.SubscriberURI, sub.ReplyURI, defaults)
+	})
+}
+
+// partitionKey returns the key of the Kafka message holding an event of the channel: the value of
+// the CloudEvents attribute configured as the partition key of the channel, or of the partitionkey
+// extension of the event if the channel has none, as in the CloudEvents Kafka binding. Events are
+// not keyed if they do not have the attribute.
+func (d *KafkaDispatcher) partitionKey(channel provisioners.ChannelReference, message *provisioners.Message) string {
+	partitionKeys, _ := d.partitionKeys.Load().(map[provisioners.ChannelReference]string)
+	attribute, ok := partitionKeys[channel]
+	if !ok {
+		attribute = partitionKeyExtension
+	}
+	return message.Attributes()[attribute]
 }
 
 func (d *KafkaDispatcher) getConfig() *multichannelfanout.Config {
@@ -225,44 +880,54 @@ func (d *KafkaDispatcher) setConfig(config *multichannelfanout.Config) {
 	d.config.Store(config)
 }
 
-func NewDispatcher(brokers []string, logger *zap.Logger) (*KafkaDispatcher, error) {
-
-	conf := sarama.NewConfig()
-	conf.Version = sarama.V1_1_0_0
-	conf.ClientID = controller.Name + "-dispatcher"
-	client, err := sarama.NewClient(brokers, conf)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create kafka client: %v", err)
-	}
-
-	producer, err := sarama.NewAsyncProducerFromClient(client)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create kafka producer: %v", err)
-	}
+func NewDispatcher(brokers []string, logger *zap.Logger, opts ...Option) (*KafkaDispatcher, error) {
 
 	dispatcher := &KafkaDispatcher{
-		dispatcher: provisioners.NewMessageDispatcher(logger.Sugar()),
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		brokers:        brokers,
+		connect:        connectBrokers,
 
-		kafkaCluster:       &saramaCluster{kafkaBrokers: brokers},
-		kafkaConsumers:     make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
-		kafkaAsyncProducer: producer,
+		nackRedeliveries: defaultNackRedeliveries,
+		nackBackoff:      defaultNackBackoff,
 
 		logger: logger,
 	}
+	for _, opt := range opts {
+		opt(dispatcher)
+	}
+
+	client, producer, kafkaCluster, err := dispatcher.connect(dispatcher.brokers, dispatcher.credentials, dispatcher.offsets)
+	if err != nil {
+		return nil, err
+	}
+	dispatcher.kafkaClient = client
+	dispatcher.kafkaAsyncProducer = producer
+	dispatcher.kafkaCluster = kafkaCluster
+	go dispatcher.logProducer(producer)
+
+	dispatcher.dispatcher = provisioners.NewMessageDispatcher(logger.Sugar(), dispatcher.dispatcherOptions...)
 	receiverFunc := provisioners.NewMessageReceiver(
 		func(channel provisioners.ChannelReference, message *provisioners.Message) error {
-			dispatcher.kafkaAsyncProducer.Input() <- toKafkaMessage(channel, message)
+			dispatcher.produce(toKafkaMessage(channel, message, dispatcher.partitionKey(channel, message)))
 			return nil
-		}, logger.Sugar())
+		}, logger.Sugar(), dispatcher.receiverOptions...)
 	dispatcher.receiver = receiverFunc
 	dispatcher.setConfig(&multichannelfanout.Config{})
 	return dispatcher, nil
 }
 
+// fromKafkaMessage converts a Kafka message of a channel's topic to a message. The record headers
+// of the CloudEvents attributes are mapped back to the headers of the HTTP binding, and the other
+// headers are kept, lower-cased, so that the events written to the topic by external producers with
+// the CloudEvents Kafka binding are dispatched like the events sent to the channel.
 func fromKafkaMessage(kafkaMessage *sarama.ConsumerMessage) *provisioners.Message {
 	headers := make(map[string]string)
 	for _, header := range kafkaMessage.Headers {
-		headers[string(header.Key)] = string(header.Value)
+		key := strings.ToLower(string(header.Key))
+		if strings.HasPrefix(key, kafkaCloudEventsPrefix) {
+			key = httpCloudEventsPrefix + strings.TrimPrefix(key, kafkaCloudEventsPrefix)
+		}
+		headers[key] = string(header.Value)
 	}
 	message := provisioners.Message{
 		Headers: headers,
@@ -271,25 +936,44 @@ func fromKafkaMessage(kafkaMessage *sarama.ConsumerMessage) *provisioners.Messag
 	return &message
 }
 
-func toKafkaMessage(channel provisioners.ChannelReference, message *provisioners.Message) *sarama.ProducerMessage {
+// toKafkaMessage converts a message to a Kafka message of the channel's topic, with the CloudEvents
+// Kafka binding: the attributes of events in the binary content mode are record headers prefixed
+// with ce_, and their data is the value of the record. Events in the structured content mode are
+// the value of the record, with their content-type header. If key is not empty, it keys the Kafka
+// message, so that it is written to the same partition as the other messages with the same key.
+func toKafkaMessage(channel provisioners.ChannelReference, message *provisioners.Message, key string) *sarama.ProducerMessage {
 	kafkaMessage := sarama.ProducerMessage{
 		Topic: topicUtils.TopicName(controller.KafkaChannelSeparator, channel.Namespace, channel.Name),
 		Value: sarama.ByteEncoder(message.Payload),
 	}
+	if key != "" {
+		kafkaMessage.Key = sarama.StringEncoder(key)
+	}
 	for h, v := range message.Headers {
+		key := strings.ToLower(h)
+		if strings.HasPrefix(key, httpCloudEventsPrefix) {
+			key = kafkaCloudEventsPrefix + strings.TrimPrefix(key, httpCloudEventsPrefix)
+		}
 		kafkaMessage.Headers = append(kafkaMessage.Headers, sarama.RecordHeader{
-			Key:   []byte(h),
+			Key:   []byte(key),
 			Value: []byte(v),
 		})
 	}
 	return &kafkaMessage
 }
 
+// consumerGroup returns the name of the consumer group of the subscription name in namespace.
+func consumerGroup(namespace, name string) string {
+	return fmt.Sprintf("%s.%s.%s", controller.Name, namespace, name)
+}
+
 func newSubscription(spec eventingduck.ChannelSubscriberSpec) subscription {
 	return subscription{
 		Name:          spec.Ref.Name,
 		Namespace:     spec.Ref.Namespace,
 		SubscriberURI: spec.SubscriberURI,
 		ReplyURI:      spec.ReplyURI,
+		ErrorURI:      spec.ErrorURI,
+		Protocol:      spec.Protocol,
 	}
 }
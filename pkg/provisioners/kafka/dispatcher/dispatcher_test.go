@@ -6,25 +6,36 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
+	cluster "github.com/bsm/sarama-cluster"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"go.uber.org/zap"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/kafka/controller"
 	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
 )
 
 type mockConsumer struct {
-	message chan *sarama.ConsumerMessage
+	partitions     chan cluster.PartitionConsumer
+	notifications  chan *cluster.Notification
+	highWaterMarks map[string]map[int32]int64
 }
 
-func (c *mockConsumer) Messages() <-chan *sarama.ConsumerMessage {
-	return c.message
+func (c *mockConsumer) Partitions() <-chan cluster.PartitionConsumer {
+	return c.partitions
+}
+
+func (c *mockConsumer) Notifications() <-chan *cluster.Notification {
+	return c.notifications
 }
 
 func (c *mockConsumer) Close() error {
@@ -35,26 +46,57 @@ func (c *mockConsumer) MarkOffset(msg *sarama.ConsumerMessage, metadata string)
 	return
 }
 
+func (c *mockConsumer) HighWaterMarks() map[string]map[int32]int64 {
+	return c.highWaterMarks
+}
+
+// mockPartitionConsumer is a claimed partition whose messages are sent to message.
+type mockPartitionConsumer struct {
+	cluster.PartitionConsumer
+	partition int32
+	message   chan *sarama.ConsumerMessage
+}
+
+func (c *mockPartitionConsumer) Messages() <-chan *sarama.ConsumerMessage {
+	return c.message
+}
+
+func (c *mockPartitionConsumer) Partition() int32 {
+	return c.partition
+}
+
 type mockSaramaCluster struct {
 	// closed closes the message channel so that it doesn't block during the test
 	closed bool
 	// Handle to the latest created consumer, useful to access underlying message chan
 	consumerChannel chan *sarama.ConsumerMessage
+	// Handle to the latest created consumer, useful to claim more partitions and rebalance
+	consumer *mockConsumer
 	// createErr will return an error when creating a consumer
 	createErr bool
 }
 
+// NewConsumer returns a consumer that claims a single partition, whose messages are sent to
+// consumerChannel.
 func (c *mockSaramaCluster) NewConsumer(groupID string, topics []string) (KafkaConsumer, error) {
 	if c.createErr {
 		return nil, fmt.Errorf("error creating consumer")
 	}
 	consumer := &mockConsumer{
+		partitions:    make(chan cluster.PartitionConsumer, 10),
+		notifications: make(chan *cluster.Notification, 10),
+	}
+	partition := &mockPartitionConsumer{
 		message: make(chan *sarama.ConsumerMessage),
 	}
+	consumer.partitions <- partition
 	if c.closed {
-		close(consumer.message)
+		close(partition.message)
+		close(consumer.partitions)
+		close(consumer.notifications)
 	}
-	c.consumerChannel = consumer.message
+	c.consumerChannel = partition.message
+	c.consumer = consumer
 	return consumer, nil
 }
 
@@ -168,6 +210,56 @@ func TestDispatcher_UpdateConfig(t *testing.T) {
 			subscribes:   []string{"subscription-2", "subscription-3"},
 			unsubscribes: []string{"subscription-1"},
 		},
+		{
+			name: "single channel w/ paused subscription",
+			oldConfig: &multichannelfanout.Config{
+				ChannelConfigs: []multichannelfanout.ChannelConfig{
+					{
+						Namespace: "default",
+						Name:      "test-channel",
+						FanoutConfig: fanout.Config{
+							Subscriptions: []eventingduck.ChannelSubscriberSpec{
+								{
+									Ref: &v1.ObjectReference{
+										Name: "subscription-1",
+									},
+									SubscriberURI: "http://test/subscriber",
+								},
+								{
+									Ref: &v1.ObjectReference{
+										Name: "subscription-2",
+									},
+									SubscriberURI: "http://test/subscriber",
+								}}}}},
+			},
+			newConfig: &multichannelfanout.Config{
+				ChannelConfigs: []multichannelfanout.ChannelConfig{
+					{
+						Namespace: "default",
+						Name:      "test-channel",
+						FanoutConfig: fanout.Config{
+							Subscriptions: []eventingduck.ChannelSubscriberSpec{
+								{
+									Ref: &v1.ObjectReference{
+										Name: "subscription-1",
+									},
+									SubscriberURI: "http://test/subscriber",
+									Paused:        true,
+								},
+								{
+									Ref: &v1.ObjectReference{
+										Name: "subscription-2",
+									},
+									SubscriberURI: "http://test/subscriber",
+								},
+							},
+						},
+					},
+				},
+			},
+			subscribes:   []string{"subscription-2"},
+			unsubscribes: []string{"subscription-1"},
+		},
 		{
 			name: "multi channel w/old and new subscriptions",
 			oldConfig: &multichannelfanout.Config{
@@ -298,17 +390,33 @@ func TestFromKafkaMessage(t *testing.T) {
 				Key:   []byte("k1"),
 				Value: []byte("v1"),
 			},
+			{
+				Key:   []byte("ce_id"),
+				Value: []byte("1234"),
+			},
+			{
+				Key:   []byte("Content-Type"),
+				Value: []byte("text/plain"),
+			},
+			// Records written before the CloudEvents Kafka binding was used have HTTP headers.
+			{
+				Key:   []byte("ce-source"),
+				Value: []byte("/orders"),
+			},
 		},
 		Value: data,
 	}
 	want := &provisioners.Message{
 		Headers: map[string]string{
-			"k1": "v1",
+			"k1":           "v1",
+			"ce-id":        "1234",
+			"content-type": "text/plain",
+			"ce-source":    "/orders",
 		},
 		Payload: data,
 	}
 	got := fromKafkaMessage(kafkaMessage)
-	if diff := cmp.Diff(want, got); diff != "" {
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(provisioners.Message{})); diff != "" {
 		t.Errorf("unexpected message (-want, +got) = %v", diff)
 	}
 }
@@ -321,13 +429,28 @@ func TestToKafkaMessage(t *testing.T) {
 	}
 	msg := &provisioners.Message{
 		Headers: map[string]string{
-			"k1": "v1",
+			"k1":             "v1",
+			"ce-specversion": "1.0",
+			"ce-id":          "1234",
+			"content-type":   "text/plain",
 		},
 		Payload: data,
 	}
 	want := &sarama.ProducerMessage{
 		Topic: "knative-eventing-channel.test-ns.test-channel",
 		Headers: []sarama.RecordHeader{
+			{
+				Key:   []byte("ce_id"),
+				Value: []byte("1234"),
+			},
+			{
+				Key:   []byte("ce_specversion"),
+				Value: []byte("1.0"),
+			},
+			{
+				Key:   []byte("content-type"),
+				Value: []byte("text/plain"),
+			},
 			{
 				Key:   []byte("k1"),
 				Value: []byte("v1"),
@@ -335,10 +458,467 @@ func TestToKafkaMessage(t *testing.T) {
 		},
 		Value: sarama.ByteEncoder(data),
 	}
-	got := toKafkaMessage(channelRef, msg)
-	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(sarama.ProducerMessage{})); diff != "" {
+	sortHeaders := cmpopts.SortSlices(func(a, b sarama.RecordHeader) bool {
+		return string(a.Key) < string(b.Key)
+	})
+	got := toKafkaMessage(channelRef, msg, "")
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(sarama.ProducerMessage{}), sortHeaders); diff != "" {
 		t.Errorf("unexpected message (-want, +got) = %v", diff)
 	}
+	if diff := cmp.Diff(msg, fromKafkaMessage(&sarama.ConsumerMessage{Headers: consumerHeaders(got.Headers), Value: data}), cmpopts.IgnoreUnexported(provisioners.Message{})); diff != "" {
+		t.Errorf("unexpected round trip (-want, +got) = %v", diff)
+	}
+
+	want.Key = sarama.StringEncoder("order-1234")
+	got = toKafkaMessage(channelRef, msg, "order-1234")
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(sarama.ProducerMessage{}), sortHeaders); diff != "" {
+		t.Errorf("unexpected keyed message (-want, +got) = %v", diff)
+	}
+}
+
+// consumerHeaders returns the headers of a consumed record with the headers of a produced one.
+func consumerHeaders(headers []sarama.RecordHeader) []*sarama.RecordHeader {
+	var consumed []*sarama.RecordHeader
+	for i := range headers {
+		consumed = append(consumed, &headers[i])
+	}
+	return consumed
+}
+
+func TestBacklog(t *testing.T) {
+	channelRef := provisioners.ChannelReference{Namespace: "test-ns", Name: "test-channel"}
+	consumer := newLagConsumer(&mockConsumer{
+		highWaterMarks: map[string]map[int32]int64{
+			"topic": {0: 10, 1: 5, 2: 7},
+		},
+	})
+	d := &KafkaDispatcher{
+		kafkaConsumers: map[provisioners.ChannelReference]map[subscription]KafkaConsumer{
+			channelRef: {subscription{Name: "sub"}: consumer},
+		},
+	}
+	consumer.MarkOffset(&sarama.ConsumerMessage{Topic: "topic", Partition: 0, Offset: 3}, "")
+	consumer.MarkOffset(&sarama.ConsumerMessage{Topic: "topic", Partition: 1, Offset: 4}, "")
+
+	// Partition 0 has 6 messages after offset 3, partition 1 has none after offset 4 and
+	// partition 2 was not consumed yet.
+	want := map[provisioners.ChannelReference]int64{channelRef: 6}
+	if diff := cmp.Diff(want, d.Backlog()); diff != "" {
+		t.Errorf("unexpected backlog (-want, +got) = %v", diff)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	channelRef := provisioners.ChannelReference{Namespace: "test-ns", Name: "test-channel"}
+	sub := subscription{Namespace: "test-ns", Name: "test-sub", SubscriberURI: "subscriber"}
+	consumer := newLagConsumer(&mockConsumer{
+		highWaterMarks: map[string]map[int32]int64{
+			"topic": {0: 10, 1: 5},
+		},
+	})
+	d := &KafkaDispatcher{
+		kafkaConsumers: map[provisioners.ChannelReference]map[subscription]KafkaConsumer{
+			channelRef: {sub: consumer},
+		},
+		logger: zap.NewNop(),
+	}
+	d.setConfig(&multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{{
+			Namespace: channelRef.Namespace,
+			Name:      channelRef.Name,
+			FanoutConfig: fanout.Config{
+				Subscriptions: []eventingduck.ChannelSubscriberSpec{{
+					Ref:           &v1.ObjectReference{Namespace: sub.Namespace, Name: sub.Name},
+					SubscriberURI: sub.SubscriberURI,
+				}},
+			},
+		}},
+	})
+	consumer.MarkOffset(&sarama.ConsumerMessage{Topic: "topic", Partition: 0, Offset: 3}, "")
+	consumer.MarkOffset(&sarama.ConsumerMessage{Topic: "topic", Partition: 1, Offset: 1}, "")
+
+	consumer.rebalanced(&cluster.Notification{Type: cluster.RebalanceError})
+	if err := d.Ready(); err == nil {
+		t.Errorf("Expected the replica not to be ready after a failed rebalance")
+	}
+
+	// Partition 0 was claimed by another replica, its lag is reported by that replica.
+	consumer.rebalanced(&cluster.Notification{
+		Type:     cluster.RebalanceOK,
+		Released: map[string][]int32{"topic": {0}},
+		Current:  map[string][]int32{"topic": {1}},
+	})
+	if err := d.Ready(); err != nil {
+		t.Errorf("Unexpected Ready error after a successful rebalance: %v", err)
+	}
+	want := map[provisioners.ChannelReference]int64{channelRef: 3}
+	if diff := cmp.Diff(want, d.Backlog()); diff != "" {
+		t.Errorf("unexpected backlog (-want, +got) = %v", diff)
+	}
+}
+
+func TestSubscribePartitions(t *testing.T) {
+	sc := &mockSaramaCluster{}
+	d := &KafkaDispatcher{
+		kafkaCluster:   sc,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		dispatcher:     provisioners.NewMessageDispatcher(zap.NewNop().Sugar()),
+		logger:         zap.NewNop(),
+	}
+
+	// The event of the first partition is only answered once the event of the second partition
+	// was received, so both partitions must be dispatched concurrently.
+	second := make(chan struct{})
+	ids := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("ce-id")
+		ids <- id
+		if id == "1" {
+			<-second
+		} else {
+			close(second)
+		}
+	}))
+	defer server.Close()
+
+	channelRef := provisioners.ChannelReference{Name: "test-channel", Namespace: "test-ns"}
+	subRef := subscription{Name: "test-sub", Namespace: "test-ns", SubscriberURI: server.URL[7:]}
+	if err := d.subscribe(channelRef, subRef); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	partition := &mockPartitionConsumer{partition: 1, message: make(chan *sarama.ConsumerMessage)}
+	sc.consumer.partitions <- partition
+	defer close(sc.consumerChannel)
+	defer close(partition.message)
+
+	for id, messages := range map[string]chan *sarama.ConsumerMessage{"1": sc.consumerChannel, "2": partition.message} {
+		messages <- &sarama.ConsumerMessage{
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte("ce-specversion"), Value: []byte("1.0")},
+				{Key: []byte("ce-id"), Value: []byte(id)},
+				{Key: []byte("ce-source"), Value: []byte("/orders")},
+			},
+			Value: []byte("data"),
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ids:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the partitions were not dispatched concurrently")
+		}
+	}
+}
+
+// mockProducer is a sarama.AsyncProducer buffering the messages written to it.
+type mockProducer struct {
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+	closed    bool
+}
+
+func newMockProducer() *mockProducer {
+	return &mockProducer{
+		input:     make(chan *sarama.ProducerMessage, 10),
+		successes: make(chan *sarama.ProducerMessage),
+		errors:    make(chan *sarama.ProducerError),
+	}
+}
+
+func (p *mockProducer) AsyncClose() {
+	p.Close()
+}
+
+func (p *mockProducer) Close() error {
+	p.closed = true
+	close(p.successes)
+	close(p.errors)
+	return nil
+}
+
+func (p *mockProducer) Input() chan<- *sarama.ProducerMessage {
+	return p.input
+}
+
+func (p *mockProducer) Successes() <-chan *sarama.ProducerMessage {
+	return p.successes
+}
+
+func (p *mockProducer) Errors() <-chan *sarama.ProducerError {
+	return p.errors
+}
+
+func TestUpdateCredentials(t *testing.T) {
+	channelRef := provisioners.ChannelReference{Namespace: "test-ns", Name: "test-channel"}
+	sub := subscription{Namespace: "test-ns", Name: "test-sub", SubscriberURI: "subscriber"}
+	oldProducer := newMockProducer()
+	oldCluster := &mockSaramaCluster{}
+	newProducer := newMockProducer()
+	newCluster := &mockSaramaCluster{}
+	var connectErr error
+	var connected []*controller.Credentials
+	d := &KafkaDispatcher{
+		kafkaAsyncProducer: oldProducer,
+		kafkaCluster:       oldCluster,
+		kafkaConsumers:     make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		credentials:        &controller.Credentials{User: "kafka", Password: "old"},
+		connect: func(brokers []string, creds *controller.Credentials, _ offsetConfig) (sarama.Client, sarama.AsyncProducer, KafkaCluster, error) {
+			connected = append(connected, creds)
+			return nil, newProducer, newCluster, connectErr
+		},
+		logger: zap.NewNop(),
+	}
+	if err := d.subscribe(channelRef, sub); err != nil {
+		t.Fatalf("Unexpected subscribe error: %v", err)
+	}
+
+	// Unchanged credentials do not rebuild the clients.
+	if err := d.UpdateCredentials(&controller.Credentials{User: "kafka", Password: "old"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(connected) != 0 {
+		t.Fatalf("Expected unchanged credentials to keep the clients")
+	}
+
+	// The clients are kept if the new ones cannot be created.
+	connectErr = fmt.Errorf("authentication failed")
+	if err := d.UpdateCredentials(&controller.Credentials{User: "kafka", Password: "invalid"}); err == nil {
+		t.Fatalf("Expected an error when the new clients cannot be created")
+	}
+	if d.producer() != oldProducer || oldProducer.closed {
+		t.Fatalf("Expected the previous producer to be kept")
+	}
+
+	connectErr = nil
+	newCreds := &controller.Credentials{User: "kafka", Password: "new"}
+	if err := d.UpdateCredentials(newCreds); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(newCreds, connected[len(connected)-1]); diff != "" {
+		t.Errorf("Unexpected credentials (-want, +got) = %v", diff)
+	}
+	if !oldProducer.closed {
+		t.Errorf("Expected the previous producer to be flushed and closed")
+	}
+	d.produce(&sarama.ProducerMessage{Topic: "topic"})
+	if len(newProducer.input) != 1 {
+		t.Errorf("Expected the message to be written by the new producer")
+	}
+
+	// The consumer of the subscription was replaced by one created with the new credentials.
+	if newCluster.consumerChannel == nil {
+		t.Fatalf("Expected the subscription to be consumed with the new credentials")
+	}
+	if _, ok := d.kafkaConsumers[channelRef][sub]; !ok {
+		t.Errorf("Expected the subscription to have a consumer")
+	}
+	select {
+	case oldCluster.consumerChannel <- &sarama.ConsumerMessage{}:
+		t.Errorf("Expected the previous consumer to be stopped")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestUpdateProvisionerConfig(t *testing.T) {
+	channelRef := provisioners.ChannelReference{Namespace: "test-ns", Name: "test-channel"}
+	sub := subscription{Namespace: "test-ns", Name: "test-sub", SubscriberURI: "subscriber"}
+	oldProducer := newMockProducer()
+	newProducer := newMockProducer()
+	newCluster := &mockSaramaCluster{}
+	creds := &controller.Credentials{User: "kafka", Password: "secret"}
+	var connected [][]string
+	var offsets []offsetConfig
+	d := &KafkaDispatcher{
+		kafkaAsyncProducer: oldProducer,
+		kafkaCluster:       &mockSaramaCluster{},
+		kafkaConsumers:     make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		brokers:            []string{"old:9092"},
+		credentials:        creds,
+		connect: func(brokers []string, c *controller.Credentials, o offsetConfig) (sarama.Client, sarama.AsyncProducer, KafkaCluster, error) {
+			if c != creds {
+				t.Errorf("Expected the clients to keep the credentials")
+			}
+			connected = append(connected, brokers)
+			offsets = append(offsets, o)
+			return nil, newProducer, newCluster, nil
+		},
+		logger: zap.NewNop(),
+	}
+	if err := d.subscribe(channelRef, sub); err != nil {
+		t.Fatalf("Unexpected subscribe error: %v", err)
+	}
+
+	// Changing only the deduplication window does not rebuild the clients.
+	if err := d.UpdateProvisionerConfig(&controller.KafkaProvisionerConfig{
+		Brokers:     []string{"old:9092"},
+		DedupWindow: time.Minute,
+		DedupSize:   10,
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(connected) != 0 {
+		t.Fatalf("Expected unchanged brokers to keep the clients")
+	}
+	if d.getDedupWindow() == nil {
+		t.Fatalf("Expected deduplication to be enabled")
+	}
+
+	if err := d.UpdateProvisionerConfig(&controller.KafkaProvisionerConfig{
+		Brokers: []string{"new:9092"},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([][]string{{"new:9092"}}, connected); diff != "" {
+		t.Errorf("Unexpected brokers (-want, +got) = %v", diff)
+	}
+	if d.getDedupWindow() != nil {
+		t.Errorf("Expected deduplication to be disabled")
+	}
+	if !oldProducer.closed {
+		t.Errorf("Expected the previous producer to be flushed and closed")
+	}
+	if newCluster.consumerChannel == nil {
+		t.Errorf("Expected the subscription to be consumed from the new brokers")
+	}
+
+	// Changing the offset configuration rebuilds the consumers with it.
+	if err := d.UpdateProvisionerConfig(&controller.KafkaProvisionerConfig{
+		Brokers:              []string{"new:9092"},
+		OffsetCommitInterval: 5 * time.Second,
+		InitialOffset:        controller.InitialOffsetOldest,
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []offsetConfig{{}, {commitInterval: 5 * time.Second, initial: controller.InitialOffsetOldest}}
+	if diff := cmp.Diff(want, offsets, cmp.AllowUnexported(offsetConfig{})); diff != "" {
+		t.Errorf("Unexpected offset configuration (-want, +got) = %v", diff)
+	}
+}
+
+func TestReady(t *testing.T) {
+	config := &multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{
+			{
+				Namespace: "test-ns",
+				Name:      "test-channel",
+				FanoutConfig: fanout.Config{
+					Subscriptions: []eventingduck.ChannelSubscriberSpec{
+						{
+							Ref: &v1.ObjectReference{
+								Name:      "test-sub",
+								Namespace: "test-ns",
+							},
+							SubscriberURI: "subscriber",
+						},
+					},
+				},
+			},
+		},
+	}
+	testCases := map[string]struct {
+		createErr bool
+		wantErr   bool
+	}{
+		"consumer group joined": {},
+		"consumer not created": {
+			createErr: true,
+			wantErr:   true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			d := &KafkaDispatcher{
+				kafkaCluster:   &mockSaramaCluster{closed: true, createErr: tc.createErr},
+				kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+
+				logger: zap.NewNop(),
+			}
+			d.setConfig(&multichannelfanout.Config{})
+			if err := d.Ready(); err != nil {
+				t.Errorf("Unexpected error before the config is loaded: %v", err)
+			}
+			if err := d.UpdateConfig(config); err != nil {
+				t.Fatalf("Unexpected UpdateConfig error: %v", err)
+			}
+			if err := d.Ready(); (err != nil) != tc.wantErr {
+				t.Errorf("Unexpected Ready error. Expected error %v. Actual %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	d := &KafkaDispatcher{
+		kafkaCluster:   &mockSaramaCluster{closed: true},
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		logger:         zap.NewNop(),
+	}
+	d.setConfig(&multichannelfanout.Config{})
+	err := d.UpdateConfig(&multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{
+			{
+				Namespace:    "test-ns",
+				Name:         "keyed",
+				PartitionKey: "subject",
+			},
+			{
+				Namespace: "test-ns",
+				Name:      "unkeyed",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	msg := &provisioners.Message{
+		Headers: map[string]string{
+			"ce-specversion": "1.0",
+			"ce-subject":     "order-1234",
+		},
+	}
+	testCases := map[string]struct {
+		channel string
+		message *provisioners.Message
+		want    string
+	}{
+		"keyed channel": {
+			channel: "keyed",
+			message: msg,
+			want:    "order-1234",
+		},
+		"unkeyed channel": {
+			channel: "unkeyed",
+			message: msg,
+		},
+		"unknown channel": {
+			channel: "unknown",
+			message: msg,
+		},
+		"missing attribute": {
+			channel: "keyed",
+			message: &provisioners.Message{Headers: map[string]string{"ce-specversion": "1.0"}},
+		},
+		"partitionkey extension": {
+			channel: "unkeyed",
+			message: &provisioners.Message{Headers: map[string]string{"ce-specversion": "1.0", "ce-partitionkey": "customer-42"}},
+			want:    "customer-42",
+		},
+		"partitionkey extension of a keyed channel": {
+			channel: "keyed",
+			message: &provisioners.Message{Headers: map[string]string{"ce-specversion": "1.0", "ce-subject": "order-1234", "ce-partitionkey": "customer-42"}},
+			want:    "order-1234",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := d.partitionKey(provisioners.ChannelReference{Namespace: "test-ns", Name: tc.channel}, tc.message)
+			if got != tc.want {
+				t.Errorf("unexpected partition key, want %q, got %q", tc.want, got)
+			}
+		})
+	}
 }
 
 type dispatchTestHandler struct {
@@ -407,6 +987,207 @@ func TestSubscribe(t *testing.T) {
 
 }
 
+func TestSubscribeAuth(t *testing.T) {
+	sc := &mockSaramaCluster{}
+	d := &KafkaDispatcher{
+		kafkaCluster:   sc,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		dispatcher:     provisioners.NewMessageDispatcher(zap.NewNop().Sugar()),
+		logger:         zap.NewNop(),
+	}
+	WithAuthResolver(auth.NewResolver(func(namespace, name string) (*v1.Secret, error) {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{auth.SecretLabel: "true"}},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		}, nil
+	}))(d)
+	d.setConfig(&multichannelfanout.Config{})
+
+	authorization := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization <- r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	err := d.UpdateConfig(&multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{{
+			Namespace: "test-ns",
+			Name:      "test-channel",
+			FanoutConfig: fanout.Config{
+				Subscriptions: []eventingduck.ChannelSubscriberSpec{{
+					Ref:           &v1.ObjectReference{Namespace: "test-ns", Name: "test-sub"},
+					SubscriberURI: server.URL[7:],
+					Auth: &eventingduck.SubscriberAuth{
+						BearerToken: &v1.SecretKeySelector{
+							LocalObjectReference: v1.LocalObjectReference{Name: "subscriber-token"},
+							Key:                  "token",
+						},
+					},
+				}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	defer close(sc.consumerChannel)
+	sc.consumerChannel <- &sarama.ConsumerMessage{Value: []byte("data")}
+
+	if got := <-authorization; got != "Bearer s3cr3t" {
+		t.Errorf("unexpected Authorization header %q", got)
+	}
+}
+
+func TestSubscribeFilter(t *testing.T) {
+	sc := &mockSaramaCluster{}
+	d := &KafkaDispatcher{
+		kafkaCluster:   sc,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		dispatcher:     provisioners.NewMessageDispatcher(zap.NewNop().Sugar()),
+		logger:         zap.NewNop(),
+	}
+	d.setConfig(&multichannelfanout.Config{})
+
+	types := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		types <- r.Header.Get("ce-type")
+	}))
+	defer server.Close()
+
+	err := d.UpdateConfig(&multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{{
+			Namespace: "test-ns",
+			Name:      "test-channel",
+			FanoutConfig: fanout.Config{
+				Subscriptions: []eventingduck.ChannelSubscriberSpec{{
+					Ref:           &v1.ObjectReference{Namespace: "test-ns", Name: "test-sub"},
+					SubscriberURI: server.URL[7:],
+					Filter:        "type = 'dev.knative.order.created'",
+				}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	defer close(sc.consumerChannel)
+	for _, eventType := range []string{"dev.knative.order.cancelled", "dev.knative.order.created"} {
+		sc.consumerChannel <- &sarama.ConsumerMessage{
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte("ce_specversion"), Value: []byte("1.0")},
+				{Key: []byte("ce_type"), Value: []byte(eventType)},
+			},
+			Value: []byte("data"),
+		}
+	}
+
+	if got := <-types; got != "dev.knative.order.created" {
+		t.Errorf("unexpected event type %q delivered to the filtered subscription", got)
+	}
+}
+
+func TestSubscribeDedup(t *testing.T) {
+	sc := &mockSaramaCluster{}
+	d := &KafkaDispatcher{
+		kafkaCluster:   sc,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		dispatcher:     provisioners.NewMessageDispatcher(zap.NewNop().Sugar()),
+		logger:         zap.NewNop(),
+	}
+	WithDedupWindow(time.Minute, 100)(d)
+
+	ids := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids <- r.Header.Get("ce-id")
+	}))
+	defer server.Close()
+
+	channelRef := provisioners.ChannelReference{
+		Name:      "test-channel",
+		Namespace: "test-ns",
+	}
+	subRef := subscription{
+		Name:          "test-sub",
+		Namespace:     "test-ns",
+		SubscriberURI: server.URL[7:],
+	}
+	if err := d.subscribe(channelRef, subRef); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	defer close(sc.consumerChannel)
+	for _, id := range []string{"1", "1", "2"} {
+		sc.consumerChannel <- &sarama.ConsumerMessage{
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte("ce-specversion"), Value: []byte("1.0")},
+				{Key: []byte("ce-id"), Value: []byte(id)},
+				{Key: []byte("ce-source"), Value: []byte("/orders")},
+			},
+			Value: []byte("data"),
+		}
+	}
+
+	// The redelivered event is skipped, so the second delivery is the event with id 2.
+	for _, want := range []string{"1", "2"} {
+		if got := <-ids; got != want {
+			t.Errorf("unexpected event id, want %s, got %s", want, got)
+		}
+	}
+}
+
+func TestSubscribeNack(t *testing.T) {
+	sc := &mockSaramaCluster{}
+	d := &KafkaDispatcher{
+		kafkaCluster:   sc,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		dispatcher:     provisioners.NewMessageDispatcher(zap.NewNop().Sugar()),
+		logger:         zap.NewNop(),
+	}
+	WithNackRedelivery(2, time.Millisecond)(d)
+
+	// The event with id 1 is nacked until its last redelivery, and the event with id 2 is always
+	// nacked, so it is given up on after its redeliveries.
+	ids := make(chan string, 10)
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("ce-id")
+		ids <- id
+		attempts[id]++
+		if id == "2" || attempts[id] < 3 {
+			w.Header().Set(provisioners.DeliveryResultHeader, provisioners.DeliveryResultNack)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	channelRef := provisioners.ChannelReference{Name: "test-channel", Namespace: "test-ns"}
+	subRef := subscription{Name: "test-sub", Namespace: "test-ns", SubscriberURI: server.URL[7:]}
+	if err := d.subscribe(channelRef, subRef); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	defer close(sc.consumerChannel)
+	for _, id := range []string{"1", "2", "3"} {
+		sc.consumerChannel <- &sarama.ConsumerMessage{
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte("ce-specversion"), Value: []byte("1.0")},
+				{Key: []byte("ce-id"), Value: []byte(id)},
+				{Key: []byte("ce-source"), Value: []byte("/orders")},
+			},
+			Value: []byte("data"),
+		}
+	}
+
+	for _, want := range []string{"1", "1", "1", "2", "2", "2", "3"} {
+		select {
+		case got := <-ids:
+			if got != want {
+				t.Errorf("unexpected event id, want %s, got %s", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %s was not delivered", want)
+		}
+	}
+}
+
 func TestSubscribeError(t *testing.T) {
 	sc := &mockSaramaCluster{
 		createErr: true,
@@ -455,6 +1236,26 @@ func TestUnsubscribeUnknownSub(t *testing.T) {
 	}
 }
 
+func TestCloseConsumers(t *testing.T) {
+	sc := &mockSaramaCluster{closed: true}
+	d := &KafkaDispatcher{
+		kafkaCluster:   sc,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		logger:         zap.NewNop(),
+	}
+	channelRef := provisioners.ChannelReference{Name: "test-channel", Namespace: "test-ns"}
+	for _, name := range []string{"test-sub-1", "test-sub-2"} {
+		if err := d.subscribe(channelRef, subscription{Name: name, Namespace: "test-ns"}); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+	}
+
+	d.closeConsumers()
+	if n := len(d.kafkaConsumers[channelRef]); n != 0 {
+		t.Errorf("Expected the consumers to be closed. Got %d consumers", n)
+	}
+}
+
 func TestKafkaDispatcher_Start(t *testing.T) {
 	d := &KafkaDispatcher{}
 	err := d.Start(make(chan struct{}))
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/kafka/controller"
+	topicUtils "github.com/knative/eventing/pkg/provisioners/utils"
+)
+
+// consumerLagDesc is named and labeled like the lag exported by kafka_exporter, so that the
+// autoscalers and dashboards built for it, such as the KEDA Prometheus scaler, work unchanged.
+var consumerLagDesc = prometheus.NewDesc(
+	"kafka_consumergroup_lag",
+	"The number of events of a partition not dispatched to a subscription yet, by consumer group, topic and partition, from the offsets committed to the brokers.",
+	[]string{"consumergroup", "topic", "partition"}, nil)
+
+// PartitionLag is the lag of the consumer group of a subscription in a partition of the topic of
+// its channel.
+type PartitionLag struct {
+	ConsumerGroup string
+	Topic         string
+	Partition     int32
+	Lag           int64
+}
+
+// ConsumerLag returns the lag of the consumer groups of the subscriptions that are not paused, in
+// every partition of the topics of their channels. Unlike Backlog, it is computed from the offsets
+// committed to the brokers rather than from the partitions consumed by this replica, so every
+// replica reports the lag of the whole consumer group, and the lag does not move between replicas
+// when the partitions are rebalanced. The subscriptions whose offsets cannot be fetched are
+// omitted.
+func (d *KafkaDispatcher) ConsumerLag() []PartitionLag {
+	type subscriptionRef struct {
+		channel provisioners.ChannelReference
+		spec    eventingduck.ChannelSubscriberSpec
+	}
+	// The offsets are fetched without holding updateLock, so that a slow broker does not hold
+	// the updates of the config.
+	var subs []subscriptionRef
+	d.updateLock.Lock()
+	for _, cc := range d.getConfig().ChannelConfigs {
+		channelRef := provisioners.ChannelReference{Namespace: cc.Namespace, Name: cc.Name}
+		for _, spec := range cc.FanoutConfig.Subscriptions {
+			// Paused subscriptions have no consumer to scale.
+			if spec.Ref == nil || spec.Paused {
+				continue
+			}
+			subs = append(subs, subscriptionRef{channel: channelRef, spec: spec})
+		}
+	}
+	d.updateLock.Unlock()
+
+	client, err := d.client()
+	if err != nil {
+		d.logger.Warn("Unable to fetch the consumer lag", zap.Error(err))
+		return nil
+	}
+	var lag []PartitionLag
+	for _, sub := range subs {
+		offsets, err := subscriptionOffsets(client, sub.channel, sub.spec)
+		if err != nil {
+			d.logger.Warn("Unable to fetch the consumer lag of subscription", zap.String("namespace", sub.spec.Ref.Namespace), zap.String("name", sub.spec.Ref.Name), zap.Error(err))
+			continue
+		}
+		group := consumerGroup(sub.spec.Ref.Namespace, sub.spec.Ref.Name)
+		topic := topicUtils.TopicName(controller.KafkaChannelSeparator, sub.channel.Namespace, sub.channel.Name)
+		for _, p := range offsets.Partitions {
+			lag = append(lag, PartitionLag{ConsumerGroup: group, Topic: topic, Partition: p.Partition, Lag: p.Lag})
+		}
+	}
+	return lag
+}
+
+// consumerLagCollector exports the ConsumerLag of a KafkaDispatcher as a gauge, fetched from the
+// brokers when the metrics are scraped.
+type consumerLagCollector struct {
+	dispatcher *KafkaDispatcher
+}
+
+func (c *consumerLagCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- consumerLagDesc
+}
+
+func (c *consumerLagCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, l := range c.dispatcher.ConsumerLag() {
+		ch <- prometheus.MustNewConstMetric(consumerLagDesc, prometheus.GaugeValue, float64(l.Lag), l.ConsumerGroup, l.Topic, strconv.Itoa(int(l.Partition)))
+	}
+}
+
+// RegisterConsumerLag exports the ConsumerLag of d in the kafka_consumergroup_lag metric, for the
+// autoscalers scaling the dispatcher with the backlog of its consumer groups.
+func RegisterConsumerLag(d *KafkaDispatcher) error {
+	return prometheus.Register(&consumerLagCollector{dispatcher: d})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestConsumerLag(t *testing.T) {
+	d, broker := newOffsetsDispatcher(t, false)
+	defer broker.Close()
+
+	// Partition 0 has 5 events after the committed offset 5, and the consumer group did not
+	// commit any offset of partition 1 yet.
+	want := []PartitionLag{
+		{ConsumerGroup: offsetsGroup, Topic: offsetsTopic, Partition: 0, Lag: 5},
+		{ConsumerGroup: offsetsGroup, Topic: offsetsTopic, Partition: 1, Lag: 0},
+	}
+	if diff := cmp.Diff(want, d.ConsumerLag()); diff != "" {
+		t.Errorf("unexpected consumer lag (-want, +got) = %v", diff)
+	}
+}
+
+func TestConsumerLagPaused(t *testing.T) {
+	d, broker := newOffsetsDispatcher(t, true)
+	defer broker.Close()
+
+	if lag := d.ConsumerLag(); len(lag) != 0 {
+		t.Errorf("Expected no lag for a paused subscription. Got %v", lag)
+	}
+}
+
+func TestConsumerLagCollector(t *testing.T) {
+	d, broker := newOffsetsDispatcher(t, false)
+	defer broker.Close()
+
+	ch := make(chan prometheus.Metric, 2)
+	(&consumerLagCollector{dispatcher: d}).Collect(ch)
+	close(ch)
+	if len(ch) != 2 {
+		t.Fatalf("Expected the lag of each partition. Got %d metrics", len(ch))
+	}
+	m := &dto.Metric{}
+	if err := (<-ch).Write(m); err != nil {
+		t.Fatalf("Unable to read the metric: %v", err)
+	}
+	if got := m.GetGauge().GetValue(); got != 5 {
+		t.Errorf("Unexpected lag. Expected 5. Actual %v", got)
+	}
+	labels := make(map[string]string)
+	for _, l := range m.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	want := map[string]string{"consumergroup": offsetsGroup, "topic": offsetsTopic, "partition": "0"}
+	if diff := cmp.Diff(want, labels); diff != "" {
+		t.Errorf("unexpected labels (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,376 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing"
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/kafka/controller"
+	topicUtils "github.com/knative/eventing/pkg/provisioners/utils"
+)
+
+const (
+	// OffsetsPortEnv enables the offsets API of the dispatcher. Its value is the port it is served
+	// on.
+	OffsetsPortEnv = "OFFSETS_PORT"
+
+	// maxOffsetTargetSize is the largest body accepted by a reset of the offsets.
+	maxOffsetTargetSize = 1024
+)
+
+var (
+	errSubscriptionNotFound  = errors.New("no such subscription")
+	errSubscriptionNotPaused = errors.New("the subscription must be paused to reset its offsets")
+	errInvalidOffsetTarget   = errors.New("exactly one of timestamp and offset must be set")
+)
+
+// PartitionOffsets are the offsets of the consumer group of a subscription in a partition of the
+// topic of its channel.
+type PartitionOffsets struct {
+	Partition int32 `json:"partition"`
+	// Committed is the offset of the next event dispatched to the subscription, or -1 if its
+	// consumer group did not commit any offset in the partition yet.
+	Committed int64 `json:"committed"`
+	// Newest is the offset of the next event written to the partition.
+	Newest int64 `json:"newest"`
+	// Lag is the number of events of the partition not dispatched to the subscription yet.
+	Lag int64 `json:"lag"`
+}
+
+// SubscriptionOffsets are the offsets of the consumer group of a subscription.
+type SubscriptionOffsets struct {
+	// Channel is the namespace/name of the channel of the subscription.
+	Channel    string             `json:"channel"`
+	Paused     bool               `json:"paused,omitempty"`
+	Partitions []PartitionOffsets `json:"partitions"`
+}
+
+// OffsetTarget is where the offsets of a subscription are reset to, for the events to be
+// dispatched again or skipped. Exactly one of its fields is set.
+type OffsetTarget struct {
+	// Timestamp resets each partition to its first event written at or after Timestamp, or to its
+	// end if there is none.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// Offset resets each partition to Offset, within the events retained by the partition.
+	Offset *int64 `json:"offset,omitempty"`
+}
+
+// Offsets returns the committed offsets of the subscription name in namespace.
+func (d *KafkaDispatcher) Offsets(namespace, name string) (*SubscriptionOffsets, error) {
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+	channelRef, spec, ok := d.findSubscription(namespace, name)
+	if !ok {
+		return nil, errSubscriptionNotFound
+	}
+	client, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionOffsets(client, channelRef, spec)
+}
+
+// ResetOffsets commits the offsets of target for the subscription name in namespace, and returns
+// them. The subscription must be paused, since the members of its consumer group would overwrite
+// the offsets with their own commits, and its consumers resume from the reset offsets once it is
+// resumed.
+func (d *KafkaDispatcher) ResetOffsets(namespace, name string, target OffsetTarget) (*SubscriptionOffsets, error) {
+	if (target.Timestamp == nil) == (target.Offset == nil) {
+		return nil, errInvalidOffsetTarget
+	}
+	d.updateLock.Lock()
+	defer d.updateLock.Unlock()
+	channelRef, spec, ok := d.findSubscription(namespace, name)
+	if !ok {
+		return nil, errSubscriptionNotFound
+	}
+	if !spec.Paused {
+		return nil, errSubscriptionNotPaused
+	}
+	client, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+
+	topic := topicUtils.TopicName(controller.KafkaChannelSeparator, channelRef.Namespace, channelRef.Name)
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the partitions of topic %s: %v", topic, err)
+	}
+	group := consumerGroup(namespace, name)
+	req := &sarama.OffsetCommitRequest{
+		Version:                 1,
+		ConsumerGroup:           group,
+		ConsumerGroupGeneration: sarama.GroupGenerationUndefined,
+	}
+	for _, partition := range partitions {
+		offset, err := targetOffset(client, topic, partition, target)
+		if err != nil {
+			return nil, err
+		}
+		req.AddBlock(topic, partition, offset, sarama.ReceiveTime, "")
+	}
+	coordinator, err := client.Coordinator(group)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the coordinator of consumer group %s: %v", group, err)
+	}
+	resp, err := coordinator.CommitOffset(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to commit the offsets of consumer group %s: %v", group, err)
+	}
+	for partition, kerr := range resp.Errors[topic] {
+		if kerr != sarama.ErrNoError {
+			return nil, fmt.Errorf("unable to commit the offset of partition %d of consumer group %s: %v", partition, group, kerr)
+		}
+	}
+	d.logger.Info("Reset the offsets of subscription", zap.String("namespace", namespace), zap.String("name", name), zap.Any("target", target))
+	return subscriptionOffsets(client, channelRef, spec)
+}
+
+// targetOffset returns the offset target resets partition of topic to.
+func targetOffset(client sarama.Client, topic string, partition int32, target OffsetTarget) (int64, error) {
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the newest offset of partition %d of topic %s: %v", partition, topic, err)
+	}
+	if target.Timestamp != nil {
+		offset, err := client.GetOffset(topic, partition, target.Timestamp.UnixNano()/int64(time.Millisecond))
+		if err != nil {
+			return 0, fmt.Errorf("unable to get the offset at %v of partition %d of topic %s: %v", target.Timestamp, partition, topic, err)
+		}
+		if offset < 0 {
+			// No event was written at or after the timestamp.
+			return newest, nil
+		}
+		return offset, nil
+	}
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the oldest offset of partition %d of topic %s: %v", partition, topic, err)
+	}
+	switch offset := *target.Offset; {
+	case offset < oldest:
+		return oldest, nil
+	case offset > newest:
+		return newest, nil
+	default:
+		return offset, nil
+	}
+}
+
+// subscriptionOffsets fetches the committed offsets of the consumer group of the subscription spec
+// of channelRef, and the newest offsets of the partitions of its topic.
+func subscriptionOffsets(client sarama.Client, channelRef provisioners.ChannelReference, spec eventingduck.ChannelSubscriberSpec) (*SubscriptionOffsets, error) {
+	topic := topicUtils.TopicName(controller.KafkaChannelSeparator, channelRef.Namespace, channelRef.Name)
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the partitions of topic %s: %v", topic, err)
+	}
+	group := consumerGroup(spec.Ref.Namespace, spec.Ref.Name)
+	coordinator, err := client.Coordinator(group)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the coordinator of consumer group %s: %v", group, err)
+	}
+	req := &sarama.OffsetFetchRequest{Version: 1, ConsumerGroup: group}
+	for _, partition := range partitions {
+		req.AddPartition(topic, partition)
+	}
+	resp, err := coordinator.FetchOffset(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the offsets of consumer group %s: %v", group, err)
+	}
+
+	offsets := &SubscriptionOffsets{
+		Channel:    channelRef.String(),
+		Paused:     spec.Paused,
+		Partitions: make([]PartitionOffsets, 0, len(partitions)),
+	}
+	for _, partition := range partitions {
+		block := resp.GetBlock(topic, partition)
+		if block == nil {
+			// The coordinator omits the partitions the consumer group never committed.
+			block = &sarama.OffsetFetchResponseBlock{Offset: -1}
+		} else if block.Err != sarama.ErrNoError {
+			return nil, fmt.Errorf("unable to fetch the offset of partition %d of consumer group %s: %v", partition, group, block.Err)
+		}
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the newest offset of partition %d of topic %s: %v", partition, topic, err)
+		}
+		p := PartitionOffsets{Partition: partition, Committed: block.Offset, Newest: newest}
+		if block.Offset >= 0 && newest > block.Offset {
+			p.Lag = newest - block.Offset
+		}
+		offsets.Partitions = append(offsets.Partitions, p)
+	}
+	return offsets, nil
+}
+
+// findSubscription returns the subscription name in namespace and its channel, from the config of
+// the dispatcher. It must be called with updateLock held.
+func (d *KafkaDispatcher) findSubscription(namespace, name string) (provisioners.ChannelReference, eventingduck.ChannelSubscriberSpec, bool) {
+	for _, cc := range d.getConfig().ChannelConfigs {
+		for _, spec := range cc.FanoutConfig.Subscriptions {
+			if spec.Ref != nil && spec.Ref.Namespace == namespace && spec.Ref.Name == name {
+				return provisioners.ChannelReference{Namespace: cc.Namespace, Name: cc.Name}, spec, true
+			}
+		}
+	}
+	return provisioners.ChannelReference{}, eventingduck.ChannelSubscriberSpec{}, false
+}
+
+// client returns the current client of the brokers. It may be replaced when the credentials are
+// rotated.
+func (d *KafkaDispatcher) client() (sarama.Client, error) {
+	d.producerLock.RLock()
+	defer d.producerLock.RUnlock()
+	if d.kafkaClient == nil {
+		return nil, errors.New("kafka client is not set")
+	}
+	return d.kafkaClient, nil
+}
+
+// OffsetsHandler serves the offsets of the subscriptions of a KafkaDispatcher at
+// /subscriptions/<namespace>/<name>/offsets. GET returns the committed offsets of the subscription,
+// and PUT resets them to the OffsetTarget in the body of the request.
+type OffsetsHandler struct {
+	dispatcher *KafkaDispatcher
+	authorize  authz.Authorizer
+	logger     *zap.Logger
+}
+
+// NewOffsetsHandler creates an OffsetsHandler of d serving the requests allowed by authorize to get
+// or update the offsets subresource of the Subscription.
+func NewOffsetsHandler(d *KafkaDispatcher, authorize authz.Authorizer, logger *zap.Logger) *OffsetsHandler {
+	return &OffsetsHandler{dispatcher: d, authorize: authorize, logger: logger}
+}
+
+func (h *OffsetsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var verb string
+	switch req.Method {
+	case http.MethodGet:
+		verb = "get"
+	case http.MethodPut:
+		verb = "update"
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "subscriptions" || parts[1] == "" || parts[2] == "" || parts[3] != "offsets" {
+		http.Error(w, "the path must be /subscriptions/<namespace>/<name>/offsets", http.StatusNotFound)
+		return
+	}
+	subscription := types.NamespacedName{Namespace: parts[1], Name: parts[2]}
+
+	err := h.authorize(req, authorizationv1.ResourceAttributes{
+		Namespace:   subscription.Namespace,
+		Verb:        verb,
+		Group:       eventing.GroupName,
+		Resource:    "subscriptions",
+		Subresource: "offsets",
+		Name:        subscription.Name,
+	})
+	switch err {
+	case nil:
+	case authz.ErrUnauthenticated:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case authz.ErrForbidden:
+		http.Error(w, fmt.Sprintf("not allowed to %s the offsets of subscription %s", verb, subscription.String()), http.StatusForbidden)
+		return
+	default:
+		h.logger.Error("Unable to authorize the offsets request", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var offsets *SubscriptionOffsets
+	if req.Method == http.MethodGet {
+		offsets, err = h.dispatcher.Offsets(subscription.Namespace, subscription.Name)
+	} else {
+		var target OffsetTarget
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxOffsetTargetSize)).Decode(&target); err != nil {
+			http.Error(w, fmt.Sprintf("invalid offset target: %v", err), http.StatusBadRequest)
+			return
+		}
+		offsets, err = h.dispatcher.ResetOffsets(subscription.Namespace, subscription.Name, target)
+	}
+	switch err {
+	case nil:
+	case errSubscriptionNotFound:
+		http.Error(w, fmt.Sprintf("no subscription %s in this dispatcher", subscription.String()), http.StatusNotFound)
+		return
+	case errInvalidOffsetTarget:
+		http.Error(w, fmt.Sprintf("invalid offset target: %v", err), http.StatusBadRequest)
+		return
+	case errSubscriptionNotPaused:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	default:
+		h.logger.Error("Unable to serve the offsets of subscription", zap.String("subscription", subscription.String()), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offsets)
+}
+
+// ServeOffsetsFromEnv serves the offsets API of d in the background, until stopCh is closed, if
+// the environment variables of the process enable it.
+func ServeOffsetsFromEnv(d *KafkaDispatcher, kc kubernetes.Interface, logger *zap.Logger, stopCh <-chan struct{}) error {
+	port := os.Getenv(OffsetsPortEnv)
+	if port == "" {
+		return nil
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return fmt.Errorf("invalid %s %q: %v", OffsetsPortEnv, port, err)
+	}
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: NewOffsetsHandler(d, authz.KubeAuthorizer(kc), logger),
+	}
+	go func() {
+		<-stopCh
+		srv.Shutdown(context.Background())
+	}()
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			logger.Error("Unable to serve the offsets API", zap.Error(err))
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/core/v1"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/sidecar/fanout"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+)
+
+const (
+	offsetsTopic = "knative-eventing-channel.test-ns.test-channel"
+	offsetsGroup = "kafka.test-ns.test-sub"
+)
+
+var offsetsTimestamp = time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newOffsetsDispatcher returns a dispatcher of the subscription test-ns/test-sub, paused or not,
+// whose client is connected to a mock broker. The topic of its channel has two partitions: the
+// consumer group committed offset 5 of partition 0, whose events are at offsets 2 to 9, and no
+// offset of partition 1, whose events are at offsets 0 to 2.
+func newOffsetsDispatcher(t *testing.T, paused bool) (*KafkaDispatcher, *sarama.MockBroker) {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(offsetsTopic, 0, broker.BrokerID()).
+			SetLeader(offsetsTopic, 1, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, offsetsGroup, broker),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(offsetsGroup, offsetsTopic, 0, 5, "", sarama.ErrNoError),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).SetVersion(1).
+			SetOffset(offsetsTopic, 0, sarama.OffsetOldest, 2).
+			SetOffset(offsetsTopic, 0, sarama.OffsetNewest, 10).
+			SetOffset(offsetsTopic, 0, offsetsTimestamp.UnixNano()/int64(time.Millisecond), 7).
+			SetOffset(offsetsTopic, 1, sarama.OffsetOldest, 0).
+			SetOffset(offsetsTopic, 1, sarama.OffsetNewest, 3).
+			SetOffset(offsetsTopic, 1, offsetsTimestamp.UnixNano()/int64(time.Millisecond), -1),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+	})
+	conf := sarama.NewConfig()
+	conf.Version = sarama.V1_1_0_0
+	client, err := sarama.NewClient([]string{broker.Addr()}, conf)
+	if err != nil {
+		t.Fatalf("Unable to create the kafka client: %v", err)
+	}
+
+	d := &KafkaDispatcher{
+		kafkaClient:    client,
+		kafkaConsumers: make(map[provisioners.ChannelReference]map[subscription]KafkaConsumer),
+		logger:         zap.NewNop(),
+	}
+	d.setConfig(&multichannelfanout.Config{
+		ChannelConfigs: []multichannelfanout.ChannelConfig{
+			{
+				Namespace: "test-ns",
+				Name:      "test-channel",
+				FanoutConfig: fanout.Config{
+					Subscriptions: []eventingduck.ChannelSubscriberSpec{
+						{
+							Ref:           &v1.ObjectReference{Name: "test-sub", Namespace: "test-ns"},
+							SubscriberURI: "subscriber",
+							Paused:        paused,
+						},
+					},
+				},
+			},
+		},
+	})
+	return d, broker
+}
+
+func TestOffsets(t *testing.T) {
+	d, broker := newOffsetsDispatcher(t, false)
+	defer broker.Close()
+	defer d.kafkaClient.Close()
+
+	got, err := d.Offsets("test-ns", "test-sub")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := &SubscriptionOffsets{
+		Channel: "test-ns/test-channel",
+		Partitions: []PartitionOffsets{
+			{Partition: 0, Committed: 5, Newest: 10, Lag: 5},
+			{Partition: 1, Committed: -1, Newest: 3},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected offsets (-want, +got) = %v", diff)
+	}
+
+	if _, err := d.Offsets("test-ns", "other-sub"); err != errSubscriptionNotFound {
+		t.Errorf("Expected %v for an unknown subscription. Actual %v", errSubscriptionNotFound, err)
+	}
+}
+
+func TestResetOffsets(t *testing.T) {
+	timestamp := offsetsTimestamp
+	before, after := int64(1), int64(12)
+	testCases := map[string]struct {
+		paused  bool
+		target  OffsetTarget
+		want    map[int32]int64
+		wantErr error
+	}{
+		"timestamp": {
+			paused: true,
+			target: OffsetTarget{Timestamp: &timestamp},
+			// Partition 1 has no event written after the timestamp.
+			want: map[int32]int64{0: 7, 1: 3},
+		},
+		"offset": {
+			paused: true,
+			target: OffsetTarget{Offset: &before},
+			want:   map[int32]int64{0: 2, 1: 1},
+		},
+		"offset after the newest": {
+			paused: true,
+			target: OffsetTarget{Offset: &after},
+			want:   map[int32]int64{0: 10, 1: 3},
+		},
+		"no target": {
+			paused:  true,
+			wantErr: errInvalidOffsetTarget,
+		},
+		"both targets": {
+			paused:  true,
+			target:  OffsetTarget{Timestamp: &timestamp, Offset: &before},
+			wantErr: errInvalidOffsetTarget,
+		},
+		"not paused": {
+			target:  OffsetTarget{Offset: &before},
+			wantErr: errSubscriptionNotPaused,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			d, broker := newOffsetsDispatcher(t, tc.paused)
+			defer broker.Close()
+			defer d.kafkaClient.Close()
+
+			_, err := d.ResetOffsets("test-ns", "test-sub", tc.target)
+			if err != tc.wantErr {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+
+			var commits []*sarama.OffsetCommitRequest
+			for _, rr := range broker.History() {
+				if req, ok := rr.Request.(*sarama.OffsetCommitRequest); ok {
+					commits = append(commits, req)
+				}
+			}
+			if tc.want == nil {
+				if len(commits) != 0 {
+					t.Errorf("Expected no offset to be committed. Actual %d commits", len(commits))
+				}
+				return
+			}
+			if len(commits) != 1 {
+				t.Fatalf("Expected the offsets to be committed once. Actual %d commits", len(commits))
+			}
+			if commits[0].ConsumerGroup != offsetsGroup {
+				t.Errorf("Unexpected consumer group. Expected %q. Actual %q", offsetsGroup, commits[0].ConsumerGroup)
+			}
+			got := make(map[int32]int64)
+			for partition := range tc.want {
+				offset, _, err := commits[0].Offset(offsetsTopic, partition)
+				if err != nil {
+					t.Fatalf("Expected the offset of partition %d to be committed: %v", partition, err)
+				}
+				got[partition] = offset
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected committed offsets (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestOffsetsHandler(t *testing.T) {
+	testCases := map[string]struct {
+		method   string
+		path     string
+		body     string
+		paused   bool
+		authErr  error
+		wantCode int
+		wantVerb string
+		wantBody string
+	}{
+		"not a GET or PUT": {
+			method:   http.MethodPost,
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		"invalid path": {
+			path:     "/subscriptions/test-ns/test-sub",
+			wantCode: http.StatusNotFound,
+		},
+		"unauthenticated": {
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			authErr:  authz.ErrUnauthenticated,
+			wantCode: http.StatusUnauthorized,
+			wantVerb: "get",
+		},
+		"forbidden": {
+			method:   http.MethodPut,
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			authErr:  authz.ErrForbidden,
+			wantCode: http.StatusForbidden,
+			wantVerb: "update",
+		},
+		"unknown subscription": {
+			path:     "/subscriptions/test-ns/other-sub/offsets",
+			wantCode: http.StatusNotFound,
+			wantVerb: "get",
+		},
+		"get": {
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			wantCode: http.StatusOK,
+			wantVerb: "get",
+			wantBody: `"partitions":[{"partition":0,"committed":5,"newest":10,"lag":5},{"partition":1,"committed":-1,"newest":3,"lag":0}]`,
+		},
+		"reset": {
+			method:   http.MethodPut,
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			body:     `{"timestamp": "2019-03-01T12:00:00Z"}`,
+			paused:   true,
+			wantCode: http.StatusOK,
+			wantVerb: "update",
+			wantBody: `"paused":true`,
+		},
+		"reset without target": {
+			method:   http.MethodPut,
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			body:     `{}`,
+			paused:   true,
+			wantCode: http.StatusBadRequest,
+			wantVerb: "update",
+		},
+		"reset with invalid target": {
+			method:   http.MethodPut,
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			body:     `{"offset": "first"}`,
+			paused:   true,
+			wantCode: http.StatusBadRequest,
+			wantVerb: "update",
+		},
+		"reset while not paused": {
+			method:   http.MethodPut,
+			path:     "/subscriptions/test-ns/test-sub/offsets",
+			body:     `{"offset": 0}`,
+			wantCode: http.StatusConflict,
+			wantVerb: "update",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			d, broker := newOffsetsDispatcher(t, tc.paused)
+			defer broker.Close()
+			defer d.kafkaClient.Close()
+
+			var verb string
+			h := NewOffsetsHandler(d, func(_ *http.Request, attrs authorizationv1.ResourceAttributes) error {
+				verb = attrs.Verb
+				return tc.authErr
+			}, zap.NewNop())
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(method, tc.path, strings.NewReader(tc.body)))
+			if w.Code != tc.wantCode {
+				t.Fatalf("Unexpected status code. Expected %v. Actual %v: %s", tc.wantCode, w.Code, w.Body.String())
+			}
+			if verb != tc.wantVerb {
+				t.Errorf("Unexpected verb authorized. Expected %q. Actual %q", tc.wantVerb, verb)
+			}
+			if tc.wantBody != "" {
+				if !strings.Contains(w.Body.String(), tc.wantBody) {
+					t.Errorf("Expected the body to contain %s. Actual %s", tc.wantBody, w.Body.String())
+				}
+				var offsets SubscriptionOffsets
+				if err := json.Unmarshal(w.Body.Bytes(), &offsets); err != nil {
+					t.Errorf("Unexpected body: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -2,13 +2,13 @@ package main
 
 import (
 	"flag"
-	"fmt"
+	"log"
 	"os"
-	"strings"
 
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -17,31 +17,40 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 
 	eventingv1alpha "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/provisioners"
 	provisionerController "github.com/knative/eventing/pkg/provisioners/kafka/controller"
 	"github.com/knative/eventing/pkg/provisioners/kafka/controller/channel"
-	"github.com/knative/pkg/configmap"
-)
-
-const (
-	BrokerConfigMapKey = "bootstrap_servers"
+	"github.com/knative/eventing/pkg/system"
 )
 
 // SchemeFunc adds types to a Scheme.
 type SchemeFunc func(*runtime.Scheme) error
 
 // ProvideFunc adds a controller to a Manager.
-type ProvideFunc func(mgr manager.Manager, config *provisionerController.KafkaProvisionerConfig, logger *zap.Logger) (controller.Controller, error)
+type ProvideFunc func(mgr manager.Manager, configs *provisionerController.ConfigStore, logger *zap.Logger) (controller.Controller, error)
 
 func main() {
 	flag.Parse()
 	logf.SetLogger(logf.ZapLogger(false))
 
-	logger := provisioners.NewProvisionerLoggerFromConfig(provisioners.NewLoggingConfig())
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	cfg := config.GetConfigOrDie()
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("unable to create the kubernetes client: %v", err)
+	}
+
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.KafkaController, stopCh)
 	defer logger.Sync()
 
 	// Setup a Manager
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	mgr, err := namespaced.NewManager(cfg, manager.Options{}, namespaced.NamespacesFromEnv())
 	if err != nil {
 		logger.Error(err, "unable to run controller manager")
 		os.Exit(1)
@@ -51,6 +60,7 @@ func main() {
 	schemeFuncs := []SchemeFunc{
 		eventingv1alpha.AddToScheme,
 		istiov1alpha3.AddToScheme,
+		securityv1beta1.AddToScheme,
 	}
 	for _, schemeFunc := range schemeFuncs {
 		schemeFunc(mgr.GetScheme())
@@ -64,7 +74,7 @@ func main() {
 	}
 
 	// TODO the underlying config map needs to be watched and the config should be reloaded if there is a change.
-	provisionerConfig, err := getProvisionerConfig()
+	provisionerConfig, err := provisionerController.GetProvisionerConfig("/etc/config-provisioner")
 
 	if err != nil {
 		logger.Error(err, "unable to run controller manager")
@@ -72,35 +82,27 @@ func main() {
 	}
 
 	for _, provider := range providers {
-		if _, err := provider(mgr, provisionerConfig, logger.Desugar()); err != nil {
+		if _, err := provider(mgr, provisionerController.NewConfigStore(provisionerConfig), logger.Desugar()); err != nil {
 			logger.Error(err, "unable to run controller manager")
 			os.Exit(1)
 		}
 	}
 
-	mgr.Start(signals.SetupSignalHandler())
-}
-
-// getProvisionerConfig returns the details of the associated Provisioner/ClusterChannelProvisioner object
-func getProvisionerConfig() (*provisionerController.KafkaProvisionerConfig, error) {
-	configMap, err := configmap.Load("/etc/config-provisioner")
-	if err != nil {
-		return nil, fmt.Errorf("error loading provisioner configuration: %s", err)
-	}
-
-	if len(configMap) == 0 {
-		return nil, fmt.Errorf("missing provisioner configuration")
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		go func() {
+			if err := provisioners.ServeMetrics(":"+port, stopCh); err != nil {
+				logger.Error("Unable to serve the metrics", zap.Error(err))
+			}
+		}()
 	}
 
-	config := &provisionerController.KafkaProvisionerConfig{}
-
-	if value, ok := configMap[BrokerConfigMapKey]; ok {
-		bootstrapServers := strings.Split(value, ",")
-		if len(bootstrapServers) != 0 {
-			config.Brokers = bootstrapServers
-			return config, nil
-		}
+	if addr := debug.Addr(); addr != "" {
+		go func() {
+			if err := debug.Serve(addr, stopCh); err != nil {
+				logger.Error("Unable to serve the debug endpoints", zap.Error(err))
+			}
+		}()
 	}
 
-	return nil, fmt.Errorf("missing key %s in provisioner configuration", BrokerConfigMapKey)
+	mgr.Start(stopCh)
 }
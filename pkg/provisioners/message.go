@@ -18,12 +18,15 @@ package provisioners
 
 import (
 	"errors"
+	"io"
 )
 
 var forwardHeaders = []string{
 	"content-type",
 	// tracing
 	"x-request-id",
+	"traceparent",
+	"tracestate",
 }
 
 var forwardPrefixes = []string{
@@ -49,6 +52,11 @@ type Message struct {
 	// Payload is the raw binary content of the message. The payload format is
 	// often described by the 'content-type' header.
 	Payload []byte
+
+	// body, if set, streams the content of the message instead of Payload, see
+	// WithBodyStreaming. bodySize is its length, or 0 or less if it is unknown.
+	body     io.Reader
+	bodySize int64
 }
 
 // ErrUnknownChannel is returned when a message is received by a channel dispatcher for a
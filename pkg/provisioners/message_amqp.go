@@ -0,0 +1,209 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pack.ag/amqp"
+)
+
+const (
+	// AMQPCloudEventsPrefix prefixes the names of the CloudEvents attributes in the application
+	// properties, per the CloudEvents AMQP binding.
+	AMQPCloudEventsPrefix = "cloudEvents:"
+
+	// amqpIdleTimeout is how long the sender to an AMQP destination is kept connected without
+	// deliveries.
+	amqpIdleTimeout = 5 * time.Minute
+
+	// amqpTimeout bounds connecting to an AMQP destination and each delivery to it.
+	amqpTimeout = 30 * time.Second
+)
+
+// amqpSenderKey identifies the sender to an AMQP destination: the container it connects to, the
+// node it sends to, its credentials and the TLS config of the connection if it is secure.
+type amqpSenderKey struct {
+	addr     string
+	address  string
+	username string
+	password string
+	secure   bool
+	config   *tls.Config
+}
+
+// amqpSender is a pooled sender to an AMQP destination.
+type amqpSender struct {
+	client *amqp.Client
+	sender *amqp.Sender
+	// failed is set once a delivery failed other than by being rejected, as the link or the
+	// connection of sender may be closed.
+	failed bool
+	// active is the number of deliveries using sender, and lastUsed the time the last one started.
+	active   int
+	lastUsed time.Time
+}
+
+// dialAMQP connects the sender described by key.
+func dialAMQP(key amqpSenderKey) (*amqpSender, error) {
+	scheme := "amqp"
+	opts := []amqp.ConnOption{amqp.ConnConnectTimeout(amqpTimeout)}
+	if key.secure {
+		scheme = "amqps"
+		// The client sets the server name of the config it is given.
+		opts = append(opts, amqp.ConnTLSConfig(key.config.Clone()))
+	}
+	if key.username != "" {
+		opts = append(opts, amqp.ConnSASLPlain(key.username, key.password))
+	} else {
+		opts = append(opts, amqp.ConnSASLAnonymous())
+	}
+	client, err := amqp.Dial(scheme+"://"+key.addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	sender, err := session.NewSender(amqp.LinkTargetAddress(key.address))
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &amqpSender{client: client, sender: sender}, nil
+}
+
+// amqpSenderFor returns the sender to the AMQP destination url, for a delivery authenticated by
+// auth, and the func to call with the result of the delivery when it is done. The senders that are
+// no longer used or that failed are closed.
+func (d *MessageDispatcher) amqpSenderFor(url *url.URL, auth Authenticator) (*amqp.Sender, func(error), error) {
+	key := amqpSenderKey{
+		addr:    amqpAddr(url),
+		address: strings.TrimPrefix(url.Path, "/"),
+		secure:  url.Scheme == "https" || url.Scheme == "amqps",
+	}
+	if key.secure {
+		key.config = d.tlsConfig
+		if ta, ok := auth.(TLSAuthenticator); ok && ta.TLSConfig() != nil {
+			key.config = ta.TLSConfig()
+		}
+		if key.config == nil {
+			key.config = &tls.Config{ServerName: url.Hostname()}
+		}
+	}
+	if auth != nil {
+		// Authenticators add their credentials to HTTP requests, whose basic auth is used for SASL
+		// PLAIN.
+		req, err := http.NewRequest(http.MethodPost, url.String(), nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create request %v", err)
+		}
+		if err := auth.Authenticate(req); err != nil {
+			return nil, nil, fmt.Errorf("unable to authenticate request %v", err)
+		}
+		key.username, key.password, _ = req.BasicAuth()
+	}
+
+	now := time.Now()
+	d.amqpSendersLock.Lock()
+	defer d.amqpSendersLock.Unlock()
+	for k, s := range d.amqpSenders {
+		if s.active == 0 && (now.Sub(s.lastUsed) > amqpIdleTimeout || s.failed) {
+			s.client.Close()
+			delete(d.amqpSenders, k)
+		}
+	}
+	s, ok := d.amqpSenders[key]
+	if !ok || s.failed {
+		sender, err := dialAMQP(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			// The failed sender is replaced.
+			s.client.Close()
+		}
+		s = sender
+		d.amqpSenders[key] = s
+	}
+	s.active++
+	s.lastUsed = now
+	release := func(err error) {
+		d.amqpSendersLock.Lock()
+		defer d.amqpSendersLock.Unlock()
+		if _, rejected := err.(*amqp.Error); err != nil && !rejected {
+			s.failed = true
+		}
+		s.active--
+	}
+	return s.sender, release, nil
+}
+
+// amqpAddr returns the host and port of url, defaulting to the AMQP port of its scheme.
+func amqpAddr(url *url.URL) string {
+	if url.Port() != "" {
+		return url.Host
+	}
+	if url.Scheme == "https" || url.Scheme == "amqps" {
+		return net.JoinHostPort(url.Hostname(), "5671")
+	}
+	return net.JoinHostPort(url.Hostname(), "5672")
+}
+
+// executeAMQP delivers message to the node of the AMQP container at url, its path without the
+// leading slash, in the binary content mode of the CloudEvents AMQP binding. The connection is
+// secured with TLS if the scheme of url is https or amqps. AMQP destinations do not reply.
+func (d *MessageDispatcher) executeAMQP(url *url.URL, message *Message, auth Authenticator) error {
+	d.logger.Infof("Dispatching message to %s with AMQP", url.String())
+	sender, release, err := d.amqpSenderFor(url, auth)
+	if err != nil {
+		return fmt.Errorf("unable to connect %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), amqpTimeout)
+	defer cancel()
+	err = sender.Send(ctx, message.toAMQPMessage())
+	release(err)
+	return err
+}
+
+// toAMQPMessage returns the event of the message in the binary content mode of the CloudEvents
+// AMQP binding: its attributes are application properties prefixed with AMQPCloudEventsPrefix,
+// except for its data content type which is the content type of the message.
+func (m *Message) toAMQPMessage() *amqp.Message {
+	attrs := m.Attributes()
+	am := amqp.NewMessage(m.Data())
+	if ct := attrs["datacontenttype"]; ct != "" {
+		am.Properties = &amqp.MessageProperties{ContentType: ct}
+	}
+	for _, name := range []string{"datacontenttype", "data_base64"} {
+		delete(attrs, name)
+	}
+	am.ApplicationProperties = make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		am.ApplicationProperties[AMQPCloudEventsPrefix+name] = value
+	}
+	return am
+}
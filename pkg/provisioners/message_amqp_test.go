@@ -0,0 +1,122 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"go.uber.org/zap"
+	"pack.ag/amqp"
+)
+
+func TestToAMQPMessage(t *testing.T) {
+	testCases := map[string]struct {
+		message *Message
+		want    *amqp.Message
+	}{
+		"binary": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Id":          "1234",
+					"Ce-Source":      "/source",
+					"Ce-Type":        "dev.knative.test",
+					"Ce-Subject":     "subject",
+					"Content-Type":   "application/octet-stream",
+				},
+				Payload: []byte{0xff, 0x00},
+			},
+			want: &amqp.Message{
+				Properties: &amqp.MessageProperties{ContentType: "application/octet-stream"},
+				ApplicationProperties: map[string]interface{}{
+					"cloudEvents:specversion": "1.0",
+					"cloudEvents:id":          "1234",
+					"cloudEvents:source":      "/source",
+					"cloudEvents:type":        "dev.knative.test",
+					"cloudEvents:subject":     "subject",
+					"cloudEvents:knativettl":  "254",
+				},
+				Data: [][]byte{{0xff, 0x00}},
+			},
+		},
+		"structured": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","source":"/source","type":"dev.knative.test","datacontenttype":"application/json","data":{"total":10}}`),
+			},
+			want: &amqp.Message{
+				Properties: &amqp.MessageProperties{ContentType: "application/json"},
+				ApplicationProperties: map[string]interface{}{
+					"cloudEvents:specversion": "1.0",
+					"cloudEvents:id":          "1234",
+					"cloudEvents:source":      "/source",
+					"cloudEvents:type":        "dev.knative.test",
+					"cloudEvents:knativettl":  "254",
+				},
+				Data: [][]byte{[]byte(`{"total":10}`)},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			// The dispatcher adds the TTL extension.
+			got := tc.message.withExtension(TTLExtension, "254").toAMQPMessage()
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreUnexported(amqp.Message{})); diff != "" {
+				t.Errorf("Unexpected AMQP message (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageAMQPOnError(t *testing.T) {
+	// Nothing listens on the address of a closed listener.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	onErrorHandler := &fakeHandler{t: t}
+	onErrorServer := httptest.NewServer(onErrorHandler)
+	defer onErrorServer.Close()
+
+	message := &Message{
+		Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234"},
+		Payload: []byte("payload"),
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	destination := "amqp://" + addr + "/orders"
+	if err := md.DispatchMessage(message, destination, "", DispatchDefaults{Protocol: eventingduck.AMQPDeliveryProtocol}); err == nil {
+		t.Errorf("Expected an error from DispatchMessage")
+	}
+
+	defaults := DispatchDefaults{
+		OnError:  getDomain(t, true, onErrorServer.URL),
+		Protocol: eventingduck.AMQPDeliveryProtocol,
+	}
+	if err := md.DispatchMessage(message, destination, "", defaults); err != nil {
+		t.Errorf("Unexpected error from DispatchMessage: %v", err)
+	}
+	onErrorHandler.popRequest(t)
+}
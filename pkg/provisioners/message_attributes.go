@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	cloudEventsHeaderPrefix   = "ce-"
+	cloudEventsExtensionStart = "x-"
+
+	structuredContentType = "application/cloudevents+json"
+)
+
+// legacyAttributeNames maps the context attribute names of CloudEvents 0.1, 0.2 and 0.3 to their
+// 1.0 equivalents, so that filters can be written once regardless of the version the producer
+// used.
+var legacyAttributeNames = map[string]string{
+	"eventtype":          "type",
+	"eventid":            "id",
+	"eventtime":          "time",
+	"cloudeventsversion": "specversion",
+	"contenttype":        "datacontenttype",
+	"schemaurl":          "dataschema",
+}
+
+// Attributes returns the CloudEvents context attributes of the message, keyed by their lower-case
+// name. Both binary (ce- headers) and structured (application/cloudevents+json) content modes are
+// understood. Extensions are included alongside the standard attributes. Attributes that are not
+// strings are represented by their JSON encoding.
+func (m *Message) Attributes() map[string]string {
+	attrs := make(map[string]string)
+	contentType := ""
+	for k, v := range m.Headers {
+		name := strings.ToLower(k)
+		if name == "content-type" {
+			contentType = v
+			continue
+		}
+		if !strings.HasPrefix(name, cloudEventsHeaderPrefix) {
+			continue
+		}
+		name = strings.TrimPrefix(name, cloudEventsHeaderPrefix)
+		// CloudEvents 0.1 prefixes extension headers with 'CE-X-'.
+		name = strings.TrimPrefix(name, cloudEventsExtensionStart)
+		setAttribute(attrs, name, v)
+	}
+
+	if strings.HasPrefix(strings.ToLower(contentType), structuredContentType) {
+		structured := map[string]interface{}{}
+		if err := json.Unmarshal(m.Payload, &structured); err == nil {
+			for k, v := range structured {
+				name := strings.ToLower(k)
+				switch name {
+				case "data":
+					continue
+				case "extensions":
+					// CloudEvents 0.1 nests extensions in their own object.
+					if ext, ok := v.(map[string]interface{}); ok {
+						for ek, ev := range ext {
+							setAttribute(attrs, strings.ToLower(ek), attributeString(ev))
+						}
+						continue
+					}
+				}
+				setAttribute(attrs, name, attributeString(v))
+			}
+		}
+	} else if contentType != "" {
+		if _, present := attrs["datacontenttype"]; !present {
+			attrs["datacontenttype"] = contentType
+		}
+	}
+	return attrs
+}
+
+// Data returns the data of the message, which is its payload in the binary content mode. In the
+// structured content mode, string data is returned unquoted, and data_base64 is decoded.
+func (m *Message) Data() []byte {
+	if !m.isStructured() {
+		return m.Payload
+	}
+	event := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		return nil
+	}
+	if b64, ok := event["data_base64"]; ok {
+		var s string
+		json.Unmarshal(b64, &s)
+		data, _ := base64.StdEncoding.DecodeString(s)
+		return data
+	}
+	var s string
+	if err := json.Unmarshal(event["data"], &s); err == nil {
+		return []byte(s)
+	}
+	return event["data"]
+}
+
+// StructuredJSON returns the event of the message in the structured content mode of the JSON
+// format. JSON data is embedded as is, other text data as a string, and binary data as
+// data_base64.
+func (m *Message) StructuredJSON() ([]byte, error) {
+	attrs := m.Attributes()
+	event := make(map[string]interface{}, len(attrs)+1)
+	for k, v := range attrs {
+		event[k] = v
+	}
+	delete(event, "data_base64")
+	data := m.Data()
+	switch {
+	case len(data) == 0:
+	case isJSONContentType(attrs["datacontenttype"]) && json.Valid(data):
+		event["data"] = json.RawMessage(data)
+	case utf8.Valid(data):
+		event["data"] = string(data)
+	default:
+		event["data_base64"] = data
+	}
+	return json.Marshal(event)
+}
+
+// isJSONContentType returns true if contentType is a JSON media type, or empty, which defaults to
+// JSON.
+func isJSONContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func setAttribute(attrs map[string]string, name, value string) {
+	if current, ok := legacyAttributeNames[name]; ok {
+		name = current
+	}
+	attrs[name] = value
+}
+
+func attributeString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMessageAttributes(t *testing.T) {
+	testCases := map[string]struct {
+		message *Message
+		want    map[string]string
+	}{
+		"empty": {
+			message: &Message{},
+			want:    map[string]string{},
+		},
+		"binary v0.2": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type":   "application/json",
+					"Ce-Specversion": "0.2",
+					"Ce-Type":        "com.example.someevent",
+					"Ce-Source":      "/mycontext",
+					"Ce-Id":          "A234-1234-1234",
+					"Ce-Myextension": "value",
+					"X-B3-Traceid":   "1234",
+				},
+				Payload: []byte(`{"hello":"world"}`),
+			},
+			want: map[string]string{
+				"datacontenttype": "application/json",
+				"specversion":     "0.2",
+				"type":            "com.example.someevent",
+				"source":          "/mycontext",
+				"id":              "A234-1234-1234",
+				"myextension":     "value",
+			},
+		},
+		"binary v0.1": {
+			message: &Message{
+				Headers: map[string]string{
+					"CE-CloudEventsVersion": "0.1",
+					"CE-EventType":          "com.example.someevent",
+					"CE-Source":             "/mycontext",
+					"CE-EventID":            "A234-1234-1234",
+					"CE-X-MyExtension":      "value",
+				},
+			},
+			want: map[string]string{
+				"specversion": "0.1",
+				"type":        "com.example.someevent",
+				"source":      "/mycontext",
+				"id":          "A234-1234-1234",
+				"myextension": "value",
+			},
+		},
+		"structured v0.1": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json; charset=utf-8",
+				},
+				Payload: []byte(`{
+					"cloudEventsVersion": "0.1",
+					"eventType": "com.example.someevent",
+					"source": "/mycontext",
+					"eventID": "A234-1234-1234",
+					"extensions": {"comExampleExtension": "value", "count": 3},
+					"contentType": "text/xml",
+					"data": "<much wow=\"xml\"/>"
+				}`),
+			},
+			want: map[string]string{
+				"specversion":         "0.1",
+				"type":                "com.example.someevent",
+				"source":              "/mycontext",
+				"id":                  "A234-1234-1234",
+				"comexampleextension": "value",
+				"count":               "3",
+				"datacontenttype":     "text/xml",
+			},
+		},
+		"structured invalid JSON": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`not json`),
+			},
+			want: map[string]string{},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.message.Attributes()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected attributes (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMessageData(t *testing.T) {
+	testCases := map[string]struct {
+		message *Message
+		want    string
+	}{
+		"binary": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Payload: []byte(`{"hello":"world"}`),
+			},
+			want: `{"hello":"world"}`,
+		},
+		"structured JSON": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","data":{"hello":"world"}}`),
+			},
+			want: `{"hello":"world"}`,
+		},
+		"structured string": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","data":"<much wow=\"xml\"/>"}`),
+			},
+			want: `<much wow="xml"/>`,
+		},
+		"structured base64": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","data_base64":"aGVsbG8="}`),
+			},
+			want: "hello",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := string(tc.message.Data()); got != tc.want {
+				t.Errorf("Unexpected data. Expected %q. Actual %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// batchContentType is the content type of the batched content mode of the CloudEvents JSON format:
+// a JSON array of events in the structured content mode.
+const batchContentType = "application/cloudevents-batch+json"
+
+// isBatch returns true if the message is a batch of events in the JSON format.
+func (m *Message) isBatch() bool {
+	for k, v := range m.Headers {
+		if strings.ToLower(k) == "content-type" {
+			return strings.HasPrefix(strings.ToLower(v), batchContentType)
+		}
+	}
+	return false
+}
+
+// splitBatch returns the events of the batch, each a message in the structured content mode. The
+// headers of the batch are copied to every event, except for its content type and its ce- headers,
+// which the events of a batch do not have.
+func (m *Message) splitBatch() ([]*Message, error) {
+	var events []json.RawMessage
+	if err := json.Unmarshal(m.Payload, &events); err != nil {
+		return nil, fmt.Errorf("the batch is not a JSON array: %v", err)
+	}
+	messages := make([]*Message, 0, len(events))
+	for i, event := range events {
+		if len(event) == 0 || event[0] != '{' {
+			return nil, fmt.Errorf("event %d of the batch is not a JSON object", i)
+		}
+		headers := map[string]string{
+			"Content-Type": structuredContentType,
+		}
+		for k, v := range m.Headers {
+			name := strings.ToLower(k)
+			if name == "content-type" || strings.HasPrefix(name, cloudEventsHeaderPrefix) {
+				continue
+			}
+			headers[k] = v
+		}
+		messages = append(messages, &Message{Headers: headers, Payload: event})
+	}
+	return messages, nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+// MaxBodySizeEnv is the environment variable holding the largest body, in bytes, of the events the
+// dispatchers accept and of the replies they forward. It defaults to DefaultMaxBodySize, and 0
+// leaves the bodies unbounded.
+const MaxBodySizeEnv = "MAX_BODY_SIZE"
+
+// DefaultMaxBodySize is the default largest body of the events, 10 MiB.
+const DefaultMaxBodySize = 10 << 20
+
+// errBodyTooLarge is returned when reading a body larger than the maximum size.
+var errBodyTooLarge = errors.New("body too large")
+
+// MaxBodySizeFromEnv returns the largest body size set by MaxBodySizeEnv, or DefaultMaxBodySize.
+func MaxBodySizeFromEnv() (int64, error) {
+	v := os.Getenv(MaxBodySizeEnv)
+	if v == "" {
+		return DefaultMaxBodySize, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a number of bytes, got %q", MaxBodySizeEnv, v)
+	}
+	return n, nil
+}
+
+// readBody reads body, whose length is size, or 0 or less if it is unknown, in a single buffer of
+// that size when it is known. It returns errBodyTooLarge if body is larger than max bytes, unless
+// max is 0.
+func readBody(body io.Reader, size, max int64) ([]byte, error) {
+	if size <= 0 {
+		return ioutil.ReadAll(limitBody(body, max))
+	}
+	if max > 0 && size > max {
+		return nil, errBodyTooLarge
+	}
+	// The HTTP server and client enforce the length of the bodies they declare.
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(body, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// IsStreamed returns true if the content of the message is streamed from the request it was
+// received in, rather than held in its Payload. It can then only be read once, see ReadBody.
+func (m *Message) IsStreamed() bool {
+	return m.body != nil
+}
+
+// ReadBody reads the streamed content of the message in its Payload, so that it can be read, or
+// sent, more than once. It does nothing if the message is not streamed.
+func (m *Message) ReadBody() error {
+	if m.body == nil {
+		return nil
+	}
+	// The body is already bounded by the receiver.
+	payload, err := readBody(m.body, m.bodySize, 0)
+	m.body = nil
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	return nil
+}
+
+// limitBody returns a reader of body that fails with errBodyTooLarge once more than max bytes were
+// read. body is returned unchanged if max is 0.
+func limitBody(body io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		return body
+	}
+	return &limitedReader{r: body, n: max}
+}
+
+// limitedReader reads from r until more than n bytes were read.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, errBodyTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	testCases := map[string]struct {
+		body        string
+		size        int64
+		max         int64
+		expectedErr error
+	}{
+		"known size": {
+			body: "0123456789",
+			size: 10,
+			max:  10,
+		},
+		"unknown size": {
+			body: "0123456789",
+			size: -1,
+			max:  10,
+		},
+		"unbounded": {
+			body: "0123456789",
+			size: -1,
+		},
+		"known size too large": {
+			body:        "0123456789",
+			size:        10,
+			max:         9,
+			expectedErr: errBodyTooLarge,
+		},
+		"unknown size too large": {
+			body:        "0123456789",
+			size:        -1,
+			max:         9,
+			expectedErr: errBodyTooLarge,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			body, err := readBody(strings.NewReader(tc.body), tc.size, tc.max)
+			if err != tc.expectedErr {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.expectedErr, err)
+			}
+			if err == nil && string(body) != tc.body {
+				t.Errorf("Unexpected body. Expected %q. Actual %q", tc.body, body)
+			}
+		})
+	}
+}
+
+func TestMaxBodySizeFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		env         string
+		expected    int64
+		expectedErr bool
+	}{
+		"default": {
+			expected: DefaultMaxBodySize,
+		},
+		"set": {
+			env:      "1048576",
+			expected: 1 << 20,
+		},
+		"unbounded": {
+			env:      "0",
+			expected: 0,
+		},
+		"negative": {
+			env:         "-1",
+			expectedErr: true,
+		},
+		"invalid": {
+			env:         "1Mi",
+			expectedErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(MaxBodySizeEnv, tc.env)
+			defer os.Unsetenv(MaxBodySizeEnv)
+			got, err := MaxBodySizeFromEnv()
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.expectedErr, err)
+			}
+			if got != tc.expected {
+				t.Errorf("Unexpected size. Expected %d. Actual %d", tc.expected, got)
+			}
+		})
+	}
+}
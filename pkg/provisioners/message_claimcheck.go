@@ -0,0 +1,131 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ClaimCheckExtension is the CloudEvents extension holding the key the data of an oversized
+	// event was stored under while the event is in a channel.
+	ClaimCheckExtension = "knativeclaimcheck"
+)
+
+// ClaimCheckStore keeps the data of oversized messages while they are in a channel, so that
+// channels backed by systems with message size limits can carry them. Implementations are in
+// pkg/provisioners/claimcheck.
+type ClaimCheckStore interface {
+	// Put stores data under key.
+	Put(key string, data []byte) error
+	// Get returns the data stored under key.
+	Get(key string) ([]byte, error)
+}
+
+// CheckIn moves the data of the message to store if its payload is larger than threshold bytes,
+// and sets the ClaimCheckExtension to the key it was stored under. The attributes of the message
+// are kept, so that it can still be routed.
+func (m *Message) CheckIn(store ClaimCheckStore, threshold int) error {
+	if len(m.Payload) <= threshold {
+		return nil
+	}
+	key := randomHex(16)
+	if !m.isStructured() {
+		if err := store.Put(key, m.Payload); err != nil {
+			return fmt.Errorf("unable to store the data of the message: %v", err)
+		}
+		m.Payload = nil
+		m.setExtension(ClaimCheckExtension, key)
+		return nil
+	}
+
+	event := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		return err
+	}
+	// The data members are stored as a JSON object, so that they are restored as they were.
+	data := map[string]json.RawMessage{}
+	for _, name := range []string{"data", "data_base64"} {
+		if v, ok := event[name]; ok {
+			data[name] = v
+			delete(event, name)
+		}
+	}
+	stored, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := store.Put(key, stored); err != nil {
+		return fmt.Errorf("unable to store the data of the message: %v", err)
+	}
+	event[ClaimCheckExtension], _ = json.Marshal(key)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	return nil
+}
+
+// CheckOut returns a copy of the message with the data that CheckIn moved to store restored. The
+// message itself, which may be dispatched concurrently, is left unchanged. Messages that were not
+// checked in are returned as is.
+func (m *Message) CheckOut(store ClaimCheckStore) (*Message, error) {
+	key := m.extension(ClaimCheckExtension)
+	if key == "" {
+		return m, nil
+	}
+	stored, err := store.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the data of the message: %v", err)
+	}
+
+	c := &Message{
+		Headers: make(map[string]string, len(m.Headers)),
+	}
+	for k, v := range m.Headers {
+		c.Headers[k] = v
+	}
+	if !m.isStructured() {
+		for k := range c.Headers {
+			if strings.ToLower(k) == cloudEventsHeaderPrefix+ClaimCheckExtension {
+				delete(c.Headers, k)
+			}
+		}
+		c.Payload = stored
+		return c, nil
+	}
+
+	event := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		return nil, err
+	}
+	data := map[string]json.RawMessage{}
+	if err := json.Unmarshal(stored, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode the data of the message: %v", err)
+	}
+	for k, v := range data {
+		event[k] = v
+	}
+	delete(event, ClaimCheckExtension)
+	if c.Payload, err = json.Marshal(event); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
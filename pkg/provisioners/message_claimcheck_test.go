@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// memoryStore is a ClaimCheckStore keeping the data in memory.
+type memoryStore struct {
+	data map[string][]byte
+	err  error
+}
+
+func (s *memoryStore) Put(key string, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.data[key] = data
+	return nil
+}
+
+func (s *memoryStore) Get(key string) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	data, ok := s.data[key]
+	if !ok {
+		return nil, fmt.Errorf("no data stored under %q", key)
+	}
+	return data, nil
+}
+
+func TestMessageClaimCheck(t *testing.T) {
+	testCases := map[string]struct {
+		message    *Message
+		threshold  int
+		checkedIn  bool
+		wantStored int
+	}{
+		"binary": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Id":          "1234",
+					"Content-Type":   "application/json",
+				},
+				Payload: []byte(`{"total": 10}`),
+			},
+			threshold: 5,
+			checkedIn: true,
+		},
+		"structured": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","type":"com.example.order","data":{"total":10}}`),
+			},
+			threshold: 5,
+			checkedIn: true,
+		},
+		"structured base64": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","data_base64":"AAEC"}`),
+			},
+			threshold: 5,
+			checkedIn: true,
+		},
+		"under threshold": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Specversion": "1.0"},
+				Payload: []byte(`{"total": 10}`),
+			},
+			threshold: 100,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			store := &memoryStore{data: map[string][]byte{}}
+			original := &Message{Headers: map[string]string{}, Payload: tc.message.Payload}
+			for k, v := range tc.message.Headers {
+				original.Headers[k] = v
+			}
+
+			m := tc.message
+			if err := m.CheckIn(store, tc.threshold); err != nil {
+				t.Fatalf("Unexpected error checking in: %v", err)
+			}
+			key := m.extension(ClaimCheckExtension)
+			if tc.checkedIn != (key != "") {
+				t.Fatalf("Unexpected claim check %q, expected checked in %v", key, tc.checkedIn)
+			}
+			if tc.checkedIn {
+				if _, ok := store.data[key]; !ok {
+					t.Errorf("Expected the data to be stored under %q", key)
+				}
+				if data := m.Data(); len(data) != 0 {
+					t.Errorf("Expected the checked in message to have no data, got %q", data)
+				}
+				if got, want := m.Attributes()["id"], original.Attributes()["id"]; got != want {
+					t.Errorf("Expected the checked in message to keep its attributes, id %q, got %q", want, got)
+				}
+			}
+
+			got, err := m.CheckOut(store)
+			if err != nil {
+				t.Fatalf("Unexpected error checking out: %v", err)
+			}
+			if diff := cmp.Diff(original.Headers, got.Headers); diff != "" {
+				t.Errorf("Unexpected headers (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(normalizeJSON(original.Payload), normalizeJSON(got.Payload)); diff != "" {
+				t.Errorf("Unexpected payload (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestMessageClaimCheckStoreErrors(t *testing.T) {
+	store := &memoryStore{data: map[string][]byte{}, err: errors.New("unavailable")}
+	m := &Message{
+		Headers: map[string]string{"Ce-Specversion": "1.0"},
+		Payload: []byte(`{"total": 10}`),
+	}
+	if err := m.CheckIn(store, 5); err == nil {
+		t.Errorf("Expected an error checking in")
+	}
+
+	m = &Message{
+		Headers: map[string]string{
+			"Ce-Specversion":       "1.0",
+			"Ce-Knativeclaimcheck": "missing",
+		},
+	}
+	if _, err := m.CheckOut(&memoryStore{data: map[string][]byte{}}); err == nil {
+		t.Errorf("Expected an error checking out")
+	}
+}
+
+// normalizeJSON decodes payloads that are JSON, so that they are compared regardless of member
+// order.
+func normalizeJSON(payload []byte) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return string(payload)
+	}
+	return v
+}
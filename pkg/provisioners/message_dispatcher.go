@@ -17,14 +17,27 @@
 package provisioners
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/delivery"
+	"github.com/knative/eventing/pkg/filter"
+	"github.com/knative/eventing/pkg/jsonschema"
+	"github.com/knative/eventing/pkg/transform"
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 )
 
@@ -49,66 +62,479 @@ type MessageDispatcher struct {
 	forwardPrefixes  []string
 	supportedSchemes map[string]bool
 
+	// tlsClients are the clients used for the destinations that require a client certificate,
+	// keyed by the TLS config of their TLSAuthenticator.
+	tlsClientsLock sync.Mutex
+	tlsClients     map[*tls.Config]*http.Client
+
+	// tlsConfig is the TLS config of httpClient, set by WithTLSConfig.
+	tlsConfig *tls.Config
+
+	// grpcConns are the connections to the destinations that events are delivered to with gRPC,
+	// which are closed once they have not been used for grpcIdleTimeout.
+	grpcConnsLock sync.Mutex
+	grpcConns     map[grpcConnKey]*grpcConn
+
+	// amqpSenders are the senders to the destinations that events are delivered to with AMQP,
+	// which are closed once they have not been used for amqpIdleTimeout.
+	amqpSendersLock sync.Mutex
+	amqpSenders     map[amqpSenderKey]*amqpSender
+
+	// mqttClients are the clients of the brokers that events are published to with MQTT, which
+	// are closed once they have not been used for mqttIdleTimeout.
+	mqttClientsLock sync.Mutex
+	mqttClients     map[mqttClientKey]*mqttClient
+
+	// claimCheck restores the data of the messages that were checked in. It is nil when messages
+	// are dispatched as they are.
+	claimCheck ClaimCheckStore
+
+	// encrypter decrypts the data of the messages that were encrypted. It is nil when messages are
+	// dispatched as they are.
+	encrypter Encrypter
+
+	// verifier verifies the signature of the messages. It is nil when signatures are not verified.
+	verifier Verifier
+
+	// audit records every delivery attempt. It is nil when deliveries are not audited.
+	audit AuditSink
+
+	// pusher pushes the messages of the subscriptions with the SSE protocol. It is nil when they
+	// are not supported.
+	pusher Pusher
+
+	// maxReplySize is the largest body of the replies forwarded, in bytes. They are unbounded when
+	// it is 0.
+	maxReplySize int64
+
+	// replyExtensions are the extensions the replies inherit from the events they respond to, in
+	// addition to those of PropagateExtensions.
+	replyExtensions []string
+
 	logger *zap.SugaredLogger
 }
 
+// DispatcherOption configures optional behavior of a MessageDispatcher.
+type DispatcherOption func(*MessageDispatcher)
+
+// WithClaimCheckStore makes the MessageDispatcher restore the data that a MessageReceiver created
+// with WithClaimCheck moved to store, before dispatching the messages.
+func WithClaimCheckStore(store ClaimCheckStore) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.claimCheck = store
+	}
+}
+
+// WithDecryption makes the MessageDispatcher decrypt the data that a MessageReceiver created with
+// WithEncryption encrypted with e, before dispatching the messages.
+func WithDecryption(e Encrypter) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.encrypter = e
+	}
+}
+
+// WithSignatureVerification makes the MessageDispatcher verify the signature of the messages with
+// v, and refuse to dispatch the messages that are not signed or whose signature is invalid.
+func WithSignatureVerification(v Verifier) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.verifier = v
+	}
+}
+
+// WithMaxReplySize makes the MessageDispatcher fail the deliveries whose reply has a body larger
+// than max bytes, rather than forwarding it. Replies are unbounded when max is 0.
+func WithMaxReplySize(max int64) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.maxReplySize = max
+	}
+}
+
+// WithReplyExtensions makes the MessageDispatcher copy the extensions names of the events it
+// delivers to the replies that do not set them, in addition to the history, the trace context and
+// the TTL.
+func WithReplyExtensions(names ...string) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.replyExtensions = append(d.replyExtensions, names...)
+	}
+}
+
 // DispatchDefaults provides default parameter values used when dispatching a message.
 type DispatchDefaults struct {
 	Namespace string
+
+	// Auth, if set, adds credentials to the request sent to the destination. It is not used for
+	// the reply.
+	Auth Authenticator
+
+	// DeadLetter, if set, receives the messages that are dropped because their TTL expired.
+	DeadLetter string
+
+	// OnError, if set, receives the messages that the destination fails to accept, with the
+	// failure in their error extensions, instead of failing the dispatch. It is not used when the
+	// reply fails to accept the response.
+	OnError string
+
+	// Channel and Subscription label the metrics of the delivery. They are the namespace/name of
+	// the Channel and of the Subscription the message is dispatched for, if any.
+	Channel      string
+	Subscription string
+
+	// Redelivery is true when the dispatch retries a failed delivery of the message. It is used
+	// to count retries.
+	Redelivery bool
+
+	// Attempt is the number of the attempt to deliver the message to the destination, counting
+	// from 1, sent in the delivery.AttemptHeader. It is 1 when it is not set, or 2 for a
+	// Redelivery.
+	Attempt int
+
+	// Protocol is the protocol the message is delivered to the destination with, HTTP if it is
+	// empty. It is not used for the reply, the dead letter and the error destination, which are
+	// always sent the message over HTTP.
+	Protocol eventingduck.DeliveryProtocol
+
+	// Filter, if set, selects the messages delivered to the destination by their context
+	// attributes, see SubscriberFilter. Dispatching the other messages trivially succeeds.
+	Filter filter.Expression
+
+	// Validator, if set, validates the messages before they are transformed and delivered to the
+	// destination. The messages that do not conform to its schema are sent to the
+	// NonConformingDeadLetter, or dropped if there is none.
+	Validator MessageValidator
+	// NonConformingDeadLetter, if set, receives the messages that do not conform to the schema of
+	// the Validator.
+	NonConformingDeadLetter string
+
+	// Transform, if set, rewrites the messages before they are delivered to the destination, see
+	// SubscriberTransform. The dispatch fails if a message cannot be transformed.
+	Transform *transform.Template
+}
+
+// MessageValidator validates the data of messages, see schema.Validator.
+type MessageValidator interface {
+	// Validate returns a *jsonschema.ValidationError if the data of m does not conform to the
+	// schema, or another error if it cannot be validated.
+	Validate(m *Message) error
+}
+
+// Authenticator adds credentials to an outgoing request.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// TLSAuthenticator is an Authenticator that may also present a client certificate to destinations
+// that require mutual TLS. TLSConfig returns nil if it does not. It should return the same
+// *tls.Config for the same certificate, since the MessageDispatcher keeps a client, and its
+// connections, per config.
+type TLSAuthenticator interface {
+	Authenticator
+	TLSConfig() *tls.Config
+}
+
+// WithTLSConfig makes the MessageDispatcher use config for the TLS connections it opens, unless
+// the Auth of the dispatch is a TLSAuthenticator. It is typically used to present a client
+// certificate to every destination.
+func WithTLSConfig(config *tls.Config) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.httpClient = newTLSClient(config)
+		d.tlsConfig = config
+	}
+}
+
+// newTLSClient creates an http.Client whose TLS connections use config.
+func newTLSClient(config *tls.Config) *http.Client {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = config
+	return &http.Client{Transport: t}
+}
+
+// clientFor returns the http.Client for a request authenticated by auth.
+func (d *MessageDispatcher) clientFor(auth Authenticator) *http.Client {
+	ta, ok := auth.(TLSAuthenticator)
+	if !ok {
+		return d.httpClient
+	}
+	config := ta.TLSConfig()
+	if config == nil {
+		return d.httpClient
+	}
+	d.tlsClientsLock.Lock()
+	defer d.tlsClientsLock.Unlock()
+	c, ok := d.tlsClients[config]
+	if !ok {
+		c = newTLSClient(config)
+		d.tlsClients[config] = c
+	}
+	return c
 }
 
 // NewMessageDispatcher creates a new message dispatcher that can dispatch
 // messages to HTTP destinations.
-func NewMessageDispatcher(logger *zap.SugaredLogger) *MessageDispatcher {
-	return &MessageDispatcher{
+func NewMessageDispatcher(logger *zap.SugaredLogger, opts ...DispatcherOption) *MessageDispatcher {
+	d := &MessageDispatcher{
 		httpClient:      &http.Client{},
 		forwardHeaders:  headerSet(forwardHeaders),
 		forwardPrefixes: forwardPrefixes,
 		supportedSchemes: map[string]bool{
 			"http":  true,
 			"https": true,
+			"amqp":  true,
+			"amqps": true,
+			"mqtt":  true,
+			"mqtts": true,
 		},
+		tlsClients:  make(map[*tls.Config]*http.Client),
+		grpcConns:   make(map[grpcConnKey]*grpcConn),
+		amqpSenders: make(map[amqpSenderKey]*amqpSender),
+		mqttClients: make(map[mqttClientKey]*mqttClient),
 
 		logger: logger,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // DispatchMessage dispatches a message to a destination over HTTP.
 //
-// The destination and reply are DNS names. For names with a single label,
-// the default namespace is used to expand it into a fully qualified name
-// within the cluster.
+// The destination and reply are URLs or DNS names, optionally followed by a
+// port and a path. For names with a single label, the default namespace is
+// used to expand it into a fully qualified name within the cluster.
+//
+// The TTL of the message is decremented. A message whose TTL has expired is
+// not dispatched, but sent to the dead letter of the defaults if there is one,
+// and dropped otherwise. The data of checked in and encrypted messages is
+// restored first, then the signature of the message is verified.
+//
+// A message that the destination fails to accept is sent to the OnError
+// destination of the defaults if there is one, and the dispatch only fails if
+// that is not accepted either.
+//
+// The message is delivered to the destination with gRPC when the Protocol of
+// the defaults is gRPC, and its reply is forwarded over HTTP. When it is AMQP
+// or MQTT, the message is sent to the node or published to the topic of the
+// destination, which does not reply.
+// When it is SSE, the message is pushed to the consumers of the Subscription of
+// the defaults instead, whatever the destination, and nothing is forwarded to
+// the reply.
+//
+// Replies in the binary content mode are streamed from the destination to the
+// reply, rather than read in memory. The bodies of the responses that are not
+// forwarded are discarded. A message whose body is streamed, see
+// WithBodyStreaming, is streamed to the destination too, unless dispatching it
+// reads its payload or may send it more than once.
 func (d *MessageDispatcher) DispatchMessage(message *Message, destination, reply string, defaults DispatchDefaults) error {
-	var err error
+	if defaults.Filter != nil && !defaults.Filter.Matches(message.Attributes()) {
+		// The destination isn't interested in this message, so dispatching it is trivially
+		// successful.
+		return nil
+	}
+	if message.IsStreamed() && d.readsPayload(defaults) {
+		if err := message.ReadBody(); err != nil {
+			return err
+		}
+	}
+	if d.claimCheck != nil {
+		var err error
+		if message, err = message.CheckOut(d.claimCheck); err != nil {
+			return err
+		}
+	}
+	if d.encrypter != nil {
+		var err error
+		if message, err = message.Decrypt(d.encrypter); err != nil {
+			return err
+		}
+	}
+	if d.verifier != nil {
+		if err := message.VerifySignature(d.verifier); err != nil {
+			return fmt.Errorf("invalid signature: %v", err)
+		}
+	}
+	if defaults.Validator != nil {
+		if err := defaults.Validator.Validate(message); err != nil {
+			return d.dropNonConforming(message, destination, err, defaults)
+		}
+	}
+	if defaults.Transform != nil {
+		var err error
+		if message, err = message.transform(defaults.Transform); err != nil {
+			return err
+		}
+	}
+	ttl := message.TTL()
+	if ttl <= 0 {
+		return d.dropExpired(message, defaults)
+	}
+	message = message.withExtension(TTLExtension, strconv.Itoa(ttl-1))
+
 	// Default to replying with the original message. If there is a destination, then replace it
 	// with the response from the call to the destination instead.
 	response := message
-	if destination != "" {
-		destinationURL := d.resolveURL(destination, defaults.Namespace)
-		response, err = d.executeRequest(destinationURL, message)
+	// responseBody is the body of the response, when it is streamed rather than read in its
+	// payload.
+	var responseBody io.Reader
+	push := defaults.Protocol == eventingduck.SSEDeliveryProtocol
+	if destination != "" || push {
+		var destinationURL *url.URL
+		if push {
+			destinationURL = pushURL(defaults.Subscription)
+		} else {
+			destinationURL = d.resolveURL(destination, defaults.Namespace)
+		}
+		span := d.startDeliverySpan(message, destinationURL, defaults)
+		start := time.Now()
+		var res *http.Response
+		var err error
+		switch defaults.Protocol {
+		case eventingduck.GRPCDeliveryProtocol:
+			response, err = d.executeGRPC(destinationURL, message, deliveryHeaders(message, defaults), defaults.Auth)
+		case eventingduck.AMQPDeliveryProtocol:
+			response, err = nil, d.executeAMQP(destinationURL, message, defaults.Auth)
+		case eventingduck.MQTTDeliveryProtocol:
+			response, err = nil, d.executeMQTT(destinationURL, message, defaults.Auth)
+		case eventingduck.SSEDeliveryProtocol:
+			// Consumers do not reply to the events pushed to them.
+			response, err = nil, d.executePush(message, defaults.Subscription)
+		default:
+			res, err = d.executeRequest(destinationURL, message, nil, deliveryHeaders(message, defaults), defaults.Auth)
+		}
+		latency := time.Since(start)
+		// The events the destination asked to drop were delivered, as far as the channel is
+		// concerned.
+		dropped := err == errDropped
+		if dropped {
+			err = nil
+		}
+		observeDelivery(defaults, latency, err)
+		d.auditDelivery(message, destinationURL, defaults, start, latency, err)
+		endDeliverySpan(span, err)
 		if err != nil {
-			return fmt.Errorf("Unable to complete request %v", err)
+			// Nacked events are redelivered rather than routed.
+			if defaults.OnError != "" && !IsNack(err) {
+				return d.routeError(message, destinationURL, err, defaults)
+			}
+			return &deliveryError{err: err}
+		}
+		if dropped {
+			messagesDropped.WithLabelValues(dropDestinationDropped).Inc()
+			d.logger.Infow("Dropping a message the destination asked to drop", zap.String("destination", destinationURL.String()))
+			return nil
+		}
+		if res != nil {
+			if reply == "" {
+				discardBody(res)
+				return nil
+			}
+			defer res.Body.Close()
+			if response, responseBody, err = d.toResponseMessage(res, message); err != nil {
+				return err
+			}
 		}
 	}
 
 	if reply != "" && response != nil {
 		replyURL := d.resolveURL(reply, defaults.Namespace)
-		_, err = d.executeRequest(replyURL, response)
+		res, err := d.executeRequest(replyURL, response, responseBody, nil, nil)
 		if err != nil {
 			return fmt.Errorf("Failed to forward reply %v", err)
 		}
+		discardBody(res)
 	}
 	return nil
 }
 
-func (d *MessageDispatcher) executeRequest(url *url.URL, message *Message) (*Message, error) {
+// readsPayload returns true if dispatching a message with defaults reads its payload, or may send it
+// more than once, so that a streamed message must be read first. Streamed messages are otherwise
+// sent, once, to the destination, or to the reply or the dead letter.
+func (d *MessageDispatcher) readsPayload(defaults DispatchDefaults) bool {
+	if defaults.Protocol != "" && defaults.Protocol != eventingduck.HTTPDeliveryProtocol {
+		return true
+	}
+	return d.claimCheck != nil || d.encrypter != nil || d.verifier != nil ||
+		defaults.Validator != nil || defaults.Transform != nil || defaults.OnError != ""
+}
+
+// dropExpired drops a message whose TTL has expired, sending it to the dead letter of defaults if
+// there is one.
+func (d *MessageDispatcher) dropExpired(message *Message, defaults DispatchDefaults) error {
+	messagesDropped.WithLabelValues(dropTTLExpired).Inc()
+	if defaults.DeadLetter == "" {
+		d.logger.Warnw("Dropping a message whose TTL expired", zap.Strings("history", message.History()))
+		return nil
+	}
+	d.logger.Warnw("Sending a message whose TTL expired to the dead letter", zap.Strings("history", message.History()))
+	res, err := d.executeRequest(d.resolveURL(defaults.DeadLetter, defaults.Namespace), message, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to send to the dead letter %v", err)
+	}
+	discardBody(res)
+	return nil
+}
+
+// dropNonConforming drops a message whose data does not conform to the schema of the Validator of
+// defaults, sending it to the NonConformingDeadLetter of defaults with the failure if there is one. Errors
+// reading the schema are returned, so that the message is retried.
+func (d *MessageDispatcher) dropNonConforming(message *Message, destination string, err error, defaults DispatchDefaults) error {
+	if _, ok := err.(*jsonschema.ValidationError); !ok {
+		return fmt.Errorf("unable to validate the event: %v", err)
+	}
+	if defaults.NonConformingDeadLetter == "" {
+		d.logger.Infow("Dropping a message that does not conform to the schema", zap.String("subscription", defaults.Subscription), zap.Error(err))
+		return nil
+	}
+	d.logger.Infow("Sending a message that does not conform to the schema to the dead letter", zap.String("subscription", defaults.Subscription), zap.Error(err))
+	res, err := d.executeRequest(d.resolveURL(defaults.NonConformingDeadLetter, defaults.Namespace), message.WithDeliveryFailure(destination, err, 0), nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to send to the dead letter %v", err)
+	}
+	discardBody(res)
+	return nil
+}
+
+// routeError sends a message that destination failed to accept with err to the OnError
+// destination of defaults, with the failure in its error extensions.
+func (d *MessageDispatcher) routeError(message *Message, destination *url.URL, err error, defaults DispatchDefaults) error {
+	d.logger.Warnw("Sending a message the destination failed to accept to the error destination", zap.String("destination", destination.String()), zap.Error(err))
+	res, err := d.executeRequest(d.resolveURL(defaults.OnError, defaults.Namespace), message.withError(destination, err), nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to send to the error destination %v", err)
+	}
+	discardBody(res)
+	return nil
+}
+
+// executeRequest sends message to url, with body as its body, or its content if body is nil, and
+// the further headers. It returns the response of a successful request, whose body must be closed.
+func (d *MessageDispatcher) executeRequest(url *url.URL, message *Message, body io.Reader, headers http.Header, auth Authenticator) (*http.Response, error) {
 	d.logger.Infof("Dispatching message to %s", url.String())
-	req, err := http.NewRequest(http.MethodPost, url.String(), bytes.NewReader(message.Payload))
+	streamed := body == nil && message.IsStreamed()
+	if streamed {
+		body = message.body
+	} else if body == nil {
+		body = bytes.NewReader(message.Payload)
+	}
+	req, err := http.NewRequest(http.MethodPost, url.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create request %v", err)
 	}
+	if streamed && message.bodySize > 0 {
+		req.ContentLength = message.bodySize
+	}
 	req.Header = d.toHTTPHeaders(message.Headers)
-	res, err := d.httpClient.Do(req)
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	if tp := message.TraceParent(); tp != "" {
+		req.Header.Set(traceParentHeader, tp)
+	}
+	if auth != nil {
+		if err := auth.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("unable to authenticate request %v", err)
+		}
+	}
+	res, err := d.clientFor(auth).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -117,25 +543,152 @@ func (d *MessageDispatcher) executeRequest(url *url.URL, message *Message) (*Mes
 		// check anyway.
 		return nil, errors.New("non-error nil result from http.Client.Do()")
 	}
-	defer res.Body.Close()
+	if err := deliveryResult(res); err != nil {
+		discardBody(res)
+		return nil, err
+	}
 	if isFailure(res.StatusCode) {
 		// reject non-successful responses
-		return nil, fmt.Errorf("unexpected HTTP response, expected 2xx, got %d", res.StatusCode)
+		discardBody(res)
+		return nil, &responseError{statusCode: res.StatusCode}
 	}
+	return res, nil
+}
+
+// toResponseMessage returns the message of the response res of a destination of message, or nil if
+// the body of res is empty and the event has 'finished'. The body of a response in the binary
+// content mode is returned rather than read in the payload of the message, so that it can be
+// streamed to the reply.
+func (d *MessageDispatcher) toResponseMessage(res *http.Response, message *Message) (*Message, io.Reader, error) {
 	headers := d.fromHTTPHeaders(res.Header)
+	// The delivery result is meant for the dispatcher, not the reply channel.
+	delete(headers, DeliveryResultHeader)
 	// TODO: add configurable whitelisting of propagated headers/prefixes (configmap?)
 	if correlationID, ok := message.Headers[correlationIDHeaderName]; ok {
 		headers[correlationIDHeaderName] = correlationID
 	}
-	payload, err := ioutil.ReadAll(res.Body)
+	response := &Message{Headers: headers}
+	var body io.Reader
+	if response.isStructured() {
+		payload, err := readBody(res.Body, res.ContentLength, d.maxReplySize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to read response %v", err)
+		}
+		if len(payload) == 0 {
+			return nil, nil, nil
+		}
+		response.Payload = payload
+	} else {
+		// The conversions of a message in the binary content mode only change its headers.
+		br := bufio.NewReader(limitBody(res.Body, d.maxReplySize))
+		if _, err := br.Peek(1); err == io.EOF {
+			return nil, nil, nil
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("Unable to read response %v", err)
+		}
+		body = br
+	}
+	// Subscribers may reply with CloudEvents of older versions of the specification.
+	if err := response.ToCloudEventsSpecVersion(); err != nil {
+		return nil, nil, fmt.Errorf("unable to convert response %v", err)
+	}
+	response.PropagateExtensions(message)
+	response.propagateExtensions(message, d.replyExtensions)
+	return response, body, nil
+}
+
+// deliveryHeaders returns the headers identifying the delivery of message with defaults to its
+// destination, so that the destination can deduplicate retried deliveries.
+func deliveryHeaders(message *Message, defaults DispatchDefaults) http.Header {
+	attempt := defaults.Attempt
+	if attempt == 0 {
+		attempt = 1
+		if defaults.Redelivery {
+			attempt = 2
+		}
+	}
+	headers := http.Header{}
+	headers.Set(delivery.AttemptHeader, strconv.Itoa(attempt))
+	attrs := message.Attributes()
+	if key := delivery.IdempotencyKey(defaults.Subscription, attrs["source"], attrs["id"]); key != "" {
+		headers.Set(delivery.IdempotencyKeyHeader, key)
+	}
+	return headers
+}
+
+// maxDiscardedBody is the largest body of a response that is discarded to reuse its connection.
+// The connections of larger responses are closed instead.
+const maxDiscardedBody = 64 << 10
+
+// discardBody discards and closes the body of res.
+func discardBody(res *http.Response) {
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxDiscardedBody))
+	res.Body.Close()
+}
+
+// startDeliverySpan starts the span of a delivery attempt of message to url, the child of the span
+// of its trace context. message must be a copy owned by the delivery. Messages without a trace
+// context are not traced.
+func (d *MessageDispatcher) startDeliverySpan(message *Message, url *url.URL, defaults DispatchDefaults) *trace.Span {
+	if message.TraceParent() == "" {
+		return nil
+	}
+	span := message.StartSpan("channel.dispatch", trace.WithSpanKind(trace.SpanKindClient))
+	span.AddAttributes(
+		trace.StringAttribute("channel", defaults.Channel),
+		trace.StringAttribute("subscription", defaults.Subscription),
+		trace.StringAttribute("http.url", url.String()),
+	)
+	return span
+}
+
+// endDeliverySpan ends the span of a delivery that failed with err if it is not nil. It does
+// nothing if span is nil.
+func endDeliverySpan(span *trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if re, ok := err.(*responseError); ok {
+		span.AddAttributes(trace.Int64Attribute("http.status_code", int64(re.statusCode)))
+	}
 	if err != nil {
-		return nil, fmt.Errorf("Unable to read response %v", err)
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// responseError is the error of a request answered with a non-successful HTTP status.
+type responseError struct {
+	statusCode int
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("unexpected HTTP response, expected 2xx, got %d", e.statusCode)
+}
+
+// deliveryError is the error of a dispatch whose destination failed to accept the message.
+type deliveryError struct {
+	err error
+}
+
+func (e *deliveryError) Error() string {
+	return fmt.Sprintf("Unable to complete request %v", e.err)
+}
+
+// ResponseStatusCode returns the HTTP status code the destination of a dispatch that failed with
+// err answered with. It returns false if the destination did not answer, or the dispatch failed
+// for another reason.
+func ResponseStatusCode(err error) (int, bool) {
+	if de, ok := err.(*deliveryError); ok {
+		err = de.err
 	}
-	if len(payload) == 0 {
-		// The response body is empty, the event has 'finished'.
-		return nil, nil
+	switch e := err.(type) {
+	case *responseError:
+		return e.statusCode, true
+	case *nackError:
+		return e.statusCode, true
 	}
-	return &Message{headers, payload}, nil
+	return 0, false
 }
 
 // isFailure returns true if the status code is not a successful HTTP status.
@@ -200,12 +753,20 @@ func (d *MessageDispatcher) resolveURL(destination string, defaultNamespace stri
 		// already a URL with a known scheme
 		return url
 	}
-	if strings.Index(destination, ".") == -1 {
-		destination = fmt.Sprintf("%s.%s.svc.cluster.local", destination, defaultNamespace)
+	// The destination may include a port and a path, e.g. svc:8443/events.
+	u, err := url.Parse("http://" + destination)
+	if err != nil {
+		u = &url.URL{Scheme: "http", Host: destination}
+	}
+	if host := u.Hostname(); strings.Index(host, ".") == -1 {
+		host = fmt.Sprintf("%s.%s.svc.cluster.local", host, defaultNamespace)
+		if port := u.Port(); port != "" {
+			host = net.JoinHostPort(host, port)
+		}
+		u.Host = host
 	}
-	return &url.URL{
-		Scheme: "http",
-		Host:   destination,
-		Path:   "/",
+	if u.Path == "" {
+		u.Path = "/"
 	}
+	return u
 }
@@ -18,15 +18,27 @@ package provisioners
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/delivery"
+	"github.com/knative/eventing/pkg/filter"
+	"github.com/knative/eventing/pkg/jsonschema"
+	"github.com/knative/eventing/pkg/transform"
 )
 
 var (
@@ -44,6 +56,7 @@ func TestDispatchMessage(t *testing.T) {
 		sendToDestination    bool
 		sendToReply          bool
 		message              *Message
+		auth                 Authenticator
 		fakeResponse         *http.Response
 		expectedErr          bool
 		expectedDestRequest  *requestValidation
@@ -64,10 +77,12 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDestRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"knative-delivery-attempt": {"1"},
+					"ce-knativettl":            {"254"},
+					"x-request-id":             {"id123"},
+					"knative-1":                {"knative-1-value"},
+					"knative-2":                {"knative-2-value"},
+					"ce-abc":                   {"ce-abc-value"},
 				},
 				Body: "destination",
 			},
@@ -87,10 +102,12 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDestRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"knative-delivery-attempt": {"1"},
+					"ce-knativettl":            {"254"},
+					"x-request-id":             {"id123"},
+					"knative-1":                {"knative-1-value"},
+					"knative-2":                {"knative-2-value"},
+					"ce-abc":                   {"ce-abc-value"},
 				},
 				Body: "destination",
 			},
@@ -115,10 +132,11 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedReplyRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"ce-knativettl": {"254"},
+					"x-request-id":  {"id123"},
+					"knative-1":     {"knative-1-value"},
+					"knative-2":     {"knative-2-value"},
+					"ce-abc":        {"ce-abc-value"},
 				},
 				Body: "reply",
 			},
@@ -138,10 +156,11 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedReplyRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"ce-knativettl": {"254"},
+					"x-request-id":  {"id123"},
+					"knative-1":     {"knative-1-value"},
+					"knative-2":     {"knative-2-value"},
+					"ce-abc":        {"ce-abc-value"},
 				},
 				Body: "reply",
 			},
@@ -167,10 +186,12 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDestRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"knative-delivery-attempt": {"1"},
+					"ce-knativettl":            {"254"},
+					"x-request-id":             {"id123"},
+					"knative-1":                {"knative-1-value"},
+					"knative-2":                {"knative-2-value"},
+					"ce-abc":                   {"ce-abc-value"},
 				},
 				Body: "destination",
 			},
@@ -196,10 +217,12 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDestRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"knative-delivery-attempt": {"1"},
+					"ce-knativettl":            {"254"},
+					"x-request-id":             {"id123"},
+					"knative-1":                {"knative-1-value"},
+					"knative-2":                {"knative-2-value"},
+					"ce-abc":                   {"ce-abc-value"},
 				},
 				Body: "destination",
 			},
@@ -230,10 +253,12 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDestRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id": {"id123"},
-					"knative-1":    {"knative-1-value"},
-					"knative-2":    {"knative-2-value"},
-					"ce-abc":       {"ce-abc-value"},
+					"knative-delivery-attempt": {"1"},
+					"ce-knativettl":            {"254"},
+					"x-request-id":             {"id123"},
+					"knative-1":                {"knative-1-value"},
+					"knative-2":                {"knative-2-value"},
+					"ce-abc":                   {"ce-abc-value"},
 				},
 				Body: "destination",
 			},
@@ -249,13 +274,62 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedReplyRequest: &requestValidation{
 				Headers: map[string][]string{
+					"ce-knativettl": {"254"},
+					"x-request-id":  {"altered-id"},
+					"knative-1":     {"new-knative-1-value"},
+					"ce-abc":        {"new-ce-abc-value"},
+				},
+				Body: "destination-response",
+			},
+		},
+		"destination and reply - authenticated": {
+			sendToDestination: true,
+			sendToReply:       true,
+			message: &Message{
+				Headers: map[string]string{
+					"x-request-id": "id123",
+				},
+				Payload: []byte("destination"),
+			},
+			auth: authenticatorFunc(func(req *http.Request) error {
+				req.Header.Set("Authorization", "Bearer s3cr3t")
+				return nil
+			}),
+			expectedDestRequest: &requestValidation{
+				Headers: map[string][]string{
+					"knative-delivery-attempt": {"1"},
+					"ce-knativettl":            {"254"},
+					"x-request-id":             {"id123"},
+					"authorization":            {"Bearer s3cr3t"},
+				},
+				Body: "destination",
+			},
+			fakeResponse: &http.Response{
+				StatusCode: http.StatusAccepted,
+				Header: map[string][]string{
 					"x-request-id": {"altered-id"},
-					"knative-1":    {"new-knative-1-value"},
-					"ce-abc":       {"new-ce-abc-value"},
+				},
+				Body: ioutil.NopCloser(bytes.NewBufferString("destination-response")),
+			},
+			// The credentials are only for the destination and are not sent to the reply.
+			expectedReplyRequest: &requestValidation{
+				Headers: map[string][]string{
+					"ce-knativettl": {"254"},
+					"x-request-id":  {"altered-id"},
 				},
 				Body: "destination-response",
 			},
 		},
+		"destination - authentication fails": {
+			sendToDestination: true,
+			message: &Message{
+				Payload: []byte("destination"),
+			},
+			auth: authenticatorFunc(func(_ *http.Request) error {
+				return errors.New("secret not found")
+			}),
+			expectedErr: true,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -278,7 +352,7 @@ func TestDispatchMessage(t *testing.T) {
 			err := md.DispatchMessage(tc.message,
 				getDomain(t, tc.sendToDestination, destServer.URL),
 				getDomain(t, tc.sendToReply, replyServer.URL),
-				DispatchDefaults{})
+				DispatchDefaults{Auth: tc.auth})
 			if tc.expectedErr != (err != nil) {
 				t.Errorf("Unexpected error from DispatchRequest. Expected %v. Actual: %v", tc.expectedErr, err)
 			}
@@ -300,6 +374,1033 @@ func TestDispatchMessage(t *testing.T) {
 	}
 }
 
+func TestDispatchMessageTTL(t *testing.T) {
+	testCases := map[string]struct {
+		ttl            string
+		deadLetter     bool
+		wantDest       string
+		wantDeadLetter bool
+	}{
+		"decremented": {
+			ttl:      "3",
+			wantDest: "2",
+		},
+		"default": {
+			ttl:      "not a number",
+			wantDest: "254",
+		},
+		"expired": {
+			ttl: "0",
+		},
+		"expired with dead letter": {
+			ttl:            "0",
+			deadLetter:     true,
+			wantDeadLetter: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{t: t}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+			deadLetterHandler := &fakeHandler{t: t}
+			deadLetterServer := httptest.NewServer(deadLetterHandler)
+			defer deadLetterServer.Close()
+
+			message := &Message{
+				Headers: map[string]string{"Ce-Knativettl": tc.ttl},
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			defaults := DispatchDefaults{DeadLetter: getDomain(t, tc.deadLetter, deadLetterServer.URL)}
+			if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", defaults); err != nil {
+				t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+			}
+			if tc.wantDest == "" {
+				if len(destHandler.requests) != 0 {
+					t.Errorf("Unexpected destination requests: %+v", destHandler.requests)
+				}
+			} else if got := destHandler.popRequest(t).Headers.Get("ce-knativettl"); got != tc.wantDest {
+				t.Errorf("Unexpected TTL. Expected %q. Actual %q", tc.wantDest, got)
+			}
+			if got := len(deadLetterHandler.requests) == 1; got != tc.wantDeadLetter {
+				t.Errorf("Unexpected dead letter delivery. Expected %v. Actual %v", tc.wantDeadLetter, got)
+			}
+			if message.Headers["Ce-Knativettl"] != tc.ttl {
+				t.Errorf("The dispatched message was changed: %v", message.Headers)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageFilter(t *testing.T) {
+	expr, err := filter.Parse(`type = 'dev.knative.order.created'`)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the filter: %v", err)
+	}
+	testCases := map[string]struct {
+		eventType string
+		wantDest  bool
+	}{
+		"matching": {
+			eventType: "dev.knative.order.created",
+			wantDest:  true,
+		},
+		"not matching": {
+			eventType: "dev.knative.order.cancelled",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{t: t}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+
+			message := &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Type":        tc.eventType,
+				},
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{Filter: expr}); err != nil {
+				t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+			}
+			if got := len(destHandler.requests) == 1; got != tc.wantDest {
+				t.Errorf("Unexpected delivery to the destination. Expected %v. Actual %v", tc.wantDest, got)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageTransform(t *testing.T) {
+	tmpl, err := transform.Parse(`{"specversion": "1.0", "type": "com.example.order.v2", "data": {"total": {{json .data.total}}}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the template: %v", err)
+	}
+	destHandler := &fakeHandler{t: t}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+
+	message := &Message{
+		Headers: map[string]string{
+			"Content-Type":   "application/json",
+			"Ce-Specversion": "1.0",
+			"Ce-Type":        "com.example.order",
+		},
+		Payload: []byte(`{"total": 10, "card": "4111111111111111"}`),
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{Transform: tmpl}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	req := destHandler.popRequest(t)
+	if got := req.Headers.Get("content-type"); got != "application/cloudevents+json" {
+		t.Errorf("Unexpected content type %q", got)
+	}
+	if strings.Contains(req.Body, "4111111111111111") || !strings.Contains(req.Body, `"type":"com.example.order.v2"`) {
+		t.Errorf("Unexpected body %q", req.Body)
+	}
+	if string(message.Payload) != `{"total": 10, "card": "4111111111111111"}` {
+		t.Errorf("The dispatched message was changed: %q", message.Payload)
+	}
+
+	failing, err := transform.Parse("{{.type}}")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the template: %v", err)
+	}
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{Transform: failing}); err == nil {
+		t.Error("Expected an error dispatching a message that cannot be transformed")
+	}
+}
+
+type fakeValidator struct {
+	err error
+}
+
+func (v fakeValidator) Validate(*Message) error {
+	return v.err
+}
+
+func TestDispatchMessageSchema(t *testing.T) {
+	testCases := map[string]struct {
+		err            error
+		deadLetter     bool
+		wantErr        bool
+		wantDelivered  bool
+		wantDeadLetter bool
+	}{
+		"conforming": {
+			deadLetter:    true,
+			wantDelivered: true,
+		},
+		"nonconforming is dropped": {
+			err: &jsonschema.ValidationError{},
+		},
+		"nonconforming is sent to the dead letter": {
+			err:            &jsonschema.ValidationError{},
+			deadLetter:     true,
+			wantDeadLetter: true,
+		},
+		"schema cannot be read": {
+			err:        errors.New("configmap not found"),
+			deadLetter: true,
+			wantErr:    true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{t: t}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+			deadLetterHandler := &fakeHandler{t: t}
+			deadLetterServer := httptest.NewServer(deadLetterHandler)
+			defer deadLetterServer.Close()
+
+			message := &Message{
+				Headers: map[string]string{
+					"Content-Type":   "application/json",
+					"Ce-Specversion": "1.0",
+					"Ce-Type":        "com.example.order",
+				},
+				Payload: []byte(`{"total": "ten"}`),
+			}
+			defaults := DispatchDefaults{Validator: fakeValidator{err: tc.err}}
+			if tc.deadLetter {
+				defaults.NonConformingDeadLetter = getDomain(t, true, deadLetterServer.URL)
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", defaults)
+			if got := err != nil; got != tc.wantErr {
+				t.Errorf("Unexpected error from DispatchMessage. Expected %v. Actual: %v", tc.wantErr, err)
+			}
+			if got := len(destHandler.requests) == 1; got != tc.wantDelivered {
+				t.Errorf("Unexpected deliveries to the destination: %d", len(destHandler.requests))
+			}
+			if got := len(deadLetterHandler.requests) == 1; got != tc.wantDeadLetter {
+				t.Fatalf("Unexpected deliveries to the dead letter: %d", len(deadLetterHandler.requests))
+			}
+			if tc.wantDeadLetter {
+				req := deadLetterHandler.popRequest(t)
+				if got := req.Headers.Get("Ce-" + ErrorDestinationExtension); got == "" {
+					t.Errorf("Expected the %s extension on the dead lettered event", ErrorDestinationExtension)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatchMessageOnError(t *testing.T) {
+	testCases := map[string]struct {
+		status      int
+		onError     bool
+		onErrorFail bool
+		wantErr     bool
+		wantCode    string
+	}{
+		"accepted": {
+			status:  http.StatusOK,
+			onError: true,
+		},
+		"failure without onError": {
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+		"failure routed": {
+			status:   http.StatusInternalServerError,
+			onError:  true,
+			wantCode: "500",
+		},
+		"failure not accepted by onError": {
+			status:      http.StatusBadRequest,
+			onError:     true,
+			onErrorFail: true,
+			wantErr:     true,
+			wantCode:    "400",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{
+				t: t,
+				response: &http.Response{
+					StatusCode: tc.status,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				},
+			}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+			onErrorHandler := &fakeHandler{t: t}
+			if tc.onErrorFail {
+				onErrorHandler.response = &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}
+			}
+			onErrorServer := httptest.NewServer(onErrorHandler)
+			defer onErrorServer.Close()
+
+			message := &Message{
+				Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234"},
+				Payload: []byte("payload"),
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			defaults := DispatchDefaults{OnError: getDomain(t, tc.onError, onErrorServer.URL)}
+			err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", defaults)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error from DispatchMessage. Expected %v. Actual: %v", tc.wantErr, err)
+			}
+			destHandler.popRequest(t)
+			if tc.wantCode == "" {
+				if len(onErrorHandler.requests) != 0 {
+					t.Errorf("Unexpected error destination requests: %+v", onErrorHandler.requests)
+				}
+				return
+			}
+			req := onErrorHandler.popRequest(t)
+			if got := req.Headers.Get("ce-knativeerrorcode"); got != tc.wantCode {
+				t.Errorf("Unexpected error code. Expected %q. Actual %q", tc.wantCode, got)
+			}
+			if got, want := req.Headers.Get("ce-knativeerrordest"), "http://"+getDomain(t, true, destServer.URL)+"/"; got != want {
+				t.Errorf("Unexpected error destination. Expected %q. Actual %q", want, got)
+			}
+			if req.Headers.Get("ce-knativeerrordata") == "" {
+				t.Errorf("Expected the description of the failure")
+			}
+			if got := req.Headers.Get("ce-knativeerrorattempts"); got != "1" {
+				t.Errorf("Unexpected error attempts. Expected %q. Actual %q", "1", got)
+			}
+			if req.Headers.Get("ce-id") != "1234" || req.Body != "payload" {
+				t.Errorf("Unexpected event sent to the error destination: %+v", req)
+			}
+			if _, ok := message.Headers["Ce-Knativeerrorcode"]; ok {
+				t.Errorf("The dispatched message was changed: %v", message.Headers)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageDeliveryHeaders(t *testing.T) {
+	testCases := map[string]struct {
+		message     *Message
+		defaults    DispatchDefaults
+		wantAttempt string
+		wantKey     string
+	}{
+		"first attempt": {
+			message:     &Message{Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234", "Ce-Source": "/orders"}},
+			defaults:    DispatchDefaults{Subscription: "ns/sub"},
+			wantAttempt: "1",
+			wantKey:     delivery.IdempotencyKey("ns/sub", "/orders", "1234"),
+		},
+		"redelivery": {
+			message:     &Message{Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234", "Ce-Source": "/orders"}},
+			defaults:    DispatchDefaults{Subscription: "ns/sub", Redelivery: true},
+			wantAttempt: "2",
+			wantKey:     delivery.IdempotencyKey("ns/sub", "/orders", "1234"),
+		},
+		"third attempt of a structured event": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","source":"/orders","type":"order"}`),
+			},
+			defaults:    DispatchDefaults{Subscription: "ns/sub", Redelivery: true, Attempt: 3},
+			wantAttempt: "3",
+			wantKey:     delivery.IdempotencyKey("ns/sub", "/orders", "1234"),
+		},
+		"stale headers replaced": {
+			message: &Message{Headers: map[string]string{
+				"Ce-Specversion":           "1.0",
+				"Ce-Id":                    "1234",
+				"Ce-Source":                "/orders",
+				"Knative-Delivery-Attempt": "5",
+				"Knative-Idempotency-Key":  "stale",
+			}},
+			defaults:    DispatchDefaults{Subscription: "ns/other"},
+			wantAttempt: "1",
+			wantKey:     delivery.IdempotencyKey("ns/other", "/orders", "1234"),
+		},
+		"no id": {
+			message:     &Message{Headers: map[string]string{"Ce-Specversion": "1.0"}},
+			wantAttempt: "1",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{t: t}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			if err := md.DispatchMessage(tc.message, getDomain(t, true, destServer.URL), "", tc.defaults); err != nil {
+				t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+			}
+			req := destHandler.popRequest(t)
+			if got := req.Headers[delivery.AttemptHeader]; len(got) != 1 || got[0] != tc.wantAttempt {
+				t.Errorf("Unexpected attempt. Expected %q. Actual %q", tc.wantAttempt, got)
+			}
+			if got := req.Headers.Get(delivery.IdempotencyKeyHeader); got != tc.wantKey {
+				t.Errorf("Unexpected idempotency key. Expected %q. Actual %q", tc.wantKey, got)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageDeliveryResult(t *testing.T) {
+	testCases := map[string]struct {
+		status      int
+		result      string
+		wantErr     bool
+		wantNack    bool
+		wantReplied bool
+		wantRouted  bool
+	}{
+		"nack with a success": {
+			status:   http.StatusAccepted,
+			result:   "nack",
+			wantErr:  true,
+			wantNack: true,
+		},
+		"nack with a failure, not routed": {
+			status:   http.StatusServiceUnavailable,
+			result:   "Nack",
+			wantErr:  true,
+			wantNack: true,
+		},
+		"drop with a failure": {
+			status: http.StatusBadRequest,
+			result: "drop",
+		},
+		"drop with a success, not replied": {
+			status: http.StatusOK,
+			result: "drop",
+		},
+		"failure without a result": {
+			status:     http.StatusBadRequest,
+			wantRouted: true,
+		},
+		"unknown result": {
+			status:      http.StatusOK,
+			result:      "other",
+			wantReplied: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			header := http.Header{"Ce-Specversion": {"1.0"}, "Ce-Id": {"reply"}}
+			if tc.result != "" {
+				header.Set(DeliveryResultHeader, tc.result)
+			}
+			destHandler := &fakeHandler{
+				t: t,
+				response: &http.Response{
+					StatusCode: tc.status,
+					Header:     header,
+					Body:       ioutil.NopCloser(strings.NewReader("reply")),
+				},
+			}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+			replyHandler := &fakeHandler{t: t}
+			replyServer := httptest.NewServer(replyHandler)
+			defer replyServer.Close()
+			onErrorHandler := &fakeHandler{t: t}
+			onErrorServer := httptest.NewServer(onErrorHandler)
+			defer onErrorServer.Close()
+
+			message := &Message{
+				Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234"},
+				Payload: []byte("payload"),
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			defaults := DispatchDefaults{OnError: getDomain(t, true, onErrorServer.URL)}
+			err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), getDomain(t, true, replyServer.URL), defaults)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error from DispatchMessage. Expected %v. Actual: %v", tc.wantErr, err)
+			}
+			if got := IsNack(err); got != tc.wantNack {
+				t.Errorf("Unexpected nack. Expected %v. Actual %v", tc.wantNack, got)
+			}
+			destHandler.popRequest(t)
+			if replied := len(replyHandler.requests) != 0; replied != tc.wantReplied {
+				t.Errorf("Unexpected reply. Expected %v. Actual %v", tc.wantReplied, replied)
+			}
+			if tc.wantReplied {
+				if got := replyHandler.popRequest(t).Headers.Get(DeliveryResultHeader); got != "" {
+					t.Errorf("Unexpected delivery result forwarded in the reply: %q", got)
+				}
+			}
+			if routed := len(onErrorHandler.requests) != 0; routed != tc.wantRouted {
+				t.Errorf("Unexpected error destination request. Expected %v. Actual %v", tc.wantRouted, routed)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageClaimCheck(t *testing.T) {
+	destHandler := &fakeHandler{t: t}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+
+	store := &memoryStore{data: map[string][]byte{"1234": []byte(`{"total": 10}`)}}
+	message := &Message{
+		Headers: map[string]string{
+			"Ce-Specversion":       "1.0",
+			"Ce-Knativeclaimcheck": "1234",
+			"Content-Type":         "application/json",
+		},
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar(), WithClaimCheckStore(store))
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	req := destHandler.popRequest(t)
+	if req.Body != `{"total": 10}` {
+		t.Errorf("Unexpected body. Expected the stored data. Actual %q", req.Body)
+	}
+	if got := req.Headers.Get("ce-knativeclaimcheck"); got != "" {
+		t.Errorf("Unexpected claim check header %q", got)
+	}
+	if len(message.Payload) != 0 {
+		t.Errorf("The dispatched message was changed: %q", message.Payload)
+	}
+
+	if err := md.DispatchMessage(&Message{
+		Headers: map[string]string{"Ce-Knativeclaimcheck": "unknown"},
+	}, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err == nil {
+		t.Errorf("Expected an error dispatching a message whose data is not stored")
+	}
+}
+
+func TestDispatchMessageStreamed(t *testing.T) {
+	destHandler := &fakeHandler{t: t}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+	streamed := func() *Message {
+		return &Message{
+			Headers: map[string]string{
+				"Ce-Specversion": "1.0",
+				"Content-Type":   "application/json",
+			},
+			body:     strings.NewReader(`{"total": 10}`),
+			bodySize: 13,
+		}
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+
+	message := streamed()
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	if req := destHandler.popRequest(t); req.Body != `{"total": 10}` {
+		t.Errorf("Unexpected body %q", req.Body)
+	}
+	if !message.IsStreamed() || message.Payload != nil {
+		t.Error("The body of the message was read rather than streamed")
+	}
+
+	// The transform reads the payload, so the body is read first.
+	tmpl, err := transform.Parse(`{"specversion": "1.0", "data": {"total": {{json .data.total}}}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the template: %v", err)
+	}
+	message = streamed()
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{Transform: tmpl}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	if req := destHandler.popRequest(t); !strings.Contains(req.Body, `"total":10`) {
+		t.Errorf("Unexpected body %q", req.Body)
+	}
+	if message.IsStreamed() || string(message.Payload) != `{"total": 10}` {
+		t.Errorf("The body of the message was not read. Payload %q", message.Payload)
+	}
+}
+
+func TestDispatchMessageReplySize(t *testing.T) {
+	testCases := map[string]struct {
+		contentType  string
+		body         string
+		expectedErr  bool
+		expectedBody string
+	}{
+		"binary reply streamed": {
+			contentType:  "application/json",
+			body:         `{"total": 10}`,
+			expectedBody: `{"total": 10}`,
+		},
+		"binary reply too large": {
+			contentType: "application/json",
+			body:        `{"total": 100}`,
+			expectedErr: true,
+		},
+		"structured reply": {
+			contentType: "application/cloudevents+json",
+			body:        `{"total": 10}`,
+			// The extensions of the message are propagated to the structured reply.
+			expectedBody: `{"knativettl":"254","total":10}`,
+		},
+		"structured reply too large": {
+			contentType: "application/cloudevents+json",
+			body:        `{"total": 100}`,
+			expectedErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			destHandler := &fakeHandler{
+				t: t,
+				response: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {tc.contentType}, "Ce-Specversion": {"1.0"}},
+					Body:       ioutil.NopCloser(strings.NewReader(tc.body)),
+				},
+			}
+			destServer := httptest.NewServer(destHandler)
+			defer destServer.Close()
+			// The replies whose body was read whole.
+			replies := make(chan string, 1)
+			replyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if body, err := ioutil.ReadAll(r.Body); err == nil {
+					replies <- string(body)
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer replyServer.Close()
+
+			md := NewMessageDispatcher(zap.NewNop().Sugar(), WithMaxReplySize(int64(len(`{"total": 10}`))))
+			err := md.DispatchMessage(&Message{Payload: []byte("destination")}, getDomain(t, true, destServer.URL), getDomain(t, true, replyServer.URL), DispatchDefaults{})
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("Unexpected error from DispatchMessage. Expected %v. Actual %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr {
+				// A streamed reply may be sent, but fails before its body is complete.
+				replyServer.Close()
+				select {
+				case body := <-replies:
+					t.Errorf("Unexpected reply forwarded: %q", body)
+				default:
+				}
+				return
+			}
+			if body := <-replies; body != tc.expectedBody {
+				t.Errorf("Unexpected reply body. Expected %q. Actual %q", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageReplyExtensions(t *testing.T) {
+	destHandler := &fakeHandler{
+		t: t,
+		response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type":    {"application/json"},
+				"Ce-Specversion":  {"1.0"},
+				"Ce-Partitionkey": {"reply"},
+			},
+			Body: ioutil.NopCloser(strings.NewReader(`{"total": 10}`)),
+		},
+	}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+	replyHandler := &fakeHandler{t: t}
+	replyServer := httptest.NewServer(replyHandler)
+	defer replyServer.Close()
+
+	message := &Message{
+		Headers: map[string]string{
+			"Ce-Specversion":  "1.0",
+			"Ce-Tenant":       "acme",
+			"Ce-Partitionkey": "original",
+			"Ce-Other":        "other",
+		},
+		Payload: []byte("destination"),
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar(), WithReplyExtensions("tenant", "partitionkey"))
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), getDomain(t, true, replyServer.URL), DispatchDefaults{}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	destHandler.popRequest(t)
+	req := replyHandler.popRequest(t)
+	if got := req.Headers.Get("Ce-Tenant"); got != "acme" {
+		t.Errorf("Unexpected tenant of the reply. Expected %q. Actual %q", "acme", got)
+	}
+	if got := req.Headers.Get("Ce-Partitionkey"); got != "reply" {
+		t.Errorf("Unexpected partitionkey %q, the reply's own should be kept", got)
+	}
+	if got := req.Headers.Get("Ce-Other"); got != "" {
+		t.Errorf("Unexpected extension of the reply %q", got)
+	}
+}
+
+func TestReplyExtensionsFromEnv(t *testing.T) {
+	defer os.Unsetenv(ReplyExtensionsEnv)
+	os.Setenv(ReplyExtensionsEnv, "")
+	if got := ReplyExtensionsFromEnv(); len(got) != 0 {
+		t.Errorf("Unexpected extensions when unset: %v", got)
+	}
+	os.Setenv(ReplyExtensionsEnv, "Tenant, partitionkey,,")
+	if diff := cmp.Diff([]string{"tenant", "partitionkey"}, ReplyExtensionsFromEnv()); diff != "" {
+		t.Errorf("Unexpected extensions (-want, +got): %v", diff)
+	}
+}
+
+func TestResponseStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	err := md.DispatchMessage(&Message{Payload: []byte("destination")}, getDomain(t, true, server.URL), "", DispatchDefaults{})
+	if code, ok := ResponseStatusCode(err); !ok || code != http.StatusUnprocessableEntity {
+		t.Errorf("Unexpected status code of %v. Expected %d. Actual %d, %v", err, http.StatusUnprocessableEntity, code, ok)
+	}
+
+	server.Close()
+	err = md.DispatchMessage(&Message{Payload: []byte("destination")}, getDomain(t, true, server.URL), "", DispatchDefaults{})
+	if code, ok := ResponseStatusCode(err); ok {
+		t.Errorf("Unexpected status code %d of the unreachable destination", code)
+	}
+}
+
+func TestDispatchMessageDecryption(t *testing.T) {
+	destHandler := &fakeHandler{t: t}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+
+	e := &xorEncrypter{}
+	message := &Message{
+		Headers: map[string]string{
+			"Ce-Specversion": "1.0",
+			"Content-Type":   "application/json",
+		},
+		Payload: []byte(`{"total": 10}`),
+	}
+	if err := message.Encrypt(e); err != nil {
+		t.Fatalf("Unexpected error encrypting: %v", err)
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar(), WithDecryption(e))
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	req := destHandler.popRequest(t)
+	if req.Body != `{"total": 10}` {
+		t.Errorf("Unexpected body. Expected the decrypted data. Actual %q", req.Body)
+	}
+	if got := req.Headers.Get("ce-knativeencrypted"); got != "" {
+		t.Errorf("Unexpected encryption header %q", got)
+	}
+}
+
+func TestDispatchMessageSignatureVerification(t *testing.T) {
+	destHandler := &fakeHandler{t: t}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+
+	signer := &prefixSigner{prefix: "signed:"}
+	md := NewMessageDispatcher(zap.NewNop().Sugar(), WithSignatureVerification(signer))
+	unsigned := &Message{
+		Headers: map[string]string{"Ce-Id": "1234"},
+		Payload: []byte("data"),
+	}
+	if err := md.DispatchMessage(unsigned, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err == nil {
+		t.Errorf("Expected an error dispatching an unsigned message")
+	}
+	forged := &Message{
+		Headers: map[string]string{"Ce-Id": "1234", "Ce-Knativesignature": "forged"},
+		Payload: []byte("data"),
+	}
+	if err := md.DispatchMessage(forged, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err == nil {
+		t.Errorf("Expected an error dispatching a message with an invalid signature")
+	}
+	signed := &Message{
+		Headers: map[string]string{"Ce-Id": "1234"},
+		Payload: []byte("data"),
+	}
+	if err := signed.Sign(signer); err != nil {
+		t.Fatalf("Unexpected error signing: %v", err)
+	}
+	if err := md.DispatchMessage(signed, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	req := destHandler.popRequest(t)
+	if got := req.Headers.Get("ce-knativesignature"); got == "" {
+		t.Errorf("Expected the signature to be delivered to the subscriber")
+	}
+}
+
+func TestDispatchMessageTLS(t *testing.T) {
+	destServer := httptest.NewTLSServer(&fakeHandler{t: t})
+	defer destServer.Close()
+	trusted := &tls.Config{RootCAs: x509.NewCertPool()}
+	trusted.RootCAs.AddCert(destServer.Certificate())
+
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	if err := md.DispatchMessage(&Message{}, destServer.URL, "", DispatchDefaults{}); err == nil {
+		t.Errorf("Expected an error dispatching to a server whose certificate is not trusted")
+	}
+	auth := &tlsAuthenticator{config: trusted}
+	for i := 0; i < 2; i++ {
+		if err := md.DispatchMessage(&Message{}, destServer.URL, "", DispatchDefaults{Auth: auth}); err != nil {
+			t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+		}
+	}
+	if len(md.tlsClients) != 1 {
+		t.Errorf("Expected a single client for the TLS config, got %d", len(md.tlsClients))
+	}
+	if err := md.DispatchMessage(&Message{}, destServer.URL, "", DispatchDefaults{Auth: &tlsAuthenticator{}}); err == nil {
+		t.Errorf("Expected an error dispatching without a TLS config to a server whose certificate is not trusted")
+	}
+
+	md = NewMessageDispatcher(zap.NewNop().Sugar(), WithTLSConfig(trusted))
+	if err := md.DispatchMessage(&Message{}, destServer.URL, "", DispatchDefaults{}); err != nil {
+		t.Errorf("Unexpected error from DispatchMessage: %v", err)
+	}
+}
+
+func TestDispatchMessageTrace(t *testing.T) {
+	const parent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	destHandler := &fakeHandler{t: t}
+	destServer := httptest.NewServer(destHandler)
+	defer destServer.Close()
+
+	message := &Message{
+		Headers: map[string]string{"Ce-Traceparent": parent},
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	if err := md.DispatchMessage(message, getDomain(t, true, destServer.URL), "", DispatchDefaults{}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	req := destHandler.popRequest(t)
+	got := req.Headers.Get("traceparent")
+	match := traceParentRegexp.FindStringSubmatch(got)
+	if match == nil {
+		t.Fatalf("Invalid traceparent %q", got)
+	}
+	if got == parent || match[1] != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("Expected a child span of %q. Actual %q", parent, got)
+	}
+	if ext := req.Headers.Get("ce-traceparent"); ext != got {
+		t.Errorf("Unexpected traceparent extension. Expected %q. Actual %q", got, ext)
+	}
+	if message.Headers["Ce-Traceparent"] != parent {
+		t.Errorf("The dispatched message was changed: %v", message.Headers)
+	}
+}
+
+func TestDispatchMessageMetrics(t *testing.T) {
+	status := http.StatusAccepted
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer destServer.Close()
+
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	defaults := DispatchDefaults{Channel: "test-ns/metrics", Subscription: "test-ns/sub"}
+	if err := md.DispatchMessage(&Message{}, getDomain(t, true, destServer.URL), "", defaults); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	defaults.Redelivery = true
+	if err := md.DispatchMessage(&Message{}, getDomain(t, true, destServer.URL), "", defaults); err == nil {
+		t.Fatalf("Expected an error from DispatchMessage")
+	}
+
+	if got := counterValue(t, messagesDelivered.WithLabelValues("test-ns", "test-ns/metrics", "test-ns/sub")); got != 1 {
+		t.Errorf("Unexpected delivered count. Expected 1. Actual %v", got)
+	}
+	if got := counterValue(t, deliveryFailures.WithLabelValues("test-ns", "test-ns/metrics", "test-ns/sub", "503")); got != 1 {
+		t.Errorf("Unexpected failure count. Expected 1. Actual %v", got)
+	}
+	if got := counterValue(t, deliveryRetries.WithLabelValues("test-ns", "test-ns/metrics", "test-ns/sub")); got != 1 {
+		t.Errorf("Unexpected retry count. Expected 1. Actual %v", got)
+	}
+	m := &dto.Metric{}
+	if err := deliveryLatency.WithLabelValues("test-ns", "test-ns/metrics", "test-ns/sub").(prometheus.Histogram).Write(m); err != nil {
+		t.Fatalf("Unable to read the latency: %v", err)
+	}
+	if got := m.GetHistogram().GetSampleCount(); got != 2 {
+		t.Errorf("Unexpected latency sample count. Expected 2. Actual %v", got)
+	}
+}
+
+func TestDispatchMessageMetricsGranularity(t *testing.T) {
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer destServer.Close()
+	defer SetMetricsGranularity("")
+
+	testCases := map[string]struct {
+		granularity string
+		labels      []string
+	}{
+		"channel": {
+			granularity: "channel",
+			labels:      []string{"granularity-ns", "granularity-ns/c", ""},
+		},
+		"namespace": {
+			granularity: "namespace",
+			labels:      []string{"granularity-ns", "", ""},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if err := SetMetricsGranularity(tc.granularity); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			before := counterValue(t, messagesDelivered.WithLabelValues(tc.labels...))
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			defaults := DispatchDefaults{Channel: "granularity-ns/c", Subscription: "granularity-ns/sub"}
+			if err := md.DispatchMessage(&Message{}, getDomain(t, true, destServer.URL), "", defaults); err != nil {
+				t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+			}
+			if got := counterValue(t, messagesDelivered.WithLabelValues(tc.labels...)); got != before+1 {
+				t.Errorf("Unexpected delivered count. Expected %v. Actual %v", before+1, got)
+			}
+		})
+	}
+
+	if err := SetMetricsGranularity("pod"); err == nil {
+		t.Error("Expected an error for an invalid granularity")
+	}
+}
+
+type recordingAuditSink struct {
+	records []AuditRecord
+}
+
+func (s *recordingAuditSink) Record(r AuditRecord) {
+	s.records = append(s.records, r)
+}
+
+func TestDispatchMessageAudit(t *testing.T) {
+	status := http.StatusAccepted
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer destServer.Close()
+
+	sink := &recordingAuditSink{}
+	md := NewMessageDispatcher(zap.NewNop().Sugar(), WithAuditSink(sink))
+	defaults := DispatchDefaults{Channel: "test-ns/audit", Subscription: "test-ns/sub"}
+	message := func(eventType string) *Message {
+		return &Message{
+			Headers: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          "event-1",
+				"ce-source":      "/test",
+				"ce-type":        eventType,
+			},
+		}
+	}
+	if err := md.DispatchMessage(message("dev.knative.test"), destServer.URL, "", defaults); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	defaults.Redelivery = true
+	if err := md.DispatchMessage(message("dev.knative.test"), destServer.URL, "", defaults); err == nil {
+		t.Fatalf("Expected an error from DispatchMessage")
+	}
+	status = http.StatusAccepted
+	if err := md.DispatchMessage(message(AuditEventType), destServer.URL, "", defaults); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("Unexpected number of audit records. Expected 2. Actual %v", len(sink.records))
+	}
+	want := []AuditRecord{{
+		EventID:      "event-1",
+		Source:       "/test",
+		Type:         "dev.knative.test",
+		Channel:      "test-ns/audit",
+		Subscription: "test-ns/sub",
+		Subscriber:   destServer.URL,
+		Outcome:      AuditDelivered,
+	}, {
+		EventID:      "event-1",
+		Source:       "/test",
+		Type:         "dev.knative.test",
+		Channel:      "test-ns/audit",
+		Subscription: "test-ns/sub",
+		Subscriber:   destServer.URL,
+		Outcome:      AuditFailed,
+		StatusCode:   http.StatusServiceUnavailable,
+		Redelivery:   true,
+	}}
+	ignore := cmpopts.IgnoreFields(AuditRecord{}, "Time", "LatencyMillis", "Error")
+	if diff := cmp.Diff(want, sink.records, ignore); diff != "" {
+		t.Errorf("Unexpected audit records (-want +got): %s", diff)
+	}
+	if sink.records[1].Error == "" {
+		t.Errorf("Expected the error of the failed delivery to be recorded")
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatalf("Unable to read the counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestResolveURL(t *testing.T) {
+	testCases := map[string]struct {
+		destination string
+		want        string
+	}{
+		"url": {
+			destination: "https://example.com:8443/events?a=b",
+			want:        "https://example.com:8443/events?a=b",
+		},
+		"fully qualified name": {
+			destination: "svc.ns.svc.cluster.local",
+			want:        "http://svc.ns.svc.cluster.local/",
+		},
+		"single label": {
+			destination: "svc",
+			want:        "http://svc.default-ns.svc.cluster.local/",
+		},
+		"single label with port and path": {
+			destination: "svc:8443/events",
+			want:        "http://svc.default-ns.svc.cluster.local:8443/events",
+		},
+		"fully qualified name with path": {
+			destination: "svc.ns.svc.cluster.local/events/v1",
+			want:        "http://svc.ns.svc.cluster.local/events/v1",
+		},
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := md.resolveURL(tc.destination, "default-ns").String(); got != tc.want {
+				t.Errorf("Unexpected URL. Expected %q, actual %q", tc.want, got)
+			}
+		})
+	}
+}
+
+type authenticatorFunc func(req *http.Request) error
+
+func (f authenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+type tlsAuthenticator struct {
+	config *tls.Config
+}
+
+func (a *tlsAuthenticator) Authenticate(*http.Request) error {
+	return nil
+}
+
+func (a *tlsAuthenticator) TLSConfig() *tls.Config {
+	return a.config
+}
+
 func getDomain(t *testing.T, shouldSend bool, serverURL string) string {
 	if shouldSend {
 		server, err := url.Parse(serverURL)
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// EncryptionExtension is the CloudEvents extension marking the messages whose data is
+	// encrypted while they are in a channel. In the structured content mode, it holds the
+	// encrypted data members of the event.
+	EncryptionExtension = "knativeencrypted"
+)
+
+// Encrypter encrypts the data of messages while they are in a channel, so that the system backing
+// the channel does not store it in plaintext. Implementations are in
+// pkg/provisioners/encryption.
+type Encrypter interface {
+	// Encrypt returns the ciphertext of plaintext.
+	Encrypt(plaintext []byte) ([]byte, error)
+	// Decrypt returns the plaintext of ciphertext.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// Encrypt replaces the data of the message by its ciphertext, and sets the EncryptionExtension.
+// The attributes of the message are kept, so that it can still be routed.
+func (m *Message) Encrypt(e Encrypter) error {
+	if !m.isStructured() {
+		ciphertext, err := e.Encrypt(m.Payload)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt the data of the message: %v", err)
+		}
+		m.Payload = ciphertext
+		m.setExtension(EncryptionExtension, "true")
+		return nil
+	}
+
+	event := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		return err
+	}
+	// The data members are encrypted as a JSON object, so that they are restored as they were.
+	data := map[string]json.RawMessage{}
+	for _, name := range []string{"data", "data_base64"} {
+		if v, ok := event[name]; ok {
+			data[name] = v
+			delete(event, name)
+		}
+	}
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := e.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt the data of the message: %v", err)
+	}
+	event[EncryptionExtension], _ = json.Marshal(base64.StdEncoding.EncodeToString(ciphertext))
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	return nil
+}
+
+// Decrypt returns a copy of the message with the data that Encrypt encrypted restored. The message
+// itself, which may be dispatched concurrently, is left unchanged. Messages that were not
+// encrypted are returned as is.
+func (m *Message) Decrypt(e Encrypter) (*Message, error) {
+	encrypted := m.extension(EncryptionExtension)
+	if encrypted == "" {
+		return m, nil
+	}
+
+	c := &Message{
+		Headers: make(map[string]string, len(m.Headers)),
+	}
+	for k, v := range m.Headers {
+		c.Headers[k] = v
+	}
+	if !m.isStructured() {
+		for k := range c.Headers {
+			if strings.ToLower(k) == cloudEventsHeaderPrefix+EncryptionExtension {
+				delete(c.Headers, k)
+			}
+		}
+		plaintext, err := e.Decrypt(m.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt the data of the message: %v", err)
+		}
+		c.Payload = plaintext
+		return c, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the encrypted data of the message: %v", err)
+	}
+	plaintext, err := e.Decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the data of the message: %v", err)
+	}
+	event := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		return nil, err
+	}
+	data := map[string]json.RawMessage{}
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode the data of the message: %v", err)
+	}
+	for k, v := range data {
+		event[k] = v
+	}
+	delete(event, EncryptionExtension)
+	if c.Payload, err = json.Marshal(event); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// xorEncrypter is an Encrypter flipping the bits of the data, so that the ciphertext differs from
+// the plaintext.
+type xorEncrypter struct {
+	err error
+}
+
+func (e *xorEncrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	return e.xor(plaintext)
+}
+
+func (e *xorEncrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	return e.xor(ciphertext)
+}
+
+func (e *xorEncrypter) xor(b []byte) ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := make([]byte, len(b))
+	for i := range b {
+		out[i] = b[i] ^ 0xff
+	}
+	return out, nil
+}
+
+func TestMessageEncryption(t *testing.T) {
+	testCases := map[string]*Message{
+		"binary": {
+			Headers: map[string]string{
+				"Ce-Specversion": "1.0",
+				"Ce-Id":          "1234",
+				"Content-Type":   "application/json",
+			},
+			Payload: []byte(`{"total": 10}`),
+		},
+		"structured": {
+			Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+			Payload: []byte(`{"specversion":"1.0","id":"1234","type":"com.example.order","data":{"total":10}}`),
+		},
+		"structured base64": {
+			Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+			Payload: []byte(`{"specversion":"1.0","id":"1234","data_base64":"AAEC"}`),
+		},
+	}
+	for n, m := range testCases {
+		t.Run(n, func(t *testing.T) {
+			e := &xorEncrypter{}
+			original := &Message{Headers: map[string]string{}, Payload: m.Payload}
+			for k, v := range m.Headers {
+				original.Headers[k] = v
+			}
+
+			if err := m.Encrypt(e); err != nil {
+				t.Fatalf("Unexpected error encrypting: %v", err)
+			}
+			if m.extension(EncryptionExtension) == "" {
+				t.Fatalf("Expected the %s extension to be set", EncryptionExtension)
+			}
+			if data := original.Data(); bytes.Contains(m.Payload, data) {
+				t.Errorf("Expected the encrypted message not to contain its data %q, got %q", data, m.Payload)
+			}
+			if got, want := m.Attributes()["id"], original.Attributes()["id"]; got != want {
+				t.Errorf("Expected the encrypted message to keep its attributes, id %q, got %q", want, got)
+			}
+
+			got, err := m.Decrypt(e)
+			if err != nil {
+				t.Fatalf("Unexpected error decrypting: %v", err)
+			}
+			if diff := cmp.Diff(original.Headers, got.Headers); diff != "" {
+				t.Errorf("Unexpected headers (-want +got): %v", diff)
+			}
+			if diff := cmp.Diff(normalizeJSON(original.Payload), normalizeJSON(got.Payload)); diff != "" {
+				t.Errorf("Unexpected payload (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestMessageEncryptionErrors(t *testing.T) {
+	m := &Message{
+		Headers: map[string]string{"Ce-Specversion": "1.0"},
+		Payload: []byte(`{"total": 10}`),
+	}
+	if err := m.Encrypt(&xorEncrypter{err: errors.New("no key")}); err == nil {
+		t.Errorf("Expected an error when the data cannot be encrypted")
+	}
+
+	// Messages that were not encrypted are dispatched as they are.
+	if got, err := m.Decrypt(&xorEncrypter{err: errors.New("no key")}); err != nil || got != m {
+		t.Errorf("Expected the message to be returned as is, got %v, %v", got, err)
+	}
+
+	if err := m.Encrypt(&xorEncrypter{}); err != nil {
+		t.Fatalf("Unexpected error encrypting: %v", err)
+	}
+	if _, err := m.Decrypt(&xorEncrypter{err: errors.New("no key")}); err == nil {
+		t.Errorf("Expected an error when the data cannot be decrypted")
+	}
+}
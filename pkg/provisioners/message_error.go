@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	// ErrorDestinationExtension is the CloudEvents extension holding the URL of the destination
+	// that failed to accept an event routed to an error destination or a dead letter.
+	ErrorDestinationExtension = "knativeerrordest"
+	// ErrorCodeExtension is the CloudEvents extension holding the HTTP status code the destination
+	// last answered with. It is not set when the destination could not be reached.
+	ErrorCodeExtension = "knativeerrorcode"
+	// ErrorDataExtension is the CloudEvents extension holding the description of the failure.
+	ErrorDataExtension = "knativeerrordata"
+	// ErrorAttemptsExtension is the CloudEvents extension holding the number of attempts to deliver
+	// the event to the destination.
+	ErrorAttemptsExtension = "knativeerrorattempts"
+)
+
+// withError returns a copy of the message with the extensions describing the failure err of its
+// delivery to destination.
+func (m *Message) withError(destination *url.URL, err error) *Message {
+	return m.WithDeliveryFailure(destination.String(), err, 1)
+}
+
+// WithDeliveryFailure returns a copy of the message with the extensions describing the failure of
+// its delivery to destination, after the given number of attempts, the last of which failed with
+// err. It is used for the messages sent to a dead letter, so that they can be triaged.
+func (m *Message) WithDeliveryFailure(destination string, err error, attempts int) *Message {
+	c := m.withExtension(ErrorDestinationExtension, destination)
+	if code, ok := ResponseStatusCode(err); ok {
+		c.setExtension(ErrorCodeExtension, strconv.Itoa(code))
+	}
+	c.setExtension(ErrorDataExtension, err.Error())
+	if attempts > 0 {
+		c.setExtension(ErrorAttemptsExtension, strconv.Itoa(attempts))
+	}
+	return c
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/knative/eventing/pkg/provisioners/cloudeventspb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcIdleTimeout is how long the connection to a gRPC destination is kept open without
+// deliveries.
+const grpcIdleTimeout = 5 * time.Minute
+
+// grpcConnKey identifies the connection to a gRPC destination: its target, and the TLS config of
+// the connection if it is secure.
+type grpcConnKey struct {
+	target string
+	secure bool
+	config *tls.Config
+}
+
+// grpcConn is a pooled connection to a gRPC destination.
+type grpcConn struct {
+	conn *grpc.ClientConn
+	// active is the number of deliveries using conn, and lastUsed the time the last one started.
+	active   int
+	lastUsed time.Time
+}
+
+// grpcConnFor returns the connection to the gRPC destination url, for a delivery authenticated by
+// auth, and the func to call when the delivery is done. The connections that are no longer used
+// are closed.
+func (d *MessageDispatcher) grpcConnFor(url *url.URL, auth Authenticator) (*grpc.ClientConn, func(), error) {
+	key := grpcConnKey{
+		target: grpcTarget(url),
+		secure: url.Scheme == "https",
+	}
+	if key.secure {
+		key.config = d.tlsConfig
+		if ta, ok := auth.(TLSAuthenticator); ok && ta.TLSConfig() != nil {
+			key.config = ta.TLSConfig()
+		}
+	}
+
+	now := time.Now()
+	d.grpcConnsLock.Lock()
+	defer d.grpcConnsLock.Unlock()
+	for k, c := range d.grpcConns {
+		if c.active == 0 && now.Sub(c.lastUsed) > grpcIdleTimeout {
+			c.conn.Close()
+			delete(d.grpcConns, k)
+		}
+	}
+	c, ok := d.grpcConns[key]
+	if !ok {
+		opt := grpc.WithInsecure()
+		if key.secure {
+			opt = grpc.WithTransportCredentials(credentials.NewTLS(key.config))
+		}
+		conn, err := grpc.Dial(key.target, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+		c = &grpcConn{conn: conn}
+		d.grpcConns[key] = c
+	}
+	c.active++
+	c.lastUsed = now
+	release := func() {
+		d.grpcConnsLock.Lock()
+		defer d.grpcConnsLock.Unlock()
+		c.active--
+	}
+	return c.conn, release, nil
+}
+
+// grpcTarget returns the host and port of url, defaulting to the port of its scheme.
+func grpcTarget(url *url.URL) string {
+	if url.Port() != "" {
+		return url.Host
+	}
+	if url.Scheme == "https" {
+		return net.JoinHostPort(url.Hostname(), "443")
+	}
+	return net.JoinHostPort(url.Hostname(), "80")
+}
+
+// executeGRPC delivers message to the Subscriber gRPC service at url, in the CloudEvents protobuf
+// format, with the further headers as metadata. The path of url is not used. It returns the message
+// the destination replied with, or nil if it did not reply.
+func (d *MessageDispatcher) executeGRPC(url *url.URL, message *Message, headers http.Header, auth Authenticator) (*Message, error) {
+	d.logger.Infof("Dispatching message to %s with gRPC", url.String())
+	md := metadata.MD{}
+	for name, values := range headers {
+		md.Set(strings.ToLower(name), values...)
+	}
+	if tp := message.TraceParent(); tp != "" {
+		md.Set(traceParentHeader, tp)
+	}
+	if auth != nil {
+		// Authenticators add their credentials to HTTP requests, which are sent as metadata.
+		req, err := http.NewRequest(http.MethodPost, url.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create request %v", err)
+		}
+		if err := auth.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("unable to authenticate request %v", err)
+		}
+		for name, values := range req.Header {
+			md.Set(strings.ToLower(name), values...)
+		}
+	}
+
+	conn, release, err := d.grpcConnFor(url, auth)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect %v", err)
+	}
+	defer release()
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	res, err := cloudeventspb.NewSubscriberClient(conn).Deliver(ctx, message.toCloudEventPB())
+	if err != nil {
+		return nil, err
+	}
+	if res.Reply == nil {
+		return nil, nil
+	}
+	response := messageFromCloudEventPB(res.Reply)
+	if correlationID, ok := message.Headers[correlationIDHeaderName]; ok {
+		response.Headers[correlationIDHeaderName] = correlationID
+	}
+	response.PropagateExtensions(message)
+	response.propagateExtensions(message, d.replyExtensions)
+	return response, nil
+}
+
+// toCloudEventPB returns the event of the message in the CloudEvents protobuf format. Its data is
+// text data if it has a textual content type, and binary data otherwise.
+func (m *Message) toCloudEventPB() *cloudeventspb.CloudEvent {
+	attrs := m.Attributes()
+	event := &cloudeventspb.CloudEvent{
+		Id:          attrs["id"],
+		Source:      attrs["source"],
+		SpecVersion: attrs["specversion"],
+		Type:        attrs["type"],
+	}
+	for _, name := range []string{"id", "source", "specversion", "type", "data_base64"} {
+		delete(attrs, name)
+	}
+	if len(attrs) > 0 {
+		event.Attributes = make(map[string]*cloudeventspb.CloudEventAttributeValue, len(attrs))
+		for name, value := range attrs {
+			event.Attributes[name] = attributeValuePB(name, value)
+		}
+	}
+	data := m.Data()
+	if isTextContentType(attrs["datacontenttype"]) && utf8.Valid(data) {
+		event.Data = &cloudeventspb.CloudEvent_TextData{TextData: string(data)}
+	} else {
+		event.Data = &cloudeventspb.CloudEvent_BinaryData{BinaryData: data}
+	}
+	return event
+}
+
+// attributeValuePB returns the value of the attribute name in the CloudEvents protobuf format. The
+// attributes whose type is defined by the spec are sent with that type, and the extensions, whose
+// type is unknown once they have been carried by HTTP headers, as strings.
+func attributeValuePB(name, value string) *cloudeventspb.CloudEventAttributeValue {
+	switch name {
+	case "time":
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			if ts, err := ptypes.TimestampProto(t); err == nil {
+				return &cloudeventspb.CloudEventAttributeValue{Attr: &cloudeventspb.CloudEventAttributeValue_CeTimestamp{CeTimestamp: ts}}
+			}
+		}
+	case "dataschema", "schemaurl":
+		if u, err := url.Parse(value); err == nil && u.IsAbs() {
+			return &cloudeventspb.CloudEventAttributeValue{Attr: &cloudeventspb.CloudEventAttributeValue_CeUri{CeUri: value}}
+		}
+	}
+	return &cloudeventspb.CloudEventAttributeValue{Attr: &cloudeventspb.CloudEventAttributeValue_CeString{CeString: value}}
+}
+
+// attributeValueString returns the canonical string encoding of an attribute value in the
+// CloudEvents protobuf format, as it is carried by HTTP headers. It returns false if the value is
+// not set.
+func attributeValueString(value *cloudeventspb.CloudEventAttributeValue) (string, bool) {
+	switch v := value.GetAttr().(type) {
+	case *cloudeventspb.CloudEventAttributeValue_CeBoolean:
+		return strconv.FormatBool(v.CeBoolean), true
+	case *cloudeventspb.CloudEventAttributeValue_CeInteger:
+		return strconv.FormatInt(int64(v.CeInteger), 10), true
+	case *cloudeventspb.CloudEventAttributeValue_CeString:
+		return v.CeString, true
+	case *cloudeventspb.CloudEventAttributeValue_CeBytes:
+		return base64.StdEncoding.EncodeToString(v.CeBytes), true
+	case *cloudeventspb.CloudEventAttributeValue_CeUri:
+		return v.CeUri, true
+	case *cloudeventspb.CloudEventAttributeValue_CeUriRef:
+		return v.CeUriRef, true
+	case *cloudeventspb.CloudEventAttributeValue_CeTimestamp:
+		t, err := ptypes.Timestamp(v.CeTimestamp)
+		if err != nil {
+			return "", false
+		}
+		return t.UTC().Format(time.RFC3339Nano), true
+	default:
+		return "", false
+	}
+}
+
+// messageFromCloudEventPB returns the message of an event in the CloudEvents protobuf format, in
+// the binary content mode.
+func messageFromCloudEventPB(event *cloudeventspb.CloudEvent) *Message {
+	headers := map[string]string{
+		"ce-id":          event.Id,
+		"ce-source":      event.Source,
+		"ce-specversion": event.SpecVersion,
+		"ce-type":        event.Type,
+	}
+	for name, value := range event.Attributes {
+		v, ok := attributeValueString(value)
+		if !ok {
+			continue
+		}
+		if name == "datacontenttype" {
+			headers["Content-Type"] = v
+			continue
+		}
+		headers[cloudEventsHeaderPrefix+name] = v
+	}
+	var payload []byte
+	switch data := event.Data.(type) {
+	case *cloudeventspb.CloudEvent_BinaryData:
+		payload = data.BinaryData
+	case *cloudeventspb.CloudEvent_TextData:
+		payload = []byte(data.TextData)
+	}
+	return &Message{Headers: headers, Payload: payload}
+}
+
+// isTextContentType returns true if contentType is a text or JSON media type.
+func isTextContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/xml" ||
+		strings.HasSuffix(mediaType, "+xml")
+}
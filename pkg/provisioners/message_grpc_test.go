@@ -0,0 +1,324 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners/cloudeventspb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSubscriber records the events delivered to it, and replies with reply, or fails with err.
+type fakeSubscriber struct {
+	reply *cloudeventspb.CloudEvent
+	err   error
+
+	lock   sync.Mutex
+	events []*cloudeventspb.CloudEvent
+	md     []metadata.MD
+}
+
+func (s *fakeSubscriber) Deliver(ctx context.Context, event *cloudeventspb.CloudEvent) (*cloudeventspb.DeliverResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, event)
+	s.md = append(s.md, md)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &cloudeventspb.DeliverResponse{Reply: s.reply}, nil
+}
+
+// startSubscriber serves s on a local port, and returns its address.
+func startSubscriber(t *testing.T, s *fakeSubscriber) (string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	cloudeventspb.RegisterSubscriberServer(server, s)
+	go server.Serve(l)
+	return l.Addr().String(), server.Stop
+}
+
+func TestDispatchMessageGRPC(t *testing.T) {
+	testCases := map[string]struct {
+		message    *Message
+		reply      *cloudeventspb.CloudEvent
+		want       *cloudeventspb.CloudEvent
+		wantReply  map[string]string
+		wantBody   string
+		wantNoSend bool
+	}{
+		"binary": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Id":          "1234",
+					"Ce-Source":      "/source",
+					"Ce-Type":        "dev.knative.test",
+					"Ce-Subject":     "subject",
+					"Content-Type":   "application/octet-stream",
+				},
+				Payload: []byte{0xff, 0x00},
+			},
+			want: &cloudeventspb.CloudEvent{
+				Id:          "1234",
+				Source:      "/source",
+				SpecVersion: "1.0",
+				Type:        "dev.knative.test",
+				Attributes: map[string]*cloudeventspb.CloudEventAttributeValue{
+					"subject":         ceString("subject"),
+					"datacontenttype": ceString("application/octet-stream"),
+					"knativettl":      ceString("254"),
+				},
+				Data: &cloudeventspb.CloudEvent_BinaryData{BinaryData: []byte{0xff, 0x00}},
+			},
+			wantNoSend: true,
+		},
+		"structured with reply": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","source":"/source","type":"dev.knative.test","datacontenttype":"application/json","data":{"total":10}}`),
+			},
+			reply: &cloudeventspb.CloudEvent{
+				Id:          "5678",
+				Source:      "/subscriber",
+				SpecVersion: "1.0",
+				Type:        "dev.knative.reply",
+				Attributes: map[string]*cloudeventspb.CloudEventAttributeValue{
+					"datacontenttype": ceString("text/plain"),
+				},
+				Data: &cloudeventspb.CloudEvent_TextData{TextData: "reply"},
+			},
+			want: &cloudeventspb.CloudEvent{
+				Id:          "1234",
+				Source:      "/source",
+				SpecVersion: "1.0",
+				Type:        "dev.knative.test",
+				Attributes: map[string]*cloudeventspb.CloudEventAttributeValue{
+					"datacontenttype": ceString("application/json"),
+					"knativettl":      ceString("254"),
+				},
+				Data: &cloudeventspb.CloudEvent_TextData{TextData: `{"total":10}`},
+			},
+			wantReply: map[string]string{
+				"ce-id":          "5678",
+				"ce-source":      "/subscriber",
+				"ce-specversion": "1.0",
+				"ce-type":        "dev.knative.reply",
+				"ce-knativettl":  "254",
+				"content-type":   "text/plain",
+			},
+			wantBody: "reply",
+		},
+		"typed attributes": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion":         "1.0",
+					"Ce-Id":                  "1234",
+					"Ce-Source":              "/source",
+					"Ce-Type":                "dev.knative.test",
+					"Ce-Time":                "2018-04-05T17:31:00.5Z",
+					"Ce-Dataschema":          "https://example.com/schema.json",
+					"Ce-Comexampleextension": "value",
+					"Content-Type":           "text/plain",
+				},
+				Payload: []byte("hello"),
+			},
+			reply: &cloudeventspb.CloudEvent{
+				Id:          "5678",
+				Source:      "/subscriber",
+				SpecVersion: "1.0",
+				Type:        "dev.knative.reply",
+				Attributes: map[string]*cloudeventspb.CloudEventAttributeValue{
+					"time":      {Attr: &cloudeventspb.CloudEventAttributeValue_CeTimestamp{CeTimestamp: &timestamp.Timestamp{Seconds: 1522949460, Nanos: 500000000}}},
+					"dataref":   {Attr: &cloudeventspb.CloudEventAttributeValue_CeUriRef{CeUriRef: "/events/5678"}},
+					"sampled":   {Attr: &cloudeventspb.CloudEventAttributeValue_CeBoolean{CeBoolean: true}},
+					"sequence":  {Attr: &cloudeventspb.CloudEventAttributeValue_CeInteger{CeInteger: -42}},
+					"signature": {Attr: &cloudeventspb.CloudEventAttributeValue_CeBytes{CeBytes: []byte{0xff, 0x00}}},
+					"unset":     {},
+				},
+			},
+			want: &cloudeventspb.CloudEvent{
+				Id:          "1234",
+				Source:      "/source",
+				SpecVersion: "1.0",
+				Type:        "dev.knative.test",
+				Attributes: map[string]*cloudeventspb.CloudEventAttributeValue{
+					"time":                {Attr: &cloudeventspb.CloudEventAttributeValue_CeTimestamp{CeTimestamp: &timestamp.Timestamp{Seconds: 1522949460, Nanos: 500000000}}},
+					"dataschema":          {Attr: &cloudeventspb.CloudEventAttributeValue_CeUri{CeUri: "https://example.com/schema.json"}},
+					"comexampleextension": ceString("value"),
+					"datacontenttype":     ceString("text/plain"),
+					"knativettl":          ceString("254"),
+				},
+				Data: &cloudeventspb.CloudEvent_TextData{TextData: "hello"},
+			},
+			wantReply: map[string]string{
+				"ce-time":      "2018-04-05T17:31:00.5Z",
+				"ce-dataref":   "/events/5678",
+				"ce-sampled":   "true",
+				"ce-sequence":  "-42",
+				"ce-signature": "/wA=",
+			},
+		},
+		"no reply": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Id":          "1234",
+					"Ce-Source":      "/source",
+					"Ce-Type":        "dev.knative.test",
+					"Content-Type":   "text/plain",
+				},
+				Payload: []byte("hello"),
+			},
+			want: &cloudeventspb.CloudEvent{
+				Id:          "1234",
+				Source:      "/source",
+				SpecVersion: "1.0",
+				Type:        "dev.knative.test",
+				Attributes: map[string]*cloudeventspb.CloudEventAttributeValue{
+					"datacontenttype": ceString("text/plain"),
+					"knativettl":      ceString("254"),
+				},
+				Data: &cloudeventspb.CloudEvent_TextData{TextData: "hello"},
+			},
+			wantNoSend: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			subscriber := &fakeSubscriber{reply: tc.reply}
+			addr, stop := startSubscriber(t, subscriber)
+			defer stop()
+			replyHandler := &fakeHandler{t: t}
+			replyServer := httptest.NewServer(replyHandler)
+			defer replyServer.Close()
+
+			md := NewMessageDispatcher(zap.NewNop().Sugar())
+			auth := authenticatorFunc(func(req *http.Request) error {
+				req.Header.Set("Authorization", "Bearer token")
+				return nil
+			})
+			defaults := DispatchDefaults{
+				Auth:     auth,
+				Protocol: eventingduck.GRPCDeliveryProtocol,
+			}
+			err := md.DispatchMessage(tc.message, addr, getDomain(t, true, replyServer.URL), defaults)
+			if err != nil {
+				t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+			}
+
+			if len(subscriber.events) != 1 {
+				t.Fatalf("Expected 1 delivered event, got %d", len(subscriber.events))
+			}
+			if got := subscriber.events[0]; !proto.Equal(tc.want, got) {
+				t.Errorf("Unexpected delivered event. Expected %v. Actual %v", tc.want, got)
+			}
+			if got := subscriber.md[0].Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+				t.Errorf("Unexpected authorization metadata: %v", got)
+			}
+
+			if tc.wantNoSend {
+				if len(replyHandler.requests) != 0 {
+					t.Errorf("Unexpected reply requests: %+v", replyHandler.requests)
+				}
+				return
+			}
+			req := replyHandler.popRequest(t)
+			for k, v := range tc.wantReply {
+				if got := req.Headers.Get(k); got != v {
+					t.Errorf("Unexpected reply header %s. Expected %q. Actual %q", k, v, got)
+				}
+			}
+			if req.Body != tc.wantBody {
+				t.Errorf("Unexpected reply body. Expected %q. Actual %q", tc.wantBody, req.Body)
+			}
+		})
+	}
+}
+
+func TestDispatchMessageGRPCOnError(t *testing.T) {
+	subscriber := &fakeSubscriber{err: status.Error(codes.Unavailable, "overloaded")}
+	addr, stop := startSubscriber(t, subscriber)
+	defer stop()
+	onErrorHandler := &fakeHandler{t: t}
+	onErrorServer := httptest.NewServer(onErrorHandler)
+	defer onErrorServer.Close()
+
+	message := &Message{
+		Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234"},
+		Payload: []byte("payload"),
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	if err := md.DispatchMessage(message, addr, "", DispatchDefaults{Protocol: eventingduck.GRPCDeliveryProtocol}); err == nil {
+		t.Errorf("Expected an error from DispatchMessage")
+	}
+
+	defaults := DispatchDefaults{
+		OnError:  getDomain(t, true, onErrorServer.URL),
+		Protocol: eventingduck.GRPCDeliveryProtocol,
+	}
+	if err := md.DispatchMessage(message, addr, "", defaults); err != nil {
+		t.Errorf("Unexpected error from DispatchMessage: %v", err)
+	}
+	req := onErrorHandler.popRequest(t)
+	if req.Headers.Get("ce-id") != "1234" || req.Body != "payload" {
+		t.Errorf("Unexpected event sent to the error destination: %+v", req)
+	}
+	if req.Headers.Get("ce-knativeerrordata") == "" {
+		t.Errorf("Expected the description of the failure")
+	}
+	if len(md.grpcConns) != 1 {
+		t.Errorf("Expected the connection to the subscriber to be reused, got %d connections", len(md.grpcConns))
+	}
+}
+
+func TestGRPCTarget(t *testing.T) {
+	testCases := map[string]string{
+		"http://subscriber.ns.svc.cluster.local/":      "subscriber.ns.svc.cluster.local:80",
+		"https://subscriber.example.com/events":        "subscriber.example.com:443",
+		"http://subscriber.ns.svc.cluster.local:8080/": "subscriber.ns.svc.cluster.local:8080",
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	for destination, want := range testCases {
+		if got := grpcTarget(md.resolveURL(destination, "default-ns")); got != want {
+			t.Errorf("Unexpected target of %q. Expected %q, actual %q", destination, want, got)
+		}
+	}
+}
+
+func ceString(s string) *cloudeventspb.CloudEventAttributeValue {
+	return &cloudeventspb.CloudEventAttributeValue{Attr: &cloudeventspb.CloudEventAttributeValue_CeString{CeString: s}}
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opencensus.io/trace"
+)
+
+const (
+	// EventHistoryExtension is the CloudEvents extension listing the hostnames of the channels an
+	// event traversed, in order, separated by "; ".
+	EventHistoryExtension = "knativehistory"
+
+	// TraceParentExtension is the CloudEvents distributed tracing extension holding the W3C trace
+	// context of an event. It is also sent as the traceparent HTTP header.
+	TraceParentExtension = "traceparent"
+
+	// TTLExtension is the CloudEvents extension holding the number of dispatches an event may still
+	// go through. It is decremented by every dispatch, and the event is dropped once it reaches
+	// zero, which breaks the loops of replies sent back to the channel they came from.
+	TTLExtension = "knativettl"
+
+	// DefaultTTL is the TTL of the events that do not have one.
+	DefaultTTL = 255
+
+	// ReplyExtensionsEnv is the comma separated list of the further CloudEvents extensions that the
+	// replies of subscribers inherit from the events they respond to, e.g. tenant,partitionkey.
+	ReplyExtensionsEnv = "REPLY_EXTENSIONS"
+
+	historySeparator  = "; "
+	traceParentHeader = "traceparent"
+)
+
+var traceParentRegexp = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-([0-9a-f]{2})$`)
+
+// History returns the hostnames of the channels the message traversed, in order.
+func (m *Message) History() []string {
+	h := m.extension(EventHistoryExtension)
+	if h == "" {
+		return nil
+	}
+	return strings.Split(h, historySeparator)
+}
+
+// AppendToHistory records that the message traversed the channel with the hostname host.
+func (m *Message) AppendToHistory(host string) {
+	m.setExtension(EventHistoryExtension, strings.Join(append(m.History(), host), historySeparator))
+}
+
+// TraceParent returns the W3C trace context of the message, or the empty string if it has none.
+func (m *Message) TraceParent() string {
+	if tp := m.extension(TraceParentExtension); tp != "" {
+		return tp
+	}
+	if !m.isStructured() {
+		return headerValue(m.Headers, traceParentHeader)
+	}
+	return ""
+}
+
+// StartSpan starts the span name, the child of the span of the trace context of the message, and
+// sets the trace context of the message to it. A new trace is started if the message has none.
+// The caller must end the span.
+func (m *Message) StartSpan(name string, opts ...trace.StartOption) *trace.Span {
+	var span *trace.Span
+	if parent, ok := parseTraceParent(m.TraceParent()); ok {
+		_, span = trace.StartSpanWithRemoteParent(context.Background(), name, parent, opts...)
+	} else {
+		_, span = trace.StartSpan(context.Background(), name, opts...)
+	}
+	m.setExtension(TraceParentExtension, formatTraceParent(span.SpanContext()))
+	return span
+}
+
+// TTL returns the number of dispatches the message may still go through.
+func (m *Message) TTL() int {
+	ttl, err := strconv.Atoi(m.extension(TTLExtension))
+	if err != nil {
+		return DefaultTTL
+	}
+	return ttl
+}
+
+// PropagateExtensions copies the history, the trace context and the TTL of original to the
+// message, when the message does not set them. It is used for the messages derived from original,
+// like the replies of subscribers, which often drop the extensions they do not understand.
+func (m *Message) PropagateExtensions(original *Message) {
+	m.propagateExtensions(original, []string{EventHistoryExtension, TraceParentExtension, TTLExtension})
+}
+
+// propagateExtensions copies the extensions names of original to the message, when the message
+// does not set them.
+func (m *Message) propagateExtensions(original *Message, names []string) {
+	for _, name := range names {
+		if m.extension(name) == "" {
+			if v := original.extension(name); v != "" {
+				m.setExtension(name, v)
+			}
+		}
+	}
+}
+
+// ReplyExtensionsFromEnv returns the extensions listed by ReplyExtensionsEnv, none if it is not
+// set.
+func ReplyExtensionsFromEnv() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv(ReplyExtensionsEnv), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseTraceParent returns the span context of the W3C trace context tp. It returns false if tp is
+// not a valid trace context.
+func parseTraceParent(tp string) (trace.SpanContext, bool) {
+	sc := trace.SpanContext{}
+	if !traceParentRegexp.MatchString(tp) {
+		return sc, false
+	}
+	parts := strings.Split(tp, "-")
+	hex.Decode(sc.TraceID[:], []byte(parts[1]))
+	hex.Decode(sc.SpanID[:], []byte(parts[2]))
+	flags, _ := hex.DecodeString(parts[3])
+	sc.TraceOptions = trace.TraceOptions(flags[0])
+	return sc, true
+}
+
+// formatTraceParent returns the W3C trace context of the span context sc.
+func formatTraceParent(sc trace.SpanContext) string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, byte(sc.TraceOptions))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// extension returns the value of the CloudEvents extension name of the message, in either content
+// mode.
+func (m *Message) extension(name string) string {
+	if m.isStructured() {
+		event := map[string]json.RawMessage{}
+		if err := json.Unmarshal(m.Payload, &event); err != nil {
+			return ""
+		}
+		var v string
+		json.Unmarshal(event[name], &v)
+		return v
+	}
+	return headerValue(m.Headers, cloudEventsHeaderPrefix+name)
+}
+
+// withExtension returns a copy of the message with the CloudEvents extension name set, leaving the
+// message, which may be dispatched concurrently, unchanged.
+func (m *Message) withExtension(name, value string) *Message {
+	c := &Message{
+		Headers:  make(map[string]string, len(m.Headers)+1),
+		Payload:  m.Payload,
+		body:     m.body,
+		bodySize: m.bodySize,
+	}
+	for k, v := range m.Headers {
+		c.Headers[k] = v
+	}
+	c.setExtension(name, value)
+	return c
+}
+
+// setExtension sets the CloudEvents extension name of the message, in either content mode.
+// Structured messages that are not JSON objects are left unchanged.
+func (m *Message) setExtension(name, value string) {
+	if m.isStructured() {
+		event := map[string]json.RawMessage{}
+		if err := json.Unmarshal(m.Payload, &event); err != nil {
+			return
+		}
+		event[name], _ = json.Marshal(value)
+		if payload, err := json.Marshal(event); err == nil {
+			m.Payload = payload
+		}
+		return
+	}
+	if m.Headers == nil {
+		m.Headers = map[string]string{}
+	}
+	header := cloudEventsHeaderPrefix + name
+	for k := range m.Headers {
+		if strings.ToLower(k) == header {
+			delete(m.Headers, k)
+		}
+	}
+	m.Headers[header] = value
+}
+
+func headerValue(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.ToLower(k) == name {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMessageAppendToHistory(t *testing.T) {
+	testCases := map[string]struct {
+		message *Message
+		want    []string
+	}{
+		"binary without history": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Specversion": "1.0"},
+			},
+			want: []string{"c.ns.svc.cluster.local"},
+		},
+		"binary with history": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion":    "1.0",
+					"Ce-Knativehistory": "a.ns.svc.cluster.local; b.ns.svc.cluster.local",
+				},
+			},
+			want: []string{"a.ns.svc.cluster.local", "b.ns.svc.cluster.local", "c.ns.svc.cluster.local"},
+		},
+		"structured with history": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion": "1.0", "knativehistory": "a.ns.svc.cluster.local"}`),
+			},
+			want: []string{"a.ns.svc.cluster.local", "c.ns.svc.cluster.local"},
+		},
+		"structured invalid JSON": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`not json`),
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tc.message.AppendToHistory("c.ns.svc.cluster.local")
+			if diff := cmp.Diff(tc.want, tc.message.History()); diff != "" {
+				t.Errorf("Unexpected history (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMessageStartSpan(t *testing.T) {
+	const parent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	testCases := map[string]struct {
+		message   *Message
+		wantTrace string
+	}{
+		"binary extension": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Traceparent": parent},
+			},
+			wantTrace: "0af7651916cd43dd8448eb211c80319c",
+		},
+		"binary header": {
+			message: &Message{
+				Headers: map[string]string{"Traceparent": parent},
+			},
+			wantTrace: "0af7651916cd43dd8448eb211c80319c",
+		},
+		"structured extension": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion": "1.0", "traceparent": "` + parent + `"}`),
+			},
+			wantTrace: "0af7651916cd43dd8448eb211c80319c",
+		},
+		"invalid trace context": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Traceparent": "garbage"},
+			},
+		},
+		"no trace context": {
+			message: &Message{},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tc.message.StartSpan("test").End()
+			got := tc.message.TraceParent()
+			match := traceParentRegexp.FindStringSubmatch(got)
+			if match == nil {
+				t.Fatalf("Invalid traceparent %q", got)
+			}
+			if got == parent {
+				t.Errorf("Expected a new span, got the parent %q", got)
+			}
+			if tc.wantTrace != "" && match[1] != tc.wantTrace {
+				t.Errorf("Unexpected trace ID. Expected %q. Actual %q", tc.wantTrace, match[1])
+			}
+		})
+	}
+}
+
+func TestMessagePropagateExtensions(t *testing.T) {
+	original := &Message{
+		Headers: map[string]string{
+			"Ce-Knativehistory": "a.ns.svc.cluster.local",
+			"Ce-Traceparent":    "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		},
+	}
+	reply := &Message{
+		Headers: map[string]string{
+			"Ce-Specversion": "1.0",
+			"Ce-Traceparent": "00-11111111111111111111111111111111-2222222222222222-01",
+		},
+	}
+	reply.PropagateExtensions(original)
+	if got := strings.Join(reply.History(), ","); got != "a.ns.svc.cluster.local" {
+		t.Errorf("Unexpected history %q", got)
+	}
+	if got := reply.TraceParent(); got != "00-11111111111111111111111111111111-2222222222222222-01" {
+		t.Errorf("Unexpected traceparent %q, the reply's own should be kept", got)
+	}
+}
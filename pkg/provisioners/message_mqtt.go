@@ -0,0 +1,188 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	// mqttIdleTimeout is how long the client of an MQTT broker is kept connected without
+	// deliveries.
+	mqttIdleTimeout = 5 * time.Minute
+	// mqttTimeout bounds the connection to a broker and each publication.
+	mqttTimeout = 30 * time.Second
+)
+
+// mqttClientKey identifies the client of an MQTT broker: the broker it connects to, its
+// credentials and the TLS config of the connection if it is secure.
+type mqttClientKey struct {
+	addr     string
+	username string
+	password string
+	secure   bool
+	config   *tls.Config
+}
+
+// mqttClient is a pooled client of an MQTT broker.
+type mqttClient struct {
+	client mqtt.Client
+	// active is the number of deliveries using client, and lastUsed the time the last one started.
+	active   int
+	lastUsed time.Time
+}
+
+// mqttClientFor returns the client of the MQTT broker of url, for a delivery authenticated by auth,
+// and the func to call when the delivery is done. The clients that are no longer used or whose
+// connection failed are closed.
+func (d *MessageDispatcher) mqttClientFor(url *url.URL, auth Authenticator) (mqtt.Client, func(), error) {
+	key := mqttClientKey{
+		addr:   mqttAddr(url),
+		secure: url.Scheme == "https" || url.Scheme == "mqtts",
+	}
+	if key.secure {
+		key.config = d.tlsConfig
+		if ta, ok := auth.(TLSAuthenticator); ok && ta.TLSConfig() != nil {
+			key.config = ta.TLSConfig()
+		}
+		if key.config == nil {
+			key.config = &tls.Config{ServerName: url.Hostname()}
+		}
+	}
+	if auth != nil {
+		// Authenticators add their credentials to HTTP requests, whose basic auth is the username
+		// and password of the client.
+		req, err := http.NewRequest(http.MethodPost, url.String(), nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create request %v", err)
+		}
+		if err := auth.Authenticate(req); err != nil {
+			return nil, nil, fmt.Errorf("unable to authenticate request %v", err)
+		}
+		key.username, key.password, _ = req.BasicAuth()
+	}
+
+	now := time.Now()
+	d.mqttClientsLock.Lock()
+	defer d.mqttClientsLock.Unlock()
+	for k, c := range d.mqttClients {
+		if c.active == 0 && (now.Sub(c.lastUsed) > mqttIdleTimeout || !c.client.IsConnected()) {
+			c.client.Disconnect(0)
+			delete(d.mqttClients, k)
+		}
+	}
+	c, ok := d.mqttClients[key]
+	if !ok || !c.client.IsConnected() {
+		client, err := dialMQTT(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			// The failed client is replaced.
+			c.client.Disconnect(0)
+		}
+		c = &mqttClient{client: client}
+		d.mqttClients[key] = c
+	}
+	c.active++
+	c.lastUsed = now
+	release := func() {
+		d.mqttClientsLock.Lock()
+		defer d.mqttClientsLock.Unlock()
+		c.active--
+	}
+	return c.client, release, nil
+}
+
+// dialMQTT connects a client to the broker of key. The client does not connect again when its
+// connection is lost, it is replaced by the next delivery.
+func dialMQTT(key mqttClientKey) (mqtt.Client, error) {
+	opts := mqtt.NewClientOptions().
+		SetProtocolVersion(4).
+		SetUsername(key.username).
+		SetPassword(key.password).
+		SetConnectTimeout(mqttTimeout).
+		SetAutoReconnect(false)
+	if key.secure {
+		opts.AddBroker("ssl://" + key.addr).SetTLSConfig(key.config)
+	} else {
+		opts.AddBroker("tcp://" + key.addr)
+	}
+	client := mqtt.NewClient(opts)
+	if err := waitMQTT(client.Connect(), "connecting to the MQTT broker"); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// waitMQTT waits up to mqttTimeout for token to complete, and returns its error. The WaitTimeout
+// of the tokens is not used, as it holds the lock the token is completed with.
+func waitMQTT(token mqtt.Token, action string) error {
+	done := make(chan struct{})
+	go func() {
+		token.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return token.Error()
+	case <-time.After(mqttTimeout):
+		return fmt.Errorf("timed out %s", action)
+	}
+}
+
+// mqttAddr returns the host and port of url, defaulting to the MQTT port of its scheme.
+func mqttAddr(url *url.URL) string {
+	if url.Port() != "" {
+		return url.Host
+	}
+	if url.Scheme == "https" || url.Scheme == "mqtts" {
+		return net.JoinHostPort(url.Hostname(), "8883")
+	}
+	return net.JoinHostPort(url.Hostname(), "1883")
+}
+
+// executeMQTT publishes message to the topic of the MQTT broker at url, its path without the
+// leading slash, with QoS 1. MQTT 3.1.1 messages have no properties, so the event is sent in the
+// structured content mode of the CloudEvents MQTT binding. The connection is secured with TLS if
+// the scheme of url is https or mqtts. MQTT destinations do not reply.
+func (d *MessageDispatcher) executeMQTT(url *url.URL, message *Message, auth Authenticator) error {
+	topic := strings.TrimPrefix(url.Path, "/")
+	if topic == "" {
+		return errors.New("the MQTT destination has no topic")
+	}
+	payload, err := message.StructuredJSON()
+	if err != nil {
+		return fmt.Errorf("unable to encode the event: %v", err)
+	}
+	d.logger.Infof("Dispatching message to %s with MQTT", url.String())
+	client, release, err := d.mqttClientFor(url, auth)
+	if err != nil {
+		return fmt.Errorf("unable to connect %v", err)
+	}
+	defer release()
+	return waitMQTT(client.Publish(topic, 1, false, payload), "waiting for the publication to be acknowledged")
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/google/go-cmp/cmp"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"go.uber.org/zap"
+)
+
+func TestDispatchMessageMQTT(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	defer l.Close()
+	published := make(chan *packets.PublishPacket, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			p, err := packets.ReadPacket(conn)
+			if err != nil {
+				return
+			}
+			switch p := p.(type) {
+			case *packets.ConnectPacket:
+				packets.NewControlPacket(packets.Connack).Write(conn)
+			case *packets.PublishPacket:
+				puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				puback.MessageID = p.MessageID
+				puback.Write(conn)
+				published <- p
+			case *packets.PingreqPacket:
+				packets.NewControlPacket(packets.Pingresp).Write(conn)
+			}
+		}
+	}()
+
+	message := &Message{
+		Headers: map[string]string{
+			"Ce-Specversion": "1.0",
+			"Ce-Id":          "1234",
+			"Ce-Source":      "/source",
+			"Ce-Type":        "dev.knative.command",
+			"Content-Type":   "application/json",
+		},
+		Payload: []byte(`{"on":true}`),
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	destination := "mqtt://" + l.Addr().String() + "/devices/lamp"
+	if err := md.DispatchMessage(message, destination, "", DispatchDefaults{Protocol: eventingduck.MQTTDeliveryProtocol}); err != nil {
+		t.Fatalf("Unexpected error from DispatchMessage: %v", err)
+	}
+
+	p := <-published
+	if p.TopicName != "devices/lamp" || p.Qos != 1 {
+		t.Errorf("Unexpected publication to %q with QoS %d", p.TopicName, p.Qos)
+	}
+	want := `{"data":{"on":true},"datacontenttype":"application/json","id":"1234","knativettl":"254","source":"/source","specversion":"1.0","type":"dev.knative.command"}`
+	if diff := cmp.Diff(want, string(p.Payload)); diff != "" {
+		t.Errorf("Unexpected payload (-want +got): %s", diff)
+	}
+}
+
+func TestDispatchMessageMQTTOnError(t *testing.T) {
+	// A port nothing listens on.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	onErrorHandler := &fakeHandler{t: t}
+	onErrorServer := httptest.NewServer(onErrorHandler)
+	defer onErrorServer.Close()
+
+	message := &Message{
+		Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234"},
+		Payload: []byte("payload"),
+	}
+	md := NewMessageDispatcher(zap.NewNop().Sugar())
+	for _, destination := range []string{"mqtt://" + addr + "/devices/lamp", "mqtt://" + addr} {
+		if err := md.DispatchMessage(message, destination, "", DispatchDefaults{Protocol: eventingduck.MQTTDeliveryProtocol}); err == nil {
+			t.Errorf("Expected an error from DispatchMessage to %s", destination)
+		}
+	}
+
+	defaults := DispatchDefaults{
+		OnError:  getDomain(t, true, onErrorServer.URL),
+		Protocol: eventingduck.MQTTDeliveryProtocol,
+	}
+	if err := md.DispatchMessage(message, "mqtt://"+addr+"/devices/lamp", "", defaults); err != nil {
+		t.Errorf("Unexpected error from DispatchMessage: %v", err)
+	}
+	onErrorHandler.popRequest(t)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/knative/eventing/pkg/delivery"
+)
+
+// The header of the responses by which subscribers explicitly nack or drop events, and its values.
+// They are defined by the delivery package for the authors of sinks.
+const (
+	DeliveryResultHeader = delivery.ResultHeader
+	DeliveryResultNack   = delivery.ResultNack
+	DeliveryResultDrop   = delivery.ResultDrop
+)
+
+// errDropped is returned by executeRequest when the destination asked for the event to be dropped.
+var errDropped = errors.New("destination dropped the event")
+
+// nackError is the failure of a delivery that the destination explicitly nacked.
+type nackError struct {
+	statusCode int
+}
+
+func (e *nackError) Error() string {
+	return fmt.Sprintf("destination nacked the event with status %d", e.statusCode)
+}
+
+// IsNack returns whether err is the failure of a delivery that the destination explicitly nacked,
+// which should be redelivered.
+func IsNack(err error) bool {
+	if de, ok := err.(*deliveryError); ok {
+		err = de.err
+	}
+	_, ok := err.(*nackError)
+	return ok
+}
+
+// deliveryResult returns the error of the response res whose DeliveryResultHeader asks for the event
+// to be nacked or dropped, or nil if it does not.
+func deliveryResult(res *http.Response) error {
+	switch strings.ToLower(strings.TrimSpace(res.Header.Get(DeliveryResultHeader))) {
+	case DeliveryResultNack:
+		return &nackError{statusCode: res.StatusCode}
+	case DeliveryResultDrop:
+		return errDropped
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"errors"
+	"net/url"
+)
+
+// Pusher pushes messages to the consumers of a subscription connected to the dispatcher, for the
+// subscribers that cannot expose an endpoint. Push returns once a consumer received the message,
+// and fails if none is connected.
+type Pusher interface {
+	Push(subscription string, message *Message) error
+}
+
+// WithPusher makes the MessageDispatcher push the messages of the subscriptions with the SSE
+// protocol with p.
+func WithPusher(p Pusher) DispatcherOption {
+	return func(d *MessageDispatcher) {
+		d.pusher = p
+	}
+}
+
+// pushURL returns the URL identifying the consumers of subscription, the namespace/name of a
+// Subscription, in the errors and audit records of the deliveries pushed to them.
+func pushURL(subscription string) *url.URL {
+	return &url.URL{Path: "/subscriptions/" + subscription}
+}
+
+// executePush pushes message to the consumers of subscription.
+func (d *MessageDispatcher) executePush(message *Message, subscription string) error {
+	if d.pusher == nil {
+		return errors.New("the dispatcher does not push events to connected consumers")
+	}
+	d.logger.Infof("Pushing message to the consumers of %s", subscription)
+	return d.pusher.Push(subscription, message)
+}
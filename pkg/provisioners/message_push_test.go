@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"go.uber.org/zap"
+)
+
+// fakePusher records the messages pushed to it, and fails with err.
+type fakePusher struct {
+	err    error
+	pushed map[string][]*Message
+}
+
+func (p *fakePusher) Push(subscription string, message *Message) error {
+	if p.pushed == nil {
+		p.pushed = make(map[string][]*Message)
+	}
+	p.pushed[subscription] = append(p.pushed[subscription], message)
+	return p.err
+}
+
+func TestDispatchMessagePush(t *testing.T) {
+	testCases := map[string]struct {
+		pusher  *fakePusher
+		onError bool
+		wantErr bool
+	}{
+		"pushed": {
+			pusher: &fakePusher{},
+		},
+		"push disabled": {
+			wantErr: true,
+		},
+		"no consumer": {
+			pusher:  &fakePusher{err: errors.New("no consumer connected")},
+			wantErr: true,
+		},
+		"no consumer routed": {
+			pusher:  &fakePusher{err: errors.New("no consumer connected")},
+			onError: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			replyHandler := &fakeHandler{t: t}
+			replyServer := httptest.NewServer(replyHandler)
+			defer replyServer.Close()
+			onErrorHandler := &fakeHandler{t: t}
+			onErrorServer := httptest.NewServer(onErrorHandler)
+			defer onErrorServer.Close()
+
+			var opts []DispatcherOption
+			if tc.pusher != nil {
+				opts = append(opts, WithPusher(tc.pusher))
+			}
+			md := NewMessageDispatcher(zap.NewNop().Sugar(), opts...)
+			message := &Message{
+				Headers: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1234"},
+				Payload: []byte("payload"),
+			}
+			defaults := DispatchDefaults{
+				OnError:      getDomain(t, tc.onError, onErrorServer.URL),
+				Subscription: "test-namespace/test-subscription",
+				Protocol:     eventingduck.SSEDeliveryProtocol,
+			}
+			err := md.DispatchMessage(message, "", getDomain(t, true, replyServer.URL), defaults)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error from DispatchMessage. Expected %v. Actual: %v", tc.wantErr, err)
+			}
+			if tc.pusher != nil && len(tc.pusher.pushed["test-namespace/test-subscription"]) != 1 {
+				t.Errorf("Unexpected pushed messages: %v", tc.pusher.pushed)
+			}
+			if len(replyHandler.requests) != 0 {
+				t.Errorf("Unexpected reply requests: %+v", replyHandler.requests)
+			}
+			if tc.onError {
+				req := onErrorHandler.popRequest(t)
+				if got, want := req.Headers.Get("ce-knativeerrordest"), "/subscriptions/test-namespace/test-subscription"; got != want {
+					t.Errorf("Unexpected error destination. Expected %q. Actual %q", want, got)
+				}
+			}
+		})
+	}
+}
@@ -18,10 +18,10 @@ package provisioners
 
 import (
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"strings"
 
+	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 )
 
@@ -35,13 +35,121 @@ type MessageReceiver struct {
 	receiverFunc    func(ChannelReference, *Message) error
 	forwardHeaders  map[string]bool
 	forwardPrefixes []string
+	strict          bool
+
+	// maxBodySize is the largest body of the messages accepted, in bytes. They are unbounded when
+	// it is 0.
+	maxBodySize int64
+
+	// streaming is true when the bodies of the messages may be streamed to the receiverFunc.
+	streaming bool
+
+	// claimCheck stores the data of the messages larger than claimCheckThreshold bytes. It is nil
+	// when messages are passed on whole.
+	claimCheck          ClaimCheckStore
+	claimCheckThreshold int
+
+	// signer signs the messages that are not signed yet. It is nil when messages are not signed.
+	signer Signer
+
+	// verifier verifies the signature of the messages that are already signed. It is nil when
+	// their signature is replaced by the signer.
+	verifier Verifier
+
+	// encrypter encrypts the data of the messages. It is nil when messages are passed on in
+	// plaintext.
+	encrypter Encrypter
+
+	// tap is given a copy of every message received. It is nil when messages are not tapped.
+	tap Tap
 
 	logger *zap.SugaredLogger
 }
 
+// ReceiverOption configures optional behavior of a MessageReceiver.
+type ReceiverOption func(*MessageReceiver)
+
+// WithStrictCloudEvents makes the MessageReceiver reject the messages that are not valid
+// CloudEvents, rather than passing them to the receiverFunc.
+func WithStrictCloudEvents() ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.strict = true
+	}
+}
+
+// WithMaxBodySize makes the MessageReceiver reject the messages whose body is larger than max
+// bytes, with a 413. Bodies are unbounded when max is 0.
+func WithMaxBodySize(max int64) ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.maxBodySize = max
+	}
+}
+
+// WithBodyStreaming makes the MessageReceiver stream the body of the messages in the binary
+// content mode from their request, rather than read it in their Payload, when it does not need
+// their Payload itself: when it neither signs, verifies, encrypts, checks in nor taps them. The
+// receiverFunc must then call Message.ReadBody before it reads the Payload or sends the message
+// more than once, and must be done with the body when it returns.
+func WithBodyStreaming() ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.streaming = true
+	}
+}
+
+// WithClaimCheck makes the MessageReceiver move the data of the messages larger than threshold
+// bytes to store, before passing them to the receiverFunc. A MessageDispatcher created with
+// WithClaimCheckStore restores the data before delivering the messages.
+func WithClaimCheck(store ClaimCheckStore, threshold int) ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.claimCheck = store
+		r.claimCheckThreshold = threshold
+	}
+}
+
+// WithSigning makes the MessageReceiver sign the messages that are not signed yet with s, before
+// passing them to the receiverFunc. The signature of the messages that are already signed is
+// replaced, unless the MessageReceiver is created WithSignedMessageVerification.
+func WithSigning(s Signer) ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.signer = s
+	}
+}
+
+// WithSignedMessageVerification makes the MessageReceiver verify the signature of the messages
+// that are already signed with v, and reject those whose signature is invalid with a 400. The
+// signature of the others is kept by WithSigning.
+func WithSignedMessageVerification(v Verifier) ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.verifier = v
+	}
+}
+
+// WithEncryption makes the MessageReceiver encrypt the data of the messages with e, before
+// passing them to the receiverFunc. A MessageDispatcher created with WithDecryption decrypts the
+// data before delivering the messages.
+func WithEncryption(e Encrypter) ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.encrypter = e
+	}
+}
+
+// Tap observes the messages received by a MessageReceiver, e.g. to stream them to a developer
+// debugging a channel. Publish must not modify the message nor block.
+type Tap interface {
+	Publish(channel ChannelReference, message *Message)
+}
+
+// WithTap makes the MessageReceiver publish every message it receives to tap, before it is
+// passed to the receiverFunc.
+func WithTap(tap Tap) ReceiverOption {
+	return func(r *MessageReceiver) {
+		r.tap = tap
+	}
+}
+
 // NewMessageReceiver creates a message receiver passing new messages to the
 // receiverFunc.
-func NewMessageReceiver(receiverFunc func(ChannelReference, *Message) error, logger *zap.SugaredLogger) *MessageReceiver {
+func NewMessageReceiver(receiverFunc func(ChannelReference, *Message) error, logger *zap.SugaredLogger, opts ...ReceiverOption) *MessageReceiver {
 	receiver := &MessageReceiver{
 		receiverFunc:    receiverFunc,
 		forwardHeaders:  headerSet(forwardHeaders),
@@ -49,6 +157,9 @@ func NewMessageReceiver(receiverFunc func(ChannelReference, *Message) error, log
 
 		logger: logger,
 	}
+	for _, opt := range opts {
+		opt(receiver)
+	}
 	return receiver
 }
 
@@ -105,11 +216,25 @@ func (r *MessageReceiver) handler() http.Handler {
 }
 
 // HandleRequest is an http Handler function. The request is converted to a
-// Message and emitted to the receiver func.
+// Message and emitted to the receiver func. A batch of events in the
+// application/cloudevents-batch+json content type is split, and each of its
+// events is emitted as a Message in the structured content mode. The batch is
+// rejected as a whole if one of its events is, and the events emitted before
+// an error are not taken back. CloudEvents of older versions of
+// the specification are converted to CloudEventsSpecVersion first. In strict
+// mode, messages that are not valid CloudEvents are rejected. The host is
+// appended to the history of the message, and a new span of its trace is
+// started. With a claim check, the data of oversized messages is stored
+// outside of the channel. With body streaming, the bodies of the messages in
+// the binary content mode may be streamed to the receiverFunc.
 //
 // The response status codes:
-//   202 - the message was sent to subscribers
+//   202 - the message, or every event of the batch, was sent to subscribers
+//   400 - the message is a CloudEvent of an unsupported spec version, or is not
+//         a valid CloudEvent in strict mode, or is an invalid batch. The body
+//         describes the problems. Or the message carries an invalid signature.
 //   404 - the request was for an unknown channel
+//   413 - the body of the message is larger than the maximum size
 //   500 - an error occurred processing the request
 func (r *MessageReceiver) HandleRequest(res http.ResponseWriter, req *http.Request) {
 	host := req.Host
@@ -122,37 +247,164 @@ func (r *MessageReceiver) HandleRequest(res http.ResponseWriter, req *http.Reque
 	}
 
 	message, err := r.fromRequest(req)
+	if err == errBodyTooLarge {
+		r.reject(res, http.StatusRequestEntityTooLarge, rejectBodyTooLarge, fmt.Errorf("the body is larger than %d bytes", r.maxBodySize))
+		return
+	}
 	if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	err = r.receiverFunc(channel, message)
-	if err != nil {
-		if err == ErrUnknownChannel {
-			res.WriteHeader(http.StatusNotFound)
-		} else {
-			res.WriteHeader(http.StatusInternalServerError)
+	mode := receiveModeBinary
+	messages := []*Message{message}
+	if message.isBatch() {
+		mode = receiveModeBatch
+		if messages, err = message.splitBatch(); err != nil {
+			r.reject(res, http.StatusBadRequest, rejectInvalidBatch, err)
+			return
+		}
+	} else if message.isStructured() {
+		mode = receiveModeStructured
+	}
+	// Every event of a batch is checked before any is passed on, so that invalid batches are
+	// rejected as a whole.
+	for i, m := range messages {
+		if err := m.ToCloudEventsSpecVersion(); err != nil {
+			r.rejectEvents(res, len(messages), http.StatusBadRequest, rejectUnsupportedSpecVersion, batchError(mode, i, err))
+			return
+		}
+		if r.strict {
+			if err := m.ValidateCloudEvent(); err != nil {
+				r.rejectEvents(res, len(messages), http.StatusBadRequest, rejectInvalidCloudEvent, batchError(mode, i, err))
+				return
+			}
 		}
-		return
 	}
 
+	namespace, name := ChannelMetricLabels(channel)
+	for _, m := range messages {
+		if status := r.receive(channel, host, m); status != http.StatusAccepted {
+			res.WriteHeader(status)
+			return
+		}
+		messagesReceived.WithLabelValues(namespace, name, mode).Inc()
+	}
 	res.WriteHeader(http.StatusAccepted)
 }
 
+// batchError returns err, prefixed with the index of the event it is about in a batch.
+func batchError(mode string, i int, err error) error {
+	if mode != receiveModeBatch {
+		return err
+	}
+	return fmt.Errorf("event %d of the batch: %v", i, err)
+}
+
+// receive passes the message on to the receiverFunc, returning the status of the response.
+func (r *MessageReceiver) receive(channel ChannelReference, host string, message *Message) int {
+	message.AppendToHistory(host)
+	if r.tap != nil {
+		r.tap.Publish(channel, message)
+	}
+	span := message.StartSpan("channel.ingress", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	span.AddAttributes(trace.StringAttribute("channel", channel.String()))
+	if message.extension(SignatureExtension) != "" {
+		if r.verifier != nil {
+			if err := message.VerifySignature(r.verifier); err != nil {
+				r.logger.Info("Rejected the message", zap.String("reason", rejectInvalidSignature), zap.Error(err))
+				messagesRejected.WithLabelValues(rejectInvalidSignature).Inc()
+				return http.StatusBadRequest
+			}
+		} else if r.signer != nil {
+			// The signature cannot be trusted, so it is replaced by the one of this ingress.
+			message.setExtension(SignatureExtension, "")
+		}
+	}
+	// The message is signed before its data is encrypted.
+	if r.signer != nil {
+		if err := message.Sign(r.signer); err != nil {
+			r.logger.Error("Unable to sign the message", zap.Error(err))
+			return http.StatusInternalServerError
+		}
+	}
+	// The data is encrypted before it is checked in, so that it is not stored in plaintext by the
+	// claim check either.
+	if r.encrypter != nil {
+		if err := message.Encrypt(r.encrypter); err != nil {
+			r.logger.Error("Unable to encrypt the message", zap.Error(err))
+			return http.StatusInternalServerError
+		}
+	}
+	if r.claimCheck != nil {
+		if err := message.CheckIn(r.claimCheck, r.claimCheckThreshold); err != nil {
+			r.logger.Error("Unable to check in the message", zap.Error(err))
+			return http.StatusInternalServerError
+		}
+	}
+
+	if err := r.receiverFunc(channel, message); err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+		switch err {
+		case ErrUnknownChannel:
+			return http.StatusNotFound
+		case errBodyTooLarge:
+			// A streamed body was read by the receiverFunc.
+			messagesRejected.WithLabelValues(rejectBodyTooLarge).Inc()
+			return http.StatusRequestEntityTooLarge
+		}
+		return http.StatusInternalServerError
+	}
+	return http.StatusAccepted
+}
+
+// reject responds to a message that is rejected for the reason with status and a body describing
+// err.
+func (r *MessageReceiver) reject(res http.ResponseWriter, status int, reason string, err error) {
+	r.rejectEvents(res, 1, status, reason, err)
+}
+
+// rejectEvents responds to a message of count events that is rejected for the reason with status
+// and a body describing err.
+func (r *MessageReceiver) rejectEvents(res http.ResponseWriter, count int, status int, reason string, err error) {
+	r.logger.Info("Rejected the message", zap.String("reason", reason), zap.Int("events", count), zap.Error(err))
+	messagesRejected.WithLabelValues(reason).Add(float64(count))
+	res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	res.WriteHeader(status)
+	res.Write([]byte(err.Error()))
+}
+
 func (r *MessageReceiver) fromRequest(req *http.Request) (*Message, error) {
-	body, err := ioutil.ReadAll(req.Body)
+	message := &Message{
+		Headers: r.fromHTTPHeaders(req.Header),
+	}
+	if r.streams(message) {
+		if r.maxBodySize > 0 && req.ContentLength > r.maxBodySize {
+			return nil, errBodyTooLarge
+		}
+		message.body = limitBody(req.Body, r.maxBodySize)
+		message.bodySize = req.ContentLength
+		return message, nil
+	}
+	// The body is read at once into a buffer of its declared length, rather than grown as it is
+	// read.
+	body, err := readBody(req.Body, req.ContentLength, r.maxBodySize)
 	if err != nil {
 		return nil, err
 	}
-	headers := r.fromHTTPHeaders(req.Header)
-	message := &Message{
-		Headers: headers,
-		Payload: body,
-	}
+	message.Payload = body
 	return message, nil
 }
 
+// streams returns true if the body of message, whose headers were read, is streamed to the
+// receiverFunc. The conversions and checks of the messages in the binary content mode only read
+// their headers.
+func (r *MessageReceiver) streams(message *Message) bool {
+	return r.streaming && r.signer == nil && r.verifier == nil && r.encrypter == nil && r.claimCheck == nil && r.tap == nil &&
+		!message.isBatch() && !message.isStructured()
+}
+
 // fromHTTPHeaders converts HTTP headers into a message header map.
 //
 // Only headers whitelisted as safe are copied. If an HTTP header exists
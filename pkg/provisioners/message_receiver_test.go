@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -38,6 +39,7 @@ func TestMessageReceiver_HandleRequest(t *testing.T) {
 		header       http.Header
 		body         string
 		bodyReader   io.Reader
+		opts         []ReceiverOption
 		expected     int
 		receiverFunc func(ChannelReference, *Message) error
 	}{
@@ -57,6 +59,71 @@ func TestMessageReceiver_HandleRequest(t *testing.T) {
 			bodyReader: &errorReader{},
 			expected:   http.StatusInternalServerError,
 		},
+		"body too large": {
+			body:     "0123456789",
+			opts:     []ReceiverOption{WithMaxBodySize(9)},
+			expected: http.StatusRequestEntityTooLarge,
+		},
+		"body of unknown length too large": {
+			bodyReader: ioutil.NopCloser(strings.NewReader("0123456789")),
+			opts:       []ReceiverOption{WithMaxBodySize(9)},
+			expected:   http.StatusRequestEntityTooLarge,
+		},
+		"body of the maximum size": {
+			body: "0123456789",
+			opts: []ReceiverOption{WithMaxBodySize(10)},
+			receiverFunc: func(_ ChannelReference, m *Message) error {
+				if string(m.Payload) != "0123456789" {
+					return fmt.Errorf("unexpected payload %q", m.Payload)
+				}
+				return nil
+			},
+			expected: http.StatusAccepted,
+		},
+		"streamed body": {
+			body: "0123456789",
+			opts: []ReceiverOption{WithBodyStreaming()},
+			receiverFunc: func(_ ChannelReference, m *Message) error {
+				if !m.IsStreamed() || m.Payload != nil {
+					return errors.New("the body is not streamed")
+				}
+				if err := m.ReadBody(); err != nil {
+					return err
+				}
+				if string(m.Payload) != "0123456789" {
+					return fmt.Errorf("unexpected payload %q", m.Payload)
+				}
+				return nil
+			},
+			expected: http.StatusAccepted,
+		},
+		"structured body not streamed": {
+			header: map[string][]string{
+				"content-type": {"application/cloudevents+json"},
+			},
+			body: `{"specversion":"1.0"}`,
+			opts: []ReceiverOption{WithBodyStreaming()},
+			receiverFunc: func(_ ChannelReference, m *Message) error {
+				if m.IsStreamed() {
+					return errors.New("the structured body is streamed")
+				}
+				return nil
+			},
+			expected: http.StatusAccepted,
+		},
+		"streamed body too large": {
+			body:     "0123456789",
+			opts:     []ReceiverOption{WithBodyStreaming(), WithMaxBodySize(9)},
+			expected: http.StatusRequestEntityTooLarge,
+		},
+		"streamed body of unknown length too large": {
+			bodyReader: ioutil.NopCloser(strings.NewReader("0123456789")),
+			opts:       []ReceiverOption{WithBodyStreaming(), WithMaxBodySize(9)},
+			receiverFunc: func(_ ChannelReference, m *Message) error {
+				return m.ReadBody()
+			},
+			expected: http.StatusRequestEntityTooLarge,
+		},
 		"unknown channel error": {
 			receiverFunc: func(_ ChannelReference, _ *Message) error {
 				return ErrUnknownChannel
@@ -69,6 +136,97 @@ func TestMessageReceiver_HandleRequest(t *testing.T) {
 			},
 			expected: http.StatusInternalServerError,
 		},
+		"unsupported spec version": {
+			header: map[string][]string{
+				"ce-specversion": {"9.9"},
+			},
+			expected: http.StatusBadRequest,
+		},
+		"strict invalid CloudEvent": {
+			header: map[string][]string{
+				"ce-specversion": {"1.0"},
+				"ce-type":        {"com.example.someevent"},
+			},
+			opts:     []ReceiverOption{WithStrictCloudEvents()},
+			expected: http.StatusBadRequest,
+		},
+		"strict valid CloudEvent": {
+			header: map[string][]string{
+				"ce-specversion": {"1.0"},
+				"ce-type":        {"com.example.someevent"},
+				"ce-source":      {"/mycontext"},
+				"ce-id":          {"A234-1234-1234"},
+			},
+			opts: []ReceiverOption{WithStrictCloudEvents()},
+			receiverFunc: func(_ ChannelReference, _ *Message) error {
+				return nil
+			},
+			expected: http.StatusAccepted,
+		},
+		"batch not a JSON array": {
+			header: map[string][]string{
+				"content-type": {"application/cloudevents-batch+json"},
+			},
+			body:     `{"specversion":"1.0"}`,
+			expected: http.StatusBadRequest,
+		},
+		"batch of an event that is not an object": {
+			header: map[string][]string{
+				"content-type": {"application/cloudevents-batch+json"},
+			},
+			body:     `[{"specversion":"1.0","type":"com.example.someevent","source":"/mycontext","id":"1"},"event"]`,
+			expected: http.StatusBadRequest,
+		},
+		"batch of an event of an unsupported spec version": {
+			header: map[string][]string{
+				"content-type": {"application/cloudevents-batch+json"},
+			},
+			body:     `[{"specversion":"1.0","type":"com.example.someevent","source":"/mycontext","id":"1"},{"specversion":"9.9"}]`,
+			expected: http.StatusBadRequest,
+		},
+		"strict batch of an invalid CloudEvent": {
+			header: map[string][]string{
+				"content-type": {"application/cloudevents-batch+json"},
+			},
+			body: `[{"specversion":"1.0","type":"com.example.someevent","source":"/mycontext","id":"1"},{"specversion":"1.0","type":"com.example.someevent"}]`,
+			opts: []ReceiverOption{WithStrictCloudEvents()},
+			receiverFunc: func(_ ChannelReference, _ *Message) error {
+				return errors.New("no event of an invalid batch is passed on")
+			},
+			expected: http.StatusBadRequest,
+		},
+		"empty batch": {
+			header: map[string][]string{
+				"content-type": {"application/cloudevents-batch+json"},
+			},
+			body: `[]`,
+			receiverFunc: func(_ ChannelReference, _ *Message) error {
+				return errors.New("no event of an empty batch is passed on")
+			},
+			expected: http.StatusAccepted,
+		},
+		"older spec version converted": {
+			header: map[string][]string{
+				"ce-cloudeventsversion": {"0.1"},
+				"ce-eventtype":          {"com.example.someevent"},
+			},
+			receiverFunc: func(_ ChannelReference, m *Message) error {
+				if !traceParentRegexp.MatchString(m.Headers["ce-traceparent"]) {
+					return fmt.Errorf("test receiver func -- bad traceparent: %q", m.Headers["ce-traceparent"])
+				}
+				delete(m.Headers, "ce-traceparent")
+				expectedHeaders := map[string]string{
+					"ce-specversion":    "1.0",
+					"ce-type":           "com.example.someevent",
+					"ce-knativehistory": "test-channel.test-namespace.svc.cluster.local",
+				}
+				if diff := cmp.Diff(expectedHeaders, m.Headers); diff != "" {
+					return fmt.Errorf("test receiver func -- bad headers (-want, +got): %s", diff)
+				}
+				return nil
+			},
+			expected: http.StatusAccepted,
+		},
 		"headers and body pass through": {
 			// The header, body, and host values set here are verified in the receiverFunc. Altering
 			// them here will require the same alteration in the receiverFunc.
@@ -91,9 +249,14 @@ func TestMessageReceiver_HandleRequest(t *testing.T) {
 				if string(m.Payload) != "message-body" {
 					return fmt.Errorf("test receiver func -- bad payload: %v", m.Payload)
 				}
+				if !traceParentRegexp.MatchString(m.Headers["ce-traceparent"]) {
+					return fmt.Errorf("test receiver func -- bad traceparent: %q", m.Headers["ce-traceparent"])
+				}
+				delete(m.Headers, "ce-traceparent")
 				expectedHeaders := map[string]string{
-					"x-requEst-id": "1234",
-					"contenT-type": "text/json",
+					"ce-knativehistory": "test-name.test-namespace.svc.cluster.local",
+					"x-requEst-id":      "1234",
+					"contenT-type":      "text/json",
 					// Note that only the first value was passed through, the remaining values were
 					// discarded.
 					"knatIve-will-pass-through": "true",
@@ -123,7 +286,7 @@ func TestMessageReceiver_HandleRequest(t *testing.T) {
 			}
 
 			f := tc.receiverFunc
-			r := NewMessageReceiver(f, zap.NewNop().Sugar())
+			r := NewMessageReceiver(f, zap.NewNop().Sugar(), tc.opts...)
 			h := r.handler()
 
 			body := tc.bodyReader
@@ -144,6 +307,166 @@ func TestMessageReceiver_HandleRequest(t *testing.T) {
 	}
 }
 
+type recordingTap struct {
+	channels []ChannelReference
+	payloads []string
+}
+
+func (t *recordingTap) Publish(channel ChannelReference, message *Message) {
+	t.channels = append(t.channels, channel)
+	t.payloads = append(t.payloads, string(message.Payload))
+}
+
+func TestMessageReceiver_Tap(t *testing.T) {
+	tap := &recordingTap{}
+	r := NewMessageReceiver(func(ChannelReference, *Message) error {
+		return nil
+	}, zap.NewNop().Sugar(), WithTap(tap))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("tapped"))
+	req.Host = "test-channel.test-namespace.svc.cluster.local"
+	resp := httptest.NewRecorder()
+	r.handler().ServeHTTP(resp, req)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v. Actual %v", http.StatusAccepted, resp.Code)
+	}
+
+	wantChannels := []ChannelReference{{Namespace: "test-namespace", Name: "test-channel"}}
+	if diff := cmp.Diff(wantChannels, tap.channels); diff != "" {
+		t.Errorf("Unexpected tapped channels (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"tapped"}, tap.payloads); diff != "" {
+		t.Errorf("Unexpected tapped payloads (-want, +got): %s", diff)
+	}
+}
+
+func TestMessageReceiver_SignedMessage(t *testing.T) {
+	ingress := &prefixSigner{prefix: "ingress:"}
+	origin := &prefixSigner{prefix: "origin:"}
+	unsigned := &Message{Headers: map[string]string{"Ce-Id": "1234"}, Payload: []byte("data")}
+	originSignature, _ := origin.Sign(unsigned.SignedContent())
+	ingressSignature, _ := ingress.Sign(unsigned.SignedContent())
+
+	testCases := map[string]struct {
+		signature     string
+		opts          []ReceiverOption
+		expected      int
+		wantSignature string
+	}{
+		"unsigned": {
+			opts:          []ReceiverOption{WithSigning(ingress), WithSignedMessageVerification(origin)},
+			expected:      http.StatusAccepted,
+			wantSignature: ingressSignature,
+		},
+		"valid signature kept": {
+			signature:     originSignature,
+			opts:          []ReceiverOption{WithSigning(ingress), WithSignedMessageVerification(origin)},
+			expected:      http.StatusAccepted,
+			wantSignature: originSignature,
+		},
+		"invalid signature": {
+			signature: "origin:forged",
+			opts:      []ReceiverOption{WithSigning(ingress), WithSignedMessageVerification(origin)},
+			expected:  http.StatusBadRequest,
+		},
+		"invalid signature without signing": {
+			signature: "origin:forged",
+			opts:      []ReceiverOption{WithSignedMessageVerification(origin)},
+			expected:  http.StatusBadRequest,
+		},
+		"unverified signature replaced": {
+			signature:     "origin:forged",
+			opts:          []ReceiverOption{WithSigning(ingress)},
+			expected:      http.StatusAccepted,
+			wantSignature: ingressSignature,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var signature string
+			r := NewMessageReceiver(func(_ ChannelReference, m *Message) error {
+				signature = m.extension(SignatureExtension)
+				return nil
+			}, zap.NewNop().Sugar(), tc.opts...)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("data"))
+			req.Host = "test-channel.test-namespace.svc.cluster.local"
+			req.Header.Set("Ce-Id", "1234")
+			if tc.signature != "" {
+				req.Header.Set("Ce-"+SignatureExtension, tc.signature)
+			}
+			resp := httptest.NewRecorder()
+			r.handler().ServeHTTP(resp, req)
+			if resp.Code != tc.expected {
+				t.Fatalf("Unexpected status code. Expected %v. Actual %v", tc.expected, resp.Code)
+			}
+			if signature != tc.wantSignature {
+				t.Errorf("Unexpected signature. Expected %q. Actual %q", tc.wantSignature, signature)
+			}
+		})
+	}
+}
+
+func TestMessageReceiver_Batch(t *testing.T) {
+	var received []*Message
+	r := NewMessageReceiver(func(_ ChannelReference, m *Message) error {
+		received = append(received, m)
+		if len(received) == 3 {
+			return errors.New("test induced receiver function error")
+		}
+		return nil
+	}, zap.NewNop().Sugar())
+
+	body := `[
+		{"specversion":"1.0","type":"com.example.someevent","source":"/mycontext","id":"1","data":{"n":1}},
+		{"specversion":"1.0","type":"com.example.someevent","source":"/mycontext","id":"2","data":"two"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Host = "test-channel.test-namespace.svc.cluster.local"
+	req.Header = map[string][]string{
+		"Content-Type":  {"application/cloudevents-batch+json; charset=utf-8"},
+		"X-Request-Id":  {"1234"},
+		"Ce-Ignored-Id": {"5678"},
+	}
+	resp := httptest.NewRecorder()
+	r.handler().ServeHTTP(resp, req)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("Unexpected status code. Expected %v. Actual %v", http.StatusAccepted, resp.Code)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("Received %d events, want 2", len(received))
+	}
+	for i, m := range received {
+		wantHeaders := map[string]string{
+			"Content-Type": "application/cloudevents+json",
+			"X-Request-Id": "1234",
+		}
+		if diff := cmp.Diff(wantHeaders, m.Headers); diff != "" {
+			t.Errorf("Unexpected headers of event %d (-want, +got): %s", i, diff)
+		}
+		if diff := cmp.Diff([]string{"test-channel.test-namespace.svc.cluster.local"}, m.History()); diff != "" {
+			t.Errorf("Unexpected history of event %d (-want, +got): %s", i, diff)
+		}
+		if id := m.Attributes()["id"]; id != fmt.Sprint(i+1) {
+			t.Errorf("Unexpected id of event %d: %q", i, id)
+		}
+	}
+	if got := string(received[1].Data()); got != "two" {
+		t.Errorf("Unexpected data of event 1: %q", got)
+	}
+
+	// The batch fails when one of its events cannot be passed on.
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Host = "test-channel.test-namespace.svc.cluster.local"
+	req.Header.Set("Content-Type", "application/cloudevents-batch+json")
+	resp = httptest.NewRecorder()
+	r.handler().ServeHTTP(resp, req)
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status code. Expected %v. Actual %v", http.StatusInternalServerError, resp.Code)
+	}
+}
+
 type errorReader struct{}
 
 var _ io.Reader = &errorReader{}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// SignatureExtension is the CloudEvents extension holding the signature of an event, as
+	// 'keyId=<id>,algorithm=<algorithm>,signature=<base64>'. It is set by the ingress of the first
+	// channel the event is sent to, and kept as the event traverses other channels.
+	SignatureExtension = "knativesignature"
+)
+
+// ErrUnsigned is returned when verifying the signature of a message that is not signed.
+var ErrUnsigned = errors.New("the message is not signed")
+
+// Signer signs the content of messages. Implementations are in pkg/provisioners/signing.
+type Signer interface {
+	// Sign returns the value of the SignatureExtension for content.
+	Sign(content []byte) (string, error)
+}
+
+// Verifier verifies the signatures created by a Signer.
+type Verifier interface {
+	// Verify returns an error if signature, the value of the SignatureExtension, is not a valid
+	// signature of content.
+	Verify(content []byte, signature string) error
+}
+
+// SignedContent returns the content of the message that is signed: the id, source, type, subject
+// and time attributes, and the SHA-256 digest of the data, each prefixed with its length as a
+// big-endian uint32, so that no two different messages have the same content. The other
+// attributes are not signed, since channels change extensions such as the history and the TTL.
+// JSON data of structured messages is compacted first, as it may be re-encoded on the way.
+func (m *Message) SignedContent() []byte {
+	attrs := m.Attributes()
+	data := m.Data()
+	if m.isStructured() && len(data) > 0 && (data[0] == '{' || data[0] == '[') {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, data); err == nil {
+			data = compacted.Bytes()
+		}
+	}
+	digest := sha256.Sum256(data)
+	var content bytes.Buffer
+	for _, field := range [][]byte{
+		[]byte(attrs["id"]),
+		[]byte(attrs["source"]),
+		[]byte(attrs["type"]),
+		[]byte(attrs["subject"]),
+		[]byte(attrs["time"]),
+		digest[:],
+	} {
+		binary.Write(&content, binary.BigEndian, uint32(len(field)))
+		content.Write(field)
+	}
+	return content.Bytes()
+}
+
+// Sign sets the SignatureExtension of the message to its signature by s. Messages that are
+// already signed keep their signature, so that it authenticates the origin of the event across
+// channels. A MessageReceiver only keeps the signature it verified, see
+// WithSignedMessageVerification.
+func (m *Message) Sign(s Signer) error {
+	if m.extension(SignatureExtension) != "" {
+		return nil
+	}
+	signature, err := s.Sign(m.SignedContent())
+	if err != nil {
+		return err
+	}
+	m.setExtension(SignatureExtension, signature)
+	return nil
+}
+
+// VerifySignature verifies the SignatureExtension of the message with v. It returns ErrUnsigned if
+// the message is not signed.
+func (m *Message) VerifySignature(v Verifier) error {
+	signature := m.extension(SignatureExtension)
+	if signature == "" {
+		return ErrUnsigned
+	}
+	return v.Verify(m.SignedContent(), signature)
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// prefixSigner is a Signer whose signatures are the prefix followed by the hex encoded content.
+type prefixSigner struct {
+	prefix string
+}
+
+func (s *prefixSigner) Sign(content []byte) (string, error) {
+	return s.prefix + hex.EncodeToString(content), nil
+}
+
+func (s *prefixSigner) Verify(content []byte, signature string) error {
+	if signature != s.prefix+hex.EncodeToString(content) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func TestMessageSignature(t *testing.T) {
+	signer := &prefixSigner{prefix: "signed:"}
+	testCases := map[string]struct {
+		message *Message
+		// modify is applied to the signed message before it is verified.
+		modify  func(*Message)
+		wantErr bool
+	}{
+		"binary": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Id":          "1234",
+					"Ce-Source":      "/source",
+					"Ce-Type":        "dev.knative.test",
+				},
+				Payload: []byte(`{"total": 10}`),
+			},
+		},
+		"binary with changed data": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Id": "1234"},
+				Payload: []byte(`{"total": 10}`),
+			},
+			modify: func(m *Message) {
+				m.Payload = []byte(`{"total": 11}`)
+			},
+			wantErr: true,
+		},
+		"binary with changed attribute": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Id": "1234", "Ce-Type": "dev.knative.test"},
+			},
+			modify: func(m *Message) {
+				m.Headers["Ce-Type"] = "dev.knative.other"
+			},
+			wantErr: true,
+		},
+		"binary with changed extension": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Id": "1234"},
+			},
+			modify: func(m *Message) {
+				m.setExtension(TTLExtension, "3")
+			},
+		},
+		"structured re-encoded": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","source":"/source","data":{"total": 10}}`),
+			},
+			modify: func(m *Message) {
+				m.setExtension("other", "value")
+			},
+		},
+		"structured with changed data": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "application/cloudevents+json"},
+				Payload: []byte(`{"specversion":"1.0","id":"1234","data":{"total":10}}`),
+			},
+			modify: func(m *Message) {
+				m.Payload = []byte(`{"specversion":"1.0","id":"1234","data":{"total":11}}`)
+			},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if err := tc.message.Sign(signer); err != nil {
+				t.Fatalf("Unexpected error signing: %v", err)
+			}
+			if tc.message.extension(SignatureExtension) == "" {
+				t.Fatalf("The message is not signed")
+			}
+			if tc.modify != nil {
+				tc.modify(tc.message)
+			}
+			err := tc.message.VerifySignature(signer)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected verification error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMessageSignatureIsKept(t *testing.T) {
+	m := &Message{
+		Headers: map[string]string{"Ce-Id": "1234"},
+		Payload: []byte("data"),
+	}
+	first := &prefixSigner{prefix: "first:"}
+	if err := m.Sign(first); err != nil {
+		t.Fatalf("Unexpected error signing: %v", err)
+	}
+	if err := m.Sign(&prefixSigner{prefix: "second:"}); err != nil {
+		t.Fatalf("Unexpected error signing: %v", err)
+	}
+	if err := m.VerifySignature(first); err != nil {
+		t.Errorf("Expected the first signature to be kept: %v", err)
+	}
+}
+
+func TestMessageSignatureUnsigned(t *testing.T) {
+	m := &Message{
+		Headers: map[string]string{"Ce-Id": "1234"},
+	}
+	if err := m.VerifySignature(&prefixSigner{}); err != ErrUnsigned {
+		t.Errorf("Unexpected error. Expected %v. Actual %v", ErrUnsigned, err)
+	}
+}
+
+func TestMessageSignedContentIsUnambiguous(t *testing.T) {
+	// The attributes of the messages are the same once joined by newlines.
+	m1 := &Message{
+		Headers: map[string]string{"Ce-Id": "1234\n/source", "Ce-Source": "dev.knative.test"},
+	}
+	m2 := &Message{
+		Headers: map[string]string{"Ce-Id": "1234", "Ce-Source": "/source\ndev.knative.test"},
+	}
+	if bytes.Equal(m1.SignedContent(), m2.SignedContent()) {
+		t.Errorf("Different messages have the same signed content %q", m1.SignedContent())
+	}
+}
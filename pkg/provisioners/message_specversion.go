@@ -0,0 +1,207 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CloudEventsSpecVersion is the version of the CloudEvents specification of the events sent by the
+// data plane. Events of older versions are converted to it when they are received.
+const CloudEventsSpecVersion = "1.0"
+
+// convertibleSpecVersions are the versions of the CloudEvents specification whose events are
+// converted to CloudEventsSpecVersion.
+var convertibleSpecVersions = map[string]bool{
+	"0.1": true,
+	"0.2": true,
+	"0.3": true,
+}
+
+// UnsupportedSpecVersionError is returned when a message is a CloudEvent of a version of the
+// specification that the data plane does not understand.
+type UnsupportedSpecVersionError struct {
+	SpecVersion string
+}
+
+func (e *UnsupportedSpecVersionError) Error() string {
+	return fmt.Sprintf("unsupported CloudEvents spec version %q", e.SpecVersion)
+}
+
+// ToCloudEventsSpecVersion converts the message, if it is a CloudEvent of an older version of the
+// specification, to a CloudEvent of CloudEventsSpecVersion, in the same content mode. Messages
+// that are not CloudEvents, or that declare no spec version, are left unchanged. An
+// UnsupportedSpecVersionError is returned if the spec version of the message is unknown.
+func (m *Message) ToCloudEventsSpecVersion() error {
+	if m.isStructured() {
+		return m.convertStructured()
+	}
+	return m.convertBinary()
+}
+
+func (m *Message) isStructured() bool {
+	for k, v := range m.Headers {
+		if strings.ToLower(k) == "content-type" {
+			return strings.HasPrefix(strings.ToLower(v), structuredContentType)
+		}
+	}
+	return false
+}
+
+// convertBinary converts the ce- headers of a message in the binary content mode.
+func (m *Message) convertBinary() error {
+	version := ""
+	for k, v := range m.Headers {
+		switch strings.ToLower(k) {
+		case cloudEventsHeaderPrefix + "specversion", cloudEventsHeaderPrefix + "cloudeventsversion":
+			version = v
+		}
+	}
+	if converted, err := needsConversion(version); !converted {
+		return err
+	}
+
+	headers := make(map[string]string, len(m.Headers))
+	contentType := ""
+	for k, v := range m.Headers {
+		name := strings.ToLower(k)
+		if !strings.HasPrefix(name, cloudEventsHeaderPrefix) {
+			headers[k] = v
+			continue
+		}
+		name = strings.TrimPrefix(name, cloudEventsHeaderPrefix)
+		if strings.HasPrefix(name, cloudEventsExtensionStart) {
+			// CloudEvents 0.1 prefixes extension headers with 'CE-X-' and encodes their values
+			// as JSON.
+			name = strings.TrimPrefix(name, cloudEventsExtensionStart)
+			var s string
+			if err := json.Unmarshal([]byte(v), &s); err == nil {
+				v = s
+			}
+		}
+		if current, ok := legacyAttributeNames[name]; ok {
+			name = current
+		}
+		switch name {
+		case "specversion":
+			continue
+		case "datacontenttype":
+			// The data content type is the Content-Type header in the binary content mode.
+			contentType = v
+			continue
+		case "datacontentencoding":
+			// CloudEvents 0.3 does not use it in the binary content mode.
+			continue
+		}
+		headers[cloudEventsHeaderPrefix+name] = v
+	}
+	headers[cloudEventsHeaderPrefix+"specversion"] = CloudEventsSpecVersion
+	if contentType != "" && !hasHeader(headers, "content-type") {
+		headers["content-type"] = contentType
+	}
+	m.Headers = headers
+	return nil
+}
+
+// convertStructured converts the JSON attributes of a message in the structured content mode.
+func (m *Message) convertStructured() error {
+	event := map[string]json.RawMessage{}
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		// Not a CloudEvent, leave it for the consumer to reject.
+		return nil
+	}
+	version := ""
+	for k, v := range event {
+		switch strings.ToLower(k) {
+		case "specversion", "cloudeventsversion":
+			if err := json.Unmarshal(v, &version); err != nil {
+				return &UnsupportedSpecVersionError{SpecVersion: string(v)}
+			}
+		}
+	}
+	if converted, err := needsConversion(version); !converted {
+		return err
+	}
+
+	converted := make(map[string]json.RawMessage, len(event))
+	base64 := false
+	for k, v := range event {
+		name := strings.ToLower(k)
+		if current, ok := legacyAttributeNames[name]; ok {
+			name = current
+		}
+		switch name {
+		case "data":
+			// The data is added once every attribute has been seen, as its name depends on
+			// the encoding.
+			continue
+		case "extensions":
+			// CloudEvents 0.1 nests extensions in their own object.
+			ext := map[string]json.RawMessage{}
+			if err := json.Unmarshal(v, &ext); err == nil {
+				for ek, ev := range ext {
+					converted[strings.ToLower(ek)] = ev
+				}
+				continue
+			}
+		case "datacontentencoding":
+			var encoding string
+			json.Unmarshal(v, &encoding)
+			base64 = strings.EqualFold(encoding, "base64")
+			continue
+		}
+		converted[name] = v
+	}
+	if data, ok := event["data"]; ok {
+		if base64 {
+			converted["data_base64"] = data
+		} else {
+			converted["data"] = data
+		}
+	}
+	converted["specversion"], _ = json.Marshal(CloudEventsSpecVersion)
+
+	payload, err := json.Marshal(converted)
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	return nil
+}
+
+// needsConversion returns true if a CloudEvent of the spec version must be converted, and an
+// error if it cannot be.
+func needsConversion(version string) (bool, error) {
+	if version == "" || version == CloudEventsSpecVersion {
+		return false, nil
+	}
+	if !convertibleSpecVersions[version] {
+		return false, &UnsupportedSpecVersionError{SpecVersion: version}
+	}
+	return true, nil
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.ToLower(k) == name {
+			return true
+		}
+	}
+	return false
+}
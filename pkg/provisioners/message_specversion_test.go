@@ -0,0 +1,191 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMessageToCloudEventsSpecVersion(t *testing.T) {
+	testCases := map[string]struct {
+		message     *Message
+		wantHeaders map[string]string
+		wantPayload string
+		wantErr     bool
+	}{
+		"not a CloudEvent": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "text/plain"},
+				Payload: []byte("hello"),
+			},
+			wantHeaders: map[string]string{"Content-Type": "text/plain"},
+			wantPayload: "hello",
+		},
+		"binary v1.0": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Type":        "com.example.someevent",
+				},
+			},
+			wantHeaders: map[string]string{
+				"Ce-Specversion": "1.0",
+				"Ce-Type":        "com.example.someevent",
+			},
+		},
+		"binary v0.1": {
+			message: &Message{
+				Headers: map[string]string{
+					"CE-CloudEventsVersion": "0.1",
+					"CE-EventType":          "com.example.someevent",
+					"CE-Source":             "/mycontext",
+					"CE-EventID":            "A234-1234-1234",
+					"CE-EventTime":          "2018-04-05T17:31:00Z",
+					"CE-X-MyExtension":      `"value"`,
+					"CE-X-Count":            "3",
+					"Content-Type":          "application/json",
+					"X-B3-Traceid":          "1234",
+				},
+				Payload: []byte(`{"hello":"world"}`),
+			},
+			wantHeaders: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-type":        "com.example.someevent",
+				"ce-source":      "/mycontext",
+				"ce-id":          "A234-1234-1234",
+				"ce-time":        "2018-04-05T17:31:00Z",
+				"ce-myextension": "value",
+				"ce-count":       "3",
+				"Content-Type":   "application/json",
+				"X-B3-Traceid":   "1234",
+			},
+			wantPayload: `{"hello":"world"}`,
+		},
+		"binary v0.2": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "0.2",
+					"Ce-Type":        "com.example.someevent",
+					"Ce-Schemaurl":   "http://example.com/schema",
+					"Ce-Myextension": "value",
+				},
+			},
+			wantHeaders: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-type":        "com.example.someevent",
+				"ce-dataschema":  "http://example.com/schema",
+				"ce-myextension": "value",
+			},
+		},
+		"binary unsupported": {
+			message: &Message{
+				Headers: map[string]string{"Ce-Specversion": "2.0"},
+			},
+			wantHeaders: map[string]string{"Ce-Specversion": "2.0"},
+			wantErr:     true,
+		},
+		"structured v0.1": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json; charset=utf-8",
+				},
+				Payload: []byte(`{
+					"cloudEventsVersion": "0.1",
+					"eventType": "com.example.someevent",
+					"source": "/mycontext",
+					"eventID": "A234-1234-1234",
+					"extensions": {"comExampleExtension": "value"},
+					"contentType": "text/xml",
+					"data": "<much wow=\"xml\"/>"
+				}`),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": "application/cloudevents+json; charset=utf-8",
+			},
+			wantPayload: `{
+				"specversion": "1.0",
+				"type": "com.example.someevent",
+				"source": "/mycontext",
+				"id": "A234-1234-1234",
+				"comexampleextension": "value",
+				"datacontenttype": "text/xml",
+				"data": "<much wow=\"xml\"/>"
+			}`,
+		},
+		"structured v0.3 base64": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`{
+					"specversion": "0.3",
+					"type": "com.example.someevent",
+					"datacontentencoding": "base64",
+					"data": "aGVsbG8="
+				}`),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": "application/cloudevents+json",
+			},
+			wantPayload: `{
+				"specversion": "1.0",
+				"type": "com.example.someevent",
+				"data_base64": "aGVsbG8="
+			}`,
+		},
+		"structured unsupported": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`{"specversion": "2.0"}`),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": "application/cloudevents+json",
+			},
+			wantPayload: `{"specversion": "2.0"}`,
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.message.ToCloudEventsSpecVersion()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.wantHeaders, tc.message.Headers); diff != "" {
+				t.Errorf("Unexpected headers (-want +got): %s", diff)
+			}
+			if tc.wantPayload == "" {
+				return
+			}
+			var want, got interface{}
+			if err := json.Unmarshal([]byte(tc.wantPayload), &want); err != nil {
+				want = tc.wantPayload
+			}
+			if err := json.Unmarshal(tc.message.Payload, &got); err != nil {
+				got = string(tc.message.Payload)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Unexpected payload (-want +got): %s", diff)
+			}
+		})
+	}
+}
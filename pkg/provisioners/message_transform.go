@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knative/eventing/pkg/transform"
+)
+
+// transform returns the message t transforms m into. The transformed message is in the structured
+// content mode and keeps the headers of m that are not part of the event. m is returned unchanged
+// if t is nil.
+func (m *Message) transform(t *transform.Template) (*Message, error) {
+	if t == nil {
+		return m, nil
+	}
+	payload, err := t.Execute(m.Attributes(), m.Data())
+	if err != nil {
+		return nil, fmt.Errorf("unable to transform the event: %v", err)
+	}
+	headers := map[string]string{
+		"content-type": "application/cloudevents+json",
+	}
+	for k, v := range m.Headers {
+		if name := strings.ToLower(k); name != "content-type" && !strings.HasPrefix(name, "ce-") {
+			headers[k] = v
+		}
+	}
+	transformed := &Message{Headers: headers, Payload: payload}
+	transformed.PropagateExtensions(m)
+	return transformed, nil
+}
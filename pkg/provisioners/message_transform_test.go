@@ -0,0 +1,68 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/transform"
+)
+
+func TestTransformMessage(t *testing.T) {
+	msg := &Message{
+		Headers: map[string]string{
+			"Content-Type":      "application/json",
+			"Ce-Specversion":    "1.0",
+			"Ce-Type":           "com.example.order",
+			"Ce-Knativehistory": "c.ns.svc.cluster.local",
+			"X-B3-Traceid":      "1234",
+		},
+		Payload: []byte(`{"total": 10, "card": "4111111111111111"}`),
+	}
+	tmpl, err := transform.Parse(`{"specversion": "1.0", "type": "com.example.order.v2", "data": {"total": {{json .data.total}}}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the template: %v", err)
+	}
+
+	got, err := msg.transform(tmpl)
+	if err != nil {
+		t.Fatalf("Unexpected error transforming the message: %v", err)
+	}
+	wantHeaders := map[string]string{
+		"content-type": "application/cloudevents+json",
+		"X-B3-Traceid": "1234",
+	}
+	if diff := cmp.Diff(wantHeaders, got.Headers); diff != "" {
+		t.Errorf("Unexpected headers (-want +got): %s", diff)
+	}
+	wantAttrs := map[string]string{
+		"specversion":    "1.0",
+		"type":           "com.example.order.v2",
+		"knativehistory": "c.ns.svc.cluster.local",
+	}
+	if diff := cmp.Diff(wantAttrs, got.Attributes()); diff != "" {
+		t.Errorf("Unexpected attributes (-want +got): %s", diff)
+	}
+	if data := string(got.Data()); data != `{"total":10}` {
+		t.Errorf("Unexpected data %q", data)
+	}
+
+	if got, _ := msg.transform(nil); got != msg {
+		t.Error("Expected the message to be unchanged without a transform")
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requiredAttributes are the context attributes every CloudEvent has.
+var requiredAttributes = []string{"id", "source", "specversion", "type"}
+
+// InvalidCloudEventError is returned when a message is not a valid CloudEvent of
+// CloudEventsSpecVersion.
+type InvalidCloudEventError struct {
+	// Problems describes each way the message is invalid.
+	Problems []string
+}
+
+func (e *InvalidCloudEventError) Error() string {
+	return "invalid CloudEvent: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateCloudEvent returns an InvalidCloudEventError if the message is not a valid CloudEvent of
+// CloudEventsSpecVersion, in either content mode. Messages of older versions must be converted
+// with ToCloudEventsSpecVersion first.
+func (m *Message) ValidateCloudEvent() error {
+	if m.isStructured() {
+		var event map[string]interface{}
+		if err := json.Unmarshal(m.Payload, &event); err != nil {
+			return &InvalidCloudEventError{Problems: []string{"the structured event is not a JSON object"}}
+		}
+	}
+
+	var problems []string
+	attrs := m.Attributes()
+	for _, name := range requiredAttributes {
+		if attrs[name] == "" {
+			problems = append(problems, fmt.Sprintf("missing required attribute %s", name))
+		}
+	}
+	if v := attrs["specversion"]; v != "" && v != CloudEventsSpecVersion {
+		problems = append(problems, fmt.Sprintf("invalid specversion %q, expected %q", v, CloudEventsSpecVersion))
+	}
+	if v := attrs["source"]; v != "" {
+		if _, err := url.Parse(v); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid source %q, expected a URI reference", v))
+		}
+	}
+	if v, ok := attrs["dataschema"]; ok {
+		if u, err := url.Parse(v); err != nil || !u.IsAbs() {
+			problems = append(problems, fmt.Sprintf("invalid dataschema %q, expected an absolute URI", v))
+		}
+	}
+	if v, ok := attrs["time"]; ok {
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid time %q, expected an RFC 3339 timestamp", v))
+		}
+	}
+	if len(problems) > 0 {
+		return &InvalidCloudEventError{Problems: problems}
+	}
+	return nil
+}
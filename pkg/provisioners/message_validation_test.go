@@ -0,0 +1,107 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMessageValidateCloudEvent(t *testing.T) {
+	testCases := map[string]struct {
+		message *Message
+		want    []string
+	}{
+		"valid binary": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "1.0",
+					"Ce-Type":        "com.example.someevent",
+					"Ce-Source":      "/mycontext",
+					"Ce-Id":          "A234-1234-1234",
+					"Ce-Time":        "2018-04-05T17:31:00Z",
+				},
+			},
+		},
+		"valid structured": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`{
+					"specversion": "1.0",
+					"type": "com.example.someevent",
+					"source": "/mycontext",
+					"id": "A234-1234-1234",
+					"dataschema": "http://example.com/schema"
+				}`),
+			},
+		},
+		"not a CloudEvent": {
+			message: &Message{
+				Headers: map[string]string{"Content-Type": "text/plain"},
+				Payload: []byte("hello"),
+			},
+			want: []string{
+				"missing required attribute id",
+				"missing required attribute source",
+				"missing required attribute specversion",
+				"missing required attribute type",
+			},
+		},
+		"invalid attributes": {
+			message: &Message{
+				Headers: map[string]string{
+					"Ce-Specversion": "0.2",
+					"Ce-Type":        "com.example.someevent",
+					"Ce-Source":      "%zz",
+					"Ce-Id":          "A234-1234-1234",
+					"Ce-Time":        "yesterday",
+					"Ce-Dataschema":  "schema",
+				},
+			},
+			want: []string{
+				`invalid specversion "0.2", expected "1.0"`,
+				`invalid source "%zz", expected a URI reference`,
+				`invalid dataschema "schema", expected an absolute URI`,
+				`invalid time "yesterday", expected an RFC 3339 timestamp`,
+			},
+		},
+		"structured invalid JSON": {
+			message: &Message{
+				Headers: map[string]string{
+					"Content-Type": "application/cloudevents+json",
+				},
+				Payload: []byte(`not json`),
+			},
+			want: []string{"the structured event is not a JSON object"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.message.ValidateCloudEvent()
+			var got []string
+			if err != nil {
+				got = err.(*InvalidCloudEventError).Problems
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected problems (-want +got): %s", diff)
+			}
+		})
+	}
+}
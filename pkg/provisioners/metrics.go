@@ -0,0 +1,212 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPortEnv is the environment variable holding the port dispatchers serve their metrics on.
+// The metrics are not served if it is not set.
+const MetricsPortEnv = "METRICS_PORT"
+
+// MetricsGranularityEnv is the environment variable holding the MetricsGranularity of the metrics
+// of the dispatchers. It defaults to SubscriptionGranularity.
+const MetricsGranularityEnv = "METRICS_GRANULARITY"
+
+// MetricsGranularity is the finest level the delivery metrics are labeled at. Coarser levels
+// collapse the finer labels to the empty string, to bound the number of series of large clusters.
+type MetricsGranularity string
+
+const (
+	// SubscriptionGranularity labels the metrics by namespace, channel and subscription.
+	SubscriptionGranularity MetricsGranularity = "subscription"
+	// ChannelGranularity labels the metrics by namespace and channel.
+	ChannelGranularity MetricsGranularity = "channel"
+	// NamespaceGranularity labels the metrics by namespace only.
+	NamespaceGranularity MetricsGranularity = "namespace"
+)
+
+// metricsGranularity is the MetricsGranularity of the metrics of this process. It is set once,
+// before events are dispatched.
+var metricsGranularity = SubscriptionGranularity
+
+// SetMetricsGranularity sets the MetricsGranularity of the metrics of this process. The empty
+// string sets the default, SubscriptionGranularity. It must be called before events are
+// dispatched.
+func SetMetricsGranularity(granularity string) error {
+	switch g := MetricsGranularity(granularity); g {
+	case "":
+		metricsGranularity = SubscriptionGranularity
+	case SubscriptionGranularity, ChannelGranularity, NamespaceGranularity:
+		metricsGranularity = g
+	default:
+		return fmt.Errorf("invalid metrics granularity %q, it must be %s, %s or %s", granularity, SubscriptionGranularity, ChannelGranularity, NamespaceGranularity)
+	}
+	return nil
+}
+
+// metricLabels returns the namespace, channel and subscription labels of the metrics of a delivery,
+// collapsed to the metricsGranularity.
+func metricLabels(defaults DispatchDefaults) (string, string, string) {
+	namespace := defaults.Namespace
+	if namespace == "" {
+		namespace = strings.SplitN(defaults.Channel, "/", 2)[0]
+	}
+	switch metricsGranularity {
+	case NamespaceGranularity:
+		return namespace, "", ""
+	case ChannelGranularity:
+		return namespace, defaults.Channel, ""
+	}
+	return namespace, defaults.Channel, defaults.Subscription
+}
+
+// ChannelMetricLabels returns the namespace and channel labels of the metrics of channel, collapsed
+// to the MetricsGranularity of this process.
+func ChannelMetricLabels(channel ChannelReference) (string, string) {
+	namespace, name, _ := metricLabels(DispatchDefaults{Namespace: channel.Namespace, Channel: channel.String()})
+	return namespace, name
+}
+
+// The reasons messages are rejected by a MessageReceiver.
+const (
+	rejectUnsupportedSpecVersion = "unsupported_specversion"
+	rejectInvalidCloudEvent      = "invalid_cloudevent"
+	rejectBodyTooLarge           = "body_too_large"
+	rejectInvalidBatch           = "invalid_batch"
+	rejectInvalidSignature       = "invalid_signature"
+)
+
+// The content modes of the events received by a MessageReceiver.
+const (
+	receiveModeBinary     = "binary"
+	receiveModeStructured = "structured"
+	receiveModeBatch      = "batch"
+)
+
+// The reasons messages are dropped by a MessageDispatcher.
+const (
+	dropTTLExpired         = "ttl_expired"
+	dropDestinationDropped = "destination_dropped"
+)
+
+var (
+	messagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "ingress",
+		Name:      "events_received_total",
+		Help:      "The number of events accepted by the channel ingress, by namespace, channel and content mode. The events of a batch are counted one by one.",
+	}, []string{"namespace", "channel", "mode"})
+
+	messagesRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "ingress",
+		Name:      "events_rejected_total",
+		Help:      "The number of events rejected by the channel ingress, by reason. The events of a rejected batch are counted one by one.",
+	}, []string{"reason"})
+
+	messagesDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "events_dropped_total",
+		Help:      "The number of events dropped by the dispatcher instead of being delivered, by reason.",
+	}, []string{"reason"})
+
+	messagesDelivered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "events_delivered_total",
+		Help:      "The number of events accepted by subscribers, by namespace, channel and subscription.",
+	}, []string{"namespace", "channel", "subscription"})
+
+	deliveryFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "delivery_failures_total",
+		Help:      "The number of failed deliveries of events to subscribers, by namespace, channel, subscription and HTTP status code. The code is empty when there was no response.",
+	}, []string{"namespace", "channel", "subscription", "code"})
+
+	deliveryRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "delivery_retries_total",
+		Help:      "The number of deliveries of events to subscribers that retried a failed delivery, by namespace, channel and subscription.",
+	}, []string{"namespace", "channel", "subscription"})
+
+	deliveryLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "delivery_latency_seconds",
+		Help:      "The time it took subscribers to respond to events, by namespace, channel and subscription.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"namespace", "channel", "subscription"})
+)
+
+func init() {
+	prometheus.MustRegister(messagesReceived, messagesRejected, messagesDropped, messagesDelivered, deliveryFailures, deliveryRetries, deliveryLatency)
+}
+
+// observeDelivery records the outcome of the delivery of an event to the destination of a
+// subscription, which took d and failed with err if it is not nil.
+func observeDelivery(defaults DispatchDefaults, d time.Duration, err error) {
+	namespace, channel, subscription := metricLabels(defaults)
+	if defaults.Redelivery {
+		deliveryRetries.WithLabelValues(namespace, channel, subscription).Inc()
+	}
+	deliveryLatency.WithLabelValues(namespace, channel, subscription).Observe(d.Seconds())
+	if err == nil {
+		messagesDelivered.WithLabelValues(namespace, channel, subscription).Inc()
+		return
+	}
+	code := ""
+	if re, ok := err.(*responseError); ok {
+		code = strconv.Itoa(re.statusCode)
+	}
+	deliveryFailures.WithLabelValues(namespace, channel, subscription, code).Inc()
+}
+
+// MetricsHandler returns the handler serving the Prometheus metrics of the data plane at /metrics.
+func MetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+// ServeMetrics serves MetricsHandler on addr until stopCh is closed.
+func ServeMetrics(addr string, stopCh <-chan struct{}) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: MetricsHandler(),
+	}
+	go func() {
+		<-stopCh
+		srv.Shutdown(context.Background())
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
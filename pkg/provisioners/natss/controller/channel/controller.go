@@ -24,6 +24,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/provisioners"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -41,7 +43,7 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		logger:   logger,
 	}
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: r,
+		Reconciler: metrics.InstrumentReconciler(controllerAgentName, r),
 	})
 	if err != nil {
 		logger.Error("Unable to create controller.", zap.Error(err))
@@ -75,5 +77,10 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		return nil, err
 	}
 
+	// Watch the AuthorizationPolicies of Channels, when Istio security is installed.
+	if err := provisioners.WatchAuthorizationPolicies(c); err != nil {
+		logger.Warn("Not watching AuthorizationPolicies.", zap.Error(err))
+	}
+
 	return c, nil
 }
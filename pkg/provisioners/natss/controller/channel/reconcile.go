@@ -54,7 +54,7 @@ func (r *reconciler) InjectClient(c client.Client) error {
 func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	r.logger.Info("Reconcile: ", zap.Any("request", request))
 
-	ctx := context.TODO()
+	ctx := provisioners.WithEventRecorder(context.TODO(), r.recorder)
 	c := &eventingv1alpha1.Channel{}
 	err := r.client.Get(ctx, request.NamespacedName, c)
 
@@ -124,7 +124,8 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 
 	if c.DeletionTimestamp != nil {
 		// K8s garbage collection will delete the K8s service and VirtualService for this channel.
-		return nil
+		// The finalizer of the dispatcher lets the AuthorizationPolicy be deleted first.
+		return provisioners.DeleteAuthorizationPolicy(ctx, r.client, c)
 	}
 
 	svc, err := provisioners.CreateK8sService(ctx, r.client, c)
@@ -147,6 +148,11 @@ func (r *reconciler) reconcile(ctx context.Context, c *eventingv1alpha1.Channel)
 		r.logger.Warn("VirtualService not owned by Channel", zap.Any("channel", c), zap.Any("virtualService", virtualService))
 	}
 
+	if _, err := provisioners.CreateAuthorizationPolicy(ctx, r.client, c); err != nil {
+		r.logger.Info("Error creating the AuthorizationPolicy for the Channel", zap.Error(err))
+		return err
+	}
+
 	c.Status.MarkProvisioned()
 	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
 	controllertesting "github.com/knative/eventing/pkg/controller/testing"
 	"github.com/knative/eventing/pkg/provisioners"
 	util "github.com/knative/eventing/pkg/provisioners"
@@ -52,6 +53,7 @@ func init() {
 	// Add types to scheme
 	eventingv1alpha1.AddToScheme(scheme.Scheme)
 	istiov1alpha3.AddToScheme(scheme.Scheme)
+	securityv1beta1.AddToScheme(scheme.Scheme)
 }
 
 var testCases = []controllertesting.TestCase{
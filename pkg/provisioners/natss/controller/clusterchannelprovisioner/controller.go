@@ -25,6 +25,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/controller/metrics"
+	"github.com/knative/eventing/pkg/provisioners"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -52,7 +54,7 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		logger:   logger,
 	}
 	c, err := controller.New(controllerAgentName, mgr, controller.Options{
-		Reconciler: r,
+		Reconciler: metrics.InstrumentReconciler(controllerAgentName, r),
 	})
 	if err != nil {
 		logger.Error("Unable to create controller.", zap.Error(err))
@@ -77,5 +79,11 @@ func ProvideController(mgr manager.Manager, logger *zap.Logger) (controller.Cont
 		return nil, err
 	}
 
+	// Watch the NetworkPolicy of the dispatcher and its config.
+	if err := provisioners.WatchDispatcherNetworkPolicy(c, Name); err != nil {
+		logger.Error("Unable to watch NetworkPolicies.", zap.Error(err))
+		return nil, err
+	}
+
 	return c, nil
 }
@@ -61,7 +61,7 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 	// cluster-scoped we need to unset the namespace or otherwise the provisioner object cannot be looked up.
 	request.NamespacedName.Namespace = ""
 
-	ctx := context.TODO()
+	ctx := provisioners.WithEventRecorder(context.TODO(), r.recorder)
 	ccp := &eventingv1alpha1.ClusterChannelProvisioner{}
 	err := r.client.Get(ctx, request.NamespacedName, ccp)
 
@@ -135,6 +135,24 @@ func (r *reconciler) reconcile(ctx context.Context, ccp *eventingv1alpha1.Cluste
 		r.logger.Warn("ClusterChannelProvisioner's K8s Service is not owned by the ClusterChannelProvisioner", zap.Any("clusterChannelProvisioner", ccp), zap.Any("service", svc))
 	}
 
+	if _, err := provisioners.CreateDispatcherNetworkPolicy(ctx, r.client, ccp); err != nil {
+		r.logger.Error("Error creating the ClusterChannelProvisioner's NetworkPolicy", zap.Error(err))
+		return err
+	}
+
+	ccp.Status.Capabilities = Capabilities()
 	ccp.Status.MarkReady()
 	return nil
 }
+
+// Capabilities returns the capabilities of NATSS Channels, published in the status of the
+// ClusterChannelProvisioner. Events are not ordered, as unacknowledged events are redelivered after
+// the following ones.
+func Capabilities() *eventingv1alpha1.ClusterChannelProvisionerCapabilities {
+	// The default max_payload of NATS servers.
+	size := int64(1024 * 1024)
+	return &eventingv1alpha1.ClusterChannelProvisionerCapabilities{
+		Persistence:  true,
+		MaxEventSize: &size,
+	}
+}
@@ -227,6 +227,11 @@ func makeReadyClusterChannelProvisioner() *eventingv1alpha1.ClusterChannelProvis
 		Status:   corev1.ConditionTrue,
 		Severity: duckv1alpha1.ConditionSeverityError,
 	}}
+	maxEventSize := int64(1048576)
+	ccp.Status.Capabilities = &eventingv1alpha1.ClusterChannelProvisionerCapabilities{
+		Persistence:  true,
+		MaxEventSize: &maxEventSize,
+	}
 	return ccp
 }
 
@@ -248,7 +253,11 @@ func makeK8sService() *corev1.Service {
 					BlockOwnerDeletion: &truePointer,
 				},
 			},
-			Labels: provisioners.DispatcherLabels(Name),
+			Labels: map[string]string{
+				"clusterChannelProvisioner": Name,
+				"role":                      "dispatcher",
+				"provisioner":               Name,
+			},
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: provisioners.DispatcherLabels(Name),
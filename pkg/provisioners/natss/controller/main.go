@@ -18,29 +18,47 @@ package main
 
 import (
 	"flag"
+	"log"
+	"os"
 
 	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	securityv1beta1 "github.com/knative/eventing/pkg/apis/istio/security/v1beta1"
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/leaderelection"
+	"github.com/knative/eventing/pkg/logconfig"
 	"github.com/knative/eventing/pkg/provisioners"
 	"github.com/knative/eventing/pkg/provisioners/natss/controller/channel"
 	"github.com/knative/eventing/pkg/provisioners/natss/controller/clusterchannelprovisioner"
+	"github.com/knative/eventing/pkg/system"
 	istiov1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	"github.com/knative/pkg/signals"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 func main() {
-	logConfig := provisioners.NewLoggingConfig()
-	logger := provisioners.NewProvisionerLoggerFromConfig(logConfig)
+	flag.Parse()
+
+	// set up signals so we handle the first shutdown signal gracefully
+	stopCh := signals.SetupSignalHandler()
+
+	cfg := config.GetConfigOrDie()
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Fatalf("Unable to create the kubernetes client: %v", err)
+	}
+
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.NatssController, stopCh)
 	defer logger.Sync()
 	logger = logger.With(
 		zap.String("eventing.knative.dev/clusterChannelProvisioner", clusterchannelprovisioner.Name),
 		zap.String("eventing.knative.dev/clusterChannelProvisionerComponent", "Controller"),
 	)
-	flag.Parse()
 
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
+	mgr, err := namespaced.NewManager(cfg, manager.Options{}, namespaced.NamespacesFromEnv(), provisioners.CacheSelectors(clusterchannelprovisioner.Name)...)
 	if err != nil {
 		logger.Fatal("Error starting up.", zap.Error(err))
 	}
@@ -48,6 +66,13 @@ func main() {
 	// Add custom types to this array to get them into the manager's scheme.
 	eventingv1alpha1.AddToScheme(mgr.GetScheme())
 	istiov1alpha3.AddToScheme(mgr.GetScheme())
+	securityv1beta1.AddToScheme(mgr.GetScheme())
+
+	// Coalesce the status updates of the Channels, so that reconciling many of them at once does
+	// not overwhelm the API server.
+	if err := provisioners.CoalesceStatusUpdates(mgr, logger.Desugar()); err != nil {
+		logger.Fatal("Invalid status update configuration", zap.Error(err))
+	}
 
 	_, err = clusterchannelprovisioner.ProvideController(mgr, logger.Desugar())
 	if err != nil {
@@ -58,9 +83,28 @@ func main() {
 		logger.Fatal("Unable to create Channel controller", zap.Error(err))
 	}
 
-	stopCh := signals.SetupSignalHandler()
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		go func() {
+			if err := provisioners.ServeMetrics(":"+port, stopCh); err != nil {
+				logger.Error("Unable to serve the metrics", zap.Error(err))
+			}
+		}()
+	}
+
+	if addr := debug.Addr(); addr != "" {
+		go func() {
+			if err := debug.Serve(addr, stopCh); err != nil {
+				logger.Error("Unable to serve the debug endpoints", zap.Error(err))
+			}
+		}()
+	}
+
+	election, err := leaderelection.ConfigFromEnv("natss-controller")
+	if err != nil {
+		logger.Fatal("Invalid leader election configuration", zap.Error(err))
+	}
 
-	err = mgr.Start(stopCh)
+	err = leaderelection.RunManager(stopCh, election, mgr, logger.Desugar())
 	if err != nil {
 		logger.Fatal("Manager.Start() returned an error", zap.Error(err))
 	}
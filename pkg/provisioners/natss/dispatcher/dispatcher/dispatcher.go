@@ -17,12 +17,15 @@ limitations under the License.
 package dispatcher
 
 import (
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
 	"github.com/knative/eventing/pkg/provisioners/natss/controller/clusterchannelprovisioner"
 	"github.com/knative/eventing/pkg/provisioners/natss/stanutil"
+	"github.com/knative/eventing/pkg/provisioners/schema"
 	"github.com/nats-io/go-nats-streaming"
 	"go.uber.org/zap"
 
@@ -44,13 +47,28 @@ type SubscriptionsSupervisor struct {
 
 	subscriptionsMux sync.Mutex
 	subscriptions    map[provisioners.ChannelReference]map[subscriptionReference]*stan.Subscription
+
+	// subscriberDefaults holds the provisioners.DispatchDefaults of the subscriptions, keyed by
+	// their subscriptionReference, with the filter, the validator, the transform and the
+	// authenticator of the deliveries to them, the validator and the authenticator created by
+	// schemaResolver and authResolver.
+	subscriberDefaults sync.Map
+	schemaResolver     *schema.Resolver
+	authResolver       *auth.Resolver
 }
 
-func NewDispatcher(natssUrl string, logger *zap.Logger) (*SubscriptionsSupervisor, error) {
+// NewDispatcher creates a SubscriptionsSupervisor connected to the NATSS server at natssUrl.
+// schemaResolver reads the schemas of the subscribers that declare one, the events to which are
+// not validated if it is nil. authResolver obtains the credentials of the subscribers that declare auth, the deliveries to
+// which fail if it is nil. receiverOpts configure the receipt of the events sent to the channels,
+// and opts their delivery to the subscribers.
+func NewDispatcher(natssUrl string, logger *zap.Logger, schemaResolver *schema.Resolver, authResolver *auth.Resolver, receiverOpts []provisioners.ReceiverOption, opts ...provisioners.DispatcherOption) (*SubscriptionsSupervisor, error) {
 	d := &SubscriptionsSupervisor{
-		logger:        logger,
-		dispatcher:    provisioners.NewMessageDispatcher(logger.Sugar()),
-		subscriptions: make(map[provisioners.ChannelReference]map[subscriptionReference]*stan.Subscription),
+		logger:         logger,
+		schemaResolver: schemaResolver,
+		authResolver:   authResolver,
+		dispatcher:     provisioners.NewMessageDispatcher(logger.Sugar(), opts...),
+		subscriptions:  make(map[provisioners.ChannelReference]map[subscriptionReference]*stan.Subscription),
 	}
 	nConn, err := stanutil.Connect(clusterchannelprovisioner.ClusterId, clientId, natssUrl, d.logger.Sugar())
 	if err != nil {
@@ -58,7 +76,7 @@ func NewDispatcher(natssUrl string, logger *zap.Logger) (*SubscriptionsSuperviso
 		return nil, err
 	}
 	d.natssConn = nConn
-	d.receiver = provisioners.NewMessageReceiver(createReceiverFunction(d, logger.Sugar()), logger.Sugar())
+	d.receiver = provisioners.NewMessageReceiver(createReceiverFunction(d, logger.Sugar()), logger.Sugar(), receiverOpts...)
 
 	return d, nil
 }
@@ -82,6 +100,17 @@ func (s *SubscriptionsSupervisor) Start(stopCh <-chan struct{}) error {
 	return nil
 }
 
+// Ready returns an error if the dispatcher lost its connection to the NATS Streaming server.
+func (s *SubscriptionsSupervisor) Ready() error {
+	if s.natssConn == nil || *s.natssConn == nil {
+		return errors.New("not connected to NATSS")
+	}
+	if nc := (*s.natssConn).NatsConn(); nc == nil || !nc.IsConnected() {
+		return errors.New("connection to NATSS lost")
+	}
+	return nil
+}
+
 func (s *SubscriptionsSupervisor) UpdateSubscriptions(channel *eventingv1alpha1.Channel, isFinalizer bool) error {
 	s.subscriptionsMux.Lock()
 	defer s.subscriptionsMux.Unlock()
@@ -112,8 +141,23 @@ func (s *SubscriptionsSupervisor) UpdateSubscriptions(channel *eventingv1alpha1.
 		s.subscriptions[cRef] = chMap
 	}
 	for _, sub := range subscriptions {
-		// check if the subscription already exist and do nothing in this case
 		subRef := newSubscriptionReference(sub)
+		defaults := provisioners.DispatchDefaults{
+			Filter:                  provisioners.SubscriberFilter(s.logger, sub),
+			Validator:               s.schemaResolver.SubscriberValidator(s.logger, sub),
+			Transform:               provisioners.SubscriberTransform(s.logger, sub),
+			NonConformingDeadLetter: sub.DeadLetterURI,
+		}
+		if sub.Auth != nil {
+			defaults.Auth = s.authResolver.Authenticator(subRef.Namespace, sub.Auth)
+		}
+		s.subscriberDefaults.Store(subRef, defaults)
+		if sub.Paused {
+			// close the subscription, but keep its durable state so that it resumes where it stopped
+			s.pause(cRef, subRef)
+			continue
+		}
+		// check if the subscription already exist and do nothing in this case
 		if _, ok := chMap[subRef]; ok {
 			activeSubs[subRef] = true
 			s.logger.Sugar().Infof("Subscription: %v already active for channel: %v", sub, cRef)
@@ -149,7 +193,17 @@ func (s *SubscriptionsSupervisor) subscribe(channel provisioners.ChannelReferenc
 			Headers: map[string]string{},
 			Payload: []byte(msg.Data),
 		}
-		if err := s.dispatcher.DispatchMessage(&message, subscription.SubscriberURI, subscription.ReplyURI, provisioners.DispatchDefaults{Namespace: subscription.Namespace}); err != nil {
+		var defaults provisioners.DispatchDefaults
+		if d, ok := s.subscriberDefaults.Load(subscription); ok {
+			defaults = d.(provisioners.DispatchDefaults)
+		}
+		defaults.Namespace = subscription.Namespace
+		defaults.OnError = subscription.ErrorURI
+		defaults.Channel = channel.String()
+		defaults.Subscription = subscription.Namespace + "/" + subscription.Name
+		defaults.Redelivery = msg.Redelivered
+		defaults.Protocol = subscription.Protocol
+		if err := s.dispatcher.DispatchMessage(&message, subscription.SubscriberURI, subscription.ReplyURI, defaults); err != nil {
 			s.logger.Error("Failed to dispatch message: ", zap.Error(err))
 			return
 		}
@@ -180,6 +234,21 @@ func (s *SubscriptionsSupervisor) unsubscribe(channel provisioners.ChannelRefere
 			return err
 		}
 		delete(s.subscriptions[channel], subscription)
+		s.subscriberDefaults.Delete(subscription)
+	}
+	return nil
+}
+
+// pause closes the subscription without removing its durable state from NATSS. It should be called
+// only while holding subscriptionsMux.
+func (s *SubscriptionsSupervisor) pause(channel provisioners.ChannelReference, subscription subscriptionReference) error {
+	if stanSub, ok := s.subscriptions[channel][subscription]; ok {
+		s.logger.Info("Pause subscription to channel:", zap.Any("channel", channel), zap.Any("subscription", subscription))
+		if err := (*stanSub).Close(); err != nil {
+			s.logger.Error("Closing NATS Streaming subscription failed: ", zap.Error(err))
+			return err
+		}
+		delete(s.subscriptions[channel], subscription)
 	}
 	return nil
 }
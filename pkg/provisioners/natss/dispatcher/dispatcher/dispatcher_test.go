@@ -76,7 +76,7 @@ func TestMain(m *testing.M) {
 	defer stopNatss(stanServer)
 
 	// start Dispatcher
-	s, err = NewDispatcher(natssTestUrl, logger.Desugar())
+	s, err = NewDispatcher(natssTestUrl, logger.Desugar(), nil, nil, nil)
 	if err != nil {
 		logger.Fatalf("Unable to create NATSS dispatcher: %v", err)
 	}
@@ -110,6 +110,17 @@ func TestSubscribeUnsubscribe(t *testing.T) {
 	}
 }
 
+func TestReady(t *testing.T) {
+	logger.Info("TestReady()")
+
+	if err := s.Ready(); err != nil {
+		t.Errorf("Expected the dispatcher connected to NATSS to be ready: %v", err)
+	}
+	if err := (&SubscriptionsSupervisor{}).Ready(); err == nil {
+		t.Errorf("Expected the dispatcher without a NATSS connection not to be ready")
+	}
+}
+
 func TestUpdateSubscriptions(t *testing.T) {
 	logger.Info("TestUpdateSubscriptions()")
 
@@ -137,6 +148,44 @@ func TestUpdateSubscriptions(t *testing.T) {
 	}
 }
 
+func TestUpdateSubscriptionsPaused(t *testing.T) {
+	logger.Info("TestUpdateSubscriptionsPaused()")
+
+	c := makeChannel()
+	c.Spec.Subscribable = subscribers.DeepCopy()
+	if err := s.UpdateSubscriptions(c, false); err != nil {
+		t.Errorf("UpdateSubscriptions failed: %v", err)
+	}
+
+	// pause the first subscription
+	c.Spec.Subscribable.Subscribers[0].Paused = true
+	if err := s.UpdateSubscriptions(c, false); err != nil {
+		t.Errorf("UpdateSubscriptions failed: %v", err)
+	}
+
+	cRef := provisioners.ChannelReference{Namespace: c.Namespace, Name: c.Name}
+	chMap := s.subscriptions[cRef]
+	if len(chMap) != len(subscribers.Subscribers)-1 {
+		t.Errorf("Wrong channel map length: %v", chMap)
+	}
+	if _, ok := chMap[newSubscriptionReference(c.Spec.Subscribable.Subscribers[0])]; ok {
+		t.Errorf("Paused subscription is still active")
+	}
+
+	// resume it
+	c.Spec.Subscribable.Subscribers[0].Paused = false
+	if err := s.UpdateSubscriptions(c, false); err != nil {
+		t.Errorf("UpdateSubscriptions failed: %v", err)
+	}
+	if len(s.subscriptions[cRef]) != len(subscribers.Subscribers) {
+		t.Errorf("Wrong channel map length: %v", s.subscriptions[cRef])
+	}
+
+	if err := s.UpdateSubscriptions(c, true); err != nil {
+		t.Errorf("UpdateSubscriptions failed: %v", err)
+	}
+}
+
 func startNatss() (*server.StanServer, error) {
 	logger.Infof("Start NATSS")
 	var err error
@@ -27,6 +27,8 @@ type subscriptionReference struct {
 	Namespace     string
 	SubscriberURI string
 	ReplyURI      string
+	ErrorURI      string
+	Protocol      eventingduck.DeliveryProtocol
 }
 
 func newSubscriptionReference(spec eventingduck.ChannelSubscriberSpec) subscriptionReference {
@@ -35,6 +37,8 @@ func newSubscriptionReference(spec eventingduck.ChannelSubscriberSpec) subscript
 		Namespace:     spec.Ref.Namespace,
 		SubscriberURI: spec.SubscriberURI,
 		ReplyURI:      spec.ReplyURI,
+		ErrorURI:      spec.ErrorURI,
+		Protocol:      spec.Protocol,
 	}
 }
 
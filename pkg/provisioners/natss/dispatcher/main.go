@@ -18,13 +18,28 @@ package main
 
 import (
 	"log"
+	"os"
 	"time"
 
+	"github.com/knative/eventing/pkg/controller/namespaced"
+	"github.com/knative/eventing/pkg/debug"
+	"github.com/knative/eventing/pkg/logconfig"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/amqp/ingress"
+	"github.com/knative/eventing/pkg/provisioners/audit"
+	"github.com/knative/eventing/pkg/provisioners/auth"
 	"github.com/knative/eventing/pkg/provisioners/natss/dispatcher/channel"
 	"github.com/knative/eventing/pkg/provisioners/natss/dispatcher/dispatcher"
+	"github.com/knative/eventing/pkg/provisioners/push"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+	"github.com/knative/eventing/pkg/provisioners/signing"
+	"github.com/knative/eventing/pkg/provisioners/tap"
+	"github.com/knative/eventing/pkg/system"
+	"github.com/knative/eventing/pkg/tracing"
 	"github.com/knative/pkg/signals"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -44,14 +59,9 @@ var (
 
 func main() {
 
-	logger, err := zap.NewProduction()
+	mgr, err := namespaced.NewManager(config.GetConfigOrDie(), manager.Options{}, namespaced.NamespacesFromEnv())
 	if err != nil {
-		log.Fatalf("Unable to create logger: %v", err)
-	}
-
-	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{})
-	if err != nil {
-		logger.Fatal("Error starting up.", zap.Error(err))
+		log.Fatalf("Error starting up: %v", err)
 	}
 
 	// Add custom types to this array to get them into the manager's scheme.
@@ -60,8 +70,77 @@ func main() {
 	stopCh := signals.SetupSignalHandler()
 	var g errgroup.Group
 
+	kc, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.Fatalf("Unable to create the kubernetes client: %v", err)
+	}
+	logger := logconfig.NewLogger(kc, system.Namespace, logconfig.NatssDispatcher, stopCh).Desugar()
+
+	if err := tracing.WatchConfigMap(kc, system.Namespace, logconfig.NatssDispatcher, logger.Sugar(), stopCh); err != nil {
+		logger.Warn("Spans are not exported until the tracing config map is created", zap.Error(err))
+	}
+
 	logger.Info("Dispatcher starting...")
-	dispatcher, err := dispatcher.NewDispatcher(clusterchannelprovisioner.NatssUrl, logger)
+	if err := provisioners.SetMetricsGranularity(os.Getenv(provisioners.MetricsGranularityEnv)); err != nil {
+		logger.Fatal("Invalid metrics configuration", zap.Error(err))
+	}
+	var opts []provisioners.DispatcherOption
+	auditSink, err := audit.FromEnv(logger, stopCh)
+	if err != nil {
+		logger.Fatal("Invalid audit configuration", zap.Error(err))
+	}
+	if auditSink != nil {
+		opts = append(opts, provisioners.WithAuditSink(auditSink))
+	}
+	tlsConfig, err := auth.TLSConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid client certificate configuration", zap.Error(err))
+	}
+	if tlsConfig != nil {
+		opts = append(opts, provisioners.WithTLSConfig(tlsConfig))
+	}
+	var receiverOpts []provisioners.ReceiverOption
+	tapHub, err := tap.FromEnv(kc, logger, stopCh)
+	if err != nil {
+		logger.Fatal("Invalid tap configuration", zap.Error(err))
+	}
+	if tapHub != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithTap(tapHub))
+	}
+	pushHub, err := push.FromEnv(kc, logger, stopCh)
+	if err != nil {
+		logger.Fatal("Invalid push configuration", zap.Error(err))
+	}
+	if pushHub != nil {
+		opts = append(opts, provisioners.WithPusher(pushHub))
+	}
+	if err := ingress.FromEnv(provisioners.MessageReceiverPort, logger, stopCh); err != nil {
+		logger.Fatal("Invalid AMQP configuration", zap.Error(err))
+	}
+	maxBodySize, err := provisioners.MaxBodySizeFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid maximum body size", zap.Error(err))
+	}
+	receiverOpts = append(receiverOpts, provisioners.WithMaxBodySize(maxBodySize))
+	opts = append(opts, provisioners.WithMaxReplySize(maxBodySize))
+	// Events can be signed at the ingress, so that the subscribers can authenticate their origin,
+	// and their signature verified before they are delivered.
+	signer, err := signing.SignerFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid signing configuration", zap.Error(err))
+	}
+	if signer != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithSigning(signer))
+	}
+	verifier, err := signing.VerifierFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid signature verification configuration", zap.Error(err))
+	}
+	if verifier != nil {
+		receiverOpts = append(receiverOpts, provisioners.WithSignedMessageVerification(verifier))
+		opts = append(opts, provisioners.WithSignatureVerification(verifier))
+	}
+	dispatcher, err := dispatcher.NewDispatcher(clusterchannelprovisioner.NatssUrl, logger, schema.NewResolver(schema.KubeConfigMapGetter(kc)), auth.NewResolver(auth.KubeSecretGetter(kc)), receiverOpts, opts...)
 	if err != nil {
 		logger.Fatal("Unable to create NATSS dispatcher.", zap.Error(err))
 	}
@@ -70,6 +149,25 @@ func main() {
 		return dispatcher.Start(stopCh)
 	})
 
+	if port := os.Getenv(provisioners.MetricsPortEnv); port != "" {
+		g.Go(func() error {
+			return provisioners.ServeMetrics(":"+port, stopCh)
+		})
+	}
+
+	if addr := debug.Addr(); addr != "" {
+		g.Go(func() error {
+			return debug.Serve(addr, stopCh)
+		})
+	}
+
+	if port := os.Getenv(provisioners.HealthPortEnv); port != "" {
+		checks := map[string]provisioners.ReadinessCheck{"natss": dispatcher.Ready}
+		g.Go(func() error {
+			return provisioners.ServeHealth(":"+port, checks, stopCh)
+		})
+	}
+
 	_, err = channel.ProvideController(dispatcher, mgr, logger)
 	if err != nil {
 		logger.Fatal("Unable to create Channel controller", zap.Error(err))
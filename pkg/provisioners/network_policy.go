@@ -0,0 +1,271 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/system"
+)
+
+const (
+	// NetworkPolicyConfigMapName is the name of the ConfigMap in the system namespace configuring
+	// the NetworkPolicies of the dispatchers.
+	NetworkPolicyConfigMapName = "config-network-policy"
+
+	networkPolicyEnabledKey           = "enabled"
+	networkPolicyProducerNamespaceKey = "producer-namespace-selector"
+	networkPolicyGatewayKey           = "gateway-selector"
+	networkPolicyOpenPortsKey         = "open-ports"
+
+	// dispatcherPort is the port the dispatchers receive events on.
+	dispatcherPort = 8080
+	// amqpPort is the port the dispatchers receive events from AMQP producers on, when enabled.
+	amqpPort = 5672
+)
+
+// NetworkPolicyConfig configures the NetworkPolicy restricting ingress to the dispatcher of a
+// ClusterChannelProvisioner.
+type NetworkPolicyConfig struct {
+	// Enabled is whether the NetworkPolicy is created.
+	Enabled bool
+	// ProducerNamespaceSelector selects the namespaces whose pods may send events to the channels.
+	// An empty selector selects every namespace.
+	ProducerNamespaceSelector *metav1.LabelSelector
+	// GatewaySelector selects the pods of the mesh gateway, in any namespace, that may send events
+	// to the channels.
+	GatewaySelector *metav1.LabelSelector
+	// OpenPorts are the ports of the dispatcher reachable from anywhere, such as the health, metrics,
+	// tap and push ports.
+	OpenPorts []int32
+}
+
+// DefaultNetworkPolicyConfig returns the config used when the ConfigMap does not exist: events are
+// only accepted from pods in the cluster and from the Istio ingress gateway.
+func DefaultNetworkPolicyConfig() *NetworkPolicyConfig {
+	return &NetworkPolicyConfig{
+		Enabled:                   true,
+		ProducerNamespaceSelector: &metav1.LabelSelector{},
+		GatewaySelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"istio": "ingressgateway"}},
+		OpenPorts:                 []int32{8081, 8082, 8083, 9090},
+	}
+}
+
+// NewNetworkPolicyConfigFromConfigMap creates a NetworkPolicyConfig from cm. The keys that are not
+// set keep their default value.
+func NewNetworkPolicyConfigFromConfigMap(cm *corev1.ConfigMap) (*NetworkPolicyConfig, error) {
+	config := DefaultNetworkPolicyConfig()
+	if v, ok := cm.Data[networkPolicyEnabledKey]; ok {
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", networkPolicyEnabledKey, v, err)
+		}
+		config.Enabled = enabled
+	}
+	if v, ok := cm.Data[networkPolicyProducerNamespaceKey]; ok {
+		s, err := parseLabelSelector(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", networkPolicyProducerNamespaceKey, v, err)
+		}
+		config.ProducerNamespaceSelector = s
+	}
+	if v, ok := cm.Data[networkPolicyGatewayKey]; ok {
+		s, err := parseLabelSelector(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", networkPolicyGatewayKey, v, err)
+		}
+		config.GatewaySelector = s
+	}
+	if v, ok := cm.Data[networkPolicyOpenPortsKey]; ok {
+		config.OpenPorts = nil
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p == "" {
+				continue
+			}
+			port, err := strconv.ParseInt(p, 10, 32)
+			if err != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("invalid %s %q: %q is not a port", networkPolicyOpenPortsKey, v, p)
+			}
+			config.OpenPorts = append(config.OpenPorts, int32(port))
+		}
+	}
+	return config, nil
+}
+
+// parseLabelSelector parses a label selector such as 'team=payments,tier!=test'.
+func parseLabelSelector(s string) (*metav1.LabelSelector, error) {
+	selector, err := metav1.ParseToLabelSelector(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	// Leave the parts of the selector that are not used unset, as they are in the NetworkPolicies
+	// read from the API server.
+	if len(selector.MatchLabels) == 0 {
+		selector.MatchLabels = nil
+	}
+	if len(selector.MatchExpressions) == 0 {
+		selector.MatchExpressions = nil
+	}
+	return selector, nil
+}
+
+// getNetworkPolicyConfig reads the NetworkPolicyConfig from its ConfigMap, if it exists.
+func getNetworkPolicyConfig(ctx context.Context, client runtimeClient.Client) (*NetworkPolicyConfig, error) {
+	cm := &corev1.ConfigMap{}
+	err := client.Get(ctx, types.NamespacedName{Namespace: system.Namespace, Name: NetworkPolicyConfigMapName}, cm)
+	if k8serrors.IsNotFound(err) {
+		return DefaultNetworkPolicyConfig(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return NewNetworkPolicyConfigFromConfigMap(cm)
+}
+
+// CreateDispatcherNetworkPolicy creates or updates the NetworkPolicy restricting ingress to the
+// dispatcher of ccp, as configured by the NetworkPolicyConfigMapName ConfigMap. When the
+// NetworkPolicies are disabled, the NetworkPolicy of ccp is deleted and nil is returned.
+func CreateDispatcherNetworkPolicy(ctx context.Context, client runtimeClient.Client, ccp *eventingv1alpha1.ClusterChannelProvisioner) (*networkingv1.NetworkPolicy, error) {
+	config, err := getNetworkPolicyConfig(ctx, client)
+	if err != nil {
+		recordEvent(ctx, ccp, corev1.EventTypeWarning, NetworkPolicyReconcileFailed, "Failed to read the NetworkPolicy config: %v", err)
+		return nil, err
+	}
+
+	npKey := types.NamespacedName{
+		Namespace: system.Namespace,
+		Name:      ChannelDispatcherServiceName(ccp.Name),
+	}
+	current := &networkingv1.NetworkPolicy{}
+	err = client.Get(ctx, npKey, current)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		recordEvent(ctx, ccp, corev1.EventTypeWarning, NetworkPolicyReconcileFailed, "Failed to get NetworkPolicy %q: %v", npKey.Name, err)
+		return nil, err
+	}
+	exists := err == nil
+
+	if !config.Enabled {
+		if exists && metav1.IsControlledBy(current, ccp) {
+			if err := client.Delete(ctx, current); err != nil && !k8serrors.IsNotFound(err) {
+				recordEvent(ctx, ccp, corev1.EventTypeWarning, NetworkPolicyReconcileFailed, "Failed to delete NetworkPolicy %q: %v", npKey.Name, err)
+				return nil, err
+			}
+			recordEvent(ctx, ccp, corev1.EventTypeNormal, NetworkPolicyDeleted, "Deleted NetworkPolicy %q", npKey.Name)
+		}
+		return nil, nil
+	}
+
+	np := newDispatcherNetworkPolicy(ccp, config)
+	if !exists {
+		if err := client.Create(ctx, np); err != nil {
+			recordEvent(ctx, ccp, corev1.EventTypeWarning, NetworkPolicyReconcileFailed, "Failed to create NetworkPolicy %q: %v", npKey.Name, err)
+			return nil, err
+		}
+		recordEvent(ctx, ccp, corev1.EventTypeNormal, NetworkPolicyCreated, "Created NetworkPolicy %q", npKey.Name)
+		return np, nil
+	}
+
+	if !equality.Semantic.DeepEqual(np.Spec, current.Spec) {
+		current.Spec = np.Spec
+		if err := client.Update(ctx, current); err != nil {
+			recordEvent(ctx, ccp, corev1.EventTypeWarning, NetworkPolicyReconcileFailed, "Failed to update NetworkPolicy %q: %v", npKey.Name, err)
+			return nil, err
+		}
+		recordEvent(ctx, ccp, corev1.EventTypeNormal, NetworkPolicyUpdated, "Updated NetworkPolicy %q", npKey.Name)
+	}
+	return current, nil
+}
+
+// newDispatcherNetworkPolicy creates the NetworkPolicy of the dispatcher of ccp. Events are
+// accepted from the producer namespaces and from the gateway, the open ports from anywhere.
+func newDispatcherNetworkPolicy(ccp *eventingv1alpha1.ClusterChannelProvisioner, config *NetworkPolicyConfig) *networkingv1.NetworkPolicy {
+	labels := DispatcherLabels(ccp.Name)
+	eventsPort := intstr.FromInt(dispatcherPort)
+	eventsAMQPPort := intstr.FromInt(amqpPort)
+	ingress := []networkingv1.NetworkPolicyIngressRule{{
+		Ports: []networkingv1.NetworkPolicyPort{{Port: &eventsPort}, {Port: &eventsAMQPPort}},
+		From: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: config.ProducerNamespaceSelector,
+		}, {
+			NamespaceSelector: &metav1.LabelSelector{},
+			PodSelector:       config.GatewaySelector,
+		}},
+	}}
+	if len(config.OpenPorts) > 0 {
+		rule := networkingv1.NetworkPolicyIngressRule{}
+		for _, p := range config.OpenPorts {
+			port := intstr.FromInt(int(p))
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Port: &port})
+		}
+		ingress = append(ingress, rule)
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ChannelDispatcherServiceName(ccp.Name),
+			Namespace: system.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ccp, schema.GroupVersionKind{
+					Group:   eventingv1alpha1.SchemeGroupVersion.Group,
+					Version: eventingv1alpha1.SchemeGroupVersion.Version,
+					Kind:    "ClusterChannelProvisioner",
+				}),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+}
+
+// WatchDispatcherNetworkPolicy makes c reconcile the ClusterChannelProvisioner ccpName when its
+// NetworkPolicy or the NetworkPolicyConfigMapName ConfigMap change.
+func WatchDispatcherNetworkPolicy(c controller.Controller, ccpName string) error {
+	err := c.Watch(&source.Kind{
+		Type: &networkingv1.NetworkPolicy{},
+	}, &handler.EnqueueRequestForOwner{OwnerType: &eventingv1alpha1.ClusterChannelProvisioner{}, IsController: true})
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{
+		Type: &corev1.ConfigMap{},
+	}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+		if o.Meta.GetNamespace() != system.Namespace || o.Meta.GetName() != NetworkPolicyConfigMapName {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: ccpName}}}
+	})})
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/system"
+)
+
+func TestNewNetworkPolicyConfigFromConfigMap(t *testing.T) {
+	testCases := map[string]struct {
+		data    map[string]string
+		want    *NetworkPolicyConfig
+		wantErr bool
+	}{
+		"empty": {
+			want: DefaultNetworkPolicyConfig(),
+		},
+		"disabled": {
+			data: map[string]string{"enabled": "false"},
+			want: func() *NetworkPolicyConfig {
+				c := DefaultNetworkPolicyConfig()
+				c.Enabled = false
+				return c
+			}(),
+		},
+		"selectors and ports": {
+			data: map[string]string{
+				"producer-namespace-selector": "eventing.knative.dev/producer=true",
+				"gateway-selector":            "app=gateway",
+				"open-ports":                  "9090, 8081",
+			},
+			want: &NetworkPolicyConfig{
+				Enabled:                   true,
+				ProducerNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"eventing.knative.dev/producer": "true"}},
+				GatewaySelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gateway"}},
+				OpenPorts:                 []int32{9090, 8081},
+			},
+		},
+		"no open ports": {
+			data: map[string]string{"open-ports": ""},
+			want: func() *NetworkPolicyConfig {
+				c := DefaultNetworkPolicyConfig()
+				c.OpenPorts = nil
+				return c
+			}(),
+		},
+		"invalid enabled": {
+			data:    map[string]string{"enabled": "maybe"},
+			wantErr: true,
+		},
+		"invalid selector": {
+			data:    map[string]string{"gateway-selector": "app in (gateway"},
+			wantErr: true,
+		},
+		"invalid port": {
+			data:    map[string]string{"open-ports": "8081,70000"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewNetworkPolicyConfigFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected config (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateDispatcherNetworkPolicy(t *testing.T) {
+	npKey := runtimeClient.ObjectKey{Namespace: system.Namespace, Name: ChannelDispatcherServiceName(clusterChannelProvisionerName)}
+	configMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace, Name: NetworkPolicyConfigMapName},
+			Data:       data,
+		}
+	}
+	testCases := map[string]struct {
+		objects []runtime.Object
+		want    *networkingv1.NetworkPolicy
+		wantErr bool
+	}{
+		"default": {
+			want: makeDispatcherNetworkPolicy(),
+		},
+		"modified": {
+			objects: []runtime.Object{func() *networkingv1.NetworkPolicy {
+				np := makeDispatcherNetworkPolicy()
+				np.Spec.Ingress = nil
+				return np
+			}()},
+			want: makeDispatcherNetworkPolicy(),
+		},
+		"configured": {
+			objects: []runtime.Object{configMap(map[string]string{
+				"producer-namespace-selector": "team=payments",
+				"open-ports":                  "",
+			})},
+			want: func() *networkingv1.NetworkPolicy {
+				np := makeDispatcherNetworkPolicy()
+				np.Spec.Ingress = np.Spec.Ingress[:1]
+				np.Spec.Ingress[0].From[0].NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}
+				return np
+			}(),
+		},
+		"disabled": {
+			objects: []runtime.Object{configMap(map[string]string{"enabled": "false"}), makeDispatcherNetworkPolicy()},
+		},
+		"invalid config": {
+			objects: []runtime.Object{configMap(map[string]string{"enabled": "maybe"}), makeDispatcherNetworkPolicy()},
+			want:    makeDispatcherNetworkPolicy(),
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			client := fake.NewFakeClient(tc.objects...)
+			_, err := CreateDispatcherNetworkPolicy(context.TODO(), client, getNewClusterChannelProvisioner())
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error. Expected error %v, actual %v", tc.wantErr, err)
+			}
+
+			got := &networkingv1.NetworkPolicy{}
+			err = client.Get(context.TODO(), npKey, got)
+			if tc.want == nil {
+				if !k8serrors.IsNotFound(err) {
+					t.Errorf("Expected the NetworkPolicy to be deleted, got %v, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unable to get the NetworkPolicy: %v", err)
+			}
+			if diff := cmp.Diff(tc.want.Spec, got.Spec); diff != "" {
+				t.Errorf("Unexpected NetworkPolicy spec (-want +got): %s", diff)
+			}
+			if !metav1.IsControlledBy(got, getNewClusterChannelProvisioner()) {
+				t.Errorf("Expected the NetworkPolicy to be controlled by the ClusterChannelProvisioner")
+			}
+		})
+	}
+}
+
+func makeDispatcherNetworkPolicy() *networkingv1.NetworkPolicy {
+	port := func(p int) *intstr.IntOrString {
+		v := intstr.FromInt(p)
+		return &v
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      ChannelDispatcherServiceName(clusterChannelProvisionerName),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         eventingv1alpha1.SchemeGroupVersion.String(),
+				Kind:               "ClusterChannelProvisioner",
+				Name:               clusterChannelProvisionerName,
+				Controller:         &truePointer,
+				BlockOwnerDeletion: &truePointer,
+			}},
+			Labels: DispatcherLabels(clusterChannelProvisionerName),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: DispatcherLabels(clusterChannelProvisionerName)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{{Port: port(8080)}, {Port: port(5672)}},
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{},
+				}, {
+					NamespaceSelector: &metav1.LabelSelector{},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"istio": "ingressgateway"}},
+				}},
+			}, {
+				Ports: []networkingv1.NetworkPolicyPort{{Port: port(8081)}, {Port: port(8082)}, {Port: port(8083)}, {Port: port(9090)}},
+			}},
+		},
+	}
+}
@@ -19,15 +19,34 @@ import (
 	"github.com/knative/pkg/logging"
 )
 
+const (
+	// statefulSetPodNameLabel is the label StatefulSets set on their pods to their name.
+	statefulSetPodNameLabel = "statefulset.kubernetes.io/pod-name"
+)
+
 func CreateDispatcherService(ctx context.Context, client runtimeClient.Client, ccp *eventingv1alpha1.ClusterChannelProvisioner) (*corev1.Service, error) {
 	svcName := ChannelDispatcherServiceName(ccp.Name)
 	svcKey := types.NamespacedName{
 		Namespace: system.Namespace,
 		Name:      svcName,
 	}
-	return createK8sService(ctx, client, svcKey, newDispatcherService(ccp))
+	return createK8sService(ctx, client, ccp, svcKey, newDispatcherService(ccp))
+}
+
+// CreateDispatcherShardService creates or updates the Service of one shard of the dispatcher of the
+// provisioner of c, selecting the pod with the ordinal shard of the dispatcher StatefulSet. It is
+// shared by the Channels of the shard, so it is not owned by c, which Events are recorded on.
+func CreateDispatcherShardService(ctx context.Context, client runtimeClient.Client, c *eventingv1alpha1.Channel, shard int) (*corev1.Service, error) {
+	svcName := ChannelDispatcherShardServiceName(c.Spec.Provisioner.Name, shard)
+	svcKey := types.NamespacedName{
+		Namespace: system.Namespace,
+		Name:      svcName,
+	}
+	return createK8sService(ctx, client, c, svcKey, newDispatcherShardService(c.Spec.Provisioner.Name, shard))
 }
 
+// UpdateClusterChannelProvisionerStatus updates the status of the ClusterChannelProvisioner to that
+// of u, through the status subresource, by the status Coalescer when one is set.
 func UpdateClusterChannelProvisionerStatus(ctx context.Context, client runtimeClient.Client, u *eventingv1alpha1.ClusterChannelProvisioner) error {
 	o := &eventingv1alpha1.ClusterChannelProvisioner{}
 	if err := client.Get(ctx, runtimeClient.ObjectKey{Namespace: u.Namespace, Name: u.Name}, o); err != nil {
@@ -37,8 +56,11 @@ func UpdateClusterChannelProvisionerStatus(ctx context.Context, client runtimeCl
 	}
 
 	if !equality.Semantic.DeepEqual(o.Status, u.Status) {
+		if statusCoalescer != nil {
+			return statusCoalescer.Update(u)
+		}
 		o.Status = u.Status
-		return client.Update(ctx, o)
+		return client.Status().Update(ctx, o)
 	}
 	return nil
 }
@@ -52,7 +74,7 @@ func newDispatcherService(ccp *eventingv1alpha1.ClusterChannelProvisioner) *core
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ChannelDispatcherServiceName(ccp.Name),
 			Namespace: system.Namespace,
-			Labels:    labels,
+			Labels:    dispatcherServiceLabels(ccp.Name),
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(ccp, schema.GroupVersionKind{
 					Group:   eventingv1alpha1.SchemeGroupVersion.Group,
@@ -74,6 +96,40 @@ func newDispatcherService(ccp *eventingv1alpha1.ClusterChannelProvisioner) *core
 	}
 }
 
+// newDispatcherShardService creates a new Service for one shard of the dispatcher of the
+// ClusterChannelProvisioner ccpName.
+func newDispatcherShardService(ccpName string, shard int) *corev1.Service {
+	name := ChannelDispatcherShardServiceName(ccpName, shard)
+	selector := DispatcherLabels(ccpName)
+	selector[statefulSetPodNameLabel] = name
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: system.Namespace,
+			Labels:    dispatcherServiceLabels(ccpName),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+}
+
+// dispatcherServiceLabels returns the labels of the dispatcher Services of ccpName, which are also
+// labeled by provisioner like the Services of its Channels, so that its controller can select them
+// all.
+func dispatcherServiceLabels(ccpName string) map[string]string {
+	labels := DispatcherLabels(ccpName)
+	labels["provisioner"] = ccpName
+	return labels
+}
+
 func DispatcherLabels(ccpName string) map[string]string {
 	return map[string]string{
 		"clusterChannelProvisioner": ccpName,
@@ -84,3 +140,9 @@ func DispatcherLabels(ccpName string) map[string]string {
 func ChannelDispatcherServiceName(ccpName string) string {
 	return fmt.Sprintf("%s-dispatcher", ccpName)
 }
+
+// ChannelDispatcherShardServiceName is the name of the Service of one shard of the dispatcher of
+// ccpName, which is also the name of the pod of the dispatcher StatefulSet serving the shard.
+func ChannelDispatcherShardServiceName(ccpName string, shard int) string {
+	return fmt.Sprintf("%s-%d", ChannelDispatcherServiceName(ccpName), shard)
+}
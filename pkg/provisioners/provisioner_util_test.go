@@ -60,6 +60,24 @@ func TestProvisionerUtils(t *testing.T) {
 			return got, err
 		},
 		want: makeDispatcherService(),
+	}, {
+		name: "CreateDispatcherService_AddLabels",
+		f: func() (metav1.Object, error) {
+			existing := makeDispatcherService()
+			existing.Labels = DispatcherLabels(clusterChannelProvisionerName)
+			existing.Labels["other"] = "label"
+			client := fake.NewFakeClient(existing)
+			CreateDispatcherService(context.TODO(), client, getNewClusterChannelProvisioner())
+
+			got := &corev1.Service{}
+			err := client.Get(context.TODO(), runtimeClient.ObjectKey{Namespace: system.Namespace, Name: fmt.Sprintf("%s-dispatcher", clusterChannelProvisionerName)}, got)
+			return got, err
+		},
+		want: func() metav1.Object {
+			svc := makeDispatcherService()
+			svc.Labels["other"] = "label"
+			return svc
+		}(),
 	}, {
 		name: "CreateDispatcherService_DoNotModifyClusterIP",
 		f: func() (metav1.Object, error) {
@@ -77,6 +95,26 @@ func TestProvisionerUtils(t *testing.T) {
 			svc.Spec.ClusterIP = testClusterIP
 			return svc
 		}(),
+	}, {
+		name: "CreateDispatcherShardService",
+		f: func() (metav1.Object, error) {
+			client := fake.NewFakeClient()
+			return CreateDispatcherShardService(context.TODO(), client, getNewChannel(), 2)
+		},
+		want: makeDispatcherShardService(),
+	}, {
+		name: "CreateDispatcherShardService_Existing",
+		f: func() (metav1.Object, error) {
+			existing := makeDispatcherShardService()
+			existing.Spec.ClusterIP = testClusterIP
+			client := fake.NewFakeClient(existing)
+			return CreateDispatcherShardService(context.TODO(), client, getNewChannel(), 2)
+		},
+		want: func() metav1.Object {
+			svc := makeDispatcherShardService()
+			svc.Spec.ClusterIP = testClusterIP
+			return svc
+		}(),
 	}, {
 		name: "UpdateClusterChannelProvisioner",
 		f: func() (metav1.Object, error) {
@@ -125,6 +163,12 @@ func TestNames(t *testing.T) {
 			return ChannelDispatcherServiceName("foo")
 		},
 		Want: "foo-dispatcher",
+	}, {
+		Name: "ChannelDispatcherShardServiceName",
+		F: func() string {
+			return ChannelDispatcherShardServiceName("foo", 3)
+		},
+		Want: "foo-dispatcher-3",
 	}}
 
 	for _, tc := range testCases {
@@ -168,7 +212,7 @@ func makeDispatcherService() *corev1.Service {
 					BlockOwnerDeletion: &truePointer,
 				},
 			},
-			Labels: DispatcherLabels(clusterChannelProvisionerName),
+			Labels: dispatcherServiceLabels(clusterChannelProvisionerName),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: DispatcherLabels(clusterChannelProvisionerName),
@@ -182,3 +226,25 @@ func makeDispatcherService() *corev1.Service {
 		},
 	}
 }
+
+func makeDispatcherShardService() *corev1.Service {
+	selector := DispatcherLabels(clusterChannelProvisionerName)
+	selector["statefulset.kubernetes.io/pod-name"] = fmt.Sprintf("%s-dispatcher-2", clusterChannelProvisionerName)
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace,
+			Name:      fmt.Sprintf("%s-dispatcher-2", clusterChannelProvisionerName),
+			Labels:    dispatcherServiceLabels(clusterChannelProvisionerName),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package push pushes the events of the Subscriptions with the SSE protocol to the consumers
+// connected to the dispatcher, as Server-Sent Events, for the subscribers behind NAT or firewalls
+// that cannot expose an endpoint. Connecting requires the permission to get the push subresource
+// of the Subscription.
+package push
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/knative/eventing/pkg/apis/eventing"
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+const (
+	// PortEnv enables pushing the events of a dispatcher. Its value is the port consumers connect
+	// to.
+	PortEnv = "PUSH_PORT"
+
+	// pushTimeout is how long a push waits for a consumer to receive the event.
+	pushTimeout = 30 * time.Second
+
+	// keepAliveInterval is the interval of the comments written to idle consumers, so that the
+	// proxies between them and the dispatcher keep their connection open.
+	keepAliveInterval = 30 * time.Second
+)
+
+// ErrNoConsumer is returned by Push when no consumer of the Subscription is connected.
+var ErrNoConsumer = errors.New("no consumer connected")
+
+// Hub pushes the events of Subscriptions to their connected consumers. Each event is pushed to a
+// single consumer, in turn.
+type Hub struct {
+	authorize authz.Authorizer
+	logger    *zap.Logger
+
+	lock      sync.Mutex
+	consumers map[string][]*consumer
+	next      map[string]int
+}
+
+// consumer is a connected consumer, receiving the events sent on deliveries until done is closed.
+type consumer struct {
+	deliveries chan *delivery
+	done       chan struct{}
+}
+
+// delivery is an event pushed to a consumer, which sends the result of writing it on result.
+type delivery struct {
+	id     string
+	event  []byte
+	result chan error
+}
+
+var _ provisioners.Pusher = (*Hub)(nil)
+
+// NewHub creates a Hub serving the consumers allowed by authorize to get the push subresource of
+// the Subscription.
+func NewHub(authorize authz.Authorizer, logger *zap.Logger) *Hub {
+	return &Hub{
+		authorize: authorize,
+		logger:    logger,
+		consumers: make(map[string][]*consumer),
+		next:      make(map[string]int),
+	}
+}
+
+// FromEnv returns the Hub configured by the environment variables of the process, or nil if
+// events are not pushed. The Hub is served until stopCh is closed.
+func FromEnv(kc kubernetes.Interface, logger *zap.Logger, stopCh <-chan struct{}) (*Hub, error) {
+	port := os.Getenv(PortEnv)
+	if port == "" {
+		return nil, nil
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %v", PortEnv, port, err)
+	}
+	hub := NewHub(authz.KubeAuthorizer(kc), logger)
+	go func() {
+		if err := hub.Serve(":"+port, stopCh); err != nil {
+			logger.Error("Unable to serve the push consumers", zap.Error(err))
+		}
+	}()
+	return hub, nil
+}
+
+// Push pushes message to a consumer of subscription, the namespace/name of a Subscription. It
+// returns once the event was written to the consumer, and fails if no consumer is connected or it
+// does not receive the event within pushTimeout.
+func (h *Hub) Push(subscription string, message *provisioners.Message) error {
+	c := h.pick(subscription)
+	if c == nil {
+		return ErrNoConsumer
+	}
+	event, err := message.StructuredJSON()
+	if err != nil {
+		return fmt.Errorf("unable to encode the event: %v", err)
+	}
+	d := &delivery{
+		id:     message.Attributes()["id"],
+		event:  event,
+		result: make(chan error, 1),
+	}
+
+	timer := time.NewTimer(pushTimeout)
+	defer timer.Stop()
+	select {
+	case c.deliveries <- d:
+	case <-c.done:
+		return errors.New("the consumer disconnected")
+	case <-timer.C:
+		return errors.New("timed out waiting for the consumer")
+	}
+	select {
+	case err := <-d.result:
+		return err
+	case <-timer.C:
+		return errors.New("timed out writing the event to the consumer")
+	}
+}
+
+// pick returns the next consumer of subscription, or nil if none is connected.
+func (h *Hub) pick(subscription string) *consumer {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	consumers := h.consumers[subscription]
+	if len(consumers) == 0 {
+		return nil
+	}
+	i := h.next[subscription] % len(consumers)
+	h.next[subscription] = i + 1
+	return consumers[i]
+}
+
+// ServeHTTP pushes the events of the Subscription /subscriptions/<namespace>/<name> to the client
+// as Server-Sent Events, until it disconnects.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "subscriptions" || parts[1] == "" || parts[2] == "" {
+		http.Error(w, "the path must be /subscriptions/<namespace>/<name>", http.StatusNotFound)
+		return
+	}
+	subscription := types.NamespacedName{Namespace: parts[1], Name: parts[2]}
+
+	err := h.authorize(req, authorizationv1.ResourceAttributes{
+		Namespace:   subscription.Namespace,
+		Verb:        "get",
+		Group:       eventing.GroupName,
+		Resource:    "subscriptions",
+		Subresource: "push",
+		Name:        subscription.Name,
+	})
+	switch err {
+	case nil:
+	case authz.ErrUnauthenticated:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case authz.ErrForbidden:
+		http.Error(w, fmt.Sprintf("not allowed to consume subscription %s", subscription.String()), http.StatusForbidden)
+		return
+	default:
+		h.logger.Error("Unable to authorize the consumer", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c := &consumer{deliveries: make(chan *delivery), done: make(chan struct{})}
+	h.add(subscription.String(), c)
+	defer h.remove(subscription.String(), c)
+	h.logger.Info("Consumer connected", zap.String("subscription", subscription.String()))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, ": consuming %s\n\n", subscription.String())
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case d := <-c.deliveries:
+			_, err := fmt.Fprintf(w, "id: %s\nevent: cloudevent\ndata: %s\n\n", strings.Replace(d.id, "\n", "", -1), d.event)
+			if err == nil {
+				flusher.Flush()
+			}
+			d.result <- err
+			if err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func (h *Hub) add(subscription string, c *consumer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.consumers[subscription] = append(h.consumers[subscription], c)
+}
+
+func (h *Hub) remove(subscription string, c *consumer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	close(c.done)
+	consumers := h.consumers[subscription]
+	for i, other := range consumers {
+		if other == c {
+			h.consumers[subscription] = append(consumers[:i:i], consumers[i+1:]...)
+			break
+		}
+	}
+	if len(h.consumers[subscription]) == 0 {
+		delete(h.consumers, subscription)
+		delete(h.next, subscription)
+	}
+}
+
+// Serve serves the Hub on addr until stopCh is closed.
+func (h *Hub) Serve(addr string, stopCh <-chan struct{}) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: h,
+	}
+	go func() {
+		<-stopCh
+		// Streams never become idle, so the server is closed rather than shut down.
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
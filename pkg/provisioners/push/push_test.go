@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package push
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var subscription = types.NamespacedName{Namespace: "test-namespace", Name: "test-subscription"}
+
+func allowAll(*http.Request, authorizationv1.ResourceAttributes) error {
+	return nil
+}
+
+func TestHubAuthorization(t *testing.T) {
+	testCases := map[string]struct {
+		method   string
+		path     string
+		err      error
+		wantCode int
+	}{
+		"not a GET": {
+			method:   http.MethodPost,
+			path:     "/subscriptions/test-namespace/test-subscription",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		"invalid path": {
+			path:     "/subscriptions/test-namespace",
+			wantCode: http.StatusNotFound,
+		},
+		"unauthenticated": {
+			path:     "/subscriptions/test-namespace/test-subscription",
+			err:      authz.ErrUnauthenticated,
+			wantCode: http.StatusUnauthorized,
+		},
+		"forbidden": {
+			path:     "/subscriptions/test-namespace/test-subscription",
+			err:      authz.ErrForbidden,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if tc.method == "" {
+				tc.method = http.MethodGet
+			}
+			want := authorizationv1.ResourceAttributes{
+				Namespace:   subscription.Namespace,
+				Verb:        "get",
+				Group:       "eventing.knative.dev",
+				Resource:    "subscriptions",
+				Subresource: "push",
+				Name:        subscription.Name,
+			}
+			hub := NewHub(func(_ *http.Request, attrs authorizationv1.ResourceAttributes) error {
+				if attrs != want {
+					t.Errorf("Unexpected attributes authorized. Expected %+v. Actual %+v", want, attrs)
+				}
+				return tc.err
+			}, zap.NewNop())
+			w := httptest.NewRecorder()
+			hub.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.wantCode {
+				t.Errorf("Unexpected status code. Expected %d. Actual %d", tc.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHubPush(t *testing.T) {
+	hub := NewHub(allowAll, zap.NewNop())
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	if err := hub.Push(subscription.String(), &provisioners.Message{Payload: []byte("no consumer")}); err != ErrNoConsumer {
+		t.Errorf("Unexpected error without consumer. Expected %v. Actual %v", ErrNoConsumer, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/subscriptions/test-namespace/test-subscription", nil)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Unable to consume the subscription: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected status code. Expected 200. Actual %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+
+	lines := bufio.NewReader(resp.Body)
+	// The comment is written once the consumer is added.
+	if line, _ := lines.ReadString('\n'); !strings.HasPrefix(line, ": consuming") {
+		t.Fatalf("Unexpected first line %q", line)
+	}
+
+	messages := []*provisioners.Message{{
+		Headers: map[string]string{
+			"Ce-Specversion": "1.0",
+			"Ce-Id":          "123",
+			"Ce-Type":        "dev.knative.test",
+			"Content-Type":   "application/json",
+		},
+		Payload: []byte(`{"hello":"world"}`),
+	}, {
+		Headers: map[string]string{
+			"Ce-Specversion": "1.0",
+			"Ce-Id":          "456",
+			"Content-Type":   "application/octet-stream",
+		},
+		Payload: []byte{0xff, 0xfe},
+	}}
+	for _, m := range messages {
+		if err := hub.Push(subscription.String(), m); err != nil {
+			t.Fatalf("Unable to push the event: %v", err)
+		}
+	}
+
+	want := []map[string]interface{}{{
+		"specversion":     "1.0",
+		"id":              "123",
+		"type":            "dev.knative.test",
+		"datacontenttype": "application/json",
+		"data":            map[string]interface{}{"hello": "world"},
+	}, {
+		"specversion":     "1.0",
+		"id":              "456",
+		"datacontenttype": "application/octet-stream",
+		"data_base64":     "//4=",
+	}}
+	var got []map[string]interface{}
+	var ids []string
+	for len(got) < len(want) {
+		line, err := lines.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Unable to read the stream: %v", err)
+		}
+		if strings.HasPrefix(line, "id: ") {
+			ids = append(ids, strings.TrimSpace(strings.TrimPrefix(line, "id: ")))
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+			t.Fatalf("Unable to decode the event %q: %v", line, err)
+		}
+		got = append(got, e)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected events (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"123", "456"}, ids); diff != "" {
+		t.Errorf("Unexpected event IDs (-want, +got): %s", diff)
+	}
+
+	// The consumer is removed once the client disconnects.
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hub.lock.Lock()
+		n := len(hub.consumers)
+		hub.lock.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("The consumer was not removed after the client disconnected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
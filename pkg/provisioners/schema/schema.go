@@ -0,0 +1,257 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema builds Validators from the SubscriberSchema of a subscription. The JSON Schemas
+// are read from ConfigMaps in the subscription's namespace or from a Confluent Schema Registry
+// when a delivery is made, so updated schemas are picked up without reconfiguring the dispatcher.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/jsonschema"
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// schemaTTL is how long a schema that was read is reused before it is read again.
+	schemaTTL = 1 * time.Minute
+
+	// latestVersion is the registry version used when a SchemaRegistryRef does not specify one.
+	latestVersion = "latest"
+
+	// registrySchemaType is the only type of registered schema that events can be validated
+	// against.
+	registrySchemaType = "JSON"
+)
+
+var messagesNonConforming = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "channel",
+	Subsystem: "dispatcher",
+	Name:      "events_nonconforming_total",
+	Help:      "The number of events whose data does not conform to the schema of a subscription.",
+})
+
+func init() {
+	prometheus.MustRegister(messagesNonConforming)
+}
+
+// ConfigMapGetter reads a ConfigMap.
+type ConfigMapGetter func(namespace, name string) (*corev1.ConfigMap, error)
+
+// KubeConfigMapGetter returns a ConfigMapGetter that reads ConfigMaps from the Kubernetes API.
+func KubeConfigMapGetter(kc kubernetes.Interface) ConfigMapGetter {
+	return func(namespace, name string) (*corev1.ConfigMap, error) {
+		return kc.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	}
+}
+
+// Resolver creates Validators. It caches the schemas it reads, so it should be shared by all the
+// deliveries of a dispatcher.
+type Resolver struct {
+	getConfigMap ConfigMapGetter
+	httpClient   *http.Client
+	now          func() time.Time
+
+	lock    sync.Mutex
+	schemas map[string]cachedSchema
+}
+
+type cachedSchema struct {
+	schema  *jsonschema.Schema
+	raw     []byte
+	fetched time.Time
+}
+
+// NewResolver creates a Resolver that reads ConfigMaps using getConfigMap.
+func NewResolver(getConfigMap ConfigMapGetter) *Resolver {
+	return &Resolver{
+		getConfigMap: getConfigMap,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		now:          time.Now,
+		schemas:      make(map[string]cachedSchema),
+	}
+}
+
+// Validator returns a Validator for a subscriber in namespace. It returns nil if s is nil, meaning
+// that events are not validated.
+func (r *Resolver) Validator(namespace string, s *eventingduck.SubscriberSchema) *Validator {
+	if s == nil {
+		return nil
+	}
+	return &Validator{
+		resolver:  r,
+		namespace: namespace,
+		schema:    *s,
+	}
+}
+
+// SubscriberValidator returns the Validator of the DispatchDefaults of the deliveries to sub. It
+// returns nil, meaning that events are not validated, if sub declares no schema, or if r is nil
+// or sub has no Subscription reference to resolve the schema in.
+func (r *Resolver) SubscriberValidator(logger *zap.Logger, sub eventingduck.ChannelSubscriberSpec) provisioners.MessageValidator {
+	if sub.Schema == nil {
+		return nil
+	}
+	if r == nil || sub.Ref == nil {
+		logger.Warn("Subscription declares a schema, but schemas cannot be resolved, events will not be validated", zap.Any("subscription", sub.Ref))
+		return nil
+	}
+	return r.Validator(sub.Ref.Namespace, sub.Schema)
+}
+
+// Validator checks that the data of events conforms to the schema described by a
+// SubscriberSchema.
+type Validator struct {
+	resolver  *Resolver
+	namespace string
+	schema    eventingduck.SubscriberSchema
+}
+
+// Validate returns a *jsonschema.ValidationError if the data of m does not conform to the schema,
+// or another error if the schema cannot be read.
+func (v *Validator) Validate(m *provisioners.Message) error {
+	s, err := v.resolver.resolve(v.namespace, v.schema)
+	if err != nil {
+		return err
+	}
+	if err := s.schema.Validate(m.Data()); err != nil {
+		if _, ok := err.(*jsonschema.ValidationError); ok {
+			messagesNonConforming.Inc()
+		}
+		return err
+	}
+	return nil
+}
+
+// IsNonConforming returns true if err was returned by Validate because the data of an event does
+// not conform to the schema.
+func IsNonConforming(err error) bool {
+	_, ok := err.(*jsonschema.ValidationError)
+	return ok
+}
+
+// Document returns the JSON Schema selected by s, as it was read, for an object in namespace.
+func (r *Resolver) Document(namespace string, s eventingduck.SubscriberSchema) (json.RawMessage, error) {
+	cached, err := r.resolve(namespace, s)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(cached.raw), nil
+}
+
+// resolve returns the cached schema selected by s, or reads and compiles it.
+func (r *Resolver) resolve(namespace string, s eventingduck.SubscriberSchema) (cachedSchema, error) {
+	var key string
+	var read func() ([]byte, error)
+	switch {
+	case s.ConfigMapKeyRef != nil:
+		key = strings.Join([]string{"configmap", namespace, s.ConfigMapKeyRef.Name, s.ConfigMapKeyRef.Key}, "/")
+		read = func() ([]byte, error) {
+			return r.configMapSchema(namespace, *s.ConfigMapKeyRef)
+		}
+	case s.Registry != nil:
+		key = strings.Join([]string{"registry", s.Registry.URL, s.Registry.Subject, s.Registry.Version}, "\n")
+		read = func() ([]byte, error) {
+			return r.registrySchema(*s.Registry)
+		}
+	default:
+		return cachedSchema{}, fmt.Errorf("no schema configured")
+	}
+
+	now := r.now()
+	r.lock.Lock()
+	cached, ok := r.schemas[key]
+	r.lock.Unlock()
+	if ok && now.Sub(cached.fetched) <= schemaTTL {
+		return cached, nil
+	}
+
+	raw, err := read()
+	if err != nil {
+		return cachedSchema{}, err
+	}
+	compiled, err := jsonschema.Compile(raw)
+	if err != nil {
+		return cachedSchema{}, fmt.Errorf("unable to compile schema: %v", err)
+	}
+	cached = cachedSchema{schema: compiled, raw: raw, fetched: now}
+	r.lock.Lock()
+	r.schemas[key] = cached
+	r.lock.Unlock()
+	return cached, nil
+}
+
+func (r *Resolver) configMapSchema(namespace string, s corev1.ConfigMapKeySelector) ([]byte, error) {
+	key := namespace + "/" + s.Name
+	cm, err := r.getConfigMap(namespace, s.Name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configmap %s: %v", key, err)
+	}
+	v, ok := cm.Data[s.Key]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s does not contain key %q", key, s.Key)
+	}
+	return []byte(v), nil
+}
+
+// registryResponse is the response of the Confluent Schema Registry to a request for a version of
+// the schema of a subject. SchemaType is empty for Avro schemas.
+type registryResponse struct {
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+func (r *Resolver) registrySchema(ref eventingduck.SchemaRegistryRef) ([]byte, error) {
+	version := ref.Version
+	if version == "" {
+		version = latestVersion
+	}
+	u := strings.TrimSuffix(ref.URL, "/") + "/subjects/" + url.PathEscape(ref.Subject) + "/versions/" + url.PathEscape(version)
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request schema: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from schema registry, expected 200, got %d", res.StatusCode)
+	}
+	rr := registryResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&rr); err != nil {
+		return nil, fmt.Errorf("unable to decode schema registry response: %v", err)
+	}
+	if rr.SchemaType != registrySchemaType {
+		return nil, fmt.Errorf("schema of subject %q is not a JSON Schema", ref.Subject)
+	}
+	return []byte(rr.Schema), nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNS        = "test-namespace"
+	configMapName = "schemas"
+
+	orderSchema = `{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}}`
+)
+
+func schemasConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      configMapName,
+		},
+		Data: map[string]string{
+			"order.json":   orderSchema,
+			"invalid.json": `{"type": 1}`,
+		},
+	}
+}
+
+// getConfigMap serves schemasConfigMap.
+func getConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	cm := schemasConfigMap()
+	if namespace != cm.Namespace || name != cm.Name {
+		return nil, errors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+	return cm, nil
+}
+
+func configMapSchema(key string) *eventingduck.SubscriberSchema {
+	return &eventingduck.SubscriberSchema{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			Key:                  key,
+		},
+	}
+}
+
+func message(data string) *provisioners.Message {
+	return &provisioners.Message{
+		Headers: map[string]string{
+			"ce-specversion": "1.0",
+			"ce-id":          "1",
+			"ce-type":        "com.example.order",
+			"ce-source":      "/orders",
+			"content-type":   "application/json",
+		},
+		Payload: []byte(data),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/subjects/orders-value/versions/latest", "/subjects/orders-value/versions/3":
+			w.Write([]byte(`{"subject": "orders-value", "version": 3, "id": 7, "schemaType": "JSON", "schema": "{\"type\": \"object\", \"required\": [\"id\"]}"}`))
+		case "/subjects/avro-value/versions/latest":
+			w.Write([]byte(`{"subject": "avro-value", "version": 1, "id": 8, "schema": "\"string\""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	testCases := map[string]struct {
+		schema            *eventingduck.SubscriberSchema
+		data              string
+		wantNonConforming bool
+		wantErr           bool
+	}{
+		"configmap conforming": {
+			schema: configMapSchema("order.json"),
+			data:   `{"id": "1234"}`,
+		},
+		"configmap nonconforming": {
+			schema:            configMapSchema("order.json"),
+			data:              `{"id": 1234}`,
+			wantNonConforming: true,
+		},
+		"configmap missing key": {
+			schema:  configMapSchema("missing.json"),
+			data:    `{"id": "1234"}`,
+			wantErr: true,
+		},
+		"configmap invalid schema": {
+			schema:  configMapSchema("invalid.json"),
+			data:    `{"id": "1234"}`,
+			wantErr: true,
+		},
+		"registry latest conforming": {
+			schema: &eventingduck.SubscriberSchema{
+				Registry: &eventingduck.SchemaRegistryRef{URL: registry.URL, Subject: "orders-value"},
+			},
+			data: `{"id": "1234"}`,
+		},
+		"registry version nonconforming": {
+			schema: &eventingduck.SubscriberSchema{
+				Registry: &eventingduck.SchemaRegistryRef{URL: registry.URL + "/", Subject: "orders-value", Version: "3"},
+			},
+			data:              `{}`,
+			wantNonConforming: true,
+		},
+		"registry unknown subject": {
+			schema: &eventingduck.SubscriberSchema{
+				Registry: &eventingduck.SchemaRegistryRef{URL: registry.URL, Subject: "unknown"},
+			},
+			data:    `{}`,
+			wantErr: true,
+		},
+		"registry avro schema": {
+			schema: &eventingduck.SubscriberSchema{
+				Registry: &eventingduck.SchemaRegistryRef{URL: registry.URL, Subject: "avro-value"},
+			},
+			data:    `"1234"`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			v := NewResolver(getConfigMap).Validator(testNS, tc.schema)
+			err := v.Validate(message(tc.data))
+			if got := IsNonConforming(err); got != tc.wantNonConforming {
+				t.Errorf("Unexpected nonconforming, expected %v, got %v (%v)", tc.wantNonConforming, got, err)
+			}
+			if got := err != nil && !IsNonConforming(err); got != tc.wantErr {
+				t.Errorf("Unexpected error, expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatorNil(t *testing.T) {
+	if v := NewResolver(getConfigMap).Validator(testNS, nil); v != nil {
+		t.Errorf("Expected no validator, got %v", v)
+	}
+}
+
+func TestSubscriberValidator(t *testing.T) {
+	logger := zap.NewNop()
+	s := &eventingduck.SubscriberSchema{}
+	ref := &corev1.ObjectReference{Namespace: testNS, Name: "sub"}
+	var nilResolver *Resolver
+	if v := nilResolver.SubscriberValidator(logger, eventingduck.ChannelSubscriberSpec{Ref: ref, Schema: s}); v != nil {
+		t.Errorf("Expected no validator without a resolver, got %v", v)
+	}
+	r := NewResolver(getConfigMap)
+	if v := r.SubscriberValidator(logger, eventingduck.ChannelSubscriberSpec{Ref: ref}); v != nil {
+		t.Errorf("Expected no validator without a schema, got %v", v)
+	}
+	if v := r.SubscriberValidator(logger, eventingduck.ChannelSubscriberSpec{Schema: s}); v != nil {
+		t.Errorf("Expected no validator without a Subscription reference, got %v", v)
+	}
+	if v := r.SubscriberValidator(logger, eventingduck.ChannelSubscriberSpec{Ref: ref, Schema: s}); v == nil {
+		t.Error("Expected a validator")
+	}
+}
+
+func TestSchemaCache(t *testing.T) {
+	reads := 0
+	r := NewResolver(func(namespace, name string) (*corev1.ConfigMap, error) {
+		reads++
+		return getConfigMap(namespace, name)
+	})
+	now := time.Now()
+	r.now = func() time.Time {
+		return now
+	}
+	v := r.Validator(testNS, configMapSchema("order.json"))
+
+	for i := 0; i < 3; i++ {
+		if err := v.Validate(message(`{"id": "1234"}`)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("Expected the ConfigMap to be read once, read %d times", reads)
+	}
+
+	now = now.Add(2 * schemaTTL)
+	if err := v.Validate(message(`{"id": "1234"}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reads != 2 {
+		t.Errorf("Expected the ConfigMap to be read again after the TTL, read %d times", reads)
+	}
+}
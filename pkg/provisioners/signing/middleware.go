@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signing
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// VerifyRequest verifies the signature of the event in req, an event delivered by a channel. The
+// body of req is left readable.
+func VerifyRequest(v provisioners.Verifier, req *http.Request) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	m := &provisioners.Message{
+		Headers: make(map[string]string, len(req.Header)),
+		Payload: body,
+	}
+	for k, vs := range req.Header {
+		if len(vs) > 0 {
+			m.Headers[strings.ToLower(k)] = vs[0]
+		}
+	}
+	return m.VerifySignature(v)
+}
+
+// Middleware returns a handler that passes to next the events whose signature is verified by v, and
+// rejects the others with 401 Unauthorized. Subscribers use it to authenticate the origin of the
+// events delivered to them.
+func Middleware(v provisioners.Verifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if err := VerifyRequest(v, req); err != nil {
+			http.Error(res, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(res, req)
+	})
+}
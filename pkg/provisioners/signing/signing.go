@@ -0,0 +1,303 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package signing provides the provisioners.Signer and provisioners.Verifier of the channels, and
+// Middleware, which verifies the signature of the events delivered to a subscriber. Events are
+// signed with HMAC-SHA256 under a shared secret, or with Ed25519 so that the verifiers only hold
+// the public key.
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// The environment variables configuring the signing and the verification of events.
+const (
+	// KeyFileEnv is the path of the file, typically mounted from a Secret, holding the key the
+	// channel ingress signs events with: a base64 encoded HMAC secret, or a PEM encoded PKCS #8
+	// Ed25519 private key.
+	KeyFileEnv = "SIGNING_KEY_FILE"
+	// KeyIDEnv is the ID of the signing key, carried in the signatures so that verifiers find the
+	// key to verify them with. It defaults to the name of the key file.
+	KeyIDEnv = "SIGNING_KEY_ID"
+	// VerificationKeysDirEnv is the path of a directory, typically mounted from a Secret or a
+	// ConfigMap, holding one file per key the signatures are verified with, named after the ID of
+	// the key: a base64 encoded HMAC secret, or a PEM encoded PKIX Ed25519 public key.
+	VerificationKeysDirEnv = "SIGNATURE_VERIFICATION_KEYS_DIR"
+)
+
+// The algorithms of the signatures.
+const (
+	HMACSHA256 = "hmac-sha256"
+	Ed25519    = "ed25519"
+)
+
+// ErrUnknownKey is returned when verifying a signature by a key that is not known.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// ErrInvalidSignature is returned when a signature does not match the signed content.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Signature is the parsed value of the provisioners.SignatureExtension.
+type Signature struct {
+	KeyID     string
+	Algorithm string
+	Value     []byte
+}
+
+// String returns the value of the provisioners.SignatureExtension for s.
+func (s Signature) String() string {
+	return fmt.Sprintf("keyId=%s,algorithm=%s,signature=%s", s.KeyID, s.Algorithm, base64.StdEncoding.EncodeToString(s.Value))
+}
+
+// ParseSignature parses the value of the provisioners.SignatureExtension.
+func ParseSignature(value string) (Signature, error) {
+	var s Signature
+	for _, part := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return s, fmt.Errorf("malformed signature %q", value)
+		}
+		switch kv[0] {
+		case "keyId":
+			s.KeyID = kv[1]
+		case "algorithm":
+			s.Algorithm = kv[1]
+		case "signature":
+			v, err := base64.StdEncoding.DecodeString(kv[1])
+			if err != nil {
+				return s, fmt.Errorf("malformed signature %q: %v", value, err)
+			}
+			s.Value = v
+		}
+	}
+	if s.KeyID == "" || s.Algorithm == "" || len(s.Value) == 0 {
+		return s, fmt.Errorf("malformed signature %q", value)
+	}
+	return s, nil
+}
+
+// HMACKey signs and verifies with HMAC-SHA256 under a shared secret.
+type HMACKey struct {
+	ID     string
+	Secret []byte
+}
+
+var _ provisioners.Signer = (*HMACKey)(nil)
+var _ provisioners.Verifier = (*HMACKey)(nil)
+
+func (k *HMACKey) mac(content []byte) []byte {
+	mac := hmac.New(sha256.New, k.Secret)
+	mac.Write(content)
+	return mac.Sum(nil)
+}
+
+// Sign implements provisioners.Signer.
+func (k *HMACKey) Sign(content []byte) (string, error) {
+	return Signature{KeyID: k.ID, Algorithm: HMACSHA256, Value: k.mac(content)}.String(), nil
+}
+
+// Verify implements provisioners.Verifier.
+func (k *HMACKey) Verify(content []byte, signature string) error {
+	s, err := parseFor(signature, k.ID, HMACSHA256)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(s.Value, k.mac(content)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Ed25519PrivateKey signs with Ed25519.
+type Ed25519PrivateKey struct {
+	ID  string
+	Key ed25519.PrivateKey
+}
+
+var _ provisioners.Signer = (*Ed25519PrivateKey)(nil)
+
+// Sign implements provisioners.Signer.
+func (k *Ed25519PrivateKey) Sign(content []byte) (string, error) {
+	return Signature{KeyID: k.ID, Algorithm: Ed25519, Value: ed25519.Sign(k.Key, content)}.String(), nil
+}
+
+// Ed25519PublicKey verifies the signatures of an Ed25519PrivateKey.
+type Ed25519PublicKey struct {
+	ID  string
+	Key ed25519.PublicKey
+}
+
+var _ provisioners.Verifier = (*Ed25519PublicKey)(nil)
+
+// Verify implements provisioners.Verifier.
+func (k *Ed25519PublicKey) Verify(content []byte, signature string) error {
+	s, err := parseFor(signature, k.ID, Ed25519)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(k.Key, content, s.Value) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// parseFor parses signature and checks that it was made by the key id with algorithm.
+func parseFor(signature, id, algorithm string) (Signature, error) {
+	s, err := ParseSignature(signature)
+	if err != nil {
+		return s, err
+	}
+	if s.KeyID != id {
+		return s, ErrUnknownKey
+	}
+	if s.Algorithm != algorithm {
+		return s, fmt.Errorf("key %q does not verify %s signatures", id, s.Algorithm)
+	}
+	return s, nil
+}
+
+// KeySet verifies signatures with the key they name, so that the signing key can be rotated, and
+// events signed by several ingresses can be verified.
+type KeySet map[string]provisioners.Verifier
+
+var _ provisioners.Verifier = KeySet(nil)
+
+// Verify implements provisioners.Verifier.
+func (ks KeySet) Verify(content []byte, signature string) error {
+	s, err := ParseSignature(signature)
+	if err != nil {
+		return err
+	}
+	v, ok := ks[s.KeyID]
+	if !ok {
+		return ErrUnknownKey
+	}
+	return v.Verify(content, signature)
+}
+
+// parseKey parses a key file: a PEM encoded Ed25519 key, or a base64 encoded HMAC secret, which
+// both signs and verifies.
+func parseKey(id string, b []byte) (interface{}, error) {
+	if block, _ := pem.Decode(b); block != nil {
+		switch block.Type {
+		case "PRIVATE KEY":
+			k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if k, ok := k.(ed25519.PrivateKey); ok {
+				return &Ed25519PrivateKey{ID: id, Key: k}, nil
+			}
+			return nil, fmt.Errorf("unsupported private key %T", k)
+		case "PUBLIC KEY":
+			k, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if k, ok := k.(ed25519.PublicKey); ok {
+				return &Ed25519PublicKey{ID: id, Key: k}, nil
+			}
+			return nil, fmt.Errorf("unsupported public key %T", k)
+		}
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	secret, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) < sha256.Size {
+		return nil, fmt.Errorf("HMAC secrets must be at least %d bytes, got %d", sha256.Size, len(secret))
+	}
+	return &HMACKey{ID: id, Secret: secret}, nil
+}
+
+// SignerFromEnv returns the Signer configured by KeyFileEnv and KeyIDEnv, or nil if events are not
+// signed.
+func SignerFromEnv() (provisioners.Signer, error) {
+	keyFile := os.Getenv(KeyFileEnv)
+	if keyFile == "" {
+		return nil, nil
+	}
+	id := os.Getenv(KeyIDEnv)
+	if id == "" {
+		id = filepath.Base(keyFile)
+	}
+	b, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", KeyFileEnv, err)
+	}
+	k, err := parseKey(id, b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key in %s: %v", keyFile, err)
+	}
+	s, ok := k.(provisioners.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a signing key", keyFile)
+	}
+	return s, nil
+}
+
+// VerifierFromEnv returns the KeySet read from VerificationKeysDirEnv, or nil if signatures are not
+// verified.
+func VerifierFromEnv() (provisioners.Verifier, error) {
+	dir := os.Getenv(VerificationKeysDirEnv)
+	if dir == "" {
+		return nil, nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", VerificationKeysDirEnv, err)
+	}
+	ks := KeySet{}
+	for _, f := range files {
+		// Secrets and ConfigMaps are mounted with hidden files and directories.
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %v", VerificationKeysDirEnv, err)
+		}
+		k, err := parseKey(f.Name(), b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s in %s: %v", f.Name(), dir, err)
+		}
+		v, ok := k.(provisioners.Verifier)
+		if !ok {
+			return nil, fmt.Errorf("%s in %s is not a verification key", f.Name(), dir)
+		}
+		ks[f.Name()] = v
+	}
+	if len(ks) == 0 {
+		return nil, fmt.Errorf("no keys in %s", dir)
+	}
+	return ks, nil
+}
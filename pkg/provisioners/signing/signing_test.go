@@ -0,0 +1,220 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var content = []byte("1234\n/source\ndev.knative.test\n\n\n")
+
+func TestHMACKey(t *testing.T) {
+	k := &HMACKey{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)}
+	sig, err := k.Sign(content)
+	if err != nil {
+		t.Fatalf("Unexpected error signing: %v", err)
+	}
+	if err := k.Verify(content, sig); err != nil {
+		t.Errorf("Unexpected error verifying: %v", err)
+	}
+	if err := k.Verify([]byte("other"), sig); err != ErrInvalidSignature {
+		t.Errorf("Unexpected error verifying other content. Expected %v. Actual %v", ErrInvalidSignature, err)
+	}
+	other := &HMACKey{ID: "k1", Secret: bytes.Repeat([]byte{2}, 32)}
+	if err := other.Verify(content, sig); err != ErrInvalidSignature {
+		t.Errorf("Unexpected error verifying with another secret. Expected %v. Actual %v", ErrInvalidSignature, err)
+	}
+	if err := (&HMACKey{ID: "k2", Secret: k.Secret}).Verify(content, sig); err != ErrUnknownKey {
+		t.Errorf("Unexpected error verifying with another key ID. Expected %v. Actual %v", ErrUnknownKey, err)
+	}
+}
+
+func TestEd25519Key(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := (&Ed25519PrivateKey{ID: "k1", Key: priv}).Sign(content)
+	if err != nil {
+		t.Fatalf("Unexpected error signing: %v", err)
+	}
+	v := &Ed25519PublicKey{ID: "k1", Key: pub}
+	if err := v.Verify(content, sig); err != nil {
+		t.Errorf("Unexpected error verifying: %v", err)
+	}
+	if err := v.Verify([]byte("other"), sig); err != ErrInvalidSignature {
+		t.Errorf("Unexpected error verifying other content. Expected %v. Actual %v", ErrInvalidSignature, err)
+	}
+	hmacSig, _ := (&HMACKey{ID: "k1", Secret: []byte("secret")}).Sign(content)
+	if err := v.Verify(content, hmacSig); err == nil {
+		t.Errorf("Expected an error verifying an HMAC signature with an Ed25519 key")
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	testCases := map[string]bool{
+		"keyId=k1,algorithm=hmac-sha256,signature=AAEC":   false,
+		"keyId=k1, algorithm=ed25519, signature=AAEC":     false,
+		"keyId=k1,algorithm=hmac-sha256":                  true,
+		"keyId=k1,algorithm=hmac-sha256,signature=%%":     true,
+		"algorithm=hmac-sha256,signature=AAEC":            true,
+		"keyId=k1,algorithm=hmac-sha256,signature=AAEC,x": true,
+		"": true,
+	}
+	for value, wantErr := range testCases {
+		t.Run(value, func(t *testing.T) {
+			_, err := ParseSignature(value)
+			if wantErr != (err != nil) {
+				t.Errorf("Unexpected error. Expected %v. Actual %v", wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "signing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keysDir := filepath.Join(dir, "keys")
+	os.Mkdir(keysDir, 0700)
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	writeFile(t, filepath.Join(dir, "ed25519"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+	writeFile(t, filepath.Join(dir, "hmac"), []byte(secret+"\n"))
+	writeFile(t, filepath.Join(keysDir, "ingress-1"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	writeFile(t, filepath.Join(keysDir, "hmac"), []byte(secret))
+	writeFile(t, filepath.Join(keysDir, ".hidden"), []byte("not a key"))
+
+	defer os.Unsetenv(KeyFileEnv)
+	defer os.Unsetenv(KeyIDEnv)
+	defer os.Unsetenv(VerificationKeysDirEnv)
+
+	if s, err := SignerFromEnv(); s != nil || err != nil {
+		t.Errorf("Expected no signer without %s, got %v, %v", KeyFileEnv, s, err)
+	}
+	if v, err := VerifierFromEnv(); v != nil || err != nil {
+		t.Errorf("Expected no verifier without %s, got %v, %v", VerificationKeysDirEnv, v, err)
+	}
+
+	os.Setenv(VerificationKeysDirEnv, keysDir)
+	v, err := VerifierFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error from VerifierFromEnv: %v", err)
+	}
+
+	os.Setenv(KeyFileEnv, filepath.Join(dir, "ed25519"))
+	os.Setenv(KeyIDEnv, "ingress-1")
+	s, err := SignerFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error from SignerFromEnv: %v", err)
+	}
+	sig, _ := s.Sign(content)
+	if err := v.Verify(content, sig); err != nil {
+		t.Errorf("Unexpected error verifying the Ed25519 signature: %v", err)
+	}
+
+	os.Setenv(KeyFileEnv, filepath.Join(dir, "hmac"))
+	os.Unsetenv(KeyIDEnv)
+	s, err = SignerFromEnv()
+	if err != nil {
+		t.Fatalf("Unexpected error from SignerFromEnv: %v", err)
+	}
+	sig, _ = s.Sign(content)
+	if err := v.Verify(content, sig); err != nil {
+		t.Errorf("Unexpected error verifying the HMAC signature: %v", err)
+	}
+
+	os.Setenv(KeyFileEnv, filepath.Join(keysDir, "ingress-1"))
+	if _, err := SignerFromEnv(); err == nil {
+		t.Errorf("Expected an error signing with a public key")
+	}
+	writeFile(t, filepath.Join(dir, "short"), []byte(base64.StdEncoding.EncodeToString([]byte("short"))))
+	os.Setenv(KeyFileEnv, filepath.Join(dir, "short"))
+	if _, err := SignerFromEnv(); err == nil {
+		t.Errorf("Expected an error signing with a short HMAC secret")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	k := &HMACKey{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)}
+	m := &provisioners.Message{
+		Headers: map[string]string{"ce-id": "1234", "ce-source": "/source"},
+		Payload: []byte("data"),
+	}
+	if err := m.Sign(k); err != nil {
+		t.Fatalf("Unexpected error signing: %v", err)
+	}
+	var delivered []byte
+	h := Middleware(k, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		delivered, _ = ioutil.ReadAll(req.Body)
+	}))
+
+	send := func(headers map[string]string, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		return res.Code
+	}
+	if code := send(m.Headers, "data"); code != http.StatusOK {
+		t.Errorf("Unexpected status for a signed event. Expected %d. Actual %d", http.StatusOK, code)
+	}
+	if string(delivered) != "data" {
+		t.Errorf("Unexpected body passed on. Expected %q. Actual %q", "data", delivered)
+	}
+	if code := send(m.Headers, "tampered"); code != http.StatusUnauthorized {
+		t.Errorf("Unexpected status for a tampered event. Expected %d. Actual %d", http.StatusUnauthorized, code)
+	}
+	if code := send(map[string]string{"ce-id": "1234"}, "data"); code != http.StatusUnauthorized {
+		t.Errorf("Unexpected status for an unsigned event. Expected %d. Actual %d", http.StatusUnauthorized, code)
+	}
+}
+
+func writeFile(t *testing.T, name string, b []byte) {
+	t.Helper()
+	if err := ioutil.WriteFile(name, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioners
+
+import (
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/knative/eventing/pkg/controller/status"
+)
+
+// statusCoalescer coalesces the status updates of UpdateChannel and
+// UpdateClusterChannelProvisionerStatus, if it is set. It is set once, before the controllers
+// start.
+var statusCoalescer *status.Coalescer
+
+// CoalesceStatusUpdates makes UpdateChannel and UpdateClusterChannelProvisionerStatus coalesce the
+// status updates of the controllers of mgr, as configured by the environment variables of
+// status.OptionsFromEnv. It must be called before mgr is started.
+func CoalesceStatusUpdates(mgr manager.Manager, logger *zap.Logger) error {
+	opts, err := status.OptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	c := status.NewCoalescer(mgr.GetClient(), opts, logger)
+	if err := mgr.Add(c); err != nil {
+		return err
+	}
+	statusCoalescer = c
+	return nil
+}
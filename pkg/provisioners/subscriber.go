@@ -0,0 +1,53 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/filter"
+	"github.com/knative/eventing/pkg/transform"
+	"go.uber.org/zap"
+)
+
+// SubscriberFilter parses the filter of sub, the Filter of the DispatchDefaults of the deliveries
+// to it. Filters are validated when the Subscription is admitted, so failures here are unexpected.
+// A subscriber whose filter cannot be parsed receives no events, rather than every event.
+func SubscriberFilter(logger *zap.Logger, sub eventingduck.ChannelSubscriberSpec) filter.Expression {
+	f, err := filter.Parse(sub.Filter)
+	if err != nil {
+		logger.Error("Unable to parse subscription filter, no events will be delivered to it", zap.Error(err), zap.Any("subscription", sub.Ref), zap.String("filter", sub.Filter))
+		return matchNone{}
+	}
+	return f
+}
+
+// SubscriberTransform parses the transform of sub, the Transform of the DispatchDefaults of the
+// deliveries to it. Transforms are validated when the Subscription is admitted, so failures here
+// are unexpected. A subscriber whose transform cannot be parsed receives the events unchanged.
+func SubscriberTransform(logger *zap.Logger, sub eventingduck.ChannelSubscriberSpec) *transform.Template {
+	t, err := transform.Parse(sub.Transform)
+	if err != nil {
+		logger.Error("Unable to parse subscription transform, events will be delivered unchanged", zap.Error(err), zap.Any("subscription", sub.Ref), zap.String("transform", sub.Transform))
+	}
+	return t
+}
+
+type matchNone struct{}
+
+func (matchNone) Matches(map[string]string) bool {
+	return false
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tap streams a sampled copy of the events flowing through channels to the developers
+// watching them, as Server-Sent Events, so traffic can be inspected without deploying a sink.
+// Watching a channel requires the permission to get the tap subresource of the channel.
+package tap
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/knative/eventing/pkg/apis/eventing"
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+const (
+	// PortEnv enables the tap of a dispatcher. Its value is the port the tap is served on.
+	PortEnv = "TAP_PORT"
+
+	// listenerBufferSize is the number of events waiting to be streamed to a listener. Events are
+	// dropped when the buffer is full, rather than slowing the channel down.
+	listenerBufferSize = 100
+)
+
+// Event is the copy of an event streamed to the listeners of a channel.
+type Event struct {
+	Channel string            `json:"channel"`
+	Headers map[string]string `json:"headers"`
+	// Data is the payload of the event when it is valid UTF-8, DataBase64 otherwise.
+	Data       string `json:"data,omitempty"`
+	DataBase64 []byte `json:"data_base64,omitempty"`
+}
+
+// Hub publishes the events received by a dispatcher to the listeners of their channel.
+type Hub struct {
+	authorize authz.Authorizer
+	logger    *zap.Logger
+
+	lock      sync.RWMutex
+	listeners map[provisioners.ChannelReference]map[*listener]bool
+}
+
+type listener struct {
+	// sample is the probability for an event to be streamed to the listener.
+	sample float64
+	events chan []byte
+}
+
+var _ provisioners.Tap = (*Hub)(nil)
+
+// NewHub creates a Hub serving the listeners allowed by authorize to get the tap subresource of
+// the channel.
+func NewHub(authorize authz.Authorizer, logger *zap.Logger) *Hub {
+	return &Hub{
+		authorize: authorize,
+		logger:    logger,
+		listeners: make(map[provisioners.ChannelReference]map[*listener]bool),
+	}
+}
+
+// FromEnv returns the Hub configured by the environment variables of the process, or nil if the
+// events are not tapped. The Hub is served until stopCh is closed.
+func FromEnv(kc kubernetes.Interface, logger *zap.Logger, stopCh <-chan struct{}) (*Hub, error) {
+	port := os.Getenv(PortEnv)
+	if port == "" {
+		return nil, nil
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %v", PortEnv, port, err)
+	}
+	hub := NewHub(authz.KubeAuthorizer(kc), logger)
+	go func() {
+		if err := hub.Serve(":"+port, stopCh); err != nil {
+			logger.Error("Unable to serve the tap", zap.Error(err))
+		}
+	}()
+	return hub, nil
+}
+
+// Publish streams a copy of message to the listeners of channel. It never blocks: the events
+// listeners are too slow to receive are dropped.
+func (h *Hub) Publish(channel provisioners.ChannelReference, message *provisioners.Message) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	var event []byte
+	for l := range h.listeners[channel] {
+		if l.sample < 1 && rand.Float64() >= l.sample {
+			continue
+		}
+		if event == nil {
+			var err error
+			if event, err = encode(channel, message); err != nil {
+				h.logger.Warn("Unable to encode the tapped event", zap.Error(err))
+				return
+			}
+		}
+		select {
+		case l.events <- event:
+		default:
+		}
+	}
+}
+
+func encode(channel provisioners.ChannelReference, message *provisioners.Message) ([]byte, error) {
+	event := Event{
+		Channel: channel.String(),
+		Headers: message.Headers,
+	}
+	if utf8.Valid(message.Payload) {
+		event.Data = string(message.Payload)
+	} else {
+		event.DataBase64 = message.Payload
+	}
+	return json.Marshal(event)
+}
+
+// ServeHTTP streams the events of the channel /channels/<namespace>/<name> as Server-Sent Events,
+// until the client disconnects. The sample query parameter, between 0 and 1, is the fraction of
+// the events streamed.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "channels" || parts[1] == "" || parts[2] == "" {
+		http.Error(w, "the path must be /channels/<namespace>/<name>", http.StatusNotFound)
+		return
+	}
+	channel := provisioners.ChannelReference{Namespace: parts[1], Name: parts[2]}
+
+	sample := 1.0
+	if s := req.URL.Query().Get("sample"); s != "" {
+		var err error
+		if sample, err = strconv.ParseFloat(s, 64); err != nil || sample <= 0 || sample > 1 {
+			http.Error(w, "sample must be a number in (0, 1]", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := h.authorize(req, authorizationv1.ResourceAttributes{
+		Namespace:   channel.Namespace,
+		Verb:        "get",
+		Group:       eventing.GroupName,
+		Resource:    "channels",
+		Subresource: "tap",
+		Name:        channel.Name,
+	})
+	switch err {
+	case nil:
+	case authz.ErrUnauthenticated:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case authz.ErrForbidden:
+		http.Error(w, fmt.Sprintf("not allowed to tap channel %s", channel.String()), http.StatusForbidden)
+		return
+	default:
+		h.logger.Error("Unable to authorize the tap", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	l := &listener{sample: sample, events: make(chan []byte, listenerBufferSize)}
+	h.add(channel, l)
+	defer h.remove(channel, l)
+	h.logger.Info("Tapping channel", zap.String("channel", channel.String()), zap.Float64("sample", sample))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, ": tapping %s\n\n", channel.String())
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-l.events:
+			fmt.Fprintf(w, "event: cloudevent\ndata: %s\n\n", event)
+			flusher.Flush()
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func (h *Hub) add(channel provisioners.ChannelReference, l *listener) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.listeners[channel] == nil {
+		h.listeners[channel] = make(map[*listener]bool)
+	}
+	h.listeners[channel][l] = true
+}
+
+func (h *Hub) remove(channel provisioners.ChannelReference, l *listener) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.listeners[channel], l)
+	if len(h.listeners[channel]) == 0 {
+		delete(h.listeners, channel)
+	}
+}
+
+// Serve serves the Hub on addr until stopCh is closed.
+func (h *Hub) Serve(addr string, stopCh <-chan struct{}) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: h,
+	}
+	go func() {
+		<-stopCh
+		// Streams never become idle, so the server is closed rather than shut down.
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/knative/eventing/pkg/authz"
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var channel = provisioners.ChannelReference{Namespace: "test-namespace", Name: "test-channel"}
+
+func allowAll(*http.Request, authorizationv1.ResourceAttributes) error {
+	return nil
+}
+
+func TestHubAuthorization(t *testing.T) {
+	testCases := map[string]struct {
+		method   string
+		path     string
+		err      error
+		wantCode int
+	}{
+		"not a GET": {
+			method:   http.MethodPost,
+			path:     "/channels/test-namespace/test-channel",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		"invalid path": {
+			path:     "/channels/test-namespace",
+			wantCode: http.StatusNotFound,
+		},
+		"invalid sample": {
+			path:     "/channels/test-namespace/test-channel?sample=2",
+			wantCode: http.StatusBadRequest,
+		},
+		"unauthenticated": {
+			path:     "/channels/test-namespace/test-channel",
+			err:      authz.ErrUnauthenticated,
+			wantCode: http.StatusUnauthorized,
+		},
+		"forbidden": {
+			path:     "/channels/test-namespace/test-channel",
+			err:      authz.ErrForbidden,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if tc.method == "" {
+				tc.method = http.MethodGet
+			}
+			want := authorizationv1.ResourceAttributes{
+				Namespace:   channel.Namespace,
+				Verb:        "get",
+				Group:       "eventing.knative.dev",
+				Resource:    "channels",
+				Subresource: "tap",
+				Name:        channel.Name,
+			}
+			hub := NewHub(func(_ *http.Request, attrs authorizationv1.ResourceAttributes) error {
+				if attrs != want {
+					t.Errorf("Unexpected attributes authorized. Expected %+v. Actual %+v", want, attrs)
+				}
+				return tc.err
+			}, zap.NewNop())
+			w := httptest.NewRecorder()
+			hub.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.wantCode {
+				t.Errorf("Unexpected status code. Expected %d. Actual %d", tc.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHubStream(t *testing.T) {
+	hub := NewHub(allowAll, zap.NewNop())
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/channels/test-namespace/test-channel", nil)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Unable to tap the channel: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected status code. Expected 200. Actual %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+
+	lines := bufio.NewReader(resp.Body)
+	// The comment is written once the listener is added.
+	if line, _ := lines.ReadString('\n'); !strings.HasPrefix(line, ": tapping") {
+		t.Fatalf("Unexpected first line %q", line)
+	}
+
+	other := provisioners.ChannelReference{Namespace: "test-namespace", Name: "other-channel"}
+	hub.Publish(other, &provisioners.Message{Payload: []byte("not tapped")})
+	hub.Publish(channel, &provisioners.Message{
+		Headers: map[string]string{"Ce-Id": "123"},
+		Payload: []byte(`{"hello":"world"}`),
+	})
+	hub.Publish(channel, &provisioners.Message{Payload: []byte{0xff, 0xfe}})
+
+	want := []Event{{
+		Channel: "test-namespace/test-channel",
+		Headers: map[string]string{"Ce-Id": "123"},
+		Data:    `{"hello":"world"}`,
+	}, {
+		Channel:    "test-namespace/test-channel",
+		DataBase64: []byte{0xff, 0xfe},
+	}}
+	var got []Event
+	for len(got) < len(want) {
+		line, err := lines.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Unable to read the stream: %v", err)
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+			t.Fatalf("Unable to decode the event %q: %v", line, err)
+		}
+		got = append(got, e)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected events (-want, +got): %s", diff)
+	}
+
+	// The listener is removed once the client disconnects.
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hub.lock.RLock()
+		n := len(hub.listeners)
+		hub.lock.RUnlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("The listener was not removed after the client disconnected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHubPublishWithoutListener(t *testing.T) {
+	hub := NewHub(allowAll, zap.NewNop())
+	// Publishing must neither block nor fail when nobody is listening.
+	hub.Publish(channel, &provisioners.Message{Payload: []byte("dropped")})
+}
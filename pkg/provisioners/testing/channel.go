@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Channel is an in-process Channel. It receives events over HTTP like the dispatchers of the
+// provisioners do, records them, and fans them out to its subscribers. Sources send events to its
+// URI as they would to the address of a real Channel.
+type Channel struct {
+	// Namespace and Name identify the Channel to the receiver, as its host would.
+	Namespace string
+	Name      string
+
+	dispatcher provisioners.Dispatcher
+	receiver   *provisioners.MessageReceiver
+	server     *httptest.Server
+
+	mu          sync.Mutex
+	messages    []*provisioners.Message
+	subscribers []string
+}
+
+// NewChannel starts a Channel named name in namespace. Its events are delivered to its
+// subscribers with dispatcher, which defaults to a provisioners.MessageDispatcher when nil. The
+// Channel must be closed once the test completes.
+func NewChannel(namespace, name string, dispatcher provisioners.Dispatcher, opts ...provisioners.ReceiverOption) *Channel {
+	logger := zap.NewNop().Sugar()
+	if dispatcher == nil {
+		dispatcher = provisioners.NewMessageDispatcher(logger)
+	}
+	c := &Channel{
+		Namespace:  namespace,
+		Name:       name,
+		dispatcher: dispatcher,
+	}
+	c.receiver = provisioners.NewMessageReceiver(c.receive, logger, opts...)
+	c.server = httptest.NewServer(c)
+	return c
+}
+
+// URI is the URI events are sent to the Channel at.
+func (c *Channel) URI() string {
+	return c.server.URL + "/"
+}
+
+// Subscribe adds uri to the subscribers of the Channel. Only the events received afterwards are
+// delivered to it.
+func (c *Channel) Subscribe(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribers = append(c.subscribers, uri)
+}
+
+// Messages returns the events received by the Channel so far, in order.
+func (c *Channel) Messages() []*provisioners.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*provisioners.Message(nil), c.messages...)
+}
+
+// WaitForMessages waits until the Channel received at least n events, and returns them. It
+// returns an error if it did not within timeout.
+func (c *Channel) WaitForMessages(n int, timeout time.Duration) ([]*provisioners.Message, error) {
+	var messages []*provisioners.Message
+	err := wait.PollImmediate(10*time.Millisecond, timeout, func() (bool, error) {
+		messages = c.Messages()
+		return len(messages) >= n, nil
+	})
+	if err != nil {
+		return messages, fmt.Errorf("received %d events, expected %d", len(messages), n)
+	}
+	return messages, nil
+}
+
+// Close stops the Channel.
+func (c *Channel) Close() {
+	c.server.Close()
+}
+
+// ServeHTTP receives the events sent to the Channel, whatever the host they were sent to.
+func (c *Channel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Host = provisioners.ChannelHostName(c.Name, c.Namespace)
+	c.receiver.HandleRequest(w, r)
+}
+
+// receive records m and fans it out to the subscribers of the Channel. The event is rejected if
+// any of them fails to accept it, as the in-memory dispatcher does.
+func (c *Channel) receive(_ provisioners.ChannelReference, m *provisioners.Message) error {
+	c.mu.Lock()
+	c.messages = append(c.messages, CopyMessage(m))
+	subscribers := append([]string(nil), c.subscribers...)
+	c.mu.Unlock()
+
+	defaults := provisioners.DispatchDefaults{
+		Namespace: c.Namespace,
+		Channel:   c.Name,
+	}
+	for _, s := range subscribers {
+		if err := c.dispatcher.DispatchMessage(CopyMessage(m), s, "", defaults); err != nil {
+			return err
+		}
+	}
+	return nil
+}
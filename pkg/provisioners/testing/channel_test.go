@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+func send(t *testing.T, uri, id string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, uri, bytes.NewBufferString(`{"hello":"world"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", provisioners.CloudEventsSpecVersion)
+	req.Header.Set("Ce-Id", id)
+	req.Header.Set("Ce-Type", "dev.knative.test")
+	req.Header.Set("Ce-Source", "/test")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func TestChannel(t *testing.T) {
+	d := &RecordingDispatcher{}
+	c := NewChannel("test-namespace", "test-channel", d)
+	defer c.Close()
+	c.Subscribe("http://first.test-namespace.svc.cluster.local/")
+	c.Subscribe("http://second.test-namespace.svc.cluster.local/")
+
+	if status := send(t, c.URI(), "1"); status != http.StatusAccepted {
+		t.Fatalf("Unexpected status. Expected %d. Actual %d", http.StatusAccepted, status)
+	}
+	messages, err := c.WaitForMessages(1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := messages[0].Headers["Ce-Id"]; id != "1" {
+		t.Errorf("Unexpected event id. Expected %q. Actual %q", "1", id)
+	}
+
+	var destinations []string
+	for _, dispatch := range d.Dispatches() {
+		destinations = append(destinations, dispatch.Destination)
+		if dispatch.Defaults.Namespace != "test-namespace" || dispatch.Defaults.Channel != "test-channel" {
+			t.Errorf("Unexpected dispatch defaults: %+v", dispatch.Defaults)
+		}
+		if diff := cmp.Diff(messages[0].Payload, dispatch.Message.Payload); diff != "" {
+			t.Errorf("Unexpected dispatched payload (-want, +got): %s", diff)
+		}
+	}
+	want := []string{"http://first.test-namespace.svc.cluster.local/", "http://second.test-namespace.svc.cluster.local/"}
+	if diff := cmp.Diff(want, destinations); diff != "" {
+		t.Errorf("Unexpected destinations (-want, +got): %s", diff)
+	}
+}
+
+func TestChannelRejectsFailedDelivery(t *testing.T) {
+	d := &RecordingDispatcher{Err: errors.New("subscriber down")}
+	c := NewChannel("test-namespace", "test-channel", d)
+	defer c.Close()
+	c.Subscribe("http://subscriber.test-namespace.svc.cluster.local/")
+
+	if status := send(t, c.URI(), "1"); status == http.StatusAccepted {
+		t.Error("Expected the event to be rejected when its delivery fails")
+	}
+	if n := len(d.Dispatches()); n != 1 {
+		t.Errorf("Unexpected number of dispatches. Expected 1. Actual %d", n)
+	}
+}
+
+func TestChannelDeliversToSubscribers(t *testing.T) {
+	received := make(chan string, 1)
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("Ce-Id")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	c := NewChannel("test-namespace", "test-channel", nil)
+	defer c.Close()
+	c.Subscribe(subscriber.URL + "/")
+
+	if status := send(t, c.URI(), "1"); status != http.StatusAccepted {
+		t.Fatalf("Unexpected status. Expected %d. Actual %d", http.StatusAccepted, status)
+	}
+	select {
+	case id := <-received:
+		if id != "1" {
+			t.Errorf("Unexpected event id. Expected %q. Actual %q", "1", id)
+		}
+	default:
+		t.Error("The subscriber did not receive the event")
+	}
+}
+
+func TestWaitForMessagesTimesOut(t *testing.T) {
+	c := NewChannel("test-namespace", "test-channel", nil)
+	defer c.Close()
+	if _, err := c.WaitForMessages(1, 20*time.Millisecond); err == nil {
+		t.Error("Expected an error waiting for an event never sent")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing provides fakes of the clients of channel dispatchers, so that sources and
+// provisioners can be unit tested without a cluster nor real channels.
+package testing
+
+import (
+	"sync"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+// Dispatch is a call to RecordingDispatcher.DispatchMessage.
+type Dispatch struct {
+	Message     *provisioners.Message
+	Destination string
+	Reply       string
+	Defaults    provisioners.DispatchDefaults
+}
+
+// RecordingDispatcher is a provisioners.Dispatcher recording the messages it is given, instead of
+// sending them. It is safe for concurrent use.
+type RecordingDispatcher struct {
+	// Err, if set, is returned by every dispatch.
+	Err error
+	// ErrFunc, if set, returns the error of each dispatch. It takes precedence over Err.
+	ErrFunc func(d Dispatch) error
+
+	mu         sync.Mutex
+	dispatches []Dispatch
+}
+
+var _ provisioners.Dispatcher = (*RecordingDispatcher)(nil)
+
+// DispatchMessage records a copy of message, including the dispatches that fail.
+func (d *RecordingDispatcher) DispatchMessage(message *provisioners.Message, destination, reply string, defaults provisioners.DispatchDefaults) error {
+	dispatch := Dispatch{
+		Message:     CopyMessage(message),
+		Destination: destination,
+		Reply:       reply,
+		Defaults:    defaults,
+	}
+	d.mu.Lock()
+	d.dispatches = append(d.dispatches, dispatch)
+	d.mu.Unlock()
+
+	if d.ErrFunc != nil {
+		return d.ErrFunc(dispatch)
+	}
+	return d.Err
+}
+
+// Dispatches returns the dispatches recorded so far, in order.
+func (d *RecordingDispatcher) Dispatches() []Dispatch {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Dispatch(nil), d.dispatches...)
+}
+
+// Reset forgets the dispatches recorded so far.
+func (d *RecordingDispatcher) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dispatches = nil
+}
+
+// CopyMessage returns a deep copy of m, which the caller may keep after m is modified.
+func CopyMessage(m *provisioners.Message) *provisioners.Message {
+	if m == nil {
+		return nil
+	}
+	c := &provisioners.Message{
+		Headers: make(map[string]string, len(m.Headers)),
+		Payload: append([]byte(nil), m.Payload...),
+	}
+	for k, v := range m.Headers {
+		c.Headers[k] = v
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+func TestRecordingDispatcher(t *testing.T) {
+	errFirst := errors.New("first")
+	d := &RecordingDispatcher{
+		Err: errors.New("default"),
+		ErrFunc: func(dispatch Dispatch) error {
+			if dispatch.Destination == "first" {
+				return errFirst
+			}
+			return nil
+		},
+	}
+	m := &provisioners.Message{Headers: map[string]string{"ce-id": "1"}, Payload: []byte("payload")}
+	if err := d.DispatchMessage(m, "first", "reply", provisioners.DispatchDefaults{Namespace: "ns"}); err != errFirst {
+		t.Errorf("Unexpected error. Expected %v. Actual %v", errFirst, err)
+	}
+	if err := d.DispatchMessage(m, "second", "", provisioners.DispatchDefaults{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// The recorded messages are copies, unaffected by later changes.
+	m.Headers["ce-id"] = "2"
+	m.Payload[0] = 'P'
+
+	dispatches := d.Dispatches()
+	if len(dispatches) != 2 {
+		t.Fatalf("Unexpected number of dispatches. Expected 2. Actual %d", len(dispatches))
+	}
+	first := dispatches[0]
+	if first.Destination != "first" || first.Reply != "reply" || first.Defaults.Namespace != "ns" {
+		t.Errorf("Unexpected dispatch: %+v", first)
+	}
+	if first.Message.Headers["ce-id"] != "1" || string(first.Message.Payload) != "payload" {
+		t.Errorf("Unexpected recorded message: %+v", first.Message)
+	}
+
+	d.Reset()
+	if n := len(d.Dispatches()); n != 0 {
+		t.Errorf("Unexpected number of dispatches after Reset. Expected 0. Actual %d", n)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recordevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Client queries the recorded events of a Recorder.
+type Client struct {
+	// URL is the base URL of the Recorder, e.g. http://recordevents.default.svc.cluster.local.
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a Client of the Recorder at url.
+func NewClient(url string) *Client {
+	return &Client{
+		URL:        strings.TrimSuffix(url, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Events returns the events recorded by the Recorder that are selected by q, in the order they
+// were received.
+func (c *Client) Events(q Query) ([]Event, error) {
+	var events []Event
+	if err := c.get(EventsPath, q, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Count returns the number of events recorded by the Recorder that are selected by q.
+func (c *Client) Count(q Query) (int, error) {
+	var count struct {
+		Count int `json:"count"`
+	}
+	if err := c.get(CountPath, q, &count); err != nil {
+		return 0, err
+	}
+	return count.Count, nil
+}
+
+// Reset makes the Recorder forget the events recorded so far.
+func (c *Client) Reset() error {
+	req, err := http.NewRequest(http.MethodDelete, c.URL+EventsPath, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status resetting the recorded events: %s", res.Status)
+	}
+	return nil
+}
+
+func (c *Client) get(path string, q Query, v interface{}) error {
+	u := c.URL + path
+	if values := q.values(); len(values) > 0 {
+		u += "?" + values.Encode()
+	}
+	res, err := c.HTTPClient.Get(u)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status querying the recorded events: %s", res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
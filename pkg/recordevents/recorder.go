@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recordevents provides a sink that records the events delivered to it and serves them
+// back through a query API, so that tests, of this project or of the pipelines of its users, can
+// assert which events reached a subscriber. Events are recorded by POSTing them to any path but
+// /events, and queried with:
+//
+//	GET /events?id=<id>&type=<type>&source=<source>        the matching events, in the order received
+//	GET /events/count?id=<id>&type=<type>&source=<source>  {"count": <number of matching events>}
+//	DELETE /events                                         forgets the events recorded so far
+//
+// Every parameter of a query is optional, and Client queries a Recorder from Go.
+package recordevents
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+const (
+	// EventsPath is the path the recorded events are queried at.
+	EventsPath = "/events"
+	// CountPath is the path the number of recorded events is queried at.
+	CountPath = EventsPath + "/count"
+
+	// DefaultMaxEvents is the number of events a Recorder keeps by default.
+	DefaultMaxEvents = 10000
+)
+
+// Event is an event recorded by a Recorder.
+type Event struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	// Attributes holds every context attribute and extension of the event, keyed by their
+	// lower-case name.
+	Attributes map[string]string `json:"attributes"`
+	Data       string            `json:"data,omitempty"`
+	// Headers holds the HTTP headers the event was delivered with.
+	Headers  map[string]string `json:"headers,omitempty"`
+	Received time.Time         `json:"received"`
+}
+
+// Query selects recorded events. Its empty fields match every event.
+type Query struct {
+	ID     string
+	Type   string
+	Source string
+}
+
+// Matches returns whether e is selected by q.
+func (q Query) Matches(e Event) bool {
+	return (q.ID == "" || q.ID == e.ID) &&
+		(q.Type == "" || q.Type == e.Type) &&
+		(q.Source == "" || q.Source == e.Source)
+}
+
+func (q Query) values() url.Values {
+	v := url.Values{}
+	if q.ID != "" {
+		v.Set("id", q.ID)
+	}
+	if q.Type != "" {
+		v.Set("type", q.Type)
+	}
+	if q.Source != "" {
+		v.Set("source", q.Source)
+	}
+	return v
+}
+
+func queryFromValues(v url.Values) Query {
+	return Query{
+		ID:     v.Get("id"),
+		Type:   v.Get("type"),
+		Source: v.Get("source"),
+	}
+}
+
+// Recorder records the events POSTed to it and serves the query API.
+type Recorder struct {
+	maxEvents   int
+	maxBodySize int64
+	logger      *zap.Logger
+
+	mutex  sync.Mutex
+	events []Event
+}
+
+var _ http.Handler = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder keeping the last maxEvents events, or every event if it is 0.
+// Events whose body is larger than maxBodySize bytes are rejected, unless it is 0. Every event
+// recorded is logged to logger.
+func NewRecorder(maxEvents int, maxBodySize int64, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		maxEvents:   maxEvents,
+		maxBodySize: maxBodySize,
+		logger:      logger,
+	}
+}
+
+// Record records e.
+func (r *Recorder) Record(e Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, e)
+	if r.maxEvents > 0 && len(r.events) > r.maxEvents {
+		r.events = append([]Event(nil), r.events[len(r.events)-r.maxEvents:]...)
+	}
+}
+
+// Events returns the recorded events selected by q, in the order they were received.
+func (r *Recorder) Events(q Query) []Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	events := []Event{}
+	for _, e := range r.events {
+		if q.Matches(e) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Reset forgets the events recorded so far.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = nil
+}
+
+// ServeHTTP records the events POSTed to any path but EventsPath, responding with:
+//
+//	202 - the event is recorded
+//	413 - the body of the event is larger than the maximum size
+//
+// and serves the query API at EventsPath.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.URL.Path == EventsPath && req.Method == http.MethodGet:
+		r.writeJSON(w, r.Events(queryFromValues(req.URL.Query())))
+	case req.URL.Path == CountPath && req.Method == http.MethodGet:
+		r.writeJSON(w, map[string]int{"count": len(r.Events(queryFromValues(req.URL.Query())))})
+	case req.URL.Path == EventsPath && req.Method == http.MethodDelete:
+		r.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasPrefix(req.URL.Path, EventsPath):
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	case req.Method == http.MethodPost:
+		r.record(w, req)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Recorder) record(w http.ResponseWriter, req *http.Request) {
+	var body io.Reader = req.Body
+	if r.maxBodySize > 0 {
+		body = io.LimitReader(req.Body, r.maxBodySize+1)
+	}
+	payload, err := ioutil.ReadAll(body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if r.maxBodySize > 0 && int64(len(payload)) > r.maxBodySize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	headers := make(map[string]string, len(req.Header))
+	for k, v := range req.Header {
+		headers[k] = v[0]
+	}
+	m := &provisioners.Message{Headers: headers, Payload: payload}
+	attrs := m.Attributes()
+	e := Event{
+		ID:         attrs["id"],
+		Type:       attrs["type"],
+		Source:     attrs["source"],
+		Attributes: attrs,
+		Data:       string(m.Data()),
+		Headers:    headers,
+		Received:   time.Now(),
+	}
+	r.Record(e)
+	r.logger.Info("Received event id="+e.ID+" type="+e.Type+" source="+e.Source, zap.String("data", e.Data))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *Recorder) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		r.logger.Error("Unable to write the response", zap.Error(err))
+	}
+}
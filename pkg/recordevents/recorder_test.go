@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recordevents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func send(t *testing.T, url string, headers map[string]string, body string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unable to create the request: %v", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unable to send the event: %v", err)
+	}
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func binaryEvent(id, eventType string) map[string]string {
+	return map[string]string{
+		"Content-Type":   "application/json",
+		"Ce-Specversion": "1.0",
+		"Ce-Id":          id,
+		"Ce-Type":        eventType,
+		"Ce-Source":      "/test",
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	s := httptest.NewServer(NewRecorder(0, 0, zap.NewNop()))
+	defer s.Close()
+	c := NewClient(s.URL + "/")
+
+	if status := send(t, s.URL, binaryEvent("1", "com.example.order"), `{"total": 10}`); status != http.StatusAccepted {
+		t.Fatalf("Unexpected status recording a binary event: %d", status)
+	}
+	if status := send(t, s.URL+"/any/path", binaryEvent("2", "com.example.refund"), `{}`); status != http.StatusAccepted {
+		t.Fatalf("Unexpected status recording a binary event: %d", status)
+	}
+	structured := `{"specversion": "1.0", "id": "3", "type": "com.example.order", "source": "/test", "data": {"total": 20}}`
+	if status := send(t, s.URL, map[string]string{"Content-Type": "application/cloudevents+json"}, structured); status != http.StatusAccepted {
+		t.Fatalf("Unexpected status recording a structured event: %d", status)
+	}
+
+	events, err := c.Events(Query{})
+	if err != nil {
+		t.Fatalf("Unexpected error querying the events: %v", err)
+	}
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	if got := strings.Join(ids, ","); got != "1,2,3" {
+		t.Errorf("Unexpected events. Expected 1,2,3, actual %s", got)
+	}
+	if data := events[0].Data; data != `{"total": 10}` {
+		t.Errorf("Unexpected data of the binary event %q", data)
+	}
+	if data := events[2].Data; data != `{"total": 20}` {
+		t.Errorf("Unexpected data of the structured event %q", data)
+	}
+
+	events, err = c.Events(Query{ID: "2"})
+	if err != nil {
+		t.Fatalf("Unexpected error querying the events: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != "com.example.refund" || events[0].Source != "/test" {
+		t.Errorf("Unexpected events with id 2: %+v", events)
+	}
+
+	if count, err := c.Count(Query{Type: "com.example.order"}); err != nil || count != 2 {
+		t.Errorf("Unexpected count of the orders. Expected 2, actual %d, error %v", count, err)
+	}
+	if count, err := c.Count(Query{Type: "com.example.order", Source: "/other"}); err != nil || count != 0 {
+		t.Errorf("Unexpected count of the orders of another source. Expected 0, actual %d, error %v", count, err)
+	}
+
+	if err := c.Reset(); err != nil {
+		t.Fatalf("Unexpected error resetting the events: %v", err)
+	}
+	if count, err := c.Count(Query{}); err != nil || count != 0 {
+		t.Errorf("Unexpected count after a reset. Expected 0, actual %d, error %v", count, err)
+	}
+}
+
+func TestRecorder_MaxEvents(t *testing.T) {
+	r := NewRecorder(2, 0, zap.NewNop())
+	for _, id := range []string{"1", "2", "3"} {
+		r.Record(Event{ID: id})
+	}
+	events := r.Events(Query{})
+	if len(events) != 2 || events[0].ID != "2" || events[1].ID != "3" {
+		t.Errorf("Expected the last 2 events to be kept, got %+v", events)
+	}
+}
+
+func TestRecorder_ServeHTTP(t *testing.T) {
+	testCases := map[string]struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		"too large": {
+			method: http.MethodPost,
+			path:   "/",
+			body:   "0123456789",
+			want:   http.StatusRequestEntityTooLarge,
+		},
+		"get an event": {
+			method: http.MethodGet,
+			path:   "/",
+			want:   http.StatusMethodNotAllowed,
+		},
+		"post a query": {
+			method: http.MethodPost,
+			path:   EventsPath,
+			want:   http.StatusMethodNotAllowed,
+		},
+		"delete the count": {
+			method: http.MethodDelete,
+			path:   CountPath,
+			want:   http.StatusMethodNotAllowed,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			r := NewRecorder(0, 5, zap.NewNop())
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+			if w.Code != tc.want {
+				t.Errorf("Unexpected status code. Expected %v, actual %v", tc.want, w.Code)
+			}
+		})
+	}
+}
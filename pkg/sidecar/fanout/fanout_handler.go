@@ -22,12 +22,18 @@ limitations under the License.
 package fanout
 
 import (
+	"crypto/tls"
 	"errors"
 	"net/http"
 	"time"
 
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/filter"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/auth"
+	"github.com/knative/eventing/pkg/provisioners/faults"
+	"github.com/knative/eventing/pkg/provisioners/schema"
+	"github.com/knative/eventing/pkg/transform"
 	"go.uber.org/zap"
 )
 
@@ -46,9 +52,43 @@ type Config struct {
 type Handler struct {
 	config Config
 
-	receivedMessages chan *forwardMessage
-	receiver         *provisioners.MessageReceiver
-	dispatcher       *provisioners.MessageDispatcher
+	// filters holds the compiled filter of each entry in config.Subscriptions, at the same index.
+	filters []filter.Expression
+	// transforms holds the compiled transform of each entry in config.Subscriptions, at the same
+	// index. Entries are nil when events are delivered unchanged.
+	transforms []*transform.Template
+
+	// authResolver creates the authenticators for subscriptions that declare auth. If it is nil,
+	// the deliveries to these subscriptions fail.
+	authResolver *auth.Resolver
+	// authenticators holds the authenticator of each entry in config.Subscriptions, at the same
+	// index. Entries are nil when no credentials are needed.
+	authenticators []provisioners.Authenticator
+
+	// schemaResolver creates the validators for subscriptions that declare a schema. If it is nil,
+	// events are never validated.
+	schemaResolver *schema.Resolver
+	// validators holds the validator of each entry in config.Subscriptions, at the same index.
+	// Entries are nil when events are not validated.
+	validators []provisioners.MessageValidator
+
+	// backlog counts the deliveries in progress. It is nil when they are not counted.
+	backlog *provisioners.BacklogCounter
+
+	// faults injects faults into the deliveries to the subscribers. It is nil outside of tests.
+	faults *faults.Injector
+
+	// concurrency and queueSize size the pool. The pool is nil, and the deliveries unbounded, when
+	// concurrency is zero.
+	concurrency int
+	queueSize   int
+	pool        *workerPool
+
+	receivedMessages  chan *forwardMessage
+	receiver          *provisioners.MessageReceiver
+	receiverOptions   []provisioners.ReceiverOption
+	dispatcher        *provisioners.MessageDispatcher
+	dispatcherOptions []provisioners.DispatcherOption
 
 	// TODO: Plumb context through the receiver and dispatcher and use that to store the timeout,
 	// rather than a member variable.
@@ -65,25 +105,200 @@ type forwardMessage struct {
 	done chan<- error
 }
 
+// Option configures optional behavior of a Handler.
+type Option func(*Handler)
+
+// WithAuthResolver makes the Handler attach credentials to deliveries to subscribers that declare
+// auth, using r to obtain them.
+func WithAuthResolver(r *auth.Resolver) Option {
+	return func(h *Handler) {
+		h.authResolver = r
+	}
+}
+
+// WithSchemaResolver makes the Handler validate the data of the events delivered to subscribers
+// that declare a schema, using r to read the schemas.
+func WithSchemaResolver(r *schema.Resolver) Option {
+	return func(h *Handler) {
+		h.schemaResolver = r
+	}
+}
+
+// WithBacklogCounter makes the Handler count the deliveries in progress of every channel in
+// backlog.
+func WithBacklogCounter(backlog *provisioners.BacklogCounter) Option {
+	return func(h *Handler) {
+		h.backlog = backlog
+	}
+}
+
+// WithWorkerPool makes the Handler deliver at most concurrency events to the subscribers at once.
+// Up to queueSize further deliveries wait for one of them to finish, and the events that would
+// exceed it are rejected.
+func WithWorkerPool(concurrency, queueSize int) Option {
+	return func(h *Handler) {
+		h.concurrency = concurrency
+		h.queueSize = queueSize
+	}
+}
+
+// WithMaxBodySize makes the Handler reject the events whose body is larger than max bytes, and
+// fail the deliveries whose reply is. Bodies are unbounded when max is 0.
+func WithMaxBodySize(max int64) Option {
+	return func(h *Handler) {
+		h.receiverOptions = append(h.receiverOptions, provisioners.WithMaxBodySize(max))
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithMaxReplySize(max))
+	}
+}
+
+// WithReplyExtensions makes the Handler copy the extensions names of the events it delivers to the
+// replies of the subscribers that do not set them.
+func WithReplyExtensions(names []string) Option {
+	return func(h *Handler) {
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithReplyExtensions(names...))
+	}
+}
+
+// WithStrictCloudEvents makes the Handler reject the events that are not valid CloudEvents, rather
+// than fanning them out.
+func WithStrictCloudEvents() Option {
+	return func(h *Handler) {
+		h.receiverOptions = append(h.receiverOptions, provisioners.WithStrictCloudEvents())
+	}
+}
+
+// WithAuditSink makes the Handler record every attempt to deliver an event to a subscriber in
+// sink.
+func WithAuditSink(sink provisioners.AuditSink) Option {
+	return func(h *Handler) {
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithAuditSink(sink))
+	}
+}
+
+// WithTLSConfig makes the Handler use config for the TLS connections to the subscribers that do not
+// declare a client certificate of their own.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(h *Handler) {
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithTLSConfig(config))
+	}
+}
+
+// WithSigning makes the Handler sign the events it receives that are not signed yet with s.
+func WithSigning(s provisioners.Signer) Option {
+	return func(h *Handler) {
+		h.receiverOptions = append(h.receiverOptions, provisioners.WithSigning(s))
+	}
+}
+
+// WithSignatureVerification makes the Handler verify the signature of the events with v when it
+// receives them, if they are already signed, and before sending them to the subscribers.
+func WithSignatureVerification(v provisioners.Verifier) Option {
+	return func(h *Handler) {
+		h.receiverOptions = append(h.receiverOptions, provisioners.WithSignedMessageVerification(v))
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithSignatureVerification(v))
+	}
+}
+
+// WithTap makes the Handler publish a copy of every event it receives to tap.
+func WithTap(tap provisioners.Tap) Option {
+	return func(h *Handler) {
+		h.receiverOptions = append(h.receiverOptions, provisioners.WithTap(tap))
+	}
+}
+
+// WithPusher makes the Handler push the events of the subscriptions with the SSE protocol to their
+// consumers connected to p.
+func WithPusher(p provisioners.Pusher) Option {
+	return func(h *Handler) {
+		h.dispatcherOptions = append(h.dispatcherOptions, provisioners.WithPusher(p))
+	}
+}
+
+// WithFaultInjection makes the Handler inject the faults of i into the deliveries to the
+// subscribers. It is only meant for test environments.
+func WithFaultInjection(i *faults.Injector) Option {
+	return func(h *Handler) {
+		h.faults = i
+	}
+}
+
 // NewHandler creates a new fanout.Handler.
-func NewHandler(logger *zap.Logger, config Config) *Handler {
+func NewHandler(logger *zap.Logger, config Config, opts ...Option) *Handler {
 	handler := &Handler{
 		logger:           logger,
 		config:           config,
-		dispatcher:       provisioners.NewMessageDispatcher(logger.Sugar()),
 		receivedMessages: make(chan *forwardMessage, messageBufferSize),
 		timeout:          defaultTimeout,
 	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	if handler.concurrency > 0 {
+		handler.pool = newWorkerPool(handler.concurrency, handler.queueSize)
+	}
+	handler.dispatcher = provisioners.NewMessageDispatcher(logger.Sugar(), handler.dispatcherOptions...)
+	handler.filters = compileFilters(logger, config.Subscriptions)
+	handler.transforms = compileTransforms(logger, config.Subscriptions)
+	handler.authenticators = handler.createAuthenticators()
+	handler.validators = createValidators(logger, handler.schemaResolver, config.Subscriptions)
 	// The receiver function needs to point back at the handler itself, so set it up after
-	// initialization.
-	handler.receiver = provisioners.NewMessageReceiver(createReceiverFunction(handler), logger.Sugar())
+	// initialization. The bodies of the events are streamed to a single subscriber.
+	receiverOptions := append(handler.receiverOptions, provisioners.WithBodyStreaming())
+	handler.receiver = provisioners.NewMessageReceiver(createReceiverFunction(handler), logger.Sugar(), receiverOptions...)
 
 	return handler
 }
 
+// compileFilters parses the filter of every subscription, see provisioners.SubscriberFilter.
+func compileFilters(logger *zap.Logger, subs []eventingduck.ChannelSubscriberSpec) []filter.Expression {
+	filters := make([]filter.Expression, len(subs))
+	for i, sub := range subs {
+		filters[i] = provisioners.SubscriberFilter(logger, sub)
+	}
+	return filters
+}
+
+// compileTransforms parses the transform of every subscription, see
+// provisioners.SubscriberTransform.
+func compileTransforms(logger *zap.Logger, subs []eventingduck.ChannelSubscriberSpec) []*transform.Template {
+	transforms := make([]*transform.Template, len(subs))
+	for i, sub := range subs {
+		transforms[i] = provisioners.SubscriberTransform(logger, sub)
+	}
+	return transforms
+}
+
+// createAuthenticators creates the authenticator of every subscription that declares auth.
+func (f *Handler) createAuthenticators() []provisioners.Authenticator {
+	authenticators := make([]provisioners.Authenticator, len(f.config.Subscriptions))
+	for i, sub := range f.config.Subscriptions {
+		if sub.Auth == nil {
+			continue
+		}
+		namespace := ""
+		if sub.Ref != nil {
+			namespace = sub.Ref.Namespace
+		}
+		// The deliveries fail if the credentials cannot be resolved, rather than being sent
+		// without them.
+		authenticators[i] = f.authResolver.Authenticator(namespace, sub.Auth)
+	}
+	return authenticators
+}
+
+// createValidators creates the validator of every subscription, see
+// schema.Resolver.SubscriberValidator.
+func createValidators(logger *zap.Logger, r *schema.Resolver, subs []eventingduck.ChannelSubscriberSpec) []provisioners.MessageValidator {
+	validators := make([]provisioners.MessageValidator, len(subs))
+	for i, sub := range subs {
+		validators[i] = r.SubscriberValidator(logger, sub)
+	}
+	return validators
+}
+
 func createReceiverFunction(f *Handler) func(provisioners.ChannelReference, *provisioners.Message) error {
-	return func(_ provisioners.ChannelReference, m *provisioners.Message) error {
-		return f.dispatch(m)
+	return func(channel provisioners.ChannelReference, m *provisioners.Message) error {
+		return f.dispatch(channel, m)
 	}
 }
 
@@ -91,14 +306,43 @@ func (f *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	f.receiver.HandleRequest(w, r)
 }
 
-// dispatch takes the request, fans it out to each subscription in f.config. If all the fanned out
-// requests return successfully, then return nil. Else, return an error.
-func (f *Handler) dispatch(msg *provisioners.Message) error {
+// dispatch takes the request sent to channel, fans it out to each subscription in f.config. If all
+// the fanned out requests return successfully, then return nil. Else, return an error.
+func (f *Handler) dispatch(channel provisioners.ChannelReference, msg *provisioners.Message) error {
 	errorCh := make(chan error, len(f.config.Subscriptions))
-	for _, sub := range f.config.Subscriptions {
-		go func(s eventingduck.ChannelSubscriberSpec) {
-			errorCh <- f.makeFanoutRequest(*msg, s)
-		}(sub)
+	attrs := msg.Attributes()
+	if msg.IsStreamed() && (f.faults != nil || f.deliveries(attrs) > 1) {
+		// A streamed body can only be sent once.
+		if err := msg.ReadBody(); err != nil {
+			return err
+		}
+	}
+	// The deliveries wait for a worker for at most the timeout of the whole fanout.
+	queueTimeout := time.After(f.timeout)
+	for i, sub := range f.config.Subscriptions {
+		if sub.Paused {
+			// The in-memory channel has nowhere to keep events for a paused subscription, so the
+			// event is dropped for it.
+			errorCh <- nil
+			continue
+		}
+		if !f.filters[i].Matches(attrs) {
+			// The subscription isn't interested in this event, so delivering it is trivially
+			// successful.
+			errorCh <- nil
+			continue
+		}
+		f.backlog.Add(channel, 1)
+		s, v, t, a := sub, f.validators[i], f.transforms[i], f.authenticators[i]
+		err := f.pool.submit(channel, queueTimeout, func() {
+			defer f.backlog.Add(channel, -1)
+			errorCh <- f.makeFanoutRequest(channel, *msg, s, v, t, a)
+		})
+		if err != nil {
+			f.backlog.Add(channel, -1)
+			f.logger.Error("Fanout had an error", zap.Error(err))
+			return err
+		}
 	}
 
 	for range f.config.Subscriptions {
@@ -117,8 +361,41 @@ func (f *Handler) dispatch(msg *provisioners.Message) error {
 	return nil
 }
 
+// deliveries returns the number of subscriptions an event with the context attributes attrs is
+// delivered to.
+func (f *Handler) deliveries(attrs map[string]string) int {
+	n := 0
+	for i, sub := range f.config.Subscriptions {
+		if !sub.Paused && f.filters[i].Matches(attrs) {
+			n++
+		}
+	}
+	return n
+}
+
 // makeFanoutRequest sends the request to exactly one subscription. It handles both the `call` and
 // the `sink` portions of the subscription.
-func (f *Handler) makeFanoutRequest(m provisioners.Message, sub eventingduck.ChannelSubscriberSpec) error {
-	return f.dispatcher.DispatchMessage(&m, sub.SubscriberURI, sub.ReplyURI, provisioners.DispatchDefaults{})
+func (f *Handler) makeFanoutRequest(channel provisioners.ChannelReference, m provisioners.Message, sub eventingduck.ChannelSubscriberSpec, v provisioners.MessageValidator, t *transform.Template, a provisioners.Authenticator) error {
+	defaults := provisioners.DispatchDefaults{
+		Validator:               v,
+		Transform:               t,
+		NonConformingDeadLetter: sub.DeadLetterURI,
+		Auth:                    a,
+		OnError:                 sub.ErrorURI,
+		Channel:                 channel.String(),
+		Subscription:            subscriptionName(sub),
+		Protocol:                sub.Protocol,
+	}
+	return f.faults.Deliver(&m, func() error {
+		return f.dispatcher.DispatchMessage(&m, sub.SubscriberURI, sub.ReplyURI, defaults)
+	})
+}
+
+// subscriptionName returns the namespace/name of the Subscription sub was created for, or the
+// empty string if it is unknown.
+func subscriptionName(sub eventingduck.ChannelSubscriberSpec) string {
+	if sub.Ref == nil {
+		return ""
+	}
+	return sub.Ref.Namespace + "/" + sub.Ref.Name
 }
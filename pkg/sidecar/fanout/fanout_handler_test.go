@@ -18,18 +18,24 @@ package fanout
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
 	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/knative/eventing/pkg/provisioners/faults"
+	"github.com/knative/eventing/pkg/provisioners/schema"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // Domains used in subscriptions, which will be replaced by the real domains of the started HTTP
@@ -60,6 +66,8 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 	testCases := map[string]struct {
 		receiverFunc   func(provisioners.ChannelReference, *provisioners.Message) error
 		timeout        time.Duration
+		concurrency    int
+		queueSize      int
 		subs           []eventingduck.ChannelSubscriberSpec
 		subscriber     func(http.ResponseWriter, *http.Request)
 		channel        func(http.ResponseWriter, *http.Request)
@@ -84,6 +92,51 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		"deliveries wait for a worker": {
+			concurrency: 1,
+			queueSize:   1,
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+				},
+				{
+					SubscriberURI: replaceSubscriber,
+				},
+			},
+			subscriber:     callableSucceed,
+			expectedStatus: http.StatusAccepted,
+		},
+		"worker queue full": {
+			concurrency: 1,
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+				},
+				{
+					SubscriberURI: replaceSubscriber,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				time.Sleep(10 * time.Millisecond)
+				writer.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"credentials cannot be resolved": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					Auth: &eventingduck.SubscriberAuth{
+						BearerToken: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "subscriber-token"},
+							Key:                  "token",
+						},
+					},
+				},
+			},
+			subscriber:     callableSucceed,
+			expectedStatus: http.StatusInternalServerError,
+		},
 		"zero subs succeed": {
 			subs:           []eventingduck.ChannelSubscriberSpec{},
 			expectedStatus: http.StatusAccepted,
@@ -116,6 +169,172 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		"subscriber fails, routed to onError": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					ErrorURI:      replaceChannel,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			channel: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"subscriber nacks, not routed to onError": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					ErrorURI:      replaceChannel,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.Header().Set(provisioners.DeliveryResultHeader, provisioners.DeliveryResultNack)
+				writer.WriteHeader(http.StatusAccepted)
+			},
+			channel: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"subscriber drops, not replied": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					ReplyURI:      replaceChannel,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.Header().Set(provisioners.DeliveryResultHeader, provisioners.DeliveryResultDrop)
+				writer.WriteHeader(http.StatusBadRequest)
+			},
+			channel: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"subscriber filtered out": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					Filter:        "type = 'com.example.otherevent'",
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"subscriber with matching filter fails": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					Filter:        "NOT EXISTS type",
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"subscriber with invalid filter is skipped": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					Filter:        "type = ",
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"subscriber with failing transform fails": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					Transform:     "{{.type}}",
+				},
+			},
+			subscriber:     callableSucceed,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"nonconforming event is dropped": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					Ref:           subscriptionRef,
+					SubscriberURI: replaceSubscriber,
+					Schema:        orderSchema,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"nonconforming event is dead lettered": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					Ref:           subscriptionRef,
+					SubscriberURI: replaceSubscriber,
+					Schema:        orderSchema,
+					DeadLetterURI: replaceChannel,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			channel: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusAccepted)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		"dead letter sink fails": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					Ref:           subscriptionRef,
+					SubscriberURI: replaceSubscriber,
+					Schema:        orderSchema,
+					DeadLetterURI: replaceChannel,
+				},
+			},
+			subscriber: callableSucceed,
+			channel: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"unreadable schema fails": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					Ref:           subscriptionRef,
+					SubscriberURI: replaceSubscriber,
+					Schema: &eventingduck.SubscriberSchema{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+							Key:                  "order.json",
+						},
+					},
+				},
+			},
+			subscriber:     callableSucceed,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"paused subscriber is skipped": {
+			subs: []eventingduck.ChannelSubscriberSpec{
+				{
+					SubscriberURI: replaceSubscriber,
+					Paused:        true,
+				},
+			},
+			subscriber: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
 		"subscriber succeeds, result fails": {
 			subs: []eventingduck.ChannelSubscriberSpec{
 				{
@@ -202,10 +421,20 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 				if sub.ReplyURI == replaceChannel {
 					sub.ReplyURI = channelServer.URL[7:] // strip the leading 'http://'
 				}
+				if sub.DeadLetterURI == replaceChannel {
+					sub.DeadLetterURI = channelServer.URL[7:] // strip the leading 'http://'
+				}
+				if sub.ErrorURI == replaceChannel {
+					sub.ErrorURI = channelServer.URL[7:] // strip the leading 'http://'
+				}
 				subs = append(subs, sub)
 			}
 
-			h := NewHandler(zap.NewNop(), Config{Subscriptions: subs})
+			opts := []Option{WithSchemaResolver(schema.NewResolver(getSchemaConfigMap))}
+			if tc.concurrency > 0 {
+				opts = append(opts, WithWorkerPool(tc.concurrency, tc.queueSize))
+			}
+			h := NewHandler(zap.NewNop(), Config{Subscriptions: subs}, opts...)
 			if tc.receiverFunc != nil {
 				h.receiver = provisioners.NewMessageReceiver(tc.receiverFunc, zap.NewNop().Sugar())
 			}
@@ -225,6 +454,108 @@ func TestFanoutHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestFanoutHandler_FaultInjection(t *testing.T) {
+	testCases := map[string]struct {
+		config       faults.Config
+		wantRequests int32
+	}{
+		"dropped": {
+			config:       faults.Config{DropRate: 1},
+			wantRequests: 0,
+		},
+		"duplicated": {
+			config:       faults.Config{DuplicateRate: 1},
+			wantRequests: 2,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var requests atomic.Int32
+			subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Inc()
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer subscriber.Close()
+
+			subs := []eventingduck.ChannelSubscriberSpec{{SubscriberURI: subscriber.URL[7:]}}
+			injector := faults.NewInjector(tc.config, zap.NewNop(), 1)
+			h := NewHandler(zap.NewNop(), Config{Subscriptions: subs}, WithFaultInjection(injector))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", "http://channelname.channelnamespace/", body(cloudEvent)))
+			if w.Code != http.StatusAccepted {
+				t.Errorf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, w.Code)
+			}
+			if got := requests.Load(); got != tc.wantRequests {
+				t.Errorf("Unexpected requests to the subscriber. Expected %d, actual %d", tc.wantRequests, got)
+			}
+		})
+	}
+}
+
+func TestFanoutHandler_StreamedBody(t *testing.T) {
+	for _, subscribers := range []int{1, 2} {
+		t.Run(fmt.Sprintf("%d subscribers", subscribers), func(t *testing.T) {
+			var lock sync.Mutex
+			var bodies []string
+			subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				lock.Lock()
+				bodies = append(bodies, string(b))
+				lock.Unlock()
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer subscriber.Close()
+
+			var subs []eventingduck.ChannelSubscriberSpec
+			for i := 0; i < subscribers; i++ {
+				subs = append(subs, eventingduck.ChannelSubscriberSpec{SubscriberURI: subscriber.URL[7:]})
+			}
+			h := NewHandler(zap.NewNop(), Config{Subscriptions: subs})
+
+			req := httptest.NewRequest("POST", "http://channelname.channelnamespace/", strings.NewReader(`<much wow="xml"/>`))
+			req.Header.Set("Content-Type", "text/xml")
+			req.Header.Set("Ce-Specversion", "1.0")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != http.StatusAccepted {
+				t.Errorf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, w.Code)
+			}
+			if len(bodies) != subscribers {
+				t.Fatalf("Unexpected deliveries. Expected %d, actual %d", subscribers, len(bodies))
+			}
+			for _, b := range bodies {
+				if b != `<much wow="xml"/>` {
+					t.Errorf("Unexpected body %q", b)
+				}
+			}
+		})
+	}
+}
+
+var (
+	subscriptionRef = &corev1.ObjectReference{Namespace: "test-namespace", Name: "test-subscription"}
+
+	orderSchema = &eventingduck.SubscriberSchema{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+			Key:                  "order.json",
+		},
+	}
+)
+
+// getSchemaConfigMap serves a ConfigMap with a schema that cloudEvent does not conform to.
+func getSchemaConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	if name != orderSchema.ConfigMapKeyRef.Name {
+		return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+	}
+	return &corev1.ConfigMap{
+		Data: map[string]string{
+			"order.json": `{"type": "object", "required": ["id"]}`,
+		},
+	}, nil
+}
+
 type fakeHandler struct {
 	handler func(http.ResponseWriter, *http.Request)
 }
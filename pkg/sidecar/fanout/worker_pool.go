@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fanout
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// errQueueFull is returned when the queue of a workerPool is full.
+	errQueueFull = errors.New("fanout queue is full")
+	// errQueueTimeout is returned when a delivery waited for a worker longer than the fanout
+	// timeout.
+	errQueueTimeout = errors.New("fanout timed out waiting for a worker")
+
+	queueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "fanout_queue_length",
+		Help:      "The number of deliveries of events to subscribers waiting for a worker, by namespace and channel.",
+	}, []string{"namespace", "channel"})
+
+	queueRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "channel",
+		Subsystem: "dispatcher",
+		Name:      "fanout_queue_full_total",
+		Help:      "The number of events rejected because the fanout queue of their channel was full, by namespace and channel.",
+	}, []string{"namespace", "channel"})
+)
+
+func init() {
+	prometheus.MustRegister(queueLength, queueRejections)
+}
+
+// workerPool bounds the deliveries of the events of a channel in progress at once. The deliveries
+// beyond its concurrency wait in a queue for a worker to be free, and are rejected when the queue
+// is full, so that a spike of traffic is pushed back to the senders rather than held in memory.
+type workerPool struct {
+	// workers holds a token for every delivery in progress.
+	workers   chan struct{}
+	queueSize int64
+	// queued is the number of deliveries waiting for a worker.
+	queued int64
+}
+
+// newWorkerPool creates a workerPool running concurrency deliveries at once, with up to queueSize
+// deliveries waiting.
+func newWorkerPool(concurrency, queueSize int) *workerPool {
+	return &workerPool{
+		workers:   make(chan struct{}, concurrency),
+		queueSize: int64(queueSize),
+	}
+}
+
+// submit runs deliver on a worker of p, waiting for one to be free until timeout fires. It returns
+// an error, without running deliver, if the queue is full or the timeout fired. p is unbounded if it
+// is nil.
+func (p *workerPool) submit(channel provisioners.ChannelReference, timeout <-chan time.Time, deliver func()) error {
+	if p == nil {
+		go deliver()
+		return nil
+	}
+	select {
+	case p.workers <- struct{}{}:
+	default:
+		if err := p.wait(channel, timeout); err != nil {
+			return err
+		}
+	}
+	go func() {
+		defer func() { <-p.workers }()
+		deliver()
+	}()
+	return nil
+}
+
+// wait queues for a worker of p until timeout fires.
+func (p *workerPool) wait(channel provisioners.ChannelReference, timeout <-chan time.Time) error {
+	namespace, name := provisioners.ChannelMetricLabels(channel)
+	if atomic.AddInt64(&p.queued, 1) > p.queueSize {
+		atomic.AddInt64(&p.queued, -1)
+		queueRejections.WithLabelValues(namespace, name).Inc()
+		return errQueueFull
+	}
+	defer atomic.AddInt64(&p.queued, -1)
+	gauge := queueLength.WithLabelValues(namespace, name)
+	gauge.Inc()
+	defer gauge.Dec()
+
+	select {
+	case p.workers <- struct{}{}:
+		return nil
+	case <-timeout:
+		return errQueueTimeout
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fanout
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var testChannel = provisioners.ChannelReference{Namespace: "test-namespace", Name: "test-channel"}
+
+func TestWorkerPool_BoundsConcurrency(t *testing.T) {
+	p := newWorkerPool(2, 10)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	var wg sync.WaitGroup
+	deliver := func() {
+		defer wg.Done()
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}
+
+	// The first two deliveries run at once, the third waits for one of them to finish.
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		if err := p.submit(testChannel, nil, deliver); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	submitted := make(chan error)
+	wg.Add(1)
+	go func() {
+		submitted <- p.submit(testChannel, nil, deliver)
+	}()
+	select {
+	case <-submitted:
+		t.Fatal("Unexpected delivery submitted while the workers were busy")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release <- struct{}{}
+	if err := <-submitted; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	close(release)
+	wg.Wait()
+	if maxRunning != 2 {
+		t.Errorf("Unexpected concurrent deliveries. Expected 2. Actual %d", maxRunning)
+	}
+}
+
+func TestWorkerPool_QueueFull(t *testing.T) {
+	p := newWorkerPool(1, 0)
+	release := make(chan struct{})
+	defer close(release)
+	if err := p.submit(testChannel, nil, func() { <-release }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := p.submit(testChannel, nil, func() { t.Error("Unexpected delivery") }); err != errQueueFull {
+		t.Errorf("Unexpected error. Expected %v. Actual %v", errQueueFull, err)
+	}
+}
+
+func TestWorkerPool_Timeout(t *testing.T) {
+	p := newWorkerPool(1, 1)
+	release := make(chan struct{})
+	defer close(release)
+	if err := p.submit(testChannel, nil, func() { <-release }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := p.submit(testChannel, time.After(10*time.Millisecond), func() { t.Error("Unexpected delivery") }); err != errQueueTimeout {
+		t.Errorf("Unexpected error. Expected %v. Actual %v", errQueueTimeout, err)
+	}
+	if p.queued != 0 {
+		t.Errorf("Unexpected queued deliveries: %d", p.queued)
+	}
+}
+
+func TestWorkerPool_Nil(t *testing.T) {
+	var p *workerPool
+	done := make(chan struct{})
+	if err := p.submit(testChannel, nil, func() { close(done) }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-done
+}
@@ -39,12 +39,18 @@ import (
 type Config struct {
 	// The configuration of each channel in this handler.
 	ChannelConfigs []ChannelConfig `json:"channelConfigs"`
+	// Shards is the number of dispatcher replicas the channels are spread across, each channel
+	// being dispatched by a single replica. The channels are not sharded if it is 0 or 1.
+	Shards int `json:"shards,omitempty"`
 }
 
 type ChannelConfig struct {
 	Namespace    string        `json:"namespace"`
 	Name         string        `json:"name"`
 	FanoutConfig fanout.Config `json:"fanoutConfig"`
+	// PartitionKey is the CloudEvents attribute whose value keys the events of the channel, so
+	// that channels backed by partitioned systems keep related events together and in order.
+	PartitionKey string `json:"partitionKey,omitempty"`
 }
 
 // MakeChannelKey creates the key used for this Channel in the Handler's handlers map.
@@ -74,15 +80,16 @@ type Handler struct {
 	logger   *zap.Logger
 	handlers map[string]*fanout.Handler
 	config   Config
+	opts     []fanout.Option
 }
 
-// NewHandler creates a new Handler.
-func NewHandler(logger *zap.Logger, conf Config) (*Handler, error) {
+// NewHandler creates a new Handler. opts are applied to every fanout.Handler it creates.
+func NewHandler(logger *zap.Logger, conf Config, opts ...fanout.Option) (*Handler, error) {
 	handlers := make(map[string]*fanout.Handler, len(conf.ChannelConfigs))
 
 	for _, cc := range conf.ChannelConfigs {
 		key := makeChannelKeyFromConfig(cc)
-		handler := fanout.NewHandler(logger, cc.FanoutConfig)
+		handler := fanout.NewHandler(logger, cc.FanoutConfig, opts...)
 		if _, present := handlers[key]; present {
 			logger.Error("Duplicate channel key", zap.String("channelKey", key))
 			return nil, fmt.Errorf("duplicate channel key: %v", key)
@@ -94,6 +101,7 @@ func NewHandler(logger *zap.Logger, conf Config) (*Handler, error) {
 		logger:   logger,
 		config:   conf,
 		handlers: handlers,
+		opts:     opts,
 	}, nil
 }
 
@@ -107,7 +115,7 @@ func (h *Handler) ConfigDiff(updated Config) string {
 // CopyWithNewConfig creates a new copy of this Handler with all the fields identical, except the
 // new Handler uses conf, rather than copying the existing Handler's config.
 func (h *Handler) CopyWithNewConfig(conf Config) (*Handler, error) {
-	return NewHandler(h.logger, conf)
+	return NewHandler(h.logger, conf, h.opts...)
 }
 
 // ServeHTTP delegates the actual handling of the request to a fanout.Handler, based on the
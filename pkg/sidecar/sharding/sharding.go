@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding spreads the in-memory Channels across the replicas of their dispatcher. Each
+// channel is assigned to a shard by jump consistent hashing of its namespace and name, so that
+// changing the number of shards only moves the channels of the shards added or removed.
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+	"github.com/knative/eventing/pkg/sidecar/swappable"
+)
+
+const (
+	// ShardsEnv is the environment variable of the in-memory channel controller setting the
+	// number of shards, which must be the number of replicas of the dispatcher.
+	ShardsEnv = "DISPATCHER_SHARDS"
+)
+
+// ShardsFromEnv returns the number of shards set by ShardsEnv, 1 if it is not set.
+func ShardsFromEnv() (int, error) {
+	v := os.Getenv(ShardsEnv)
+	if v == "" {
+		return 1, nil
+	}
+	shards, err := strconv.Atoi(v)
+	if err != nil || shards < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", ShardsEnv, v)
+	}
+	return shards, nil
+}
+
+// Shard returns the shard, between 0 and shards-1, of the channel namespace/name.
+func Shard(namespace, name string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(namespace + "/" + name))
+	return jump(h.Sum64(), shards)
+}
+
+// jump is the jump consistent hash of Lamping and Veach, mapping key to one of buckets.
+func jump(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// Ordinal returns the ordinal of the StatefulSet pod hostname, e.g. 2 for the
+// in-memory-channel-dispatcher-2 pod of the in-memory-channel-dispatcher StatefulSet. It returns
+// false if hostname is not a pod of statefulSet.
+func Ordinal(hostname, statefulSet string) (int, bool) {
+	suffix := strings.TrimPrefix(hostname, statefulSet+"-")
+	if suffix == hostname {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil || ordinal < 0 || strconv.Itoa(ordinal) != suffix {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// Filter returns an UpdateConfig passing update the configuration of the channels of shard only,
+// when the configuration is sharded. Replicas beyond the number of shards are given no channel.
+func Filter(shard int, update swappable.UpdateConfig) swappable.UpdateConfig {
+	return func(config *multichannelfanout.Config) error {
+		if config == nil || config.Shards <= 1 {
+			return update(config)
+		}
+		filtered := &multichannelfanout.Config{
+			ChannelConfigs: make([]multichannelfanout.ChannelConfig, 0),
+			Shards:         config.Shards,
+		}
+		for _, cc := range config.ChannelConfigs {
+			if Shard(cc.Namespace, cc.Name, config.Shards) == shard {
+				filtered.ChannelConfigs = append(filtered.ChannelConfigs, cc)
+			}
+		}
+		return update(filtered)
+	}
+}
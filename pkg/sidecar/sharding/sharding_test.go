@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
+)
+
+func TestShardsFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		"unset":    {want: 1},
+		"set":      {value: "3", want: 3},
+		"zero":     {value: "0", wantErr: true},
+		"negative": {value: "-2", wantErr: true},
+		"invalid":  {value: "three", wantErr: true},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(ShardsEnv, tc.value)
+			defer os.Unsetenv(ShardsEnv)
+			got, err := ShardsFromEnv()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("Unexpected shards. Expected %d. Actual %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestShard(t *testing.T) {
+	const channels = 1000
+	for _, shards := range []int{0, 1, 2, 5, 10} {
+		counts := make(map[int]int)
+		for i := 0; i < channels; i++ {
+			shard := Shard("ns", fmt.Sprintf("c%d", i), shards)
+			if shard != Shard("ns", fmt.Sprintf("c%d", i), shards) {
+				t.Fatalf("Shard of c%d is not stable", i)
+			}
+			if shard < 0 || (shards > 1 && shard >= shards) || (shards <= 1 && shard != 0) {
+				t.Fatalf("Shard of c%d out of range for %d shards: %d", i, shards, shard)
+			}
+			counts[shard]++
+		}
+		// Every shard gets a fair share of the channels.
+		for shard, count := range counts {
+			if shards > 1 && count < channels/shards/2 {
+				t.Errorf("Shard %d of %d has %d channels", shard, shards, count)
+			}
+		}
+	}
+}
+
+func TestShardMovesFewChannels(t *testing.T) {
+	const channels = 1000
+	moved := 0
+	for i := 0; i < channels; i++ {
+		before := Shard("ns", fmt.Sprintf("c%d", i), 4)
+		after := Shard("ns", fmt.Sprintf("c%d", i), 5)
+		if before != after {
+			if after != 4 {
+				t.Errorf("c%d moved from shard %d to the existing shard %d", i, before, after)
+			}
+			moved++
+		}
+	}
+	// About a fifth of the channels move to the new shard.
+	if moved < channels/10 || moved > channels*3/10 {
+		t.Errorf("Unexpected number of channels moved: %d", moved)
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	testCases := map[string]struct {
+		hostname string
+		want     int
+		wantOK   bool
+	}{
+		"first":              {hostname: "dispatcher-0", want: 0, wantOK: true},
+		"later":              {hostname: "dispatcher-12", want: 12, wantOK: true},
+		"other statefulset":  {hostname: "other-1"},
+		"deployment pod":     {hostname: "dispatcher-7d9f8b-x2k4p"},
+		"statefulset itself": {hostname: "dispatcher"},
+		"leading zero":       {hostname: "dispatcher-01"},
+		"sign":               {hostname: "dispatcher-+1"},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, ok := Ordinal(tc.hostname, "dispatcher")
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("Unexpected ordinal. Expected %d, %v. Actual %d, %v", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	config := multichannelfanout.Config{}
+	for i := 0; i < 20; i++ {
+		config.ChannelConfigs = append(config.ChannelConfigs, multichannelfanout.ChannelConfig{
+			Namespace: "ns",
+			Name:      fmt.Sprintf("c%d", i),
+		})
+	}
+
+	var got *multichannelfanout.Config
+	update := func(c *multichannelfanout.Config) error {
+		got = c
+		return nil
+	}
+
+	// The configuration is passed as is when it is not sharded.
+	if err := Filter(1, update)(&config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&config, got); diff != "" {
+		t.Errorf("Unexpected config (-want +got): %s", diff)
+	}
+
+	config.Shards = 3
+	seen := make(map[string]bool)
+	for shard := 0; shard < config.Shards; shard++ {
+		if err := Filter(shard, update)(&config); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.Shards != config.Shards {
+			t.Errorf("Unexpected shards. Expected %d. Actual %d", config.Shards, got.Shards)
+		}
+		for _, cc := range got.ChannelConfigs {
+			if Shard(cc.Namespace, cc.Name, config.Shards) != shard {
+				t.Errorf("Channel %s given to shard %d", cc.Name, shard)
+			}
+			if seen[cc.Name] {
+				t.Errorf("Channel %s given to several shards", cc.Name)
+			}
+			seen[cc.Name] = true
+		}
+	}
+	if len(seen) != len(config.ChannelConfigs) {
+		t.Errorf("Unexpected number of channels given to the shards. Expected %d. Actual %d", len(config.ChannelConfigs), len(seen))
+	}
+
+	// Replicas beyond the number of shards are given no channel.
+	if err := Filter(3, update)(&config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got.ChannelConfigs) != 0 {
+		t.Errorf("Unexpected channels given to an extra replica: %v", got.ChannelConfigs)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/knative/eventing/pkg/sidecar/fanout"
 	"github.com/knative/eventing/pkg/sidecar/multichannelfanout"
 	"go.uber.org/zap"
 )
@@ -39,6 +40,9 @@ type Handler struct {
 	fanout     atomic.Value
 	updateLock sync.Mutex
 	logger     *zap.Logger
+	// configured is set to 1 once the handler serves a configuration, rather than the empty one it
+	// may be created with.
+	configured int32
 }
 
 type UpdateConfig func(config *multichannelfanout.Config) error
@@ -51,15 +55,20 @@ func NewHandler(handler *multichannelfanout.Handler, logger *zap.Logger) *Handle
 		logger: logger.With(zap.String("httpHandler", "swappable")),
 	}
 	h.setMultiChannelFanoutHandler(handler)
+	h.configured = 1
 	return h
 }
 
-func NewEmptyHandler(logger *zap.Logger) (*Handler, error) {
-	h, err := multichannelfanout.NewHandler(logger, multichannelfanout.Config{})
+// NewEmptyHandler creates a new swappable.Handler with an empty configuration. opts are applied
+// to every fanout.Handler created for later configurations.
+func NewEmptyHandler(logger *zap.Logger, opts ...fanout.Option) (*Handler, error) {
+	h, err := multichannelfanout.NewHandler(logger, multichannelfanout.Config{}, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return NewHandler(h, logger), nil
+	sh := NewHandler(h, logger)
+	sh.configured = 0
+	return sh, nil
 }
 
 // getMultiChannelFanoutHandler gets the current multichannelfanout.Handler to delegate all HTTP
@@ -95,6 +104,16 @@ func (h *Handler) UpdateConfig(config *multichannelfanout.Config) error {
 		}
 		h.setMultiChannelFanoutHandler(newIh)
 	}
+	atomic.StoreInt32(&h.configured, 1)
+	return nil
+}
+
+// Ready returns an error until the handler was given its first configuration, as it fans out the
+// events of no channel before that.
+func (h *Handler) Ready() error {
+	if atomic.LoadInt32(&h.configured) == 0 {
+		return errors.New("no configuration loaded yet")
+	}
 	return nil
 }
 
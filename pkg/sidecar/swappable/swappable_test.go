@@ -164,6 +164,30 @@ func TestHandler_NilConfigChange(t *testing.T) {
 	}
 }
 
+func TestHandler_Ready(t *testing.T) {
+	h, err := NewEmptyHandler(zap.NewNop())
+	if err != nil {
+		t.Errorf("Unexpected error creating handler: %v", err)
+	}
+	if err := h.Ready(); err == nil {
+		t.Errorf("Expected the handler not to be ready before its first configuration.")
+	}
+
+	if err := h.UpdateConfig(nil); err == nil {
+		t.Errorf("Expected an error when updating to a nil config.")
+	}
+	if err := h.Ready(); err == nil {
+		t.Errorf("Expected the handler not to be ready after an invalid configuration.")
+	}
+
+	if err := h.UpdateConfig(&multichannelfanout.Config{}); err != nil {
+		t.Errorf("Unexpected error updating config: %v", err)
+	}
+	if err := h.Ready(); err != nil {
+		t.Errorf("Unexpected error once the handler is configured: %v", err)
+	}
+}
+
 func updateConfigAndTest(t *testing.T, h *Handler, config multichannelfanout.Config) {
 	server := httptest.NewServer(&successHandler{})
 	defer server.Close()
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NewSubject returns an empty object of kind gvk, or false if a SinkBinding cannot bind objects of
+// that kind.
+func NewSubject(gvk schema.GroupVersionKind) (runtime.Object, bool) {
+	switch gvk {
+	case appsv1.SchemeGroupVersion.WithKind("Deployment"):
+		return &appsv1.Deployment{}, true
+	case appsv1.SchemeGroupVersion.WithKind("StatefulSet"):
+		return &appsv1.StatefulSet{}, true
+	case batchv1.SchemeGroupVersion.WithKind("Job"):
+		return &batchv1.Job{}, true
+	default:
+		return nil, false
+	}
+}
+
+// IsSubject returns whether the object of kind gvk named name is the subject of b.
+func IsSubject(b *v1alpha1.SinkBinding, gvk schema.GroupVersionKind, name string) bool {
+	return schema.FromAPIVersionAndKind(b.Spec.Subject.APIVersion, b.Spec.Subject.Kind) == gvk && b.Spec.Subject.Name == name
+}
+
+// PodSpec returns the spec of the pod template of subject, which must have been returned by
+// NewSubject.
+func PodSpec(subject runtime.Object) *corev1.PodSpec {
+	switch s := subject.(type) {
+	case *appsv1.Deployment:
+		return &s.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return &s.Spec.Template.Spec
+	case *batchv1.Job:
+		return &s.Spec.Template.Spec
+	default:
+		return nil
+	}
+}
+
+// Bind gives sinkURI to the containers of spec in the SinkBindingEnvVar environment variable. It
+// returns whether spec was changed.
+func Bind(spec *corev1.PodSpec, sinkURI string) bool {
+	changed := false
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		found := false
+		for j := range c.Env {
+			if c.Env[j].Name != v1alpha1.SinkBindingEnvVar {
+				continue
+			}
+			found = true
+			if c.Env[j].Value != sinkURI || c.Env[j].ValueFrom != nil {
+				c.Env[j] = corev1.EnvVar{Name: v1alpha1.SinkBindingEnvVar, Value: sinkURI}
+				changed = true
+			}
+		}
+		if !found {
+			c.Env = append(c.Env, corev1.EnvVar{Name: v1alpha1.SinkBindingEnvVar, Value: sinkURI})
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Unbind removes the SinkBindingEnvVar environment variable from the containers of spec. It
+// returns whether spec was changed.
+func Unbind(spec *corev1.PodSpec) bool {
+	changed := false
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		env := c.Env[:0]
+		for _, e := range c.Env {
+			if e.Name == v1alpha1.SinkBindingEnvVar {
+				changed = true
+				continue
+			}
+			env = append(env, e)
+		}
+		if len(env) == 0 {
+			env = nil
+		}
+		c.Env = env
+	}
+	return changed
+}
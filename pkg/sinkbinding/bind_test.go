@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewSubject(t *testing.T) {
+	for _, gvk := range v1alpha1.SinkBindingSubjectKinds {
+		subject, ok := NewSubject(gvk)
+		if !ok {
+			t.Errorf("NewSubject(%v) is not a subject", gvk)
+			continue
+		}
+		if PodSpec(subject) == nil {
+			t.Errorf("PodSpec(%T) = nil", subject)
+		}
+	}
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		{Group: "extensions", Version: "v1beta1", Kind: "Deployment"},
+	} {
+		if _, ok := NewSubject(gvk); ok {
+			t.Errorf("NewSubject(%v) is a subject", gvk)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	const sinkURI = "http://sink.default.svc.cluster.local/"
+	env := func(value string) corev1.EnvVar {
+		return corev1.EnvVar{Name: v1alpha1.SinkBindingEnvVar, Value: value}
+	}
+	other := corev1.EnvVar{Name: "OTHER", Value: "value"}
+	tests := []struct {
+		name        string
+		containers  []corev1.Container
+		want        []corev1.Container
+		wantChanged bool
+	}{{
+		name:        "unbound",
+		containers:  []corev1.Container{{Name: "a"}, {Name: "b", Env: []corev1.EnvVar{other}}},
+		want:        []corev1.Container{{Name: "a", Env: []corev1.EnvVar{env(sinkURI)}}, {Name: "b", Env: []corev1.EnvVar{other, env(sinkURI)}}},
+		wantChanged: true,
+	}, {
+		name:        "bound to another sink",
+		containers:  []corev1.Container{{Name: "a", Env: []corev1.EnvVar{env("http://old/"), other}}},
+		want:        []corev1.Container{{Name: "a", Env: []corev1.EnvVar{env(sinkURI), other}}},
+		wantChanged: true,
+	}, {
+		name:        "bound",
+		containers:  []corev1.Container{{Name: "a", Env: []corev1.EnvVar{other, env(sinkURI)}}},
+		want:        []corev1.Container{{Name: "a", Env: []corev1.EnvVar{other, env(sinkURI)}}},
+		wantChanged: false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &corev1.PodSpec{Containers: test.containers}
+			if changed := Bind(spec, sinkURI); changed != test.wantChanged {
+				t.Errorf("Bind() = %v, want %v", changed, test.wantChanged)
+			}
+			if diff := cmp.Diff(test.want, spec.Containers); diff != "" {
+				t.Errorf("unexpected containers (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestUnbind(t *testing.T) {
+	other := corev1.EnvVar{Name: "OTHER", Value: "value"}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "a", Env: []corev1.EnvVar{{Name: v1alpha1.SinkBindingEnvVar, Value: "http://sink/"}}},
+		{Name: "b", Env: []corev1.EnvVar{other, {Name: v1alpha1.SinkBindingEnvVar, Value: "http://sink/"}}},
+	}}
+	if !Unbind(spec) {
+		t.Error("Unbind() = false, want true")
+	}
+	want := []corev1.Container{{Name: "a"}, {Name: "b", Env: []corev1.EnvVar{other}}}
+	if diff := cmp.Diff(want, spec.Containers); diff != "" {
+		t.Errorf("unexpected containers (-want, +got) = %v", diff)
+	}
+	if Unbind(spec) {
+		t.Error("Unbind() of an unbound spec = true, want false")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/knative/eventing/pkg/admission"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	listers "github.com/knative/eventing/pkg/client/listers/sources/v1alpha1"
+	"github.com/mattbaird/jsonpatch"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Webhook is a mutating admission webhook that binds the subjects of SinkBindings as they are
+// created and updated. It is what binds Jobs, whose pod template cannot be updated once they have
+// been created.
+type Webhook struct {
+	Client  kubernetes.Interface
+	Options admission.Options
+	Lister  listers.SinkBindingLister
+	Logger  *zap.Logger
+}
+
+// Run registers the Webhook and serves admission requests until stop is closed.
+func (wh *Webhook) Run(stop <-chan struct{}) error {
+	return admission.Serve(wh.Logger, wh.Options, wh.register, wh, stop)
+}
+
+// register registers the Webhook as a mutating webhook of the subject kinds, trusting caCert.
+func (wh *Webhook) register(caCert []byte) error {
+	var rules []admissionregistrationv1beta1.RuleWithOperations
+	for _, gvk := range v1alpha1.SinkBindingSubjectKinds {
+		rules = append(rules, admissionregistrationv1beta1.RuleWithOperations{
+			Operations: []admissionregistrationv1beta1.OperationType{
+				admissionregistrationv1beta1.Create,
+				admissionregistrationv1beta1.Update,
+			},
+			Rule: admissionregistrationv1beta1.Rule{
+				APIGroups:   []string{gvk.Group},
+				APIVersions: []string{gvk.Version},
+				Resources:   []string{resource(gvk)},
+			},
+		})
+	}
+	return admission.RegisterMutating(wh.Client, wh.Options, rules, caCert)
+}
+
+// resource returns the resource of the subject kind gvk.
+func resource(gvk schema.GroupVersionKind) string {
+	switch gvk.Kind {
+	case "Deployment":
+		return "deployments"
+	case "StatefulSet":
+		return "statefulsets"
+	case "Job":
+		return "jobs"
+	default:
+		return ""
+	}
+}
+
+// ServeHTTP implements the admission webhook.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admission.Handler(wh.admit).ServeHTTP(w, r)
+}
+
+// admit binds the subject in request, if it is the subject of a SinkBinding. The subject is always
+// admitted.
+func (wh *Webhook) admit(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	allowed := &admissionv1beta1.AdmissionResponse{Allowed: true}
+	if request.Operation != admissionv1beta1.Create && request.Operation != admissionv1beta1.Update {
+		return allowed
+	}
+	gvk := schema.GroupVersionKind{Group: request.Kind.Group, Version: request.Kind.Version, Kind: request.Kind.Kind}
+	subject, ok := NewSubject(gvk)
+	if !ok {
+		return allowed
+	}
+	if err := json.Unmarshal(request.Object.Raw, subject); err != nil {
+		wh.Logger.Error("Failed to decode the subject", zap.Any("kind", gvk), zap.Error(err))
+		return allowed
+	}
+
+	sinkURI, err := wh.sinkURI(gvk, subject.(metav1.Object))
+	if err != nil {
+		wh.Logger.Error("Failed to list the sink bindings", zap.String("namespace", request.Namespace), zap.Error(err))
+		return allowed
+	}
+	if sinkURI == "" {
+		return allowed
+	}
+
+	patch, err := bindPatch(subject, sinkURI)
+	if err != nil {
+		wh.Logger.Error("Failed to bind the subject", zap.Any("kind", gvk), zap.Error(err))
+		return allowed
+	}
+	if patch == nil {
+		return allowed
+	}
+	patchType := admissionv1beta1.PatchTypeJSONPatch
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
+
+// sinkURI returns the URI of the sink of the SinkBinding whose subject is the object m of kind
+// gvk, or the empty string if there is none or its sink has not been resolved. SinkBindings being
+// deleted are ignored, so that their subjects can be unbound.
+func (wh *Webhook) sinkURI(gvk schema.GroupVersionKind, m metav1.Object) (string, error) {
+	if m.GetName() == "" {
+		return "", nil
+	}
+	bindings, err := wh.Lister.SinkBindings(m.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	for _, b := range bindings {
+		if b.DeletionTimestamp != nil || !IsSubject(b, gvk, m.GetName()) {
+			continue
+		}
+		return b.Status.SinkURI, nil
+	}
+	return "", nil
+}
+
+// bindPatch returns the JSON patch that binds subject to sinkURI, or nil if it is already bound.
+func bindPatch(subject runtime.Object, sinkURI string) ([]byte, error) {
+	before, err := json.Marshal(subject)
+	if err != nil {
+		return nil, err
+	}
+	spec := PodSpec(subject)
+	if spec == nil {
+		return nil, errors.New("the subject has no pod template")
+	}
+	if !Bind(spec, sinkURI) {
+		return nil, nil
+	}
+	after, err := json.Marshal(subject)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreatePatch(before, after)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(patch)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knative/eventing/pkg/apis/sources/v1alpha1"
+	listers "github.com/knative/eventing/pkg/client/listers/sources/v1alpha1"
+	"github.com/mattbaird/jsonpatch"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	testNS  = "testnamespace"
+	sinkURI = "http://sink.testnamespace.svc.cluster.local/"
+)
+
+func TestAdmit(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name      string
+		bindings  []*v1alpha1.SinkBinding
+		kind      metav1.GroupVersionKind
+		operation admissionv1beta1.Operation
+		object    runtime.Object
+		wantPatch []jsonpatch.JsonPatchOperation
+	}{{
+		name:      "deployment",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "Deployment", "emitter", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Create,
+		object:    deployment("emitter"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/spec/template/spec/containers/0/env",
+			Value:     []interface{}{map[string]interface{}{"name": v1alpha1.SinkBindingEnvVar, "value": sinkURI}},
+		}},
+	}, {
+		name:      "job",
+		bindings:  []*v1alpha1.SinkBinding{binding("batch/v1", "Job", "emitter", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"},
+		operation: admissionv1beta1.Create,
+		object:    job("emitter"),
+		wantPatch: []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/spec/template/spec/containers/0/env",
+			Value:     []interface{}{map[string]interface{}{"name": v1alpha1.SinkBindingEnvVar, "value": sinkURI}},
+		}},
+	}, {
+		name:      "not a subject",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "Deployment", "other", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Create,
+		object:    deployment("emitter"),
+	}, {
+		name:      "subject of another kind",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "StatefulSet", "emitter", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Create,
+		object:    deployment("emitter"),
+	}, {
+		name:      "sink not resolved",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "Deployment", "emitter", "")},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Create,
+		object:    deployment("emitter"),
+	}, {
+		name: "binding being deleted",
+		bindings: []*v1alpha1.SinkBinding{func() *v1alpha1.SinkBinding {
+			b := binding("apps/v1", "Deployment", "emitter", sinkURI)
+			b.DeletionTimestamp = &now
+			return b
+		}()},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Update,
+		object:    deployment("emitter"),
+	}, {
+		name:      "already bound",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "Deployment", "emitter", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Update,
+		object: func() runtime.Object {
+			d := deployment("emitter")
+			Bind(&d.Spec.Template.Spec, sinkURI)
+			return d
+		}(),
+	}, {
+		name:      "delete",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "Deployment", "emitter", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		operation: admissionv1beta1.Delete,
+	}, {
+		name:      "unsupported kind",
+		bindings:  []*v1alpha1.SinkBinding{binding("apps/v1", "Deployment", "emitter", sinkURI)},
+		kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		operation: admissionv1beta1.Create,
+		object:    &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "emitter"}},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, b := range test.bindings {
+				indexer.Add(b)
+			}
+			wh := &Webhook{
+				Lister: listers.NewSinkBindingLister(indexer),
+				Logger: zap.NewNop(),
+			}
+
+			request := &admissionv1beta1.AdmissionRequest{
+				UID:       types.UID("test-uid"),
+				Kind:      test.kind,
+				Namespace: testNS,
+				Operation: test.operation,
+			}
+			if test.object != nil {
+				raw, err := json.Marshal(test.object)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.Object.Raw = raw
+			}
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: request})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			wh.ServeHTTP(rec, req)
+
+			var review admissionv1beta1.AdmissionReview
+			if err := json.NewDecoder(rec.Body).Decode(&review); err != nil {
+				t.Fatalf("could not decode the response: %v", err)
+			}
+			response := review.Response
+			if !response.Allowed {
+				t.Error("the subject was not admitted")
+			}
+			if response.UID != request.UID {
+				t.Errorf("unexpected UID: want %q, got %q", request.UID, response.UID)
+			}
+			var patch []jsonpatch.JsonPatchOperation
+			if response.Patch != nil {
+				if err := json.Unmarshal(response.Patch, &patch); err != nil {
+					t.Fatalf("could not decode the patch: %v", err)
+				}
+			}
+			if diff := cmp.Diff(test.wantPatch, patch); diff != "" {
+				t.Errorf("unexpected patch (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTPContentType(t *testing.T) {
+	wh := &Webhook{Logger: zap.NewNop()}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(nil))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	wh.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("unexpected status: want %d, got %d", http.StatusUnsupportedMediaType, rec.Code)
+	}
+}
+
+func binding(apiVersion, kind, name, sinkURI string) *v1alpha1.SinkBinding {
+	return &v1alpha1.SinkBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      "binding",
+		},
+		Spec: v1alpha1.SinkBindingSpec{
+			Subject: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Name: name},
+			Sink:    &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Name: "sink"},
+		},
+		Status: v1alpha1.SinkBindingStatus{SinkURI: sinkURI},
+	}
+}
+
+func podTemplate() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "emitter", Image: "emitter-image"}},
+		},
+	}
+}
+
+func deployment(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: name},
+		Spec:       appsv1.DeploymentSpec{Template: podTemplate()},
+	}
+}
+
+func job(name string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: name},
+		Spec:       batchv1.JobSpec{Template: podTemplate()},
+	}
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package subscriptionvalidator implements a validating admission webhook rejecting the
+// Subscriptions whose references cannot resolve, so that they are reported when the Subscriptions
+// are applied instead of leaving them not ready, and the Subscriptions whose requester may not read
+// the Secrets holding the credentials of their subscriber.
+package subscriptionvalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/knative/pkg/apis"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/knative/eventing/pkg/admission"
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+)
+
+// Webhook is a validating admission webhook rejecting the Subscriptions whose channel does not
+// exist, or whose subscriber, reply or dead letter sink reference a kind the cluster does not
+// serve. References to objects that do not exist yet are accepted, except for the channel, as the
+// objects may be created after the Subscription. Only the references that are set or changed are
+// checked, so that Subscriptions whose references broke later can still be updated and deleted.
+//
+// The dispatchers send the values of the Secrets referenced by the auth of the subscriber to the
+// subscriber, so the Subscriptions are also rejected when their subscriber is set or changed by a
+// requester who may not get these Secrets.
+type Webhook struct {
+	// Client registers the Webhook and reviews the access of the requesters to Secrets.
+	Client kubernetes.Interface
+	// Discovery lists the kinds served by the cluster.
+	Discovery discovery.ServerResourcesInterface
+	// Dynamic reads the Channels.
+	Dynamic dynamic.Interface
+	Options admission.Options
+	Logger  *zap.Logger
+}
+
+// Run registers the Webhook and serves admission requests until stop is closed.
+func (wh *Webhook) Run(stop <-chan struct{}) error {
+	return admission.Serve(wh.Logger, wh.Options, wh.register, wh, stop)
+}
+
+// register registers the Webhook as a validating webhook of the Subscriptions, trusting caCert.
+func (wh *Webhook) register(caCert []byte) error {
+	return admission.RegisterValidating(wh.Client, wh.Options, []admissionregistrationv1beta1.RuleWithOperations{{
+		Operations: []admissionregistrationv1beta1.OperationType{
+			admissionregistrationv1beta1.Create,
+			admissionregistrationv1beta1.Update,
+		},
+		Rule: admissionregistrationv1beta1.Rule{
+			APIGroups:   []string{v1alpha1.SchemeGroupVersion.Group},
+			APIVersions: []string{v1alpha1.SchemeGroupVersion.Version, v1beta1.SchemeGroupVersion.Version},
+			Resources:   []string{"subscriptions"},
+		},
+	}}, caCert)
+}
+
+// ServeHTTP implements the admission webhook.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admission.Handler(wh.admit).ServeHTTP(w, r)
+}
+
+// admit rejects the Subscription in request if one of its references that is set or changed
+// cannot resolve, or if its requester may not read the credentials of its subscriber.
+func (wh *Webhook) admit(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	allowed := &admissionv1beta1.AdmissionResponse{Allowed: true}
+	if request.Operation != admissionv1beta1.Create && request.Operation != admissionv1beta1.Update {
+		return allowed
+	}
+	sub, err := decode(request.Kind.Version, request.Object.Raw)
+	if err != nil {
+		wh.Logger.Error("Failed to decode the Subscription", zap.Any("kind", request.Kind), zap.Error(err))
+		return allowed
+	}
+	if sub.DeletionTimestamp != nil {
+		return allowed
+	}
+	old := &v1alpha1.Subscription{}
+	if request.Operation == admissionv1beta1.Update {
+		if old, err = decode(request.Kind.Version, request.OldObject.Raw); err != nil {
+			wh.Logger.Error("Failed to decode the old Subscription", zap.Any("kind", request.Kind), zap.Error(err))
+			return allowed
+		}
+	}
+
+	errs := wh.validate(sub, old)
+	if !equality.Semantic.DeepEqual(sub.Spec.Subscriber, old.Spec.Subscriber) {
+		errs = errs.Also(wh.checkSecretAccess(request.UserInfo, sub).ViaField("spec", "subscriber", "auth"))
+	}
+	if errs != nil {
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: errs.Error(),
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+			},
+		}
+	}
+	return allowed
+}
+
+// decode decodes the Subscription raw of version, as a v1alpha1 Subscription.
+func decode(version string, raw []byte) (*v1alpha1.Subscription, error) {
+	sub := &v1alpha1.Subscription{}
+	switch version {
+	case v1alpha1.SchemeGroupVersion.Version:
+		return sub, json.Unmarshal(raw, sub)
+	case v1beta1.SchemeGroupVersion.Version:
+		beta := &v1beta1.Subscription{}
+		if err := json.Unmarshal(raw, beta); err != nil {
+			return nil, err
+		}
+		return sub, beta.ConvertTo(sub)
+	default:
+		return nil, fmt.Errorf("unsupported version %q", version)
+	}
+}
+
+// validate checks the references of sub that differ from those of old.
+func (wh *Webhook) validate(sub, old *v1alpha1.Subscription) *apis.FieldError {
+	var errs *apis.FieldError
+	if !equality.Semantic.DeepEqual(sub.Spec.Channel, old.Spec.Channel) {
+		errs = errs.Also(wh.checkExists(sub.Namespace, sub.Spec.Channel).ViaField("spec", "channel"))
+	}
+	if ref, oldRef := subscriberRef(sub.Spec.Subscriber), subscriberRef(old.Spec.Subscriber); ref != nil && !equality.Semantic.DeepEqual(ref, oldRef) {
+		errs = errs.Also(wh.checkServed(*ref).ViaField("spec", "subscriber", "ref"))
+	}
+	if ref, oldRef := replyRef(sub.Spec.Reply), replyRef(old.Spec.Reply); ref != nil && !equality.Semantic.DeepEqual(ref, oldRef) {
+		errs = errs.Also(wh.checkServed(*ref).ViaField("spec", "reply", "channel"))
+	}
+	if ref, oldRef := deadLetterRef(sub.Spec.Schema), deadLetterRef(old.Spec.Schema); ref != nil && !equality.Semantic.DeepEqual(ref, oldRef) {
+		errs = errs.Also(wh.checkServed(*ref).ViaField("spec", "schema", "deadLetterSink", "ref"))
+	}
+	if ref, oldRef := subscriberRef(sub.Spec.OnError), subscriberRef(old.Spec.OnError); ref != nil && !equality.Semantic.DeepEqual(ref, oldRef) {
+		errs = errs.Also(wh.checkServed(*ref).ViaField("spec", "onError", "ref"))
+	}
+	return errs
+}
+
+// checkServed returns an error if the kind of ref is not served by the cluster. Errors reading the
+// served kinds are logged and ignored.
+func (wh *Webhook) checkServed(ref corev1.ObjectReference) *apis.FieldError {
+	resources, err := wh.Discovery.ServerResourcesForGroupVersion(ref.APIVersion)
+	if apierrors.IsNotFound(err) {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("apiVersion %q is not served by the cluster", ref.APIVersion),
+			Paths:   []string{"apiVersion"},
+			Details: "Check the apiVersion of the reference, or install the CustomResourceDefinition it belongs to.",
+		}
+	}
+	if err != nil {
+		wh.Logger.Warn("Failed to list the served resources", zap.String("apiVersion", ref.APIVersion), zap.Error(err))
+		return nil
+	}
+	for _, r := range resources.APIResources {
+		if r.Kind == ref.Kind {
+			return nil
+		}
+	}
+	return &apis.FieldError{
+		Message: fmt.Sprintf("kind %q is not served by the cluster in %q", ref.Kind, ref.APIVersion),
+		Paths:   []string{"kind"},
+		Details: "Check the kind of the reference, or install the CustomResourceDefinition it belongs to.",
+	}
+}
+
+// checkExists returns an error if the object referenced by ref does not exist in namespace, or
+// if its kind is not served by the cluster.
+func (wh *Webhook) checkExists(namespace string, ref corev1.ObjectReference) *apis.FieldError {
+	if fe := wh.checkServed(ref); fe != nil {
+		return fe
+	}
+	rc := wh.Dynamic.Resource(apis.KindToResource(ref.GroupVersionKind())).Namespace(namespace)
+	_, err := rc.Get(ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("%s %q does not exist in namespace %q", ref.Kind, ref.Name, namespace),
+			Paths:   []string{"name"},
+			Details: fmt.Sprintf("Create the %s before the Subscription.", ref.Kind),
+		}
+	}
+	if err != nil {
+		wh.Logger.Warn("Failed to get the referenced object", zap.Any("ref", ref), zap.Error(err))
+	}
+	return nil
+}
+
+// checkSecretAccess returns an error for each Secret referenced by the auth of the subscriber of
+// sub that user may not get. Errors reviewing the access are returned as well, as the Secrets must
+// not be sent to a subscriber chosen by a requester who may not read them.
+func (wh *Webhook) checkSecretAccess(user authenticationv1.UserInfo, sub *v1alpha1.Subscription) *apis.FieldError {
+	if sub.Spec.Subscriber == nil || sub.Spec.Subscriber.Auth == nil {
+		return nil
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	var errs *apis.FieldError
+	for _, s := range authSecrets(*sub.Spec.Subscriber.Auth) {
+		sar, err := wh.Client.AuthorizationV1().SubjectAccessReviews().Create(&authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: sub.Namespace,
+					Verb:      "get",
+					Resource:  "secrets",
+					Name:      s.name,
+				},
+				User:   user.Username,
+				Groups: user.Groups,
+				Extra:  extra,
+				UID:    user.UID,
+			},
+		})
+		if err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("unable to review the access to secret %q: %v", s.name, err),
+				Paths:   []string{s.path},
+			})
+			continue
+		}
+		if !sar.Status.Allowed {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("%s may not get secret %q in namespace %q", user.Username, s.name, sub.Namespace),
+				Paths:   []string{s.path},
+				Details: "The credentials are sent to the subscriber, so only those who may read them can reference them.",
+			})
+		}
+	}
+	return errs
+}
+
+// authSecret is a Secret referenced by a SubscriberAuth, at path.
+type authSecret struct {
+	name string
+	path string
+}
+
+// authSecrets returns the Secrets referenced by a.
+func authSecrets(a eventingduck.SubscriberAuth) []authSecret {
+	var secrets []authSecret
+	if a.BearerToken != nil {
+		secrets = append(secrets, authSecret{a.BearerToken.Name, "bearerToken.name"})
+	}
+	if a.Basic != nil {
+		secrets = append(secrets,
+			authSecret{a.Basic.Username.Name, "basic.username.name"},
+			authSecret{a.Basic.Password.Name, "basic.password.name"})
+	}
+	if a.OIDC != nil {
+		secrets = append(secrets,
+			authSecret{a.OIDC.ClientID.Name, "oidc.clientID.name"},
+			authSecret{a.OIDC.ClientSecret.Name, "oidc.clientSecret.name"})
+	}
+	if a.ClientCertificate != nil {
+		secrets = append(secrets, authSecret{a.ClientCertificate.SecretName, "clientCertificate.secretName"})
+	}
+	return secrets
+}
+
+func subscriberRef(s *v1alpha1.SubscriberSpec) *corev1.ObjectReference {
+	if s == nil || s.Ref == nil || equality.Semantic.DeepEqual(s.Ref, &corev1.ObjectReference{}) {
+		return nil
+	}
+	return s.Ref
+}
+
+func replyRef(r *v1alpha1.ReplyStrategy) *corev1.ObjectReference {
+	if r == nil {
+		return nil
+	}
+	return r.Channel
+}
+
+func deadLetterRef(s *v1alpha1.SubscriptionSchema) *corev1.ObjectReference {
+	if s == nil {
+		return nil
+	}
+	return subscriberRef(s.DeadLetterSink)
+}
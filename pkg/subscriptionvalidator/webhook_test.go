@@ -0,0 +1,364 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscriptionvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	eventingduck "github.com/knative/eventing/pkg/apis/duck/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1beta1"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const testNS = "testnamespace"
+
+// fakeDiscovery serves the kinds of its resources.
+type fakeDiscovery struct {
+	discovery.ServerResourcesInterface
+	resources map[string][]string
+}
+
+func (d *fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	kinds, ok := d.resources[groupVersion]
+	if !ok {
+		gv, _ := schema.ParseGroupVersion(groupVersion)
+		return nil, apierrors.NewNotFound(gv.WithResource("").GroupResource(), "")
+	}
+	list := &metav1.APIResourceList{GroupVersion: groupVersion}
+	for _, kind := range kinds {
+		list.APIResources = append(list.APIResources, metav1.APIResource{Kind: kind})
+	}
+	return list, nil
+}
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation admissionv1beta1.Operation
+		version   string
+		object    runtime.Object
+		oldObject runtime.Object
+		wantError string
+	}{{
+		name:      "valid",
+		operation: admissionv1beta1.Create,
+		object:    subscription(channelRef("channel"), subscriber("Service"), reply("Channel")),
+	}, {
+		name:      "missing channel",
+		operation: admissionv1beta1.Create,
+		object:    subscription(channelRef("missing"), nil, nil),
+		wantError: `Channel "missing" does not exist in namespace "testnamespace": spec.channel.name`,
+	}, {
+		name:      "channel of unknown kind",
+		operation: admissionv1beta1.Create,
+		object: subscription(corev1.ObjectReference{
+			APIVersion: "eventing.knative.dev/v1alpha1",
+			Kind:       "Chanel",
+			Name:       "channel",
+		}, nil, nil),
+		wantError: `kind "Chanel" is not served by the cluster in "eventing.knative.dev/v1alpha1": spec.channel.kind`,
+	}, {
+		name:      "subscriber of unknown apiVersion",
+		operation: admissionv1beta1.Create,
+		object: subscription(channelRef("channel"), &corev1.ObjectReference{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Name:       "subscriber",
+		}, nil),
+		wantError: `apiVersion "serving.knative.dev/v1" is not served by the cluster: spec.subscriber.ref.apiVersion`,
+	}, {
+		name:      "missing subscriber",
+		operation: admissionv1beta1.Create,
+		object:    subscription(channelRef("channel"), subscriber("Service"), nil),
+	}, {
+		name:      "reply of unknown kind",
+		operation: admissionv1beta1.Create,
+		object:    subscription(channelRef("channel"), nil, reply("Broker")),
+		wantError: `kind "Broker" is not served by the cluster in "eventing.knative.dev/v1alpha1": spec.reply.channel.kind`,
+	}, {
+		name:      "v1beta1",
+		operation: admissionv1beta1.Create,
+		version:   "v1beta1",
+		object:    betaSubscription(subscription(channelRef("missing"), nil, nil)),
+		wantError: `Channel "missing" does not exist in namespace "testnamespace": spec.channel.name`,
+	}, {
+		name:      "unchanged references",
+		operation: admissionv1beta1.Update,
+		object:    subscription(channelRef("missing"), nil, reply("Broker")),
+		oldObject: subscription(channelRef("missing"), nil, reply("Broker")),
+	}, {
+		name:      "changed reference",
+		operation: admissionv1beta1.Update,
+		object:    subscription(channelRef("channel"), nil, reply("Broker")),
+		oldObject: subscription(channelRef("missing"), nil, reply("Broker")),
+	}, {
+		name:      "deleted",
+		operation: admissionv1beta1.Update,
+		object:    deleted(subscription(channelRef("missing"), nil, nil)),
+		oldObject: subscription(channelRef("channel"), nil, nil),
+	}, {
+		name:      "delete",
+		operation: admissionv1beta1.Delete,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wh := &Webhook{
+				Discovery: &fakeDiscovery{resources: map[string][]string{
+					"eventing.knative.dev/v1alpha1": {"Channel", "Subscription"},
+					"serving.knative.dev/v1alpha1":  {"Service"},
+				}},
+				Dynamic: fake.NewSimpleDynamicClient(runtime.NewScheme(), channel("channel")),
+				Logger:  zap.NewNop(),
+			}
+
+			version := test.version
+			if version == "" {
+				version = "v1alpha1"
+			}
+			request := &admissionv1beta1.AdmissionRequest{
+				UID:       types.UID("test-uid"),
+				Kind:      metav1.GroupVersionKind{Group: "eventing.knative.dev", Version: version, Kind: "Subscription"},
+				Namespace: testNS,
+				Operation: test.operation,
+			}
+			if test.object != nil {
+				raw, err := json.Marshal(test.object)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.Object.Raw = raw
+			}
+			if test.oldObject != nil {
+				raw, err := json.Marshal(test.oldObject)
+				if err != nil {
+					t.Fatal(err)
+				}
+				request.OldObject.Raw = raw
+			}
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: request})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			wh.ServeHTTP(rec, req)
+
+			var review admissionv1beta1.AdmissionReview
+			if err := json.NewDecoder(rec.Body).Decode(&review); err != nil {
+				t.Fatalf("could not decode the response: %v", err)
+			}
+			response := review.Response
+			if response.UID != request.UID {
+				t.Errorf("unexpected UID: want %q, got %q", request.UID, response.UID)
+			}
+			if test.wantError == "" {
+				if !response.Allowed {
+					t.Errorf("the object was not admitted: %v", response.Result.Message)
+				}
+				return
+			}
+			if response.Allowed {
+				t.Fatal("the object was admitted")
+			}
+			if !strings.Contains(response.Result.Message, test.wantError) {
+				t.Errorf("unexpected error: want %q, got %q", test.wantError, response.Result.Message)
+			}
+		})
+	}
+}
+
+func TestAdmitSecretAccess(t *testing.T) {
+	// alice may only get the Secret "readable".
+	var reviewed []authorizationv1.SubjectAccessReviewSpec
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sar := &authorizationv1.SubjectAccessReview{}
+		json.NewDecoder(r.Body).Decode(sar)
+		reviewed = append(reviewed, sar.Spec)
+		attrs := sar.Spec.ResourceAttributes
+		sar.Status.Allowed = sar.Spec.User == "alice" && attrs.Namespace == testNS && attrs.Verb == "get" &&
+			attrs.Resource == "secrets" && attrs.Name == "readable"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sar)
+	}))
+	defer s.Close()
+	kc, err := kubernetes.NewForConfig(&rest.Config{Host: s.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	wh := &Webhook{
+		Client: kc,
+		Discovery: &fakeDiscovery{resources: map[string][]string{
+			"eventing.knative.dev/v1alpha1": {"Channel"},
+		}},
+		Dynamic: fake.NewSimpleDynamicClient(runtime.NewScheme(), channel("channel")),
+		Logger:  zap.NewNop(),
+	}
+	withAuth := func(token, dnsName string) *v1alpha1.Subscription {
+		sub := subscription(channelRef("channel"), nil, nil)
+		sub.Spec.Subscriber = &v1alpha1.SubscriberSpec{
+			DNSName: &dnsName,
+			Auth: &eventingduck.SubscriberAuth{
+				BearerToken: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: token},
+					Key:                  "token",
+				},
+			},
+		}
+		return sub
+	}
+	user := authenticationv1.UserInfo{Username: "alice", Groups: []string{"developers"}}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1beta1.Operation
+		object      *v1alpha1.Subscription
+		oldObject   *v1alpha1.Subscription
+		wantAllowed bool
+		wantReviews int
+	}{{
+		name:        "readable secret",
+		operation:   admissionv1beta1.Create,
+		object:      withAuth("readable", "http://example.com/"),
+		wantAllowed: true,
+		wantReviews: 1,
+	}, {
+		name:        "unreadable secret",
+		operation:   admissionv1beta1.Create,
+		object:      withAuth("unreadable", "http://example.com/"),
+		wantReviews: 1,
+	}, {
+		name:        "unchanged subscriber",
+		operation:   admissionv1beta1.Update,
+		object:      withAuth("unreadable", "http://example.com/"),
+		oldObject:   withAuth("unreadable", "http://example.com/"),
+		wantAllowed: true,
+	}, {
+		name:        "subscriber moved",
+		operation:   admissionv1beta1.Update,
+		object:      withAuth("unreadable", "http://attacker.example.com/"),
+		oldObject:   withAuth("unreadable", "http://example.com/"),
+		wantReviews: 1,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reviewed = nil
+			request := &admissionv1beta1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "eventing.knative.dev", Version: "v1alpha1", Kind: "Subscription"},
+				Namespace: testNS,
+				Operation: test.operation,
+				UserInfo:  user,
+			}
+			request.Object.Raw, _ = json.Marshal(test.object)
+			if test.oldObject != nil {
+				request.OldObject.Raw, _ = json.Marshal(test.oldObject)
+			}
+			response := wh.admit(request)
+			if response.Allowed != test.wantAllowed {
+				t.Errorf("unexpected admission: want %v, got %v (%v)", test.wantAllowed, response.Allowed, response.Result)
+			}
+			if len(reviewed) != test.wantReviews {
+				t.Fatalf("unexpected number of access reviews: want %d, got %d", test.wantReviews, len(reviewed))
+			}
+			if len(reviewed) > 0 && (reviewed[0].User != user.Username || len(reviewed[0].Groups) != 1) {
+				t.Errorf("unexpected access review: %+v", reviewed[0])
+			}
+		})
+	}
+}
+
+func TestServeHTTPContentType(t *testing.T) {
+	wh := &Webhook{Logger: zap.NewNop()}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(nil))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	wh.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("unexpected status: want %d, got %d", http.StatusUnsupportedMediaType, rec.Code)
+	}
+}
+
+func channel(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "eventing.knative.dev/v1alpha1",
+		"kind":       "Channel",
+		"metadata": map[string]interface{}{
+			"namespace": testNS,
+			"name":      name,
+		},
+	}}
+}
+
+func channelRef(name string) corev1.ObjectReference {
+	return corev1.ObjectReference{APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Channel", Name: name}
+}
+
+func subscriber(kind string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{APIVersion: "serving.knative.dev/v1alpha1", Kind: kind, Name: "subscriber"}
+}
+
+func reply(kind string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{APIVersion: "eventing.knative.dev/v1alpha1", Kind: kind, Name: "reply"}
+}
+
+func subscription(channel corev1.ObjectReference, subscriberRef, replyRef *corev1.ObjectReference) *v1alpha1.Subscription {
+	sub := &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "sub"},
+		Spec:       v1alpha1.SubscriptionSpec{Channel: channel},
+	}
+	if subscriberRef != nil {
+		sub.Spec.Subscriber = &v1alpha1.SubscriberSpec{Ref: subscriberRef}
+	}
+	if replyRef != nil {
+		sub.Spec.Reply = &v1alpha1.ReplyStrategy{Channel: replyRef}
+	}
+	return sub
+}
+
+func betaSubscription(sub *v1alpha1.Subscription) *v1beta1.Subscription {
+	beta := &v1beta1.Subscription{}
+	if err := beta.ConvertFrom(sub); err != nil {
+		panic(err)
+	}
+	return beta
+}
+
+func deleted(sub *v1alpha1.Subscription) *v1alpha1.Subscription {
+	now := metav1.Now()
+	sub.DeletionTimestamp = &now
+	return sub
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigName is the name of the config map used for knative-eventing tracing config.
+	ConfigName = "config-tracing"
+
+	backendKey        = "backend"
+	zipkinEndpointKey = "zipkin-endpoint"
+	otlpEndpointKey   = "otlp-endpoint"
+	sampleRateKey     = "sample-rate"
+	debugKey          = "debug"
+
+	defaultSampleRate = 0.1
+)
+
+// Backend is the system the spans are exported to.
+type Backend string
+
+const (
+	// None disables the export of spans. The trace context is still propagated.
+	None Backend = "none"
+	// Zipkin exports the spans to the Zipkin v2 HTTP API. Jaeger collectors accept it on their
+	// Zipkin port.
+	Zipkin Backend = "zipkin"
+	// OTLP exports the spans to an OpenTelemetry collector with OTLP over HTTP, in JSON.
+	OTLP Backend = "otlp"
+)
+
+// Config is the tracing configuration read from the config-tracing ConfigMap.
+type Config struct {
+	Backend Backend
+	// Endpoint is the URL the spans are sent to, e.g. http://zipkin.istio-system:9411/api/v2/spans
+	// or http://otel-collector:4318/v1/traces.
+	Endpoint string
+	// SampleRate is the probability that a new trace is sampled. Traces continued from a sampled
+	// parent are always sampled.
+	SampleRate float64
+	// Debug samples every trace.
+	Debug bool
+}
+
+// NewConfigFromMap creates a Config from the data of the config-tracing ConfigMap. Tracing is
+// disabled if the data is empty.
+func NewConfigFromMap(data map[string]string) (*Config, error) {
+	cfg := &Config{
+		Backend:    None,
+		SampleRate: defaultSampleRate,
+	}
+	if backend, ok := data[backendKey]; ok {
+		cfg.Backend = Backend(backend)
+	}
+	switch cfg.Backend {
+	case None:
+	case Zipkin:
+		cfg.Endpoint = data[zipkinEndpointKey]
+	case OTLP:
+		cfg.Endpoint = data[otlpEndpointKey]
+	default:
+		return nil, fmt.Errorf("unsupported tracing backend %q", cfg.Backend)
+	}
+	if cfg.Backend != None {
+		if u, err := url.Parse(cfg.Endpoint); err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid %s endpoint %q", cfg.Backend, cfg.Endpoint)
+		}
+	}
+	if rate, ok := data[sampleRateKey]; ok {
+		f, err := strconv.ParseFloat(rate, 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("invalid %s %q, it must be between 0 and 1", sampleRateKey, rate)
+		}
+		cfg.SampleRate = f
+	}
+	if debug, ok := data[debugKey]; ok {
+		b, err := strconv.ParseBool(debug)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", debugKey, debug)
+		}
+		cfg.Debug = b
+	}
+	return cfg, nil
+}
+
+// NewConfigFromConfigMap creates a Config from the config-tracing ConfigMap.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) (*Config, error) {
+	return NewConfigFromMap(cm.Data)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewConfigFromMap(t *testing.T) {
+	testCases := map[string]struct {
+		data    map[string]string
+		want    *Config
+		wantErr bool
+	}{
+		"empty": {
+			data: map[string]string{},
+			want: &Config{Backend: None, SampleRate: defaultSampleRate},
+		},
+		"zipkin": {
+			data: map[string]string{
+				backendKey:        "zipkin",
+				zipkinEndpointKey: "http://zipkin.istio-system:9411/api/v2/spans",
+				sampleRateKey:     "0.5",
+			},
+			want: &Config{Backend: Zipkin, Endpoint: "http://zipkin.istio-system:9411/api/v2/spans", SampleRate: 0.5},
+		},
+		"otlp": {
+			data: map[string]string{
+				backendKey:      "otlp",
+				otlpEndpointKey: "http://otel-collector:4318/v1/traces",
+				debugKey:        "true",
+			},
+			want: &Config{Backend: OTLP, Endpoint: "http://otel-collector:4318/v1/traces", SampleRate: defaultSampleRate, Debug: true},
+		},
+		"unsupported backend": {
+			data:    map[string]string{backendKey: "stackdriver"},
+			wantErr: true,
+		},
+		"missing endpoint": {
+			data:    map[string]string{backendKey: "zipkin"},
+			wantErr: true,
+		},
+		"invalid sample rate": {
+			data:    map[string]string{sampleRateKey: "2"},
+			wantErr: true,
+		},
+		"invalid debug": {
+			data:    map[string]string{debugKey: "maybe"},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewConfigFromMap(tc.data)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected config (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// batchSize is the number of spans sent in a single request.
+	batchSize = 100
+	// batchInterval is the longest a span waits to be sent.
+	batchInterval = time.Second
+	// bufferSize is the number of spans waiting to be sent. Spans are dropped when it is full.
+	bufferSize = 1000
+)
+
+// encoder encodes a batch of spans of the service in the body of a request.
+type encoder func(service string, spans []*trace.SpanData) ([]byte, error)
+
+// exporter is a trace.Exporter sending batches of spans to an HTTP endpoint.
+type exporter struct {
+	endpoint string
+	service  string
+	encode   encoder
+	client   *http.Client
+	logger   *zap.SugaredLogger
+
+	spans chan *trace.SpanData
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+var _ trace.Exporter = (*exporter)(nil)
+
+// newExporter returns the exporter of the spans of service to the backend of cfg, which must not
+// be None. The exporter sends spans until it is closed.
+func newExporter(cfg *Config, service string, logger *zap.SugaredLogger) *exporter {
+	e := &exporter{
+		endpoint: cfg.Endpoint,
+		service:  service,
+		encode:   encodeZipkin,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		logger:   logger,
+		spans:    make(chan *trace.SpanData, bufferSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if cfg.Backend == OTLP {
+		e.encode = encodeOTLP
+	}
+	go e.run()
+	return e
+}
+
+// ExportSpan queues s to be sent. It never blocks: s is dropped if too many spans are queued.
+func (e *exporter) ExportSpan(s *trace.SpanData) {
+	select {
+	case e.spans <- s:
+	default:
+	}
+}
+
+// close sends the queued spans and stops the exporter.
+func (e *exporter) close() {
+	close(e.stop)
+	<-e.done
+}
+
+func (e *exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+	var batch []*trace.SpanData
+	for {
+		select {
+		case s := <-e.spans:
+			if batch = append(batch, s); len(batch) >= batchSize {
+				e.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			e.send(batch)
+			batch = nil
+		case <-e.stop:
+			for len(e.spans) > 0 {
+				batch = append(batch, <-e.spans)
+			}
+			e.send(batch)
+			return
+		}
+	}
+}
+
+func (e *exporter) send(batch []*trace.SpanData) {
+	if len(batch) == 0 {
+		return
+	}
+	body, err := e.encode(e.service, batch)
+	if err != nil {
+		e.logger.Errorw("Unable to encode spans", zap.Error(err))
+		return
+	}
+	res, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		e.logger.Errorw("Unable to export spans", zap.Error(err))
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		e.logger.Errorw("Unable to export spans", zap.Int("status", res.StatusCode))
+	}
+}
+
+// zipkinSpan is a span of the Zipkin v2 API.
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+var zipkinKinds = map[int]string{
+	trace.SpanKindServer: "SERVER",
+	trace.SpanKindClient: "CLIENT",
+}
+
+func encodeZipkin(service string, spans []*trace.SpanData) ([]byte, error) {
+	zs := make([]zipkinSpan, 0, len(spans))
+	for _, s := range spans {
+		z := zipkinSpan{
+			TraceID:       s.TraceID.String(),
+			ID:            s.SpanID.String(),
+			Name:          s.Name,
+			Kind:          zipkinKinds[s.SpanKind],
+			Timestamp:     s.StartTime.UnixNano() / int64(time.Microsecond),
+			Duration:      int64(s.EndTime.Sub(s.StartTime) / time.Microsecond),
+			LocalEndpoint: zipkinEndpoint{ServiceName: service},
+			Tags:          make(map[string]string, len(s.Attributes)+1),
+		}
+		if s.ParentSpanID != (trace.SpanID{}) {
+			z.ParentID = s.ParentSpanID.String()
+		}
+		for k, v := range s.Attributes {
+			z.Tags[k] = fmt.Sprint(v)
+		}
+		if s.Code != trace.StatusCodeOK {
+			z.Tags["error"] = s.Message
+		}
+		zs = append(zs, z)
+	}
+	return json.Marshal(zs)
+}
+
+// The types of the OTLP JSON encoding of spans.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// The OTLP span kinds and status codes.
+const (
+	otlpKindInternal = 1
+	otlpKindServer   = 2
+	otlpKindClient   = 3
+
+	otlpStatusError = 2
+)
+
+var otlpKinds = map[int]int{
+	trace.SpanKindServer: otlpKindServer,
+	trace.SpanKindClient: otlpKindClient,
+}
+
+func encodeOTLP(service string, spans []*trace.SpanData) ([]byte, error) {
+	ss := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		o := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			Name:              s.Name,
+			Kind:              otlpKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+		}
+		if kind, ok := otlpKinds[s.SpanKind]; ok {
+			o.Kind = kind
+		}
+		if s.ParentSpanID != (trace.SpanID{}) {
+			o.ParentSpanID = s.ParentSpanID.String()
+		}
+		for k, v := range s.Attributes {
+			o.Attributes = append(o.Attributes, otlpAttribute{Key: k, Value: otlpValue(v)})
+		}
+		if s.Code != trace.StatusCodeOK {
+			o.Status = otlpStatus{Code: otlpStatusError, Message: s.Message}
+		}
+		ss = append(ss, o)
+	}
+	return json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpAttribute{{Key: "service.name", Value: otlpValue(service)}},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/knative/eventing"},
+				Spans: ss,
+			}},
+		}},
+	})
+}
+
+// otlpValue returns the OTLP AnyValue of an attribute value, which is a string, a bool or an int64.
+// Integers are encoded as strings, as they are 64 bits.
+func otlpValue(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+)
+
+var testSpan = &trace.SpanData{
+	SpanContext: trace.SpanContext{
+		TraceID: trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:  trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+	},
+	ParentSpanID: trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	SpanKind:     trace.SpanKindClient,
+	Name:         "channel.dispatch",
+	StartTime:    time.Unix(1500000000, 0),
+	EndTime:      time.Unix(1500000000, int64(250*time.Millisecond)),
+	Attributes: map[string]interface{}{
+		"channel":          "test-ns/test-channel",
+		"http.status_code": int64(503),
+	},
+	Status: trace.Status{Code: trace.StatusCodeUnknown, Message: "unexpected HTTP response"},
+}
+
+func TestExporter(t *testing.T) {
+	testCases := map[string]struct {
+		backend Backend
+		check   func(t *testing.T, body []byte)
+	}{
+		"zipkin": {
+			backend: Zipkin,
+			check: func(t *testing.T, body []byte) {
+				var spans []zipkinSpan
+				if err := json.Unmarshal(body, &spans); err != nil {
+					t.Fatalf("Invalid Zipkin spans: %v", err)
+				}
+				if len(spans) != 1 {
+					t.Fatalf("Expected 1 span, got %d", len(spans))
+				}
+				s := spans[0]
+				if s.TraceID != "0af7651916cd43dd8448eb211c80319c" || s.ID != "b7ad6b7169203331" || s.ParentID != "00f067aa0ba902b7" {
+					t.Errorf("Unexpected span IDs: %+v", s)
+				}
+				if s.Kind != "CLIENT" || s.Duration != 250000 || s.LocalEndpoint.ServiceName != "test-service" {
+					t.Errorf("Unexpected span: %+v", s)
+				}
+				if s.Tags["http.status_code"] != "503" || s.Tags["error"] != "unexpected HTTP response" {
+					t.Errorf("Unexpected tags: %v", s.Tags)
+				}
+			},
+		},
+		"otlp": {
+			backend: OTLP,
+			check: func(t *testing.T, body []byte) {
+				var req otlpRequest
+				if err := json.Unmarshal(body, &req); err != nil {
+					t.Fatalf("Invalid OTLP request: %v", err)
+				}
+				if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+					t.Fatalf("Expected 1 span, got %s", body)
+				}
+				if got := req.ResourceSpans[0].Resource.Attributes[0].Value["stringValue"]; got != "test-service" {
+					t.Errorf("Unexpected service name %v", got)
+				}
+				s := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+				if s.TraceID != "0af7651916cd43dd8448eb211c80319c" || s.SpanID != "b7ad6b7169203331" || s.ParentSpanID != "00f067aa0ba902b7" {
+					t.Errorf("Unexpected span IDs: %+v", s)
+				}
+				if s.Kind != otlpKindClient || s.StartTimeUnixNano != "1500000000000000000" || s.EndTimeUnixNano != "1500000000250000000" {
+					t.Errorf("Unexpected span: %+v", s)
+				}
+				if s.Status.Code != otlpStatusError {
+					t.Errorf("Unexpected status: %+v", s.Status)
+				}
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			bodies := make(chan []byte, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				bodies <- body
+			}))
+			defer server.Close()
+
+			e := newExporter(&Config{Backend: tc.backend, Endpoint: server.URL}, "test-service", zap.NewNop().Sugar())
+			e.ExportSpan(testSpan)
+			e.close()
+
+			select {
+			case body := <-bodies:
+				tc.check(t, body)
+			default:
+				t.Fatal("The span was not exported")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing configures the export of the OpenCensus spans of the process from the
+// config-tracing ConfigMap.
+package tracing
+
+import (
+	"sync"
+
+	"github.com/knative/pkg/configmap"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	mu sync.Mutex
+	// current is the exporter of the spans of the process. It is nil when spans are not exported.
+	current *exporter
+)
+
+// ApplyConfig makes the process sample and export its spans, as the service, as configured by cfg.
+// It replaces the previous configuration.
+func ApplyConfig(cfg *Config, service string, logger *zap.SugaredLogger) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sampler := trace.ProbabilitySampler(cfg.SampleRate)
+	if cfg.Debug {
+		sampler = trace.AlwaysSample()
+	}
+	trace.ApplyConfig(trace.Config{DefaultSampler: sampler})
+
+	if current != nil {
+		trace.UnregisterExporter(current)
+		current.close()
+		current = nil
+	}
+	if cfg.Backend != None {
+		current = newExporter(cfg, service, logger)
+		trace.RegisterExporter(current)
+	}
+}
+
+// UpdateExporterFromConfigMap returns a function applying the config-tracing ConfigMap to the
+// process, for a configmap.Watcher. Invalid configurations are logged and ignored.
+func UpdateExporterFromConfigMap(service string, logger *zap.SugaredLogger) func(*corev1.ConfigMap) {
+	return func(cm *corev1.ConfigMap) {
+		cfg, err := NewConfigFromConfigMap(cm)
+		if err != nil {
+			logger.Errorw("Invalid tracing configuration, it is ignored", zap.Error(err))
+			return
+		}
+		ApplyConfig(cfg, service, logger)
+		logger.Infow("Updated the tracing configuration", zap.String("backend", string(cfg.Backend)), zap.Float64("sampleRate", cfg.SampleRate))
+	}
+}
+
+// WatchConfigMap applies the config-tracing ConfigMap of namespace to the process until stopCh is
+// closed. It returns an error if the ConfigMap does not exist, but keeps watching for it: spans
+// are not exported until it is created.
+func WatchConfigMap(kc kubernetes.Interface, namespace, service string, logger *zap.SugaredLogger, stopCh <-chan struct{}) error {
+	w := configmap.NewInformedWatcher(kc, namespace)
+	w.Watch(ConfigName, UpdateExporterFromConfigMap(service, logger))
+	return w.Start(stopCh)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transform rewrites CloudEvents with Go templates, so that simple adaptations, like
+// renaming attributes, redacting fields of the data or rewrapping it, do not require deploying an
+// intermediate service.
+//
+// A template is executed with a map holding the context attributes of the event, keyed by their
+// lower-case names, and its data under the key "data". JSON data is decoded, so its fields can be
+// referenced, e.g. {{.data.customer.id}}; other data is a string. The output of the template is
+// the transformed event, in the JSON structured content mode of CloudEvents 1.0. The function
+// "json" encodes a value as JSON, e.g.
+//
+//   {
+//     "specversion": "1.0",
+//     "type": "com.example.order.v2",
+//     "source": {{json .source}},
+//     "id": {{json .id}},
+//     "data": {"customer": {{json .data.customer.id}}}
+//   }
+//
+// Templates are meant to be parsed once, when the configuration that contains them is loaded, and
+// then executed for every event.
+package transform
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DataKey is the key of the data of the event in the map a Template is executed with.
+const DataKey = "data"
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Template is a parsed transformation.
+type Template struct {
+	t *template.Template
+}
+
+// Parse parses text into a Template. An empty text returns a nil Template, which leaves events
+// unchanged.
+func Parse(text string) (*Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	t, err := template.New("transform").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{t: t}, nil
+}
+
+// Execute transforms the event with the context attributes attrs, keyed by their lower-case names,
+// and the data data. It returns the transformed event, in the JSON structured content mode.
+func (t *Template) Execute(attrs map[string]string, data []byte) ([]byte, error) {
+	event := make(map[string]interface{}, len(attrs)+1)
+	for k, v := range attrs {
+		event[k] = v
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err == nil {
+		event[DataKey] = decoded
+	} else {
+		event[DataKey] = string(data)
+	}
+
+	var out bytes.Buffer
+	if err := t.t.Execute(&out, event); err != nil {
+		return nil, err
+	}
+	var transformed map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &transformed); err != nil {
+		return nil, fmt.Errorf("the transformed event is not a JSON object: %v", err)
+	}
+	if transformed["specversion"] == nil {
+		return nil, errors.New("the transformed event has no specversion")
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	if tmpl, err := Parse("  "); tmpl != nil || err != nil {
+		t.Errorf("Expected a nil Template for an empty text. Actual %v, %v", tmpl, err)
+	}
+	if _, err := Parse("{{.type"); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestExecute(t *testing.T) {
+	attrs := map[string]string{
+		"specversion": "1.0",
+		"type":        "com.example.order",
+		"source":      "/orders",
+		"id":          "1234",
+	}
+	testCases := map[string]struct {
+		template string
+		data     string
+		want     string
+		wantErr  bool
+	}{
+		"rename attribute": {
+			template: `{"specversion": "1.0", "type": "com.example.order.v2", "source": {{json .source}}, "id": {{json .id}}, "data": {{json .data}}}`,
+			data:     `{"total": 10}`,
+			want:     `{"specversion": "1.0", "type": "com.example.order.v2", "source": "/orders", "id": "1234", "data": {"total": 10}}`,
+		},
+		"redact field": {
+			template: `{"specversion": "1.0", "type": {{json .type}}, "source": {{json .source}}, "id": {{json .id}}, "data": {"total": {{json .data.total}}}}`,
+			data:     `{"total": 10, "card": "4111111111111111"}`,
+			want:     `{"specversion": "1.0", "type": "com.example.order", "source": "/orders", "id": "1234", "data": {"total": 10}}`,
+		},
+		"rewrap non-JSON data": {
+			template: `{"specversion": "1.0", "type": {{json .type}}, "source": {{json .source}}, "id": {{json .id}}, "data": {"text": {{json .data}}}}`,
+			data:     `hello`,
+			want:     `{"specversion": "1.0", "type": "com.example.order", "source": "/orders", "id": "1234", "data": {"text": "hello"}}`,
+		},
+		"not a JSON object": {
+			template: `{{.type}}`,
+			wantErr:  true,
+		},
+		"no specversion": {
+			template: `{"type": {{json .type}}}`,
+			wantErr:  true,
+		},
+		"execution error": {
+			template: `{{index .data 1}}`,
+			data:     `{"total": 10}`,
+			wantErr:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			tmpl, err := Parse(tc.template)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing the template: %v", err)
+			}
+			got, err := tmpl.Execute(attrs, []byte(tc.data))
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error. Expected %v. Actual %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			var wantEvent, gotEvent interface{}
+			json.Unmarshal([]byte(tc.want), &wantEvent)
+			if err := json.Unmarshal(got, &gotEvent); err != nil {
+				t.Fatalf("Invalid JSON %q: %v", got, err)
+			}
+			if diff := cmp.Diff(wantEvent, gotEvent); diff != "" {
+				t.Errorf("Unexpected event (-want +got): %s", diff)
+			}
+		})
+	}
+}
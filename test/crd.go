@@ -18,14 +18,25 @@ package test
 // crd contains functions that construct boilerplate CRD definitions.
 
 import (
+	"fmt"
+
 	sourcesv1alpha1 "github.com/knative/eventing-sources/pkg/apis/sources/v1alpha1"
 	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	servingv1alpha1 "github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// CloudEvent specifies the arguments for a CloudEvent sent by the sendevents image.
+type CloudEvent struct {
+	ID     string
+	Type   string
+	Source string
+	Data   string
+}
+
 // Route returns a Route object in namespace
 func Route(name string, namespace string, configName string) *servingv1alpha1.Route {
 	return &servingv1alpha1.Route{
@@ -193,3 +204,100 @@ func NGinxPod(namespace string) *corev1.Pod {
 		},
 	}
 }
+
+// SubscriberSpecForService returns a SubscriberSpec for a given Kubernetes Service.
+func SubscriberSpecForService(name string) *v1alpha1.SubscriberSpec {
+	return &v1alpha1.SubscriberSpec{
+		Ref: &corev1.ObjectReference{
+			Kind:       "Service",
+			APIVersion: "v1",
+			Name:       name,
+		},
+	}
+}
+
+// EventLoggerPod returns a Pod that logs every event it receives, using the recordevents image
+// at imagePath. selector labels the Pod so that a Service can route events to it.
+func EventLoggerPod(name string, namespace string, imagePath string, selector map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      selector,
+			Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "recordevents",
+					Image: imagePath,
+					Ports: []corev1.ContainerPort{
+						{
+							ContainerPort: 8080,
+						},
+					},
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyAlways,
+		},
+	}
+}
+
+// EventSenderPod returns a Pod that sends event to sink once, using the sendevents image at
+// imagePath. The empty fields of event keep the defaults of the image.
+func EventSenderPod(name string, namespace string, imagePath string, sink string, event CloudEvent) *corev1.Pod {
+	args := []string{"-sink", sink, "-event-id", event.ID}
+	if event.Type != "" {
+		args = append(args, "-event-type", event.Type)
+	}
+	if event.Source != "" {
+		args = append(args, "-source", event.Source)
+	}
+	if event.Data != "" {
+		args = append(args, "-data", event.Data)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "sendevents",
+					Image: imagePath,
+					Args:  args,
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
+	}
+}
+
+// Service returns a Service in namespace that routes port 80 to port 8080 of the Pods matching
+// selector.
+func Service(name string, namespace string, selector map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(8080),
+				},
+			},
+		},
+	}
+}
+
+// ServiceHostName returns the cluster-local host name of the Service called name in namespace.
+func ServiceHostName(name string, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace)
+}
@@ -23,11 +23,15 @@ import (
 	"fmt"
 	"time"
 
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	eventingtyped "github.com/knative/eventing/pkg/client/clientset/versioned/typed/eventing/v1alpha1"
 	servingV1alpha1 "github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	servingtyped "github.com/knative/serving/pkg/client/clientset/versioned/typed/serving/v1alpha1"
 	"go.opencensus.io/trace"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	coretyped "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 const (
@@ -52,3 +56,57 @@ func WaitForRouteState(client servingtyped.RouteInterface, name string, inState
 		return inState(r)
 	})
 }
+
+// WaitForChannelState polls the status of the Channel called name from client every interval
+// until inState returns `true` indicating it is done, returns an error or timeout. desc will be
+// used to name the metric that is emitted to track how long it took for name to get into the state
+// checked by inState.
+func WaitForChannelState(client eventingtyped.ChannelInterface, name string, inState func(c *eventingv1alpha1.Channel) (bool, error), desc string) error {
+	metricName := fmt.Sprintf("WaitForChannelState/%s/%s", name, desc)
+	_, span := trace.StartSpan(context.Background(), metricName)
+	defer span.End()
+
+	return wait.PollImmediate(interval, timeout, func() (bool, error) {
+		c, err := client.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return true, err
+		}
+		return inState(c)
+	})
+}
+
+// WaitForSubscriptionState polls the status of the Subscription called name from client every
+// interval until inState returns `true` indicating it is done, returns an error or timeout. desc
+// will be used to name the metric that is emitted to track how long it took for name to get into
+// the state checked by inState.
+func WaitForSubscriptionState(client eventingtyped.SubscriptionInterface, name string, inState func(s *eventingv1alpha1.Subscription) (bool, error), desc string) error {
+	metricName := fmt.Sprintf("WaitForSubscriptionState/%s/%s", name, desc)
+	_, span := trace.StartSpan(context.Background(), metricName)
+	defer span.End()
+
+	return wait.PollImmediate(interval, timeout, func() (bool, error) {
+		s, err := client.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return true, err
+		}
+		return inState(s)
+	})
+}
+
+// WaitForPodState polls the status of the Pod called name from client every interval until
+// inState returns `true` indicating it is done, returns an error or timeout. desc will be used to
+// name the metric that is emitted to track how long it took for name to get into the state checked
+// by inState.
+func WaitForPodState(client coretyped.PodInterface, name string, inState func(p *corev1.Pod) (bool, error), desc string) error {
+	metricName := fmt.Sprintf("WaitForPodState/%s/%s", name, desc)
+	_, span := trace.StartSpan(context.Background(), metricName)
+	defer span.End()
+
+	return wait.PollImmediate(interval, timeout, func() (bool, error) {
+		p, err := client.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return true, err
+		}
+		return inState(p)
+	})
+}
@@ -0,0 +1,50 @@
+// +build e2e
+
+/*
+Copyright 2019 The Knative Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/knative/eventing/test"
+	"github.com/knative/eventing/test/framework"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestChannelDelivery sends an event to a Channel of every provisioner under test and checks that
+// a subscriber receives it.
+func TestChannelDelivery(t *testing.T) {
+	framework.RunForProvisioners(t, func(t *testing.T, provisioner *corev1.ObjectReference) {
+		f := framework.Setup(t)
+		defer f.TearDown()
+
+		channel := fmt.Sprintf("e2e-delivery-%s", provisioner.Name)
+		sink := fmt.Sprintf("e2e-delivery-sink-%s", provisioner.Name)
+		event := test.CloudEvent{
+			ID:   fmt.Sprintf("e2e-delivery-%s", provisioner.Name),
+			Data: `{"msg":"TestChannelDelivery"}`,
+		}
+
+		f.CreateChannelOrFail(channel, provisioner)
+		subscriber := f.CreateRecordingSinkOrFail(sink)
+		f.CreateSubscriptionOrFail(fmt.Sprintf("e2e-delivery-sub-%s", provisioner.Name), channel, subscriber)
+
+		f.SendEventOrFail(fmt.Sprintf("e2e-delivery-sender-%s", provisioner.Name), channel, event)
+		f.AssertEventReceivedOrFail(sink, event)
+	})
+}
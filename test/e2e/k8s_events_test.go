@@ -22,8 +22,7 @@ import (
 	"time"
 
 	"github.com/knative/eventing/test"
-	pkgTest "github.com/knative/pkg/test"
-	"github.com/knative/pkg/test/logging"
+	"github.com/knative/eventing/test/framework"
 )
 
 const (
@@ -33,49 +32,46 @@ const (
 	channelName      = "e2e-k8s-events-channel"
 	provisionerName  = "in-memory-channel"
 	subscriptionName = "e2e-k8s-events-subscription"
+	testNamespace    = "e2etest"
 )
 
 func TestKubernetesEvents(t *testing.T) {
-
-	logger := logging.GetContextLogger("TestKubernetesEvents")
-
-	clients, cleaner := Setup(t, logger)
-
-	test.CleanupOnInterrupt(func() { TearDown(clients, cleaner, logger) }, logger)
-	defer TearDown(clients, cleaner, logger)
+	f := framework.Setup(t)
+	defer f.TearDown()
+	logger := f.Logger
 
 	logger.Infof("Creating ServiceAccount and Binding")
 
-	err := CreateServiceAccountAndBinding(clients, serviceAccount, logger, cleaner)
+	err := f.CreateServiceAccountAndBinding(serviceAccount)
 	if err != nil {
 		t.Fatalf("Failed to create ServiceAccount or Binding: %v", err)
 	}
 
 	logger.Infof("Creating Channel")
-	channel := test.Channel(channelName, defaultNamespaceName, test.ClusterChannelProvisioner(provisionerName))
-	err = CreateChannel(clients, channel, logger, cleaner)
+	channel := test.Channel(channelName, f.Namespace, test.ClusterChannelProvisioner(provisionerName))
+	err = f.CreateChannel(channel)
 	if err != nil {
 		t.Fatalf("Failed to create Channel: %v", err)
 	}
 
 	logger.Infof("Creating EventSource")
-	k8sSource := test.KubernetesEventSource(eventSource, defaultNamespaceName, testNamespace, serviceAccount, test.ChannelRef(channelName))
-	err = CreateKubernetesEventSource(clients, k8sSource, logger, cleaner)
+	k8sSource := test.KubernetesEventSource(eventSource, f.Namespace, testNamespace, serviceAccount, test.ChannelRef(channelName))
+	err = f.CreateKubernetesEventSource(k8sSource)
 	if err != nil {
 		t.Fatalf("Failed to create KubernetesEventSource: %v", err)
 	}
 
 	logger.Infof("Creating Route and Config")
 	// The receiver of events which is accessible through Route
-	configImagePath := ImagePath("k8sevents")
-	err = WithRouteReady(clients, logger, cleaner, routeName, configImagePath)
+	configImagePath := framework.ImagePath("k8sevents")
+	err = f.WithRouteReady(routeName, configImagePath)
 	if err != nil {
 		t.Fatalf("The Route was not marked as Ready to serve traffic: %v", err)
 	}
 
 	logger.Infof("Creating Subscription")
-	subscription := test.Subscription(subscriptionName, defaultNamespaceName, test.ChannelRef(channelName), test.SubscriberSpecForRoute(routeName), nil)
-	err = CreateSubscription(clients, subscription, logger, cleaner)
+	subscription := test.Subscription(subscriptionName, f.Namespace, test.ChannelRef(channelName), test.SubscriberSpecForRoute(routeName), nil)
+	err = f.CreateSubscription(subscription)
 	if err != nil {
 		t.Fatalf("Failed to create Subscription: %v", err)
 	}
@@ -83,23 +79,23 @@ func TestKubernetesEvents(t *testing.T) {
 	//Work around for: https://github.com/knative/eventing/issues/125
 	//and the fact that even after pods are up, due to Istio slowdown, there's
 	//about 5-6 seconds that traffic won't be passed through.
-	WaitForAllPodsRunning(clients, logger, pkgTest.Flags.Namespace)
+	f.WaitForAllPodsRunning(f.Namespace)
 	time.Sleep(10 * time.Second)
 
 	logger.Infof("Creating Pod")
 
-	err = CreatePod(clients, test.NGinxPod(testNamespace), logger, cleaner)
+	err = f.CreatePod(test.NGinxPod(testNamespace))
 	if err != nil {
 		t.Fatalf("Failed to create Pod: %v", err)
 	}
 
-	WaitForAllPodsRunning(clients, logger, testNamespace)
+	f.WaitForAllPodsRunning(testNamespace)
 
-	err = WaitForLogContent(clients, logger, routeName, "user-container", "Created container")
+	err = f.WaitForLogContent(routeName, "user-container", "Created container")
 	if err != nil {
 		t.Fatalf("Events for container created not received: %v", err)
 	}
-	err = WaitForLogContent(clients, logger, routeName, "user-container", "Started container")
+	err = f.WaitForLogContent(routeName, "user-container", "Started container")
 	if err != nil {
 		t.Fatalf("Events for container started not received: %v", err)
 	}
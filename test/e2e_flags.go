@@ -32,8 +32,9 @@ var EventingFlags = initializeEventingFlags()
 
 // EventingEnvironmentFlags holds the e2e flags needed only by the eventing repo
 type EventingEnvironmentFlags struct {
-	DockerRepo string // Docker repo (defaults to $DOCKER_REPO_OVERRIDE)
-	Tag        string // Tag for test images
+	DockerRepo   string // Docker repo (defaults to $DOCKER_REPO_OVERRIDE)
+	Tag          string // Tag for test images
+	Provisioners string // Comma-separated ClusterChannelProvisioners the Channel tests run against
 }
 
 func initializeEventingFlags() *EventingEnvironmentFlags {
@@ -45,6 +46,9 @@ func initializeEventingFlags() *EventingEnvironmentFlags {
 
 	flag.StringVar(&f.Tag, "tag", "e2e", "Provide the version tag for the test images.")
 
+	flag.StringVar(&f.Provisioners, "provisioners", "in-memory-channel",
+		"Provide the comma-separated names of the ClusterChannelProvisioners the Channel tests run against.")
+
 	flag.Parse()
 
 	logging.InitializeLogger(pkgTest.Flags.LogVerbose)
@@ -0,0 +1,359 @@
+/*
+Copyright 2019 The Knative Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package framework contains the building blocks of the eventing end-to-end tests: creating
+// Channels and Subscriptions, deploying a sink that records the events it receives, sending events
+// and asserting their receipt. Tests outside this repository, e.g. those of contrib provisioners,
+// can import it to get end-to-end coverage of their components.
+package framework
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	sourcesv1alpha1 "github.com/knative/eventing-sources/pkg/apis/sources/v1alpha1"
+	"github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
+	"github.com/knative/eventing/test"
+	pkgTest "github.com/knative/pkg/test"
+	"github.com/knative/pkg/test/logging"
+	servingV1alpha1 "github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacV1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	// Mysteriously required to support GCP auth (required by k8s libs).
+	// Apparently just importing it is enough. @_@ side effects @_@.
+	// https://github.com/kubernetes/client-go/issues/242
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+)
+
+const (
+	// DefaultNamespaceName is the namespace the tests run in unless --namespace is given.
+	DefaultNamespaceName = "e2etestfn3"
+
+	interval = 1 * time.Second
+	timeout  = 1 * time.Minute
+)
+
+// Framework holds the clients of an end-to-end test and the resources it created, so that they
+// are deleted when the test ends.
+type Framework struct {
+	T         *testing.T
+	Clients   *test.Clients
+	Cleaner   *test.Cleaner
+	Logger    *logging.BaseLogger
+	Namespace string
+}
+
+// Setup creates the clients needed by the end-to-end test t. The resources created through the
+// returned Framework are deleted by TearDown, or when the test is interrupted.
+func Setup(t *testing.T) *Framework {
+	if pkgTest.Flags.Namespace == "" {
+		pkgTest.Flags.Namespace = DefaultNamespaceName
+	}
+
+	logger := logging.GetContextLogger(t.Name())
+	clients, err := test.NewClients(
+		pkgTest.Flags.Kubeconfig,
+		pkgTest.Flags.Cluster,
+		pkgTest.Flags.Namespace)
+	if err != nil {
+		t.Fatalf("Couldn't initialize clients: %v", err)
+	}
+
+	f := &Framework{
+		T:         t,
+		Clients:   clients,
+		Cleaner:   test.NewCleaner(logger, clients.Dynamic),
+		Logger:    logger,
+		Namespace: pkgTest.Flags.Namespace,
+	}
+	test.CleanupOnInterrupt(f.TearDown, logger)
+	return f
+}
+
+// TearDown deletes the resources created through f.
+func (f *Framework) TearDown() {
+	f.Cleaner.Clean(true)
+
+	// There seems to be an Istio bug where if we delete / create
+	// VirtualServices too quickly we will hit pro-longed "No health
+	// upstream" causing timeouts.  Adding this small sleep to
+	// sidestep the issue.
+	//
+	// TODO(#1376):  Fix this when upstream fix is released.
+	f.Logger.Info("Sleeping for 20 seconds after clean to avoid hitting issue in #1376")
+	time.Sleep(20 * time.Second)
+}
+
+// Provisioners returns references to the ClusterChannelProvisioners named by --provisioners.
+func Provisioners() []*corev1.ObjectReference {
+	var provisioners []*corev1.ObjectReference
+	for _, name := range strings.Split(test.EventingFlags.Provisioners, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			provisioners = append(provisioners, test.ClusterChannelProvisioner(name))
+		}
+	}
+	return provisioners
+}
+
+// RunForProvisioners runs f as a subtest of t once for each of the ClusterChannelProvisioners
+// named by --provisioners.
+func RunForProvisioners(t *testing.T, f func(t *testing.T, provisioner *corev1.ObjectReference)) {
+	for _, provisioner := range Provisioners() {
+		provisioner := provisioner
+		t.Run(provisioner.Name, func(t *testing.T) {
+			f(t, provisioner)
+		})
+	}
+}
+
+// ImagePath is a helper function to prefix image name with repo and suffix with tag
+func ImagePath(name string) string {
+	return fmt.Sprintf("%s/%s:%s", test.EventingFlags.DockerRepo, name, test.EventingFlags.Tag)
+}
+
+// CreateRouteAndConfig will create Route and Config objects.
+// The Config object will serve requests to a container started from the image at imagePath.
+func (f *Framework) CreateRouteAndConfig(name string, imagePath string) error {
+	configurations := f.Clients.Serving.ServingV1alpha1().Configurations(f.Namespace)
+	config, err := configurations.Create(
+		test.Configuration(name, f.Namespace, imagePath))
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(servingV1alpha1.SchemeGroupVersion.Group, servingV1alpha1.SchemeGroupVersion.Version, "configurations", f.Namespace, config.ObjectMeta.Name)
+
+	routes := f.Clients.Serving.ServingV1alpha1().Routes(f.Namespace)
+	route, err := routes.Create(
+		test.Route(name, f.Namespace, name))
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(servingV1alpha1.SchemeGroupVersion.Group, servingV1alpha1.SchemeGroupVersion.Version, "routes", f.Namespace, route.ObjectMeta.Name)
+	return nil
+}
+
+// WithRouteReady will create Route and Config objects and wait until they're ready.
+func (f *Framework) WithRouteReady(name string, imagePath string) error {
+	if err := f.CreateRouteAndConfig(name, imagePath); err != nil {
+		return err
+	}
+	routes := f.Clients.Serving.ServingV1alpha1().Routes(f.Namespace)
+	return test.WaitForRouteState(routes, name, test.IsRouteReady, "RouteIsReady")
+}
+
+// CreateKubernetesEventSource creates a KubernetesEventSource
+func (f *Framework) CreateKubernetesEventSource(source *sourcesv1alpha1.KubernetesEventSource) error {
+	k8sSources := f.Clients.Sources.SourcesV1alpha1().KubernetesEventSources(f.Namespace)
+	res, err := k8sSources.Create(source)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(sourcesv1alpha1.SchemeGroupVersion.Group, sourcesv1alpha1.SchemeGroupVersion.Version, "kuberneteseventsources", f.Namespace, res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateChannel will create a Channel
+func (f *Framework) CreateChannel(channel *v1alpha1.Channel) error {
+	channels := f.Clients.Eventing.EventingV1alpha1().Channels(f.Namespace)
+	res, err := channels.Create(channel)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(v1alpha1.SchemeGroupVersion.Group, v1alpha1.SchemeGroupVersion.Version, "channels", f.Namespace, res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateChannelOrFail creates a Channel called name that is provisioned by provisioner, and
+// waits until it is ready. It fails the test if it is not.
+func (f *Framework) CreateChannelOrFail(name string, provisioner *corev1.ObjectReference) {
+	f.Logger.Infof("Creating Channel %q provisioned by %q", name, provisioner.Name)
+	if err := f.CreateChannel(test.Channel(name, f.Namespace, provisioner)); err != nil {
+		f.T.Fatalf("Failed to create Channel %q: %v", name, err)
+	}
+	channels := f.Clients.Eventing.EventingV1alpha1().Channels(f.Namespace)
+	if err := test.WaitForChannelState(channels, name, test.IsChannelReady, "ChannelIsReady"); err != nil {
+		f.T.Fatalf("Channel %q did not become ready: %v", name, err)
+	}
+}
+
+// CreateSubscription will create a Subscription
+func (f *Framework) CreateSubscription(subs *v1alpha1.Subscription) error {
+	subscriptions := f.Clients.Eventing.EventingV1alpha1().Subscriptions(f.Namespace)
+	res, err := subscriptions.Create(subs)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(v1alpha1.SchemeGroupVersion.Group, v1alpha1.SchemeGroupVersion.Version, "subscriptions", f.Namespace, res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateSubscriptionOrFail creates a Subscription called name from the Channel called channel to
+// subscriber, and waits until it is ready. It fails the test if it is not.
+func (f *Framework) CreateSubscriptionOrFail(name string, channel string, subscriber *v1alpha1.SubscriberSpec) {
+	f.Logger.Infof("Creating Subscription %q to Channel %q", name, channel)
+	subscription := test.Subscription(name, f.Namespace, test.ChannelRef(channel), subscriber, nil)
+	if err := f.CreateSubscription(subscription); err != nil {
+		f.T.Fatalf("Failed to create Subscription %q: %v", name, err)
+	}
+	subscriptions := f.Clients.Eventing.EventingV1alpha1().Subscriptions(f.Namespace)
+	if err := test.WaitForSubscriptionState(subscriptions, name, test.IsSubscriptionReady, "SubscriptionIsReady"); err != nil {
+		f.T.Fatalf("Subscription %q did not become ready: %v", name, err)
+	}
+}
+
+// CreateServiceAccount will create a service account
+func (f *Framework) CreateServiceAccount(sa *corev1.ServiceAccount) error {
+	sas := f.Clients.Kube.Kube.CoreV1().ServiceAccounts(f.Namespace)
+	res, err := sas.Create(sa)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(corev1.SchemeGroupVersion.Group, corev1.SchemeGroupVersion.Version, "serviceaccounts", f.Namespace, res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateClusterRoleBinding will create a service account binding
+func (f *Framework) CreateClusterRoleBinding(crb *rbacV1beta1.ClusterRoleBinding) error {
+	clusterRoleBindings := f.Clients.Kube.Kube.RbacV1beta1().ClusterRoleBindings()
+	res, err := clusterRoleBindings.Create(crb)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(rbacV1beta1.SchemeGroupVersion.Group, rbacV1beta1.SchemeGroupVersion.Version, "clusterrolebindings", "", res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateServiceAccountAndBinding creates both ServiceAccount and ClusterRoleBinding with default
+// cluster-admin role
+func (f *Framework) CreateServiceAccountAndBinding(name string) error {
+	if err := f.CreateServiceAccount(test.ServiceAccount(name, f.Namespace)); err != nil {
+		return err
+	}
+	crb := test.ClusterRoleBinding("e2e-tests-admin", f.Namespace, name, "cluster-admin")
+	return f.CreateClusterRoleBinding(crb)
+}
+
+// CreatePod will create a Pod
+func (f *Framework) CreatePod(pod *corev1.Pod) error {
+	pods := f.Clients.Kube.Kube.CoreV1().Pods(pod.GetNamespace())
+	res, err := pods.Create(pod)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(corev1.SchemeGroupVersion.Group, corev1.SchemeGroupVersion.Version, "pods", res.ObjectMeta.Namespace, res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateService will create a Service
+func (f *Framework) CreateService(svc *corev1.Service) error {
+	svcs := f.Clients.Kube.Kube.CoreV1().Services(svc.GetNamespace())
+	res, err := svcs.Create(svc)
+	if err != nil {
+		return err
+	}
+	f.Cleaner.Add(corev1.SchemeGroupVersion.Group, corev1.SchemeGroupVersion.Version, "services", res.ObjectMeta.Namespace, res.ObjectMeta.Name)
+	return nil
+}
+
+// CreateRecordingSinkOrFail deploys a Pod called name that logs the events it receives, behind a
+// Service of the same name, and waits until the Pod runs. It returns a SubscriberSpec for the
+// Service. It fails the test if the sink cannot be deployed.
+func (f *Framework) CreateRecordingSinkOrFail(name string) *v1alpha1.SubscriberSpec {
+	f.Logger.Infof("Creating recording sink %q", name)
+	selector := map[string]string{"e2etest": name}
+	if err := f.CreatePod(test.EventLoggerPod(name, f.Namespace, ImagePath("recordevents"), selector)); err != nil {
+		f.T.Fatalf("Failed to create recording sink Pod %q: %v", name, err)
+	}
+	if err := f.CreateService(test.Service(name, f.Namespace, selector)); err != nil {
+		f.T.Fatalf("Failed to create recording sink Service %q: %v", name, err)
+	}
+	pods := f.Clients.Kube.Kube.CoreV1().Pods(f.Namespace)
+	if err := test.WaitForPodState(pods, name, test.IsPodRunning, "PodIsRunning"); err != nil {
+		f.T.Fatalf("Recording sink %q is not running: %v", name, err)
+	}
+	return test.SubscriberSpecForService(name)
+}
+
+// SendEventOrFail runs a Pod called name that sends event to the Channel called channel, and
+// waits until the Channel accepted it. It fails the test if it did not.
+func (f *Framework) SendEventOrFail(name string, channel string, event test.CloudEvent) {
+	c, err := f.Clients.Eventing.EventingV1alpha1().Channels(f.Namespace).Get(channel, metav1.GetOptions{})
+	if err != nil {
+		f.T.Fatalf("Failed to get Channel %q: %v", channel, err)
+	}
+	if c.Status.Address.Hostname == "" {
+		f.T.Fatalf("Channel %q is not addressable", channel)
+	}
+	sink := fmt.Sprintf("http://%s", c.Status.Address.Hostname)
+
+	f.Logger.Infof("Sending event %q to %s", event.ID, sink)
+	if err := f.CreatePod(test.EventSenderPod(name, f.Namespace, ImagePath("sendevents"), sink, event)); err != nil {
+		f.T.Fatalf("Failed to create event sender Pod %q: %v", name, err)
+	}
+	pods := f.Clients.Kube.Kube.CoreV1().Pods(f.Namespace)
+	if err := test.WaitForPodState(pods, name, test.IsPodSucceeded, "PodSucceeded"); err != nil {
+		f.T.Fatalf("Failed to send event %q: %v", event.ID, err)
+	}
+}
+
+// AssertEventReceivedOrFail waits until the recording sink called sink logged event. It fails the
+// test if it did not within the timeout.
+func (f *Framework) AssertEventReceivedOrFail(sink string, event test.CloudEvent) {
+	content := fmt.Sprintf("id=%s type=", event.ID)
+	if err := f.WaitForLogContent(sink, "recordevents", content); err != nil {
+		logs, _ := f.PodLogs(sink, "recordevents")
+		f.T.Fatalf("Event %q was not received by %q: %v\nLogs:\n%s", event.ID, sink, err, logs)
+	}
+}
+
+// PodLogs returns Pod logs for given Pod and Container
+func (f *Framework) PodLogs(podName string, containerName string) ([]byte, error) {
+	pods := f.Clients.Kube.Kube.CoreV1().Pods(f.Namespace)
+	podList, err := pods.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range podList.Items {
+		if strings.Contains(pod.Name, podName) {
+			result := pods.GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: containerName,
+			}).Do()
+			return result.Raw()
+		}
+	}
+	return nil, fmt.Errorf("Could not find logs for %s/%s", podName, containerName)
+}
+
+// WaitForLogContent waits until logs for given Pod/Container include the given content.
+// If the content is not present within timeout it returns error.
+func (f *Framework) WaitForLogContent(podName string, containerName string, content string) error {
+	return wait.PollImmediate(interval, timeout, func() (bool, error) {
+		logs, err := f.PodLogs(podName, containerName)
+		if err != nil {
+			return true, err
+		}
+		return strings.Contains(string(logs), content), nil
+	})
+}
+
+// WaitForAllPodsRunning will wait until all pods in the given namespace are running
+func (f *Framework) WaitForAllPodsRunning(namespace string) error {
+	return pkgTest.WaitForPodListState(f.Clients.Kube, test.PodsRunning, "PodsAreRunning", namespace)
+}
@@ -16,6 +16,9 @@ limitations under the License.
 package test
 
 import (
+	"fmt"
+
+	eventingv1alpha1 "github.com/knative/eventing/pkg/apis/eventing/v1alpha1"
 	"github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -52,3 +55,32 @@ func PodsRunning(podList *corev1.PodList) (bool, error) {
 	}
 	return true, nil
 }
+
+// IsChannelReady will check the status conditions of the Channel and return true if the Channel is
+// ready to receive events.
+func IsChannelReady(c *eventingv1alpha1.Channel) (bool, error) {
+	return c.Status.IsReady(), nil
+}
+
+// IsSubscriptionReady will check the status conditions of the Subscription and return true if the
+// Subscription is ready to deliver events.
+func IsSubscriptionReady(s *eventingv1alpha1.Subscription) (bool, error) {
+	return s.Status.IsReady(), nil
+}
+
+// IsPodRunning will check the phase of the Pod and return true if its containers are running.
+func IsPodRunning(p *corev1.Pod) (bool, error) {
+	return p.Status.Phase == corev1.PodRunning, nil
+}
+
+// IsPodSucceeded will check the phase of the Pod and return true once its containers exited
+// successfully. It returns an error if they failed.
+func IsPodSucceeded(p *corev1.Pod) (bool, error) {
+	switch p.Status.Phase {
+	case corev1.PodSucceeded:
+		return true, nil
+	case corev1.PodFailed:
+		return true, fmt.Errorf("pod %s failed: %s", p.Name, p.Status.Message)
+	}
+	return false, nil
+}
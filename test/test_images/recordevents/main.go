@@ -0,0 +1,33 @@
+/*
+Copyright 2019 The Knative Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    https://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// recordevents records every event it receives with a recordevents.Recorder, which also logs them
+// so that end-to-end tests can assert their receipt from the Pod logs.
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/knative/eventing/pkg/recordevents"
+	"go.uber.org/zap"
+)
+
+func main() {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("Unable to create logger: %v", err)
+	}
+	log.Print("Ready and listening on port 8080")
+	log.Fatal(http.ListenAndServe(":8080", recordevents.NewRecorder(recordevents.DefaultMaxEvents, 0, logger)))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Knative Authors
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    https://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// sendevents sends a single binary-mode CloudEvent to a sink and exits once the sink accepted it.
+// It retries while the sink, or the Istio sidecar in front of it, is not ready yet.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/knative/eventing/pkg/provisioners"
+)
+
+var (
+	sink      = flag.String("sink", "", "The URI to send the event to.")
+	eventID   = flag.String("event-id", "", "The id of the event.")
+	eventType = flag.String("event-type", "dev.knative.eventing.e2e", "The type of the event.")
+	source    = flag.String("source", "/e2e/sendevents", "The source of the event.")
+	data      = flag.String("data", "{}", "The JSON data of the event.")
+	attempts  = flag.Int("attempts", 30, "How many times to try sending the event.")
+	interval  = flag.Duration("interval", 2*time.Second, "How long to wait between attempts.")
+)
+
+func send() error {
+	req, err := http.NewRequest(http.MethodPost, *sink, strings.NewReader(*data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", provisioners.CloudEventsSpecVersion)
+	req.Header.Set("Ce-Id", *eventID)
+	req.Header.Set("Ce-Type", *eventType)
+	req.Header.Set("Ce-Source", *source)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+func main() {
+	flag.Parse()
+	if *sink == "" || *eventID == "" {
+		log.Fatal("-sink and -event-id are required")
+	}
+
+	var err error
+	for i := 0; i < *attempts; i++ {
+		if err = send(); err == nil {
+			log.Printf("Sent event id=%s to %s", *eventID, *sink)
+			return
+		}
+		log.Printf("Failed to send event id=%s to %s: %v", *eventID, *sink, err)
+		time.Sleep(*interval)
+	}
+	log.Fatalf("Giving up sending event id=%s after %d attempts: %v", *eventID, *attempts, err)
+}